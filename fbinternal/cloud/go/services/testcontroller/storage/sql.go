@@ -171,7 +171,7 @@ func (s *sqlTestControllerStorage) GetNextTestForExecution() (*TestCase, error)
 		// FOR UPDATE SKIP LOCKED
 		// Query instead of QueryRow because we don't want to error out if
 		// there's no results (this is a valid response)
-		rows, err := s.builder.Select(pkCol, typeCol, configCol, isExecutingCol, stateCol, errCol, lastExecutedCol, nextScheduledCol).
+		selectNext := s.builder.Select(pkCol, typeCol, configCol, isExecutingCol, stateCol, errCol, lastExecutedCol, nextScheduledCol).
 			From(testCaseTable).
 			Where(
 				squirrel.Or{
@@ -187,8 +187,8 @@ func (s *sqlTestControllerStorage) GetNextTestForExecution() (*TestCase, error)
 					},
 				},
 			).
-			Limit(1).
-			Suffix("FOR UPDATE SKIP LOCKED").
+			Limit(1)
+		rows, err := s.builder.ForUpdateSkipLocked(selectNext).
 			RunWith(tx).
 			Query()
 		if err != nil {
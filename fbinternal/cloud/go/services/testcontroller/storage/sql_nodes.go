@@ -255,11 +255,11 @@ func (s *sqlNodeLeasorStorage) getNodeLeaseTxFn(id string, tag *string, specific
 			}
 		}
 
-		rows, err := s.builder.Select(idCol, vpnIPCol, tagCol, availCol, lastLeasedCol).
+		selectAvailable := s.builder.Select(idCol, vpnIPCol, tagCol, availCol, lastLeasedCol).
 			From(nodeTable).
 			Where(whereClause).
-			Limit(1).
-			Suffix("FOR UPDATE SKIP LOCKED").
+			Limit(1)
+		rows, err := s.builder.ForUpdateSkipLocked(selectAvailable).
 			RunWith(tx).
 			Query()
 		if err != nil {
@@ -117,10 +117,18 @@ func TestTestControllerStore(t *testing.T) {
 func expectCreateTable(mock sqlmock.Sqlmock) {
 	mock.ExpectBegin()
 	mock.ExpectExec("CREATE TABLE IF NOT EXISTS network_table").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS network_table_changes").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("CREATE INDEX IF NOT EXISTS network_table_changes_seq_idx").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS network_table_changes_cursor").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO network_table_changes_cursor").WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 }
 
 func expectUpsert(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT type, \"key\", value, version FROM network_table").
+		WithArgs("network", "testcontroller", "gateway_version").
+		WillReturnRows(sqlmock.NewRows([]string{"type", "key", "value", "version"}))
+
 	mock.ExpectExec("INSERT INTO network_table \\(network_id,type,\"key\",version\\) "+
 		"VALUES \\(\\$1,\\$2,\\$3,\\$4\\) "+
 		"ON CONFLICT \\(network_id, type, \"key\"\\) "+
@@ -128,6 +136,8 @@ func expectUpsert(mock sqlmock.Sqlmock) {
 	).
 		WithArgs("network", "testcontroller", "gateway_version", 1).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec("INSERT INTO network_table_changes").WillReturnResult(sqlmock.NewResult(1, 1))
 }
 
 func expectPut(mock sqlmock.Sqlmock, upgrade []byte) {
@@ -141,6 +151,8 @@ func expectPut(mock sqlmock.Sqlmock, upgrade []byte) {
 	mock.ExpectExec("INSERT INTO network_table").
 		WithArgs("network", "testcontroller", "gateway_version", upgrade, 0).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec("INSERT INTO network_table_changes").WillReturnResult(sqlmock.NewResult(1, 1))
 }
 
 func expectGet(mock sqlmock.Sqlmock, upgrade []byte) {
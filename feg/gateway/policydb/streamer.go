@@ -27,6 +27,9 @@ type storedObjectListener struct {
 	streamMap object_store.ObjectMap
 	name      string
 	protoBuf  proto.Message
+	// version of the stream's data last applied, empty until a full resync
+	// is applied
+	version string
 }
 
 type BaseNameStreamListener struct {
@@ -64,36 +67,56 @@ func (listener *storedObjectListener) ReportError(e error) error {
 }
 
 func (listener *storedObjectListener) Update(ub *orcprotos.DataUpdateBatch) bool {
-	if !ub.GetResync() {
-		return true
-	}
-
-	currMap, err := listener.streamMap.GetAll()
-	if err != nil {
-		glog.Errorf("Streamer error getting current %s: %v", listener.name, err)
-		return true
+	// Updates failing to apply reset the version, so the next stream request
+	// gets a full resync
+	listener.version = ""
+
+	var currMap map[string]interface{}
+	if ub.GetResync() {
+		var err error
+		currMap, err = listener.streamMap.GetAll()
+		if err != nil {
+			glog.Errorf("Streamer error getting current %s: %v", listener.name, err)
+			return true
+		}
 	}
 
+	ok := true
 	for _, u := range ub.GetUpdates() {
 		messageSet := proto.Clone(listener.protoBuf)
 		if err := proto.Unmarshal(u.GetValue(), messageSet); err != nil {
 			glog.Errorf("Streamer Unmarshal Error: %v for %s '%s'", err, listener.name, u.GetKey())
+			ok = false
 			continue
 		}
 		if err := listener.streamMap.Set(u.GetKey(), messageSet); err != nil {
 			glog.Errorf("Streamer store Error: %v for %s '%s'", err, listener.name, u.GetKey())
+			ok = false
 		}
 		delete(currMap, u.GetKey())
 	}
+	removed := ub.GetRemovedKeys()
 	for key := range currMap {
 		// leftovers
+		removed = append(removed, key)
+	}
+	for _, key := range removed {
 		if err := listener.streamMap.Delete(key); err != nil {
 			glog.Errorf("Streamer deletion Error: %v for %s '%s'", err, listener.name, key)
+			ok = false
 		}
 	}
+	if ok {
+		listener.version = ub.GetVersion()
+	}
 	return true
 }
 
+// GetVersion returns the version of the stream's data last applied
+func (listener *storedObjectListener) GetVersion() string {
+	return listener.version
+}
+
 func (listener *storedObjectListener) GetExtraArgs() *any.Any {
 	return nil
 }
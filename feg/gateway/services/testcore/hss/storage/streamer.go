@@ -24,6 +24,9 @@ import (
 
 type subscriberListener struct {
 	store SubscriberStore
+	// version of the subscribers last applied, empty until a full resync is
+	// applied
+	version string
 }
 
 func NewSubscriberListener(store SubscriberStore) *subscriberListener {
@@ -42,11 +45,16 @@ func (listener *subscriberListener) ReportError(err error) error {
 func (listener *subscriberListener) Update(batch *orc8rprotos.DataUpdateBatch) bool {
 	glog.V(2).Infof("streaming %d subscriber update(s)", len(batch.GetUpdates()))
 	store := listener.store
+	// Updates failing to apply reset the version, so the next stream request
+	// gets a full resync
+	listener.version = ""
+	ok := true
 
 	if batch.GetResync() {
 		err := store.DeleteAllSubscribers()
 		if err != nil {
 			glog.Errorf("failed to clear subscriber database: %s", err.Error())
+			ok = false
 		}
 	}
 
@@ -54,6 +62,7 @@ func (listener *subscriberListener) Update(batch *orc8rprotos.DataUpdateBatch) b
 		subscriber := &lteprotos.SubscriberData{}
 		if err := proto.Unmarshal(update.GetValue(), subscriber); err != nil {
 			glog.Errorf("failed to unmarshal subscriber update for %s: %s", update.GetKey(), err.Error())
+			ok = false
 			continue
 		}
 
@@ -64,16 +73,43 @@ func (listener *subscriberListener) Update(batch *orc8rprotos.DataUpdateBatch) b
 				subscriber.State = oldSub.State
 			}
 			err = store.UpdateSubscriber(subscriber)
-			glog.Errorf("failed to update subscriber(%s): %s", id, err.Error())
+			if err != nil {
+				glog.Errorf("failed to update subscriber(%s): %s", id, err.Error())
+				ok = false
+			}
 		} else {
 			err = store.AddSubscriber(subscriber)
-			glog.Errorf("failed to add subscriber(%s): %s", id, err.Error())
+			if err != nil {
+				glog.Errorf("failed to add subscriber(%s): %s", id, err.Error())
+				ok = false
+			}
 		}
 	}
 
+	for _, key := range batch.GetRemovedKeys() {
+		sid, err := lteprotos.SidProto(key)
+		if err != nil {
+			glog.Errorf("failed to parse removed subscriber key %s: %s", key, err.Error())
+			ok = false
+			continue
+		}
+		if err := store.DeleteSubscriber(sid.GetId()); err != nil {
+			glog.Errorf("failed to delete subscriber(%s): %s", sid.GetId(), err.Error())
+			ok = false
+		}
+	}
+
+	if ok {
+		listener.version = batch.GetVersion()
+	}
 	return true
 }
 
+// GetVersion returns the version of the subscribers last applied
+func (listener *subscriberListener) GetVersion() string {
+	return listener.version
+}
+
 func (listener *subscriberListener) GetExtraArgs() *any.Any {
 	return nil
 }
@@ -24,6 +24,7 @@ import (
 	"magma/orc8r/cloud/go/services/analytics/query_api"
 	"magma/orc8r/cloud/go/services/configurator"
 	"magma/orc8r/cloud/go/services/state"
+	state_types "magma/orc8r/cloud/go/services/state/types"
 	"magma/orc8r/cloud/go/services/state/wrappers"
 	"magma/orc8r/lib/go/metrics"
 
@@ -67,18 +68,21 @@ func (x *UserMetricsCalculation) Calculate(prometheusClient query_api.Prometheus
 
 		// get all subscribers state across (configured and federated subscribers)
 		subscriberStateTypes := []string{lte.SubscriberStateType}
-		states, err := state.SearchStates(outgoingCtx, networkID, subscriberStateTypes, nil, nil, serdes.State)
-		if err != nil {
-			continue
-		}
 		users := make(map[string]struct{})
 		var exists = struct{}{}
 		activeSessionsPerAPN := make(map[string]int)
-		for stateID, st := range states {
-			users[stateID.DeviceID] = exists
-			for k, v := range getActiveSessionsPerApn(st.ReportedState) {
-				activeSessionsPerAPN[k] += v
+		filter := state.SearchFilter{TypeFilter: subscriberStateTypes}
+		err = state.ForEachStatesPage(outgoingCtx, networkID, filter, serdes.State, func(states state_types.StatesByID) error {
+			for stateID, st := range states {
+				users[stateID.DeviceID] = exists
+				for k, v := range getActiveSessionsPerApn(st.ReportedState) {
+					activeSessionsPerAPN[k] += v
+				}
 			}
+			return nil
+		})
+		if err != nil {
+			continue
 		}
 		labels := prometheus.Labels{metrics.NetworkLabelName: networkID}
 
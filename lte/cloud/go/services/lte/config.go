@@ -23,4 +23,10 @@ type Config struct {
 	// DefaultSubscriberdbSyncInterval is the the default interval in
 	// seconds between gateway requests to sync its subscriberdb with cloud.
 	DefaultSubscriberdbSyncInterval uint32 `yaml:"defaultSubscriberdbSyncInterval"`
+	// StreamerSnapshotCacheSize is the number of recent snapshots retained
+	// per network and stream, for streaming delta updates to gateways.
+	StreamerSnapshotCacheSize int `yaml:"streamerSnapshotCacheSize"`
+	// StreamerLoadMaxAgeSec is the max age in seconds of a network's loaded
+	// stream data reused across gateway requests. 0 reloads on every request.
+	StreamerLoadMaxAgeSec uint32 `yaml:"streamerLoadMaxAgeSec"`
 }
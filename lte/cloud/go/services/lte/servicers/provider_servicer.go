@@ -16,8 +16,10 @@ package servicers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"magma/lte/cloud/go/lte"
+	lte_service "magma/lte/cloud/go/services/lte"
 	policydb_streamer "magma/lte/cloud/go/services/policydb/streamer"
 	subscriber_streamer "magma/lte/cloud/go/services/subscriberdb/streamer"
 	streamer_protos "magma/orc8r/cloud/go/services/streamer/protos"
@@ -25,31 +27,41 @@ import (
 	"magma/orc8r/lib/go/protos"
 )
 
-type providerServicer struct{}
+type providerServicer struct {
+	// streamers are the providers by stream name. Providers are shared
+	// across requests, since they cache snapshots and loads per network.
+	streamers map[string]providers.StreamProvider
+}
 
-func NewProviderServicer() streamer_protos.StreamProviderServer {
-	return &providerServicer{}
+func NewProviderServicer(config lte_service.Config) streamer_protos.StreamProviderServer {
+	cacheSize := config.StreamerSnapshotCacheSize
+	maxAge := time.Duration(config.StreamerLoadMaxAgeSec) * time.Second
+	return &providerServicer{
+		streamers: map[string]providers.StreamProvider{
+			lte.SubscriberStreamName:       subscriber_streamer.NewSubscribersProvider(cacheSize, maxAge),
+			lte.PolicyStreamName:           policydb_streamer.NewPoliciesProvider(cacheSize, maxAge),
+			lte.ApnRuleMappingsStreamName:  policydb_streamer.NewApnRuleMappingsProvider(cacheSize, maxAge),
+			lte.BaseNameStreamName:         policydb_streamer.NewBaseNamesProvider(cacheSize, maxAge),
+			lte.NetworkWideRulesStreamName: &policydb_streamer.NetworkWideRulesProvider{},
+			lte.RatingGroupStreamName:      policydb_streamer.NewRatingGroupsProvider(cacheSize, maxAge),
+		},
+	}
 }
 
 func (s *providerServicer) GetUpdates(ctx context.Context, req *protos.StreamRequest) (*protos.DataUpdateBatch, error) {
-	var streamer providers.StreamProvider
-	switch req.GetStreamName() {
-	case lte.SubscriberStreamName:
-		streamer = &subscriber_streamer.SubscribersProvider{}
-	case lte.PolicyStreamName:
-		streamer = &policydb_streamer.PoliciesProvider{}
-	case lte.ApnRuleMappingsStreamName:
-		streamer = &policydb_streamer.ApnRuleMappingsProvider{}
-	case lte.BaseNameStreamName:
-		streamer = &policydb_streamer.BaseNamesProvider{}
-	case lte.NetworkWideRulesStreamName:
-		streamer = &policydb_streamer.NetworkWideRulesProvider{}
-	case lte.RatingGroupStreamName:
-		streamer = &policydb_streamer.RatingGroupsProvider{}
-	default:
+	streamer, ok := s.streamers[req.GetStreamName()]
+	if !ok {
 		return nil, fmt.Errorf("GetUpdates failed: unknown stream name provided: %s", req.GetStreamName())
 	}
 
+	if deltaStreamer, ok := streamer.(providers.DeltaStreamProvider); ok {
+		batch, err := deltaStreamer.GetDeltaUpdates(ctx, req.GetGatewayId(), req.GetVersion(), req.GetExtraArgs())
+		if err != nil {
+			return &protos.DataUpdateBatch{}, err
+		}
+		return batch, nil
+	}
+
 	updates, err := streamer.GetUpdates(ctx, req.GetGatewayId(), req.GetExtraArgs())
 	if err != nil {
 		return &protos.DataUpdateBatch{}, err
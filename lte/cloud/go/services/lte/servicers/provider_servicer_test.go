@@ -64,7 +64,21 @@ func TestLTEStreamProviderServicer_GetUpdates(t *testing.T) {
 		assert.NoError(t, err)
 		want, err := subscriberStreamer.GetUpdates(context.Background(), hwID, nil)
 		assert.NoError(t, err)
-		assert.Equal(t, &protos.DataUpdateBatch{Updates: want}, got)
+		assert.True(t, got.Resync)
+		assert.NotEmpty(t, got.Version)
+		assert.Equal(t, want, got.Updates)
+
+		// Unchanged subscribers stream as an empty delta
+		delta, err := c.GetUpdates(ctx, &protos.StreamRequest{
+			GatewayId:  hwID,
+			StreamName: lte.SubscriberStreamName,
+			Version:    got.Version,
+		})
+		assert.NoError(t, err)
+		assert.False(t, delta.Resync)
+		assert.Equal(t, got.Version, delta.Version)
+		assert.Empty(t, delta.Updates)
+		assert.Empty(t, delta.RemovedKeys)
 	})
 }
 
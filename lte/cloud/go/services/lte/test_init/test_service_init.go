@@ -55,7 +55,7 @@ func StartTestServiceWithConfig(t *testing.T, serviceConfig lte_service.Config)
 
 	srv, lis := test_utils.NewTestOrchestratorService(t, lte.ModuleName, lte_service.ServiceName, labels, annotations)
 	builder_protos.RegisterMconfigBuilderServer(srv.GrpcServer, servicers.NewBuilderServicer(serviceConfig))
-	provider_protos.RegisterStreamProviderServer(srv.GrpcServer, servicers.NewProviderServicer())
+	provider_protos.RegisterStreamProviderServer(srv.GrpcServer, servicers.NewProviderServicer(serviceConfig))
 
 	// Init storage
 	db, err := sqorc.Open("sqlite3", ":memory:")
@@ -17,12 +17,14 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	"magma/lte/cloud/go/lte"
 	lte_protos "magma/lte/cloud/go/protos"
 	"magma/lte/cloud/go/serdes"
 	"magma/lte/cloud/go/services/policydb/obsidian/models"
 	"magma/orc8r/cloud/go/services/configurator"
+	"magma/orc8r/cloud/go/services/streamer/providers"
 	"magma/orc8r/cloud/go/storage"
 	merrors "magma/orc8r/lib/go/errors"
 	"magma/orc8r/lib/go/protos"
@@ -34,16 +36,80 @@ import (
 
 // TODO: need to stream down the infinite credit charging keys from here
 
-type RatingGroupsProvider struct{}
+// networkStream computes the updates of a stream whose data is the same for
+// all gateways of a network.
+// The zero value streams full snapshots, loading the network's data on every
+// request.
+type networkStream struct {
+	// snapshots caches recent snapshots per network, for computing delta
+	// updates.
+	snapshots *providers.NetworkSnapshotCache
+	// loads caches each network's updates.
+	loads *providers.NetworkLoadCache
+}
 
-func (p *RatingGroupsProvider) GetUpdates(ctx context.Context, gatewayId string, extraArgs *any.Any) ([]*protos.DataUpdate, error) {
+// newNetworkStream returns a stream retaining snapshotCacheSize snapshots per
+// network for delta updates, and reusing a network's updates loaded within
+// loadMaxAge across gateway requests.
+func newNetworkStream(snapshotCacheSize int, loadMaxAge time.Duration) networkStream {
+	return networkStream{
+		snapshots: providers.NewNetworkSnapshotCache(snapshotCacheSize),
+		loads:     providers.NewNetworkLoadCache(loadMaxAge),
+	}
+}
+
+// getUpdates returns the gateway's network ID and the network's updates, as
+// returned by load.
+func (s networkStream) getUpdates(gatewayId string, load func(networkID string) ([]*protos.DataUpdate, error)) (string, []*protos.DataUpdate, error) {
 	gwEnt, err := configurator.LoadEntityForPhysicalID(gatewayId, configurator.EntityLoadCriteria{}, serdes.Entity)
+	if err != nil {
+		return "", nil, err
+	}
+	if s.loads == nil {
+		updates, err := load(gwEnt.NetworkID)
+		return gwEnt.NetworkID, updates, err
+	}
+	updates, err := s.loads.Get(gwEnt.NetworkID, func() (interface{}, error) { return load(gwEnt.NetworkID) })
+	if err != nil {
+		return "", nil, err
+	}
+	return gwEnt.NetworkID, updates.([]*protos.DataUpdate), nil
+}
+
+// getDeltaBatch returns the batch of updates to bring the gateway from the
+// passed version to the network's updates, as returned by load.
+func (s networkStream) getDeltaBatch(gatewayId string, version string, load func(networkID string) ([]*protos.DataUpdate, error)) (*protos.DataUpdateBatch, error) {
+	networkID, updates, err := s.getUpdates(gatewayId, load)
 	if err != nil {
 		return nil, err
 	}
+	if s.snapshots == nil {
+		return &protos.DataUpdateBatch{Updates: updates, Resync: true}, nil
+	}
+	return s.snapshots.GetDeltaBatch(networkID, version, updates), nil
+}
+
+type RatingGroupsProvider struct {
+	networkStream
+}
+
+// NewRatingGroupsProvider returns a rating groups provider caching as newNetworkStream describes.
+func NewRatingGroupsProvider(snapshotCacheSize int, loadMaxAge time.Duration) *RatingGroupsProvider {
+	return &RatingGroupsProvider{networkStream: newNetworkStream(snapshotCacheSize, loadMaxAge)}
+}
+
+func (p *RatingGroupsProvider) GetDeltaUpdates(ctx context.Context, gatewayId string, version string, extraArgs *any.Any) (*protos.DataUpdateBatch, error) {
+	return p.getDeltaBatch(gatewayId, version, loadRatingGroupUpdates)
+}
 
+func (p *RatingGroupsProvider) GetUpdates(ctx context.Context, gatewayId string, extraArgs *any.Any) ([]*protos.DataUpdate, error) {
+	_, updates, err := p.getUpdates(gatewayId, loadRatingGroupUpdates)
+	return updates, err
+}
+
+func loadRatingGroupUpdates(networkID string) ([]*protos.DataUpdate, error) {
 	ratingGroupEnts, _, err := configurator.LoadAllEntitiesOfType(
-		gwEnt.NetworkID, lte.RatingGroupEntityType,
+		networkID, lte.RatingGroupEntityType,
 		configurator.EntityLoadCriteria{LoadConfig: true},
 		serdes.Entity,
 	)
@@ -84,23 +150,34 @@ func ratingGroupsToUpdates(ratingGroups []*lte_protos.RatingGroup) ([]*protos.Da
 	return ret, nil
 }
 
-type PoliciesProvider struct{}
+type PoliciesProvider struct {
+	networkStream
+}
+
+// NewPoliciesProvider returns a policies provider caching as newNetworkStream describes.
+func NewPoliciesProvider(snapshotCacheSize int, loadMaxAge time.Duration) *PoliciesProvider {
+	return &PoliciesProvider{networkStream: newNetworkStream(snapshotCacheSize, loadMaxAge)}
+}
+
+func (p *PoliciesProvider) GetDeltaUpdates(ctx context.Context, gatewayId string, version string, extraArgs *any.Any) (*protos.DataUpdateBatch, error) {
+	return p.getDeltaBatch(gatewayId, version, loadPolicyUpdates)
+}
 
 func (p *PoliciesProvider) GetUpdates(ctx context.Context, gatewayId string, extraArgs *any.Any) ([]*protos.DataUpdate, error) {
-	gw, err := configurator.LoadEntityForPhysicalID(gatewayId, configurator.EntityLoadCriteria{}, serdes.Entity)
-	if err != nil {
-		return nil, err
-	}
+	_, updates, err := p.getUpdates(gatewayId, loadPolicyUpdates)
+	return updates, err
+}
 
+func loadPolicyUpdates(networkID string) ([]*protos.DataUpdate, error) {
 	rules, _, err := configurator.LoadAllEntitiesOfType(
-		gw.NetworkID, lte.PolicyRuleEntityType,
+		networkID, lte.PolicyRuleEntityType,
 		configurator.EntityLoadCriteria{LoadConfig: true},
 		serdes.Entity,
 	)
 	if err != nil {
 		return nil, err
 	}
-	qosProfiles, err := loadQosProfiles(gw.NetworkID)
+	qosProfiles, err := loadQosProfiles(networkID)
 	if err != nil {
 		return nil, err
 	}
@@ -161,16 +238,27 @@ func rulesToUpdates(rules []*lte_protos.PolicyRule) ([]*protos.DataUpdate, error
 	return ret, nil
 }
 
-type BaseNamesProvider struct{}
+type BaseNamesProvider struct {
+	networkStream
+}
+
+// NewBaseNamesProvider returns a base names provider caching as newNetworkStream describes.
+func NewBaseNamesProvider(snapshotCacheSize int, loadMaxAge time.Duration) *BaseNamesProvider {
+	return &BaseNamesProvider{networkStream: newNetworkStream(snapshotCacheSize, loadMaxAge)}
+}
+
+func (p *BaseNamesProvider) GetDeltaUpdates(ctx context.Context, gatewayId string, version string, extraArgs *any.Any) (*protos.DataUpdateBatch, error) {
+	return p.getDeltaBatch(gatewayId, version, loadBaseNameUpdates)
+}
 
 func (p *BaseNamesProvider) GetUpdates(ctx context.Context, gatewayId string, extraArgs *any.Any) ([]*protos.DataUpdate, error) {
-	gwEnt, err := configurator.LoadEntityForPhysicalID(gatewayId, configurator.EntityLoadCriteria{}, serdes.Entity)
-	if err != nil {
-		return nil, err
-	}
+	_, updates, err := p.getUpdates(gatewayId, loadBaseNameUpdates)
+	return updates, err
+}
 
+func loadBaseNameUpdates(networkID string) ([]*protos.DataUpdate, error) {
 	bnEnts, _, err := configurator.LoadAllEntitiesOfType(
-		gwEnt.NetworkID, lte.BaseNameEntityType,
+		networkID, lte.BaseNameEntityType,
 		configurator.EntityLoadCriteria{LoadConfig: true, LoadAssocsFromThis: true, LoadAssocsToThis: true},
 		serdes.Entity,
 	)
@@ -204,17 +292,28 @@ func bnsToUpdates(bns []*lte_protos.ChargingRuleBaseNameRecord) ([]*protos.DataU
 	return ret, nil
 }
 
-type ApnRuleMappingsProvider struct{}
+type ApnRuleMappingsProvider struct {
+	networkStream
+}
+
+// NewApnRuleMappingsProvider returns a rule mappings provider caching as newNetworkStream describes.
+func NewApnRuleMappingsProvider(snapshotCacheSize int, loadMaxAge time.Duration) *ApnRuleMappingsProvider {
+	return &ApnRuleMappingsProvider{networkStream: newNetworkStream(snapshotCacheSize, loadMaxAge)}
+}
+
+func (p *ApnRuleMappingsProvider) GetDeltaUpdates(ctx context.Context, gatewayId string, version string, extraArgs *any.Any) (*protos.DataUpdateBatch, error) {
+	return p.getDeltaBatch(gatewayId, version, loadApnRuleMappingUpdates)
+}
 
 // GetUpdates implements GetUpdates for the rule mappings stream provider
 func (p *ApnRuleMappingsProvider) GetUpdates(ctx context.Context, gatewayId string, extraArgs *any.Any) ([]*protos.DataUpdate, error) {
-	gwEnt, err := configurator.LoadEntityForPhysicalID(gatewayId, configurator.EntityLoadCriteria{}, serdes.Entity)
-	if err != nil {
-		return nil, err
-	}
+	_, updates, err := p.getUpdates(gatewayId, loadApnRuleMappingUpdates)
+	return updates, err
+}
 
+func loadApnRuleMappingUpdates(networkID string) ([]*protos.DataUpdate, error) {
 	loadCrit := configurator.EntityLoadCriteria{LoadAssocsFromThis: true}
-	subEnts, _, err := configurator.LoadAllEntitiesOfType(gwEnt.NetworkID, lte.SubscriberEntityType, loadCrit, serdes.Entity)
+	subEnts, _, err := configurator.LoadAllEntitiesOfType(networkID, lte.SubscriberEntityType, loadCrit, serdes.Entity)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load subscribers")
 	}
@@ -222,7 +321,7 @@ func (p *ApnRuleMappingsProvider) GetUpdates(ctx context.Context, gatewayId stri
 	ret := make([]*protos.DataUpdate, 0, len(subEnts))
 
 	for _, subEnt := range subEnts {
-		subscriberPolicySet, err := getSubscriberPolicySet(gwEnt.NetworkID, subEnt)
+		subscriberPolicySet, err := getSubscriberPolicySet(networkID, subEnt)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to build subscriber policy sets")
 		}
@@ -44,17 +44,21 @@ import (
 	"github.com/golang/glog"
 	"github.com/labstack/echo"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	Subscribers               = "subscribers"
 	SubscribersV2             = "subscribers_v2"
 	SubscriberState           = "subscriber_state"
+	SubscriberStateV2         = "subscriber_state_v2"
 	ListSubscribersPath       = ltehandlers.ManageNetworkPath + obsidian.UrlSep + Subscribers
 	ListSubscribersV2Path     = ltehandlers.ManageNetworkPath + obsidian.UrlSep + SubscribersV2
 	ManageSubscriberPath      = ListSubscribersPath + obsidian.UrlSep + ":subscriber_id"
 	ListSubscriberStatePath   = ltehandlers.ManageNetworkPath + obsidian.UrlSep + SubscriberState
 	ManageSubscriberStatePath = ListSubscriberStatePath + obsidian.UrlSep + ":subscriber_id"
+	ListSubscriberStateV2Path = ltehandlers.ManageNetworkPath + obsidian.UrlSep + SubscriberStateV2
 	ActivateSubscriberPath    = ManageSubscriberPath + obsidian.UrlSep + "activate"
 	DeactivateSubscriberPath  = ManageSubscriberPath + obsidian.UrlSep + "deactivate"
 	SubscriberProfilePath     = ManageSubscriberPath + obsidian.UrlSep + "lte" + obsidian.UrlSep + "sub_profile"
@@ -79,6 +83,7 @@ func GetHandlers() []obsidian.Handler {
 		{Path: ManageSubscriberPath, Methods: obsidian.DELETE, HandlerFunc: deleteSubscriberHandler},
 
 		{Path: ListSubscriberStatePath, Methods: obsidian.GET, HandlerFunc: listSubscriberStateHandler},
+		{Path: ListSubscriberStateV2Path, Methods: obsidian.GET, HandlerFunc: listSubscriberStateV2Handler},
 		{Path: ManageSubscriberStatePath, Methods: obsidian.GET, HandlerFunc: getSubscriberStateHandler},
 
 		{Path: ActivateSubscriberPath, Methods: obsidian.POST, HandlerFunc: makeSubscriberStateHandler(subscribermodels.LteSubscriptionStateACTIVE)},
@@ -95,6 +100,10 @@ func GetHandlers() []obsidian.Handler {
 
 const (
 	mobilitydStateExpectedMatchCount = 2
+
+	// defaultSubscriberStatePageSize is the number of states per page of
+	// subscriber states, when no page size is requested.
+	defaultSubscriberStatePageSize = 100
 )
 
 var (
@@ -216,16 +225,10 @@ func listSubscribersV2Handler(c echo.Context) error {
 		return nerr
 	}
 
-	var pageSize uint64 = 0
-	var err error
-	if pageSizeParam := c.QueryParam(ParamPageSize); pageSizeParam != "" {
-		pageSize, err = strconv.ParseUint(pageSizeParam, 10, 32)
-		if err != nil {
-			err := fmt.Errorf("invalid page size parameter: %s", err)
-			return obsidian.HttpError(err, http.StatusBadRequest)
-		}
+	pageSize, pageToken, nerr := getPageParams(c)
+	if nerr != nil {
+		return nerr
 	}
-	pageToken := c.QueryParam(ParamPageToken)
 	reqCtx := c.Request().Context()
 
 	// First check for query params to filter by
@@ -255,7 +258,7 @@ func listSubscribersV2Handler(c echo.Context) error {
 
 	// List subscribers for a given page. If no page is specified, the max
 	// size will be returned.
-	subs, nextPageToken, err := loadSubscriberPage(reqCtx, networkID, uint32(pageSize), pageToken)
+	subs, nextPageToken, err := loadSubscriberPage(reqCtx, networkID, pageSize, pageToken)
 	if err != nil {
 		return obsidian.HttpError(err, http.StatusInternalServerError)
 	}
@@ -404,6 +407,47 @@ func listSubscriberStateHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, modelsBySID)
 }
 
+// listSubscriberStateV2Handler returns a page of subscriber states, keyed by
+// subscriber ID.
+//
+// The returned states can be paginated using the page_size and page_token
+// parameters, as for listSubscribersV2Handler. Pages are made of individual
+// states rather than subscribers, so a subscriber's state can be split across
+// consecutive pages. The page size defaults to defaultSubscriberStatePageSize.
+func listSubscriberStateV2Handler(c echo.Context) error {
+	networkID, nerr := obsidian.GetNetworkId(c)
+	if nerr != nil {
+		return nerr
+	}
+	pageSize, pageToken, nerr := getPageParams(c)
+	if nerr != nil {
+		return nerr
+	}
+	if pageSize == 0 {
+		pageSize = defaultSubscriberStatePageSize
+	}
+
+	filter := state.SearchFilter{TypeFilter: allSubscriberStateTypes}
+	states, nextPageToken, err := state.SearchStatesPage(c.Request().Context(), networkID, filter, pageSize, pageToken, serdes.State)
+	if status.Code(err) == codes.InvalidArgument {
+		return obsidian.HttpError(errors.New(status.Convert(err).Message()), http.StatusBadRequest)
+	}
+	if err != nil {
+		return obsidian.HttpError(err, http.StatusInternalServerError)
+	}
+
+	modelsBySID := map[string]*subscribermodels.SubscriberState{}
+	for sid, states := range groupStatesBySID(states, nil) {
+		modelsBySID[sid] = makeSubscriberState(sid, states)
+	}
+
+	paginatedStates := subscribermodels.PaginatedSubscriberStates{
+		NextPageToken:    subscribermodels.NextPageToken(nextPageToken),
+		SubscriberStates: modelsBySID,
+	}
+	return c.JSON(http.StatusOK, paginatedStates)
+}
+
 func getSubscriberStateHandler(c echo.Context) error {
 	networkID, subscriberID, nerr := getNetworkAndSubIDs(c)
 	if nerr != nil {
@@ -897,22 +941,25 @@ func loadAllStatesForIMSIs(ctx context.Context, networkID string, imsis []string
 		return nil, err
 	}
 	states := mergeStates(imsiKeyStates, imsiCompositeKeyStates)
+	return groupStatesBySID(states, shouldLoadState), nil
+}
+
+// groupStatesBySID groups states by the subscriber they belong to. If
+// shouldLoadState is non-nil, mobilityd states whose IMSI it rejects are
+// dropped.
+func groupStatesBySID(states state_types.StatesByID, shouldLoadState func(imsi string) bool) map[string]state_types.StatesByID {
 	// Each entry in this map contains all the states that the SID cares about.
 	// The DeviceID fields of the state IDs in the nested maps do not have to
 	// match the SID, as in the case of mobilityd state for example.
 	statesBySid := map[string]state_types.StatesByID{}
 	for stateID, st := range states {
-		sidKey := stateID.DeviceID
-		if stateID.Type == lte.MobilitydStateType {
-			matches := mobilitydStateKeyRe.FindStringSubmatch(stateID.DeviceID)
-			if len(matches) != mobilitydStateExpectedMatchCount {
-				glog.Errorf("mobilityd state composite ID %s did not match regex", sidKey)
-				continue
-			}
-			if !shouldLoadState(matches[1]) {
-				continue
-			}
-			sidKey = matches[1]
+		sidKey, ok := getSubscriberIDForState(stateID)
+		if !ok {
+			glog.Errorf("mobilityd state composite ID %s did not match regex", stateID.DeviceID)
+			continue
+		}
+		if stateID.Type == lte.MobilitydStateType && shouldLoadState != nil && !shouldLoadState(sidKey) {
+			continue
 		}
 
 		if _, exists := statesBySid[sidKey]; !exists {
@@ -921,7 +968,20 @@ func loadAllStatesForIMSIs(ctx context.Context, networkID string, imsis []string
 		statesBySid[sidKey][stateID] = st
 	}
 
-	return statesBySid, nil
+	return statesBySid
+}
+
+// getSubscriberIDForState returns the ID of the subscriber the state belongs
+// to, or false if the state's ID is malformed.
+func getSubscriberIDForState(stateID state_types.ID) (string, bool) {
+	if stateID.Type != lte.MobilitydStateType {
+		return stateID.DeviceID, true
+	}
+	matches := mobilitydStateKeyRe.FindStringSubmatch(stateID.DeviceID)
+	if len(matches) != mobilitydStateExpectedMatchCount {
+		return "", false
+	}
+	return matches[1], true
 }
 
 func makeSubscriberState(subscriberID string, states state_types.StatesByID) *subscribermodels.SubscriberState {
@@ -942,6 +1002,20 @@ func mergeStates(s1 state_types.StatesByID, s2 state_types.StatesByID) state_typ
 	return s1
 }
 
+// getPageParams returns the page size and page token query parameters.
+func getPageParams(c echo.Context) (uint32, string, *echo.HTTPError) {
+	var pageSize uint64 = 0
+	var err error
+	if pageSizeParam := c.QueryParam(ParamPageSize); pageSizeParam != "" {
+		pageSize, err = strconv.ParseUint(pageSizeParam, 10, 32)
+		if err != nil {
+			err := fmt.Errorf("invalid page size parameter: %s", err)
+			return 0, "", obsidian.HttpError(err, http.StatusBadRequest)
+		}
+	}
+	return uint32(pageSize), c.QueryParam(ParamPageToken), nil
+}
+
 func getSubscriberLoadCriteria(pageSize uint32, pageToken string) configurator.EntityLoadCriteria {
 	loadCriteria := configurator.EntityLoadCriteria{
 		LoadMetadata:       true,
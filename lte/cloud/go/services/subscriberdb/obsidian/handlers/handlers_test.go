@@ -15,6 +15,7 @@ package handlers_test
 
 import (
 	"context"
+	"net/url"
 	"testing"
 	"time"
 
@@ -1248,6 +1249,160 @@ func TestListSubscriberStates(t *testing.T) {
 	tests.RunUnitTest(t, e, tc)
 }
 
+func TestListSubscriberStatesV2(t *testing.T) {
+	configuratorTestInit.StartTestService(t)
+	deviceTestInit.StartTestService(t)
+	stateTestInit.StartTestService(t)
+	err := configurator.CreateNetwork(configurator.Network{ID: "n0"}, serdes.Network)
+	assert.NoError(t, err)
+
+	e := echo.New()
+	testURLRoot := "/magma/v1/lte/:network_id/subscriber_state_v2"
+	listSubscriberStates := tests.GetHandlerByPathAndMethod(t, handlers.GetHandlers(), testURLRoot, obsidian.GET).HandlerFunc
+
+	// Initially no state
+	tc := tests.Test{
+		Method:         "GET",
+		URL:            testURLRoot + "?page_size=2",
+		Handler:        listSubscriberStates,
+		ParamNames:     []string{"network_id"},
+		ParamValues:    []string{"n0"},
+		ExpectedStatus: 200,
+		ExpectedResult: tests.JSONMarshaler(subscriberModels.PaginatedSubscriberStates{
+			NextPageToken:    "",
+			SubscriberStates: map[string]*subscriberModels.SubscriberState{},
+		}),
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Create gateway and report states
+	_, err = configurator.CreateEntity(
+		"n0",
+		configurator.NetworkEntity{Type: orc8r.MagmadGatewayType, Key: "g0", Config: &models.MagmadGatewayConfigs{}, PhysicalID: "hw0"},
+		serdes.Entity,
+	)
+	assert.NoError(t, err)
+	ctx := test_utils.GetContextWithCertificate(t, "hw0")
+	directoryState := directorydTypes.DirectoryRecord{LocationHistory: []string{"foo", "bar"}}
+	test_utils.ReportState(t, ctx, orc8r.DirectoryRecordType, "IMSI1234567890", &directoryState, serdes.State)
+	mobilitydState := state.ArbitraryJSON{"ip": map[string]interface{}{"address": "wKiArg=="}}
+	test_utils.ReportState(t, ctx, lte.MobilitydStateType, "IMSI1234567890.oai.ipv4", &mobilitydState, serdes.State)
+	subState0 := state.ArbitraryJSON{"subscriber_state": state.ArbitraryJSON{"imsi": "IMSI1234567890"}}
+	test_utils.ReportState(t, ctx, lte.SubscriberStateType, "IMSI1234567890", &subState0, serdes.State)
+	subState1 := state.ArbitraryJSON{"subscriber_state": state.ArbitraryJSON{"imsi": "IMSI0987654321"}}
+	test_utils.ReportState(t, ctx, lte.SubscriberStateType, "IMSI0987654321", &subState1, serdes.State)
+
+	// Pages are ordered by state type then key, so a subscriber's states
+	// can be split across pages
+	filter := state.SearchFilter{TypeFilter: []string{orc8r.DirectoryRecordType, lte.MobilitydStateType, lte.SubscriberStateType}}
+	_, pageToken, err := state.SearchStatesPage(context.Background(), "n0", filter, 3, "", serdes.State)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pageToken)
+
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            testURLRoot + "?page_size=3",
+		Handler:        listSubscriberStates,
+		ParamNames:     []string{"network_id"},
+		ParamValues:    []string{"n0"},
+		ExpectedStatus: 200,
+		ExpectedResult: tests.JSONMarshaler(subscriberModels.PaginatedSubscriberStates{
+			NextPageToken: subscriberModels.NextPageToken(pageToken),
+			SubscriberStates: map[string]*subscriberModels.SubscriberState{
+				"IMSI0987654321": {
+					SubscriberState: subState1,
+				},
+				"IMSI1234567890": {
+					Mobility: []*subscriberModels.SubscriberIPAllocation{
+						{
+							Apn: "oai.ipv4",
+							IP:  "192.168.128.174",
+						},
+					},
+					Directory: &subscriberModels.SubscriberDirectoryRecord{
+						LocationHistory: []string{"foo", "bar"},
+					},
+				},
+			},
+		}),
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Last page
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            testURLRoot + "?page_size=3&page_token=" + url.QueryEscape(pageToken),
+		Handler:        listSubscriberStates,
+		ParamNames:     []string{"network_id"},
+		ParamValues:    []string{"n0"},
+		ExpectedStatus: 200,
+		ExpectedResult: tests.JSONMarshaler(subscriberModels.PaginatedSubscriberStates{
+			NextPageToken: "",
+			SubscriberStates: map[string]*subscriberModels.SubscriberState{
+				"IMSI1234567890": {
+					SubscriberState: subState0,
+				},
+			},
+		}),
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Default page size
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            testURLRoot,
+		Handler:        listSubscriberStates,
+		ParamNames:     []string{"network_id"},
+		ParamValues:    []string{"n0"},
+		ExpectedStatus: 200,
+		ExpectedResult: tests.JSONMarshaler(subscriberModels.PaginatedSubscriberStates{
+			NextPageToken: "",
+			SubscriberStates: map[string]*subscriberModels.SubscriberState{
+				"IMSI0987654321": {
+					SubscriberState: subState1,
+				},
+				"IMSI1234567890": {
+					SubscriberState: subState0,
+					Mobility: []*subscriberModels.SubscriberIPAllocation{
+						{
+							Apn: "oai.ipv4",
+							IP:  "192.168.128.174",
+						},
+					},
+					Directory: &subscriberModels.SubscriberDirectoryRecord{
+						LocationHistory: []string{"foo", "bar"},
+					},
+				},
+			},
+		}),
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Invalid page token
+	tc = tests.Test{
+		Method:                 "GET",
+		URL:                    testURLRoot + "?page_token=%25%25",
+		Handler:                listSubscriberStates,
+		ParamNames:             []string{"network_id"},
+		ParamValues:            []string{"n0"},
+		ExpectedStatus:         400,
+		ExpectedErrorSubstring: "invalid page token",
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Invalid page size
+	tc = tests.Test{
+		Method:                 "GET",
+		URL:                    testURLRoot + "?page_size=foo",
+		Handler:                listSubscriberStates,
+		ParamNames:             []string{"network_id"},
+		ParamValues:            []string{"n0"},
+		ExpectedStatus:         400,
+		ExpectedErrorSubstring: "invalid page size parameter",
+	}
+	tests.RunUnitTest(t, e, tc)
+}
+
 func TestGetSubscriberState(t *testing.T) {
 	configuratorTestInit.StartTestService(t)
 	deviceTestInit.StartTestService(t)
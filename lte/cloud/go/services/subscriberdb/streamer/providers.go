@@ -16,6 +16,7 @@ package streamer
 import (
 	"context"
 	"sort"
+	"time"
 
 	"magma/lte/cloud/go/lte"
 	lte_protos "magma/lte/cloud/go/protos"
@@ -23,6 +24,7 @@ import (
 	lte_models "magma/lte/cloud/go/services/lte/obsidian/models"
 	"magma/lte/cloud/go/services/subscriberdb/obsidian/models"
 	"magma/orc8r/cloud/go/services/configurator"
+	"magma/orc8r/cloud/go/services/streamer/providers"
 	"magma/orc8r/lib/go/protos"
 
 	"github.com/golang/protobuf/proto"
@@ -33,12 +35,55 @@ import (
 // TODO(hcgatewood): after v1.6, remove this servicer in favor of the direct gRPC ListSubscribers
 
 // SubscribersProvider provides the implementation for subscriber streaming.
-type SubscribersProvider struct{}
+// The zero value streams full snapshots, loading subscribers on every
+// request.
+type SubscribersProvider struct {
+	// snapshots caches up to snapshotCacheSize recent subscriber snapshots
+	// per network, for computing delta updates.
+	snapshots *providers.NetworkSnapshotCache
+	// loads caches each network's subscribers and APNs for loadMaxAge,
+	// across gateway requests.
+	loads *providers.NetworkLoadCache
+}
+
+// NewSubscribersProvider returns a subscribers provider with the passed cache bounds.
+func NewSubscribersProvider(snapshotCacheSize int, loadMaxAge time.Duration) *SubscribersProvider {
+	return &SubscribersProvider{
+		snapshots: providers.NewNetworkSnapshotCache(snapshotCacheSize),
+		loads:     providers.NewNetworkLoadCache(loadMaxAge),
+	}
+}
+
+// GetDeltaUpdates returns the subscribers changed or removed since the
+// gateway's version, falling back to all subscribers.
+func (p *SubscribersProvider) GetDeltaUpdates(ctx context.Context, gatewayId string, version string, extraArgs *any.Any) (*protos.DataUpdateBatch, error) {
+	networkID, updates, err := p.getUpdates(gatewayId)
+	if err != nil {
+		return nil, err
+	}
+	if p.snapshots == nil {
+		return &protos.DataUpdateBatch{Updates: updates, Resync: true}, nil
+	}
+	return p.snapshots.GetDeltaBatch(networkID, version, updates), nil
+}
 
 func (p *SubscribersProvider) GetUpdates(ctx context.Context, gatewayId string, extraArgs *any.Any) ([]*protos.DataUpdate, error) {
+	_, updates, err := p.getUpdates(gatewayId)
+	return updates, err
+}
+
+// networkSubscribers are the network-wide configurator entities which make
+// up the subscriber stream.
+type networkSubscribers struct {
+	subEnts    configurator.NetworkEntities
+	apnsByName map[string]*lte_models.ApnConfiguration
+}
+
+// getUpdates returns the gateway's network ID and subscriber updates.
+func (p *SubscribersProvider) getUpdates(gatewayId string) (string, []*protos.DataUpdate, error) {
 	magmadGateway, err := configurator.LoadEntityForPhysicalID(gatewayId, configurator.EntityLoadCriteria{LoadAssocsFromThis: true}, serdes.Entity)
 	if err != nil {
-		return nil, errors.Wrapf(err, "load magmad gateway for physical ID %s", gatewayId)
+		return "", nil, errors.Wrapf(err, "load magmad gateway for physical ID %s", gatewayId)
 	}
 	gateway, err := configurator.LoadEntity(
 		magmadGateway.NetworkID, lte.CellularGatewayEntityType, magmadGateway.Key,
@@ -46,54 +91,78 @@ func (p *SubscribersProvider) GetUpdates(ctx context.Context, gatewayId string,
 		serdes.Entity,
 	)
 	if err != nil {
-		return nil, errors.Wrapf(err, "load cellular gateway from magmad gateway %s", magmadGateway.Key)
+		return "", nil, errors.Wrapf(err, "load cellular gateway from magmad gateway %s", magmadGateway.Key)
 	}
-	subEnts, _, err := configurator.LoadAllEntitiesOfType(
-		gateway.NetworkID, lte.SubscriberEntityType, configurator.EntityLoadCriteria{LoadConfig: true,
-			LoadAssocsToThis: true, LoadAssocsFromThis: true},
-		serdes.Entity,
-	)
+	loaded, err := p.loadNetworkSubscribers(gateway.NetworkID)
 	if err != nil {
-		return nil, errors.Wrapf(err, "load all subscribers in network of gateway %s", gateway.Key)
+		return "", nil, errors.Wrapf(err, "load all subscribers in network of gateway %s", gateway.Key)
 	}
-	apnsByName, apnResourcesByAPN, err := loadAPNs(gateway)
+	apnResources, err := lte_models.LoadAPNResources(gateway.NetworkID, gateway.Associations.Filter(lte.APNResourceEntityType).Keys())
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
-	subProtos := make([]*lte_protos.SubscriberData, 0, len(subEnts))
-	for _, sub := range subEnts {
-		subProto, err := subscriberToMconfig(sub, apnsByName, apnResourcesByAPN)
+	subProtos := make([]*lte_protos.SubscriberData, 0, len(loaded.subEnts))
+	for _, sub := range loaded.subEnts {
+		subProto, err := subscriberToMconfig(sub, loaded.apnsByName, apnResources)
 		if err != nil {
-			return nil, err
+			return "", nil, err
 		}
 		subProto.NetworkId = &protos.NetworkID{Id: gateway.NetworkID}
 		subProtos = append(subProtos, subProto)
 	}
 
-	return subscribersToUpdates(subProtos)
+	updates, err := subscribersToUpdates(subProtos)
+	if err != nil {
+		return "", nil, err
+	}
+	return gateway.NetworkID, updates, nil
 }
 
-func loadAPNs(gateway configurator.NetworkEntity) (map[string]*lte_models.ApnConfiguration, lte_models.ApnResources, error) {
+func (p *SubscribersProvider) loadNetworkSubscribers(networkID string) (*networkSubscribers, error) {
+	load := func() (interface{}, error) {
+		subEnts, _, err := configurator.LoadAllEntitiesOfType(
+			networkID, lte.SubscriberEntityType, configurator.EntityLoadCriteria{LoadConfig: true,
+				LoadAssocsToThis: true, LoadAssocsFromThis: true},
+			serdes.Entity,
+		)
+		if err != nil {
+			return nil, err
+		}
+		apnsByName, err := loadAPNs(networkID)
+		if err != nil {
+			return nil, err
+		}
+		return &networkSubscribers{subEnts: subEnts, apnsByName: apnsByName}, nil
+	}
+	if p.loads == nil {
+		loaded, err := load()
+		if err != nil {
+			return nil, err
+		}
+		return loaded.(*networkSubscribers), nil
+	}
+	loaded, err := p.loads.Get(networkID, load)
+	if err != nil {
+		return nil, err
+	}
+	return loaded.(*networkSubscribers), nil
+}
+
+func loadAPNs(networkID string) (map[string]*lte_models.ApnConfiguration, error) {
 	apns, _, err := configurator.LoadAllEntitiesOfType(
-		gateway.NetworkID, lte.APNEntityType,
+		networkID, lte.APNEntityType,
 		configurator.EntityLoadCriteria{LoadConfig: true},
 		serdes.Entity,
 	)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	apnsByName := map[string]*lte_models.ApnConfiguration{}
 	for _, ent := range apns {
 		apnsByName[ent.Key] = ent.Config.(*lte_models.ApnConfiguration)
 	}
-
-	apnResources, err := lte_models.LoadAPNResources(gateway.NetworkID, gateway.Associations.Filter(lte.APNResourceEntityType).Keys())
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return apnsByName, apnResources, nil
+	return apnsByName, nil
 }
 
 func subscribersToUpdates(subs []*lte_protos.SubscriberData) ([]*protos.DataUpdate, error) {
@@ -167,6 +167,12 @@ func TestSubscriberdbStreamer(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expected, actual)
 
+	deltaProvider := provider.(providers.DeltaStreamProvider)
+	snapshot, err := deltaProvider.GetDeltaUpdates(context.Background(), "hw1", "", nil)
+	assert.NoError(t, err)
+	assert.True(t, snapshot.Resync)
+	assert.Equal(t, expected, snapshot.Updates)
+
 	// Create policies and base name associated to sub
 	_, err = configurator.CreateEntities(
 		"n1",
@@ -202,6 +208,13 @@ func TestSubscriberdbStreamer(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expected, actual)
 
+	// Only the changed subscriber is streamed as a delta
+	delta, err := deltaProvider.GetDeltaUpdates(context.Background(), "hw1", snapshot.Version, nil)
+	assert.NoError(t, err)
+	assert.False(t, delta.Resync)
+	assert.Equal(t, expected.([]*protos.DataUpdate)[:1], delta.Updates)
+	assert.Empty(t, delta.RemovedKeys)
+
 	// Create gateway-specific APN configuration
 	var writes []configurator.EntityWriteOperation
 	writes = append(writes, configurator.NetworkEntity{
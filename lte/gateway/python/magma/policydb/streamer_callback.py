@@ -39,34 +39,69 @@ from magma.policydb.rule_store import PolicyRuleDict
 from orc8r.protos.streamer_pb2 import DataUpdate
 
 
-class PolicyDBStreamerCallback(StreamerClient.Callback):
+class _VersionedStreamerCallback(StreamerClient.VersionedCallback):
     """
-    Callback implementation for the PolicyDB StreamerClient instance.
+    Base for policydb stream callbacks, which track the version last applied
+    so the cloud only streams changes. Subclasses apply updates in
+    process_update and removals in remove_keys.
     """
 
     def __init__(self):
-        self._policy_dict = PolicyRuleDict()
+        self._version = ''
 
     def get_request_args(self, stream_name: str) -> Any:
         return None
 
+    def get_version(self, stream_name: str) -> str:
+        return self._version
+
+    def process_versioned_update(
+        self, stream_name: str, updates: List[DataUpdate],
+        resync: bool, removed_keys: List[str], version: str,
+    ):
+        self.process_update(stream_name, updates, resync)
+        if not resync and removed_keys:
+            self.remove_keys(removed_keys)
+        self._version = version
+
+    def remove_keys(self, keys: List[str]):
+        """
+        Remove the keys deleted in the cloud since the last applied version.
+        """
+        raise NotImplementedError()
+
+
+class PolicyDBStreamerCallback(_VersionedStreamerCallback):
+    """
+    Callback implementation for the PolicyDB StreamerClient instance.
+    """
+
+    def __init__(self):
+        super().__init__()
+        self._policy_dict = PolicyRuleDict()
+
     def process_update(self, stream_name, updates, resync):
         logging.info(
             "Processing %d policy updates (resync=%s)",
             len(updates), resync,
         )
+        policy_ids = set()
+        for update in updates:
+            policy = PolicyRule()
+            policy.ParseFromString(update.value)
+            self._store_policy_rule(policy)
+            policy_ids.add(policy.id)
         if resync:
-            policy_ids = set()
-            for update in updates:
-                policy = PolicyRule()
-                policy.ParseFromString(update.value)
-                self._store_policy_rule(policy)
-                policy_ids.add(policy.id)
             logging.debug("Resync with policies: %s", ','.join(policy_ids))
             self._remove_old_policies(policy_ids)
+        if resync or updates:
             self._policy_dict.send_update_notification()
-        else:
-            pass
+
+    def remove_keys(self, keys):
+        for key in keys:
+            if key in self._policy_dict:
+                del self._policy_dict[key]
+        self._policy_dict.send_update_notification()
 
     def _store_policy_rule(self, policy):
         self._policy_dict[policy.id] = policy
@@ -81,7 +116,7 @@ class PolicyDBStreamerCallback(StreamerClient.Callback):
             del self._policy_dict[rule]
 
 
-class BaseNamesStreamerCallback(StreamerClient.Callback):
+class BaseNamesStreamerCallback(_VersionedStreamerCallback):
     """
     Callback for the base names streamer policy which persists the basenames
     and rules associated to the basename
@@ -91,11 +126,9 @@ class BaseNamesStreamerCallback(StreamerClient.Callback):
         self,
         basenames_dict: BaseNameDict,
     ):
+        super().__init__()
         self._basenames = basenames_dict
 
-    def get_request_args(self, stream_name: str) -> Any:
-        return None
-
     def process_update(
         self, stream_name: str, updates: List[DataUpdate],
         resync: bool,
@@ -106,8 +139,13 @@ class BaseNamesStreamerCallback(StreamerClient.Callback):
             basename.ParseFromString(update.value)
             self._basenames[update.key] = basename
 
+    def remove_keys(self, keys):
+        for key in keys:
+            if key in self._basenames:
+                del self._basenames[key]
 
-class ApnRuleMappingsStreamerCallback(StreamerClient.Callback):
+
+class ApnRuleMappingsStreamerCallback(_VersionedStreamerCallback):
     """
     Callback for the apn rule mappings streamer policy which persists
     the mapping of (imsi, subscriber) tuples -> rules
@@ -119,13 +157,11 @@ class ApnRuleMappingsStreamerCallback(StreamerClient.Callback):
         rules_by_basename: BaseNameDict,
         apn_rules_by_sid: ApnRuleAssignmentsDict,
     ):
+        super().__init__()
         self._session_mgr_stub = session_mgr_stub
         self._rules_by_basename = rules_by_basename
         self._apn_rules_by_sid = apn_rules_by_sid
 
-    def get_request_args(self, stream_name: str) -> Any:
-        return None
-
     def process_update(
         self,
         stream_name: str,
@@ -165,6 +201,11 @@ class ApnRuleMappingsStreamerCallback(StreamerClient.Callback):
         except grpc.RpcError as e:
             logging.error('Unable to apply apn->policy updates %s', str(e))
 
+    def remove_keys(self, keys):
+        for imsi in keys:
+            if imsi in self._apn_rules_by_sid:
+                del self._apn_rules_by_sid[imsi]
+
     def _are_sub_policies_updated(
         self,
         subscriber_id: str,
@@ -248,7 +289,7 @@ class ApnRuleMappingsStreamerCallback(StreamerClient.Callback):
         return desired_rules
 
 
-class RatingGroupsStreamerCallback(StreamerClient.Callback):
+class RatingGroupsStreamerCallback(_VersionedStreamerCallback):
     """
     Callback for the rating groups streamer which persists the rating groups
     """
@@ -257,11 +298,9 @@ class RatingGroupsStreamerCallback(StreamerClient.Callback):
             self,
             rating_groups_dict: RatingGroupsDict,
     ):
+        super().__init__()
         self._rating_groups = rating_groups_dict
 
-    def get_request_args(self, stream_name: str) -> Any:
-        return None
-
     def process_update(
         self, stream_name: str, updates: List[DataUpdate],
         resync: bool,
@@ -271,3 +310,8 @@ class RatingGroupsStreamerCallback(StreamerClient.Callback):
             rg = RatingGroup()
             rg.ParseFromString(update.value)
             self._rating_groups[update.key] = rg
+
+    def remove_keys(self, keys):
+        for key in keys:
+            if key in self._rating_groups:
+                del self._rating_groups[key]
@@ -284,3 +284,35 @@ class ApnRuleMappingsStreamerCallbackTest(unittest.TestCase):
             called_with, expected_2.SerializeToString(),
             'SetSessionRules call has incorrect arguments',
         )
+
+    def test_VersionedUpdate(self):
+        """
+        Test delta updates remove the streamed removed keys, and the version
+        is tracked across updates.
+        """
+        apn_rules_dict = {}
+        stub = MockLocalSessionManagerStub()
+        stub.SetSessionRules = Mock(
+            side_effect=get_SetSessionRules_side_effect([]),
+        )
+        callback = ApnRuleMappingsStreamerCallback(stub, {}, apn_rules_dict)
+        self.assertEqual(callback.get_version("stream"), '')
+
+        updates = [
+            DataUpdate(
+                key=imsi,
+                value=SubscriberPolicySet(
+                    rules_per_apn=[
+                        ApnPolicySet(apn="apn1", assigned_policies=["p1"]),
+                    ],
+                ).SerializeToString(),
+            ) for imsi in ["imsi_1", "imsi_2"]
+        ]
+        callback.process_versioned_update("stream", updates, True, [], "v1")
+        self.assertEqual(sorted(apn_rules_dict), ["imsi_1", "imsi_2"])
+        self.assertEqual(callback.get_version("stream"), "v1")
+
+        callback.process_versioned_update("stream", [], False, ["imsi_1"], "v2")
+        self.assertEqual(sorted(apn_rules_dict), ["imsi_2"])
+        self.assertEqual(callback.get_version("stream"), "v2")
+
@@ -12,7 +12,7 @@ limitations under the License.
 """
 
 import logging
-from typing import Any
+from typing import Any, List
 
 from lte.protos.s6a_service_pb2 import DeleteSubscriberRequest
 from lte.protos.s6a_service_pb2_grpc import S6aServiceStub
@@ -21,7 +21,7 @@ from magma.common.service_registry import ServiceRegistry
 from magma.common.streamer import StreamerClient
 
 
-class SubscriberDBStreamerCallback(StreamerClient.Callback):
+class SubscriberDBStreamerCallback(StreamerClient.VersionedCallback):
     """
     Callback implementation for the SubscriberDB StreamerClient instance.
     """
@@ -29,10 +29,46 @@ class SubscriberDBStreamerCallback(StreamerClient.Callback):
     def __init__(self, store, loop):
         self._store = store
         self._loop = loop
+        # Version of the subscribers last applied to the store. Empty until
+        # the first full resync, since the store may hold stale subscribers.
+        self._version = ''
 
     def get_request_args(self, stream_name: str) -> Any:
         return None
 
+    def get_version(self, stream_name: str) -> str:
+        return self._version
+
+    def process_versioned_update(
+        self, stream_name, updates, resync,
+        removed_keys: List[str], version: str,
+    ):
+        """
+        Full resyncs replace the store's subscribers. Otherwise only the
+        changed subscribers are upserted and the removed ones deleted, and
+        the removed or no longer active subscribers are detached.
+        """
+        if resync:
+            self.process_update(stream_name, updates, resync)
+            self._version = version
+            return
+
+        logging.info(
+            "Processing %d subscriber updates and %d removals",
+            len(updates), len(removed_keys),
+        )
+        inactive_sub_ids = list(removed_keys)
+        for update in updates:
+            sub = SubscriberData()
+            sub.ParseFromString(update.value)
+            self._store.upsert_subscriber(sub)
+            if sub.lte.state != LTESubscription.ACTIVE:
+                inactive_sub_ids.append(update.key)
+        for key in removed_keys:
+            self._store.delete_subscriber(key)
+        self.detach_subscribers(inactive_sub_ids)
+        self._version = version
+
     def process_update(self, stream_name, updates, resync):
         """
         The cloud streams ALL subscribers registered, both active and inactive.
@@ -98,7 +134,15 @@ class SubscriberDBStreamerCallback(StreamerClient.Callback):
             sub_id for sub_id in old_sub_ids
             if sub_id not in set(new_sub_ids)
         ]
-        if len(deleted_sub_ids) == 0:
+        self.detach_subscribers(deleted_sub_ids)
+
+    def detach_subscribers(self, sub_ids):
+        """
+        Send grpc DeleteSubscriber request to mme to detach the subscribers.
+        :param sub_ids: a list of subscriber ids with 'IMSI' prepended
+        :return: n/a
+        """
+        if len(sub_ids) == 0:
             return
         # send detach request to mme for all deleted subscribers.
         chan = ServiceRegistry.get_rpc_channel(
@@ -109,7 +153,7 @@ class SubscriberDBStreamerCallback(StreamerClient.Callback):
         req = DeleteSubscriberRequest()
 
         # mme expects a list of IMSIs without "IMSI" prefix
-        imsis_to_delete_without_prefix = [sub[4:] for sub in deleted_sub_ids]
+        imsis_to_delete_without_prefix = [sub[4:] for sub in sub_ids]
 
         req.imsi_list.extend(imsis_to_delete_without_prefix)
         future = client.DeleteSubscriber.future(req)
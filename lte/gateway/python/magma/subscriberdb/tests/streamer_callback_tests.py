@@ -19,9 +19,15 @@ import unittest
 import unittest.mock
 
 from lte.protos.s6a_service_pb2 import DeleteSubscriberRequest
+from lte.protos.subscriberdb_pb2 import (
+    LTESubscription,
+    SubscriberData,
+    SubscriberID,
+)
 from magma.common.service_registry import ServiceRegistry
 from magma.subscriberdb.store.sqlite import SqliteStore
 from magma.subscriberdb.streamer_callback import SubscriberDBStreamerCallback
+from orc8r.protos.streamer_pb2 import DataUpdate
 
 
 class MockFuture(object):
@@ -51,6 +57,7 @@ class SubscriberDBStreamerCallbackTests(unittest.TestCase):
         # Create sqlite3 database for testing
         self._tmpfile = tempfile.TemporaryDirectory()
         store = SqliteStore(self._tmpfile.name + '/')
+        self._store = store
         self._streamer_callback = \
             SubscriberDBStreamerCallback(store, loop=asyncio.new_event_loop())
         ServiceRegistry.add_service('test', '0.0.0.0', 0)
@@ -104,6 +111,66 @@ class SubscriberDBStreamerCallbackTests(unittest.TestCase):
             DeleteSubscriberRequest(imsi_list=["101", "303"]),
         )
 
+    @unittest.mock.patch('magma.subscriberdb.streamer_callback.S6aServiceStub')
+    def test_process_versioned_update(self, s6a_service_mock_stub):
+        """
+        Test the streamer_callback applies delta updates and tracks the
+        version.
+        """
+        mock = unittest.mock.Mock()
+        mock.DeleteSubscriber.future.side_effect = [unittest.mock.Mock()]
+        s6a_service_mock_stub.side_effect = [mock]
+
+        def update(imsi, state):
+            sub = SubscriberData(
+                sid=SubscriberID(id=imsi, type=SubscriberID.IMSI),
+                lte=LTESubscription(state=state),
+            )
+            return DataUpdate(
+                key='IMSI' + imsi, value=sub.SerializeToString(),
+            )
+
+        self.assertEqual('', self._streamer_callback.get_version('sdb'))
+
+        # Resync replaces the store
+        self._streamer_callback.process_versioned_update(
+            'sdb',
+            [
+                update('101', LTESubscription.ACTIVE),
+                update('202', LTESubscription.ACTIVE),
+                update('303', LTESubscription.ACTIVE),
+            ],
+            True, [], 'v1',
+        )
+        self.assertEqual(
+            ['IMSI101', 'IMSI202', 'IMSI303'],
+            sorted(self._store.list_subscribers()),
+        )
+        self.assertEqual('v1', self._streamer_callback.get_version('sdb'))
+
+        # Delta upserts changed subscribers, deletes removed ones, and
+        # detaches both the removed and the deactivated subscribers
+        self._streamer_callback.process_versioned_update(
+            'sdb',
+            [
+                update('202', LTESubscription.INACTIVE),
+                update('404', LTESubscription.ACTIVE),
+            ],
+            False, ['IMSI303'], 'v2',
+        )
+        self.assertEqual(
+            ['IMSI101', 'IMSI202', 'IMSI404'],
+            sorted(self._store.list_subscribers()),
+        )
+        self.assertEqual(
+            LTESubscription.INACTIVE,
+            self._store.get_subscriber_data('IMSI202').lte.state,
+        )
+        self.assertEqual('v2', self._streamer_callback.get_version('sdb'))
+        mock.DeleteSubscriber.future.assert_called_once_with(
+            DeleteSubscriberRequest(imsi_list=["303", "202"]),
+        )
+
 
 if __name__ == "__main__":
     unittest.main()
@@ -0,0 +1,665 @@
+/*
+ * Copyright 2020 The Magma Authors.
+ *
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package blobstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/sqorc"
+	"magma/orc8r/cloud/go/storage"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/golang/glog"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	batchCol     = "batch"
+	idxCol       = "idx"
+	seqCol       = "seq"
+	kindCol      = "kind"
+	createdAtCol = "created_at"
+
+	cursorIDCol        = "id"
+	cursorRowID        = 0
+	compactedCol       = "compacted"
+	subscribedUntilCol = "subscribed_until"
+	subIDCol           = "id"
+	updatedAtCol       = "updated_at"
+
+	// maxSequenceBatch is the max number of pending changes assigned a
+	// cursor by a single call to GetChanges.
+	maxSequenceBatch = 1000
+
+	// SubscriptionTTL is how long a change log subscription stays live
+	// without being acknowledged.
+	SubscriptionTTL = time.Hour
+	// ChangeRetention is the max age of a change in the change log,
+	// whether or not all subscriptions have acknowledged it.
+	ChangeRetention = 24 * time.Hour
+
+	watchPollInterval = 500 * time.Millisecond
+	watchBatchSize    = 100
+	// watchAckInterval is the max interval between acknowledgements of a
+	// watch's subscription while it's idle.
+	watchAckInterval = time.Minute
+)
+
+// ErrChangesCompacted is returned when reading the change log from a cursor
+// whose following changes have since been trimmed, or were never recorded
+// because no subscription was live. Consumers should resync from the
+// blobstore and restart from cursor 0.
+var ErrChangesCompacted = errors.New("changes after cursor have been compacted")
+
+// ChangeKind is the kind of write which produced a change.
+type ChangeKind uint8
+
+const (
+	ChangeCreated ChangeKind = iota + 1
+	ChangeUpdated
+	ChangeDeleted
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeCreated:
+		return "create"
+	case ChangeUpdated:
+		return "update"
+	case ChangeDeleted:
+		return "delete"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(k))
+	}
+}
+
+// Change is an entry in the change feed of a blobstore table.
+type Change struct {
+	// Cursor is the position of the change in the feed. Cursors are strictly
+	// increasing in the order in which changes become visible.
+	Cursor    uint64
+	NetworkID string
+	Type      string
+	Key       string
+	// Version is the blob's version after the write, or the version of the
+	// deleted blob for ChangeDeleted.
+	Version uint64
+	Kind    ChangeKind
+}
+
+// TK returns the type and key of the changed blob.
+func (c Change) TK() storage.TypeAndKey {
+	return storage.TypeAndKey{Type: c.Type, Key: c.Key}
+}
+
+// ChangeHandler receives ordered batches of changes from a watch.
+// Returning an error stops the watch.
+type ChangeHandler func(changes []Change) error
+
+// changeLog records and reads changes to a blobstore table.
+//
+// Writers append changes without a cursor, so concurrent writers don't
+// contend with each other. Readers assign cursors to pending changes before
+// reading them, serialized by a lock on a single-row cursor table which is
+// only taken when there are pending changes. Since a pending change is only
+// visible to readers once its writer commits, cursor order matches the order
+// in which changes become visible, and a consumer which has read up to a
+// cursor will never miss a later-committed change.
+//
+// Changes are only recorded while the table has a live subscription, and
+// are trimmed once acknowledged by every live subscription or older than
+// ChangeRetention. The highest trimmed cursor is kept in the cursor table,
+// so consumers resuming from before it get ErrChangesCompacted rather than
+// silently missing changes.
+//
+// The cursor table also keeps when the last live subscription expires.
+// Writers which don't see a live subscription confirm it under a lock of the
+// cursor row, which acknowledgements update under the same lock, so a writer
+// can't skip recording a change which commits after the first subscription
+// was acknowledged. Writers thus only contend on the cursor row while the
+// table has no live subscription.
+type changeLog struct {
+	tableName string
+	builder   sqorc.StatementBuilder
+}
+
+func newChangeLog(blobTableName string, builder sqorc.StatementBuilder) changeLog {
+	if builder == nil {
+		builder = sqorc.GetSqlBuilder()
+	}
+	return changeLog{tableName: blobTableName + "_changes", builder: builder}
+}
+
+func (l changeLog) cursorTableName() string {
+	return l.tableName + "_cursor"
+}
+
+func (l changeLog) subscriptionTableName() string {
+	return l.tableName + "_subs"
+}
+
+func (l changeLog) initTables(tx *sql.Tx) error {
+	_, err := l.builder.CreateTable(l.tableName).
+		IfNotExists().
+		Column(batchCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(idxCol).Type(sqorc.ColumnTypeInt).NotNull().EndColumn().
+		Column(seqCol).Type(sqorc.ColumnTypeBigInt).EndColumn().
+		Column(nidCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(typeCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(keyCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(verCol).Type(sqorc.ColumnTypeBigInt).NotNull().Default(0).EndColumn().
+		Column(kindCol).Type(sqorc.ColumnTypeInt).NotNull().EndColumn().
+		Column(createdAtCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+		PrimaryKey(batchCol, idxCol).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "create change log table")
+	}
+
+	_, err = l.builder.CreateIndex(l.tableName + "_seq_idx").
+		IfNotExists().
+		On(l.tableName).
+		Columns(seqCol).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "create change log seq index")
+	}
+
+	_, err = l.builder.CreateTable(l.cursorTableName()).
+		IfNotExists().
+		Column(cursorIDCol).Type(sqorc.ColumnTypeInt).PrimaryKey().EndColumn().
+		Column(seqCol).Type(sqorc.ColumnTypeBigInt).NotNull().Default(0).EndColumn().
+		Column(compactedCol).Type(sqorc.ColumnTypeBigInt).NotNull().Default(0).EndColumn().
+		Column(subscribedUntilCol).Type(sqorc.ColumnTypeBigInt).NotNull().Default(0).EndColumn().
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "create change log cursor table")
+	}
+
+	_, err = l.builder.Insert(l.cursorTableName()).
+		Columns(cursorIDCol, seqCol, compactedCol).
+		Values(cursorRowID, 0, 0).
+		OnConflict(nil, cursorIDCol).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "initialize change log cursor")
+	}
+
+	_, err = l.builder.CreateTable(l.subscriptionTableName()).
+		IfNotExists().
+		Column(subIDCol).Type(sqorc.ColumnTypeText).PrimaryKey().EndColumn().
+		Column(seqCol).Type(sqorc.ColumnTypeBigInt).NotNull().Default(0).EndColumn().
+		Column(updatedAtCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "create change log subscription table")
+	}
+	return nil
+}
+
+// record appends pending changes to the log, if the table has a live
+// subscription.
+func (l changeLog) record(tx *sql.Tx, networkID string, changes []Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	now := clock.Now()
+	subscribed, err := l.isSubscribed(tx, now)
+	if err != nil {
+		return err
+	}
+	if !subscribed {
+		// A subscription may have been acknowledged after this transaction's
+		// snapshot, or may still be acknowledged before this transaction
+		// commits, so confirm under lock. Reads of the locked row are
+		// current.
+		_, err = l.lockCursor(tx)
+		if err != nil {
+			return err
+		}
+		subscribed, err = l.isSubscribed(tx, now)
+		if err != nil {
+			return err
+		}
+	}
+	if !subscribed {
+		return nil
+	}
+
+	batch := uuid.New().String()
+	createdAt := now.UnixNano()
+	columns := []string{batchCol, idxCol, nidCol, typeCol, keyCol, verCol, kindCol, createdAtCol}
+	chunkSize := sqorc.GetInsertChunkSize(l.builder, len(columns))
+	for start := 0; start < len(changes); start += chunkSize {
+		end := start + chunkSize
+		if end > len(changes) {
+			end = len(changes)
+		}
+		insertBuilder := l.builder.Insert(l.tableName).Columns(columns...)
+		for i := start; i < end; i++ {
+			c := changes[i]
+			insertBuilder = insertBuilder.Values(batch, i, networkID, c.Type, c.Key, c.Version, c.Kind, createdAt)
+		}
+		_, err = insertBuilder.RunWith(tx).Exec()
+		if err != nil {
+			return errors.Wrap(err, "error recording blob changes")
+		}
+	}
+	return nil
+}
+
+// get returns up to limit changes after the cursor which match the filter,
+// ordered by cursor.
+func (l changeLog) get(tx *sql.Tx, filter SearchFilter, cursor uint64, limit uint64) ([]Change, error) {
+	err := l.sequence(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if cursor != 0 {
+		compacted, err := l.compacted(tx)
+		if err != nil {
+			return nil, err
+		}
+		if cursor < compacted {
+			return nil, ErrChangesCompacted
+		}
+	}
+
+	whereCondition := append(sq.And{sq.Gt{seqCol: cursor}}, getSearchWhereCondition(filter)...)
+	rows, err := l.builder.Select(seqCol, nidCol, typeCol, keyCol, verCol, kindCol).
+		From(l.tableName).
+		Where(whereCondition).
+		OrderBy(seqCol).
+		Limit(limit).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query change log")
+	}
+	defer sqorc.CloseRowsLogOnError(rows, "GetChanges")
+
+	var changes []Change
+	for rows.Next() {
+		c := Change{}
+		err = rows.Scan(&c.Cursor, &c.NetworkID, &c.Type, &c.Key, &c.Version, &c.Kind)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan change row")
+		}
+		changes = append(changes, c)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, errors.Wrap(err, "sql rows err")
+	}
+	return changes, nil
+}
+
+// sequence assigns cursors to committed, pending changes.
+// The cursor row is only locked if there are pending changes, so readers
+// of an idle change log don't serialize on it.
+func (l changeLog) sequence(tx *sql.Tx) error {
+	var batch string
+	err := l.builder.Select(batchCol).
+		From(l.tableName).
+		Where(sq.Eq{seqCol: nil}).
+		Limit(1).
+		RunWith(tx).
+		QueryRow().
+		Scan(&batch)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to check for pending changes")
+	}
+
+	seq, err := l.lockCursor(tx)
+	if err != nil {
+		return err
+	}
+
+	rows, err := l.builder.Select(batchCol, idxCol).
+		From(l.tableName).
+		Where(sq.Eq{seqCol: nil}).
+		OrderBy(createdAtCol, batchCol, idxCol).
+		Limit(maxSequenceBatch).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return errors.Wrap(err, "failed to query pending changes")
+	}
+	type pendingChange struct {
+		batch string
+		idx   int
+	}
+	var pending []pendingChange
+	for rows.Next() {
+		p := pendingChange{}
+		err = rows.Scan(&p.batch, &p.idx)
+		if err != nil {
+			sqorc.CloseRowsLogOnError(rows, "sequence")
+			return errors.Wrap(err, "failed to scan pending change")
+		}
+		pending = append(pending, p)
+	}
+	err = rows.Err()
+	sqorc.CloseRowsLogOnError(rows, "sequence")
+	if err != nil {
+		return errors.Wrap(err, "sql rows err")
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	sc := sq.NewStmtCache(tx)
+	defer sqorc.ClearStatementCacheLogOnError(sc, "sequence")
+	for _, p := range pending {
+		seq++
+		_, err = l.builder.Update(l.tableName).
+			Set(seqCol, seq).
+			Where(sq.And{sq.Eq{batchCol: p.batch}, sq.Eq{idxCol: p.idx}}).
+			RunWith(sc).
+			Exec()
+		if err != nil {
+			return errors.Wrapf(err, "failed to assign cursor to change (%s, %d)", p.batch, p.idx)
+		}
+	}
+
+	_, err = l.builder.Update(l.cursorTableName()).
+		Set(seqCol, seq).
+		Where(sq.Eq{cursorIDCol: cursorRowID}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "failed to update change log cursor")
+	}
+	return nil
+}
+
+// lockCursor locks the cursor row until the end of the transaction,
+// returning the last assigned cursor.
+func (l changeLog) lockCursor(tx *sql.Tx) (uint64, error) {
+	_, err := l.builder.Update(l.cursorTableName()).
+		Set(seqCol, sq.Expr(seqCol)).
+		Where(sq.Eq{cursorIDCol: cursorRowID}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to lock change log cursor")
+	}
+
+	var seq uint64
+	err = l.builder.Select(seqCol).
+		From(l.cursorTableName()).
+		Where(sq.Eq{cursorIDCol: cursorRowID}).
+		RunWith(tx).
+		QueryRow().
+		Scan(&seq)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read change log cursor")
+	}
+	return seq, nil
+}
+
+// compacted returns the highest cursor trimmed from the change log.
+func (l changeLog) compacted(tx *sql.Tx) (uint64, error) {
+	var compacted uint64
+	err := l.builder.Select(compactedCol).
+		From(l.cursorTableName()).
+		Where(sq.Eq{cursorIDCol: cursorRowID}).
+		RunWith(tx).
+		QueryRow().
+		Scan(&compacted)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read change log compaction cursor")
+	}
+	return compacted, nil
+}
+
+// isSubscribed returns whether the table has a live subscription.
+func (l changeLog) isSubscribed(tx *sql.Tx, now time.Time) (bool, error) {
+	var subscribedUntil int64
+	err := l.builder.Select(subscribedUntilCol).
+		From(l.cursorTableName()).
+		Where(sq.Eq{cursorIDCol: cursorRowID}).
+		RunWith(tx).
+		QueryRow().
+		Scan(&subscribedUntil)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read change log subscription expiry")
+	}
+	return subscribedUntil >= now.UnixNano(), nil
+}
+
+// ack registers or refreshes a subscription at the passed cursor, then
+// trims changes which are no longer needed by any live subscription.
+func (l changeLog) ack(tx *sql.Tx, subscriptionID string, cursor uint64) error {
+	now := clock.Now()
+
+	// Lock the cursor row first, so writers checking for a live
+	// subscription wait for this acknowledgement to commit
+	seq, err := l.lockCursor(tx)
+	if err != nil {
+		return err
+	}
+	subscribed, err := l.isSubscribed(tx, now)
+	if err != nil {
+		return err
+	}
+	if !subscribed {
+		// Changes made while no subscription was live weren't recorded
+		err = l.markGap(tx, seq)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = l.builder.Delete(l.subscriptionTableName()).
+		Where(sq.Lt{updatedAtCol: now.Add(-SubscriptionTTL).UnixNano()}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "failed to delete expired change log subscriptions")
+	}
+
+	_, err = l.builder.Insert(l.subscriptionTableName()).
+		Columns(subIDCol, seqCol, updatedAtCol).
+		Values(subscriptionID, cursor, now.UnixNano()).
+		OnConflict(
+			[]sqorc.UpsertValue{
+				{Column: seqCol, Value: cursor},
+				{Column: updatedAtCol, Value: now.UnixNano()},
+			},
+			subIDCol,
+		).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrapf(err, "failed to acknowledge change log subscription %s", subscriptionID)
+	}
+	_, err = l.builder.Update(l.cursorTableName()).
+		Set(subscribedUntilCol, now.Add(SubscriptionTTL).UnixNano()).
+		Where(sq.And{sq.Eq{cursorIDCol: cursorRowID}, sq.Lt{subscribedUntilCol: now.Add(SubscriptionTTL).UnixNano()}}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "failed to update change log subscription expiry")
+	}
+
+	return l.compact(tx, now)
+}
+
+// markGap skips the cursor after the last assigned cursor seq, and marks
+// all changes up to it as compacted, so consumers resuming from before the
+// gap get ErrChangesCompacted.
+// The cursor row must be locked.
+func (l changeLog) markGap(tx *sql.Tx, seq uint64) error {
+	// Nothing was ever recorded, so there's no gap
+	if seq == 0 {
+		return nil
+	}
+	_, err := l.builder.Update(l.cursorTableName()).
+		Set(seqCol, seq+1).
+		Set(compactedCol, seq+1).
+		Where(sq.Eq{cursorIDCol: cursorRowID}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "failed to mark change log gap")
+	}
+	return nil
+}
+
+// compact trims changes acknowledged by all live subscriptions, and changes
+// older than ChangeRetention.
+func (l changeLog) compact(tx *sql.Tx, now time.Time) error {
+	var acked sql.NullInt64
+	err := l.builder.Select(fmt.Sprintf("MIN(%s)", seqCol)).
+		From(l.subscriptionTableName()).
+		RunWith(tx).
+		QueryRow().
+		Scan(&acked)
+	if err != nil {
+		return errors.Wrap(err, "failed to query acknowledged changes")
+	}
+	var expired sql.NullInt64
+	err = l.builder.Select(fmt.Sprintf("MAX(%s)", seqCol)).
+		From(l.tableName).
+		Where(sq.Lt{createdAtCol: now.Add(-ChangeRetention).UnixNano()}).
+		RunWith(tx).
+		QueryRow().
+		Scan(&expired)
+	if err != nil {
+		return errors.Wrap(err, "failed to query expired changes")
+	}
+
+	bound := acked.Int64
+	if expired.Int64 > bound {
+		bound = expired.Int64
+	}
+	if bound <= 0 {
+		return nil
+	}
+
+	_, err = l.builder.Delete(l.tableName).
+		Where(sq.LtOrEq{seqCol: bound}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "failed to trim change log")
+	}
+	_, err = l.builder.Update(l.cursorTableName()).
+		Set(compactedCol, bound).
+		Where(sq.And{sq.Eq{cursorIDCol: cursorRowID}, sq.Lt{compactedCol: bound}}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "failed to update change log compaction cursor")
+	}
+	return nil
+}
+
+// watch polls the factory's change log, passing new changes to the handler
+// until the context is cancelled or the handler returns an error.
+// Errors reading the change log are logged and retried on the next poll.
+//
+// The watch holds a change log subscription, acknowledged as changes are
+// handled, which expires SubscriptionTTL after the watch stops.
+func watch(ctx context.Context, fact BlobStorageFactory, filter SearchFilter, cursor uint64, handler ChangeHandler) error {
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	subscriptionID := uuid.New().String()
+	err := ackChanges(fact, subscriptionID, cursor)
+	if err != nil {
+		return err
+	}
+	lastAck := clock.Now()
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		changes, err := getChanges(fact, filter, cursor)
+		if err == ErrChangesCompacted {
+			return err
+		}
+		if err != nil {
+			glog.Errorf("Error polling blobstore change log: %s", err)
+		}
+		if len(changes) != 0 {
+			err = handler(changes)
+			if err != nil {
+				return err
+			}
+			cursor = changes[len(changes)-1].Cursor
+		}
+		if len(changes) != 0 || clock.Since(lastAck) >= watchAckInterval {
+			err = ackChanges(fact, subscriptionID, cursor)
+			if err != nil {
+				glog.Errorf("Error acknowledging blobstore change log subscription: %s", err)
+			} else {
+				lastAck = clock.Now()
+			}
+		}
+		// More changes may be immediately available
+		if len(changes) == watchBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func getChanges(fact BlobStorageFactory, filter SearchFilter, cursor uint64) ([]Change, error) {
+	store, err := fact.StartTransaction(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start transaction")
+	}
+	changes, err := store.GetChanges(filter, cursor, watchBatchSize)
+	if err != nil {
+		_ = store.Rollback()
+		return nil, err
+	}
+	return changes, store.Commit()
+}
+
+func ackChanges(fact BlobStorageFactory, subscriptionID string, cursor uint64) error {
+	store, err := fact.StartTransaction(nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	err = store.AckChanges(subscriptionID, cursor)
+	if err != nil {
+		_ = store.Rollback()
+		return err
+	}
+	return store.Commit()
+}
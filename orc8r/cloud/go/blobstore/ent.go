@@ -18,14 +18,18 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"magma/orc8r/cloud/go/blobstore/ent"
 	"magma/orc8r/cloud/go/blobstore/ent/blob"
 	"magma/orc8r/cloud/go/blobstore/ent/predicate"
+	"magma/orc8r/cloud/go/clock"
 	"magma/orc8r/cloud/go/sqorc"
 	"magma/orc8r/cloud/go/storage"
 	magmaerrors "magma/orc8r/lib/go/errors"
 
+	"github.com/facebookincubator/ent/dialect"
 	entsql "github.com/facebookincubator/ent/dialect/sql"
 	"github.com/thoas/go-funk"
 )
@@ -47,7 +51,17 @@ func NewEntStorage(tableName string, db *sql.DB, builder sqorc.StatementBuilder)
 	// ent is created and initialized once per service (process).
 	// therefore, it's safe to set the table used by the builders.
 	blob.Table = tableName
-	return &entFactory{tableName: tableName, db: db, client: client, builder: builder}
+	if builder == nil {
+		builder = sqorc.GetSqlBuilder()
+	}
+	return &entFactory{
+		tableName: tableName,
+		db:        db,
+		client:    client,
+		builder:   builder,
+		changes:   newChangeLog(tableName, builder),
+		expiries:  newExpiries(tableName, builder),
+	}
 }
 
 type entFactory struct {
@@ -55,6 +69,8 @@ type entFactory struct {
 	db        *sql.DB
 	client    *ent.Client
 	builder   sqorc.StatementBuilder
+	changes   changeLog
+	expiries  expiries
 }
 
 func (f *entFactory) InitializeFactory() error {
@@ -66,11 +82,19 @@ func (f *entFactory) StartTransaction(opts *storage.TxOptions) (TransactionalBlo
 	if err != nil {
 		return nil, err
 	}
-	return &entStorage{Tx: tx}, nil
+	return &entStorage{Tx: tx, tableName: f.tableName, builder: f.builder, changes: f.changes, expiries: f.expiries}, nil
+}
+
+func (f *entFactory) Watch(ctx context.Context, filter SearchFilter, cursor uint64, handler ChangeHandler) error {
+	return watch(ctx, f, filter, cursor, handler)
 }
 
 type entStorage struct {
 	*ent.Tx
+	tableName string
+	builder   sqorc.StatementBuilder
+	changes   changeLog
+	expiries  expiries
 }
 
 func (e *entStorage) Get(networkID string, id storage.TypeAndKey) (Blob, error) {
@@ -88,7 +112,7 @@ func (e *entStorage) GetMany(networkID string, ids []storage.TypeAndKey) (Blobs,
 	ctx := context.Background()
 	var blobs Blobs
 	err := e.Blob.Query().
-		Where(P(networkID, ids)).
+		Where(P(networkID, ids), e.expiries.livePredicate(e.tableName, clock.Now().Unix())).
 		Select(blob.FieldKey, blob.FieldType, blob.FieldValue, blob.FieldVersion).
 		Scan(ctx, &blobs)
 	if err != nil {
@@ -99,6 +123,9 @@ func (e *entStorage) GetMany(networkID string, ids []storage.TypeAndKey) (Blobs,
 
 func (e *entStorage) Search(filter SearchFilter, criteria LoadCriteria) (map[string]Blobs, error) {
 	ctx := context.Background()
+	if err := validateSearch(filter, criteria); err != nil {
+		return map[string]Blobs{}, err
+	}
 
 	// Get fields from load criteria
 	selectField := blob.FieldNetworkID
@@ -116,17 +143,33 @@ func (e *entStorage) Search(filter SearchFilter, criteria LoadCriteria) (map[str
 		preds = append(preds, blob.TypeIn(filter.GetTypes()...))
 	}
 	if !funk.IsEmpty(filter.KeyPrefix) {
-		preds = append(preds, blob.KeyHasPrefix(*filter.KeyPrefix))
+		preds = append(preds, e.keyPrefixPredicate(*filter.KeyPrefix))
 	} else {
 		if !funk.IsEmpty(filter.Keys) {
 			preds = append(preds, blob.KeyIn(filter.GetKeys()...))
 		}
 	}
 
+	if after := criteria.StartAfter; after != nil {
+		preds = append(preds, blob.Or(
+			blob.TypeGT(after.Type),
+			blob.And(blob.Type(after.Type), blob.KeyGT(after.Key)),
+		))
+	}
+
+	preds = append(preds, e.expiries.livePredicate(e.tableName, clock.Now().Unix()))
+	query := e.Blob.Query().
+		Where(blob.And(preds...))
+	if criteria.isPaginated() {
+		query = query.Order(ent.Asc(blob.FieldType, blob.FieldKey))
+	}
+	if criteria.PageSize != 0 {
+		query = query.Limit(int(criteria.PageSize))
+	}
+
 	ret := map[string]Blobs{}
 	var blobs []blobWithNetworkID
-	err := e.Blob.Query().
-		Where(blob.And(preds...)).
+	err := query.
 		Select(selectField, selectFields...). // handle ent select's at-least-once variadic method signature
 		Scan(ctx, &blobs)
 	if err != nil {
@@ -143,7 +186,8 @@ func (e *entStorage) Search(filter SearchFilter, criteria LoadCriteria) (map[str
 
 func (e *entStorage) IncrementVersion(networkID string, id storage.TypeAndKey) error {
 	ctx := context.Background()
-	switch _, err := e.Get(networkID, id); {
+	existing, err := e.getExisting(networkID, id)
+	switch {
 	case err == magmaerrors.ErrNotFound:
 		_, err = e.Blob.Create().
 			SetKey(id.Key).
@@ -151,28 +195,46 @@ func (e *entStorage) IncrementVersion(networkID string, id storage.TypeAndKey) e
 			SetNetworkID(networkID).
 			SetVersion(1).
 			Save(ctx)
-		return err
+		if err != nil {
+			return err
+		}
+		return e.changes.record(e.sqlTx(), networkID, []Change{getIncrementChange(id, nil)})
 	case err != nil: // err != not found.
 		return err
 	default:
-		return e.Blob.Update().
+		err = e.Blob.Update().
 			Where(blob.NetworkID(networkID), blob.Type(id.Type), blob.Key(id.Key)).
 			AddVersion(1).
 			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return e.changes.record(e.sqlTx(), networkID, []Change{getIncrementChange(id, Blobs{existing})})
 	}
 }
 
 func (e *entStorage) Delete(networkID string, ids []storage.TypeAndKey) error {
 	ctx := context.Background()
-	_, err := e.Blob.Delete().
+	existingBlobs, err := e.sqlStore().getExisting(networkID, ids)
+	if err != nil {
+		return fmt.Errorf("error reading existing blobs: %s", err)
+	}
+	_, err = e.Blob.Delete().
 		Where(P(networkID, ids)).
 		Exec(ctx)
-	return err
+	if err != nil {
+		return err
+	}
+	err = e.expiries.deleteExisting(e.sqlTx(), networkID, existingBlobs)
+	if err != nil {
+		return err
+	}
+	return e.changes.record(e.sqlTx(), networkID, getDeleteChanges(existingBlobs))
 }
 
 func (e *entStorage) CreateOrUpdate(networkID string, blobs Blobs) error {
 	ctx := context.Background()
-	existingBlobs, err := e.GetMany(networkID, getBlobIDs(blobs))
+	existingBlobs, err := e.sqlStore().getExisting(networkID, getBlobIDs(blobs))
 	if err != nil {
 		return fmt.Errorf("error reading existing blobs: %s", err)
 	}
@@ -204,7 +266,31 @@ func (e *entStorage) CreateOrUpdate(networkID string, blobs Blobs) error {
 			return err
 		}
 	}
-	return nil
+	err = e.expiries.set(e.sqlTx(), networkID, blobs, existingBlobs)
+	if err != nil {
+		return err
+	}
+	return e.changes.record(e.sqlTx(), networkID, changeSet.getChanges())
+}
+
+// CompareAndSwap is delegated to the SQL storage implementation, since ent
+// doesn't report the number of rows affected by guarded updates.
+func (e *entStorage) CompareAndSwap(networkID string, expected map[storage.TypeAndKey]uint64, blobs Blobs) error {
+	return e.sqlStore().CompareAndSwap(networkID, expected, blobs)
+}
+
+// DeleteExpired is delegated to the SQL storage implementation, since blob
+// expiries aren't part of the ent schema.
+func (e *entStorage) DeleteExpired(now time.Time, limit uint64) (map[string]storage.TKs, error) {
+	return e.sqlStore().DeleteExpired(now, limit)
+}
+
+func (e *entStorage) GetChanges(filter SearchFilter, cursor uint64, limit uint64) ([]Change, error) {
+	return e.changes.get(e.sqlTx(), filter, cursor, limit)
+}
+
+func (e *entStorage) AckChanges(subscriptionID string, cursor uint64) error {
+	return e.changes.ack(e.sqlTx(), subscriptionID, cursor)
 }
 
 func (e *entStorage) GetExistingKeys(keys []string, filter SearchFilter) ([]string, error) {
@@ -220,11 +306,60 @@ func (e *entStorage) GetExistingKeys(keys []string, filter SearchFilter) ([]stri
 		preds = append(preds, blob.And(and...))
 	}
 	return e.Blob.Query().
-		Where(blob.Or(preds...)).
+		Where(blob.Or(preds...), e.expiries.livePredicate(e.tableName, clock.Now().Unix())).
 		GroupBy(blob.FieldKey).
 		Strings(ctx)
 }
 
+// getExisting returns the stored blob, including an expired blob which
+// hasn't been swept yet.
+func (e *entStorage) getExisting(networkID string, id storage.TypeAndKey) (Blob, error) {
+	blobs, err := e.sqlStore().getExisting(networkID, []storage.TypeAndKey{id})
+	if err != nil {
+		return Blob{}, err
+	}
+	if len(blobs) == 0 {
+		return Blob{}, magmaerrors.ErrNotFound
+	}
+	return blobs[0], nil
+}
+
+// sqlTx returns the SQL transaction underlying the ent transaction, for
+// accessing the change log.
+func (e *entStorage) sqlTx() *sql.Tx {
+	return e.ExecQuerier().(*sql.Tx)
+}
+
+// sqlStore returns a SQL storage bound to the underlying SQL transaction.
+func (e *entStorage) sqlStore() *sqlBlobStorage {
+	return &sqlBlobStorage{tableName: e.tableName, tx: e.sqlTx(), builder: e.builder, changes: e.changes, expiries: e.expiries}
+}
+
+// keyPrefixPredicate matches blobs whose keys start with the passed prefix.
+// ent's prefix predicate doesn't escape LIKE wildcards, and its predicates
+// can't carry an ESCAPE clause, so the escaped pattern is matched in a raw
+// subquery instead.
+func (e *entStorage) keyPrefixPredicate(prefix string) predicate.Blob {
+	return func(s *entsql.Selector) {
+		pattern := likeEscaper.Replace(prefix) + "%"
+		matching := fmt.Sprintf(
+			"SELECT %[1]s.%[2]s FROM %[1]s WHERE %[1]s.%[2]s LIKE %[3]s ESCAPE '%[4]s'",
+			e.tableName, keyCol, quoteLiteral(s.Dialect(), pattern), likeEscapeChar,
+		)
+		s.Where(entsql.In(s.C(blob.FieldKey), entsql.Raw(matching)))
+	}
+}
+
+// quoteLiteral returns the passed string as a SQL string literal of the
+// passed dialect. MySQL also treats backslashes in literals as escapes.
+func quoteLiteral(sqlDialect string, s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+	if sqlDialect == dialect.MySQL {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + s + "'"
+}
+
 func P(networkID string, ids []storage.TypeAndKey) predicate.Blob {
 	preds := make([]predicate.Blob, 0, len(ids))
 	for _, id := range ids {
@@ -237,6 +237,12 @@ func (c *Client) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error)
 	}, nil
 }
 
+// ExecQuerier returns the underlying SQL transaction, for executing
+// statements against tables which aren't managed by ent.
+func (tx *Tx) ExecQuerier() sql.ExecQuerier {
+	return tx.config.driver.(*txDriver).tx.(*sql.Tx).ExecQuerier
+}
+
 // keys returns the keys/ids from the edge map.
 func keys(m map[int]struct{}) []int {
 	s := make([]int, 0, len(m))
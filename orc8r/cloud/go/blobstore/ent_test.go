@@ -115,3 +115,38 @@ func TestIntegration(t *testing.T) {
 	fact := blobstore.NewEntStorage("states", db, sqorc.GetSqlBuilder())
 	integration(t, fact)
 }
+
+func TestKeyPrefix(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	fact := blobstore.NewEntStorage("states", db, sqorc.GetSqlBuilder())
+	keyPrefixIntegration(t, fact)
+}
+
+func TestChangeFeed(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	fact := blobstore.NewEntStorage("states", db, sqorc.GetSqlBuilder())
+	changeFeedIntegration(t, fact)
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	fact := blobstore.NewEntStorage("states", db, sqorc.GetSqlBuilder())
+	compareAndSwapIntegration(t, fact)
+}
+
+func TestPaginatedSearch(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	fact := blobstore.NewEntStorage("states", db, sqorc.GetSqlBuilder())
+	paginatedSearchIntegration(t, fact)
+}
+
+func TestExpiry(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	fact := blobstore.NewEntStorage("states", db, sqorc.NewSQLiteStatementBuilder())
+	expiryIntegration(t, fact)
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright 2020 The Magma Authors.
+ *
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package blobstore
+
+import (
+	"database/sql"
+	"fmt"
+
+	"magma/orc8r/cloud/go/blobstore/ent/blob"
+	"magma/orc8r/cloud/go/blobstore/ent/predicate"
+	"magma/orc8r/cloud/go/sqorc"
+	"magma/orc8r/cloud/go/storage"
+
+	sq "github.com/Masterminds/squirrel"
+	entsql "github.com/facebookincubator/ent/dialect/sql"
+	"github.com/pkg/errors"
+)
+
+const (
+	expiresAtCol = "expires_at"
+)
+
+// expiries tracks the expiry times of blobs in a blobstore table.
+//
+// Expiry times are kept in a separate table so blobs without an expiry,
+// which are the common case, don't pay for the column, and so the ent
+// storage implementation doesn't need to know about them.
+type expiries struct {
+	tableName string
+	builder   sqorc.StatementBuilder
+}
+
+func newExpiries(blobTableName string, builder sqorc.StatementBuilder) expiries {
+	if builder == nil {
+		builder = sqorc.GetSqlBuilder()
+	}
+	return expiries{tableName: blobTableName + "_expiries", builder: builder}
+}
+
+func (e expiries) initTables(tx *sql.Tx) error {
+	_, err := e.builder.CreateTable(e.tableName).
+		IfNotExists().
+		Column(nidCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(typeCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(keyCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(expiresAtCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+		PrimaryKey(nidCol, typeCol, keyCol).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "create expiry table")
+	}
+
+	_, err = e.builder.CreateIndex(e.tableName + "_expires_at_idx").
+		IfNotExists().
+		On(e.tableName).
+		Columns(expiresAtCol).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "create expiry index")
+	}
+	return nil
+}
+
+// set replaces the expiry times of the written blobs. Blobs with an
+// ExpiresAt of 0 have their expiry cleared.
+// existing holds the blobs' stored versions, with their stored expiry times,
+// so writes which neither set nor clear an expiry don't touch the table.
+func (e expiries) set(tx *sql.Tx, networkID string, blobs Blobs, existing Blobs) error {
+	existingExpiries := map[storage.TypeAndKey]int64{}
+	for _, blob := range existing {
+		existingExpiries[storage.TypeAndKey{Type: blob.Type, Key: blob.Key}] = blob.ExpiresAt
+	}
+	var changed []storage.TypeAndKey
+	var expiring Blobs
+	for _, blob := range blobs {
+		id := storage.TypeAndKey{Type: blob.Type, Key: blob.Key}
+		if blob.ExpiresAt != 0 || existingExpiries[id] != 0 {
+			changed = append(changed, id)
+		}
+		if blob.ExpiresAt != 0 {
+			expiring = append(expiring, blob)
+		}
+	}
+
+	err := e.delete(tx, networkID, changed)
+	if err != nil {
+		return err
+	}
+
+	columns := []string{nidCol, typeCol, keyCol, expiresAtCol}
+	chunkSize := sqorc.GetInsertChunkSize(e.builder, len(columns))
+	for start := 0; start < len(expiring); start += chunkSize {
+		end := start + chunkSize
+		if end > len(expiring) {
+			end = len(expiring)
+		}
+		insertBuilder := e.builder.Insert(e.tableName).Columns(columns...)
+		for _, blob := range expiring[start:end] {
+			insertBuilder = insertBuilder.Values(networkID, blob.Type, blob.Key, blob.ExpiresAt)
+		}
+		_, err = insertBuilder.RunWith(tx).Exec()
+		if err != nil {
+			return errors.Wrap(err, "error setting blob expiries")
+		}
+	}
+	return nil
+}
+
+// deleteExisting clears the expiry times of the stored blobs which have one.
+func (e expiries) deleteExisting(tx *sql.Tx, networkID string, existing Blobs) error {
+	var ids []storage.TypeAndKey
+	for _, blob := range existing {
+		if blob.ExpiresAt != 0 {
+			ids = append(ids, storage.TypeAndKey{Type: blob.Type, Key: blob.Key})
+		}
+	}
+	return e.delete(tx, networkID, ids)
+}
+
+func (e expiries) delete(tx *sql.Tx, networkID string, ids []storage.TypeAndKey) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := e.builder.Delete(e.tableName).
+		Where(getWhereCondition(networkID, ids)).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "error clearing blob expiries")
+	}
+	return nil
+}
+
+// getExpired returns the IDs of up to limit blobs which expired at or before
+// now, keyed by network ID.
+// The returned rows are locked until the end of the transaction, and rows
+// locked by other transactions are skipped, so concurrent sweepers don't
+// contend with each other.
+func (e expiries) getExpired(tx *sql.Tx, now int64, limit uint64) (map[string]storage.TKs, error) {
+	selectBuilder := e.builder.Select(nidCol, typeCol, keyCol).
+		From(e.tableName).
+		Where(sq.LtOrEq{expiresAtCol: now}).
+		OrderBy(expiresAtCol).
+		Limit(limit)
+	rows, err := e.builder.ForUpdateSkipLocked(selectBuilder).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query expired blobs")
+	}
+	defer sqorc.CloseRowsLogOnError(rows, "getExpired")
+
+	ret := map[string]storage.TKs{}
+	for rows.Next() {
+		var nid string
+		var tk storage.TypeAndKey
+		err = rows.Scan(&nid, &tk.Type, &tk.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan expired blob row")
+		}
+		ret[nid] = append(ret[nid], tk)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, errors.Wrap(err, "sql rows err")
+	}
+	return ret, nil
+}
+
+// liveCondition matches rows of the blob table which haven't expired as of
+// now.
+func (e expiries) liveCondition(blobTableName string, now int64) sq.Sqlizer {
+	return sq.Expr(
+		fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s WHERE %s AND %s.%s <= ?)", e.tableName, e.matchCondition(blobTableName), e.tableName, expiresAtCol),
+		now,
+	)
+}
+
+// livePredicate is the ent equivalent of liveCondition.
+func (e expiries) livePredicate(blobTableName string, now int64) predicate.Blob {
+	return func(s *entsql.Selector) {
+		expired := fmt.Sprintf(
+			"SELECT %[1]s.%[3]s FROM %[1]s WHERE %[1]s.%[4]s = %[2]s.%[4]s AND %[1]s.%[5]s = %[2]s.%[5]s AND %[1]s.%[6]s <= %[7]d",
+			e.tableName, blobTableName, keyCol, nidCol, typeCol, expiresAtCol, now,
+		)
+		s.Where(entsql.Not(entsql.In(s.C(blob.FieldKey), entsql.Raw(expired))))
+	}
+}
+
+// expiresAtColumn returns a column selecting the expiry time of rows of the
+// blob table, or 0 if they don't expire.
+func (e expiries) expiresAtColumn(blobTableName string) string {
+	return fmt.Sprintf("COALESCE((SELECT %s.%s FROM %s WHERE %s), 0)", e.tableName, expiresAtCol, e.tableName, e.matchCondition(blobTableName))
+}
+
+// matchCondition matches rows of the expiry table to the row of the blob
+// table in the enclosing query.
+func (e expiries) matchCondition(blobTableName string) string {
+	return fmt.Sprintf(
+		"%[1]s.%[3]s = %[2]s.%[3]s AND %[1]s.%[4]s = %[2]s.%[4]s AND %[1]s.%[5]s = %[2]s.%[5]s",
+		e.tableName, blobTableName, nidCol, typeCol, keyCol,
+	)
+}
@@ -14,10 +14,15 @@
 package blobstore_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"sort"
 	"testing"
+	"time"
 
 	"magma/orc8r/cloud/go/blobstore"
+	"magma/orc8r/cloud/go/clock"
 	"magma/orc8r/cloud/go/storage"
 	magmaerrors "magma/orc8r/lib/go/errors"
 
@@ -281,6 +286,487 @@ func integration(t *testing.T, fact blobstore.BlobStorageFactory) {
 	assert.Equal(t, blobstore.Blobs{{Type: "t3", Key: "k3", Value: []byte("v5"), Version: 2}}, getManyActual)
 }
 
+func keyPrefixIntegration(t *testing.T, fact blobstore.BlobStorageFactory) {
+	err := fact.InitializeFactory()
+	assert.NoError(t, err)
+	store, err := fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	err = store.CreateOrUpdate("network", blobstore.Blobs{
+		{Type: "t", Key: "a_b-1", Value: []byte("v1")},
+		{Type: "t", Key: "axb-1", Value: []byte("v2")},
+		{Type: "t", Key: "a%b-1", Value: []byte("v3")},
+		{Type: "t", Key: "a%%b-1", Value: []byte("v4")},
+		{Type: "t", Key: "a!b-1", Value: []byte("v5")},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	// LIKE wildcards and the escape character in prefixes match literally
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	for prefix, expected := range map[string]string{
+		"a_b-": "a_b-1",
+		"a%b-": "a%b-1",
+		"a!b-": "a!b-1",
+	} {
+		actual, err := store.Search(blobstore.CreateSearchFilter(strPtr("network"), nil, nil, strPtr(prefix)), blobstore.GetDefaultLoadCriteria())
+		assert.NoError(t, err)
+		assert.Len(t, actual["network"], 1, prefix)
+		if len(actual["network"]) == 1 {
+			assert.Equal(t, expected, actual["network"][0].Key)
+		}
+	}
+
+	// Pages only hold keys with the prefix, so they're only short at the end
+	criteria := blobstore.LoadCriteria{PageSize: 1}
+	actual, err := store.Search(blobstore.CreateSearchFilter(strPtr("network"), nil, nil, strPtr("a_b-")), criteria)
+	assert.NoError(t, err)
+	assert.Len(t, actual["network"], 1)
+	if len(actual["network"]) == 1 {
+		assert.Equal(t, "a_b-1", actual["network"][0].Key)
+	}
+	assert.NoError(t, store.Commit())
+}
+
+func changeFeedIntegration(t *testing.T, fact blobstore.BlobStorageFactory) {
+	err := fact.InitializeFactory()
+	assert.NoError(t, err)
+
+	// Writes aren't recorded without a live subscription
+	store, err := fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	err = store.CreateOrUpdate("network0", blobstore.Blobs{{Type: "t0", Key: "k0", Value: []byte("v0")}})
+	assert.NoError(t, err)
+	changes, err := store.GetChanges(blobstore.SearchFilter{}, 0, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.NoError(t, store.AckChanges("sub1", 0))
+	assert.NoError(t, store.Commit())
+
+	// Create, update, increment, delete
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	err = store.CreateOrUpdate("network1", blobstore.Blobs{
+		{Type: "t1", Key: "k1", Value: []byte("v1")},
+		{Type: "t2", Key: "k2", Value: []byte("v2"), Version: 5},
+	})
+	assert.NoError(t, err)
+	err = store.CreateOrUpdate("network2", blobstore.Blobs{{Type: "t1", Key: "k3", Value: []byte("v3")}})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	err = store.CreateOrUpdate("network1", blobstore.Blobs{{Type: "t1", Key: "k1", Value: []byte("v11")}})
+	assert.NoError(t, err)
+	err = store.IncrementVersion("network1", storage.TypeAndKey{Type: "t2", Key: "k2"})
+	assert.NoError(t, err)
+	err = store.IncrementVersion("network1", storage.TypeAndKey{Type: "t3", Key: "k4"})
+	assert.NoError(t, err)
+	err = store.Delete("network1", []storage.TypeAndKey{{Type: "t1", Key: "k1"}, {Type: "t9", Key: "k9"}})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	// Rolled back writes aren't recorded
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	err = store.CreateOrUpdate("network1", blobstore.Blobs{{Type: "t1", Key: "k5", Value: []byte("v5")}})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Rollback())
+
+	expected := []blobstore.Change{
+		{Cursor: 1, NetworkID: "network1", Type: "t1", Key: "k1", Version: 0, Kind: blobstore.ChangeCreated},
+		{Cursor: 2, NetworkID: "network1", Type: "t2", Key: "k2", Version: 5, Kind: blobstore.ChangeCreated},
+		{Cursor: 3, NetworkID: "network2", Type: "t1", Key: "k3", Version: 0, Kind: blobstore.ChangeCreated},
+		{Cursor: 4, NetworkID: "network1", Type: "t1", Key: "k1", Version: 1, Kind: blobstore.ChangeUpdated},
+		{Cursor: 5, NetworkID: "network1", Type: "t2", Key: "k2", Version: 6, Kind: blobstore.ChangeUpdated},
+		{Cursor: 6, NetworkID: "network1", Type: "t3", Key: "k4", Version: 1, Kind: blobstore.ChangeCreated},
+		{Cursor: 7, NetworkID: "network1", Type: "t1", Key: "k1", Version: 1, Kind: blobstore.ChangeDeleted},
+	}
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	changes, err = store.GetChanges(blobstore.SearchFilter{}, 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, changes)
+
+	// Resume from a cursor, with a limit
+	changes, err = store.GetChanges(blobstore.SearchFilter{}, 3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, expected[3:5], changes)
+
+	// Filtered
+	changes, err = store.GetChanges(blobstore.CreateSearchFilter(strPtr("network1"), []string{"t1"}, nil, nil), 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []blobstore.Change{expected[0], expected[3], expected[6]}, changes)
+	changes, err = store.GetChanges(blobstore.CreateSearchFilter(nil, nil, []string{"k3"}, nil), 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []blobstore.Change{expected[2]}, changes)
+	assert.NoError(t, store.Commit())
+
+	// Watch from a cursor, stopping once caught up
+	ctx, cancel := context.WithCancel(context.Background())
+	var watched []blobstore.Change
+	err = fact.Watch(ctx, blobstore.CreateSearchFilter(strPtr("network1"), nil, nil, nil), 4, func(changes []blobstore.Change) error {
+		watched = append(watched, changes...)
+		if changes[len(changes)-1].Cursor == 7 {
+			cancel()
+		}
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, expected[4:], watched)
+
+	// Handler errors stop the watch
+	err = fact.Watch(context.Background(), blobstore.SearchFilter{}, 0, func(changes []blobstore.Change) error {
+		return errors.New("handler error")
+	})
+	assert.EqualError(t, err, "handler error")
+
+	// Once all subscriptions expire, consumers can't resume across the gap
+	now := time.Now()
+	clock.SetAndFreezeClock(t, now.Add(blobstore.SubscriptionTTL+time.Minute))
+	defer clock.UnfreezeClock(t)
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, store.AckChanges("sub1", 7))
+	_, err = store.GetChanges(blobstore.SearchFilter{}, 7, 100)
+	assert.Equal(t, blobstore.ErrChangesCompacted, err)
+	changes, err = store.GetChanges(blobstore.SearchFilter{}, 0, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.NoError(t, store.Commit())
+
+	// Changes are trimmed once acknowledged by all live subscriptions
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, store.AckChanges("sub2", 8))
+	err = store.CreateOrUpdate("network1", blobstore.Blobs{{Type: "t1", Key: "k1", Value: []byte("v1")}})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	err = store.CreateOrUpdate("network1", blobstore.Blobs{{Type: "t1", Key: "k1", Value: []byte("v2")}})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	changes, err = store.GetChanges(blobstore.SearchFilter{}, 8, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{9, 10}, getCursors(changes))
+	assert.NoError(t, store.AckChanges("sub1", 10))
+	changes, err = store.GetChanges(blobstore.SearchFilter{}, 8, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{9, 10}, getCursors(changes))
+	assert.NoError(t, store.AckChanges("sub2", 9))
+	_, err = store.GetChanges(blobstore.SearchFilter{}, 8, 100)
+	assert.Equal(t, blobstore.ErrChangesCompacted, err)
+	changes, err = store.GetChanges(blobstore.SearchFilter{}, 9, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{10}, getCursors(changes))
+	assert.NoError(t, store.Commit())
+
+	// Changes are trimmed after the retention period, even if a live
+	// subscription hasn't acknowledged them
+	for elapsed := time.Duration(0); elapsed <= blobstore.ChangeRetention; elapsed += blobstore.SubscriptionTTL / 2 {
+		clock.SetAndFreezeClock(t, now.Add(blobstore.SubscriptionTTL+time.Minute+elapsed+time.Minute))
+		store, err = fact.StartTransaction(nil)
+		assert.NoError(t, err)
+		assert.NoError(t, store.AckChanges("sub2", 9))
+		assert.NoError(t, store.Commit())
+	}
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	_, err = store.GetChanges(blobstore.SearchFilter{}, 9, 100)
+	assert.Equal(t, blobstore.ErrChangesCompacted, err)
+	changes, err = store.GetChanges(blobstore.SearchFilter{}, 10, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.NoError(t, store.Commit())
+}
+
+func getCursors(changes []blobstore.Change) []uint64 {
+	var cursors []uint64
+	for _, c := range changes {
+		cursors = append(cursors, c.Cursor)
+	}
+	return cursors
+}
+
+func compareAndSwapIntegration(t *testing.T, fact blobstore.BlobStorageFactory) {
+	err := fact.InitializeFactory()
+	assert.NoError(t, err)
+
+	tk1 := storage.TypeAndKey{Type: "t1", Key: "k1"}
+	tk2 := storage.TypeAndKey{Type: "t1", Key: "k2"}
+	tk3 := storage.TypeAndKey{Type: "t2", Key: "k3"}
+
+	// Create, expecting blobs to not exist
+	store, err := fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	err = store.CompareAndSwap("network", map[storage.TypeAndKey]uint64{tk1: storage.VersionAbsent, tk2: storage.VersionAbsent}, blobstore.Blobs{
+		{Type: "t1", Key: "k1", Value: []byte("v1")},
+		{Type: "t1", Key: "k2", Value: []byte("v2")},
+		{Type: "t2", Key: "k3", Value: []byte("v3")},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	// Update with matching versions
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	err = store.CompareAndSwap("network", map[storage.TypeAndKey]uint64{tk1: 0, tk3: 0}, blobstore.Blobs{
+		{Type: "t1", Key: "k1", Value: []byte("v1")},
+	})
+	assert.NoError(t, err)
+	err = store.CompareAndSwap("network", map[storage.TypeAndKey]uint64{tk1: 1, tk2: 0}, blobstore.Blobs{
+		{Type: "t1", Key: "k1", Value: []byte("v1a")},
+		{Type: "t1", Key: "k2", Value: []byte("v2a")},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	blobs, err := store.GetMany("network", []storage.TypeAndKey{tk1, tk2, tk3})
+	assert.NoError(t, err)
+	assert.Equal(t, blobstore.Blobs{
+		{Type: "t1", Key: "k1", Value: []byte("v1a"), Version: 2},
+		{Type: "t1", Key: "k2", Value: []byte("v2a"), Version: 1},
+		{Type: "t2", Key: "k3", Value: []byte("v3"), Version: 0},
+	}, blobs)
+	assert.NoError(t, store.Commit())
+
+	// Mismatched versions, including an unwritten blob and a missing blob
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	tk4 := storage.TypeAndKey{Type: "t2", Key: "k4"}
+	err = store.CompareAndSwap("network", map[storage.TypeAndKey]uint64{tk1: 1, tk2: 1, tk3: 1, tk4: 3}, blobstore.Blobs{
+		{Type: "t1", Key: "k1", Value: []byte("v1b")},
+		{Type: "t1", Key: "k2", Value: []byte("v2b")},
+	})
+	assert.Equal(t, &storage.VersionConflictError{Versions: map[storage.TypeAndKey]uint64{tk1: 2, tk3: 0, tk4: storage.VersionAbsent}}, err)
+	assert.EqualError(t, err, "version conflict on [t1-k1 t2-k3 t2-k4]")
+
+	// Blob expected to not exist already exists
+	err = store.CompareAndSwap("network", map[storage.TypeAndKey]uint64{tk2: storage.VersionAbsent}, blobstore.Blobs{
+		{Type: "t1", Key: "k2", Value: []byte("v2b")},
+	})
+	assert.Equal(t, &storage.VersionConflictError{Versions: map[storage.TypeAndKey]uint64{tk2: 1}}, err)
+
+	// Blob expected to not exist already exists at version 0
+	err = store.CompareAndSwap("network", map[storage.TypeAndKey]uint64{tk3: storage.VersionAbsent}, blobstore.Blobs{
+		{Type: "t2", Key: "k3", Value: []byte("v3b")},
+	})
+	assert.Equal(t, &storage.VersionConflictError{Versions: map[storage.TypeAndKey]uint64{tk3: 0}}, err)
+	assert.NoError(t, store.Rollback())
+
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	blob, err := store.Get("network", tk1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v1a"), blob.Value)
+	assert.NoError(t, store.Commit())
+}
+
+func expiryIntegration(t *testing.T, fact blobstore.BlobStorageFactory) {
+	err := fact.InitializeFactory()
+	assert.NoError(t, err)
+
+	tk1 := storage.TypeAndKey{Type: "t1", Key: "k1"}
+	tk2 := storage.TypeAndKey{Type: "t1", Key: "k2"}
+	tk3 := storage.TypeAndKey{Type: "t2", Key: "k3"}
+	tk4 := storage.TypeAndKey{Type: "t2", Key: "k4"}
+
+	clock.SetAndFreezeClock(t, time.Unix(50, 0))
+	defer clock.UnfreezeClock(t)
+
+	store, err := fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, store.AckChanges("sub1", 0))
+	err = store.CreateOrUpdate("network1", blobstore.Blobs{
+		{Type: "t1", Key: "k1", Value: []byte("v1"), ExpiresAt: 100},
+		{Type: "t1", Key: "k2", Value: []byte("v2"), ExpiresAt: 200},
+		{Type: "t2", Key: "k3", Value: []byte("v3")},
+	})
+	assert.NoError(t, err)
+	err = store.CreateOrUpdate("network2", blobstore.Blobs{
+		{Type: "t2", Key: "k4", Value: []byte("v4"), ExpiresAt: 100},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	// Nothing has expired yet
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	deleted, err := store.DeleteExpired(time.Unix(99, 0), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, deleted)
+	assert.NoError(t, store.Commit())
+
+	// Rewriting a blob replaces its expiry, clearing it when unset
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	err = store.CreateOrUpdate("network1", blobstore.Blobs{
+		{Type: "t1", Key: "k2", Value: []byte("v2a")},
+		{Type: "t2", Key: "k3", Value: []byte("v3a"), ExpiresAt: 300},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	// Expired blobs are hidden from reads before they're swept
+	clock.SetAndFreezeClock(t, time.Unix(150, 0))
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	_, err = store.Get("network1", tk1)
+	assert.Equal(t, magmaerrors.ErrNotFound, err)
+	blobs, err := store.GetMany("network1", []storage.TypeAndKey{tk1, tk2, tk3})
+	assert.NoError(t, err)
+	assert.Equal(t, []storage.TypeAndKey{tk2, tk3}, blobs.TKs())
+	searched, err := store.Search(blobstore.CreateSearchFilter(nil, []string{"t2"}, nil, nil), blobstore.GetDefaultLoadCriteria())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]blobstore.Blobs{"network1": {{Type: "t2", Key: "k3", Value: []byte("v3a"), Version: 1}}}, searched)
+	keys, err := store.GetExistingKeys([]string{"k1", "k2"}, blobstore.SearchFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"k2"}, keys)
+
+	// Create-only writes succeed over unswept expired blobs
+	err = store.CompareAndSwap("network2", map[storage.TypeAndKey]uint64{tk4: storage.VersionAbsent}, blobstore.Blobs{
+		{Type: "t2", Key: "k4", Value: []byte("v4a")},
+	})
+	assert.NoError(t, err)
+	blob, err := store.Get("network2", tk4)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v4a"), blob.Value)
+	assert.NoError(t, store.Rollback())
+
+	// Expired blobs are deleted up to the limit, earliest expiry first
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	deleted, err = store.DeleteExpired(time.Unix(1000, 0), 1)
+	assert.NoError(t, err)
+	assert.Len(t, deleted, 1)
+	deleted2, err := store.DeleteExpired(time.Unix(1000, 0), 10)
+	assert.NoError(t, err)
+	for nid, tks := range deleted2 {
+		deleted[nid] = append(deleted[nid], tks...)
+	}
+	assert.Equal(t, map[string]storage.TKs{"network1": {tk1, tk3}, "network2": {tk4}}, sortTKsByNetwork(deleted))
+	assert.NoError(t, store.Commit())
+
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	blobs, err = store.GetMany("network1", []storage.TypeAndKey{tk1, tk2, tk3})
+	assert.NoError(t, err)
+	assert.Equal(t, blobstore.Blobs{{Type: "t1", Key: "k2", Value: []byte("v2a"), Version: 1}}, blobs)
+	deleted, err = store.DeleteExpired(time.Unix(1000, 0), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	// Deletions of expired blobs are recorded in the change log
+	changes, err := store.GetChanges(blobstore.SearchFilter{}, 0, 100)
+	assert.NoError(t, err)
+	var deletedIDs []storage.TypeAndKey
+	for _, c := range changes {
+		if c.Kind == blobstore.ChangeDeleted {
+			deletedIDs = append(deletedIDs, storage.TypeAndKey{Type: c.Type, Key: c.Key})
+		}
+	}
+	assert.ElementsMatch(t, []storage.TypeAndKey{tk1, tk3, tk4}, deletedIDs)
+	assert.NoError(t, store.Commit())
+
+	// Sweeper deletes expired blobs in batches
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	var toCreate blobstore.Blobs
+	for i := 0; i < 150; i++ {
+		toCreate = append(toCreate, blobstore.Blob{Type: "t3", Key: fmt.Sprintf("k%d", i), Value: []byte("v"), ExpiresAt: 500})
+	}
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	for i := 0; i < len(toCreate); i += 10 {
+		assert.NoError(t, store.CreateOrUpdate("network1", toCreate[i:i+10]))
+	}
+	assert.NoError(t, store.Commit())
+
+	n, err := blobstore.NewSweeper("table", fact, time.Minute).Sweep()
+	assert.NoError(t, err)
+	assert.Equal(t, 150, n)
+
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	keys, err = blobstore.ListKeys(store, "network1", "t3")
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+	assert.NoError(t, store.Commit())
+}
+
+func paginatedSearchIntegration(t *testing.T, fact blobstore.BlobStorageFactory) {
+	err := fact.InitializeFactory()
+	assert.NoError(t, err)
+
+	store, err := fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	err = store.CreateOrUpdate("network1", blobstore.Blobs{
+		{Type: "t2", Key: "k1", Value: []byte("v3")},
+		{Type: "t1", Key: "k2", Value: []byte("v2")},
+		{Type: "t1", Key: "k1", Value: []byte("v1")},
+		{Type: "t2", Key: "k2", Value: []byte("v4")},
+		{Type: "t3", Key: "k1", Value: []byte("v5")},
+	})
+	assert.NoError(t, err)
+	err = store.CreateOrUpdate("network2", blobstore.Blobs{
+		{Type: "t1", Key: "k1", Value: []byte("v6")},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	store, err = fact.StartTransaction(nil)
+	assert.NoError(t, err)
+	filter := blobstore.CreateSearchFilter(strPtr("network1"), []string{"t1", "t2"}, nil, nil)
+
+	// Pages are ordered by type then key
+	criteria := blobstore.LoadCriteria{LoadValue: true, PageSize: 3}
+	actual, err := store.Search(filter, criteria)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]blobstore.Blobs{"network1": {
+		{Type: "t1", Key: "k1", Value: []byte("v1")},
+		{Type: "t1", Key: "k2", Value: []byte("v2")},
+		{Type: "t2", Key: "k1", Value: []byte("v3")},
+	}}, actual)
+
+	criteria.StartAfter = &storage.TypeAndKey{Type: "t2", Key: "k1"}
+	actual, err = store.Search(filter, criteria)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]blobstore.Blobs{"network1": {
+		{Type: "t2", Key: "k2", Value: []byte("v4")},
+	}}, actual)
+
+	criteria.StartAfter = &storage.TypeAndKey{Type: "t2", Key: "k2"}
+	actual, err = store.Search(filter, criteria)
+	assert.NoError(t, err)
+	assert.Empty(t, actual)
+
+	// Start after without page size returns the remainder
+	actual, err = store.Search(filter, blobstore.LoadCriteria{StartAfter: &storage.TypeAndKey{Type: "t1", Key: "k2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]blobstore.Blobs{"network1": {
+		{Type: "t2", Key: "k1"},
+		{Type: "t2", Key: "k2"},
+	}}, actual)
+
+	// Paginated searches must be within a network
+	_, err = store.Search(blobstore.SearchFilter{}, blobstore.LoadCriteria{PageSize: 1})
+	assert.EqualError(t, err, "paginated searches must specify a network ID")
+	assert.NoError(t, store.Commit())
+}
+
+func sortTKsByNetwork(tksByNetwork map[string]storage.TKs) map[string]storage.TKs {
+	for _, tks := range tksByNetwork {
+		sort.Slice(tks, getTKsComparator(tks))
+	}
+	return tksByNetwork
+}
+
 type searchTestCase struct {
 	nid       *string
 	types     []string
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	blobstore "magma/orc8r/cloud/go/blobstore"
 
 	mock "github.com/stretchr/testify/mock"
@@ -51,3 +53,17 @@ func (_m *BlobStorageFactory) StartTransaction(opts *storage.TxOptions) (blobsto
 
 	return r0, r1
 }
+
+// Watch provides a mock function with given fields: ctx, filter, cursor, handler
+func (_m *BlobStorageFactory) Watch(ctx context.Context, filter blobstore.SearchFilter, cursor uint64, handler blobstore.ChangeHandler) error {
+	ret := _m.Called(ctx, filter, cursor, handler)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, blobstore.SearchFilter, uint64, blobstore.ChangeHandler) error); ok {
+		r0 = rf(ctx, filter, cursor, handler)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
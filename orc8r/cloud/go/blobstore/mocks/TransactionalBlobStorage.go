@@ -8,6 +8,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	storage "magma/orc8r/cloud/go/storage"
+
+	time "time"
 )
 
 // TransactionalBlobStorage is an autogenerated mock type for the TransactionalBlobStorage type
@@ -15,6 +17,20 @@ type TransactionalBlobStorage struct {
 	mock.Mock
 }
 
+// AckChanges provides a mock function with given fields: subscriptionID, cursor
+func (_m *TransactionalBlobStorage) AckChanges(subscriptionID string, cursor uint64) error {
+	ret := _m.Called(subscriptionID, cursor)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, uint64) error); ok {
+		r0 = rf(subscriptionID, cursor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Commit provides a mock function with given fields:
 func (_m *TransactionalBlobStorage) Commit() error {
 	ret := _m.Called()
@@ -29,6 +45,20 @@ func (_m *TransactionalBlobStorage) Commit() error {
 	return r0
 }
 
+// CompareAndSwap provides a mock function with given fields: networkID, expected, blobs
+func (_m *TransactionalBlobStorage) CompareAndSwap(networkID string, expected map[storage.TypeAndKey]uint64, blobs blobstore.Blobs) error {
+	ret := _m.Called(networkID, expected, blobs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, map[storage.TypeAndKey]uint64, blobstore.Blobs) error); ok {
+		r0 = rf(networkID, expected, blobs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateOrUpdate provides a mock function with given fields: networkID, blobs
 func (_m *TransactionalBlobStorage) CreateOrUpdate(networkID string, blobs blobstore.Blobs) error {
 	ret := _m.Called(networkID, blobs)
@@ -57,6 +87,29 @@ func (_m *TransactionalBlobStorage) Delete(networkID string, ids []storage.TypeA
 	return r0
 }
 
+// DeleteExpired provides a mock function with given fields: now, limit
+func (_m *TransactionalBlobStorage) DeleteExpired(now time.Time, limit uint64) (map[string]storage.TKs, error) {
+	ret := _m.Called(now, limit)
+
+	var r0 map[string]storage.TKs
+	if rf, ok := ret.Get(0).(func(time.Time, uint64) map[string]storage.TKs); ok {
+		r0 = rf(now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]storage.TKs)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time, uint64) error); ok {
+		r1 = rf(now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Get provides a mock function with given fields: networkID, id
 func (_m *TransactionalBlobStorage) Get(networkID string, id storage.TypeAndKey) (blobstore.Blob, error) {
 	ret := _m.Called(networkID, id)
@@ -78,6 +131,29 @@ func (_m *TransactionalBlobStorage) Get(networkID string, id storage.TypeAndKey)
 	return r0, r1
 }
 
+// GetChanges provides a mock function with given fields: filter, cursor, limit
+func (_m *TransactionalBlobStorage) GetChanges(filter blobstore.SearchFilter, cursor uint64, limit uint64) ([]blobstore.Change, error) {
+	ret := _m.Called(filter, cursor, limit)
+
+	var r0 []blobstore.Change
+	if rf, ok := ret.Get(0).(func(blobstore.SearchFilter, uint64, uint64) []blobstore.Change); ok {
+		r0 = rf(filter, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]blobstore.Change)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(blobstore.SearchFilter, uint64, uint64) error); ok {
+		r1 = rf(filter, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetExistingKeys provides a mock function with given fields: keys, filter
 func (_m *TransactionalBlobStorage) GetExistingKeys(keys []string, filter blobstore.SearchFilter) ([]string, error) {
 	ret := _m.Called(keys, filter)
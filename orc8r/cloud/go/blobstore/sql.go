@@ -18,7 +18,10 @@ import (
 	"database/sql"
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 
+	"magma/orc8r/cloud/go/clock"
 	"magma/orc8r/cloud/go/sqorc"
 	"magma/orc8r/cloud/go/storage"
 	magmaerrors "magma/orc8r/lib/go/errors"
@@ -35,18 +38,36 @@ const (
 	keyCol  = "\"key\""
 	valCol  = "value"
 	verCol  = "version"
+
+	// likeEscapeChar escapes LIKE wildcards in key prefixes. Backslash isn't
+	// used since MySQL also treats it as an escape in string literals.
+	likeEscapeChar = "!"
+)
+
+var likeEscaper = strings.NewReplacer(
+	likeEscapeChar, likeEscapeChar+likeEscapeChar,
+	"%", likeEscapeChar+"%",
+	"_", likeEscapeChar+"_",
 )
 
 // NewSQLBlobStorageFactory returns a BlobStorageFactory implementation which
 // will return storage APIs backed by SQL.
 func NewSQLBlobStorageFactory(tableName string, db *sql.DB, sqlBuilder sqorc.StatementBuilder) BlobStorageFactory {
-	return &sqlBlobStoreFactory{tableName: tableName, db: db, builder: sqlBuilder}
+	return &sqlBlobStoreFactory{
+		tableName: tableName,
+		db:        db,
+		builder:   sqlBuilder,
+		changes:   newChangeLog(tableName, sqlBuilder),
+		expiries:  newExpiries(tableName, sqlBuilder),
+	}
 }
 
 type sqlBlobStoreFactory struct {
 	tableName string
 	db        *sql.DB
 	builder   sqorc.StatementBuilder
+	changes   changeLog
+	expiries  expiries
 }
 
 func (fact *sqlBlobStoreFactory) StartTransaction(opts *storage.TxOptions) (TransactionalBlobStorage, error) {
@@ -54,7 +75,11 @@ func (fact *sqlBlobStoreFactory) StartTransaction(opts *storage.TxOptions) (Tran
 	if err != nil {
 		return nil, err
 	}
-	return &sqlBlobStorage{tableName: fact.tableName, tx: tx, builder: fact.builder}, nil
+	return &sqlBlobStorage{tableName: fact.tableName, tx: tx, builder: fact.builder, changes: fact.changes, expiries: fact.expiries}, nil
+}
+
+func (fact *sqlBlobStoreFactory) Watch(ctx context.Context, filter SearchFilter, cursor uint64, handler ChangeHandler) error {
+	return watch(ctx, fact, filter, cursor, handler)
 }
 
 func getSqlOpts(opts *storage.TxOptions) *sql.TxOptions {
@@ -73,6 +98,12 @@ func (fact *sqlBlobStoreFactory) InitializeFactory() error {
 		return err
 	}
 	err = fact.initTable(tx, fact.tableName)
+	if err == nil {
+		err = fact.changes.initTables(tx)
+	}
+	if err == nil {
+		err = fact.expiries.initTables(tx)
+	}
 	if err != nil {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
 			glog.Errorf("error rolling back transaction initializing blobstore factory: %s", rollbackErr)
@@ -101,6 +132,8 @@ type sqlBlobStorage struct {
 	tableName string
 	tx        *sql.Tx
 	builder   sqorc.StatementBuilder
+	changes   changeLog
+	expiries  expiries
 }
 
 func (store *sqlBlobStorage) Commit() error {
@@ -139,7 +172,10 @@ func (store *sqlBlobStorage) GetMany(networkID string, ids []storage.TypeAndKey)
 		return nil, err
 	}
 
-	whereCondition := getWhereCondition(networkID, ids)
+	whereCondition := sq.And{
+		getWhereCondition(networkID, ids),
+		store.expiries.liveCondition(store.tableName, clock.Now().Unix()),
+	}
 	rows, err := store.builder.Select(typeCol, keyCol, valCol, verCol).From(store.tableName).
 		Where(whereCondition).
 		RunWith(store.tx).
@@ -168,38 +204,71 @@ func (store *sqlBlobStorage) GetMany(networkID string, ids []storage.TypeAndKey)
 	return blobs, nil
 }
 
+// getExisting returns the stored blobs with the passed IDs, with their expiry
+// times, including expired blobs which haven't been swept yet.
+func (store *sqlBlobStorage) getExisting(networkID string, ids []storage.TypeAndKey) (Blobs, error) {
+	if err := store.validateTx(); err != nil {
+		return nil, err
+	}
+
+	rows, err := store.builder.Select(typeCol, keyCol, valCol, verCol, store.expiries.expiresAtColumn(store.tableName)).
+		From(store.tableName).
+		Where(getWhereCondition(networkID, ids)).
+		RunWith(store.tx).
+		Query()
+	if err != nil {
+		return nil, err
+	}
+	defer sqorc.CloseRowsLogOnError(rows, "getExisting")
+
+	var blobs Blobs
+	for rows.Next() {
+		blob := Blob{}
+		err = rows.Scan(&blob.Type, &blob.Key, &blob.Value, &blob.Version, &blob.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, blob)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, errors.Wrap(err, "sql rows err")
+	}
+	return blobs, nil
+}
+
 func (store *sqlBlobStorage) Search(filter SearchFilter, criteria LoadCriteria) (map[string]Blobs, error) {
 	ret := map[string]Blobs{}
 	if err := store.validateTx(); err != nil {
 		return ret, err
 	}
 
+	if err := validateSearch(filter, criteria); err != nil {
+		return ret, err
+	}
+
 	// Get select columns from load criteria
 	selectCols := []string{nidCol, typeCol, keyCol, verCol}
 	if criteria.LoadValue {
 		selectCols = append(selectCols, valCol)
 	}
 
-	// Get where condition from search filter
-	// Use and condition to deterministically order clauses for testing
-	whereCondition := sq.And{}
-	if filter.NetworkID != nil {
-		whereCondition = append(whereCondition, sq.Eq{nidCol: *filter.NetworkID})
+	whereCondition := append(
+		getSearchWhereCondition(filter),
+		store.expiries.liveCondition(store.tableName, clock.Now().Unix()),
+	)
+	if criteria.StartAfter != nil {
+		whereCondition = append(whereCondition, getStartAfterCondition(*criteria.StartAfter))
 	}
-	if !funk.IsEmpty(filter.Types) {
-		whereCondition = append(whereCondition, sq.Eq{typeCol: filter.GetTypes()})
+	selectBuilder := store.builder.Select(selectCols...).From(store.tableName).
+		Where(whereCondition)
+	if criteria.isPaginated() {
+		selectBuilder = selectBuilder.OrderBy(typeCol, keyCol)
 	}
-	// Apply only one of prefix or match predicates; prefix takes precedence
-	if !funk.IsEmpty(filter.KeyPrefix) {
-		whereCondition = append(whereCondition, sq.Like{keyCol: fmt.Sprintf("%s%%", *filter.KeyPrefix)})
-	} else {
-		if !funk.IsEmpty(filter.Keys) {
-			whereCondition = append(whereCondition, sq.Eq{keyCol: filter.GetKeys()})
-		}
+	if criteria.PageSize != 0 {
+		selectBuilder = selectBuilder.Limit(criteria.PageSize)
 	}
-
-	rows, err := store.builder.Select(selectCols...).From(store.tableName).
-		Where(whereCondition).
+	rows, err := selectBuilder.
 		RunWith(store.tx).
 		Query()
 	if err != nil {
@@ -233,27 +302,48 @@ func (store *sqlBlobStorage) Search(filter SearchFilter, criteria LoadCriteria)
 }
 
 func (store *sqlBlobStorage) CreateOrUpdate(networkID string, blobs Blobs) error {
-	// defer tx validation to GetMany
-	existingBlobs, err := store.GetMany(networkID, getBlobIDs(blobs))
+	return store.createOrUpdate(networkID, nil, blobs)
+}
+
+func (store *sqlBlobStorage) CompareAndSwap(networkID string, expected map[storage.TypeAndKey]uint64, blobs Blobs) error {
+	return store.createOrUpdate(networkID, expected, blobs)
+}
+
+// createOrUpdate writes blobs, checking the versions in expected if non-nil.
+// Writes to blobs with expected versions are guarded against concurrent
+// writes which commit after the versions are checked.
+//
+// Expired blobs which haven't been swept yet are treated as not existing by
+// the version checks, and are overwritten.
+func (store *sqlBlobStorage) createOrUpdate(networkID string, expected map[storage.TypeAndKey]uint64, blobs Blobs) error {
+	// defer tx validation to getExisting
+	existingBlobs, err := store.getExisting(networkID, getBlobIDsWithExpected(blobs, expected))
 	if err != nil {
 		return fmt.Errorf("Error reading existing blobs: %s", err)
 	}
+	if err := storage.CheckVersions(expected, getVersions(getLive(existingBlobs, clock.Now()))); err != nil {
+		return err
+	}
 	blobsToCreateAndChange := partitionBlobsToCreateAndChange(blobs, existingBlobs)
 
 	if len(blobsToCreateAndChange.blobsToChange) > 0 {
-		err := store.updateExistingBlobs(networkID, blobsToCreateAndChange.blobsToChange)
+		err := store.updateExistingBlobs(networkID, blobsToCreateAndChange.blobsToChange, expected)
 		if err != nil {
 			return err
 		}
 	}
 	if len(blobsToCreateAndChange.blobsToCreate) > 0 {
-		err := store.insertNewBlobs(networkID, blobsToCreateAndChange.blobsToCreate)
+		err := store.insertNewBlobs(networkID, blobsToCreateAndChange.blobsToCreate, expected)
 		if err != nil {
 			return err
 		}
 	}
+	err = store.expiries.set(store.tx, networkID, blobs, existingBlobs)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return store.changes.record(store.tx, networkID, blobsToCreateAndChange.getChanges())
 }
 
 func (store *sqlBlobStorage) GetExistingKeys(keys []string, filter SearchFilter) ([]string, error) {
@@ -271,7 +361,7 @@ func (store *sqlBlobStorage) GetExistingKeys(keys []string, filter SearchFilter)
 		whereConditions = append(whereConditions, and)
 	}
 	rows, err := store.builder.Select(keyCol).Distinct().From(store.tableName).
-		Where(whereConditions).
+		Where(sq.And{whereConditions, store.expiries.liveCondition(store.tableName, clock.Now().Unix())}).
 		RunWith(store.tx).
 		Query()
 	if err != nil {
@@ -295,24 +385,53 @@ func (store *sqlBlobStorage) GetExistingKeys(keys []string, filter SearchFilter)
 }
 
 func (store *sqlBlobStorage) Delete(networkID string, ids []storage.TypeAndKey) error {
-	if err := store.validateTx(); err != nil {
-		return err
+	// defer tx validation to getExisting
+	existingBlobs, err := store.getExisting(networkID, ids)
+	if err != nil {
+		return fmt.Errorf("Error reading existing blobs: %s", err)
 	}
 
 	whereCondition := getWhereCondition(networkID, ids)
-	_, err := store.builder.Delete(store.tableName).
+	_, err = store.builder.Delete(store.tableName).
 		Where(whereCondition).
 		RunWith(store.tx).
 		Exec()
-	return err
+	if err != nil {
+		return err
+	}
+	err = store.expiries.deleteExisting(store.tx, networkID, existingBlobs)
+	if err != nil {
+		return err
+	}
+
+	return store.changes.record(store.tx, networkID, getDeleteChanges(existingBlobs))
 }
 
-func (store *sqlBlobStorage) IncrementVersion(networkID string, id storage.TypeAndKey) error {
+func (store *sqlBlobStorage) DeleteExpired(now time.Time, limit uint64) (map[string]storage.TKs, error) {
 	if err := store.validateTx(); err != nil {
-		return err
+		return nil, err
+	}
+	expired, err := store.expiries.getExpired(store.tx, now.Unix(), limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, networkID := range getSortedNetworkIDs(expired) {
+		err = store.Delete(networkID, expired[networkID])
+		if err != nil {
+			return nil, errors.Wrapf(err, "error deleting expired blobs in network %s", networkID)
+		}
 	}
+	return expired, nil
+}
 
-	_, err := store.builder.Insert(store.tableName).
+func (store *sqlBlobStorage) IncrementVersion(networkID string, id storage.TypeAndKey) error {
+	// defer tx validation to getExisting
+	existingBlobs, err := store.getExisting(networkID, []storage.TypeAndKey{id})
+	if err != nil {
+		return fmt.Errorf("Error reading existing blobs: %s", err)
+	}
+
+	_, err = store.builder.Insert(store.tableName).
 		Columns(nidCol, typeCol, keyCol, verCol).
 		Values(networkID, id.Type, id.Key, 1).
 		OnConflict(
@@ -324,7 +443,22 @@ func (store *sqlBlobStorage) IncrementVersion(networkID string, id storage.TypeA
 	if err != nil {
 		return errors.Wrapf(err, "Error incrementing version on network %s with type %s and key %s", networkID, id.Type, id.Key)
 	}
-	return nil
+
+	return store.changes.record(store.tx, networkID, []Change{getIncrementChange(id, existingBlobs)})
+}
+
+func (store *sqlBlobStorage) GetChanges(filter SearchFilter, cursor uint64, limit uint64) ([]Change, error) {
+	if err := store.validateTx(); err != nil {
+		return nil, err
+	}
+	return store.changes.get(store.tx, filter, cursor, limit)
+}
+
+func (store *sqlBlobStorage) AckChanges(subscriptionID string, cursor uint64) error {
+	if err := store.validateTx(); err != nil {
+		return err
+	}
+	return store.changes.ack(store.tx, subscriptionID, cursor)
 }
 
 func (store *sqlBlobStorage) validateTx() error {
@@ -334,7 +468,7 @@ func (store *sqlBlobStorage) validateTx() error {
 	return nil
 }
 
-func (store *sqlBlobStorage) updateExistingBlobs(networkID string, blobsToChange map[storage.TypeAndKey]blobChange) error {
+func (store *sqlBlobStorage) updateExistingBlobs(networkID string, blobsToChange map[storage.TypeAndKey]blobChange, expected map[storage.TypeAndKey]uint64) error {
 	// Let squirrel cache prepared statements for us (there should only be 1)
 	sc := sq.NewStmtCache(store.tx)
 	defer sqorc.ClearStatementCacheLogOnError(sc, "updateExistingBlobs")
@@ -346,39 +480,139 @@ func (store *sqlBlobStorage) updateExistingBlobs(networkID string, blobsToChange
 		if change.new.Version != 0 {
 			updatedVersion = change.new.Version
 		}
-		_, err := store.builder.Update(store.tableName).
+		// Use explicit sq.And to preserve ordering of WHERE clause items
+		where := sq.And{
+			sq.Eq{nidCol: networkID},
+			sq.Eq{typeCol: blobID.Type},
+			sq.Eq{keyCol: blobID.Key},
+		}
+		expectedVersion, guarded := expected[blobID]
+		// A blob expected to not exist can only be changed if it has expired
+		if guarded && expectedVersion == storage.VersionAbsent {
+			expectedVersion = change.old.Version
+		}
+		if guarded {
+			where = append(where, sq.Eq{verCol: expectedVersion})
+		}
+		res, err := store.builder.Update(store.tableName).
 			Set(valCol, change.new.Value).
 			Set(verCol, updatedVersion).
-			Where(
-				// Use explicit sq.And to preserve ordering of WHERE clause items
-				sq.And{
-					sq.Eq{nidCol: networkID},
-					sq.Eq{typeCol: blobID.Type},
-					sq.Eq{keyCol: blobID.Key},
-				},
-			).
+			Where(where).
 			RunWith(sc).
 			Exec()
 		if err != nil {
 			return fmt.Errorf("error updating blob (%s, %s, %s): %s", networkID, blobID.Type, blobID.Key, err)
 		}
+		if guarded {
+			if err := store.checkGuardedWrite(networkID, blobID, res); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
-func (store *sqlBlobStorage) insertNewBlobs(networkID string, blobs Blobs) error {
-	insertBuilder := store.builder.Insert(store.tableName).
-		Columns(nidCol, typeCol, keyCol, valCol, verCol)
+func (store *sqlBlobStorage) insertNewBlobs(networkID string, blobs Blobs, expected map[storage.TypeAndKey]uint64) error {
+	var unguardedBlobs, guardedBlobs Blobs
 	for _, blob := range blobs {
-		insertBuilder = insertBuilder.Values(networkID, blob.Type, blob.Key, blob.Value, blob.Version)
+		if _, guarded := expected[storage.TypeAndKey{Type: blob.Type, Key: blob.Key}]; guarded {
+			guardedBlobs = append(guardedBlobs, blob)
+			continue
+		}
+		unguardedBlobs = append(unguardedBlobs, blob)
 	}
-	_, err := insertBuilder.RunWith(store.tx).Exec()
-	if err != nil {
-		return errors.Wrap(err, "error creating blobs")
+
+	columns := []string{nidCol, typeCol, keyCol, valCol, verCol}
+	chunkSize := sqorc.GetInsertChunkSize(store.builder, len(columns))
+	for start := 0; start < len(unguardedBlobs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(unguardedBlobs) {
+			end = len(unguardedBlobs)
+		}
+		insertBuilder := store.builder.Insert(store.tableName).Columns(columns...)
+		for _, blob := range unguardedBlobs[start:end] {
+			insertBuilder = insertBuilder.Values(networkID, blob.Type, blob.Key, blob.Value, blob.Version)
+		}
+		_, err := insertBuilder.RunWith(store.tx).Exec()
+		if err != nil {
+			return errors.Wrap(err, "error creating blobs")
+		}
+	}
+
+	// Blobs expected to not exist are inserted one at a time, so a blob
+	// created by a concurrent write can be reported as a conflict
+	for _, blob := range guardedBlobs {
+		res, err := store.builder.Insert(store.tableName).
+			Columns(nidCol, typeCol, keyCol, valCol, verCol).
+			Values(networkID, blob.Type, blob.Key, blob.Value, blob.Version).
+			OnConflict(nil, nidCol, typeCol, keyCol).
+			RunWith(store.tx).
+			Exec()
+		if err != nil {
+			return errors.Wrap(err, "error creating blobs")
+		}
+		err = store.checkGuardedWrite(networkID, storage.TypeAndKey{Type: blob.Type, Key: blob.Key}, res)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// checkGuardedWrite returns a version conflict if a write guarded by the
+// blob's expected version didn't affect any rows.
+func (store *sqlBlobStorage) checkGuardedWrite(networkID string, id storage.TypeAndKey, res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected by guarded write")
+	}
+	if affected != 0 {
+		return nil
+	}
+	stored, err := store.getExisting(networkID, []storage.TypeAndKey{id})
+	if err != nil {
+		return fmt.Errorf("Error reading conflicting blob: %s", err)
+	}
+	storedVersion, exists := getVersions(getLive(stored, clock.Now()))[id]
+	if !exists {
+		storedVersion = storage.VersionAbsent
+	}
+	return &storage.VersionConflictError{Versions: map[storage.TypeAndKey]uint64{id: storedVersion}}
+}
+
+// getSearchWhereCondition returns the where condition matching the search
+// filter.
+func getSearchWhereCondition(filter SearchFilter) sq.And {
+	// Use and condition to deterministically order clauses for testing
+	whereCondition := sq.And{}
+	if filter.NetworkID != nil {
+		whereCondition = append(whereCondition, sq.Eq{nidCol: *filter.NetworkID})
+	}
+	if !funk.IsEmpty(filter.Types) {
+		whereCondition = append(whereCondition, sq.Eq{typeCol: filter.GetTypes()})
+	}
+	// Apply only one of prefix or match predicates; prefix takes precedence
+	if !funk.IsEmpty(filter.KeyPrefix) {
+		whereCondition = append(whereCondition, sq.Expr(
+			fmt.Sprintf("%s LIKE ? ESCAPE '%s'", keyCol, likeEscapeChar),
+			likeEscaper.Replace(*filter.KeyPrefix)+"%",
+		))
+	} else {
+		if !funk.IsEmpty(filter.Keys) {
+			whereCondition = append(whereCondition, sq.Eq{keyCol: filter.GetKeys()})
+		}
+	}
+	return whereCondition
+}
+
+// getStartAfterCondition matches blobs ordered after the passed type and key.
+func getStartAfterCondition(id storage.TypeAndKey) sq.Or {
+	return sq.Or{
+		sq.Gt{typeCol: id.Type},
+		sq.And{sq.Eq{typeCol: id.Type}, sq.Gt{keyCol: id.Key}},
+	}
+}
+
 func getWhereCondition(networkID string, ids []storage.TypeAndKey) sq.Or {
 	whereConditions := make(sq.Or, 0, len(ids))
 	for _, id := range ids {
@@ -392,6 +626,15 @@ func getWhereCondition(networkID string, ids []storage.TypeAndKey) sq.Or {
 	return whereConditions
 }
 
+func getSortedNetworkIDs(tksByNetwork map[string]storage.TKs) []string {
+	networkIDs := make([]string, 0, len(tksByNetwork))
+	for networkID := range tksByNetwork {
+		networkIDs = append(networkIDs, networkID)
+	}
+	sort.Strings(networkIDs)
+	return networkIDs
+}
+
 func getBlobIDs(blobs Blobs) []storage.TypeAndKey {
 	ret := make([]storage.TypeAndKey, 0, len(blobs))
 	for _, blob := range blobs {
@@ -400,6 +643,43 @@ func getBlobIDs(blobs Blobs) []storage.TypeAndKey {
 	return ret
 }
 
+// getBlobIDsWithExpected returns the IDs of the blobs, followed by the
+// sorted IDs of any other blobs with expected versions.
+func getBlobIDsWithExpected(blobs Blobs, expected map[storage.TypeAndKey]uint64) []storage.TypeAndKey {
+	ids := getBlobIDs(blobs)
+	written := map[storage.TypeAndKey]bool{}
+	for _, id := range ids {
+		written[id] = true
+	}
+	var others storage.TKs
+	for id := range expected {
+		if !written[id] {
+			others = append(others, id)
+		}
+	}
+	others.Sort()
+	return append(ids, others...)
+}
+
+// getLive returns the blobs which haven't expired as of now.
+func getLive(blobs Blobs, now time.Time) Blobs {
+	var live Blobs
+	for _, blob := range blobs {
+		if blob.ExpiresAt == 0 || blob.ExpiresAt > now.Unix() {
+			live = append(live, blob)
+		}
+	}
+	return live
+}
+
+func getVersions(blobs Blobs) map[storage.TypeAndKey]uint64 {
+	ret := make(map[storage.TypeAndKey]uint64, len(blobs))
+	for _, blob := range blobs {
+		ret[storage.TypeAndKey{Type: blob.Type, Key: blob.Key}] = blob.Version
+	}
+	return ret
+}
+
 type blobChange struct {
 	old Blob
 	new Blob
@@ -429,6 +709,41 @@ func partitionBlobsToCreateAndChange(blobsToUpdate Blobs, existingBlobs Blobs) b
 	return ret
 }
 
+// getChanges returns the changes resulting from applying the partitioned
+// writes, with versions matching those written by CreateOrUpdate.
+func (p blobsToCreateAndChange) getChanges() []Change {
+	changes := make([]Change, 0, len(p.blobsToCreate)+len(p.blobsToChange))
+	for _, blob := range p.blobsToCreate {
+		changes = append(changes, Change{Type: blob.Type, Key: blob.Key, Version: blob.Version, Kind: ChangeCreated})
+	}
+	for _, id := range getSortedTypeAndKeys(p.blobsToChange) {
+		change := p.blobsToChange[id]
+		version := change.old.Version + 1
+		if change.new.Version != 0 {
+			version = change.new.Version
+		}
+		changes = append(changes, Change{Type: id.Type, Key: id.Key, Version: version, Kind: ChangeUpdated})
+	}
+	return changes
+}
+
+func getDeleteChanges(deletedBlobs Blobs) []Change {
+	changes := make([]Change, 0, len(deletedBlobs))
+	for _, blob := range deletedBlobs {
+		changes = append(changes, Change{Type: blob.Type, Key: blob.Key, Version: blob.Version, Kind: ChangeDeleted})
+	}
+	return changes
+}
+
+// getIncrementChange returns the change resulting from incrementing the
+// version of a blob, given the blob's existing state (if any).
+func getIncrementChange(id storage.TypeAndKey, existingBlobs Blobs) Change {
+	if len(existingBlobs) == 0 {
+		return Change{Type: id.Type, Key: id.Key, Version: 1, Kind: ChangeCreated}
+	}
+	return Change{Type: id.Type, Key: id.Key, Version: existingBlobs[0].Version + 1, Kind: ChangeUpdated}
+}
+
 func getSortedTypeAndKeys(blobsToChange map[storage.TypeAndKey]blobChange) []storage.TypeAndKey {
 	ret := make([]storage.TypeAndKey, 0, len(blobsToChange))
 	for k := range blobsToChange {
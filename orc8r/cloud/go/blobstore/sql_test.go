@@ -16,6 +16,7 @@ package blobstore_test
 import (
 	"database/sql/driver"
 	"errors"
+	"math"
 	"testing"
 
 	"magma/orc8r/cloud/go/blobstore"
@@ -32,9 +33,10 @@ func TestSqlBlobStorage_Get(t *testing.T) {
 		setup: func(mock sqlmock.Sqlmock) {
 			mock.ExpectQuery(
 				"SELECT type, \"key\", value, version FROM network_table "+
-					"WHERE \\(\\(network_id = \\$1 AND type = \\$2 AND \"key\" = \\$3\\)\\)",
+					"WHERE \\(\\(\\(network_id = \\$1 AND type = \\$2 AND \"key\" = \\$3\\)\\) "+
+					"AND NOT EXISTS \\(SELECT 1 FROM network_table_expiries WHERE .* <= \\$4\\)\\)",
 			).
-				WithArgs("network", "t1", "k1").
+				WithArgs("network", "t1", "k1", sqlmock.AnyArg()).
 				WillReturnRows(
 					sqlmock.NewRows([]string{"type", "key", "value", "version"}).
 						AddRow("t1", "k1", []byte("value1"), 42),
@@ -52,9 +54,10 @@ func TestSqlBlobStorage_Get(t *testing.T) {
 		setup: func(mock sqlmock.Sqlmock) {
 			mock.ExpectQuery(
 				"SELECT type, \"key\", value, version FROM network_table "+
-					"WHERE \\(\\(network_id = \\$1 AND type = \\$2 AND \"key\" = \\$3\\)\\)",
+					"WHERE \\(\\(\\(network_id = \\$1 AND type = \\$2 AND \"key\" = \\$3\\)\\) "+
+					"AND NOT EXISTS \\(SELECT 1 FROM network_table_expiries WHERE .* <= \\$4\\)\\)",
 			).
-				WithArgs("network", "t2", "k2").
+				WithArgs("network", "t2", "k2", sqlmock.AnyArg()).
 				WillReturnRows(
 					sqlmock.NewRows([]string{"type", "key", "value", "version"}),
 				)
@@ -72,9 +75,10 @@ func TestSqlBlobStorage_Get(t *testing.T) {
 		setup: func(mock sqlmock.Sqlmock) {
 			mock.ExpectQuery(
 				"SELECT type, \"key\", value, version FROM network_table "+
-					"WHERE \\(\\(network_id = \\$1 AND type = \\$2 AND \"key\" = \\$3\\)\\)",
+					"WHERE \\(\\(\\(network_id = \\$1 AND type = \\$2 AND \"key\" = \\$3\\)\\) "+
+					"AND NOT EXISTS \\(SELECT 1 FROM network_table_expiries WHERE .* <= \\$4\\)\\)",
 			).
-				WithArgs("network", "t3", "k3").
+				WithArgs("network", "t3", "k3", sqlmock.AnyArg()).
 				WillReturnError(errors.New("mock query error"))
 		},
 
@@ -95,10 +99,11 @@ func TestSqlBlobStorage_GetMany(t *testing.T) {
 		setup: func(mock sqlmock.Sqlmock) {
 			mock.ExpectQuery(
 				"SELECT type, \"key\", value, version FROM network_table "+
-					"WHERE \\("+
+					"WHERE \\(\\("+
 					"\\(network_id = \\$1 AND type = \\$2 AND \"key\" = \\$3\\) OR "+
-					"\\(network_id = \\$4 AND type = \\$5 AND \"key\" = \\$6\\)\\)").
-				WithArgs("network", "t1", "k1", "network", "t2", "k2").
+					"\\(network_id = \\$4 AND type = \\$5 AND \"key\" = \\$6\\)\\) "+
+					"AND NOT EXISTS \\(SELECT 1 FROM network_table_expiries WHERE .* <= \\$7\\)\\)").
+				WithArgs("network", "t1", "k1", "network", "t2", "k2", sqlmock.AnyArg()).
 				WillReturnRows(
 					sqlmock.NewRows([]string{"type", "key", "value", "version"}).
 						AddRow("t1", "k1", []byte("value1"), 42).
@@ -120,7 +125,7 @@ func TestSqlBlobStorage_GetMany(t *testing.T) {
 	queryError := &testCase{
 		setup: func(mock sqlmock.Sqlmock) {
 			mock.ExpectQuery("SELECT type, \"key\", value, version FROM network_table").
-				WithArgs("network", "t1", "k1", "network", "t2", "k2").
+				WithArgs("network", "t1", "k1", "network", "t2", "k2", sqlmock.AnyArg()).
 				WillReturnError(errors.New("mock query error"))
 		},
 
@@ -140,7 +145,7 @@ func TestSqlBlobStorage_Search(t *testing.T) {
 	happyPath := &testCase{
 		setup: func(mock sqlmock.Sqlmock) {
 			mock.ExpectQuery("SELECT network_id, type, \"key\", version, value FROM network_table").
-				WithArgs("network", "t1", "t2", "t3", "k1", "k2", "k3").
+				WithArgs("network", "t1", "t2", "t3", "k1", "k2", "k3", sqlmock.AnyArg()).
 				WillReturnRows(
 					sqlmock.NewRows([]string{"network_id", "type", "key", "version", "value"}).
 						AddRow("network", "t1", "k1", 42, []byte("value1")).
@@ -167,7 +172,7 @@ func TestSqlBlobStorage_Search(t *testing.T) {
 	keyPrefix := &testCase{
 		setup: func(mock sqlmock.Sqlmock) {
 			mock.ExpectQuery("SELECT network_id, type, \"key\", version, value FROM network_table").
-				WithArgs("network", "t1", "t2", "kprefix%").
+				WithArgs("network", "t1", "t2", "kprefix%", sqlmock.AnyArg()).
 				WillReturnRows(
 					sqlmock.NewRows([]string{"network_id", "type", "key", "version", "value"}).
 						AddRow("network", "t1", "kprefix1", 42, []byte("value1")).
@@ -194,7 +199,7 @@ func TestSqlBlobStorage_Search(t *testing.T) {
 	emptyFilterReturnsAll := &testCase{
 		setup: func(mock sqlmock.Sqlmock) {
 			mock.ExpectQuery("SELECT network_id, type, \"key\", version, value FROM network_table").
-				WithArgs(). // no args
+				WithArgs(sqlmock.AnyArg()).
 				WillReturnRows(
 					sqlmock.NewRows([]string{"network_id", "type", "key", "version", "value"}).
 						AddRow("network1", "t1", "k1", 42, []byte("value1")).
@@ -225,7 +230,7 @@ func TestSqlBlobStorage_Search(t *testing.T) {
 	multipleNetworks := &testCase{
 		setup: func(mock sqlmock.Sqlmock) {
 			mock.ExpectQuery("SELECT network_id, type, \"key\", version, value FROM network_table").
-				WithArgs("t1", "t2", "t3", "k1", "k2", "k3").
+				WithArgs("t1", "t2", "t3", "k1", "k2", "k3", sqlmock.AnyArg()).
 				WillReturnRows(
 					sqlmock.NewRows([]string{"network_id", "type", "key", "version", "value"}).
 						AddRow("network1", "t1", "k1", 42, []byte("value1")).
@@ -254,7 +259,7 @@ func TestSqlBlobStorage_Search(t *testing.T) {
 	loadCriteria := &testCase{
 		setup: func(mock sqlmock.Sqlmock) {
 			mock.ExpectQuery("SELECT network_id, type, \"key\", version FROM network_table").
-				WithArgs("t1", "t2", "t3", "k1", "k2", "k3").
+				WithArgs("t1", "t2", "t3", "k1", "k2", "k3", sqlmock.AnyArg()).
 				WillReturnRows(
 					sqlmock.NewRows([]string{"network_id", "type", "key", "version"}).
 						AddRow("network1", "t1", "k1", 42).
@@ -283,7 +288,7 @@ func TestSqlBlobStorage_Search(t *testing.T) {
 	queryError := &testCase{
 		setup: func(mock sqlmock.Sqlmock) {
 			mock.ExpectQuery("SELECT network_id, type, \"key\", version, value FROM network_table").
-				WithArgs("network", "t1", "t2", "t3", "k1", "k2", "k3").
+				WithArgs("network", "t1", "t2", "t3", "k1", "k2", "k3", sqlmock.AnyArg()).
 				WillReturnError(errors.New("mock error"))
 		},
 
@@ -326,6 +331,13 @@ func TestSqlBlobStorage_CreateOrUpdate(t *testing.T) {
 			mock.ExpectExec("INSERT INTO network_table").
 				WithArgs("network", "t2", "k2", []byte("world"), 1000).
 				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			expectRecordChanges(
+				mock,
+				"network",
+				blobstore.Change{Type: "t2", Key: "k2", Version: 1000, Kind: blobstore.ChangeCreated},
+				blobstore.Change{Type: "t1", Key: "k1", Version: 43, Kind: blobstore.ChangeUpdated},
+			)
 		},
 
 		run: func(store blobstore.TransactionalBlobStorage) (interface{}, error) {
@@ -350,7 +362,7 @@ func TestSqlBlobStorage_CreateOrUpdate(t *testing.T) {
 				[]driver.Value{"network", "t1", "k1", "network", "t2", "k2"},
 				blobstore.Blobs{
 					{Type: "t1", Key: "k1", Value: []byte("hello"), Version: 42},
-					{Type: "t2", Key: "k2", Value: []byte("world"), Version: 43},
+					{Type: "t2", Key: "k2", Value: []byte("world"), Version: 43, ExpiresAt: 1600000000},
 				},
 			)
 
@@ -362,6 +374,14 @@ func TestSqlBlobStorage_CreateOrUpdate(t *testing.T) {
 				WithArgs([]byte("foo"), 44, "network", "t2", "k2").
 				WillReturnResult(sqlmock.NewResult(1, 1))
 			updatePrepare.WillBeClosed()
+
+			expectClearExpiries(mock, []driver.Value{"network", "t2", "k2"})
+			expectRecordChanges(
+				mock,
+				"network",
+				blobstore.Change{Type: "t1", Key: "k1", Version: 100, Kind: blobstore.ChangeUpdated},
+				blobstore.Change{Type: "t2", Key: "k2", Version: 44, Kind: blobstore.ChangeUpdated},
+			)
 		},
 
 		run: func(store blobstore.TransactionalBlobStorage) (interface{}, error) {
@@ -393,6 +413,17 @@ func TestSqlBlobStorage_CreateOrUpdate(t *testing.T) {
 					"network", "t2", "k2", []byte("world"), 1000,
 				).
 				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			expectClearExpiries(mock, []driver.Value{"network", "t2", "k2"})
+			mock.ExpectExec("INSERT INTO network_table_expiries").
+				WithArgs("network", "t2", "k2", 1600000000).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			expectRecordChanges(
+				mock,
+				"network",
+				blobstore.Change{Type: "t1", Key: "k1", Version: 0, Kind: blobstore.ChangeCreated},
+				blobstore.Change{Type: "t2", Key: "k2", Version: 1000, Kind: blobstore.ChangeCreated},
+			)
 		},
 
 		run: func(store blobstore.TransactionalBlobStorage) (interface{}, error) {
@@ -400,7 +431,7 @@ func TestSqlBlobStorage_CreateOrUpdate(t *testing.T) {
 				"network",
 				blobstore.Blobs{
 					{Type: "t1", Key: "k1", Value: []byte("hello"), Version: 0},
-					{Type: "t2", Key: "k2", Value: []byte("world"), Version: 1000},
+					{Type: "t2", Key: "k2", Value: []byte("world"), Version: 1000, ExpiresAt: 1600000000},
 				},
 			)
 			return nil, err
@@ -486,11 +517,27 @@ func TestSqlBlobStorage_CreateOrUpdate(t *testing.T) {
 }
 
 func TestSqlBlobStorage_Delete(t *testing.T) {
+	// (t1, k1) exists, (t2, k2) does not
 	happyPath := &testCase{
 		setup: func(mock sqlmock.Sqlmock) {
+			expectGetMany(
+				mock,
+				[]driver.Value{"network", "t1", "k1", "network", "t2", "k2"},
+				blobstore.Blobs{
+					{Type: "t1", Key: "k1", Value: []byte("hello"), Version: 42, ExpiresAt: 1600000000},
+				},
+			)
+
 			mock.ExpectExec("DELETE FROM network_table").
 				WithArgs("network", "t1", "k1", "network", "t2", "k2").
 				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			expectClearExpiries(mock, []driver.Value{"network", "t1", "k1"})
+			expectRecordChanges(
+				mock,
+				"network",
+				blobstore.Change{Type: "t1", Key: "k1", Version: 42, Kind: blobstore.ChangeDeleted},
+			)
 		},
 
 		run: func(store blobstore.TransactionalBlobStorage) (interface{}, error) {
@@ -504,6 +551,14 @@ func TestSqlBlobStorage_Delete(t *testing.T) {
 
 	queryError := &testCase{
 		setup: func(mock sqlmock.Sqlmock) {
+			expectGetMany(
+				mock,
+				[]driver.Value{"network", "t1", "k1", "network", "t2", "k2"},
+				blobstore.Blobs{
+					{Type: "t1", Key: "k1", Value: []byte("hello"), Version: 42},
+				},
+			)
+
 			mock.ExpectExec("DELETE FROM network_table").
 				WithArgs("network", "t1", "k1", "network", "t2", "k2").
 				WillReturnError(errors.New("mock query error"))
@@ -525,6 +580,8 @@ func TestSqlBlobStorage_Delete(t *testing.T) {
 func TestSqlBlobStorage_IncrementVersion(t *testing.T) {
 	happyPath := &testCase{
 		setup: func(mock sqlmock.Sqlmock) {
+			expectGetMany(mock, []driver.Value{"network", "t1", "k1"}, nil)
+
 			mock.ExpectExec("INSERT INTO network_table \\(network_id,type,\"key\",version\\) "+
 				"VALUES \\(\\$1,\\$2,\\$3,\\$4\\) "+
 				"ON CONFLICT \\(network_id, type, \"key\"\\) "+
@@ -532,6 +589,36 @@ func TestSqlBlobStorage_IncrementVersion(t *testing.T) {
 			).
 				WithArgs("network", "t1", "k1", 1).
 				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			expectRecordChanges(
+				mock,
+				"network",
+				blobstore.Change{Type: "t1", Key: "k1", Version: 1, Kind: blobstore.ChangeCreated},
+			)
+		},
+		run: func(store blobstore.TransactionalBlobStorage) (interface{}, error) {
+			err := store.IncrementVersion("network", storage.TypeAndKey{Type: "t1", Key: "k1"})
+			return nil, err
+		},
+		expectedError:  nil,
+		expectedResult: nil,
+	}
+
+	// A subscription acknowledged concurrently is seen under the cursor lock
+	racingAck := &testCase{
+		setup: func(mock sqlmock.Sqlmock) {
+			expectGetMany(mock, []driver.Value{"network", "t1", "k1"}, nil)
+			mock.ExpectExec("INSERT INTO network_table").
+				WithArgs("network", "t1", "k1", 1).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectQuery("SELECT subscribed_until FROM network_table_changes_cursor").
+				WillReturnRows(sqlmock.NewRows([]string{"subscribed_until"}).AddRow(0))
+			expectLockCursor(mock)
+			mock.ExpectQuery("SELECT subscribed_until FROM network_table_changes_cursor").
+				WillReturnRows(sqlmock.NewRows([]string{"subscribed_until"}).AddRow(math.MaxInt64))
+			mock.ExpectExec("INSERT INTO network_table_changes").
+				WithArgs(sqlmock.AnyArg(), 0, "network", "t1", "k1", 1, blobstore.ChangeCreated, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
 		},
 		run: func(store blobstore.TransactionalBlobStorage) (interface{}, error) {
 			err := store.IncrementVersion("network", storage.TypeAndKey{Type: "t1", Key: "k1"})
@@ -541,7 +628,34 @@ func TestSqlBlobStorage_IncrementVersion(t *testing.T) {
 		expectedResult: nil,
 	}
 
+	// Changes aren't recorded without a live subscription
+	unsubscribed := &testCase{
+		setup: func(mock sqlmock.Sqlmock) {
+			expectGetMany(mock, []driver.Value{"network", "t1", "k1"}, nil)
+			mock.ExpectExec("INSERT INTO network_table").
+				WithArgs("network", "t1", "k1", 1).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectQuery("SELECT subscribed_until FROM network_table_changes_cursor").
+				WillReturnRows(sqlmock.NewRows([]string{"subscribed_until"}).AddRow(0))
+			expectLockCursor(mock)
+			mock.ExpectQuery("SELECT subscribed_until FROM network_table_changes_cursor").
+				WillReturnRows(sqlmock.NewRows([]string{"subscribed_until"}).AddRow(0))
+			mock.ExpectCommit()
+		},
+		run: func(store blobstore.TransactionalBlobStorage) (interface{}, error) {
+			err := store.IncrementVersion("network", storage.TypeAndKey{Type: "t1", Key: "k1"})
+			if err != nil {
+				return nil, err
+			}
+			return nil, store.Commit()
+		},
+		expectedError:  nil,
+		expectedResult: nil,
+	}
+
 	runCase(t, happyPath)
+	runCase(t, racingAck)
+	runCase(t, unsubscribed)
 }
 
 func TestSqlBlobStorage_Integration(t *testing.T) {
@@ -554,6 +668,51 @@ func TestSqlBlobStorage_Integration(t *testing.T) {
 	integration(t, fact)
 }
 
+func TestSqlBlobStorage_KeyPrefix(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Could not initialize sqlite DB: %s", err)
+	}
+	fact := blobstore.NewSQLBlobStorageFactory("network_table", db, sqorc.GetSqlBuilder())
+	keyPrefixIntegration(t, fact)
+}
+
+func TestSqlBlobStorage_ChangeFeed(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Could not initialize sqlite DB: %s", err)
+	}
+	fact := blobstore.NewSQLBlobStorageFactory("network_table", db, sqorc.GetSqlBuilder())
+	changeFeedIntegration(t, fact)
+}
+
+func TestSqlBlobStorage_CompareAndSwap(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Could not initialize sqlite DB: %s", err)
+	}
+	fact := blobstore.NewSQLBlobStorageFactory("network_table", db, sqorc.GetSqlBuilder())
+	compareAndSwapIntegration(t, fact)
+}
+
+func TestSqlBlobStorage_PaginatedSearch(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Could not initialize sqlite DB: %s", err)
+	}
+	fact := blobstore.NewSQLBlobStorageFactory("network_table", db, sqorc.GetSqlBuilder())
+	paginatedSearchIntegration(t, fact)
+}
+
+func TestSqlBlobStorage_Expiry(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Could not initialize sqlite DB: %s", err)
+	}
+	fact := blobstore.NewSQLBlobStorageFactory("network_table", db, sqorc.NewSQLiteStatementBuilder())
+	expiryIntegration(t, fact)
+}
+
 type testCase struct {
 	// setup query expectations (begin/table init is generically handled)
 	setup func(sqlmock.Sqlmock)
@@ -596,21 +755,54 @@ func runCase(t *testing.T, test *testCase) {
 	if test.expectedResult != nil {
 		assert.Equal(t, test.expectedResult, actual)
 	}
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func expectCreateTable(mock sqlmock.Sqlmock) {
 	mock.ExpectBegin()
 	mock.ExpectExec("CREATE TABLE IF NOT EXISTS network_table").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS network_table_changes").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("CREATE INDEX IF NOT EXISTS network_table_changes_seq_idx").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS network_table_changes_cursor").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO network_table_changes_cursor").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS network_table_changes_subs").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS network_table_expiries").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("CREATE INDEX IF NOT EXISTS network_table_expiries_expires_at_idx").WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 }
 
+func expectRecordChanges(mock sqlmock.Sqlmock, networkID string, changes ...blobstore.Change) {
+	var args []driver.Value
+	for i, c := range changes {
+		args = append(args, sqlmock.AnyArg(), i, networkID, c.Type, c.Key, c.Version, c.Kind, sqlmock.AnyArg())
+	}
+	mock.ExpectQuery("SELECT subscribed_until FROM network_table_changes_cursor").
+		WillReturnRows(sqlmock.NewRows([]string{"subscribed_until"}).AddRow(math.MaxInt64))
+	mock.ExpectExec("INSERT INTO network_table_changes").
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func expectLockCursor(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("UPDATE network_table_changes_cursor SET seq = seq").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT seq FROM network_table_changes_cursor").
+		WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(0))
+}
+
+func expectClearExpiries(mock sqlmock.Sqlmock, args []driver.Value) {
+	mock.ExpectExec("DELETE FROM network_table_expiries").
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
 func expectGetMany(mock sqlmock.Sqlmock, args []driver.Value, blobs blobstore.Blobs) {
-	rows := sqlmock.NewRows([]string{"type", "key", "value", "version"})
+	rows := sqlmock.NewRows([]string{"type", "key", "value", "version", "expires_at"})
 	for _, blob := range blobs {
-		rows.AddRow(blob.Type, blob.Key, blob.Value, blob.Version)
+		rows.AddRow(blob.Type, blob.Key, blob.Value, blob.Version, blob.ExpiresAt)
 	}
 
-	mock.ExpectQuery("SELECT type, \"key\", value, version FROM network_table").
+	mock.ExpectQuery("SELECT type, \"key\", value, version, COALESCE\\(\\(SELECT network_table_expiries.expires_at FROM network_table_expiries").
 		WithArgs(args...).
 		WillReturnRows(rows)
 }
@@ -14,11 +14,14 @@
 package blobstore
 
 import (
+	"context"
 	"sort"
 	"strings"
+	"time"
 
 	"magma/orc8r/cloud/go/storage"
 
+	"github.com/pkg/errors"
 	"github.com/thoas/go-funk"
 )
 
@@ -28,6 +31,11 @@ type Blob struct {
 	Key     string
 	Value   []byte
 	Version uint64
+
+	// ExpiresAt is the Unix time, in seconds, after which the blob is
+	// deleted by the sweeper. 0 indicates the blob never expires.
+	// Each write replaces the blob's expiry. Reads don't load the expiry.
+	ExpiresAt int64
 }
 
 type Blobs []Blob
@@ -82,6 +90,17 @@ type BlobStorageFactory interface {
 	// operations, and returns a TransactionalBlobStorage instance tied to the
 	// opened transaction.
 	StartTransaction(opts *storage.TxOptions) (TransactionalBlobStorage, error)
+
+	// Watch blocks, passing ordered batches of changes matching the filter
+	// to the handler, until the context is cancelled or the handler returns
+	// an error.
+	// Only changes recorded after the passed cursor are emitted. Consumers
+	// can resume a watch by passing the Cursor of the last change they
+	// handled, or 0 to start from the beginning of the retained change log.
+	// The watch holds a change log subscription while it runs, see
+	// TransactionalBlobStorage.AckChanges. Returns ErrChangesCompacted if
+	// the consumer has fallen behind the retained change log.
+	Watch(ctx context.Context, filter SearchFilter, cursor uint64, handler ChangeHandler) error
 }
 
 // TransactionalBlobStorage is the client API for blob storage operations
@@ -123,6 +142,16 @@ type TransactionalBlobStorage interface {
 	// implementation.
 	CreateOrUpdate(networkID string, blobs Blobs) error
 
+	// CompareAndSwap writes blobs to the storage like CreateOrUpdate, but
+	// only if the stored version of each blob in expected matches its
+	// expected version. An expected version of storage.VersionAbsent
+	// requires the blob to not exist. Blobs without an expected version are written unconditionally,
+	// and expected may include blobs which aren't written.
+	// If any versions don't match, a *storage.VersionConflictError listing
+	// the stored versions of the mismatched blobs is returned, and the
+	// transaction should be rolled back.
+	CompareAndSwap(networkID string, expected map[storage.TypeAndKey]uint64, blobs Blobs) error
+
 	// GetExistingKeys takes in a list of keys and returns a list of keys that
 	// exist from the input.
 	// The filter specifies whether to look at the entire storage or just in
@@ -132,9 +161,30 @@ type TransactionalBlobStorage interface {
 	// Delete deletes specified blobs from storage.
 	Delete(networkID string, ids []storage.TypeAndKey) error
 
+	// DeleteExpired deletes up to limit blobs which expired at or before now,
+	// returning the IDs of the deleted blobs keyed by network ID.
+	// Concurrent calls delete disjoint sets of blobs.
+	DeleteExpired(now time.Time, limit uint64) (map[string]storage.TKs, error)
+
 	// IncrementVersion is an atomic upsert (INSERT DO ON CONFLICT) that
 	// increments the version column or inserts 1 if it does not exist.
 	IncrementVersion(networkID string, id storage.TypeAndKey) error
+
+	// GetChanges returns up to limit changes matching the search filter,
+	// recorded after the passed cursor, in cursor order.
+	// Changes from committed transactions are assigned cursors as part of
+	// this call, so it must be called from a writable transaction.
+	// Returns ErrChangesCompacted if changes after the cursor have been
+	// trimmed from the change log.
+	GetChanges(filter SearchFilter, cursor uint64, limit uint64) ([]Change, error)
+
+	// AckChanges registers or refreshes the change log subscription with
+	// the passed ID, recording that its consumer has handled all changes up
+	// to the cursor.
+	// Changes are only recorded while at least one subscription is live,
+	// i.e. acknowledged within SubscriptionTTL. Changes acknowledged by all
+	// live subscriptions, or older than ChangeRetention, are trimmed.
+	AckChanges(subscriptionID string, cursor uint64) error
 }
 
 // GetAllOfType returns all blobs in the network of the passed type.
@@ -241,6 +291,26 @@ type LoadCriteria struct {
 	// LoadValue specifies whether to load the value of a blob.
 	// Set to false to only load blob metadata.
 	LoadValue bool
+
+	// PageSize, if non-zero, limits a search to at most PageSize blobs.
+	// Paginated searches are ordered by type then key, and must specify a
+	// network ID.
+	PageSize uint64
+	// StartAfter, if non-nil, limits a paginated search to blobs ordered
+	// after the specified type and key, i.e. the last blob of the previous
+	// page.
+	StartAfter *storage.TypeAndKey
+}
+
+func (c LoadCriteria) isPaginated() bool {
+	return c.PageSize != 0 || c.StartAfter != nil
+}
+
+func validateSearch(filter SearchFilter, criteria LoadCriteria) error {
+	if criteria.isPaginated() && filter.NetworkID == nil {
+		return errors.New("paginated searches must specify a network ID")
+	}
+	return nil
 }
 
 func stringListToSet(v []string) map[string]bool {
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2020 The Magma Authors.
+ *
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package blobstore
+
+import (
+	"context"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// TableLabel values contain the name of the swept blobstore table.
+	TableLabel = "table"
+	// TypeLabel values contain the type of the expired blobs.
+	TypeLabel = "type"
+
+	sweepBatchSize = 100
+)
+
+var (
+	expiredBlobs = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "blobstore_expired_blobs_total",
+			Help: "Number of expired blobs deleted by the blobstore sweeper",
+		},
+		[]string{TableLabel, TypeLabel},
+	)
+	sweepErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "blobstore_sweep_errors_total",
+			Help: "Number of failed blobstore sweeper passes",
+		},
+		[]string{TableLabel},
+	)
+)
+
+// Sweeper periodically deletes expired blobs from a blobstore table.
+//
+// Deleting an expired blob is equivalent to a call to Delete, so the
+// deletion is recorded in the table's change log.
+// Multiple sweepers can safely run against the same table, e.g. from
+// multiple replicas of a service.
+type Sweeper struct {
+	tableName string
+	factory   BlobStorageFactory
+	interval  time.Duration
+}
+
+// NewSweeper returns a sweeper for the blobstore table backing factory.
+// The table name is only used to label metrics.
+func NewSweeper(tableName string, factory BlobStorageFactory, interval time.Duration) *Sweeper {
+	return &Sweeper{tableName: tableName, factory: factory, interval: interval}
+}
+
+// Run sweeps expired blobs every interval.
+// Returns only upon context cancellation.
+func (s *Sweeper) Run(ctx context.Context) {
+	for {
+		_, err := s.Sweep()
+		if err != nil {
+			sweepErrors.WithLabelValues(s.tableName).Inc()
+			glog.Errorf("Failed to sweep expired blobs from table %s: %s", s.tableName, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			glog.Infof("Blobstore sweeper for table %s canceled", s.tableName)
+			return
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+// Sweep deletes all blobs which have expired, returning the number of
+// deleted blobs.
+func (s *Sweeper) Sweep() (int, error) {
+	total := 0
+	for {
+		n, err := s.sweepBatch()
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < sweepBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (s *Sweeper) sweepBatch() (int, error) {
+	store, err := s.factory.StartTransaction(nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "start transaction")
+	}
+	deleted, err := store.DeleteExpired(clock.Now(), sweepBatchSize)
+	if err != nil {
+		_ = store.Rollback()
+		return 0, errors.Wrap(err, "delete expired blobs")
+	}
+	err = store.Commit()
+	if err != nil {
+		return 0, errors.Wrap(err, "commit transaction")
+	}
+
+	n := 0
+	for _, tks := range deleted {
+		for _, tk := range tks {
+			expiredBlobs.WithLabelValues(s.tableName, tk.Type).Inc()
+		}
+		n += len(tks)
+	}
+	return n, nil
+}
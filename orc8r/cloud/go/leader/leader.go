@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package leader elects a single leader among service replicas, for jobs
+// which must only run on one replica at a time.
+package leader
+
+import (
+	"database/sql"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/sqorc"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	leaseTableName = "leader_leases"
+
+	nameCol    = "name"
+	holderCol  = "holder"
+	expiresCol = "expires"
+)
+
+// Elector elects a leader among the replicas contending for an election.
+type Elector interface {
+	// IsLeader acquires or renews leadership, returning true iff this
+	// replica is the leader.
+	// Leadership lapses unless renewed within the elector's lease duration,
+	// so callers should call IsLeader before each unit of leader-only work.
+	IsLeader() (bool, error)
+}
+
+// SQLElector elects leaders via leases in a shared SQL table.
+//
+// Lease columns:
+//	- name		-- name of the election
+//	- holder	-- random ID of the replica holding the lease
+//	- expires	-- Unix time, in nanoseconds, after which the lease expires
+type SQLElector struct {
+	db            *sql.DB
+	builder       sqorc.StatementBuilder
+	name          string
+	holder        string
+	leaseDuration time.Duration
+}
+
+// NewSQLElector returns an elector for the named election, whose leader
+// holds a lease of the passed duration.
+// Initialize must be called before IsLeader.
+func NewSQLElector(db *sql.DB, builder sqorc.StatementBuilder, name string, leaseDuration time.Duration) *SQLElector {
+	return &SQLElector{
+		db:            db,
+		builder:       builder,
+		name:          name,
+		holder:        uuid.New().String(),
+		leaseDuration: leaseDuration,
+	}
+}
+
+// Initialize the elector's storage.
+func (e *SQLElector) Initialize() error {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		_, err := e.builder.CreateTable(leaseTableName).
+			IfNotExists().
+			Column(nameCol).Type(sqorc.ColumnTypeText).NotNull().PrimaryKey().EndColumn().
+			Column(holderCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(expiresCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			RunWith(tx).
+			Exec()
+		return nil, errors.Wrap(err, "initialize leader lease table")
+	}
+	_, err := sqorc.ExecInTx(e.db, nil, nil, txFn)
+	return err
+}
+
+func (e *SQLElector) IsLeader() (bool, error) {
+	now := clock.Now()
+	expires := now.Add(e.leaseDuration).UnixNano()
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		// Renew the held lease, or take over an expired one
+		res, err := e.builder.Update(leaseTableName).
+			Set(holderCol, e.holder).
+			Set(expiresCol, expires).
+			Where(squirrel.And{
+				squirrel.Eq{nameCol: e.name},
+				squirrel.Or{
+					squirrel.Eq{holderCol: e.holder},
+					squirrel.LtOrEq{expiresCol: now.UnixNano()},
+				},
+			}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "update lease")
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, errors.Wrap(err, "get updated lease count")
+		}
+		if n != 0 {
+			return true, nil
+		}
+
+		var count uint64
+		err = e.builder.Select("COUNT(*)").
+			From(leaseTableName).
+			Where(squirrel.Eq{nameCol: e.name}).
+			RunWith(tx).
+			QueryRow().
+			Scan(&count)
+		if err != nil {
+			return nil, errors.Wrap(err, "check for existing lease")
+		}
+		if count != 0 {
+			// Lease is held by another replica
+			return false, nil
+		}
+		_, err = e.builder.Insert(leaseTableName).
+			Columns(nameCol, holderCol, expiresCol).
+			Values(e.name, e.holder, expires).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "insert lease")
+		}
+		return true, nil
+	}
+	ret, err := sqorc.ExecInTx(e.db, nil, nil, txFn)
+	if err != nil {
+		return false, errors.Wrapf(err, "elect leader of %s", e.name)
+	}
+	return ret.(bool), nil
+}
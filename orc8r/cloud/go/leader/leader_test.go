@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leader_test
+
+import (
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/leader"
+	"magma/orc8r/cloud/go/sqorc"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLElector(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	newElector := func(name string) leader.Elector {
+		e := leader.NewSQLElector(db, sqorc.NewSQLiteStatementBuilder(), name, time.Minute)
+		require.NoError(t, e.Initialize())
+		return e
+	}
+	a := newElector("some_job")
+	b := newElector("some_job")
+	other := newElector("other_job")
+
+	// First contender is elected
+	assertLeader(t, a, true)
+	assertLeader(t, b, false)
+	// Elections are independent
+	assertLeader(t, other, true)
+
+	// Leader renews its lease
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(50*time.Second))
+	assertLeader(t, a, true)
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(100*time.Second))
+	assertLeader(t, b, false)
+
+	// Lease lapses without renewal, another contender takes over
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(200*time.Second))
+	assertLeader(t, b, true)
+	assertLeader(t, a, false)
+}
+
+func assertLeader(t *testing.T, e leader.Elector, expected bool) {
+	isLeader, err := e.IsLeader()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, isLeader)
+}
@@ -22,4 +22,14 @@ const (
 	CLIENT_CERT_CN_KEY = "X-Magma-Client-Cert-Cn"
 	// Client Certificate Serial Number Header
 	CLIENT_CERT_SN_KEY = "X-Magma-Client-Cert-Serial"
+	// Authorization Header, carrying API tokens
+	AUTHORIZATION_KEY = "Authorization"
+	// Authorization scheme of API tokens
+	BEARER_SCHEME = "Bearer"
+
+	// Echo context keys under which the access middleware stores the
+	// request's authenticated operator identity and, if token-authenticated,
+	// its API token
+	OPERATOR_CONTEXT_KEY  = "magma-operator"
+	API_TOKEN_CONTEXT_KEY = "magma-api-token"
 )
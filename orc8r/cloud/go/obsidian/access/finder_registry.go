@@ -80,6 +80,7 @@ func makeFinderRegistry(version string) finderRegistryType {
 		finderMap: finderMap{
 			obsidian.MagmaNetworksUrlPart:  func(c echo.Context) []*protos.Identity { return getNetworkIdentity(c, networkRoot) },
 			obsidian.MagmaOperatorsUrlPart: func(c echo.Context) []*protos.Identity { return getOperatorIdentity(c, operatorRoot) },
+			obsidian.MagmaTokensUrlPart:    getTokenIdentities,
 		},
 		defaultFinder: func(c echo.Context) []*protos.Identity { return getDefaultNetworkIdentity(c, magmaRoot) },
 	}
@@ -133,3 +134,9 @@ func getOperatorIdentity(c echo.Context, identityRoot string) []*protos.Identity
 	// We don't really know what resource is being requested - request all wildcards
 	return SupervisorWildcards()
 }
+
+// API Token Identity Finder
+// Operators manage only their own API tokens, so need no entity permissions
+func getTokenIdentities(c echo.Context) []*protos.Identity {
+	return []*protos.Identity{}
+}
@@ -19,17 +19,33 @@ import (
 
 	"magma/orc8r/cloud/go/identity"
 	"magma/orc8r/cloud/go/services/certifier"
+	certprotos "magma/orc8r/cloud/go/services/certifier/protos"
 	"magma/orc8r/lib/go/errors"
 	"magma/orc8r/lib/go/protos"
 
 	"github.com/golang/glog"
 )
 
-// getOperator returns Identity of request's Operator (client).
+// getOperator returns Identity of request's Operator (client), identified by
+// either the client's TLS certificate or, absent a certificate, an API token.
+// The API token is also returned, to restrict the request to the token's
+// scope, and is nil for certificate-authenticated requests.
+// If either the request is missing both credentials or the credential is not
+// found by Certifier or one of credential & its identity checks fail
+// - nil will be returned & the corresponding error logged
+func getOperator(req *http.Request, decorate logDecorator) (*protos.Identity, *certprotos.APIToken, error) {
+	if len(req.Header.Get(CLIENT_CERT_SN_KEY)) == 0 && len(req.Header.Get(AUTHORIZATION_KEY)) != 0 {
+		return getTokenOperator(req, decorate)
+	}
+	operator, err := getCertOperator(req, decorate)
+	return operator, nil, err
+}
+
+// getCertOperator returns Identity of request's Operator (client).
 // If either the request is missing TLS certificate headers or the certificate's
 // SN is not found by Certifier or one of certificate & its identity checks fail
 // - nil will be returned & the corresponding error logged
-func getOperator(req *http.Request, decorate logDecorator) (*protos.Identity, error) {
+func getCertOperator(req *http.Request, decorate logDecorator) (*protos.Identity, error) {
 	// Get Certificate SN header value
 	// TBD: to optimize - use map directly
 	csn := req.Header.Get(CLIENT_CERT_SN_KEY)
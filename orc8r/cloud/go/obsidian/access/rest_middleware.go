@@ -30,7 +30,9 @@ import (
 // Access Middleware:
 // 1) determines request's access type (READ/WRITE)
 // 2) finds Operator & Entities of the request
-// 3) verifies Operator's access permissions for the entities
+// 3) verifies the request is within its API token's scope, if token-authenticated
+// 4) verifies Operator's access permissions for the entities, or else that
+// one of Operator's roles permits the request's route
 func Middleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		decorate := getDecorator(c.Request())
@@ -40,13 +42,17 @@ func Middleware(next echo.HandlerFunc) echo.HandlerFunc {
 		}
 		glog.V(1).Infof("Received request in access middleware: %+v", req)
 
-		operator, err := getOperator(req, decorate)
+		operator, token, err := getOperator(req, decorate)
 		if err != nil {
 			return transformErr(decorate, err, http.StatusUnauthorized, "Invalid client credentials: %s", err)
 		}
 		if operator == nil {
 			return makeErr(decorate, http.StatusUnauthorized, "missing client credentials")
 		}
+		c.Set(OPERATOR_CONTEXT_KEY, operator)
+		if token != nil {
+			c.Set(API_TOKEN_CONTEXT_KEY, token)
+		}
 
 		perms := getRequestedPermissions(req, decorate)
 		isStatic := strings.HasPrefix(c.Path(), obsidian.StaticURLPrefix) || strings.HasPrefix(c.Path(), obsidian.StaticURLPrefixLegacy)
@@ -55,6 +61,14 @@ func Middleware(next echo.HandlerFunc) echo.HandlerFunc {
 			// Get Request's Entities' Ids
 			ids := FindRequestedIdentities(c)
 
+			// Check the request is within the token's scope
+			if token != nil {
+				err = checkTokenScope(token, perms, ids)
+				if err != nil {
+					return makeErr(decorate, http.StatusForbidden, "access denied (%s)", err)
+				}
+			}
+
 			// Check Operator's ACL for required entity permissions
 			ents := make([]*accessprotos.AccessControl_Entity, 0, len(ids))
 			for _, id := range ids {
@@ -62,7 +76,14 @@ func Middleware(next echo.HandlerFunc) echo.HandlerFunc {
 			}
 			err = accessd.CheckPermissions(c.Request().Context(), operator, ents...)
 			if err != nil {
-				return transformErr(decorate, err, http.StatusForbidden, "access denied (%s)", err)
+				if _, ok := err.(merrors.ClientInitError); ok {
+					return transformErr(decorate, err, http.StatusForbidden, "access denied (%s)", err)
+				}
+				roleErr := accessd.CheckRoutePermissions(req.Context(), operator, req.Method, req.URL.Path)
+				if roleErr != nil {
+					glog.V(1).Info(decorate("Role check error '%s'", roleErr))
+					return makeErr(decorate, http.StatusForbidden, "access denied (%s)", err)
+				}
 			}
 		}
 
@@ -14,16 +14,27 @@ limitations under the License.
 package tests
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
 	"github.com/labstack/echo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"magma/orc8r/cloud/go/identity"
 	"magma/orc8r/cloud/go/obsidian/access"
+	"magma/orc8r/cloud/go/services/accessd"
+	accessprotos "magma/orc8r/cloud/go/services/accessd/protos"
+	"magma/orc8r/cloud/go/services/certifier"
 	tenantsh "magma/orc8r/cloud/go/services/tenants/obsidian/handlers"
 )
 
+const (
+	tokensV1      = "/magma/v1/tokens"
+	subscribersV1 = "/magma/v1/lte/:network_id/subscribers"
+)
+
 func TestMiddlewareWithoutCertifier(t *testing.T) {
 	e := startTestMidlewareServer(t)
 
@@ -217,6 +228,123 @@ func TestMiddleware(t *testing.T) {
 
 }
 
+func TestMiddleware_APITokens(t *testing.T) {
+	operCertSn, _ := MockAccessControl(t)
+	ctx := context.Background()
+	oper := identity.NewOperator(TEST_OPERATOR_ID)
+	super := identity.NewOperator(TEST_SUPER_OPERATOR_ID)
+
+	e := startTestMidlewareServer(t)
+	listener := WaitForTestServer(t, e)
+	if listener == nil {
+		return // WaitForTestServer should have 'logged' error already
+	}
+	urlPrefix := "http://" + listener.Addr().String()
+
+	expectStatus := func(method, url, bearer string, expected int) {
+		s, err := SendTokenRequest(method, urlPrefix+url, bearer)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, s, "%s %s", method, url)
+	}
+
+	// Read-only token is limited to read access, even where ACL allows write
+	readOnly, err := certifier.IssueAPIToken(ctx, oper, nil, false, 0)
+	require.NoError(t, err)
+	expectStatus("GET", RegisterNetworkV1+"/"+TEST_NETWORK_ID, readOnly.Bearer, 200)
+	expectStatus("PUT", RegisterNetworkV1+"/"+WRITE_TEST_NETWORK_ID, readOnly.Bearer, 403)
+
+	// Writable token is still limited by the operator's ACL
+	writable, err := certifier.IssueAPIToken(ctx, oper, nil, true, 0)
+	require.NoError(t, err)
+	expectStatus("PUT", RegisterNetworkV1+"/"+WRITE_TEST_NETWORK_ID, writable.Bearer, 200)
+	expectStatus("PUT", RegisterNetworkV1+"/"+TEST_NETWORK_ID, writable.Bearer, 403)
+	expectStatus("GET", RegisterNetworkV1, writable.Bearer, 403)
+
+	// Network-restricted token excludes other networks and wildcards
+	superUnscoped, err := certifier.IssueAPIToken(ctx, super, nil, true, 0)
+	require.NoError(t, err)
+	superScoped, err := certifier.IssueAPIToken(ctx, super, []string{TEST_NETWORK_ID}, true, 0)
+	require.NoError(t, err)
+	expectStatus("GET", RegisterNetworkV1, superUnscoped.Bearer, 200)
+	expectStatus("PUT", RegisterNetworkV1+"/"+TEST_NETWORK_ID, superScoped.Bearer, 200)
+	expectStatus("GET", RegisterNetworkV1+"/"+WRITE_TEST_NETWORK_ID, superScoped.Bearer, 403)
+	expectStatus("GET", RegisterNetworkV1, superScoped.Bearer, 403)
+	expectStatus("GET", "/malformed/url", superScoped.Bearer, 403)
+
+	// Invalid and revoked tokens are unauthorized
+	expectStatus("GET", RegisterNetworkV1+"/"+TEST_NETWORK_ID, "invalid.token", 401)
+	require.NoError(t, certifier.RevokeAPIToken(ctx, oper, readOnly.Token.Id))
+	expectStatus("GET", RegisterNetworkV1+"/"+TEST_NETWORK_ID, readOnly.Bearer, 401)
+
+	// Any operator may manage their own tokens
+	s, err := SendRequest("GET", urlPrefix+tokensV1, operCertSn)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, s)
+}
+
+func TestMiddleware_Roles(t *testing.T) {
+	operCertSn, _ := MockAccessControl(t)
+	ctx := context.Background()
+	oper := identity.NewOperator(TEST_OPERATOR_ID)
+	roleOper := identity.NewOperator("carol")
+
+	e := startTestMidlewareServer(t)
+	listener := WaitForTestServer(t, e)
+	if listener == nil {
+		return // WaitForTestServer should have 'logged' error already
+	}
+	urlPrefix := "http://" + listener.Addr().String()
+
+	roleToken, err := certifier.IssueAPIToken(ctx, roleOper, nil, true, 0)
+	require.NoError(t, err)
+	expectStatus := func(method, url string, expected int) {
+		s, err := SendTokenRequest(method, urlPrefix+url, roleToken.Bearer)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, s, "%s %s", method, url)
+	}
+	subscribersURL := func(networkID string) string {
+		return "/magma/v1/lte/" + networkID + "/subscribers"
+	}
+
+	// Operator with neither ACL nor roles
+	expectStatus("GET", subscribersURL(TEST_NETWORK_ID), 403)
+
+	// Network-scoped role permits only its routes in its networks
+	bindings := []*accessprotos.RoleBinding{
+		{Role: accessprotos.SubscriberOperatorRole, Networks: []string{TEST_NETWORK_ID}},
+	}
+	require.NoError(t, accessd.SetRoleBindings(ctx, roleOper, bindings))
+	expectStatus("GET", subscribersURL(TEST_NETWORK_ID), 200)
+	expectStatus("POST", subscribersURL(TEST_NETWORK_ID), 200)
+	expectStatus("POST", subscribersURL(WRITE_TEST_NETWORK_ID), 403)
+	expectStatus("GET", RegisterNetworkV1+"/"+TEST_NETWORK_ID, 403)
+
+	// Global read-only role
+	bindings = append(bindings, &accessprotos.RoleBinding{Role: accessprotos.ReadOnlyAuditorRole})
+	require.NoError(t, accessd.SetRoleBindings(ctx, roleOper, bindings))
+	expectStatus("GET", RegisterNetworkV1, 200)
+	expectStatus("GET", RegisterNetworkV1+"/"+WRITE_TEST_NETWORK_ID, 200)
+	expectStatus("PUT", RegisterNetworkV1+"/"+WRITE_TEST_NETWORK_ID, 403)
+	expectStatus("POST", RegisterNetworkV1, 403)
+
+	// Roles extend an operator's ACL
+	s, err := SendRequest("PUT", urlPrefix+RegisterNetworkV1+"/"+TEST_NETWORK_ID, operCertSn)
+	assert.NoError(t, err)
+	assert.Equal(t, 403, s)
+	bindings = []*accessprotos.RoleBinding{{Role: accessprotos.NetworkAdminRole, Networks: []string{TEST_NETWORK_ID}}}
+	require.NoError(t, accessd.SetRoleBindings(ctx, oper, bindings))
+	s, err = SendRequest("PUT", urlPrefix+RegisterNetworkV1+"/"+TEST_NETWORK_ID, operCertSn)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, s)
+
+	// API token scope still applies
+	readOnly, err := certifier.IssueAPIToken(ctx, roleOper, nil, false, 0)
+	require.NoError(t, err)
+	s, err = SendTokenRequest("POST", urlPrefix+subscribersURL(TEST_NETWORK_ID), readOnly.Bearer)
+	assert.NoError(t, err)
+	assert.Equal(t, 403, s)
+}
+
 func startTestMidlewareServer(t *testing.T) *echo.Echo {
 	e := echo.New()
 
@@ -252,6 +380,19 @@ func startTestMidlewareServer(t *testing.T) *echo.Echo {
 		return c.String(http.StatusOK, "!")
 	})
 
+	// API tokens Endpoint requiring no entity permissions
+	e.GET(tokensV1, func(c echo.Context) error {
+		return c.String(http.StatusOK, "All good!")
+	})
+
+	// Subscribers Endpoints requiring a specific Network Entity Access Permissions
+	e.GET(subscribersV1, func(c echo.Context) error {
+		return c.String(http.StatusOK, "All good!")
+	})
+	e.POST(subscribersV1, func(c echo.Context) error {
+		return c.String(http.StatusOK, "")
+	})
+
 	// Tenants Endpoint requiring Network Wildcard WRITE access permissions
 	e.POST(tenantsh.TenantInfoURL, func(c echo.Context) error {
 		return c.String(http.StatusOK, "All good!")
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"magma/orc8r/cloud/go/identity"
+	accessprotos "magma/orc8r/cloud/go/services/accessd/protos"
+	"magma/orc8r/cloud/go/services/certifier"
+	certprotos "magma/orc8r/cloud/go/services/certifier/protos"
+	"magma/orc8r/lib/go/errors"
+	"magma/orc8r/lib/go/protos"
+
+	"github.com/golang/glog"
+)
+
+// getTokenOperator returns Identity of request's Operator (client) and its
+// API token, from the request's bearer Authorization header.
+func getTokenOperator(req *http.Request, decorate logDecorator) (*protos.Identity, *certprotos.APIToken, error) {
+	auth := strings.SplitN(req.Header.Get(AUTHORIZATION_KEY), " ", 2)
+	if len(auth) != 2 || !strings.EqualFold(auth[0], BEARER_SCHEME) || len(auth[1]) == 0 {
+		glog.V(1).Info(decorate("Unsupported REST authorization header"))
+		return nil, nil, fmt.Errorf("unsupported authorization, must be a bearer API token")
+	}
+	token, err := certifier.AuthenticateAPIToken(req.Context(), strings.TrimSpace(auth[1]))
+	if err != nil {
+		if _, ok := err.(errors.ClientInitError); ok {
+			glog.Error(decorate("API token lookup error '%s'", err))
+			return nil, nil, err
+		}
+		glog.V(1).Info(decorate("API token lookup error '%s'", err))
+		return nil, nil, fmt.Errorf("invalid API token, err: %v", err)
+	}
+	if !identity.IsOperator(token.Operator) {
+		glog.Error(decorate("Non-operator identity for API token: %s", token.Id))
+		return nil, nil, fmt.Errorf("identity must be for an operator")
+	}
+	return token.Operator, token, nil
+}
+
+// checkTokenScope verifies the requested permissions for the requested
+// entities are within the scope of the API token.
+// Network-restricted tokens grant access only to their networks' entities,
+// never to wildcards.
+func checkTokenScope(token *certprotos.APIToken, perms accessprotos.AccessControl_Permission, ids []*protos.Identity) error {
+	if perms&accessprotos.AccessControl_WRITE != 0 && !token.Writable {
+		return fmt.Errorf("API token is read-only")
+	}
+	if len(token.Networks) == 0 {
+		return nil
+	}
+	networks := map[string]bool{}
+	for _, nid := range token.Networks {
+		networks[nid] = true
+	}
+	for _, id := range ids {
+		if !networks[id.GetNetwork()] {
+			return fmt.Errorf("%s is outside the API token's networks", id.HashString())
+		}
+	}
+	return nil
+}
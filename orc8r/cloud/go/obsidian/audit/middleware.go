@@ -0,0 +1,271 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit contains the obsidian middleware which records mutating REST
+// requests to the audit service.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/obsidian/access"
+	"magma/orc8r/cloud/go/serde"
+	"magma/orc8r/cloud/go/services/accessd/protos"
+	"magma/orc8r/cloud/go/services/audit"
+	audit_protos "magma/orc8r/cloud/go/services/audit/protos"
+	certprotos "magma/orc8r/cloud/go/services/certifier/protos"
+	"magma/orc8r/cloud/go/services/configurator"
+	lib_protos "magma/orc8r/lib/go/protos"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/labstack/echo"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxDigestedBodyBytes is the max size of the request body prefix
+	// buffered and digested for audit. Larger bodies are streamed through to
+	// the handler, and their entries marked truncated.
+	MaxDigestedBodyBytes = 1 << 20
+	// recordQueueLen is the max number of audit entries awaiting recording.
+	recordQueueLen = 1000
+)
+
+var recorder = &asyncRecorder{queue: make(chan *audit_protos.Entry, recordQueueLen)}
+
+// Middleware records each mutating (POST, PUT, PATCH, DELETE) request to the
+// audit service, along with its outcome and, for network-scoped requests, the
+// configurator revisions of the network before and after the request and the
+// entities changed between them.
+//
+// Only requests of authenticated operators are recorded, so audit middleware
+// must follow the access middleware. Requests denied by the access middleware
+// aren't recorded, so unauthenticated clients can't load the audit service.
+//
+// Entries are timestamped when the request arrives. Revisions are read around
+// the request rather than within its transaction, so concurrent writes to the
+// same network may be attributed to each other's entries. Entries are
+// completed inline, and only recorded asynchronously, after the response.
+func Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		if req == nil || !isMutating(req.Method) {
+			return next(c)
+		}
+		operator, tokenID := getOperator(c)
+		if operator == "" {
+			return next(c)
+		}
+
+		entry := &audit_protos.Entry{
+			Method:     req.Method,
+			Path:       req.URL.Path,
+			NetworkId:  getNetworkID(c),
+			Operator:   operator,
+			ApiTokenId: tokenID,
+		}
+		ts, err := ptypes.TimestampProto(clock.Now())
+		if err != nil {
+			glog.Errorf("Failed to timestamp %s %s for audit: %s", req.Method, req.URL.Path, err)
+		}
+		entry.Timestamp = ts
+		digest, truncated, err := digestBody(req)
+		if err != nil {
+			glog.Errorf("Failed to digest body of %s %s for audit: %s", req.Method, req.URL.Path, err)
+		}
+		entry.RequestDigest = digest
+		entry.RequestTruncated = truncated
+		if entry.NetworkId != "" {
+			entry.RevisionBefore = getLatestRevision(entry.NetworkId)
+		}
+
+		nextErr := next(c)
+
+		entry.Status = int32(getStatus(c, nextErr))
+		if entry.NetworkId != "" {
+			entry.RevisionAfter = getLatestRevision(entry.NetworkId)
+			entry.EntityChanges = getEntityChanges(entry.NetworkId, entry.RevisionBefore, entry.RevisionAfter)
+		}
+		recorder.enqueue(entry)
+		return nextErr
+	}
+}
+
+// Flush blocks until all audit entries of completed requests are recorded.
+func Flush() {
+	recorder.pending.Wait()
+}
+
+// asyncRecorder records audit entries in the background, so requests don't
+// wait on the audit service.
+type asyncRecorder struct {
+	queue   chan *audit_protos.Entry
+	start   sync.Once
+	pending sync.WaitGroup
+}
+
+// enqueue queues the entry for recording. When the queue is full, the entry
+// is recorded inline, slowing requests rather than dropping entries.
+func (r *asyncRecorder) enqueue(entry *audit_protos.Entry) {
+	r.start.Do(func() { go r.run() })
+	r.pending.Add(1)
+	select {
+	case r.queue <- entry:
+	default:
+		glog.Warningf("Audit record queue full, recording entry for %s %s inline", entry.Method, entry.Path)
+		r.record(entry)
+	}
+}
+
+func (r *asyncRecorder) run() {
+	for entry := range r.queue {
+		r.record(entry)
+	}
+}
+
+func (r *asyncRecorder) record(entry *audit_protos.Entry) {
+	defer r.pending.Done()
+	err := audit.RecordEntry(context.Background(), entry)
+	if err != nil {
+		glog.Errorf("Failed to record audit entry for %s %s: %s", entry.Method, entry.Path, err)
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// getNetworkID returns the network ID of the request, preferring the route's
+// path param. Requests proxied to other services don't have their route's
+// path params, so their network ID is determined by path.
+func getNetworkID(c echo.Context) string {
+	if networkID := c.Param("network_id"); networkID != "" {
+		return networkID
+	}
+	return protos.GetPathNetworkID(c.Request().URL.Path)
+}
+
+// digestBody returns the hex-encoded SHA-256 digest of the request body, up
+// to MaxDigestedBodyBytes, and whether the body exceeded that size.
+// The body is restored for subsequent handlers, streaming any remainder
+// rather than buffering it.
+func digestBody(req *http.Request) (string, bool, error) {
+	if req.Body == nil {
+		return "", false, nil
+	}
+	prefix, err := ioutil.ReadAll(io.LimitReader(req.Body, MaxDigestedBodyBytes+1))
+	if err != nil {
+		return "", false, errors.Wrap(err, "read request body")
+	}
+	req.Body = &prefixedBody{Reader: io.MultiReader(bytes.NewReader(prefix), req.Body), Closer: req.Body}
+
+	truncated := len(prefix) > MaxDigestedBodyBytes
+	if truncated {
+		prefix = prefix[:MaxDigestedBodyBytes]
+	}
+	digest := sha256.Sum256(prefix)
+	return hex.EncodeToString(digest[:]), truncated, nil
+}
+
+// prefixedBody is a request body whose prefix was read ahead.
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+func getStatus(c echo.Context, err error) int {
+	if err == nil {
+		return c.Response().Status
+	}
+	if httpErr, ok := err.(*echo.HTTPError); ok {
+		return httpErr.Code
+	}
+	return http.StatusInternalServerError
+}
+
+// getOperator returns the ID of the request's operator and, if
+// token-authenticated, of its API token.
+// The operator is as authenticated by the access middleware or, absent the
+// access middleware, by the request's TLS client certificate.
+func getOperator(c echo.Context) (string, string) {
+	var tokenID string
+	if token, ok := c.Get(access.API_TOKEN_CONTEXT_KEY).(*certprotos.APIToken); ok {
+		tokenID = token.Id
+	}
+	if operator, ok := c.Get(access.OPERATOR_CONTEXT_KEY).(*lib_protos.Identity); ok {
+		return operator.GetOperator(), tokenID
+	}
+	if tlsState := c.Request().TLS; tlsState != nil && len(tlsState.PeerCertificates) > 0 {
+		return tlsState.PeerCertificates[0].Subject.CommonName, tokenID
+	}
+	return "", tokenID
+}
+
+// getLatestRevision returns the latest revision of the network, or 0 if it
+// has none or it can't be determined.
+func getLatestRevision(networkID string) uint64 {
+	rev, err := configurator.GetLatestRevision(networkID)
+	if err != nil {
+		glog.V(2).Infof("No revision of network %s for audit: %s", networkID, err)
+		return 0
+	}
+	return rev.Revision
+}
+
+// getEntityChanges returns the changes to the network's entities between the
+// revisions. Entity configs aren't deserialized, since only entity versions
+// are recorded.
+func getEntityChanges(networkID string, before, after uint64) []*audit_protos.EntityChange {
+	if before == 0 || after == 0 || before == after {
+		return nil
+	}
+	diff, err := configurator.DiffRevisions(
+		networkID,
+		configurator.RevisionSelector{Revision: before},
+		configurator.RevisionSelector{Revision: after},
+		serde.NewRegistry(),
+		serde.NewRegistry(),
+	)
+	if err != nil {
+		glog.Errorf("Failed to diff revisions %d and %d of network %s for audit: %s", before, after, networkID, err)
+		return nil
+	}
+
+	var changes []*audit_protos.EntityChange
+	for _, entDiff := range diff.Entities {
+		change := &audit_protos.EntityChange{Type: entDiff.ID.Type, Key: entDiff.ID.Key}
+		if entDiff.Before != nil {
+			change.VersionBefore = &wrappers.UInt64Value{Value: entDiff.Before.Version}
+		}
+		if entDiff.After != nil {
+			change.VersionAfter = &wrappers.UInt64Value{Value: entDiff.After.Version}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
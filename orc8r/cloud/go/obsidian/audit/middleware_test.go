@@ -0,0 +1,184 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/identity"
+	"magma/orc8r/cloud/go/obsidian/access"
+	"magma/orc8r/cloud/go/obsidian/audit"
+	"magma/orc8r/cloud/go/serde"
+	"magma/orc8r/cloud/go/services/audit/storage"
+	audit_test_init "magma/orc8r/cloud/go/services/audit/test_init"
+	certprotos "magma/orc8r/cloud/go/services/certifier/protos"
+	"magma/orc8r/cloud/go/services/configurator"
+	configurator_test_init "magma/orc8r/cloud/go/services/configurator/test_init"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	configurator_test_init.StartTestService(t)
+	store := audit_test_init.StartTestService(t)
+	require.NoError(t, configurator.CreateNetwork(configurator.Network{ID: "n1"}, serde.NewRegistry()))
+
+	createGateway := func(c echo.Context) error {
+		body, err := ioutil.ReadAll(c.Request().Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"id":"gw1"}`, string(body))
+		_, err = configurator.CreateEntity("n1", configurator.NetworkEntity{Type: "gateway", Key: "gw1"}, serde.NewRegistry())
+		require.NoError(t, err)
+		return c.NoContent(http.StatusCreated)
+	}
+	deny := func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusForbidden, "access denied")
+	}
+	read := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+
+	// Reads and unauthenticated writes aren't audited
+	c := newContext("GET", "/magma/v1/networks/n1/gateways", "")
+	assert.NoError(t, audit.Middleware(read)(c))
+	c = newContext("POST", "/magma/v1/networks/n1/gateways", "")
+	assert.Error(t, audit.Middleware(deny)(c))
+	audit.Flush()
+	entries, err := store.ListEntries(storage.EntryFilter{}, 10, nil)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// Write, with network from path param and operator from access middleware
+	c = newContext("POST", "/magma/v1/networks/n1/gateways", `{"id":"gw1"}`)
+	c.SetParamNames("network_id")
+	c.SetParamValues("n1")
+	c.Set(access.OPERATOR_CONTEXT_KEY, identity.NewOperator("bob"))
+	c.Set(access.API_TOKEN_CONTEXT_KEY, &certprotos.APIToken{Id: "token1"})
+	assert.NoError(t, audit.Middleware(createGateway)(c))
+	audit.Flush()
+
+	entries, err = store.ListEntries(storage.EntryFilter{}, 10, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	digest := sha256.Sum256([]byte(`{"id":"gw1"}`))
+	assert.Equal(t, "bob", entry.Operator)
+	assert.Equal(t, "token1", entry.ApiTokenId)
+	assert.Equal(t, "POST", entry.Method)
+	assert.Equal(t, "/magma/v1/networks/n1/gateways", entry.Path)
+	assert.Equal(t, "n1", entry.NetworkId)
+	assert.Equal(t, hex.EncodeToString(digest[:]), entry.RequestDigest)
+	assert.False(t, entry.RequestTruncated)
+	assert.Equal(t, int32(201), entry.Status)
+	assert.NotZero(t, entry.RevisionBefore)
+	assert.Greater(t, entry.RevisionAfter, entry.RevisionBefore)
+	require.Len(t, entry.EntityChanges, 1)
+	assert.Equal(t, "gateway", entry.EntityChanges[0].Type)
+	assert.Equal(t, "gw1", entry.EntityChanges[0].Key)
+	assert.Nil(t, entry.EntityChanges[0].VersionBefore)
+	assert.NotNil(t, entry.EntityChanges[0].VersionAfter)
+
+	// Denied write, with network from path and operator from TLS client
+	// certificate
+	c = newContext("DELETE", "/magma/v1/lte/n1/subscribers/IMSI1", "")
+	c.Request().TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "alice"}}}}
+	err = audit.Middleware(deny)(c)
+	assert.Error(t, err)
+	audit.Flush()
+
+	entries, err = store.ListEntries(storage.EntryFilter{}, 10, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	// Entries may share a timestamp, so don't rely on listing order
+	entry = entries[0]
+	if entry.Method != "DELETE" {
+		entry = entries[1]
+	}
+	assert.Equal(t, "alice", entry.Operator)
+	assert.Empty(t, entry.ApiTokenId)
+	assert.Equal(t, "DELETE", entry.Method)
+	assert.Equal(t, "n1", entry.NetworkId)
+	assert.Equal(t, int32(403), entry.Status)
+	assert.Equal(t, entry.RevisionBefore, entry.RevisionAfter)
+	assert.Empty(t, entry.EntityChanges)
+
+	// Oversized bodies are passed through whole, with only their prefix
+	// digested
+	largeBody := strings.Repeat("x", audit.MaxDigestedBodyBytes+10)
+	readLarge := func(c echo.Context) error {
+		body, err := ioutil.ReadAll(c.Request().Body)
+		require.NoError(t, err)
+		assert.Equal(t, largeBody, string(body))
+		return c.NoContent(http.StatusOK)
+	}
+	c = newContext("PUT", "/magma/v1/networks/n2/description", largeBody)
+	c.Set(access.OPERATOR_CONTEXT_KEY, identity.NewOperator("bob"))
+	assert.NoError(t, audit.Middleware(readLarge)(c))
+	audit.Flush()
+
+	entries, err = store.ListEntries(storage.EntryFilter{NetworkID: "n2"}, 10, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	digest = sha256.Sum256([]byte(largeBody[:audit.MaxDigestedBodyBytes]))
+	assert.Equal(t, hex.EncodeToString(digest[:]), entries[0].RequestDigest)
+	assert.True(t, entries[0].RequestTruncated)
+
+	// Entries are timestamped on arrival, and only cover the network's
+	// revisions up to the request's response
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	createGateway2 := func(c echo.Context) error {
+		_, err := configurator.CreateEntity("n1", configurator.NetworkEntity{Type: "gateway", Key: "gw2"}, serde.NewRegistry())
+		require.NoError(t, err)
+		clock.SetAndFreezeClock(t, time.Unix(1060, 0))
+		return c.NoContent(http.StatusCreated)
+	}
+	c = newContext("POST", "/magma/v1/networks/n1/gateways", "")
+	c.SetParamNames("network_id")
+	c.SetParamValues("n1")
+	c.Set(access.OPERATOR_CONTEXT_KEY, identity.NewOperator("bob"))
+	assert.NoError(t, audit.Middleware(createGateway2)(c))
+	_, err = configurator.CreateEntity("n1", configurator.NetworkEntity{Type: "gateway", Key: "gw3"}, serde.NewRegistry())
+	require.NoError(t, err)
+	audit.Flush()
+
+	entries, err = store.ListEntries(storage.EntryFilter{NetworkID: "n1", Start: time.Unix(1000, 0), End: time.Unix(1001, 0)}, 10, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(1000), entries[0].Timestamp.Seconds)
+	require.Len(t, entries[0].EntityChanges, 1)
+	assert.Equal(t, "gw2", entries[0].EntityChanges[0].Key)
+	rev, err := configurator.GetLatestRevision("n1")
+	require.NoError(t, err)
+	assert.Less(t, entries[0].RevisionAfter, rev.Revision)
+}
+
+func newContext(method, path, body string) echo.Context {
+	req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
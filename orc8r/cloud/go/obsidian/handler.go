@@ -19,6 +19,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"magma/orc8r/cloud/go/services/tenants"
 	"magma/orc8r/lib/go/util"
 
 	"github.com/golang/glog"
@@ -173,11 +174,17 @@ func AttachHandlers(e *echo.Echo, handlers []Handler, m ...echo.MiddlewareFunc)
 
 // HttpError wraps the passed error as an HTTP error.
 // Code is optional, defaulting to http.StatusInternalServerError (500).
+// Tenant quota errors, which any write can return, are
+// http.StatusForbidden (403) unless the caller passed a code other than
+// http.StatusInternalServerError.
 func HttpError(err error, code ...int) *echo.HTTPError {
 	status := http.StatusInternalServerError
 	if len(code) > 0 && isValidResponseCode(code[0]) {
 		status = code[0]
 	}
+	if status == http.StatusInternalServerError && tenants.IsQuotaExceededError(err) {
+		status = http.StatusForbidden
+	}
 	// TODO(hcgatewood): we should be handling REST error logging and metrics via middleware
 	if isServerErrCode(status) {
 		glog.Infof("REST HTTP Error: %s, Status: %d", err, status)
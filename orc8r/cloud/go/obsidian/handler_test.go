@@ -15,12 +15,18 @@ package obsidian
 
 import (
 	"errors"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"magma/orc8r/cloud/go/services/tenants"
+
 	"github.com/labstack/echo"
+	pkg_errors "github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type testCase struct {
@@ -80,3 +86,25 @@ func runCase(t *testing.T, tc testCase) {
 	}
 	assert.Equal(t, tc.expectHandlerFuncCalled, handlerFuncCalled)
 }
+
+func TestHttpError(t *testing.T) {
+	err := HttpError(errors.New("foo"))
+	assert.Equal(t, http.StatusInternalServerError, err.Code)
+	err = HttpError(errors.New("foo"), http.StatusBadRequest)
+	assert.Equal(t, http.StatusBadRequest, err.Code)
+
+	// Quota errors are forbidden, unless the caller passed a specific code
+	quotaErr := tenants.NewQuotaExceededError(1, "networks", 2)
+	err = HttpError(quotaErr, http.StatusInternalServerError)
+	assert.Equal(t, http.StatusForbidden, err.Code)
+	assert.Equal(t, "tenant 1 quota exceeded: at most 2 networks allowed", err.Message)
+	err = HttpError(pkg_errors.Wrap(quotaErr, "create gateway"))
+	assert.Equal(t, http.StatusForbidden, err.Code)
+	err = HttpError(quotaErr, http.StatusBadRequest)
+	assert.Equal(t, http.StatusBadRequest, err.Code)
+
+	// Other resource exhaustion, e.g. exceeding the max gRPC message size,
+	// isn't a quota error
+	err = HttpError(status.Error(codes.ResourceExhausted, "grpc: received message larger than max"))
+	assert.Equal(t, http.StatusInternalServerError, err.Code)
+}
@@ -18,6 +18,7 @@ const (
 
 	MagmaNetworksUrlPart  = "networks"
 	MagmaOperatorsUrlPart = "operators"
+	MagmaTokensUrlPart    = "tokens"
 
 	// "/magma"
 	RestRoot = UrlSep + "magma"
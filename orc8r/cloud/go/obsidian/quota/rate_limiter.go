@@ -0,0 +1,204 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quota contains obsidian middleware enforcing tenant quotas on REST
+// API requests.
+package quota
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/obsidian/access"
+	"magma/orc8r/cloud/go/services/accessd/protos"
+	"magma/orc8r/cloud/go/services/tenants"
+	lib_protos "magma/orc8r/lib/go/protos"
+
+	"github.com/golang/glog"
+	"github.com/labstack/echo"
+)
+
+const (
+	// tenantsRefreshInterval is how often tenants' networks and quotas are
+	// reloaded
+	tenantsRefreshInterval = time.Minute
+
+	retryAfterHeader = "Retry-After"
+)
+
+// RateLimiter limits the rate of REST API requests to a tenant's networks,
+// across all its operators, to the tenant's max_requests_per_second quota,
+// with bursts of up to one second's worth of requests.
+// Each obsidian replica limits the requests it serves, so the limit across
+// the deployment is the quota times the number of replicas.
+// Only authenticated requests are limited, so unauthenticated clients can't
+// use up the quota of a tenant's operators. The limiter must therefore follow
+// the access middleware.
+// Requests which aren't network-scoped, or to networks without a tenant,
+// aren't limited.
+type RateLimiter struct {
+	sync.Mutex
+	// tenantByNetwork maps network ID to the ID of its tenant
+	tenantByNetwork map[string]int64
+	// limitByTenant maps tenant ID to its requests per second limit, for
+	// rate-limited tenants
+	limitByTenant map[int64]uint32
+	refreshedAt   time.Time
+	// bucketByTenant maps tenant ID to the bucket of its requests
+	bucketByTenant map[int64]*tokenBucket
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{bucketByTenant: map[int64]*tokenBucket{}}
+}
+
+// Middleware rejects requests exceeding their tenant's rate limit with
+// http.StatusTooManyRequests (429).
+func (l *RateLimiter) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		networkID := c.Param("network_id")
+		if networkID == "" {
+			networkID = protos.GetPathNetworkID(c.Request().URL.Path)
+		}
+		if networkID != "" && isAuthenticated(c) {
+			tenantID, limit, ok := l.allow(c, networkID)
+			if !ok {
+				c.Response().Header().Set(retryAfterHeader, strconv.Itoa(1))
+				return echo.NewHTTPError(
+					http.StatusTooManyRequests,
+					fmt.Sprintf("tenant %d exceeded its quota of %d requests per second", tenantID, limit),
+				)
+			}
+		}
+		return next(c)
+	}
+}
+
+// isAuthenticated returns true if the request has an authenticated caller,
+// either the operator found by the access middleware or the subject of a
+// verified TLS client certificate.
+func isAuthenticated(c echo.Context) bool {
+	if operator, ok := c.Get(access.OPERATOR_CONTEXT_KEY).(*lib_protos.Identity); ok && operator != nil {
+		return true
+	}
+	state := c.Request().TLS
+	return state != nil && len(state.VerifiedChains) > 0
+}
+
+// allow returns true if the request to the network is within its tenant's
+// rate limit, consuming a token of the tenant's bucket.
+// Otherwise returns the tenant's ID and rate limit.
+func (l *RateLimiter) allow(c echo.Context, networkID string) (int64, uint32, bool) {
+	now := clock.Now()
+	if l.claimRefresh(now) {
+		l.refreshTenants(c)
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	tenantID, ok := l.tenantByNetwork[networkID]
+	if !ok {
+		return 0, 0, true
+	}
+	limit, ok := l.limitByTenant[tenantID]
+	if !ok {
+		return 0, 0, true
+	}
+	bucket, ok := l.bucketByTenant[tenantID]
+	if !ok || bucket.rate != limit {
+		bucket = newTokenBucket(limit, now)
+		l.bucketByTenant[tenantID] = bucket
+	}
+	return tenantID, limit, bucket.take(now)
+}
+
+// claimRefresh returns true if tenants are due a refresh, in which case the
+// caller is responsible for the refresh. Refreshes are claimed at most once
+// per interval, even on failure, so concurrent requests don't pile onto the
+// tenants service.
+func (l *RateLimiter) claimRefresh(now time.Time) bool {
+	l.Lock()
+	defer l.Unlock()
+	if now.Sub(l.refreshedAt) < tenantsRefreshInterval {
+		return false
+	}
+	l.refreshedAt = now
+	return true
+}
+
+// refreshTenants reloads tenants' networks and rate limits, and forgets the
+// buckets of idle tenants. On failure, the previously loaded tenants are
+// kept.
+// Tenants are loaded without holding the lock, so other requests are limited
+// by the previously loaded tenants in the meantime.
+func (l *RateLimiter) refreshTenants(c echo.Context) {
+	tenantList, err := tenants.GetAllTenants(c.Request().Context())
+	if err != nil {
+		glog.Errorf("Failed to refresh tenant rate limits: %s", err)
+		return
+	}
+	tenantByNetwork := map[string]int64{}
+	limitByTenant := map[int64]uint32{}
+	for _, t := range tenantList.Tenants {
+		for _, nid := range t.GetTenant().GetNetworks() {
+			tenantByNetwork[nid] = t.Id
+		}
+		if limit := t.GetTenant().GetQuotas().GetMaxRequestsPerSecond(); limit != 0 {
+			limitByTenant[t.Id] = limit
+		}
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	l.tenantByNetwork = tenantByNetwork
+	l.limitByTenant = limitByTenant
+	// Buckets refill within a second, so idle buckets are full, and
+	// recreating them is equivalent
+	idleSince := clock.Now().Add(-tenantsRefreshInterval)
+	for tenantID, bucket := range l.bucketByTenant {
+		if bucket.last.Before(idleSince) {
+			delete(l.bucketByTenant, tenantID)
+		}
+	}
+}
+
+// tokenBucket holds up to rate tokens, refilled at rate tokens per second.
+type tokenBucket struct {
+	rate   uint32
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate uint32, now time.Time) *tokenBucket {
+	return &tokenBucket{rate: rate, tokens: float64(rate), last: now}
+}
+
+func (b *tokenBucket) take(now time.Time) bool {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens += elapsed * float64(b.rate)
+		if b.tokens > float64(b.rate) {
+			b.tokens = float64(b.rate)
+		}
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
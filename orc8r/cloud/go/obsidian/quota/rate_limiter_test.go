@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/identity"
+	"magma/orc8r/cloud/go/obsidian/access"
+	"magma/orc8r/cloud/go/obsidian/quota"
+	"magma/orc8r/cloud/go/services/tenants"
+	tenants_test_init "magma/orc8r/cloud/go/services/tenants/test_init"
+	"magma/orc8r/lib/go/protos"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	tenants_test_init.StartTestService(t)
+	_, err := tenants.CreateTenant(context.Background(), 1, &protos.Tenant{
+		Name:     "limited",
+		Networks: []string{"n1", "n2"},
+		Quotas:   &protos.TenantQuotas{MaxRequestsPerSecond: 2},
+	})
+	require.NoError(t, err)
+	_, err = tenants.CreateTenant(context.Background(), 2, &protos.Tenant{Name: "unlimited", Networks: []string{"n3"}})
+	require.NoError(t, err)
+
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	middleware := quota.NewRateLimiter().Middleware(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	requestAs := func(operator string, path string) error {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+		if operator != "" {
+			c.Set(access.OPERATOR_CONTEXT_KEY, identity.NewOperator(operator))
+		}
+		return middleware(c)
+	}
+	request := func(path string) error {
+		return requestAs("op1", path)
+	}
+
+	// Unauthenticated requests don't use up the tenant's quota
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, requestAs("", "/magma/v1/networks/n1"))
+	}
+
+	// Burst of up to one second's worth of requests, shared across the
+	// tenant's networks
+	assert.NoError(t, request("/magma/v1/networks/n1"))
+	assert.NoError(t, request("/magma/v1/lte/n2/subscribers"))
+	err = request("/magma/v1/networks/n1/gateways")
+	require.Error(t, err)
+	httpErr, ok := err.(*echo.HTTPError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusTooManyRequests, httpErr.Code)
+	assert.Equal(t, "tenant 1 exceeded its quota of 2 requests per second", httpErr.Message)
+
+	// The quota is shared by the tenant's operators
+	assert.Error(t, requestAs("op2", "/magma/v1/networks/n1"))
+
+	// Other tenants and non-network requests aren't limited
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, request("/magma/v1/networks/n3"))
+		assert.NoError(t, request("/magma/v1/networks/n4"))
+		assert.NoError(t, request("/magma/v1/tenants"))
+	}
+
+	// Refilled over time
+	clock.SetAndFreezeClock(t, time.Unix(1000, int64(500*time.Millisecond)))
+	assert.NoError(t, request("/magma/v1/networks/n1"))
+	assert.Error(t, request("/magma/v1/networks/n1"))
+}
@@ -24,6 +24,8 @@ import (
 
 	"magma/orc8r/cloud/go/obsidian"
 	"magma/orc8r/cloud/go/obsidian/access"
+	"magma/orc8r/cloud/go/obsidian/audit"
+	"magma/orc8r/cloud/go/obsidian/quota"
 	"magma/orc8r/cloud/go/obsidian/reverse_proxy"
 	"magma/orc8r/cloud/go/obsidian/swagger/handlers"
 
@@ -118,6 +120,10 @@ func Start() {
 	} else {
 		e.Use(access.Middleware)
 	}
+	// Rate limiter and audit middleware follow authentication, to only
+	// limit and audit authenticated callers
+	e.Use(quota.NewRateLimiter().Middleware)
+	e.Use(audit.Middleware)
 
 	reverseProxyHandler := reverse_proxy.NewReverseProxyHandler()
 	pathPrefixesByAddr, err := reverse_proxy.GetEchoServerAddressToPathPrefixes()
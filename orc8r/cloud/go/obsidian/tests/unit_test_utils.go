@@ -38,9 +38,11 @@ type Test struct {
 
 	ParamNames  []string
 	ParamValues []string
+	Headers     map[string]string
 
-	ExpectedStatus int
-	ExpectedResult encoding.BinaryMarshaler
+	ExpectedStatus  int
+	ExpectedResult  encoding.BinaryMarshaler
+	ExpectedHeaders map[string]string
 
 	ExpectedError          string
 	ExpectedErrorSubstring string
@@ -64,6 +66,10 @@ func RunUnitTest(t *testing.T, e *echo.Echo, test Test) {
 		req = httptest.NewRequest(test.Method, test.URL, bytes.NewReader([]byte{}))
 	}
 
+	for name, value := range test.Headers {
+		req.Header.Set(name, value)
+	}
+
 	recorder := httptest.NewRecorder()
 	c := e.NewContext(req, recorder)
 	c.SetParamNames(test.ParamNames...)
@@ -74,6 +80,9 @@ func RunUnitTest(t *testing.T, e *echo.Echo, test Test) {
 		c.Error(handlerErr)
 	}
 	assert.Equal(t, test.ExpectedStatus, recorder.Code)
+	for name, value := range test.ExpectedHeaders {
+		assert.Equal(t, value, recorder.Header().Get(name))
+	}
 
 	if test.ExpectedError != "" {
 		if httpErr, ok := handlerErr.(*echo.HTTPError); ok {
@@ -54,11 +54,13 @@ const (
 	StateIndexerLabel       = "orc8r.io/state_indexer"
 	StreamProviderLabel     = "orc8r.io/stream_provider"
 	SwaggerSpecLabel        = "orc8r.io/swagger_spec"
+	TenantQuotaLabel        = "orc8r.io/tenant_quota"
 
 	ObsidianHandlersPathPrefixesAnnotation = "orc8r.io/obsidian_handlers_path_prefixes"
 	StateIndexerVersionAnnotation          = "orc8r.io/state_indexer_version"
 	StateIndexerTypesAnnotation            = "orc8r.io/state_indexer_types"
 	StreamProviderStreamsAnnotation        = "orc8r.io/stream_provider_streams"
+	TenantQuotaEntityTypesAnnotation       = "orc8r.io/tenant_quota_entity_types"
 )
 
 // Environment variables
@@ -73,6 +73,16 @@ func (srv *testStateServer) SyncStates(ctx context.Context, req *protos.SyncStat
 	return &protos.SyncStatesResponse{UnsyncedStates: []*protos.IDAndVersion{}}, nil
 }
 
+func (srv *testStateServer) CompareAndSwapStates(ctx context.Context, req *protos.CompareAndSwapStatesRequest) (*protos.Void, error) {
+	srv.lastClientIdentity = proto.Clone(protos.GetClientIdentity(ctx)).(*protos.Identity)
+	return &protos.Void{}, nil
+}
+
+func (srv *testStateServer) StreamStates(req *protos.GetStatesRequest, stream protos.StateService_StreamStatesServer) error {
+	srv.lastClientIdentity = proto.Clone(protos.GetClientIdentity(stream.Context())).(*protos.Identity)
+	return nil
+}
+
 func TestIdentityInjector(t *testing.T) {
 	configuratorTestInit.StartTestService(t)
 	deviceTestInit.StartTestService(t)
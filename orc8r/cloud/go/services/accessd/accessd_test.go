@@ -21,9 +21,12 @@ import (
 	"magma/orc8r/cloud/go/services/accessd"
 	accessprotos "magma/orc8r/cloud/go/services/accessd/protos"
 	accessd_test_service "magma/orc8r/cloud/go/services/accessd/test_init"
+	"magma/orc8r/cloud/go/services/tenants"
+	tenants_test_init "magma/orc8r/cloud/go/services/tenants/test_init"
 	"magma/orc8r/lib/go/protos"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccessManager(t *testing.T) {
@@ -171,3 +174,94 @@ func TestAccessManager(t *testing.T) {
 		assert.Equal(t, "Id_Operator_operator2", opers[0].HashString())
 	}
 }
+
+func TestRoles(t *testing.T) {
+	accessd_test_service.StartTestService(t)
+	tenants_test_init.StartTestService(t)
+	ctx := context.Background()
+
+	op := identity.NewOperator("operator1")
+	subscribersPath := "/magma/v1/lte/n1/subscribers/IMSI001010000000001"
+
+	// Built-in roles can't be modified
+	err := accessd.SetRole(ctx, &accessprotos.Role{Name: accessprotos.NetworkAdminRole})
+	assert.Error(t, err)
+	err = accessd.DeleteRole(ctx, accessprotos.ReadOnlyAuditorRole)
+	assert.Error(t, err)
+	err = accessd.SetRole(ctx, &accessprotos.Role{Name: "bad", Rules: []*accessprotos.Role_Rule{{Path: "magma"}}})
+	assert.Error(t, err)
+
+	gatewayReader := &accessprotos.Role{
+		Name:  "gateway-reader",
+		Rules: []*accessprotos.Role_Rule{{Path: "/magma/v1/lte/:network_id/gateways", Methods: []string{"GET"}}},
+	}
+	require.NoError(t, accessd.SetRole(ctx, gatewayReader))
+	roles, err := accessd.ListRoles(ctx)
+	require.NoError(t, err)
+	var roleNames []string
+	for _, role := range roles {
+		roleNames = append(roleNames, role.Name)
+	}
+	expectedNames := []string{"gateway-reader", accessprotos.NetworkAdminRole, accessprotos.ReadOnlyAuditorRole, accessprotos.SubscriberOperatorRole}
+	assert.Equal(t, expectedNames, roleNames)
+
+	// No bindings, no access
+	bindings, err := accessd.GetRoleBindings(ctx, op)
+	assert.NoError(t, err)
+	assert.Empty(t, bindings)
+	assert.Error(t, accessd.CheckRoutePermissions(ctx, op, "GET", subscribersPath))
+
+	// Unknown roles can't be bound
+	err = accessd.SetRoleBindings(ctx, op, []*accessprotos.RoleBinding{{Role: "unknown"}})
+	assert.Error(t, err)
+
+	// Network-scoped bindings
+	bindings = []*accessprotos.RoleBinding{
+		{Role: accessprotos.SubscriberOperatorRole, Networks: []string{"n1"}},
+		{Role: "gateway-reader", Networks: []string{"n2"}},
+	}
+	require.NoError(t, accessd.SetRoleBindings(ctx, op, bindings))
+	bindingsRecvd, err := accessd.GetRoleBindings(ctx, op)
+	assert.NoError(t, err)
+	assert.Len(t, bindingsRecvd, 2)
+
+	assert.NoError(t, accessd.CheckRoutePermissions(ctx, op, "PUT", subscribersPath))
+	assert.NoError(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/lte/n1/apns"))
+	assert.Error(t, accessd.CheckRoutePermissions(ctx, op, "POST", "/magma/v1/lte/n1/apns"))
+	assert.Error(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/lte/n1/gateways"))
+	assert.Error(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/lte/n2/subscribers"))
+	assert.NoError(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/lte/n2/gateways/g1"))
+	assert.Error(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/lte/n2"))
+
+	// Deleted roles grant no permissions
+	require.NoError(t, accessd.DeleteRole(ctx, "gateway-reader"))
+	assert.Error(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/lte/n2/gateways/g1"))
+
+	// Tenant-scoped bindings cover the tenant's networks
+	_, err = tenants.CreateTenant(ctx, 1, &protos.Tenant{Name: "tenant1", Networks: []string{"n3", "n4"}})
+	require.NoError(t, err)
+	bindings = []*accessprotos.RoleBinding{{Role: accessprotos.NetworkAdminRole, Tenants: []int64{1, 2}}}
+	require.NoError(t, accessd.SetRoleBindings(ctx, op, bindings))
+	assert.NoError(t, accessd.CheckRoutePermissions(ctx, op, "DELETE", "/magma/v1/networks/n3"))
+	assert.NoError(t, accessd.CheckRoutePermissions(ctx, op, "POST", "/magma/v1/lte/n4/gateways"))
+	assert.Error(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/lte/n1/gateways"))
+	assert.Error(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/networks"))
+
+	// Scoped bindings of rules without a network parameter are scoped by the
+	// requested network
+	bindings = []*accessprotos.RoleBinding{{Role: accessprotos.ReadOnlyAuditorRole, Networks: []string{"n1"}}}
+	require.NoError(t, accessd.SetRoleBindings(ctx, op, bindings))
+	assert.NoError(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/lte/n1/gateways"))
+	assert.NoError(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/networks/n1"))
+	assert.Error(t, accessd.CheckRoutePermissions(ctx, op, "PUT", "/magma/v1/lte/n1/gateways/g1"))
+	assert.Error(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/lte/n2/gateways"))
+	assert.Error(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/networks"))
+
+	// Global bindings cover all matched routes
+	bindings = []*accessprotos.RoleBinding{{Role: accessprotos.ReadOnlyAuditorRole}}
+	require.NoError(t, accessd.SetRoleBindings(ctx, op, bindings))
+	assert.NoError(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/networks"))
+	assert.NoError(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v1/tenants/1"))
+	assert.Error(t, accessd.CheckRoutePermissions(ctx, op, "PUT", "/magma/v1/networks/n1"))
+	assert.Error(t, accessd.CheckRoutePermissions(ctx, op, "GET", "/magma/v2"))
+}
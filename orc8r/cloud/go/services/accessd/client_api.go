@@ -191,3 +191,85 @@ func ListOperators(ctx context.Context) ([]*protos.Identity, error) {
 	}
 	return opslist.List, nil
 }
+
+// SetRole creates or overwrites a custom role
+func SetRole(ctx context.Context, role *accessprotos.Role) error {
+	client, err := getAccessdClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.SetRole(ctx, role)
+	if err != nil {
+		errMsg := fmt.Sprintf("Set Role %s error: %s", role.GetName(), err)
+		glog.Error(errMsg)
+		return errors.New(errMsg)
+	}
+	return nil
+}
+
+// DeleteRole deletes a custom role
+func DeleteRole(ctx context.Context, name string) error {
+	client, err := getAccessdClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.DeleteRole(ctx, &accessprotos.Role{Name: name})
+	if err != nil {
+		errMsg := fmt.Sprintf("Delete Role %s error: %s", name, err)
+		glog.Error(errMsg)
+		return errors.New(errMsg)
+	}
+	return nil
+}
+
+// ListRoles returns all built-in and custom roles
+func ListRoles(ctx context.Context) ([]*accessprotos.Role, error) {
+	client, err := getAccessdClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.ListRoles(ctx, &protos.Void{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Roles, nil
+}
+
+// SetRoleBindings overwrites the role bindings of an operator
+func SetRoleBindings(ctx context.Context, operator *protos.Identity, bindings []*accessprotos.RoleBinding) error {
+	client, err := getAccessdClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.SetRoleBindings(ctx, &accessprotos.RoleBindings{Operator: operator, Bindings: bindings})
+	if err != nil {
+		errMsg := fmt.Sprintf("Set Role Bindings for Operator %s error: %s", operator.HashString(), err)
+		glog.Error(errMsg)
+		return errors.New(errMsg)
+	}
+	return nil
+}
+
+// GetRoleBindings returns the role bindings of an operator
+func GetRoleBindings(ctx context.Context, operator *protos.Identity) ([]*accessprotos.RoleBinding, error) {
+	client, err := getAccessdClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.GetRoleBindings(ctx, operator)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Bindings, nil
+}
+
+// CheckRoutePermissions verifies one of operator's role bindings permits
+// the REST request's method and URL path
+func CheckRoutePermissions(ctx context.Context, operator *protos.Identity, method, path string) error {
+	client, err := getAccessdClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.CheckRoutePermissions(ctx, &accessprotos.RouteRequest{Operator: operator, Method: method, Path: path})
+	return err
+}
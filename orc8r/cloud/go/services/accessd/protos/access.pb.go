@@ -322,6 +322,320 @@ func (m *AccessControl_Lists) GetAcls() []*AccessControl_List {
 	return nil
 }
 
+// Role is a named set of REST route permissions, which can be bound to
+// operators in place of (or in addition to) per-entity ACLs.
+type Role struct {
+	Name                 string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description          string       `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Rules                []*Role_Rule `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Role) Reset()         { *m = Role{} }
+func (m *Role) String() string { return proto.CompactTextString(m) }
+func (*Role) ProtoMessage()    {}
+func (*Role) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5561ecc1e13321af, []int{1}
+}
+
+func (m *Role) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Role.Unmarshal(m, b)
+}
+func (m *Role) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Role.Marshal(b, m, deterministic)
+}
+func (m *Role) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Role.Merge(m, src)
+}
+func (m *Role) XXX_Size() int {
+	return xxx_messageInfo_Role.Size(m)
+}
+func (m *Role) XXX_DiscardUnknown() {
+	xxx_messageInfo_Role.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Role proto.InternalMessageInfo
+
+func (m *Role) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Role) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Role) GetRules() []*Role_Rule {
+	if m != nil {
+		return m.Rules
+	}
+	return nil
+}
+
+// Rule grants access to all REST routes under a path prefix.
+type Role_Rule struct {
+	// Path prefix, matched against request paths segment by segment.
+	// Segments of the form ":<name>" or "*" match any single segment, and
+	// the ":network_id" segment determines the request's network.
+	// E.g. /magma/v1/lte/:network_id/subscribers
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Allowed HTTP methods, all methods if empty
+	Methods              []string `protobuf:"bytes,2,rep,name=methods,proto3" json:"methods,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Role_Rule) Reset()         { *m = Role_Rule{} }
+func (m *Role_Rule) String() string { return proto.CompactTextString(m) }
+func (*Role_Rule) ProtoMessage()    {}
+func (*Role_Rule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5561ecc1e13321af, []int{1, 0}
+}
+
+func (m *Role_Rule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Role_Rule.Unmarshal(m, b)
+}
+func (m *Role_Rule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Role_Rule.Marshal(b, m, deterministic)
+}
+func (m *Role_Rule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Role_Rule.Merge(m, src)
+}
+func (m *Role_Rule) XXX_Size() int {
+	return xxx_messageInfo_Role_Rule.Size(m)
+}
+func (m *Role_Rule) XXX_DiscardUnknown() {
+	xxx_messageInfo_Role_Rule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Role_Rule proto.InternalMessageInfo
+
+func (m *Role_Rule) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Role_Rule) GetMethods() []string {
+	if m != nil {
+		return m.Methods
+	}
+	return nil
+}
+
+type Roles struct {
+	Roles                []*Role  `protobuf:"bytes,1,rep,name=roles,proto3" json:"roles,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Roles) Reset()         { *m = Roles{} }
+func (m *Roles) String() string { return proto.CompactTextString(m) }
+func (*Roles) ProtoMessage()    {}
+func (*Roles) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5561ecc1e13321af, []int{2}
+}
+
+func (m *Roles) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Roles.Unmarshal(m, b)
+}
+func (m *Roles) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Roles.Marshal(b, m, deterministic)
+}
+func (m *Roles) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Roles.Merge(m, src)
+}
+func (m *Roles) XXX_Size() int {
+	return xxx_messageInfo_Roles.Size(m)
+}
+func (m *Roles) XXX_DiscardUnknown() {
+	xxx_messageInfo_Roles.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Roles proto.InternalMessageInfo
+
+func (m *Roles) GetRoles() []*Role {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+// RoleBinding grants an operator a role, scoped to a set of networks and/or
+// the networks of a set of tenants. A binding with neither networks nor
+// tenants is global, i.e. applies to all routes matched by the role,
+// including routes which are not network-scoped.
+type RoleBinding struct {
+	Role                 string   `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Networks             []string `protobuf:"bytes,2,rep,name=networks,proto3" json:"networks,omitempty"`
+	Tenants              []int64  `protobuf:"varint,3,rep,packed,name=tenants,proto3" json:"tenants,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RoleBinding) Reset()         { *m = RoleBinding{} }
+func (m *RoleBinding) String() string { return proto.CompactTextString(m) }
+func (*RoleBinding) ProtoMessage()    {}
+func (*RoleBinding) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5561ecc1e13321af, []int{3}
+}
+
+func (m *RoleBinding) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoleBinding.Unmarshal(m, b)
+}
+func (m *RoleBinding) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoleBinding.Marshal(b, m, deterministic)
+}
+func (m *RoleBinding) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoleBinding.Merge(m, src)
+}
+func (m *RoleBinding) XXX_Size() int {
+	return xxx_messageInfo_RoleBinding.Size(m)
+}
+func (m *RoleBinding) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoleBinding.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoleBinding proto.InternalMessageInfo
+
+func (m *RoleBinding) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *RoleBinding) GetNetworks() []string {
+	if m != nil {
+		return m.Networks
+	}
+	return nil
+}
+
+func (m *RoleBinding) GetTenants() []int64 {
+	if m != nil {
+		return m.Tenants
+	}
+	return nil
+}
+
+// Operator's role bindings
+type RoleBindings struct {
+	Operator             *protos.Identity `protobuf:"bytes,1,opt,name=operator,proto3" json:"operator,omitempty"`
+	Bindings             []*RoleBinding   `protobuf:"bytes,2,rep,name=bindings,proto3" json:"bindings,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *RoleBindings) Reset()         { *m = RoleBindings{} }
+func (m *RoleBindings) String() string { return proto.CompactTextString(m) }
+func (*RoleBindings) ProtoMessage()    {}
+func (*RoleBindings) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5561ecc1e13321af, []int{4}
+}
+
+func (m *RoleBindings) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoleBindings.Unmarshal(m, b)
+}
+func (m *RoleBindings) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoleBindings.Marshal(b, m, deterministic)
+}
+func (m *RoleBindings) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoleBindings.Merge(m, src)
+}
+func (m *RoleBindings) XXX_Size() int {
+	return xxx_messageInfo_RoleBindings.Size(m)
+}
+func (m *RoleBindings) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoleBindings.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoleBindings proto.InternalMessageInfo
+
+func (m *RoleBindings) GetOperator() *protos.Identity {
+	if m != nil {
+		return m.Operator
+	}
+	return nil
+}
+
+func (m *RoleBindings) GetBindings() []*RoleBinding {
+	if m != nil {
+		return m.Bindings
+	}
+	return nil
+}
+
+// RPC Request used to verify an operator's access to a REST route
+type RouteRequest struct {
+	Operator *protos.Identity `protobuf:"bytes,1,opt,name=operator,proto3" json:"operator,omitempty"`
+	// HTTP method of the request
+	Method string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	// URL path of the request
+	Path                 string   `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RouteRequest) Reset()         { *m = RouteRequest{} }
+func (m *RouteRequest) String() string { return proto.CompactTextString(m) }
+func (*RouteRequest) ProtoMessage()    {}
+func (*RouteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5561ecc1e13321af, []int{5}
+}
+
+func (m *RouteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RouteRequest.Unmarshal(m, b)
+}
+func (m *RouteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RouteRequest.Marshal(b, m, deterministic)
+}
+func (m *RouteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RouteRequest.Merge(m, src)
+}
+func (m *RouteRequest) XXX_Size() int {
+	return xxx_messageInfo_RouteRequest.Size(m)
+}
+func (m *RouteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RouteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RouteRequest proto.InternalMessageInfo
+
+func (m *RouteRequest) GetOperator() *protos.Identity {
+	if m != nil {
+		return m.Operator
+	}
+	return nil
+}
+
+func (m *RouteRequest) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *RouteRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterEnum("magma.orc8r.accessd.AccessControl_Permission", AccessControl_Permission_name, AccessControl_Permission_value)
 	proto.RegisterType((*AccessControl)(nil), "magma.orc8r.accessd.AccessControl")
@@ -331,6 +645,12 @@ func init() {
 	proto.RegisterType((*AccessControl_ListRequest)(nil), "magma.orc8r.accessd.AccessControl.ListRequest")
 	proto.RegisterType((*AccessControl_PermissionsRequest)(nil), "magma.orc8r.accessd.AccessControl.PermissionsRequest")
 	proto.RegisterType((*AccessControl_Lists)(nil), "magma.orc8r.accessd.AccessControl.Lists")
+	proto.RegisterType((*Role)(nil), "magma.orc8r.accessd.Role")
+	proto.RegisterType((*Role_Rule)(nil), "magma.orc8r.accessd.Role.Rule")
+	proto.RegisterType((*Roles)(nil), "magma.orc8r.accessd.Roles")
+	proto.RegisterType((*RoleBinding)(nil), "magma.orc8r.accessd.RoleBinding")
+	proto.RegisterType((*RoleBindings)(nil), "magma.orc8r.accessd.RoleBindings")
+	proto.RegisterType((*RouteRequest)(nil), "magma.orc8r.accessd.RouteRequest")
 }
 
 func init() {
@@ -338,42 +658,59 @@ func init() {
 }
 
 var fileDescriptor_5561ecc1e13321af = []byte{
-	// 554 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x51, 0x8f, 0xd2, 0x4c,
-	0x14, 0x6d, 0x59, 0x20, 0xec, 0xed, 0x07, 0xe9, 0x77, 0xd5, 0x04, 0xc7, 0x97, 0x4d, 0x13, 0x23,
-	0x46, 0xb7, 0x8d, 0x6c, 0x36, 0xd9, 0xac, 0x26, 0x8a, 0xd0, 0x98, 0x4d, 0x70, 0xd1, 0xd1, 0x75,
-	0x93, 0x7d, 0xab, 0xed, 0x88, 0xcd, 0x42, 0x07, 0x3b, 0x03, 0x09, 0x6f, 0xbe, 0xf9, 0x87, 0x7d,
-	0x34, 0xc6, 0x74, 0x5a, 0xa0, 0x64, 0xd9, 0x4d, 0xd1, 0x7d, 0xa2, 0x9d, 0x39, 0xe7, 0xdc, 0x73,
-	0xcf, 0x1d, 0xa6, 0x70, 0xc0, 0x63, 0xff, 0x28, 0x76, 0xfc, 0x11, 0x9f, 0x06, 0xce, 0x90, 0x3b,
-	0x82, 0xc5, 0xb3, 0xd0, 0x67, 0xc2, 0xf1, 0x7c, 0x9f, 0x09, 0x11, 0x38, 0x93, 0x98, 0x4b, 0xbe,
-	0x78, 0xb5, 0xd5, 0x1b, 0xde, 0x19, 0x7b, 0xc3, 0xb1, 0x67, 0x2b, 0xaa, 0x9d, 0x01, 0xc9, 0xfd,
-	0x54, 0x29, 0xc3, 0xfb, 0x7c, 0x3c, 0xe6, 0x51, 0x8a, 0x27, 0x0f, 0xd6, 0xb6, 0xc2, 0x80, 0x45,
-	0x32, 0x94, 0xf3, 0x74, 0xd3, 0xfa, 0x5d, 0x81, 0x7a, 0x47, 0x69, 0x74, 0x79, 0x24, 0x63, 0x3e,
-	0x22, 0xdf, 0x75, 0xa8, 0xba, 0x0a, 0x82, 0x0f, 0xa1, 0x14, 0x06, 0x4d, 0x7d, 0x4f, 0x6f, 0x19,
-	0xed, 0x7b, 0x76, 0xbe, 0xec, 0x49, 0xa6, 0x42, 0x4b, 0x61, 0x80, 0x03, 0x30, 0x26, 0x2c, 0x1e,
-	0x87, 0x42, 0x84, 0x3c, 0x12, 0xcd, 0xd2, 0x9e, 0xde, 0x6a, 0xb4, 0xf7, 0xed, 0x0d, 0x36, 0xed,
-	0xb5, 0x52, 0xf6, 0xbb, 0x25, 0x8b, 0xe6, 0x15, 0xc8, 0x4f, 0x1d, 0xca, 0xfd, 0x50, 0x48, 0x7c,
-	0x06, 0x35, 0x3e, 0x61, 0xb1, 0x27, 0x79, 0x7c, 0xb3, 0x8d, 0x25, 0x0c, 0xdf, 0x43, 0x4d, 0xad,
-	0x85, 0x2c, 0x71, 0xb2, 0xd3, 0x32, 0xda, 0x87, 0x05, 0x9c, 0x24, 0xd5, 0x6c, 0x37, 0xe3, 0xb9,
-	0x91, 0x8c, 0xe7, 0x74, 0x29, 0x43, 0xbe, 0x40, 0x7d, 0x6d, 0x0b, 0x4d, 0xd8, 0xb9, 0x64, 0x73,
-	0xe5, 0x68, 0x97, 0x26, 0x8f, 0xf8, 0x12, 0x2a, 0x33, 0x6f, 0x34, 0x65, 0xaa, 0x79, 0xa3, 0xfd,
-	0xb8, 0x40, 0xc9, 0x34, 0x63, 0x9a, 0xf2, 0x8e, 0x4b, 0x47, 0x3a, 0xf9, 0xa1, 0x83, 0x91, 0x18,
-	0xa1, 0xec, 0xdb, 0x94, 0xfd, 0x5d, 0xf7, 0xee, 0x95, 0xee, 0xb7, 0xb0, 0xb2, 0xea, 0x78, 0x06,
-	0xb8, 0x9a, 0x8d, 0xf8, 0x07, 0x3f, 0xfb, 0x50, 0x4d, 0xd7, 0xb2, 0x60, 0xae, 0x21, 0x64, 0x20,
-	0xd2, 0x83, 0x4a, 0x12, 0x80, 0xc0, 0xe7, 0x50, 0xf6, 0xfc, 0x91, 0x68, 0xea, 0xaa, 0x87, 0x47,
-	0x05, 0x27, 0x48, 0x15, 0xc9, 0x7a, 0x02, 0xb0, 0x72, 0x8f, 0x35, 0x28, 0x9f, 0x0e, 0x4e, 0x5d,
-	0x53, 0x4b, 0x9e, 0xa8, 0xdb, 0xe9, 0x99, 0x3a, 0xee, 0x42, 0xe5, 0x9c, 0x9e, 0x7c, 0x74, 0xcd,
-	0x52, 0xfb, 0x57, 0x05, 0xee, 0xae, 0x29, 0xbd, 0xf5, 0x22, 0x6f, 0xc8, 0x62, 0xa4, 0x60, 0x7c,
-	0x60, 0x72, 0xb0, 0xe8, 0xc4, 0x2e, 0xea, 0x21, 0x0d, 0x8b, 0xfc, 0xbf, 0x86, 0xff, 0xc4, 0xc3,
-	0xc0, 0xd2, 0xf0, 0x0c, 0x1a, 0x67, 0x93, 0xc0, 0x93, 0xec, 0x76, 0x65, 0x5f, 0x40, 0xa3, 0xc7,
-	0x46, 0x2c, 0x27, 0xbb, 0x39, 0xe7, 0xcd, 0x6c, 0x0a, 0x8d, 0x37, 0xab, 0x46, 0x3b, 0xdd, 0xfe,
-	0x75, 0xec, 0xa2, 0x63, 0xb0, 0x34, 0xbc, 0x00, 0x33, 0xa7, 0x29, 0x3a, 0xdd, 0xbe, 0x40, 0xb2,
-	0x51, 0x55, 0x31, 0x48, 0xab, 0xa0, 0xb4, 0xb0, 0x34, 0x94, 0xca, 0x6f, 0xee, 0x7c, 0xe2, 0xe1,
-	0x56, 0x77, 0xcd, 0xe2, 0x3c, 0x93, 0xe2, 0x7f, 0x0d, 0x4b, 0xc3, 0x73, 0x30, 0xbb, 0x5f, 0x99,
-	0x7f, 0x99, 0xaf, 0x7b, 0x2b, 0xc3, 0x7b, 0x05, 0xf5, 0x04, 0xb3, 0xcc, 0x0a, 0xaf, 0xa2, 0xc8,
-	0x0d, 0xd1, 0x59, 0x1a, 0x1e, 0xc3, 0x7f, 0xe9, 0xf8, 0xb3, 0x6b, 0x7b, 0x8b, 0xe1, 0xbf, 0xb6,
-	0x2f, 0x9e, 0xaa, 0x55, 0xa7, 0xd8, 0x97, 0xe8, 0x73, 0x55, 0xfd, 0x1e, 0xfc, 0x09, 0x00, 0x00,
-	0xff, 0xff, 0x12, 0x00, 0x1c, 0x7f, 0xba, 0x06, 0x00, 0x00,
+	// 817 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5b, 0x4f, 0xe3, 0x46,
+	0x14, 0x8e, 0x73, 0x23, 0x39, 0x86, 0x90, 0x4e, 0x4b, 0x15, 0x5c, 0xa9, 0x4a, 0x2d, 0x55, 0x4d,
+	0xd5, 0xe2, 0xa8, 0x01, 0x24, 0x4a, 0x91, 0xda, 0x10, 0x22, 0x84, 0x04, 0xa4, 0x3b, 0x2c, 0x8b,
+	0xc4, 0x9b, 0xb1, 0x67, 0x83, 0x85, 0xed, 0xc9, 0x7a, 0x26, 0xac, 0x78, 0xda, 0x7d, 0xdb, 0x7f,
+	0xb1, 0xcf, 0xfb, 0x03, 0xf7, 0x79, 0xb5, 0x9a, 0xf1, 0x25, 0xce, 0x92, 0x80, 0xb9, 0x3c, 0x31,
+	0x67, 0xe6, 0x9c, 0xef, 0xfb, 0xce, 0x85, 0xe3, 0xc0, 0x3a, 0x0d, 0xac, 0xad, 0xa0, 0x6d, 0xb9,
+	0x74, 0x6c, 0xb7, 0x87, 0xb4, 0xcd, 0x48, 0x70, 0xed, 0x58, 0x84, 0xb5, 0x4d, 0xcb, 0x22, 0x8c,
+	0xd9, 0xed, 0x51, 0x40, 0x39, 0x8d, 0x4d, 0x43, 0x5a, 0xe8, 0x7b, 0xcf, 0x1c, 0x7a, 0xa6, 0x21,
+	0x43, 0x8d, 0xc8, 0x51, 0x5b, 0x0d, 0x91, 0x22, 0x7f, 0x8b, 0x7a, 0x1e, 0xf5, 0x43, 0x7f, 0xed,
+	0xa7, 0xa9, 0x27, 0xc7, 0x26, 0x3e, 0x77, 0xf8, 0x4d, 0xf8, 0xa8, 0x7f, 0x29, 0xc1, 0x52, 0x57,
+	0x62, 0xf4, 0xa8, 0xcf, 0x03, 0xea, 0x6a, 0xef, 0x15, 0x28, 0xf7, 0xa5, 0x0b, 0xfa, 0x15, 0xf2,
+	0x8e, 0xdd, 0x50, 0x9a, 0x4a, 0x4b, 0xed, 0xac, 0x18, 0x69, 0xda, 0x83, 0x08, 0x05, 0xe7, 0x1d,
+	0x1b, 0x0d, 0x40, 0x1d, 0x91, 0xc0, 0x73, 0x18, 0x73, 0xa8, 0xcf, 0x1a, 0xf9, 0xa6, 0xd2, 0xaa,
+	0x75, 0xd6, 0x8c, 0x19, 0x32, 0x8d, 0x29, 0x2a, 0xe3, 0xff, 0x24, 0x0a, 0xa7, 0x11, 0xb4, 0xcf,
+	0x0a, 0x14, 0x0f, 0x1d, 0xc6, 0xd1, 0x5f, 0x50, 0xa1, 0x23, 0x12, 0x98, 0x9c, 0x06, 0x77, 0xcb,
+	0x48, 0xdc, 0xd0, 0x0b, 0xa8, 0xc8, 0x3b, 0x87, 0x08, 0x25, 0x85, 0x96, 0xda, 0xd9, 0xcc, 0xa0,
+	0x44, 0xb0, 0x19, 0xfd, 0x28, 0xae, 0xef, 0xf3, 0xe0, 0x06, 0x27, 0x30, 0xda, 0x6b, 0x58, 0x9a,
+	0x7a, 0x42, 0x75, 0x28, 0x5c, 0x91, 0x1b, 0xa9, 0xa8, 0x8a, 0xc5, 0x11, 0xfd, 0x0b, 0xa5, 0x6b,
+	0xd3, 0x1d, 0x13, 0x99, 0xbc, 0xda, 0xf9, 0x3d, 0x03, 0x65, 0x58, 0x63, 0x1c, 0xc6, 0x6d, 0xe7,
+	0xb7, 0x14, 0xed, 0x83, 0x02, 0xaa, 0x10, 0x82, 0xc9, 0x9b, 0x31, 0x79, 0x5c, 0xf6, 0xfd, 0x5b,
+	0xd9, 0x3f, 0x40, 0xca, 0x24, 0xe3, 0x6b, 0x40, 0x93, 0xde, 0xb0, 0x27, 0xe8, 0x59, 0x83, 0x72,
+	0x78, 0xd7, 0xc8, 0xdf, 0x15, 0x10, 0x39, 0x69, 0x7b, 0x50, 0x12, 0x05, 0x60, 0xe8, 0x1f, 0x28,
+	0x9a, 0x96, 0xcb, 0x1a, 0x8a, 0xcc, 0xe1, 0xb7, 0x8c, 0x1d, 0xc4, 0x32, 0x48, 0xff, 0x03, 0x60,
+	0xa2, 0x1e, 0x55, 0xa0, 0x78, 0x3c, 0x38, 0xee, 0xd7, 0x73, 0xe2, 0x84, 0xfb, 0xdd, 0xbd, 0xba,
+	0x82, 0xaa, 0x50, 0x3a, 0xc3, 0x07, 0x2f, 0xfb, 0xf5, 0xbc, 0xfe, 0x49, 0x81, 0x22, 0xa6, 0x2e,
+	0x41, 0x08, 0x8a, 0xbe, 0xe9, 0x91, 0xa8, 0xab, 0xf2, 0x8c, 0x9a, 0xa0, 0xda, 0x84, 0x59, 0x81,
+	0x33, 0xe2, 0x0e, 0xf5, 0x65, 0x0e, 0x55, 0x9c, 0xbe, 0x42, 0x1b, 0x50, 0x0a, 0xc6, 0x2e, 0x61,
+	0x8d, 0x82, 0x54, 0xfa, 0xf3, 0x4c, 0xa5, 0x02, 0xdf, 0xc0, 0x63, 0x97, 0xe0, 0xd0, 0x59, 0xdb,
+	0x80, 0x22, 0x1e, 0x87, 0x9c, 0x23, 0x93, 0x5f, 0xc6, 0x9c, 0xe2, 0x8c, 0x1a, 0xb0, 0xe0, 0x11,
+	0x7e, 0x49, 0xed, 0xb0, 0x83, 0x55, 0x1c, 0x9b, 0xfa, 0x16, 0x94, 0x04, 0x12, 0x43, 0x6d, 0x28,
+	0x05, 0xe2, 0x10, 0x95, 0x67, 0x75, 0x2e, 0x29, 0x0e, 0xfd, 0xf4, 0x33, 0x50, 0x85, 0xb9, 0xeb,
+	0xf8, 0xb6, 0xe3, 0x0f, 0x05, 0xad, 0xb8, 0x8f, 0x69, 0xc5, 0x19, 0x69, 0x50, 0xf1, 0x09, 0x7f,
+	0x4b, 0x83, 0xab, 0x98, 0x37, 0xb1, 0x85, 0x24, 0x4e, 0x7c, 0xd3, 0xe7, 0x61, 0x9a, 0x05, 0x1c,
+	0x9b, 0xfa, 0x3b, 0x58, 0x4c, 0x01, 0xb3, 0xc7, 0x8c, 0xc8, 0x0e, 0x54, 0x2e, 0xa2, 0xf0, 0x68,
+	0x64, 0x9b, 0x73, 0xf3, 0x89, 0x78, 0x70, 0x12, 0xa1, 0x7b, 0x42, 0xc0, 0x98, 0x93, 0x27, 0xcc,
+	0xe8, 0x8f, 0x50, 0x0e, 0x2b, 0x1c, 0xf5, 0x37, 0xb2, 0x92, 0xe6, 0x14, 0x26, 0xcd, 0xe9, 0x7c,
+	0xac, 0xc2, 0x0f, 0x53, 0x73, 0x77, 0x64, 0xfa, 0xe6, 0x90, 0x04, 0x08, 0x83, 0x7a, 0x42, 0xf8,
+	0x20, 0xc6, 0x34, 0xb2, 0x4e, 0x6c, 0x28, 0x5b, 0xfb, 0x6e, 0xca, 0xff, 0x15, 0x75, 0x6c, 0x3d,
+	0x87, 0x4e, 0xa1, 0x76, 0x3a, 0xb2, 0x4d, 0x4e, 0x9e, 0x17, 0x76, 0x07, 0x6a, 0x7b, 0xc4, 0x25,
+	0x29, 0xd8, 0xd9, 0x25, 0x9a, 0x1d, 0x8d, 0xa1, 0xb6, 0x3f, 0x49, 0xb4, 0xdb, 0x3b, 0x9c, 0x17,
+	0x9d, 0xf5, 0x9f, 0x56, 0xcf, 0xa1, 0x73, 0xa8, 0xa7, 0x30, 0x59, 0xb7, 0x77, 0xc8, 0x90, 0x36,
+	0x13, 0x55, 0x46, 0x68, 0xad, 0x8c, 0xd0, 0x4c, 0xcf, 0x21, 0x2e, 0xf5, 0xa6, 0xb6, 0x19, 0xda,
+	0x7c, 0xd0, 0x97, 0x29, 0xde, 0x7e, 0x5a, 0xf6, 0x45, 0xaa, 0xe7, 0xd0, 0x19, 0xd4, 0x7b, 0x97,
+	0xc4, 0xba, 0x4a, 0xf3, 0x3e, 0x4b, 0xf3, 0xfe, 0x83, 0x25, 0xe1, 0x93, 0xd4, 0x0a, 0xdd, 0xf6,
+	0xd2, 0xee, 0x28, 0x9d, 0x9e, 0x43, 0xdb, 0xb0, 0x18, 0xb6, 0x3f, 0xfa, 0xc8, 0x3f, 0xa4, 0xf9,
+	0x7f, 0xc3, 0xc2, 0x09, 0xe1, 0x72, 0x5d, 0xce, 0x5f, 0x3a, 0xf3, 0xa6, 0x0e, 0x42, 0xda, 0x47,
+	0x46, 0x57, 0x65, 0x69, 0xe4, 0xfa, 0xbb, 0x37, 0xe5, 0x34, 0x9e, 0x98, 0x81, 0x03, 0x58, 0x8e,
+	0x64, 0x27, 0x8b, 0xea, 0x97, 0xfb, 0x76, 0x0c, 0x9b, 0x2d, 0xe4, 0x08, 0x96, 0xf7, 0xbf, 0x81,
+	0x9a, 0x53, 0xc0, 0xfb, 0x19, 0xf4, 0x1c, 0x1a, 0xc0, 0x8a, 0x9c, 0x13, 0xb9, 0xc3, 0xd2, 0xc3,
+	0x32, 0x2f, 0x7a, 0xb2, 0xea, 0x66, 0xea, 0xdb, 0x35, 0xce, 0xff, 0x94, 0xb7, 0xed, 0x6c, 0xbf,
+	0x2c, 0x2f, 0xca, 0xf2, 0xef, 0xfa, 0xd7, 0x01, 0x00, 0x84, 0x7b, 0x33, 0xe6, 0x8a, 0x0a, 0x00,
+	0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -416,6 +753,21 @@ type AccessControlManagerClient interface {
 	ListOperators(ctx context.Context, in *protos.Void, opts ...grpc.CallOption) (*protos.Identity_List, error)
 	// Cleanup a given entity from all Operators' ACLs
 	DeleteEntity(ctx context.Context, in *protos.Identity, opts ...grpc.CallOption) (*protos.Void, error)
+	// Creates or overwrites a custom role
+	// Built-in roles can't be modified
+	SetRole(ctx context.Context, in *Role, opts ...grpc.CallOption) (*protos.Void, error)
+	// Deletes a custom role, only the role's name is used
+	// Bindings to a deleted role grant no permissions
+	DeleteRole(ctx context.Context, in *Role, opts ...grpc.CallOption) (*protos.Void, error)
+	// Lists all built-in and custom roles
+	ListRoles(ctx context.Context, in *protos.Void, opts ...grpc.CallOption) (*Roles, error)
+	// Overwrites the role bindings of an operator
+	SetRoleBindings(ctx context.Context, in *RoleBindings, opts ...grpc.CallOption) (*protos.Void, error)
+	// Returns the role bindings of an operator
+	GetRoleBindings(ctx context.Context, in *protos.Identity, opts ...grpc.CallOption) (*RoleBindings, error)
+	// CheckRoutePermissions verifies that one of the operator's role bindings
+	// permits the requested REST route and method, within the binding's scope
+	CheckRoutePermissions(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*protos.Void, error)
 }
 
 type accessControlManagerClient struct {
@@ -507,6 +859,60 @@ func (c *accessControlManagerClient) DeleteEntity(ctx context.Context, in *proto
 	return out, nil
 }
 
+func (c *accessControlManagerClient) SetRole(ctx context.Context, in *Role, opts ...grpc.CallOption) (*protos.Void, error) {
+	out := new(protos.Void)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.accessd.AccessControlManager/SetRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accessControlManagerClient) DeleteRole(ctx context.Context, in *Role, opts ...grpc.CallOption) (*protos.Void, error) {
+	out := new(protos.Void)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.accessd.AccessControlManager/DeleteRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accessControlManagerClient) ListRoles(ctx context.Context, in *protos.Void, opts ...grpc.CallOption) (*Roles, error) {
+	out := new(Roles)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.accessd.AccessControlManager/ListRoles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accessControlManagerClient) SetRoleBindings(ctx context.Context, in *RoleBindings, opts ...grpc.CallOption) (*protos.Void, error) {
+	out := new(protos.Void)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.accessd.AccessControlManager/SetRoleBindings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accessControlManagerClient) GetRoleBindings(ctx context.Context, in *protos.Identity, opts ...grpc.CallOption) (*RoleBindings, error) {
+	out := new(RoleBindings)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.accessd.AccessControlManager/GetRoleBindings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accessControlManagerClient) CheckRoutePermissions(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*protos.Void, error) {
+	out := new(protos.Void)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.accessd.AccessControlManager/CheckRoutePermissions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AccessControlManagerServer is the server API for AccessControlManager service.
 type AccessControlManagerServer interface {
 	// Overwrites Permissions for operator Identity to manage others
@@ -537,6 +943,21 @@ type AccessControlManagerServer interface {
 	ListOperators(context.Context, *protos.Void) (*protos.Identity_List, error)
 	// Cleanup a given entity from all Operators' ACLs
 	DeleteEntity(context.Context, *protos.Identity) (*protos.Void, error)
+	// Creates or overwrites a custom role
+	// Built-in roles can't be modified
+	SetRole(context.Context, *Role) (*protos.Void, error)
+	// Deletes a custom role, only the role's name is used
+	// Bindings to a deleted role grant no permissions
+	DeleteRole(context.Context, *Role) (*protos.Void, error)
+	// Lists all built-in and custom roles
+	ListRoles(context.Context, *protos.Void) (*Roles, error)
+	// Overwrites the role bindings of an operator
+	SetRoleBindings(context.Context, *RoleBindings) (*protos.Void, error)
+	// Returns the role bindings of an operator
+	GetRoleBindings(context.Context, *protos.Identity) (*RoleBindings, error)
+	// CheckRoutePermissions verifies that one of the operator's role bindings
+	// permits the requested REST route and method, within the binding's scope
+	CheckRoutePermissions(context.Context, *RouteRequest) (*protos.Void, error)
 }
 
 // UnimplementedAccessControlManagerServer can be embedded to have forward compatible implementations.
@@ -570,6 +991,24 @@ func (*UnimplementedAccessControlManagerServer) ListOperators(ctx context.Contex
 func (*UnimplementedAccessControlManagerServer) DeleteEntity(ctx context.Context, req *protos.Identity) (*protos.Void, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteEntity not implemented")
 }
+func (*UnimplementedAccessControlManagerServer) SetRole(ctx context.Context, req *Role) (*protos.Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRole not implemented")
+}
+func (*UnimplementedAccessControlManagerServer) DeleteRole(ctx context.Context, req *Role) (*protos.Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRole not implemented")
+}
+func (*UnimplementedAccessControlManagerServer) ListRoles(ctx context.Context, req *protos.Void) (*Roles, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRoles not implemented")
+}
+func (*UnimplementedAccessControlManagerServer) SetRoleBindings(ctx context.Context, req *RoleBindings) (*protos.Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRoleBindings not implemented")
+}
+func (*UnimplementedAccessControlManagerServer) GetRoleBindings(ctx context.Context, req *protos.Identity) (*RoleBindings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoleBindings not implemented")
+}
+func (*UnimplementedAccessControlManagerServer) CheckRoutePermissions(ctx context.Context, req *RouteRequest) (*protos.Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckRoutePermissions not implemented")
+}
 
 func RegisterAccessControlManagerServer(s *grpc.Server, srv AccessControlManagerServer) {
 	s.RegisterService(&_AccessControlManager_serviceDesc, srv)
@@ -737,6 +1176,114 @@ func _AccessControlManager_DeleteEntity_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AccessControlManager_SetRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Role)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccessControlManagerServer).SetRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.accessd.AccessControlManager/SetRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccessControlManagerServer).SetRole(ctx, req.(*Role))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccessControlManager_DeleteRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Role)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccessControlManagerServer).DeleteRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.accessd.AccessControlManager/DeleteRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccessControlManagerServer).DeleteRole(ctx, req.(*Role))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccessControlManager_ListRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(protos.Void)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccessControlManagerServer).ListRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.accessd.AccessControlManager/ListRoles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccessControlManagerServer).ListRoles(ctx, req.(*protos.Void))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccessControlManager_SetRoleBindings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoleBindings)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccessControlManagerServer).SetRoleBindings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.accessd.AccessControlManager/SetRoleBindings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccessControlManagerServer).SetRoleBindings(ctx, req.(*RoleBindings))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccessControlManager_GetRoleBindings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(protos.Identity)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccessControlManagerServer).GetRoleBindings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.accessd.AccessControlManager/GetRoleBindings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccessControlManagerServer).GetRoleBindings(ctx, req.(*protos.Identity))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccessControlManager_CheckRoutePermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccessControlManagerServer).CheckRoutePermissions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.accessd.AccessControlManager/CheckRoutePermissions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccessControlManagerServer).CheckRoutePermissions(ctx, req.(*RouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _AccessControlManager_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "magma.orc8r.accessd.AccessControlManager",
 	HandlerType: (*AccessControlManagerServer)(nil),
@@ -777,6 +1324,30 @@ var _AccessControlManager_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteEntity",
 			Handler:    _AccessControlManager_DeleteEntity_Handler,
 		},
+		{
+			MethodName: "SetRole",
+			Handler:    _AccessControlManager_SetRole_Handler,
+		},
+		{
+			MethodName: "DeleteRole",
+			Handler:    _AccessControlManager_DeleteRole_Handler,
+		},
+		{
+			MethodName: "ListRoles",
+			Handler:    _AccessControlManager_ListRoles_Handler,
+		},
+		{
+			MethodName: "SetRoleBindings",
+			Handler:    _AccessControlManager_SetRoleBindings_Handler,
+		},
+		{
+			MethodName: "GetRoleBindings",
+			Handler:    _AccessControlManager_GetRoleBindings_Handler,
+		},
+		{
+			MethodName: "CheckRoutePermissions",
+			Handler:    _AccessControlManager_CheckRoutePermissions_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "orc8r/cloud/go/services/accessd/protos/access.proto",
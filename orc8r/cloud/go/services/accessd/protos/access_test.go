@@ -39,3 +39,38 @@ func TestAccessControlDefinitions(t *testing.T) {
 
 	assert.Equal(t, "NONE", protos.AccessControl_Permission(16).ToString())
 }
+
+func TestRole_Rule_Match(t *testing.T) {
+	rule := &protos.Role_Rule{Path: "/magma/v1/lte/:network_id/*/subscribers", Methods: []string{"GET"}}
+
+	match, nid := rule.Match("GET", "/magma/v1/lte/n1/x/subscribers/IMSI1")
+	assert.True(t, match)
+	assert.Equal(t, "n1", nid)
+	match, _ = rule.Match("get", "/magma/v1/lte/n1/x/subscribers/")
+	assert.True(t, match)
+
+	match, _ = rule.Match("POST", "/magma/v1/lte/n1/x/subscribers")
+	assert.False(t, match)
+	match, _ = rule.Match("GET", "/magma/v1/lte/n1/x")
+	assert.False(t, match)
+	match, _ = rule.Match("GET", "/magma/v1/lte/n1/x/subscriber")
+	assert.False(t, match)
+
+	rule = &protos.Role_Rule{Path: "/magma/v1"}
+	match, nid = rule.Match("DELETE", "/magma/v1/networks/n1")
+	assert.True(t, match)
+	assert.Empty(t, nid)
+
+	for name, role := range protos.GetBuiltinRoles() {
+		assert.Equal(t, name, role.Name)
+		assert.NoError(t, protos.VerifyRole(role))
+	}
+}
+
+func TestGetPathNetworkID(t *testing.T) {
+	assert.Equal(t, "n1", protos.GetPathNetworkID("/magma/v1/networks/n1"))
+	assert.Equal(t, "n1", protos.GetPathNetworkID("/magma/v1/lte/n1/subscribers/IMSI1"))
+	assert.Equal(t, "n1", protos.GetPathNetworkID("/magma/v1/feg_lte/n1/gateways"))
+	assert.Empty(t, protos.GetPathNetworkID("/magma/v1/networks"))
+	assert.Empty(t, protos.GetPathNetworkID("/magma/v1/tenants/1"))
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protos
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// Built-in role names
+	NetworkAdminRole       = "network-admin"
+	SubscriberOperatorRole = "subscriber-operator"
+	ReadOnlyAuditorRole    = "read-only-auditor"
+
+	// NetworkIDPathParam is the route path segment which determines the
+	// network of a request
+	NetworkIDPathParam = ":network_id"
+
+	pathSep = "/"
+)
+
+// networkRootPaths are the roots of all network-scoped routes
+var networkRootPaths = []string{
+	"/magma/v1/networks/:network_id",
+	"/magma/v1/lte/:network_id",
+	"/magma/v1/feg/:network_id",
+	"/magma/v1/feg_lte/:network_id",
+	"/magma/v1/cwf/:network_id",
+	"/magma/v1/wifi/:network_id",
+}
+
+// GetBuiltinRoles returns the roles every orc8r deployment provides, keyed
+// by name.
+func GetBuiltinRoles() map[string]*Role {
+	readMethods := []string{"GET", "HEAD"}
+	return map[string]*Role{
+		NetworkAdminRole: {
+			Name:        NetworkAdminRole,
+			Description: "Full access to all network-scoped routes",
+			Rules:       getNetworkRules(),
+		},
+		SubscriberOperatorRole: {
+			Name:        SubscriberOperatorRole,
+			Description: "Manage LTE subscribers, read their APNs and policies",
+			Rules: []*Role_Rule{
+				{Path: "/magma/v1/lte/:network_id/subscribers"},
+				{Path: "/magma/v1/lte/:network_id/subscriber_config"},
+				{Path: "/magma/v1/lte/:network_id/msisdns"},
+				{Path: "/magma/v1/lte/:network_id/apns", Methods: readMethods},
+				{Path: "/magma/v1/lte/:network_id/policies", Methods: readMethods},
+			},
+		},
+		ReadOnlyAuditorRole: {
+			Name:        ReadOnlyAuditorRole,
+			Description: "Read access to all routes",
+			Rules: []*Role_Rule{
+				{Path: "/magma/v1", Methods: readMethods},
+			},
+		},
+	}
+}
+
+// GetPathNetworkID returns the network ID of a network-scoped route path, or
+// empty if the path isn't network-scoped.
+func GetPathNetworkID(path string) string {
+	for _, rule := range getNetworkRules() {
+		if match, networkID := rule.Match("", path); match {
+			return networkID
+		}
+	}
+	return ""
+}
+
+// VerifyRole verifies the role has a name and well-formed rules.
+func VerifyRole(role *Role) error {
+	if role == nil || len(role.Name) == 0 {
+		return status.Error(codes.InvalidArgument, "missing role name")
+	}
+	for i, rule := range role.Rules {
+		if rule == nil || !strings.HasPrefix(rule.Path, pathSep) {
+			return status.Errorf(codes.InvalidArgument, "invalid rule @ index %d of role %s: path must be absolute", i, role.Name)
+		}
+	}
+	return nil
+}
+
+// Match returns true if the rule permits the method for the path, as well as
+// the network ID of the path, if the rule's path determines one.
+func (rule *Role_Rule) Match(method, path string) (bool, string) {
+	if !rule.matchMethod(method) {
+		return false, ""
+	}
+	ruleSegs := splitPath(rule.Path)
+	pathSegs := splitPath(path)
+	if len(ruleSegs) > len(pathSegs) {
+		return false, ""
+	}
+	networkID := ""
+	for i, seg := range ruleSegs {
+		switch {
+		case seg == NetworkIDPathParam:
+			networkID = pathSegs[i]
+		case seg == "*" || strings.HasPrefix(seg, ":"):
+		case seg != pathSegs[i]:
+			return false, ""
+		}
+	}
+	return true, networkID
+}
+
+func getNetworkRules() []*Role_Rule {
+	var rules []*Role_Rule
+	for _, path := range networkRootPaths {
+		rules = append(rules, &Role_Rule{Path: path})
+	}
+	return rules
+}
+
+func (rule *Role_Rule) matchMethod(method string) bool {
+	if len(rule.Methods) == 0 {
+		return true
+	}
+	for _, m := range rule.Methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitPath(path string) []string {
+	var segs []string
+	for _, seg := range strings.Split(path, pathSep) {
+		if len(seg) > 0 {
+			segs = append(segs, seg)
+		}
+	}
+	return segs
+}
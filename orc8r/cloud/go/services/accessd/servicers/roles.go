@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicers
+
+import (
+	"sort"
+
+	accessprotos "magma/orc8r/cloud/go/services/accessd/protos"
+	"magma/orc8r/cloud/go/services/tenants"
+	merrors "magma/orc8r/lib/go/errors"
+	"magma/orc8r/lib/go/protos"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SetRole creates or overwrites a custom role
+func (srv *AccessControlServer) SetRole(ctx context.Context, role *accessprotos.Role) (*protos.Void, error) {
+	err := accessprotos.VerifyRole(role)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := accessprotos.GetBuiltinRoles()[role.Name]; ok {
+		return nil, status.Errorf(codes.InvalidArgument, "built-in role %s can't be modified", role.Name)
+	}
+	return &protos.Void{}, srv.store.PutRole(role)
+}
+
+// DeleteRole deletes a custom role
+func (srv *AccessControlServer) DeleteRole(ctx context.Context, role *accessprotos.Role) (*protos.Void, error) {
+	if len(role.GetName()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing role name")
+	}
+	if _, ok := accessprotos.GetBuiltinRoles()[role.Name]; ok {
+		return nil, status.Errorf(codes.InvalidArgument, "built-in role %s can't be deleted", role.Name)
+	}
+	return &protos.Void{}, srv.store.DeleteRole(role.Name)
+}
+
+// ListRoles lists all built-in and custom roles, by name
+func (srv *AccessControlServer) ListRoles(ctx context.Context, _ *protos.Void) (*accessprotos.Roles, error) {
+	rolesByName, err := srv.getRoles()
+	if err != nil {
+		return nil, err
+	}
+	res := &accessprotos.Roles{}
+	for _, role := range rolesByName {
+		res.Roles = append(res.Roles, role)
+	}
+	sort.Slice(res.Roles, func(i, j int) bool { return res.Roles[i].Name < res.Roles[j].Name })
+	return res, nil
+}
+
+// SetRoleBindings overwrites the role bindings of an operator
+func (srv *AccessControlServer) SetRoleBindings(ctx context.Context, req *accessprotos.RoleBindings) (*protos.Void, error) {
+	if req == nil || req.Operator == nil {
+		return nil, status.Error(codes.InvalidArgument, "Nil Operator")
+	}
+	rolesByName, err := srv.getRoles()
+	if err != nil {
+		return nil, err
+	}
+	for i, binding := range req.Bindings {
+		if binding == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid role binding @ index: %d", i)
+		}
+		if _, ok := rolesByName[binding.Role]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown role %s", binding.Role)
+		}
+	}
+	return &protos.Void{}, srv.store.PutRoleBindings(req.Operator, req)
+}
+
+// GetRoleBindings returns the role bindings of an operator, which are empty
+// if none were set
+func (srv *AccessControlServer) GetRoleBindings(ctx context.Context, oper *protos.Identity) (*accessprotos.RoleBindings, error) {
+	if oper == nil {
+		return nil, status.Error(codes.InvalidArgument, "Nil Operator")
+	}
+	bindings, err := srv.store.GetRoleBindings(oper)
+	if status.Code(err) == codes.NotFound {
+		return &accessprotos.RoleBindings{Operator: oper}, nil
+	}
+	return bindings, err
+}
+
+// CheckRoutePermissions verifies that one of the operator's role bindings
+// permits the requested route and method.
+// Global bindings permit every route matched by their role. Bindings scoped
+// to networks or tenants only permit routes whose network is in scope.
+func (srv *AccessControlServer) CheckRoutePermissions(ctx context.Context, req *accessprotos.RouteRequest) (*protos.Void, error) {
+	if req == nil || req.Operator == nil {
+		return nil, status.Error(codes.InvalidArgument, "Nil Operator")
+	}
+	bindings, err := srv.GetRoleBindings(ctx, req.Operator)
+	if err != nil {
+		return nil, err
+	}
+	rolesByName, err := srv.getRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, binding := range bindings.Bindings {
+		role, ok := rolesByName[binding.Role]
+		if !ok {
+			continue
+		}
+		for _, rule := range role.Rules {
+			match, networkID := rule.Match(req.Method, req.Path)
+			if !match {
+				continue
+			}
+			if len(binding.Networks) == 0 && len(binding.Tenants) == 0 {
+				return &protos.Void{}, nil
+			}
+			if len(networkID) == 0 {
+				// Rules which don't bind a network, e.g. those covering all
+				// routes, are scoped by the requested network
+				networkID = accessprotos.GetPathNetworkID(req.Path)
+			}
+			if len(networkID) == 0 {
+				continue
+			}
+			inScope, err := isNetworkInScope(ctx, binding, networkID)
+			if err != nil {
+				return nil, err
+			}
+			if inScope {
+				return &protos.Void{}, nil
+			}
+		}
+	}
+	return nil, status.Errorf(
+		codes.PermissionDenied,
+		"No role of %s permits %s %s",
+		req.Operator.HashString(), req.Method, req.Path)
+}
+
+// getRoles returns all built-in and custom roles, keyed by name
+func (srv *AccessControlServer) getRoles() (map[string]*accessprotos.Role, error) {
+	custom, err := srv.store.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+	ret := accessprotos.GetBuiltinRoles()
+	for _, role := range custom {
+		if _, ok := ret[role.Name]; !ok {
+			ret[role.Name] = role
+		}
+	}
+	return ret, nil
+}
+
+func isNetworkInScope(ctx context.Context, binding *accessprotos.RoleBinding, networkID string) (bool, error) {
+	for _, nid := range binding.Networks {
+		if nid == networkID {
+			return true, nil
+		}
+	}
+	for _, tid := range binding.Tenants {
+		tenant, err := tenants.GetTenant(ctx, tid)
+		if err == merrors.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return false, status.Errorf(codes.Unavailable, "failed to get tenant %d: %s", tid, err)
+		}
+		for _, nid := range tenant.Networks {
+			if nid == networkID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -40,4 +40,20 @@ type AccessdStorage interface {
 
 	// DeleteACL removes the ACL associated with the passed identity.
 	DeleteACL(id *protos.Identity) error
+
+	// ListRoles returns all stored (i.e. custom) roles.
+	ListRoles() ([]*accessprotos.Role, error)
+
+	// PutRole creates or overwrites a role, keyed by its name.
+	PutRole(role *accessprotos.Role) error
+
+	// DeleteRole removes the role with the passed name.
+	DeleteRole(name string) error
+
+	// GetRoleBindings returns the role bindings of the passed identity.
+	// If not found, returns wrapped codes.NotFound.
+	GetRoleBindings(id *protos.Identity) (*accessprotos.RoleBindings, error)
+
+	// PutRoleBindings overwrites the role bindings of the passed identity.
+	PutRoleBindings(id *protos.Identity, bindings *accessprotos.RoleBindings) error
 }
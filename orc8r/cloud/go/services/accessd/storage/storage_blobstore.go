@@ -17,6 +17,7 @@ import (
 	"magma/orc8r/cloud/go/blobstore"
 	accessprotos "magma/orc8r/cloud/go/services/accessd/protos"
 	"magma/orc8r/cloud/go/storage"
+	merrors "magma/orc8r/lib/go/errors"
 	"magma/orc8r/lib/go/protos"
 
 	"github.com/golang/protobuf/proto"
@@ -31,6 +32,13 @@ const (
 	// AccessdDefaultType is the default type blobstore uses for accessd protos.
 	AccessdDefaultType = "access_control"
 
+	// RoleType is the type blobstore uses for roles, keyed by role name.
+	RoleType = "role"
+
+	// RoleBindingsType is the type blobstore uses for operators' role
+	// bindings, keyed by operator hash string.
+	RoleBindingsType = "role_bindings"
+
 	// Blobstore needs a network ID, but accessd is network-agnostic so we
 	// will use a placeholder value.
 	placeholderNetworkID = "placeholder_network"
@@ -241,3 +249,153 @@ func (a *accessdBlobstore) DeleteACL(id *protos.Identity) error {
 	}
 	return nil
 }
+
+func (a *accessdBlobstore) ListRoles() ([]*accessprotos.Role, error) {
+	store, err := a.factory.StartTransaction(&storage.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to start transaction: %s", err)
+	}
+	defer store.Rollback()
+
+	names, err := blobstore.ListKeys(store, placeholderNetworkID, RoleType)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list keys: %s", err)
+	}
+
+	roles := make([]*accessprotos.Role, 0, len(names))
+	if len(names) == 0 {
+		return roles, store.Commit()
+	}
+
+	blobs, err := store.GetMany(placeholderNetworkID, storage.MakeTKs(RoleType, names))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get many roles: %s", err)
+	}
+	for _, blob := range blobs {
+		role := &accessprotos.Role{}
+		err = proto.Unmarshal(blob.Value, role)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to unmarshal role: %s", err)
+		}
+		roles = append(roles, role)
+	}
+
+	err = store.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to commit transaction: %s", err)
+	}
+	return roles, nil
+}
+
+func (a *accessdBlobstore) PutRole(role *accessprotos.Role) error {
+	if role == nil {
+		return status.Error(codes.InvalidArgument, "nil Role")
+	}
+
+	store, err := a.factory.StartTransaction(nil)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to start transaction: %s", err)
+	}
+	defer store.Rollback()
+
+	marshaledRole, err := proto.Marshal(role)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal role: %s", err)
+	}
+
+	blob := blobstore.Blob{Type: RoleType, Key: role.Name, Value: marshaledRole}
+	err = store.CreateOrUpdate(placeholderNetworkID, blobstore.Blobs{blob})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to put role: %s", err)
+	}
+
+	err = store.Commit()
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to commit transaction: %s", err)
+	}
+	return nil
+}
+
+func (a *accessdBlobstore) DeleteRole(name string) error {
+	store, err := a.factory.StartTransaction(nil)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to start transaction: %s", err)
+	}
+	defer store.Rollback()
+
+	tk := storage.TypeAndKey{Type: RoleType, Key: name}
+	err = store.Delete(placeholderNetworkID, []storage.TypeAndKey{tk})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to delete role: %s", err)
+	}
+
+	err = store.Commit()
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to commit transaction: %s", err)
+	}
+	return nil
+}
+
+func (a *accessdBlobstore) GetRoleBindings(id *protos.Identity) (*accessprotos.RoleBindings, error) {
+	if id == nil {
+		return nil, status.Error(codes.InvalidArgument, "nil Identity")
+	}
+
+	store, err := a.factory.StartTransaction(&storage.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to start transaction: %s", err)
+	}
+	defer store.Rollback()
+
+	blob, err := store.Get(placeholderNetworkID, storage.TypeAndKey{Type: RoleBindingsType, Key: id.HashString()})
+	if err == merrors.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "no role bindings for Operator %s", id.HashString())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get role bindings: %s", err)
+	}
+
+	bindings := &accessprotos.RoleBindings{}
+	err = proto.Unmarshal(blob.Value, bindings)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to unmarshal role bindings: %s", err)
+	}
+
+	err = store.Commit()
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to commit transaction: %s", err)
+	}
+	return bindings, nil
+}
+
+func (a *accessdBlobstore) PutRoleBindings(id *protos.Identity, bindings *accessprotos.RoleBindings) error {
+	if id == nil {
+		return status.Error(codes.InvalidArgument, "nil Identity")
+	}
+	if bindings == nil {
+		return status.Error(codes.InvalidArgument, "nil RoleBindings")
+	}
+
+	store, err := a.factory.StartTransaction(nil)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to start transaction: %s", err)
+	}
+	defer store.Rollback()
+
+	marshaledBindings, err := proto.Marshal(bindings)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal role bindings: %s", err)
+	}
+
+	blob := blobstore.Blob{Type: RoleBindingsType, Key: id.HashString(), Value: marshaledBindings}
+	err = store.CreateOrUpdate(placeholderNetworkID, blobstore.Blobs{blob})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to put role bindings: %s", err)
+	}
+
+	err = store.Commit()
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to commit transaction: %s", err)
+	}
+	return nil
+}
@@ -168,4 +168,61 @@ func testAccessdStorageImpl(t *testing.T, store storage.AccessdStorage) {
 
 	err = store.DeleteACL(nil)
 	assert.Error(t, err)
+
+	testRolesImpl(t, store)
+}
+
+func testRolesImpl(t *testing.T, store storage.AccessdStorage) {
+	op := identity.NewOperator("test_operator_0")
+	role0 := &accessprotos.Role{
+		Name:  "role0",
+		Rules: []*accessprotos.Role_Rule{{Path: "/magma/v1/networks/:network_id", Methods: []string{"GET"}}},
+	}
+	role1 := &accessprotos.Role{
+		Name:        "role1",
+		Description: "everything",
+		Rules:       []*accessprotos.Role_Rule{{Path: "/magma"}},
+	}
+
+	// Empty initially
+	roles, err := store.ListRoles()
+	assert.NoError(t, err)
+	assert.Len(t, roles, 0)
+	_, err = store.GetRoleBindings(op)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "NotFound")
+
+	// Put and list roles
+	assert.NoError(t, store.PutRole(role0))
+	assert.NoError(t, store.PutRole(role1))
+	roles, err = store.ListRoles()
+	assert.NoError(t, err)
+	assert.Len(t, roles, 2)
+	for _, role := range roles {
+		assert.True(t, proto.Equal(map[string]*accessprotos.Role{"role0": role0, "role1": role1}[role.Name], role))
+	}
+
+	// Delete role
+	assert.NoError(t, store.DeleteRole("role0"))
+	roles, err = store.ListRoles()
+	assert.NoError(t, err)
+	assert.Len(t, roles, 1)
+	assert.True(t, proto.Equal(role1, roles[0]))
+
+	// Put and Get role bindings
+	bindings := &accessprotos.RoleBindings{
+		Operator: op,
+		Bindings: []*accessprotos.RoleBinding{{Role: "role1", Networks: []string{"n1"}, Tenants: []int64{1}}},
+	}
+	assert.NoError(t, store.PutRoleBindings(op, bindings))
+	bindingsRecvd, err := store.GetRoleBindings(op)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(bindings, bindingsRecvd))
+
+	// Nil arguments return err, don't cause panic
+	assert.Error(t, store.PutRole(nil))
+	_, err = store.GetRoleBindings(nil)
+	assert.Error(t, err)
+	assert.Error(t, store.PutRoleBindings(nil, bindings))
+	assert.Error(t, store.PutRoleBindings(op, nil))
 }
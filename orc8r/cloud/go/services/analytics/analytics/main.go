@@ -49,14 +49,21 @@ func main() {
 }
 
 func getExporter(config *analytics.Config) analytics.Exporter {
+	exporters, err := analytics.NewExporters(config.Exporters)
+	if err != nil {
+		glog.Fatalf("Error creating analytics exporters: %s", err)
+	}
 	if config.ExportMetrics {
-		return analytics.NewWWWExporter(
+		exporters = append(exporters, analytics.NewWWWExporter(
 			config.MetricsPrefix,
 			config.AppID,
 			config.AppSecret,
 			config.MetricExportURL,
 			config.CategoryName,
-		)
+		))
+	}
+	if len(exporters) == 0 {
+		return nil
 	}
-	return nil
+	return analytics.NewMultiExporter(exporters...)
 }
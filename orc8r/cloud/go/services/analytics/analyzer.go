@@ -83,23 +83,34 @@ func (a *PrometheusAnalyzer) Analyze() {
 		glog.Infof("err %v failed to get remote collectors", err)
 		return
 	}
+	var results []*protos.CalculationResult
 	for _, c := range collectorClients {
 		collectResp, err := c.Collect(context.Background(), &protos.CollectRequest{})
 		if err != nil || collectResp == nil {
 			glog.Infof("err %v or empty response when attempting to collect from service", err)
 			continue
 		}
-		for _, res := range collectResp.GetResults() {
-			if a.Exporter == nil {
-				continue
-			}
-
-			err = a.Exporter.Export(res, http.DefaultClient)
-			if err != nil {
-				glog.Errorf("Error exporting result: %v", err)
-			} else {
-				glog.V(10).Infof("Exported %s, %s, %f", res.MetricName, res.Labels, res.Value)
-			}
+		results = append(results, collectResp.GetResults()...)
+	}
+	if a.Exporter == nil || len(results) == 0 {
+		return
+	}
+
+	if batchExporter, ok := a.Exporter.(BatchExporter); ok {
+		err = batchExporter.ExportBatch(results)
+		if err != nil {
+			glog.Errorf("Error exporting results: %v", err)
+		} else {
+			glog.V(10).Infof("Exported %d results", len(results))
+		}
+		return
+	}
+	for _, res := range results {
+		err = a.Exporter.Export(res, http.DefaultClient)
+		if err != nil {
+			glog.Errorf("Error exporting result: %v", err)
+		} else {
+			glog.V(10).Infof("Exported %s, %s, %f", res.MetricName, res.Labels, res.Value)
 		}
 	}
 }
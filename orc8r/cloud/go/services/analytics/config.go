@@ -41,6 +41,10 @@ type Config struct {
 	AppID           string `yaml:"appID"`
 	MetricExportURL string `yaml:"metricExportURL"`
 	CategoryName    string `yaml:"categoryName"`
+
+	// Exporters are additional exporters, created from the exporter
+	// registry by type
+	Exporters []ExporterConfig `yaml:"exporters"`
 }
 
 func GetServiceConfig() Config {
@@ -14,7 +14,8 @@ limitations under the License.
 // Package analytics provides the analytics service.
 // Analytics service primarily aggregates all the metrics reported by various
 // reporting services (lte/cwf/orchestrator) based on a user configurable
-// periodic interval and exports it to the user-configured exporter.
+// periodic interval and exports it to the user-configured exporters, which
+// are created by type from the exporter registry.
 package analytics
 
 const (
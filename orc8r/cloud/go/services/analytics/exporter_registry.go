@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/analytics/protos"
+	"magma/orc8r/lib/go/metrics"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+// Built-in exporter types
+const (
+	WebhookExporterType = "webhook"
+	LineExporterType    = "line"
+	FileExporterType    = "file"
+)
+
+const defaultExportTimeout = 30 * time.Second
+
+// BatchExporter is implemented by exporters which export all results of an
+// analysis run at once, e.g. into a single file.
+type BatchExporter interface {
+	Exporter
+	ExportBatch(results []*protos.CalculationResult) error
+}
+
+// ExporterConfig configures an exporter in the analytics service config.
+// Which fields apply depends on the exporter type.
+type ExporterConfig struct {
+	// Type selects the exporter's factory from the registry.
+	Type string `yaml:"type"`
+	// URL is the webhook endpoint.
+	URL string `yaml:"url"`
+	// Headers are added to webhook requests, e.g. for authorization.
+	Headers map[string]string `yaml:"headers"`
+	// Address is the host:port of the line protocol endpoint.
+	Address string `yaml:"address"`
+	// Directory is where file exports are dropped.
+	Directory string `yaml:"directory"`
+	// Format of file exports: csv, jsonl or parquet.
+	Format string `yaml:"format"`
+	// Timeout of each export. Defaults to 30s.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// ExporterFactory creates an exporter from its config.
+type ExporterFactory func(cfg ExporterConfig) (Exporter, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]ExporterFactory{
+		WebhookExporterType: NewWebhookExporter,
+		LineExporterType:    NewLineExporter,
+		FileExporterType:    NewFileExporter,
+	}
+)
+
+// RegisterExporterFactory registers the factory for an exporter type,
+// overwriting any existing factory of the type.
+func RegisterExporterFactory(exporterType string, factory ExporterFactory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[exporterType] = factory
+}
+
+// NewExporters creates the configured exporters from the registry.
+func NewExporters(cfgs []ExporterConfig) ([]Exporter, error) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	var ret []Exporter
+	for i, cfg := range cfgs {
+		factory, ok := factories[cfg.Type]
+		if !ok {
+			return nil, errors.Errorf("unknown type %q of analytics exporter %d", cfg.Type, i)
+		}
+		e, err := factory(cfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "create %s analytics exporter", cfg.Type)
+		}
+		ret = append(ret, e)
+	}
+	return ret, nil
+}
+
+// NewMultiExporter returns an exporter which exports to each of the
+// exporters, batching results for those which support it.
+func NewMultiExporter(exporters ...Exporter) BatchExporter {
+	return multiExporter(exporters)
+}
+
+type multiExporter []Exporter
+
+func (m multiExporter) Export(res *protos.CalculationResult, client HttpClient) error {
+	var errs *multierror.Error
+	for _, e := range m {
+		errs = multierror.Append(errs, e.Export(res, client))
+	}
+	return errs.ErrorOrNil()
+}
+
+func (m multiExporter) ExportBatch(results []*protos.CalculationResult) error {
+	var errs *multierror.Error
+	for _, e := range m {
+		errs = multierror.Append(errs, exportResults(e, results))
+	}
+	return errs.ErrorOrNil()
+}
+
+// exportResults exports the results in one batch if the exporter supports
+// it, else one at a time.
+func exportResults(e Exporter, results []*protos.CalculationResult) error {
+	if batchExporter, ok := e.(BatchExporter); ok {
+		return batchExporter.ExportBatch(results)
+	}
+	var errs *multierror.Error
+	for _, res := range results {
+		errs = multierror.Append(errs, e.Export(res, http.DefaultClient))
+	}
+	return errs.ErrorOrNil()
+}
+
+// exportRecord is the serialized form of a calculation result, shared by the
+// built-in exporters.
+type exportRecord struct {
+	// Time is when the result was exported, in seconds since the epoch.
+	// Calculation results don't carry the time they were calculated at, so
+	// all records of a batch share its export time.
+	Time       int64             `json:"time"`
+	MetricName string            `json:"metric_name"`
+	NetworkID  string            `json:"network_id,omitempty"`
+	Value      float64           `json:"value"`
+	Labels     map[string]string `json:"labels"`
+}
+
+func makeExportRecords(results []*protos.CalculationResult) []exportRecord {
+	now := clock.Now().Unix()
+	ret := make([]exportRecord, 0, len(results))
+	for _, res := range results {
+		labels := res.Labels
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		ret = append(ret, exportRecord{
+			Time:       now,
+			MetricName: res.MetricName,
+			NetworkID:  labels[metrics.NetworkLabelName],
+			Value:      res.Value,
+			Labels:     labels,
+		})
+	}
+	return ret
+}
+
+func (cfg ExporterConfig) getTimeout() time.Duration {
+	if cfg.Timeout <= 0 {
+		return defaultExportTimeout
+	}
+	return cfg.Timeout
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/analytics/protos"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testResults = []*protos.CalculationResult{
+	{MetricName: "active_users", Value: 3, Labels: map[string]string{"networkID": "n1", "days": "7"}},
+	{MetricName: "throughput", Value: 1.5},
+}
+
+func TestNewExporters(t *testing.T) {
+	_, err := NewExporters([]ExporterConfig{{Type: "unknown"}})
+	assert.EqualError(t, err, `unknown type "unknown" of analytics exporter 0`)
+	_, err = NewExporters([]ExporterConfig{{Type: WebhookExporterType}})
+	assert.EqualError(t, err, "create webhook analytics exporter: webhook exporter requires a URL")
+
+	RegisterExporterFactory("test", func(cfg ExporterConfig) (Exporter, error) { return testExporter, nil })
+	exporters, err := NewExporters([]ExporterConfig{{Type: "test"}, {Type: WebhookExporterType, URL: "http://localhost"}})
+	require.NoError(t, err)
+	assert.Len(t, exporters, 2)
+	assert.Equal(t, testExporter, exporters[0])
+}
+
+func TestWebhookExporter(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	var received []exportRecord
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("bad gateway"))
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer srv.Close()
+
+	e, err := NewWebhookExporter(ExporterConfig{URL: srv.URL, Headers: map[string]string{"Authorization": "secret"}})
+	require.NoError(t, err)
+	require.NoError(t, e.(BatchExporter).ExportBatch(testResults))
+	assert.Equal(t, []exportRecord{
+		{Time: 1000, MetricName: "active_users", NetworkID: "n1", Value: 3, Labels: map[string]string{"networkID": "n1", "days": "7"}},
+		{Time: 1000, MetricName: "throughput", Value: 1.5, Labels: map[string]string{}},
+	}, received)
+
+	e, err = NewWebhookExporter(ExporterConfig{URL: srv.URL + "/fail", Headers: map[string]string{"Authorization": "secret"}})
+	require.NoError(t, err)
+	assert.EqualError(t, e.Export(testResults[0], nil), "webhook returned 502 Bad Gateway: bad gateway")
+}
+
+func TestLineExporter(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+	lines := make(chan []string)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var ret []string
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			ret = append(ret, scanner.Text())
+		}
+		lines <- ret
+	}()
+
+	e, err := NewLineExporter(ExporterConfig{Address: lis.Addr().String()})
+	require.NoError(t, err)
+	require.NoError(t, e.(BatchExporter).ExportBatch(testResults))
+	assert.Equal(t, []string{
+		"n1\t" + `{"time":1000,"metric_name":"active_users","network_id":"n1","value":3,"labels":{"days":"7","networkID":"n1"}}`,
+		"\t" + `{"time":1000,"metric_name":"throughput","value":1.5,"labels":{}}`,
+	}, <-lines)
+}
+
+func TestFileExporter(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	dir, err := ioutil.TempDir("", "analytics_export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = NewFileExporter(ExporterConfig{Directory: dir, Format: "xlsx"})
+	assert.EqualError(t, err, `unsupported file export format "xlsx"`)
+
+	e, err := NewFileExporter(ExporterConfig{Directory: dir})
+	require.NoError(t, err)
+	require.NoError(t, e.(BatchExporter).ExportBatch(testResults))
+	// Files of the same time don't collide
+	require.NoError(t, e.Export(testResults[1], nil))
+
+	e, err = NewFileExporter(ExporterConfig{Directory: dir, Format: JSONLFileFormat})
+	require.NoError(t, err)
+	require.NoError(t, e.Export(testResults[1], nil))
+
+	e, err = NewFileExporter(ExporterConfig{Directory: dir, Format: ParquetFileFormat})
+	require.NoError(t, err)
+	require.NoError(t, e.(BatchExporter).ExportBatch(testResults))
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	assert.Equal(t, []string{"analytics-19700101T001640Z-1.csv", "analytics-19700101T001640Z.csv", "analytics-19700101T001640Z.jsonl", "analytics-19700101T001640Z.parquet"}, names)
+
+	contents, err := ioutil.ReadFile(filepath.Join(dir, "analytics-19700101T001640Z.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, "time,metric_name,network_id,value,labels\n"+
+		`1000,active_users,n1,3,"{""days"":""7"",""networkID"":""n1""}"`+"\n"+
+		"1000,throughput,,1.5,{}\n", string(contents))
+
+	contents, err = ioutil.ReadFile(filepath.Join(dir, "analytics-19700101T001640Z.jsonl"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"time":1000,"metric_name":"throughput","value":1.5,"labels":{}}`+"\n", string(contents))
+
+	// Parquet files are framed by magic bytes, with the footer length before
+	// the trailing ones
+	contents, err = ioutil.ReadFile(filepath.Join(dir, "analytics-19700101T001640Z.parquet"))
+	require.NoError(t, err)
+	assert.Equal(t, "PAR1", string(contents[:4]))
+	assert.Equal(t, "PAR1", string(contents[len(contents)-4:]))
+	footerLen := int(binary.LittleEndian.Uint32(contents[len(contents)-8:]))
+	footer := contents[len(contents)-8-footerLen : len(contents)-8]
+	assert.Contains(t, string(footer), "metric_name")
+	assert.Contains(t, string(footer), "magma analytics")
+}
+
+func TestMultiExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "analytics_export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fileExporter, err := NewFileExporter(ExporterConfig{Directory: dir})
+	require.NoError(t, err)
+	failing, err := NewWebhookExporter(ExporterConfig{URL: "http://127.0.0.1:1", Timeout: time.Second})
+	require.NoError(t, err)
+
+	// Failing exporters don't prevent others from exporting
+	err = NewMultiExporter(failing, fileExporter).ExportBatch(testResults)
+	assert.Error(t, err)
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
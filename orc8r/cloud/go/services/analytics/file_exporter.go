@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/analytics/protos"
+
+	"github.com/pkg/errors"
+)
+
+// File export formats
+const (
+	CSVFileFormat     = "csv"
+	JSONLFileFormat   = "jsonl"
+	ParquetFileFormat = "parquet"
+)
+
+const fileTimeFormat = "20060102T150405Z"
+
+var csvHeader = []string{"time", "metric_name", "network_id", "value", "labels"}
+
+// fileExporter drops a file of results into a directory per batch. Files
+// are written under a temporary name then renamed, so consumers never see
+// partial files.
+type fileExporter struct {
+	dir    string
+	format string
+}
+
+// NewFileExporter returns an exporter which drops files into cfg.Directory,
+// in cfg.Format (CSV by default).
+// The time column of exported files holds the export time of each batch,
+// rather than the calculation time of its results.
+func NewFileExporter(cfg ExporterConfig) (Exporter, error) {
+	if cfg.Directory == "" {
+		return nil, errors.New("file exporter requires a directory")
+	}
+	format := cfg.Format
+	if format == "" {
+		format = CSVFileFormat
+	}
+	if format != CSVFileFormat && format != JSONLFileFormat && format != ParquetFileFormat {
+		return nil, errors.Errorf("unsupported file export format %q", format)
+	}
+	if err := os.MkdirAll(cfg.Directory, 0755); err != nil {
+		return nil, errors.Wrapf(err, "create export directory %s", cfg.Directory)
+	}
+	return &fileExporter{dir: cfg.Directory, format: format}, nil
+}
+
+func (e *fileExporter) Export(res *protos.CalculationResult, _ HttpClient) error {
+	return e.ExportBatch([]*protos.CalculationResult{res})
+}
+
+func (e *fileExporter) ExportBatch(results []*protos.CalculationResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	name := fmt.Sprintf("analytics-%s.%s", clock.Now().UTC().Format(fileTimeFormat), e.format)
+	f, err := os.Create(filepath.Join(e.dir, "."+name))
+	if err != nil {
+		return errors.Wrap(err, "create export file")
+	}
+	defer os.Remove(f.Name())
+
+	records := makeExportRecords(results)
+	switch e.format {
+	case CSVFileFormat:
+		err = writeCSV(f, records)
+	case JSONLFileFormat:
+		err = writeJSONL(f, records)
+	default:
+		err = writeParquet(f, records)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return errors.Wrap(err, "write export file")
+	}
+	return errors.Wrap(uniqueRename(f.Name(), filepath.Join(e.dir, name)), "write export file")
+}
+
+func writeCSV(w io.Writer, records []exportRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		labels, err := json.Marshal(r.Labels)
+		if err != nil {
+			return err
+		}
+		row := []string{
+			strconv.FormatInt(r.Time, 10),
+			r.MetricName,
+			r.NetworkID,
+			strconv.FormatFloat(r.Value, 'f', -1, 64),
+			string(labels),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeJSONL(w io.Writer, records []exportRecord) error {
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uniqueRename renames the file to dst, suffixing dst's base name with a
+// counter if a file of that name already exists.
+func uniqueRename(src, dst string) error {
+	ext := filepath.Ext(dst)
+	base := dst[:len(dst)-len(ext)]
+	for i := 1; ; i++ {
+		_, err := os.Stat(dst)
+		if os.IsNotExist(err) {
+			return os.Rename(src, dst)
+		}
+		if err != nil {
+			return err
+		}
+		dst = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"time"
+
+	"magma/orc8r/cloud/go/services/analytics/protos"
+
+	"github.com/pkg/errors"
+)
+
+// lineKeySeparator separates each line's key from its value, matching the
+// default of kafka-console-producer's parse.key mode.
+const lineKeySeparator = '\t'
+
+// lineExporter writes results over TCP in a Kafka-compatible line protocol:
+// one message per line, of the form <key>\t<value>, where the key is the
+// result's network ID and the value is the JSON record. This is the input
+// format of kafka-console-producer with parse.key=true, so any stand-in
+// which pipes its input to a Kafka producer can receive it.
+type lineExporter struct {
+	address string
+	cfg     ExporterConfig
+}
+
+// NewLineExporter returns an exporter which writes results to cfg.Address.
+func NewLineExporter(cfg ExporterConfig) (Exporter, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("line exporter requires an address")
+	}
+	return &lineExporter{address: cfg.Address, cfg: cfg}, nil
+}
+
+func (e *lineExporter) Export(res *protos.CalculationResult, _ HttpClient) error {
+	return e.ExportBatch([]*protos.CalculationResult{res})
+}
+
+func (e *lineExporter) ExportBatch(results []*protos.CalculationResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	conn, err := net.DialTimeout("tcp", e.address, e.cfg.getTimeout())
+	if err != nil {
+		return errors.Wrapf(err, "connect to line exporter endpoint %s", e.address)
+	}
+	defer conn.Close()
+	err = conn.SetWriteDeadline(time.Now().Add(e.cfg.getTimeout()))
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(conn)
+	for _, record := range makeExportRecords(results) {
+		value, err := json.Marshal(record)
+		if err != nil {
+			return errors.Wrap(err, "marshal analytics result")
+		}
+		w.WriteString(record.NetworkID)
+		w.WriteByte(lineKeySeparator)
+		w.Write(value)
+		w.WriteByte('\n')
+	}
+	return errors.Wrap(w.Flush(), "write analytics results")
+}
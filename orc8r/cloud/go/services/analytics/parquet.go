@@ -0,0 +1,266 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+
+	"github.com/golang/snappy"
+)
+
+// Minimal Parquet writer for file exports. Each file holds a single row
+// group of required, PLAIN encoded, snappy compressed columns, one data page
+// per column. The file metadata is Thrift compact protocol encoded, per
+// https://github.com/apache/parquet-format.
+
+const parquetMagic = "PAR1"
+
+// Parquet physical types, converted types and other enums
+const (
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetUTF8            = 0
+	parquetTimestampMillis = 9
+
+	parquetRequired      = 0
+	parquetPlain         = 0
+	parquetRLE           = 3
+	parquetSnappy        = 1
+	parquetDataPage      = 0
+	parquetFormatVersion = 1
+)
+
+// Thrift compact protocol types
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+type parquetColumn struct {
+	name         string
+	physicalType int32
+	// convertedType is the logical type, or -1 for none
+	convertedType int32
+	// values is the PLAIN encoding of the column's values
+	values bytes.Buffer
+}
+
+func writeParquet(w io.Writer, records []exportRecord) error {
+	columns := []*parquetColumn{
+		{name: "time", physicalType: parquetInt64, convertedType: parquetTimestampMillis},
+		{name: "metric_name", physicalType: parquetByteArray, convertedType: parquetUTF8},
+		{name: "network_id", physicalType: parquetByteArray, convertedType: parquetUTF8},
+		{name: "value", physicalType: parquetDouble, convertedType: -1},
+		{name: "labels", physicalType: parquetByteArray, convertedType: parquetUTF8},
+	}
+	for _, r := range records {
+		labels, err := json.Marshal(r.Labels)
+		if err != nil {
+			return err
+		}
+		putInt64(&columns[0].values, r.Time*1000)
+		putByteArray(&columns[1].values, []byte(r.MetricName))
+		putByteArray(&columns[2].values, []byte(r.NetworkID))
+		putInt64(&columns[3].values, int64(math.Float64bits(r.Value)))
+		putByteArray(&columns[4].values, labels)
+	}
+
+	buf := bytes.NewBufferString(parquetMagic)
+	numRows := int64(len(records))
+	var chunks []func(*thriftWriter)
+	var totalSize int64
+	for _, col := range columns {
+		col := col
+		offset := int64(buf.Len())
+		compressed := snappy.Encode(nil, col.values.Bytes())
+		header := &thriftWriter{}
+		header.i32Field(1, parquetDataPage)
+		header.i32Field(2, int32(col.values.Len()))
+		header.i32Field(3, int32(len(compressed)))
+		header.structField(5, func(t *thriftWriter) {
+			t.i32Field(1, int32(numRows))
+			t.i32Field(2, parquetPlain)
+			t.i32Field(3, parquetRLE)
+			t.i32Field(4, parquetRLE)
+		})
+		header.stop()
+		buf.Write(header.Bytes())
+		buf.Write(compressed)
+
+		uncompressedSize := int64(header.Len() + col.values.Len())
+		compressedSize := int64(header.Len() + len(compressed))
+		totalSize += uncompressedSize
+		chunks = append(chunks, func(t *thriftWriter) {
+			t.i64Field(2, offset)
+			t.structField(3, func(t *thriftWriter) {
+				t.i32Field(1, col.physicalType)
+				t.listField(2, thriftI32, 1, func(t *thriftWriter) { t.varint(parquetPlain) })
+				t.listField(3, thriftBinary, 1, func(t *thriftWriter) { t.binary(col.name) })
+				t.i32Field(4, parquetSnappy)
+				t.i64Field(5, numRows)
+				t.i64Field(6, uncompressedSize)
+				t.i64Field(7, compressedSize)
+				t.i64Field(9, offset)
+			})
+		})
+	}
+
+	schema := []func(*thriftWriter){func(t *thriftWriter) {
+		t.binaryField(4, "schema")
+		t.i32Field(5, int32(len(columns)))
+	}}
+	for _, col := range columns {
+		col := col
+		schema = append(schema, func(t *thriftWriter) {
+			t.i32Field(1, col.physicalType)
+			t.i32Field(3, parquetRequired)
+			t.binaryField(4, col.name)
+			if col.convertedType >= 0 {
+				t.i32Field(6, col.convertedType)
+			}
+		})
+	}
+	rowGroup := func(t *thriftWriter) {
+		t.structListField(1, chunks)
+		t.i64Field(2, totalSize)
+		t.i64Field(3, numRows)
+	}
+
+	footer := &thriftWriter{}
+	footer.i32Field(1, parquetFormatVersion)
+	footer.structListField(2, schema)
+	footer.i64Field(3, numRows)
+	footer.structListField(4, []func(*thriftWriter){rowGroup})
+	footer.binaryField(6, "magma analytics")
+	footer.stop()
+	buf.Write(footer.Bytes())
+	binary.Write(buf, binary.LittleEndian, uint32(footer.Len()))
+	buf.WriteString(parquetMagic)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func putInt64(buf *bytes.Buffer, v int64) {
+	binary.Write(buf, binary.LittleEndian, v)
+}
+
+func putByteArray(buf *bytes.Buffer, v []byte) {
+	binary.Write(buf, binary.LittleEndian, uint32(len(v)))
+	buf.Write(v)
+}
+
+// thriftWriter writes Thrift compact protocol structs. Fields of a struct
+// must be written in increasing ID order, followed by a stop.
+type thriftWriter struct {
+	bytes.Buffer
+	lastFieldID int16
+	// parentFieldIDs is the last field ID of each enclosing struct
+	parentFieldIDs []int16
+}
+
+func (t *thriftWriter) fieldHeader(id int16, fieldType byte) {
+	if delta := id - t.lastFieldID; delta > 0 && delta <= 15 {
+		t.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.WriteByte(fieldType)
+		t.varint(int64(id))
+	}
+	t.lastFieldID = id
+}
+
+func (t *thriftWriter) i32Field(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64Field(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) binaryField(id int16, v string) {
+	t.fieldHeader(id, thriftBinary)
+	t.binary(v)
+}
+
+func (t *thriftWriter) structField(id int16, writeFields func(*thriftWriter)) {
+	t.fieldHeader(id, thriftStruct)
+	t.writeStruct(writeFields)
+}
+
+// listField writes a list of size scalar elements.
+func (t *thriftWriter) listField(id int16, elemType byte, size int, writeElems func(*thriftWriter)) {
+	t.fieldHeader(id, thriftList)
+	t.listHeader(elemType, size)
+	writeElems(t)
+}
+
+// structListField writes a list of structs, one per element's fields.
+func (t *thriftWriter) structListField(id int16, elems []func(*thriftWriter)) {
+	t.fieldHeader(id, thriftList)
+	t.listHeader(thriftStruct, len(elems))
+	for _, writeFields := range elems {
+		t.writeStruct(writeFields)
+	}
+}
+
+func (t *thriftWriter) listHeader(elemType byte, size int) {
+	if size < 15 {
+		t.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	t.WriteByte(0xf0 | elemType)
+	t.uvarint(uint64(size))
+}
+
+func (t *thriftWriter) writeStruct(writeFields func(*thriftWriter)) {
+	t.parentFieldIDs = append(t.parentFieldIDs, t.lastFieldID)
+	t.lastFieldID = 0
+	writeFields(t)
+	t.stop()
+}
+
+// stop ends the current struct.
+func (t *thriftWriter) stop() {
+	t.WriteByte(0)
+	if n := len(t.parentFieldIDs); n > 0 {
+		t.lastFieldID = t.parentFieldIDs[n-1]
+		t.parentFieldIDs = t.parentFieldIDs[:n-1]
+	}
+}
+
+func (t *thriftWriter) binary(v string) {
+	t.uvarint(uint64(len(v)))
+	t.WriteString(v)
+}
+
+// varint writes a zigzag encoded varint, as used for i16, i32 and i64.
+func (t *thriftWriter) varint(v int64) {
+	t.uvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (t *thriftWriter) uvarint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	t.Write(buf[:binary.PutUvarint(buf[:], v)])
+}
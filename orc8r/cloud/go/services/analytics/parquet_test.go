@@ -0,0 +1,290 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goldenFilepath holds the export of testRecords. Whenever it's regenerated,
+// it must be checked with a reference Parquet reader, by running
+// TestWriteParquet_Pyarrow.
+const goldenFilepath = "testdata/export.parquet.golden"
+
+var testRecords = []exportRecord{
+	{Time: 1000, MetricName: "active_users", NetworkID: "n1", Value: 3, Labels: map[string]string{"networkID": "n1", "days": "7"}},
+	{Time: 1000, MetricName: "throughput", Value: 1.5, Labels: map[string]string{}},
+}
+
+func TestWriteParquet(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, writeParquet(buf, testRecords))
+	contents := buf.Bytes()
+
+	// The footer is framed by its length and the trailing magic bytes
+	assert.Equal(t, "PAR1", string(contents[:4]))
+	assert.Equal(t, "PAR1", string(contents[len(contents)-4:]))
+	footerLen := int(binary.LittleEndian.Uint32(contents[len(contents)-8:]))
+	footer, err := readThriftStruct(bytes.NewReader(contents[len(contents)-8-footerLen : len(contents)-8]))
+	require.NoError(t, err)
+	assert.Equal(t, int64(parquetFormatVersion), footer[1])
+	assert.Equal(t, int64(2), footer[3])
+	assert.Equal(t, "magma analytics", footer[6])
+
+	schema := footer[2].([]interface{})
+	require.Len(t, schema, 6)
+	assert.Equal(t, thriftFields{4: "schema", 5: int64(5)}, schema[0])
+	assert.Equal(t, thriftFields{1: int64(parquetInt64), 3: int64(parquetRequired), 4: "time", 6: int64(parquetTimestampMillis)}, schema[1])
+	assert.Equal(t, thriftFields{1: int64(parquetByteArray), 3: int64(parquetRequired), 4: "metric_name", 6: int64(parquetUTF8)}, schema[2])
+	assert.Equal(t, thriftFields{1: int64(parquetByteArray), 3: int64(parquetRequired), 4: "network_id", 6: int64(parquetUTF8)}, schema[3])
+	assert.Equal(t, thriftFields{1: int64(parquetDouble), 3: int64(parquetRequired), 4: "value"}, schema[4])
+	assert.Equal(t, thriftFields{1: int64(parquetByteArray), 3: int64(parquetRequired), 4: "labels", 6: int64(parquetUTF8)}, schema[5])
+
+	rowGroups := footer[4].([]interface{})
+	require.Len(t, rowGroups, 1)
+	rowGroup := rowGroups[0].(thriftFields)
+	assert.Equal(t, int64(2), rowGroup[3])
+	chunks := rowGroup[1].([]interface{})
+	require.Len(t, chunks, 5)
+
+	// Each column chunk points at a data page holding the column's values
+	columns := map[string][]interface{}{}
+	var totalSize int64
+	for i, chunk := range chunks {
+		meta := chunk.(thriftFields)[3].(thriftFields)
+		offset := meta[9].(int64)
+		assert.Equal(t, offset, chunk.(thriftFields)[2])
+		assert.Equal(t, []interface{}{int64(parquetPlain)}, meta[2])
+		assert.Equal(t, int64(parquetSnappy), meta[4])
+		assert.Equal(t, int64(2), meta[5])
+		totalSize += meta[6].(int64)
+
+		page := bytes.NewReader(contents[offset:])
+		header, err := readThriftStruct(page)
+		require.NoError(t, err)
+		assert.Equal(t, int64(parquetDataPage), header[1])
+		assert.Equal(t, thriftFields{1: int64(2), 2: int64(parquetPlain), 3: int64(parquetRLE), 4: int64(parquetRLE)}, header[5])
+		compressed := make([]byte, header[3].(int64))
+		_, err = io.ReadFull(page, compressed)
+		require.NoError(t, err)
+		headerLen := int64(len(contents[offset:]) - page.Len() - len(compressed))
+		assert.Equal(t, headerLen+int64(len(compressed)), meta[7])
+		assert.Equal(t, headerLen+header[2].(int64), meta[6])
+		if i+1 < len(chunks) {
+			assert.Equal(t, offset+meta[7].(int64), chunks[i+1].(thriftFields)[3].(thriftFields)[9])
+		}
+
+		values, err := snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+		require.Len(t, values, int(header[2].(int64)))
+		name := meta[3].([]interface{})[0].(string)
+		columns[name] = readPlainValues(t, meta[1].(int64), values, 2)
+	}
+	assert.Equal(t, totalSize, rowGroup[2])
+
+	assert.Equal(t, map[string][]interface{}{
+		"time":        {int64(1000000), int64(1000000)},
+		"metric_name": {"active_users", "throughput"},
+		"network_id":  {"n1", ""},
+		"value":       {float64(3), 1.5},
+		"labels":      {`{"days":"7","networkID":"n1"}`, "{}"},
+	}, columns)
+
+	// Empty exports are valid files without rows
+	buf.Reset()
+	require.NoError(t, writeParquet(buf, nil))
+	contents = buf.Bytes()
+	footerLen = int(binary.LittleEndian.Uint32(contents[len(contents)-8:]))
+	footer, err = readThriftStruct(bytes.NewReader(contents[len(contents)-8-footerLen : len(contents)-8]))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), footer[3])
+}
+
+// TestWriteParquet_Golden checks exports match the golden file byte for byte.
+func TestWriteParquet_Golden(t *testing.T) {
+	golden, err := ioutil.ReadFile(goldenFilepath)
+	require.NoError(t, err)
+	buf := &bytes.Buffer{}
+	require.NoError(t, writeParquet(buf, testRecords))
+	assert.Equal(t, golden, buf.Bytes())
+}
+
+// pyarrowReadScript prints the columns of the Parquet file, with timestamps
+// as milliseconds since the epoch.
+const pyarrowReadScript = `
+import json, sys
+import pyarrow as pa
+import pyarrow.parquet as pq
+table = pq.read_table(sys.argv[1])
+time = table.column("time")
+assert pa.types.is_timestamp(time.type) and time.type.unit == "ms", time.type
+table = table.set_column(0, "time", time.cast(pa.int64()))
+json.dump({name: table.column(name).to_pylist() for name in table.column_names}, sys.stdout)
+`
+
+// TestWriteParquet_Pyarrow checks the golden file, and empty exports, are
+// readable by a reference Parquet implementation. It's skipped unless
+// python3 with pyarrow is installed, so it must be run by hand whenever the
+// golden file is regenerated.
+func TestWriteParquet_Pyarrow(t *testing.T) {
+	if err := exec.Command("python3", "-c", "import pyarrow.parquet").Run(); err != nil {
+		t.Skip("requires python3 with pyarrow")
+	}
+	readParquet := func(path string) map[string][]interface{} {
+		out, err := exec.Command("python3", "-c", pyarrowReadScript, path).Output()
+		require.NoError(t, err)
+		columns := map[string][]interface{}{}
+		require.NoError(t, json.Unmarshal(out, &columns))
+		return columns
+	}
+
+	columns := readParquet(goldenFilepath)
+	assert.Equal(t, map[string][]interface{}{
+		"time":        {float64(1000000), float64(1000000)},
+		"metric_name": {"active_users", "throughput"},
+		"network_id":  {"n1", ""},
+		"value":       {float64(3), 1.5},
+		"labels":      {`{"days":"7","networkID":"n1"}`, "{}"},
+	}, columns)
+
+	f, err := ioutil.TempFile("", "analytics-*.parquet")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	require.NoError(t, writeParquet(f, nil))
+	require.NoError(t, f.Close())
+	columns = readParquet(f.Name())
+	assert.Equal(t, map[string][]interface{}{
+		"time":        {},
+		"metric_name": {},
+		"network_id":  {},
+		"value":       {},
+		"labels":      {},
+	}, columns)
+}
+
+// readPlainValues decodes n PLAIN encoded values of the physical type.
+func readPlainValues(t *testing.T, physicalType int64, values []byte, n int) []interface{} {
+	r := bytes.NewReader(values)
+	var ret []interface{}
+	for i := 0; i < n; i++ {
+		switch physicalType {
+		case parquetInt64:
+			var v int64
+			require.NoError(t, binary.Read(r, binary.LittleEndian, &v))
+			ret = append(ret, v)
+		case parquetDouble:
+			var v uint64
+			require.NoError(t, binary.Read(r, binary.LittleEndian, &v))
+			ret = append(ret, math.Float64frombits(v))
+		case parquetByteArray:
+			var l uint32
+			require.NoError(t, binary.Read(r, binary.LittleEndian, &l))
+			v := make([]byte, l)
+			_, err := io.ReadFull(r, v)
+			require.NoError(t, err)
+			ret = append(ret, string(v))
+		default:
+			t.Fatalf("unexpected physical type %d", physicalType)
+		}
+	}
+	assert.Zero(t, r.Len(), "trailing column data")
+	return ret
+}
+
+// thriftFields holds the fields of a Thrift struct by ID. Integers are read
+// as int64, binaries as strings, lists as []interface{} and structs as
+// thriftFields.
+type thriftFields map[int16]interface{}
+
+// readThriftStruct reads a Thrift compact protocol struct, independently of
+// thriftWriter.
+func readThriftStruct(r *bytes.Reader) (thriftFields, error) {
+	fields := thriftFields{}
+	var lastID int16
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == 0 {
+			return fields, nil
+		}
+		id := lastID + int16(b>>4)
+		if b>>4 == 0 {
+			v, err := binary.ReadVarint(r)
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+		fields[id], err = readThriftValue(r, b&0x0f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "field %d", id)
+		}
+		lastID = id
+	}
+}
+
+func readThriftValue(r *bytes.Reader, typ byte) (interface{}, error) {
+	switch typ {
+	case thriftI32, thriftI64:
+		return binary.ReadVarint(r)
+	case thriftBinary:
+		l, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		v := make([]byte, l)
+		_, err = io.ReadFull(r, v)
+		return string(v), err
+	case thriftList:
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		size := uint64(b >> 4)
+		if size == 15 {
+			size, err = binary.ReadUvarint(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+		var elems []interface{}
+		for i := uint64(0); i < size; i++ {
+			elem, err := readThriftValue(r, b&0x0f)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		}
+		return elems, nil
+	case thriftStruct:
+		return readThriftStruct(r)
+	default:
+		return nil, errors.Errorf("unsupported thrift type %d", typ)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"magma/orc8r/cloud/go/services/analytics/protos"
+
+	"github.com/pkg/errors"
+)
+
+// webhookExporter posts results as a JSON array of records to an HTTP
+// endpoint.
+type webhookExporter struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewWebhookExporter returns an exporter which posts results to cfg.URL.
+func NewWebhookExporter(cfg ExporterConfig) (Exporter, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("webhook exporter requires a URL")
+	}
+	return &webhookExporter{
+		url:     cfg.URL,
+		headers: cfg.Headers,
+		client:  &http.Client{Timeout: cfg.getTimeout()},
+	}, nil
+}
+
+func (e *webhookExporter) Export(res *protos.CalculationResult, _ HttpClient) error {
+	return e.ExportBatch([]*protos.CalculationResult{res})
+}
+
+func (e *webhookExporter) ExportBatch(results []*protos.CalculationResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(makeExportRecords(results))
+	if err != nil {
+		return errors.Wrap(err, "marshal analytics results")
+	}
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "create webhook request")
+	}
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "post analytics results")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		errMsg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, bytes.TrimSpace(errMsg))
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/obsidian"
+	"magma/orc8r/cloud/go/obsidian/swagger"
+	swagger_protos "magma/orc8r/cloud/go/obsidian/swagger/protos"
+	"magma/orc8r/cloud/go/orc8r"
+	"magma/orc8r/cloud/go/service"
+	"magma/orc8r/cloud/go/services/audit"
+	"magma/orc8r/cloud/go/services/audit/obsidian/handlers"
+	"magma/orc8r/cloud/go/services/audit/protos"
+	"magma/orc8r/cloud/go/services/audit/servicers"
+	"magma/orc8r/cloud/go/services/audit/storage"
+	"magma/orc8r/cloud/go/sqorc"
+	storage2 "magma/orc8r/cloud/go/storage"
+
+	"github.com/golang/glog"
+)
+
+const (
+	retentionConfigKey      = "retention"
+	exportToEventdConfigKey = "export_to_eventd"
+
+	retentionSweepInterval = time.Hour
+)
+
+func main() {
+	srv, err := service.NewOrchestratorService(orc8r.ModuleName, audit.ServiceName)
+	if err != nil {
+		glog.Fatalf("Error creating audit service %s", err)
+	}
+	db, err := sqorc.Open(storage2.GetSQLDriver(), storage2.GetDatabaseSource())
+	if err != nil {
+		glog.Fatalf("Failed to connect to database: %s", err)
+	}
+	store := storage.NewSQLAuditStorage(db, sqorc.GetSqlBuilder())
+	err = store.Initialize()
+	if err != nil {
+		glog.Fatalf("Error initializing audit database: %s", err)
+	}
+
+	var exporters []servicers.Exporter
+	if srv.Config.MustGetBool(exportToEventdConfigKey) {
+		exporters = append(exporters, servicers.NewEventdExporter())
+	}
+	protos.RegisterAuditServer(srv.GrpcServer, servicers.NewAuditServicer(store, exporters...))
+
+	swagger_protos.RegisterSwaggerSpecServer(srv.GrpcServer, swagger.NewSpecServicerFromFile(audit.ServiceName))
+
+	obsidian.AttachHandlers(srv.EchoServer, handlers.GetObsidianHandlers())
+
+	retention, err := time.ParseDuration(srv.Config.MustGetString(retentionConfigKey))
+	if err != nil {
+		glog.Fatalf("Invalid audit retention: %s", err)
+	}
+	go sweepExpiredEntries(store, retention)
+
+	err = srv.Run()
+	if err != nil {
+		glog.Fatalf("Error running service: %s", err)
+	}
+}
+
+// sweepExpiredEntries periodically deletes audit entries older than the
+// retention duration.
+func sweepExpiredEntries(store storage.AuditStorage, retention time.Duration) {
+	for range time.Tick(retentionSweepInterval) {
+		n, err := store.DeleteEntriesBefore(clock.Now().Add(-retention))
+		if err != nil {
+			glog.Errorf("Failed to delete expired audit entries: %s", err)
+			continue
+		}
+		if n > 0 {
+			glog.Infof("Deleted %d expired audit entries", n)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+
+	"magma/orc8r/cloud/go/services/audit/protos"
+	merrors "magma/orc8r/lib/go/errors"
+	"magma/orc8r/lib/go/registry"
+
+	"github.com/golang/glog"
+)
+
+// RecordEntry durably records an audit entry. The entry's ID is assigned by
+// the audit service, as is its timestamp if unset.
+func RecordEntry(ctx context.Context, entry *protos.Entry) error {
+	client, err := getAuditClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.RecordEntry(ctx, entry)
+	return err
+}
+
+// ListEntries returns a page of audit entries matching the request, newest
+// first, along with the token of the next page, which is empty when there
+// are no more entries.
+func ListEntries(ctx context.Context, req *protos.ListEntriesRequest) ([]*protos.Entry, string, error) {
+	client, err := getAuditClient()
+	if err != nil {
+		return nil, "", err
+	}
+	res, err := client.ListEntries(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	return res.Entries, res.NextPageToken, nil
+}
+
+func getAuditClient() (protos.AuditClient, error) {
+	conn, err := registry.GetConnection(ServiceName)
+	if err != nil {
+		initErr := merrors.NewInitError(err, ServiceName)
+		glog.Error(initErr)
+		return nil, initErr
+	}
+	return protos.NewAuditClient(conn), nil
+}
@@ -0,0 +1,24 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit contains the audit service, which durably records mutating
+// REST requests made through obsidian: who made them, their outcome, and the
+// configurator entities they changed.
+package audit
+
+const (
+	ServiceName = "AUDIT"
+
+	// DBTableName is the name of the SQL table holding audit entries.
+	DBTableName = "audit_log"
+)
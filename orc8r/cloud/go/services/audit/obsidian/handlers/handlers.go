@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package handlers contains the REST API handlers through which operators
+// read the audit log.
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"magma/orc8r/cloud/go/obsidian"
+	"magma/orc8r/cloud/go/services/audit"
+	"magma/orc8r/cloud/go/services/audit/obsidian/models"
+	"magma/orc8r/cloud/go/services/audit/protos"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/labstack/echo"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	AuditRootPath = obsidian.V1Root + "audit"
+
+	ParamOperator  = "operator"
+	ParamNetworkID = "network_id"
+	ParamStart     = "start"
+	ParamEnd       = "end"
+	ParamPageSize  = "page_size"
+	ParamPageToken = "page_token"
+)
+
+// GetObsidianHandlers returns all the obsidian handlers for the audit log.
+func GetObsidianHandlers() []obsidian.Handler {
+	return []obsidian.Handler{
+		{Path: AuditRootPath, Methods: obsidian.GET, HandlerFunc: listEntries},
+	}
+}
+
+func listEntries(c echo.Context) error {
+	req, err := getListEntriesRequest(c)
+	if err != nil {
+		return obsidian.HttpError(err, http.StatusBadRequest)
+	}
+	entries, nextPageToken, err := audit.ListEntries(c.Request().Context(), req)
+	if status.Code(err) == codes.InvalidArgument {
+		return obsidian.HttpError(err, http.StatusBadRequest)
+	}
+	if err != nil {
+		return obsidian.HttpError(err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, (&models.PaginatedAuditEntries{}).FromProto(entries, nextPageToken))
+}
+
+func getListEntriesRequest(c echo.Context) (*protos.ListEntriesRequest, error) {
+	req := &protos.ListEntriesRequest{
+		Operator:  c.QueryParam(ParamOperator),
+		NetworkId: c.QueryParam(ParamNetworkID),
+		PageToken: c.QueryParam(ParamPageToken),
+	}
+	var err error
+	if start := c.QueryParam(ParamStart); start != "" {
+		req.Start, err = parseTimeParam(ParamStart, start)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if end := c.QueryParam(ParamEnd); end != "" {
+		req.End, err = parseTimeParam(ParamEnd, end)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if pageSize := c.QueryParam(ParamPageSize); pageSize != "" {
+		size, err := strconv.ParseUint(pageSize, 10, 32)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid page size parameter")
+		}
+		req.PageSize = uint32(size)
+	}
+	return req, nil
+}
+
+func parseTimeParam(name, value string) (*timestamp.Timestamp, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s parameter, expected RFC 3339 time", name)
+	}
+	return ptypes.TimestampProto(t)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers_test
+
+import (
+	"testing"
+
+	"magma/orc8r/cloud/go/obsidian"
+	"magma/orc8r/cloud/go/obsidian/tests"
+	"magma/orc8r/cloud/go/services/audit/obsidian/handlers"
+	"magma/orc8r/cloud/go/services/audit/obsidian/models"
+	"magma/orc8r/cloud/go/services/audit/protos"
+	audit_test_init "magma/orc8r/cloud/go/services/audit/test_init"
+	"magma/orc8r/cloud/go/services/audit/test_utils"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListEntries(t *testing.T) {
+	store := audit_test_init.StartTestService(t)
+	e := echo.New()
+	listEntries := tests.GetHandlerByPathAndMethod(t, handlers.GetObsidianHandlers(), "/magma/v1/audit", obsidian.GET).HandlerFunc
+
+	// Empty
+	tc := tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/audit",
+		Handler:        listEntries,
+		ExpectedStatus: 200,
+		ExpectedResult: &models.PaginatedAuditEntries{Entries: []*models.AuditEntry{}},
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	e0 := test_utils.NewEntry(t, "e0", 1000, "bob", "n1")
+	e0.TenantId = &wrappers.Int64Value{Value: 7}
+	e0.RevisionBefore = 3
+	e0.RevisionAfter = 4
+	e0.EntityChanges = []*protos.EntityChange{
+		{Type: "magmad_gateway", Key: "gw1", VersionAfter: &wrappers.UInt64Value{Value: 0}},
+	}
+	e1 := test_utils.NewEntry(t, "e1", 2000, "alice", "n2")
+	e2 := test_utils.NewEntry(t, "e2", 3000, "bob", "")
+	for _, entry := range []*protos.Entry{e0, e1, e2} {
+		require.NoError(t, store.PutEntry(entry))
+	}
+
+	// All
+	tc.ExpectedResult = (&models.PaginatedAuditEntries{}).FromProto([]*protos.Entry{e2, e1, e0}, "")
+	tests.RunUnitTest(t, e, tc)
+
+	// Filtered
+	tc.URL = "/magma/v1/audit?operator=bob&start=1970-01-01T00:00:01Z&end=1970-01-01T00:00:03Z"
+	tc.ExpectedResult = (&models.PaginatedAuditEntries{}).FromProto([]*protos.Entry{e0}, "")
+	tests.RunUnitTest(t, e, tc)
+
+	tc.URL = "/magma/v1/audit?network_id=n2"
+	tc.ExpectedResult = (&models.PaginatedAuditEntries{}).FromProto([]*protos.Entry{e1}, "")
+	tests.RunUnitTest(t, e, tc)
+
+	// Invalid params
+	tc = tests.Test{
+		Method:                 "GET",
+		URL:                    "/magma/v1/audit?start=yesterday",
+		Handler:                listEntries,
+		ExpectedStatus:         400,
+		ExpectedErrorSubstring: "invalid start parameter",
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	tc.URL = "/magma/v1/audit?page_size=-1"
+	tc.ExpectedErrorSubstring = "invalid page size parameter"
+	tests.RunUnitTest(t, e, tc)
+
+	tc.URL = "/magma/v1/audit?page_token=foo"
+	tc.ExpectedErrorSubstring = "invalid page token"
+	tests.RunUnitTest(t, e, tc)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"magma/orc8r/cloud/go/services/audit/protos"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+)
+
+func (m *AuditEntry) FromProto(entry *protos.Entry) *AuditEntry {
+	timestamp, _ := ptypes.Timestamp(entry.Timestamp)
+	m.ID = entry.Id
+	m.Timestamp = strfmt.DateTime(timestamp)
+	m.Operator = entry.Operator
+	m.APITokenID = entry.ApiTokenId
+	m.Method = entry.Method
+	m.Path = entry.Path
+	m.NetworkID = entry.NetworkId
+	if entry.TenantId != nil {
+		tenantID := entry.TenantId.Value
+		m.TenantID = &tenantID
+	}
+	m.RequestDigest = entry.RequestDigest
+	m.RequestTruncated = entry.RequestTruncated
+	m.Status = entry.Status
+	m.RevisionBefore = entry.RevisionBefore
+	m.RevisionAfter = entry.RevisionAfter
+	for _, change := range entry.EntityChanges {
+		m.EntityChanges = append(m.EntityChanges, &AuditEntityChange{
+			Type:          change.Type,
+			Key:           change.Key,
+			VersionBefore: fromUInt64Value(change.VersionBefore),
+			VersionAfter:  fromUInt64Value(change.VersionAfter),
+		})
+	}
+	return m
+}
+
+func (m *PaginatedAuditEntries) FromProto(entries []*protos.Entry, nextPageToken string) *PaginatedAuditEntries {
+	m.Entries = make([]*AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		m.Entries = append(m.Entries, (&AuditEntry{}).FromProto(entry))
+	}
+	m.NextPageToken = nextPageToken
+	return m
+}
+
+func fromUInt64Value(v *wrappers.UInt64Value) *uint64 {
+	if v == nil {
+		return nil
+	}
+	ret := v.Value
+	return &ret
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicers
+
+import (
+	"strings"
+
+	"magma/orc8r/cloud/go/services/audit/protos"
+	"magma/orc8r/cloud/go/services/eventd"
+	eventd_protos "magma/orc8r/cloud/go/services/eventd/protos"
+	lib_protos "magma/orc8r/lib/go/protos"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// AuditStreamName is the eventd stream to which audit entries are exported
+const AuditStreamName = "audit"
+
+// Exporter exports recorded audit entries to an external sink.
+type Exporter interface {
+	Export(ctx context.Context, entry *protos.Entry) error
+}
+
+type eventdExporter struct{}
+
+// NewEventdExporter returns an exporter which writes audit entries as events
+// of the audit stream, so they can be queried alongside other eventd events.
+func NewEventdExporter() Exporter {
+	return &eventdExporter{}
+}
+
+func (e *eventdExporter) Export(ctx context.Context, entry *protos.Entry) error {
+	value, err := (&jsonpb.Marshaler{OrigName: true}).MarshalToString(entry)
+	if err != nil {
+		return errors.Wrap(err, "marshal audit entry")
+	}
+	// Entries are exported until they're acknowledged, so the event is
+	// keyed by the entry ID to store retried exports once
+	record := &eventd_protos.EventRecord{
+		NetworkId: entry.NetworkId,
+		Timestamp: entry.Timestamp,
+		EventId:   entry.Id,
+		Event: &lib_protos.Event{
+			StreamName: AuditStreamName,
+			EventType:  "rest_" + strings.ToLower(entry.Method),
+			Tag:        entry.Operator,
+			Value:      value,
+		},
+	}
+	err = eventd.PutEvents(ctx, []*eventd_protos.EventRecord{record})
+	return errors.Wrap(err, "put audit event")
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicers
+
+import (
+	"encoding/base64"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/audit/protos"
+	"magma/orc8r/cloud/go/services/audit/storage"
+	"magma/orc8r/cloud/go/services/tenants"
+	lib_protos "magma/orc8r/lib/go/protos"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/google/uuid"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+type auditServicer struct {
+	store     storage.AuditStorage
+	exporters []Exporter
+}
+
+// NewAuditServicer returns an audit servicer which stores entries in the
+// passed storage, then exports them to each of the passed exporters.
+func NewAuditServicer(store storage.AuditStorage, exporters ...Exporter) protos.AuditServer {
+	return &auditServicer{store: store, exporters: exporters}
+}
+
+func (a *auditServicer) RecordEntry(ctx context.Context, entry *protos.Entry) (*lib_protos.Void, error) {
+	if entry == nil {
+		return nil, status.Error(codes.InvalidArgument, "nil audit entry")
+	}
+	if entry.Method == "" || entry.Path == "" {
+		return nil, status.Error(codes.InvalidArgument, "audit entry must have a method and path")
+	}
+
+	entry.Id = uuid.New().String()
+	if entry.Timestamp == nil {
+		ts, err := ptypes.TimestampProto(clock.Now())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "convert timestamp: %s", err)
+		}
+		entry.Timestamp = ts
+	}
+	if entry.NetworkId != "" && entry.TenantId == nil {
+		entry.TenantId = getTenantID(ctx, entry.NetworkId)
+	}
+
+	err := a.store.PutEntry(entry)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "store audit entry: %s", err)
+	}
+
+	// Entries are durably recorded before export, so export failures are
+	// only logged
+	for _, exporter := range a.exporters {
+		err = exporter.Export(ctx, entry)
+		if err != nil {
+			glog.Errorf("Failed to export audit entry %s: %s", entry.Id, err)
+		}
+	}
+	return &lib_protos.Void{}, nil
+}
+
+func (a *auditServicer) ListEntries(ctx context.Context, req *protos.ListEntriesRequest) (*protos.ListEntriesResponse, error) {
+	filter := storage.EntryFilter{
+		Operator:  req.Operator,
+		NetworkID: req.NetworkId,
+	}
+	var err error
+	if req.Start != nil {
+		filter.Start, err = ptypes.Timestamp(req.Start)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid start time: %s", err)
+		}
+	}
+	if req.End != nil {
+		filter.End, err = ptypes.Timestamp(req.End)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid end time: %s", err)
+		}
+	}
+
+	pageSize := uint64(req.PageSize)
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	var cursor *storage.Cursor
+	if req.PageToken != "" {
+		token, err := deserializePageToken(req.PageToken)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %s", err)
+		}
+		cursor = &storage.Cursor{Timestamp: token.LastTimestamp, ID: token.LastId}
+	}
+
+	entries, err := a.store.ListEntries(filter, pageSize, cursor)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list audit entries: %s", err)
+	}
+
+	res := &protos.ListEntriesResponse{Entries: entries}
+	if uint64(len(entries)) == pageSize {
+		last := entries[len(entries)-1]
+		lastTime, err := ptypes.Timestamp(last.Timestamp)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "invalid timestamp of audit entry %s: %s", last.Id, err)
+		}
+		res.NextPageToken, err = serializePageToken(&protos.EntryPageToken{
+			LastTimestamp: lastTime.UnixNano() / int64(time.Millisecond),
+			LastId:        last.Id,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "serialize page token: %s", err)
+		}
+	}
+	return res, nil
+}
+
+// getTenantID returns the ID of the tenant owning the network, or nil if
+// none does or tenants can't be reached.
+func getTenantID(ctx context.Context, networkID string) *wrappers.Int64Value {
+	tenantList, err := tenants.GetAllTenants(ctx)
+	if err != nil {
+		glog.Errorf("Failed to get tenant of network %s: %s", networkID, err)
+		return nil
+	}
+	for _, t := range tenantList.GetTenants() {
+		for _, nid := range t.GetTenant().GetNetworks() {
+			if nid == networkID {
+				return &wrappers.Int64Value{Value: t.Id}
+			}
+		}
+	}
+	return nil
+}
+
+func serializePageToken(token *protos.EntryPageToken) (string, error) {
+	marshaledToken, err := proto.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(marshaledToken), nil
+}
+
+func deserializePageToken(encoded string) (*protos.EntryPageToken, error) {
+	marshaledToken, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	token := &protos.EntryPageToken{}
+	err = proto.Unmarshal(marshaledToken, token)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
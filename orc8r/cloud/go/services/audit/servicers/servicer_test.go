@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/audit/protos"
+	"magma/orc8r/cloud/go/services/audit/servicers"
+	"magma/orc8r/cloud/go/services/audit/storage"
+	"magma/orc8r/cloud/go/services/tenants"
+	tenants_test_init "magma/orc8r/cloud/go/services/tenants/test_init"
+	"magma/orc8r/cloud/go/sqorc"
+	lib_protos "magma/orc8r/lib/go/protos"
+
+	"github.com/golang/protobuf/ptypes"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockExporter struct {
+	exported []*protos.Entry
+}
+
+func (m *mockExporter) Export(ctx context.Context, entry *protos.Entry) error {
+	m.exported = append(m.exported, entry)
+	return nil
+}
+
+func TestAuditServicer(t *testing.T) {
+	tenants_test_init.StartTestService(t)
+	_, err := tenants.CreateTenant(context.Background(), 7, &lib_protos.Tenant{Name: "t7", Networks: []string{"n1"}})
+	require.NoError(t, err)
+
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	store := storage.NewSQLAuditStorage(db, sqorc.GetSqlBuilder())
+	require.NoError(t, store.Initialize())
+	exporter := &mockExporter{}
+	srv := servicers.NewAuditServicer(store, exporter)
+	ctx := context.Background()
+
+	// Invalid entries
+	_, err = srv.RecordEntry(ctx, &protos.Entry{Operator: "bob"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Record, assigning ID, timestamp, and tenant
+	_, err = srv.RecordEntry(ctx, &protos.Entry{Operator: "bob", Method: "POST", Path: "/magma/v1/networks/n1/gateways", NetworkId: "n1", Status: 201})
+	assert.NoError(t, err)
+	clock.SetAndFreezeClock(t, time.Unix(1001, 0))
+	_, err = srv.RecordEntry(ctx, &protos.Entry{Operator: "alice", Method: "DELETE", Path: "/magma/v1/networks/n2", NetworkId: "n2", Status: 204})
+	assert.NoError(t, err)
+	clock.SetAndFreezeClock(t, time.Unix(1002, 0))
+	_, err = srv.RecordEntry(ctx, &protos.Entry{Operator: "bob", Method: "PUT", Path: "/magma/v1/networks/n1", NetworkId: "n1", Status: 403})
+	assert.NoError(t, err)
+
+	require.Len(t, exporter.exported, 3)
+	first := exporter.exported[0]
+	assert.NotEmpty(t, first.Id)
+	assert.Equal(t, int64(1000), first.Timestamp.Seconds)
+	assert.Equal(t, int64(7), first.TenantId.GetValue())
+	assert.Nil(t, exporter.exported[1].TenantId)
+
+	// List all
+	res, err := srv.ListEntries(ctx, &protos.ListEntriesRequest{})
+	assert.NoError(t, err)
+	assert.Empty(t, res.NextPageToken)
+	require.Len(t, res.Entries, 3)
+	assert.Equal(t, "PUT", res.Entries[0].Method)
+	assert.Equal(t, first.String(), res.Entries[2].String())
+
+	// Paginated
+	res, err = srv.ListEntries(ctx, &protos.ListEntriesRequest{PageSize: 2})
+	assert.NoError(t, err)
+	require.Len(t, res.Entries, 2)
+	assert.NotEmpty(t, res.NextPageToken)
+	res, err = srv.ListEntries(ctx, &protos.ListEntriesRequest{PageSize: 2, PageToken: res.NextPageToken})
+	assert.NoError(t, err)
+	require.Len(t, res.Entries, 1)
+	assert.Equal(t, first.Id, res.Entries[0].Id)
+
+	// Filtered
+	start, err := ptypes.TimestampProto(time.Unix(1001, 0))
+	require.NoError(t, err)
+	res, err = srv.ListEntries(ctx, &protos.ListEntriesRequest{Operator: "bob", Start: start})
+	assert.NoError(t, err)
+	require.Len(t, res.Entries, 1)
+	assert.Equal(t, "PUT", res.Entries[0].Method)
+	res, err = srv.ListEntries(ctx, &protos.ListEntriesRequest{NetworkId: "n2"})
+	assert.NoError(t, err)
+	require.Len(t, res.Entries, 1)
+	assert.Equal(t, "alice", res.Entries[0].Operator)
+
+	// Invalid page token
+	_, err = srv.ListEntries(ctx, &protos.ListEntriesRequest{PageToken: "not base64!"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
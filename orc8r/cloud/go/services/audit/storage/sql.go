@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"database/sql"
+	"time"
+
+	"magma/orc8r/cloud/go/services/audit"
+	"magma/orc8r/cloud/go/services/audit/protos"
+	"magma/orc8r/cloud/go/sqorc"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+)
+
+const (
+	idCol        = "id"
+	timestampCol = "timestamp"
+	operatorCol  = "operator"
+	networkIDCol = "network_id"
+	entryCol     = "entry"
+)
+
+// sqlAuditStorage stores audit entries in a SQL table.
+//
+// Table columns:
+//   - id			-- unique ID of the entry
+//   - timestamp		-- Unix milliseconds of the entry's request
+//   - operator		-- ID of the requesting operator
+//   - network_id	-- network of the request, or empty
+//   - entry			-- marshaled Entry proto
+//
+// Filtered columns are duplicated out of the entry so listing doesn't need to
+// unmarshal non-matching entries.
+type sqlAuditStorage struct {
+	db      *sql.DB
+	builder sqorc.StatementBuilder
+}
+
+// NewSQLAuditStorage returns a SQL-backed implementation of AuditStorage.
+func NewSQLAuditStorage(db *sql.DB, builder sqorc.StatementBuilder) AuditStorage {
+	return &sqlAuditStorage{db: db, builder: builder}
+}
+
+func (s *sqlAuditStorage) Initialize() error {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		_, err := s.builder.CreateTable(audit.DBTableName).
+			IfNotExists().
+			Column(idCol).Type(sqorc.ColumnTypeText).NotNull().PrimaryKey().EndColumn().
+			Column(timestampCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			Column(operatorCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(networkIDCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(entryCol).Type(sqorc.ColumnTypeBytes).NotNull().EndColumn().
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize audit log table")
+		}
+
+		_, err = s.builder.CreateIndex(audit.DBTableName+"_timestamp_idx").
+			IfNotExists().
+			On(audit.DBTableName).
+			Columns(timestampCol, idCol).
+			RunWith(tx).
+			Exec()
+		return nil, errors.Wrap(err, "initialize audit log index")
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+func (s *sqlAuditStorage) PutEntry(entry *protos.Entry) error {
+	if entry == nil || entry.Id == "" {
+		return errors.New("audit entry must have an ID")
+	}
+	timestamp, err := ptypes.Timestamp(entry.Timestamp)
+	if err != nil {
+		return errors.Wrap(err, "invalid audit entry timestamp")
+	}
+	marshaledEntry, err := proto.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "marshal audit entry")
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		_, err := s.builder.Insert(audit.DBTableName).
+			Columns(idCol, timestampCol, operatorCol, networkIDCol, entryCol).
+			Values(entry.Id, toMillis(timestamp), entry.Operator, entry.NetworkId, marshaledEntry).
+			RunWith(tx).
+			Exec()
+		return nil, errors.Wrapf(err, "insert audit entry %s", entry.Id)
+	}
+	_, err = sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+func (s *sqlAuditStorage) ListEntries(filter EntryFilter, limit uint64, after *Cursor) ([]*protos.Entry, error) {
+	where := squirrel.And{}
+	if filter.Operator != "" {
+		where = append(where, squirrel.Eq{operatorCol: filter.Operator})
+	}
+	if filter.NetworkID != "" {
+		where = append(where, squirrel.Eq{networkIDCol: filter.NetworkID})
+	}
+	if !filter.Start.IsZero() {
+		where = append(where, squirrel.GtOrEq{timestampCol: toMillis(filter.Start)})
+	}
+	if !filter.End.IsZero() {
+		where = append(where, squirrel.Lt{timestampCol: toMillis(filter.End)})
+	}
+	if after != nil {
+		where = append(where, squirrel.Or{
+			squirrel.Lt{timestampCol: after.Timestamp},
+			squirrel.And{squirrel.Eq{timestampCol: after.Timestamp}, squirrel.Lt{idCol: after.ID}},
+		})
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		rows, err := s.builder.Select(entryCol).
+			From(audit.DBTableName).
+			Where(where).
+			OrderBy(timestampCol+" DESC", idCol+" DESC").
+			Limit(limit).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "select audit entries")
+		}
+		defer sqorc.CloseRowsLogOnError(rows, "ListEntries")
+
+		entries := []*protos.Entry{}
+		for rows.Next() {
+			var marshaledEntry []byte
+			err = rows.Scan(&marshaledEntry)
+			if err != nil {
+				return nil, errors.Wrap(err, "scan audit entry row")
+			}
+			entry := &protos.Entry{}
+			err = proto.Unmarshal(marshaledEntry, entry)
+			if err != nil {
+				return nil, errors.Wrap(err, "unmarshal audit entry")
+			}
+			entries = append(entries, entry)
+		}
+		err = rows.Err()
+		if err != nil {
+			return nil, errors.Wrap(err, "sql rows err")
+		}
+		return entries, nil
+	}
+
+	txRet, err := sqorc.ExecInTx(s.db, &sql.TxOptions{ReadOnly: true}, nil, txFn)
+	if err != nil {
+		return nil, err
+	}
+	return txRet.([]*protos.Entry), nil
+}
+
+func (s *sqlAuditStorage) DeleteEntriesBefore(t time.Time) (int64, error) {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		res, err := s.builder.Delete(audit.DBTableName).
+			Where(squirrel.Lt{timestampCol: toMillis(t)}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "delete expired audit entries")
+		}
+		n, err := res.RowsAffected()
+		return n, errors.Wrap(err, "get number of deleted audit entries")
+	}
+	txRet, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	if err != nil {
+		return 0, err
+	}
+	return txRet.(int64), nil
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
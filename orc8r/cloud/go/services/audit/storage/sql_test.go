@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/services/audit/protos"
+	"magma/orc8r/cloud/go/services/audit/storage"
+	"magma/orc8r/cloud/go/services/audit/test_utils"
+	"magma/orc8r/cloud/go/sqorc"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLAuditStorage(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	store := storage.NewSQLAuditStorage(db, sqorc.GetSqlBuilder())
+	require.NoError(t, store.Initialize())
+
+	// Empty
+	entries, err := store.ListEntries(storage.EntryFilter{}, 10, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	e0 := test_utils.NewEntry(t, "e0", 1000, "bob", "n1")
+	e1 := test_utils.NewEntry(t, "e1", 2000, "alice", "n1")
+	e2 := test_utils.NewEntry(t, "e2", 2000, "bob", "n2")
+	e3 := test_utils.NewEntry(t, "e3", 3000, "bob", "")
+	for _, e := range []*protos.Entry{e0, e1, e2, e3} {
+		require.NoError(t, store.PutEntry(e))
+	}
+
+	// Duplicate ID
+	assert.Error(t, store.PutEntry(test_utils.NewEntry(t, "e0", 4000, "bob", "")))
+	assert.Error(t, store.PutEntry(&protos.Entry{}))
+
+	// Newest first, ties broken by ID
+	entries, err = store.ListEntries(storage.EntryFilter{}, 10, nil)
+	assert.NoError(t, err)
+	assertEntries(t, []*protos.Entry{e3, e2, e1, e0}, entries)
+
+	// Paginated
+	entries, err = store.ListEntries(storage.EntryFilter{}, 2, nil)
+	assert.NoError(t, err)
+	assertEntries(t, []*protos.Entry{e3, e2}, entries)
+	entries, err = store.ListEntries(storage.EntryFilter{}, 2, &storage.Cursor{Timestamp: 2000, ID: "e2"})
+	assert.NoError(t, err)
+	assertEntries(t, []*protos.Entry{e1, e0}, entries)
+
+	// Filtered
+	entries, err = store.ListEntries(storage.EntryFilter{Operator: "bob"}, 10, nil)
+	assert.NoError(t, err)
+	assertEntries(t, []*protos.Entry{e3, e2, e0}, entries)
+	entries, err = store.ListEntries(storage.EntryFilter{Operator: "bob", NetworkID: "n1"}, 10, nil)
+	assert.NoError(t, err)
+	assertEntries(t, []*protos.Entry{e0}, entries)
+	entries, err = store.ListEntries(storage.EntryFilter{Start: time.Unix(2, 0), End: time.Unix(3, 0)}, 10, nil)
+	assert.NoError(t, err)
+	assertEntries(t, []*protos.Entry{e2, e1}, entries)
+
+	// Retention
+	n, err := store.DeleteEntriesBefore(time.Unix(2, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	entries, err = store.ListEntries(storage.EntryFilter{}, 10, nil)
+	assert.NoError(t, err)
+	assertEntries(t, []*protos.Entry{e3, e2, e1}, entries)
+}
+
+func assertEntries(t *testing.T, expected, actual []*protos.Entry) {
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		assert.Equal(t, expected[i].Id, actual[i].Id)
+		assert.Equal(t, expected[i].String(), actual[i].String())
+	}
+}
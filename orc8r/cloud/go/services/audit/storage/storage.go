@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"time"
+
+	"magma/orc8r/cloud/go/services/audit/protos"
+)
+
+// AuditStorage persists audit entries.
+type AuditStorage interface {
+	// Initialize the backing store.
+	Initialize() error
+
+	// PutEntry stores an audit entry. The entry's ID must be unique.
+	PutEntry(entry *protos.Entry) error
+
+	// ListEntries returns up to limit entries matching the filter, newest
+	// first, starting after the cursor if non-nil.
+	ListEntries(filter EntryFilter, limit uint64, after *Cursor) ([]*protos.Entry, error)
+
+	// DeleteEntriesBefore deletes entries older than the passed time,
+	// returning the number of entries deleted.
+	DeleteEntriesBefore(t time.Time) (int64, error)
+}
+
+// EntryFilter filters listed audit entries. Zero-valued fields match all
+// entries.
+type EntryFilter struct {
+	Operator  string
+	NetworkID string
+	// Start is inclusive
+	Start time.Time
+	// End is exclusive
+	End time.Time
+}
+
+// Cursor identifies the position of an entry in listing order.
+type Cursor struct {
+	// Timestamp of the entry, in Unix milliseconds
+	Timestamp int64
+	ID        string
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test_init
+
+import (
+	"testing"
+
+	"magma/orc8r/cloud/go/orc8r"
+	"magma/orc8r/cloud/go/services/audit"
+	"magma/orc8r/cloud/go/services/audit/protos"
+	"magma/orc8r/cloud/go/services/audit/servicers"
+	"magma/orc8r/cloud/go/services/audit/storage"
+	"magma/orc8r/cloud/go/sqorc"
+	"magma/orc8r/cloud/go/test_utils"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+// StartTestService instantiates an audit service backed by an in-memory
+// storage, returning the storage.
+func StartTestService(t *testing.T) storage.AuditStorage {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	store := storage.NewSQLAuditStorage(db, sqorc.GetSqlBuilder())
+	require.NoError(t, store.Initialize())
+
+	srv, lis := test_utils.NewTestService(t, orc8r.ModuleName, audit.ServiceName)
+	protos.RegisterAuditServer(srv.GrpcServer, servicers.NewAuditServicer(store))
+	go srv.RunTest(lis)
+	return store
+}
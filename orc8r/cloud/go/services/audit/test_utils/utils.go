@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test_utils
+
+import (
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/services/audit/protos"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+)
+
+// NewEntry returns an audit entry of a successful network creation, at the
+// passed Unix milliseconds.
+func NewEntry(t *testing.T, id string, millis int64, operator, networkID string) *protos.Entry {
+	ts, err := ptypes.TimestampProto(time.Unix(0, millis*int64(time.Millisecond)))
+	require.NoError(t, err)
+	return &protos.Entry{
+		Id:        id,
+		Timestamp: ts,
+		Operator:  operator,
+		NetworkId: networkID,
+		Method:    "POST",
+		Path:      "/magma/v1/networks",
+		Status:    201,
+	}
+}
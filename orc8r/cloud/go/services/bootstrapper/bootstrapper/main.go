@@ -14,17 +14,29 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/rsa"
+	"crypto/x509"
 	"flag"
+	"io/ioutil"
 
+	"magma/orc8r/cloud/go/blobstore"
+	"magma/orc8r/cloud/go/leader"
 	"magma/orc8r/cloud/go/orc8r"
 	"magma/orc8r/cloud/go/service"
 	"magma/orc8r/cloud/go/services/bootstrapper"
+	bootstrapper_protos "magma/orc8r/cloud/go/services/bootstrapper/protos"
+	"magma/orc8r/cloud/go/services/bootstrapper/rotation"
 	"magma/orc8r/cloud/go/services/bootstrapper/servicers"
+	"magma/orc8r/cloud/go/services/magmad"
+	"magma/orc8r/cloud/go/sqorc"
+	"magma/orc8r/cloud/go/storage"
 	"magma/orc8r/lib/go/protos"
 	"magma/orc8r/lib/go/security/key"
+	"magma/orc8r/lib/go/service/config"
 
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 )
 
 var (
@@ -46,14 +58,73 @@ func main() {
 		glog.Fatalf("Error coercing bootstrapper private key to RSA private key; actual type: %T", key)
 	}
 
+	var serviceConfig bootstrapper.Config
+	_, _, err = config.GetStructuredServiceConfig(orc8r.ModuleName, bootstrapper.ServiceName, &serviceConfig)
+	if err != nil {
+		glog.Fatalf("Error parsing bootstrapper config: %+v", err)
+	}
+
 	servicer, err := servicers.NewBootstrapperServer(rsaPrivateKey)
 	if err != nil {
 		glog.Fatalf("Error creating bootstrapper servicer: %+v", err)
 	}
+	if len(serviceConfig.TPM.EndorsementRoots) != 0 {
+		servicer.EndorsementRoots, err = loadCertPool(serviceConfig.TPM.EndorsementRoots)
+		if err != nil {
+			glog.Fatalf("Error loading TPM endorsement roots: %+v", err)
+		}
+	}
+	if serviceConfig.TPM.CredentialKeyFile != "" {
+		credentialKey, err := ioutil.ReadFile(serviceConfig.TPM.CredentialKeyFile)
+		if err != nil {
+			glog.Fatalf("Error reading TPM credential key: %+v", err)
+		}
+		err = servicer.SetCredentialKey(credentialKey)
+		if err != nil {
+			glog.Fatalf("Error setting TPM credential key: %+v", err)
+		}
+	}
 	protos.RegisterBootstrapperServer(srv.GrpcServer, servicer)
 
+	db, err := sqorc.Open(storage.GetSQLDriver(), storage.GetDatabaseSource())
+	if err != nil {
+		glog.Fatalf("Error connecting to database: %+v", err)
+	}
+	fact := blobstore.NewEntStorage(rotation.BootstrapperTableBlobstore, db, sqorc.GetSqlBuilder())
+	err = fact.InitializeFactory()
+	if err != nil {
+		glog.Fatalf("Error initializing bootstrapper database: %+v", err)
+	}
+	rotationStore := rotation.NewBlobstoreStore(fact)
+	bootstrapper_protos.RegisterCertRotationServer(srv.GrpcServer, servicers.NewCertRotationServicer(rotationStore))
+
+	elector := leader.NewSQLElector(db, sqorc.GetSqlBuilder(), rotation.LeaderElectionName, rotation.LeaseDuration(serviceConfig.CertRotation))
+	err = elector.Initialize()
+	if err != nil {
+		glog.Fatalf("Error initializing cert rotation leader election: %+v", err)
+	}
+	rotator, err := rotation.NewRotator(serviceConfig.CertRotation, rotationStore, magmad.GatewayRebootstrap, elector)
+	if err != nil {
+		glog.Fatalf("Error creating cert rotator: %+v", err)
+	}
+	go rotator.Run(context.Background())
+
 	err = srv.Run()
 	if err != nil {
 		glog.Fatalf("Error running service: %+v", err)
 	}
 }
+
+func loadCertPool(pemFiles []string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, file := range pemFiles {
+		pemBytes, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(pemBytes) {
+			return nil, errors.Errorf("no certificates found in %s", file)
+		}
+	}
+	return pool, nil
+}
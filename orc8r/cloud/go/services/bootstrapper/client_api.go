@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"context"
+
+	"magma/orc8r/cloud/go/services/bootstrapper/protos"
+	merrors "magma/orc8r/lib/go/errors"
+	"magma/orc8r/lib/go/registry"
+)
+
+func getCertRotationClient() (protos.CertRotationClient, error) {
+	conn, err := registry.GetConnection(ServiceName)
+	if err != nil {
+		return nil, merrors.NewInitError(err, ServiceName)
+	}
+	return protos.NewCertRotationClient(conn), nil
+}
+
+// GetCertRotationStatuses returns the certificate rotation statuses of the
+// passed gateways, keyed by hardware ID.
+// Gateways whose rotation isn't yet tracked are omitted.
+func GetCertRotationStatuses(ctx context.Context, networkID string, hwIDs []string) (map[string]*protos.CertRotationStatus, error) {
+	client, err := getCertRotationClient()
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.GetCertRotationStatuses(ctx, &protos.GetCertRotationStatusesRequest{NetworkId: networkID, HardwareIds: hwIDs})
+	if err != nil {
+		return nil, err
+	}
+	return res.Statuses, nil
+}
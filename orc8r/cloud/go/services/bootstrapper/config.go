@@ -0,0 +1,35 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"magma/orc8r/cloud/go/services/bootstrapper/rotation"
+)
+
+// Config represents the configuration provided to the bootstrapper service
+type Config struct {
+	CertRotation rotation.Config `yaml:"certRotation"`
+	TPM          TPMConfig       `yaml:"tpm"`
+}
+
+// TPMConfig configures TPM 2.0 attestation of gateways.
+type TPMConfig struct {
+	// EndorsementRoots are paths to PEM files of the TPM manufacturer CAs
+	// trusted to certify gateway endorsement keys.
+	EndorsementRoots []string `yaml:"endorsementRoots"`
+	// CredentialKeyFile is the path to a file holding the dedicated secret
+	// from which the secrets of TPM credentials are derived. If unset, a
+	// key is derived from the bootstrapper's private key.
+	CredentialKeyFile string `yaml:"credentialKeyFile"`
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotation
+
+import (
+	"time"
+
+	"magma/orc8r/cloud/go/services/bootstrapper/protos"
+	"magma/orc8r/lib/go/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	certExpiresInHours = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gateway_cert_expires_in_hours",
+			Help: "Hours until the gateway's certificate expires",
+		},
+		[]string{metrics.NetworkLabelName, metrics.GatewayLabelName},
+	)
+	certRotationFailed = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gateway_cert_rotation_failed",
+			Help: "1 if the gateway failed to rotate its certificate, 0 otherwise",
+		},
+		[]string{metrics.NetworkLabelName, metrics.GatewayLabelName},
+	)
+)
+
+func reportStatus(networkID, gatewayID string, status *protos.CertRotationStatus, now time.Time) {
+	expiresIn := fromMs(status.CertExpirationTime).Sub(now)
+	certExpiresInHours.WithLabelValues(networkID, gatewayID).Set(expiresIn.Hours())
+
+	failed := 0.0
+	if status.Status == protos.CertRotationStatus_FAILED {
+		failed = 1
+	}
+	certRotationFailed.WithLabelValues(networkID, gatewayID).Set(failed)
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rotation proactively rotates gateway certificates before they
+// expire.
+//
+// Gateways report their certificate's expiration time in their gateway
+// status. Once a gateway's certificate enters the rotation window, the
+// gateway is instructed to rebootstrap, via its magmad service, until it
+// reports a new certificate. Gateways which fail to rotate are reported via
+// metrics, and the rotation status of each gateway is persisted for the REST
+// API.
+package rotation
+
+import (
+	"context"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/leader"
+	"magma/orc8r/cloud/go/orc8r"
+	"magma/orc8r/cloud/go/serdes"
+	"magma/orc8r/cloud/go/services/bootstrapper/protos"
+	"magma/orc8r/cloud/go/services/configurator"
+	"magma/orc8r/cloud/go/services/state"
+	state_types "magma/orc8r/cloud/go/services/state/types"
+
+	"github.com/go-openapi/swag"
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LeaderElectionName is the name of the leader election among
+	// bootstrapper replicas for running the rotator.
+	LeaderElectionName = "bootstrapper_cert_rotation"
+
+	// DefaultWindow is the default rotation window.
+	DefaultWindow = 36 * time.Hour
+	// DefaultCheckInterval is the default interval between certificate
+	// checks.
+	DefaultCheckInterval = 10 * time.Minute
+	// DefaultRetryInterval is the default minimum time between instructions
+	// to a gateway.
+	DefaultRetryInterval = time.Hour
+	// DefaultMaxAttempts is the default number of instructions after which
+	// rotation fails.
+	DefaultMaxAttempts = 3
+)
+
+// Config configures gateway certificate rotation.
+// Unset fields take their default values.
+type Config struct {
+	// Enabled controls whether gateways are instructed to rotate their
+	// certificates. Rotation statuses are tracked regardless.
+	Enabled bool `yaml:"enabled"`
+	// Window is how long before its expiration a certificate is rotated.
+	Window time.Duration `yaml:"window"`
+	// CheckInterval is how often gateway certificates are checked.
+	CheckInterval time.Duration `yaml:"checkInterval"`
+	// RetryInterval is the minimum time between instructions to a gateway.
+	RetryInterval time.Duration `yaml:"retryInterval"`
+	// MaxAttempts is the number of instructions after which a gateway which
+	// hasn't rotated its certificate is considered to have failed.
+	MaxAttempts uint32 `yaml:"maxAttempts"`
+}
+
+// withDefaults returns the config with unset fields set to their defaults.
+// Returns an error for negative durations.
+func (c Config) withDefaults() (Config, error) {
+	if c.Window < 0 || c.CheckInterval < 0 || c.RetryInterval < 0 {
+		return Config{}, errors.Errorf("cert rotation durations must be positive: window %s, check interval %s, retry interval %s", c.Window, c.CheckInterval, c.RetryInterval)
+	}
+	if c.Window == 0 {
+		c.Window = DefaultWindow
+	}
+	if c.CheckInterval == 0 {
+		c.CheckInterval = DefaultCheckInterval
+	}
+	if c.RetryInterval == 0 {
+		c.RetryInterval = DefaultRetryInterval
+	}
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = DefaultMaxAttempts
+	}
+	return c, nil
+}
+
+// RebootstrapFunc instructs a gateway to rebootstrap.
+type RebootstrapFunc func(networkID, gatewayID string) error
+
+// Rotator tracks gateway certificate expiry, instructing gateways to rotate
+// their certificates within the configured window.
+type Rotator struct {
+	config      Config
+	store       Store
+	rebootstrap RebootstrapFunc
+	elector     leader.Elector
+}
+
+// NewRotator returns a rotator which instructs gateways via the passed
+// rebootstrap function. Only the replica elected by the passed elector
+// checks certificates, so each gateway is instructed once. A nil elector
+// checks certificates on every replica.
+// Returns an error for invalid configs.
+func NewRotator(config Config, store Store, rebootstrap RebootstrapFunc, elector leader.Elector) (*Rotator, error) {
+	config, err := config.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	return &Rotator{config: config, store: store, rebootstrap: rebootstrap, elector: elector}, nil
+}
+
+// LeaseDuration returns the leader lease duration for the passed config,
+// outlasting the interval at which the leader renews its lease.
+func LeaseDuration(config Config) time.Duration {
+	config, err := config.withDefaults()
+	if err != nil {
+		return DefaultCheckInterval * 2
+	}
+	return config.CheckInterval * 2
+}
+
+// Run periodically checks and rotates gateway certificates, while this
+// replica is the leader.
+// Returns only upon context cancellation.
+func (r *Rotator) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			glog.Warning("Cert rotation job canceled")
+			return
+		case <-time.After(r.config.CheckInterval):
+		}
+
+		if !r.isLeader() {
+			continue
+		}
+		err := r.RotateCerts(ctx)
+		if err != nil {
+			glog.Errorf("Error rotating gateway certificates: %v", err)
+		}
+	}
+}
+
+func (r *Rotator) isLeader() bool {
+	if r.elector == nil {
+		return true
+	}
+	isLeader, err := r.elector.IsLeader()
+	if err != nil {
+		glog.Errorf("Error electing cert rotation leader: %v", err)
+		return false
+	}
+	return isLeader
+}
+
+// RotateCerts checks the certificates of all gateways, instructing those due
+// for rotation to rebootstrap.
+func (r *Rotator) RotateCerts(ctx context.Context) error {
+	networks, err := configurator.ListNetworkIDs()
+	if err != nil {
+		return errors.Wrap(err, "list network IDs")
+	}
+	for _, networkID := range networks {
+		err = r.rotateNetworkCerts(ctx, networkID)
+		if err != nil {
+			glog.Errorf("Error rotating gateway certificates for network %s: %v", networkID, err)
+		}
+	}
+	return nil
+}
+
+func (r *Rotator) rotateNetworkCerts(ctx context.Context, networkID string) error {
+	gateways, _, err := configurator.LoadEntities(
+		networkID,
+		swag.String(orc8r.MagmadGatewayType),
+		nil,
+		nil,
+		nil,
+		configurator.EntityLoadCriteria{},
+		serdes.Entity,
+	)
+	if err != nil {
+		return errors.Wrap(err, "load gateways")
+	}
+
+	var hwIDs []string
+	for _, gw := range gateways {
+		if gw.PhysicalID != "" {
+			hwIDs = append(hwIDs, gw.PhysicalID)
+		}
+	}
+	if len(hwIDs) == 0 {
+		return nil
+	}
+	states, err := state.GetStates(ctx, networkID, state_types.MakeIDs(orc8r.GatewayStateType, hwIDs...), serdes.State)
+	if err != nil {
+		return errors.Wrap(err, "get gateway states")
+	}
+	statuses, err := r.store.GetStatuses(networkID, hwIDs)
+	if err != nil {
+		return errors.Wrap(err, "get cert rotation statuses")
+	}
+
+	now := clock.Now()
+	updates := map[string]*protos.CertRotationStatus{}
+	for _, gw := range gateways {
+		st, ok := states[state_types.ID{Type: orc8r.GatewayStateType, DeviceID: gw.PhysicalID}]
+		if !ok || st.CertExpirationTime == 0 {
+			continue
+		}
+		status := r.updateStatus(networkID, gw.Key, statuses[gw.PhysicalID], st.CertExpirationTime, now)
+		updates[gw.PhysicalID] = status
+		reportStatus(networkID, gw.Key, status, now)
+	}
+	return r.store.PutStatuses(networkID, updates)
+}
+
+// updateStatus returns the updated rotation status of a gateway, instructing
+// the gateway to rebootstrap if its certificate is due for rotation.
+func (r *Rotator) updateStatus(
+	networkID, gatewayID string,
+	prev *protos.CertRotationStatus,
+	certExpirationTime int64,
+	now time.Time,
+) *protos.CertRotationStatus {
+	status := &protos.CertRotationStatus{}
+	if prev != nil {
+		status = proto.Clone(prev).(*protos.CertRotationStatus)
+	}
+
+	// A new certificate resets the rotation
+	if certExpirationTime != status.CertExpirationTime {
+		if status.CertExpirationTime != 0 {
+			status.LastRotationTime = toMs(now)
+		}
+		status.CertExpirationTime = certExpirationTime
+		status.Status = protos.CertRotationStatus_OK
+		status.Attempts = 0
+		status.LastAttemptTime = 0
+		status.LastError = ""
+	}
+
+	expiration := fromMs(certExpirationTime)
+	if expiration.Sub(now) > r.config.Window {
+		return status
+	}
+
+	sinceLastAttempt := now.Sub(fromMs(status.LastAttemptTime))
+	if r.config.Enabled && sinceLastAttempt >= r.config.RetryInterval {
+		glog.Infof("Instructing gateway %s in network %s to rotate its certificate, expiring at %s", gatewayID, networkID, expiration)
+		status.Attempts++
+		status.LastAttemptTime = toMs(now)
+		status.LastError = ""
+		err := r.rebootstrap(networkID, gatewayID)
+		if err != nil {
+			glog.Errorf("Error instructing gateway %s in network %s to rebootstrap: %v", gatewayID, networkID, err)
+			status.LastError = err.Error()
+		}
+	}
+
+	status.Status = protos.CertRotationStatus_ROTATING
+	if status.Attempts >= r.config.MaxAttempts || !now.Before(expiration) {
+		status.Status = protos.CertRotationStatus_FAILED
+	}
+	return status
+}
+
+func toMs(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func fromMs(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
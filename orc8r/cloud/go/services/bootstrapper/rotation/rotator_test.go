@@ -0,0 +1,161 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/blobstore"
+	"magma/orc8r/cloud/go/services/bootstrapper/protos"
+	"magma/orc8r/cloud/go/sqorc"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	nid  = "some_network"
+	gwID = "some_gateway"
+)
+
+func TestRotator_UpdateStatus(t *testing.T) {
+	var rebootstraps int
+	var rebootstrapErr error
+	rebootstrap := func(networkID, gatewayID string) error {
+		assert.Equal(t, nid, networkID)
+		assert.Equal(t, gwID, gatewayID)
+		rebootstraps++
+		return rebootstrapErr
+	}
+	r, err := NewRotator(
+		Config{Enabled: true, Window: 36 * time.Hour, RetryInterval: time.Hour, MaxAttempts: 2},
+		nil,
+		rebootstrap,
+		nil,
+	)
+	require.NoError(t, err)
+
+	now := time.Unix(1000000, 0)
+	expiration := toMs(now.Add(72 * time.Hour))
+
+	// Outside rotation window
+	status := r.updateStatus(nid, gwID, nil, expiration, now)
+	assert.Equal(t, &protos.CertRotationStatus{Status: protos.CertRotationStatus_OK, CertExpirationTime: expiration}, status)
+	assert.Equal(t, 0, rebootstraps)
+
+	// Inside rotation window, gateway instructed
+	now = now.Add(48 * time.Hour)
+	status = r.updateStatus(nid, gwID, status, expiration, now)
+	assert.Equal(t, protos.CertRotationStatus_ROTATING, status.Status)
+	assert.Equal(t, uint32(1), status.Attempts)
+	assert.Equal(t, toMs(now), status.LastAttemptTime)
+	assert.Equal(t, 1, rebootstraps)
+
+	// Within retry interval, gateway not instructed
+	status = r.updateStatus(nid, gwID, status, expiration, now.Add(time.Minute))
+	assert.Equal(t, protos.CertRotationStatus_ROTATING, status.Status)
+	assert.Equal(t, 1, rebootstraps)
+
+	// Failed instruction after retry interval exhausts attempts
+	rebootstrapErr = errors.New("gateway not connected")
+	now = now.Add(time.Hour)
+	status = r.updateStatus(nid, gwID, status, expiration, now)
+	assert.Equal(t, protos.CertRotationStatus_FAILED, status.Status)
+	assert.Equal(t, uint32(2), status.Attempts)
+	assert.Equal(t, "gateway not connected", status.LastError)
+	assert.Equal(t, 2, rebootstraps)
+
+	// New certificate resets rotation
+	newExpiration := toMs(now.Add(10 * 24 * time.Hour))
+	status = r.updateStatus(nid, gwID, status, newExpiration, now.Add(time.Minute))
+	assert.Equal(t, &protos.CertRotationStatus{
+		Status:             protos.CertRotationStatus_OK,
+		CertExpirationTime: newExpiration,
+		LastRotationTime:   toMs(now.Add(time.Minute)),
+	}, status)
+	assert.Equal(t, 2, rebootstraps)
+}
+
+func TestRotator_UpdateStatus_Disabled(t *testing.T) {
+	r, err := NewRotator(
+		Config{Enabled: false, Window: 36 * time.Hour, RetryInterval: time.Hour, MaxAttempts: 2},
+		nil,
+		func(networkID, gatewayID string) error {
+			t.Fatal("gateway instructed while rotation disabled")
+			return nil
+		},
+		nil,
+	)
+	require.NoError(t, err)
+
+	now := time.Unix(1000000, 0)
+	expiration := toMs(now.Add(time.Hour))
+	status := r.updateStatus(nid, gwID, nil, expiration, now)
+	assert.Equal(t, protos.CertRotationStatus_ROTATING, status.Status)
+	assert.Zero(t, status.Attempts)
+
+	// Expired certificates fail regardless
+	status = r.updateStatus(nid, gwID, status, expiration, now.Add(2*time.Hour))
+	assert.Equal(t, protos.CertRotationStatus_FAILED, status.Status)
+}
+
+func TestNewRotator_Config(t *testing.T) {
+	// Unset fields are defaulted
+	r, err := NewRotator(Config{Enabled: true, MaxAttempts: 5}, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, Config{
+		Enabled:       true,
+		Window:        DefaultWindow,
+		CheckInterval: DefaultCheckInterval,
+		RetryInterval: DefaultRetryInterval,
+		MaxAttempts:   5,
+	}, r.config)
+	assert.Equal(t, 2*DefaultCheckInterval, LeaseDuration(Config{}))
+
+	// Negative durations are rejected
+	_, err = NewRotator(Config{CheckInterval: -time.Second}, nil, nil, nil)
+	assert.Error(t, err)
+	_, err = NewRotator(Config{RetryInterval: -time.Second}, nil, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestBlobstoreStore(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	fact := blobstore.NewEntStorage(BootstrapperTableBlobstore, db, sqorc.GetSqlBuilder())
+	require.NoError(t, fact.InitializeFactory())
+	store := NewBlobstoreStore(fact)
+
+	statuses, err := store.GetStatuses(nid, []string{"hw1", "hw2"})
+	assert.NoError(t, err)
+	assert.Empty(t, statuses)
+
+	hw1 := &protos.CertRotationStatus{Status: protos.CertRotationStatus_ROTATING, CertExpirationTime: 42, Attempts: 1}
+	hw2 := &protos.CertRotationStatus{Status: protos.CertRotationStatus_OK, CertExpirationTime: 43}
+	err = store.PutStatuses(nid, map[string]*protos.CertRotationStatus{"hw1": hw1, "hw2": hw2})
+	assert.NoError(t, err)
+
+	statuses, err = store.GetStatuses(nid, []string{"hw1", "hw3"})
+	assert.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, hw1.String(), statuses["hw1"].String())
+
+	// Statuses are scoped by network
+	statuses, err = store.GetStatuses("other_network", []string{"hw1", "hw2"})
+	assert.NoError(t, err)
+	assert.Empty(t, statuses)
+}
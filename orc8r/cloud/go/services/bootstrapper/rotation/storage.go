@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotation
+
+import (
+	"magma/orc8r/cloud/go/blobstore"
+	"magma/orc8r/cloud/go/services/bootstrapper/protos"
+	"magma/orc8r/cloud/go/storage"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BootstrapperTableBlobstore is the service-wide blobstore table for
+	// bootstrapper data.
+	BootstrapperTableBlobstore = "bootstrapper_blobstore"
+
+	// CertRotationStatusType is the type of CertRotationStatus used in
+	// blobstore type fields.
+	CertRotationStatusType = "cert_rotation_status"
+)
+
+// Store persists the certificate rotation status of gateways, keyed by
+// network ID and hardware ID.
+type Store interface {
+	// GetStatuses returns the rotation statuses of the passed gateways, keyed
+	// by hardware ID. Gateways without a status are omitted.
+	GetStatuses(networkID string, hwIDs []string) (map[string]*protos.CertRotationStatus, error)
+
+	// PutStatuses creates or updates the rotation statuses of gateways,
+	// keyed by hardware ID.
+	PutStatuses(networkID string, statuses map[string]*protos.CertRotationStatus) error
+}
+
+type blobstoreStore struct {
+	factory blobstore.BlobStorageFactory
+}
+
+// NewBlobstoreStore returns a rotation status store backed by blobstore.
+func NewBlobstoreStore(factory blobstore.BlobStorageFactory) Store {
+	return &blobstoreStore{factory: factory}
+}
+
+func (b *blobstoreStore) GetStatuses(networkID string, hwIDs []string) (map[string]*protos.CertRotationStatus, error) {
+	ret := map[string]*protos.CertRotationStatus{}
+	if len(hwIDs) == 0 {
+		return ret, nil
+	}
+
+	store, err := b.factory.StartTransaction(&storage.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start transaction")
+	}
+	defer store.Rollback()
+
+	blobs, err := store.GetMany(networkID, storage.MakeTKs(CertRotationStatusType, hwIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cert rotation statuses")
+	}
+	for _, blob := range blobs {
+		status := &protos.CertRotationStatus{}
+		err = proto.Unmarshal(blob.Value, status)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal cert rotation status")
+		}
+		ret[blob.Key] = status
+	}
+
+	return ret, store.Commit()
+}
+
+func (b *blobstoreStore) PutStatuses(networkID string, statuses map[string]*protos.CertRotationStatus) error {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	store, err := b.factory.StartTransaction(nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer store.Rollback()
+
+	var blobs blobstore.Blobs
+	for hwID, status := range statuses {
+		marshaled, err := proto.Marshal(status)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal cert rotation status")
+		}
+		blobs = append(blobs, blobstore.Blob{Type: CertRotationStatusType, Key: hwID, Value: marshaled})
+	}
+	err = store.CreateOrUpdate(networkID, blobs)
+	if err != nil {
+		return errors.Wrap(err, "failed to put cert rotation statuses")
+	}
+
+	return store.Commit()
+}
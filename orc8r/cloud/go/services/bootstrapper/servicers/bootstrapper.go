@@ -23,6 +23,7 @@ import (
 	"crypto/x509"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"log"
 	"math/big"
 	"time"
@@ -38,6 +39,7 @@ import (
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/hkdf"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -49,9 +51,21 @@ const ChallengeLength = 512
 const TimeLength = 8 // length of time encoded in byte array from int64
 const MinKeyLength = 1024
 const GatewayCertificateDuration = time.Hour * 97 // 4 days, lifetime of GW Certificate
+const MinCredentialKeyLength = 32                 // min length of a dedicated TPM credential key
+
+// credentialKeyLabel domain-separates the TPM credential key derived from
+// the bootstrapper's private key, when no dedicated key is configured.
+const credentialKeyLabel = "magma bootstrapper tpm credential key"
 
 type BootstrapperServer struct {
 	privKey *rsa.PrivateKey
+	// credentialKey derives the secrets of TPM credentials from challenges
+	credentialKey []byte
+
+	// EndorsementRoots are the TPM manufacturer CAs trusted to certify the
+	// endorsement keys of gateways with TPM2_ATTESTATION challenge keys.
+	// If nil, such gateways cannot bootstrap.
+	EndorsementRoots *x509.CertPool
 }
 
 func NewBootstrapperServer(privKey *rsa.PrivateKey) (*BootstrapperServer, error) {
@@ -60,23 +74,50 @@ func NewBootstrapperServer(privKey *rsa.PrivateKey) (*BootstrapperServer, error)
 		return nil, errorLogger(errors.Errorf("private key is too short: actual len (%d) is less than minimum len (%d)", privKey.N.BitLen(), MinKeyLength))
 	}
 	srv.privKey = privKey
+	credentialKey, err := deriveCredentialKey(privKey)
+	if err != nil {
+		return nil, errorLogger(errors.Wrap(err, "derive TPM credential key"))
+	}
+	srv.credentialKey = credentialKey
 	return srv, nil
 }
 
+// SetCredentialKey sets a dedicated secret from which the secrets of TPM
+// credentials are derived, in place of the key derived from the private key.
+func (srv *BootstrapperServer) SetCredentialKey(key []byte) error {
+	if len(key) < MinCredentialKeyLength {
+		return errors.Errorf("credential key is too short: actual len (%d) is less than minimum len (%d)", len(key), MinCredentialKeyLength)
+	}
+	srv.credentialKey = append([]byte{}, key...)
+	return nil
+}
+
+// deriveCredentialKey derives a TPM credential key from the private key via
+// HKDF, so the signing key isn't itself used as the credential key.
+func deriveCredentialKey(privKey *rsa.PrivateKey) ([]byte, error) {
+	kdf := hkdf.New(sha256.New, x509.MarshalPKCS1PrivateKey(privKey), nil, []byte(credentialKeyLabel))
+	key := make([]byte, sha256.Size)
+	_, err := io.ReadFull(kdf, key)
+	return key, err
+}
+
 // generate challenge in the format of [randomText : timestamp : signature]
 func (srv *BootstrapperServer) GetChallenge(ctx context.Context, hwId *protos.AccessGatewayID) (*protos.Challenge, error) {
 	var keyType protos.ChallengeKey_KeyType
 
 	// case based on the env variable whether to use magmad or configurator
 	var err error
-	keyType, _, err = getChallengeKey(ctx, hwId.Id)
+	var key []byte
+	var endorsementCerts [][]byte
+	keyType, key, endorsementCerts, err = getChallengeKey(ctx, hwId.Id)
 	if err != nil {
 		return nil, err
 	}
 
 	if keyType != protos.ChallengeKey_ECHO &&
 		keyType != protos.ChallengeKey_SOFTWARE_RSA_SHA256 &&
-		keyType != protos.ChallengeKey_SOFTWARE_ECDSA_SHA256 {
+		keyType != protos.ChallengeKey_SOFTWARE_ECDSA_SHA256 &&
+		keyType != protos.ChallengeKey_TPM2_ATTESTATION {
 		return nil, errorLogger(status.Errorf(codes.Aborted, "Unsupported key type: %s", keyType))
 	}
 
@@ -99,13 +140,20 @@ func (srv *BootstrapperServer) GetChallenge(ctx context.Context, hwId *protos.Ac
 	}
 	challenge = append(challenge, signature...)
 
-	return &protos.Challenge{KeyType: keyType, Challenge: challenge}, nil
+	ret := &protos.Challenge{KeyType: keyType, Challenge: challenge}
+	if keyType == protos.ChallengeKey_TPM2_ATTESTATION {
+		ret.TpmCredential, err = srv.makeTPMCredential(key, endorsementCerts, challenge)
+		if err != nil {
+			return nil, errorLogger(status.Errorf(codes.Aborted, "Failed to make TPM credential: %s", err))
+		}
+	}
+	return ret, nil
 }
 
 // verify the response by client and return signed certificate if response is correct
 func (srv *BootstrapperServer) RequestSign(ctx context.Context, resp *protos.Response) (*protos.Certificate, error) {
 	hwId := resp.HwId.Id
-	keyType, key, err := getChallengeKey(ctx, hwId)
+	keyType, key, _, err := getChallengeKey(ctx, hwId)
 	if err != nil {
 		return nil, err
 	}
@@ -123,6 +171,8 @@ func (srv *BootstrapperServer) RequestSign(ctx context.Context, resp *protos.Res
 		err = verifySoftwareRSASHA256(resp, key)
 	case protos.ChallengeKey_SOFTWARE_ECDSA_SHA256:
 		err = verifySoftwareECDSASHA256(resp, key)
+	case protos.ChallengeKey_TPM2_ATTESTATION:
+		err = srv.verifyTPMQuote(resp, key)
 	default:
 		err = fmt.Errorf("Unsupported key type: %s", keyType)
 	}
@@ -258,30 +308,36 @@ func verifySoftwareECDSASHA256(resp *protos.Response, key []byte) error {
 	return nil
 }
 
-func getChallengeKey(ctx context.Context, hwID string) (protos.ChallengeKey_KeyType, []byte, error) {
+// getChallengeKey returns the type and key of the gateway's challenge key,
+// and for TPM2_ATTESTATION keys, the endorsement key certificate chain.
+func getChallengeKey(ctx context.Context, hwID string) (protos.ChallengeKey_KeyType, []byte, [][]byte, error) {
 	var empty protos.ChallengeKey_KeyType
 	entity, err := configurator.LoadEntityForPhysicalID(hwID, configurator.EntityLoadCriteria{}, serdes.Entity)
 	if err != nil {
-		return empty, nil, errorLogger(status.Errorf(codes.NotFound, "Gateway with hwid %s is not registered: %s", hwID, err))
+		return empty, nil, nil, errorLogger(status.Errorf(codes.NotFound, "Gateway with hwid %s is not registered: %s", hwID, err))
 	}
 	iRecord, err := device.GetDevice(strippedIncomingCtx(ctx), entity.NetworkID, orc8r.AccessGatewayRecordType, hwID, serdes.Device)
 	if err != nil {
-		return empty, nil, errorLogger(status.Errorf(codes.NotFound, "Failed to find gateway record: %s", err))
+		return empty, nil, nil, errorLogger(status.Errorf(codes.NotFound, "Failed to find gateway record: %s", err))
 	}
 	record, ok := iRecord.(*models.GatewayDevice)
 	if !ok {
-		return empty, nil, errorLogger(status.Errorf(codes.NotFound, "Failed to find gateway record"))
+		return empty, nil, nil, errorLogger(status.Errorf(codes.NotFound, "Failed to find gateway record"))
 	}
 
 	var key []byte
 	keyType, ok := protos.ChallengeKey_KeyType_value[record.Key.KeyType]
 	if !ok {
-		return empty, nil, errorLogger(status.Errorf(codes.Aborted, "Unsupported key type: %v", keyType))
+		return empty, nil, nil, errorLogger(status.Errorf(codes.Aborted, "Unsupported key type: %v", keyType))
 	}
 	if record.Key.Key != nil {
 		key = *record.Key.Key
 	}
-	return protos.ChallengeKey_KeyType(keyType), key, nil
+	var endorsementCerts [][]byte
+	for _, cert := range record.Key.EndorsementCerts {
+		endorsementCerts = append(endorsementCerts, cert)
+	}
+	return protos.ChallengeKey_KeyType(keyType), key, endorsementCerts, nil
 }
 
 // Bootstrapper needs to ignore incoming SN/CN headers in ctx when it fans
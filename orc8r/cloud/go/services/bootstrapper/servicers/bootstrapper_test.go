@@ -14,6 +14,7 @@ limitations under the License.
 package servicers_test
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/rand"
@@ -44,6 +45,7 @@ import (
 	"magma/orc8r/lib/go/protos"
 	"magma/orc8r/lib/go/security/csr"
 	"magma/orc8r/lib/go/security/key"
+	"magma/orc8r/lib/go/security/tpm"
 
 	"github.com/emakeev/snowflake"
 	"github.com/go-openapi/strfmt"
@@ -56,6 +58,7 @@ const (
 	echoType  = "ECHO"
 	rsaType   = "SOFTWARE_RSA_SHA256"
 	ecdsaType = "SOFTWARE_ECDSA_SHA256"
+	tpmType   = "TPM2_ATTESTATION"
 )
 
 func testWithECHO(
@@ -192,8 +195,105 @@ func testWithECDSA(
 	assert.NotNil(t, cert)
 }
 
-// Test with real GW bootstrapper
-func testWithGatewayBootstrapper(t *testing.T, networkId string) {
+func testWithTPM(
+	t *testing.T, networkId string, srv *servicers.BootstrapperServer, ctx context.Context) {
+
+	testAgHwId := "test_ag_tpm"
+	caCert, caKey, err := tpm.NewSimulatedManufacturerCA()
+	assert.NoError(t, err)
+	sim, err := tpm.NewSimulator(caCert, caKey)
+	assert.NoError(t, err)
+	registerTPMGateway(t, networkId, testAgHwId, sim)
+
+	// no trusted manufacturer roots
+	_, err = srv.GetChallenge(ctx, &protos.AccessGatewayID{Id: testAgHwId})
+	assert.Error(t, err)
+
+	// untrusted manufacturer root
+	otherCACert, _, err := tpm.NewSimulatedManufacturerCA()
+	assert.NoError(t, err)
+	srv.EndorsementRoots = x509.NewCertPool()
+	srv.EndorsementRoots.AddCert(otherCACert)
+	_, err = srv.GetChallenge(ctx, &protos.AccessGatewayID{Id: testAgHwId})
+	assert.Error(t, err)
+
+	srv.EndorsementRoots.AddCert(caCert)
+	challenge, err := srv.GetChallenge(ctx, &protos.AccessGatewayID{Id: testAgHwId})
+	assert.NoError(t, err)
+	assert.Equal(t, challenge.KeyType, protos.ChallengeKey_TPM2_ATTESTATION)
+	assert.NotNil(t, challenge.TpmCredential)
+
+	// quote qualified by the challenge and activated secret
+	secret, err := sim.ActivateCredential(challenge.TpmCredential.CredentialBlob, challenge.TpmCredential.EncryptedSecret)
+	assert.NoError(t, err)
+	qualifyingData := sha256.Sum256(append(append([]byte{}, challenge.Challenge...), secret...))
+	quote, signature, err := sim.Quote(qualifyingData[:])
+	assert.NoError(t, err)
+
+	c, err := csr.CreateCSR(time.Hour*24*10, "cn", "cn")
+	assert.NoError(t, err)
+	resp := protos.Response{
+		HwId:      &protos.AccessGatewayID{Id: testAgHwId},
+		Challenge: challenge.Challenge,
+		Response: &protos.Response_TpmResponse{
+			TpmResponse: &protos.Response_TPMQuote{Quote: quote, Signature: signature},
+		},
+		Csr: c,
+	}
+	cert, err := srv.RequestSign(ctx, &resp)
+	assert.NoError(t, err)
+	assert.NotNil(t, cert)
+
+	// quote not qualified by the activated secret
+	qualifyingData = sha256.Sum256(challenge.Challenge)
+	quote, signature, err = sim.Quote(qualifyingData[:])
+	assert.NoError(t, err)
+	resp.Response = &protos.Response_TpmResponse{
+		TpmResponse: &protos.Response_TPMQuote{Quote: quote, Signature: signature},
+	}
+	_, err = srv.RequestSign(ctx, &resp)
+	assert.Error(t, err)
+
+	// a different TPM, e.g. of a cloned gateway, can't activate the
+	// credential, nor sign as the attestation key
+	otherSim, err := tpm.NewSimulator(caCert, caKey)
+	assert.NoError(t, err)
+	_, err = otherSim.ActivateCredential(challenge.TpmCredential.CredentialBlob, challenge.TpmCredential.EncryptedSecret)
+	assert.Error(t, err)
+	qualifyingData = sha256.Sum256(append(append([]byte{}, challenge.Challenge...), secret...))
+	quote, signature, err = otherSim.Quote(qualifyingData[:])
+	assert.NoError(t, err)
+	resp.Response = &protos.Response_TpmResponse{
+		TpmResponse: &protos.Response_TPMQuote{Quote: quote, Signature: signature},
+	}
+	_, err = srv.RequestSign(ctx, &resp)
+	assert.Error(t, err)
+
+	srv.EndorsementRoots = nil
+}
+
+func registerTPMGateway(t *testing.T, networkId string, hwId string, sim *tpm.Simulator) {
+	akPublic, err := sim.AttestationKeyPublic()
+	assert.NoError(t, err)
+	encodedAKPublic := strfmt.Base64(akPublic)
+	configurator_test_utils.RegisterGateway(
+		t,
+		networkId,
+		hwId,
+		&models.GatewayDevice{
+			HardwareID: hwId,
+			Key: &models.ChallengeKey{
+				KeyType:          tpmType,
+				Key:              &encodedAKPublic,
+				EndorsementCerts: []strfmt.Base64{sim.EndorsementCertificate()},
+			},
+		},
+	)
+}
+
+// Test with real GW bootstrapper, responding to TPM2_ATTESTATION challenges
+// with the passed simulated TPM if non-nil
+func testWithGatewayBootstrapper(t *testing.T, networkId string, sim *tpm.Simulator, endorsementRoots *x509.CertPool) {
 	srv, lis := test_utils.NewTestService(t, orc8r.ModuleName, bootstrapper.ServiceName)
 	assert.Equal(t, protos.ServiceInfo_STARTING, srv.State)
 	assert.Equal(t, protos.ServiceInfo_APP_UNHEALTHY, srv.Health)
@@ -203,6 +303,7 @@ func testWithGatewayBootstrapper(t *testing.T, networkId string) {
 
 	bootstrapperSrv, err := servicers.NewBootstrapperServer(privateKey.(*rsa.PrivateKey))
 	assert.NoError(t, err)
+	bootstrapperSrv.EndorsementRoots = endorsementRoots
 	protos.RegisterBootstrapperServer(srv.GrpcServer, bootstrapperSrv)
 
 	go srv.RunTest(lis)
@@ -253,6 +354,9 @@ func testWithGatewayBootstrapper(t *testing.T, networkId string) {
 	snowflakePath := filepath.Join(tmpDir, "snowflake")
 
 	b := bootstrap_client.NewLocalBootstrapper(completeChan)
+	if sim != nil {
+		b.TPM = sim
+	}
 	err = b.Initialize(snowflakePath)
 	assert.NoError(t, err)
 
@@ -266,18 +370,22 @@ func testWithGatewayBootstrapper(t *testing.T, networkId string) {
 	assert.NoError(t, err)
 	encodedPubKey := strfmt.Base64(pubKey)
 
-	configurator_test_utils.RegisterGateway(
-		t,
-		networkId,
-		gwHwId,
-		&models.GatewayDevice{
-			HardwareID: gwHwId,
-			Key: &models.ChallengeKey{
-				KeyType: ecdsaType,
-				Key:     &encodedPubKey,
+	if sim != nil {
+		registerTPMGateway(t, networkId, gwHwId, sim)
+	} else {
+		configurator_test_utils.RegisterGateway(
+			t,
+			networkId,
+			gwHwId,
+			&models.GatewayDevice{
+				HardwareID: gwHwId,
+				Key: &models.ChallengeKey{
+					KeyType: ecdsaType,
+					Key:     &encodedPubKey,
+				},
 			},
-		},
-	)
+		)
+	}
 
 	err = b.PeriodicCheck(time.Now())
 	assert.NoError(t, err)
@@ -410,11 +518,19 @@ func TestBootstrapperServer(t *testing.T) {
 	_, err = servicers.NewBootstrapperServer(privateKey.(*rsa.PrivateKey))
 	assert.Error(t, err)
 
-	// create bootstrapper server
+	// dedicated TPM credential keys must be long enough
 	privateKey, err = key.GenerateKey("", 2048)
 	assert.NoError(t, err)
 	srv, err := servicers.NewBootstrapperServer(privateKey.(*rsa.PrivateKey))
 	assert.NoError(t, err)
+	assert.Error(t, srv.SetCredentialKey([]byte("short")))
+	assert.NoError(t, srv.SetCredentialKey(bytes.Repeat([]byte{0x42}, servicers.MinCredentialKeyLength)))
+
+	// create bootstrapper server
+	privateKey, err = key.GenerateKey("", 2048)
+	assert.NoError(t, err)
+	srv, err = servicers.NewBootstrapperServer(privateKey.(*rsa.PrivateKey))
+	assert.NoError(t, err)
 
 	// for signing csr
 	certifier_test_init.StartTestService(t)
@@ -432,5 +548,24 @@ func TestBootstrapperServer(t *testing.T) {
 		context.Background(),
 		metadata.Pairs("x-magma-client-cert-cn", "bla"))
 	testNegative(t, testNetworkID, srv, ctx)
-	testWithGatewayBootstrapper(t, testNetworkID)
+	testWithTPM(t, testNetworkID, srv, ctx)
+	testWithGatewayBootstrapper(t, testNetworkID, nil, nil)
+}
+
+func TestBootstrapperServer_GatewayTPM(t *testing.T) {
+	configurator_test_init.StartTestService(t)
+	device_test_init.StartTestService(t)
+	certifier_test_init.StartTestService(t)
+
+	testNetworkID := "bootstrapper_test_network"
+	err := configurator.CreateNetwork(configurator.Network{ID: testNetworkID, Name: "Test Network Name"}, serdes.Network)
+	assert.NoError(t, err)
+
+	caCert, caKey, err := tpm.NewSimulatedManufacturerCA()
+	assert.NoError(t, err)
+	sim, err := tpm.NewSimulator(caCert, caKey)
+	assert.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	testWithGatewayBootstrapper(t, testNetworkID, sim, roots)
 }
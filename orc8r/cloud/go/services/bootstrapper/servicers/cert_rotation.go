@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicers
+
+import (
+	"magma/orc8r/cloud/go/services/bootstrapper/protos"
+	"magma/orc8r/cloud/go/services/bootstrapper/rotation"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type certRotationServicer struct {
+	store rotation.Store
+}
+
+// NewCertRotationServicer returns a servicer exposing the certificate
+// rotation statuses of gateways.
+func NewCertRotationServicer(store rotation.Store) protos.CertRotationServer {
+	return &certRotationServicer{store: store}
+}
+
+func (c *certRotationServicer) GetCertRotationStatuses(ctx context.Context, req *protos.GetCertRotationStatusesRequest) (*protos.GetCertRotationStatusesResponse, error) {
+	if req.NetworkId == "" {
+		return nil, status.Error(codes.InvalidArgument, "network ID must be non-empty")
+	}
+	statuses, err := c.store.GetStatuses(req.NetworkId, req.HardwareIds)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get cert rotation statuses: %v", err)
+	}
+	return &protos.GetCertRotationStatusesResponse{Statuses: statuses}, nil
+}
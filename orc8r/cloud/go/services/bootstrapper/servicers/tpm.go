@@ -0,0 +1,155 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicers
+
+import (
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+
+	"magma/orc8r/lib/go/protos"
+	"magma/orc8r/lib/go/security/tpm"
+)
+
+// TPM 2.0 attestation proves a gateway holds the TPM it was registered with,
+// rather than a copy of a key file:
+//	1. The registered endorsement key (EK) certificate chain is verified up
+//	   to a trusted TPM manufacturer root.
+//	2. A secret, derived from the challenge, is protected by
+//	   TPM2_MakeCredential such that only the TPM holding both the EK and the
+//	   registered attestation key (AK) can recover it.
+//	3. The gateway responds with a quote by the AK, qualified by
+//	   SHA256(challenge || secret), proving it recovered the secret.
+
+// makeTPMCredential verifies the gateway's endorsement key, then protects
+// the challenge's secret for the gateway's TPM.
+func (srv *BootstrapperServer) makeTPMCredential(key []byte, endorsementCerts [][]byte, challenge []byte) (*protos.TPMCredential, error) {
+	ak, err := decodeAttestationKey(key)
+	if err != nil {
+		return nil, err
+	}
+	ek, err := srv.verifyEndorsementKey(endorsementCerts)
+	if err != nil {
+		return nil, err
+	}
+	akName, err := ak.Name()
+	if err != nil {
+		return nil, err
+	}
+	credentialBlob, encryptedSecret, err := tpm.MakeCredential(ek, akName, srv.tpmCredentialSecret(challenge))
+	if err != nil {
+		return nil, err
+	}
+	return &protos.TPMCredential{CredentialBlob: credentialBlob, EncryptedSecret: encryptedSecret}, nil
+}
+
+// verifyTPMQuote verifies the response is a quote by the gateway's
+// attestation key, qualified by the challenge and its credential's secret.
+func (srv *BootstrapperServer) verifyTPMQuote(resp *protos.Response, key []byte) error {
+	response := resp.GetTpmResponse()
+	if response == nil {
+		return fmt.Errorf("Wrong type of response, expected TPM quote")
+	}
+	ak, err := decodeAttestationKey(key)
+	if err != nil {
+		return err
+	}
+
+	sig, err := tpm.DecodeSignature(response.Signature)
+	if err != nil {
+		return err
+	}
+	err = sig.Verify(ak.Key, response.Quote)
+	if err != nil {
+		return fmt.Errorf("Failed to verify quote signature: %s", err)
+	}
+
+	attest, err := tpm.DecodeAttest(response.Quote)
+	if err != nil {
+		return err
+	}
+	if attest.Magic != tpm.GeneratedValue {
+		return fmt.Errorf("Quote was not generated by a TPM")
+	}
+	if !hmac.Equal(attest.ExtraData, tpmQualifyingData(resp.Challenge, srv.tpmCredentialSecret(resp.Challenge))) {
+		return fmt.Errorf("Wrong response")
+	}
+	return nil
+}
+
+// verifyEndorsementKey verifies the endorsement key certificate chain, and
+// returns the endorsement key.
+func (srv *BootstrapperServer) verifyEndorsementKey(endorsementCerts [][]byte) (*rsa.PublicKey, error) {
+	if srv.EndorsementRoots == nil {
+		return nil, fmt.Errorf("No trusted TPM manufacturer roots configured")
+	}
+	if len(endorsementCerts) == 0 {
+		return nil, fmt.Errorf("No endorsement certificates registered")
+	}
+
+	var certs []*x509.Certificate
+	for _, der := range endorsementCerts {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse endorsement certificate: %s", err)
+		}
+		certs = append(certs, cert)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         srv.EndorsementRoots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to verify endorsement certificate: %s", err)
+	}
+
+	ek, ok := certs[0].PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("Unsupported endorsement key type: %T", certs[0].PublicKey)
+	}
+	return ek, nil
+}
+
+// tpmCredentialSecret derives the secret of a challenge's TPM credential,
+// such that it need not be stored between challenge and response.
+func (srv *BootstrapperServer) tpmCredentialSecret(challenge []byte) []byte {
+	mac := hmac.New(sha256.New, srv.credentialKey)
+	mac.Write(challenge)
+	return mac.Sum(nil)
+}
+
+func decodeAttestationKey(key []byte) (*tpm.Public, error) {
+	ak, err := tpm.DecodePublic(key)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse attestation key: %s", err)
+	}
+	if ak.Attributes&tpm.AttestationKeyAttrs != tpm.AttestationKeyAttrs {
+		return nil, fmt.Errorf("Attestation key is not a restricted signing key bound to its TPM")
+	}
+	return ak, nil
+}
+
+func tpmQualifyingData(challenge, secret []byte) []byte {
+	h := sha256.New()
+	h.Write(challenge)
+	h.Write(secret)
+	return h.Sum(nil)
+}
@@ -19,12 +19,17 @@ import (
 	"time"
 
 	"magma/orc8r/cloud/go/blobstore"
+	"magma/orc8r/cloud/go/obsidian"
+	"magma/orc8r/cloud/go/obsidian/swagger"
+	swagger_protos "magma/orc8r/cloud/go/obsidian/swagger/protos"
 	"magma/orc8r/cloud/go/orc8r"
 	"magma/orc8r/cloud/go/service"
 	"magma/orc8r/cloud/go/services/analytics"
 	analytics_protos "magma/orc8r/cloud/go/services/analytics/protos"
 	"magma/orc8r/cloud/go/services/certifier"
 	analytics_service "magma/orc8r/cloud/go/services/certifier/analytics"
+	"magma/orc8r/cloud/go/services/certifier/handlers"
+	token_handlers "magma/orc8r/cloud/go/services/certifier/obsidian/handlers"
 	certprotos "magma/orc8r/cloud/go/services/certifier/protos"
 	"magma/orc8r/cloud/go/services/certifier/servicers"
 	"magma/orc8r/cloud/go/services/certifier/storage"
@@ -105,7 +110,21 @@ func main() {
 	if err != nil {
 		glog.Fatalf("Failed to create certifier server: %s", err)
 	}
+	if serviceConfig.APITokens.DefaultTTL != 0 {
+		servicers.DefaultAPITokenTTL = serviceConfig.APITokens.DefaultTTL
+	}
+	if serviceConfig.APITokens.MaxTTL != 0 {
+		servicers.MaxAPITokenTTL = serviceConfig.APITokens.MaxTTL
+	}
 	certprotos.RegisterCertifierServer(srv.GrpcServer, servicer)
+	swagger_protos.RegisterSwaggerSpecServer(srv.GrpcServer, swagger.NewSpecServicerFromFile(certifier.ServiceName))
+
+	// Serve CRLs and OCSP responses to relying parties, and API token
+	// management to operators via obsidian
+	if srv.EchoServer != nil {
+		handlers.AttachHandlers(srv.EchoServer, servicer)
+		obsidian.AttachHandlers(srv.EchoServer, token_handlers.GetObsidianHandlers())
+	}
 
 	// Start Garbage Collector Ticker
 	go func() {
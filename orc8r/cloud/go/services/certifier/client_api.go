@@ -16,6 +16,7 @@ package certifier
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"magma/orc8r/cloud/go/clock"
 	certifierprotos "magma/orc8r/cloud/go/services/certifier/protos"
@@ -192,6 +193,86 @@ func RevokeCertificateSN(ctx context.Context, sn string) error {
 	return RevokeCertificate(ctx, &protos.Certificate_SN{Sn: sn})
 }
 
+// RevokeCertificateWithReason revokes the certificate of given SN, recording
+// the reason for revocation in the CA's CRL and OCSP responses.
+func RevokeCertificateWithReason(ctx context.Context, sn string, reason certifierprotos.RevocationReason) error {
+	client, err := getCertifierClient()
+	if err != nil {
+		return err
+	}
+
+	glog.V(2).Infof("Certifier: revoking certificate with SN: %s, reason: %s", sn, reason)
+
+	_, err = client.RevokeCertificateWithReason(ctx, &certifierprotos.RevokeCertificateRequest{Sn: &protos.Certificate_SN{Sn: sn}, Reason: reason})
+	if err != nil {
+		glog.Errorf("Failed to revoke certificate with SN: %s, %s", sn, err)
+		return err
+	}
+	return nil
+}
+
+// GetCRL returns the DER-encoded CRL of the CA of given cert type
+func GetCRL(ctx context.Context, certType protos.CertType) ([]byte, error) {
+	client, err := getCertifierClient()
+	if err != nil {
+		return nil, err
+	}
+	crl, err := client.GetCRL(ctx, &certifierprotos.GetCRLRequest{CertType: certType})
+	if err != nil {
+		glog.Errorf("Failed to get CRL: %s", err)
+		return nil, err
+	}
+	return crl.CrlDer, nil
+}
+
+// IssueAPIToken issues a new API token for the operator, restricted to the
+// passed networks, or all networks if empty.
+// Returns the issued token along with its bearer credential.
+func IssueAPIToken(ctx context.Context, operator *protos.Identity, networks []string, writable bool, ttl time.Duration) (*certifierprotos.IssuedAPIToken, error) {
+	client, err := getCertifierClient()
+	if err != nil {
+		return nil, err
+	}
+	req := &certifierprotos.IssueAPITokenRequest{Operator: operator, Networks: networks, Writable: writable}
+	if ttl != 0 {
+		req.Ttl = ptypes.DurationProto(ttl)
+	}
+	return client.IssueAPIToken(ctx, req)
+}
+
+// AuthenticateAPIToken returns the API token of the bearer credential, if
+// the token is valid.
+func AuthenticateAPIToken(ctx context.Context, bearer string) (*certifierprotos.APIToken, error) {
+	client, err := getCertifierClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.AuthenticateAPIToken(ctx, &certifierprotos.AuthenticateAPITokenRequest{Bearer: bearer})
+}
+
+// RevokeAPIToken revokes the operator's API token of given ID.
+func RevokeAPIToken(ctx context.Context, operator *protos.Identity, id string) error {
+	client, err := getCertifierClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.RevokeAPIToken(ctx, &certifierprotos.RevokeAPITokenRequest{Operator: operator, Id: id})
+	return err
+}
+
+// ListAPITokens returns the operator's unexpired API tokens.
+func ListAPITokens(ctx context.Context, operator *protos.Identity) ([]*certifierprotos.APIToken, error) {
+	client, err := getCertifierClient()
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := client.ListAPITokens(ctx, operator)
+	if err != nil {
+		return nil, err
+	}
+	return tokens.Tokens, nil
+}
+
 // Let certifier to remove expired certificates
 func CollectGarbage(ctx context.Context) error {
 	client, err := getCertifierClient()
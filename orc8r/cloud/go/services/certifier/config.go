@@ -14,6 +14,8 @@
 package certifier
 
 import (
+	"time"
+
 	"magma/orc8r/cloud/go/services/analytics/calculations"
 )
 
@@ -22,4 +24,13 @@ type Config struct {
 	Analytics      calculations.AnalyticsConfig `yaml:"analytics"`
 	CertsDirectory string                       `yaml:"certsDirectory"`
 	Certs          []string                     `yaml:"orchestratorCerts"`
+	APITokens      APITokenConfig               `yaml:"apiTokens"`
+}
+
+// APITokenConfig configures the lifetimes of operators' REST API tokens.
+type APITokenConfig struct {
+	// DefaultTTL is the lifetime of tokens issued without a requested lifetime.
+	DefaultTTL time.Duration `yaml:"defaultTTL"`
+	// MaxTTL is the max lifetime of tokens.
+	MaxTTL time.Duration `yaml:"maxTTL"`
 }
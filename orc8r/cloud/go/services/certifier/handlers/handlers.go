@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package handlers contains the certifier's unauthenticated HTTP endpoints,
+// through which relying parties outside orc8r, such as proxies and partner
+// systems, check the revocation status of gateway and operator certificates.
+//
+// These are served directly by the certifier rather than proxied through
+// obsidian, as relying parties don't hold orc8r client certificates.
+package handlers
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	certprotos "magma/orc8r/cloud/go/services/certifier/protos"
+	"magma/orc8r/lib/go/protos"
+
+	"github.com/labstack/echo"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// CRLPath serves the DER-encoded CRL of a CA, by lowercase cert type,
+	// e.g. /crl/default.
+	CRLPath = "/crl/:cert_type"
+	// OCSPPath serves RFC 6960 OCSP requests, either POSTed or base64-encoded
+	// in the GET path.
+	OCSPPath = "/ocsp"
+
+	crlContentType  = "application/pkix-crl"
+	ocspContentType = "application/ocsp-response"
+
+	// maxOCSPRequestSize bounds POSTed OCSP requests, which are well under
+	// 1KB in practice.
+	maxOCSPRequestSize = 1 << 16
+)
+
+// RevocationServer is the subset of the certifier servicer backing the
+// revocation endpoints.
+type RevocationServer interface {
+	GetCRL(ctx context.Context, req *certprotos.GetCRLRequest) (*certprotos.CRL, error)
+	GetOCSPResponse(ctx context.Context, req *certprotos.OCSPRequest) (*certprotos.OCSPResponse, error)
+}
+
+// AttachHandlers attaches the CRL and OCSP endpoints to the echo server.
+func AttachHandlers(e *echo.Echo, server RevocationServer) {
+	e.GET(CRLPath, getCRLHandler(server))
+	e.POST(OCSPPath, postOCSPHandler(server))
+	e.GET(OCSPPath+"/*", getOCSPHandler(server))
+}
+
+func getCRLHandler(server RevocationServer) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		certType, ok := protos.CertType_value[strings.ToUpper(c.Param("cert_type"))]
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, "unknown cert type")
+		}
+		crl, err := server.GetCRL(c.Request().Context(), &certprotos.GetCRLRequest{CertType: protos.CertType(certType)})
+		if status.Code(err) == codes.NotFound {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		return c.Blob(http.StatusOK, crlContentType, crl.CrlDer)
+	}
+}
+
+func postOCSPHandler(server RevocationServer) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		reqDER, err := ioutil.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, maxOCSPRequestSize))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return respondOCSP(c, server, reqDER)
+	}
+}
+
+// getOCSPHandler serves OCSP requests sent via GET, per RFC 6960 appendix A.1.
+func getOCSPHandler(server RevocationServer) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		encoded, err := url.PathUnescape(c.Param("*"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		reqDER, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return respondOCSP(c, server, reqDER)
+	}
+}
+
+func respondOCSP(c echo.Context, server RevocationServer, reqDER []byte) error {
+	resp, err := server.GetOCSPResponse(c.Request().Context(), &certprotos.OCSPRequest{RequestDer: reqDER})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.Blob(http.StatusOK, ocspContentType, resp.ResponseDer)
+}
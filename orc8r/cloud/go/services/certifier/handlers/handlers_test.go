@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"magma/orc8r/cloud/go/services/certifier/handlers"
+	certprotos "magma/orc8r/cloud/go/services/certifier/protos"
+	"magma/orc8r/lib/go/protos"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeRevocationServer struct{}
+
+func (fakeRevocationServer) GetCRL(ctx context.Context, req *certprotos.GetCRLRequest) (*certprotos.CRL, error) {
+	if req.CertType != protos.CertType_VPN {
+		return nil, status.Error(codes.NotFound, "no CA")
+	}
+	return &certprotos.CRL{CrlDer: []byte("crl")}, nil
+}
+
+func (fakeRevocationServer) GetOCSPResponse(ctx context.Context, req *certprotos.OCSPRequest) (*certprotos.OCSPResponse, error) {
+	return &certprotos.OCSPResponse{ResponseDer: append([]byte("response to "), req.RequestDer...)}, nil
+}
+
+func TestHandlers(t *testing.T) {
+	e := echo.New()
+	handlers.AttachHandlers(e, fakeRevocationServer{})
+
+	rec := serve(e, httptest.NewRequest(http.MethodGet, "/crl/vpn", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/pkix-crl", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "crl", rec.Body.String())
+
+	rec = serve(e, httptest.NewRequest(http.MethodGet, "/crl/default", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = serve(e, httptest.NewRequest(http.MethodGet, "/crl/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serve(e, httptest.NewRequest(http.MethodPost, "/ocsp", bytes.NewReader([]byte("req+/="))))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/ocsp-response", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "response to req+/=", rec.Body.String())
+
+	// GET requests are base64 encoded, then URL encoded
+	encoded := base64.StdEncoding.EncodeToString([]byte("req\xfb\xff"))
+	rec = serve(e, httptest.NewRequest(http.MethodGet, "/ocsp/"+encoded, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "response to req\xfb\xff", rec.Body.String())
+
+	rec = serve(e, httptest.NewRequest(http.MethodGet, "/ocsp/not-base64", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func serve(e *echo.Echo, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package handlers contains the REST API handlers through which operators
+// manage their API tokens.
+//
+// Tokens are managed only by operators authenticated by client certificate,
+// so a leaked token can't be used to issue further tokens.
+package handlers
+
+import (
+	"net/http"
+
+	"magma/orc8r/cloud/go/identity"
+	"magma/orc8r/cloud/go/obsidian"
+	"magma/orc8r/cloud/go/obsidian/access"
+	"magma/orc8r/cloud/go/services/certifier"
+	"magma/orc8r/cloud/go/services/certifier/obsidian/models"
+	"magma/orc8r/lib/go/protos"
+
+	"github.com/labstack/echo"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	pathParamTokenID = "token_id"
+
+	TokensRootPath = obsidian.V1Root + obsidian.MagmaTokensUrlPart
+	TokensPath     = TokensRootPath + obsidian.UrlSep + ":" + pathParamTokenID
+)
+
+// GetObsidianHandlers returns all the obsidian handlers for API tokens.
+func GetObsidianHandlers() []obsidian.Handler {
+	return []obsidian.Handler{
+		{Path: TokensRootPath, Methods: obsidian.GET, HandlerFunc: listTokens},
+		{Path: TokensRootPath, Methods: obsidian.POST, HandlerFunc: issueToken},
+		{Path: TokensPath, Methods: obsidian.DELETE, HandlerFunc: revokeToken},
+	}
+}
+
+func listTokens(c echo.Context) error {
+	operator, err := getCertOperator(c)
+	if err != nil {
+		return err
+	}
+	tokens, err := certifier.ListAPITokens(c.Request().Context(), operator)
+	if err != nil {
+		return makeHTTPError(err)
+	}
+	ret := make([]*models.APIToken, 0, len(tokens))
+	for _, token := range tokens {
+		ret = append(ret, (&models.APIToken{}).FromProto(token))
+	}
+	return c.JSON(http.StatusOK, ret)
+}
+
+func issueToken(c echo.Context) error {
+	operator, err := getCertOperator(c)
+	if err != nil {
+		return err
+	}
+	payload := &models.APITokenRequest{}
+	if err := c.Bind(payload); err != nil {
+		return obsidian.HttpError(err, http.StatusBadRequest)
+	}
+	if err := payload.ValidateModel(); err != nil {
+		return obsidian.HttpError(err, http.StatusBadRequest)
+	}
+	issued, err := certifier.IssueAPIToken(c.Request().Context(), operator, payload.Networks, payload.Writable, payload.TTL())
+	if err != nil {
+		return makeHTTPError(err)
+	}
+	return c.JSON(http.StatusCreated, (&models.IssuedAPIToken{}).FromProto(issued))
+}
+
+func revokeToken(c echo.Context) error {
+	operator, err := getCertOperator(c)
+	if err != nil {
+		return err
+	}
+	tokenID := c.Param(pathParamTokenID)
+	if tokenID == "" {
+		return obsidian.HttpError(errors.New("missing token ID"), http.StatusBadRequest)
+	}
+	err = certifier.RevokeAPIToken(c.Request().Context(), operator, tokenID)
+	if err != nil {
+		return makeHTTPError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// getCertOperator returns the identity of the operator making the request,
+// as authenticated by client certificate.
+func getCertOperator(c echo.Context) (*protos.Identity, error) {
+	csn := c.Request().Header.Get(access.CLIENT_CERT_SN_KEY)
+	if len(csn) == 0 {
+		return nil, obsidian.HttpError(errors.New("API tokens can only be managed with a client certificate"), http.StatusForbidden)
+	}
+	operator, err := certifier.GetVerifiedCertificateIdentity(c.Request().Context(), csn)
+	if err != nil {
+		return nil, obsidian.HttpError(errors.Wrap(err, "invalid client certificate"), http.StatusUnauthorized)
+	}
+	if !identity.IsOperator(operator) {
+		return nil, obsidian.HttpError(errors.New("identity must be for an operator"), http.StatusForbidden)
+	}
+	return operator, nil
+}
+
+func makeHTTPError(err error) *echo.HTTPError {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return obsidian.HttpError(err, http.StatusBadRequest)
+	case codes.NotFound:
+		return obsidian.HttpError(err, http.StatusNotFound)
+	default:
+		return obsidian.HttpError(err, http.StatusInternalServerError)
+	}
+}
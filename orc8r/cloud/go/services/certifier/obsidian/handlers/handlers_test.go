@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers_test
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/identity"
+	"magma/orc8r/cloud/go/obsidian"
+	"magma/orc8r/cloud/go/obsidian/access"
+	"magma/orc8r/cloud/go/obsidian/tests"
+	"magma/orc8r/cloud/go/services/certifier"
+	"magma/orc8r/cloud/go/services/certifier/obsidian/handlers"
+	"magma/orc8r/cloud/go/services/certifier/obsidian/models"
+	certifier_test_init "magma/orc8r/cloud/go/services/certifier/test_init"
+	"magma/orc8r/lib/go/security/cert"
+	"magma/orc8r/lib/go/security/csr"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPITokenHandlers(t *testing.T) {
+	certifier_test_init.StartTestService(t)
+	e := echo.New()
+	ctx := context.Background()
+
+	obsidianHandlers := handlers.GetObsidianHandlers()
+	listTokens := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, "/magma/v1/tokens", obsidian.GET).HandlerFunc
+	issueToken := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, "/magma/v1/tokens", obsidian.POST).HandlerFunc
+	revokeToken := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, "/magma/v1/tokens/:token_id", obsidian.DELETE).HandlerFunc
+
+	operator := identity.NewOperator("bob")
+	certSn := signOperatorCert(t, "bob")
+	certHeaders := map[string]string{access.CLIENT_CERT_SN_KEY: certSn}
+
+	// Token-authenticated requests can't manage tokens
+	tc := tests.Test{
+		Method:         "POST",
+		URL:            "/magma/v1/tokens",
+		Payload:        &models.APITokenRequest{Writable: true},
+		Handler:        issueToken,
+		ExpectedStatus: 403,
+		ExpectedError:  "API tokens can only be managed with a client certificate",
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Invalid lifetime
+	tc = tests.Test{
+		Method:                 "POST",
+		URL:                    "/magma/v1/tokens",
+		Payload:                &models.APITokenRequest{ExpiresIn: 7 * 24 * 60 * 60},
+		Headers:                certHeaders,
+		Handler:                issueToken,
+		ExpectedStatus:         400,
+		ExpectedErrorSubstring: "API token TTL must be positive and at most",
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Issue
+	tc = tests.Test{
+		Method:         "POST",
+		URL:            "/magma/v1/tokens",
+		Payload:        &models.APITokenRequest{Networks: []string{"n1"}, Writable: true, ExpiresIn: 600},
+		Headers:        certHeaders,
+		Handler:        issueToken,
+		ExpectedStatus: 201,
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	issued, err := certifier.ListAPITokens(ctx, operator)
+	require.NoError(t, err)
+	require.Len(t, issued, 1)
+	assert.Equal(t, []string{"n1"}, issued[0].Networks)
+	assert.True(t, issued[0].Writable)
+
+	// List
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/tokens",
+		Headers:        certHeaders,
+		Handler:        listTokens,
+		ExpectedStatus: 200,
+		ExpectedResult: tests.JSONMarshaler([]*models.APIToken{(&models.APIToken{}).FromProto(issued[0])}),
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Revoke
+	tc = tests.Test{
+		Method:         "DELETE",
+		URL:            "/magma/v1/tokens/" + issued[0].Id,
+		Headers:        certHeaders,
+		ParamNames:     []string{"token_id"},
+		ParamValues:    []string{issued[0].Id},
+		Handler:        revokeToken,
+		ExpectedStatus: 204,
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	tc.ExpectedStatus = 404
+	tc.ExpectedErrorSubstring = "not found"
+	tests.RunUnitTest(t, e, tc)
+
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/tokens",
+		Headers:        certHeaders,
+		Handler:        listTokens,
+		ExpectedStatus: 200,
+		ExpectedResult: tests.JSONMarshaler([]*models.APIToken{}),
+	}
+	tests.RunUnitTest(t, e, tc)
+}
+
+func TestIssuedAPIToken_FromProto(t *testing.T) {
+	certifier_test_init.StartTestService(t)
+	operator := identity.NewOperator("bob")
+
+	issued, err := certifier.IssueAPIToken(context.Background(), operator, []string{"n1", "n2"}, true, time.Hour)
+	require.NoError(t, err)
+	model := (&models.IssuedAPIToken{}).FromProto(issued)
+	assert.NoError(t, model.Validate(nil))
+	assert.Equal(t, issued.Bearer, model.AccessToken)
+	assert.Equal(t, "Bearer", model.TokenType)
+	assert.InDelta(t, 3600, model.ExpiresIn, 1)
+	assert.Equal(t, "read write network:n1 network:n2", model.Scope)
+	assert.Equal(t, issued.Token.Id, model.TokenID)
+}
+
+func signOperatorCert(t *testing.T, operatorID string) string {
+	csrMsg, err := csr.CreateCSR(time.Hour, operatorID, operatorID)
+	require.NoError(t, err)
+	certMsg, err := certifier.SignCSR(context.Background(), csrMsg)
+	require.NoError(t, err)
+	c, err := x509.ParseCertificate(certMsg.CertDer)
+	require.NoError(t, err)
+	return cert.SerialToString(c.SerialNumber)
+}
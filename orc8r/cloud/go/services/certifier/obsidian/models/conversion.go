@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"strings"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/certifier/protos"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/golang/protobuf/ptypes"
+)
+
+const (
+	scopeRead          = "read"
+	scopeWrite         = "write"
+	scopeNetworkPrefix = "network:"
+)
+
+func (m *APITokenRequest) ValidateModel() error {
+	return m.Validate(strfmt.Default)
+}
+
+// TTL returns the requested lifetime of the token, or 0 for the default
+// lifetime.
+func (m *APITokenRequest) TTL() time.Duration {
+	return time.Duration(m.ExpiresIn) * time.Second
+}
+
+func (m *APIToken) FromProto(token *protos.APIToken) *APIToken {
+	issuedAt, _ := ptypes.Timestamp(token.IssuedAt)
+	expiresAt, _ := ptypes.Timestamp(token.ExpiresAt)
+	m.TokenID = token.Id
+	m.Networks = token.Networks
+	m.Writable = token.Writable
+	m.IssuedAt = strfmt.DateTime(issuedAt)
+	m.ExpiresAt = strfmt.DateTime(expiresAt)
+	return m
+}
+
+func (m *IssuedAPIToken) FromProto(issued *protos.IssuedAPIToken) *IssuedAPIToken {
+	expiresAt, _ := ptypes.Timestamp(issued.Token.GetExpiresAt())
+	m.AccessToken = issued.Bearer
+	m.TokenType = IssuedAPITokenTokenTypeBearer
+	m.ExpiresIn = int64(expiresAt.Sub(clock.Now()).Round(time.Second) / time.Second)
+	m.Scope = getScope(issued.Token)
+	m.TokenID = issued.Token.GetId()
+	return m
+}
+
+func getScope(token *protos.APIToken) string {
+	scope := []string{scopeRead}
+	if token.GetWritable() {
+		scope = append(scope, scopeWrite)
+	}
+	for _, nid := range token.GetNetworks() {
+		scope = append(scope, scopeNetworkPrefix+nid)
+	}
+	return strings.Join(scope, " ")
+}
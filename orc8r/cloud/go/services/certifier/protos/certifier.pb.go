@@ -7,6 +7,7 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
@@ -26,6 +27,47 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// RevocationReason mirrors the CRLReason codes of RFC 5280, section 5.3.1.
+type RevocationReason int32
+
+const (
+	RevocationReason_UNSPECIFIED            RevocationReason = 0
+	RevocationReason_KEY_COMPROMISE         RevocationReason = 1
+	RevocationReason_CA_COMPROMISE          RevocationReason = 2
+	RevocationReason_AFFILIATION_CHANGED    RevocationReason = 3
+	RevocationReason_SUPERSEDED             RevocationReason = 4
+	RevocationReason_CESSATION_OF_OPERATION RevocationReason = 5
+	RevocationReason_PRIVILEGE_WITHDRAWN    RevocationReason = 9
+)
+
+var RevocationReason_name = map[int32]string{
+	0: "UNSPECIFIED",
+	1: "KEY_COMPROMISE",
+	2: "CA_COMPROMISE",
+	3: "AFFILIATION_CHANGED",
+	4: "SUPERSEDED",
+	5: "CESSATION_OF_OPERATION",
+	9: "PRIVILEGE_WITHDRAWN",
+}
+
+var RevocationReason_value = map[string]int32{
+	"UNSPECIFIED":            0,
+	"KEY_COMPROMISE":         1,
+	"CA_COMPROMISE":          2,
+	"AFFILIATION_CHANGED":    3,
+	"SUPERSEDED":             4,
+	"CESSATION_OF_OPERATION": 5,
+	"PRIVILEGE_WITHDRAWN":    9,
+}
+
+func (x RevocationReason) String() string {
+	return proto.EnumName(RevocationReason_name, int32(x))
+}
+
+func (RevocationReason) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_0037205171c15011, []int{0}
+}
+
 type CertificateInfo struct {
 	Id                   *protos.Identity     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	NotBefore            *timestamp.Timestamp `protobuf:"bytes,2,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
@@ -261,13 +303,617 @@ func (m *GetCARequest) GetCertType() protos.CertType {
 	return protos.CertType_DEFAULT
 }
 
+// RevocationInfo records the revocation of a certificate.
+type RevocationInfo struct {
+	// cert_info of the revoked certificate
+	CertInfo             *CertificateInfo     `protobuf:"bytes,1,opt,name=cert_info,json=certInfo,proto3" json:"cert_info,omitempty"`
+	RevokedAt            *timestamp.Timestamp `protobuf:"bytes,2,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	Reason               RevocationReason     `protobuf:"varint,3,opt,name=reason,proto3,enum=magma.orc8r.certifier.RevocationReason" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *RevocationInfo) Reset()         { *m = RevocationInfo{} }
+func (m *RevocationInfo) String() string { return proto.CompactTextString(m) }
+func (*RevocationInfo) ProtoMessage()    {}
+func (*RevocationInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0037205171c15011, []int{5}
+}
+
+func (m *RevocationInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevocationInfo.Unmarshal(m, b)
+}
+func (m *RevocationInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevocationInfo.Marshal(b, m, deterministic)
+}
+func (m *RevocationInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevocationInfo.Merge(m, src)
+}
+func (m *RevocationInfo) XXX_Size() int {
+	return xxx_messageInfo_RevocationInfo.Size(m)
+}
+func (m *RevocationInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevocationInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevocationInfo proto.InternalMessageInfo
+
+func (m *RevocationInfo) GetCertInfo() *CertificateInfo {
+	if m != nil {
+		return m.CertInfo
+	}
+	return nil
+}
+
+func (m *RevocationInfo) GetRevokedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.RevokedAt
+	}
+	return nil
+}
+
+func (m *RevocationInfo) GetReason() RevocationReason {
+	if m != nil {
+		return m.Reason
+	}
+	return RevocationReason_UNSPECIFIED
+}
+
+type RevokeCertificateRequest struct {
+	Sn                   *protos.Certificate_SN `protobuf:"bytes,1,opt,name=sn,proto3" json:"sn,omitempty"`
+	Reason               RevocationReason       `protobuf:"varint,2,opt,name=reason,proto3,enum=magma.orc8r.certifier.RevocationReason" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *RevokeCertificateRequest) Reset()         { *m = RevokeCertificateRequest{} }
+func (m *RevokeCertificateRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeCertificateRequest) ProtoMessage()    {}
+func (*RevokeCertificateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0037205171c15011, []int{6}
+}
+
+func (m *RevokeCertificateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeCertificateRequest.Unmarshal(m, b)
+}
+func (m *RevokeCertificateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeCertificateRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeCertificateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeCertificateRequest.Merge(m, src)
+}
+func (m *RevokeCertificateRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeCertificateRequest.Size(m)
+}
+func (m *RevokeCertificateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeCertificateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeCertificateRequest proto.InternalMessageInfo
+
+func (m *RevokeCertificateRequest) GetSn() *protos.Certificate_SN {
+	if m != nil {
+		return m.Sn
+	}
+	return nil
+}
+
+func (m *RevokeCertificateRequest) GetReason() RevocationReason {
+	if m != nil {
+		return m.Reason
+	}
+	return RevocationReason_UNSPECIFIED
+}
+
+type GetCRLRequest struct {
+	CertType             protos.CertType `protobuf:"varint,1,opt,name=cert_type,json=certType,proto3,enum=magma.orc8r.CertType" json:"cert_type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetCRLRequest) Reset()         { *m = GetCRLRequest{} }
+func (m *GetCRLRequest) String() string { return proto.CompactTextString(m) }
+func (*GetCRLRequest) ProtoMessage()    {}
+func (*GetCRLRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0037205171c15011, []int{7}
+}
+
+func (m *GetCRLRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCRLRequest.Unmarshal(m, b)
+}
+func (m *GetCRLRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCRLRequest.Marshal(b, m, deterministic)
+}
+func (m *GetCRLRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCRLRequest.Merge(m, src)
+}
+func (m *GetCRLRequest) XXX_Size() int {
+	return xxx_messageInfo_GetCRLRequest.Size(m)
+}
+func (m *GetCRLRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCRLRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCRLRequest proto.InternalMessageInfo
+
+func (m *GetCRLRequest) GetCertType() protos.CertType {
+	if m != nil {
+		return m.CertType
+	}
+	return protos.CertType_DEFAULT
+}
+
+type CRL struct {
+	CrlDer               []byte   `protobuf:"bytes,1,opt,name=crl_der,json=crlDer,proto3" json:"crl_der,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CRL) Reset()         { *m = CRL{} }
+func (m *CRL) String() string { return proto.CompactTextString(m) }
+func (*CRL) ProtoMessage()    {}
+func (*CRL) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0037205171c15011, []int{8}
+}
+
+func (m *CRL) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CRL.Unmarshal(m, b)
+}
+func (m *CRL) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CRL.Marshal(b, m, deterministic)
+}
+func (m *CRL) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CRL.Merge(m, src)
+}
+func (m *CRL) XXX_Size() int {
+	return xxx_messageInfo_CRL.Size(m)
+}
+func (m *CRL) XXX_DiscardUnknown() {
+	xxx_messageInfo_CRL.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CRL proto.InternalMessageInfo
+
+func (m *CRL) GetCrlDer() []byte {
+	if m != nil {
+		return m.CrlDer
+	}
+	return nil
+}
+
+type OCSPRequest struct {
+	RequestDer           []byte   `protobuf:"bytes,1,opt,name=request_der,json=requestDer,proto3" json:"request_der,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OCSPRequest) Reset()         { *m = OCSPRequest{} }
+func (m *OCSPRequest) String() string { return proto.CompactTextString(m) }
+func (*OCSPRequest) ProtoMessage()    {}
+func (*OCSPRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0037205171c15011, []int{9}
+}
+
+func (m *OCSPRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OCSPRequest.Unmarshal(m, b)
+}
+func (m *OCSPRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OCSPRequest.Marshal(b, m, deterministic)
+}
+func (m *OCSPRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OCSPRequest.Merge(m, src)
+}
+func (m *OCSPRequest) XXX_Size() int {
+	return xxx_messageInfo_OCSPRequest.Size(m)
+}
+func (m *OCSPRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OCSPRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OCSPRequest proto.InternalMessageInfo
+
+func (m *OCSPRequest) GetRequestDer() []byte {
+	if m != nil {
+		return m.RequestDer
+	}
+	return nil
+}
+
+type OCSPResponse struct {
+	ResponseDer          []byte   `protobuf:"bytes,1,opt,name=response_der,json=responseDer,proto3" json:"response_der,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OCSPResponse) Reset()         { *m = OCSPResponse{} }
+func (m *OCSPResponse) String() string { return proto.CompactTextString(m) }
+func (*OCSPResponse) ProtoMessage()    {}
+func (*OCSPResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0037205171c15011, []int{10}
+}
+
+func (m *OCSPResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OCSPResponse.Unmarshal(m, b)
+}
+func (m *OCSPResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OCSPResponse.Marshal(b, m, deterministic)
+}
+func (m *OCSPResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OCSPResponse.Merge(m, src)
+}
+func (m *OCSPResponse) XXX_Size() int {
+	return xxx_messageInfo_OCSPResponse.Size(m)
+}
+func (m *OCSPResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_OCSPResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OCSPResponse proto.InternalMessageInfo
+
+func (m *OCSPResponse) GetResponseDer() []byte {
+	if m != nil {
+		return m.ResponseDer
+	}
+	return nil
+}
+
+// APIToken is a short-lived bearer credential of an operator, for the REST API.
+type APIToken struct {
+	Id       string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Operator *protos.Identity `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
+	// networks to which the token's access is restricted.
+	// Empty for all networks accessible to the operator.
+	Networks []string `protobuf:"bytes,3,rep,name=networks,proto3" json:"networks,omitempty"`
+	// writable tokens grant write, as well as read, access.
+	Writable  bool                 `protobuf:"varint,4,opt,name=writable,proto3" json:"writable,omitempty"`
+	IssuedAt  *timestamp.Timestamp `protobuf:"bytes,5,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	ExpiresAt *timestamp.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// secret_hash is the SHA-256 hash of the token's secret.
+	// Only set in storage.
+	SecretHash           []byte   `protobuf:"bytes,7,opt,name=secret_hash,json=secretHash,proto3" json:"secret_hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *APIToken) Reset()         { *m = APIToken{} }
+func (m *APIToken) String() string { return proto.CompactTextString(m) }
+func (*APIToken) ProtoMessage()    {}
+func (*APIToken) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0037205171c15011, []int{11}
+}
+
+func (m *APIToken) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_APIToken.Unmarshal(m, b)
+}
+func (m *APIToken) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_APIToken.Marshal(b, m, deterministic)
+}
+func (m *APIToken) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APIToken.Merge(m, src)
+}
+func (m *APIToken) XXX_Size() int {
+	return xxx_messageInfo_APIToken.Size(m)
+}
+func (m *APIToken) XXX_DiscardUnknown() {
+	xxx_messageInfo_APIToken.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_APIToken proto.InternalMessageInfo
+
+func (m *APIToken) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *APIToken) GetOperator() *protos.Identity {
+	if m != nil {
+		return m.Operator
+	}
+	return nil
+}
+
+func (m *APIToken) GetNetworks() []string {
+	if m != nil {
+		return m.Networks
+	}
+	return nil
+}
+
+func (m *APIToken) GetWritable() bool {
+	if m != nil {
+		return m.Writable
+	}
+	return false
+}
+
+func (m *APIToken) GetIssuedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.IssuedAt
+	}
+	return nil
+}
+
+func (m *APIToken) GetExpiresAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return nil
+}
+
+func (m *APIToken) GetSecretHash() []byte {
+	if m != nil {
+		return m.SecretHash
+	}
+	return nil
+}
+
+type IssueAPITokenRequest struct {
+	Operator *protos.Identity `protobuf:"bytes,1,opt,name=operator,proto3" json:"operator,omitempty"`
+	Networks []string         `protobuf:"bytes,2,rep,name=networks,proto3" json:"networks,omitempty"`
+	Writable bool             `protobuf:"varint,3,opt,name=writable,proto3" json:"writable,omitempty"`
+	// ttl of the token, defaulting to the certifier's configured TTL
+	Ttl                  *duration.Duration `protobuf:"bytes,4,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *IssueAPITokenRequest) Reset()         { *m = IssueAPITokenRequest{} }
+func (m *IssueAPITokenRequest) String() string { return proto.CompactTextString(m) }
+func (*IssueAPITokenRequest) ProtoMessage()    {}
+func (*IssueAPITokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0037205171c15011, []int{12}
+}
+
+func (m *IssueAPITokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IssueAPITokenRequest.Unmarshal(m, b)
+}
+func (m *IssueAPITokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IssueAPITokenRequest.Marshal(b, m, deterministic)
+}
+func (m *IssueAPITokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IssueAPITokenRequest.Merge(m, src)
+}
+func (m *IssueAPITokenRequest) XXX_Size() int {
+	return xxx_messageInfo_IssueAPITokenRequest.Size(m)
+}
+func (m *IssueAPITokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IssueAPITokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IssueAPITokenRequest proto.InternalMessageInfo
+
+func (m *IssueAPITokenRequest) GetOperator() *protos.Identity {
+	if m != nil {
+		return m.Operator
+	}
+	return nil
+}
+
+func (m *IssueAPITokenRequest) GetNetworks() []string {
+	if m != nil {
+		return m.Networks
+	}
+	return nil
+}
+
+func (m *IssueAPITokenRequest) GetWritable() bool {
+	if m != nil {
+		return m.Writable
+	}
+	return false
+}
+
+func (m *IssueAPITokenRequest) GetTtl() *duration.Duration {
+	if m != nil {
+		return m.Ttl
+	}
+	return nil
+}
+
+type IssuedAPIToken struct {
+	Token *APIToken `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// bearer is the token's credential, returned only upon issuance.
+	Bearer               string   `protobuf:"bytes,2,opt,name=bearer,proto3" json:"bearer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IssuedAPIToken) Reset()         { *m = IssuedAPIToken{} }
+func (m *IssuedAPIToken) String() string { return proto.CompactTextString(m) }
+func (*IssuedAPIToken) ProtoMessage()    {}
+func (*IssuedAPIToken) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0037205171c15011, []int{13}
+}
+
+func (m *IssuedAPIToken) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IssuedAPIToken.Unmarshal(m, b)
+}
+func (m *IssuedAPIToken) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IssuedAPIToken.Marshal(b, m, deterministic)
+}
+func (m *IssuedAPIToken) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IssuedAPIToken.Merge(m, src)
+}
+func (m *IssuedAPIToken) XXX_Size() int {
+	return xxx_messageInfo_IssuedAPIToken.Size(m)
+}
+func (m *IssuedAPIToken) XXX_DiscardUnknown() {
+	xxx_messageInfo_IssuedAPIToken.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IssuedAPIToken proto.InternalMessageInfo
+
+func (m *IssuedAPIToken) GetToken() *APIToken {
+	if m != nil {
+		return m.Token
+	}
+	return nil
+}
+
+func (m *IssuedAPIToken) GetBearer() string {
+	if m != nil {
+		return m.Bearer
+	}
+	return ""
+}
+
+type AuthenticateAPITokenRequest struct {
+	Bearer               string   `protobuf:"bytes,1,opt,name=bearer,proto3" json:"bearer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuthenticateAPITokenRequest) Reset()         { *m = AuthenticateAPITokenRequest{} }
+func (m *AuthenticateAPITokenRequest) String() string { return proto.CompactTextString(m) }
+func (*AuthenticateAPITokenRequest) ProtoMessage()    {}
+func (*AuthenticateAPITokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0037205171c15011, []int{14}
+}
+
+func (m *AuthenticateAPITokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthenticateAPITokenRequest.Unmarshal(m, b)
+}
+func (m *AuthenticateAPITokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthenticateAPITokenRequest.Marshal(b, m, deterministic)
+}
+func (m *AuthenticateAPITokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthenticateAPITokenRequest.Merge(m, src)
+}
+func (m *AuthenticateAPITokenRequest) XXX_Size() int {
+	return xxx_messageInfo_AuthenticateAPITokenRequest.Size(m)
+}
+func (m *AuthenticateAPITokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthenticateAPITokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthenticateAPITokenRequest proto.InternalMessageInfo
+
+func (m *AuthenticateAPITokenRequest) GetBearer() string {
+	if m != nil {
+		return m.Bearer
+	}
+	return ""
+}
+
+type RevokeAPITokenRequest struct {
+	// operator which owns the token
+	Operator             *protos.Identity `protobuf:"bytes,1,opt,name=operator,proto3" json:"operator,omitempty"`
+	Id                   string           `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *RevokeAPITokenRequest) Reset()         { *m = RevokeAPITokenRequest{} }
+func (m *RevokeAPITokenRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeAPITokenRequest) ProtoMessage()    {}
+func (*RevokeAPITokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0037205171c15011, []int{15}
+}
+
+func (m *RevokeAPITokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeAPITokenRequest.Unmarshal(m, b)
+}
+func (m *RevokeAPITokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeAPITokenRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeAPITokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeAPITokenRequest.Merge(m, src)
+}
+func (m *RevokeAPITokenRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeAPITokenRequest.Size(m)
+}
+func (m *RevokeAPITokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeAPITokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeAPITokenRequest proto.InternalMessageInfo
+
+func (m *RevokeAPITokenRequest) GetOperator() *protos.Identity {
+	if m != nil {
+		return m.Operator
+	}
+	return nil
+}
+
+func (m *RevokeAPITokenRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type APITokens struct {
+	Tokens               []*APIToken `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *APITokens) Reset()         { *m = APITokens{} }
+func (m *APITokens) String() string { return proto.CompactTextString(m) }
+func (*APITokens) ProtoMessage()    {}
+func (*APITokens) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0037205171c15011, []int{16}
+}
+
+func (m *APITokens) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_APITokens.Unmarshal(m, b)
+}
+func (m *APITokens) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_APITokens.Marshal(b, m, deterministic)
+}
+func (m *APITokens) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APITokens.Merge(m, src)
+}
+func (m *APITokens) XXX_Size() int {
+	return xxx_messageInfo_APITokens.Size(m)
+}
+func (m *APITokens) XXX_DiscardUnknown() {
+	xxx_messageInfo_APITokens.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_APITokens proto.InternalMessageInfo
+
+func (m *APITokens) GetTokens() []*APIToken {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterEnum("magma.orc8r.certifier.RevocationReason", RevocationReason_name, RevocationReason_value)
 	proto.RegisterType((*CertificateInfo)(nil), "magma.orc8r.certifier.CertificateInfo")
 	proto.RegisterType((*CertificateInfoMap)(nil), "magma.orc8r.certifier.CertificateInfoMap")
 	proto.RegisterMapType((map[string]*CertificateInfo)(nil), "magma.orc8r.certifier.CertificateInfoMap.CertificatesEntry")
 	proto.RegisterType((*AddCertRequest)(nil), "magma.orc8r.certifier.AddCertRequest")
 	proto.RegisterType((*SerialNumbers)(nil), "magma.orc8r.certifier.SerialNumbers")
 	proto.RegisterType((*GetCARequest)(nil), "magma.orc8r.certifier.GetCARequest")
+	proto.RegisterType((*RevocationInfo)(nil), "magma.orc8r.certifier.RevocationInfo")
+	proto.RegisterType((*RevokeCertificateRequest)(nil), "magma.orc8r.certifier.RevokeCertificateRequest")
+	proto.RegisterType((*GetCRLRequest)(nil), "magma.orc8r.certifier.GetCRLRequest")
+	proto.RegisterType((*CRL)(nil), "magma.orc8r.certifier.CRL")
+	proto.RegisterType((*OCSPRequest)(nil), "magma.orc8r.certifier.OCSPRequest")
+	proto.RegisterType((*OCSPResponse)(nil), "magma.orc8r.certifier.OCSPResponse")
+	proto.RegisterType((*APIToken)(nil), "magma.orc8r.certifier.APIToken")
+	proto.RegisterType((*IssueAPITokenRequest)(nil), "magma.orc8r.certifier.IssueAPITokenRequest")
+	proto.RegisterType((*IssuedAPIToken)(nil), "magma.orc8r.certifier.IssuedAPIToken")
+	proto.RegisterType((*AuthenticateAPITokenRequest)(nil), "magma.orc8r.certifier.AuthenticateAPITokenRequest")
+	proto.RegisterType((*RevokeAPITokenRequest)(nil), "magma.orc8r.certifier.RevokeAPITokenRequest")
+	proto.RegisterType((*APITokens)(nil), "magma.orc8r.certifier.APITokens")
 }
 
 func init() {
@@ -275,45 +921,86 @@ func init() {
 }
 
 var fileDescriptor_0037205171c15011 = []byte{
-	// 598 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0xcb, 0x6e, 0xd3, 0x40,
-	0x14, 0xb5, 0x13, 0x5a, 0x9a, 0xdb, 0x52, 0x92, 0x41, 0x95, 0x5c, 0x17, 0x89, 0x60, 0x28, 0x0a,
-	0x1b, 0x1b, 0x85, 0x05, 0xe1, 0xb1, 0x49, 0x42, 0x09, 0x95, 0xda, 0x4a, 0x38, 0x11, 0x0b, 0x36,
-	0x91, 0x63, 0xdf, 0x58, 0xa3, 0xda, 0x9e, 0x30, 0x9e, 0x44, 0xca, 0x0f, 0xf0, 0x27, 0xfc, 0x16,
-	0xe2, 0x53, 0x90, 0x5f, 0xc5, 0x4e, 0x1c, 0xf0, 0x2a, 0x33, 0xf7, 0x9e, 0x39, 0xe7, 0xcc, 0x99,
-	0x1b, 0x19, 0x7a, 0x8c, 0xdb, 0x3d, 0x6e, 0xd8, 0x1e, 0x5b, 0x3a, 0x86, 0xcb, 0x8c, 0x10, 0xf9,
-	0x8a, 0xda, 0x18, 0x1a, 0x36, 0x72, 0x41, 0xe7, 0x14, 0xb9, 0xb1, 0xe0, 0x4c, 0xb0, 0x5c, 0x41,
-	0x8f, 0x0b, 0xe4, 0xc4, 0xb7, 0x5c, 0xdf, 0xd2, 0xe3, 0xf3, 0xfa, 0x5d, 0x53, 0x7d, 0x9c, 0x10,
-	0x96, 0x1f, 0x52, 0x4f, 0x8b, 0x5d, 0xe6, 0xfb, 0x2c, 0x48, 0x5b, 0x67, 0x85, 0x16, 0x75, 0x30,
-	0x10, 0x54, 0xac, 0xd3, 0xe6, 0x13, 0x97, 0x31, 0xd7, 0xc3, 0xa4, 0x3b, 0x5b, 0xce, 0x0d, 0x41,
-	0x7d, 0x0c, 0x85, 0xe5, 0x2f, 0x12, 0x80, 0xf6, 0x5b, 0x86, 0x87, 0xc3, 0x44, 0xcc, 0xb6, 0x04,
-	0x5e, 0x06, 0x73, 0x46, 0xce, 0xa1, 0x46, 0x1d, 0x45, 0x6e, 0xcb, 0x9d, 0xc3, 0xee, 0x89, 0x9e,
-	0xb7, 0x7b, 0x99, 0xb2, 0x9b, 0x35, 0xea, 0x90, 0xb7, 0x00, 0x01, 0x13, 0xd3, 0x19, 0xce, 0x19,
-	0x47, 0xa5, 0x16, 0xc3, 0x55, 0x3d, 0x11, 0xd4, 0x33, 0x41, 0x7d, 0x92, 0x09, 0x9a, 0x8d, 0x80,
-	0x89, 0x41, 0x0c, 0x26, 0x6f, 0x20, 0xda, 0x4c, 0xad, 0xb9, 0x40, 0xae, 0xd4, 0xff, 0x7b, 0xf2,
-	0x20, 0x60, 0xa2, 0x1f, 0x61, 0x49, 0x17, 0x1a, 0x51, 0x34, 0x53, 0xb1, 0x5e, 0xa0, 0x72, 0xaf,
-	0x2d, 0x77, 0x8e, 0x37, 0x1c, 0x46, 0x77, 0x99, 0xac, 0x17, 0x68, 0x1e, 0xd8, 0xe9, 0x4a, 0xfb,
-	0x25, 0x03, 0xd9, 0xb8, 0xe2, 0xb5, 0xb5, 0x20, 0x53, 0x38, 0xb2, 0xff, 0x56, 0x43, 0x45, 0x6e,
-	0xd7, 0x3b, 0x87, 0xdd, 0xf7, 0x7a, 0xe9, 0xf3, 0xe8, 0xdb, 0x04, 0xf9, 0x52, 0x78, 0x11, 0x08,
-	0xbe, 0x36, 0x0b, 0x84, 0xaa, 0x0b, 0xad, 0x2d, 0x08, 0x69, 0x42, 0xfd, 0x16, 0xd7, 0x71, 0xb8,
-	0x0d, 0x33, 0x5a, 0x92, 0x0f, 0xb0, 0xb7, 0xb2, 0xbc, 0x65, 0x96, 0xe0, 0x8b, 0x6a, 0x06, 0xcc,
-	0xe4, 0xd0, 0xbb, 0x5a, 0x4f, 0xd6, 0x7e, 0xc8, 0x70, 0xdc, 0x77, 0x9c, 0x08, 0x61, 0xe2, 0xf7,
-	0x25, 0x86, 0xa2, 0xea, 0x13, 0x9e, 0x42, 0x1c, 0xd3, 0xd4, 0x41, 0x1e, 0xcb, 0x1f, 0x99, 0xf7,
-	0xa3, 0xfd, 0xc7, 0xcd, 0xa4, 0xeb, 0xd5, 0x92, 0x7e, 0x0a, 0x0f, 0xc6, 0xc8, 0xa9, 0xe5, 0xdd,
-	0x2c, 0xfd, 0x19, 0xf2, 0x30, 0xba, 0x6d, 0x18, 0x24, 0xd1, 0x36, 0xcc, 0x68, 0xa9, 0x0d, 0xe0,
-	0x68, 0x84, 0x62, 0xd8, 0xcf, 0x8c, 0x16, 0x64, 0xe4, 0x4a, 0x32, 0xdd, 0x9f, 0x7b, 0xd0, 0x18,
-	0x66, 0xc1, 0x90, 0x21, 0xec, 0xc5, 0x8c, 0xe4, 0xd9, 0x8e, 0xe4, 0xf2, 0x7a, 0xea, 0xa3, 0x22,
-	0x79, 0x3f, 0xe2, 0xd1, 0x24, 0x32, 0x00, 0x32, 0xa6, 0x6e, 0x90, 0xa6, 0x98, 0xe6, 0x4c, 0x9a,
-	0x45, 0xf0, 0xd8, 0x54, 0x95, 0x2d, 0x6f, 0x29, 0x56, 0x93, 0xc8, 0x04, 0x0e, 0x47, 0x28, 0xb2,
-	0x7c, 0xc9, 0xd9, 0x2e, 0xa8, 0x3e, 0xbe, 0x51, 0x2b, 0xbe, 0xb2, 0x26, 0x91, 0x0b, 0x68, 0x99,
-	0xb8, 0x62, 0xb7, 0x98, 0x37, 0xf6, 0x4f, 0xee, 0x56, 0xa1, 0xf9, 0x95, 0x51, 0x47, 0x93, 0xc8,
-	0xd5, 0xdd, 0x88, 0x64, 0x1c, 0xe7, 0x3b, 0x2c, 0x14, 0x27, 0xa9, 0x9c, 0xed, 0x0b, 0x34, 0x3f,
-	0xd1, 0x20, 0x4f, 0x17, 0x92, 0xf2, 0x31, 0x53, 0x9f, 0xef, 0x90, 0x29, 0x0c, 0x8a, 0x26, 0x91,
-	0x6b, 0x68, 0x5e, 0xd1, 0x50, 0x14, 0x28, 0xb7, 0xb5, 0x2b, 0xd3, 0x7d, 0x86, 0xfd, 0x11, 0x8a,
-	0xbe, 0xe7, 0x95, 0x91, 0xbc, 0xac, 0xfc, 0x27, 0xd7, 0x24, 0xd2, 0x83, 0xe3, 0x21, 0xf3, 0x3c,
-	0xb4, 0xc5, 0xc8, 0xe2, 0x33, 0xcb, 0xc5, 0x32, 0xc6, 0xb2, 0x94, 0x06, 0xaf, 0xbe, 0x25, 0x55,
-	0xa3, 0xea, 0xb7, 0x62, 0xb6, 0x1f, 0xff, 0xbe, 0xfe, 0x13, 0x00, 0x00, 0xff, 0xff, 0x7d, 0x4f,
-	0x1b, 0x2f, 0x5e, 0x06, 0x00, 0x00,
+	// 1262 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0xed, 0x72, 0xda, 0x46,
+	0x17, 0x46, 0x10, 0x13, 0x73, 0xb0, 0x09, 0xde, 0x7c, 0x11, 0xf9, 0x9d, 0xc4, 0x51, 0xde, 0xb4,
+	0x69, 0xd3, 0x81, 0xc6, 0x9d, 0x4c, 0x9c, 0xb6, 0x33, 0x1d, 0x59, 0xc8, 0xb6, 0x5a, 0x6c, 0xd3,
+	0xc5, 0x49, 0xa6, 0x99, 0xce, 0x30, 0x42, 0xac, 0xb1, 0xc6, 0xa0, 0xa5, 0xab, 0xc5, 0xa9, 0x6f,
+	0xa0, 0xd3, 0x6b, 0xe8, 0x15, 0xf4, 0x4f, 0x7b, 0x2f, 0xbd, 0x81, 0x4e, 0x2f, 0xa5, 0xb3, 0x2b,
+	0x09, 0x23, 0x90, 0x80, 0xb4, 0xfd, 0xc5, 0x7e, 0x3c, 0xe7, 0xeb, 0x39, 0x67, 0xcf, 0x11, 0xb0,
+	0x43, 0x99, 0xb3, 0xc3, 0x6a, 0x4e, 0x9f, 0x8e, 0xba, 0xb5, 0x1e, 0xad, 0xf9, 0x84, 0x5d, 0xb8,
+	0x0e, 0xf1, 0x6b, 0x0e, 0x61, 0xdc, 0x3d, 0x75, 0x09, 0xab, 0x0d, 0x19, 0xe5, 0x74, 0xe2, 0xa0,
+	0x2a, 0x0f, 0xd0, 0xed, 0x81, 0xdd, 0x1b, 0xd8, 0x55, 0x29, 0x5f, 0x1d, 0x5f, 0xaa, 0xff, 0x0b,
+	0x14, 0x26, 0x0b, 0xa9, 0xf7, 0xe2, 0xb7, 0x74, 0x30, 0xa0, 0x5e, 0x78, 0xb5, 0x19, 0xbb, 0x72,
+	0xbb, 0xc4, 0xe3, 0x2e, 0xbf, 0x0c, 0x2f, 0xef, 0xf7, 0x28, 0xed, 0xf5, 0x49, 0x70, 0xdb, 0x19,
+	0x9d, 0xd6, 0xba, 0x23, 0x66, 0x73, 0x77, 0x2c, 0xfc, 0x60, 0xfa, 0x9e, 0xbb, 0x03, 0xe2, 0x73,
+	0x7b, 0x30, 0x0c, 0x00, 0xda, 0x5f, 0x0a, 0xdc, 0x30, 0x02, 0x67, 0x1c, 0x9b, 0x13, 0xcb, 0x3b,
+	0xa5, 0xe8, 0x31, 0x64, 0xdd, 0x6e, 0x45, 0xd9, 0x52, 0x9e, 0x14, 0xb7, 0x6f, 0x57, 0x27, 0xc3,
+	0xb1, 0x42, 0xeb, 0x38, 0xeb, 0x76, 0xd1, 0x4b, 0x00, 0x8f, 0xf2, 0x76, 0x87, 0x9c, 0x52, 0x46,
+	0x2a, 0x59, 0x09, 0x57, 0xab, 0x81, 0xc1, 0x6a, 0x64, 0xb0, 0x7a, 0x12, 0x19, 0xc4, 0x05, 0x8f,
+	0xf2, 0x5d, 0x09, 0x46, 0x2f, 0x40, 0x6c, 0xda, 0xf6, 0x29, 0x27, 0xac, 0x92, 0x5b, 0x28, 0xb9,
+	0xea, 0x51, 0xae, 0x0b, 0x2c, 0xda, 0x86, 0x82, 0xa0, 0xae, 0xcd, 0x2f, 0x87, 0xa4, 0x72, 0x6d,
+	0x4b, 0x79, 0x52, 0x9a, 0xf2, 0x50, 0xc4, 0x72, 0x72, 0x39, 0x24, 0x78, 0xd5, 0x09, 0x57, 0xda,
+	0x9f, 0x0a, 0xa0, 0xa9, 0x10, 0x0f, 0xed, 0x21, 0x6a, 0xc3, 0x9a, 0x73, 0x75, 0xea, 0x57, 0x94,
+	0xad, 0xdc, 0x93, 0xe2, 0xf6, 0x17, 0xd5, 0xc4, 0xf4, 0x55, 0x67, 0x15, 0x4c, 0x1e, 0xf9, 0xa6,
+	0xc7, 0xd9, 0x25, 0x8e, 0x29, 0x54, 0x7b, 0xb0, 0x31, 0x03, 0x41, 0x65, 0xc8, 0x9d, 0x93, 0x4b,
+	0x49, 0x6e, 0x01, 0x8b, 0x25, 0xfa, 0x12, 0x56, 0x2e, 0xec, 0xfe, 0x28, 0x62, 0xf0, 0x83, 0xe5,
+	0x1c, 0xc0, 0x81, 0xd0, 0xe7, 0xd9, 0x1d, 0x45, 0xfb, 0x49, 0x81, 0x92, 0xde, 0xed, 0x0a, 0x04,
+	0x26, 0x3f, 0x8c, 0x88, 0xcf, 0x97, 0x4d, 0xe1, 0x3d, 0x90, 0x34, 0xb5, 0xbb, 0x84, 0x49, 0xf3,
+	0x6b, 0xf8, 0xba, 0xd8, 0xd7, 0xa7, 0x99, 0xce, 0x2d, 0xc7, 0xf4, 0x43, 0x58, 0x6f, 0x11, 0xe6,
+	0xda, 0xfd, 0xa3, 0xd1, 0xa0, 0x43, 0x98, 0x2f, 0xa2, 0xf5, 0xbd, 0x80, 0xda, 0x02, 0x16, 0x4b,
+	0x6d, 0x17, 0xd6, 0xf6, 0x09, 0x37, 0xf4, 0xc8, 0xd1, 0x98, 0x19, 0x65, 0x39, 0x33, 0x7f, 0x28,
+	0x50, 0xc2, 0xe4, 0x82, 0x3a, 0xb2, 0xd2, 0x65, 0xc9, 0x1a, 0xa1, 0x1a, 0xd7, 0x3b, 0xa5, 0x15,
+	0xe5, 0xbd, 0x88, 0x94, 0x7a, 0xa5, 0x92, 0x97, 0x00, 0x8c, 0x5c, 0xd0, 0x73, 0xd2, 0x6d, 0xdb,
+	0x7c, 0x99, 0x82, 0x0e, 0xd1, 0x3a, 0x47, 0x5f, 0x41, 0x9e, 0x11, 0xdb, 0xa7, 0x5e, 0x48, 0xd5,
+	0x87, 0x29, 0xc6, 0xaf, 0xdc, 0xc6, 0x12, 0x8e, 0x43, 0x31, 0xed, 0x67, 0x05, 0x2a, 0x58, 0xaa,
+	0x9b, 0xf0, 0x2f, 0x22, 0xe9, 0x29, 0x64, 0x7d, 0x2f, 0x0c, 0x6b, 0x73, 0x86, 0x9d, 0x10, 0x5c,
+	0x6d, 0x1d, 0xe1, 0xac, 0xef, 0x4d, 0xb8, 0x92, 0xfd, 0x67, 0xae, 0x18, 0xb0, 0x2e, 0x52, 0x84,
+	0x1b, 0xff, 0x26, 0x47, 0xf7, 0x21, 0x67, 0xe0, 0x06, 0xba, 0x0b, 0xd7, 0x1d, 0xd6, 0x97, 0xf5,
+	0xa5, 0xc8, 0xfa, 0xca, 0x3b, 0xac, 0x5f, 0x27, 0x4c, 0xab, 0x42, 0xf1, 0xd8, 0x68, 0x35, 0x23,
+	0x13, 0x0f, 0xa0, 0xc8, 0x82, 0xe5, 0x04, 0x16, 0xc2, 0x23, 0x81, 0x7f, 0x06, 0x6b, 0x01, 0xde,
+	0x1f, 0x52, 0xcf, 0x27, 0xe8, 0x21, 0xac, 0xb1, 0x70, 0x3d, 0x21, 0x51, 0x8c, 0xce, 0x84, 0xc8,
+	0x2f, 0x59, 0x58, 0xd5, 0x9b, 0xd6, 0x09, 0x3d, 0x27, 0x1e, 0x2a, 0x8d, 0x1f, 0x44, 0x41, 0x56,
+	0xfe, 0x33, 0x58, 0xa5, 0x43, 0xc2, 0x6c, 0x4e, 0x59, 0x98, 0xe9, 0x94, 0x67, 0x32, 0x86, 0x21,
+	0x15, 0x56, 0x3d, 0xc2, 0xdf, 0x51, 0x76, 0xee, 0x57, 0x72, 0xb2, 0xa2, 0xc7, 0x7b, 0x71, 0xf7,
+	0x8e, 0xb9, 0xdc, 0xee, 0xf4, 0x83, 0xb6, 0xb4, 0x8a, 0xc7, 0x7b, 0xd1, 0xec, 0x5c, 0xdf, 0x1f,
+	0x05, 0x55, 0xb5, 0xb2, 0xb8, 0xd9, 0x05, 0x60, 0x9d, 0x8b, 0x7a, 0x24, 0x3f, 0x0e, 0x5d, 0x46,
+	0x7c, 0x21, 0x99, 0x5f, 0x5c, 0x8f, 0x21, 0x5a, 0x97, 0x7c, 0xfa, 0xc4, 0x61, 0x84, 0xb7, 0xcf,
+	0x6c, 0xff, 0xac, 0x72, 0x3d, 0xe0, 0x33, 0x38, 0x3a, 0xb0, 0xfd, 0x33, 0xed, 0x77, 0x05, 0x6e,
+	0x59, 0xc2, 0x50, 0xc4, 0x50, 0x94, 0x89, 0x49, 0x62, 0x94, 0xf7, 0x27, 0x26, 0x3b, 0x87, 0x98,
+	0xdc, 0x14, 0x31, 0x4f, 0x21, 0xc7, 0x79, 0x5f, 0xf2, 0x55, 0xdc, 0xbe, 0x37, 0x13, 0x58, 0x3d,
+	0x1c, 0x65, 0x58, 0xa0, 0xb4, 0x36, 0x94, 0xac, 0x80, 0x98, 0x28, 0xa5, 0xcf, 0x61, 0x85, 0x8b,
+	0x45, 0xe8, 0xe6, 0x83, 0x94, 0x3a, 0x1f, 0x07, 0x18, 0xa0, 0xd1, 0x1d, 0xc8, 0x77, 0x88, 0xcd,
+	0xc2, 0x8e, 0x57, 0xc0, 0xe1, 0x4e, 0x7b, 0x0e, 0x9b, 0xfa, 0x88, 0x9f, 0x89, 0xe8, 0xc4, 0x73,
+	0x9a, 0xe6, 0xe5, 0x4a, 0x4c, 0x89, 0x89, 0xbd, 0x85, 0xdb, 0xc1, 0xbb, 0xfd, 0x0f, 0x88, 0x0c,
+	0x8a, 0x34, 0x1b, 0x15, 0xa9, 0x56, 0x87, 0x42, 0xa4, 0xd5, 0x47, 0x2f, 0x20, 0x2f, 0x03, 0x88,
+	0x26, 0xd5, 0xc2, 0x78, 0x43, 0xf8, 0xc7, 0xbf, 0x2a, 0x50, 0x9e, 0x7e, 0xec, 0xe8, 0x06, 0x14,
+	0x5f, 0x1d, 0xb5, 0x9a, 0xa6, 0x61, 0xed, 0x59, 0x66, 0xbd, 0x9c, 0x41, 0x08, 0x4a, 0xdf, 0x98,
+	0xdf, 0xb5, 0x8d, 0xe3, 0xc3, 0x26, 0x3e, 0x3e, 0xb4, 0x5a, 0x66, 0x59, 0x41, 0x1b, 0xb0, 0x6e,
+	0xe8, 0x93, 0x47, 0x59, 0x74, 0x17, 0x6e, 0xea, 0x7b, 0x7b, 0x56, 0xc3, 0xd2, 0x4f, 0xac, 0xe3,
+	0xa3, 0xb6, 0x71, 0xa0, 0x1f, 0xed, 0x9b, 0xf5, 0x72, 0x0e, 0x95, 0x00, 0x5a, 0xaf, 0x9a, 0x26,
+	0x6e, 0x99, 0x75, 0xb3, 0x5e, 0xbe, 0x86, 0x54, 0xb8, 0x63, 0x98, 0xad, 0x56, 0x00, 0x3b, 0xde,
+	0x6b, 0x1f, 0x37, 0x4d, 0x2c, 0x37, 0xe5, 0x15, 0xa1, 0xa4, 0x89, 0xad, 0xd7, 0x56, 0xc3, 0xdc,
+	0x37, 0xdb, 0x6f, 0xac, 0x93, 0x83, 0x3a, 0xd6, 0xdf, 0x1c, 0x95, 0x0b, 0xdb, 0xbf, 0x01, 0x14,
+	0x8c, 0x28, 0x12, 0x64, 0xc0, 0x8a, 0x9c, 0x15, 0xe8, 0x51, 0x4a, 0xa8, 0x93, 0x93, 0x44, 0xbd,
+	0x19, 0x6f, 0x49, 0xba, 0xd0, 0xa3, 0x65, 0xd0, 0x2e, 0xa0, 0x96, 0xdb, 0xf3, 0xc2, 0xf9, 0x18,
+	0xf6, 0x4a, 0x54, 0x8e, 0x83, 0x5b, 0x58, 0xad, 0xa4, 0xf5, 0x55, 0x2d, 0x83, 0x4e, 0xa0, 0xb8,
+	0x4f, 0x78, 0x94, 0x30, 0x34, 0xaf, 0x05, 0xab, 0x4b, 0x8e, 0x1d, 0x2d, 0x83, 0x4c, 0xd8, 0x98,
+	0xe9, 0xf8, 0xf3, 0x75, 0x6f, 0xc4, 0x2e, 0x5f, 0x53, 0xb7, 0xab, 0x65, 0x90, 0x03, 0x9b, 0x33,
+	0x6a, 0xde, 0xb8, 0xfc, 0x2c, 0x4c, 0x74, 0x6d, 0x4e, 0xfb, 0x4f, 0x1a, 0x36, 0xc9, 0x46, 0x1a,
+	0x90, 0x0f, 0x66, 0x02, 0xfa, 0xff, 0x9c, 0x5c, 0x8c, 0x47, 0x86, 0xaa, 0xa6, 0xb1, 0x80, 0x1b,
+	0x5a, 0x06, 0x7d, 0x0f, 0x37, 0xf6, 0x09, 0x8f, 0xf5, 0x73, 0x2d, 0x45, 0x60, 0x62, 0x48, 0xa8,
+	0x8f, 0xe6, 0x62, 0x02, 0x45, 0xd2, 0xd7, 0xd2, 0x54, 0xb6, 0x1f, 0xa7, 0x3d, 0x95, 0xd8, 0x47,
+	0x53, 0x72, 0xe4, 0xdf, 0x42, 0x79, 0xcf, 0xf5, 0x26, 0xd5, 0xf9, 0x28, 0xf9, 0x21, 0xab, 0x69,
+	0xd4, 0xc4, 0xbe, 0x89, 0xb4, 0x0c, 0x3a, 0x84, 0x72, 0xc3, 0xf5, 0x79, 0x4c, 0xe5, 0xac, 0xed,
+	0xa5, 0xd5, 0x1d, 0xc8, 0xdc, 0xe8, 0xfd, 0x7e, 0x92, 0x92, 0x8f, 0x96, 0xfe, 0x9e, 0xd5, 0x32,
+	0x88, 0xc0, 0x7a, 0x6c, 0x26, 0xa0, 0xa7, 0x29, 0xd2, 0x49, 0x93, 0x43, 0x7d, 0x3c, 0x0f, 0x3c,
+	0x6e, 0xdb, 0x5a, 0x06, 0x0d, 0xe0, 0x56, 0x52, 0xa7, 0x45, 0xdb, 0x69, 0x69, 0x4a, 0x6f, 0xcb,
+	0xea, 0xa2, 0x2e, 0xa8, 0x65, 0x50, 0x0b, 0x4a, 0xf1, 0x0e, 0x8d, 0x3e, 0x99, 0xfb, 0x26, 0xa6,
+	0x4d, 0x24, 0x96, 0xc5, 0xd7, 0xb0, 0x2e, 0x72, 0x78, 0xd5, 0x9e, 0x53, 0x6a, 0x62, 0x6b, 0x81,
+	0x7f, 0x22, 0x81, 0x3b, 0x50, 0x32, 0x68, 0xbf, 0x4f, 0x1c, 0xbe, 0x6f, 0xb3, 0x8e, 0xdd, 0x23,
+	0x49, 0x89, 0x4c, 0xf2, 0x62, 0xf7, 0xd3, 0xb7, 0xc1, 0x69, 0x6d, 0xd9, 0x7f, 0xab, 0x9d, 0xbc,
+	0xfc, 0xfd, 0xec, 0xef, 0x01, 0x00, 0xd6, 0x8e, 0x74, 0xac, 0xe0, 0x0e, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -342,6 +1029,16 @@ type CertifierClient interface {
 	// If the certificate does not exist or is expired, this request is ignored.
 	//
 	RevokeCertificate(ctx context.Context, in *protos.Certificate_SN, opts ...grpc.CallOption) (*protos.Void, error)
+	// Revoke an existing certificate, recording the reason for revocation.
+	// Throws NOT_FOUND if the certificate is missing.
+	//
+	RevokeCertificateWithReason(ctx context.Context, in *RevokeCertificateRequest, opts ...grpc.CallOption) (*protos.Void, error)
+	// Returns the current CRL of the requested CA, signed by the CA.
+	//
+	GetCRL(ctx context.Context, in *GetCRLRequest, opts ...grpc.CallOption) (*CRL, error)
+	// Returns the signed OCSP response to an OCSP request.
+	//
+	GetOCSPResponse(ctx context.Context, in *OCSPRequest, opts ...grpc.CallOption) (*OCSPResponse, error)
 	// Add provided Certificate (AddCertRequest.cert_der) into Certifier table and
 	// associates its Serial Number with given Identity (AddCertRequest.id)
 	AddCertificate(ctx context.Context, in *AddCertRequest, opts ...grpc.CallOption) (*protos.Void, error)
@@ -352,6 +1049,20 @@ type CertifierClient interface {
 	ListCertificates(ctx context.Context, in *protos.Void, opts ...grpc.CallOption) (*SerialNumbers, error)
 	// Returns all registered Certificates
 	GetAll(ctx context.Context, in *protos.Void, opts ...grpc.CallOption) (*CertificateInfoMap, error)
+	// Issues a new API token for an operator.
+	//
+	IssueAPIToken(ctx context.Context, in *IssueAPITokenRequest, opts ...grpc.CallOption) (*IssuedAPIToken, error)
+	// Returns the API token of a bearer credential.
+	// Throws UNAUTHENTICATED if the token is unknown, invalid, or expired.
+	//
+	AuthenticateAPIToken(ctx context.Context, in *AuthenticateAPITokenRequest, opts ...grpc.CallOption) (*APIToken, error)
+	// Revokes an API token of an operator.
+	// Throws NOT_FOUND if the operator has no such token.
+	//
+	RevokeAPIToken(ctx context.Context, in *RevokeAPITokenRequest, opts ...grpc.CallOption) (*protos.Void, error)
+	// Returns the unexpired API tokens of an operator.
+	//
+	ListAPITokens(ctx context.Context, in *protos.Identity, opts ...grpc.CallOption) (*APITokens, error)
 	// cleanup expired certificates
 	//
 	CollectGarbage(ctx context.Context, in *protos.Void, opts ...grpc.CallOption) (*protos.Void, error)
@@ -401,6 +1112,33 @@ func (c *certifierClient) RevokeCertificate(ctx context.Context, in *protos.Cert
 	return out, nil
 }
 
+func (c *certifierClient) RevokeCertificateWithReason(ctx context.Context, in *RevokeCertificateRequest, opts ...grpc.CallOption) (*protos.Void, error) {
+	out := new(protos.Void)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.certifier.Certifier/RevokeCertificateWithReason", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *certifierClient) GetCRL(ctx context.Context, in *GetCRLRequest, opts ...grpc.CallOption) (*CRL, error) {
+	out := new(CRL)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.certifier.Certifier/GetCRL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *certifierClient) GetOCSPResponse(ctx context.Context, in *OCSPRequest, opts ...grpc.CallOption) (*OCSPResponse, error) {
+	out := new(OCSPResponse)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.certifier.Certifier/GetOCSPResponse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *certifierClient) AddCertificate(ctx context.Context, in *AddCertRequest, opts ...grpc.CallOption) (*protos.Void, error) {
 	out := new(protos.Void)
 	err := c.cc.Invoke(ctx, "/magma.orc8r.certifier.Certifier/AddCertificate", in, out, opts...)
@@ -437,6 +1175,42 @@ func (c *certifierClient) GetAll(ctx context.Context, in *protos.Void, opts ...g
 	return out, nil
 }
 
+func (c *certifierClient) IssueAPIToken(ctx context.Context, in *IssueAPITokenRequest, opts ...grpc.CallOption) (*IssuedAPIToken, error) {
+	out := new(IssuedAPIToken)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.certifier.Certifier/IssueAPIToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *certifierClient) AuthenticateAPIToken(ctx context.Context, in *AuthenticateAPITokenRequest, opts ...grpc.CallOption) (*APIToken, error) {
+	out := new(APIToken)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.certifier.Certifier/AuthenticateAPIToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *certifierClient) RevokeAPIToken(ctx context.Context, in *RevokeAPITokenRequest, opts ...grpc.CallOption) (*protos.Void, error) {
+	out := new(protos.Void)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.certifier.Certifier/RevokeAPIToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *certifierClient) ListAPITokens(ctx context.Context, in *protos.Identity, opts ...grpc.CallOption) (*APITokens, error) {
+	out := new(APITokens)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.certifier.Certifier/ListAPITokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *certifierClient) CollectGarbage(ctx context.Context, in *protos.Void, opts ...grpc.CallOption) (*protos.Void, error) {
 	out := new(protos.Void)
 	err := c.cc.Invoke(ctx, "/magma.orc8r.certifier.Certifier/CollectGarbage", in, out, opts...)
@@ -462,6 +1236,16 @@ type CertifierServer interface {
 	// If the certificate does not exist or is expired, this request is ignored.
 	//
 	RevokeCertificate(context.Context, *protos.Certificate_SN) (*protos.Void, error)
+	// Revoke an existing certificate, recording the reason for revocation.
+	// Throws NOT_FOUND if the certificate is missing.
+	//
+	RevokeCertificateWithReason(context.Context, *RevokeCertificateRequest) (*protos.Void, error)
+	// Returns the current CRL of the requested CA, signed by the CA.
+	//
+	GetCRL(context.Context, *GetCRLRequest) (*CRL, error)
+	// Returns the signed OCSP response to an OCSP request.
+	//
+	GetOCSPResponse(context.Context, *OCSPRequest) (*OCSPResponse, error)
 	// Add provided Certificate (AddCertRequest.cert_der) into Certifier table and
 	// associates its Serial Number with given Identity (AddCertRequest.id)
 	AddCertificate(context.Context, *AddCertRequest) (*protos.Void, error)
@@ -472,6 +1256,20 @@ type CertifierServer interface {
 	ListCertificates(context.Context, *protos.Void) (*SerialNumbers, error)
 	// Returns all registered Certificates
 	GetAll(context.Context, *protos.Void) (*CertificateInfoMap, error)
+	// Issues a new API token for an operator.
+	//
+	IssueAPIToken(context.Context, *IssueAPITokenRequest) (*IssuedAPIToken, error)
+	// Returns the API token of a bearer credential.
+	// Throws UNAUTHENTICATED if the token is unknown, invalid, or expired.
+	//
+	AuthenticateAPIToken(context.Context, *AuthenticateAPITokenRequest) (*APIToken, error)
+	// Revokes an API token of an operator.
+	// Throws NOT_FOUND if the operator has no such token.
+	//
+	RevokeAPIToken(context.Context, *RevokeAPITokenRequest) (*protos.Void, error)
+	// Returns the unexpired API tokens of an operator.
+	//
+	ListAPITokens(context.Context, *protos.Identity) (*APITokens, error)
 	// cleanup expired certificates
 	//
 	CollectGarbage(context.Context, *protos.Void) (*protos.Void, error)
@@ -493,6 +1291,15 @@ func (*UnimplementedCertifierServer) GetIdentity(ctx context.Context, req *proto
 func (*UnimplementedCertifierServer) RevokeCertificate(ctx context.Context, req *protos.Certificate_SN) (*protos.Void, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RevokeCertificate not implemented")
 }
+func (*UnimplementedCertifierServer) RevokeCertificateWithReason(ctx context.Context, req *RevokeCertificateRequest) (*protos.Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeCertificateWithReason not implemented")
+}
+func (*UnimplementedCertifierServer) GetCRL(ctx context.Context, req *GetCRLRequest) (*CRL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCRL not implemented")
+}
+func (*UnimplementedCertifierServer) GetOCSPResponse(ctx context.Context, req *OCSPRequest) (*OCSPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOCSPResponse not implemented")
+}
 func (*UnimplementedCertifierServer) AddCertificate(ctx context.Context, req *AddCertRequest) (*protos.Void, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddCertificate not implemented")
 }
@@ -505,6 +1312,18 @@ func (*UnimplementedCertifierServer) ListCertificates(ctx context.Context, req *
 func (*UnimplementedCertifierServer) GetAll(ctx context.Context, req *protos.Void) (*CertificateInfoMap, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAll not implemented")
 }
+func (*UnimplementedCertifierServer) IssueAPIToken(ctx context.Context, req *IssueAPITokenRequest) (*IssuedAPIToken, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueAPIToken not implemented")
+}
+func (*UnimplementedCertifierServer) AuthenticateAPIToken(ctx context.Context, req *AuthenticateAPITokenRequest) (*APIToken, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthenticateAPIToken not implemented")
+}
+func (*UnimplementedCertifierServer) RevokeAPIToken(ctx context.Context, req *RevokeAPITokenRequest) (*protos.Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAPIToken not implemented")
+}
+func (*UnimplementedCertifierServer) ListAPITokens(ctx context.Context, req *protos.Identity) (*APITokens, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAPITokens not implemented")
+}
 func (*UnimplementedCertifierServer) CollectGarbage(ctx context.Context, req *protos.Void) (*protos.Void, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CollectGarbage not implemented")
 }
@@ -585,6 +1404,60 @@ func _Certifier_RevokeCertificate_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Certifier_RevokeCertificateWithReason_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertifierServer).RevokeCertificateWithReason(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.certifier.Certifier/RevokeCertificateWithReason",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertifierServer).RevokeCertificateWithReason(ctx, req.(*RevokeCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Certifier_GetCRL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCRLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertifierServer).GetCRL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.certifier.Certifier/GetCRL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertifierServer).GetCRL(ctx, req.(*GetCRLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Certifier_GetOCSPResponse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OCSPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertifierServer).GetOCSPResponse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.certifier.Certifier/GetOCSPResponse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertifierServer).GetOCSPResponse(ctx, req.(*OCSPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Certifier_AddCertificate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AddCertRequest)
 	if err := dec(in); err != nil {
@@ -657,6 +1530,78 @@ func _Certifier_GetAll_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Certifier_IssueAPIToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueAPITokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertifierServer).IssueAPIToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.certifier.Certifier/IssueAPIToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertifierServer).IssueAPIToken(ctx, req.(*IssueAPITokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Certifier_AuthenticateAPIToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticateAPITokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertifierServer).AuthenticateAPIToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.certifier.Certifier/AuthenticateAPIToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertifierServer).AuthenticateAPIToken(ctx, req.(*AuthenticateAPITokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Certifier_RevokeAPIToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPITokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertifierServer).RevokeAPIToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.certifier.Certifier/RevokeAPIToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertifierServer).RevokeAPIToken(ctx, req.(*RevokeAPITokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Certifier_ListAPITokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(protos.Identity)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertifierServer).ListAPITokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.certifier.Certifier/ListAPITokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertifierServer).ListAPITokens(ctx, req.(*protos.Identity))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Certifier_CollectGarbage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(protos.Void)
 	if err := dec(in); err != nil {
@@ -695,6 +1640,18 @@ var _Certifier_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RevokeCertificate",
 			Handler:    _Certifier_RevokeCertificate_Handler,
 		},
+		{
+			MethodName: "RevokeCertificateWithReason",
+			Handler:    _Certifier_RevokeCertificateWithReason_Handler,
+		},
+		{
+			MethodName: "GetCRL",
+			Handler:    _Certifier_GetCRL_Handler,
+		},
+		{
+			MethodName: "GetOCSPResponse",
+			Handler:    _Certifier_GetOCSPResponse_Handler,
+		},
 		{
 			MethodName: "AddCertificate",
 			Handler:    _Certifier_AddCertificate_Handler,
@@ -711,6 +1668,22 @@ var _Certifier_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetAll",
 			Handler:    _Certifier_GetAll_Handler,
 		},
+		{
+			MethodName: "IssueAPIToken",
+			Handler:    _Certifier_IssueAPIToken_Handler,
+		},
+		{
+			MethodName: "AuthenticateAPIToken",
+			Handler:    _Certifier_AuthenticateAPIToken_Handler,
+		},
+		{
+			MethodName: "RevokeAPIToken",
+			Handler:    _Certifier_RevokeAPIToken_Handler,
+		},
+		{
+			MethodName: "ListAPITokens",
+			Handler:    _Certifier_ListAPITokens_Handler,
+		},
 		{
 			MethodName: "CollectGarbage",
 			Handler:    _Certifier_CollectGarbage_Handler,
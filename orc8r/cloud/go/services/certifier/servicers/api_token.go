@@ -0,0 +1,218 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/identity"
+	certprotos "magma/orc8r/cloud/go/services/certifier/protos"
+	merrors "magma/orc8r/lib/go/errors"
+	"magma/orc8r/lib/go/protos"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// apiTokenSep separates a token's ID from its secret in its bearer
+	// credential.
+	apiTokenSep = "."
+
+	apiTokenIDLen     = 16
+	apiTokenSecretLen = 32
+)
+
+// IssueAPIToken issues a new API token for an operator, returning its bearer
+// credential. Only the hash of the token's secret is stored.
+func (srv *CertifierServer) IssueAPIToken(ctx context.Context, req *certprotos.IssueAPITokenRequest) (*certprotos.IssuedAPIToken, error) {
+	if req == nil || req.Operator == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid API token request")
+	}
+	if !identity.IsOperator(req.Operator) {
+		return nil, status.Errorf(codes.InvalidArgument, "API tokens can only be issued to operators")
+	}
+	ttl := DefaultAPITokenTTL
+	if req.Ttl != nil {
+		var err error
+		ttl, err = ptypes.Duration(req.Ttl)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid API token TTL: %s", err)
+		}
+	}
+	if ttl <= 0 || ttl > MaxAPITokenTTL {
+		return nil, status.Errorf(codes.InvalidArgument, "API token TTL must be positive and at most %s", MaxAPITokenTTL)
+	}
+
+	idBytes, err := randomBytes(apiTokenIDLen)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to generate API token ID: %s", err)
+	}
+	secretBytes, err := randomBytes(apiTokenSecretLen)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to generate API token secret: %s", err)
+	}
+	id := hex.EncodeToString(idBytes)
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	now := clock.Now().UTC()
+	issuedAt, _ := ptypes.TimestampProto(now)
+	expiresAt, _ := ptypes.TimestampProto(now.Add(ttl))
+	token := &certprotos.APIToken{
+		Id:         id,
+		Operator:   req.Operator,
+		Networks:   req.Networks,
+		Writable:   req.Writable,
+		IssuedAt:   issuedAt,
+		ExpiresAt:  expiresAt,
+		SecretHash: hashAPITokenSecret(secret),
+	}
+	err = srv.store.PutAPIToken(token)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to store API token: %s", err)
+	}
+	return &certprotos.IssuedAPIToken{Token: withoutSecret(token), Bearer: id + apiTokenSep + secret}, nil
+}
+
+// AuthenticateAPIToken returns the API token of a bearer credential.
+func (srv *CertifierServer) AuthenticateAPIToken(ctx context.Context, req *certprotos.AuthenticateAPITokenRequest) (*certprotos.APIToken, error) {
+	id, secret, ok := splitBearer(req.GetBearer())
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "Malformed API token")
+	}
+	token, err := srv.store.GetAPIToken(id)
+	if err == merrors.ErrNotFound {
+		return nil, status.Errorf(codes.Unauthenticated, "Unknown API token")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to load API token: %s", err)
+	}
+	if subtle.ConstantTimeCompare(hashAPITokenSecret(secret), token.SecretHash) != 1 {
+		return nil, status.Errorf(codes.Unauthenticated, "Invalid API token")
+	}
+	if isExpired(token, clock.Now()) {
+		return nil, status.Errorf(codes.Unauthenticated, "API token has expired")
+	}
+	return withoutSecret(token), nil
+}
+
+// RevokeAPIToken deletes an API token of an operator.
+// Tokens of other operators are reported as not found.
+func (srv *CertifierServer) RevokeAPIToken(ctx context.Context, req *certprotos.RevokeAPITokenRequest) (*protos.Void, error) {
+	if req == nil || req.Operator == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid revoke API token request")
+	}
+	token, err := srv.store.GetAPIToken(req.Id)
+	if err == merrors.ErrNotFound || (err == nil && token.Operator.HashString() != req.Operator.HashString()) {
+		return nil, status.Errorf(codes.NotFound, "API token %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to load API token: %s", err)
+	}
+	err = srv.store.DeleteAPIToken(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to delete API token: %s", err)
+	}
+	return &protos.Void{}, nil
+}
+
+// ListAPITokens returns the unexpired API tokens of an operator, in order of
+// issuance.
+func (srv *CertifierServer) ListAPITokens(ctx context.Context, operator *protos.Identity) (*certprotos.APITokens, error) {
+	if operator == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Nil operator")
+	}
+	tokens, err := srv.store.GetAllAPITokens()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to load API tokens: %s", err)
+	}
+	now := clock.Now()
+	res := &certprotos.APITokens{}
+	for _, token := range tokens {
+		if token.Operator.HashString() == operator.HashString() && !isExpired(token, now) {
+			res.Tokens = append(res.Tokens, withoutSecret(token))
+		}
+	}
+	sort.Slice(res.Tokens, func(i, j int) bool {
+		ti, tj := res.Tokens[i], res.Tokens[j]
+		if ti.IssuedAt.GetSeconds() != tj.IssuedAt.GetSeconds() {
+			return ti.IssuedAt.GetSeconds() < tj.IssuedAt.GetSeconds()
+		}
+		return ti.Id < tj.Id
+	})
+	return res, nil
+}
+
+// collectAPITokenGarbage removes expired API tokens.
+func (srv *CertifierServer) collectAPITokenGarbage() (int, error) {
+	tokens, err := srv.store.GetAllAPITokens()
+	if err != nil {
+		return 0, err
+	}
+	var multiErr *merrors.Multi
+	count := 0
+	now := clock.Now()
+	for id, token := range tokens {
+		if isExpired(token, now) {
+			err = srv.store.DeleteAPIToken(id)
+			if err != nil {
+				multiErr = multiErr.AddFmt(err, "'%s' delete API token error:", id)
+			} else {
+				count += 1
+			}
+		}
+	}
+	return count, multiErr.AsError()
+}
+
+func splitBearer(bearer string) (string, string, bool) {
+	parts := strings.Split(bearer, apiTokenSep)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+func hashAPITokenSecret(secret string) []byte {
+	hash := sha256.Sum256([]byte(secret))
+	return hash[:]
+}
+
+func isExpired(token *certprotos.APIToken, now time.Time) bool {
+	expiresAt, err := ptypes.Timestamp(token.ExpiresAt)
+	return err != nil || !now.Before(expiresAt)
+}
+
+func withoutSecret(token *certprotos.APIToken) *certprotos.APIToken {
+	ret := proto.Clone(token).(*certprotos.APIToken)
+	ret.SecretHash = nil
+	return ret
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	return b, err
+}
@@ -38,13 +38,19 @@ import (
 )
 
 var (
-	NumTrialsForSn      int
-	CollectGarbageAfter time.Duration // remove cert if expired for certain amount of time
+	NumTrialsForSn           int
+	CollectGarbageAfter      time.Duration // remove cert if expired for certain amount of time
+	RevocationStatusValidity time.Duration // validity of published CRLs and OCSP responses
+	DefaultAPITokenTTL       time.Duration // validity of API tokens issued without a requested TTL
+	MaxAPITokenTTL           time.Duration // max validity of API tokens
 )
 
 func init() {
 	NumTrialsForSn = 1
 	CollectGarbageAfter = time.Hour * 24
+	RevocationStatusValidity = time.Hour
+	DefaultAPITokenTTL = time.Hour
+	MaxAPITokenTTL = time.Hour * 24
 }
 
 type CAInfo struct {
@@ -307,19 +313,7 @@ func (srv *CertifierServer) GetIdentity(
 func (srv *CertifierServer) RevokeCertificate(
 	ctx context.Context, snMsg *protos.Certificate_SN) (*protos.Void, error) {
 
-	var certSN string
-	if snMsg != nil {
-		certSN = strings.TrimLeft(snMsg.Sn, "0")
-	}
-	_, err := srv.store.GetCertInfo(certSN)
-	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "Cannot find certificate with SN: %s", certSN)
-	}
-	err = srv.store.DeleteCertInfo(certSN)
-	if err != nil {
-		return nil, status.Errorf(codes.Aborted, "Failed to delete certificate: %s", err)
-	}
-	return &protos.Void{}, nil
+	return srv.revokeCertificate(snMsg, certprotos.RevocationReason_UNSPECIFIED)
 }
 
 func (srv *CertifierServer) AddCertificate(ctx context.Context, req *certprotos.AddCertRequest) (*protos.Void, error) {
@@ -443,6 +437,16 @@ func (srv *CertifierServer) CollectGarbageImpl(ctx context.Context) (int, error)
 			}
 		}
 	}
+	revocationCount, err := srv.collectRevocationGarbage()
+	count += revocationCount
+	if err != nil {
+		multiErr = multiErr.Add(err)
+	}
+	tokenCount, err := srv.collectAPITokenGarbage()
+	count += tokenCount
+	if err != nil {
+		multiErr = multiErr.Add(err)
+	}
 	if multiErr.AsError() != nil {
 		glog.Errorf("Failed to delete certificate[s]: %v", multiErr)
 		return count, status.Error(codes.Internal, multiErr.Error())
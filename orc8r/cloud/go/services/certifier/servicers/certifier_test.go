@@ -19,17 +19,25 @@ import (
 	"time"
 
 	"magma/orc8r/cloud/go/blobstore"
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/identity"
+	certprotos "magma/orc8r/cloud/go/services/certifier/protos"
 	"magma/orc8r/cloud/go/services/certifier/servicers"
 	"magma/orc8r/cloud/go/services/certifier/storage"
 	"magma/orc8r/cloud/go/sqorc"
 	"magma/orc8r/lib/go/protos"
+	"magma/orc8r/lib/go/security/cert"
 	certifierTestUtils "magma/orc8r/lib/go/security/csr"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestCertifierBlobstore(t *testing.T) {
@@ -42,6 +50,210 @@ func TestCertifierBlobstore(t *testing.T) {
 	testCertifierImpl(t, store)
 }
 
+func TestCertifierRevocation(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	fact := blobstore.NewEntStorage(storage.CertifierTableBlobstore, db, sqorc.GetSqlBuilder())
+	require.NoError(t, fact.InitializeFactory())
+	store := storage.NewCertifierBlobstore(fact)
+
+	caCert, caKey, err := certifierTestUtils.CreateSignedCertAndPrivKey(time.Hour * 24 * 10)
+	require.NoError(t, err)
+	vpnCert, vpnKey, err := certifierTestUtils.CreateSignedCertAndPrivKey(time.Hour * 24 * 10)
+	require.NoError(t, err)
+	caMap := map[protos.CertType]*servicers.CAInfo{
+		protos.CertType_DEFAULT: {caCert, caKey},
+		protos.CertType_VPN:     {vpnCert, vpnKey},
+	}
+	srv, err := servicers.NewCertifierServer(store, caMap)
+	require.NoError(t, err)
+
+	signCert := func() *x509.Certificate {
+		csrMsg, err := certifierTestUtils.CreateCSR(time.Hour*24, "cn", "cn")
+		require.NoError(t, err)
+		certMsg, err := srv.SignAddCertificate(ctx, csrMsg)
+		require.NoError(t, err)
+		c, err := x509.ParseCertificate(certMsg.CertDer)
+		require.NoError(t, err)
+		return c
+	}
+	getOCSPStatus := func(c *x509.Certificate, issuer *x509.Certificate) *ocsp.Response {
+		reqDER, err := ocsp.CreateRequest(c, issuer, nil)
+		require.NoError(t, err)
+		resp, err := srv.GetOCSPResponse(ctx, &certprotos.OCSPRequest{RequestDer: reqDER})
+		require.NoError(t, err)
+		parsed, err := ocsp.ParseResponseForCert(resp.ResponseDer, c, issuer)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	good := signCert()
+	revoked := signCert()
+	revokedUnspecified := signCert()
+
+	// Empty CRL, good OCSP status
+	crl, err := srv.GetCRL(ctx, &certprotos.GetCRLRequest{CertType: protos.CertType_DEFAULT})
+	require.NoError(t, err)
+	parsedCRL, err := x509.ParseCRL(crl.CrlDer)
+	require.NoError(t, err)
+	assert.NoError(t, caCert.CheckCRLSignature(parsedCRL))
+	assert.Empty(t, parsedCRL.TBSCertList.RevokedCertificates)
+	assert.Equal(t, ocsp.Good, getOCSPStatus(revoked, caCert).Status)
+
+	// Revoke
+	_, err = srv.RevokeCertificateWithReason(ctx, &certprotos.RevokeCertificateRequest{
+		Sn:     &protos.Certificate_SN{Sn: cert.SerialToString(revoked.SerialNumber)},
+		Reason: certprotos.RevocationReason_KEY_COMPROMISE,
+	})
+	require.NoError(t, err)
+	_, err = srv.RevokeCertificate(ctx, &protos.Certificate_SN{Sn: cert.SerialToString(revokedUnspecified.SerialNumber)})
+	require.NoError(t, err)
+	_, err = srv.GetIdentity(ctx, &protos.Certificate_SN{Sn: cert.SerialToString(revoked.SerialNumber)})
+	assert.Error(t, err)
+	_, err = srv.RevokeCertificate(ctx, &protos.Certificate_SN{Sn: cert.SerialToString(revoked.SerialNumber)})
+	assert.Error(t, err)
+
+	// CRL lists revoked certs of the CA
+	crl, err = srv.GetCRL(ctx, &certprotos.GetCRLRequest{CertType: protos.CertType_DEFAULT})
+	require.NoError(t, err)
+	parsedCRL, err = x509.ParseCRL(crl.CrlDer)
+	require.NoError(t, err)
+	assert.NoError(t, caCert.CheckCRLSignature(parsedCRL))
+	require.Len(t, parsedCRL.TBSCertList.RevokedCertificates, 2)
+	for _, entry := range parsedCRL.TBSCertList.RevokedCertificates {
+		switch entry.SerialNumber.Cmp(revoked.SerialNumber) {
+		case 0:
+			require.Len(t, entry.Extensions, 1)
+			assert.Equal(t, []byte{0x0a, 0x01, 0x01}, entry.Extensions[0].Value)
+		default:
+			assert.Equal(t, 0, entry.SerialNumber.Cmp(revokedUnspecified.SerialNumber))
+			assert.Empty(t, entry.Extensions)
+		}
+	}
+	crl, err = srv.GetCRL(ctx, &certprotos.GetCRLRequest{CertType: protos.CertType_VPN})
+	require.NoError(t, err)
+	parsedCRL, err = x509.ParseCRL(crl.CrlDer)
+	require.NoError(t, err)
+	assert.NoError(t, vpnCert.CheckCRLSignature(parsedCRL))
+	assert.Empty(t, parsedCRL.TBSCertList.RevokedCertificates)
+
+	// OCSP
+	assert.Equal(t, ocsp.Good, getOCSPStatus(good, caCert).Status)
+	status := getOCSPStatus(revoked, caCert)
+	assert.Equal(t, ocsp.Revoked, status.Status)
+	assert.Equal(t, ocsp.KeyCompromise, status.RevocationReason)
+	assert.Equal(t, ocsp.Unspecified, getOCSPStatus(revokedUnspecified, caCert).RevocationReason)
+
+	// Certs unknown to the issuing CA are of unknown status
+	reqDER, err := ocsp.CreateRequest(good, vpnCert, nil)
+	require.NoError(t, err)
+	resp, err := srv.GetOCSPResponse(ctx, &certprotos.OCSPRequest{RequestDer: reqDER})
+	require.NoError(t, err)
+	parsed, err := ocsp.ParseResponse(resp.ResponseDer, vpnCert)
+	require.NoError(t, err)
+	assert.Equal(t, ocsp.Unknown, parsed.Status)
+
+	// Unknown issuer and malformed requests
+	otherCA, _, err := certifierTestUtils.CreateSignedCertAndPrivKey(time.Hour * 24 * 10)
+	require.NoError(t, err)
+	reqDER, err = ocsp.CreateRequest(good, otherCA, nil)
+	require.NoError(t, err)
+	resp, err = srv.GetOCSPResponse(ctx, &certprotos.OCSPRequest{RequestDer: reqDER})
+	require.NoError(t, err)
+	assert.Equal(t, ocsp.UnauthorizedErrorResponse, resp.ResponseDer)
+	resp, err = srv.GetOCSPResponse(ctx, &certprotos.OCSPRequest{RequestDer: []byte("malformed")})
+	require.NoError(t, err)
+	assert.Equal(t, ocsp.MalformedRequestErrorResponse, resp.ResponseDer)
+
+	// Revocations are garbage collected along with expired certs
+	servicers.CollectGarbageAfter = -time.Hour * 48
+	defer func() { servicers.CollectGarbageAfter = time.Hour * 24 }()
+	_, err = srv.CollectGarbage(ctx, nil)
+	require.NoError(t, err)
+	revocations, err := store.GetAllRevocationInfo()
+	require.NoError(t, err)
+	assert.Empty(t, revocations)
+}
+
+func TestCertifierAPITokens(t *testing.T) {
+	ctx := context.Background()
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	fact := blobstore.NewEntStorage(storage.CertifierTableBlobstore, db, sqorc.GetSqlBuilder())
+	require.NoError(t, fact.InitializeFactory())
+	store := storage.NewCertifierBlobstore(fact)
+
+	caCert, caKey, err := certifierTestUtils.CreateSignedCertAndPrivKey(time.Hour * 24 * 10)
+	require.NoError(t, err)
+	srv, err := servicers.NewCertifierServer(store, map[protos.CertType]*servicers.CAInfo{protos.CertType_DEFAULT: {caCert, caKey}})
+	require.NoError(t, err)
+
+	bob := identity.NewOperator("bob")
+	alice := identity.NewOperator("alice")
+
+	// Only operators, within the max TTL
+	_, err = srv.IssueAPIToken(ctx, &certprotos.IssueAPITokenRequest{Operator: identity.NewNetwork("n0")})
+	assert.Error(t, err)
+	_, err = srv.IssueAPIToken(ctx, &certprotos.IssueAPITokenRequest{Operator: bob, Ttl: ptypes.DurationProto(servicers.MaxAPITokenTTL + time.Second)})
+	assert.Error(t, err)
+
+	// Issue and authenticate
+	issued, err := srv.IssueAPIToken(ctx, &certprotos.IssueAPITokenRequest{Operator: bob, Networks: []string{"n0"}, Writable: true})
+	require.NoError(t, err)
+	assert.Empty(t, issued.Token.SecretHash)
+	expiresAt, err := ptypes.Timestamp(issued.Token.ExpiresAt)
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1000, 0).Add(servicers.DefaultAPITokenTTL).UTC(), expiresAt)
+
+	token, err := srv.AuthenticateAPIToken(ctx, &certprotos.AuthenticateAPITokenRequest{Bearer: issued.Bearer})
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(issued.Token, token))
+	assert.True(t, proto.Equal(bob, token.Operator))
+	assert.Equal(t, []string{"n0"}, token.Networks)
+	assert.True(t, token.Writable)
+
+	// Malformed, unknown, and wrong secret
+	for _, bearer := range []string{"", "malformed", issued.Token.Id + ".wrong_secret", "unknown." + issued.Bearer[len(issued.Token.Id)+1:]} {
+		_, err = srv.AuthenticateAPIToken(ctx, &certprotos.AuthenticateAPITokenRequest{Bearer: bearer})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), bearer)
+	}
+
+	// List only the operator's own tokens
+	short, err := srv.IssueAPIToken(ctx, &certprotos.IssueAPITokenRequest{Operator: bob, Ttl: ptypes.DurationProto(time.Minute)})
+	require.NoError(t, err)
+	_, err = srv.IssueAPIToken(ctx, &certprotos.IssueAPITokenRequest{Operator: alice})
+	require.NoError(t, err)
+	tokens, err := srv.ListAPITokens(ctx, bob)
+	require.NoError(t, err)
+	assert.Len(t, tokens.Tokens, 2)
+
+	// Operators can't revoke others' tokens
+	_, err = srv.RevokeAPIToken(ctx, &certprotos.RevokeAPITokenRequest{Operator: alice, Id: issued.Token.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = srv.RevokeAPIToken(ctx, &certprotos.RevokeAPITokenRequest{Operator: bob, Id: issued.Token.Id})
+	require.NoError(t, err)
+	_, err = srv.AuthenticateAPIToken(ctx, &certprotos.AuthenticateAPITokenRequest{Bearer: issued.Bearer})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Expired tokens are rejected, unlisted, and collected
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(time.Minute))
+	_, err = srv.AuthenticateAPIToken(ctx, &certprotos.AuthenticateAPITokenRequest{Bearer: short.Bearer})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	tokens, err = srv.ListAPITokens(ctx, bob)
+	require.NoError(t, err)
+	assert.Empty(t, tokens.Tokens)
+	count, err := srv.CollectGarbageImpl(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	all, err := store.GetAllAPITokens()
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
 func testCertifierImpl(t *testing.T, store storage.CertifierStorage) {
 	ctx := context.Background()
 
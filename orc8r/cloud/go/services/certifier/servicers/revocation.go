@@ -0,0 +1,258 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicers
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"magma/orc8r/cloud/go/clock"
+	certprotos "magma/orc8r/cloud/go/services/certifier/protos"
+	merrors "magma/orc8r/lib/go/errors"
+	"magma/orc8r/lib/go/protos"
+	"magma/orc8r/lib/go/security/cert"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/crypto/ocsp"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// oidExtensionReasonCode is the OID of the CRL entry reason code extension,
+// per RFC 5280, section 5.3.1.
+var oidExtensionReasonCode = asn1.ObjectIdentifier{2, 5, 29, 21}
+
+func (srv *CertifierServer) RevokeCertificateWithReason(
+	ctx context.Context, req *certprotos.RevokeCertificateRequest) (*protos.Void, error) {
+
+	if req == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid revoke certificate request")
+	}
+	return srv.revokeCertificate(req.Sn, req.Reason)
+}
+
+// GetCRL returns a CRL listing the revoked certificates of the requested CA,
+// signed by the CA.
+func (srv *CertifierServer) GetCRL(ctx context.Context, req *certprotos.GetCRLRequest) (*certprotos.CRL, error) {
+	if req == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid CRL request")
+	}
+	ca, ok := srv.CAs[req.CertType]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "No CA found for given cert type: %s", req.CertType.String())
+	}
+
+	revocations, err := srv.store.GetAllRevocationInfo()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get revoked certificates: %s", err)
+	}
+	var revoked []pkix.RevokedCertificate
+	for sn, revocation := range revocations {
+		if revocation.CertInfo.GetCertType() != req.CertType {
+			continue
+		}
+		entry, err := makeCRLEntry(sn, revocation)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to make CRL entry for certificate SN %s: %s", sn, err)
+		}
+		revoked = append(revoked, entry)
+	}
+	sort.Slice(revoked, func(i, j int) bool { return revoked[i].SerialNumber.Cmp(revoked[j].SerialNumber) < 0 })
+
+	now := clock.Now().UTC()
+	crlDER, err := ca.Cert.CreateCRL(rand.Reader, ca.PrivKey, revoked, now, now.Add(RevocationStatusValidity))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to sign CRL: %s", err)
+	}
+	return &certprotos.CRL{CrlDer: crlDER}, nil
+}
+
+// GetOCSPResponse returns the signed status of the certificate in an OCSP
+// request.
+// Per RFC 6960, malformed requests, and requests for certificates issued by
+// unknown CAs, receive unsigned error responses rather than gRPC errors.
+func (srv *CertifierServer) GetOCSPResponse(ctx context.Context, req *certprotos.OCSPRequest) (*certprotos.OCSPResponse, error) {
+	if req == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid OCSP request")
+	}
+	ocspReq, err := ocsp.ParseRequest(req.RequestDer)
+	if err != nil {
+		return &certprotos.OCSPResponse{ResponseDer: ocsp.MalformedRequestErrorResponse}, nil
+	}
+	certType, ca, ok := srv.getOCSPIssuer(ocspReq)
+	if !ok {
+		return &certprotos.OCSPResponse{ResponseDer: ocsp.UnauthorizedErrorResponse}, nil
+	}
+	signer, ok := ca.PrivKey.(crypto.Signer)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "CA private key for cert type %s cannot sign", certType.String())
+	}
+
+	now := clock.Now().UTC()
+	template := ocsp.Response{
+		SerialNumber: ocspReq.SerialNumber,
+		IssuerHash:   ocspReq.HashAlgorithm,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(RevocationStatusValidity),
+	}
+	err = srv.setOCSPStatus(&template, cert.SerialToString(ocspReq.SerialNumber), certType)
+	if err != nil {
+		glog.Errorf("Failed to get status of certificate SN %s: %s", cert.SerialToString(ocspReq.SerialNumber), err)
+		return &certprotos.OCSPResponse{ResponseDer: ocsp.TryLaterErrorResponse}, nil
+	}
+
+	respDER, err := ocsp.CreateResponse(ca.Cert, ca.Cert, template, signer)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to sign OCSP response: %s", err)
+	}
+	return &certprotos.OCSPResponse{ResponseDer: respDER}, nil
+}
+
+func (srv *CertifierServer) revokeCertificate(snMsg *protos.Certificate_SN, reason certprotos.RevocationReason) (*protos.Void, error) {
+	var certSN string
+	if snMsg != nil {
+		certSN = strings.TrimLeft(snMsg.Sn, "0")
+	}
+	certInfo, err := srv.store.GetCertInfo(certSN)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Cannot find certificate with SN: %s", certSN)
+	}
+	revokedAt, err := ptypes.TimestampProto(clock.Now())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Invalid revocation time: %s", err)
+	}
+	revocation := &certprotos.RevocationInfo{CertInfo: certInfo, RevokedAt: revokedAt, Reason: reason}
+	err = srv.store.RevokeCertInfo(certSN, revocation)
+	if err != nil {
+		return nil, status.Errorf(codes.Aborted, "Failed to revoke certificate: %s", err)
+	}
+	return &protos.Void{}, nil
+}
+
+// getOCSPIssuer returns the CA matching the issuer name and key hashes of an
+// OCSP request.
+func (srv *CertifierServer) getOCSPIssuer(req *ocsp.Request) (protos.CertType, *CAInfo, bool) {
+	if !req.HashAlgorithm.Available() {
+		return 0, nil, false
+	}
+	for certType, ca := range srv.CAs {
+		var publicKeyInfo struct {
+			Algorithm pkix.AlgorithmIdentifier
+			PublicKey asn1.BitString
+		}
+		_, err := asn1.Unmarshal(ca.Cert.RawSubjectPublicKeyInfo, &publicKeyInfo)
+		if err != nil {
+			glog.Errorf("Failed to parse public key of CA for cert type %s: %s", certType.String(), err)
+			continue
+		}
+		nameHash := req.HashAlgorithm.New()
+		nameHash.Write(ca.Cert.RawSubject)
+		keyHash := req.HashAlgorithm.New()
+		keyHash.Write(publicKeyInfo.PublicKey.RightAlign())
+		if bytes.Equal(nameHash.Sum(nil), req.IssuerNameHash) && bytes.Equal(keyHash.Sum(nil), req.IssuerKeyHash) {
+			return certType, ca, true
+		}
+	}
+	return 0, nil, false
+}
+
+// setOCSPStatus sets the status of the certificate with the passed serial
+// number, issued by the CA of the passed cert type.
+// Certificates which aren't tracked are of unknown status.
+func (srv *CertifierServer) setOCSPStatus(template *ocsp.Response, sn string, certType protos.CertType) error {
+	template.Status = ocsp.Unknown
+
+	revocation, err := srv.store.GetRevocationInfo(sn)
+	if err == nil {
+		if revocation.CertInfo.GetCertType() == certType {
+			template.Status = ocsp.Revoked
+			template.RevokedAt, _ = ptypes.Timestamp(revocation.RevokedAt)
+			template.RevocationReason = int(revocation.Reason)
+		}
+		return nil
+	}
+	if err != merrors.ErrNotFound {
+		return err
+	}
+
+	certInfo, err := srv.store.GetCertInfo(sn)
+	if err == merrors.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if certInfo.CertType == certType {
+		template.Status = ocsp.Good
+	}
+	return nil
+}
+
+// collectRevocationGarbage removes the revocation info of certificates which
+// would have been garbage collected had they not been revoked.
+// Expired certificates are rejected by relying parties regardless, so need
+// not be listed.
+func (srv *CertifierServer) collectRevocationGarbage() (int, error) {
+	revocations, err := srv.store.GetAllRevocationInfo()
+	if err != nil {
+		return 0, err
+	}
+	var multiErr *merrors.Multi
+	count := 0
+	for sn, revocation := range revocations {
+		notAfter, _ := ptypes.Timestamp(revocation.CertInfo.GetNotAfter())
+		notAfter = notAfter.Add(CollectGarbageAfter)
+		if clock.Now().UTC().After(notAfter) {
+			err = srv.store.DeleteRevocationInfo(sn)
+			if err != nil {
+				multiErr = multiErr.AddFmt(err, "'%s' delete revocation error:", sn)
+			} else {
+				count += 1
+			}
+		}
+	}
+	return count, multiErr.AsError()
+}
+
+func makeCRLEntry(sn string, revocation *certprotos.RevocationInfo) (pkix.RevokedCertificate, error) {
+	serialNumber, ok := new(big.Int).SetString(sn, 16)
+	if !ok {
+		return pkix.RevokedCertificate{}, fmt.Errorf("invalid serial number")
+	}
+	revokedAt, err := ptypes.Timestamp(revocation.RevokedAt)
+	if err != nil {
+		return pkix.RevokedCertificate{}, err
+	}
+	entry := pkix.RevokedCertificate{SerialNumber: serialNumber, RevocationTime: revokedAt}
+
+	// Per RFC 5280, the reason code extension should be absent rather than
+	// unspecified
+	if revocation.Reason != certprotos.RevocationReason_UNSPECIFIED {
+		reasonCode, err := asn1.Marshal(asn1.Enumerated(revocation.Reason))
+		if err != nil {
+			return pkix.RevokedCertificate{}, err
+		}
+		entry.Extensions = []pkix.Extension{{Id: oidExtensionReasonCode, Value: reasonCode}}
+	}
+	return entry, nil
+}
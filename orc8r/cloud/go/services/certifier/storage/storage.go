@@ -38,4 +38,33 @@ type CertifierStorage interface {
 	// DeleteCertInfo removes the serial number and its certificate info.
 	// Returns success even when nothing is deleted (i.e. serial number not found).
 	DeleteCertInfo(serialNumber string) error
+
+	// RevokeCertInfo atomically removes the serial number's certificate info
+	// and records its revocation.
+	RevokeCertInfo(serialNumber string, revocation *protos.RevocationInfo) error
+
+	// GetRevocationInfo returns the revocation info associated with the serial number.
+	// If not found, returns ErrNotFound from magma/orc8r/lib/go/errors.
+	GetRevocationInfo(serialNumber string) (*protos.RevocationInfo, error)
+
+	// GetAllRevocationInfo returns a map of all revoked serial numbers to their revocation info.
+	GetAllRevocationInfo() (map[string]*protos.RevocationInfo, error)
+
+	// DeleteRevocationInfo removes the serial number's revocation info.
+	// Returns success even when nothing is deleted (i.e. serial number not found).
+	DeleteRevocationInfo(serialNumber string) error
+
+	// PutAPIToken stores the API token, keyed by its ID.
+	PutAPIToken(token *protos.APIToken) error
+
+	// GetAPIToken returns the API token with the passed ID.
+	// If not found, returns ErrNotFound from magma/orc8r/lib/go/errors.
+	GetAPIToken(id string) (*protos.APIToken, error)
+
+	// GetAllAPITokens returns a map of all token IDs to their API tokens.
+	GetAllAPITokens() (map[string]*protos.APIToken, error)
+
+	// DeleteAPIToken removes the API token with the passed ID.
+	// Returns success even when nothing is deleted (i.e. token not found).
+	DeleteAPIToken(id string) error
 }
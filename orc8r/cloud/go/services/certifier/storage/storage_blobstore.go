@@ -30,6 +30,12 @@ const (
 	// CertInfoType is the type of CertInfo used in blobstore type fields.
 	CertInfoType = "certificate_info"
 
+	// RevocationInfoType is the type of RevocationInfo used in blobstore type fields.
+	RevocationInfoType = "revocation_info"
+
+	// APITokenType is the type of APIToken used in blobstore type fields.
+	APITokenType = "api_token"
+
 	// Blobstore needs a network ID, but certifier is network-agnostic so we
 	// will use a placeholder value.
 	placeholderNetworkID = "placeholder_network"
@@ -168,3 +174,202 @@ func (c *certifierBlobstore) DeleteCertInfo(serialNumber string) error {
 
 	return store.Commit()
 }
+
+func (c *certifierBlobstore) RevokeCertInfo(serialNumber string, revocation *protos.RevocationInfo) error {
+	store, err := c.factory.StartTransaction(nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer store.Rollback()
+
+	marshaledRevocation, err := proto.Marshal(revocation)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal revocation info")
+	}
+
+	tk := storage.TypeAndKey{Type: CertInfoType, Key: serialNumber}
+	err = store.Delete(placeholderNetworkID, []storage.TypeAndKey{tk})
+	if err != nil {
+		return errors.Wrap(err, "failed to delete certificate info")
+	}
+	blob := blobstore.Blob{Type: RevocationInfoType, Key: serialNumber, Value: marshaledRevocation}
+	err = store.CreateOrUpdate(placeholderNetworkID, blobstore.Blobs{blob})
+	if err != nil {
+		return errors.Wrap(err, "failed to put revocation info")
+	}
+
+	return store.Commit()
+}
+
+func (c *certifierBlobstore) GetRevocationInfo(serialNumber string) (*protos.RevocationInfo, error) {
+	store, err := c.factory.StartTransaction(&storage.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start transaction")
+	}
+	defer store.Rollback()
+
+	blob, err := store.Get(placeholderNetworkID, storage.TypeAndKey{Type: RevocationInfoType, Key: serialNumber})
+	if err == merrors.ErrNotFound {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get revocation info")
+	}
+
+	info := &protos.RevocationInfo{}
+	err = proto.Unmarshal(blob.Value, info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal revocation info")
+	}
+
+	return info, store.Commit()
+}
+
+func (c *certifierBlobstore) GetAllRevocationInfo() (map[string]*protos.RevocationInfo, error) {
+	infos := map[string]*protos.RevocationInfo{}
+
+	store, err := c.factory.StartTransaction(&storage.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start transaction")
+	}
+	defer store.Rollback()
+
+	serialNumbers, err := blobstore.ListKeys(store, placeholderNetworkID, RevocationInfoType)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list keys")
+	}
+
+	if len(serialNumbers) == 0 {
+		return infos, store.Commit()
+	}
+
+	tks := storage.MakeTKs(RevocationInfoType, serialNumbers)
+	blobs, err := store.GetMany(placeholderNetworkID, tks)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get many revocation info")
+	}
+
+	for _, blob := range blobs {
+		info := &protos.RevocationInfo{}
+		err = proto.Unmarshal(blob.Value, info)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal revocation info")
+		}
+		infos[blob.Key] = info
+	}
+
+	return infos, store.Commit()
+}
+
+func (c *certifierBlobstore) DeleteRevocationInfo(serialNumber string) error {
+	store, err := c.factory.StartTransaction(nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer store.Rollback()
+
+	tk := storage.TypeAndKey{Type: RevocationInfoType, Key: serialNumber}
+	err = store.Delete(placeholderNetworkID, []storage.TypeAndKey{tk})
+	if err != nil {
+		return errors.Wrap(err, "failed to delete revocation info")
+	}
+
+	return store.Commit()
+}
+
+func (c *certifierBlobstore) PutAPIToken(token *protos.APIToken) error {
+	store, err := c.factory.StartTransaction(nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer store.Rollback()
+
+	marshaledToken, err := proto.Marshal(token)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal API token")
+	}
+
+	blob := blobstore.Blob{Type: APITokenType, Key: token.Id, Value: marshaledToken}
+	err = store.CreateOrUpdate(placeholderNetworkID, blobstore.Blobs{blob})
+	if err != nil {
+		return errors.Wrap(err, "failed to put API token")
+	}
+
+	return store.Commit()
+}
+
+func (c *certifierBlobstore) GetAPIToken(id string) (*protos.APIToken, error) {
+	store, err := c.factory.StartTransaction(&storage.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start transaction")
+	}
+	defer store.Rollback()
+
+	blob, err := store.Get(placeholderNetworkID, storage.TypeAndKey{Type: APITokenType, Key: id})
+	if err == merrors.ErrNotFound {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get API token")
+	}
+
+	token := &protos.APIToken{}
+	err = proto.Unmarshal(blob.Value, token)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal API token")
+	}
+
+	return token, store.Commit()
+}
+
+func (c *certifierBlobstore) GetAllAPITokens() (map[string]*protos.APIToken, error) {
+	tokens := map[string]*protos.APIToken{}
+
+	store, err := c.factory.StartTransaction(&storage.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start transaction")
+	}
+	defer store.Rollback()
+
+	ids, err := blobstore.ListKeys(store, placeholderNetworkID, APITokenType)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list keys")
+	}
+
+	if len(ids) == 0 {
+		return tokens, store.Commit()
+	}
+
+	tks := storage.MakeTKs(APITokenType, ids)
+	blobs, err := store.GetMany(placeholderNetworkID, tks)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get many API tokens")
+	}
+
+	for _, blob := range blobs {
+		token := &protos.APIToken{}
+		err = proto.Unmarshal(blob.Value, token)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal API token")
+		}
+		tokens[blob.Key] = token
+	}
+
+	return tokens, store.Commit()
+}
+
+func (c *certifierBlobstore) DeleteAPIToken(id string) error {
+	store, err := c.factory.StartTransaction(nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer store.Rollback()
+
+	tk := storage.TypeAndKey{Type: APITokenType, Key: id}
+	err = store.Delete(placeholderNetworkID, []storage.TypeAndKey{tk})
+	if err != nil {
+		return errors.Wrap(err, "failed to delete API token")
+	}
+
+	return store.Commit()
+}
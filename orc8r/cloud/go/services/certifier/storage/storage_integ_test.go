@@ -124,4 +124,61 @@ func testCertifierStorageImpl(t *testing.T, store storage.CertifierStorage) {
 	assert.True(t, proto.Equal(infos[sn0], info0))
 	assert.True(t, proto.Equal(infos[sn1], info1))
 	assert.True(t, proto.Equal(infos[sn2], info2))
+
+	// Revoke info0 -- cert info removed, revocation info added
+	revocation0 := &protos.RevocationInfo{
+		CertInfo:  info0,
+		RevokedAt: &timestamp.Timestamp{Seconds: 0x5555},
+		Reason:    protos.RevocationReason_KEY_COMPROMISE,
+	}
+	err = store.RevokeCertInfo(sn0, revocation0)
+	assert.NoError(t, err)
+	_, err = store.GetCertInfo(sn0)
+	assert.EqualError(t, err, merrors.ErrNotFound.Error())
+	revocation, err := store.GetRevocationInfo(sn0)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(revocation, revocation0))
+	_, err = store.GetRevocationInfo(sn1)
+	assert.EqualError(t, err, merrors.ErrNotFound.Error())
+	revocations, err := store.GetAllRevocationInfo()
+	assert.NoError(t, err)
+	assert.Len(t, revocations, 1)
+	assert.True(t, proto.Equal(revocations[sn0], revocation0))
+
+	// Delete revocation info0
+	err = store.DeleteRevocationInfo(sn0)
+	assert.NoError(t, err)
+	revocations, err = store.GetAllRevocationInfo()
+	assert.NoError(t, err)
+	assert.Len(t, revocations, 0)
+
+	// API tokens -- don't collide with cert or revocation info
+	token0 := &protos.APIToken{
+		Id:         sn1,
+		Networks:   []string{"network_0"},
+		Writable:   true,
+		ExpiresAt:  &timestamp.Timestamp{Seconds: 0x6666},
+		SecretHash: []byte("some_hash"),
+	}
+	_, err = store.GetAPIToken(sn1)
+	assert.EqualError(t, err, merrors.ErrNotFound.Error())
+	err = store.PutAPIToken(token0)
+	assert.NoError(t, err)
+	token, err := store.GetAPIToken(sn1)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(token, token0))
+	info, err = store.GetCertInfo(sn1)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(info, info1))
+	tokens, err := store.GetAllAPITokens()
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 1)
+	assert.True(t, proto.Equal(tokens[sn1], token0))
+
+	// Delete token0
+	err = store.DeleteAPIToken(sn1)
+	assert.NoError(t, err)
+	tokens, err = store.GetAllAPITokens()
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 0)
 }
@@ -0,0 +1,251 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configurator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"magma/orc8r/cloud/go/serde"
+	"magma/orc8r/cloud/go/services/configurator/protos"
+	"magma/orc8r/cloud/go/services/configurator/storage"
+	storage2 "magma/orc8r/cloud/go/storage"
+
+	"github.com/pkg/errors"
+)
+
+// NetworkBundleVersion is the version of the bundle format written by
+// ExportNetwork. ImportNetwork only accepts bundles of this version.
+const NetworkBundleVersion = 1
+
+// NetworkBundle is a portable, declarative description of a network and its
+// entity graph.
+//
+// Configs are embedded as the JSON they're serialized to by their serdes, so
+// a bundle can be reviewed and edited by hand. Internal fields (versions,
+// graph IDs) aren't included.
+type NetworkBundle struct {
+	Version  uint32         `json:"version"`
+	Network  BundleNetwork  `json:"network"`
+	Entities []BundleEntity `json:"entities"`
+}
+
+// BundleNetwork is a network's metadata and configs, keyed by config type.
+type BundleNetwork struct {
+	ID          string                     `json:"id"`
+	Type        string                     `json:"type,omitempty"`
+	Name        string                     `json:"name,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Configs     map[string]json.RawMessage `json:"configs,omitempty"`
+}
+
+// BundleEntity is a network entity and its outgoing associations.
+type BundleEntity struct {
+	Type         string           `json:"type"`
+	Key          string           `json:"key"`
+	Name         string           `json:"name,omitempty"`
+	Description  string           `json:"description,omitempty"`
+	PhysicalID   string           `json:"physical_id,omitempty"`
+	Config       json.RawMessage  `json:"config,omitempty"`
+	Associations []BundleEntityID `json:"associations,omitempty"`
+}
+
+// NonJSONConfigError is returned by ExportNetwork for configs whose serde
+// doesn't serialize them to JSON, and which therefore can't be embedded in a
+// bundle.
+type NonJSONConfigError struct {
+	// Type is the config's type.
+	Type string
+}
+
+func (e *NonJSONConfigError) Error() string {
+	return fmt.Sprintf("config of type %s is not serialized as JSON", e.Type)
+}
+
+// BundleEntityID identifies an entity within a bundle.
+type BundleEntityID struct {
+	Type string `json:"type"`
+	Key  string `json:"key"`
+}
+
+// ExportNetwork serializes the latest state of a network and its entity graph
+// into a bundle. Every config must have a serde registered in the passed
+// registries and must serialize to JSON, otherwise a *NonJSONConfigError is
+// returned, wrapped.
+// If the network is not found, returns ErrNotFound from
+// magma/orc8r/lib/go/errors.
+func ExportNetwork(networkID string, networkSerdes, entitySerdes serde.Registry) (NetworkBundle, error) {
+	client, err := getNBConfiguratorClient()
+	if err != nil {
+		return NetworkBundle{}, err
+	}
+	snapshot, err := client.LoadSnapshot(context.Background(), &protos.LoadSnapshotRequest{NetworkID: networkID})
+	if err != nil {
+		return NetworkBundle{}, mapRevisionError(err)
+	}
+
+	network := snapshot.Network
+	bundle := NetworkBundle{
+		Version: NetworkBundleVersion,
+		Network: BundleNetwork{
+			ID:          network.ID,
+			Type:        network.Type,
+			Name:        network.Name,
+			Description: network.Description,
+		},
+		Entities: make([]BundleEntity, 0, len(snapshot.Entities)),
+	}
+	if len(network.Configs) != 0 {
+		bundle.Network.Configs = map[string]json.RawMessage{}
+	}
+	for typ, config := range network.Configs {
+		exported, err := exportConfig(typ, config, networkSerdes)
+		if err != nil {
+			return NetworkBundle{}, errors.Wrapf(err, "failed to export network config %s", typ)
+		}
+		bundle.Network.Configs[typ] = exported
+	}
+
+	for _, ent := range snapshot.Entities {
+		exported := BundleEntity{
+			Type:        ent.Type,
+			Key:         ent.Key,
+			Name:        ent.Name,
+			Description: ent.Description,
+			PhysicalID:  ent.PhysicalID,
+		}
+		if len(ent.Config) != 0 {
+			exported.Config, err = exportConfig(ent.Type, ent.Config, entitySerdes)
+			if err != nil {
+				return NetworkBundle{}, errors.Wrapf(err, "failed to export entity %s", ent.GetTypeAndKey())
+			}
+		}
+		for _, assoc := range ent.Associations {
+			exported.Associations = append(exported.Associations, BundleEntityID{Type: assoc.Type, Key: assoc.Key})
+		}
+		bundle.Entities = append(bundle.Entities, exported)
+	}
+	return bundle, nil
+}
+
+// ImportNetwork brings a network and its entity graph to the state described
+// by a bundle in a single transaction. The network is created if it doesn't
+// exist, and existing entities which aren't in the bundle are deleted.
+//
+// The bundle is imported into the passed network ID, or the bundle's own
+// network ID if empty, so a bundle exported from one network can be imported
+// into another. Configs are validated against the passed serdes before
+// anything is written.
+//
+// Returns the changes made. If dryRun is set, the import is checked against
+// the store but nothing is written, and the returned changes are the plan.
+func ImportNetwork(networkID string, bundle NetworkBundle, dryRun bool, networkSerdes, entitySerdes serde.Registry) (NetworkDiff, error) {
+	req, err := bundle.toImportRequest(networkID, networkSerdes, entitySerdes)
+	if err != nil {
+		return NetworkDiff{}, errors.Wrap(err, "invalid bundle")
+	}
+	req.DryRun = dryRun
+
+	client, err := getNBConfiguratorClient()
+	if err != nil {
+		return NetworkDiff{}, err
+	}
+	res, err := client.ImportNetwork(context.Background(), req)
+	if err != nil {
+		return NetworkDiff{}, err
+	}
+	return (NetworkDiff{}).fromProto(res, networkSerdes, entitySerdes)
+}
+
+func (nb NetworkBundle) toImportRequest(networkID string, networkSerdes, entitySerdes serde.Registry) (*protos.ImportNetworkRequest, error) {
+	if nb.Version != NetworkBundleVersion {
+		return nil, errors.Errorf("unsupported bundle version %d, expected %d", nb.Version, NetworkBundleVersion)
+	}
+	if networkID == "" {
+		networkID = nb.Network.ID
+	}
+	if networkID == "" {
+		return nil, errors.New("network ID must be non-empty")
+	}
+
+	network := &storage.Network{
+		ID:          networkID,
+		Type:        nb.Network.Type,
+		Name:        nb.Network.Name,
+		Description: nb.Network.Description,
+		Configs:     map[string][]byte{},
+	}
+	for typ, config := range nb.Network.Configs {
+		imported, err := importConfig(typ, config, networkSerdes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid network config %s", typ)
+		}
+		network.Configs[typ] = imported
+	}
+
+	entities := make([]*storage.NetworkEntity, 0, len(nb.Entities))
+	for _, ent := range nb.Entities {
+		tk := storage2.TypeAndKey{Type: ent.Type, Key: ent.Key}
+		imported := &storage.NetworkEntity{
+			Type:        ent.Type,
+			Key:         ent.Key,
+			Name:        ent.Name,
+			Description: ent.Description,
+			PhysicalID:  ent.PhysicalID,
+		}
+		if len(ent.Config) != 0 {
+			config, err := importConfig(ent.Type, ent.Config, entitySerdes)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid config for entity %s", tk)
+			}
+			imported.Config = config
+		}
+		for _, assoc := range ent.Associations {
+			imported.Associations = append(imported.Associations, &storage.EntityID{Type: assoc.Type, Key: assoc.Key})
+		}
+		entities = append(entities, imported)
+	}
+	sort.Slice(entities, func(i, j int) bool { return entities[i].GetTypeAndKey().IsLessThan(entities[j].GetTypeAndKey()) })
+
+	return &protos.ImportNetworkRequest{Network: network, Entities: entities}, nil
+}
+
+// exportConfig checks that a serialized config is understood by its serde
+// and can be embedded in a bundle as JSON.
+func exportConfig(typ string, config []byte, serdes serde.Registry) (json.RawMessage, error) {
+	if _, err := serde.Deserialize(config, typ, serdes); err != nil {
+		return nil, err
+	}
+	if !json.Valid(config) {
+		return nil, &NonJSONConfigError{Type: typ}
+	}
+	return json.RawMessage(config), nil
+}
+
+// importConfig deserializes and validates a config embedded in a bundle,
+// returning its serialized form.
+func importConfig(typ string, config json.RawMessage, serdes serde.Registry) ([]byte, error) {
+	model, err := serde.Deserialize(config, typ, serdes)
+	if err != nil {
+		return nil, err
+	}
+	if validatable, ok := model.(serde.ValidatableModel); ok {
+		if err := validatable.ValidateModel(); err != nil {
+			return nil, err
+		}
+	}
+	return serde.Serialize(model, typ, serdes)
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configurator_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"magma/orc8r/cloud/go/serde"
+	"magma/orc8r/cloud/go/services/configurator"
+	"magma/orc8r/cloud/go/services/configurator/test_init"
+	"magma/orc8r/cloud/go/storage"
+	merrors "magma/orc8r/lib/go/errors"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkBundles(t *testing.T) {
+	test_init.StartTestService(t)
+
+	networkSerdes := serde.NewRegistry(serde.NewBinarySerde(configurator.NetworkConfigSerdeDomain, "foo", &bundleConfig{}))
+	entitySerdes := serde.NewRegistry(serde.NewBinarySerde(configurator.NetworkEntitySerdeDomain, "foo", &bundleConfig{}))
+
+	_, err := configurator.ExportNetwork("n1", networkSerdes, entitySerdes)
+	assert.Equal(t, merrors.ErrNotFound, err)
+
+	err = configurator.CreateNetwork(
+		configurator.Network{ID: "n1", Type: "lte", Name: "lab", Configs: map[string]interface{}{"foo": &bundleConfig{Value: "hello"}}},
+		networkSerdes,
+	)
+	assert.NoError(t, err)
+	_, err = configurator.CreateEntities(
+		"n1",
+		[]configurator.NetworkEntity{
+			{Type: "bar", Key: "b1", Name: "bar 1"},
+			{Type: "foo", Key: "f1", Config: &bundleConfig{Value: "world"}, Associations: []storage.TypeAndKey{{Type: "bar", Key: "b1"}}},
+		},
+		entitySerdes,
+	)
+	assert.NoError(t, err)
+
+	// Export
+	bundle, err := configurator.ExportNetwork("n1", networkSerdes, entitySerdes)
+	assert.NoError(t, err)
+	expected := configurator.NetworkBundle{
+		Version: configurator.NetworkBundleVersion,
+		Network: configurator.BundleNetwork{ID: "n1", Type: "lte", Name: "lab", Configs: map[string]json.RawMessage{"foo": json.RawMessage(`{"value":"hello"}`)}},
+		Entities: []configurator.BundleEntity{
+			{Type: "bar", Key: "b1", Name: "bar 1"},
+			{Type: "foo", Key: "f1", Config: json.RawMessage(`{"value":"world"}`), Associations: []configurator.BundleEntityID{{Type: "bar", Key: "b1"}}},
+		},
+	}
+	assert.Equal(t, expected, bundle)
+
+	_, err = configurator.ExportNetwork("n1", serde.NewRegistry(), entitySerdes)
+	assert.EqualError(t, err, "failed to export network config foo: no serde in registry for type foo")
+
+	// Configs which don't serialize to JSON can't be exported
+	textSerdes := serde.NewRegistry(
+		serde.NewBinarySerde(configurator.NetworkEntitySerdeDomain, "foo", &bundleConfig{}),
+		serde.NewBinarySerde(configurator.NetworkEntitySerdeDomain, "text", &textConfig{}),
+	)
+	_, err = configurator.CreateEntity("n1", configurator.NetworkEntity{Type: "text", Key: "t1", Config: &textConfig{Value: "plain"}}, textSerdes)
+	assert.NoError(t, err)
+	_, err = configurator.ExportNetwork("n1", networkSerdes, textSerdes)
+	assert.EqualError(t, err, "failed to export entity text-t1: config of type text is not serialized as JSON")
+	assert.Equal(t, &configurator.NonJSONConfigError{Type: "text"}, pkgerrors.Cause(err))
+	err = configurator.DeleteEntity("n1", "text", "t1")
+	assert.NoError(t, err)
+
+	// Dry run import into a new network
+	plan, err := configurator.ImportNetwork("n2", bundle, true, networkSerdes, entitySerdes)
+	assert.NoError(t, err)
+	assert.Nil(t, plan.NetworkBefore)
+	assert.Equal(t, "n2", plan.NetworkAfter.ID)
+	assert.Equal(t, &bundleConfig{Value: "hello"}, plan.NetworkAfter.Configs["foo"])
+	created, updated, deleted := plan.EntityChanges()
+	assert.Equal(t, storage.TKs{{Type: "bar", Key: "b1"}, {Type: "foo", Key: "f1"}}, created)
+	assert.Empty(t, updated)
+	assert.Empty(t, deleted)
+	exists, err := configurator.DoesNetworkExist("n2")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	// Import into a new network
+	_, err = configurator.ImportNetwork("n2", bundle, false, networkSerdes, entitySerdes)
+	assert.NoError(t, err)
+	imported, err := configurator.ExportNetwork("n2", networkSerdes, entitySerdes)
+	assert.NoError(t, err)
+	expected.Network.ID = "n2"
+	assert.Equal(t, expected, imported)
+
+	// Importing the same bundle again is a no-op
+	plan, err = configurator.ImportNetwork("n2", bundle, true, networkSerdes, entitySerdes)
+	assert.NoError(t, err)
+	assert.Nil(t, plan.NetworkAfter)
+	assert.Empty(t, plan.Entities)
+
+	// Create, update, and delete entities
+	bundle.Entities = []configurator.BundleEntity{
+		{Type: "bar", Key: "b2"},
+		{Type: "foo", Key: "f1", Config: json.RawMessage(`{"value":"updated"}`), Associations: []configurator.BundleEntityID{{Type: "bar", Key: "b2"}}},
+	}
+	plan, err = configurator.ImportNetwork("n2", bundle, true, networkSerdes, entitySerdes)
+	assert.NoError(t, err)
+	created, updated, deleted = plan.EntityChanges()
+	assert.Equal(t, storage.TKs{{Type: "bar", Key: "b2"}}, created)
+	assert.Equal(t, storage.TKs{{Type: "foo", Key: "f1"}}, updated)
+	assert.Equal(t, storage.TKs{{Type: "bar", Key: "b1"}}, deleted)
+
+	_, err = configurator.ImportNetwork("n2", bundle, false, networkSerdes, entitySerdes)
+	assert.NoError(t, err)
+	ent, err := configurator.LoadEntity("n2", "foo", "f1", configurator.FullEntityLoadCriteria(), entitySerdes)
+	assert.NoError(t, err)
+	assert.Equal(t, &bundleConfig{Value: "updated"}, ent.Config)
+	assert.Equal(t, storage.TKs{{Type: "bar", Key: "b2"}}, ent.Associations)
+	exists, err = configurator.DoesEntityExist("n2", "bar", "b1")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	// Source network is untouched
+	bundle, err = configurator.ExportNetwork("n1", networkSerdes, entitySerdes)
+	assert.NoError(t, err)
+	expected.Network.ID = "n1"
+	assert.Equal(t, expected, bundle)
+
+	// Invalid bundles
+	invalid := bundle
+	invalid.Version = 2
+	_, err = configurator.ImportNetwork("n3", invalid, true, networkSerdes, entitySerdes)
+	assert.EqualError(t, err, "invalid bundle: unsupported bundle version 2, expected 1")
+
+	invalid = bundle
+	invalid.Entities = []configurator.BundleEntity{{Type: "foo", Key: "f1", Config: json.RawMessage(`{"value":""}`)}}
+	_, err = configurator.ImportNetwork("n3", invalid, true, networkSerdes, entitySerdes)
+	assert.EqualError(t, err, "invalid bundle: invalid config for entity foo-f1: value must be non-empty")
+
+	invalid.Entities = []configurator.BundleEntity{{Type: "foo", Key: "f1", Associations: []configurator.BundleEntityID{{Type: "bar", Key: "b3"}}}}
+	_, err = configurator.ImportNetwork("n3", invalid, true, networkSerdes, entitySerdes)
+	assert.Contains(t, err.Error(), "entity foo-f1 is associated to bar-b3, which is not included")
+
+	exists, err = configurator.DoesNetworkExist("n3")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+type bundleConfig struct {
+	Value string `json:"value"`
+}
+
+func (m *bundleConfig) MarshalBinary() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m *bundleConfig) UnmarshalBinary(b []byte) error {
+	return json.Unmarshal(b, m)
+}
+
+func (m *bundleConfig) ValidateModel() error {
+	if m.Value == "" {
+		return errors.New("value must be non-empty")
+	}
+	return nil
+}
+
+type textConfig struct {
+	Value string
+}
+
+func (m *textConfig) MarshalBinary() ([]byte, error) {
+	return []byte(m.Value), nil
+}
+
+func (m *textConfig) UnmarshalBinary(b []byte) error {
+	m.Value = string(b)
+	return nil
+}
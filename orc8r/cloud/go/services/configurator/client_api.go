@@ -28,6 +28,8 @@ import (
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/pkg/errors"
 	"github.com/thoas/go-funk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // ListNetworkIDs loads a list of all networkIDs registered
@@ -286,7 +288,7 @@ func WriteEntities(networkID string, writes []EntityWriteOperation, serdes serde
 
 	_, err = client.WriteEntities(context.Background(), req)
 	if err != nil {
-		return err
+		return mapVersionConflictError(err)
 	}
 	return nil
 }
@@ -360,7 +362,7 @@ func UpdateEntities(networkID string, updates []EntityUpdateCriteria, serdes ser
 	}
 	res, err := client.UpdateEntities(context.Background(), req)
 	if err != nil {
-		return nil, err
+		return nil, mapVersionConflictError(err)
 	}
 
 	updatedEnts := funk.Values(res.UpdatedEntities).([]*storage.NetworkEntity)
@@ -710,6 +712,127 @@ func CountEntitiesOfType(networkID string, entityType string, criteria EntityLoa
 	return res.Count, nil
 }
 
+// ListRevisions returns the recorded revisions of a network, oldest first.
+func ListRevisions(networkID string) ([]NetworkRevision, error) {
+	client, err := getNBConfiguratorClient()
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.ListRevisions(context.Background(), &protos.ListRevisionsRequest{NetworkID: networkID})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]NetworkRevision, 0, len(res.Revisions))
+	for _, rev := range res.Revisions {
+		ret = append(ret, (NetworkRevision{}).fromProto(rev))
+	}
+	return ret, nil
+}
+
+// GetLatestRevision returns the latest recorded revision of a network.
+// If the network has no revisions, returns ErrNotFound from
+// magma/orc8r/lib/go/errors.
+func GetLatestRevision(networkID string) (NetworkRevision, error) {
+	client, err := getNBConfiguratorClient()
+	if err != nil {
+		return NetworkRevision{}, err
+	}
+	res, err := client.ListRevisions(context.Background(), &protos.ListRevisionsRequest{NetworkID: networkID, LatestOnly: true})
+	if err != nil {
+		return NetworkRevision{}, err
+	}
+	if len(res.Revisions) == 0 {
+		return NetworkRevision{}, merrors.ErrNotFound
+	}
+	return (NetworkRevision{}).fromProto(res.Revisions[0]), nil
+}
+
+// LoadSnapshot loads a network and its entity graph as of the selected
+// revision. Configs without a registered serde are left serialized.
+// If not found, returns ErrNotFound from magma/orc8r/lib/go/errors.
+func LoadSnapshot(networkID string, revision RevisionSelector, networkSerdes, entitySerdes serde.Registry) (NetworkSnapshot, error) {
+	client, err := getNBConfiguratorClient()
+	if err != nil {
+		return NetworkSnapshot{}, err
+	}
+	res, err := client.LoadSnapshot(
+		context.Background(),
+		&protos.LoadSnapshotRequest{NetworkID: networkID, Revision: revision.toProto()},
+	)
+	if err != nil {
+		return NetworkSnapshot{}, mapRevisionError(err)
+	}
+	return (NetworkSnapshot{}).fromProto(res, networkSerdes, entitySerdes)
+}
+
+// DiffRevisions returns the changes to a network and its entity graph between
+// two revisions.
+// If either revision is not found, returns ErrNotFound from
+// magma/orc8r/lib/go/errors.
+func DiffRevisions(networkID string, from, to RevisionSelector, networkSerdes, entitySerdes serde.Registry) (NetworkDiff, error) {
+	client, err := getNBConfiguratorClient()
+	if err != nil {
+		return NetworkDiff{}, err
+	}
+	res, err := client.DiffRevisions(
+		context.Background(),
+		&protos.DiffRevisionsRequest{NetworkID: networkID, From: from.toProto(), To: to.toProto()},
+	)
+	if err != nil {
+		return NetworkDiff{}, mapRevisionError(err)
+	}
+	return (NetworkDiff{}).fromProto(res, networkSerdes, entitySerdes)
+}
+
+// RollbackNetwork returns a network and its entity graph to their state as of
+// the selected revision, returning the changes made. The rollback is recorded
+// as a new revision.
+// If the revision is not found, returns ErrNotFound from
+// magma/orc8r/lib/go/errors.
+func RollbackNetwork(networkID string, revision RevisionSelector, networkSerdes, entitySerdes serde.Registry) (NetworkDiff, error) {
+	client, err := getNBConfiguratorClient()
+	if err != nil {
+		return NetworkDiff{}, err
+	}
+	res, err := client.RollbackNetwork(
+		context.Background(),
+		&protos.RollbackNetworkRequest{NetworkID: networkID, Revision: revision.toProto()},
+	)
+	if err != nil {
+		return NetworkDiff{}, mapRevisionError(err)
+	}
+	return (NetworkDiff{}).fromProto(res, networkSerdes, entitySerdes)
+}
+
+func mapRevisionError(err error) error {
+	if status.Convert(err).Code() == codes.NotFound {
+		return merrors.ErrNotFound
+	}
+	return err
+}
+
+// mapVersionConflictError converts an Aborted status with version conflict
+// details back to a *storage.VersionConflictError.
+func mapVersionConflictError(err error) error {
+	st := status.Convert(err)
+	if st.Code() != codes.Aborted {
+		return err
+	}
+	conflict := &storage2.VersionConflictError{Versions: map[storage2.TypeAndKey]uint64{}}
+	for _, detail := range st.Details() {
+		ent, ok := detail.(*storage.NetworkEntity)
+		if !ok {
+			continue
+		}
+		conflict.Versions[ent.GetTypeAndKey()] = ent.Version
+	}
+	if len(conflict.Versions) == 0 {
+		return err
+	}
+	return conflict
+}
+
 func getNBConfiguratorClient() (protos.NorthboundConfiguratorClient, error) {
 	conn, err := registry.GetConnection(ServiceName)
 	if err != nil {
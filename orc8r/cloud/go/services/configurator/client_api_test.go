@@ -13,13 +13,19 @@ limitations under the License.
 package configurator_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"magma/orc8r/cloud/go/orc8r"
 	"magma/orc8r/cloud/go/serde"
 	"magma/orc8r/cloud/go/services/configurator"
 	"magma/orc8r/cloud/go/services/configurator/test_init"
+	"magma/orc8r/cloud/go/services/tenants"
 	"magma/orc8r/cloud/go/storage"
+	merrors "magma/orc8r/lib/go/errors"
+	"magma/orc8r/lib/go/protos"
+	"magma/orc8r/lib/go/registry"
 
 	"github.com/go-openapi/swag"
 	"github.com/stretchr/testify/assert"
@@ -295,6 +301,164 @@ func TestConfiguratorService(t *testing.T) {
 	assert.Equal(t, "foobar", entities[0].Name)
 }
 
+func TestConfiguratorService_ExpectedVersion(t *testing.T) {
+	test_init.StartTestService(t)
+
+	networkSerdes := serde.NewRegistry(&mockSerde{domain: configurator.NetworkConfigSerdeDomain, serdeType: "foo"})
+	entitySerdes := serde.NewRegistry(&mockSerde{domain: configurator.NetworkEntitySerdeDomain, serdeType: "foo"})
+
+	err := configurator.CreateNetwork(configurator.Network{ID: networkID1}, networkSerdes)
+	assert.NoError(t, err)
+	_, err = configurator.CreateEntity(networkID1, configurator.NetworkEntity{Type: "foo", Key: "bar", Config: "world"}, entitySerdes)
+	assert.NoError(t, err)
+
+	updated, err := configurator.UpdateEntity(networkID1, configurator.EntityUpdateCriteria{Type: "foo", Key: "bar", NewConfig: "hello", ExpectedVersion: swag.Uint64(0)}, entitySerdes)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), updated.Version)
+
+	_, err = configurator.UpdateEntity(networkID1, configurator.EntityUpdateCriteria{Type: "foo", Key: "bar", NewConfig: "stale", ExpectedVersion: swag.Uint64(0)}, entitySerdes)
+	assert.Equal(t, &storage.VersionConflictError{Versions: map[storage.TypeAndKey]uint64{{Type: "foo", Key: "bar"}: 1}}, err)
+
+	ent, err := configurator.LoadEntity(networkID1, "foo", "bar", configurator.EntityLoadCriteria{LoadConfig: true}, entitySerdes)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", ent.Config)
+}
+
+func TestConfiguratorService_Revisions(t *testing.T) {
+	test_init.StartTestService(t)
+
+	networkSerdes := serde.NewRegistry(&mockSerde{domain: configurator.NetworkConfigSerdeDomain, serdeType: "foo"})
+	entitySerdes := serde.NewRegistry(&mockSerde{domain: configurator.NetworkEntitySerdeDomain, serdeType: "foo"})
+
+	err := configurator.CreateNetwork(configurator.Network{ID: networkID1, Configs: map[string]interface{}{"foo": "hello"}}, networkSerdes)
+	assert.NoError(t, err)
+	_, err = configurator.CreateEntity(networkID1, configurator.NetworkEntity{Type: "foo", Key: "bar", Config: "world"}, entitySerdes)
+	assert.NoError(t, err)
+	err = configurator.CreateOrUpdateEntityConfig(networkID1, "foo", "bar", "goodbye", entitySerdes)
+	assert.NoError(t, err)
+
+	revisions, err := configurator.ListRevisions(networkID1)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 3)
+	for i, rev := range revisions {
+		assert.Equal(t, networkID1, rev.NetworkID)
+		assert.Equal(t, uint64(i+1), rev.Revision)
+	}
+	latest, err := configurator.GetLatestRevision(networkID1)
+	assert.NoError(t, err)
+	assert.Equal(t, revisions[2], latest)
+	_, err = configurator.GetLatestRevision(networkID2)
+	assert.Equal(t, merrors.ErrNotFound, err)
+
+	snapshot, err := configurator.LoadSnapshot(networkID1, configurator.RevisionSelector{Revision: 2}, networkSerdes, entitySerdes)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), snapshot.Revision.Revision)
+	assert.Equal(t, map[string]interface{}{"foo": "hello"}, snapshot.Network.Configs)
+	assert.Len(t, snapshot.Entities, 1)
+	assert.Equal(t, "world", snapshot.Entities[0].Config)
+
+	_, err = configurator.LoadSnapshot(networkID1, configurator.RevisionSelector{Revision: 4}, networkSerdes, entitySerdes)
+	assert.Equal(t, merrors.ErrNotFound, err)
+	_, err = configurator.LoadSnapshot(networkID1, configurator.RevisionSelector{Timestamp: 1}, networkSerdes, entitySerdes)
+	assert.Equal(t, merrors.ErrNotFound, err)
+
+	diff, err := configurator.DiffRevisions(networkID1, configurator.RevisionSelector{Revision: 1}, configurator.RevisionSelector{}, networkSerdes, entitySerdes)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), diff.From.Revision)
+	assert.Equal(t, uint64(3), diff.To.Revision)
+	assert.Nil(t, diff.NetworkAfter)
+	assert.Len(t, diff.Entities, 1)
+	assert.Equal(t, storage.TypeAndKey{Type: "foo", Key: "bar"}, diff.Entities[0].ID)
+	assert.Nil(t, diff.Entities[0].Before)
+	assert.Equal(t, "goodbye", diff.Entities[0].After.Config)
+
+	diff, err = configurator.RollbackNetwork(networkID1, configurator.RevisionSelector{Revision: 2}, networkSerdes, entitySerdes)
+	assert.NoError(t, err)
+	assert.Len(t, diff.Entities, 1)
+	assert.Equal(t, "goodbye", diff.Entities[0].Before.Config)
+	assert.Equal(t, "world", diff.Entities[0].After.Config)
+
+	config, err := configurator.LoadEntityConfig(networkID1, "foo", "bar", entitySerdes)
+	assert.NoError(t, err)
+	assert.Equal(t, "world", config)
+	revisions, err = configurator.ListRevisions(networkID1)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 4)
+
+	_, err = configurator.RollbackNetwork(networkID1, configurator.RevisionSelector{Revision: 5}, networkSerdes, entitySerdes)
+	assert.Equal(t, merrors.ErrNotFound, err)
+}
+
+func TestConfiguratorService_TenantQuotas(t *testing.T) {
+	test_init.StartTestService(t)
+	serdes := serde.NewRegistry()
+
+	_, err := configurator.CreateNetworks([]configurator.Network{{ID: networkID1}, {ID: networkID2}}, serdes)
+	assert.NoError(t, err)
+	_, err = tenants.CreateTenant(context.Background(), 1, &protos.Tenant{
+		Name:     "tenant1",
+		Networks: []string{networkID1, networkID2},
+		Quotas:   &protos.TenantQuotas{MaxGateways: 2},
+	})
+	assert.NoError(t, err)
+
+	_, err = configurator.CreateEntity(networkID1, configurator.NetworkEntity{Type: orc8r.MagmadGatewayType, Key: "gw1"}, serdes)
+	assert.NoError(t, err)
+	_, err = configurator.CreateEntities(networkID2, []configurator.NetworkEntity{
+		{Type: orc8r.MagmadGatewayType, Key: "gw2"},
+		{Type: orc8r.MagmadGatewayType, Key: "gw3"},
+	}, serdes)
+	assert.True(t, tenants.IsQuotaExceededError(err))
+	assert.Contains(t, err.Error(), "tenant 1 quota exceeded: at most 2 magmad_gateway entities allowed")
+
+	// Nothing was written
+	exists, err := configurator.DoesEntityExist(networkID2, orc8r.MagmadGatewayType, "gw2")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	err = configurator.WriteEntities(networkID2, []configurator.EntityWriteOperation{
+		configurator.NetworkEntity{Type: orc8r.MagmadGatewayType, Key: "gw2"},
+	}, serdes)
+	assert.NoError(t, err)
+	err = configurator.WriteEntities(networkID2, []configurator.EntityWriteOperation{
+		configurator.NetworkEntity{Type: orc8r.MagmadGatewayType, Key: "gw3"},
+	}, serdes)
+	assert.True(t, tenants.IsQuotaExceededError(err))
+
+	// Rollbacks which recreate entities are checked too
+	revision, err := configurator.GetLatestRevision(networkID2)
+	assert.NoError(t, err)
+	err = configurator.DeleteEntity(networkID2, orc8r.MagmadGatewayType, "gw2")
+	assert.NoError(t, err)
+	_, err = configurator.CreateEntity(networkID1, configurator.NetworkEntity{Type: orc8r.MagmadGatewayType, Key: "gw4"}, serdes)
+	assert.NoError(t, err)
+	_, err = configurator.RollbackNetwork(networkID2, configurator.RevisionSelector{Revision: revision.Revision}, serdes, serdes)
+	assert.True(t, tenants.IsQuotaExceededError(err))
+	exists, err = configurator.DoesEntityExist(networkID2, orc8r.MagmadGatewayType, "gw2")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	// Other types are unlimited
+	_, err = configurator.CreateEntity(networkID2, configurator.NetworkEntity{Type: "foo", Key: "foo"}, serdes)
+	assert.NoError(t, err)
+
+	// Until registered for a quota
+	registry.AddService(registry.ServiceLocation{
+		Name:        "quota_test_service",
+		Labels:      map[string]string{orc8r.TenantQuotaLabel: "true"},
+		Annotations: map[string]string{orc8r.TenantQuotaEntityTypesAnnotation: "max_subscribers:foo"},
+	})
+	err = tenants.SetTenant(context.Background(), 1, protos.Tenant{
+		Name:     "tenant1",
+		Networks: []string{networkID1, networkID2},
+		Quotas:   &protos.TenantQuotas{MaxGateways: 2, MaxSubscribers: 1},
+	})
+	assert.NoError(t, err)
+	_, err = configurator.CreateEntity(networkID1, configurator.NetworkEntity{Type: "foo", Key: "foo"}, serdes)
+	assert.True(t, tenants.IsQuotaExceededError(err))
+	assert.Contains(t, err.Error(), "tenant 1 quota exceeded: at most 1 foo entities allowed")
+}
+
 func strPointer(str string) *string {
 	return &str
 }
@@ -716,6 +716,383 @@ func (m *DeleteEntitiesRequest) GetID() []*storage.EntityID {
 	return nil
 }
 
+// RevisionSelector identifies a revision of a network. An empty selector
+// selects the latest revision.
+type RevisionSelector struct {
+	// Types that are valid to be assigned to Selector:
+	//	*RevisionSelector_Revision
+	//	*RevisionSelector_Timestamp
+	Selector             isRevisionSelector_Selector `protobuf_oneof:"selector"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *RevisionSelector) Reset()         { *m = RevisionSelector{} }
+func (m *RevisionSelector) String() string { return proto.CompactTextString(m) }
+func (*RevisionSelector) ProtoMessage()    {}
+func (*RevisionSelector) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dd920317c7204fbb, []int{15}
+}
+
+func (m *RevisionSelector) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevisionSelector.Unmarshal(m, b)
+}
+func (m *RevisionSelector) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevisionSelector.Marshal(b, m, deterministic)
+}
+func (m *RevisionSelector) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevisionSelector.Merge(m, src)
+}
+func (m *RevisionSelector) XXX_Size() int {
+	return xxx_messageInfo_RevisionSelector.Size(m)
+}
+func (m *RevisionSelector) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevisionSelector.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevisionSelector proto.InternalMessageInfo
+
+type isRevisionSelector_Selector interface {
+	isRevisionSelector_Selector()
+}
+
+type RevisionSelector_Revision struct {
+	Revision uint64 `protobuf:"varint,1,opt,name=revision,proto3,oneof"`
+}
+
+type RevisionSelector_Timestamp struct {
+	Timestamp int64 `protobuf:"varint,2,opt,name=timestamp,proto3,oneof"`
+}
+
+func (*RevisionSelector_Revision) isRevisionSelector_Selector() {}
+
+func (*RevisionSelector_Timestamp) isRevisionSelector_Selector() {}
+
+func (m *RevisionSelector) GetSelector() isRevisionSelector_Selector {
+	if m != nil {
+		return m.Selector
+	}
+	return nil
+}
+
+func (m *RevisionSelector) GetRevision() uint64 {
+	if x, ok := m.GetSelector().(*RevisionSelector_Revision); ok {
+		return x.Revision
+	}
+	return 0
+}
+
+func (m *RevisionSelector) GetTimestamp() int64 {
+	if x, ok := m.GetSelector().(*RevisionSelector_Timestamp); ok {
+		return x.Timestamp
+	}
+	return 0
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*RevisionSelector) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*RevisionSelector_Revision)(nil),
+		(*RevisionSelector_Timestamp)(nil),
+	}
+}
+
+type ListRevisionsRequest struct {
+	NetworkID string `protobuf:"bytes,1,opt,name=networkID,proto3" json:"networkID,omitempty"`
+	// If set, only the latest revision is returned
+	LatestOnly           bool     `protobuf:"varint,2,opt,name=latestOnly,proto3" json:"latestOnly,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRevisionsRequest) Reset()         { *m = ListRevisionsRequest{} }
+func (m *ListRevisionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRevisionsRequest) ProtoMessage()    {}
+func (*ListRevisionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dd920317c7204fbb, []int{16}
+}
+
+func (m *ListRevisionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRevisionsRequest.Unmarshal(m, b)
+}
+func (m *ListRevisionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRevisionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListRevisionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRevisionsRequest.Merge(m, src)
+}
+func (m *ListRevisionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRevisionsRequest.Size(m)
+}
+func (m *ListRevisionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRevisionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRevisionsRequest proto.InternalMessageInfo
+
+func (m *ListRevisionsRequest) GetNetworkID() string {
+	if m != nil {
+		return m.NetworkID
+	}
+	return ""
+}
+
+func (m *ListRevisionsRequest) GetLatestOnly() bool {
+	if m != nil {
+		return m.LatestOnly
+	}
+	return false
+}
+
+type ListRevisionsResponse struct {
+	Revisions            []*storage.NetworkRevision `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *ListRevisionsResponse) Reset()         { *m = ListRevisionsResponse{} }
+func (m *ListRevisionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListRevisionsResponse) ProtoMessage()    {}
+func (*ListRevisionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dd920317c7204fbb, []int{17}
+}
+
+func (m *ListRevisionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRevisionsResponse.Unmarshal(m, b)
+}
+func (m *ListRevisionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRevisionsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListRevisionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRevisionsResponse.Merge(m, src)
+}
+func (m *ListRevisionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListRevisionsResponse.Size(m)
+}
+func (m *ListRevisionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRevisionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRevisionsResponse proto.InternalMessageInfo
+
+func (m *ListRevisionsResponse) GetRevisions() []*storage.NetworkRevision {
+	if m != nil {
+		return m.Revisions
+	}
+	return nil
+}
+
+type LoadSnapshotRequest struct {
+	NetworkID            string            `protobuf:"bytes,1,opt,name=networkID,proto3" json:"networkID,omitempty"`
+	Revision             *RevisionSelector `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *LoadSnapshotRequest) Reset()         { *m = LoadSnapshotRequest{} }
+func (m *LoadSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*LoadSnapshotRequest) ProtoMessage()    {}
+func (*LoadSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dd920317c7204fbb, []int{18}
+}
+
+func (m *LoadSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoadSnapshotRequest.Unmarshal(m, b)
+}
+func (m *LoadSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoadSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *LoadSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoadSnapshotRequest.Merge(m, src)
+}
+func (m *LoadSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_LoadSnapshotRequest.Size(m)
+}
+func (m *LoadSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoadSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoadSnapshotRequest proto.InternalMessageInfo
+
+func (m *LoadSnapshotRequest) GetNetworkID() string {
+	if m != nil {
+		return m.NetworkID
+	}
+	return ""
+}
+
+func (m *LoadSnapshotRequest) GetRevision() *RevisionSelector {
+	if m != nil {
+		return m.Revision
+	}
+	return nil
+}
+
+type DiffRevisionsRequest struct {
+	NetworkID            string            `protobuf:"bytes,1,opt,name=networkID,proto3" json:"networkID,omitempty"`
+	From                 *RevisionSelector `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To                   *RevisionSelector `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DiffRevisionsRequest) Reset()         { *m = DiffRevisionsRequest{} }
+func (m *DiffRevisionsRequest) String() string { return proto.CompactTextString(m) }
+func (*DiffRevisionsRequest) ProtoMessage()    {}
+func (*DiffRevisionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dd920317c7204fbb, []int{19}
+}
+
+func (m *DiffRevisionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffRevisionsRequest.Unmarshal(m, b)
+}
+func (m *DiffRevisionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffRevisionsRequest.Marshal(b, m, deterministic)
+}
+func (m *DiffRevisionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffRevisionsRequest.Merge(m, src)
+}
+func (m *DiffRevisionsRequest) XXX_Size() int {
+	return xxx_messageInfo_DiffRevisionsRequest.Size(m)
+}
+func (m *DiffRevisionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffRevisionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffRevisionsRequest proto.InternalMessageInfo
+
+func (m *DiffRevisionsRequest) GetNetworkID() string {
+	if m != nil {
+		return m.NetworkID
+	}
+	return ""
+}
+
+func (m *DiffRevisionsRequest) GetFrom() *RevisionSelector {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *DiffRevisionsRequest) GetTo() *RevisionSelector {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+type RollbackNetworkRequest struct {
+	NetworkID            string            `protobuf:"bytes,1,opt,name=networkID,proto3" json:"networkID,omitempty"`
+	Revision             *RevisionSelector `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *RollbackNetworkRequest) Reset()         { *m = RollbackNetworkRequest{} }
+func (m *RollbackNetworkRequest) String() string { return proto.CompactTextString(m) }
+func (*RollbackNetworkRequest) ProtoMessage()    {}
+func (*RollbackNetworkRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dd920317c7204fbb, []int{20}
+}
+
+func (m *RollbackNetworkRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RollbackNetworkRequest.Unmarshal(m, b)
+}
+func (m *RollbackNetworkRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RollbackNetworkRequest.Marshal(b, m, deterministic)
+}
+func (m *RollbackNetworkRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RollbackNetworkRequest.Merge(m, src)
+}
+func (m *RollbackNetworkRequest) XXX_Size() int {
+	return xxx_messageInfo_RollbackNetworkRequest.Size(m)
+}
+func (m *RollbackNetworkRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RollbackNetworkRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RollbackNetworkRequest proto.InternalMessageInfo
+
+func (m *RollbackNetworkRequest) GetNetworkID() string {
+	if m != nil {
+		return m.NetworkID
+	}
+	return ""
+}
+
+func (m *RollbackNetworkRequest) GetRevision() *RevisionSelector {
+	if m != nil {
+		return m.Revision
+	}
+	return nil
+}
+
+type ImportNetworkRequest struct {
+	// Network is created if it doesn't exist
+	Network *storage.Network `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	// All entities of the network. Existing entities which aren't included
+	// are deleted.
+	Entities []*storage.NetworkEntity `protobuf:"bytes,2,rep,name=entities,proto3" json:"entities,omitempty"`
+	// If dry_run is set, the changes are computed but not written
+	DryRun               bool     `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportNetworkRequest) Reset()         { *m = ImportNetworkRequest{} }
+func (m *ImportNetworkRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportNetworkRequest) ProtoMessage()    {}
+func (*ImportNetworkRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dd920317c7204fbb, []int{21}
+}
+
+func (m *ImportNetworkRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportNetworkRequest.Unmarshal(m, b)
+}
+func (m *ImportNetworkRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportNetworkRequest.Marshal(b, m, deterministic)
+}
+func (m *ImportNetworkRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportNetworkRequest.Merge(m, src)
+}
+func (m *ImportNetworkRequest) XXX_Size() int {
+	return xxx_messageInfo_ImportNetworkRequest.Size(m)
+}
+func (m *ImportNetworkRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportNetworkRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportNetworkRequest proto.InternalMessageInfo
+
+func (m *ImportNetworkRequest) GetNetwork() *storage.Network {
+	if m != nil {
+		return m.Network
+	}
+	return nil
+}
+
+func (m *ImportNetworkRequest) GetEntities() []*storage.NetworkEntity {
+	if m != nil {
+		return m.Entities
+	}
+	return nil
+}
+
+func (m *ImportNetworkRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*ListNetworkIDsResponse)(nil), "magma.orc8r.configurator.ListNetworkIDsResponse")
 	proto.RegisterType((*LoadNetworksRequest)(nil), "magma.orc8r.configurator.LoadNetworksRequest")
@@ -734,6 +1111,13 @@ func init() {
 	proto.RegisterType((*UpdateEntitiesResponse)(nil), "magma.orc8r.configurator.UpdateEntitiesResponse")
 	proto.RegisterMapType((map[string]*storage.NetworkEntity)(nil), "magma.orc8r.configurator.UpdateEntitiesResponse.UpdatedEntitiesEntry")
 	proto.RegisterType((*DeleteEntitiesRequest)(nil), "magma.orc8r.configurator.DeleteEntitiesRequest")
+	proto.RegisterType((*RevisionSelector)(nil), "magma.orc8r.configurator.RevisionSelector")
+	proto.RegisterType((*ListRevisionsRequest)(nil), "magma.orc8r.configurator.ListRevisionsRequest")
+	proto.RegisterType((*ListRevisionsResponse)(nil), "magma.orc8r.configurator.ListRevisionsResponse")
+	proto.RegisterType((*LoadSnapshotRequest)(nil), "magma.orc8r.configurator.LoadSnapshotRequest")
+	proto.RegisterType((*DiffRevisionsRequest)(nil), "magma.orc8r.configurator.DiffRevisionsRequest")
+	proto.RegisterType((*RollbackNetworkRequest)(nil), "magma.orc8r.configurator.RollbackNetworkRequest")
+	proto.RegisterType((*ImportNetworkRequest)(nil), "magma.orc8r.configurator.ImportNetworkRequest")
 }
 
 func init() {
@@ -741,59 +1125,78 @@ func init() {
 }
 
 var fileDescriptor_dd920317c7204fbb = []byte{
-	// 831 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x57, 0x4d, 0x6f, 0xd3, 0x4a,
-	0x14, 0x8d, 0x53, 0xbd, 0xb4, 0xb9, 0x7d, 0xfd, 0x78, 0xf3, 0x9a, 0x28, 0x2f, 0x7a, 0x42, 0xd5,
-	0xac, 0x0a, 0x82, 0xb8, 0x24, 0x40, 0xab, 0x8a, 0x55, 0x93, 0x20, 0x42, 0xab, 0xaa, 0xb5, 0xa0,
-	0x95, 0xba, 0x00, 0xb9, 0xc9, 0x34, 0x98, 0x26, 0x9e, 0x74, 0x3c, 0x6e, 0x15, 0x96, 0x6c, 0xf8,
-	0x43, 0xb0, 0xe3, 0x4f, 0xb0, 0xe4, 0xd7, 0x80, 0xe2, 0x19, 0x3b, 0xb6, 0xe3, 0x24, 0x9e, 0x2e,
-	0x10, 0xab, 0x58, 0x33, 0x3e, 0xe7, 0xcc, 0xbd, 0xf7, 0xf8, 0xce, 0x0d, 0x3c, 0xa7, 0xac, 0xbd,
-	0xcb, 0xf4, 0x76, 0x8f, 0xba, 0x1d, 0xbd, 0x4b, 0x75, 0x87, 0xb0, 0x1b, 0xab, 0x4d, 0x1c, 0xbd,
-	0x4d, 0xed, 0x4b, 0xab, 0xeb, 0x32, 0x93, 0x53, 0xa6, 0x0f, 0x18, 0xe5, 0xd4, 0xd1, 0x6d, 0xca,
-	0xf8, 0xfb, 0x0b, 0xea, 0xda, 0x9d, 0x8a, 0xb7, 0x82, 0x4a, 0x7d, 0xb3, 0xdb, 0x37, 0x2b, 0x1e,
-	0x47, 0x25, 0x8c, 0x28, 0xff, 0x27, 0x78, 0x25, 0xb0, 0x4d, 0xfb, 0x7d, 0x6a, 0x0b, 0x50, 0x79,
-	0x2f, 0x95, 0xa4, 0xc3, 0x29, 0x33, 0xbb, 0xc4, 0xff, 0x15, 0x58, 0xbc, 0x0b, 0xc5, 0x43, 0xcb,
-	0xe1, 0x47, 0x84, 0xdf, 0x52, 0x76, 0xd5, 0x6a, 0x38, 0x06, 0x71, 0x06, 0xd4, 0x76, 0x08, 0xba,
-	0x07, 0x60, 0x07, 0xab, 0x25, 0x6d, 0x73, 0x61, 0x2b, 0x6f, 0x84, 0x56, 0xf0, 0x57, 0x0d, 0xfe,
-	0x3d, 0xa4, 0x66, 0x47, 0x42, 0x1d, 0x83, 0x5c, 0xbb, 0xc4, 0xe1, 0xe8, 0x04, 0x96, 0xda, 0xcc,
-	0xe2, 0x84, 0x59, 0x66, 0x29, 0xbb, 0xa9, 0x6d, 0x2d, 0x57, 0x9f, 0x56, 0xa6, 0x45, 0x55, 0xf1,
-	0x0f, 0x23, 0x49, 0x46, 0x7c, 0x75, 0x09, 0x36, 0x02, 0x1a, 0x74, 0x00, 0xb9, 0x4b, 0xab, 0xc7,
-	0x09, 0x2b, 0x2d, 0x78, 0x84, 0x35, 0x25, 0xc2, 0x17, 0x1e, 0xd4, 0x90, 0x14, 0xf8, 0x2d, 0x14,
-	0xea, 0x8c, 0x98, 0x9c, 0xc4, 0x0f, 0xde, 0x84, 0x25, 0x19, 0x9e, 0x08, 0x77, 0xb9, 0x7a, 0x3f,
-	0xb5, 0x8e, 0x11, 0x40, 0xb1, 0x0d, 0xc5, 0x38, 0xbf, 0xcc, 0xe8, 0x6b, 0x58, 0x6f, 0x7b, 0x3b,
-	0x9d, 0x77, 0x77, 0x17, 0x5a, 0x93, 0x14, 0x3e, 0x3b, 0xfe, 0x00, 0x85, 0x37, 0x83, 0x4e, 0x42,
-	0x3c, 0x27, 0xb0, 0xe8, 0x7a, 0x1b, 0xbe, 0xca, 0x4e, 0x6a, 0x15, 0x41, 0x18, 0x54, 0xc2, 0xe7,
-	0xc1, 0x3b, 0x50, 0x68, 0x90, 0x1e, 0x99, 0xd4, 0x9a, 0x67, 0x96, 0xef, 0xd2, 0x2c, 0x4d, 0x9b,
-	0x5b, 0xdc, 0x22, 0x01, 0xee, 0x7f, 0xc8, 0x07, 0x6f, 0x95, 0xb4, 0x4d, 0x6d, 0x2b, 0x6f, 0x8c,
-	0x17, 0xd0, 0xab, 0xa0, 0xee, 0xc2, 0x48, 0xd5, 0xf9, 0x01, 0x78, 0x02, 0xc3, 0xc9, 0xb2, 0xa3,
-	0xe3, 0x90, 0x2d, 0x85, 0x8b, 0x9e, 0xa8, 0xb0, 0x4d, 0xba, 0x12, 0x7f, 0x84, 0x8d, 0xb3, 0xd1,
-	0xb3, 0x5a, 0x4c, 0x0d, 0xc8, 0xdd, 0x8e, 0x50, 0x4e, 0x29, 0xeb, 0x15, 0xe5, 0xe1, 0xf4, 0x53,
-	0x8c, 0xd9, 0x87, 0x92, 0xdb, 0x90, 0x58, 0xfc, 0x4d, 0x03, 0x34, 0xb9, 0x8d, 0x5a, 0x90, 0x13,
-	0xf6, 0xf0, 0x74, 0x97, 0xab, 0x7a, 0xea, 0x8a, 0x0b, 0x9e, 0x97, 0x19, 0x43, 0x12, 0xa0, 0x63,
-	0xc8, 0x89, 0xaa, 0xcb, 0xdc, 0x3f, 0x4b, 0x9b, 0xad, 0xa8, 0x77, 0x46, 0x8c, 0x82, 0x67, 0x3f,
-	0x0f, 0x8b, 0x4c, 0x9c, 0x13, 0xff, 0xc8, 0x42, 0x21, 0x96, 0x3b, 0xf9, 0x8d, 0x9c, 0x8f, 0xbf,
-	0x11, 0x22, 0xf7, 0xa4, 0x7b, 0x55, 0x63, 0x09, 0xbe, 0x14, 0x5f, 0x03, 0x51, 0x58, 0x17, 0x47,
-	0x09, 0x71, 0x8b, 0x22, 0x34, 0xd2, 0x14, 0x21, 0x74, 0xcc, 0x8a, 0x08, 0x32, 0xa0, 0x6e, 0xda,
-	0x9c, 0x0d, 0x8d, 0x35, 0x37, 0xba, 0x5a, 0x76, 0x60, 0x23, 0xe9, 0x45, 0xb4, 0x0e, 0x0b, 0x57,
-	0x64, 0x28, 0xbd, 0x31, 0x7a, 0x44, 0x4d, 0xf8, 0xeb, 0xc6, 0xec, 0xb9, 0x7e, 0xb2, 0x95, 0x63,
-	0x15, 0xe8, 0xbd, 0xec, 0xae, 0x86, 0x3f, 0x69, 0x7e, 0x83, 0x53, 0x33, 0xe6, 0x01, 0x2c, 0xc5,
-	0xb2, 0xa2, 0x7c, 0x8a, 0x80, 0x00, 0x73, 0xbf, 0x09, 0xfe, 0xce, 0x02, 0xe3, 0xcf, 0x9a, 0xdf,
-	0x0b, 0xd5, 0x42, 0x3f, 0x1e, 0x77, 0x4a, 0x11, 0xf9, 0x1d, 0xcd, 0x3e, 0x6e, 0x94, 0x3f, 0x35,
-	0x28, 0xc6, 0x4f, 0x22, 0x13, 0x30, 0x48, 0x70, 0xa1, 0x48, 0x40, 0x73, 0xba, 0x6a, 0x32, 0xd7,
-	0x9f, 0x6c, 0xc3, 0x6b, 0xff, 0xaa, 0x50, 0x2b, 0xc5, 0x1e, 0x64, 0x5b, 0x0d, 0x59, 0x85, 0x07,
-	0x69, 0xab, 0xd0, 0x6a, 0x18, 0xd9, 0x56, 0xa3, 0xfa, 0x25, 0x0f, 0xc5, 0xa3, 0x60, 0xa2, 0xaa,
-	0x87, 0xde, 0x47, 0x67, 0xb0, 0x1a, 0x1d, 0x73, 0xd0, 0x3f, 0x11, 0xf2, 0x53, 0x6a, 0x75, 0xca,
-	0xdb, 0xd3, 0xf5, 0x92, 0x67, 0x24, 0x9c, 0x41, 0x2e, 0xac, 0x46, 0x6f, 0x7b, 0x34, 0x23, 0x69,
-	0x89, 0x73, 0xc7, 0x2c, 0xd9, 0xe4, 0x41, 0x02, 0x67, 0xd0, 0x29, 0xac, 0x46, 0x2f, 0xfd, 0x59,
-	0xb2, 0x89, 0xe3, 0x41, 0x79, 0x32, 0x01, 0x82, 0x37, 0x7a, 0xc1, 0xcf, 0xe2, 0x4d, 0x1c, 0x05,
-	0x92, 0x79, 0x39, 0xfc, 0x1d, 0x9e, 0x15, 0xd1, 0xa3, 0x19, 0xa9, 0x9e, 0x9c, 0x29, 0xcb, 0x6a,
-	0x03, 0x9f, 0x41, 0x1c, 0xb7, 0xc7, 0x71, 0x06, 0x31, 0x58, 0x89, 0xb4, 0x6f, 0x54, 0x49, 0xdd,
-	0xe7, 0x85, 0xae, 0xae, 0x78, 0x2f, 0x84, 0x0d, 0x11, 0x88, 0xce, 0x35, 0x44, 0x5c, 0x75, 0x3b,
-	0x3d, 0x20, 0x2c, 0x1b, 0xed, 0x11, 0xf3, 0x0d, 0xa1, 0x20, 0x9b, 0xdc, 0x7e, 0xc2, 0x7e, 0x49,
-	0x23, 0x9b, 0xd8, 0x0f, 0x92, 0xfd, 0xe2, 0x08, 0xbf, 0x04, 0xac, 0x73, 0xfc, 0x12, 0xe7, 0x54,
-	0x1a, 0x14, 0x03, 0xbb, 0xb8, 0xb0, 0x52, 0xa7, 0xae, 0xcd, 0xef, 0xaa, 0x5a, 0x4b, 0xab, 0xea,
-	0xa9, 0xf8, 0xb2, 0xfb, 0xb5, 0xf3, 0xc7, 0x1e, 0x4e, 0x57, 0xf8, 0xe7, 0x78, 0x91, 0xf3, 0x7e,
-	0x6b, 0xbf, 0x02, 0x00, 0x00, 0xff, 0xff, 0xc8, 0x10, 0xf2, 0xe7, 0x6f, 0x0e, 0x00, 0x00,
+	// 1121 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x58, 0x41, 0x6f, 0x1b, 0x45,
+	0x14, 0xf6, 0x3a, 0xc5, 0xb1, 0x5f, 0xea, 0x24, 0x0c, 0xb6, 0x31, 0xab, 0x2a, 0x8a, 0xe6, 0x14,
+	0x10, 0xf5, 0xa6, 0x0e, 0xd0, 0x28, 0x42, 0x1c, 0x62, 0xbb, 0x8a, 0x69, 0xd5, 0xa6, 0xd3, 0xd2,
+	0x4a, 0x15, 0xa2, 0xda, 0xd8, 0xe3, 0x64, 0xc9, 0x7a, 0xc7, 0x9d, 0x9d, 0x4d, 0x65, 0x90, 0x38,
+	0x70, 0xe1, 0xef, 0x70, 0xe1, 0xc6, 0x85, 0x9f, 0xc0, 0x91, 0x3f, 0xc2, 0x15, 0xe4, 0x9d, 0xd9,
+	0xf5, 0xee, 0x7a, 0x63, 0xef, 0x46, 0xa8, 0xe2, 0x14, 0x6b, 0x76, 0xbf, 0xef, 0x9b, 0xf7, 0xde,
+	0xb7, 0xf3, 0xde, 0x04, 0xbe, 0x64, 0x7c, 0x70, 0xc8, 0x8d, 0x81, 0xcd, 0xbc, 0xa1, 0x71, 0xce,
+	0x0c, 0x97, 0xf2, 0x2b, 0x6b, 0x40, 0x5d, 0x63, 0xc0, 0x9c, 0x91, 0x75, 0xee, 0x71, 0x53, 0x30,
+	0x6e, 0x4c, 0x38, 0x13, 0xcc, 0x35, 0x1c, 0xc6, 0xc5, 0xc5, 0x19, 0xf3, 0x9c, 0x61, 0xcb, 0x5f,
+	0x41, 0xcd, 0xb1, 0x79, 0x3e, 0x36, 0x5b, 0x3e, 0x47, 0x2b, 0x8a, 0xd0, 0x3f, 0x92, 0xbc, 0x0a,
+	0x38, 0x60, 0xe3, 0x31, 0x73, 0x24, 0x48, 0x3f, 0xca, 0x24, 0xe9, 0x0a, 0xc6, 0xcd, 0x73, 0x1a,
+	0xfc, 0x95, 0x58, 0x7c, 0x08, 0x8d, 0x47, 0x96, 0x2b, 0x1e, 0x53, 0xf1, 0x96, 0xf1, 0xcb, 0x7e,
+	0xd7, 0x25, 0xd4, 0x9d, 0x30, 0xc7, 0xa5, 0x68, 0x07, 0xc0, 0x09, 0x57, 0x9b, 0xda, 0xee, 0xda,
+	0x5e, 0x85, 0x44, 0x56, 0xf0, 0x6f, 0x1a, 0x7c, 0xf0, 0x88, 0x99, 0x43, 0x05, 0x75, 0x09, 0x7d,
+	0xe3, 0x51, 0x57, 0xa0, 0xa7, 0x50, 0x1e, 0x70, 0x4b, 0x50, 0x6e, 0x99, 0xcd, 0xe2, 0xae, 0xb6,
+	0xb7, 0xd1, 0xfe, 0xbc, 0x75, 0x5d, 0x54, 0xad, 0x60, 0x33, 0x8a, 0x64, 0xc6, 0xd7, 0x51, 0x60,
+	0x12, 0xd2, 0xa0, 0x87, 0x50, 0x1a, 0x59, 0xb6, 0xa0, 0xbc, 0xb9, 0xe6, 0x13, 0x1e, 0xe4, 0x22,
+	0x7c, 0xe0, 0x43, 0x89, 0xa2, 0xc0, 0xdf, 0x41, 0xbd, 0xc3, 0xa9, 0x29, 0x68, 0x72, 0xe3, 0x3d,
+	0x28, 0xab, 0xf0, 0x64, 0xb8, 0x1b, 0xed, 0x8f, 0x33, 0xeb, 0x90, 0x10, 0x8a, 0x1d, 0x68, 0x24,
+	0xf9, 0x55, 0x46, 0x9f, 0xc3, 0xf6, 0xc0, 0x7f, 0x32, 0x7c, 0x7d, 0x73, 0xa1, 0x2d, 0x45, 0x11,
+	0xb0, 0xe3, 0xef, 0xa1, 0xfe, 0xcd, 0x64, 0x98, 0x12, 0xcf, 0x53, 0x58, 0xf7, 0xfc, 0x07, 0x81,
+	0xca, 0xfd, 0xcc, 0x2a, 0x92, 0x30, 0xac, 0x44, 0xc0, 0x83, 0xef, 0x43, 0xbd, 0x4b, 0x6d, 0xba,
+	0xa8, 0xb5, 0xca, 0x2c, 0x7f, 0x2a, 0xb3, 0xf4, 0x1c, 0x61, 0x09, 0x8b, 0x86, 0xb8, 0x3b, 0x50,
+	0x09, 0xdf, 0x6a, 0x6a, 0xbb, 0xda, 0x5e, 0x85, 0xcc, 0x17, 0xd0, 0xd7, 0x61, 0xdd, 0xa5, 0x91,
+	0xda, 0xab, 0x03, 0xf0, 0x05, 0xa6, 0x8b, 0x65, 0x47, 0xa7, 0x11, 0x5b, 0x4a, 0x17, 0x7d, 0x96,
+	0x87, 0x6d, 0xd1, 0x95, 0xf8, 0x07, 0xa8, 0xbd, 0x9c, 0xfd, 0xce, 0x17, 0x53, 0x17, 0x4a, 0x6f,
+	0x67, 0x28, 0xb7, 0x59, 0xf4, 0x8b, 0xf2, 0xe9, 0xf5, 0xbb, 0x98, 0xb3, 0x4f, 0x15, 0x37, 0x51,
+	0x58, 0xfc, 0xbb, 0x06, 0x68, 0xf1, 0x31, 0xea, 0x43, 0x49, 0xda, 0xc3, 0xd7, 0xdd, 0x68, 0x1b,
+	0x99, 0x2b, 0x2e, 0x79, 0x4e, 0x0a, 0x44, 0x11, 0xa0, 0x53, 0x28, 0xc9, 0xaa, 0xab, 0xdc, 0x7f,
+	0x91, 0x35, 0x5b, 0x71, 0xef, 0xcc, 0x18, 0x25, 0xcf, 0x71, 0x05, 0xd6, 0xb9, 0xdc, 0x27, 0xfe,
+	0xab, 0x08, 0xf5, 0x44, 0xee, 0xd4, 0x37, 0xf2, 0x6a, 0xfe, 0x8d, 0x50, 0xf5, 0x4c, 0xb9, 0x37,
+	0x6f, 0x2c, 0xe1, 0x97, 0x12, 0x68, 0x20, 0x06, 0xdb, 0x72, 0x2b, 0x11, 0x6e, 0x59, 0x84, 0x6e,
+	0x96, 0x22, 0x44, 0xb6, 0xd9, 0x92, 0x41, 0x86, 0xd4, 0x3d, 0x47, 0xf0, 0x29, 0xd9, 0xf2, 0xe2,
+	0xab, 0xba, 0x0b, 0xb5, 0xb4, 0x17, 0xd1, 0x36, 0xac, 0x5d, 0xd2, 0xa9, 0xf2, 0xc6, 0xec, 0x27,
+	0xea, 0xc1, 0x7b, 0x57, 0xa6, 0xed, 0x05, 0xc9, 0xce, 0x1d, 0xab, 0x44, 0x1f, 0x15, 0x0f, 0x35,
+	0xfc, 0xb3, 0x16, 0x1c, 0x70, 0xf9, 0x8c, 0xf9, 0x10, 0xca, 0x89, 0xac, 0xe4, 0xde, 0x45, 0x48,
+	0x80, 0x45, 0x70, 0x08, 0xbe, 0xcb, 0x02, 0xe3, 0x5f, 0xb4, 0xe0, 0x2c, 0xcc, 0x17, 0xfa, 0xe9,
+	0xfc, 0xa4, 0x94, 0x91, 0xdf, 0xd0, 0xec, 0xf3, 0x83, 0xf2, 0x1f, 0x0d, 0x1a, 0xc9, 0x9d, 0xa8,
+	0x04, 0x4c, 0x52, 0x5c, 0x28, 0x13, 0xd0, 0xbb, 0x5e, 0x35, 0x9d, 0xeb, 0xff, 0x6c, 0xc3, 0x37,
+	0x41, 0xab, 0xc8, 0x57, 0x8a, 0x23, 0x28, 0xf6, 0xbb, 0xaa, 0x0a, 0x9f, 0x64, 0xad, 0x42, 0xbf,
+	0x4b, 0x8a, 0xfd, 0x2e, 0xfe, 0x16, 0xb6, 0x09, 0xbd, 0xb2, 0x5c, 0x8b, 0x39, 0xcf, 0xa8, 0x4d,
+	0x07, 0x82, 0x71, 0x74, 0x07, 0xca, 0x5c, 0xad, 0xf9, 0x62, 0xb7, 0x4e, 0x0a, 0x24, 0x5c, 0x41,
+	0x3b, 0x50, 0x11, 0xd6, 0x98, 0xba, 0xc2, 0x1c, 0x4f, 0xfc, 0x98, 0xd7, 0x4e, 0x0a, 0x64, 0xbe,
+	0x74, 0x0c, 0x50, 0x76, 0x15, 0x13, 0x7e, 0x0e, 0xb5, 0xd9, 0xa4, 0x14, 0x28, 0x64, 0x8c, 0x67,
+	0x07, 0xc0, 0x9e, 0x39, 0x42, 0x3c, 0x71, 0xec, 0xa9, 0x2f, 0x51, 0x26, 0x91, 0x15, 0x7c, 0x01,
+	0xf5, 0x04, 0xab, 0xb2, 0xc9, 0x13, 0xa8, 0x04, 0xdb, 0x0c, 0xfc, 0x71, 0x2f, 0xfb, 0x94, 0xa0,
+	0x90, 0x64, 0xce, 0x81, 0x7f, 0x94, 0x1d, 0xf8, 0x99, 0x63, 0x4e, 0xdc, 0x0b, 0x26, 0xb2, 0x6d,
+	0xff, 0x41, 0x24, 0x7d, 0xd2, 0x13, 0x4b, 0x8a, 0x92, 0x4c, 0xfe, 0x3c, 0xd1, 0xf8, 0x57, 0x0d,
+	0x6a, 0x5d, 0x6b, 0x34, 0xca, 0x99, 0xbd, 0xaf, 0xe0, 0xd6, 0x88, 0xb3, 0xf1, 0x0d, 0xa4, 0x7d,
+	0xdc, 0xcc, 0x4d, 0x82, 0x35, 0xd7, 0x72, 0xa3, 0x8b, 0x82, 0xe1, 0x9f, 0xa0, 0x41, 0x98, 0x6d,
+	0x9f, 0x99, 0x83, 0xcb, 0x30, 0xab, 0xef, 0x32, 0x65, 0x7f, 0x68, 0x50, 0xeb, 0x8f, 0x27, 0x8c,
+	0x8b, 0x84, 0x7c, 0x07, 0xd6, 0x95, 0x9a, 0xea, 0xf2, 0x39, 0xa6, 0xc7, 0x00, 0xf9, 0x9f, 0x9e,
+	0xf6, 0xe8, 0x43, 0x58, 0x1f, 0xf2, 0xe9, 0x6b, 0xee, 0x39, 0x7e, 0xae, 0xcb, 0xa4, 0x34, 0xe4,
+	0x53, 0xe2, 0x39, 0xed, 0xbf, 0xab, 0xd0, 0x78, 0x1c, 0xde, 0x71, 0x3a, 0x11, 0x4e, 0xf4, 0x12,
+	0x36, 0xe3, 0x17, 0x0f, 0xf4, 0x7e, 0x6c, 0x03, 0x2f, 0x98, 0x35, 0xd4, 0xf7, 0xaf, 0xdf, 0x53,
+	0xfa, 0xad, 0x05, 0x17, 0x90, 0x07, 0x9b, 0xf1, 0xf9, 0x1b, 0x2d, 0x89, 0x2c, 0xf5, 0x26, 0xa0,
+	0xef, 0x67, 0x07, 0x84, 0xb2, 0x2f, 0x60, 0x33, 0x3e, 0x86, 0x2f, 0x93, 0x4d, 0x1d, 0xd8, 0xf5,
+	0xc5, 0x04, 0x48, 0xde, 0xf8, 0xc8, 0xbd, 0x8c, 0x37, 0x75, 0x38, 0x4f, 0xe7, 0x15, 0x70, 0x3b,
+	0x7a, 0x7b, 0x43, 0x77, 0x97, 0xa4, 0x7a, 0xf1, 0x96, 0xa7, 0xe7, 0xbb, 0x82, 0x11, 0xea, 0x7a,
+	0xb6, 0xc0, 0x05, 0xc4, 0xa1, 0x1a, 0x1b, 0xa8, 0x50, 0x2b, 0xf3, 0xe4, 0x25, 0x75, 0x8d, 0x9c,
+	0x93, 0x5a, 0xd4, 0x10, 0xa1, 0xe8, 0x4a, 0x43, 0x24, 0x55, 0xf7, 0xb3, 0x03, 0xa2, 0xb2, 0xf1,
+	0xae, 0xbd, 0xda, 0x10, 0x39, 0x64, 0xd3, 0x07, 0x82, 0xa8, 0x5f, 0xb2, 0xc8, 0xa6, 0x76, 0xe8,
+	0x74, 0xbf, 0xb8, 0xd2, 0x2f, 0x21, 0xeb, 0x0a, 0xbf, 0x24, 0x39, 0x73, 0x5d, 0xdd, 0x42, 0xbb,
+	0x78, 0x50, 0xed, 0x30, 0xcf, 0x11, 0x37, 0x55, 0x3d, 0xc8, 0xaa, 0xea, 0xab, 0x44, 0x5d, 0x1a,
+	0x6b, 0xca, 0xcb, 0x5c, 0x9a, 0x36, 0x13, 0xe8, 0x46, 0xe6, 0xf7, 0xc3, 0xba, 0x71, 0xb8, 0x1d,
+	0x6d, 0xcf, 0xab, 0x22, 0x4d, 0xb4, 0x71, 0x3d, 0xfb, 0x6c, 0x10, 0x20, 0x71, 0x01, 0x4d, 0xa0,
+	0x1a, 0x6b, 0xca, 0xcb, 0xe2, 0x4c, 0xeb, 0xde, 0xfa, 0xdd, 0xcc, 0xaa, 0x33, 0xb8, 0x7f, 0xea,
+	0x6c, 0x25, 0x9a, 0x2a, 0x5a, 0x62, 0xf2, 0xf4, 0xfe, 0x9b, 0x5f, 0x75, 0x02, 0xd5, 0x58, 0x27,
+	0x5d, 0x16, 0x67, 0x5a, 0xcb, 0xcd, 0xad, 0x78, 0x7c, 0xf0, 0xea, 0x9e, 0x8f, 0x30, 0x72, 0xfc,
+	0x37, 0xf0, 0xac, 0xe4, 0xff, 0x3d, 0xf8, 0x77, 0x00, 0xe5, 0x11, 0x1a, 0x4e, 0x43, 0x14, 0x00,
+	0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -831,6 +1234,20 @@ type NorthboundConfiguratorClient interface {
 	LoadEntities(ctx context.Context, in *LoadEntitiesRequest, opts ...grpc.CallOption) (*storage.EntityLoadResult, error)
 	// CountEntities counts the number of Entities specified by the request
 	CountEntities(ctx context.Context, in *LoadEntitiesRequest, opts ...grpc.CallOption) (*storage.EntityCountResult, error)
+	// ListRevisions fetches the revision history of a network
+	ListRevisions(ctx context.Context, in *ListRevisionsRequest, opts ...grpc.CallOption) (*ListRevisionsResponse, error)
+	// LoadSnapshot fetches the state of a network and its entity graph as of
+	// a revision
+	LoadSnapshot(ctx context.Context, in *LoadSnapshotRequest, opts ...grpc.CallOption) (*storage.NetworkSnapshot, error)
+	// DiffRevisions fetches the changes to a network between two revisions
+	DiffRevisions(ctx context.Context, in *DiffRevisionsRequest, opts ...grpc.CallOption) (*storage.NetworkDiff, error)
+	// RollbackNetwork returns a network and its entity graph to their state
+	// as of a revision, and returns the changes that were made
+	RollbackNetwork(ctx context.Context, in *RollbackNetworkRequest, opts ...grpc.CallOption) (*storage.NetworkDiff, error)
+	// ImportNetwork brings a network and its entity graph to the state
+	// described by the request in a single transaction, and returns the
+	// changes that were (or, for a dry run, would have been) made
+	ImportNetwork(ctx context.Context, in *ImportNetworkRequest, opts ...grpc.CallOption) (*storage.NetworkDiff, error)
 }
 
 type northboundConfiguratorClient struct {
@@ -940,6 +1357,51 @@ func (c *northboundConfiguratorClient) CountEntities(ctx context.Context, in *Lo
 	return out, nil
 }
 
+func (c *northboundConfiguratorClient) ListRevisions(ctx context.Context, in *ListRevisionsRequest, opts ...grpc.CallOption) (*ListRevisionsResponse, error) {
+	out := new(ListRevisionsResponse)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.configurator.NorthboundConfigurator/ListRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *northboundConfiguratorClient) LoadSnapshot(ctx context.Context, in *LoadSnapshotRequest, opts ...grpc.CallOption) (*storage.NetworkSnapshot, error) {
+	out := new(storage.NetworkSnapshot)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.configurator.NorthboundConfigurator/LoadSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *northboundConfiguratorClient) DiffRevisions(ctx context.Context, in *DiffRevisionsRequest, opts ...grpc.CallOption) (*storage.NetworkDiff, error) {
+	out := new(storage.NetworkDiff)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.configurator.NorthboundConfigurator/DiffRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *northboundConfiguratorClient) RollbackNetwork(ctx context.Context, in *RollbackNetworkRequest, opts ...grpc.CallOption) (*storage.NetworkDiff, error) {
+	out := new(storage.NetworkDiff)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.configurator.NorthboundConfigurator/RollbackNetwork", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *northboundConfiguratorClient) ImportNetwork(ctx context.Context, in *ImportNetworkRequest, opts ...grpc.CallOption) (*storage.NetworkDiff, error) {
+	out := new(storage.NetworkDiff)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.configurator.NorthboundConfigurator/ImportNetwork", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NorthboundConfiguratorServer is the server API for NorthboundConfigurator service.
 type NorthboundConfiguratorServer interface {
 	// ListNetworkIDs fetches the list of networkIDs registered
@@ -965,6 +1427,20 @@ type NorthboundConfiguratorServer interface {
 	LoadEntities(context.Context, *LoadEntitiesRequest) (*storage.EntityLoadResult, error)
 	// CountEntities counts the number of Entities specified by the request
 	CountEntities(context.Context, *LoadEntitiesRequest) (*storage.EntityCountResult, error)
+	// ListRevisions fetches the revision history of a network
+	ListRevisions(context.Context, *ListRevisionsRequest) (*ListRevisionsResponse, error)
+	// LoadSnapshot fetches the state of a network and its entity graph as of
+	// a revision
+	LoadSnapshot(context.Context, *LoadSnapshotRequest) (*storage.NetworkSnapshot, error)
+	// DiffRevisions fetches the changes to a network between two revisions
+	DiffRevisions(context.Context, *DiffRevisionsRequest) (*storage.NetworkDiff, error)
+	// RollbackNetwork returns a network and its entity graph to their state
+	// as of a revision, and returns the changes that were made
+	RollbackNetwork(context.Context, *RollbackNetworkRequest) (*storage.NetworkDiff, error)
+	// ImportNetwork brings a network and its entity graph to the state
+	// described by the request in a single transaction, and returns the
+	// changes that were (or, for a dry run, would have been) made
+	ImportNetwork(context.Context, *ImportNetworkRequest) (*storage.NetworkDiff, error)
 }
 
 // UnimplementedNorthboundConfiguratorServer can be embedded to have forward compatible implementations.
@@ -1004,6 +1480,21 @@ func (*UnimplementedNorthboundConfiguratorServer) LoadEntities(ctx context.Conte
 func (*UnimplementedNorthboundConfiguratorServer) CountEntities(ctx context.Context, req *LoadEntitiesRequest) (*storage.EntityCountResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CountEntities not implemented")
 }
+func (*UnimplementedNorthboundConfiguratorServer) ListRevisions(ctx context.Context, req *ListRevisionsRequest) (*ListRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRevisions not implemented")
+}
+func (*UnimplementedNorthboundConfiguratorServer) LoadSnapshot(ctx context.Context, req *LoadSnapshotRequest) (*storage.NetworkSnapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadSnapshot not implemented")
+}
+func (*UnimplementedNorthboundConfiguratorServer) DiffRevisions(ctx context.Context, req *DiffRevisionsRequest) (*storage.NetworkDiff, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffRevisions not implemented")
+}
+func (*UnimplementedNorthboundConfiguratorServer) RollbackNetwork(ctx context.Context, req *RollbackNetworkRequest) (*storage.NetworkDiff, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackNetwork not implemented")
+}
+func (*UnimplementedNorthboundConfiguratorServer) ImportNetwork(ctx context.Context, req *ImportNetworkRequest) (*storage.NetworkDiff, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportNetwork not implemented")
+}
 
 func RegisterNorthboundConfiguratorServer(s *grpc.Server, srv NorthboundConfiguratorServer) {
 	s.RegisterService(&_NorthboundConfigurator_serviceDesc, srv)
@@ -1207,6 +1698,96 @@ func _NorthboundConfigurator_CountEntities_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NorthboundConfigurator_ListRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NorthboundConfiguratorServer).ListRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.configurator.NorthboundConfigurator/ListRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NorthboundConfiguratorServer).ListRevisions(ctx, req.(*ListRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NorthboundConfigurator_LoadSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NorthboundConfiguratorServer).LoadSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.configurator.NorthboundConfigurator/LoadSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NorthboundConfiguratorServer).LoadSnapshot(ctx, req.(*LoadSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NorthboundConfigurator_DiffRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NorthboundConfiguratorServer).DiffRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.configurator.NorthboundConfigurator/DiffRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NorthboundConfiguratorServer).DiffRevisions(ctx, req.(*DiffRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NorthboundConfigurator_RollbackNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackNetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NorthboundConfiguratorServer).RollbackNetwork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.configurator.NorthboundConfigurator/RollbackNetwork",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NorthboundConfiguratorServer).RollbackNetwork(ctx, req.(*RollbackNetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NorthboundConfigurator_ImportNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportNetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NorthboundConfiguratorServer).ImportNetwork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.configurator.NorthboundConfigurator/ImportNetwork",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NorthboundConfiguratorServer).ImportNetwork(ctx, req.(*ImportNetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _NorthboundConfigurator_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "magma.orc8r.configurator.NorthboundConfigurator",
 	HandlerType: (*NorthboundConfiguratorServer)(nil),
@@ -1255,6 +1836,26 @@ var _NorthboundConfigurator_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CountEntities",
 			Handler:    _NorthboundConfigurator_CountEntities_Handler,
 		},
+		{
+			MethodName: "ListRevisions",
+			Handler:    _NorthboundConfigurator_ListRevisions_Handler,
+		},
+		{
+			MethodName: "LoadSnapshot",
+			Handler:    _NorthboundConfigurator_LoadSnapshot_Handler,
+		},
+		{
+			MethodName: "DiffRevisions",
+			Handler:    _NorthboundConfigurator_DiffRevisions_Handler,
+		},
+		{
+			MethodName: "RollbackNetwork",
+			Handler:    _NorthboundConfigurator_RollbackNetwork_Handler,
+		},
+		{
+			MethodName: "ImportNetwork",
+			Handler:    _NorthboundConfigurator_ImportNetwork_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "orc8r/cloud/go/services/configurator/protos/northbound.proto",
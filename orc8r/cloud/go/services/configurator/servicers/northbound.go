@@ -16,12 +16,15 @@ package servicers
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"magma/orc8r/cloud/go/services/configurator/protos"
 	"magma/orc8r/cloud/go/services/configurator/storage"
 	orc8rStorage "magma/orc8r/cloud/go/storage"
+	merrors "magma/orc8r/lib/go/errors"
 	commonProtos "magma/orc8r/lib/go/protos"
 
+	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -181,6 +184,9 @@ func (srv *nbConfiguratorServicer) WriteEntities(context context.Context, req *p
 			updatedEnt, err := store.UpdateEntity(req.NetworkID, *op.Update)
 			if err != nil {
 				storage.RollbackLogOnError(store)
+				if conflictErr := versionConflictError(err); conflictErr != nil {
+					return emptyRes, conflictErr
+				}
 				return emptyRes, status.Error(codes.Internal, err.Error())
 			}
 			ret.UpdatedEntities[updatedEnt.Key] = &updatedEnt
@@ -189,6 +195,11 @@ func (srv *nbConfiguratorServicer) WriteEntities(context context.Context, req *p
 			return emptyRes, status.Error(codes.InvalidArgument, fmt.Sprintf("write request %T not recognized", write))
 		}
 	}
+	err = checkTenantQuotas(context, store, req.NetworkID, ret.CreatedEntities)
+	if err != nil {
+		storage.RollbackLogOnError(store)
+		return emptyRes, err
+	}
 	return ret, store.Commit()
 }
 
@@ -208,6 +219,11 @@ func (srv *nbConfiguratorServicer) CreateEntities(context context.Context, req *
 		}
 		createdEntities = append(createdEntities, &createdEntity)
 	}
+	err = checkTenantQuotas(context, store, req.NetworkID, createdEntities)
+	if err != nil {
+		storage.RollbackLogOnError(store)
+		return emptyRes, err
+	}
 	return &protos.CreateEntitiesResponse{CreatedEntities: createdEntities}, store.Commit()
 }
 
@@ -223,6 +239,9 @@ func (srv *nbConfiguratorServicer) UpdateEntities(context context.Context, req *
 		updatedEntity, err := store.UpdateEntity(req.NetworkID, *update)
 		if err != nil {
 			storage.RollbackLogOnError(store)
+			if conflictErr := versionConflictError(err); conflictErr != nil {
+				return emptyRes, conflictErr
+			}
 			return emptyRes, err
 		}
 		updatedEntities[update.Key] = &updatedEntity
@@ -251,3 +270,212 @@ func (srv *nbConfiguratorServicer) DeleteEntities(context context.Context, req *
 	}
 	return void, store.Commit()
 }
+
+func (srv *nbConfiguratorServicer) ListRevisions(context context.Context, req *protos.ListRevisionsRequest) (*protos.ListRevisionsResponse, error) {
+	emptyRes := &protos.ListRevisionsResponse{}
+	store, err := srv.factory.StartTransaction(context, &orc8rStorage.TxOptions{ReadOnly: true})
+	if err != nil {
+		return emptyRes, err
+	}
+
+	if req.LatestOnly {
+		revision, err := store.GetRevisionAt(req.NetworkID, math.MaxInt64)
+		if errors.Cause(err) == merrors.ErrNotFound {
+			return emptyRes, store.Commit()
+		}
+		if err != nil {
+			storage.RollbackLogOnError(store)
+			return emptyRes, err
+		}
+		return &protos.ListRevisionsResponse{Revisions: []*storage.NetworkRevision{&revision}}, store.Commit()
+	}
+
+	revisions, err := store.ListRevisions(req.NetworkID)
+	if err != nil {
+		storage.RollbackLogOnError(store)
+		return emptyRes, err
+	}
+	return &protos.ListRevisionsResponse{Revisions: revisions}, store.Commit()
+}
+
+func (srv *nbConfiguratorServicer) LoadSnapshot(context context.Context, req *protos.LoadSnapshotRequest) (*storage.NetworkSnapshot, error) {
+	emptyRes := &storage.NetworkSnapshot{}
+	store, err := srv.factory.StartTransaction(context, &orc8rStorage.TxOptions{ReadOnly: true})
+	if err != nil {
+		return emptyRes, err
+	}
+
+	snapshot, err := loadSnapshot(store, req.NetworkID, req.Revision)
+	if err != nil {
+		storage.RollbackLogOnError(store)
+		return emptyRes, revisionError(err)
+	}
+	return &snapshot, store.Commit()
+}
+
+func (srv *nbConfiguratorServicer) DiffRevisions(context context.Context, req *protos.DiffRevisionsRequest) (*storage.NetworkDiff, error) {
+	emptyRes := &storage.NetworkDiff{}
+	store, err := srv.factory.StartTransaction(context, &orc8rStorage.TxOptions{ReadOnly: true})
+	if err != nil {
+		return emptyRes, err
+	}
+
+	from, err := loadSnapshot(store, req.NetworkID, req.From)
+	if err != nil {
+		storage.RollbackLogOnError(store)
+		return emptyRes, revisionError(err)
+	}
+	to, err := loadSnapshot(store, req.NetworkID, req.To)
+	if err != nil {
+		storage.RollbackLogOnError(store)
+		return emptyRes, revisionError(err)
+	}
+	diff := storage.DiffSnapshots(from, to)
+	return &diff, store.Commit()
+}
+
+func (srv *nbConfiguratorServicer) RollbackNetwork(context context.Context, req *protos.RollbackNetworkRequest) (*storage.NetworkDiff, error) {
+	emptyRes := &storage.NetworkDiff{}
+	store, err := srv.factory.StartTransaction(context, &orc8rStorage.TxOptions{ReadOnly: false})
+	if err != nil {
+		return emptyRes, err
+	}
+
+	revision, err := resolveRevision(store, req.NetworkID, req.Revision)
+	if err != nil {
+		storage.RollbackLogOnError(store)
+		return emptyRes, revisionError(err)
+	}
+	diff, err := storage.RollbackNetwork(store, req.NetworkID, revision)
+	if err != nil {
+		storage.RollbackLogOnError(store)
+		return emptyRes, revisionError(err)
+	}
+	err = checkTenantQuotas(context, store, req.NetworkID, getCreatedEntities(diff))
+	if err != nil {
+		storage.RollbackLogOnError(store)
+		return emptyRes, err
+	}
+	return &diff, store.Commit()
+}
+
+func (srv *nbConfiguratorServicer) ImportNetwork(context context.Context, req *protos.ImportNetworkRequest) (*storage.NetworkDiff, error) {
+	emptyRes := &storage.NetworkDiff{}
+	if err := validateImportNetworkRequest(req); err != nil {
+		return emptyRes, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// Dry runs still write, so the import is checked against the store's
+	// constraints, but roll back instead of committing
+	store, err := srv.factory.StartTransaction(context, &orc8rStorage.TxOptions{ReadOnly: false})
+	if err != nil {
+		return emptyRes, err
+	}
+
+	diff, err := storage.ApplySnapshot(store, storage.NetworkSnapshot{Network: req.Network, Entities: req.Entities})
+	if err != nil {
+		storage.RollbackLogOnError(store)
+		return emptyRes, err
+	}
+	err = checkTenantQuotas(context, store, req.Network.ID, getCreatedEntities(diff))
+	if err != nil {
+		storage.RollbackLogOnError(store)
+		return emptyRes, err
+	}
+	if req.DryRun {
+		return &diff, store.Rollback()
+	}
+	return &diff, store.Commit()
+}
+
+// getCreatedEntities returns the entities created by the changes in diff.
+func getCreatedEntities(diff storage.NetworkDiff) []*storage.NetworkEntity {
+	var created []*storage.NetworkEntity
+	for _, entDiff := range diff.Entities {
+		if entDiff.Before == nil {
+			created = append(created, entDiff.After)
+		}
+	}
+	return created
+}
+
+func validateImportNetworkRequest(req *protos.ImportNetworkRequest) error {
+	networkID := req.Network.GetID()
+	if networkID == "" {
+		return errors.New("network ID must be non-empty")
+	}
+	if networkID == storage.InternalNetworkID {
+		return errors.New("cannot import the internal network")
+	}
+	seen := map[orc8rStorage.TypeAndKey]bool{}
+	for _, ent := range req.Entities {
+		tk := ent.GetTypeAndKey()
+		if tk.Type == "" || tk.Key == "" {
+			return errors.New("entity type and key must be non-empty")
+		}
+		if seen[tk] {
+			return errors.Errorf("entity %s is included more than once", tk)
+		}
+		seen[tk] = true
+	}
+	for _, ent := range req.Entities {
+		for _, assoc := range ent.Associations {
+			if !seen[assoc.ToTypeAndKey()] {
+				return errors.Errorf("entity %s is associated to %s, which is not included", ent.GetTypeAndKey(), assoc.ToTypeAndKey())
+			}
+		}
+	}
+	return nil
+}
+
+func loadSnapshot(store storage.ConfiguratorStorage, networkID string, selector *protos.RevisionSelector) (storage.NetworkSnapshot, error) {
+	revision, err := resolveRevision(store, networkID, selector)
+	if err != nil {
+		return storage.NetworkSnapshot{}, err
+	}
+	return store.LoadSnapshot(networkID, revision)
+}
+
+// resolveRevision returns the revision number identified by the selector,
+// where 0 is the latest revision.
+func resolveRevision(store storage.ConfiguratorStorage, networkID string, selector *protos.RevisionSelector) (uint64, error) {
+	switch sel := selector.GetSelector().(type) {
+	case nil:
+		return 0, nil
+	case *protos.RevisionSelector_Revision:
+		return sel.Revision, nil
+	case *protos.RevisionSelector_Timestamp:
+		rev, err := store.GetRevisionAt(networkID, sel.Timestamp)
+		if err != nil {
+			return 0, err
+		}
+		return rev.Revision, nil
+	default:
+		return 0, status.Errorf(codes.InvalidArgument, "revision selector %T not recognized", sel)
+	}
+}
+
+func revisionError(err error) error {
+	if errors.Cause(err) == merrors.ErrNotFound {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return err
+}
+
+// versionConflictError converts a version conflict to an Aborted status,
+// with the stored version of each mismatched entity attached as details.
+// Returns nil if err isn't a version conflict.
+func versionConflictError(err error) error {
+	conflict, ok := errors.Cause(err).(*orc8rStorage.VersionConflictError)
+	if !ok {
+		return nil
+	}
+	st := status.New(codes.Aborted, conflict.Error())
+	for _, id := range conflict.IDs() {
+		withDetail, err := st.WithDetails(&storage.NetworkEntity{Type: id.Type, Key: id.Key, Version: conflict.Versions[id]})
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		st = withDetail
+	}
+	return st.Err()
+}
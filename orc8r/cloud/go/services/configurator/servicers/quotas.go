@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicers
+
+import (
+	"context"
+
+	"magma/orc8r/cloud/go/services/configurator/storage"
+	"magma/orc8r/cloud/go/services/tenants"
+	merrors "magma/orc8r/lib/go/errors"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// checkTenantQuotas verifies the tenant owning the network, if any, remains
+// within its quotas for the types of the created entities.
+// The tenant's networks are locked before counting, serializing concurrent
+// writes to the tenant's networks, and entities are counted within the
+// store's transaction, so counts include the created entities.
+// If the network's tenant can't be determined, the write is rejected as
+// unavailable rather than risk exceeding the tenant's quotas.
+func checkTenantQuotas(ctx context.Context, store storage.ConfiguratorStorage, networkID string, created []*storage.NetworkEntity) error {
+	quotaByType, err := tenants.GetQuotaEntityTypes()
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "get tenant quota entity types: %s", err)
+	}
+	createdTypes := map[string]bool{}
+	for _, ent := range created {
+		if _, ok := quotaByType[ent.Type]; ok {
+			createdTypes[ent.Type] = true
+		}
+	}
+	if len(createdTypes) == 0 {
+		return nil
+	}
+
+	tenantID, tenant, err := tenants.GetNetworkTenant(ctx, networkID)
+	if err == merrors.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "get tenant of network %s for quota check: %s", networkID, err)
+	}
+	err = store.LockNetworks(tenant.Networks)
+	if err != nil {
+		return err
+	}
+
+	for entityType := range createdTypes {
+		limit := tenants.GetQuotaLimit(tenant.Quotas, quotaByType[entityType])
+		if limit == 0 {
+			continue
+		}
+		var count uint64
+		for _, nid := range tenant.Networks {
+			res, err := store.CountEntities(nid, storage.EntityLoadFilter{TypeFilter: &wrappers.StringValue{Value: entityType}}, storage.EntityLoadCriteria{})
+			if err != nil {
+				return err
+			}
+			count += res.Count
+		}
+		if count > uint64(limit) {
+			return tenants.NewQuotaExceededError(tenantID, entityType+" entities", limit)
+		}
+	}
+	return nil
+}
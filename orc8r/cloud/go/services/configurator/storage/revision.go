@@ -0,0 +1,236 @@
+/*
+ * Copyright 2020 The Magma Authors.
+ *
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"sort"
+
+	"magma/orc8r/cloud/go/storage"
+	merrors "magma/orc8r/lib/go/errors"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/pkg/errors"
+)
+
+// DiffSnapshots returns the changes to a network between two snapshots.
+// Edges are owned by the entity they originate from, so an entity is only
+// considered changed if its fields or outgoing associations changed. Versions
+// and other internal fields are ignored.
+func DiffSnapshots(from, to NetworkSnapshot) NetworkDiff {
+	diff := NetworkDiff{From: from.Revision, To: to.Revision}
+	if !proto.Equal(comparableNetwork(from.Network), comparableNetwork(to.Network)) {
+		diff.NetworkBefore = from.Network
+		diff.NetworkAfter = to.Network
+	}
+
+	fromByTK := snapshotEntitiesByTK(from)
+	toByTK := snapshotEntitiesByTK(to)
+	var tks storage.TKs
+	for tk := range fromByTK {
+		tks = append(tks, tk)
+	}
+	for tk := range toByTK {
+		if _, ok := fromByTK[tk]; !ok {
+			tks = append(tks, tk)
+		}
+	}
+	sort.Slice(tks, func(i, j int) bool { return tks[i].IsLessThan(tks[j]) })
+
+	for _, tk := range tks {
+		before, after := fromByTK[tk], toByTK[tk]
+		if before != nil && after != nil && proto.Equal(comparableEntity(before), comparableEntity(after)) {
+			continue
+		}
+		diff.Entities = append(diff.Entities, &EntityDiff{
+			ID:     (&EntityID{}).FromTypeAndKey(tk),
+			Before: before,
+			After:  after,
+		})
+	}
+	return diff
+}
+
+// RollbackNetwork writes the changes needed to return a network and its entity
+// graph to their state as of a revision, returning the changes made. The
+// rollback doesn't rewrite history - it's recorded as a new revision when the
+// store's transaction is committed.
+func RollbackNetwork(store ConfiguratorStorage, networkID string, revision uint64) (NetworkDiff, error) {
+	target, err := store.LoadSnapshot(networkID, revision)
+	if err != nil {
+		return NetworkDiff{}, errors.Wrapf(err, "failed to load revision %d", revision)
+	}
+	return ApplySnapshot(store, target)
+}
+
+// ApplySnapshot writes the changes needed to bring a network and its entity
+// graph to the state described by a snapshot, returning the changes made.
+// The network is created if it doesn't exist, and entities not in the
+// snapshot are deleted.
+func ApplySnapshot(store ConfiguratorStorage, target NetworkSnapshot) (NetworkDiff, error) {
+	networkID := target.Network.GetID()
+	if networkID == "" {
+		return NetworkDiff{}, errors.New("snapshot must include a network")
+	}
+	current, err := store.LoadSnapshot(networkID, 0)
+	if errors.Cause(err) == merrors.ErrNotFound {
+		current = NetworkSnapshot{}
+	} else if err != nil {
+		return NetworkDiff{}, errors.Wrap(err, "failed to load latest revision")
+	}
+	diff := DiffSnapshots(current, target)
+
+	switch {
+	case diff.NetworkAfter != nil && diff.NetworkBefore == nil:
+		_, err = store.CreateNetwork(*diff.NetworkAfter)
+	case diff.NetworkAfter != nil:
+		err = store.UpdateNetworks([]NetworkUpdateCriteria{getNetworkUpdateToTarget(diff.NetworkBefore, diff.NetworkAfter)})
+	}
+	if err != nil {
+		return NetworkDiff{}, errors.Wrap(err, "failed to write network")
+	}
+
+	// Write entities in two passes so edges are only created once both of
+	// their endpoints exist
+	//	- First, delete, create (without edges), and update fields
+	//	- Then, set edges of all created and updated entities
+	for _, entDiff := range diff.Entities {
+		var err error
+		switch {
+		case entDiff.After == nil:
+			_, err = store.UpdateEntity(networkID, EntityUpdateCriteria{Type: entDiff.ID.Type, Key: entDiff.ID.Key, DeleteEntity: true})
+		case entDiff.Before == nil:
+			_, err = store.CreateEntity(networkID, NetworkEntity{
+				Type:        entDiff.After.Type,
+				Key:         entDiff.After.Key,
+				Name:        entDiff.After.Name,
+				Description: entDiff.After.Description,
+				PhysicalID:  entDiff.After.PhysicalID,
+				Config:      entDiff.After.Config,
+			})
+		default:
+			_, err = store.UpdateEntity(networkID, getEntityUpdateToTarget(entDiff.Before, entDiff.After))
+		}
+		if err != nil {
+			return NetworkDiff{}, errors.Wrapf(err, "failed to write entity %s", entDiff.ID.ToTypeAndKey())
+		}
+	}
+	for _, entDiff := range diff.Entities {
+		if entDiff.After == nil {
+			continue
+		}
+		var beforeAssocs []*EntityID
+		if entDiff.Before != nil {
+			beforeAssocs = entDiff.Before.Associations
+		}
+		if idsEqual(beforeAssocs, entDiff.After.Associations) {
+			continue
+		}
+		_, err := store.UpdateEntity(networkID, EntityUpdateCriteria{
+			Type:              entDiff.ID.Type,
+			Key:               entDiff.ID.Key,
+			AssociationsToSet: &EntityAssociationsToSet{AssociationsToSet: entDiff.After.Associations},
+		})
+		if err != nil {
+			return NetworkDiff{}, errors.Wrapf(err, "failed to write associations of entity %s", entDiff.ID.ToTypeAndKey())
+		}
+	}
+
+	return diff, nil
+}
+
+func snapshotEntitiesByTK(snapshot NetworkSnapshot) map[storage.TypeAndKey]*NetworkEntity {
+	ret := make(map[storage.TypeAndKey]*NetworkEntity, len(snapshot.Entities))
+	for _, ent := range snapshot.Entities {
+		ret[ent.GetTypeAndKey()] = ent
+	}
+	return ret
+}
+
+func comparableNetwork(network *Network) *Network {
+	if network == nil {
+		return nil
+	}
+	ret := proto.Clone(network).(*Network)
+	ret.Version = 0
+	return ret
+}
+
+func comparableEntity(ent *NetworkEntity) *NetworkEntity {
+	ret := proto.Clone(ent).(*NetworkEntity)
+	ret.NetworkID, ret.Pk, ret.GraphID = "", "", ""
+	ret.ParentAssociations = nil
+	ret.Version = 0
+	ret.Associations = sortedIDs(ret.Associations)
+	return ret
+}
+
+func idsEqual(a, b []*EntityID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = sortedIDs(a), sortedIDs(b)
+	for i := range a {
+		if a[i].ToTypeAndKey() != b[i].ToTypeAndKey() {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedIDs(ids []*EntityID) []*EntityID {
+	ret := append([]*EntityID{}, ids...)
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ToTypeAndKey().IsLessThan(ret[j].ToTypeAndKey()) })
+	return ret
+}
+
+// getEntityUpdateToTarget returns an update which sets the fields of an entity
+// which differ from the target. Associations are updated separately.
+func getEntityUpdateToTarget(current, target *NetworkEntity) EntityUpdateCriteria {
+	update := EntityUpdateCriteria{Type: target.Type, Key: target.Key}
+	if current.Name != target.Name {
+		update.NewName = &wrappers.StringValue{Value: target.Name}
+	}
+	if current.Description != target.Description {
+		update.NewDescription = &wrappers.StringValue{Value: target.Description}
+	}
+	if current.PhysicalID != target.PhysicalID {
+		update.NewPhysicalID = &wrappers.StringValue{Value: target.PhysicalID}
+	}
+	if !bytes.Equal(current.Config, target.Config) {
+		update.NewConfig = &wrappers.BytesValue{Value: target.Config}
+	}
+	return update
+}
+
+func getNetworkUpdateToTarget(current, target *Network) NetworkUpdateCriteria {
+	update := NetworkUpdateCriteria{
+		ID:                   target.ID,
+		NewName:              &wrappers.StringValue{Value: target.Name},
+		NewDescription:       &wrappers.StringValue{Value: target.Description},
+		NewType:              &wrappers.StringValue{Value: target.Type},
+		ConfigsToAddOrUpdate: map[string][]byte{},
+	}
+	for configType, value := range target.Configs {
+		update.ConfigsToAddOrUpdate[configType] = value
+	}
+	for configType := range current.Configs {
+		if _, ok := target.Configs[configType]; !ok {
+			update.ConfigsToDelete = append(update.ConfigsToDelete, configType)
+		}
+	}
+	sort.Strings(update.ConfigsToDelete)
+	return update
+}
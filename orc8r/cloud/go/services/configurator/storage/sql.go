@@ -22,6 +22,7 @@ import (
 
 	"magma/orc8r/cloud/go/sqorc"
 	"magma/orc8r/cloud/go/storage"
+	merrors "magma/orc8r/lib/go/errors"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
@@ -34,6 +35,11 @@ const (
 
 	entityTable      = "cfg_entities"
 	entityAssocTable = "cfg_assocs"
+
+	revisionTable        = "cfg_revisions"
+	revisionHeadTable    = "cfg_revision_heads"
+	networkRevisionTable = "cfg_network_revisions"
+	entityRevisionTable  = "cfg_entity_revisions"
 )
 
 const (
@@ -60,6 +66,14 @@ const (
 
 	aFrCol = "from_pk"
 	aToCol = "to_pk"
+
+	revNidCol       = "network_id"
+	revRevCol       = "revision"
+	revCreatedAtCol = "created_at"
+	revTypeCol      = "type"
+	revKeyCol       = "\"key\""
+	revDeletedCol   = "deleted"
+	revValCol       = "value"
 )
 
 // NewSQLConfiguratorStorageFactory returns a ConfiguratorStorageFactory
@@ -115,8 +129,9 @@ func (fact *sqlConfiguratorStorageFactory) InitializeServiceStorage() (err error
 	// tables that are already created will also have the type column.
 	// TODO Remove after 1-2 months to ensure service isn't disrupted
 	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s text", networksTable, nwTypeCol))
-	// special case sqlite3 because ADD COLUMN IF NOT EXISTS is not supported
-	// and we only run sqlite3 for unit tests
+	// special case sqlite3 because ADD COLUMN IF NOT EXISTS is not supported.
+	// sqlite3 support postdates the type column, so the column was created
+	// with the table
 	if err != nil && os.Getenv("SQL_DRIVER") != "sqlite3" {
 		err = errors.Wrap(err, "failed to add 'type' field to networks table")
 	}
@@ -208,6 +223,15 @@ func (fact *sqlConfiguratorStorageFactory) InitializeServiceStorage() (err error
 		return
 	}
 
+	err = initRevisionTables(tx, fact.builder)
+	if err != nil {
+		return
+	}
+
+	// Networks created before revision history was recorded get a baseline
+	// revision of their current state
+	store := fact.newStorage(tx)
+	err = store.recordBaselineRevisions()
 	return
 }
 
@@ -216,7 +240,11 @@ func (fact *sqlConfiguratorStorageFactory) StartTransaction(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	return &sqlConfiguratorStorage{tx: tx, idGenerator: fact.idGenerator, builder: fact.builder, maxEntityLoadSize: fact.maxEntityLoadSize}, nil
+	return fact.newStorage(tx), nil
+}
+
+func (fact *sqlConfiguratorStorageFactory) newStorage(tx *sql.Tx) *sqlConfiguratorStorage {
+	return &sqlConfiguratorStorage{tx: tx, idGenerator: fact.idGenerator, builder: fact.builder, maxEntityLoadSize: fact.maxEntityLoadSize, changes: revisionChanges{}}
 }
 
 func getSqlOpts(opts *storage.TxOptions) *sql.TxOptions {
@@ -234,9 +262,18 @@ type sqlConfiguratorStorage struct {
 	idGenerator       storage.IDGenerator
 	builder           sqorc.StatementBuilder
 	maxEntityLoadSize uint32
+
+	// changes tracks writes made within the transaction, which are recorded
+	// in the revision history on commit
+	changes revisionChanges
 }
 
 func (store *sqlConfiguratorStorage) Commit() error {
+	err := store.recordRevisions()
+	if err != nil {
+		RollbackLogOnError(store)
+		return err
+	}
 	return store.tx.Commit()
 }
 
@@ -332,6 +369,7 @@ func (store *sqlConfiguratorStorage) CreateNetwork(network Network) (Network, er
 	if err != nil {
 		return Network{}, fmt.Errorf("error inserting network: %s", err)
 	}
+	store.changes.touchNetwork(network.ID)
 
 	if funk.IsEmpty(network.Configs) {
 		return network, nil
@@ -361,6 +399,7 @@ func (store *sqlConfiguratorStorage) UpdateNetworks(updates []NetworkUpdateCrite
 	networksToDelete := []string{}
 	networksToUpdate := []NetworkUpdateCriteria{}
 	for _, update := range updates {
+		store.changes.touchNetwork(update.ID)
 		if update.DeleteNetwork {
 			networksToDelete = append(networksToDelete, update.ID)
 		} else {
@@ -394,6 +433,24 @@ func (store *sqlConfiguratorStorage) UpdateNetworks(updates []NetworkUpdateCrite
 	return nil
 }
 
+func (store *sqlConfiguratorStorage) LockNetworks(networkIDs []string) error {
+	// No-op updates lock the rows in every dialect. Lock one at a time in a
+	// consistent order, so concurrent lockers can't deadlock.
+	sorted := append([]string{}, networkIDs...)
+	sort.Strings(sorted)
+	for _, nid := range sorted {
+		_, err := store.builder.Update(networksTable).
+			Set(nwVerCol, sq.Expr(nwVerCol)).
+			Where(sq.Eq{nwIDCol: nid}).
+			RunWith(store.tx).
+			Exec()
+		if err != nil {
+			return errors.Wrapf(err, "failed to lock network %s", nid)
+		}
+	}
+	return nil
+}
+
 func (store *sqlConfiguratorStorage) CountEntities(networkID string, filter EntityLoadFilter, loadCriteria EntityLoadCriteria) (EntityCountResult, error) {
 	ret := EntityCountResult{Count: 0}
 	count, err := store.countEntities(networkID, filter, loadCriteria)
@@ -521,20 +578,38 @@ func (store *sqlConfiguratorStorage) CreateEntity(networkID string, entity Netwo
 	}
 
 	createdEnt.NetworkID = networkID
+	store.changes.touchEntities(networkID, createdEnt.GetTypeAndKey())
 	return createdEnt, nil
 }
 
 func (store *sqlConfiguratorStorage) UpdateEntity(networkID string, update EntityUpdateCriteria) (NetworkEntity, error) {
 	emptyRet := NetworkEntity{Type: update.Type, Key: update.Key}
 	entToUpdate, err := store.loadEntToUpdate(networkID, update)
+	if update.ExpectedVersion != nil {
+		storedVersion := storage.VersionAbsent
+		if entToUpdate != nil {
+			storedVersion = entToUpdate.Version
+		}
+		if conflictErr := checkEntityVersion(update, storedVersion); conflictErr != nil {
+			return emptyRet, conflictErr
+		}
+	}
 	if err != nil && !update.DeleteEntity {
 		return emptyRet, errors.Wrap(err, "failed to load entity being updated")
 	}
 	if entToUpdate == nil {
 		return emptyRet, nil
 	}
+	store.changes.touchEntities(networkID, update.GetTypeAndKey())
 
 	if update.DeleteEntity {
+		// Deleting the entity also deletes the edges to it, so its parents
+		// change as well
+		err = store.touchParents(networkID, update.GetID())
+		if err != nil {
+			return emptyRet, err
+		}
+
 		// Cascading FK relations in the schema will handle the other tables
 		_, err := store.builder.Delete(entityTable).
 			Where(sq.And{
@@ -617,3 +692,69 @@ func (store *sqlConfiguratorStorage) LoadGraphForEntity(networkID string, entity
 		Edges:        edges,
 	}, nil
 }
+
+func (store *sqlConfiguratorStorage) ListRevisions(networkID string) ([]*NetworkRevision, error) {
+	rows, err := store.builder.Select(revNidCol, revRevCol, revCreatedAtCol).
+		From(revisionTable).
+		Where(sq.Eq{revNidCol: networkID}).
+		OrderBy(revRevCol).
+		RunWith(store.tx).
+		Query()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query revisions")
+	}
+	defer sqorc.CloseRowsLogOnError(rows, "ListRevisions")
+
+	revisions := []*NetworkRevision{}
+	for rows.Next() {
+		rev := &NetworkRevision{}
+		err = rows.Scan(&rev.NetworkID, &rev.Revision, &rev.CreatedAt)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan revision")
+		}
+		revisions = append(revisions, rev)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, errors.Wrap(err, "sql rows err")
+	}
+	return revisions, nil
+}
+
+func (store *sqlConfiguratorStorage) GetRevisionAt(networkID string, timestamp int64) (NetworkRevision, error) {
+	return store.scanRevision(
+		store.builder.Select(revNidCol, revRevCol, revCreatedAtCol).
+			From(revisionTable).
+			Where(sq.And{
+				sq.Eq{revNidCol: networkID},
+				sq.LtOrEq{revCreatedAtCol: timestamp},
+			}).
+			OrderBy(fmt.Sprintf("%s DESC", revRevCol)).
+			Limit(1),
+	)
+}
+
+func (store *sqlConfiguratorStorage) LoadSnapshot(networkID string, revision uint64) (NetworkSnapshot, error) {
+	rev, err := store.getRevision(networkID, revision)
+	if err != nil {
+		return NetworkSnapshot{}, err
+	}
+
+	network, err := store.loadNetworkRevision(networkID, rev.Revision)
+	if err != nil {
+		return NetworkSnapshot{}, err
+	}
+	if network == nil {
+		return NetworkSnapshot{}, merrors.ErrNotFound
+	}
+
+	entsByTK, err := store.loadEntityRevisions(networkID, rev.Revision)
+	if err != nil {
+		return NetworkSnapshot{}, err
+	}
+	fillSnapshotAssocs(entsByTK)
+	ents := entsByTK.Ents()
+	SortEntities(ents) // for deterministic return
+
+	return NetworkSnapshot{Revision: &rev, Network: network, Entities: ents}, nil
+}
@@ -207,12 +207,23 @@ func (store *sqlConfiguratorStorage) loadEntToUpdate(networkID string, update En
 
 // entOut is an output parameter
 func (store *sqlConfiguratorStorage) processEntityFieldsUpdate(pk string, update EntityUpdateCriteria, entOut *NetworkEntity) error {
-	_, err := store.getEntityUpdateQueryBuilder(pk, update).
+	res, err := store.getEntityUpdateQueryBuilder(pk, update).
 		RunWith(store.tx).
 		Exec()
 	if err != nil {
 		return errors.Wrap(err, "failed to update entity fields")
 	}
+	if update.ExpectedVersion != nil {
+		// The version was checked on load, but a concurrent writer may have
+		// updated the entity since
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return errors.Wrap(err, "failed to get rows affected by entity update")
+		}
+		if rowsAffected == 0 {
+			return store.getEntityVersionConflict(entOut.NetworkID, update)
+		}
+	}
 
 	if update.NewName != nil {
 		entOut.Name = (*update.NewName).Value
@@ -308,10 +319,38 @@ func (store *sqlConfiguratorStorage) getEntityUpdateQueryBuilder(pk string, upda
 	if update.NewConfig != nil {
 		updateBuilder = updateBuilder.Set(entConfCol, update.NewConfig.Value)
 	}
+	if update.ExpectedVersion != nil {
+		updateBuilder = updateBuilder.Where(sq.Eq{entVerCol: update.ExpectedVersion.Value})
+	}
 	updateBuilder = updateBuilder.Set(entVerCol, sq.Expr(fmt.Sprintf("%s+1", entVerCol)))
 	return updateBuilder
 }
 
+// getEntityVersionConflict reloads the entity being updated and returns the
+// version conflict between it and the update.
+func (store *sqlConfiguratorStorage) getEntityVersionConflict(networkID string, update EntityUpdateCriteria) error {
+	loaded, err := store.loadEntities(networkID, EntityLoadFilter{IDs: []*EntityID{update.GetID()}}, EntityLoadCriteria{})
+	if err != nil {
+		return errors.Wrap(err, "failed to load entity after conflicting update")
+	}
+	storedVersion := storage.VersionAbsent
+	for _, ent := range loaded {
+		storedVersion = ent.Version
+	}
+	if conflictErr := checkEntityVersion(update, storedVersion); conflictErr != nil {
+		return conflictErr
+	}
+	return errors.Errorf("entity (%s, %s) was not updated", update.Type, update.Key)
+}
+
+// checkEntityVersion returns a *storage.VersionConflictError if the update's
+// expected version doesn't match the stored version.
+func checkEntityVersion(update EntityUpdateCriteria, storedVersion uint64) error {
+	expected := map[storage.TypeAndKey]uint64{update.GetTypeAndKey(): update.ExpectedVersion.Value}
+	stored := map[storage.TypeAndKey]uint64{update.GetTypeAndKey(): storedVersion}
+	return storage.CheckVersions(expected, stored)
+}
+
 // entToUpdateOut is an output parameter
 func (store *sqlConfiguratorStorage) deleteEdges(networkID string, edgesToDelete []*EntityID, entToUpdateOut *NetworkEntity) error {
 	if funk.IsEmpty(edgesToDelete) {
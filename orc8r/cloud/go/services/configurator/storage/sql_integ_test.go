@@ -17,12 +17,16 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"magma/orc8r/cloud/go/clock"
 	"magma/orc8r/cloud/go/services/configurator/storage"
 	"magma/orc8r/cloud/go/sqorc"
 	orc8r_storage "magma/orc8r/cloud/go/storage"
+	merrors "magma/orc8r/lib/go/errors"
 
 	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -919,3 +923,240 @@ func TestSqlConfiguratorStorage_Integration(t *testing.T) {
 	assert.Error(t, err)
 	assert.NoError(t, store.Commit())
 }
+
+func TestSqlConfiguratorStorage_RevisionIntegration(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:?_foreign_keys=1")
+	if err != nil {
+		t.Fatalf("Could not initialize sqlite DB: %s", err)
+	}
+	factory := storage.NewSQLConfiguratorStorageFactory(db, &mockIDGenerator{}, sqorc.GetSqlBuilder(), integTestMaxLoadSize)
+	err = factory.InitializeServiceStorage()
+	assert.NoError(t, err)
+	defer clock.UnfreezeClock(t)
+
+	// Revision 1: create network and an entity
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	store, err := factory.StartTransaction(context.Background(), nil)
+	assert.NoError(t, err)
+	_, err = store.CreateNetwork(storage.Network{ID: "n1", Type: "type1", Name: "Network 1", Configs: map[string][]byte{"hello": []byte("world")}})
+	assert.NoError(t, err)
+	_, err = store.CreateEntity("n1", storage.NetworkEntity{Type: "foo", Key: "bar", Name: "foobar", Config: []byte("foobar")})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	// Revision 2: add a child entity and update the network's config
+	clock.SetAndFreezeClock(t, time.Unix(2000, 0))
+	store, err = factory.StartTransaction(context.Background(), nil)
+	assert.NoError(t, err)
+	_, err = store.CreateEntity("n1", storage.NetworkEntity{
+		Type:         "baz",
+		Key:          "quz",
+		Associations: []*storage.EntityID{{Type: "foo", Key: "bar"}},
+	})
+	assert.NoError(t, err)
+	err = store.UpdateNetworks([]storage.NetworkUpdateCriteria{{ID: "n1", ConfigsToAddOrUpdate: map[string][]byte{"hello": []byte("there")}}})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	// Revision 3: update the parent entity and delete the child
+	clock.SetAndFreezeClock(t, time.Unix(3000, 0))
+	store, err = factory.StartTransaction(context.Background(), nil)
+	assert.NoError(t, err)
+	_, err = store.UpdateEntity("n1", storage.EntityUpdateCriteria{Type: "foo", Key: "bar", NewName: &wrappers.StringValue{Value: "barfoo"}})
+	assert.NoError(t, err)
+	_, err = store.UpdateEntity("n1", storage.EntityUpdateCriteria{Type: "baz", Key: "quz", DeleteEntity: true})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	// Read-only transactions don't record revisions
+	store, err = factory.StartTransaction(context.Background(), &orc8r_storage.TxOptions{ReadOnly: true})
+	assert.NoError(t, err)
+	revisions, err := store.ListRevisions("n1")
+	assert.NoError(t, err)
+	expectedRevisions := []*storage.NetworkRevision{
+		{NetworkID: "n1", Revision: 1, CreatedAt: 1000},
+		{NetworkID: "n1", Revision: 2, CreatedAt: 2000},
+		{NetworkID: "n1", Revision: 3, CreatedAt: 3000},
+	}
+	assert.Equal(t, expectedRevisions, revisions)
+
+	rev, err := store.GetRevisionAt("n1", 2500)
+	assert.NoError(t, err)
+	assert.Equal(t, *expectedRevisions[1], rev)
+	_, err = store.GetRevisionAt("n1", 500)
+	assert.Equal(t, merrors.ErrNotFound, errors.Cause(err))
+
+	// Load the graph as of each revision
+	snapshot1, err := store.LoadSnapshot("n1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"hello": []byte("world")}, snapshot1.Network.Configs)
+	assert.Len(t, snapshot1.Entities, 1)
+	assert.Equal(t, "foobar", snapshot1.Entities[0].Name)
+
+	snapshot2, err := store.LoadSnapshot("n1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"hello": []byte("there")}, snapshot2.Network.Configs)
+	assert.Len(t, snapshot2.Entities, 2)
+	assert.Equal(t, "baz", snapshot2.Entities[0].Type)
+	assert.Equal(t, []*storage.EntityID{{Type: "foo", Key: "bar"}}, snapshot2.Entities[0].Associations)
+	assert.Equal(t, "foo", snapshot2.Entities[1].Type)
+	assert.Equal(t, []*storage.EntityID{{Type: "baz", Key: "quz"}}, snapshot2.Entities[1].ParentAssociations)
+
+	latest, err := store.LoadSnapshot("n1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, *expectedRevisions[2], *latest.Revision)
+	assert.Len(t, latest.Entities, 1)
+	assert.Equal(t, "barfoo", latest.Entities[0].Name)
+	assert.Empty(t, latest.Entities[0].ParentAssociations)
+
+	_, err = store.LoadSnapshot("n1", 4)
+	assert.Equal(t, merrors.ErrNotFound, errors.Cause(err))
+	_, err = store.LoadSnapshot("n2", 0)
+	assert.Equal(t, merrors.ErrNotFound, errors.Cause(err))
+
+	// Diff two revisions
+	diff := storage.DiffSnapshots(snapshot2, latest)
+	assert.Equal(t, *expectedRevisions[1], *diff.From)
+	assert.Equal(t, *expectedRevisions[2], *diff.To)
+	assert.Nil(t, diff.NetworkBefore)
+	assert.Nil(t, diff.NetworkAfter)
+	assert.Len(t, diff.Entities, 2)
+	assert.Equal(t, &storage.EntityID{Type: "baz", Key: "quz"}, diff.Entities[0].ID)
+	assert.NotNil(t, diff.Entities[0].Before)
+	assert.Nil(t, diff.Entities[0].After)
+	assert.Equal(t, &storage.EntityID{Type: "foo", Key: "bar"}, diff.Entities[1].ID)
+	assert.Equal(t, "foobar", diff.Entities[1].Before.Name)
+	assert.Equal(t, "barfoo", diff.Entities[1].After.Name)
+	assert.NoError(t, store.Commit())
+
+	// Roll back to revision 2, which is recorded as revision 4
+	clock.SetAndFreezeClock(t, time.Unix(4000, 0))
+	store, err = factory.StartTransaction(context.Background(), nil)
+	assert.NoError(t, err)
+	rollbackDiff, err := storage.RollbackNetwork(store, "n1", 2)
+	assert.NoError(t, err)
+	assert.Nil(t, rollbackDiff.NetworkAfter)
+	assert.Len(t, rollbackDiff.Entities, 2)
+	assert.Nil(t, rollbackDiff.Entities[0].Before)
+	assert.Equal(t, "quz", rollbackDiff.Entities[0].After.Key)
+	assert.Equal(t, "foobar", rollbackDiff.Entities[1].After.Name)
+	assert.NoError(t, store.Commit())
+
+	store, err = factory.StartTransaction(context.Background(), &orc8r_storage.TxOptions{ReadOnly: true})
+	assert.NoError(t, err)
+	latest, err = store.LoadSnapshot("n1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.NetworkRevision{NetworkID: "n1", Revision: 4, CreatedAt: 4000}, *latest.Revision)
+	diff = storage.DiffSnapshots(snapshot2, latest)
+	assert.Nil(t, diff.NetworkAfter)
+	assert.Empty(t, diff.Entities)
+
+	loaded, err := store.LoadEntities("n1", storage.EntityLoadFilter{}, storage.FullEntityLoadCriteria)
+	assert.NoError(t, err)
+	assert.Len(t, loaded.Entities, 2)
+	assert.NoError(t, store.Commit())
+
+	// Roll the network's config back to revision 1
+	store, err = factory.StartTransaction(context.Background(), nil)
+	assert.NoError(t, err)
+	rollbackDiff, err = storage.RollbackNetwork(store, "n1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("there"), rollbackDiff.NetworkBefore.Configs["hello"])
+	assert.Equal(t, []byte("world"), rollbackDiff.NetworkAfter.Configs["hello"])
+	assert.Len(t, rollbackDiff.Entities, 1)
+	assert.NoError(t, store.Commit())
+
+	// History survives deletion of the network
+	store, err = factory.StartTransaction(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.NoError(t, store.UpdateNetworks([]storage.NetworkUpdateCriteria{{ID: "n1", DeleteNetwork: true}}))
+	assert.NoError(t, store.Commit())
+
+	store, err = factory.StartTransaction(context.Background(), &orc8r_storage.TxOptions{ReadOnly: true})
+	assert.NoError(t, err)
+	_, err = store.LoadSnapshot("n1", 0)
+	assert.Equal(t, merrors.ErrNotFound, errors.Cause(err))
+	snapshot, err := store.LoadSnapshot("n1", 2)
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Entities, 2)
+	assert.NoError(t, store.Commit())
+}
+
+func TestSqlConfiguratorStorage_ExpectedVersionIntegration(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:?_foreign_keys=1")
+	if err != nil {
+		t.Fatalf("Could not initialize sqlite DB: %s", err)
+	}
+	factory := storage.NewSQLConfiguratorStorageFactory(db, &mockIDGenerator{}, sqorc.GetSqlBuilder(), integTestMaxLoadSize)
+	err = factory.InitializeServiceStorage()
+	assert.NoError(t, err)
+
+	store, err := factory.StartTransaction(context.Background(), nil)
+	assert.NoError(t, err)
+	_, err = store.CreateNetwork(storage.Network{ID: "n1"})
+	assert.NoError(t, err)
+	_, err = store.CreateEntity("n1", storage.NetworkEntity{Type: "foo", Key: "bar", Name: "foobar"})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	// Matching version: update applied
+	store, err = factory.StartTransaction(context.Background(), nil)
+	assert.NoError(t, err)
+	updated, err := store.UpdateEntity("n1", storage.EntityUpdateCriteria{
+		Type:            "foo",
+		Key:             "bar",
+		NewName:         &wrappers.StringValue{Value: "barfoo"},
+		ExpectedVersion: &wrappers.UInt64Value{Value: 0},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), updated.Version)
+	assert.NoError(t, store.Commit())
+
+	// Stale version: conflict with the stored version
+	store, err = factory.StartTransaction(context.Background(), nil)
+	assert.NoError(t, err)
+	_, err = store.UpdateEntity("n1", storage.EntityUpdateCriteria{
+		Type:            "foo",
+		Key:             "bar",
+		NewName:         &wrappers.StringValue{Value: "stale"},
+		ExpectedVersion: &wrappers.UInt64Value{Value: 0},
+	})
+	expectedErr := &orc8r_storage.VersionConflictError{Versions: map[orc8r_storage.TypeAndKey]uint64{{Type: "foo", Key: "bar"}: 1}}
+	assert.Equal(t, expectedErr, err)
+	assert.NoError(t, store.Rollback())
+
+	// Nonexistent entity: conflict unless absence is expected
+	store, err = factory.StartTransaction(context.Background(), nil)
+	assert.NoError(t, err)
+	_, err = store.UpdateEntity("n1", storage.EntityUpdateCriteria{
+		Type:            "foo",
+		Key:             "nope",
+		DeleteEntity:    true,
+		ExpectedVersion: &wrappers.UInt64Value{Value: 2},
+	})
+	expectedErr = &orc8r_storage.VersionConflictError{Versions: map[orc8r_storage.TypeAndKey]uint64{{Type: "foo", Key: "nope"}: orc8r_storage.VersionAbsent}}
+	assert.Equal(t, expectedErr, err)
+	_, err = store.UpdateEntity("n1", storage.EntityUpdateCriteria{
+		Type:            "foo",
+		Key:             "nope",
+		DeleteEntity:    true,
+		ExpectedVersion: &wrappers.UInt64Value{Value: 0},
+	})
+	assert.Equal(t, expectedErr, err)
+	_, err = store.UpdateEntity("n1", storage.EntityUpdateCriteria{
+		Type:            "foo",
+		Key:             "nope",
+		DeleteEntity:    true,
+		ExpectedVersion: &wrappers.UInt64Value{Value: orc8r_storage.VersionAbsent},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Commit())
+
+	store, err = factory.StartTransaction(context.Background(), &orc8r_storage.TxOptions{ReadOnly: true})
+	assert.NoError(t, err)
+	loaded, err := store.LoadEntities("n1", storage.EntityLoadFilter{}, storage.EntityLoadCriteria{LoadMetadata: true})
+	assert.NoError(t, err)
+	assert.Len(t, loaded.Entities, 1)
+	assert.Equal(t, "barfoo", loaded.Entities[0].Name)
+	assert.NoError(t, store.Commit())
+}
@@ -0,0 +1,564 @@
+/*
+ * Copyright 2020 The Magma Authors.
+ *
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/sqorc"
+	"magma/orc8r/cloud/go/storage"
+	merrors "magma/orc8r/lib/go/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// Revision history is append-only. Each revision of a network records a
+// snapshot of the network (if it changed) and of each entity which changed,
+// including entities whose outgoing edges changed. A deleted network or
+// entity is recorded as a tombstone row.
+//
+// The state of a network as of revision R is the latest snapshot at or
+// before R of the network and of each of its entities.
+//
+// The revision history tables intentionally have no foreign keys to the
+// network and entity tables, so history outlives deletion.
+
+// revisionChanges tracks the networks and entities written to within a
+// transaction, keyed by network ID.
+type revisionChanges map[string]*networkChanges
+
+type networkChanges struct {
+	network  bool
+	entities map[storage.TypeAndKey]struct{}
+}
+
+func (c revisionChanges) get(networkID string) *networkChanges {
+	changes, ok := c[networkID]
+	if !ok {
+		changes = &networkChanges{entities: map[storage.TypeAndKey]struct{}{}}
+		c[networkID] = changes
+	}
+	return changes
+}
+
+func (c revisionChanges) touchNetwork(networkID string) {
+	c.get(networkID).network = true
+}
+
+func (c revisionChanges) touchEntities(networkID string, tks ...storage.TypeAndKey) {
+	changes := c.get(networkID)
+	for _, tk := range tks {
+		changes.entities[tk] = struct{}{}
+	}
+}
+
+func (n *networkChanges) entityIDs() []*EntityID {
+	ids := make([]*EntityID, 0, len(n.entities))
+	for tk := range n.entities {
+		ids = append(ids, (&EntityID{}).FromTypeAndKey(tk))
+	}
+	SortIDs(ids)
+	return ids
+}
+
+func initRevisionTables(tx *sql.Tx, builder sqorc.StatementBuilder) error {
+	_, err := builder.CreateTable(revisionTable).
+		IfNotExists().
+		Column(revNidCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(revRevCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+		Column(revCreatedAtCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+		PrimaryKey(revNidCol, revRevCol).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "failed to create revisions table")
+	}
+
+	_, err = builder.CreateTable(revisionHeadTable).
+		IfNotExists().
+		Column(revNidCol).Type(sqorc.ColumnTypeText).PrimaryKey().EndColumn().
+		Column(revRevCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "failed to create revision heads table")
+	}
+
+	_, err = builder.CreateTable(networkRevisionTable).
+		IfNotExists().
+		Column(revNidCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(revRevCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+		Column(revDeletedCol).Type(sqorc.ColumnTypeBool).NotNull().EndColumn().
+		Column(revValCol).Type(sqorc.ColumnTypeBytes).EndColumn().
+		PrimaryKey(revNidCol, revRevCol).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "failed to create network revisions table")
+	}
+
+	_, err = builder.CreateTable(entityRevisionTable).
+		IfNotExists().
+		Column(revNidCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(revRevCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+		Column(revTypeCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(revKeyCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(revDeletedCol).Type(sqorc.ColumnTypeBool).NotNull().EndColumn().
+		Column(revValCol).Type(sqorc.ColumnTypeBytes).EndColumn().
+		PrimaryKey(revNidCol, revTypeCol, revKeyCol, revRevCol).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "failed to create entity revisions table")
+	}
+
+	_, err = builder.CreateIndex("entity_revision_idx").
+		IfNotExists().
+		On(entityRevisionTable).
+		Columns(revNidCol, revRevCol).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "failed to create entity revision index")
+	}
+
+	return nil
+}
+
+// touchParents marks the parents of an entity as changed.
+func (store *sqlConfiguratorStorage) touchParents(networkID string, id *EntityID) error {
+	parentAssocs, err := store.loadAssocs(networkID, EntityLoadFilter{IDs: []*EntityID{id}}, EntityLoadCriteria{}, loadParents)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load parents of entity %s", id.ToTypeAndKey())
+	}
+	for _, assoc := range parentAssocs {
+		store.changes.touchEntities(networkID, assoc.fromTK)
+	}
+	return nil
+}
+
+// recordBaselineRevisions records a full snapshot of every network which
+// doesn't have a revision history yet.
+func (store *sqlConfiguratorStorage) recordBaselineRevisions() error {
+	rows, err := store.builder.Select(nwIDCol).
+		From(networksTable).
+		LeftJoin(fmt.Sprintf("%s ON %s.%s = %s.%s", revisionHeadTable, revisionHeadTable, revNidCol, networksTable, nwIDCol)).
+		Where(sq.Eq{fmt.Sprintf("%s.%s", revisionHeadTable, revNidCol): nil}).
+		RunWith(store.tx).
+		Query()
+	if err != nil {
+		return errors.Wrap(err, "failed to query networks without revision history")
+	}
+	var networkIDs []string
+	for rows.Next() {
+		var networkID string
+		err = rows.Scan(&networkID)
+		if err != nil {
+			sqorc.CloseRowsLogOnError(rows, "recordBaselineRevisions")
+			return errors.Wrap(err, "failed to scan network ID")
+		}
+		networkIDs = append(networkIDs, networkID)
+	}
+	err = rows.Err()
+	sqorc.CloseRowsLogOnError(rows, "recordBaselineRevisions")
+	if err != nil {
+		return errors.Wrap(err, "sql rows err")
+	}
+
+	for _, networkID := range networkIDs {
+		tks, err := store.loadAllEntityTKs(networkID)
+		if err != nil {
+			return err
+		}
+		store.changes.touchNetwork(networkID)
+		store.changes.touchEntities(networkID, tks...)
+	}
+	return store.recordRevisions()
+}
+
+func (store *sqlConfiguratorStorage) loadAllEntityTKs(networkID string) ([]storage.TypeAndKey, error) {
+	rows, err := store.builder.Select(entTypeCol, entKeyCol).
+		From(entityTable).
+		Where(sq.Eq{entNidCol: networkID}).
+		RunWith(store.tx).
+		Query()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query entities of network %s", networkID)
+	}
+	defer sqorc.CloseRowsLogOnError(rows, "loadAllEntityTKs")
+
+	var tks []storage.TypeAndKey
+	for rows.Next() {
+		tk := storage.TypeAndKey{}
+		err = rows.Scan(&tk.Type, &tk.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan entity ID")
+		}
+		tks = append(tks, tk)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, errors.Wrap(err, "sql rows err")
+	}
+	return tks, nil
+}
+
+// recordRevisions records a new revision for each network written to within
+// the transaction.
+func (store *sqlConfiguratorStorage) recordRevisions() error {
+	if len(store.changes) == 0 {
+		return nil
+	}
+
+	// Sort network IDs so concurrent transactions lock revision heads in a
+	// consistent order
+	networkIDs := make([]string, 0, len(store.changes))
+	for networkID := range store.changes {
+		networkIDs = append(networkIDs, networkID)
+	}
+	sort.Strings(networkIDs)
+
+	now := clock.Now().Unix()
+	for _, networkID := range networkIDs {
+		err := store.recordRevision(networkID, store.changes[networkID], now)
+		if err != nil {
+			return errors.Wrapf(err, "failed to record revision of network %s", networkID)
+		}
+	}
+	store.changes = revisionChanges{}
+	return nil
+}
+
+func (store *sqlConfiguratorStorage) recordRevision(networkID string, changes *networkChanges, createdAt int64) error {
+	revision, err := store.incrementRevisionHead(networkID)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.builder.Insert(revisionTable).
+		Columns(revNidCol, revRevCol, revCreatedAtCol).
+		Values(networkID, revision, createdAt).
+		RunWith(store.tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "failed to insert revision")
+	}
+
+	if changes.network {
+		networkDeleted, err := store.recordNetworkRevision(networkID, revision)
+		if err != nil {
+			return err
+		}
+		// Entities of a deleted network are deleted along with it
+		if networkDeleted && revision > 1 {
+			prevEnts, err := store.loadEntityRevisions(networkID, revision-1)
+			if err != nil {
+				return err
+			}
+			for tk := range prevEnts {
+				changes.entities[tk] = struct{}{}
+			}
+		}
+	}
+
+	return store.recordEntityRevisions(networkID, revision, changes.entityIDs())
+}
+
+// incrementRevisionHead increments and returns the latest revision of a
+// network. The head row stays locked until the end of the transaction, which
+// serializes the recording of revisions of a network.
+func (store *sqlConfiguratorStorage) incrementRevisionHead(networkID string) (uint64, error) {
+	res, err := store.builder.Update(revisionHeadTable).
+		Set(revRevCol, sq.Expr(fmt.Sprintf("%s+1", revRevCol))).
+		Where(sq.Eq{revNidCol: networkID}).
+		RunWith(store.tx).
+		Exec()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to increment revision head")
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		_, err = store.builder.Insert(revisionHeadTable).
+			Columns(revNidCol, revRevCol).
+			Values(networkID, 1).
+			RunWith(store.tx).
+			Exec()
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to insert revision head")
+		}
+		return 1, nil
+	}
+
+	var revision uint64
+	err = store.builder.Select(revRevCol).
+		From(revisionHeadTable).
+		Where(sq.Eq{revNidCol: networkID}).
+		RunWith(store.tx).
+		QueryRow().
+		Scan(&revision)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read revision head")
+	}
+	return revision, nil
+}
+
+// recordNetworkRevision records the current state of a network. Returns true
+// if the network was deleted.
+func (store *sqlConfiguratorStorage) recordNetworkRevision(networkID string, revision uint64) (bool, error) {
+	loaded, err := store.LoadNetworks(NetworkLoadFilter{Ids: []string{networkID}}, FullNetworkLoadCriteria)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to load network")
+	}
+
+	deleted := len(loaded.Networks) == 0
+	var value []byte
+	if !deleted {
+		value, err = proto.Marshal(loaded.Networks[0])
+		if err != nil {
+			return false, errors.Wrap(err, "failed to marshal network")
+		}
+	}
+
+	_, err = store.builder.Insert(networkRevisionTable).
+		Columns(revNidCol, revRevCol, revDeletedCol, revValCol).
+		Values(networkID, revision, deleted, value).
+		RunWith(store.tx).
+		Exec()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to insert network revision")
+	}
+	return deleted, nil
+}
+
+// recordEntityRevisions records the current state of the passed entities.
+func (store *sqlConfiguratorStorage) recordEntityRevisions(networkID string, revision uint64, ids []*EntityID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	sc := sq.NewStmtCache(store.tx)
+	defer sqorc.ClearStatementCacheLogOnError(sc, "recordEntityRevisions")
+
+	// Entity loads are bounded by the max load size, so load in chunks
+	chunkSize := int(store.maxEntityLoadSize)
+	for start := 0; start < len(ids); start += chunkSize {
+		end := start + chunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk := ids[start:end]
+
+		criteria := EntityLoadCriteria{LoadMetadata: true, LoadConfig: true, LoadAssocsFromThis: true}
+		loaded, err := store.LoadEntities(networkID, EntityLoadFilter{IDs: chunk}, criteria)
+		if err != nil {
+			return errors.Wrap(err, "failed to load changed entities")
+		}
+		loadedByTK := map[storage.TypeAndKey]*NetworkEntity{}
+		for _, ent := range loaded.Entities {
+			loadedByTK[ent.GetTypeAndKey()] = ent
+		}
+
+		for _, id := range chunk {
+			ent, found := loadedByTK[id.ToTypeAndKey()]
+			var value []byte
+			if found {
+				value, err = proto.Marshal(toEntityRevision(ent))
+				if err != nil {
+					return errors.Wrapf(err, "failed to marshal entity %s", id.ToTypeAndKey())
+				}
+			}
+			_, err = store.builder.Insert(entityRevisionTable).
+				Columns(revNidCol, revRevCol, revTypeCol, revKeyCol, revDeletedCol, revValCol).
+				Values(networkID, revision, id.Type, id.Key, !found, value).
+				RunWith(sc).
+				Exec()
+			if err != nil {
+				return errors.Wrapf(err, "failed to insert revision of entity %s", id.ToTypeAndKey())
+			}
+		}
+	}
+	return nil
+}
+
+// toEntityRevision strips the fields of an entity which aren't recorded in
+// its revision history.
+func toEntityRevision(ent *NetworkEntity) *NetworkEntity {
+	return &NetworkEntity{
+		Type:         ent.Type,
+		Key:          ent.Key,
+		Name:         ent.Name,
+		Description:  ent.Description,
+		PhysicalID:   ent.PhysicalID,
+		Config:       ent.Config,
+		Associations: ent.Associations,
+		Version:      ent.Version,
+	}
+}
+
+// getRevision returns the requested revision of a network, or the latest
+// revision if the requested revision is 0.
+func (store *sqlConfiguratorStorage) getRevision(networkID string, revision uint64) (NetworkRevision, error) {
+	where := sq.And{sq.Eq{revNidCol: networkID}}
+	if revision != 0 {
+		where = append(where, sq.Eq{revRevCol: revision})
+	}
+	return store.scanRevision(
+		store.builder.Select(revNidCol, revRevCol, revCreatedAtCol).
+			From(revisionTable).
+			Where(where).
+			OrderBy(fmt.Sprintf("%s DESC", revRevCol)).
+			Limit(1),
+	)
+}
+
+func (store *sqlConfiguratorStorage) scanRevision(builder sq.SelectBuilder) (NetworkRevision, error) {
+	ret := NetworkRevision{}
+	err := builder.RunWith(store.tx).QueryRow().Scan(&ret.NetworkID, &ret.Revision, &ret.CreatedAt)
+	if err == sql.ErrNoRows {
+		return NetworkRevision{}, merrors.ErrNotFound
+	}
+	if err != nil {
+		return NetworkRevision{}, errors.Wrap(err, "failed to query revision")
+	}
+	return ret, nil
+}
+
+// loadNetworkRevision returns the state of a network as of a revision, or nil
+// if the network didn't exist as of the revision.
+func (store *sqlConfiguratorStorage) loadNetworkRevision(networkID string, revision uint64) (*Network, error) {
+	var deleted bool
+	var value []byte
+	err := store.builder.Select(revDeletedCol, revValCol).
+		From(networkRevisionTable).
+		Where(sq.And{
+			sq.Eq{revNidCol: networkID},
+			sq.LtOrEq{revRevCol: revision},
+		}).
+		OrderBy(fmt.Sprintf("%s DESC", revRevCol)).
+		Limit(1).
+		RunWith(store.tx).
+		QueryRow().
+		Scan(&deleted, &value)
+	if err == sql.ErrNoRows || deleted {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query network revision")
+	}
+
+	network := &Network{}
+	err = proto.Unmarshal(value, network)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal network revision")
+	}
+	return network, nil
+}
+
+// loadEntityRevisions returns the entities of a network as of a revision.
+// Associations are as recorded, and may include edges to entities which were
+// deleted later.
+func (store *sqlConfiguratorStorage) loadEntityRevisions(networkID string, revision uint64) (EntitiesByTK, error) {
+	// SELECT r.type, r."key", r.value FROM cfg_entity_revisions AS r
+	// JOIN (
+	//   SELECT type, "key", MAX(revision) AS latest FROM cfg_entity_revisions
+	//   WHERE network_id = $1 AND revision <= $2 GROUP BY type, "key"
+	// ) AS l ON r.type = l.type AND r."key" = l."key" AND r.revision = l.latest
+	// WHERE r.network_id = $3 AND r.deleted = $4
+	latestSql, latestArgs, err := sq.Select(revTypeCol, revKeyCol, fmt.Sprintf("MAX(%s) AS latest", revRevCol)).
+		From(entityRevisionTable).
+		Where(sq.And{
+			sq.Eq{revNidCol: networkID},
+			sq.LtOrEq{revRevCol: revision},
+		}).
+		GroupBy(revTypeCol, revKeyCol).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build latest entity revisions query")
+	}
+
+	revCol := func(c string) string {
+		return fmt.Sprintf("r.%s", c)
+	}
+	rows, err := store.builder.Select(revCol(revTypeCol), revCol(revKeyCol), revCol(revValCol)).
+		From(fmt.Sprintf("%s AS r", entityRevisionTable)).
+		Join(
+			fmt.Sprintf(
+				"(%s) AS l ON r.%s = l.%s AND r.%s = l.%s AND r.%s = l.latest",
+				latestSql, revTypeCol, revTypeCol, revKeyCol, revKeyCol, revRevCol,
+			),
+			latestArgs...,
+		).
+		Where(sq.And{
+			sq.Eq{revCol(revNidCol): networkID},
+			sq.Eq{revCol(revDeletedCol): false},
+		}).
+		RunWith(store.tx).
+		Query()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query entity revisions")
+	}
+	defer sqorc.CloseRowsLogOnError(rows, "loadEntityRevisions")
+
+	entsByTK := EntitiesByTK{}
+	for rows.Next() {
+		var entType, key string
+		var value []byte
+		err = rows.Scan(&entType, &key, &value)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan entity revision")
+		}
+		ent := &NetworkEntity{}
+		err = proto.Unmarshal(value, ent)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal revision of entity (%s, %s)", entType, key)
+		}
+		ent.NetworkID = networkID
+		entsByTK[ent.GetTypeAndKey()] = ent
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, errors.Wrap(err, "sql rows err")
+	}
+	return entsByTK, nil
+}
+
+// fillSnapshotAssocs drops recorded edges to entities which don't exist in
+// the snapshot, then fills out parent associations.
+func fillSnapshotAssocs(entsByTK EntitiesByTK) {
+	for _, ent := range entsByTK {
+		var assocs []*EntityID
+		for _, assoc := range ent.Associations {
+			child, ok := entsByTK[assoc.ToTypeAndKey()]
+			if !ok {
+				continue
+			}
+			assocs = append(assocs, assoc)
+			child.ParentAssociations = append(child.ParentAssociations, ent.GetID())
+		}
+		ent.Associations = assocs
+	}
+	for _, ent := range entsByTK {
+		SortIDs(ent.Associations)
+		SortIDs(ent.ParentAssociations)
+	}
+}
@@ -1067,6 +1067,7 @@ func TestSqlConfiguratorStorage_UpdateEntity(t *testing.T) {
 	deleteCase := &testCase{
 		setup: func(m sqlmock.Sqlmock) {
 			expectBasicEntityQueries(m, expectedFooBarQuery)
+			expectAssocQuery(m, "network", [][]driver.Value{{"bar", "foo"}}, nil)
 			m.ExpectExec("DELETE FROM cfg_entities").WithArgs("network", "foo", "bar").WillReturnResult(mockResult)
 			expectBulkEntityQuery(m, []driver.Value{"g1"})
 		},
@@ -1080,6 +1081,7 @@ func TestSqlConfiguratorStorage_UpdateEntity(t *testing.T) {
 	deleteWithPartition := &testCase{
 		setup: func(m sqlmock.Sqlmock) {
 			expectBasicEntityQueries(m, expectedFooBarQuery)
+			expectAssocQuery(m, "network", [][]driver.Value{{"bar", "foo"}}, nil)
 			m.ExpectExec("DELETE FROM cfg_entities").WithArgs("network", "foo", "bar").WillReturnResult(mockResult)
 			// make foobar the root of a tree so we partition the graph into
 			// 3 components:
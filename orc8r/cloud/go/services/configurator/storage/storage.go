@@ -66,6 +66,12 @@ type ConfiguratorStorage interface {
 	// UpdateNetworks updates a set of networks.
 	UpdateNetworks(updates []NetworkUpdateCriteria) error
 
+	// LockNetworks locks the networks until the transaction completes,
+	// blocking other transactions locking any of them. This serializes
+	// writes which check invariants spanning the networks, such as tenant
+	// quotas. Networks which don't exist are ignored.
+	LockNetworks(networkIDs []string) error
+
 	// =======================================================================
 	// Entity Operations
 	// =======================================================================
@@ -101,6 +107,26 @@ type ConfiguratorStorage interface {
 	// entity. The load criteria fields on associations are ignored, and the
 	// returned entities will always have both association fields filled out.
 	LoadGraphForEntity(networkID string, entityID EntityID, loadCriteria EntityLoadCriteria) (EntityGraph, error)
+
+	// =======================================================================
+	// Revision Operations
+	// =======================================================================
+
+	// ListRevisions returns the revision history of a network, ordered by
+	// revision.
+	ListRevisions(networkID string) ([]*NetworkRevision, error)
+
+	// GetRevisionAt returns the latest revision of a network recorded at or
+	// before the given unix timestamp (seconds).
+	// If no such revision exists, returns ErrNotFound from
+	// magma/orc8r/lib/go/errors.
+	GetRevisionAt(networkID string, timestamp int64) (NetworkRevision, error)
+
+	// LoadSnapshot returns the state of a network and its full entity graph
+	// as of the given revision. A revision of 0 loads the latest revision.
+	// If the revision doesn't exist, or the network didn't exist as of the
+	// revision, returns ErrNotFound from magma/orc8r/lib/go/errors.
+	LoadSnapshot(networkID string, revision uint64) (NetworkSnapshot, error)
 }
 
 // RollbackLogOnError calls Rollback on the provided ConfiguratorStorage and
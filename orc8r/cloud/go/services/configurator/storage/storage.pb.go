@@ -861,9 +861,12 @@ type EntityUpdateCriteria struct {
 	AssociationsToSet    *EntityAssociationsToSet `protobuf:"bytes,30,opt,name=associations_to_set,json=associationsToSet,proto3" json:"associations_to_set,omitempty"`
 	AssociationsToAdd    []*EntityID              `protobuf:"bytes,31,rep,name=associations_to_add,json=associationsToAdd,proto3" json:"associations_to_add,omitempty"`
 	AssociationsToDelete []*EntityID              `protobuf:"bytes,32,rep,name=associations_to_delete,json=associationsToDelete,proto3" json:"associations_to_delete,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
-	XXX_unrecognized     []byte                   `json:"-"`
-	XXX_sizecache        int32                    `json:"-"`
+	// If set, the update is only applied if the entity's stored version
+	// matches. A value of 0 requires the entity to not exist.
+	ExpectedVersion      *wrappers.UInt64Value `protobuf:"bytes,40,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *EntityUpdateCriteria) Reset()         { *m = EntityUpdateCriteria{} }
@@ -961,6 +964,13 @@ func (m *EntityUpdateCriteria) GetAssociationsToDelete() []*EntityID {
 	return nil
 }
 
+func (m *EntityUpdateCriteria) GetExpectedVersion() *wrappers.UInt64Value {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return nil
+}
+
 type EntityAssociationsToSet struct {
 	AssociationsToSet    []*EntityID `protobuf:"bytes,1,rep,name=associations_to_set,json=associationsToSet,proto3" json:"associations_to_set,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
@@ -1107,6 +1117,260 @@ func (m *GraphEdge) GetFrom() *EntityID {
 	return nil
 }
 
+// NetworkRevision is a point in the revision history of a network. Every
+// committed transaction which writes to a network's configs, entities, or
+// edges creates a new revision of that network.
+type NetworkRevision struct {
+	NetworkID string `protobuf:"bytes,1,opt,name=networkID,proto3" json:"networkID,omitempty"`
+	// Revisions start at 1 and increase by 1 with each write to the network.
+	Revision uint64 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	// Unix timestamp (seconds) at which the revision was recorded
+	CreatedAt            int64    `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NetworkRevision) Reset()         { *m = NetworkRevision{} }
+func (m *NetworkRevision) String() string { return proto.CompactTextString(m) }
+func (*NetworkRevision) ProtoMessage()    {}
+func (*NetworkRevision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1622decbcca5fb09, []int{16}
+}
+
+func (m *NetworkRevision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NetworkRevision.Unmarshal(m, b)
+}
+func (m *NetworkRevision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NetworkRevision.Marshal(b, m, deterministic)
+}
+func (m *NetworkRevision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkRevision.Merge(m, src)
+}
+func (m *NetworkRevision) XXX_Size() int {
+	return xxx_messageInfo_NetworkRevision.Size(m)
+}
+func (m *NetworkRevision) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkRevision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkRevision proto.InternalMessageInfo
+
+func (m *NetworkRevision) GetNetworkID() string {
+	if m != nil {
+		return m.NetworkID
+	}
+	return ""
+}
+
+func (m *NetworkRevision) GetRevision() uint64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *NetworkRevision) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+// NetworkSnapshot is the state of a network and its entity graph as of a
+// revision.
+type NetworkSnapshot struct {
+	Revision *NetworkRevision `protobuf:"bytes,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	Network  *Network         `protobuf:"bytes,2,opt,name=network,proto3" json:"network,omitempty"`
+	// All entities in the network as of the revision, with both association
+	// fields filled out. Internal fields (pk, graphID) are not recorded in
+	// the revision history and will be empty.
+	Entities             []*NetworkEntity `protobuf:"bytes,3,rep,name=entities,proto3" json:"entities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *NetworkSnapshot) Reset()         { *m = NetworkSnapshot{} }
+func (m *NetworkSnapshot) String() string { return proto.CompactTextString(m) }
+func (*NetworkSnapshot) ProtoMessage()    {}
+func (*NetworkSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1622decbcca5fb09, []int{17}
+}
+
+func (m *NetworkSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NetworkSnapshot.Unmarshal(m, b)
+}
+func (m *NetworkSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NetworkSnapshot.Marshal(b, m, deterministic)
+}
+func (m *NetworkSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkSnapshot.Merge(m, src)
+}
+func (m *NetworkSnapshot) XXX_Size() int {
+	return xxx_messageInfo_NetworkSnapshot.Size(m)
+}
+func (m *NetworkSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkSnapshot proto.InternalMessageInfo
+
+func (m *NetworkSnapshot) GetRevision() *NetworkRevision {
+	if m != nil {
+		return m.Revision
+	}
+	return nil
+}
+
+func (m *NetworkSnapshot) GetNetwork() *Network {
+	if m != nil {
+		return m.Network
+	}
+	return nil
+}
+
+func (m *NetworkSnapshot) GetEntities() []*NetworkEntity {
+	if m != nil {
+		return m.Entities
+	}
+	return nil
+}
+
+// EntityDiff is the change to a single entity between two revisions.
+type EntityDiff struct {
+	ID *EntityID `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// Before is nil if the entity was created between the two revisions.
+	Before *NetworkEntity `protobuf:"bytes,2,opt,name=before,proto3" json:"before,omitempty"`
+	// After is nil if the entity was deleted between the two revisions.
+	After                *NetworkEntity `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *EntityDiff) Reset()         { *m = EntityDiff{} }
+func (m *EntityDiff) String() string { return proto.CompactTextString(m) }
+func (*EntityDiff) ProtoMessage()    {}
+func (*EntityDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1622decbcca5fb09, []int{18}
+}
+
+func (m *EntityDiff) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EntityDiff.Unmarshal(m, b)
+}
+func (m *EntityDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EntityDiff.Marshal(b, m, deterministic)
+}
+func (m *EntityDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EntityDiff.Merge(m, src)
+}
+func (m *EntityDiff) XXX_Size() int {
+	return xxx_messageInfo_EntityDiff.Size(m)
+}
+func (m *EntityDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_EntityDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EntityDiff proto.InternalMessageInfo
+
+func (m *EntityDiff) GetID() *EntityID {
+	if m != nil {
+		return m.ID
+	}
+	return nil
+}
+
+func (m *EntityDiff) GetBefore() *NetworkEntity {
+	if m != nil {
+		return m.Before
+	}
+	return nil
+}
+
+func (m *EntityDiff) GetAfter() *NetworkEntity {
+	if m != nil {
+		return m.After
+	}
+	return nil
+}
+
+// NetworkDiff is the set of changes to a network between two revisions.
+type NetworkDiff struct {
+	From *NetworkRevision `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   *NetworkRevision `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// NetworkBefore and NetworkAfter are set only if the network's metadata
+	// or configs changed between the two revisions.
+	NetworkBefore *Network `protobuf:"bytes,3,opt,name=network_before,json=networkBefore,proto3" json:"network_before,omitempty"`
+	NetworkAfter  *Network `protobuf:"bytes,4,opt,name=network_after,json=networkAfter,proto3" json:"network_after,omitempty"`
+	// Entities which were created, updated, or deleted between the two
+	// revisions, ordered by (type, key).
+	Entities             []*EntityDiff `protobuf:"bytes,5,rep,name=entities,proto3" json:"entities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *NetworkDiff) Reset()         { *m = NetworkDiff{} }
+func (m *NetworkDiff) String() string { return proto.CompactTextString(m) }
+func (*NetworkDiff) ProtoMessage()    {}
+func (*NetworkDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1622decbcca5fb09, []int{19}
+}
+
+func (m *NetworkDiff) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NetworkDiff.Unmarshal(m, b)
+}
+func (m *NetworkDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NetworkDiff.Marshal(b, m, deterministic)
+}
+func (m *NetworkDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkDiff.Merge(m, src)
+}
+func (m *NetworkDiff) XXX_Size() int {
+	return xxx_messageInfo_NetworkDiff.Size(m)
+}
+func (m *NetworkDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkDiff proto.InternalMessageInfo
+
+func (m *NetworkDiff) GetFrom() *NetworkRevision {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *NetworkDiff) GetTo() *NetworkRevision {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+func (m *NetworkDiff) GetNetworkBefore() *Network {
+	if m != nil {
+		return m.NetworkBefore
+	}
+	return nil
+}
+
+func (m *NetworkDiff) GetNetworkAfter() *Network {
+	if m != nil {
+		return m.NetworkAfter
+	}
+	return nil
+}
+
+func (m *NetworkDiff) GetEntities() []*EntityDiff {
+	if m != nil {
+		return m.Entities
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Network)(nil), "magma.orc8r.configurator.storage.Network")
 	proto.RegisterMapType((map[string][]byte)(nil), "magma.orc8r.configurator.storage.Network.ConfigsEntry")
@@ -1126,6 +1390,10 @@ func init() {
 	proto.RegisterType((*EntityAssociationsToSet)(nil), "magma.orc8r.configurator.storage.EntityAssociationsToSet")
 	proto.RegisterType((*EntityGraph)(nil), "magma.orc8r.configurator.storage.EntityGraph")
 	proto.RegisterType((*GraphEdge)(nil), "magma.orc8r.configurator.storage.GraphEdge")
+	proto.RegisterType((*NetworkRevision)(nil), "magma.orc8r.configurator.storage.NetworkRevision")
+	proto.RegisterType((*NetworkSnapshot)(nil), "magma.orc8r.configurator.storage.NetworkSnapshot")
+	proto.RegisterType((*EntityDiff)(nil), "magma.orc8r.configurator.storage.EntityDiff")
+	proto.RegisterType((*NetworkDiff)(nil), "magma.orc8r.configurator.storage.NetworkDiff")
 }
 
 func init() {
@@ -1133,83 +1401,98 @@ func init() {
 }
 
 var fileDescriptor_1622decbcca5fb09 = []byte{
-	// 1244 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x57, 0xef, 0x6e, 0x1b, 0x45,
-	0x10, 0xd7, 0x9d, 0x9d, 0xc6, 0x1e, 0xdb, 0x6d, 0xb2, 0x71, 0xdb, 0xa3, 0x2d, 0xa9, 0x39, 0x04,
-	0x4a, 0x8b, 0xb0, 0x8b, 0x8b, 0x4a, 0x09, 0x05, 0x29, 0x8d, 0x9d, 0xca, 0x02, 0xd2, 0x70, 0x31,
-	0x08, 0x15, 0xa1, 0x63, 0xeb, 0xdb, 0x38, 0x27, 0xdb, 0xb7, 0xa7, 0xdd, 0x75, 0x8c, 0xfb, 0x02,
-	0x80, 0xca, 0xd3, 0xf0, 0x08, 0xbc, 0x09, 0x2f, 0xc0, 0x67, 0x3e, 0xa2, 0xfd, 0x73, 0xf6, 0xd9,
-	0x69, 0x15, 0x1f, 0x20, 0xf1, 0xc9, 0xbb, 0xb3, 0xfb, 0xfb, 0xed, 0xce, 0xec, 0xcc, 0x6f, 0xce,
-	0xb0, 0x4b, 0x59, 0xef, 0x21, 0x6b, 0xf4, 0x86, 0x74, 0x1c, 0x34, 0xfa, 0xb4, 0xc1, 0x09, 0x3b,
-	0x0b, 0x7b, 0x84, 0x37, 0x7a, 0x34, 0x3a, 0x09, 0xfb, 0x63, 0x86, 0x05, 0x65, 0x0d, 0x2e, 0x28,
-	0xc3, 0x7d, 0x92, 0xfc, 0xd6, 0x63, 0x46, 0x05, 0x45, 0xb5, 0x11, 0xee, 0x8f, 0x70, 0x5d, 0x31,
-	0xd4, 0xd3, 0xfb, 0xeb, 0x66, 0xdf, 0x8d, 0xed, 0x3e, 0xa5, 0xfd, 0x21, 0x69, 0xa8, 0xfd, 0xcf,
-	0xc7, 0x27, 0x8d, 0x09, 0xc3, 0x71, 0x4c, 0x18, 0xd7, 0x0c, 0xee, 0x4b, 0x1b, 0xd6, 0x0f, 0x89,
-	0x98, 0x50, 0x36, 0x40, 0x97, 0xc1, 0xee, 0xb4, 0x1c, 0xab, 0x66, 0xed, 0x14, 0x3d, 0xbb, 0xd3,
-	0x42, 0x08, 0xf2, 0xdd, 0x69, 0x4c, 0x1c, 0x5b, 0x59, 0xd4, 0x58, 0xda, 0x22, 0x3c, 0x22, 0x0e,
-	0x68, 0x9b, 0x1c, 0xa3, 0x1a, 0x94, 0x02, 0xc2, 0x7b, 0x2c, 0x8c, 0x45, 0x48, 0x23, 0xa7, 0xa4,
-	0x96, 0xd2, 0x26, 0x74, 0x04, 0xeb, 0xfa, 0x76, 0xdc, 0xa9, 0xd6, 0x72, 0x3b, 0xa5, 0xe6, 0x83,
-	0xfa, 0x45, 0x37, 0xaf, 0x9b, 0x5b, 0xd5, 0xf7, 0x35, 0xb0, 0x1d, 0x09, 0x36, 0xf5, 0x12, 0x1a,
-	0xe4, 0xc0, 0xfa, 0x19, 0x61, 0x5c, 0x9e, 0xb7, 0x5d, 0xb3, 0x76, 0xf2, 0x5e, 0x32, 0xbd, 0xb1,
-	0x0b, 0xe5, 0x34, 0x04, 0x6d, 0x40, 0x6e, 0x40, 0xa6, 0xc6, 0x2d, 0x39, 0x44, 0x55, 0x58, 0x3b,
-	0xc3, 0xc3, 0xb1, 0x76, 0xac, 0xec, 0xe9, 0xc9, 0xae, 0xfd, 0xd0, 0x72, 0x03, 0xd8, 0x34, 0xc7,
-	0x7e, 0x41, 0x71, 0x70, 0x10, 0x0e, 0x05, 0x61, 0x92, 0x20, 0x0c, 0xb8, 0x63, 0xd5, 0x72, 0x92,
-	0x20, 0x0c, 0x38, 0xfa, 0x14, 0x4a, 0x62, 0x1a, 0x13, 0xff, 0x44, 0x6d, 0x50, 0x34, 0xa5, 0xe6,
-	0xad, 0xba, 0x0e, 0x75, 0x3d, 0x09, 0x75, 0xfd, 0x58, 0xb0, 0x30, 0xea, 0x7f, 0x23, 0xd9, 0x3d,
-	0x90, 0x00, 0x4d, 0xe8, 0x7e, 0x0f, 0x5b, 0xa9, 0x53, 0xf6, 0x59, 0x28, 0x08, 0x0b, 0x31, 0x7a,
-	0x1b, 0x2a, 0x43, 0x8a, 0x03, 0x7f, 0x44, 0x04, 0x0e, 0xb0, 0xc0, 0xea, 0xca, 0x05, 0xaf, 0x2c,
-	0x8d, 0x5f, 0x1a, 0x1b, 0x7a, 0x0b, 0xd4, 0xdc, 0x4f, 0xc2, 0x69, 0xab, 0x3d, 0x25, 0x69, 0x33,
-	0x5e, 0xbb, 0xbf, 0x5a, 0x0b, 0x5e, 0x78, 0x84, 0x8f, 0x87, 0x02, 0xb5, 0xa1, 0x10, 0x69, 0xa3,
-	0x76, 0xa5, 0xd4, 0xbc, 0xb3, 0xf2, 0x1b, 0x78, 0x33, 0x28, 0xba, 0x07, 0x55, 0x33, 0xee, 0xb4,
-	0xb8, 0x1f, 0x51, 0xe1, 0x9f, 0xd0, 0x71, 0x14, 0x38, 0xb6, 0x8a, 0x0e, 0x9a, 0xaf, 0x1d, 0x52,
-	0x71, 0x20, 0x57, 0xdc, 0x9f, 0xf3, 0x70, 0xd5, 0xf0, 0x7c, 0x1d, 0x07, 0x58, 0x90, 0x99, 0xc3,
-	0xcb, 0xf9, 0xf6, 0x0e, 0x5c, 0x0e, 0xc8, 0x90, 0x08, 0xe2, 0x1b, 0x1a, 0x95, 0x65, 0x05, 0xaf,
-	0xa2, 0xad, 0x49, 0x9a, 0x7e, 0x24, 0x3d, 0x99, 0xf8, 0x2a, 0x0d, 0xab, 0x2b, 0x84, 0x7e, 0x3d,
-	0x22, 0x93, 0x43, 0x99, 0xa7, 0x6d, 0xb8, 0x22, 0x81, 0xe9, 0x5c, 0xbd, 0xba, 0x02, 0xfe, 0x72,
-	0x44, 0x26, 0xad, 0x54, 0x32, 0x9b, 0xf3, 0xe5, 0x83, 0x3a, 0xd7, 0x56, 0x3c, 0x5f, 0xd5, 0xce,
-	0x2f, 0x16, 0x38, 0xe6, 0xdd, 0x7c, 0x41, 0x7d, 0x1c, 0x04, 0x3e, 0x65, 0xfe, 0x58, 0x05, 0xc5,
-	0xd9, 0x56, 0x6f, 0xf2, 0xd5, 0xca, 0x6f, 0xb2, 0x18, 0xcb, 0xa4, 0x4a, 0xba, 0x74, 0x2f, 0x08,
-	0x9e, 0x32, 0xbd, 0xa8, 0x4b, 0xa6, 0xda, 0x7b, 0xc5, 0x12, 0xba, 0x0b, 0x9b, 0xa9, 0xab, 0xe8,
-	0x00, 0x3b, 0xb7, 0xd5, 0x23, 0x5e, 0x99, 0x01, 0x5a, 0xca, 0x7c, 0xe3, 0x09, 0xbc, 0xf1, 0x5a,
-	0xfa, 0x4c, 0xe5, 0x75, 0x0f, 0x0a, 0xed, 0x48, 0x84, 0x62, 0xaa, 0xc5, 0x45, 0x45, 0x50, 0x03,
-	0xd5, 0x38, 0xe1, 0xb2, 0x67, 0x5c, 0xee, 0x6f, 0x39, 0xa8, 0x18, 0x87, 0x35, 0x12, 0xdd, 0x82,
-	0xe2, 0x2c, 0xc9, 0x0c, 0x78, 0x6e, 0x98, 0xb1, 0xda, 0xe7, 0x59, 0x73, 0xf3, 0x1b, 0xfe, 0x33,
-	0x11, 0xdb, 0x06, 0x88, 0x4f, 0xa7, 0x3c, 0xec, 0xe1, 0x61, 0xa7, 0xa5, 0x32, 0xaf, 0xe8, 0xa5,
-	0x2c, 0xe8, 0x1a, 0x5c, 0xd2, 0x91, 0x53, 0x8a, 0x54, 0xf6, 0xcc, 0x4c, 0x4a, 0x55, 0x9f, 0xe1,
-	0xf8, 0xb4, 0xd3, 0x72, 0x76, 0x14, 0x28, 0x99, 0xca, 0x02, 0x88, 0x07, 0xce, 0x1d, 0x5d, 0x00,
-	0xf1, 0x00, 0x1d, 0x42, 0x19, 0x73, 0x4e, 0x7b, 0x21, 0x96, 0x07, 0x72, 0xa7, 0xa9, 0x72, 0xe2,
-	0xee, 0xc5, 0x39, 0x91, 0x44, 0xd5, 0x5b, 0xc0, 0xa3, 0xef, 0x60, 0x2b, 0xc6, 0x8c, 0x44, 0xc2,
-	0x5f, 0xa0, 0xbd, 0x9f, 0x99, 0x16, 0x69, 0x9a, 0xbd, 0x34, 0x79, 0x4a, 0x81, 0x0f, 0x16, 0x14,
-	0xd8, 0xfd, 0xdd, 0x86, 0x0d, 0x0d, 0x4d, 0xa9, 0xe8, 0x92, 0x66, 0x5a, 0xd9, 0x34, 0x13, 0x7d,
-	0x02, 0x30, 0x20, 0xd3, 0x2c, 0x8a, 0x5b, 0x1c, 0x90, 0xa9, 0x01, 0x3f, 0x82, 0x5c, 0xa7, 0xc5,
-	0x9d, 0x5c, 0x66, 0xbf, 0x25, 0x0c, 0x3d, 0x98, 0xbf, 0x5f, 0x7e, 0x95, 0x72, 0x4f, 0x5e, 0xf7,
-	0xd1, 0x42, 0xbe, 0xac, 0xad, 0xe2, 0xf0, 0x7c, 0xbf, 0xfb, 0x97, 0x05, 0x68, 0x1e, 0xc4, 0x6c,
-	0x4d, 0xe2, 0x36, 0x94, 0x52, 0x4d, 0xc2, 0xf4, 0x08, 0x98, 0xf7, 0x08, 0xf4, 0x3e, 0x6c, 0xa9,
-	0x0d, 0x2a, 0x2d, 0x94, 0x02, 0x88, 0xd3, 0x90, 0xab, 0x12, 0x29, 0x78, 0x1b, 0x72, 0x49, 0x3d,
-	0x35, 0xef, 0xd2, 0xee, 0x69, 0xc8, 0xd1, 0x07, 0x70, 0x35, 0xbd, 0xfd, 0x84, 0xd1, 0x91, 0x06,
-	0xe4, 0x15, 0x00, 0xcd, 0x01, 0x07, 0x8c, 0x8e, 0x14, 0xe4, 0x26, 0x14, 0x63, 0xdc, 0x27, 0x3e,
-	0x0f, 0x5f, 0x10, 0xe7, 0x52, 0xcd, 0xda, 0xa9, 0x78, 0x05, 0x69, 0x38, 0x0e, 0x5f, 0x10, 0xf4,
-	0x26, 0x80, 0x5a, 0x14, 0x74, 0x40, 0x22, 0x67, 0x5d, 0x17, 0xb1, 0xb4, 0x74, 0xa5, 0xc1, 0xfd,
-	0xc3, 0x4a, 0xe7, 0x8f, 0xe9, 0x5f, 0x9f, 0x43, 0x81, 0x48, 0x5b, 0x48, 0x92, 0xfe, 0xd5, 0x58,
-	0x59, 0x2b, 0x35, 0x99, 0x37, 0x23, 0x40, 0xdf, 0x02, 0x4a, 0xc6, 0x4b, 0x3d, 0x2c, 0x5b, 0x7e,
-	0x6c, 0x24, 0x2c, 0x49, 0xb7, 0x43, 0xef, 0xca, 0x1e, 0xf3, 0xa3, 0xf0, 0x53, 0xfe, 0x69, 0xe1,
-	0xa9, 0x48, 0xf3, 0xd1, 0xcc, 0xc7, 0x3b, 0xb0, 0xa9, 0x59, 0xf6, 0xe9, 0x38, 0x12, 0xc6, 0xc7,
-	0x2a, 0xac, 0xf5, 0xe4, 0x54, 0x3d, 0x6a, 0xde, 0xd3, 0x13, 0x77, 0x1f, 0xae, 0xe8, 0xad, 0x33,
-	0xb4, 0xec, 0xc2, 0x43, 0xcc, 0x85, 0x1f, 0x46, 0xbd, 0xe1, 0x38, 0x20, 0x81, 0xaf, 0xee, 0x91,
-	0xa8, 0x30, 0x92, 0x6b, 0x1d, 0xb3, 0xa4, 0xa1, 0xee, 0xcb, 0x35, 0xa8, 0xea, 0xe1, 0x52, 0x13,
-	0x5e, 0x49, 0x87, 0x65, 0xda, 0x99, 0xd6, 0x6c, 0x4e, 0xd2, 0x9d, 0xb9, 0xac, 0x8d, 0x46, 0x9a,
-	0xff, 0xef, 0xc6, 0xbc, 0x0f, 0xd2, 0xe2, 0xa7, 0x8a, 0x6e, 0x95, 0xf6, 0x5c, 0x89, 0xc8, 0xe4,
-	0x68, 0xae, 0xe2, 0xbb, 0x00, 0x92, 0xc4, 0x94, 0xce, 0x75, 0x45, 0x70, 0xf3, 0x1c, 0xc1, 0xe3,
-	0xa9, 0x20, 0xdc, 0xe8, 0x4c, 0x44, 0x26, 0xa6, 0xac, 0x42, 0xd8, 0x4a, 0x0b, 0xad, 0xac, 0x2b,
-	0x4e, 0x84, 0x6a, 0x07, 0xa5, 0xe6, 0xc7, 0xab, 0xe6, 0x55, 0x5a, 0x65, 0xbb, 0xf4, 0x98, 0x08,
-	0x6f, 0x13, 0x2f, 0x9b, 0xd0, 0xb3, 0xf3, 0x47, 0xe1, 0x20, 0x50, 0x1d, 0x3c, 0x5b, 0x0a, 0x2f,
-	0x71, 0xef, 0x05, 0x01, 0xfa, 0x01, 0xae, 0x2d, 0x73, 0x9b, 0x0f, 0x84, 0x5a, 0x66, 0xfa, 0xea,
-	0x22, 0xbd, 0xfe, 0xa2, 0x70, 0xc7, 0x70, 0xfd, 0x35, 0xbe, 0xbe, 0xca, 0x31, 0x19, 0x43, 0xeb,
-	0xdf, 0x3a, 0x76, 0x4c, 0x84, 0xfb, 0xa7, 0x05, 0x25, 0xbd, 0xfe, 0x44, 0x6a, 0xf4, 0x7f, 0xab,
-	0x29, 0x4f, 0xa1, 0xc2, 0x28, 0x15, 0xfe, 0x8c, 0x31, 0xbb, 0x9c, 0x94, 0x25, 0x41, 0x3b, 0x21,
-	0xdc, 0x83, 0x35, 0x12, 0xf4, 0x49, 0xd2, 0xb7, 0xde, 0xbb, 0x98, 0x48, 0x79, 0xd5, 0x0e, 0xfa,
-	0xc4, 0xd3, 0x48, 0xf7, 0x27, 0x0b, 0x8a, 0x33, 0x23, 0xda, 0x05, 0x5b, 0x50, 0xd3, 0x79, 0xb3,
-	0x5c, 0xcb, 0x16, 0x14, 0x7d, 0x06, 0x79, 0x29, 0xfb, 0xa6, 0xf3, 0x66, 0x41, 0x2b, 0xdc, 0xe3,
-	0x0f, 0x9f, 0x35, 0x15, 0xa4, 0x91, 0xe5, 0xdf, 0xee, 0xf3, 0x4b, 0xaa, 0xe0, 0xee, 0xff, 0x1d,
-	0x00, 0x00, 0xff, 0xff, 0xee, 0x67, 0x19, 0x9c, 0x24, 0x0f, 0x00, 0x00,
+	// 1476 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0xdf, 0x72, 0xdb, 0x44,
+	0x17, 0x1f, 0xc9, 0x4e, 0x62, 0x1f, 0xdb, 0x4d, 0xb2, 0x71, 0x5b, 0x7d, 0x69, 0xbf, 0xd4, 0x9f,
+	0xbe, 0x81, 0x49, 0x0b, 0xd8, 0x6d, 0xda, 0x29, 0x25, 0x14, 0x66, 0xdc, 0xd8, 0x09, 0x1e, 0x68,
+	0x1a, 0x94, 0xb4, 0xc3, 0x94, 0x61, 0xc4, 0xd6, 0x5a, 0x3b, 0xc2, 0x8e, 0x56, 0xb3, 0x5a, 0xc7,
+	0x75, 0x6f, 0xb9, 0x00, 0x06, 0x9e, 0x86, 0x47, 0xe0, 0x19, 0xb8, 0xe0, 0x96, 0x17, 0xe0, 0x82,
+	0x2b, 0x2e, 0x99, 0xfd, 0x23, 0x5b, 0x76, 0xdb, 0x89, 0x95, 0x32, 0xc3, 0x55, 0xa4, 0xb3, 0xfb,
+	0xfb, 0xed, 0x9e, 0x73, 0x7e, 0xe7, 0x1c, 0x2b, 0xb0, 0x4d, 0x59, 0xfb, 0x1e, 0xab, 0xb5, 0xfb,
+	0x74, 0xe0, 0xd5, 0xba, 0xb4, 0x16, 0x11, 0x76, 0xea, 0xb7, 0x49, 0x54, 0x6b, 0xd3, 0xa0, 0xe3,
+	0x77, 0x07, 0x0c, 0x73, 0xca, 0x6a, 0x11, 0xa7, 0x0c, 0x77, 0x49, 0xfc, 0xb7, 0x1a, 0x32, 0xca,
+	0x29, 0xaa, 0x9c, 0xe0, 0xee, 0x09, 0xae, 0x4a, 0x86, 0x6a, 0x72, 0x7f, 0x55, 0xef, 0x5b, 0xdf,
+	0xe8, 0x52, 0xda, 0xed, 0x93, 0x9a, 0xdc, 0xff, 0x6c, 0xd0, 0xa9, 0x0d, 0x19, 0x0e, 0x43, 0xc2,
+	0x22, 0xc5, 0x60, 0xff, 0x68, 0xc2, 0xd2, 0x3e, 0xe1, 0x43, 0xca, 0x7a, 0xe8, 0x02, 0x98, 0xad,
+	0x86, 0x65, 0x54, 0x8c, 0xcd, 0xbc, 0x63, 0xb6, 0x1a, 0x08, 0x41, 0xf6, 0x68, 0x14, 0x12, 0xcb,
+	0x94, 0x16, 0xf9, 0x2c, 0x6c, 0x01, 0x3e, 0x21, 0x16, 0x28, 0x9b, 0x78, 0x46, 0x15, 0x28, 0x78,
+	0x24, 0x6a, 0x33, 0x3f, 0xe4, 0x3e, 0x0d, 0xac, 0x82, 0x5c, 0x4a, 0x9a, 0xd0, 0x01, 0x2c, 0xa9,
+	0xdb, 0x45, 0x56, 0xb9, 0x92, 0xd9, 0x2c, 0x6c, 0xdd, 0xad, 0x9e, 0x75, 0xf3, 0xaa, 0xbe, 0x55,
+	0x75, 0x47, 0x01, 0x9b, 0x01, 0x67, 0x23, 0x27, 0xa6, 0x41, 0x16, 0x2c, 0x9d, 0x12, 0x16, 0x89,
+	0xf3, 0x36, 0x2a, 0xc6, 0x66, 0xd6, 0x89, 0x5f, 0xd7, 0xb7, 0xa1, 0x98, 0x84, 0xa0, 0x15, 0xc8,
+	0xf4, 0xc8, 0x48, 0xbb, 0x25, 0x1e, 0x51, 0x19, 0x16, 0x4e, 0x71, 0x7f, 0xa0, 0x1c, 0x2b, 0x3a,
+	0xea, 0x65, 0xdb, 0xbc, 0x67, 0xd8, 0x1e, 0xac, 0xea, 0x63, 0x3f, 0xa3, 0xd8, 0xdb, 0xf5, 0xfb,
+	0x9c, 0x30, 0x41, 0xe0, 0x7b, 0x91, 0x65, 0x54, 0x32, 0x82, 0xc0, 0xf7, 0x22, 0xf4, 0x11, 0x14,
+	0xf8, 0x28, 0x24, 0x6e, 0x47, 0x6e, 0x90, 0x34, 0x85, 0xad, 0xab, 0x55, 0x15, 0xea, 0x6a, 0x1c,
+	0xea, 0xea, 0x21, 0x67, 0x7e, 0xd0, 0x7d, 0x22, 0xd8, 0x1d, 0x10, 0x00, 0x45, 0x68, 0x7f, 0x05,
+	0x6b, 0x89, 0x53, 0x76, 0x98, 0xcf, 0x09, 0xf3, 0x31, 0xfa, 0x3f, 0x94, 0xfa, 0x14, 0x7b, 0xee,
+	0x09, 0xe1, 0xd8, 0xc3, 0x1c, 0xcb, 0x2b, 0xe7, 0x9c, 0xa2, 0x30, 0x3e, 0xd4, 0x36, 0xf4, 0x3f,
+	0x90, 0xef, 0x6e, 0x1c, 0x4e, 0x53, 0xee, 0x29, 0x08, 0x9b, 0xf6, 0xda, 0xfe, 0xc9, 0x98, 0xf2,
+	0xc2, 0x21, 0xd1, 0xa0, 0xcf, 0x51, 0x13, 0x72, 0x81, 0x32, 0x2a, 0x57, 0x0a, 0x5b, 0xd7, 0xe7,
+	0xce, 0x81, 0x33, 0x86, 0xa2, 0x9b, 0x50, 0xd6, 0xcf, 0xad, 0x46, 0xe4, 0x06, 0x94, 0xbb, 0x1d,
+	0x3a, 0x08, 0x3c, 0xcb, 0x94, 0xd1, 0x41, 0x93, 0xb5, 0x7d, 0xca, 0x77, 0xc5, 0x8a, 0xfd, 0x7d,
+	0x16, 0x2e, 0x6a, 0x9e, 0xc7, 0xa1, 0x87, 0x39, 0x19, 0x3b, 0x3c, 0xab, 0xb7, 0xb7, 0xe0, 0x82,
+	0x47, 0xfa, 0x84, 0x13, 0x57, 0xd3, 0x48, 0x95, 0xe5, 0x9c, 0x92, 0xb2, 0xc6, 0x32, 0x7d, 0x5f,
+	0x78, 0x32, 0x74, 0xa5, 0x0c, 0xcb, 0x73, 0x84, 0x7e, 0x29, 0x20, 0xc3, 0x7d, 0xa1, 0xd3, 0x26,
+	0x2c, 0x0b, 0x60, 0x52, 0xab, 0x17, 0xe7, 0xc0, 0x5f, 0x08, 0xc8, 0xb0, 0x91, 0x10, 0xb3, 0x3e,
+	0x5f, 0x24, 0xd4, 0xba, 0x34, 0xe7, 0xf9, 0xb2, 0x76, 0x7e, 0x30, 0xc0, 0xd2, 0x79, 0x73, 0x39,
+	0x75, 0xb1, 0xe7, 0xb9, 0x94, 0xb9, 0x03, 0x19, 0x14, 0x6b, 0x43, 0xe6, 0xe4, 0xf3, 0xb9, 0x73,
+	0x32, 0x1d, 0xcb, 0xb8, 0x4a, 0x8e, 0x68, 0xdd, 0xf3, 0x1e, 0x31, 0xb5, 0xa8, 0x4a, 0xa6, 0xdc,
+	0x7e, 0xc5, 0x12, 0xba, 0x01, 0xab, 0x89, 0xab, 0xa8, 0x00, 0x5b, 0xd7, 0x64, 0x12, 0x97, 0xc7,
+	0x80, 0x86, 0x34, 0xaf, 0xef, 0xc1, 0x7f, 0x5e, 0x4b, 0x9f, 0xaa, 0xbc, 0x6e, 0x42, 0xae, 0x19,
+	0x70, 0x9f, 0x8f, 0x54, 0x73, 0x91, 0x11, 0x54, 0x40, 0xf9, 0x1c, 0x73, 0x99, 0x63, 0x2e, 0xfb,
+	0xe7, 0x0c, 0x94, 0xb4, 0xc3, 0x0a, 0x89, 0xae, 0x42, 0x7e, 0x2c, 0x32, 0x0d, 0x9e, 0x18, 0xc6,
+	0xac, 0xe6, 0xcb, 0xac, 0x99, 0xc9, 0x0d, 0xcf, 0xd7, 0xc4, 0x36, 0x00, 0xc2, 0xe3, 0x51, 0xe4,
+	0xb7, 0x71, 0xbf, 0xd5, 0x90, 0xca, 0xcb, 0x3b, 0x09, 0x0b, 0xba, 0x04, 0x8b, 0x2a, 0x72, 0xb2,
+	0x23, 0x15, 0x1d, 0xfd, 0x26, 0x5a, 0x55, 0x97, 0xe1, 0xf0, 0xb8, 0xd5, 0xb0, 0x36, 0x25, 0x28,
+	0x7e, 0x15, 0x05, 0x10, 0xf6, 0xac, 0xeb, 0xaa, 0x00, 0xc2, 0x1e, 0xda, 0x87, 0x22, 0x8e, 0x22,
+	0xda, 0xf6, 0xb1, 0x38, 0x30, 0xb2, 0xb6, 0xa4, 0x26, 0x6e, 0x9c, 0xad, 0x89, 0x38, 0xaa, 0xce,
+	0x14, 0x1e, 0x7d, 0x09, 0x6b, 0x21, 0x66, 0x24, 0xe0, 0xee, 0x14, 0xed, 0xed, 0xd4, 0xb4, 0x48,
+	0xd1, 0xd4, 0x93, 0xe4, 0x89, 0x0e, 0xbc, 0x3b, 0xd5, 0x81, 0xed, 0x5f, 0x4c, 0x58, 0x51, 0xd0,
+	0x44, 0x17, 0x9d, 0xe9, 0x99, 0x46, 0xba, 0x9e, 0x89, 0x3e, 0x04, 0xe8, 0x91, 0x51, 0x9a, 0x8e,
+	0x9b, 0xef, 0x91, 0x91, 0x06, 0xdf, 0x87, 0x4c, 0xab, 0x11, 0x59, 0x99, 0xd4, 0x7e, 0x0b, 0x18,
+	0xba, 0x3b, 0xc9, 0x5f, 0x76, 0x9e, 0x72, 0x8f, 0xb3, 0x7b, 0x7f, 0x4a, 0x2f, 0x0b, 0xf3, 0x38,
+	0x3c, 0xd9, 0x6f, 0xff, 0x65, 0x00, 0x9a, 0x04, 0x31, 0xdd, 0x90, 0xb8, 0x06, 0x85, 0xc4, 0x90,
+	0xd0, 0x33, 0x02, 0x26, 0x33, 0x02, 0xbd, 0x07, 0x6b, 0x72, 0x83, 0x94, 0x85, 0xec, 0x00, 0xfc,
+	0xd8, 0x8f, 0x64, 0x89, 0xe4, 0x9c, 0x15, 0xb1, 0x24, 0x53, 0x1d, 0x1d, 0xd1, 0xa3, 0x63, 0x3f,
+	0x42, 0xb7, 0xe0, 0x62, 0x72, 0x7b, 0x87, 0xd1, 0x13, 0x05, 0xc8, 0x4a, 0x00, 0x9a, 0x00, 0x76,
+	0x19, 0x3d, 0x91, 0x90, 0x2b, 0x90, 0x0f, 0x71, 0x97, 0xb8, 0x91, 0xff, 0x82, 0x58, 0x8b, 0x15,
+	0x63, 0xb3, 0xe4, 0xe4, 0x84, 0xe1, 0xd0, 0x7f, 0x41, 0xd0, 0x7f, 0x01, 0xe4, 0x22, 0xa7, 0x3d,
+	0x12, 0x58, 0x4b, 0xaa, 0x88, 0x85, 0xe5, 0x48, 0x18, 0xec, 0xdf, 0x8d, 0xa4, 0x7e, 0xf4, 0xfc,
+	0xfa, 0x14, 0x72, 0x44, 0xd8, 0x7c, 0x12, 0xcf, 0xaf, 0xda, 0xdc, 0xbd, 0x52, 0x91, 0x39, 0x63,
+	0x02, 0xf4, 0x05, 0xa0, 0xf8, 0x79, 0x66, 0x86, 0xa5, 0xd3, 0xc7, 0x4a, 0xcc, 0x12, 0x4f, 0x3b,
+	0xf4, 0xb6, 0x98, 0x31, 0xcf, 0xb9, 0x9b, 0xf0, 0x4f, 0x35, 0x9e, 0x92, 0x30, 0x1f, 0x8c, 0x7d,
+	0xbc, 0x0e, 0xab, 0x8a, 0x65, 0x87, 0x0e, 0x02, 0xae, 0x7d, 0x2c, 0xc3, 0x42, 0x5b, 0xbc, 0xca,
+	0xa4, 0x66, 0x1d, 0xf5, 0x62, 0xef, 0xc0, 0xb2, 0xda, 0x3a, 0x46, 0x8b, 0x29, 0xdc, 0xc7, 0x11,
+	0x77, 0xfd, 0xa0, 0xdd, 0x1f, 0x78, 0xc4, 0x73, 0xe5, 0x3d, 0xe2, 0x2e, 0x8c, 0xc4, 0x5a, 0x4b,
+	0x2f, 0x29, 0xa8, 0xfd, 0xeb, 0x02, 0x94, 0xd5, 0xe3, 0xcc, 0x10, 0x9e, 0xab, 0x0f, 0x0b, 0xd9,
+	0xe9, 0xd1, 0xac, 0x4f, 0x52, 0x93, 0xb9, 0xa8, 0x8c, 0xba, 0x35, 0xff, 0xdb, 0x83, 0x79, 0x07,
+	0x84, 0xc5, 0x4d, 0x14, 0xdd, 0x3c, 0xe3, 0xb9, 0x14, 0x90, 0xe1, 0xc1, 0xa4, 0x8b, 0x6f, 0x03,
+	0x08, 0x12, 0x5d, 0x3a, 0x97, 0x25, 0xc1, 0x95, 0x97, 0x08, 0x1e, 0x8c, 0x38, 0x89, 0x74, 0x9f,
+	0x09, 0xc8, 0x50, 0x97, 0x95, 0x0f, 0x6b, 0xc9, 0x46, 0x2b, 0xea, 0x2a, 0x22, 0x5c, 0x8e, 0x83,
+	0xc2, 0xd6, 0x07, 0xf3, 0xea, 0x2a, 0xd9, 0x65, 0x8f, 0xe8, 0x21, 0xe1, 0xce, 0x2a, 0x9e, 0x35,
+	0xa1, 0xa7, 0x2f, 0x1f, 0x85, 0x3d, 0xcf, 0xba, 0x96, 0x5a, 0xc2, 0x33, 0xdc, 0x75, 0xcf, 0x43,
+	0x5f, 0xc3, 0xa5, 0x59, 0x6e, 0xfd, 0x03, 0xa1, 0x92, 0x9a, 0xbe, 0x3c, 0x4d, 0xaf, 0x7e, 0x51,
+	0xa0, 0x3d, 0x58, 0x21, 0xcf, 0x43, 0xd2, 0xe6, 0xc4, 0x73, 0xe3, 0x21, 0xb2, 0xf9, 0x9a, 0x5c,
+	0x3d, 0x6e, 0x05, 0xfc, 0xee, 0x1d, 0x15, 0xeb, 0xe5, 0x18, 0xf5, 0x44, 0x8f, 0x9a, 0x01, 0x5c,
+	0x7e, 0x4d, 0xd0, 0xd0, 0xd3, 0x57, 0x27, 0xc3, 0x78, 0xd3, 0x08, 0x1d, 0x12, 0x6e, 0xff, 0x61,
+	0x40, 0x41, 0xad, 0xef, 0x89, 0x66, 0xff, 0xcf, 0x36, 0xa7, 0x47, 0x50, 0x62, 0x94, 0x72, 0x77,
+	0xcc, 0x98, 0xbe, 0x2f, 0x15, 0x05, 0x41, 0x33, 0x26, 0xac, 0xc3, 0x02, 0xf1, 0xba, 0x24, 0x1e,
+	0x80, 0xef, 0x9c, 0x4d, 0x24, 0xbd, 0x6a, 0x7a, 0x5d, 0xe2, 0x28, 0xa4, 0xfd, 0x9d, 0x01, 0xf9,
+	0xb1, 0x11, 0x6d, 0x83, 0xc9, 0xa9, 0x1e, 0xe1, 0x69, 0xae, 0x65, 0x72, 0x8a, 0x3e, 0x86, 0xac,
+	0x98, 0x1f, 0x96, 0x99, 0x1a, 0x2d, 0x71, 0xf6, 0x37, 0xb0, 0x1c, 0x7f, 0x95, 0x90, 0x53, 0x5f,
+	0x88, 0xe0, 0x8c, 0x9f, 0x84, 0xeb, 0x90, 0x63, 0x7a, 0xa7, 0x3c, 0x34, 0xeb, 0x8c, 0xdf, 0xc5,
+	0x20, 0x6a, 0x33, 0x82, 0x85, 0x0c, 0x31, 0x97, 0x8d, 0x3a, 0xe3, 0xe4, 0xb5, 0xa5, 0xce, 0xed,
+	0x3f, 0x8d, 0xf1, 0x61, 0x87, 0x01, 0x0e, 0xa3, 0x63, 0xca, 0xd1, 0xc3, 0x04, 0x9d, 0x8a, 0xc0,
+	0xad, 0xf9, 0xbf, 0xa3, 0x34, 0x30, 0x71, 0x83, 0x1d, 0x58, 0x8a, 0x3f, 0x76, 0x54, 0x44, 0x52,
+	0x7c, 0x95, 0xc5, 0xc8, 0x29, 0xf9, 0x65, 0xde, 0x50, 0x7e, 0xf6, 0x6f, 0x06, 0x80, 0x32, 0x36,
+	0xfc, 0x4e, 0x47, 0xe4, 0x5a, 0x47, 0x35, 0x65, 0xae, 0x5b, 0x0d, 0xb4, 0x07, 0x8b, 0xcf, 0x48,
+	0x87, 0x32, 0xa2, 0x7d, 0x4b, 0x7d, 0x2b, 0x0d, 0x47, 0x4d, 0x58, 0xc0, 0x1d, 0xf1, 0xc3, 0x2f,
+	0x73, 0x3e, 0x1e, 0x85, 0xb6, 0xbf, 0xcd, 0x40, 0x41, 0x2f, 0x48, 0xdf, 0x9a, 0x5a, 0x8b, 0xe7,
+	0xce, 0xa3, 0x84, 0xa3, 0xba, 0x2c, 0x07, 0xf3, 0xbc, 0x24, 0xa2, 0x2a, 0x0e, 0xc4, 0xe8, 0x92,
+	0x66, 0x57, 0x47, 0x2c, 0x93, 0x56, 0x0d, 0x25, 0x4d, 0xf0, 0x40, 0x85, 0x6c, 0x1f, 0x62, 0x83,
+	0xab, 0x42, 0x97, 0x4d, 0x4b, 0x58, 0xd4, 0xf8, 0xba, 0x80, 0xa3, 0x4f, 0x12, 0x1a, 0x5b, 0x90,
+	0x1a, 0x7b, 0x77, 0x5e, 0x35, 0x88, 0x58, 0x4f, 0x04, 0xf6, 0xe0, 0xce, 0xd3, 0x2d, 0x09, 0xac,
+	0xa5, 0xf9, 0xc7, 0xd7, 0xb3, 0x45, 0x39, 0x10, 0x6e, 0xff, 0x3d, 0x00, 0xf6, 0x80, 0xfe, 0x89,
+	0x2f, 0x13, 0x00, 0x00,
 }
@@ -25,6 +25,7 @@ import (
 	"magma/orc8r/cloud/go/services/configurator/protos"
 	"magma/orc8r/cloud/go/services/configurator/servicers"
 	"magma/orc8r/cloud/go/services/configurator/storage"
+	tenants_test_init "magma/orc8r/cloud/go/services/tenants/test_init"
 	"magma/orc8r/cloud/go/sqorc"
 	"magma/orc8r/cloud/go/test_utils"
 )
@@ -47,6 +48,8 @@ func StartTestService(t *testing.T) {
 
 	accessd_test_init.StartTestService(t)
 	certifier_test_init.StartTestService(t)
+	// Tenant quotas are checked on writes
+	tenants_test_init.StartTestService(t)
 
 	srv, lis := test_utils.NewTestService(t, orc8r.ModuleName, configurator.ServiceName)
 	nb, err := servicers.NewNorthboundConfiguratorServicer(storageFactory)
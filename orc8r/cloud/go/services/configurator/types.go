@@ -17,6 +17,7 @@ import (
 	"fmt"
 
 	"magma/orc8r/cloud/go/serde"
+	"magma/orc8r/cloud/go/services/configurator/protos"
 	"magma/orc8r/cloud/go/services/configurator/storage"
 	storage2 "magma/orc8r/cloud/go/storage"
 
@@ -524,6 +525,11 @@ type EntityUpdateCriteria struct {
 	AssociationsToSet    []storage2.TypeAndKey
 	AssociationsToAdd    []storage2.TypeAndKey
 	AssociationsToDelete []storage2.TypeAndKey
+
+	// If set, the update is only applied if the entity's stored version
+	// matches. A value of 0 requires the entity to not exist.
+	// On mismatch, the update fails with a *storage.VersionConflictError.
+	ExpectedVersion *uint64
 }
 
 func (euc EntityUpdateCriteria) toProto(serdes serde.Registry) (*storage.EntityUpdateCriteria, error) {
@@ -536,6 +542,7 @@ func (euc EntityUpdateCriteria) toProto(serdes serde.Registry) (*storage.EntityU
 		NewPhysicalID:        strPtrToWrapper(euc.NewPhysicalID),
 		AssociationsToAdd:    tksToEntIDs(euc.AssociationsToAdd),
 		AssociationsToDelete: tksToEntIDs(euc.AssociationsToDelete),
+		ExpectedVersion:      uint64PtrToWrapper(euc.ExpectedVersion),
 	}
 
 	if euc.AssociationsToSet != nil {
@@ -569,6 +576,166 @@ func (euc EntityUpdateCriteria) GetTypeAndKey() storage2.TypeAndKey {
 
 func (euc EntityUpdateCriteria) isEntityWriteOperation() {}
 
+// RevisionSelector identifies a revision of a network. The zero value
+// selects the latest revision. If both fields are set, Revision takes
+// precedence.
+type RevisionSelector struct {
+	Revision uint64
+	// Timestamp is a Unix timestamp (seconds). Selects the latest revision
+	// recorded at or before the timestamp.
+	Timestamp int64
+}
+
+func (rs RevisionSelector) toProto() *protos.RevisionSelector {
+	switch {
+	case rs.Revision != 0:
+		return &protos.RevisionSelector{Selector: &protos.RevisionSelector_Revision{Revision: rs.Revision}}
+	case rs.Timestamp != 0:
+		return &protos.RevisionSelector{Selector: &protos.RevisionSelector_Timestamp{Timestamp: rs.Timestamp}}
+	default:
+		return nil
+	}
+}
+
+// NetworkRevision identifies a recorded revision of a network. Each write
+// to a network or its entities is recorded as a new revision.
+type NetworkRevision struct {
+	NetworkID string
+	Revision  uint64
+	// CreatedAt is the Unix timestamp (seconds) at which the revision was
+	// recorded
+	CreatedAt int64
+}
+
+func (nr NetworkRevision) fromProto(p *storage.NetworkRevision) NetworkRevision {
+	nr.NetworkID = p.GetNetworkID()
+	nr.Revision = p.GetRevision()
+	nr.CreatedAt = p.GetCreatedAt()
+	return nr
+}
+
+// NetworkSnapshot is the state of a network and its entity graph as of a
+// revision.
+type NetworkSnapshot struct {
+	Revision NetworkRevision
+	Network  Network
+	Entities NetworkEntities
+}
+
+func (ns NetworkSnapshot) fromProto(p *storage.NetworkSnapshot, networkSerdes, entitySerdes serde.Registry) (NetworkSnapshot, error) {
+	network, err := networkFromProtoWithDefault(p.Network, networkSerdes)
+	if err != nil {
+		return ns, err
+	}
+	ns.Revision = (NetworkRevision{}).fromProto(p.Revision)
+	ns.Network = network
+	ns.Entities = make(NetworkEntities, 0, len(p.Entities))
+	for _, protoEnt := range p.Entities {
+		ent, err := (NetworkEntity{}).fromProtoWithDefault(protoEnt, entitySerdes)
+		if err != nil {
+			return ns, errors.Wrapf(err, "failed to deserialize entity %s", protoEnt.GetTypeAndKey())
+		}
+		ns.Entities = append(ns.Entities, ent)
+	}
+	return ns, nil
+}
+
+// EntityDiff is the change to a single entity between two revisions.
+type EntityDiff struct {
+	ID storage2.TypeAndKey
+	// Before is nil if the entity was created between the two revisions
+	Before *NetworkEntity
+	// After is nil if the entity was deleted between the two revisions
+	After *NetworkEntity
+}
+
+// NetworkDiff is the set of changes to a network between two revisions.
+type NetworkDiff struct {
+	From NetworkRevision
+	To   NetworkRevision
+
+	// NetworkBefore and NetworkAfter are set only if the network's metadata
+	// or configs changed between the two revisions
+	NetworkBefore *Network
+	NetworkAfter  *Network
+
+	// Entities which were created, updated, or deleted between the two
+	// revisions, ordered by (type, key)
+	Entities []EntityDiff
+}
+
+// EntityChanges returns the IDs of the entities which were created, updated,
+// and deleted between the two revisions.
+func (nd NetworkDiff) EntityChanges() (created, updated, deleted storage2.TKs) {
+	for _, entDiff := range nd.Entities {
+		switch {
+		case entDiff.Before == nil:
+			created = append(created, entDiff.ID)
+		case entDiff.After == nil:
+			deleted = append(deleted, entDiff.ID)
+		default:
+			updated = append(updated, entDiff.ID)
+		}
+	}
+	return
+}
+
+func (nd NetworkDiff) fromProto(p *storage.NetworkDiff, networkSerdes, entitySerdes serde.Registry) (NetworkDiff, error) {
+	nd.From = (NetworkRevision{}).fromProto(p.From)
+	nd.To = (NetworkRevision{}).fromProto(p.To)
+	if p.NetworkBefore != nil {
+		network, err := networkFromProtoWithDefault(p.NetworkBefore, networkSerdes)
+		if err != nil {
+			return nd, err
+		}
+		nd.NetworkBefore = &network
+	}
+	if p.NetworkAfter != nil {
+		network, err := networkFromProtoWithDefault(p.NetworkAfter, networkSerdes)
+		if err != nil {
+			return nd, err
+		}
+		nd.NetworkAfter = &network
+	}
+
+	nd.Entities = make([]EntityDiff, 0, len(p.Entities))
+	for _, protoDiff := range p.Entities {
+		entDiff := EntityDiff{ID: protoDiff.ID.ToTypeAndKey()}
+		if protoDiff.Before != nil {
+			ent, err := (NetworkEntity{}).fromProtoWithDefault(protoDiff.Before, entitySerdes)
+			if err != nil {
+				return nd, errors.Wrapf(err, "failed to deserialize entity %s", entDiff.ID)
+			}
+			entDiff.Before = &ent
+		}
+		if protoDiff.After != nil {
+			ent, err := (NetworkEntity{}).fromProtoWithDefault(protoDiff.After, entitySerdes)
+			if err != nil {
+				return nd, errors.Wrapf(err, "failed to deserialize entity %s", entDiff.ID)
+			}
+			entDiff.After = &ent
+		}
+		nd.Entities = append(nd.Entities, entDiff)
+	}
+	return nd, nil
+}
+
+// networkFromProtoWithDefault is the same as Network.FromProto, except
+// configs without a registered serde are left as serialized bytes rather
+// than dropped, so a revision's configs are complete.
+func networkFromProtoWithDefault(protoNet *storage.Network, serdes serde.Registry) (Network, error) {
+	n, err := (Network{}).FromProto(protoNet, serdes)
+	if err != nil {
+		return n, err
+	}
+	for typ, config := range protoNet.Configs {
+		if _, ok := n.Configs[typ]; !ok {
+			n.Configs[typ] = config
+		}
+	}
+	return n, nil
+}
+
 func marshalConfigs(configs map[string]interface{}, serdes serde.Registry) (map[string][]byte, error) {
 	ret := map[string][]byte{}
 	for configType, iConfig := range configs {
@@ -609,6 +776,13 @@ func strPtrToWrapper(in *string) *wrappers.StringValue {
 	return &wrappers.StringValue{Value: *in}
 }
 
+func uint64PtrToWrapper(in *uint64) *wrappers.UInt64Value {
+	if in == nil {
+		return nil
+	}
+	return &wrappers.UInt64Value{Value: *in}
+}
+
 func tksToEntIDs(tks []storage2.TypeAndKey) []*storage.EntityID {
 	if funk.IsEmpty(tks) {
 		return nil
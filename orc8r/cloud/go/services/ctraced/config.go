@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ctraced
+
+import (
+	"time"
+)
+
+const (
+	defaultDefaultTimeout  = 5 * time.Minute
+	defaultMaxTimeout      = time.Hour
+	defaultStopGracePeriod = 2 * time.Minute
+	defaultMaxTraceSize    = 256 << 20
+	defaultRetention       = 7 * 24 * time.Hour
+	defaultSweepInterval   = time.Minute
+)
+
+// Config represents the configuration provided to the ctraced service
+type Config struct {
+	// DefaultTimeout is the time limit of call traces started without one.
+	DefaultTimeout time.Duration `yaml:"default_timeout"`
+	// MaxTimeout bounds the time limit of call traces.
+	MaxTimeout time.Duration `yaml:"max_timeout"`
+	// StopGracePeriod is how long past a call trace's time limit its
+	// gateways have to report the trace, before ctraced stops it on them.
+	StopGracePeriod time.Duration `yaml:"stop_grace_period"`
+
+	// MaxTraceSize bounds the size of each gateway's capture of a call
+	// trace, in bytes.
+	MaxTraceSize uint64 `yaml:"max_trace_size"`
+
+	// Retention is how long ended call traces are kept before they're
+	// deleted.
+	Retention time.Duration `yaml:"retention"`
+	// SweepInterval is the interval at which call traces are checked
+	// against their time limit and the retention.
+	SweepInterval time.Duration `yaml:"sweep_interval"`
+}
+
+// WithDefaults returns the config with unset fields set to their defaults.
+func (c Config) WithDefaults() Config {
+	if c.DefaultTimeout <= 0 {
+		c.DefaultTimeout = defaultDefaultTimeout
+	}
+	if c.MaxTimeout <= 0 {
+		c.MaxTimeout = defaultMaxTimeout
+	}
+	if c.StopGracePeriod <= 0 {
+		c.StopGracePeriod = defaultStopGracePeriod
+	}
+	if c.MaxTraceSize == 0 {
+		c.MaxTraceSize = defaultMaxTraceSize
+	}
+	if c.Retention <= 0 {
+		c.Retention = defaultRetention
+	}
+	if c.SweepInterval <= 0 {
+		c.SweepInterval = defaultSweepInterval
+	}
+	return c
+}
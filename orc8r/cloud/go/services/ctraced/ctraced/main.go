@@ -14,19 +14,24 @@ limitations under the License.
 package main
 
 import (
+	"context"
+
 	"magma/orc8r/cloud/go/blobstore"
+	"magma/orc8r/cloud/go/leader"
 	"magma/orc8r/cloud/go/obsidian"
 	"magma/orc8r/cloud/go/obsidian/swagger"
 	swagger_protos "magma/orc8r/cloud/go/obsidian/swagger/protos"
 	"magma/orc8r/cloud/go/orc8r"
 	"magma/orc8r/cloud/go/service"
 	"magma/orc8r/cloud/go/services/ctraced"
+	"magma/orc8r/cloud/go/services/ctraced/lifecycle"
 	"magma/orc8r/cloud/go/services/ctraced/obsidian/handlers"
 	"magma/orc8r/cloud/go/services/ctraced/servicers"
 	ctraced_storage "magma/orc8r/cloud/go/services/ctraced/storage"
 	"magma/orc8r/cloud/go/sqorc"
 	"magma/orc8r/cloud/go/storage"
 	"magma/orc8r/lib/go/protos"
+	"magma/orc8r/lib/go/service/config"
 
 	"github.com/golang/glog"
 )
@@ -37,6 +42,12 @@ func main() {
 	if err != nil {
 		glog.Fatalf("Error creating ctraced service: %+v", err)
 	}
+	var cfg ctraced.Config
+	_, _, err = config.GetStructuredServiceConfig(orc8r.ModuleName, ctraced.ServiceName, &cfg)
+	if err != nil {
+		glog.Fatalf("Error reading ctraced config: %+v", err)
+	}
+	cfg = cfg.WithDefaults()
 
 	// Init storage
 	db, err := sqorc.Open(storage.GetSQLDriver(), storage.GetDatabaseSource())
@@ -51,11 +62,21 @@ func main() {
 	ctracedBlobstore := ctraced_storage.NewCtracedBlobstore(fact)
 
 	// Init gRPC servicer
-	protos.RegisterCallTraceControllerServer(srv.GrpcServer, servicers.NewCallTraceServicer(ctracedBlobstore))
+	recorder := lifecycle.NewRecorder(ctracedBlobstore, cfg.MaxTraceSize)
+	protos.RegisterCallTraceControllerServer(srv.GrpcServer, servicers.NewCallTraceServicer(recorder))
 	swagger_protos.RegisterSwaggerSpecServer(srv.GrpcServer, swagger.NewSpecServicerFromFile(ctraced.ServiceName))
 
 	gwClient := handlers.NewGwCtracedClient()
-	obsidian.AttachHandlers(srv.EchoServer, handlers.GetObsidianHandlers(gwClient, ctracedBlobstore))
+	obsidian.AttachHandlers(srv.EchoServer, handlers.GetObsidianHandlers(gwClient, ctracedBlobstore, cfg))
+
+	// Stop call traces past their time limit and delete them past the
+	// retention. The lease outlasts the interval at which the leader renews it
+	elector := leader.NewSQLElector(db, sqorc.GetSqlBuilder(), lifecycle.LeaderElectionName, 2*cfg.SweepInterval)
+	err = elector.Initialize()
+	if err != nil {
+		glog.Fatalf("Error initializing call trace sweeper leader election: %+v", err)
+	}
+	go lifecycle.NewSweeper(gwClient, ctracedBlobstore, cfg, elector).Run(context.Background())
 
 	// Run service
 	err = srv.Run()
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decode
+
+import (
+	"github.com/pkg/errors"
+)
+
+var errTruncatedPER = errors.New("truncated PER encoding")
+
+// perReader reads the ASN.1 aligned PER encoding of S1AP messages, per
+// ITU-T X.691. Only the encodings used by S1AP are supported.
+type perReader struct {
+	data []byte
+	// pos is the position in bits
+	pos int
+}
+
+// readBits reads an n bit field, n <= 64.
+func (r *perReader) readBits(n int) (uint64, error) {
+	if r.pos+n > 8*len(r.data) {
+		return 0, errTruncatedPER
+	}
+	var v uint64
+	for i := 0; i < n; i++ {
+		bit := r.data[(r.pos+i)/8] >> (7 - uint((r.pos+i)%8)) & 1
+		v = v<<1 | uint64(bit)
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *perReader) readBool() (bool, error) {
+	v, err := r.readBits(1)
+	return v == 1, err
+}
+
+func (r *perReader) align() {
+	r.pos = (r.pos + 7) &^ 7
+}
+
+// readOctets reads n octet aligned octets.
+func (r *perReader) readOctets(n int) ([]byte, error) {
+	r.align()
+	start := r.pos / 8
+	if start+n > len(r.data) {
+		return nil, errTruncatedPER
+	}
+	r.pos += 8 * n
+	return r.data[start : start+n], nil
+}
+
+// readLength reads an unconstrained length determinant. Fragmented
+// encodings, used for values of 16K octets and more, aren't supported.
+func (r *perReader) readLength() (int, error) {
+	r.align()
+	first, err := r.readBits(8)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case first&0x80 == 0:
+		return int(first), nil
+	case first&0xc0 == 0x80:
+		second, err := r.readBits(8)
+		if err != nil {
+			return 0, err
+		}
+		return int(first&0x3f)<<8 | int(second), nil
+	}
+	return 0, errors.New("fragmented PER length determinants are not supported")
+}
+
+// readOpenType reads the octets of an open type, or an unconstrained octet
+// string.
+func (r *perReader) readOpenType() ([]byte, error) {
+	n, err := r.readLength()
+	if err != nil {
+		return nil, err
+	}
+	return r.readOctets(n)
+}
+
+// readAlignedInt reads a constrained whole number of a range of 256 to 64K
+// values, which is encoded in lengthOctets octet aligned octets.
+func (r *perReader) readAlignedInt(lengthOctets int) (uint64, error) {
+	b, err := r.readOctets(lengthOctets)
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, o := range b {
+		v = v<<8 | uint64(o)
+	}
+	return v, nil
+}
+
+// readLargeInt reads a constrained whole number of a range of more than 64K
+// values, which is prefixed with its length in octets in a field of
+// lengthBits bits.
+func (r *perReader) readLargeInt(lengthBits int) (uint64, error) {
+	n, err := r.readBits(lengthBits)
+	if err != nil {
+		return 0, err
+	}
+	return r.readAlignedInt(int(n) + 1)
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package decode decodes the control plane messages of call trace captures,
+// to present the call flow of a trace as a ladder of messages between
+// network nodes.
+//
+// The decoder covers the LTE control plane: S1AP and the NAS messages it
+// carries between eNodeB and MME, GTPv2-C between MME and S/PGW, Diameter
+// towards the HSS, PCRF and OCS, and the SCTP associations S1AP and Diameter
+// run on. Packets of other protocols, e.g. user plane traffic, are skipped.
+package decode
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+
+	"magma/orc8r/cloud/go/services/ctraced/pcap"
+)
+
+// Protocol is the protocol of a decoded message.
+type Protocol string
+
+const (
+	ProtocolS1AP     Protocol = "S1AP"
+	ProtocolNAS      Protocol = "NAS"
+	ProtocolGTPv2    Protocol = "GTPv2"
+	ProtocolDiameter Protocol = "DIAMETER"
+	ProtocolSCTP     Protocol = "SCTP"
+)
+
+// Message is a decoded control plane message.
+type Message struct {
+	Timestamp time.Time
+	// Source and Destination are the IP addresses of the nodes the message
+	// was sent from and to
+	Source      string
+	Destination string
+	Protocol    Protocol
+	// MessageType is the name of the message, e.g. "Attach request"
+	MessageType string
+	// Procedure is the name of the procedure the message is part of,
+	// e.g. "Attach"
+	Procedure string
+	// IEs are the values of the message's key information elements, keyed
+	// by snake case name, e.g. "imsi"
+	IEs map[string]string
+}
+
+const (
+	linkTypeNull      = 0
+	linkTypeEthernet  = 1
+	linkTypeRaw       = 101
+	linkTypeLinuxSLL  = 113
+	linkTypeIPv4      = 228
+	linkTypeIPv6      = 229
+	linkTypeLinuxSLL2 = 276
+
+	etherTypeIPv4   = 0x0800
+	etherTypeIPv6   = 0x86dd
+	etherTypeVLAN   = 0x8100
+	etherTypeQinQ   = 0x88a8
+	ipProtocolTCP   = 6
+	ipProtocolUDP   = 17
+	ipProtocolSCTP  = 132
+	ipv6HopByHop    = 0
+	ipv6Routing     = 43
+	ipv6Fragment    = 44
+	ipv6DestOptions = 60
+
+	// maxMessageSize bounds the size of messages reassembled from several
+	// packets, to drop streams whose reassembly went astray.
+	maxMessageSize = 1 << 20
+)
+
+// Decoder decodes the messages of the packets of a capture.
+// Decoders track SCTP and TCP streams to reassemble messages spanning
+// several packets, so all packets of a capture should be decoded in order
+// by the same decoder.
+type Decoder struct {
+	sctpFragments map[streamKey][]byte
+	tcpStreams    map[streamKey]*tcpStream
+}
+
+// streamKey identifies a unidirectional stream of messages between two
+// endpoints.
+type streamKey struct {
+	src, dst         string
+	srcPort, dstPort uint16
+	stream           uint16
+}
+
+// NewDecoder returns a decoder for a new capture.
+func NewDecoder() *Decoder {
+	return &Decoder{
+		sctpFragments: map[streamKey][]byte{},
+		tcpStreams:    map[streamKey]*tcpStream{},
+	}
+}
+
+// Decode returns the control plane messages of the packet, in the order
+// they appear in the packet.
+// Packets, and messages, which can't be decoded are skipped: call traces
+// capture all traffic of a gateway's interfaces, of which only the control
+// plane is of interest.
+func (d *Decoder) Decode(p *pcap.Packet) []*Message {
+	proto, payload, ok := decodeLink(p.Interface.LinkType, p.Data)
+	if !ok {
+		return nil
+	}
+	ip, ok := decodeIP(proto, payload)
+	if !ok {
+		return nil
+	}
+
+	var msgs []*Message
+	switch ip.protocol {
+	case ipProtocolSCTP:
+		msgs = d.decodeSCTP(ip)
+	case ipProtocolTCP:
+		msgs = d.decodeTCP(ip)
+	case ipProtocolUDP:
+		msgs = decodeUDP(ip)
+	}
+	for _, m := range msgs {
+		m.Timestamp = p.Timestamp
+		m.Source = ip.src
+		m.Destination = ip.dst
+	}
+	return msgs
+}
+
+// decodeLink returns the ether type and the payload of the link layer frame.
+func decodeLink(linkType uint16, frame []byte) (uint16, []byte, bool) {
+	switch linkType {
+	case linkTypeEthernet:
+		if len(frame) < 14 {
+			return 0, nil, false
+		}
+		etherType, payload := binary.BigEndian.Uint16(frame[12:14]), frame[14:]
+		for etherType == etherTypeVLAN || etherType == etherTypeQinQ {
+			if len(payload) < 4 {
+				return 0, nil, false
+			}
+			etherType, payload = binary.BigEndian.Uint16(payload[2:4]), payload[4:]
+		}
+		return etherType, payload, true
+	case linkTypeLinuxSLL:
+		if len(frame) < 16 {
+			return 0, nil, false
+		}
+		return binary.BigEndian.Uint16(frame[14:16]), frame[16:], true
+	case linkTypeLinuxSLL2:
+		if len(frame) < 20 {
+			return 0, nil, false
+		}
+		return binary.BigEndian.Uint16(frame[0:2]), frame[20:], true
+	case linkTypeNull:
+		if len(frame) < 4 {
+			return 0, nil, false
+		}
+		// The address family is in the host byte order of the capturing
+		// host, IPv6 has a different value on each BSD
+		switch binary.LittleEndian.Uint32(frame[0:4]) {
+		case 2:
+			return etherTypeIPv4, frame[4:], true
+		case 24, 28, 30:
+			return etherTypeIPv6, frame[4:], true
+		}
+		return 0, nil, false
+	case linkTypeRaw, linkTypeIPv4, linkTypeIPv6:
+		if len(frame) == 0 {
+			return 0, nil, false
+		}
+		switch frame[0] >> 4 {
+		case 4:
+			return etherTypeIPv4, frame, true
+		case 6:
+			return etherTypeIPv6, frame, true
+		}
+	}
+	return 0, nil, false
+}
+
+// ipPacket is the transport layer payload of an IP packet.
+type ipPacket struct {
+	src, dst string
+	protocol uint8
+	payload  []byte
+}
+
+// decodeIP decodes the IP packet. Fragmented packets aren't reassembled,
+// control plane messages fit in a single packet in practice.
+func decodeIP(etherType uint16, data []byte) (*ipPacket, bool) {
+	switch etherType {
+	case etherTypeIPv4:
+		if len(data) < 20 || data[0]>>4 != 4 {
+			return nil, false
+		}
+		headerLen := int(data[0]&0x0f) * 4
+		totalLen := int(binary.BigEndian.Uint16(data[2:4]))
+		// More fragments flag, or fragment offset
+		fragmented := binary.BigEndian.Uint16(data[6:8])&0x3fff != 0
+		if headerLen < 20 || totalLen < headerLen || totalLen > len(data) || fragmented {
+			return nil, false
+		}
+		return &ipPacket{
+			src:      net.IP(data[12:16]).String(),
+			dst:      net.IP(data[16:20]).String(),
+			protocol: data[9],
+			payload:  data[headerLen:totalLen],
+		}, true
+	case etherTypeIPv6:
+		if len(data) < 40 || data[0]>>4 != 6 {
+			return nil, false
+		}
+		payloadLen := int(binary.BigEndian.Uint16(data[4:6]))
+		if 40+payloadLen > len(data) {
+			return nil, false
+		}
+		ip := &ipPacket{
+			src:     net.IP(data[8:24]).String(),
+			dst:     net.IP(data[24:40]).String(),
+			payload: data[40 : 40+payloadLen],
+		}
+		next := data[6]
+		for next == ipv6HopByHop || next == ipv6Routing || next == ipv6DestOptions {
+			if len(ip.payload) < 8 {
+				return nil, false
+			}
+			extLen := (int(ip.payload[1]) + 1) * 8
+			if extLen > len(ip.payload) {
+				return nil, false
+			}
+			next, ip.payload = ip.payload[0], ip.payload[extLen:]
+		}
+		if next == ipv6Fragment {
+			return nil, false
+		}
+		ip.protocol = next
+		return ip, true
+	}
+	return nil, false
+}
+
+// decodeTBCD decodes the telephony binary coded decimal digits, e.g. of an
+// IMSI, per 3GPP TS 29.002.
+func decodeTBCD(data []byte) string {
+	digits := make([]byte, 0, 2*len(data))
+	for _, b := range data {
+		for _, digit := range []byte{b & 0x0f, b >> 4} {
+			if digit > 9 {
+				return string(digits)
+			}
+			digits = append(digits, '0'+digit)
+		}
+	}
+	return string(digits)
+}
+
+// decodeAPN decodes the access point name from its DNS label encoding.
+func decodeAPN(data []byte) string {
+	var apn []byte
+	for len(data) > 0 {
+		labelLen := int(data[0])
+		if labelLen+1 > len(data) {
+			break
+		}
+		if len(apn) > 0 {
+			apn = append(apn, '.')
+		}
+		apn = append(apn, data[1:labelLen+1]...)
+		data = data[labelLen+1:]
+	}
+	return string(apn)
+}
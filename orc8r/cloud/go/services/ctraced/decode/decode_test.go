@@ -0,0 +1,528 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decode_test
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/services/ctraced/decode"
+	"magma/orc8r/cloud/go/services/ctraced/pcap"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	enb = "10.0.2.10"
+	mme = "10.0.2.1"
+	sgw = "10.0.3.1"
+	hss = "10.0.4.1"
+
+	linkTypeEthernet = 1
+	linkTypeLinuxSLL = 113
+)
+
+var (
+	// IMSI 001010000000001 as EPS mobile identity
+	nasIMSI = []byte{0x09, 0x10, 0x10, 0x00, 0x00, 0x00, 0x00, 0x10}
+	// IMSI 001010000000001 in TBCD
+	tbcdIMSI = []byte{0x00, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0xf1}
+	apn      = append([]byte{8}, "internet"...)
+)
+
+func TestDecoder_S1AP(t *testing.T) {
+	pdnConnectivityRequest := []byte{0x02, 0x01, 0xd0, 0x11}
+	attachRequest := concat(
+		[]byte{0x07, 0x41, 0x71, byte(len(nasIMSI))}, nasIMSI,
+		// UE network capability
+		[]byte{0x02, 0xe0, 0xe0},
+		lve(pdnConnectivityRequest),
+	)
+	initialUEMessage := s1apPDU(0, 12,
+		s1apIE{8, s1apInt(3, 1)},
+		s1apIE{26, openType(attachRequest)},
+	)
+
+	activateDefaultBearerRequest := concat([]byte{0x52, 0x01, 0xc1, 0x01, 0x09, byte(len(apn))}, apn)
+	attachAccept := concat(
+		[]byte{0x07, 0x42, 0x01, 0x21},
+		// TAI list
+		[]byte{0x06, 0x00, 0x00, 0xf1, 0x10, 0x00, 0x01},
+		lve(activateDefaultBearerRequest),
+	)
+	// Integrity protected with MAC and sequence number
+	protectedAttachAccept := concat([]byte{0x17, 0x01, 0x02, 0x03, 0x04, 0x01}, attachAccept)
+	initialContextSetupRequest := s1apPDU(0, 9,
+		s1apIE{0, s1apInt(4, 7)},
+		s1apIE{8, s1apInt(3, 1)},
+		s1apIE{24, erabToBeSetupList(protectedAttachAccept)},
+	)
+	initialContextSetupResponse := s1apPDU(1, 9,
+		s1apIE{0, s1apInt(4, 7)},
+		s1apIE{8, s1apInt(3, 1)},
+	)
+	// Cause nas: detach
+	ueContextReleaseCommand := s1apPDU(0, 23, s1apIE{2, bits(0, 1, 2, 3, 0, 1, 2, 2)})
+	serviceRequest := []byte{0xc7, 0x01, 0x02, 0x03}
+	uplinkNASTransport := s1apPDU(0, 13, s1apIE{26, openType(serviceRequest)})
+
+	half := len(initialContextSetupRequest) / 2
+	packets := []*pcap.Packet{
+		packet(1, linkTypeEthernet, ipv4(enb, mme, 132, sctp(36412, 36412, sctpChunk(1, 0, make([]byte, 16))))),
+		packet(2, linkTypeEthernet, ipv4(enb, mme, 132, sctp(36412, 36412, sctpData(0x03, 1, 18, initialUEMessage)))),
+		// Fragmented across packets
+		packet(3, linkTypeEthernet, ipv4(mme, enb, 132, sctp(36412, 36412, sctpData(0x02, 1, 18, initialContextSetupRequest[:half])))),
+		packet(4, linkTypeEthernet, ipv4(mme, enb, 132, sctp(36412, 36412, sctpData(0x01, 1, 18, initialContextSetupRequest[half:])))),
+		// Several messages in one packet, along with a SACK
+		packet(5, linkTypeLinuxSLL, ipv4(enb, mme, 132, sctp(36412, 36412,
+			sctpChunk(3, 0, make([]byte, 12)),
+			sctpData(0x03, 1, 18, initialContextSetupResponse),
+			sctpData(0x03, 1, 18, uplinkNASTransport),
+		))),
+		packet(6, linkTypeLinuxSLL, ipv4(mme, enb, 132, sctp(36412, 36412, sctpData(0x03, 1, 18, ueContextReleaseCommand)))),
+		// Not S1AP
+		packet(7, linkTypeEthernet, ipv4(enb, mme, 132, sctp(36412, 36412, sctpData(0x03, 1, 60, []byte{0x01, 0x02})))),
+		packet(8, linkTypeEthernet, ipv4(enb, mme, 17, udp(2152, 2152, []byte{0x30, 0xff, 0x00, 0x00}))),
+	}
+
+	expected := []*decode.Message{
+		{
+			Timestamp: time.Unix(1, 0), Source: enb, Destination: mme,
+			Protocol: decode.ProtocolSCTP, MessageType: "INIT", Procedure: "Association",
+			IEs: map[string]string{},
+		},
+		{
+			Timestamp: time.Unix(2, 0), Source: enb, Destination: mme,
+			Protocol: decode.ProtocolS1AP, MessageType: "InitialUEMessage", Procedure: "InitialUEMessage",
+			IEs: map[string]string{"enb_ue_s1ap_id": "1"},
+		},
+		{
+			Timestamp: time.Unix(2, 0), Source: enb, Destination: mme,
+			Protocol: decode.ProtocolNAS, MessageType: "Attach request", Procedure: "Attach",
+			IEs: map[string]string{"imsi": "001010000000001"},
+		},
+		{
+			Timestamp: time.Unix(2, 0), Source: enb, Destination: mme,
+			Protocol: decode.ProtocolNAS, MessageType: "PDN connectivity request", Procedure: "PDN Connectivity",
+			IEs: map[string]string{"pti": "1"},
+		},
+		{
+			Timestamp: time.Unix(4, 0), Source: mme, Destination: enb,
+			Protocol: decode.ProtocolS1AP, MessageType: "InitialContextSetupRequest", Procedure: "InitialContextSetup",
+			IEs: map[string]string{"mme_ue_s1ap_id": "7", "enb_ue_s1ap_id": "1"},
+		},
+		{
+			Timestamp: time.Unix(4, 0), Source: mme, Destination: enb,
+			Protocol: decode.ProtocolNAS, MessageType: "Attach accept", Procedure: "Attach",
+			IEs: map[string]string{},
+		},
+		{
+			Timestamp: time.Unix(4, 0), Source: mme, Destination: enb,
+			Protocol: decode.ProtocolNAS, MessageType: "Activate default EPS bearer context request", Procedure: "Default EPS Bearer Context Activation",
+			IEs: map[string]string{"pti": "1", "eps_bearer_id": "5", "apn": "internet"},
+		},
+		{
+			Timestamp: time.Unix(5, 0), Source: enb, Destination: mme,
+			Protocol: decode.ProtocolS1AP, MessageType: "InitialContextSetupResponse", Procedure: "InitialContextSetup",
+			IEs: map[string]string{"mme_ue_s1ap_id": "7", "enb_ue_s1ap_id": "1"},
+		},
+		{
+			Timestamp: time.Unix(5, 0), Source: enb, Destination: mme,
+			Protocol: decode.ProtocolS1AP, MessageType: "UplinkNASTransport", Procedure: "UplinkNASTransport",
+			IEs: map[string]string{},
+		},
+		{
+			Timestamp: time.Unix(5, 0), Source: enb, Destination: mme,
+			Protocol: decode.ProtocolNAS, MessageType: "Service request", Procedure: "Service Request",
+			IEs: map[string]string{},
+		},
+		{
+			Timestamp: time.Unix(6, 0), Source: mme, Destination: enb,
+			Protocol: decode.ProtocolS1AP, MessageType: "UEContextReleaseCommand", Procedure: "UEContextRelease",
+			IEs: map[string]string{"cause": "nas: detach"},
+		},
+	}
+	assert.Equal(t, expected, decodeAll(packets))
+}
+
+func TestDecoder_GTPv2(t *testing.T) {
+	createSessionRequest := gtpv2(32, 0, 1,
+		gtpv2IE(1, tbcdIMSI),
+		gtpv2IE(71, apn),
+	)
+	createSessionResponse := gtpv2(33, 0x1234, 1,
+		gtpv2IE(2, []byte{16, 0}),
+		gtpv2IE(73, []byte{5}),
+	)
+	packets := []*pcap.Packet{
+		packet(1, linkTypeEthernet, ipv4(mme, sgw, 17, udp(2123, 2123, createSessionRequest))),
+		packet(2, linkTypeEthernet, ipv4(sgw, mme, 17, udp(2123, 2123, createSessionResponse))),
+		// GTPv1 isn't decoded
+		packet(3, linkTypeEthernet, ipv4(sgw, mme, 17, udp(2123, 2123, []byte{0x32, 0x01, 0x00, 0x04, 0, 0, 0, 0, 0, 0, 0, 0}))),
+		// Nor are messages with lengths shorter than their headers
+		packet(4, linkTypeEthernet, ipv4(sgw, mme, 17, udp(2123, 2123, []byte{0x48, 0x20, 0x00, 0x00, 0, 0, 0, 0, 0, 0, 0, 0}))),
+		packet(5, linkTypeEthernet, ipv4(sgw, mme, 17, udp(2123, 2123, []byte{0x48, 0x20, 0x00, 0x04, 0, 0, 0, 0, 0, 0, 0, 0}))),
+		packet(6, linkTypeEthernet, ipv4(sgw, mme, 17, udp(2123, 2123, []byte{0x40, 0x20, 0x00, 0x00, 0, 0, 0, 0}))),
+		// Nor truncated messages
+		packet(7, linkTypeEthernet, ipv4(sgw, mme, 17, udp(2123, 2123, createSessionRequest[:len(createSessionRequest)-1]))),
+	}
+
+	expected := []*decode.Message{
+		{
+			Timestamp: time.Unix(1, 0), Source: mme, Destination: sgw,
+			Protocol: decode.ProtocolGTPv2, MessageType: "Create Session Request", Procedure: "Create Session",
+			IEs: map[string]string{"teid": "0x00000000", "sequence": "1", "imsi": "001010000000001", "apn": "internet"},
+		},
+		{
+			Timestamp: time.Unix(2, 0), Source: sgw, Destination: mme,
+			Protocol: decode.ProtocolGTPv2, MessageType: "Create Session Response", Procedure: "Create Session",
+			IEs: map[string]string{"teid": "0x00001234", "sequence": "1", "cause": "16", "eps_bearer_id": "5"},
+		},
+	}
+	assert.Equal(t, expected, decodeAll(packets))
+}
+
+func TestDecoder_Diameter(t *testing.T) {
+	updateLocationRequest := diameter(0xc0, 316, 16777251, 1,
+		avp(263, "mme;1"),
+		avp(264, "mme.magma"),
+		avp(1, "001010000000001"),
+	)
+	updateLocationAnswer := diameter(0x40, 316, 16777251, 1,
+		avp(263, "mme;1"),
+		avp(264, "hss.magma"),
+		avpUint32(268, 2001),
+	)
+	creditControlRequest := diameter(0xc0, 272, 16777238, 2,
+		avp(263, "pcef;1"),
+		avpUint32(416, 1),
+		avpGrouped(443, avp(444, "001010000000001")),
+	)
+	half := len(updateLocationRequest) / 2
+
+	packets := []*pcap.Packet{
+		packet(1, linkTypeEthernet, ipv4(mme, hss, 6, tcp(40000, 3868, 99, 0x02, nil))),
+		// Split across segments, and retransmitted
+		packet(2, linkTypeEthernet, ipv4(mme, hss, 6, tcp(40000, 3868, 100, 0x18, updateLocationRequest[:half]))),
+		packet(3, linkTypeEthernet, ipv4(mme, hss, 6, tcp(40000, 3868, 100+uint32(half), 0x18, updateLocationRequest[half:]))),
+		packet(4, linkTypeEthernet, ipv4(mme, hss, 6, tcp(40000, 3868, 100+uint32(half), 0x18, updateLocationRequest[half:]))),
+		// Stream picked up mid-capture
+		packet(5, linkTypeEthernet, ipv4(hss, mme, 6, tcp(3868, 40000, 5000, 0x18, updateLocationAnswer))),
+		// Diameter over SCTP
+		packet(6, linkTypeEthernet, ipv4(mme, hss, 132, sctp(3868, 3868, sctpData(0x03, 0, 46, creditControlRequest)))),
+	}
+
+	expected := []*decode.Message{
+		{
+			Timestamp: time.Unix(3, 0), Source: mme, Destination: hss,
+			Protocol: decode.ProtocolDiameter, MessageType: "Update-Location-Request", Procedure: "Update-Location",
+			IEs: map[string]string{
+				"application": "S6a", "hop_by_hop_id": "0x00000001",
+				"session_id": "mme;1", "origin_host": "mme.magma", "user_name": "001010000000001",
+			},
+		},
+		{
+			Timestamp: time.Unix(5, 0), Source: hss, Destination: mme,
+			Protocol: decode.ProtocolDiameter, MessageType: "Update-Location-Answer", Procedure: "Update-Location",
+			IEs: map[string]string{
+				"application": "S6a", "hop_by_hop_id": "0x00000001",
+				"session_id": "mme;1", "origin_host": "hss.magma", "result_code": "2001",
+			},
+		},
+		{
+			Timestamp: time.Unix(6, 0), Source: mme, Destination: hss,
+			Protocol: decode.ProtocolDiameter, MessageType: "Credit-Control-Request", Procedure: "Credit-Control",
+			IEs: map[string]string{
+				"application": "Gx", "hop_by_hop_id": "0x00000002",
+				"session_id": "pcef;1", "cc_request_type": "INITIAL_REQUEST", "subscription_id": "001010000000001",
+			},
+		},
+	}
+	assert.Equal(t, expected, decodeAll(packets))
+}
+
+func TestFilter_Match(t *testing.T) {
+	attachRequest := &decode.Message{Protocol: decode.ProtocolNAS, Procedure: "Attach"}
+	smc := &decode.Message{Protocol: decode.ProtocolNAS, Procedure: "Security Mode Control"}
+	csr := &decode.Message{Protocol: decode.ProtocolGTPv2, Procedure: "Create Session"}
+
+	assert.True(t, decode.Filter{}.Match(attachRequest))
+
+	f := decode.Filter{Protocols: []string{"nas"}}
+	assert.True(t, f.Match(attachRequest))
+	assert.True(t, f.Match(smc))
+	assert.False(t, f.Match(csr))
+
+	f = decode.Filter{Procedures: []string{"security_mode_control", "create-session"}}
+	assert.False(t, f.Match(attachRequest))
+	assert.True(t, f.Match(smc))
+	assert.True(t, f.Match(csr))
+
+	f = decode.Filter{Protocols: []string{"GTPv2"}, Procedures: []string{"attach"}}
+	assert.False(t, f.Match(attachRequest))
+	assert.False(t, f.Match(csr))
+}
+
+func decodeAll(packets []*pcap.Packet) []*decode.Message {
+	d := decode.NewDecoder()
+	var msgs []*decode.Message
+	for _, p := range packets {
+		msgs = append(msgs, d.Decode(p)...)
+	}
+	return msgs
+}
+
+func packet(sec int64, linkType uint16, ipPacket []byte) *pcap.Packet {
+	var frame []byte
+	switch linkType {
+	case linkTypeEthernet:
+		frame = concat(make([]byte, 12), []byte{0x08, 0x00}, ipPacket)
+	case linkTypeLinuxSLL:
+		frame = concat(make([]byte, 14), []byte{0x08, 0x00}, ipPacket)
+	}
+	return &pcap.Packet{
+		Interface:      &pcap.Interface{LinkType: linkType, SnapLen: 65535},
+		Timestamp:      time.Unix(sec, 0),
+		OriginalLength: uint32(len(frame)),
+		Data:           frame,
+	}
+}
+
+func ipv4(src, dst string, protocol uint8, payload []byte) []byte {
+	hdr := make([]byte, 20)
+	hdr[0] = 0x45
+	binary.BigEndian.PutUint16(hdr[2:4], uint16(20+len(payload)))
+	hdr[8] = 64
+	hdr[9] = protocol
+	copy(hdr[12:16], net.ParseIP(src).To4())
+	copy(hdr[16:20], net.ParseIP(dst).To4())
+	return concat(hdr, payload)
+}
+
+func udp(srcPort, dstPort uint16, payload []byte) []byte {
+	hdr := make([]byte, 8)
+	binary.BigEndian.PutUint16(hdr[0:2], srcPort)
+	binary.BigEndian.PutUint16(hdr[2:4], dstPort)
+	binary.BigEndian.PutUint16(hdr[4:6], uint16(8+len(payload)))
+	return concat(hdr, payload)
+}
+
+func tcp(srcPort, dstPort uint16, seq uint32, flags uint8, payload []byte) []byte {
+	hdr := make([]byte, 20)
+	binary.BigEndian.PutUint16(hdr[0:2], srcPort)
+	binary.BigEndian.PutUint16(hdr[2:4], dstPort)
+	binary.BigEndian.PutUint32(hdr[4:8], seq)
+	hdr[12] = 5 << 4
+	hdr[13] = flags
+	return concat(hdr, payload)
+}
+
+func sctp(srcPort, dstPort uint16, chunks ...[]byte) []byte {
+	hdr := make([]byte, 12)
+	binary.BigEndian.PutUint16(hdr[0:2], srcPort)
+	binary.BigEndian.PutUint16(hdr[2:4], dstPort)
+	return concat(append([][]byte{hdr}, chunks...)...)
+}
+
+func sctpChunk(chunkType, flags uint8, value []byte) []byte {
+	hdr := []byte{chunkType, flags, 0, 0}
+	binary.BigEndian.PutUint16(hdr[2:4], uint16(4+len(value)))
+	return concat(hdr, value, make([]byte, (4-len(value)%4)%4))
+}
+
+func sctpData(flags uint8, stream uint16, ppid uint32, data []byte) []byte {
+	hdr := make([]byte, 12)
+	binary.BigEndian.PutUint16(hdr[4:6], stream)
+	binary.BigEndian.PutUint32(hdr[8:12], ppid)
+	return sctpChunk(0, flags, concat(hdr, data))
+}
+
+func gtpv2(msgType uint8, teid uint32, seq uint32, ies ...[]byte) []byte {
+	body := concat(ies...)
+	hdr := make([]byte, 12)
+	hdr[0] = 0x48
+	hdr[1] = msgType
+	binary.BigEndian.PutUint16(hdr[2:4], uint16(8+len(body)))
+	binary.BigEndian.PutUint32(hdr[4:8], teid)
+	binary.BigEndian.PutUint32(hdr[8:12], seq<<8)
+	return concat(hdr, body)
+}
+
+func gtpv2IE(ieType uint8, value []byte) []byte {
+	hdr := []byte{ieType, 0, 0, 0}
+	binary.BigEndian.PutUint16(hdr[1:3], uint16(len(value)))
+	return concat(hdr, value)
+}
+
+func diameter(flags uint8, command uint32, app uint32, hopByHop uint32, avps ...[]byte) []byte {
+	body := concat(avps...)
+	hdr := make([]byte, 20)
+	binary.BigEndian.PutUint32(hdr[0:4], uint32(20+len(body)))
+	hdr[0] = 1
+	binary.BigEndian.PutUint32(hdr[4:8], command)
+	hdr[4] = flags
+	binary.BigEndian.PutUint32(hdr[8:12], app)
+	binary.BigEndian.PutUint32(hdr[12:16], hopByHop)
+	return concat(hdr, body)
+}
+
+func avp(code uint32, value string) []byte {
+	return avpBytes(code, []byte(value))
+}
+
+func avpUint32(code uint32, value uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, value)
+	return avpBytes(code, b)
+}
+
+func avpGrouped(code uint32, avps ...[]byte) []byte {
+	return avpBytes(code, concat(avps...))
+}
+
+func avpBytes(code uint32, value []byte) []byte {
+	hdr := make([]byte, 8)
+	binary.BigEndian.PutUint32(hdr[0:4], code)
+	binary.BigEndian.PutUint32(hdr[4:8], uint32(8+len(value)))
+	hdr[4] = 0x40
+	return concat(hdr, value, make([]byte, (4-len(value)%4)%4))
+}
+
+func lve(value []byte) []byte {
+	return concat([]byte{byte(len(value) >> 8), byte(len(value))}, value)
+}
+
+type s1apIE struct {
+	id    uint16
+	value []byte
+}
+
+// s1apPDU returns the aligned PER encoding of the S1AP PDU.
+func s1apPDU(pduType uint8, procedureCode uint8, ies ...s1apIE) []byte {
+	value := &bitWriter{}
+	value.writeBits(0, 1)
+	value.writeOctets([]byte{0, byte(len(ies))})
+	for _, ie := range ies {
+		value.writeOctets([]byte{byte(ie.id >> 8), byte(ie.id)})
+		value.writeBits(0, 2)
+		value.writeOpenType(ie.value)
+	}
+
+	pdu := &bitWriter{}
+	pdu.writeBits(0, 1)
+	pdu.writeBits(uint64(pduType), 2)
+	pdu.writeOctets([]byte{procedureCode})
+	pdu.writeBits(0, 2)
+	pdu.writeOpenType(value.data)
+	return pdu.data
+}
+
+// s1apInt returns the encoding of v as a constrained whole number of
+// octets octets.
+func s1apInt(octets int, v uint64) []byte {
+	w := &bitWriter{}
+	w.writeBits(uint64(octets-1), 2)
+	b := make([]byte, octets)
+	for i := octets - 1; i >= 0; i-- {
+		b[i], v = byte(v), v>>8
+	}
+	w.writeOctets(b)
+	return w.data
+}
+
+// erabToBeSetupList returns an E-RABToBeSetupListCtxtSUReq with a single
+// E-RAB, carrying the NAS PDU.
+func erabToBeSetupList(nasPDU []byte) []byte {
+	item := &bitWriter{}
+	// Extension marker, NAS-PDU present, no IE extensions, E-RAB-ID 5
+	item.writeBits(0, 1)
+	item.writeBits(0x2, 2)
+	item.writeBits(0, 1)
+	item.writeBits(5, 4)
+	// QoS parameters without GBR information, QCI 9, and ARP
+	item.writeBits(0, 3)
+	item.writeOctets([]byte{9})
+	item.writeBits(0, 2)
+	item.writeBits(15, 4)
+	item.writeBits(0, 2)
+	// IPv4 transport layer address and TEID
+	item.writeBits(0, 1)
+	item.writeBits(31, 8)
+	item.writeOctets(net.ParseIP(sgw).To4())
+	item.writeOctets([]byte{0, 0, 0, 1})
+	item.writeOpenType(nasPDU)
+
+	list := &bitWriter{}
+	list.writeOctets([]byte{0})
+	list.writeOctets([]byte{0, 52})
+	list.writeBits(0, 2)
+	list.writeOpenType(item.data)
+	return list.data
+}
+
+func openType(value []byte) []byte {
+	w := &bitWriter{}
+	w.writeOpenType(value)
+	return w.data
+}
+
+// bits returns the bit fields, given as pairs of value and width.
+func bits(fields ...uint64) []byte {
+	w := &bitWriter{}
+	for i := 0; i < len(fields); i += 2 {
+		w.writeBits(fields[i], int(fields[i+1]))
+	}
+	return w.data
+}
+
+// bitWriter writes aligned PER encodings.
+type bitWriter struct {
+	data []byte
+	pos  int
+}
+
+func (w *bitWriter) writeBits(v uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if w.pos%8 == 0 {
+			w.data = append(w.data, 0)
+		}
+		w.data[len(w.data)-1] |= byte(v>>uint(i)&1) << (7 - uint(w.pos%8))
+		w.pos++
+	}
+}
+
+func (w *bitWriter) writeOctets(b []byte) {
+	w.pos = len(w.data) * 8
+	w.data = append(w.data, b...)
+	w.pos += 8 * len(b)
+}
+
+func (w *bitWriter) writeOpenType(value []byte) {
+	if len(value) < 128 {
+		w.writeOctets([]byte{byte(len(value))})
+	} else {
+		w.writeOctets([]byte{0x80 | byte(len(value)>>8), byte(len(value))})
+	}
+	w.writeOctets(value)
+}
+
+func concat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
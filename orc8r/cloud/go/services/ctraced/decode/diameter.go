@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decode
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	diameterHeaderLen   = 20
+	diameterFlagRequest = 0x80
+	avpFlagVendor       = 0x80
+
+	avpUserName               = 1
+	avpSessionID              = 263
+	avpOriginHost             = 264
+	avpResultCode             = 268
+	avpDestinationHost        = 293
+	avpExperimentalResult     = 297
+	avpExperimentalResultCode = 298
+	avpCCRequestType          = 416
+	avpSubscriptionID         = 443
+	avpSubscriptionIDData     = 444
+)
+
+// diameterCommands are the names of the Diameter commands of the base
+// protocol and the applications used by the LTE core.
+var diameterCommands = map[uint32]string{
+	257: "Capabilities-Exchange",
+	258: "Re-Auth",
+	272: "Credit-Control",
+	274: "Abort-Session",
+	275: "Session-Termination",
+	280: "Device-Watchdog",
+	282: "Disconnect-Peer",
+	265: "AA",
+	268: "Diameter-EAP",
+	301: "Server-Assignment",
+	303: "Multimedia-Auth",
+	304: "Registration-Termination",
+	316: "Update-Location",
+	317: "Cancel-Location",
+	318: "Authentication-Information",
+	319: "Insert-Subscriber-Data",
+	320: "Delete-Subscriber-Data",
+	321: "Purge-UE",
+	322: "Reset",
+	323: "Notify",
+}
+
+// diameterApplications are the names of the interfaces of the Diameter
+// applications used by the LTE core.
+var diameterApplications = map[uint32]string{
+	0:        "Base",
+	4:        "Gy",
+	16777236: "Rx",
+	16777238: "Gx",
+	16777251: "S6a",
+	16777264: "SWx",
+	16777265: "SWm",
+	16777272: "S6b",
+}
+
+var ccRequestTypes = map[uint32]string{
+	1: "INITIAL_REQUEST",
+	2: "UPDATE_REQUEST",
+	3: "TERMINATION_REQUEST",
+	4: "EVENT_REQUEST",
+}
+
+// diameterMessageLength returns the length of the Diameter message starting
+// at the beginning of data, if data looks like the start of a message.
+func diameterMessageLength(data []byte) (int, bool) {
+	if len(data) < diameterHeaderLen || data[0] != 1 {
+		return 0, false
+	}
+	msgLen := int(uint24(data[1:4]))
+	if msgLen < diameterHeaderLen || msgLen > maxMessageSize {
+		return 0, false
+	}
+	return msgLen, true
+}
+
+func decodeDiameter(data []byte) (*Message, error) {
+	msgLen, ok := diameterMessageLength(data)
+	if !ok || msgLen > len(data) {
+		return nil, errors.New("invalid Diameter header")
+	}
+	flags := data[4]
+	commandCode := uint24(data[5:8])
+	appID := binary.BigEndian.Uint32(data[8:12])
+
+	command, ok := diameterCommands[commandCode]
+	if !ok {
+		command = fmt.Sprintf("Command %d", commandCode)
+	}
+	msgType := command + "-Answer"
+	if flags&diameterFlagRequest != 0 {
+		msgType = command + "-Request"
+	}
+	app, ok := diameterApplications[appID]
+	if !ok {
+		app = strconv.FormatUint(uint64(appID), 10)
+	}
+
+	ies := map[string]string{
+		"application":   app,
+		"hop_by_hop_id": fmt.Sprintf("0x%08x", binary.BigEndian.Uint32(data[12:16])),
+	}
+	err := walkAVPs(data[diameterHeaderLen:msgLen], func(code uint32, value []byte) error {
+		switch code {
+		case avpUserName:
+			ies["user_name"] = string(value)
+		case avpSessionID:
+			ies["session_id"] = string(value)
+		case avpOriginHost:
+			ies["origin_host"] = string(value)
+		case avpDestinationHost:
+			ies["destination_host"] = string(value)
+		case avpResultCode:
+			if len(value) == 4 {
+				ies["result_code"] = strconv.FormatUint(uint64(binary.BigEndian.Uint32(value)), 10)
+			}
+		case avpCCRequestType:
+			if len(value) != 4 {
+				break
+			}
+			if t, ok := ccRequestTypes[binary.BigEndian.Uint32(value)]; ok {
+				ies["cc_request_type"] = t
+			}
+		case avpExperimentalResult:
+			return walkAVPs(value, func(code uint32, value []byte) error {
+				if code == avpExperimentalResultCode && len(value) == 4 {
+					ies["experimental_result_code"] = strconv.FormatUint(uint64(binary.BigEndian.Uint32(value)), 10)
+				}
+				return nil
+			})
+		case avpSubscriptionID:
+			return walkAVPs(value, func(code uint32, value []byte) error {
+				if code == avpSubscriptionIDData {
+					ies["subscription_id"] = string(value)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		Protocol:    ProtocolDiameter,
+		MessageType: msgType,
+		Procedure:   command,
+		IEs:         ies,
+	}, nil
+}
+
+// walkAVPs calls f with the code and value of each AVP in data.
+func walkAVPs(data []byte, f func(code uint32, value []byte) error) error {
+	for len(data) > 0 {
+		if len(data) < 8 {
+			return errors.New("truncated AVP header")
+		}
+		code := binary.BigEndian.Uint32(data[0:4])
+		avpLen := int(uint24(data[5:8]))
+		headerLen := 8
+		if data[4]&avpFlagVendor != 0 {
+			headerLen = 12
+		}
+		if avpLen < headerLen || avpLen > len(data) {
+			return errors.Errorf("invalid length %d of AVP %d", avpLen, code)
+		}
+		if err := f(code, data[headerLen:avpLen]); err != nil {
+			return err
+		}
+		if pad(avpLen) >= len(data) {
+			return nil
+		}
+		data = data[pad(avpLen):]
+	}
+	return nil
+}
+
+func uint24(b []byte) uint32 {
+	return uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decode
+
+import (
+	"strings"
+)
+
+// Filter selects messages by protocol and procedure.
+// Names are matched ignoring case, spaces, hyphens and underscores, e.g.
+// "security_mode_control" matches the "Security Mode Control" procedure.
+type Filter struct {
+	// Protocols the messages are of. Empty matches all protocols.
+	Protocols []string
+	// Procedures the messages are part of. Empty matches all procedures.
+	Procedures []string
+}
+
+// Match returns true if the message passes the filter.
+func (f Filter) Match(m *Message) bool {
+	return matchName(f.Protocols, string(m.Protocol)) && matchName(f.Procedures, m.Procedure)
+}
+
+func matchName(names []string, name string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	name = normalizeName(name)
+	for _, n := range names {
+		if normalizeName(n) == name {
+			return true
+		}
+	}
+	return false
+}
+
+var nameReplacer = strings.NewReplacer(" ", "", "-", "", "_", "")
+
+func normalizeName(name string) string {
+	return strings.ToLower(nameReplacer.Replace(name))
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decode
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	gtpv2FlagTEID = 0x08
+
+	gtpv2IEIMSI  = 1
+	gtpv2IECause = 2
+	gtpv2IEAPN   = 71
+	gtpv2IEEBI   = 73
+)
+
+type gtpv2MessageType struct {
+	name      string
+	procedure string
+}
+
+// gtpv2MessageTypes are the names of the GTPv2-C messages exchanged on the
+// S11 and S5/S8 interfaces, per 3GPP TS 29.274.
+var gtpv2MessageTypes = map[uint8]gtpv2MessageType{
+	1:   {"Echo Request", "Echo"},
+	2:   {"Echo Response", "Echo"},
+	3:   {"Version Not Supported Indication", "Version Not Supported"},
+	32:  {"Create Session Request", "Create Session"},
+	33:  {"Create Session Response", "Create Session"},
+	34:  {"Modify Bearer Request", "Modify Bearer"},
+	35:  {"Modify Bearer Response", "Modify Bearer"},
+	36:  {"Delete Session Request", "Delete Session"},
+	37:  {"Delete Session Response", "Delete Session"},
+	64:  {"Modify Bearer Command", "Modify Bearer Command"},
+	65:  {"Modify Bearer Failure Indication", "Modify Bearer Command"},
+	66:  {"Delete Bearer Command", "Delete Bearer Command"},
+	67:  {"Delete Bearer Failure Indication", "Delete Bearer Command"},
+	68:  {"Bearer Resource Command", "Bearer Resource Command"},
+	69:  {"Bearer Resource Failure Indication", "Bearer Resource Command"},
+	95:  {"Create Bearer Request", "Create Bearer"},
+	96:  {"Create Bearer Response", "Create Bearer"},
+	97:  {"Update Bearer Request", "Update Bearer"},
+	98:  {"Update Bearer Response", "Update Bearer"},
+	99:  {"Delete Bearer Request", "Delete Bearer"},
+	100: {"Delete Bearer Response", "Delete Bearer"},
+	170: {"Release Access Bearers Request", "Release Access Bearers"},
+	171: {"Release Access Bearers Response", "Release Access Bearers"},
+	176: {"Downlink Data Notification", "Downlink Data Notification"},
+	177: {"Downlink Data Notification Acknowledge", "Downlink Data Notification"},
+	179: {"PGW Restart Notification", "PGW Restart Notification"},
+	180: {"PGW Restart Notification Acknowledge", "PGW Restart Notification"},
+}
+
+func decodeGTPv2(data []byte) (*Message, error) {
+	if len(data) < 8 || data[0]>>5 != 2 {
+		return nil, errors.New("not a GTPv2 message")
+	}
+	msgLen := int(binary.BigEndian.Uint16(data[2:4])) + 4
+	if msgLen > len(data) {
+		return nil, errors.New("truncated GTPv2 message")
+	}
+
+	ieOffset := 8
+	if data[0]&gtpv2FlagTEID != 0 {
+		ieOffset = 12
+	}
+	if msgLen < ieOffset {
+		return nil, errors.Errorf("invalid GTPv2 message length %d", msgLen-4)
+	}
+
+	ies := map[string]string{}
+	var seq []byte
+	if data[0]&gtpv2FlagTEID != 0 {
+		ies["teid"] = fmt.Sprintf("0x%08x", binary.BigEndian.Uint32(data[4:8]))
+		seq, ieOffset = data[8:11], 12
+	} else {
+		seq = data[4:7]
+	}
+	ies["sequence"] = strconv.FormatUint(uint64(uint24(seq)), 10)
+
+	for body := data[ieOffset:msgLen]; len(body) > 0; {
+		if len(body) < 4 {
+			return nil, errors.New("truncated GTPv2 IE header")
+		}
+		ieType, instance := body[0], body[3]&0x0f
+		ieLen := int(binary.BigEndian.Uint16(body[1:3]))
+		if 4+ieLen > len(body) {
+			return nil, errors.Errorf("invalid length %d of GTPv2 IE %d", ieLen, ieType)
+		}
+		value := body[4 : 4+ieLen]
+		body = body[4+ieLen:]
+		if instance != 0 || len(value) == 0 {
+			continue
+		}
+		switch ieType {
+		case gtpv2IEIMSI:
+			ies["imsi"] = decodeTBCD(value)
+		case gtpv2IECause:
+			ies["cause"] = strconv.Itoa(int(value[0]))
+		case gtpv2IEAPN:
+			ies["apn"] = decodeAPN(value)
+		case gtpv2IEEBI:
+			ies["eps_bearer_id"] = strconv.Itoa(int(value[0] & 0x0f))
+		}
+	}
+
+	msgType, ok := gtpv2MessageTypes[data[1]]
+	if !ok {
+		msgType.name = fmt.Sprintf("Message type %d", data[1])
+	}
+	return &Message{
+		Protocol:    ProtocolGTPv2,
+		MessageType: msgType.name,
+		Procedure:   msgType.procedure,
+		IEs:         ies,
+	}, nil
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decode
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	nasProtocolEMM = 0x7
+	nasProtocolESM = 0x2
+
+	nasSecurityPlain                 = 0
+	nasSecurityIntegrityProtected    = 1
+	nasSecurityCiphered              = 2
+	nasSecurityIntegrityProtectedNew = 3
+	nasSecurityCipheredNew           = 4
+	nasSecurityServiceRequest        = 12
+	// nasSecurityHeaderLen is the length of the security protected message
+	// header: security header type, MAC and sequence number
+	nasSecurityHeaderLen = 6
+
+	nasIdentityTypeIMSI = 1
+	nasIdentityTypeGUTI = 6
+
+	emmAttachRequest                = 0x41
+	emmAttachAccept                 = 0x42
+	emmAttachComplete               = 0x43
+	emmIdentityResponse             = 0x56
+	esmActivateDefaultBearerRequest = 0xc1
+)
+
+type nasMessageType struct {
+	name      string
+	procedure string
+}
+
+// emmMessageTypes are the names of the EPS mobility management messages,
+// per 3GPP TS 24.301.
+var emmMessageTypes = map[uint8]nasMessageType{
+	0x41: {"Attach request", "Attach"},
+	0x42: {"Attach accept", "Attach"},
+	0x43: {"Attach complete", "Attach"},
+	0x44: {"Attach reject", "Attach"},
+	0x45: {"Detach request", "Detach"},
+	0x46: {"Detach accept", "Detach"},
+	0x48: {"Tracking area update request", "Tracking Area Update"},
+	0x49: {"Tracking area update accept", "Tracking Area Update"},
+	0x4a: {"Tracking area update complete", "Tracking Area Update"},
+	0x4b: {"Tracking area update reject", "Tracking Area Update"},
+	0x4c: {"Extended service request", "Service Request"},
+	0x4e: {"Service reject", "Service Request"},
+	0x50: {"GUTI reallocation command", "GUTI Reallocation"},
+	0x51: {"GUTI reallocation complete", "GUTI Reallocation"},
+	0x52: {"Authentication request", "Authentication"},
+	0x53: {"Authentication response", "Authentication"},
+	0x54: {"Authentication reject", "Authentication"},
+	0x5c: {"Authentication failure", "Authentication"},
+	0x55: {"Identity request", "Identification"},
+	0x56: {"Identity response", "Identification"},
+	0x5d: {"Security mode command", "Security Mode Control"},
+	0x5e: {"Security mode complete", "Security Mode Control"},
+	0x5f: {"Security mode reject", "Security Mode Control"},
+	0x60: {"EMM status", "EMM Status"},
+	0x61: {"EMM information", "EMM Information"},
+	0x62: {"Downlink NAS transport", "NAS Transport"},
+	0x63: {"Uplink NAS transport", "NAS Transport"},
+	0x64: {"CS service notification", "CS Service Notification"},
+}
+
+// emmCauseMessageTypes are the EMM messages starting with an EMM cause.
+var emmCauseMessageTypes = map[uint8]bool{
+	0x44: true, 0x4b: true, 0x4e: true, 0x5c: true, 0x5f: true, 0x60: true,
+}
+
+// esmMessageTypes are the names of the EPS session management messages,
+// per 3GPP TS 24.301.
+var esmMessageTypes = map[uint8]nasMessageType{
+	0xc1: {"Activate default EPS bearer context request", "Default EPS Bearer Context Activation"},
+	0xc2: {"Activate default EPS bearer context accept", "Default EPS Bearer Context Activation"},
+	0xc3: {"Activate default EPS bearer context reject", "Default EPS Bearer Context Activation"},
+	0xc5: {"Activate dedicated EPS bearer context request", "Dedicated EPS Bearer Context Activation"},
+	0xc6: {"Activate dedicated EPS bearer context accept", "Dedicated EPS Bearer Context Activation"},
+	0xc7: {"Activate dedicated EPS bearer context reject", "Dedicated EPS Bearer Context Activation"},
+	0xc9: {"Modify EPS bearer context request", "EPS Bearer Context Modification"},
+	0xca: {"Modify EPS bearer context accept", "EPS Bearer Context Modification"},
+	0xcb: {"Modify EPS bearer context reject", "EPS Bearer Context Modification"},
+	0xcd: {"Deactivate EPS bearer context request", "EPS Bearer Context Deactivation"},
+	0xce: {"Deactivate EPS bearer context accept", "EPS Bearer Context Deactivation"},
+	0xd0: {"PDN connectivity request", "PDN Connectivity"},
+	0xd1: {"PDN connectivity reject", "PDN Connectivity"},
+	0xd2: {"PDN disconnect request", "PDN Disconnect"},
+	0xd3: {"PDN disconnect reject", "PDN Disconnect"},
+	0xd4: {"Bearer resource allocation request", "Bearer Resource Allocation"},
+	0xd5: {"Bearer resource allocation reject", "Bearer Resource Allocation"},
+	0xd6: {"Bearer resource modification request", "Bearer Resource Modification"},
+	0xd7: {"Bearer resource modification reject", "Bearer Resource Modification"},
+	0xd9: {"ESM information request", "ESM Information"},
+	0xda: {"ESM information response", "ESM Information"},
+	0xe8: {"ESM status", "ESM Status"},
+}
+
+// esmCauseMessageTypes are the ESM messages starting with an ESM cause.
+var esmCauseMessageTypes = map[uint8]bool{
+	0xc3: true, 0xc7: true, 0xcb: true, 0xd1: true, 0xd3: true, 0xd5: true, 0xd7: true, 0xe8: true,
+}
+
+// decodeNAS decodes the NAS message, followed by the ESM message it carries
+// in its ESM message container, if any.
+// Ciphered messages can't be decoded, they're reported as such.
+func decodeNAS(pdu []byte) ([]*Message, error) {
+	if len(pdu) < 2 {
+		return nil, errors.New("truncated NAS message")
+	}
+	securityHeader, protocol := pdu[0]>>4, pdu[0]&0x0f
+	if protocol == nasProtocolESM {
+		return decodeESM(pdu)
+	}
+	if protocol != nasProtocolEMM {
+		return nil, errors.Errorf("unsupported NAS protocol discriminator %d", protocol)
+	}
+
+	switch securityHeader {
+	case nasSecurityPlain:
+		return decodeEMM(pdu)
+	case nasSecurityIntegrityProtected, nasSecurityIntegrityProtectedNew:
+		if len(pdu) <= nasSecurityHeaderLen {
+			return nil, errors.New("truncated security protected NAS message")
+		}
+		return decodeNAS(pdu[nasSecurityHeaderLen:])
+	case nasSecurityCiphered, nasSecurityCipheredNew:
+		return []*Message{{
+			Protocol:    ProtocolNAS,
+			MessageType: "Ciphered message",
+			IEs:         map[string]string{},
+		}}, nil
+	case nasSecurityServiceRequest:
+		return []*Message{{
+			Protocol:    ProtocolNAS,
+			MessageType: "Service request",
+			Procedure:   "Service Request",
+			IEs:         map[string]string{},
+		}}, nil
+	}
+	return nil, errors.Errorf("unsupported NAS security header type %d", securityHeader)
+}
+
+func decodeEMM(pdu []byte) ([]*Message, error) {
+	msgType := pdu[1]
+	name, ok := emmMessageTypes[msgType]
+	if !ok {
+		name.name = fmt.Sprintf("EMM message type 0x%02x", msgType)
+	}
+	msg := &Message{
+		Protocol:    ProtocolNAS,
+		MessageType: name.name,
+		Procedure:   name.procedure,
+		IEs:         map[string]string{},
+	}
+	if emmCauseMessageTypes[msgType] && len(pdu) > 2 {
+		msg.IEs["emm_cause"] = strconv.Itoa(int(pdu[2]))
+	}
+
+	// Only the leading mandatory IEs are decoded, optional IEs follow them
+	// in a message specific format
+	var esmContainer []byte
+	ies := &nasReader{data: pdu}
+	switch msgType {
+	case emmAttachRequest:
+		// Header, attach type and NAS key set identifier
+		ies.skip(3)
+		decodeMobileIdentity(ies.readLV(), msg.IEs)
+		// UE network capability
+		ies.readLV()
+		esmContainer = ies.readLVE()
+	case emmAttachAccept:
+		// Header, attach result and T3412 value
+		ies.skip(4)
+		// TAI list
+		ies.readLV()
+		esmContainer = ies.readLVE()
+	case emmAttachComplete:
+		// Header
+		ies.skip(2)
+		esmContainer = ies.readLVE()
+	case emmIdentityResponse:
+		// Header
+		ies.skip(2)
+		decodeMobileIdentity(ies.readLV(), msg.IEs)
+	}
+
+	msgs := []*Message{msg}
+	if len(esmContainer) > 0 {
+		esm, err := decodeESM(esmContainer)
+		if err != nil {
+			return nil, errors.Wrap(err, "decode ESM message container")
+		}
+		msgs = append(msgs, esm...)
+	}
+	return msgs, nil
+}
+
+func decodeESM(pdu []byte) ([]*Message, error) {
+	if len(pdu) < 3 {
+		return nil, errors.New("truncated ESM message")
+	}
+	msgType := pdu[2]
+	name, ok := esmMessageTypes[msgType]
+	if !ok {
+		name.name = fmt.Sprintf("ESM message type 0x%02x", msgType)
+	}
+	msg := &Message{
+		Protocol:    ProtocolNAS,
+		MessageType: name.name,
+		Procedure:   name.procedure,
+		IEs:         map[string]string{"pti": strconv.Itoa(int(pdu[1]))},
+	}
+	if ebi := pdu[0] >> 4; ebi != 0 {
+		msg.IEs["eps_bearer_id"] = strconv.Itoa(int(ebi))
+	}
+	if esmCauseMessageTypes[msgType] && len(pdu) > 3 {
+		msg.IEs["esm_cause"] = strconv.Itoa(int(pdu[3]))
+	}
+	if msgType == esmActivateDefaultBearerRequest {
+		ies := &nasReader{data: pdu}
+		// Header
+		ies.skip(3)
+		// EPS QoS
+		ies.readLV()
+		if apn := ies.readLV(); len(apn) > 0 {
+			msg.IEs["apn"] = decodeAPN(apn)
+		}
+	}
+	return []*Message{msg}, nil
+}
+
+// decodeMobileIdentity adds the IMSI, or the M-TMSI of the GUTI, of the EPS
+// mobile identity to ies.
+func decodeMobileIdentity(identity []byte, ies map[string]string) {
+	if len(identity) == 0 {
+		return
+	}
+	switch identity[0] & 0x07 {
+	case nasIdentityTypeIMSI:
+		// The first digit shares its octet with the identity type
+		if digit := identity[0] >> 4; digit <= 9 {
+			ies["imsi"] = string([]byte{'0' + digit}) + decodeTBCD(identity[1:])
+		}
+	case nasIdentityTypeGUTI:
+		if len(identity) == 11 {
+			ies["m_tmsi"] = fmt.Sprintf("0x%08x", binary.BigEndian.Uint32(identity[7:11]))
+		}
+	}
+}
+
+// nasReader reads the IEs of a NAS message. Reading past the end of the
+// message yields empty IEs.
+type nasReader struct {
+	data []byte
+}
+
+func (r *nasReader) skip(n int) {
+	if n > len(r.data) {
+		n = len(r.data)
+	}
+	r.data = r.data[n:]
+}
+
+// readLV reads an IE of type LV, i.e. with a one octet length.
+func (r *nasReader) readLV() []byte {
+	if len(r.data) < 1 {
+		return nil
+	}
+	n := int(r.data[0])
+	if 1+n > len(r.data) {
+		r.data = nil
+		return nil
+	}
+	value := r.data[1 : 1+n]
+	r.data = r.data[1+n:]
+	return value
+}
+
+// readLVE reads an IE of type LV-E, i.e. with a two octet length.
+func (r *nasReader) readLVE() []byte {
+	if len(r.data) < 2 {
+		return nil
+	}
+	n := int(binary.BigEndian.Uint16(r.data[0:2]))
+	if 2+n > len(r.data) {
+		r.data = nil
+		return nil
+	}
+	value := r.data[2 : 2+n]
+	r.data = r.data[2+n:]
+	return value
+}
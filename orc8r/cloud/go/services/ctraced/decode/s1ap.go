@@ -0,0 +1,510 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decode
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	s1apInitiatingMessage   = 0
+	s1apSuccessfulOutcome   = 1
+	s1apUnsuccessfulOutcome = 2
+
+	s1apIDMMEUES1APID                  = 0
+	s1apIDCause                        = 2
+	s1apIDENBUES1APID                  = 8
+	s1apIDERABToBeSetupListBearerSUReq = 16
+	s1apIDERABToBeSetupListCtxtSUReq   = 24
+	s1apIDNASPDU                       = 26
+	s1apIDUEPagingID                   = 43
+
+	s1apPagingIDIMSI = 1
+)
+
+// s1apProcedure is an S1AP elementary procedure, with the names of its
+// initiating, successful outcome and unsuccessful outcome messages.
+type s1apProcedure struct {
+	name     string
+	messages [3]string
+}
+
+// class2 returns an elementary procedure without response.
+func class2(name string) s1apProcedure {
+	return s1apProcedure{name: name, messages: [3]string{name}}
+}
+
+// s1apProcedures are the S1AP elementary procedures by procedure code,
+// per 3GPP TS 36.413.
+var s1apProcedures = map[uint64]s1apProcedure{
+	0:  {"HandoverPreparation", [3]string{"HandoverRequired", "HandoverCommand", "HandoverPreparationFailure"}},
+	1:  {"HandoverResourceAllocation", [3]string{"HandoverRequest", "HandoverRequestAcknowledge", "HandoverFailure"}},
+	2:  class2("HandoverNotification"),
+	3:  {"PathSwitchRequest", [3]string{"PathSwitchRequest", "PathSwitchRequestAcknowledge", "PathSwitchRequestFailure"}},
+	4:  {"HandoverCancel", [3]string{"HandoverCancel", "HandoverCancelAcknowledge"}},
+	5:  {"E-RABSetup", [3]string{"E-RABSetupRequest", "E-RABSetupResponse"}},
+	6:  {"E-RABModify", [3]string{"E-RABModifyRequest", "E-RABModifyResponse"}},
+	7:  {"E-RABRelease", [3]string{"E-RABReleaseCommand", "E-RABReleaseResponse"}},
+	8:  class2("E-RABReleaseIndication"),
+	9:  {"InitialContextSetup", [3]string{"InitialContextSetupRequest", "InitialContextSetupResponse", "InitialContextSetupFailure"}},
+	10: class2("Paging"),
+	11: class2("DownlinkNASTransport"),
+	12: class2("InitialUEMessage"),
+	13: class2("UplinkNASTransport"),
+	14: {"Reset", [3]string{"Reset", "ResetAcknowledge"}},
+	15: class2("ErrorIndication"),
+	16: class2("NASNonDeliveryIndication"),
+	17: {"S1Setup", [3]string{"S1SetupRequest", "S1SetupResponse", "S1SetupFailure"}},
+	18: class2("UEContextReleaseRequest"),
+	19: class2("DownlinkS1cdma2000tunnelling"),
+	20: class2("UplinkS1cdma2000tunnelling"),
+	21: {"UEContextModification", [3]string{"UEContextModificationRequest", "UEContextModificationResponse", "UEContextModificationFailure"}},
+	22: class2("UECapabilityInfoIndication"),
+	23: {"UEContextRelease", [3]string{"UEContextReleaseCommand", "UEContextReleaseComplete"}},
+	24: class2("ENBStatusTransfer"),
+	25: class2("MMEStatusTransfer"),
+	26: class2("DeactivateTrace"),
+	27: class2("TraceStart"),
+	28: class2("TraceFailureIndication"),
+	29: {"ENBConfigurationUpdate", [3]string{"ENBConfigurationUpdate", "ENBConfigurationUpdateAcknowledge", "ENBConfigurationUpdateFailure"}},
+	30: {"MMEConfigurationUpdate", [3]string{"MMEConfigurationUpdate", "MMEConfigurationUpdateAcknowledge", "MMEConfigurationUpdateFailure"}},
+	31: class2("LocationReportingControl"),
+	32: class2("LocationReportingFailureIndication"),
+	33: class2("LocationReport"),
+	34: class2("OverloadStart"),
+	35: class2("OverloadStop"),
+	36: {"WriteReplaceWarning", [3]string{"WriteReplaceWarningRequest", "WriteReplaceWarningResponse"}},
+	37: class2("ENBDirectInformationTransfer"),
+	38: class2("MMEDirectInformationTransfer"),
+	39: class2("PrivateMessage"),
+	40: class2("ENBConfigurationTransfer"),
+	41: class2("MMEConfigurationTransfer"),
+	42: class2("CellTrafficTrace"),
+	43: {"Kill", [3]string{"KillRequest", "KillResponse"}},
+	44: class2("DownlinkUEAssociatedLPPaTransport"),
+	45: class2("UplinkUEAssociatedLPPaTransport"),
+	46: class2("DownlinkNonUEAssociatedLPPaTransport"),
+	47: class2("UplinkNonUEAssociatedLPPaTransport"),
+	48: {"UERadioCapabilityMatch", [3]string{"UERadioCapabilityMatchRequest", "UERadioCapabilityMatchResponse"}},
+	49: class2("PWSRestartIndication"),
+}
+
+// s1apCauses are the root values of the S1AP cause groups.
+var s1apCauses = []struct {
+	group  string
+	values []string
+}{
+	{"radioNetwork", []string{
+		"unspecified", "tx2relocoverall-expiry", "successful-handover",
+		"release-due-to-eutran-generated-reason", "handover-cancelled", "partial-handover",
+		"ho-failure-in-target-EPC-eNB-or-target-system", "ho-target-not-allowed",
+		"tS1relocoverall-expiry", "tS1relocprep-expiry", "cell-not-available", "unknown-targetID",
+		"no-radio-resources-available-in-target-cell", "unknown-mme-ue-s1ap-id",
+		"unknown-enb-ue-s1ap-id", "unknown-pair-ue-s1ap-id", "handover-desirable-for-radio-reason",
+		"time-critical-handover", "resource-optimisation-handover", "reduce-load-in-serving-cell",
+		"user-inactivity", "radio-connection-with-ue-lost", "load-balancing-tau-required",
+		"cs-fallback-triggered", "ue-not-available-for-ps-service", "radio-resources-not-available",
+		"failure-in-radio-interface-procedure", "invalid-qos-combination", "interrat-redirection",
+		"interaction-with-other-procedure", "unknown-E-RAB-ID", "multiple-E-RAB-ID-instances",
+		"encryption-and-or-integrity-protection-algorithms-not-supported",
+		"s1-intra-system-handover-triggered", "s1-inter-system-handover-triggered",
+		"x2-handover-triggered",
+	}},
+	{"transport", []string{"transport-resource-unavailable", "unspecified"}},
+	{"nas", []string{"normal-release", "authentication-failure", "detach", "unspecified"}},
+	{"protocol", []string{
+		"transfer-syntax-error", "abstract-syntax-error-reject",
+		"abstract-syntax-error-ignore-and-notify", "message-not-compatible-with-receiver-state",
+		"semantic-error", "abstract-syntax-error-falsely-constructed-message", "unspecified",
+	}},
+	{"misc", []string{
+		"control-processing-overload", "not-enough-user-plane-processing-resources",
+		"hardware-failure", "om-intervention", "unspecified", "unknown-PLMN",
+	}},
+}
+
+// decodeS1AP decodes the S1AP message, followed by the NAS messages it
+// carries, if any.
+func decodeS1AP(pdu []byte) ([]*Message, error) {
+	r := &perReader{data: pdu}
+	extended, err := r.readBool()
+	if err != nil {
+		return nil, err
+	}
+	if extended {
+		return nil, errors.New("unsupported S1AP PDU type")
+	}
+	pduType, err := r.readBits(2)
+	if err != nil {
+		return nil, err
+	}
+	if pduType > s1apUnsuccessfulOutcome {
+		return nil, errors.Errorf("invalid S1AP PDU type %d", pduType)
+	}
+	procedureCode, err := r.readAlignedInt(1)
+	if err != nil {
+		return nil, err
+	}
+	// Criticality
+	if _, err := r.readBits(2); err != nil {
+		return nil, err
+	}
+	value, err := r.readOpenType()
+	if err != nil {
+		return nil, err
+	}
+
+	procedure, ok := s1apProcedures[procedureCode]
+	if !ok {
+		procedure.name = fmt.Sprintf("Procedure %d", procedureCode)
+	}
+	msgType := procedure.messages[pduType]
+	if msgType == "" {
+		msgType = procedure.name
+	}
+	msg := &Message{
+		Protocol:    ProtocolS1AP,
+		MessageType: msgType,
+		Procedure:   procedure.name,
+		IEs:         map[string]string{},
+	}
+	nasPDUs, err := decodeS1APIEs(value, msg.IEs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decode %s", msgType)
+	}
+
+	msgs := []*Message{msg}
+	for _, pdu := range nasPDUs {
+		nas, err := decodeNAS(pdu)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decode NAS PDU of %s", msgType)
+		}
+		msgs = append(msgs, nas...)
+	}
+	return msgs, nil
+}
+
+// decodeS1APIEs adds the key IEs of the S1AP message's protocol IE
+// container to ies, and returns the NAS PDUs the message carries.
+func decodeS1APIEs(value []byte, ies map[string]string) ([][]byte, error) {
+	r := &perReader{data: value}
+	// Extension marker
+	if _, err := r.readBits(1); err != nil {
+		return nil, err
+	}
+	count, err := r.readAlignedInt(2)
+	if err != nil {
+		return nil, err
+	}
+
+	var nasPDUs [][]byte
+	for i := uint64(0); i < count; i++ {
+		id, err := r.readAlignedInt(2)
+		if err != nil {
+			return nil, err
+		}
+		// Criticality
+		if _, err := r.readBits(2); err != nil {
+			return nil, err
+		}
+		ieValue, err := r.readOpenType()
+		if err != nil {
+			return nil, err
+		}
+
+		ie := &perReader{data: ieValue}
+		switch id {
+		case s1apIDMMEUES1APID:
+			v, err := ie.readLargeInt(2)
+			if err != nil {
+				return nil, errors.Wrap(err, "decode MME-UE-S1AP-ID")
+			}
+			ies["mme_ue_s1ap_id"] = strconv.FormatUint(v, 10)
+		case s1apIDENBUES1APID:
+			v, err := ie.readLargeInt(2)
+			if err != nil {
+				return nil, errors.Wrap(err, "decode eNB-UE-S1AP-ID")
+			}
+			ies["enb_ue_s1ap_id"] = strconv.FormatUint(v, 10)
+		case s1apIDCause:
+			cause, err := decodeS1APCause(ie)
+			if err != nil {
+				return nil, errors.Wrap(err, "decode Cause")
+			}
+			ies["cause"] = cause
+		case s1apIDUEPagingID:
+			imsi, err := decodeS1APPagingIMSI(ie)
+			if err != nil {
+				return nil, errors.Wrap(err, "decode UEPagingID")
+			}
+			if imsi != "" {
+				ies["imsi"] = imsi
+			}
+		case s1apIDNASPDU:
+			pdu, err := ie.readOpenType()
+			if err != nil {
+				return nil, errors.Wrap(err, "decode NAS-PDU")
+			}
+			nasPDUs = append(nasPDUs, pdu)
+		case s1apIDERABToBeSetupListCtxtSUReq, s1apIDERABToBeSetupListBearerSUReq:
+			pdus, err := decodeERABToBeSetupList(ie, id == s1apIDERABToBeSetupListCtxtSUReq)
+			if err != nil {
+				return nil, errors.Wrap(err, "decode E-RABToBeSetupList")
+			}
+			nasPDUs = append(nasPDUs, pdus...)
+		}
+	}
+	return nasPDUs, nil
+}
+
+func decodeS1APCause(r *perReader) (string, error) {
+	// Extension marker of the choice
+	extended, err := r.readBool()
+	if err != nil {
+		return "", err
+	}
+	if extended {
+		return "extension", nil
+	}
+	group, err := r.readBits(3)
+	if err != nil {
+		return "", err
+	}
+	if group >= uint64(len(s1apCauses)) {
+		return "", errors.Errorf("invalid cause group %d", group)
+	}
+	cause := s1apCauses[group]
+
+	// Extension marker of the enumeration
+	extended, err = r.readBool()
+	if err != nil {
+		return "", err
+	}
+	if extended {
+		return cause.group + ": extension", nil
+	}
+	value, err := r.readBits(bitsFor(len(cause.values)))
+	if err != nil {
+		return "", err
+	}
+	if value >= uint64(len(cause.values)) {
+		return "", errors.Errorf("invalid %s cause %d", cause.group, value)
+	}
+	return cause.group + ": " + cause.values[value], nil
+}
+
+// decodeS1APPagingIMSI returns the IMSI of the UE paging identity, if the
+// UE is paged by IMSI rather than S-TMSI.
+func decodeS1APPagingIMSI(r *perReader) (string, error) {
+	// Extension marker of the choice
+	extended, err := r.readBool()
+	if err != nil {
+		return "", err
+	}
+	identityType, err := r.readBits(1)
+	if err != nil {
+		return "", err
+	}
+	if extended || identityType != s1apPagingIDIMSI {
+		return "", nil
+	}
+	// IMSI: OCTET STRING (SIZE (3..8))
+	n, err := r.readBits(3)
+	if err != nil {
+		return "", err
+	}
+	imsi, err := r.readOctets(int(n) + 3)
+	if err != nil {
+		return "", err
+	}
+	return decodeTBCD(imsi), nil
+}
+
+// decodeERABToBeSetupList returns the NAS PDUs of the E-RABs to set up of
+// an InitialContextSetupRequest or an E-RABSetupRequest, i.e. the Attach
+// accept and the bearer activation requests.
+func decodeERABToBeSetupList(r *perReader, ctxt bool) ([][]byte, error) {
+	// SEQUENCE (SIZE(1..maxnoofE-RABs))
+	n, err := r.readAlignedInt(1)
+	if err != nil {
+		return nil, err
+	}
+
+	var nasPDUs [][]byte
+	for i := uint64(0); i <= n; i++ {
+		// Protocol IE ID and criticality
+		if _, err := r.readAlignedInt(2); err != nil {
+			return nil, err
+		}
+		if _, err := r.readBits(2); err != nil {
+			return nil, err
+		}
+		item, err := r.readOpenType()
+		if err != nil {
+			return nil, err
+		}
+		pdu, err := decodeERABToBeSetupItem(&perReader{data: item}, ctxt)
+		if err != nil {
+			return nil, err
+		}
+		if pdu != nil {
+			nasPDUs = append(nasPDUs, pdu)
+		}
+	}
+	return nasPDUs, nil
+}
+
+// decodeERABToBeSetupItem returns the NAS PDU of the E-RAB to set up, if
+// any. The NAS PDU is optional in InitialContextSetupRequests, but
+// mandatory in E-RABSetupRequests.
+func decodeERABToBeSetupItem(r *perReader, ctxt bool) ([]byte, error) {
+	// Extension marker, and presence bitmap of the optional NAS-PDU, if
+	// ctxt, and IE extensions
+	optional := 1
+	if ctxt {
+		optional = 2
+	}
+	if _, err := r.readBits(1); err != nil {
+		return nil, err
+	}
+	present, err := r.readBits(optional)
+	if err != nil {
+		return nil, err
+	}
+	hasNASPDU := !ctxt || present&0x2 != 0
+
+	// E-RAB-ID: INTEGER (0..15, ...)
+	if _, err := r.readBits(5); err != nil {
+		return nil, err
+	}
+	if err := skipERABLevelQoSParameters(r); err != nil {
+		return nil, err
+	}
+	// TransportLayerAddress: BIT STRING (SIZE(1..160, ...))
+	extended, err := r.readBool()
+	if err != nil {
+		return nil, err
+	}
+	if extended {
+		return nil, errors.New("unsupported transport layer address size")
+	}
+	bits, err := r.readBits(8)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.readOctets((int(bits) + 1 + 7) / 8); err != nil {
+		return nil, err
+	}
+	// GTP-TEID: OCTET STRING (SIZE(4))
+	if _, err := r.readOctets(4); err != nil {
+		return nil, err
+	}
+	if !hasNASPDU {
+		return nil, nil
+	}
+	return r.readOpenType()
+}
+
+func skipERABLevelQoSParameters(r *perReader) error {
+	// Extension marker, and presence bitmap of the optional GBR QoS
+	// information and IE extensions
+	if _, err := r.readBits(1); err != nil {
+		return err
+	}
+	present, err := r.readBits(2)
+	if err != nil {
+		return err
+	}
+	// QCI: INTEGER (0..255)
+	if _, err := r.readAlignedInt(1); err != nil {
+		return err
+	}
+	// AllocationAndRetentionPriority: extension marker, presence of IE
+	// extensions, priority level, pre-emption capability and vulnerability
+	if _, err := r.readBits(1); err != nil {
+		return err
+	}
+	arpExtensions, err := r.readBool()
+	if err != nil {
+		return err
+	}
+	if _, err := r.readBits(4 + 1 + 1); err != nil {
+		return err
+	}
+	if arpExtensions {
+		if err := skipProtocolExtensions(r); err != nil {
+			return err
+		}
+	}
+	if present&0x2 != 0 {
+		// GBR-QosInformation: extension marker, presence of IE extensions,
+		// and 4 bit rates of INTEGER (0..10000000000)
+		if _, err := r.readBits(1); err != nil {
+			return err
+		}
+		gbrExtensions, err := r.readBool()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 4; i++ {
+			if _, err := r.readLargeInt(3); err != nil {
+				return err
+			}
+		}
+		if gbrExtensions {
+			if err := skipProtocolExtensions(r); err != nil {
+				return err
+			}
+		}
+	}
+	if present&0x1 != 0 {
+		return skipProtocolExtensions(r)
+	}
+	return nil
+}
+
+// skipProtocolExtensions skips a ProtocolExtensionContainer.
+func skipProtocolExtensions(r *perReader) error {
+	// SEQUENCE (SIZE (1..maxProtocolExtensions))
+	n, err := r.readAlignedInt(2)
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i <= n; i++ {
+		// ID, criticality and extension value
+		if _, err := r.readAlignedInt(2); err != nil {
+			return err
+		}
+		if _, err := r.readBits(2); err != nil {
+			return err
+		}
+		if _, err := r.readOpenType(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bitsFor returns the number of bits of a constrained whole number of n
+// values.
+func bitsFor(n int) int {
+	bits := 0
+	for (1 << uint(bits)) < n {
+		bits++
+	}
+	return bits
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decode
+
+import (
+	"encoding/binary"
+)
+
+const (
+	portGTPC     = 2123
+	portDiameter = 3868
+	portS1AP     = 36412
+
+	sctpChunkData             = 0
+	sctpChunkInit             = 1
+	sctpChunkInitAck          = 2
+	sctpChunkAbort            = 6
+	sctpChunkShutdown         = 7
+	sctpChunkShutdownAck      = 8
+	sctpChunkError            = 9
+	sctpChunkCookieEcho       = 10
+	sctpChunkCookieAck        = 11
+	sctpChunkShutdownComplete = 14
+
+	sctpFlagBeginning = 0x02
+	sctpFlagEnding    = 0x01
+
+	sctpPPIDS1AP         = 18
+	sctpPPIDDiameter     = 46
+	sctpPPIDDiameterDTLS = 47
+
+	tcpFlagFIN = 0x01
+	tcpFlagSYN = 0x02
+	tcpFlagRST = 0x04
+)
+
+// sctpControlChunks are the names of the SCTP control chunks reported as
+// messages. Chunks recurring throughout an association, i.e. SACKs and
+// heartbeats, are left out of the ladder.
+var sctpControlChunks = map[uint8]string{
+	sctpChunkInit:             "INIT",
+	sctpChunkInitAck:          "INIT_ACK",
+	sctpChunkAbort:            "ABORT",
+	sctpChunkShutdown:         "SHUTDOWN",
+	sctpChunkShutdownAck:      "SHUTDOWN_ACK",
+	sctpChunkError:            "ERROR",
+	sctpChunkCookieEcho:       "COOKIE_ECHO",
+	sctpChunkCookieAck:        "COOKIE_ACK",
+	sctpChunkShutdownComplete: "SHUTDOWN_COMPLETE",
+}
+
+// decodeSCTP decodes the association control chunks and the S1AP and
+// Diameter messages of the SCTP packet. Messages fragmented across DATA
+// chunks are reassembled.
+func (d *Decoder) decodeSCTP(ip *ipPacket) []*Message {
+	data := ip.payload
+	if len(data) < 12 {
+		return nil
+	}
+	srcPort, dstPort := binary.BigEndian.Uint16(data[0:2]), binary.BigEndian.Uint16(data[2:4])
+
+	var msgs []*Message
+	for chunks := data[12:]; len(chunks) >= 4; {
+		chunkType, flags := chunks[0], chunks[1]
+		chunkLen := int(binary.BigEndian.Uint16(chunks[2:4]))
+		if chunkLen < 4 || chunkLen > len(chunks) {
+			break
+		}
+		value := chunks[4:chunkLen]
+		if pad(chunkLen) >= len(chunks) {
+			chunks = nil
+		} else {
+			chunks = chunks[pad(chunkLen):]
+		}
+
+		if name, ok := sctpControlChunks[chunkType]; ok {
+			msgs = append(msgs, &Message{
+				Protocol:    ProtocolSCTP,
+				MessageType: name,
+				Procedure:   "Association",
+				IEs:         map[string]string{},
+			})
+			continue
+		}
+		if chunkType != sctpChunkData || len(value) < 12 {
+			continue
+		}
+
+		key := streamKey{
+			src: ip.src, dst: ip.dst,
+			srcPort: srcPort, dstPort: dstPort,
+			stream: binary.BigEndian.Uint16(value[4:6]),
+		}
+		ppid := binary.BigEndian.Uint32(value[8:12])
+		payload, complete := d.reassembleSCTP(key, flags, value[12:])
+		if !complete {
+			continue
+		}
+		switch {
+		case ppid == sctpPPIDS1AP || (ppid == 0 && (srcPort == portS1AP || dstPort == portS1AP)):
+			if s1ap, err := decodeS1AP(payload); err == nil {
+				msgs = append(msgs, s1ap...)
+			}
+		case ppid == sctpPPIDDiameter || ppid == sctpPPIDDiameterDTLS || (ppid == 0 && (srcPort == portDiameter || dstPort == portDiameter)):
+			if m, err := decodeDiameter(payload); err == nil {
+				msgs = append(msgs, m)
+			}
+		}
+	}
+	return msgs
+}
+
+// reassembleSCTP returns the user message of the DATA chunk's stream, once
+// its last fragment has been received.
+// Fragments are expected in order, SCTP retransmissions are rare enough on
+// the links of a gateway for this not to matter.
+func (d *Decoder) reassembleSCTP(key streamKey, flags uint8, fragment []byte) ([]byte, bool) {
+	beginning, ending := flags&sctpFlagBeginning != 0, flags&sctpFlagEnding != 0
+	if beginning && ending {
+		delete(d.sctpFragments, key)
+		return fragment, true
+	}
+
+	buf, ok := d.sctpFragments[key]
+	switch {
+	case beginning:
+		buf = append([]byte{}, fragment...)
+	case ok:
+		buf = append(buf, fragment...)
+	default:
+		// The beginning of the message wasn't captured
+		return nil, false
+	}
+	if ending || len(buf) > maxMessageSize {
+		delete(d.sctpFragments, key)
+		return buf, ending
+	}
+	d.sctpFragments[key] = buf
+	return nil, false
+}
+
+// tcpStream is the unparsed data of a TCP stream carrying Diameter.
+type tcpStream struct {
+	nextSeq uint32
+	buf     []byte
+}
+
+// decodeTCP decodes the Diameter messages of the TCP segment. Diameter
+// messages are reassembled from the segments of their TCP stream.
+func (d *Decoder) decodeTCP(ip *ipPacket) []*Message {
+	data := ip.payload
+	if len(data) < 20 {
+		return nil
+	}
+	srcPort, dstPort := binary.BigEndian.Uint16(data[0:2]), binary.BigEndian.Uint16(data[2:4])
+	if srcPort != portDiameter && dstPort != portDiameter {
+		return nil
+	}
+	seq := binary.BigEndian.Uint32(data[4:8])
+	headerLen := int(data[12]>>4) * 4
+	flags := data[13]
+	if headerLen < 20 || headerLen > len(data) {
+		return nil
+	}
+	payload := data[headerLen:]
+
+	key := streamKey{src: ip.src, dst: ip.dst, srcPort: srcPort, dstPort: dstPort}
+	if flags&(tcpFlagFIN|tcpFlagRST) != 0 {
+		defer delete(d.tcpStreams, key)
+	}
+	stream, ok := d.tcpStreams[key]
+	if flags&tcpFlagSYN != 0 {
+		d.tcpStreams[key] = &tcpStream{nextSeq: seq + 1}
+		return nil
+	}
+	if !ok {
+		// The stream started before the capture, it's picked up at the
+		// next segment starting a message
+		stream = &tcpStream{nextSeq: seq}
+		d.tcpStreams[key] = stream
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+
+	switch offset := int32(seq - stream.nextSeq); {
+	case offset < 0:
+		// Retransmission of data already decoded
+		return nil
+	case offset > 0:
+		// Data is missing, so the buffered message can't be completed
+		stream.buf = nil
+	}
+	stream.buf = append(stream.buf, payload...)
+	stream.nextSeq = seq + uint32(len(payload))
+
+	var msgs []*Message
+	for len(stream.buf) >= diameterHeaderLen {
+		msgLen, ok := diameterMessageLength(stream.buf)
+		if !ok {
+			stream.buf = nil
+			break
+		}
+		if msgLen > len(stream.buf) {
+			break
+		}
+		if m, err := decodeDiameter(stream.buf[:msgLen]); err == nil {
+			msgs = append(msgs, m)
+		}
+		stream.buf = stream.buf[msgLen:]
+	}
+	if len(stream.buf) == 0 {
+		stream.buf = nil
+	}
+	return msgs
+}
+
+// decodeUDP decodes the GTPv2-C message of the UDP datagram.
+func decodeUDP(ip *ipPacket) []*Message {
+	data := ip.payload
+	if len(data) < 8 {
+		return nil
+	}
+	srcPort, dstPort := binary.BigEndian.Uint16(data[0:2]), binary.BigEndian.Uint16(data[2:4])
+	if srcPort != portGTPC && dstPort != portGTPC {
+		return nil
+	}
+	m, err := decodeGTPv2(data[8:])
+	if err != nil {
+		return nil
+	}
+	return []*Message{m}
+}
+
+// pad returns n rounded up to a multiple of 4.
+func pad(n int) int {
+	return (n + 3) &^ 3
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lifecycle records the progress of call traces on their gateways,
+// and stops and deletes call traces per their time limit and retention.
+//
+// A call trace runs on one or more gateways. Each gateway reports the end of
+// the trace with its capture, either inline or uploaded beforehand in
+// chunks. The call trace ends once it ended on all its gateways.
+package lifecycle
+
+import (
+	"fmt"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/orc8r"
+	"magma/orc8r/cloud/go/serdes"
+	"magma/orc8r/cloud/go/services/configurator"
+	"magma/orc8r/cloud/go/services/ctraced/obsidian/models"
+	"magma/orc8r/cloud/go/services/ctraced/storage"
+	storage2 "magma/orc8r/cloud/go/storage"
+	merrors "magma/orc8r/lib/go/errors"
+	"magma/orc8r/lib/go/protos"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// maxUpdateAttempts bounds the attempts to update a call trace which is
+// concurrently updated, e.g. by several of its gateways.
+const maxUpdateAttempts = 5
+
+var (
+	// ErrUnknownGateway indicates the call trace doesn't run on the gateway.
+	ErrUnknownGateway = errors.New("call trace does not run on gateway")
+	// ErrEnded indicates the call trace already ended on the gateway.
+	ErrEnded = errors.New("call trace already ended on gateway")
+	// ErrChunkOutOfOrder indicates a chunk was uploaded before the chunks
+	// preceding it.
+	ErrChunkOutOfOrder = errors.New("call trace chunk uploaded out of order")
+	// ErrChunkCountMismatch indicates the number of chunks reported by a
+	// gateway differs from the number of chunks it uploaded.
+	ErrChunkCountMismatch = errors.New("call trace chunk count does not match chunks uploaded")
+	// ErrTooLarge indicates the gateway's capture exceeds the maximum size.
+	ErrTooLarge = errors.New("call trace exceeds maximum size")
+
+	errUnchanged = errors.New("call trace unchanged")
+)
+
+// GatewayClient ends call traces on gateways.
+type GatewayClient interface {
+	EndCallTrace(networkId string, gatewayId string, req *protos.EndTraceRequest) (*protos.EndTraceResponse, error)
+}
+
+// Recorder records gateways' captures of call traces.
+type Recorder struct {
+	store        storage.CtracedStorage
+	maxTraceSize uint64
+}
+
+// NewRecorder returns a recorder which stores captures in store, and rejects
+// captures larger than maxTraceSize bytes.
+func NewRecorder(store storage.CtracedStorage, maxTraceSize uint64) *Recorder {
+	return &Recorder{store: store, maxTraceSize: maxTraceSize}
+}
+
+// StoreChunk stores a chunk of the gateway's capture of the call trace.
+// Chunks must be stored in order. Storing a chunk which was already stored
+// is a no-op, so gateways can retry uploads.
+func (r *Recorder) StoreChunk(networkID string, callTraceID string, gatewayID string, index uint32, data []byte) error {
+	trace, _, err := LoadCallTrace(networkID, callTraceID)
+	if err != nil {
+		return err
+	}
+	gw, err := getActiveGatewayState(trace, gatewayID)
+	if err != nil {
+		return err
+	}
+	if index < gw.Chunks {
+		return nil
+	}
+	if index > gw.Chunks {
+		return ErrChunkOutOfOrder
+	}
+	if gw.Size+uint64(len(data)) > r.maxTraceSize {
+		return ErrTooLarge
+	}
+
+	err = r.store.StoreCallTraceChunk(networkID, callTraceID, gatewayID, index, data)
+	if err != nil {
+		return err
+	}
+	_, err = UpdateCallTrace(networkID, callTraceID, func(trace *models.CallTrace) error {
+		gw, err := getActiveGatewayState(trace, gatewayID)
+		if err != nil {
+			return err
+		}
+		if index < gw.Chunks {
+			return errUnchanged
+		}
+		if index > gw.Chunks {
+			return ErrChunkOutOfOrder
+		}
+		gw.Chunks++
+		gw.Size += uint64(len(data))
+		trace.SetGatewayState(gatewayID, gw, clock.Now())
+		return nil
+	})
+	return err
+}
+
+// EndGatewayTrace records the end of the call trace on the gateway.
+// The gateway's capture is either content, or the chunkCount chunks the
+// gateway uploaded beforehand.
+func (r *Recorder) EndGatewayTrace(networkID string, callTraceID string, gatewayID string, success bool, content []byte, chunkCount uint32) (*models.CallTrace, error) {
+	trace, _, err := LoadCallTrace(networkID, callTraceID)
+	if err != nil {
+		return nil, err
+	}
+	gw, err := getActiveGatewayState(trace, gatewayID)
+	if err != nil {
+		return nil, err
+	}
+
+	inline := chunkCount == 0 && len(content) > 0
+	if inline {
+		if gw.Chunks > 0 {
+			return nil, ErrChunkCountMismatch
+		}
+		if uint64(len(content)) > r.maxTraceSize {
+			return nil, ErrTooLarge
+		}
+		err = r.store.StoreCallTraceChunk(networkID, callTraceID, gatewayID, 0, content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return UpdateCallTrace(networkID, callTraceID, func(trace *models.CallTrace) error {
+		gw, err := getActiveGatewayState(trace, gatewayID)
+		if err != nil {
+			return err
+		}
+		if inline {
+			gw.Chunks, gw.Size = 1, uint64(len(content))
+		} else if gw.Chunks != chunkCount {
+			return ErrChunkCountMismatch
+		}
+		gw.Ended = true
+		gw.Success = success
+		trace.SetGatewayState(gatewayID, gw, clock.Now())
+		return nil
+	})
+}
+
+// StopCallTrace ends the call trace on each of its gateways it hasn't ended
+// on yet, and records their captures. Gateways which fail to end the trace
+// are recorded as having failed, so the call trace still ends.
+func (r *Recorder) StopCallTrace(client GatewayClient, networkID string, callTraceID string) error {
+	trace, _, err := LoadCallTrace(networkID, callTraceID)
+	if err != nil {
+		return err
+	}
+
+	errs := merrors.NewMulti()
+	for _, gatewayID := range trace.Config.GetGatewayIDs() {
+		if trace.GetGatewayState(gatewayID).Ended {
+			continue
+		}
+		err := r.stopOnGateway(client, networkID, callTraceID, gatewayID)
+		errs = errs.AddFmt(err, "failed to end call trace on gateway %s:", gatewayID)
+	}
+	return errs.AsError()
+}
+
+func (r *Recorder) stopOnGateway(client GatewayClient, networkID string, callTraceID string, gatewayID string) error {
+	resp, err := client.EndCallTrace(networkID, gatewayID, &protos.EndTraceRequest{TraceId: callTraceID})
+	if err == nil && !resp.Success {
+		err = errors.New("gateway failed to end call trace")
+	}
+	if err == nil {
+		_, err = r.EndGatewayTrace(networkID, callTraceID, gatewayID, true, resp.TraceContent, resp.ChunkCount)
+	}
+	// The gateway may have reported the end of the trace concurrently
+	if err == nil || err == ErrEnded {
+		return nil
+	}
+
+	_, failErr := UpdateCallTrace(networkID, callTraceID, func(trace *models.CallTrace) error {
+		gw := trace.GetGatewayState(gatewayID)
+		if gw.Ended {
+			return errUnchanged
+		}
+		gw.Ended = true
+		gw.Success = false
+		trace.SetGatewayState(gatewayID, gw, clock.Now())
+		return nil
+	})
+	if failErr != nil {
+		glog.Errorf("Failed to record failure of call trace %s on gateway %s: %+v", callTraceID, gatewayID, failErr)
+	}
+	return err
+}
+
+// LoadCallTrace returns the call trace and the version of its entity.
+// Returns ErrNotFound from magma/orc8r/lib/go/errors if the call trace
+// doesn't exist.
+func LoadCallTrace(networkID string, callTraceID string) (*models.CallTrace, uint64, error) {
+	ent, err := configurator.LoadEntity(
+		networkID, orc8r.CallTraceEntityType, callTraceID,
+		configurator.EntityLoadCriteria{LoadConfig: true},
+		serdes.Entity,
+	)
+	if err == merrors.ErrNotFound {
+		return nil, 0, err
+	}
+	if err != nil {
+		return nil, 0, errors.Wrap(err, fmt.Sprintf("failed to load call trace %s", callTraceID))
+	}
+	trace := &models.CallTrace{}
+	err = trace.FromBackendModels(ent)
+	if err != nil {
+		return nil, 0, err
+	}
+	return trace, ent.Version, nil
+}
+
+// UpdateCallTrace applies update to the call trace, and stores the result.
+// Updates conflicting with concurrent updates of the call trace are retried
+// against the updated call trace.
+func UpdateCallTrace(networkID string, callTraceID string, update func(trace *models.CallTrace) error) (*models.CallTrace, error) {
+	for attempt := 1; ; attempt++ {
+		trace, version, err := LoadCallTrace(networkID, callTraceID)
+		if err != nil {
+			return nil, err
+		}
+		err = update(trace)
+		if err == errUnchanged {
+			return trace, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = configurator.UpdateEntity(
+			networkID,
+			configurator.EntityUpdateCriteria{
+				Type:            orc8r.CallTraceEntityType,
+				Key:             callTraceID,
+				NewConfig:       trace,
+				ExpectedVersion: &version,
+			},
+			serdes.Entity,
+		)
+		if _, isConflict := err.(*storage2.VersionConflictError); isConflict && attempt < maxUpdateAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to update call trace %s", callTraceID))
+		}
+		return trace, nil
+	}
+}
+
+// getActiveGatewayState returns the state of the call trace on the gateway,
+// if the call trace runs on the gateway and hasn't ended on it.
+func getActiveGatewayState(trace *models.CallTrace, gatewayID string) (models.CallTraceGatewayState, error) {
+	if !trace.HasGateway(gatewayID) {
+		return models.CallTraceGatewayState{}, ErrUnknownGateway
+	}
+	gw := trace.GetGatewayState(gatewayID)
+	if gw.Ended {
+		return models.CallTraceGatewayState{}, ErrEnded
+	}
+	return gw, nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/leader"
+	"magma/orc8r/cloud/go/orc8r"
+	"magma/orc8r/cloud/go/serdes"
+	"magma/orc8r/cloud/go/services/configurator"
+	"magma/orc8r/cloud/go/services/ctraced"
+	"magma/orc8r/cloud/go/services/ctraced/obsidian/models"
+	"magma/orc8r/cloud/go/services/ctraced/storage"
+	merrors "magma/orc8r/lib/go/errors"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// LeaderElectionName is the name of the leader election among ctraced
+// replicas for sweeping call traces.
+const LeaderElectionName = "ctraced_sweeper"
+
+// Sweeper stops call traces which exceeded their time limit without being
+// reported by their gateways, and deletes call traces which ended longer
+// than the retention ago.
+type Sweeper struct {
+	client   GatewayClient
+	store    storage.CtracedStorage
+	recorder *Recorder
+	cfg      ctraced.Config
+	elector  leader.Elector
+}
+
+// NewSweeper returns a sweeper of the call traces stored in store. Only the
+// replica elected by the passed elector sweeps, so each call trace is stopped
+// and deleted once. A nil elector always sweeps.
+func NewSweeper(client GatewayClient, store storage.CtracedStorage, cfg ctraced.Config, elector leader.Elector) *Sweeper {
+	return &Sweeper{
+		client:   client,
+		store:    store,
+		recorder: NewRecorder(store, cfg.MaxTraceSize),
+		cfg:      cfg,
+		elector:  elector,
+	}
+}
+
+// Run sweeps call traces every sweep interval, until the context is
+// cancelled.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.SweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.isLeader() {
+				continue
+			}
+			if err := s.Sweep(); err != nil {
+				glog.Errorf("Error sweeping call traces: %+v", err)
+			}
+		}
+	}
+}
+
+func (s *Sweeper) isLeader() bool {
+	if s.elector == nil {
+		return true
+	}
+	isLeader, err := s.elector.IsLeader()
+	if err != nil {
+		glog.Errorf("Error electing call trace sweeper leader: %+v", err)
+		return false
+	}
+	return isLeader
+}
+
+// Sweep stops and deletes the call traces of all networks per their time
+// limit and the retention.
+func (s *Sweeper) Sweep() error {
+	networkIDs, err := configurator.ListNetworkIDs()
+	if err != nil {
+		return errors.Wrap(err, "failed to list networks")
+	}
+	errs := merrors.NewMulti()
+	for _, networkID := range networkIDs {
+		errs = errs.AddFmt(s.sweepNetwork(networkID), "network %s:", networkID)
+	}
+	return errs.AsError()
+}
+
+func (s *Sweeper) sweepNetwork(networkID string) error {
+	now := clock.Now()
+	errs := merrors.NewMulti()
+	pageToken := ""
+	for {
+		ents, nextPageToken, err := configurator.LoadAllEntitiesOfType(
+			networkID, orc8r.CallTraceEntityType,
+			configurator.EntityLoadCriteria{LoadConfig: true, PageToken: pageToken},
+			serdes.Entity,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to load call traces")
+		}
+		for _, ent := range ents {
+			trace := &models.CallTrace{}
+			if err := trace.FromBackendModels(ent); err != nil {
+				errs = errs.AddFmt(err, "call trace %s:", ent.Key)
+				continue
+			}
+			errs = errs.AddFmt(s.sweepCallTrace(networkID, ent.Key, trace, now), "call trace %s:", ent.Key)
+		}
+		if nextPageToken == "" {
+			return errs.AsError()
+		}
+		pageToken = nextPageToken
+	}
+}
+
+func (s *Sweeper) sweepCallTrace(networkID string, callTraceID string, trace *models.CallTrace, now time.Time) error {
+	if trace.IsEnded() {
+		endTime := time.Time(trace.State.EndTime)
+		// Call traces within the retention are kept
+		if endTime.IsZero() || now.Sub(endTime) < s.cfg.Retention {
+			return nil
+		}
+		glog.Infof("Deleting call trace %s of network %s, which ended at %s", callTraceID, networkID, endTime)
+		if err := s.store.DeleteCallTrace(networkID, callTraceID); err != nil {
+			return err
+		}
+		return configurator.DeleteEntity(networkID, orc8r.CallTraceEntityType, callTraceID)
+	}
+
+	deadline, ok := trace.GetDeadline()
+	if !ok || now.Before(deadline.Add(s.cfg.StopGracePeriod)) {
+		return nil
+	}
+	glog.Infof("Stopping call trace %s of network %s, which exceeded its time limit at %s", callTraceID, networkID, deadline)
+	_, err := UpdateCallTrace(networkID, callTraceID, func(trace *models.CallTrace) error {
+		if trace.State.TimedOut {
+			return errUnchanged
+		}
+		trace.State.TimedOut = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return s.recorder.StopCallTrace(s.client, networkID, callTraceID)
+}
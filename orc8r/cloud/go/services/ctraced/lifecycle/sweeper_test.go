@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle_test
+
+import (
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/orc8r"
+	"magma/orc8r/cloud/go/serdes"
+	"magma/orc8r/cloud/go/services/configurator"
+	configurator_test_init "magma/orc8r/cloud/go/services/configurator/test_init"
+	"magma/orc8r/cloud/go/services/ctraced"
+	"magma/orc8r/cloud/go/services/ctraced/lifecycle"
+	"magma/orc8r/cloud/go/services/ctraced/obsidian/models"
+	"magma/orc8r/cloud/go/services/ctraced/storage"
+	"magma/orc8r/cloud/go/test_utils"
+	merrors "magma/orc8r/lib/go/errors"
+	"magma/orc8r/lib/go/protos"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockGatewayClient struct {
+	ended []string
+}
+
+func (c *mockGatewayClient) EndCallTrace(networkId string, gatewayId string, req *protos.EndTraceRequest) (*protos.EndTraceResponse, error) {
+	c.ended = append(c.ended, gatewayId)
+	if gatewayId == "g2" {
+		return nil, errors.New("gateway unreachable")
+	}
+	return &protos.EndTraceResponse{Success: true, TraceContent: []byte("capture")}, nil
+}
+
+func TestSweeper_Sweep(t *testing.T) {
+	configurator_test_init.StartTestService(t)
+	err := configurator.CreateNetwork(configurator.Network{ID: "n1"}, serdes.Network)
+	require.NoError(t, err)
+
+	store := storage.NewCtracedBlobstore(test_utils.NewSQLBlobstore(t, "ctraced_sweeper_test_blobstore"))
+	client := &mockGatewayClient{}
+	cfg := ctraced.Config{}.WithDefaults()
+	sweeper := lifecycle.NewSweeper(client, store, cfg, nil)
+
+	startTime := time.Unix(1000000, 0).UTC()
+	clock.SetAndFreezeClock(t, startTime)
+	defer clock.UnfreezeClock(t)
+
+	trace := &models.CallTrace{
+		Config: &models.CallTraceConfig{
+			TraceID:    "t1",
+			TraceType:  models.CallTraceConfigTraceTypeGATEWAY,
+			GatewayID:  "g1",
+			GatewayIds: []string{"g2"},
+			Timeout:    60,
+		},
+		State: &models.CallTraceState{},
+	}
+	trace.Start(startTime)
+	_, err = configurator.CreateEntity("n1", trace.ToEntity(), serdes.Entity)
+	require.NoError(t, err)
+
+	// Within the time limit and grace period, nothing happens
+	clock.SetAndFreezeClock(t, startTime.Add(time.Minute+cfg.StopGracePeriod-time.Second))
+	require.NoError(t, sweeper.Sweep())
+	assert.Empty(t, client.ended)
+
+	// Past them, the trace is stopped on all its gateways, and gateways
+	// which fail to end it are recorded as ended unsuccessfully
+	stopTime := startTime.Add(time.Minute + cfg.StopGracePeriod)
+	clock.SetAndFreezeClock(t, stopTime)
+	err = sweeper.Sweep()
+	assert.EqualError(t, err, "network n1: call trace t1: failed to end call trace on gateway g2: gateway unreachable")
+	assert.ElementsMatch(t, []string{"g1", "g2"}, client.ended)
+
+	trace, _, err = lifecycle.LoadCallTrace("n1", "t1")
+	require.NoError(t, err)
+	assert.True(t, trace.IsEnded())
+	assert.True(t, trace.State.TimedOut)
+	assert.True(t, trace.State.CallTraceAvailable)
+	assert.Equal(t, models.CallTraceGatewayState{Ended: true, Success: true, Chunks: 1, Size: 7}, trace.GetGatewayState("g1"))
+	assert.Equal(t, models.CallTraceGatewayState{Ended: true}, trace.GetGatewayState("g2"))
+
+	// Ended traces aren't stopped again
+	require.NoError(t, sweeper.Sweep())
+	assert.Len(t, client.ended, 2)
+
+	// Within the retention, the trace is kept
+	clock.SetAndFreezeClock(t, stopTime.Add(cfg.Retention-time.Second))
+	require.NoError(t, sweeper.Sweep())
+	_, err = store.GetCallTraceChunk("n1", "t1", "g1", 0)
+	assert.NoError(t, err)
+
+	// Past it, the trace and its captures are deleted
+	clock.SetAndFreezeClock(t, stopTime.Add(cfg.Retention))
+	require.NoError(t, sweeper.Sweep())
+	_, err = store.GetCallTraceChunk("n1", "t1", "g1", 0)
+	assert.Equal(t, merrors.ErrNotFound, err)
+	exists, err := configurator.DoesEntityExist("n1", orc8r.CallTraceEntityType, "t1")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -14,18 +14,28 @@
 package handlers
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
+	"magma/orc8r/cloud/go/clock"
 	"magma/orc8r/cloud/go/obsidian"
 	"magma/orc8r/cloud/go/orc8r"
 	"magma/orc8r/cloud/go/serdes"
 	"magma/orc8r/cloud/go/services/configurator"
+	"magma/orc8r/cloud/go/services/ctraced"
+	"magma/orc8r/cloud/go/services/ctraced/decode"
+	"magma/orc8r/cloud/go/services/ctraced/lifecycle"
 	"magma/orc8r/cloud/go/services/ctraced/obsidian/models"
+	"magma/orc8r/cloud/go/services/ctraced/pcap"
 	"magma/orc8r/cloud/go/services/ctraced/storage"
 	merrors "magma/orc8r/lib/go/errors"
 	"magma/orc8r/lib/go/protos"
 
+	"github.com/golang/glog"
 	"github.com/labstack/echo"
 	"github.com/pkg/errors"
 )
@@ -38,19 +48,27 @@ const (
 	tracingPath = tracingRootPath + obsidian.UrlSep + ":" + pathParamTraceID
 	// v1/networks/:network_id/tracing/:trace_id/download
 	tracingDownloadPath = tracingPath + obsidian.UrlSep + "download"
+	// v1/networks/:network_id/tracing/:trace_id/ladder
+	tracingLadderPath = tracingPath + obsidian.UrlSep + "ladder"
 
 	pathParamTraceID   = "trace_id"
 	pathParamNetworkID = "network_id"
+
+	queryParamProtocols  = "protocols"
+	queryParamProcedures = "procedures"
+	urlListDelimiter     = ","
 )
 
-func GetObsidianHandlers(client GwCtracedClient, storage storage.CtracedStorage) []obsidian.Handler {
+func GetObsidianHandlers(client GwCtracedClient, storage storage.CtracedStorage, cfg ctraced.Config) []obsidian.Handler {
+	recorder := lifecycle.NewRecorder(storage, cfg.MaxTraceSize)
 	ret := []obsidian.Handler{
 		{Path: tracingRootPath, Methods: obsidian.GET, HandlerFunc: listCallTraces},
-		{Path: tracingRootPath, Methods: obsidian.POST, HandlerFunc: getCreateCallTraceHandlerFunc(client)},
+		{Path: tracingRootPath, Methods: obsidian.POST, HandlerFunc: getCreateCallTraceHandlerFunc(client, cfg)},
 		{Path: tracingPath, Methods: obsidian.GET, HandlerFunc: getCallTrace},
-		{Path: tracingPath, Methods: obsidian.PUT, HandlerFunc: getUpdateCallTraceHandlerFunc(client, storage)},
+		{Path: tracingPath, Methods: obsidian.PUT, HandlerFunc: getUpdateCallTraceHandlerFunc(client, recorder)},
 		{Path: tracingPath, Methods: obsidian.DELETE, HandlerFunc: getDeleteCallTraceHandlerFunc(client, storage)},
 		{Path: tracingDownloadPath, Methods: obsidian.GET, HandlerFunc: getDownloadCallTraceHandlerFunc(storage)},
+		{Path: tracingLadderPath, Methods: obsidian.GET, HandlerFunc: getCallTraceLadderHandlerFunc(storage)},
 	}
 
 	return ret
@@ -78,7 +96,7 @@ func listCallTraces(c echo.Context) error {
 	return c.JSON(http.StatusOK, ret)
 }
 
-func getCreateCallTraceHandlerFunc(client GwCtracedClient) echo.HandlerFunc {
+func getCreateCallTraceHandlerFunc(client GwCtracedClient, serviceCfg ctraced.Config) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		networkID, nerr := obsidian.GetNetworkId(c)
 		if nerr != nil {
@@ -88,13 +106,14 @@ func getCreateCallTraceHandlerFunc(client GwCtracedClient) echo.HandlerFunc {
 		if err := c.Bind(cfg); err != nil {
 			return obsidian.HttpError(err, http.StatusBadRequest)
 		}
-		ctr := &models.CallTrace{
-			Config: cfg,
-			State: &models.CallTraceState{
-				CallTraceAvailable: false,
-				CallTraceEnding:    false,
-			},
+		if cfg.Timeout == 0 {
+			cfg.Timeout = uint32(serviceCfg.DefaultTimeout / time.Second)
+		}
+		if time.Duration(cfg.Timeout)*time.Second > serviceCfg.MaxTimeout {
+			return obsidian.HttpError(fmt.Errorf("call trace timeout exceeds maximum of %s", serviceCfg.MaxTimeout), http.StatusBadRequest)
 		}
+		ctr := &models.CallTrace{Config: cfg}
+		ctr.Start(clock.Now())
 		exists, err := configurator.DoesEntityExist(networkID, orc8r.CallTraceEntityType, cfg.TraceID)
 		if exists {
 			return obsidian.HttpError(errors.New(fmt.Sprintf("Call trace id: %s already exists", cfg.TraceID)))
@@ -111,23 +130,40 @@ func getCreateCallTraceHandlerFunc(client GwCtracedClient) echo.HandlerFunc {
 			return obsidian.HttpError(errors.Wrap(err, "failed to build call trace request"), http.StatusInternalServerError)
 		}
 
-		resp, err := client.StartCallTrace(networkID, cfg.GatewayID, req)
-		if err != nil {
-			return obsidian.HttpError(errors.Wrap(err, "failed to start call trace"), http.StatusInternalServerError)
-		}
-		if !resp.Success {
-			return obsidian.HttpError(errors.New("failed to start call trace"), http.StatusInternalServerError)
+		var started []string
+		for _, gatewayID := range cfg.GetGatewayIDs() {
+			resp, err := client.StartCallTrace(networkID, gatewayID, req)
+			if err == nil && !resp.Success {
+				err = errors.New("gateway failed to start call trace")
+			}
+			if err != nil {
+				endStartedCallTraces(client, networkID, cfg.TraceID, started)
+				return obsidian.HttpError(errors.Wrap(err, fmt.Sprintf("failed to start call trace on gateway %s", gatewayID)), http.StatusInternalServerError)
+			}
+			started = append(started, gatewayID)
 		}
 
 		createdEntity := ctr.ToEntity()
 		_, err = configurator.CreateEntity(networkID, createdEntity, serdes.Entity)
 		if err != nil {
+			endStartedCallTraces(client, networkID, cfg.TraceID, started)
 			return obsidian.HttpError(errors.Wrap(err, "failed to create call trace"), http.StatusInternalServerError)
 		}
 		return c.JSON(http.StatusCreated, cfg.TraceID)
 	}
 }
 
+// endStartedCallTraces ends the call trace on the gateways it was started on,
+// when it couldn't be started on all its gateways.
+func endStartedCallTraces(client GwCtracedClient, networkID string, callTraceID string, gatewayIDs []string) {
+	for _, gatewayID := range gatewayIDs {
+		_, err := client.EndCallTrace(networkID, gatewayID, &protos.EndTraceRequest{TraceId: callTraceID})
+		if err != nil {
+			glog.Errorf("Failed to end call trace %s on gateway %s: %+v", callTraceID, gatewayID, err)
+		}
+	}
+}
+
 func getCallTrace(c echo.Context) error {
 	callTrace, err := getCallTraceModel(c)
 	if err != nil {
@@ -136,7 +172,7 @@ func getCallTrace(c echo.Context) error {
 	return c.JSON(http.StatusOK, callTrace)
 }
 
-func getUpdateCallTraceHandlerFunc(client GwCtracedClient, storage storage.CtracedStorage) echo.HandlerFunc {
+func getUpdateCallTraceHandlerFunc(client GwCtracedClient, recorder *lifecycle.Recorder) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		networkID, callTraceID, nerr := getNetworkIDAndCallTraceID(c)
 		if nerr != nil {
@@ -158,23 +194,27 @@ func getUpdateCallTraceHandlerFunc(client GwCtracedClient, storage storage.Ctrac
 			return obsidian.HttpError(errors.New("Error: call trace end already triggered earlier"), http.StatusBadRequest)
 		}
 
-		req := &protos.EndTraceRequest{
-			TraceId: callTraceID,
-		}
-		resp, err := client.EndCallTrace(networkID, callTrace.Config.GatewayID, req)
+		err = recorder.StopCallTrace(client, networkID, callTraceID)
 		if err != nil {
-			return err
+			return obsidian.HttpError(errors.Wrap(err, "failed to end call trace"), http.StatusInternalServerError)
 		}
-		if !resp.Success {
-			return obsidian.HttpError(errors.New("Failed to end call trace"), http.StatusInternalServerError)
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func getDeleteCallTraceHandlerFunc(client GwCtracedClient, storage storage.CtracedStorage) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		networkID, callTraceID, nerr := getNetworkIDAndCallTraceID(c)
+		if nerr != nil {
+			return nerr
 		}
 
-		err = storage.StoreCallTrace(networkID, callTraceID, resp.TraceContent)
+		err := storage.DeleteCallTrace(networkID, callTraceID)
 		if err != nil {
-			return obsidian.HttpError(errors.Wrap(err, fmt.Sprintf("failed to save call trace data, network-id: %s, gateway-id: %s, calltrace-id: %s", networkID, callTrace.Config.GatewayID, callTraceID)), http.StatusInternalServerError)
+			return obsidian.HttpError(errors.Wrap(err, "failed to delete call trace data"), http.StatusInternalServerError)
 		}
 
-		_, err = configurator.UpdateEntity(networkID, mutableCallTrace.ToEntityUpdateCriteria(callTraceID, *callTrace), serdes.Entity)
+		err = configurator.DeleteEntity(networkID, orc8r.CallTraceEntityType, callTraceID)
 		if err != nil {
 			return obsidian.HttpError(err, http.StatusInternalServerError)
 		}
@@ -182,48 +222,136 @@ func getUpdateCallTraceHandlerFunc(client GwCtracedClient, storage storage.Ctrac
 	}
 }
 
-func getDeleteCallTraceHandlerFunc(client GwCtracedClient, storage storage.CtracedStorage) echo.HandlerFunc {
+// getDownloadCallTraceHandlerFunc streams the call trace's capture.
+// Captures of multiple gateways are merged into a single capture.
+func getDownloadCallTraceHandlerFunc(store storage.CtracedStorage) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		networkID, callTraceID, nerr := getNetworkIDAndCallTraceID(c)
 		if nerr != nil {
 			return nerr
 		}
-
-		err := storage.DeleteCallTrace(networkID, callTraceID)
+		callTrace, err := getAvailableCallTraceModel(c)
 		if err != nil {
-			return obsidian.HttpError(errors.Wrap(err, "failed to delete call trace data"), http.StatusInternalServerError)
+			return err
+		}
+		captures, _, err := openCaptures(store, networkID, callTraceID, callTrace)
+		if err != nil {
+			return obsidian.HttpError(err, http.StatusInternalServerError)
 		}
 
-		err = configurator.DeleteEntity(networkID, orc8r.CallTraceEntityType, callTraceID)
+		if len(captures) == 1 {
+			res := writeDownloadHeader(c, callTraceID)
+			_, err = io.Copy(res, captures[0])
+			return err
+		}
+		readers, err := readCaptures(captures)
 		if err != nil {
 			return obsidian.HttpError(err, http.StatusInternalServerError)
 		}
-		return c.NoContent(http.StatusNoContent)
+		res := writeDownloadHeader(c, callTraceID)
+		return pcap.Merge(res, readers...)
 	}
 }
 
-func getDownloadCallTraceHandlerFunc(storage storage.CtracedStorage) echo.HandlerFunc {
+func getCallTraceLadderHandlerFunc(store storage.CtracedStorage) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		networkID, callTraceID, nerr := getNetworkIDAndCallTraceID(c)
 		if nerr != nil {
 			return nerr
 		}
+		callTrace, err := getAvailableCallTraceModel(c)
+		if err != nil {
+			return err
+		}
+		filter := decode.Filter{
+			Protocols:  getListQueryParam(c, queryParamProtocols),
+			Procedures: getListQueryParam(c, queryParamProcedures),
+		}
 
-		callTrace, err := storage.GetCallTrace(networkID, callTraceID)
+		captures, gatewayIDs, err := openCaptures(store, networkID, callTraceID, callTrace)
+		if err != nil {
+			return obsidian.HttpError(err, http.StatusInternalServerError)
+		}
+		readers, err := readCaptures(captures)
+		if err != nil {
+			return obsidian.HttpError(err, http.StatusInternalServerError)
+		}
+		merged, err := pcap.NewMergeReader(readers...)
 		if err != nil {
-			return obsidian.HttpError(errors.Wrap(err, "failed to retrieve call trace data"), http.StatusInternalServerError)
+			return obsidian.HttpError(errors.Wrap(err, "failed to read call trace data"), http.StatusInternalServerError)
 		}
 
-		res := c.Response()
-		header := res.Header()
-		header.Set(echo.HeaderContentType, "application/pcapng")
-		header.Set(echo.HeaderContentDisposition, "attachment; filename="+fmt.Sprintf("%s.pcapng", callTraceID))
-		res.WriteHeader(http.StatusOK)
-		_, err = res.Write(callTrace)
-		return err
+		// Streams are tracked per capture, as each gateway captures its own
+		// connections
+		decoders := make([]*decode.Decoder, len(readers))
+		for i := range decoders {
+			decoders[i] = decode.NewDecoder()
+		}
+		ladder := &models.CallTraceLadder{Nodes: []string{}, Messages: []*models.CallTraceMessage{}}
+		for {
+			p, source, err := merged.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return obsidian.HttpError(errors.Wrap(err, "failed to read call trace data"), http.StatusInternalServerError)
+			}
+			for _, msg := range decoders[source].Decode(p) {
+				if filter.Match(msg) {
+					ladder.AddMessage(msg, gatewayIDs[source])
+				}
+			}
+		}
+		return c.JSON(http.StatusOK, ladder)
 	}
 }
 
+// openCaptures returns the captures of the call trace, along with the IDs of
+// the gateways which captured them.
+func openCaptures(store storage.CtracedStorage, networkID string, callTraceID string, callTrace *models.CallTrace) ([]io.Reader, []string, error) {
+	var captures []io.Reader
+	var gatewayIDs []string
+	for _, gatewayID := range callTrace.Config.GetGatewayIDs() {
+		gw := callTrace.GetGatewayState(gatewayID)
+		if gw.Chunks == 0 {
+			continue
+		}
+		captures = append(captures, storage.NewCallTraceReader(store, networkID, callTraceID, gatewayID, gw.Chunks))
+		gatewayIDs = append(gatewayIDs, gatewayID)
+	}
+	if len(captures) > 0 {
+		return captures, gatewayIDs, nil
+	}
+
+	// Call traces predating chunked storage are stored as one file
+	data, err := store.GetCallTrace(networkID, callTraceID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to retrieve call trace data")
+	}
+	return []io.Reader{bytes.NewReader(data)}, []string{callTrace.Config.GatewayID}, nil
+}
+
+func readCaptures(captures []io.Reader) ([]*pcap.Reader, error) {
+	readers := make([]*pcap.Reader, 0, len(captures))
+	for _, capture := range captures {
+		rd, err := pcap.NewReader(capture)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read call trace data")
+		}
+		readers = append(readers, rd)
+	}
+	return readers, nil
+}
+
+func writeDownloadHeader(c echo.Context, callTraceID string) *echo.Response {
+	res := c.Response()
+	header := res.Header()
+	header.Set(echo.HeaderContentType, "application/pcapng")
+	header.Set(echo.HeaderContentDisposition, "attachment; filename="+fmt.Sprintf("%s.pcapng", callTraceID))
+	res.WriteHeader(http.StatusOK)
+	return res
+}
+
 func getCallTraceModel(c echo.Context) (*models.CallTrace, error) {
 	networkID, callTraceID, nerr := getNetworkIDAndCallTraceID(c)
 	if nerr != nil {
@@ -248,6 +376,27 @@ func getCallTraceModel(c echo.Context) (*models.CallTrace, error) {
 	return callTrace, nil
 }
 
+// getAvailableCallTraceModel returns the call trace, if its capture is
+// available.
+func getAvailableCallTraceModel(c echo.Context) (*models.CallTrace, error) {
+	callTrace, err := getCallTraceModel(c)
+	if err != nil {
+		return nil, err
+	}
+	if callTrace.State == nil || !callTrace.State.CallTraceAvailable {
+		return nil, obsidian.HttpError(errors.New("call trace is not available for download"), http.StatusNotFound)
+	}
+	return callTrace, nil
+}
+
+func getListQueryParam(c echo.Context, param string) []string {
+	val := c.QueryParam(param)
+	if val == "" {
+		return nil
+	}
+	return strings.Split(val, urlListDelimiter)
+}
+
 func getNetworkIDAndCallTraceID(c echo.Context) (string, string, *echo.HTTPError) {
 	vals, err := obsidian.GetParamValues(c, pathParamNetworkID, pathParamTraceID)
 	if err != nil {
@@ -264,11 +413,18 @@ func buildStartTraceRequest(cfg *models.CallTraceConfig) (*protos.StartTraceRequ
 		CaptureFilters: cfg.CaptureFilters,
 		DisplayFilters: cfg.DisplayFilters,
 	}
+	if cfg.TraceType == models.CallTraceConfigTraceTypeSUBSCRIBER {
+		req.TraceType = protos.StartTraceRequest_SUBSCRIBER
+		req.Imsi = cfg.Imsi
+		if req.DisplayFilters == "" {
+			req.DisplayFilters = fmt.Sprintf("e212.imsi == \"%s\"", strings.TrimPrefix(cfg.Imsi, "IMSI"))
+		}
+	}
 	return req, nil
 }
 
 func shouldEndTraceBeTriggered(callTrace *models.CallTrace, mutable *models.MutableCallTrace) bool {
-	if callTrace.State.CallTraceEnding {
+	if callTrace.State.CallTraceEnding || callTrace.IsEnded() {
 		return false
 	}
 	return *mutable.RequestedEnd
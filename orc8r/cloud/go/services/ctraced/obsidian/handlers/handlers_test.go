@@ -14,22 +14,35 @@
 package handlers_test
 
 import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
 	"magma/orc8r/cloud/go/obsidian"
 	"magma/orc8r/cloud/go/obsidian/tests"
 	"magma/orc8r/cloud/go/serdes"
 	"magma/orc8r/cloud/go/services/configurator"
+	"magma/orc8r/cloud/go/services/ctraced"
 	"magma/orc8r/cloud/go/services/ctraced/obsidian/handlers"
 	traceModels "magma/orc8r/cloud/go/services/ctraced/obsidian/models"
+	"magma/orc8r/cloud/go/services/ctraced/pcap"
 	"magma/orc8r/cloud/go/services/ctraced/storage"
 	"magma/orc8r/cloud/go/test_utils"
 	"magma/orc8r/lib/go/protos"
-	"testing"
 
 	configurator_test_init "magma/orc8r/cloud/go/services/configurator/test_init"
 
+	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
 	"github.com/labstack/echo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type MockGWCtracedClient struct{}
@@ -52,11 +65,14 @@ func (c MockGWCtracedClient) EndCallTrace(networkId string, gatewayId string, re
 func TestCtracedHandlersBasic(t *testing.T) {
 	configurator_test_init.StartTestService(t)
 	e := echo.New()
+	startTime := time.Unix(1000000, 0).UTC()
+	clock.SetAndFreezeClock(t, startTime)
+	defer clock.UnfreezeClock(t)
 
 	mockGWClient := MockGWCtracedClient{}
 	fact := test_utils.NewSQLBlobstore(t, "ctraced_handlers_test_blobstore")
 	blobstore := storage.NewCtracedBlobstore(fact)
-	obsidianHandlers := handlers.GetObsidianHandlers(mockGWClient, blobstore)
+	obsidianHandlers := handlers.GetObsidianHandlers(mockGWClient, blobstore, ctraced.Config{}.WithDefaults())
 	err := configurator.CreateNetwork(configurator.Network{ID: "n1"}, serdes.Network)
 	assert.NoError(t, err)
 
@@ -95,6 +111,8 @@ func TestCtracedHandlersBasic(t *testing.T) {
 		State: &traceModels.CallTraceState{
 			CallTraceAvailable: false,
 			CallTraceEnding:    false,
+			StartTime:          strfmt.DateTime(startTime),
+			Gateways:           map[string]traceModels.CallTraceGatewayState{"test_gateway_id": {}},
 		},
 	}
 
@@ -157,6 +175,11 @@ func TestCtracedHandlersBasic(t *testing.T) {
 	testTrace.State = &traceModels.CallTraceState{
 		CallTraceAvailable: true,
 		CallTraceEnding:    true,
+		StartTime:          strfmt.DateTime(startTime),
+		EndTime:            strfmt.DateTime(startTime),
+		Gateways: map[string]traceModels.CallTraceGatewayState{
+			"test_gateway_id": {Ended: true, Success: true, Chunks: 1, Size: 27},
+		},
 	}
 	tc = tests.Test{
 		Method:         "GET",
@@ -211,3 +234,313 @@ func TestCtracedHandlersBasic(t *testing.T) {
 	tc.ExpectedResult = tests.JSONMarshaler(map[string]*traceModels.CallTrace{})
 	tests.RunUnitTest(t, e, tc)
 }
+
+type multiGatewayClient struct {
+	started  map[string]*protos.StartTraceRequest
+	captures map[string][]byte
+}
+
+func (c *multiGatewayClient) StartCallTrace(networkId string, gatewayId string, req *protos.StartTraceRequest) (*protos.StartTraceResponse, error) {
+	c.started[gatewayId] = req
+	return &protos.StartTraceResponse{Success: true}, nil
+}
+
+func (c *multiGatewayClient) EndCallTrace(networkId string, gatewayId string, req *protos.EndTraceRequest) (*protos.EndTraceResponse, error) {
+	return &protos.EndTraceResponse{Success: true, TraceContent: c.captures[gatewayId]}, nil
+}
+
+func TestCtracedHandlers_MultiGateway(t *testing.T) {
+	configurator_test_init.StartTestService(t)
+	e := echo.New()
+
+	client := &multiGatewayClient{
+		started: map[string]*protos.StartTraceRequest{},
+		captures: map[string][]byte{
+			"g1": newCapture(t, 1, "g1-1", "g1-3"),
+			"g2": newCapture(t, 2, "g2-2", "g2-4"),
+		},
+	}
+	fact := test_utils.NewSQLBlobstore(t, "ctraced_handlers_multi_gateway_test_blobstore")
+	blobstore := storage.NewCtracedBlobstore(fact)
+	obsidianHandlers := handlers.GetObsidianHandlers(client, blobstore, ctraced.Config{}.WithDefaults())
+	err := configurator.CreateNetwork(configurator.Network{ID: "n1"}, serdes.Network)
+	assert.NoError(t, err)
+
+	createTrace := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, "/magma/v1/networks/:network_id/tracing", obsidian.POST).HandlerFunc
+	updateTrace := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, "/magma/v1/networks/:network_id/tracing/:trace_id", obsidian.PUT).HandlerFunc
+	downloadTrace := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, "/magma/v1/networks/:network_id/tracing/:trace_id/download", obsidian.GET).HandlerFunc
+
+	// Time limits are bounded
+	cfg := &traceModels.CallTraceConfig{
+		TraceID:    "SubscriberTrace",
+		TraceType:  traceModels.CallTraceConfigTraceTypeSUBSCRIBER,
+		GatewayID:  "g1",
+		GatewayIds: []string{"g2"},
+		Imsi:       "IMSI001010000000001",
+		Timeout:    7200,
+	}
+	tc := tests.Test{
+		Method:         "POST",
+		URL:            "/magma/v1/networks/n1/tracing",
+		Payload:        cfg,
+		ParamNames:     []string{"network_id"},
+		ParamValues:    []string{"n1"},
+		Handler:        createTrace,
+		ExpectedStatus: 400,
+		ExpectedError:  "call trace timeout exceeds maximum of 1h0m0s",
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Subscriber traces need an IMSI
+	cfg.Timeout = 0
+	cfg.Imsi = ""
+	tc.ExpectedError = "subscriber call trace must specify an IMSI"
+	tests.RunUnitTest(t, e, tc)
+
+	// Subscriber traces start on all gateways, filtered by IMSI
+	cfg.Imsi = "IMSI001010000000001"
+	tc.ExpectedStatus = 201
+	tc.ExpectedError = ""
+	tests.RunUnitTest(t, e, tc)
+	expectedReq := &protos.StartTraceRequest{
+		TraceId:        "SubscriberTrace",
+		TraceType:      protos.StartTraceRequest_SUBSCRIBER,
+		Imsi:           "IMSI001010000000001",
+		Timeout:        300,
+		DisplayFilters: `e212.imsi == "001010000000001"`,
+	}
+	assert.Equal(t, map[string]*protos.StartTraceRequest{"g1": expectedReq, "g2": expectedReq}, client.started)
+
+	// Downloads are only available once the trace ended
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/networks/n1/tracing/SubscriberTrace/download",
+		ParamNames:     []string{"network_id", "trace_id"},
+		ParamValues:    []string{"n1", "SubscriberTrace"},
+		Handler:        downloadTrace,
+		ExpectedStatus: 404,
+		ExpectedError:  "call trace is not available for download",
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	tc = tests.Test{
+		Method:         "PUT",
+		URL:            "/magma/v1/networks/n1/tracing/SubscriberTrace",
+		Payload:        &traceModels.MutableCallTrace{RequestedEnd: swag.Bool(true)},
+		ParamNames:     []string{"network_id", "trace_id"},
+		ParamValues:    []string{"n1", "SubscriberTrace"},
+		Handler:        updateTrace,
+		ExpectedStatus: 204,
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Captures of all gateways are merged in time order
+	req := httptest.NewRequest(http.MethodGet, "/magma/v1/networks/n1/tracing/SubscriberTrace/download", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("network_id", "trace_id")
+	c.SetParamValues("n1", "SubscriberTrace")
+	require.NoError(t, downloadTrace(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "attachment; filename=SubscriberTrace.pcapng", rec.Header().Get(echo.HeaderContentDisposition))
+
+	merged, err := pcap.NewReader(rec.Body)
+	require.NoError(t, err)
+	var packets []string
+	for {
+		p, err := merged.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		packets = append(packets, string(p.Data))
+	}
+	assert.Equal(t, []string{"g1-1", "g2-2", "g1-3", "g2-4"}, packets)
+}
+
+// newCapture returns a capture of the packets, captured a second apart from
+// the first second.
+func newCapture(t *testing.T, first int64, packets ...string) []byte {
+	buf := &bytes.Buffer{}
+	w := pcap.NewWriter(buf)
+	iface := &pcap.Interface{LinkType: 1, SnapLen: 65535}
+	for i, data := range packets {
+		err := w.WritePacket(&pcap.Packet{
+			Interface:      iface,
+			Timestamp:      time.Unix(first+2*int64(i), 0),
+			OriginalLength: uint32(len(data)),
+			Data:           []byte(data),
+		})
+		require.NoError(t, err)
+	}
+	return buf.Bytes()
+}
+
+func TestCtracedHandlers_Ladder(t *testing.T) {
+	configurator_test_init.StartTestService(t)
+	e := echo.New()
+
+	const mme, sgw = "10.0.2.1", "10.0.3.1"
+	client := &multiGatewayClient{
+		started: map[string]*protos.StartTraceRequest{},
+		captures: map[string][]byte{
+			"g1": newGTPv2Capture(t,
+				gtpv2Packet{sec: 1, src: mme, dst: sgw, msgType: 32},
+			),
+			"g2": newGTPv2Capture(t,
+				gtpv2Packet{sec: 2, src: mme, dst: sgw, msgType: 1},
+				gtpv2Packet{sec: 3, src: sgw, dst: mme, msgType: 33},
+			),
+		},
+	}
+	fact := test_utils.NewSQLBlobstore(t, "ctraced_handlers_ladder_test_blobstore")
+	blobstore := storage.NewCtracedBlobstore(fact)
+	obsidianHandlers := handlers.GetObsidianHandlers(client, blobstore, ctraced.Config{}.WithDefaults())
+	err := configurator.CreateNetwork(configurator.Network{ID: "n1"}, serdes.Network)
+	assert.NoError(t, err)
+
+	createTrace := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, "/magma/v1/networks/:network_id/tracing", obsidian.POST).HandlerFunc
+	updateTrace := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, "/magma/v1/networks/:network_id/tracing/:trace_id", obsidian.PUT).HandlerFunc
+	getLadder := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, "/magma/v1/networks/:network_id/tracing/:trace_id/ladder", obsidian.GET).HandlerFunc
+
+	tc := tests.Test{
+		Method: "POST",
+		URL:    "/magma/v1/networks/n1/tracing",
+		Payload: &traceModels.CallTraceConfig{
+			TraceID:    "Trace",
+			TraceType:  traceModels.CallTraceConfigTraceTypeGATEWAY,
+			GatewayID:  "g1",
+			GatewayIds: []string{"g2"},
+		},
+		ParamNames:     []string{"network_id"},
+		ParamValues:    []string{"n1"},
+		Handler:        createTrace,
+		ExpectedStatus: 201,
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// The ladder is only available once the trace ended
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/networks/n1/tracing/Trace/ladder",
+		ParamNames:     []string{"network_id", "trace_id"},
+		ParamValues:    []string{"n1", "Trace"},
+		Handler:        getLadder,
+		ExpectedStatus: 404,
+		ExpectedError:  "call trace is not available for download",
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	tc = tests.Test{
+		Method:         "PUT",
+		URL:            "/magma/v1/networks/n1/tracing/Trace",
+		Payload:        &traceModels.MutableCallTrace{RequestedEnd: swag.Bool(true)},
+		ParamNames:     []string{"network_id", "trace_id"},
+		ParamValues:    []string{"n1", "Trace"},
+		Handler:        updateTrace,
+		ExpectedStatus: 204,
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Messages of all gateways are merged in time order
+	csr := &traceModels.CallTraceMessage{
+		Timestamp:   strfmt.DateTime(time.Unix(1, 0)),
+		GatewayID:   "g1",
+		Source:      mme,
+		Destination: sgw,
+		Protocol:    traceModels.CallTraceMessageProtocolGTPv2,
+		MessageType: "Create Session Request",
+		Procedure:   "Create Session",
+		Ies:         map[string]string{"teid": "0x00000000", "sequence": "1"},
+	}
+	echoReq := &traceModels.CallTraceMessage{
+		Timestamp:   strfmt.DateTime(time.Unix(2, 0)),
+		GatewayID:   "g2",
+		Source:      mme,
+		Destination: sgw,
+		Protocol:    traceModels.CallTraceMessageProtocolGTPv2,
+		MessageType: "Echo Request",
+		Procedure:   "Echo",
+		Ies:         map[string]string{"teid": "0x00000000", "sequence": "1"},
+	}
+	csResp := &traceModels.CallTraceMessage{
+		Timestamp:   strfmt.DateTime(time.Unix(3, 0)),
+		GatewayID:   "g2",
+		Source:      sgw,
+		Destination: mme,
+		Protocol:    traceModels.CallTraceMessageProtocolGTPv2,
+		MessageType: "Create Session Response",
+		Procedure:   "Create Session",
+		Ies:         map[string]string{"teid": "0x00000000", "sequence": "1"},
+	}
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/networks/n1/tracing/Trace/ladder",
+		ParamNames:     []string{"network_id", "trace_id"},
+		ParamValues:    []string{"n1", "Trace"},
+		Handler:        getLadder,
+		ExpectedStatus: 200,
+		ExpectedResult: &traceModels.CallTraceLadder{
+			Nodes:    []string{mme, sgw},
+			Messages: []*traceModels.CallTraceMessage{csr, echoReq, csResp},
+		},
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Filtered by procedure
+	tc.URL = "/magma/v1/networks/n1/tracing/Trace/ladder?procedures=create_session"
+	tc.ExpectedResult = &traceModels.CallTraceLadder{
+		Nodes:    []string{mme, sgw},
+		Messages: []*traceModels.CallTraceMessage{csr, csResp},
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Filtered by protocol
+	tc.URL = "/magma/v1/networks/n1/tracing/Trace/ladder?protocols=S1AP,NAS"
+	tc.ExpectedResult = &traceModels.CallTraceLadder{
+		Nodes:    []string{},
+		Messages: []*traceModels.CallTraceMessage{},
+	}
+	tests.RunUnitTest(t, e, tc)
+}
+
+type gtpv2Packet struct {
+	sec      int64
+	src, dst string
+	msgType  uint8
+}
+
+// newGTPv2Capture returns a capture of GTPv2-C messages without IEs, sent
+// over Ethernet.
+func newGTPv2Capture(t *testing.T, packets ...gtpv2Packet) []byte {
+	buf := &bytes.Buffer{}
+	w := pcap.NewWriter(buf)
+	iface := &pcap.Interface{LinkType: 1, SnapLen: 65535}
+	for _, p := range packets {
+		gtp := []byte{0x48, p.msgType, 0, 8, 0, 0, 0, 0, 0, 0, 1, 0}
+		udp := make([]byte, 8)
+		binary.BigEndian.PutUint16(udp[0:2], 2123)
+		binary.BigEndian.PutUint16(udp[2:4], 2123)
+		binary.BigEndian.PutUint16(udp[4:6], uint16(8+len(gtp)))
+		ip := make([]byte, 20)
+		ip[0], ip[9] = 0x45, 17
+		binary.BigEndian.PutUint16(ip[2:4], uint16(20+len(udp)+len(gtp)))
+		copy(ip[12:16], net.ParseIP(p.src).To4())
+		copy(ip[16:20], net.ParseIP(p.dst).To4())
+		eth := make([]byte, 14)
+		eth[12] = 0x08
+
+		var data []byte
+		for _, part := range [][]byte{eth, ip, udp, gtp} {
+			data = append(data, part...)
+		}
+		err := w.WritePacket(&pcap.Packet{
+			Interface:      iface,
+			Timestamp:      time.Unix(p.sec, 0),
+			OriginalLength: uint32(len(data)),
+			Data:           data,
+		})
+		require.NoError(t, err)
+	}
+	return buf.Bytes()
+}
@@ -30,7 +30,17 @@ type CallTraceConfig struct {
 	// ID of gateway to run call tracing on
 	GatewayID string `json:"gateway_id,omitempty"`
 
-	// Timeout of call trace in seconds
+	// IDs of further gateways to run call tracing on, e.g. the gateways a traced subscriber may be handed over between. The captures of all gateways are merged on download.
+	//
+	GatewayIds []string `json:"gateway_ids"`
+
+	// IMSI of the subscriber to trace. Only applies if trace_type is SUBSCRIBER. Unless display filters are specified, captures are filtered to packets which carry the IMSI.
+	//
+	// Pattern: ^(IMSI\d{10,15})$
+	Imsi string `json:"imsi,omitempty"`
+
+	// Time limit of the call trace in seconds, after which it's stopped. Defaults to, and is bounded by, the limits configured for ctraced.
+	//
 	Timeout uint32 `json:"timeout,omitempty"`
 
 	// trace id
@@ -39,9 +49,11 @@ type CallTraceConfig struct {
 
 	// Trace Type:
 	//  * GATEWAY - Call trace for a gateway
+	//  * SUBSCRIBER - Call trace for a subscriber, on each gateway the
+	//    subscriber may be served by
 	//
 	// Required: true
-	// Enum: [GATEWAY]
+	// Enum: [GATEWAY SUBSCRIBER]
 	TraceType string `json:"trace_type"`
 }
 
@@ -49,6 +61,10 @@ type CallTraceConfig struct {
 func (m *CallTraceConfig) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateImsi(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateTraceID(formats); err != nil {
 		res = append(res, err)
 	}
@@ -63,6 +79,19 @@ func (m *CallTraceConfig) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *CallTraceConfig) validateImsi(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Imsi) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("imsi", "body", string(m.Imsi), `^(IMSI\d{10,15})$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *CallTraceConfig) validateTraceID(formats strfmt.Registry) error {
 
 	if err := validate.RequiredString("trace_id", "body", string(m.TraceID)); err != nil {
@@ -76,7 +105,7 @@ var callTraceConfigTypeTraceTypePropEnum []interface{}
 
 func init() {
 	var res []string
-	if err := json.Unmarshal([]byte(`["GATEWAY"]`), &res); err != nil {
+	if err := json.Unmarshal([]byte(`["GATEWAY","SUBSCRIBER"]`), &res); err != nil {
 		panic(err)
 	}
 	for _, v := range res {
@@ -88,6 +117,9 @@ const (
 
 	// CallTraceConfigTraceTypeGATEWAY captures enum value "GATEWAY"
 	CallTraceConfigTraceTypeGATEWAY string = "GATEWAY"
+
+	// CallTraceConfigTraceTypeSUBSCRIBER captures enum value "SUBSCRIBER"
+	CallTraceConfigTraceTypeSUBSCRIBER string = "SUBSCRIBER"
 )
 
 // prop value enum
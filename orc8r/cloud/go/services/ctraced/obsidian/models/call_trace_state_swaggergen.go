@@ -8,7 +8,9 @@ package models
 import (
 	strfmt "github.com/go-openapi/strfmt"
 
+	"github.com/go-openapi/errors"
 	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 )
 
 // CallTraceState Full state object of a call trace
@@ -20,10 +22,89 @@ type CallTraceState struct {
 
 	// True if trace has been requested to end
 	CallTraceEnding bool `json:"call_trace_ending,omitempty"`
+
+	// Time the call trace ended on all of its gateways
+	// Format: date-time
+	EndTime strfmt.DateTime `json:"end_time,omitempty"`
+
+	// State of the call trace on each of its gateways
+	Gateways map[string]CallTraceGatewayState `json:"gateways,omitempty"`
+
+	// Time the call trace was started
+	// Format: date-time
+	StartTime strfmt.DateTime `json:"start_time,omitempty"`
+
+	// True if the trace was stopped for exceeding its time limit
+	TimedOut bool `json:"timed_out,omitempty"`
 }
 
 // Validate validates this call trace state
 func (m *CallTraceState) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateEndTime(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateGateways(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateStartTime(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *CallTraceState) validateEndTime(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.EndTime) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("end_time", "body", "date-time", m.EndTime.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *CallTraceState) validateGateways(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Gateways) { // not required
+		return nil
+	}
+
+	for k := range m.Gateways {
+
+		if swag.IsZero(m.Gateways[k]) { // not required
+			continue
+		}
+		if val, ok := m.Gateways[k]; ok {
+			if err := val.Validate(formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *CallTraceState) validateStartTime(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.StartTime) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("start_time", "body", "date-time", m.StartTime.String(), formats); err != nil {
+		return err
+	}
+
 	return nil
 }
 
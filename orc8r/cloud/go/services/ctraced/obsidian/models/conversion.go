@@ -18,6 +18,9 @@ import (
 
 	"magma/orc8r/cloud/go/orc8r"
 	"magma/orc8r/cloud/go/services/configurator"
+	"magma/orc8r/cloud/go/services/ctraced/decode"
+
+	"github.com/go-openapi/strfmt"
 )
 
 func (c *CallTrace) ToEntity() configurator.NetworkEntity {
@@ -59,3 +62,32 @@ func (c *MutableCallTrace) ToCallTrace(callTrace CallTrace) *CallTrace {
 	callTrace.State.CallTraceAvailable = *c.RequestedEnd
 	return &callTrace
 }
+
+// AddMessage appends the message captured by the gateway to the ladder,
+// along with the nodes it's exchanged between.
+func (m *CallTraceLadder) AddMessage(msg *decode.Message, gatewayID string) {
+	for _, node := range []string{msg.Source, msg.Destination} {
+		if !m.hasNode(node) {
+			m.Nodes = append(m.Nodes, node)
+		}
+	}
+	m.Messages = append(m.Messages, &CallTraceMessage{
+		Timestamp:   strfmt.DateTime(msg.Timestamp),
+		GatewayID:   gatewayID,
+		Source:      msg.Source,
+		Destination: msg.Destination,
+		Protocol:    string(msg.Protocol),
+		MessageType: msg.MessageType,
+		Procedure:   msg.Procedure,
+		Ies:         msg.IEs,
+	})
+}
+
+func (m *CallTraceLadder) hasNode(node string) bool {
+	for _, n := range m.Nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
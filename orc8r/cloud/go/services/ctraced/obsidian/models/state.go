@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/go-openapi/strfmt"
+)
+
+// GetGatewayIDs returns the IDs of the gateways the call trace runs on.
+func (m *CallTraceConfig) GetGatewayIDs() []string {
+	var ids []string
+	seen := map[string]bool{}
+	for _, id := range append([]string{m.GatewayID}, m.GatewayIds...) {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// HasGateway returns true if the call trace runs on the gateway.
+func (m *CallTrace) HasGateway(gatewayID string) bool {
+	for _, id := range m.Config.GetGatewayIDs() {
+		if id == gatewayID {
+			return true
+		}
+	}
+	return false
+}
+
+// Start sets the state of the call trace to having started on all its
+// gateways at now.
+func (m *CallTrace) Start(now time.Time) {
+	m.State = &CallTraceState{
+		StartTime: strfmt.DateTime(now),
+		Gateways:  map[string]CallTraceGatewayState{},
+	}
+	for _, id := range m.Config.GetGatewayIDs() {
+		m.State.Gateways[id] = CallTraceGatewayState{}
+	}
+}
+
+// GetDeadline returns the time at which the call trace exceeds its time
+// limit. Returns false if the call trace predates time limits.
+func (m *CallTrace) GetDeadline() (time.Time, bool) {
+	if m.State == nil || time.Time(m.State.StartTime).IsZero() {
+		return time.Time{}, false
+	}
+	return time.Time(m.State.StartTime).Add(time.Duration(m.Config.Timeout) * time.Second), true
+}
+
+// IsEnded returns true if the call trace has ended on all its gateways.
+func (m *CallTrace) IsEnded() bool {
+	if m.State == nil {
+		return false
+	}
+	if len(m.State.Gateways) == 0 {
+		// Call traces predating per-gateway state end with the request to
+		// end them
+		return m.State.CallTraceEnding
+	}
+	return !time.Time(m.State.EndTime).IsZero()
+}
+
+// GetGatewayState returns the state of the call trace on the gateway.
+func (m *CallTrace) GetGatewayState(gatewayID string) CallTraceGatewayState {
+	if m.State == nil {
+		return CallTraceGatewayState{}
+	}
+	return m.State.Gateways[gatewayID]
+}
+
+// SetGatewayState sets the state of the call trace on the gateway.
+// Once the call trace has ended on all its gateways, the call trace ends at
+// now, and is available if any gateway captured it successfully.
+func (m *CallTrace) SetGatewayState(gatewayID string, state CallTraceGatewayState, now time.Time) {
+	if m.State == nil {
+		m.State = &CallTraceState{}
+	}
+	if m.State.Gateways == nil {
+		m.State.Gateways = map[string]CallTraceGatewayState{}
+	}
+	m.State.Gateways[gatewayID] = state
+
+	available := false
+	for _, id := range m.Config.GetGatewayIDs() {
+		gw := m.State.Gateways[id]
+		if !gw.Ended {
+			return
+		}
+		available = available || gw.Success
+	}
+	m.State.CallTraceEnding = true
+	m.State.CallTraceAvailable = available
+	if time.Time(m.State.EndTime).IsZero() {
+		m.State.EndTime = strfmt.DateTime(now)
+	}
+}
@@ -15,12 +15,19 @@ package models
 
 import (
 	"github.com/go-openapi/strfmt"
+	"github.com/pkg/errors"
 )
 
 func (m *CallTrace) ValidateModel() error {
 	if err := m.Validate(strfmt.Default); err != nil {
 		return err
 	}
+	if len(m.Config.GetGatewayIDs()) == 0 {
+		return errors.New("call trace must run on at least one gateway")
+	}
+	if m.Config.TraceType == CallTraceConfigTraceTypeSUBSCRIBER && m.Config.Imsi == "" {
+		return errors.New("subscriber call trace must specify an IMSI")
+	}
 	return nil
 }
 
@@ -0,0 +1,344 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pcap reads and merges call trace captures in the pcap and pcapng
+// formats written by TShark.
+package pcap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/bits"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	pcapMagicMicros = 0xa1b2c3d4
+	pcapMagicNanos  = 0xa1b23c4d
+
+	blockTypeSectionHeader        = 0x0a0d0d0a
+	blockTypeInterfaceDescription = 1
+	blockTypeEnhancedPacket       = 6
+	byteOrderMagic                = 0x1a2b3c4d
+
+	optionEndOfOptions = 0
+	optionTSResolution = 9
+	optionTSOffset     = 14
+
+	// maxBlockSize bounds the size of a single record, to fail fast on
+	// corrupt captures rather than allocating arbitrary amounts of memory.
+	maxBlockSize = 16 << 20
+)
+
+// Interface is a capture interface, i.e. the link packets were captured on.
+type Interface struct {
+	LinkType uint16
+	SnapLen  uint32
+
+	// tsUnits is the number of timestamp units per second
+	tsUnits uint64
+	// tsOffset is the number of seconds added to each timestamp
+	tsOffset int64
+}
+
+// Packet is a single captured packet.
+type Packet struct {
+	Interface *Interface
+	Timestamp time.Time
+	// OriginalLength is the length of the packet when it was captured,
+	// which exceeds the length of Data if the packet was truncated.
+	OriginalLength uint32
+	Data           []byte
+}
+
+// Reader reads the packets of a capture in either the pcap or the pcapng
+// format.
+type Reader struct {
+	r     *bufio.Reader
+	order binary.ByteOrder
+	ng    bool
+
+	// interfaces are the interfaces described in the current pcapng section.
+	// Captures in the pcap format have a single interface.
+	interfaces []*Interface
+}
+
+// NewReader returns a reader over the capture in r, after reading the
+// capture's header.
+func NewReader(r io.Reader) (*Reader, error) {
+	rd := &Reader{r: bufio.NewReader(r)}
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(rd.r, magic); err != nil {
+		return nil, errors.Wrap(err, "read capture header")
+	}
+
+	if binary.LittleEndian.Uint32(magic) == blockTypeSectionHeader {
+		rd.ng = true
+		if err := rd.readSectionHeader(); err != nil {
+			return nil, err
+		}
+		return rd, nil
+	}
+
+	tsUnits := uint64(time.Second / time.Microsecond)
+	switch {
+	case binary.LittleEndian.Uint32(magic) == pcapMagicMicros:
+		rd.order = binary.LittleEndian
+	case binary.BigEndian.Uint32(magic) == pcapMagicMicros:
+		rd.order = binary.BigEndian
+	case binary.LittleEndian.Uint32(magic) == pcapMagicNanos:
+		rd.order, tsUnits = binary.LittleEndian, uint64(time.Second)
+	case binary.BigEndian.Uint32(magic) == pcapMagicNanos:
+		rd.order, tsUnits = binary.BigEndian, uint64(time.Second)
+	default:
+		return nil, fmt.Errorf("unrecognized capture format, magic number %x", magic)
+	}
+
+	// Version (4), time zone (4) and significant figures (4) are unused
+	hdr := make([]byte, 20)
+	if _, err := io.ReadFull(rd.r, hdr); err != nil {
+		return nil, errors.Wrap(err, "read pcap header")
+	}
+	rd.interfaces = []*Interface{{
+		SnapLen:  rd.order.Uint32(hdr[12:16]),
+		LinkType: uint16(rd.order.Uint32(hdr[16:20])),
+		tsUnits:  tsUnits,
+	}}
+	return rd, nil
+}
+
+// Next returns the next packet of the capture.
+// Returns io.EOF once all packets have been read.
+func (rd *Reader) Next() (*Packet, error) {
+	if rd.ng {
+		return rd.nextBlock()
+	}
+	return rd.nextRecord()
+}
+
+func (rd *Reader) nextRecord() (*Packet, error) {
+	hdr := make([]byte, 16)
+	if err := readFull(rd.r, hdr); err != nil {
+		return nil, err
+	}
+	capLen := rd.order.Uint32(hdr[8:12])
+	if capLen > maxBlockSize {
+		return nil, fmt.Errorf("packet length %d exceeds maximum of %d", capLen, maxBlockSize)
+	}
+	data := make([]byte, capLen)
+	if _, err := io.ReadFull(rd.r, data); err != nil {
+		return nil, errors.Wrap(unexpectedEOF(err), "read packet")
+	}
+
+	iface := rd.interfaces[0]
+	return &Packet{
+		Interface:      iface,
+		Timestamp:      iface.timestamp(uint64(rd.order.Uint32(hdr[0:4]))*iface.tsUnits + uint64(rd.order.Uint32(hdr[4:8]))),
+		OriginalLength: rd.order.Uint32(hdr[12:16]),
+		Data:           data,
+	}, nil
+}
+
+// nextBlock reads pcapng blocks until the next packet. Blocks other than
+// section headers, interface descriptions and enhanced packets are skipped.
+func (rd *Reader) nextBlock() (*Packet, error) {
+	for {
+		hdr := make([]byte, 4)
+		if err := readFull(rd.r, hdr); err != nil {
+			return nil, err
+		}
+		if binary.LittleEndian.Uint32(hdr) == blockTypeSectionHeader {
+			if err := rd.readSectionHeader(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		blockType := rd.order.Uint32(hdr)
+		body, err := rd.readBlockBody()
+		if err != nil {
+			return nil, err
+		}
+		switch blockType {
+		case blockTypeInterfaceDescription:
+			iface, err := rd.parseInterfaceDescription(body)
+			if err != nil {
+				return nil, err
+			}
+			rd.interfaces = append(rd.interfaces, iface)
+		case blockTypeEnhancedPacket:
+			return rd.parseEnhancedPacket(body)
+		}
+	}
+}
+
+// readSectionHeader reads a section header block, after its block type.
+// Interfaces are scoped to their section, so are reset.
+func (rd *Reader) readSectionHeader() error {
+	hdr := make([]byte, 8)
+	if _, err := io.ReadFull(rd.r, hdr); err != nil {
+		return errors.Wrap(unexpectedEOF(err), "read section header")
+	}
+	switch {
+	case binary.LittleEndian.Uint32(hdr[4:8]) == byteOrderMagic:
+		rd.order = binary.LittleEndian
+	case binary.BigEndian.Uint32(hdr[4:8]) == byteOrderMagic:
+		rd.order = binary.BigEndian
+	default:
+		return fmt.Errorf("invalid pcapng byte-order magic %x", hdr[4:8])
+	}
+	length := rd.order.Uint32(hdr[0:4])
+	if length < 28 || length%4 != 0 || length > maxBlockSize {
+		return fmt.Errorf("invalid pcapng section header length %d", length)
+	}
+	// Skip version, section length and options
+	if _, err := io.CopyN(ioutil.Discard, rd.r, int64(length)-12); err != nil {
+		return errors.Wrap(unexpectedEOF(err), "read section header")
+	}
+	rd.interfaces = nil
+	return nil
+}
+
+// readBlockBody reads the remainder of a block after its block type, and
+// returns the block's body, i.e. without the lengths.
+func (rd *Reader) readBlockBody() ([]byte, error) {
+	lenBytes := make([]byte, 4)
+	if _, err := io.ReadFull(rd.r, lenBytes); err != nil {
+		return nil, errors.Wrap(unexpectedEOF(err), "read block length")
+	}
+	length := rd.order.Uint32(lenBytes)
+	if length < 12 || length%4 != 0 || length > maxBlockSize {
+		return nil, fmt.Errorf("invalid pcapng block length %d", length)
+	}
+	block := make([]byte, length-8)
+	if _, err := io.ReadFull(rd.r, block); err != nil {
+		return nil, errors.Wrap(unexpectedEOF(err), "read block")
+	}
+	return block[:len(block)-4], nil
+}
+
+func (rd *Reader) parseInterfaceDescription(body []byte) (*Interface, error) {
+	if len(body) < 8 {
+		return nil, errors.New("interface description block too short")
+	}
+	iface := &Interface{
+		LinkType: rd.order.Uint16(body[0:2]),
+		SnapLen:  rd.order.Uint32(body[4:8]),
+		tsUnits:  uint64(time.Second / time.Microsecond),
+	}
+
+	opts := body[8:]
+	for len(opts) >= 4 {
+		code, length := rd.order.Uint16(opts[0:2]), int(rd.order.Uint16(opts[2:4]))
+		if code == optionEndOfOptions {
+			break
+		}
+		padded := pad(length)
+		if len(opts) < 4+padded {
+			return nil, errors.New("interface description option exceeds block")
+		}
+		value := opts[4 : 4+length]
+		switch {
+		case code == optionTSResolution && length == 1:
+			units, err := parseTSResolution(value[0])
+			if err != nil {
+				return nil, err
+			}
+			iface.tsUnits = units
+		case code == optionTSOffset && length == 8:
+			iface.tsOffset = int64(rd.order.Uint64(value))
+		}
+		opts = opts[4+padded:]
+	}
+	return iface, nil
+}
+
+func (rd *Reader) parseEnhancedPacket(body []byte) (*Packet, error) {
+	if len(body) < 20 {
+		return nil, errors.New("enhanced packet block too short")
+	}
+	ifaceID := rd.order.Uint32(body[0:4])
+	if int(ifaceID) >= len(rd.interfaces) {
+		return nil, fmt.Errorf("packet references undescribed interface %d", ifaceID)
+	}
+	capLen := rd.order.Uint32(body[12:16])
+	if int(capLen) > len(body)-20 {
+		return nil, errors.New("packet data exceeds block")
+	}
+
+	iface := rd.interfaces[ifaceID]
+	ts := uint64(rd.order.Uint32(body[4:8]))<<32 | uint64(rd.order.Uint32(body[8:12]))
+	data := make([]byte, capLen)
+	copy(data, body[20:])
+	return &Packet{
+		Interface:      iface,
+		Timestamp:      iface.timestamp(ts),
+		OriginalLength: rd.order.Uint32(body[16:20]),
+		Data:           data,
+	}, nil
+}
+
+// timestamp converts a timestamp in the interface's units to a time.
+func (iface *Interface) timestamp(ts uint64) time.Time {
+	secs, frac := ts/iface.tsUnits, ts%iface.tsUnits
+	// frac < tsUnits, so the quotient fits in 64 bits
+	hi, lo := bits.Mul64(frac, uint64(time.Second))
+	nanos, _ := bits.Div64(hi, lo, iface.tsUnits)
+	return time.Unix(int64(secs)+iface.tsOffset, int64(nanos))
+}
+
+// parseTSResolution returns the timestamp units per second for the value of
+// an if_tsresol option. The most significant bit selects between a negative
+// power of 10 and a negative power of 2.
+func parseTSResolution(v byte) (uint64, error) {
+	exp := uint64(v & 0x7f)
+	if v&0x80 != 0 {
+		if exp > 63 {
+			return 0, fmt.Errorf("unsupported timestamp resolution 2^-%d", exp)
+		}
+		return 1 << exp, nil
+	}
+	if exp > 19 {
+		return 0, fmt.Errorf("unsupported timestamp resolution 10^-%d", exp)
+	}
+	units := uint64(1)
+	for i := uint64(0); i < exp; i++ {
+		units *= 10
+	}
+	return units, nil
+}
+
+// readFull reads a record header, returning io.EOF only if the capture ended
+// cleanly before the header.
+func readFull(r io.Reader, buf []byte) error {
+	_, err := io.ReadFull(r, buf)
+	if err == io.EOF {
+		return io.EOF
+	}
+	if err != nil {
+		return errors.Wrap(err, "read record header")
+	}
+	return nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pcap_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/services/ctraced/pcap"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	linkTypeEthernet = 1
+	linkTypeLinuxSLL = 113
+)
+
+func TestReader_Pcap(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		capture := newPcap(order, linkTypeEthernet,
+			pcapRecord{sec: 10, usec: 500, data: []byte("abc")},
+			pcapRecord{sec: 11, usec: 0, data: []byte("defg")},
+		)
+		rd, err := pcap.NewReader(bytes.NewReader(capture))
+		require.NoError(t, err)
+
+		p, err := rd.Next()
+		require.NoError(t, err)
+		assert.Equal(t, time.Unix(10, 500000), p.Timestamp)
+		assert.Equal(t, []byte("abc"), p.Data)
+		assert.Equal(t, uint32(3), p.OriginalLength)
+		assert.Equal(t, uint16(linkTypeEthernet), p.Interface.LinkType)
+		assert.Equal(t, uint32(65535), p.Interface.SnapLen)
+
+		p, err = rd.Next()
+		require.NoError(t, err)
+		assert.Equal(t, time.Unix(11, 0), p.Timestamp)
+		assert.Equal(t, []byte("defg"), p.Data)
+
+		_, err = rd.Next()
+		assert.Equal(t, io.EOF, err)
+	}
+}
+
+func TestReader_Pcapng(t *testing.T) {
+	capture := newPcapng(
+		ngInterface{linkType: linkTypeEthernet},
+		ngInterface{linkType: linkTypeLinuxSLL, tsResolution: 9},
+		ngPacket{iface: 1, ts: 2000000001, data: []byte("b")},
+		ngPacket{iface: 0, ts: 1000001, data: []byte("abcde")},
+	)
+	rd, err := pcap.NewReader(bytes.NewReader(capture))
+	require.NoError(t, err)
+
+	p, err := rd.Next()
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(2, 1), p.Timestamp)
+	assert.Equal(t, uint16(linkTypeLinuxSLL), p.Interface.LinkType)
+	assert.Equal(t, []byte("b"), p.Data)
+
+	p, err = rd.Next()
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1, 1000), p.Timestamp)
+	assert.Equal(t, uint16(linkTypeEthernet), p.Interface.LinkType)
+	assert.Equal(t, []byte("abcde"), p.Data)
+
+	_, err = rd.Next()
+	assert.Equal(t, io.EOF, err)
+
+	// Truncated capture
+	rd, err = pcap.NewReader(bytes.NewReader(capture[:len(capture)-6]))
+	require.NoError(t, err)
+	_, err = rd.Next()
+	require.NoError(t, err)
+	_, err = rd.Next()
+	assert.Error(t, err)
+	assert.NotEqual(t, io.EOF, err)
+
+	_, err = pcap.NewReader(bytes.NewReader([]byte("abcdefghijklmnopqrstuvwxyz")))
+	assert.Error(t, err)
+}
+
+func TestMerge(t *testing.T) {
+	gw1 := newPcap(binary.LittleEndian, linkTypeEthernet,
+		pcapRecord{sec: 1, data: []byte("gw1-1")},
+		pcapRecord{sec: 3, data: []byte("gw1-3")},
+		pcapRecord{sec: 4, data: []byte("gw1-4")},
+	)
+	gw2 := newPcapng(
+		ngInterface{linkType: linkTypeLinuxSLL},
+		ngPacket{iface: 0, ts: 2000000, data: []byte("gw2-2")},
+		ngPacket{iface: 0, ts: 3000000, data: []byte("gw2-3")},
+		ngPacket{iface: 0, ts: 5000000, data: []byte("gw2-5")},
+	)
+	rd1, err := pcap.NewReader(bytes.NewReader(gw1))
+	require.NoError(t, err)
+	rd2, err := pcap.NewReader(bytes.NewReader(gw2))
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, pcap.Merge(buf, rd1, rd2))
+
+	merged, err := pcap.NewReader(buf)
+	require.NoError(t, err)
+	var data []string
+	var linkTypes []uint16
+	for {
+		p, err := merged.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data = append(data, string(p.Data))
+		linkTypes = append(linkTypes, p.Interface.LinkType)
+	}
+	assert.Equal(t, []string{"gw1-1", "gw2-2", "gw1-3", "gw2-3", "gw1-4", "gw2-5"}, data)
+	assert.Equal(t, []uint16{1, 113, 1, 113, 1, 113}, linkTypes)
+
+	// Merging no packets is still a valid capture
+	buf.Reset()
+	require.NoError(t, pcap.Merge(buf))
+	merged, err = pcap.NewReader(buf)
+	require.NoError(t, err)
+	_, err = merged.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+type pcapRecord struct {
+	sec, usec uint32
+	data      []byte
+}
+
+func newPcap(order binary.ByteOrder, linkType uint32, records ...pcapRecord) []byte {
+	buf := &bytes.Buffer{}
+	write := func(vs ...interface{}) {
+		for _, v := range vs {
+			_ = binary.Write(buf, order, v)
+		}
+	}
+	write(uint32(0xa1b2c3d4), uint16(2), uint16(4), int32(0), uint32(0), uint32(65535), linkType)
+	for _, r := range records {
+		write(r.sec, r.usec, uint32(len(r.data)), uint32(len(r.data)), r.data)
+	}
+	return buf.Bytes()
+}
+
+type ngInterface struct {
+	linkType     uint16
+	tsResolution byte
+}
+
+type ngPacket struct {
+	iface uint32
+	ts    uint64
+	data  []byte
+}
+
+func newPcapng(blocks ...interface{}) []byte {
+	buf := &bytes.Buffer{}
+	writeBlock := func(blockType uint32, body []byte) {
+		length := uint32(12 + len(body))
+		_ = binary.Write(buf, binary.LittleEndian, blockType)
+		_ = binary.Write(buf, binary.LittleEndian, length)
+		buf.Write(body)
+		_ = binary.Write(buf, binary.LittleEndian, length)
+	}
+	le := func(vs ...interface{}) []byte {
+		b := &bytes.Buffer{}
+		for _, v := range vs {
+			_ = binary.Write(b, binary.LittleEndian, v)
+		}
+		return b.Bytes()
+	}
+
+	writeBlock(0x0a0d0d0a, le(uint32(0x1a2b3c4d), uint16(1), uint16(0), int64(-1)))
+	for _, block := range blocks {
+		switch b := block.(type) {
+		case ngInterface:
+			body := le(b.linkType, uint16(0), uint32(262144))
+			if b.tsResolution != 0 {
+				body = append(body, le(uint16(9), uint16(1), b.tsResolution, [3]byte{})...)
+			}
+			writeBlock(1, append(body, le(uint32(0))...))
+		case ngPacket:
+			padded := make([]byte, (len(b.data)+3)&^3)
+			copy(padded, b.data)
+			body := le(b.iface, uint32(b.ts>>32), uint32(b.ts), uint32(len(b.data)), uint32(len(b.data)), padded)
+			writeBlock(6, body)
+		}
+	}
+	return buf.Bytes()
+}
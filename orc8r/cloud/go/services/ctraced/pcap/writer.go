@@ -0,0 +1,210 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pcap
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// tsResolutionNanos is the if_tsresol option value for nanosecond timestamps
+const tsResolutionNanos = 9
+
+// Writer writes packets as a capture in the pcapng format, with nanosecond
+// timestamps. Each distinct interface of the written packets is described
+// before its first packet.
+type Writer struct {
+	w          io.Writer
+	started    bool
+	interfaces map[*Interface]uint32
+}
+
+// NewWriter returns a writer of a pcapng capture to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w, interfaces: map[*Interface]uint32{}}
+}
+
+// WritePacket writes the packet to the capture.
+func (w *Writer) WritePacket(p *Packet) error {
+	if !w.started {
+		if err := w.writeSectionHeader(); err != nil {
+			return err
+		}
+		w.started = true
+	}
+	ifaceID, ok := w.interfaces[p.Interface]
+	if !ok {
+		ifaceID = uint32(len(w.interfaces))
+		if err := w.writeInterfaceDescription(p.Interface); err != nil {
+			return err
+		}
+		w.interfaces[p.Interface] = ifaceID
+	}
+
+	ts := uint64(p.Timestamp.UnixNano())
+	body := make([]byte, 20+pad(len(p.Data)))
+	binary.LittleEndian.PutUint32(body[0:4], ifaceID)
+	binary.LittleEndian.PutUint32(body[4:8], uint32(ts>>32))
+	binary.LittleEndian.PutUint32(body[8:12], uint32(ts))
+	binary.LittleEndian.PutUint32(body[12:16], uint32(len(p.Data)))
+	binary.LittleEndian.PutUint32(body[16:20], p.OriginalLength)
+	copy(body[20:], p.Data)
+	return w.writeBlock(blockTypeEnhancedPacket, body)
+}
+
+// Flush writes the capture's header if no packets were written, so an empty
+// capture is still a valid one.
+func (w *Writer) Flush() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	return w.writeSectionHeader()
+}
+
+func (w *Writer) writeSectionHeader() error {
+	body := make([]byte, 16)
+	binary.LittleEndian.PutUint32(body[0:4], byteOrderMagic)
+	binary.LittleEndian.PutUint16(body[4:6], 1)
+	binary.LittleEndian.PutUint16(body[6:8], 0)
+	// Section length is unspecified
+	binary.LittleEndian.PutUint64(body[8:16], ^uint64(0))
+	return w.writeBlock(blockTypeSectionHeader, body)
+}
+
+func (w *Writer) writeInterfaceDescription(iface *Interface) error {
+	body := make([]byte, 20)
+	binary.LittleEndian.PutUint16(body[0:2], iface.LinkType)
+	binary.LittleEndian.PutUint32(body[4:8], iface.SnapLen)
+	binary.LittleEndian.PutUint16(body[8:10], optionTSResolution)
+	binary.LittleEndian.PutUint16(body[10:12], 1)
+	body[12] = tsResolutionNanos
+	// Followed by the end of options
+	return w.writeBlock(blockTypeInterfaceDescription, body)
+}
+
+func (w *Writer) writeBlock(blockType uint32, body []byte) error {
+	length := uint32(12 + len(body))
+	block := make([]byte, length)
+	binary.LittleEndian.PutUint32(block[0:4], blockType)
+	binary.LittleEndian.PutUint32(block[4:8], length)
+	copy(block[8:], body)
+	binary.LittleEndian.PutUint32(block[length-4:], length)
+	_, err := w.w.Write(block)
+	return errors.Wrap(err, "write capture")
+}
+
+// Merge writes the packets of the captures to w as a single pcapng capture,
+// ordered by timestamp. Packets with equal timestamps are ordered by the
+// position of their capture in captures.
+// Captures are read incrementally, so the merged capture is streamed.
+func Merge(w io.Writer, captures ...*Reader) error {
+	merged, err := NewMergeReader(captures...)
+	if err != nil {
+		return err
+	}
+	out := NewWriter(w)
+	for {
+		p, _, err := merged.Next()
+		if err == io.EOF {
+			return out.Flush()
+		}
+		if err != nil {
+			return err
+		}
+		if err := out.WritePacket(p); err != nil {
+			return err
+		}
+	}
+}
+
+// MergeReader reads the packets of several captures in the order Merge
+// writes them.
+type MergeReader struct {
+	captures []*Reader
+	pending  *packetHeap
+}
+
+// NewMergeReader returns a reader over the merged packets of the captures.
+func NewMergeReader(captures ...*Reader) (*MergeReader, error) {
+	m := &MergeReader{captures: captures, pending: &packetHeap{}}
+	for i, c := range captures {
+		if err := m.pending.pushNext(i, c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Next returns the next packet across all captures, along with the position
+// of the packet's capture in captures.
+// Returns io.EOF once all packets have been read.
+func (m *MergeReader) Next() (*Packet, int, error) {
+	if m.pending.Len() == 0 {
+		return nil, 0, io.EOF
+	}
+	next := heap.Pop(m.pending).(sourcedPacket)
+	if err := m.pending.pushNext(next.source, m.captures[next.source]); err != nil {
+		return nil, 0, err
+	}
+	return next.packet, next.source, nil
+}
+
+type sourcedPacket struct {
+	packet *Packet
+	source int
+}
+
+type packetHeap []sourcedPacket
+
+func (h packetHeap) Len() int { return len(h) }
+
+func (h packetHeap) Less(i, j int) bool {
+	ti, tj := h[i].packet.Timestamp, h[j].packet.Timestamp
+	if ti.Equal(tj) {
+		return h[i].source < h[j].source
+	}
+	return ti.Before(tj)
+}
+
+func (h packetHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *packetHeap) Push(x interface{}) { *h = append(*h, x.(sourcedPacket)) }
+
+func (h *packetHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// pushNext pushes the next packet of the capture, if any.
+func (h *packetHeap) pushNext(source int, capture *Reader) error {
+	p, err := capture.Next()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "read capture %d", source)
+	}
+	heap.Push(h, sourcedPacket{packet: p, source: source})
+	return nil
+}
+
+func pad(n int) int {
+	return (n + 3) &^ 3
+}
@@ -15,83 +15,67 @@ package servicers
 
 import (
 	"context"
-	"fmt"
 
-	"magma/orc8r/cloud/go/orc8r"
-	"magma/orc8r/cloud/go/serdes"
-	"magma/orc8r/cloud/go/services/configurator"
-	"magma/orc8r/cloud/go/services/ctraced/obsidian/models"
-	"magma/orc8r/cloud/go/services/ctraced/storage"
+	"magma/orc8r/cloud/go/services/ctraced/lifecycle"
 	merrors "magma/orc8r/lib/go/errors"
 	"magma/orc8r/lib/go/protos"
 
+	"github.com/golang/glog"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type callTraceServicer struct {
-	storage storage.CtracedStorage
+	recorder *lifecycle.Recorder
 }
 
-func NewCallTraceServicer(storage storage.CtracedStorage) protos.CallTraceControllerServer {
-	return &callTraceServicer{storage: storage}
+func NewCallTraceServicer(recorder *lifecycle.Recorder) protos.CallTraceControllerServer {
+	return &callTraceServicer{recorder: recorder}
 }
 
 func (srv *callTraceServicer) ReportEndedCallTrace(ctx context.Context, req *protos.ReportEndedTraceRequest) (*protos.ReportEndedTraceResponse, error) {
-	networkID, err := getNetworkID(ctx)
+	networkID, gatewayID, err := getGatewayID(ctx)
 	if err != nil {
 		return nil, err
 	}
-	callTrace, err := getCallTraceModel(networkID, req.TraceId)
+	_, err = srv.recorder.EndGatewayTrace(networkID, req.TraceId, gatewayID, req.Success, req.TraceContent, req.ChunkCount)
 	if err != nil {
-		return nil, err
+		return nil, mapRecorderError(err, networkID, gatewayID, req.TraceId)
 	}
+	return &protos.ReportEndedTraceResponse{}, nil
+}
 
-	err = srv.storage.StoreCallTrace(networkID, req.TraceId, req.TraceContent)
+func (srv *callTraceServicer) ReportCallTraceChunk(ctx context.Context, req *protos.ReportCallTraceChunkRequest) (*protos.ReportCallTraceChunkResponse, error) {
+	networkID, gatewayID, err := getGatewayID(ctx)
 	if err != nil {
-		return nil, status.Errorf(codes.Aborted, fmt.Sprintf("failed to save call trace data, network-id: %s, gateway-id: %s, calltrace-id: %s", networkID, callTrace.Config.GatewayID, req.TraceId))
-	}
-
-	callTrace.State.CallTraceEnding = req.Success
-	callTrace.State.CallTraceAvailable = req.Success
-
-	update := configurator.EntityUpdateCriteria{
-		Type:      orc8r.CallTraceEntityType,
-		Key:       req.TraceId,
-		NewConfig: callTrace,
+		return nil, err
 	}
-
-	_, err = configurator.UpdateEntity(networkID, update, serdes.Entity)
+	err = srv.recorder.StoreChunk(networkID, req.TraceId, gatewayID, req.Index, req.Content)
 	if err != nil {
-		return nil, status.Errorf(codes.Aborted, fmt.Sprintf("failed to update call trace, network-id: %s, gateway-id: %s, calltrace-id: %s", networkID, callTrace.Config.GatewayID, req.TraceId))
+		return nil, mapRecorderError(err, networkID, gatewayID, req.TraceId)
 	}
-	return &protos.ReportEndedTraceResponse{}, nil
+	return &protos.ReportCallTraceChunkResponse{}, nil
 }
 
-func getNetworkID(ctx context.Context) (string, error) {
+func getGatewayID(ctx context.Context) (string, string, error) {
 	id, err := protos.GetGatewayIdentity(ctx)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
-	return id.GetNetworkId(), nil
+	return id.GetNetworkId(), id.GetLogicalId(), nil
 }
 
-func getCallTraceModel(networkID string, callTraceID string) (*models.CallTrace, error) {
-	ent, err := configurator.LoadEntity(
-		networkID, orc8r.CallTraceEntityType, callTraceID,
-		configurator.EntityLoadCriteria{LoadConfig: true},
-		serdes.Entity,
-	)
-	if err == merrors.ErrNotFound {
-		return nil, status.Errorf(codes.InvalidArgument, "Call trace not found")
-	}
-	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "Failed to load call trace")
-	}
-	callTrace := &models.CallTrace{}
-	err = callTrace.FromBackendModels(ent)
-	if err != nil {
-		return nil, status.Errorf(codes.Aborted, "Failed to load call trace")
+func mapRecorderError(err error, networkID string, gatewayID string, callTraceID string) error {
+	switch err {
+	case merrors.ErrNotFound:
+		return status.Errorf(codes.InvalidArgument, "Call trace not found")
+	case lifecycle.ErrUnknownGateway:
+		return status.Errorf(codes.PermissionDenied, "Call trace %s does not run on gateway %s", callTraceID, gatewayID)
+	case lifecycle.ErrEnded, lifecycle.ErrChunkOutOfOrder, lifecycle.ErrChunkCountMismatch:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case lifecycle.ErrTooLarge:
+		return status.Error(codes.ResourceExhausted, err.Error())
 	}
-	return callTrace, nil
+	glog.Errorf("Failed to save call trace data, network-id: %s, gateway-id: %s, calltrace-id: %s: %+v", networkID, gatewayID, callTraceID, err)
+	return status.Errorf(codes.Aborted, "failed to save call trace data, network-id: %s, gateway-id: %s, calltrace-id: %s", networkID, gatewayID, callTraceID)
 }
@@ -16,10 +16,12 @@ package servicers_test
 import (
 	"testing"
 
+	"magma/orc8r/cloud/go/clock"
 	"magma/orc8r/cloud/go/orc8r"
 	"magma/orc8r/cloud/go/serdes"
 	"magma/orc8r/cloud/go/services/configurator"
 	"magma/orc8r/cloud/go/services/configurator/test_init"
+	"magma/orc8r/cloud/go/services/ctraced/lifecycle"
 	models "magma/orc8r/cloud/go/services/ctraced/obsidian/models"
 	"magma/orc8r/cloud/go/services/ctraced/servicers"
 	"magma/orc8r/cloud/go/services/ctraced/storage"
@@ -46,7 +48,7 @@ func TestCallTraceServicer(t *testing.T) {
 	// Create a call trace
 	testTraceCfg := &models.CallTraceConfig{
 		TraceID:   "CallTrace1",
-		GatewayID: "test_gateway_id",
+		GatewayID: testGwLogicalId,
 		Timeout:   300,
 		TraceType: models.CallTraceConfigTraceTypeGATEWAY,
 	}
@@ -76,7 +78,7 @@ func TestCallTraceServicer(t *testing.T) {
 
 	fact := test_utils.NewSQLBlobstore(t, "ctraced_trace_servicer_test_blobstore")
 	blobstore := storage.NewCtracedBlobstore(fact)
-	srv := servicers.NewCallTraceServicer(blobstore)
+	srv := servicers.NewCallTraceServicer(lifecycle.NewRecorder(blobstore, 1024))
 
 	// Missing subscriber ID
 	req := &protos.ReportEndedTraceRequest{TraceId: "CallTrace0", Success: true, TraceContent: []byte("abcdefghijklmnopqrstuvwxyz\n")}
@@ -98,4 +100,87 @@ func TestCallTraceServicer(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, true, testCallTrace.State.CallTraceAvailable)
 	assert.Equal(t, true, testCallTrace.State.CallTraceEnding)
+	assert.Equal(t, models.CallTraceGatewayState{Ended: true, Success: true, Chunks: 1, Size: 27}, testCallTrace.State.Gateways[testGwLogicalId])
+
+	// Reporting a call trace twice fails
+	_, err = srv.ReportEndedCallTrace(ctx, req)
+	assert.EqualError(t, err, "rpc error: code = FailedPrecondition desc = call trace already ended on gateway")
+
+	// Gateways can only report their own call traces
+	otherID := protos.Identity{}
+	otherID.SetGateway(&protos.Identity_Gateway{HardwareId: "hw2", NetworkId: testNetworkId, LogicalId: "g2"})
+	otherCtx := otherID.NewContextWithIdentity(context.Background())
+	_, err = srv.ReportEndedCallTrace(otherCtx, req)
+	assert.EqualError(t, err, "rpc error: code = PermissionDenied desc = Call trace CallTrace1 does not run on gateway g2")
+}
+
+func TestCallTraceServicer_Chunks(t *testing.T) {
+	test_init.StartTestService(t)
+	deviceTestInit.StartTestService(t)
+
+	err := configurator.CreateNetwork(configurator.Network{ID: "n1"}, serdes.Network)
+	assert.NoError(t, err)
+	trace := &models.CallTrace{
+		Config: &models.CallTraceConfig{
+			TraceID:    "CallTrace1",
+			GatewayID:  "g1",
+			GatewayIds: []string{"g2"},
+			Timeout:    300,
+			TraceType:  models.CallTraceConfigTraceTypeGATEWAY,
+		},
+	}
+	trace.Start(clock.Now())
+	_, err = configurator.CreateEntity("n1", trace.ToEntity(), serdes.Entity)
+	assert.NoError(t, err)
+
+	ctxs := map[string]context.Context{}
+	for _, gatewayID := range []string{"g1", "g2"} {
+		id := protos.Identity{}
+		id.SetGateway(&protos.Identity_Gateway{HardwareId: "hw_" + gatewayID, NetworkId: "n1", LogicalId: gatewayID})
+		ctxs[gatewayID] = id.NewContextWithIdentity(context.Background())
+	}
+
+	fact := test_utils.NewSQLBlobstore(t, "ctraced_trace_servicer_chunks_test_blobstore")
+	blobstore := storage.NewCtracedBlobstore(fact)
+	srv := servicers.NewCallTraceServicer(lifecycle.NewRecorder(blobstore, 10))
+
+	// Chunks must be uploaded in order, and retried chunks are ignored
+	_, err = srv.ReportCallTraceChunk(ctxs["g1"], &protos.ReportCallTraceChunkRequest{TraceId: "CallTrace1", Index: 1, Content: []byte("def")})
+	assert.EqualError(t, err, "rpc error: code = FailedPrecondition desc = call trace chunk uploaded out of order")
+	for i, content := range []string{"abc", "def", "def"} {
+		index := uint32(i)
+		if i == 2 {
+			index = 1
+		}
+		_, err = srv.ReportCallTraceChunk(ctxs["g1"], &protos.ReportCallTraceChunkRequest{TraceId: "CallTrace1", Index: index, Content: []byte(content)})
+		assert.NoError(t, err)
+	}
+
+	// Captures are bounded in size
+	_, err = srv.ReportCallTraceChunk(ctxs["g1"], &protos.ReportCallTraceChunkRequest{TraceId: "CallTrace1", Index: 2, Content: []byte("ghijk")})
+	assert.EqualError(t, err, "rpc error: code = ResourceExhausted desc = call trace exceeds maximum size")
+
+	// The chunk count must match the chunks uploaded
+	_, err = srv.ReportEndedCallTrace(ctxs["g1"], &protos.ReportEndedTraceRequest{TraceId: "CallTrace1", Success: true, ChunkCount: 3})
+	assert.EqualError(t, err, "rpc error: code = FailedPrecondition desc = call trace chunk count does not match chunks uploaded")
+	_, err = srv.ReportEndedCallTrace(ctxs["g1"], &protos.ReportEndedTraceRequest{TraceId: "CallTrace1", Success: true, ChunkCount: 2})
+	assert.NoError(t, err)
+
+	// The call trace doesn't end until it ended on all its gateways
+	trace, _, err = lifecycle.LoadCallTrace("n1", "CallTrace1")
+	assert.NoError(t, err)
+	assert.False(t, trace.IsEnded())
+	assert.Equal(t, models.CallTraceGatewayState{Ended: true, Success: true, Chunks: 2, Size: 6}, trace.State.Gateways["g1"])
+
+	_, err = srv.ReportEndedCallTrace(ctxs["g2"], &protos.ReportEndedTraceRequest{TraceId: "CallTrace1", Success: false})
+	assert.NoError(t, err)
+	trace, _, err = lifecycle.LoadCallTrace("n1", "CallTrace1")
+	assert.NoError(t, err)
+	assert.True(t, trace.IsEnded())
+	assert.True(t, trace.State.CallTraceAvailable)
+	assert.Equal(t, models.CallTraceGatewayState{Ended: true}, trace.State.Gateways["g2"])
+
+	chunk, err := blobstore.GetCallTraceChunk("n1", "CallTrace1", "g1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("def"), chunk)
 }
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// NewCallTraceReader returns a reader over a gateway's capture for the call
+// trace, which fetches the capture's chunks one at a time as it's read.
+func NewCallTraceReader(store CtracedStorage, networkID string, callTraceID string, gatewayID string, chunkCount uint32) io.Reader {
+	return &chunkReader{
+		store:       store,
+		networkID:   networkID,
+		callTraceID: callTraceID,
+		gatewayID:   gatewayID,
+		chunkCount:  chunkCount,
+	}
+}
+
+type chunkReader struct {
+	store       CtracedStorage
+	networkID   string
+	callTraceID string
+	gatewayID   string
+	chunkCount  uint32
+
+	next    uint32
+	current []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.current) == 0 {
+		if r.next >= r.chunkCount {
+			return 0, io.EOF
+		}
+		chunk, err := r.store.GetCallTraceChunk(r.networkID, r.callTraceID, r.gatewayID, r.next)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to read chunk %d of gateway %s's capture", r.next, r.gatewayID)
+		}
+		r.current = chunk
+		r.next++
+	}
+	n := copy(p, r.current)
+	r.current = r.current[n:]
+	return n, nil
+}
@@ -16,6 +16,10 @@ package storage
 // CtracedStorage is the persistence service interface for call traces.
 // All call trace accesses from ctraced service must go through this interface.
 // Call traces should be stored as .pcap files
+//
+// Each gateway's capture of a call trace is stored as a sequence of chunks,
+// numbered from 0. Call traces stored as a single file predate chunked
+// storage.
 type CtracedStorage interface {
 
 	// StoreCallTrace stores the call trace file
@@ -24,7 +28,13 @@ type CtracedStorage interface {
 	// GetCallTrace returns the call trace file
 	GetCallTrace(networkID string, callTraceID string) ([]byte, error)
 
-	// DeleteCallTrace deletes the call trace file
-	// Returns an error if the call trace does not exist
+	// StoreCallTraceChunk stores a chunk of a gateway's capture for the call trace
+	StoreCallTraceChunk(networkID string, callTraceID string, gatewayID string, index uint32, data []byte) error
+
+	// GetCallTraceChunk returns a chunk of a gateway's capture for the call trace
+	// Returns ErrNotFound if the chunk does not exist
+	GetCallTraceChunk(networkID string, callTraceID string, gatewayID string, index uint32) ([]byte, error)
+
+	// DeleteCallTrace deletes the call trace file and all chunks of the call trace
 	DeleteCallTrace(networkID string, callTraceID string) error
 }
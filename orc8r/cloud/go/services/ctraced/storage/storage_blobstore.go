@@ -29,6 +29,9 @@ const (
 
 	// CtracedBlobType is the blobstore type field for call traces
 	CtracedBlobType = "call_trace"
+
+	// CtracedChunkBlobType is the blobstore type field for call trace chunks
+	CtracedChunkBlobType = "call_trace_chunk"
 )
 
 // NewCtracedBlobstore returns a ctraced storage implementation
@@ -84,23 +87,84 @@ func (c *ctracedBlobStore) GetCallTrace(networkID string, callTraceID string) ([
 	return blob.Value, store.Commit()
 }
 
-// DeleteCallTrace
-func (c *ctracedBlobStore) DeleteCallTrace(networkID string, callTraceID string) error {
-	store, err := c.factory.StartTransaction(&storage.TxOptions{ReadOnly: true})
+// StoreCallTraceChunk
+func (c *ctracedBlobStore) StoreCallTraceChunk(networkID string, callTraceID string, gatewayID string, index uint32, data []byte) error {
+	store, err := c.factory.StartTransaction(nil)
 	if err != nil {
 		return errors.Wrap(err, "failed to start transaction")
 	}
 	defer store.Rollback()
 
-	err = store.Delete(
+	err = store.CreateOrUpdate(
 		networkID,
-		[]storage.TypeAndKey{
-			{Type: CtracedBlobType, Key: callTraceID},
+		blobstore.Blobs{
+			{Type: CtracedChunkBlobType, Key: getChunkKey(callTraceID, gatewayID, index), Value: data, Version: 0},
 		},
 	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to store chunk %d of call trace %s", index, callTraceID))
+	}
+
+	return store.Commit()
+}
+
+// GetCallTraceChunk
+func (c *ctracedBlobStore) GetCallTraceChunk(networkID string, callTraceID string, gatewayID string, index uint32) ([]byte, error) {
+	store, err := c.factory.StartTransaction(&storage.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start transaction")
+	}
+	defer store.Rollback()
+
+	blob, err := store.Get(
+		networkID,
+		storage.TypeAndKey{Type: CtracedChunkBlobType, Key: getChunkKey(callTraceID, gatewayID, index)},
+	)
+	if err == merrors.ErrNotFound {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to get chunk %d of call trace %s", index, callTraceID))
+	}
+
+	return blob.Value, store.Commit()
+}
+
+// DeleteCallTrace
+func (c *ctracedBlobStore) DeleteCallTrace(networkID string, callTraceID string) error {
+	store, err := c.factory.StartTransaction(nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer store.Rollback()
+
+	chunkPrefix := getChunkKeyPrefix(callTraceID)
+	chunks, err := store.Search(
+		blobstore.CreateSearchFilter(&networkID, []string{CtracedChunkBlobType}, nil, &chunkPrefix),
+		blobstore.LoadCriteria{LoadValue: false},
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to list chunks of call trace %s", callTraceID))
+	}
+
+	tks := []storage.TypeAndKey{{Type: CtracedBlobType, Key: callTraceID}}
+	for _, chunk := range chunks[networkID] {
+		tks = append(tks, storage.TypeAndKey{Type: CtracedChunkBlobType, Key: chunk.Key})
+	}
+	err = store.Delete(networkID, tks)
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("failed to delete call trace %s", callTraceID))
 	}
 
 	return store.Commit()
 }
+
+// getChunkKey returns the blobstore key of a chunk.
+// Trace IDs are URL path parameters, so can't contain the separator.
+func getChunkKey(callTraceID string, gatewayID string, index uint32) string {
+	return fmt.Sprintf("%s%s/%d", getChunkKeyPrefix(callTraceID), gatewayID, index)
+}
+
+func getChunkKeyPrefix(callTraceID string) string {
+	return callTraceID + "/"
+}
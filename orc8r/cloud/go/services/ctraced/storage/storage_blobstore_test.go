@@ -15,16 +15,19 @@ package storage_test
 
 import (
 	"errors"
+	"io/ioutil"
 	"testing"
 
 	"magma/orc8r/cloud/go/blobstore"
 	"magma/orc8r/cloud/go/blobstore/mocks"
 	cstorage "magma/orc8r/cloud/go/services/ctraced/storage"
 	"magma/orc8r/cloud/go/storage"
+	"magma/orc8r/cloud/go/test_utils"
 	merrors "magma/orc8r/lib/go/errors"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -181,6 +184,7 @@ func TestCtracedBlobstoreStorage_DeleteCallTrace(t *testing.T) {
 	blobStoreMock = &mocks.TransactionalBlobStorage{}
 	blobFactMock.On("StartTransaction", mock.Anything).Return(blobStoreMock, nil).Once()
 	blobStoreMock.On("Rollback").Return(nil).Once()
+	blobStoreMock.On("Search", mock.Anything, mock.Anything).Return(map[string]blobstore.Blobs{}, nil).Once()
 	blobStoreMock.On("Delete", placeholderNetworkID, tkSet).Return(nil).Once()
 	blobStoreMock.On("Commit").Return(nil).Once()
 	store = cstorage.NewCtracedBlobstore(blobFactMock)
@@ -190,3 +194,54 @@ func TestCtracedBlobstoreStorage_DeleteCallTrace(t *testing.T) {
 	blobFactMock.AssertExpectations(t)
 	blobStoreMock.AssertExpectations(t)
 }
+
+func TestCtracedBlobstoreStorage_Chunks(t *testing.T) {
+	fact := test_utils.NewSQLBlobstore(t, "ctraced_storage_chunks_test_blobstore")
+	store := cstorage.NewCtracedBlobstore(fact)
+
+	require.NoError(t, store.StoreCallTraceChunk(placeholderNetworkID, "trace1", "gw1", 0, []byte("abc")))
+	require.NoError(t, store.StoreCallTraceChunk(placeholderNetworkID, "trace1", "gw1", 1, []byte("defg")))
+	require.NoError(t, store.StoreCallTraceChunk(placeholderNetworkID, "trace1", "gw2", 0, []byte("xyz")))
+	require.NoError(t, store.StoreCallTraceChunk(placeholderNetworkID, "trace10", "gw1", 0, []byte("other")))
+	require.NoError(t, store.StoreCallTrace(placeholderNetworkID, "trace1", []byte("legacy")))
+
+	chunk, err := store.GetCallTraceChunk(placeholderNetworkID, "trace1", "gw1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("defg"), chunk)
+	_, err = store.GetCallTraceChunk(placeholderNetworkID, "trace1", "gw1", 2)
+	assert.Equal(t, merrors.ErrNotFound, err)
+
+	// Chunks are read in order
+	data, err := ioutil.ReadAll(cstorage.NewCallTraceReader(store, placeholderNetworkID, "trace1", "gw1", 2))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("abcdefg"), data)
+	_, err = ioutil.ReadAll(cstorage.NewCallTraceReader(store, placeholderNetworkID, "trace1", "gw1", 3))
+	assert.Error(t, err)
+
+	// Deleting a call trace deletes all its chunks, and only its chunks
+	require.NoError(t, store.DeleteCallTrace(placeholderNetworkID, "trace1"))
+	_, err = store.GetCallTraceChunk(placeholderNetworkID, "trace1", "gw1", 0)
+	assert.Equal(t, merrors.ErrNotFound, err)
+	_, err = store.GetCallTraceChunk(placeholderNetworkID, "trace1", "gw2", 0)
+	assert.Equal(t, merrors.ErrNotFound, err)
+	_, err = store.GetCallTrace(placeholderNetworkID, "trace1")
+	assert.Equal(t, merrors.ErrNotFound, err)
+	chunk, err = store.GetCallTraceChunk(placeholderNetworkID, "trace10", "gw1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("other"), chunk)
+
+	// LIKE wildcards in trace IDs only match themselves
+	require.NoError(t, store.StoreCallTraceChunk(placeholderNetworkID, "a_c", "gw1", 0, []byte("wildcard")))
+	require.NoError(t, store.StoreCallTraceChunk(placeholderNetworkID, "abc", "gw1", 0, []byte("abc")))
+	require.NoError(t, store.StoreCallTraceChunk(placeholderNetworkID, "%", "gw1", 0, []byte("percent")))
+	require.NoError(t, store.DeleteCallTrace(placeholderNetworkID, "a_c"))
+	require.NoError(t, store.DeleteCallTrace(placeholderNetworkID, "%"))
+	_, err = store.GetCallTraceChunk(placeholderNetworkID, "a_c", "gw1", 0)
+	assert.Equal(t, merrors.ErrNotFound, err)
+	chunk, err = store.GetCallTraceChunk(placeholderNetworkID, "abc", "gw1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("abc"), chunk)
+	chunk, err = store.GetCallTraceChunk(placeholderNetworkID, "trace10", "gw1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("other"), chunk)
+}
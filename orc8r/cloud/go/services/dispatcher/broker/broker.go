@@ -54,6 +54,15 @@ type GatewayRPCBroker interface {
 	CancelGatewayRequest(gwId string, reqId uint32) error
 }
 
+// RequestAcker is a GatewayRPCBroker which is notified once a request from a
+// gateway's request queue is sent to the gateway, e.g. to only then delete
+// its persisted copy.
+type RequestAcker interface {
+	// AckRequest is called by the SyncRPC servicer after req, received from
+	// the gateway's request queue, is written to the gateway's stream.
+	AckRequest(gwId string, req *protos.SyncRPCRequest) error
+}
+
 // GatewayRPCBrokerImpl implements a GatewayRPCBroker, managing a response table and request queue.
 type GatewayRPCBrokerImpl struct {
 	responseTable memstore.ResponseTable
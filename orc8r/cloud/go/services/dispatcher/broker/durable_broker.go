@@ -0,0 +1,443 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/sqorc"
+	"magma/orc8r/lib/go/protos"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// DefaultPollInterval is the default interval at which a durable broker
+	// polls shared storage for requests and responses.
+	DefaultPollInterval = 100 * time.Millisecond
+	// maxIdlePollInterval is the max interval to which polling backs off
+	// while there are no requests or responses to deliver.
+	maxIdlePollInterval = time.Second
+
+	// requestDeadline is how long requests, and responses, are kept before
+	// expiring. Matches the HTTP server's response timeout.
+	requestDeadline = 15 * time.Second
+	// sweepInterval is how often expired requests and responses are deleted.
+	sweepInterval = 10 * time.Second
+	// claimLimit is the max number of requests claimed per poll.
+	claimLimit = 100
+	// claimTimeout is how long a claimed request has to be sent to its
+	// gateway before it can be claimed again, e.g. after a replica restart.
+	claimTimeout = 5 * time.Second
+	// reqIDBlockSize is the number of request IDs reserved by a replica at a
+	// time.
+	reqIDBlockSize = 1000
+)
+
+// DurableGatewayRPCBroker implements a GatewayRPCBroker whose requests and
+// responses are exchanged through shared storage, supporting multiple
+// dispatcher replicas.
+//
+// Requests are persisted with a deadline until sent to the gateway by the
+// replica holding the gateway's SyncRPC stream, which may differ from the
+// replica that received the request, and may change across gateway
+// reconnects and replica restarts. Requests claimed by a replica which fails
+// to send them are claimed again after claimTimeout, so delivery is
+// at-least-once. Requests for gateways whose stream is held by the receiving
+// replica are queued directly, without a database round trip. Responses are
+// persisted until claimed by the replica awaiting them.
+//
+// Request IDs are allocated from a sequence in storage, in blocks reserved by
+// each replica, so requests from different replicas never share an ID, and
+// their responses are claimed by the replica which sent them. IDs wrap
+// around after 2^32 requests, long after their requests expire.
+//
+// Run must be called for requests and responses to be delivered.
+type DurableGatewayRPCBroker struct {
+	store        *sqlStore
+	pollInterval time.Duration
+
+	queuesMu sync.RWMutex
+	// queues holds the request queue of each gateway whose SyncRPC stream is
+	// held by this replica.
+	queues map[string]chan *protos.SyncRPCRequest
+
+	claimedMu sync.Mutex
+	// claimed holds the requests claimed from storage which are queued but
+	// not yet sent to their gateway.
+	claimed map[*protos.SyncRPCRequest]gatewayRequest
+
+	awaitingMu sync.Mutex
+	// awaiting holds the response channels of requests sent from this
+	// replica, keyed by request ID.
+	awaiting map[uint32]*awaitingResponse
+
+	reqIDMu sync.Mutex
+	// nextReqID and lastReqID bound the remainder of the block of request
+	// IDs reserved by this replica.
+	nextReqID uint64
+	lastReqID uint64
+}
+
+type awaitingResponse struct {
+	respChan chan *protos.GatewayResponse
+	deadline time.Time
+}
+
+// NewDurableGatewayRPCBroker returns a broker backed by the passed database.
+func NewDurableGatewayRPCBroker(db *sql.DB, builder sqorc.StatementBuilder, pollInterval time.Duration) *DurableGatewayRPCBroker {
+	return &DurableGatewayRPCBroker{
+		store:        &sqlStore{db: db, builder: builder},
+		pollInterval: pollInterval,
+		queues:       map[string]chan *protos.SyncRPCRequest{},
+		claimed:      map[*protos.SyncRPCRequest]gatewayRequest{},
+		awaiting:     map[uint32]*awaitingResponse{},
+		nextReqID:    1,
+	}
+}
+
+// Initialize the broker's storage.
+// Call before other methods.
+func (broker *DurableGatewayRPCBroker) Initialize() error {
+	return broker.store.initialize()
+}
+
+// Run periodically delivers requests to the gateways connected to this
+// replica, and responses to the requests sent from this replica.
+// Polling backs off, up to maxIdlePollInterval, while idle.
+// Returns only upon context cancellation.
+func (broker *DurableGatewayRPCBroker) Run(ctx context.Context) {
+	var lastSweep time.Time
+	interval := broker.pollInterval
+	for {
+		select {
+		case <-ctx.Done():
+			glog.Warning("Dispatcher broker async job canceled")
+			return
+		case <-time.After(interval):
+		}
+
+		if broker.Poll() {
+			interval = broker.pollInterval
+		} else if interval *= 2; interval > maxIdlePollInterval {
+			interval = maxIdlePollInterval
+		}
+		if clock.Since(lastSweep) > sweepInterval {
+			broker.sweep()
+			lastSweep = clock.Now()
+		}
+	}
+}
+
+// Poll delivers any pending requests and responses.
+// Returns false iff idle, i.e. no requests were delivered and no responses
+// are awaited.
+func (broker *DurableGatewayRPCBroker) Poll() bool {
+	n, err := broker.deliverRequests()
+	if err != nil {
+		glog.Errorf("Error delivering SyncRPC requests: %v", err)
+	}
+	awaiting, err := broker.deliverResponses()
+	if err != nil {
+		glog.Errorf("Error delivering SyncRPC responses: %v", err)
+	}
+	return n != 0 || awaiting
+}
+
+func (broker *DurableGatewayRPCBroker) SendRequestToGateway(gwReq *protos.GatewayRequest) (*GatewayResponseChannel, error) {
+	if gwReq == nil || len(gwReq.GwId) == 0 {
+		return nil, errors.New("gwReq cannot be nil and gwId cannot be empty string")
+	}
+
+	reqID, err := broker.allocateReqID()
+	if err != nil {
+		return nil, err
+	}
+	now := clock.Now()
+	respChan := broker.initializeResponse(reqID, now.Add(requestDeadline))
+	syncRPCReq := &protos.SyncRPCRequest{ReqId: reqID, ReqBody: gwReq}
+	if broker.enqueueLocal(gwReq.GwId, syncRPCReq) {
+		return &GatewayResponseChannel{RespChan: respChan, ReqId: reqID}, nil
+	}
+	err = broker.store.putRequest(syncRPCReq, now, now.Add(requestDeadline))
+	if err != nil {
+		broker.removeResponse(reqID)
+		return nil, err
+	}
+	return &GatewayResponseChannel{RespChan: respChan, ReqId: reqID}, nil
+}
+
+func (broker *DurableGatewayRPCBroker) ProcessGatewayResponse(response *protos.SyncRPCResponse) error {
+	if response == nil {
+		return errors.New("cannot send nil SyncRPCResponse")
+	}
+	if response.RespBody == nil {
+		glog.Errorf("Nil response body received, forward to httpServer anyways\n")
+	}
+	now := clock.Now()
+	if broker.sendResponse(response.ReqId, response.RespBody, now) {
+		return nil
+	}
+	return broker.store.putResponse(response, now, now.Add(requestDeadline))
+}
+
+func (broker *DurableGatewayRPCBroker) InitializeGateway(gwId string) chan *protos.SyncRPCRequest {
+	broker.queuesMu.Lock()
+	defer broker.queuesMu.Unlock()
+	if oldQueue, ok := broker.queues[gwId]; ok {
+		close(oldQueue)
+		broker.releaseClaimed(gwId)
+	}
+	queue := make(chan *protos.SyncRPCRequest, queueLen)
+	broker.queues[gwId] = queue
+	return queue
+}
+
+func (broker *DurableGatewayRPCBroker) CleanupGateway(gwId string) error {
+	broker.queuesMu.Lock()
+	defer broker.queuesMu.Unlock()
+	queue, ok := broker.queues[gwId]
+	if !ok {
+		glog.Warningf("HWID %v: no request queue found to clean up", gwId)
+		return nil
+	}
+	close(queue)
+	delete(broker.queues, gwId)
+	broker.releaseClaimed(gwId)
+	pendingRequests.DeleteLabelValues(gwId)
+	return nil
+}
+
+func (broker *DurableGatewayRPCBroker) CancelGatewayRequest(gwId string, reqId uint32) error {
+	broker.removeResponse(reqId)
+	syncRPCRequest := &protos.SyncRPCRequest{ReqId: reqId, ReqBody: &protos.GatewayRequest{GwId: gwId}, ConnClosed: true}
+	if broker.enqueueLocal(gwId, syncRPCRequest) {
+		return nil
+	}
+	now := clock.Now()
+	return broker.store.putCancel(syncRPCRequest, now, now.Add(requestDeadline))
+}
+
+// AckRequest deletes a request claimed from storage, once it's sent to the
+// gateway's stream.
+func (broker *DurableGatewayRPCBroker) AckRequest(gwId string, req *protos.SyncRPCRequest) error {
+	broker.claimedMu.Lock()
+	claimed, ok := broker.claimed[req]
+	delete(broker.claimed, req)
+	broker.claimedMu.Unlock()
+	if !ok {
+		// Queued directly, or released on gateway cleanup
+		return nil
+	}
+	return broker.store.deleteRequests([]string{claimed.id})
+}
+
+// enqueueLocal queues a request directly, if the gateway's stream is held by
+// this replica and its queue has space.
+// Returns true iff the request was queued.
+func (broker *DurableGatewayRPCBroker) enqueueLocal(gwID string, req *protos.SyncRPCRequest) bool {
+	broker.queuesMu.RLock()
+	defer broker.queuesMu.RUnlock()
+	queue, ok := broker.queues[gwID]
+	if !ok {
+		return false
+	}
+	select {
+	case queue <- req:
+		return true
+	default:
+		return false
+	}
+}
+
+// releaseClaimed forgets the gateway's claimed requests, which can then be
+// claimed again once their claims time out.
+// Must be called with queuesMu held.
+func (broker *DurableGatewayRPCBroker) releaseClaimed(gwID string) {
+	broker.claimedMu.Lock()
+	defer broker.claimedMu.Unlock()
+	for req, claimed := range broker.claimed {
+		if claimed.gwID == gwID {
+			delete(broker.claimed, req)
+		}
+	}
+}
+
+// deliverRequests moves pending requests from storage to the queues of the
+// gateways connected to this replica, as space allows.
+// Returns the number of delivered requests.
+func (broker *DurableGatewayRPCBroker) deliverRequests() (int, error) {
+	broker.queuesMu.RLock()
+	defer broker.queuesMu.RUnlock()
+
+	var gwIDs []string
+	for gwID := range broker.queues {
+		gwIDs = append(gwIDs, gwID)
+	}
+
+	broker.claimedMu.Lock()
+	defer broker.claimedMu.Unlock()
+	queued := map[string]bool{}
+	for _, claimed := range broker.claimed {
+		queued[claimed.id] = true
+	}
+	deliver := func(req gatewayRequest) bool {
+		if queued[req.id] {
+			// Claim timed out before the request was sent, keep the
+			// queued copy
+			return false
+		}
+		select {
+		case broker.queues[req.gwID] <- req.req:
+			broker.claimed[req.req] = req
+			return true
+		default:
+			return false
+		}
+	}
+
+	now := clock.Now()
+	return broker.store.claimRequests(gwIDs, claimLimit, now, now.Add(claimTimeout), deliver)
+}
+
+// deliverResponses moves responses from storage to the requests awaiting
+// them on this replica.
+// Returns true iff any responses are awaited.
+func (broker *DurableGatewayRPCBroker) deliverResponses() (bool, error) {
+	broker.awaitingMu.Lock()
+	var reqIDs []uint32
+	for reqID := range broker.awaiting {
+		reqIDs = append(reqIDs, reqID)
+	}
+	broker.awaitingMu.Unlock()
+
+	resps, err := broker.store.claimResponses(reqIDs)
+	if err != nil {
+		return len(reqIDs) != 0, err
+	}
+	now := clock.Now()
+	for _, r := range resps {
+		if !broker.sendResponse(r.reqID, r.resp, now) {
+			glog.Errorf("No response channel found for reqId %v", r.reqID)
+		}
+	}
+	return len(reqIDs) != 0, nil
+}
+
+// reportPending reports the number of pending requests for each gateway
+// connected to this replica.
+func (broker *DurableGatewayRPCBroker) reportPending(now time.Time) {
+	broker.queuesMu.RLock()
+	defer broker.queuesMu.RUnlock()
+
+	var gwIDs []string
+	for gwID := range broker.queues {
+		gwIDs = append(gwIDs, gwID)
+	}
+	counts, err := broker.store.countPending(gwIDs, now)
+	if err != nil {
+		glog.Errorf("Error counting pending SyncRPC requests: %v", err)
+		return
+	}
+	for _, gwID := range gwIDs {
+		pendingRequests.WithLabelValues(gwID).Set(float64(counts[gwID] + len(broker.queues[gwID])))
+	}
+}
+
+// sweep deletes expired requests and responses, forgets requests whose
+// responses are no longer awaited, and reports pending requests.
+func (broker *DurableGatewayRPCBroker) sweep() {
+	now := clock.Now()
+	broker.reportPending(now)
+	expired, err := broker.store.deleteExpired(now)
+	if err != nil {
+		glog.Errorf("Error deleting expired SyncRPC requests: %v", err)
+	}
+	for gwID, n := range expired {
+		expiredRequests.WithLabelValues(gwID).Add(float64(n))
+	}
+
+	broker.awaitingMu.Lock()
+	defer broker.awaitingMu.Unlock()
+	for reqID, a := range broker.awaiting {
+		if now.After(a.deadline) {
+			delete(broker.awaiting, reqID)
+		}
+	}
+}
+
+// allocateReqID returns the next request ID of this replica's block,
+// reserving a new block once it's used up. 0 isn't a valid request ID.
+func (broker *DurableGatewayRPCBroker) allocateReqID() (uint32, error) {
+	broker.reqIDMu.Lock()
+	defer broker.reqIDMu.Unlock()
+	for {
+		if broker.nextReqID > broker.lastReqID {
+			last, err := broker.store.reserveRequestIDs(reqIDBlockSize)
+			if err != nil {
+				return 0, err
+			}
+			broker.nextReqID, broker.lastReqID = last-reqIDBlockSize+1, last
+		}
+		reqID := uint32(broker.nextReqID)
+		broker.nextReqID++
+		if reqID != 0 {
+			return reqID, nil
+		}
+	}
+}
+
+// initializeResponse registers a response channel for the request ID.
+func (broker *DurableGatewayRPCBroker) initializeResponse(reqID uint32, deadline time.Time) chan *protos.GatewayResponse {
+	broker.awaitingMu.Lock()
+	defer broker.awaitingMu.Unlock()
+	respChan := make(chan *protos.GatewayResponse)
+	broker.awaiting[reqID] = &awaitingResponse{respChan: respChan, deadline: deadline}
+	return respChan
+}
+
+func (broker *DurableGatewayRPCBroker) removeResponse(reqID uint32) {
+	broker.awaitingMu.Lock()
+	defer broker.awaitingMu.Unlock()
+	delete(broker.awaiting, reqID)
+}
+
+// sendResponse sends a response to its awaiting request, if the request was
+// sent from this replica, extending the request's deadline.
+// Returns true iff the request was sent from this replica.
+func (broker *DurableGatewayRPCBroker) sendResponse(reqID uint32, resp *protos.GatewayResponse, now time.Time) bool {
+	broker.awaitingMu.Lock()
+	a, ok := broker.awaiting[reqID]
+	if ok {
+		a.deadline = now.Add(requestDeadline)
+	}
+	broker.awaitingMu.Unlock()
+	if !ok {
+		return false
+	}
+
+	select {
+	case a.respChan <- resp:
+	case <-time.After(processResponseTimeout):
+		// Give up sending, as the response channel is no longer waited on
+		glog.Errorf("Sending response to reqId %v timed out as respChan is not being actively waited on", reqID)
+		broker.removeResponse(reqID)
+	}
+	return true
+}
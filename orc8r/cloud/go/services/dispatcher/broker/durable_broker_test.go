@@ -0,0 +1,230 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/dispatcher/broker"
+	"magma/orc8r/cloud/go/sqorc"
+	"magma/orc8r/lib/go/protos"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	gw1 = "some_hwid_1"
+	gw2 = "some_hwid_2"
+)
+
+func TestDurableBroker_AcrossReplicas(t *testing.T) {
+	db := openTestDB(t)
+	replicaA := newTestBroker(t, db)
+	replicaB := newTestBroker(t, db)
+
+	// gw1's stream is on replica B, request arrives at replica A
+	queue := replicaB.InitializeGateway(gw1)
+	gwReq := &protos.GatewayRequest{GwId: gw1, Authority: "some_authority", Path: "/some/path", Payload: []byte("some_payload")}
+	respChan, err := replicaA.SendRequestToGateway(gwReq)
+	require.NoError(t, err)
+	assert.NotZero(t, respChan.ReqId)
+
+	replicaA.Poll()
+	assert.Len(t, queue, 0)
+	replicaB.Poll()
+	require.Len(t, queue, 1)
+	syncReq := <-queue
+	assert.Equal(t, respChan.ReqId, syncReq.ReqId)
+	assert.Equal(t, gwReq.Payload, syncReq.ReqBody.Payload)
+	require.NoError(t, replicaB.AckRequest(gw1, syncReq))
+
+	// Delivered requests aren't redelivered
+	replicaB.Poll()
+	assert.Len(t, queue, 0)
+
+	// Response arrives at replica B, is routed to replica A
+	gwResp := &protos.GatewayResponse{Status: "200", Payload: []byte("some_response")}
+	err = replicaB.ProcessGatewayResponse(&protos.SyncRPCResponse{ReqId: syncReq.ReqId, RespBody: gwResp})
+	require.NoError(t, err)
+	go replicaA.Poll()
+	select {
+	case resp := <-respChan.RespChan:
+		assert.Equal(t, gwResp.Payload, resp.Payload)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for response")
+	}
+
+	// Cancel is routed to replica B
+	err = replicaA.CancelGatewayRequest(gw1, respChan.ReqId)
+	require.NoError(t, err)
+	replicaB.Poll()
+	require.Len(t, queue, 1)
+	cancel := <-queue
+	assert.Equal(t, respChan.ReqId, cancel.ReqId)
+	assert.True(t, cancel.ConnClosed)
+
+	// Requests and responses on the replica holding the gateway's stream are
+	// delivered directly
+	localQueue := replicaA.InitializeGateway(gw2)
+	localResp, err := replicaA.SendRequestToGateway(&protos.GatewayRequest{GwId: gw2})
+	require.NoError(t, err)
+	require.Len(t, localQueue, 1)
+	localReq := <-localQueue
+	require.NoError(t, replicaA.AckRequest(gw2, localReq))
+	replicaB.InitializeGateway(gw2)
+	replicaB.Poll()
+	assert.Len(t, localQueue, 0)
+	go func() {
+		_ = replicaA.ProcessGatewayResponse(&protos.SyncRPCResponse{ReqId: localReq.ReqId, RespBody: gwResp})
+	}()
+	select {
+	case resp := <-localResp.RespChan:
+		assert.Equal(t, gwResp.Payload, resp.Payload)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for response")
+	}
+
+	err = replicaB.CleanupGateway(gw1)
+	assert.NoError(t, err)
+	_, ok := <-queue
+	assert.False(t, ok)
+}
+
+func TestDurableBroker_RequestIDs(t *testing.T) {
+	db := openTestDB(t)
+	replicaA := newTestBroker(t, db)
+	replicaB := newTestBroker(t, db)
+	replicaA.InitializeGateway(gw1)
+
+	// Request IDs are unique across replicas, whether requests are queued
+	// locally or persisted
+	reqIDs := map[uint32]bool{}
+	for i := 0; i < 10; i++ {
+		for _, replica := range []*broker.DurableGatewayRPCBroker{replicaA, replicaB} {
+			respChan, err := replica.SendRequestToGateway(&protos.GatewayRequest{GwId: gw1})
+			require.NoError(t, err)
+			assert.NotZero(t, respChan.ReqId)
+			assert.False(t, reqIDs[respChan.ReqId], "duplicate request ID %d", respChan.ReqId)
+			reqIDs[respChan.ReqId] = true
+		}
+	}
+
+	// Including replicas started later
+	replicaC := newTestBroker(t, db)
+	respChan, err := replicaC.SendRequestToGateway(&protos.GatewayRequest{GwId: gw1})
+	require.NoError(t, err)
+	assert.False(t, reqIDs[respChan.ReqId], "duplicate request ID %d", respChan.ReqId)
+}
+
+func TestDurableBroker_Persistence(t *testing.T) {
+	db := openTestDB(t)
+	replicaA := newTestBroker(t, db)
+
+	// Request is sent while gateway is disconnected
+	respChan, err := replicaA.SendRequestToGateway(&protos.GatewayRequest{GwId: gw1})
+	require.NoError(t, err)
+	replicaA.Poll()
+
+	// Gateway connects to a new replica, e.g. after a restart
+	replicaB := newTestBroker(t, db)
+	queue := replicaB.InitializeGateway(gw1)
+	replicaB.Poll()
+	require.Len(t, queue, 1)
+	assert.Equal(t, respChan.ReqId, (<-queue).ReqId)
+}
+
+func TestDurableBroker_Deadline(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	db := openTestDB(t)
+	replicaA := newTestBroker(t, db)
+	replicaB := newTestBroker(t, db)
+
+	_, err := replicaA.SendRequestToGateway(&protos.GatewayRequest{GwId: gw1})
+	require.NoError(t, err)
+
+	// Expired requests aren't delivered
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(time.Minute))
+	queue := replicaB.InitializeGateway(gw1)
+	replicaB.Poll()
+	assert.Len(t, queue, 0)
+}
+
+func TestDurableBroker_Redelivery(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	db := openTestDB(t)
+	replicaA := newTestBroker(t, db)
+	replicaB := newTestBroker(t, db)
+
+	// Idle replicas report no work
+	queue := replicaB.InitializeGateway(gw1)
+	assert.False(t, replicaB.Poll())
+
+	respChan, err := replicaA.SendRequestToGateway(&protos.GatewayRequest{GwId: gw1})
+	require.NoError(t, err)
+	assert.True(t, replicaB.Poll())
+	require.Len(t, queue, 1)
+	<-queue
+
+	// Replica B restarts before sending the request to the gateway, which
+	// reconnects to replica C. The request is redelivered once its claim
+	// times out.
+	replicaC := newTestBroker(t, db)
+	queue = replicaC.InitializeGateway(gw1)
+	replicaC.Poll()
+	assert.Len(t, queue, 0)
+
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(6*time.Second))
+	replicaC.Poll()
+	require.Len(t, queue, 1)
+	syncReq := <-queue
+	assert.Equal(t, respChan.ReqId, syncReq.ReqId)
+
+	// Once sent, the request isn't redelivered
+	require.NoError(t, replicaC.AckRequest(gw1, syncReq))
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(12*time.Second))
+	replicaC.Poll()
+	assert.Len(t, queue, 0)
+}
+
+func TestDurableBroker_InvalidRequests(t *testing.T) {
+	b := newTestBroker(t, openTestDB(t))
+
+	_, err := b.SendRequestToGateway(nil)
+	assert.Error(t, err)
+	_, err = b.SendRequestToGateway(&protos.GatewayRequest{})
+	assert.Error(t, err)
+	err = b.ProcessGatewayResponse(nil)
+	assert.Error(t, err)
+}
+
+func openTestDB(t *testing.T) *sql.DB {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	return db
+}
+
+func newTestBroker(t *testing.T, db *sql.DB) *broker.DurableGatewayRPCBroker {
+	b := broker.NewDurableGatewayRPCBroker(db, sqorc.NewSQLiteStatementBuilder(), broker.DefaultPollInterval)
+	require.NoError(t, b.Initialize())
+	return b
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// GatewayIDLabel is the label for the hardware ID of a gateway.
+	GatewayIDLabel = "gateway_id"
+)
+
+var (
+	pendingRequests = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dispatcher_pending_requests",
+			Help: "Number of SyncRPC requests awaiting delivery to a gateway connected to this dispatcher replica",
+		},
+		[]string{GatewayIDLabel},
+	)
+	expiredRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dispatcher_expired_requests_total",
+			Help: "Number of SyncRPC requests which expired before delivery to their gateway",
+		},
+		[]string{GatewayIDLabel},
+	)
+)
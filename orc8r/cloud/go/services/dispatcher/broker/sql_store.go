@@ -0,0 +1,499 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"magma/orc8r/cloud/go/sqorc"
+	"magma/orc8r/lib/go/protos"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	requestTableName  = "dispatcher_requests"
+	responseTableName = "dispatcher_responses"
+	reqIDSeqTableName = "dispatcher_request_id_seq"
+
+	idCol        = "id"
+	reqIDCol     = "req_id"
+	gatewayIDCol = "gateway_id"
+	requestCol   = "request"
+	responseCol  = "response"
+	createdCol   = "created"
+	deadlineCol  = "deadline"
+	claimedCol   = "claimed_until"
+	lastReqIDCol = "last_req_id"
+
+	reqIDSeqRowID = 0
+)
+
+// sqlStore persists SyncRPC requests and responses in shared SQL tables, so
+// they can be exchanged between dispatcher replicas.
+//
+// Request columns:
+//	- id			-- unique ID of the row. The request ID for requests,
+//					   random for cancellations of a request
+//	- req_id		-- SyncRPC request ID
+//	- gateway_id	-- hardware ID of the gateway to which to send the request
+//	- request		-- serialized SyncRPCRequest
+//	- created		-- Unix time, in nanoseconds, at which the row was created
+//	- deadline		-- Unix time, in nanoseconds, after which the row expires
+//	- claimed_until	-- Unix time, in nanoseconds, until which the request is
+//					   claimed by a replica for sending to the gateway.
+//					   The row is deleted once sent, and can be claimed again
+//					   after this time otherwise
+//
+// Response columns:
+//	- id			-- random unique ID of the row
+//	- req_id		-- SyncRPC request ID
+//	- response		-- serialized GatewayResponse
+//	- created		-- Unix time, in nanoseconds, at which the row was created
+//	- deadline		-- Unix time, in nanoseconds, after which the row expires
+//
+// Request ID sequence columns, of a single row:
+//	- id			-- ID of the row, always 0
+//	- last_req_id	-- last reserved request ID, before wrapping to 32 bits
+type sqlStore struct {
+	db      *sql.DB
+	builder sqorc.StatementBuilder
+}
+
+// gatewayRequest is a request bound for a gateway.
+type gatewayRequest struct {
+	// id of the request's row
+	id   string
+	gwID string
+	req  *protos.SyncRPCRequest
+}
+
+// requestResponse is a response to a request.
+type requestResponse struct {
+	reqID uint32
+	resp  *protos.GatewayResponse
+}
+
+func (s *sqlStore) initialize() error {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		_, err := s.builder.CreateTable(requestTableName).
+			IfNotExists().
+			Column(idCol).Type(sqorc.ColumnTypeText).NotNull().PrimaryKey().EndColumn().
+			Column(reqIDCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			Column(gatewayIDCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(requestCol).Type(sqorc.ColumnTypeBytes).NotNull().EndColumn().
+			Column(createdCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			Column(deadlineCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			Column(claimedCol).Type(sqorc.ColumnTypeBigInt).NotNull().Default(0).EndColumn().
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize dispatcher request table")
+		}
+		_, err = s.builder.CreateIndex(requestTableName + "_gateway_id_idx").
+			IfNotExists().
+			On(requestTableName).
+			Columns(gatewayIDCol).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize dispatcher request table index")
+		}
+
+		_, err = s.builder.CreateTable(responseTableName).
+			IfNotExists().
+			Column(idCol).Type(sqorc.ColumnTypeText).NotNull().PrimaryKey().EndColumn().
+			Column(reqIDCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			Column(responseCol).Type(sqorc.ColumnTypeBytes).EndColumn().
+			Column(createdCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			Column(deadlineCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize dispatcher response table")
+		}
+		_, err = s.builder.CreateIndex(responseTableName + "_req_id_idx").
+			IfNotExists().
+			On(responseTableName).
+			Columns(reqIDCol).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize dispatcher response table index")
+		}
+
+		_, err = s.builder.CreateTable(reqIDSeqTableName).
+			IfNotExists().
+			Column(idCol).Type(sqorc.ColumnTypeInt).PrimaryKey().EndColumn().
+			Column(lastReqIDCol).Type(sqorc.ColumnTypeBigInt).NotNull().Default(0).EndColumn().
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize dispatcher request ID sequence table")
+		}
+		_, err = s.builder.Insert(reqIDSeqTableName).
+			Columns(idCol, lastReqIDCol).
+			Values(reqIDSeqRowID, 0).
+			OnConflict(nil, idCol).
+			RunWith(tx).
+			Exec()
+		return nil, errors.Wrap(err, "initialize dispatcher request ID sequence")
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+// reserveRequestIDs reserves the next n request IDs, returning the last of
+// them.
+func (s *sqlStore) reserveRequestIDs(n uint64) (uint64, error) {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		_, err := s.builder.Update(reqIDSeqTableName).
+			Set(lastReqIDCol, squirrel.Expr(fmt.Sprintf("%s + ?", lastReqIDCol), n)).
+			Where(squirrel.Eq{idCol: reqIDSeqRowID}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "reserve request IDs")
+		}
+		var last uint64
+		err = s.builder.Select(lastReqIDCol).
+			From(reqIDSeqTableName).
+			Where(squirrel.Eq{idCol: reqIDSeqRowID}).
+			RunWith(tx).
+			QueryRow().
+			Scan(&last)
+		return last, errors.Wrap(err, "read reserved request IDs")
+	}
+	txRet, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	if err != nil {
+		return 0, err
+	}
+	return txRet.(uint64), nil
+}
+
+// putRequest persists a new request.
+func (s *sqlStore) putRequest(req *protos.SyncRPCRequest, now time.Time, deadline time.Time) error {
+	marshaled, err := proto.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "marshal SyncRPC request")
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		return nil, s.insertRequest(tx, fmt.Sprint(req.ReqId), req.ReqBody.GwId, req.ReqId, marshaled, now, deadline)
+	}
+	_, err = sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+// putCancel persists a request to cancel a previous request.
+func (s *sqlStore) putCancel(req *protos.SyncRPCRequest, now time.Time, deadline time.Time) error {
+	marshaled, err := proto.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "marshal SyncRPC request")
+	}
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		return nil, s.insertRequest(tx, uuid.New().String(), req.ReqBody.GwId, req.ReqId, marshaled, now, deadline)
+	}
+	_, err = sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+func (s *sqlStore) insertRequest(tx *sql.Tx, id string, gwID string, reqID uint32, marshaled []byte, now time.Time, deadline time.Time) error {
+	_, err := s.builder.Insert(requestTableName).
+		Columns(idCol, reqIDCol, gatewayIDCol, requestCol, createdCol, deadlineCol).
+		Values(id, reqID, gwID, marshaled, now.UnixNano(), deadline.UnixNano()).
+		RunWith(tx).
+		Exec()
+	return errors.Wrapf(err, "insert request %d for gateway %s", reqID, gwID)
+}
+
+// claimRequests claims unexpired, unclaimed requests for the passed gateways,
+// oldest first, handing them to the deliver function.
+// Only requests which deliver accepts are claimed, until claimedUntil. They
+// must be removed with deleteRequests once sent to the gateway, else they
+// can be claimed again after claimedUntil.
+// Returns the number of claimed requests.
+func (s *sqlStore) claimRequests(gwIDs []string, limit uint64, now time.Time, claimedUntil time.Time, deliver func(gatewayRequest) bool) (int, error) {
+	if len(gwIDs) == 0 {
+		return 0, nil
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		selectBuilder := s.builder.Select(idCol, gatewayIDCol, requestCol).
+			From(requestTableName).
+			Where(squirrel.And{
+				squirrel.Eq{gatewayIDCol: gwIDs},
+				squirrel.Gt{deadlineCol: now.UnixNano()},
+				squirrel.LtOrEq{claimedCol: now.UnixNano()},
+			}).
+			OrderBy(createdCol, idCol).
+			Limit(limit)
+		rows, err := s.builder.ForUpdateSkipLocked(selectBuilder).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "select pending requests")
+		}
+		defer sqorc.CloseRowsLogOnError(rows, "claimRequests")
+
+		var reqs []gatewayRequest
+		for rows.Next() {
+			var id, gwID string
+			var marshaled []byte
+			err = rows.Scan(&id, &gwID, &marshaled)
+			if err != nil {
+				return nil, errors.Wrap(err, "scan pending request")
+			}
+			req := &protos.SyncRPCRequest{}
+			err = proto.Unmarshal(marshaled, req)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unmarshal pending request %s", id)
+			}
+			reqs = append(reqs, gatewayRequest{id: id, gwID: gwID, req: req})
+		}
+		if err = rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "sql rows err")
+		}
+
+		var delivered []string
+		for _, req := range reqs {
+			if deliver(req) {
+				delivered = append(delivered, req.id)
+			}
+		}
+		if len(delivered) == 0 {
+			return 0, nil
+		}
+		_, err = s.builder.Update(requestTableName).
+			Set(claimedCol, claimedUntil.UnixNano()).
+			Where(squirrel.Eq{idCol: delivered}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "claim delivered requests")
+		}
+		return len(delivered), nil
+	}
+	txRet, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	if err != nil {
+		return 0, err
+	}
+	return txRet.(int), nil
+}
+
+// deleteRequests deletes the passed requests, e.g. once sent to their
+// gateway.
+func (s *sqlStore) deleteRequests(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		_, err := s.builder.Delete(requestTableName).
+			Where(squirrel.Eq{idCol: ids}).
+			RunWith(tx).
+			Exec()
+		return nil, errors.Wrap(err, "delete sent requests")
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+// putResponse persists a response to a request.
+func (s *sqlStore) putResponse(resp *protos.SyncRPCResponse, now time.Time, deadline time.Time) error {
+	var marshaled []byte
+	if resp.RespBody != nil {
+		var err error
+		marshaled, err = proto.Marshal(resp.RespBody)
+		if err != nil {
+			return errors.Wrap(err, "marshal gateway response")
+		}
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		_, err := s.builder.Insert(responseTableName).
+			Columns(idCol, reqIDCol, responseCol, createdCol, deadlineCol).
+			Values(uuid.New().String(), resp.ReqId, marshaled, now.UnixNano(), deadline.UnixNano()).
+			RunWith(tx).
+			Exec()
+		return nil, errors.Wrapf(err, "insert response to request %d", resp.ReqId)
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+// claimResponses removes and returns the responses to the passed requests,
+// oldest first.
+func (s *sqlStore) claimResponses(reqIDs []uint32) ([]requestResponse, error) {
+	if len(reqIDs) == 0 {
+		return nil, nil
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		selectBuilder := s.builder.Select(idCol, reqIDCol, responseCol).
+			From(responseTableName).
+			Where(squirrel.Eq{reqIDCol: reqIDs}).
+			OrderBy(createdCol, idCol)
+		rows, err := s.builder.ForUpdateSkipLocked(selectBuilder).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "select responses")
+		}
+		defer sqorc.CloseRowsLogOnError(rows, "claimResponses")
+
+		var ids []string
+		var resps []requestResponse
+		for rows.Next() {
+			var id string
+			var reqID uint32
+			var marshaled []byte
+			err = rows.Scan(&id, &reqID, &marshaled)
+			if err != nil {
+				return nil, errors.Wrap(err, "scan response")
+			}
+			var resp *protos.GatewayResponse
+			if marshaled != nil {
+				resp = &protos.GatewayResponse{}
+				err = proto.Unmarshal(marshaled, resp)
+				if err != nil {
+					return nil, errors.Wrapf(err, "unmarshal response %s", id)
+				}
+			}
+			ids = append(ids, id)
+			resps = append(resps, requestResponse{reqID: reqID, resp: resp})
+		}
+		if err = rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "sql rows err")
+		}
+		if len(ids) == 0 {
+			return resps, nil
+		}
+
+		_, err = s.builder.Delete(responseTableName).
+			Where(squirrel.Eq{idCol: ids}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "delete claimed responses")
+		}
+		return resps, nil
+	}
+
+	txRet, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	if err != nil {
+		return nil, err
+	}
+	return txRet.([]requestResponse), nil
+}
+
+// deleteExpired deletes expired requests and responses.
+// Returns the number of expired requests, keyed by gateway ID.
+func (s *sqlStore) deleteExpired(now time.Time) (map[string]int, error) {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		expired := map[string]int{}
+		rows, err := s.builder.Select(gatewayIDCol, "COUNT(*)").
+			From(requestTableName).
+			Where(squirrel.LtOrEq{deadlineCol: now.UnixNano()}).
+			GroupBy(gatewayIDCol).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "count expired requests")
+		}
+		defer sqorc.CloseRowsLogOnError(rows, "deleteExpired")
+		for rows.Next() {
+			var gwID string
+			var count int
+			err = rows.Scan(&gwID, &count)
+			if err != nil {
+				return nil, errors.Wrap(err, "scan expired request count")
+			}
+			expired[gwID] = count
+		}
+		if err = rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "sql rows err")
+		}
+
+		_, err = s.builder.Delete(requestTableName).
+			Where(squirrel.LtOrEq{deadlineCol: now.UnixNano()}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "delete expired requests")
+		}
+		_, err = s.builder.Delete(responseTableName).
+			Where(squirrel.LtOrEq{deadlineCol: now.UnixNano()}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "delete expired responses")
+		}
+		return expired, nil
+	}
+
+	txRet, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	if err != nil {
+		return nil, err
+	}
+	return txRet.(map[string]int), nil
+}
+
+// countPending returns the number of unexpired requests for each of the
+// passed gateways.
+func (s *sqlStore) countPending(gwIDs []string, now time.Time) (map[string]int, error) {
+	if len(gwIDs) == 0 {
+		return map[string]int{}, nil
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		counts := map[string]int{}
+		rows, err := s.builder.Select(gatewayIDCol, "COUNT(*)").
+			From(requestTableName).
+			Where(squirrel.And{
+				squirrel.Eq{gatewayIDCol: gwIDs},
+				squirrel.Gt{deadlineCol: now.UnixNano()},
+			}).
+			GroupBy(gatewayIDCol).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "count pending requests")
+		}
+		defer sqorc.CloseRowsLogOnError(rows, "countPending")
+		for rows.Next() {
+			var gwID string
+			var count int
+			err = rows.Scan(&gwID, &count)
+			if err != nil {
+				return nil, errors.Wrap(err, "scan pending request count")
+			}
+			counts[gwID] = count
+		}
+		if err = rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "sql rows err")
+		}
+		return counts, nil
+	}
+
+	txRet, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	if err != nil {
+		return nil, err
+	}
+	return txRet.(map[string]int), nil
+}
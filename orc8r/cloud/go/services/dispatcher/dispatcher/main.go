@@ -14,6 +14,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"magma/orc8r/cloud/go/orc8r"
@@ -22,8 +23,11 @@ import (
 	syncRpcBroker "magma/orc8r/cloud/go/services/dispatcher/broker"
 	"magma/orc8r/cloud/go/services/dispatcher/httpserver"
 	"magma/orc8r/cloud/go/services/dispatcher/servicers"
+	"magma/orc8r/cloud/go/sqorc"
+	"magma/orc8r/cloud/go/storage"
 	"magma/orc8r/lib/go/protos"
 	platform_service "magma/orc8r/lib/go/service"
+	"magma/orc8r/lib/go/service/config"
 
 	"github.com/golang/glog"
 	"google.golang.org/grpc"
@@ -31,6 +35,10 @@ import (
 
 const (
 	HttpServerPort = 9080
+
+	// useDurableBrokerKey is the dispatcher config key for whether to use
+	// the database-backed SyncRPC broker.
+	useDurableBrokerKey = "use_durable_broker"
 )
 
 func main() {
@@ -50,7 +58,7 @@ func main() {
 	}
 
 	// create a broker
-	broker := syncRpcBroker.NewGatewayReqRespBroker()
+	broker := newBroker(srv.Config)
 
 	// get ec2 public host name
 	hostName := service.MustGetHostname()
@@ -72,3 +80,24 @@ func main() {
 		glog.Fatalf("Error running service: %+v", err)
 	}
 }
+
+func newBroker(cfg *config.ConfigMap) syncRpcBroker.GatewayRPCBroker {
+	useDurable, err := cfg.GetBool(useDurableBrokerKey)
+	if err != nil || !useDurable {
+		glog.Info("Using in-memory SyncRPC broker")
+		return syncRpcBroker.NewGatewayReqRespBroker()
+	}
+
+	db, err := sqorc.Open(storage.GetSQLDriver(), storage.GetDatabaseSource())
+	if err != nil {
+		glog.Fatalf("Error connecting to database: %v", err)
+	}
+	broker := syncRpcBroker.NewDurableGatewayRPCBroker(db, sqorc.GetSqlBuilder(), syncRpcBroker.DefaultPollInterval)
+	err = broker.Initialize()
+	if err != nil {
+		glog.Fatalf("Error initializing SyncRPC broker database: %v", err)
+	}
+	go broker.Run(context.Background())
+	glog.Info("Using durable SyncRPC broker")
+	return broker
+}
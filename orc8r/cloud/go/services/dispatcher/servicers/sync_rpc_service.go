@@ -146,6 +146,11 @@ func (srv *SyncRPCService) sendToStream(
 					coordinator.sendErr(fmt.Errorf("HWID %v: sendToStream: error sending to stream: %v", coordinator.GwID, err))
 					return
 				}
+				if acker, ok := srv.broker.(broker.RequestAcker); ok {
+					if err := acker.AckRequest(coordinator.GwID, reqToSend); err != nil {
+						glog.Errorf("HWID %v: sendToStream: error acknowledging sent request: %v", coordinator.GwID, err)
+					}
+				}
 			}
 		}
 	}
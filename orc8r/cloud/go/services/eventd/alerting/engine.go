@@ -0,0 +1,472 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package alerting evaluates alert rules against the events logged to
+// eventd, raising alerts to Alertmanager.
+//
+// Each rule counts the matching events of a network, optionally per
+// subscriber or gateway, and fires while at least its threshold of events
+// were logged within its window. Alerts are labeled with their network, so
+// Alertmanager routes them to the network's alert receivers, as configured
+// through metricsd.
+//
+// Only the eventd replica holding the alerting leader lease evaluates the
+// rules. The evaluation state of each network, i.e. the offset after its last
+// counted event and the counts of its groups, is persisted after every
+// evaluation, so a new leader resumes where the previous one left off.
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/leader"
+	"magma/orc8r/cloud/go/services/eventd"
+	"magma/orc8r/cloud/go/services/eventd/obsidian/models"
+	"magma/orc8r/cloud/go/services/eventd/storage"
+	"magma/orc8r/cloud/go/services/eventd/tail"
+	"magma/orc8r/lib/go/metrics"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/golang/glog"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	am_models "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/thoas/go-funk"
+)
+
+const (
+	alertNameLabel  = "alertname"
+	severityLabel   = "severity"
+	descriptionAnno = "description"
+
+	// LeaderElectionName is the name of the leader election among eventd
+	// replicas for evaluating alert rules
+	LeaderElectionName = "eventd_alerting"
+
+	// maxBatchesPerEvaluation bounds the events read from each network per
+	// evaluation, so a burst of events in one network doesn't delay
+	// evaluating the others. Events left over are counted by the following
+	// evaluations, and reported by the evaluation backlog metric.
+	maxBatchesPerEvaluation = 50
+	// resolveTimeoutIntervals is the number of evaluation intervals after
+	// which Alertmanager resolves a firing alert that stops being sent
+	resolveTimeoutIntervals = 4
+)
+
+// Engine evaluates alert rules against the events of all networks.
+type Engine struct {
+	store        storage.EventStorage
+	states       StateStore
+	elector      leader.Elector
+	notifier     Notifier
+	rules        []eventd.AlertRule
+	interval     time.Duration
+	listNetworks func() ([]string, error)
+
+	// loaded is whether the evaluation state was loaded from the state
+	// store since this replica became the leader
+	loaded  bool
+	tailers map[string]*tail.Tailer
+	groups  map[groupKey]*group
+}
+
+// groupKey identifies the events counted together by a rule.
+type groupKey struct {
+	rule       int
+	networkID  string
+	tag        string
+	hardwareID string
+}
+
+type group struct {
+	// timestamps of the latest counted events by timestamp, at most the
+	// rule's threshold, oldest first
+	timestamps []time.Time
+	// firingSince is the time the group started firing, zero if it isn't
+	firingSince time.Time
+}
+
+// NewEngine returns an engine which evaluates the rules every interval,
+// against the events of the networks listed by listNetworks. Evaluation
+// state is persisted to the passed state store. Only the replica elected by
+// the passed elector evaluates the rules; a nil elector evaluates them on
+// every replica.
+func NewEngine(
+	store storage.EventStorage,
+	states StateStore,
+	elector leader.Elector,
+	notifier Notifier,
+	rules []eventd.AlertRule,
+	interval time.Duration,
+	listNetworks func() ([]string, error),
+) (*Engine, error) {
+	names := map[string]bool{}
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		if names[rule.Name] {
+			return nil, errors.Errorf("duplicate alert rule %s", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+	return &Engine{
+		store:        store,
+		states:       states,
+		elector:      elector,
+		notifier:     notifier,
+		rules:        rules,
+		interval:     interval,
+		listNetworks: listNetworks,
+		tailers:      map[string]*tail.Tailer{},
+		groups:       map[groupKey]*group{},
+	}, nil
+}
+
+// Run evaluates the rules every interval, while this replica is the leader,
+// until the context is done.
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !e.isLeader() {
+				e.reset()
+				continue
+			}
+			if err := e.Evaluate(ctx); err != nil {
+				glog.Errorf("Failed to evaluate event alert rules: %s", err)
+			}
+		}
+	}
+}
+
+func (e *Engine) isLeader() bool {
+	if e.elector == nil {
+		return true
+	}
+	isLeader, err := e.elector.IsLeader()
+	if err != nil {
+		glog.Errorf("Error electing event alerting leader: %s", err)
+		return false
+	}
+	return isLeader
+}
+
+// reset forgets the evaluation state, which is reloaded from the state store
+// once this replica becomes the leader again.
+func (e *Engine) reset() {
+	if !e.loaded {
+		return
+	}
+	e.loaded = false
+	e.tailers = map[string]*tail.Tailer{}
+	e.groups = map[groupKey]*group{}
+	evaluationBacklog.Reset()
+}
+
+// Evaluate counts the events logged since the previous evaluation, then
+// raises the alerts of firing groups, and resolves the alerts of groups
+// which stopped firing.
+// Events logged before a network's first evaluation aren't counted.
+func (e *Engine) Evaluate(ctx context.Context) error {
+	if !e.loaded {
+		err := e.load()
+		if err != nil {
+			return errors.Wrap(err, "load evaluation state")
+		}
+		e.loaded = true
+	}
+
+	networkIDs, err := e.listNetworks()
+	if err != nil {
+		return errors.Wrap(err, "list networks")
+	}
+
+	var errs *multierror.Error
+	evaluated := map[string]bool{}
+	for _, networkID := range networkIDs {
+		if !e.hasRules(networkID) {
+			continue
+		}
+		evaluated[networkID] = true
+		err = e.countNewEvents(ctx, networkID)
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "count events of network %s", networkID))
+		}
+	}
+	for networkID := range e.tailers {
+		if !evaluated[networkID] {
+			delete(e.tailers, networkID)
+			evaluationBacklog.DeleteLabelValues(networkID)
+		}
+	}
+
+	alerts := e.getAlerts(clock.Now())
+	err = e.save()
+	if err != nil {
+		errs = multierror.Append(errs, errors.Wrap(err, "save evaluation state"))
+	}
+	if len(alerts) > 0 {
+		err = e.notifier.Notify(ctx, alerts)
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrap(err, "notify alerts"))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+func (e *Engine) hasRules(networkID string) bool {
+	for _, rule := range e.rules {
+		if len(rule.Networks) == 0 || funk.ContainsString(rule.Networks, networkID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Engine) countNewEvents(ctx context.Context, networkID string) error {
+	tailer, ok := e.tailers[networkID]
+	if !ok {
+		offset, err := tail.NewestOffset(ctx, e.store)
+		if err != nil {
+			return err
+		}
+		tailer = tail.NewTailer(e.store, tail.Filter{NetworkID: networkID}, offset)
+		e.tailers[networkID] = tailer
+	}
+	for i := 0; i < maxBatchesPerEvaluation; i++ {
+		events, err := tailer.Next(ctx, 0)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			evaluationBacklog.WithLabelValues(networkID).Set(0)
+			return nil
+		}
+		for _, event := range events {
+			e.count(networkID, event.Event)
+		}
+	}
+
+	// Events are left to count, so alerts may be delayed
+	evaluationsTruncated.WithLabelValues(networkID).Inc()
+	position, err := e.store.GetTailPosition(ctx)
+	if err != nil {
+		return err
+	}
+	backlog := position - tailer.Offset().Position
+	evaluationBacklog.WithLabelValues(networkID).Set(float64(backlog))
+	glog.Warningf("Event alert evaluation of network %s is behind by %d storage positions", networkID, backlog)
+	return nil
+}
+
+func (e *Engine) count(networkID string, event models.Event) {
+	ts, err := time.Parse(time.RFC3339Nano, event.Timestamp)
+	if err != nil {
+		glog.Errorf("Invalid timestamp of event %s/%s: %s", event.StreamName, event.EventType, err)
+		return
+	}
+	for i, rule := range e.rules {
+		if !matches(rule, networkID, event) {
+			continue
+		}
+		key := groupKey{rule: i, networkID: networkID}
+		for _, field := range rule.GroupBy {
+			switch field {
+			case eventd.AlertGroupByTag:
+				key.tag = event.Tag
+			case eventd.AlertGroupByHardwareID:
+				key.hardwareID = event.HardwareID
+			}
+		}
+		g, ok := e.groups[key]
+		if !ok {
+			g = &group{}
+			e.groups[key] = g
+		}
+		// Events aren't stored in timestamp order, e.g. when buffered on
+		// their way to orc8r, so keep timestamps sorted
+		j := sort.Search(len(g.timestamps), func(j int) bool { return g.timestamps[j].After(ts) })
+		g.timestamps = append(g.timestamps, time.Time{})
+		copy(g.timestamps[j+1:], g.timestamps[j:])
+		g.timestamps[j] = ts
+		if n := len(g.timestamps); n > rule.Threshold {
+			g.timestamps = g.timestamps[n-rule.Threshold:]
+		}
+	}
+}
+
+// load restores the evaluation state from the state store. Groups of rules
+// which were removed are dropped.
+func (e *Engine) load() error {
+	states, err := e.states.GetStates()
+	if err != nil {
+		return err
+	}
+	ruleIndices := map[string]int{}
+	for i, rule := range e.rules {
+		ruleIndices[rule.Name] = i
+	}
+
+	e.tailers = map[string]*tail.Tailer{}
+	e.groups = map[groupKey]*group{}
+	for networkID, state := range states {
+		e.tailers[networkID] = tail.NewTailer(e.store, tail.Filter{NetworkID: networkID}, state.Offset)
+		for _, gs := range state.Groups {
+			i, ok := ruleIndices[gs.Rule]
+			if !ok || len(gs.Timestamps) == 0 {
+				continue
+			}
+			rule := e.rules[i]
+			key := groupKey{rule: i, networkID: networkID}
+			for _, field := range rule.GroupBy {
+				switch field {
+				case eventd.AlertGroupByTag:
+					key.tag = gs.Tag
+				case eventd.AlertGroupByHardwareID:
+					key.hardwareID = gs.HardwareID
+				}
+			}
+			timestamps := gs.Timestamps
+			sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+			if n := len(timestamps); n > rule.Threshold {
+				timestamps = timestamps[n-rule.Threshold:]
+			}
+			e.groups[key] = &group{timestamps: timestamps, firingSince: gs.FiringSince}
+		}
+	}
+	return nil
+}
+
+// save persists the evaluation state of the evaluated networks.
+func (e *Engine) save() error {
+	states := map[string]NetworkState{}
+	for networkID, tailer := range e.tailers {
+		states[networkID] = NetworkState{Offset: tailer.Offset(), Groups: []GroupState{}}
+	}
+	for key, g := range e.groups {
+		state, ok := states[key.networkID]
+		if !ok {
+			continue
+		}
+		state.Groups = append(state.Groups, GroupState{
+			Rule:        e.rules[key.rule].Name,
+			Tag:         key.tag,
+			HardwareID:  key.hardwareID,
+			Timestamps:  g.timestamps,
+			FiringSince: g.firingSince,
+		})
+		states[key.networkID] = state
+	}
+	return e.states.SetStates(states)
+}
+
+func matches(rule eventd.AlertRule, networkID string, event models.Event) bool {
+	if len(rule.Networks) > 0 && !funk.ContainsString(rule.Networks, networkID) {
+		return false
+	}
+	if len(rule.Streams) > 0 && !funk.ContainsString(rule.Streams, event.StreamName) {
+		return false
+	}
+	if len(rule.EventTypes) > 0 && !funk.ContainsString(rule.EventTypes, event.EventType) {
+		return false
+	}
+	return true
+}
+
+// getAlerts returns the alerts of firing groups, and the resolved alerts of
+// groups which stopped firing, sorted by labels.
+func (e *Engine) getAlerts(now time.Time) am_models.PostableAlerts {
+	alerts := am_models.PostableAlerts{}
+	for key, g := range e.groups {
+		rule := e.rules[key.rule]
+		windowStart := now.Add(-rule.Window)
+		inWindow := sort.Search(len(g.timestamps), func(i int) bool { return !g.timestamps[i].Before(windowStart) })
+		g.timestamps = g.timestamps[inWindow:]
+		firing := len(g.timestamps) >= rule.Threshold
+		switch {
+		case firing:
+			if g.firingSince.IsZero() {
+				g.firingSince = now
+			}
+			endsAt := now.Add(resolveTimeoutIntervals * e.interval)
+			alerts = append(alerts, e.toAlert(key, g, endsAt))
+		case !g.firingSince.IsZero():
+			alerts = append(alerts, e.toAlert(key, g, now))
+			g.firingSince = time.Time{}
+		}
+
+		// Forget groups which can't fire until new events are counted
+		if g.firingSince.IsZero() && len(g.timestamps) == 0 {
+			delete(e.groups, key)
+		}
+	}
+	// fmt prints maps sorted by key
+	sort.Slice(alerts, func(i, j int) bool {
+		return fmt.Sprint(alerts[i].Labels) < fmt.Sprint(alerts[j].Labels)
+	})
+	return alerts
+}
+
+func (e *Engine) toAlert(key groupKey, g *group, endsAt time.Time) *am_models.PostableAlert {
+	rule := e.rules[key.rule]
+	labels := am_models.LabelSet{}
+	for k, v := range rule.Labels {
+		labels[k] = v
+	}
+	labels[alertNameLabel] = rule.Name
+	labels[metrics.NetworkLabelName] = key.networkID
+	if rule.Severity != "" {
+		labels[severityLabel] = rule.Severity
+	}
+	for _, field := range rule.GroupBy {
+		switch field {
+		case eventd.AlertGroupByTag:
+			labels[eventd.AlertGroupByTag] = key.tag
+		case eventd.AlertGroupByHardwareID:
+			labels[eventd.AlertGroupByHardwareID] = key.hardwareID
+		}
+	}
+
+	annotations := am_models.LabelSet{}
+	for k, v := range rule.Annotations {
+		annotations[k] = v
+	}
+	if _, ok := annotations[descriptionAnno]; !ok {
+		annotations[descriptionAnno] = getDescription(rule)
+	}
+
+	return &am_models.PostableAlert{
+		Annotations: annotations,
+		StartsAt:    strfmt.DateTime(g.firingSince),
+		EndsAt:      strfmt.DateTime(endsAt),
+		Alert:       am_models.Alert{Labels: labels},
+	}
+}
+
+func getDescription(rule eventd.AlertRule) string {
+	events := "events"
+	if len(rule.EventTypes) > 0 {
+		events = strings.Join(rule.EventTypes, ", ") + " events"
+	}
+	return fmt.Sprintf("At least %d %s logged within %s", rule.Threshold, events, rule.Window)
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerting_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/eventd"
+	"magma/orc8r/cloud/go/services/eventd/alerting"
+	"magma/orc8r/cloud/go/services/eventd/protos"
+	"magma/orc8r/cloud/go/services/eventd/storage"
+	"magma/orc8r/cloud/go/services/eventd/test_utils"
+	"magma/orc8r/cloud/go/sqorc"
+
+	"github.com/go-openapi/strfmt"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoas/go-funk"
+)
+
+type mockNotifier struct {
+	notified []models.PostableAlerts
+	err      error
+}
+
+func (m *mockNotifier) Notify(ctx context.Context, alerts models.PostableAlerts) error {
+	m.notified = append(m.notified, alerts)
+	return m.err
+}
+
+var rules = []eventd.AlertRule{
+	{
+		Name:        "RepeatedAttachFailure",
+		Streams:     []string{"mme"},
+		EventTypes:  []string{"attach_failure"},
+		GroupBy:     []string{"tag"},
+		Window:      time.Minute,
+		Threshold:   3,
+		Severity:    "major",
+		Annotations: map[string]string{"summary": "Subscriber failing to attach"},
+	},
+	{
+		Name:       "GatewayRestarted",
+		Networks:   []string{"n2"},
+		EventTypes: []string{"restarted"},
+		GroupBy:    []string{"hardware_id"},
+		Window:     time.Minute,
+		Threshold:  1,
+		Labels:     map[string]string{"team": "ran"},
+	},
+}
+
+func TestEngine(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	store := storage.NewSQLEventStorage(db, sqorc.GetSqlBuilder())
+	require.NoError(t, store.Initialize())
+	states := alerting.NewSQLStateStore(db, sqorc.GetSqlBuilder())
+	require.NoError(t, states.Initialize())
+	ctx := context.Background()
+
+	notifier := &mockNotifier{}
+	listNetworks := func() ([]string, error) { return []string{"n1", "n2"}, nil }
+	engine, err := alerting.NewEngine(store, states, nil, notifier, rules, 10*time.Second, listNetworks)
+	require.NoError(t, err)
+
+	// Events logged before the first evaluation aren't counted
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{
+		test_utils.NewEventRecord(t, "n2", "magmad", "restarted", "hw9", "", time.Unix(999, 0)),
+	}))
+	require.NoError(t, engine.Evaluate(ctx))
+	assert.Empty(t, notifier.notified)
+
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw1", "IMSI1", time.Unix(1000, 0)),
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw2", "IMSI2", time.Unix(1000, 0)),
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw1", "IMSI1", time.Unix(1001, 0)),
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_success", "hw1", "IMSI1", time.Unix(1001, 0)),
+		test_utils.NewEventRecord(t, "n1", "sessiond", "attach_failure", "hw1", "IMSI1", time.Unix(1001, 0)),
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw1", "IMSI1", time.Unix(1002, 0)),
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw2", "IMSI2", time.Unix(1002, 0)),
+		test_utils.NewEventRecord(t, "n1", "magmad", "restarted", "hw1", "", time.Unix(1002, 0)),
+		test_utils.NewEventRecord(t, "n2", "magmad", "restarted", "hw9", "", time.Unix(1003, 0)),
+	}))
+	clock.SetAndFreezeClock(t, time.Unix(1005, 0))
+	require.NoError(t, engine.Evaluate(ctx))
+	require.Len(t, notifier.notified, 1)
+	attachFailure := &models.PostableAlert{
+		Annotations: models.LabelSet{
+			"summary":     "Subscriber failing to attach",
+			"description": "At least 3 attach_failure events logged within 1m0s",
+		},
+		StartsAt: strfmt.DateTime(time.Unix(1005, 0)),
+		EndsAt:   strfmt.DateTime(time.Unix(1045, 0)),
+		Alert: models.Alert{Labels: models.LabelSet{
+			"alertname": "RepeatedAttachFailure",
+			"networkID": "n1",
+			"severity":  "major",
+			"tag":       "IMSI1",
+		}},
+	}
+	gatewayRestarted := &models.PostableAlert{
+		Annotations: models.LabelSet{"description": "At least 1 restarted events logged within 1m0s"},
+		StartsAt:    strfmt.DateTime(time.Unix(1005, 0)),
+		EndsAt:      strfmt.DateTime(time.Unix(1045, 0)),
+		Alert: models.Alert{Labels: models.LabelSet{
+			"alertname":   "GatewayRestarted",
+			"networkID":   "n2",
+			"hardware_id": "hw9",
+			"team":        "ran",
+		}},
+	}
+	assert.Equal(t, models.PostableAlerts{gatewayRestarted, attachFailure}, notifier.notified[0])
+
+	// Firing alerts are re-sent until they resolve
+	clock.SetAndFreezeClock(t, time.Unix(1015, 0))
+	require.NoError(t, engine.Evaluate(ctx))
+	require.Len(t, notifier.notified, 2)
+	attachFailure.EndsAt = strfmt.DateTime(time.Unix(1055, 0))
+	gatewayRestarted.EndsAt = strfmt.DateTime(time.Unix(1055, 0))
+	assert.Equal(t, models.PostableAlerts{gatewayRestarted, attachFailure}, notifier.notified[1])
+
+	// Resolved once the events leave the window
+	clock.SetAndFreezeClock(t, time.Unix(1065, 0))
+	require.NoError(t, engine.Evaluate(ctx))
+	require.Len(t, notifier.notified, 3)
+	attachFailure.EndsAt = strfmt.DateTime(time.Unix(1065, 0))
+	gatewayRestarted.EndsAt = strfmt.DateTime(time.Unix(1065, 0))
+	assert.Equal(t, models.PostableAlerts{gatewayRestarted, attachFailure}, notifier.notified[2])
+
+	clock.SetAndFreezeClock(t, time.Unix(1075, 0))
+	require.NoError(t, engine.Evaluate(ctx))
+	assert.Len(t, notifier.notified, 3)
+
+	// Notification errors are returned
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{
+		test_utils.NewEventRecord(t, "n2", "magmad", "restarted", "hw9", "", time.Unix(1075, 0)),
+	}))
+	notifier.err = errors.New("alertmanager unavailable")
+	assert.Error(t, engine.Evaluate(ctx))
+}
+
+func TestEngine_Resume(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	store := storage.NewSQLEventStorage(db, sqorc.GetSqlBuilder())
+	require.NoError(t, store.Initialize())
+	states := alerting.NewSQLStateStore(db, sqorc.GetSqlBuilder())
+	require.NoError(t, states.Initialize())
+	ctx := context.Background()
+
+	notifier := &mockNotifier{}
+	networks := []string{"n1", "n2"}
+	listNetworks := func() ([]string, error) { return networks, nil }
+	engine, err := alerting.NewEngine(store, states, nil, notifier, rules, 10*time.Second, listNetworks)
+	require.NoError(t, err)
+	require.NoError(t, engine.Evaluate(ctx))
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw1", "IMSI1", time.Unix(1000, 0)),
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw1", "IMSI1", time.Unix(1001, 0)),
+	}))
+	clock.SetAndFreezeClock(t, time.Unix(1005, 0))
+	require.NoError(t, engine.Evaluate(ctx))
+	assert.Empty(t, notifier.notified)
+
+	// Another engine, e.g. of a new leader, resumes from the persisted
+	// state, so neither the counted events nor the events logged in between
+	// are lost
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw1", "IMSI1", time.Unix(1006, 0)),
+	}))
+	engine, err = alerting.NewEngine(store, states, nil, notifier, rules, 10*time.Second, listNetworks)
+	require.NoError(t, err)
+	clock.SetAndFreezeClock(t, time.Unix(1010, 0))
+	require.NoError(t, engine.Evaluate(ctx))
+	require.Len(t, notifier.notified, 1)
+	require.Len(t, notifier.notified[0], 1)
+	assert.Equal(t, int64(1010), time.Time(notifier.notified[0][0].StartsAt).Unix())
+	assert.Equal(t, "RepeatedAttachFailure", notifier.notified[0][0].Labels["alertname"])
+
+	// Firing alerts keep their start time across engines
+	engine, err = alerting.NewEngine(store, states, nil, notifier, rules, 10*time.Second, listNetworks)
+	require.NoError(t, err)
+	clock.SetAndFreezeClock(t, time.Unix(1020, 0))
+	require.NoError(t, engine.Evaluate(ctx))
+	require.Len(t, notifier.notified, 2)
+	assert.Equal(t, int64(1010), time.Time(notifier.notified[1][0].StartsAt).Unix())
+
+	// States of networks which are no longer evaluated are deleted
+	networks = []string{"n2"}
+	require.NoError(t, engine.Evaluate(ctx))
+	got, err := states.GetStates()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"n2"}, funk.Keys(got))
+}
+
+func TestEngine_OutOfOrderEvents(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	store := storage.NewSQLEventStorage(db, sqorc.GetSqlBuilder())
+	require.NoError(t, store.Initialize())
+	states := alerting.NewSQLStateStore(db, sqorc.GetSqlBuilder())
+	require.NoError(t, states.Initialize())
+	ctx := context.Background()
+
+	notifier := &mockNotifier{}
+	listNetworks := func() ([]string, error) { return []string{"n1"}, nil }
+	engine, err := alerting.NewEngine(store, states, nil, notifier, rules, 10*time.Second, listNetworks)
+	require.NoError(t, err)
+	require.NoError(t, engine.Evaluate(ctx))
+
+	// Events are counted by their timestamps rather than the order they're
+	// stored in, so a late event from before the window doesn't count
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw1", "IMSI1", time.Unix(1100, 0)),
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw1", "IMSI1", time.Unix(1101, 0)),
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw1", "IMSI1", time.Unix(1030, 0)),
+	}))
+	clock.SetAndFreezeClock(t, time.Unix(1105, 0))
+	require.NoError(t, engine.Evaluate(ctx))
+	assert.Empty(t, notifier.notified)
+
+	// A late event within the window does
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw1", "IMSI1", time.Unix(1090, 0)),
+	}))
+	clock.SetAndFreezeClock(t, time.Unix(1110, 0))
+	require.NoError(t, engine.Evaluate(ctx))
+	require.Len(t, notifier.notified, 1)
+	require.Len(t, notifier.notified[0], 1)
+	assert.Equal(t, "IMSI1", notifier.notified[0][0].Labels["tag"])
+
+	// The alert resolves once fewer than the threshold of events are left
+	// within the window, i.e. once the earliest of them leaves it
+	clock.SetAndFreezeClock(t, time.Unix(1155, 0))
+	require.NoError(t, engine.Evaluate(ctx))
+	require.Len(t, notifier.notified, 2)
+	assert.Equal(t, int64(1155), time.Time(notifier.notified[1][0].EndsAt).Unix())
+}
+
+func TestNewEngine_InvalidRules(t *testing.T) {
+	listNetworks := func() ([]string, error) { return nil, nil }
+	_, err := alerting.NewEngine(nil, nil, nil, &mockNotifier{}, []eventd.AlertRule{rules[0], rules[0]}, time.Second, listNetworks)
+	assert.EqualError(t, err, "duplicate alert rule RepeatedAttachFailure")
+
+	invalid := rules[0]
+	invalid.GroupBy = []string{"imsi"}
+	_, err = alerting.NewEngine(nil, nil, nil, &mockNotifier{}, []eventd.AlertRule{invalid}, time.Second, listNetworks)
+	assert.Error(t, err)
+
+	invalid = rules[0]
+	invalid.Threshold = 0
+	_, err = alerting.NewEngine(nil, nil, nil, &mockNotifier{}, []eventd.AlertRule{invalid}, time.Second, listNetworks)
+	assert.EqualError(t, err, "alert rule RepeatedAttachFailure must have a positive threshold")
+}
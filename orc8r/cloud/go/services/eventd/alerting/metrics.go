@@ -0,0 +1,38 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerting
+
+import (
+	"magma/orc8r/lib/go/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	evaluationBacklog = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "eventd_alert_evaluation_backlog",
+			Help: "Storage positions between the last event counted for the network's alert rules and the newest stored event, 0 once evaluation caught up",
+		},
+		[]string{metrics.NetworkLabelName},
+	)
+	evaluationsTruncated = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "eventd_alert_evaluations_truncated_total",
+			Help: "Number of alert evaluations of the network which left events to count in later evaluations",
+		},
+		[]string{metrics.NetworkLabelName},
+	)
+)
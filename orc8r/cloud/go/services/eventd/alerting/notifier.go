@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+const (
+	alertmanagerAPIAlertPath = "/alerts"
+	notifyTimeout            = 10 * time.Second
+)
+
+// Notifier raises alerts.
+type Notifier interface {
+	Notify(ctx context.Context, alerts models.PostableAlerts) error
+}
+
+type alertmanagerNotifier struct {
+	alertsURL string
+	client    *http.Client
+}
+
+// NewAlertmanagerNotifier returns a notifier which posts alerts to the
+// Alertmanager API at the passed URL, such as metricsd's
+// alertmanagerApiURL.
+func NewAlertmanagerNotifier(apiURL string) Notifier {
+	return &alertmanagerNotifier{
+		alertsURL: apiURL + alertmanagerAPIAlertPath,
+		client:    &http.Client{Timeout: notifyTimeout},
+	}
+}
+
+func (n *alertmanagerNotifier) Notify(ctx context.Context, alerts models.PostableAlerts) error {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return errors.Wrap(err, "marshal alerts")
+	}
+	req, err := http.NewRequest(http.MethodPost, n.alertsURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "create alertmanager request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, "post alerts to alertmanager")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("alertmanager error %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
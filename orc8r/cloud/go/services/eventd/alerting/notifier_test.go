@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerting_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"magma/orc8r/cloud/go/services/eventd/alerting"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertmanagerNotifier(t *testing.T) {
+	var received []map[string]interface{}
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/alerts", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	notifier := alerting.NewAlertmanagerNotifier(srv.URL + "/api/v2")
+	alerts := models.PostableAlerts{{Alert: models.Alert{Labels: models.LabelSet{"alertname": "a", "networkID": "n1"}}}}
+	assert.NoError(t, notifier.Notify(context.Background(), alerts))
+	require.Len(t, received, 1)
+	assert.Equal(t, map[string]interface{}{"alertname": "a", "networkID": "n1"}, received[0]["labels"])
+
+	status = http.StatusBadRequest
+	assert.EqualError(t, notifier.Notify(context.Background(), alerts), "alertmanager error 400: ")
+}
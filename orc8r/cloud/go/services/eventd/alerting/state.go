@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerting
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"magma/orc8r/cloud/go/services/eventd/tail"
+	"magma/orc8r/cloud/go/sqorc"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+)
+
+const (
+	statesTableName = "eventd_alert_states"
+
+	networkIDCol = "network_id"
+	positionCol  = "position"
+	skipCol      = "skip"
+	groupsCol    = "group_states"
+)
+
+// NetworkState is the evaluation state of a network's alert rules.
+type NetworkState struct {
+	// Offset after the last event counted for the network
+	Offset tail.Offset
+	Groups []GroupState
+}
+
+// GroupState is the state of the events counted together by a rule.
+type GroupState struct {
+	Rule       string `json:"rule"`
+	Tag        string `json:"tag,omitempty"`
+	HardwareID string `json:"hardware_id,omitempty"`
+	// Timestamps of the latest counted events, oldest first
+	Timestamps []time.Time `json:"timestamps"`
+	// FiringSince is the time the group started firing, zero if it isn't
+	FiringSince time.Time `json:"firing_since"`
+}
+
+// StateStore persists the evaluation state of each network, so evaluation
+// resumes where it left off after a restart or a change of leader.
+type StateStore interface {
+	// GetStates returns the states of all networks, keyed by network ID.
+	GetStates() (map[string]NetworkState, error)
+
+	// SetStates replaces the states of all networks.
+	SetStates(states map[string]NetworkState) error
+}
+
+// SQLStateStore is a StateStore backed by a SQL database.
+//
+// States columns:
+//   - network_id	-- network of the state
+//   - position		-- position of the network's tail offset
+//   - skip			-- skip of the network's tail offset
+//   - group_states	-- JSON-encoded group states
+type SQLStateStore struct {
+	db      *sql.DB
+	builder sqorc.StatementBuilder
+}
+
+// NewSQLStateStore returns a state store backed by the SQL database.
+// Initialize must be called before use.
+func NewSQLStateStore(db *sql.DB, builder sqorc.StatementBuilder) *SQLStateStore {
+	return &SQLStateStore{db: db, builder: builder}
+}
+
+// Initialize the store's table.
+func (s *SQLStateStore) Initialize() error {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		_, err := s.builder.CreateTable(statesTableName).
+			IfNotExists().
+			Column(networkIDCol).Type(sqorc.ColumnTypeText).NotNull().PrimaryKey().EndColumn().
+			Column(positionCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			Column(skipCol).Type(sqorc.ColumnTypeInt).NotNull().EndColumn().
+			Column(groupsCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			RunWith(tx).
+			Exec()
+		return nil, errors.Wrap(err, "initialize alert states table")
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+func (s *SQLStateStore) GetStates() (map[string]NetworkState, error) {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		rows, err := s.builder.Select(networkIDCol, positionCol, skipCol, groupsCol).
+			From(statesTableName).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "select alert states")
+		}
+		defer sqorc.CloseRowsLogOnError(rows, "GetStates")
+
+		ret := map[string]NetworkState{}
+		for rows.Next() {
+			var networkID, groups string
+			var state NetworkState
+			err = rows.Scan(&networkID, &state.Offset.Position, &state.Offset.Skip, &groups)
+			if err != nil {
+				return nil, errors.Wrap(err, "scan alert state")
+			}
+			err = json.Unmarshal([]byte(groups), &state.Groups)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unmarshal alert groups of network %s", networkID)
+			}
+			ret[networkID] = state
+		}
+		return ret, errors.Wrap(rows.Err(), "iterate alert states")
+	}
+	ret, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	if err != nil {
+		return nil, err
+	}
+	return ret.(map[string]NetworkState), nil
+}
+
+func (s *SQLStateStore) SetStates(states map[string]NetworkState) error {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		networkIDs := make([]string, 0, len(states))
+		for networkID, state := range states {
+			networkIDs = append(networkIDs, networkID)
+			groups, err := json.Marshal(state.Groups)
+			if err != nil {
+				return nil, errors.Wrapf(err, "marshal alert groups of network %s", networkID)
+			}
+			_, err = s.builder.Insert(statesTableName).
+				Columns(networkIDCol, positionCol, skipCol, groupsCol).
+				Values(networkID, state.Offset.Position, state.Offset.Skip, string(groups)).
+				OnConflict(
+					[]sqorc.UpsertValue{
+						{Column: positionCol, Value: state.Offset.Position},
+						{Column: skipCol, Value: state.Offset.Skip},
+						{Column: groupsCol, Value: string(groups)},
+					},
+					networkIDCol,
+				).
+				RunWith(tx).
+				Exec()
+			if err != nil {
+				return nil, errors.Wrapf(err, "upsert alert state of network %s", networkID)
+			}
+		}
+		_, err := s.builder.Delete(statesTableName).
+			Where(squirrel.NotEq{networkIDCol: networkIDs}).
+			RunWith(tx).
+			Exec()
+		return nil, errors.Wrap(err, "delete stale alert states")
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventd
+
+import (
+	"context"
+
+	"magma/orc8r/cloud/go/services/eventd/protos"
+	merrors "magma/orc8r/lib/go/errors"
+	"magma/orc8r/lib/go/registry"
+
+	"github.com/golang/glog"
+)
+
+// PutEvents stores a batch of events in eventd's storage backend. Records
+// without a timestamp are timestamped by eventd.
+func PutEvents(ctx context.Context, records []*protos.EventRecord) error {
+	client, err := getEventStoreClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.PutEvents(ctx, &protos.PutEventsRequest{Events: records})
+	return err
+}
+
+// TailEvents streams batches of events matching the request as they're
+// stored, until the context is done.
+func TailEvents(ctx context.Context, req *protos.TailEventsRequest) (protos.EventStore_TailEventsClient, error) {
+	client, err := getEventStoreClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.TailEvents(ctx, req)
+}
+
+func getEventStoreClient() (protos.EventStoreClient, error) {
+	conn, err := registry.GetConnection(ServiceName)
+	if err != nil {
+		initErr := merrors.NewInitError(err, ServiceName)
+		glog.Error(initErr)
+		return nil, initErr
+	}
+	return protos.NewEventStoreClient(conn), nil
+}
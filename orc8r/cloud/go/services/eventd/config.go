@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventd
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultStorageBackend          = "elasticsearch"
+	defaultRetention               = 7 * 24 * time.Hour
+	defaultAlertEvaluationInterval = 15 * time.Second
+
+	// AlertGroupByTag groups alert rule counts by event tag, which is the
+	// subscriber ID of subscriber events
+	AlertGroupByTag = "tag"
+	// AlertGroupByHardwareID groups alert rule counts by the hardware ID of
+	// the gateway which emitted the events
+	AlertGroupByHardwareID = "hardware_id"
+)
+
+// Config represents the configuration provided to the eventd service
+type Config struct {
+	// Storage is the backend storing events, one of "elasticsearch" or
+	// "sql". Log queries are always served by Elasticsearch.
+	Storage string `yaml:"storage"`
+	// Retention bounds the age of events stored by the sql backend.
+	// Elasticsearch indices are expired outside orc8r.
+	Retention time.Duration `yaml:"retention"`
+
+	// AlertEvaluationInterval is the interval at which alert rules are
+	// evaluated against new events.
+	AlertEvaluationInterval time.Duration `yaml:"alert_evaluation_interval"`
+	// AlertRules are evaluated against incoming events, raising alerts to
+	// Alertmanager.
+	AlertRules []AlertRule `yaml:"alert_rules"`
+}
+
+// AlertRule fires an alert when at least threshold matching events of a
+// network are logged within the window, per group.
+type AlertRule struct {
+	// Name is the alertname label of raised alerts.
+	Name string `yaml:"name"`
+	// Networks the rule applies to, all networks if empty.
+	Networks []string `yaml:"networks"`
+	// Streams whose events are counted, all streams if empty.
+	Streams []string `yaml:"streams"`
+	// EventTypes which are counted, all types if empty.
+	EventTypes []string `yaml:"event_types"`
+	// GroupBy lists the event fields events are counted by, each one of
+	// "tag" or "hardware_id". Events of a network are counted together if
+	// empty.
+	GroupBy []string `yaml:"group_by"`
+
+	Window    time.Duration `yaml:"window"`
+	Threshold int           `yaml:"threshold"`
+
+	// Severity is the severity label of raised alerts.
+	Severity string `yaml:"severity"`
+	// Labels and Annotations are added to raised alerts.
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// Validate verifies the rule can be evaluated.
+func (r AlertRule) Validate() error {
+	if r.Name == "" {
+		return errors.New("alert rule must have a name")
+	}
+	if r.Window <= 0 {
+		return errors.Errorf("alert rule %s must have a positive window", r.Name)
+	}
+	if r.Threshold <= 0 {
+		return errors.Errorf("alert rule %s must have a positive threshold", r.Name)
+	}
+	for _, field := range r.GroupBy {
+		if field != AlertGroupByTag && field != AlertGroupByHardwareID {
+			return errors.Errorf("alert rule %s can't group by %q, must be one of %s, %s", r.Name, field, AlertGroupByTag, AlertGroupByHardwareID)
+		}
+	}
+	return nil
+}
+
+// WithDefaults returns the config with unset fields set to their defaults.
+func (c Config) WithDefaults() Config {
+	if c.Storage == "" {
+		c.Storage = defaultStorageBackend
+	}
+	if c.Retention <= 0 {
+		c.Retention = defaultRetention
+	}
+	if c.AlertEvaluationInterval <= 0 {
+		c.AlertEvaluationInterval = defaultAlertEvaluationInterval
+	}
+	return c
+}
@@ -11,6 +11,17 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Package eventd contains the eventd service, which serves queries over
+// events emitted by gateways and orc8r services.
+//
+// Events are stored either in Elasticsearch, where fluentd writes gateway
+// events, or in orc8r's SQL database, for deployments without an
+// Elasticsearch cluster.
 package eventd
 
-const ServiceName = "EVENTD"
+const (
+	ServiceName = "EVENTD"
+
+	// DBTablePrefix prefixes the names of the SQL tables holding events.
+	DBTablePrefix = "eventd"
+)
@@ -14,15 +14,37 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/leader"
 	"magma/orc8r/cloud/go/obsidian"
 	"magma/orc8r/cloud/go/obsidian/swagger"
 	swagger_protos "magma/orc8r/cloud/go/obsidian/swagger/protos"
 	"magma/orc8r/cloud/go/orc8r"
 	"magma/orc8r/cloud/go/service"
+	"magma/orc8r/cloud/go/services/configurator"
 	"magma/orc8r/cloud/go/services/eventd"
+	"magma/orc8r/cloud/go/services/eventd/alerting"
+	"magma/orc8r/cloud/go/services/eventd/eventd_client"
+	"magma/orc8r/cloud/go/services/eventd/ingest"
 	"magma/orc8r/cloud/go/services/eventd/obsidian/handlers"
+	"magma/orc8r/cloud/go/services/eventd/protos"
+	"magma/orc8r/cloud/go/services/eventd/servicers"
+	"magma/orc8r/cloud/go/services/eventd/storage"
+	"magma/orc8r/cloud/go/services/metricsd"
+	"magma/orc8r/cloud/go/sqorc"
+	storage2 "magma/orc8r/cloud/go/storage"
+	"magma/orc8r/lib/go/service/config"
 
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+const (
+	retentionSweepInterval   = time.Hour
+	elasticInitRetryInterval = 30 * time.Second
 )
 
 func main() {
@@ -31,7 +53,28 @@ func main() {
 		glog.Fatalf("Error creating service: %+v", err)
 	}
 
-	obsidian.AttachHandlers(srv.EchoServer, handlers.GetObsidianHandlers())
+	var serviceConfig eventd.Config
+	_, _, err = config.GetStructuredServiceConfig(orc8r.ModuleName, eventd.ServiceName, &serviceConfig)
+	if err != nil {
+		glog.Fatalf("Error parsing eventd service config: %+v", err)
+	}
+	serviceConfig = serviceConfig.WithDefaults()
+
+	logClient, logClientErr := eventd_client.GetElasticClient()
+	store, storeErr := getEventStorage(serviceConfig)
+	if storeErr != nil {
+		glog.Errorf("Error initializing %s event storage: %+v", serviceConfig.Storage, storeErr)
+	} else {
+		protos.RegisterEventStoreServer(srv.GrpcServer, servicers.NewEventStoreServicer(store))
+		startAlertEngine(store, serviceConfig)
+		// Fluentd writes gateway events straight to Elasticsearch, and to
+		// eventd for other backends
+		if serviceConfig.Storage != storage.ElasticBackend {
+			srv.EchoServer.POST(ingest.FluentdPath, ingest.GetFluentdHandler(store))
+		}
+	}
+
+	obsidian.AttachHandlers(srv.EchoServer, handlers.GetObsidianHandlers(store, storeErr, logClient, logClientErr))
 
 	swagger_protos.RegisterSwaggerSpecServer(srv.GrpcServer, swagger.NewSpecServicerFromFile(eventd.ServiceName))
 
@@ -40,3 +83,96 @@ func main() {
 		glog.Fatalf("Error running eventd service: %+v", err)
 	}
 }
+
+func getEventStorage(serviceConfig eventd.Config) (storage.EventStorage, error) {
+	switch serviceConfig.Storage {
+	case storage.ElasticBackend:
+		client, err := eventd_client.GetElasticClient()
+		if err != nil {
+			return nil, err
+		}
+		store := storage.NewElasticEventStorage(client)
+		// Only tails depend on initialization, so don't block queries on it
+		go initializeElasticStorage(store)
+		return store, nil
+	case storage.SQLBackend:
+		db, err := sqorc.Open(storage2.GetSQLDriver(), storage2.GetDatabaseSource())
+		if err != nil {
+			return nil, errors.Wrap(err, "connect to database")
+		}
+		store := storage.NewSQLEventStorage(db, sqorc.GetSqlBuilder())
+		err = store.Initialize()
+		if err != nil {
+			return nil, err
+		}
+		go sweepExpiredEvents(store, serviceConfig.Retention)
+		return store, nil
+	default:
+		return nil, errors.Errorf("unknown event storage backend %s", serviceConfig.Storage)
+	}
+}
+
+// startAlertEngine evaluates the configured alert rules against the stored
+// events, raising alerts to metricsd's Alertmanager.
+func startAlertEngine(store storage.EventStorage, serviceConfig eventd.Config) {
+	if len(serviceConfig.AlertRules) == 0 {
+		return
+	}
+	metricsdConfig, err := config.GetServiceConfig(orc8r.ModuleName, metricsd.ServiceName)
+	if err != nil {
+		glog.Fatalf("Error reading metricsd config for event alerting: %+v", err)
+	}
+	notifier := alerting.NewAlertmanagerNotifier(metricsdConfig.MustGetString(metricsd.AlertmanagerApiURL))
+
+	// Evaluation state and leadership are kept in SQL, whatever the event
+	// storage backend
+	db, err := sqorc.Open(storage2.GetSQLDriver(), storage2.GetDatabaseSource())
+	if err != nil {
+		glog.Fatalf("Error connecting to database for event alerting: %+v", err)
+	}
+	states := alerting.NewSQLStateStore(db, sqorc.GetSqlBuilder())
+	err = states.Initialize()
+	if err != nil {
+		glog.Fatalf("Error initializing event alerting state: %+v", err)
+	}
+	// The lease outlasts the interval at which the leader renews it
+	elector := leader.NewSQLElector(db, sqorc.GetSqlBuilder(), alerting.LeaderElectionName, 2*serviceConfig.AlertEvaluationInterval)
+	err = elector.Initialize()
+	if err != nil {
+		glog.Fatalf("Error initializing event alerting leader election: %+v", err)
+	}
+
+	engine, err := alerting.NewEngine(store, states, elector, notifier, serviceConfig.AlertRules, serviceConfig.AlertEvaluationInterval, configurator.ListNetworkIDs)
+	if err != nil {
+		glog.Fatalf("Error creating event alert engine: %+v", err)
+	}
+	go engine.Run(context.Background())
+}
+
+// initializeElasticStorage initializes the storage, retrying until
+// Elasticsearch is reachable.
+func initializeElasticStorage(store storage.ElasticEventStorage) {
+	for {
+		err := store.Initialize()
+		if err == nil {
+			return
+		}
+		glog.Errorf("Failed to initialize Elasticsearch event storage, retrying: %s", err)
+		time.Sleep(elasticInitRetryInterval)
+	}
+}
+
+// sweepExpiredEvents periodically deletes events older than the retention
+// duration.
+func sweepExpiredEvents(store storage.SQLEventStorage, retention time.Duration) {
+	for range time.Tick(retentionSweepInterval) {
+		n, err := store.DeleteEventsBefore(clock.Now().Add(-retention))
+		if err != nil {
+			glog.Errorf("Failed to delete expired events: %s", err)
+			continue
+		}
+		if n > 0 {
+			glog.Infof("Deleted %d expired events", n)
+		}
+	}
+}
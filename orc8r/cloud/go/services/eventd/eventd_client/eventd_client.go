@@ -45,6 +45,10 @@ const (
 	elasticFilterHardwareID = "hw_id" + dotKeyword
 	elasticFilterEventTag   = "event_tag" + dotKeyword // We use event_tag as fluentd uses the "tag" field
 	elasticFilterTimestamp  = "@timestamp"
+
+	// ElasticIngestTimeField holds the time events were indexed at, set by
+	// eventd's ingest pipeline
+	ElasticIngestTimeField = "ingest_time"
 )
 
 // GetElasticClient parses es config and instanciates a new es client
@@ -160,6 +164,46 @@ func GetEventCount(ctx context.Context, params MultiStreamEventQueryParams, clie
 	return result, nil
 }
 
+// GetIngestedEvents queries es for events matching the params which were
+// indexed in the [start, end) range of Unix milliseconds, in the order they
+// were indexed. It returns the events along with their index times.
+func GetIngestedEvents(ctx context.Context, params MultiStreamEventQueryParams, start, end int64, client *elastic.Client) ([]models.Event, []int64, error) {
+	params.Start, params.End = nil, nil
+	query := params.toElasticBoolQuery().
+		Filter(elastic.NewRangeQuery(ElasticIngestTimeField).Gte(start).Lt(end).Format("epoch_millis"))
+	result, err := client.Search().
+		Index("eventd*").
+		From(params.From).
+		Size(params.Size).
+		Sort(ElasticIngestTimeField, true).
+		Sort(elasticFilterTimestamp, true).
+		Query(query).
+		Do(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if result.Error != nil {
+		return nil, nil, fmt.Errorf("Elastic Error Type: %s, Reason: %s", result.Error.Type, result.Error.Reason)
+	}
+
+	events, err := GetEventResults(result.Hits.Hits)
+	if err != nil {
+		return nil, nil, err
+	}
+	ingestTimes := make([]int64, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		var ingestHit struct {
+			IngestTime time.Time `json:"ingest_time"`
+		}
+		if err := json.Unmarshal(hit.Source, &ingestHit); err != nil {
+			return nil, nil, fmt.Errorf("Unable to Unmarshal ingest time from elastic.Hit. "+
+				"elastic.Hit.Source: %s, Error: %s", hit.Source, err)
+		}
+		ingestTimes = append(ingestTimes, ingestHit.IngestTime.UnixNano()/int64(time.Millisecond))
+	}
+	return events, ingestTimes, nil
+}
+
 func doSearch(ctx context.Context, search *elastic.SearchService) ([]models.Event, error) {
 	result, err := search.Do(ctx)
 	if err != nil {
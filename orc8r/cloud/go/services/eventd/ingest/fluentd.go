@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ingest receives gateway events for eventd's storage backend.
+// Gateways forward events to orc8r's fluentd, which writes them straight to
+// Elasticsearch, or to eventd for the other backends.
+package ingest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/eventd/protos"
+	"magma/orc8r/cloud/go/services/eventd/storage"
+	lib_protos "magma/orc8r/lib/go/protos"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/labstack/echo"
+)
+
+const (
+	// FluentdPath is the path of eventd's internal endpoint receiving
+	// gateway events from fluentd's http output.
+	FluentdPath = "/eventd/v1/fluentd"
+
+	// maxFluentdRequestSize bounds the body of each fluentd request. Larger
+	// requests are rejected, so fluentd's buffer chunks must be smaller.
+	maxFluentdRequestSize = 8 << 20
+)
+
+// fluentdRecord is a gateway event as forwarded by fluentd. Fields are named
+// as by the gateway's eventd and fluent-bit, which adds the hardware and
+// network IDs.
+type fluentdRecord struct {
+	StreamName string `json:"stream_name"`
+	EventType  string `json:"event_type"`
+	Tag        string `json:"event_tag"`
+	// Value is the JSON-encoded event value
+	Value      string `json:"value"`
+	HardwareID string `json:"hw_id"`
+	NetworkID  string `json:"network_id"`
+	// Timestamp is injected by fluentd, in RFC 3339 format
+	Timestamp string `json:"@timestamp"`
+}
+
+// GetFluentdHandler returns a handler storing the gateway events of fluentd
+// http output requests. Request bodies are either a JSON array of records,
+// or newline-delimited records.
+//
+// Invalid records are logged and dropped, since fluentd would otherwise
+// retry them forever. Storage failures return 503, which fluentd retries.
+func GetFluentdHandler(store storage.EventStorage) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		body, err := ioutil.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, maxFluentdRequestSize))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		fluentdRecords, err := decodeFluentdRecords(body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		now := clock.Now()
+		var records []*protos.EventRecord
+		for _, r := range fluentdRecords {
+			record, err := r.toEventRecord(now)
+			if err != nil {
+				glog.Warningf("Dropping invalid event from gateway %q of network %q: %s", r.HardwareID, r.NetworkID, err)
+				continue
+			}
+			records = append(records, record)
+		}
+		if len(records) == 0 {
+			return c.NoContent(http.StatusNoContent)
+		}
+
+		err = store.PutEvents(c.Request().Context(), records)
+		if err != nil {
+			glog.Errorf("Failed to store %d gateway events: %s", len(records), err)
+			return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func decodeFluentdRecords(body []byte) ([]fluentdRecord, error) {
+	body = bytes.TrimSpace(body)
+	var records []fluentdRecord
+	if len(body) > 0 && body[0] == '[' {
+		err := json.Unmarshal(body, &records)
+		return records, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	for {
+		var record fluentdRecord
+		err := dec.Decode(&record)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+}
+
+// toEventRecord converts the fluentd record, timestamping it with now if it
+// has no timestamp.
+func (r fluentdRecord) toEventRecord(now time.Time) (*protos.EventRecord, error) {
+	ts := now
+	if r.Timestamp != "" {
+		var err error
+		ts, err = time.Parse(time.RFC3339Nano, r.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tsProto, err := ptypes.TimestampProto(ts)
+	if err != nil {
+		return nil, err
+	}
+	record := &protos.EventRecord{
+		NetworkId:  r.NetworkID,
+		HardwareId: r.HardwareID,
+		Timestamp:  tsProto,
+		Event: &lib_protos.Event{
+			StreamName: r.StreamName,
+			EventType:  r.EventType,
+			Tag:        r.Tag,
+			Value:      r.Value,
+		},
+	}
+	return record, storage.ValidateRecord(record)
+}
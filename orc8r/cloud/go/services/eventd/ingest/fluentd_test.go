@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingest_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/obsidian"
+	"magma/orc8r/cloud/go/obsidian/tests"
+	"magma/orc8r/cloud/go/services/eventd/ingest"
+	"magma/orc8r/cloud/go/services/eventd/obsidian/handlers"
+	"magma/orc8r/cloud/go/services/eventd/obsidian/models"
+	eventd_test_init "magma/orc8r/cloud/go/services/eventd/test_init"
+
+	"github.com/labstack/echo"
+)
+
+func TestFluentdHandler(t *testing.T) {
+	store := eventd_test_init.StartTestService(t)
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	e := echo.New()
+
+	obsidianHandlers := handlers.GetObsidianHandlers(store, nil, nil, errors.New("elastic isn't configured"))
+	listStreams := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, handlers.EventsRootPath, obsidian.GET).HandlerFunc
+
+	// Gateway events, as forwarded by fluentd. Events without a network are
+	// dropped, and events without a timestamp are timestamped by eventd.
+	tc := tests.Test{
+		Method: "POST",
+		URL:    ingest.FluentdPath,
+		Payload: tests.JSONMarshaler([]map[string]string{
+			{
+				"stream_name": "sessiond",
+				"event_type":  "session_created",
+				"event_tag":   "IMSI1",
+				"value":       `{"apn": "internet"}`,
+				"hw_id":       "hw1",
+				"network_id":  "n1",
+				"gateway_id":  "gw1",
+				"@timestamp":  "1970-01-01T00:15:00.5Z",
+			},
+			{
+				"stream_name": "mme",
+				"event_type":  "attach_success",
+				"event_tag":   "IMSI1",
+				"value":       `{}`,
+				"hw_id":       "hw1",
+				"network_id":  "n1",
+			},
+			{
+				"stream_name": "mme",
+				"event_type":  "attach_success",
+				"value":       `{}`,
+				"hw_id":       "hw2",
+			},
+		}),
+		Handler:        ingest.GetFluentdHandler(store),
+		ExpectedStatus: 204,
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	tc = tests.Test{
+		Method:           "POST",
+		URL:              ingest.FluentdPath,
+		Payload:          tests.JSONMarshaler([]map[string]string{}),
+		MalformedPayload: true,
+		Handler:          ingest.GetFluentdHandler(store),
+		ExpectedStatus:   400,
+		ExpectedError:    "invalid character 'x' looking for beginning of value",
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/events/n1",
+		ParamNames:     []string{"network_id"},
+		ParamValues:    []string{"n1"},
+		Handler:        listStreams,
+		ExpectedStatus: 200,
+		ExpectedResult: tests.JSONMarshaler([]models.Event{
+			{
+				StreamName: "sessiond",
+				EventType:  "session_created",
+				HardwareID: "hw1",
+				Tag:        "IMSI1",
+				Timestamp:  "1970-01-01T00:15:00.5Z",
+				Value:      map[string]interface{}{"apn": "internet"},
+			},
+			{
+				StreamName: "mme",
+				EventType:  "attach_success",
+				HardwareID: "hw1",
+				Tag:        "IMSI1",
+				Timestamp:  "1970-01-01T00:16:40Z",
+				Value:      map[string]interface{}{},
+			},
+		}),
+	}
+	tests.RunUnitTest(t, e, tc)
+}
@@ -23,6 +23,7 @@ import (
 	"magma/orc8r/cloud/go/obsidian"
 	eventdC "magma/orc8r/cloud/go/services/eventd/eventd_client"
 	logH "magma/orc8r/cloud/go/services/eventd/log/handlers"
+	"magma/orc8r/cloud/go/services/eventd/storage"
 
 	"github.com/go-openapi/strfmt"
 	"github.com/golang/glog"
@@ -52,6 +53,7 @@ const (
 	EventsRootPath  = obsidian.V1Root + "events" + obsidian.UrlSep + ":" + pathParamNetworkID
 	EventsPath      = EventsRootPath + obsidian.UrlSep + ":" + pathParamStreamName
 	EventsCountPath = EventsRootPath + obsidian.UrlSep + "about" + obsidian.UrlSep + "count"
+	EventsTailPath  = EventsRootPath + obsidian.UrlSep + "about" + obsidian.UrlSep + "tail"
 
 	ManageNetworkPath  = obsidian.V1Root + "networks" + obsidian.UrlSep + ":network_id"
 	LogSearchQueryPath = ManageNetworkPath + obsidian.UrlSep + "logs" + obsidian.UrlSep + "search"
@@ -59,31 +61,36 @@ const (
 )
 
 // GetObsidianHandlers returns all the obsidian handlers for eventd.
-func GetObsidianHandlers() []obsidian.Handler {
+// Event queries are served by the event storage, and log queries by
+// Elasticsearch. Handlers whose backend failed to initialize respond with
+// the initialization error.
+func GetObsidianHandlers(store storage.EventStorage, storeErr error, logClient *elastic.Client, logClientErr error) []obsidian.Handler {
 	var ret []obsidian.Handler
 
-	client, err := eventdC.GetElasticClient()
-	if err != nil {
-		ret = append(ret, setInitErrorHandlers(err)...)
-		return ret
+	if logClientErr != nil {
+		ret = append(ret, setInitErrorHandlers(logClientErr, LogSearchQueryPath, LogCountQueryPath)...)
+	} else {
+		ret = append(ret, obsidian.Handler{Path: LogSearchQueryPath, Methods: obsidian.GET, HandlerFunc: logH.GetQueryLogHandler(logClient)})
+		ret = append(ret, obsidian.Handler{Path: LogCountQueryPath, Methods: obsidian.GET, HandlerFunc: logH.GetCountLogHandler(logClient)})
 	}
 
-	ret = append(ret, obsidian.Handler{Path: LogSearchQueryPath, Methods: obsidian.GET, HandlerFunc: logH.GetQueryLogHandler(client)})
-	ret = append(ret, obsidian.Handler{Path: LogCountQueryPath, Methods: obsidian.GET, HandlerFunc: logH.GetCountLogHandler(client)})
-	ret = append(ret, obsidian.Handler{Path: EventsRootPath, Methods: obsidian.GET, HandlerFunc: GetMultiStreamEventsHandler(client)})
-	ret = append(ret, obsidian.Handler{Path: EventsCountPath, Methods: obsidian.GET, HandlerFunc: GetEventCountHandler(client)})
-	ret = append(ret, obsidian.Handler{Path: EventsPath, Methods: obsidian.GET, HandlerFunc: GetEventsHandler(client)})
+	if storeErr != nil {
+		ret = append(ret, setInitErrorHandlers(storeErr, EventsRootPath, EventsPath, EventsCountPath, EventsTailPath)...)
+	} else {
+		ret = append(ret, obsidian.Handler{Path: EventsRootPath, Methods: obsidian.GET, HandlerFunc: GetMultiStreamEventsHandler(store)})
+		ret = append(ret, obsidian.Handler{Path: EventsCountPath, Methods: obsidian.GET, HandlerFunc: GetEventCountHandler(store)})
+		ret = append(ret, obsidian.Handler{Path: EventsTailPath, Methods: obsidian.GET, HandlerFunc: GetTailEventsHandler(store)})
+		ret = append(ret, obsidian.Handler{Path: EventsPath, Methods: obsidian.GET, HandlerFunc: GetEventsHandler(store)})
+	}
 	return ret
 }
 
-func setInitErrorHandlers(err error) []obsidian.Handler {
-	return []obsidian.Handler{
-		{Path: LogSearchQueryPath, Methods: obsidian.GET, HandlerFunc: getInitErrorHandler(err)},
-		{Path: LogCountQueryPath, Methods: obsidian.GET, HandlerFunc: getInitErrorHandler(err)},
-		{Path: EventsRootPath, Methods: obsidian.GET, HandlerFunc: getInitErrorHandler(err)},
-		{Path: EventsPath, Methods: obsidian.GET, HandlerFunc: getInitErrorHandler(err)},
-		{Path: EventsCountPath, Methods: obsidian.GET, HandlerFunc: getInitErrorHandler(err)},
+func setInitErrorHandlers(err error, paths ...string) []obsidian.Handler {
+	var ret []obsidian.Handler
+	for _, path := range paths {
+		ret = append(ret, obsidian.Handler{Path: path, Methods: obsidian.GET, HandlerFunc: getInitErrorHandler(err)})
 	}
+	return ret
 }
 
 func getInitErrorHandler(err error) func(c echo.Context) error {
@@ -92,37 +99,37 @@ func getInitErrorHandler(err error) func(c echo.Context) error {
 	}
 }
 
-// GetEventsHandler returns a Handler that uses the provided elastic client
-func GetEventsHandler(client *elastic.Client) func(c echo.Context) error {
+// GetEventsHandler returns a Handler that uses the provided event storage
+func GetEventsHandler(store storage.EventStorage) func(c echo.Context) error {
 	return func(c echo.Context) error {
-		return EventsHandler(c, client)
+		return EventsHandler(c, store)
 	}
 }
 
-// GetMultiStreamEventsHandler returns a handler for the multi-stream elastic
+// GetMultiStreamEventsHandler returns a handler for the multi-stream
 // event query endpoint.
-func GetMultiStreamEventsHandler(client *elastic.Client) func(c echo.Context) error {
+func GetMultiStreamEventsHandler(store storage.EventStorage) func(c echo.Context) error {
 	return func(c echo.Context) error {
-		return MultiStreamEventsHandler(c, client)
+		return MultiStreamEventsHandler(c, store)
 	}
 }
 
-// GetEventCountHandler returns a handler for multi-stream elastic
+// GetEventCountHandler returns a handler for multi-stream
 // event count query endpoint.
-func GetEventCountHandler(client *elastic.Client) func(c echo.Context) error {
+func GetEventCountHandler(store storage.EventStorage) func(c echo.Context) error {
 	return func(c echo.Context) error {
-		return EventCountHandler(c, client)
+		return EventCountHandler(c, store)
 	}
 }
 
-// EventsHandler handles event querying using ES
-func EventsHandler(c echo.Context, client *elastic.Client) error {
+// EventsHandler handles single-stream event queries
+func EventsHandler(c echo.Context, store storage.EventStorage) error {
 	queryParams, err := getQueryParameters(c)
 	if err != nil {
 		return obsidian.HttpError(err, http.StatusBadRequest)
 	}
 
-	results, err := eventdC.GetEvents(c.Request().Context(), queryParams, client)
+	results, err := store.GetEvents(c.Request().Context(), queryParams)
 	if err != nil {
 		glog.Error(err)
 		return obsidian.HttpError(err, http.StatusInternalServerError)
@@ -134,13 +141,13 @@ func EventsHandler(c echo.Context, client *elastic.Client) error {
 // primarily the ability to query across multiple streams and tags.
 // This handler will also accept an optional query size limit and offset for
 // paginated queries.
-func MultiStreamEventsHandler(c echo.Context, client *elastic.Client) error {
+func MultiStreamEventsHandler(c echo.Context, store storage.EventStorage) error {
 	params, err := getMultiStreamQueryParameters(c)
 	if err != nil {
 		return obsidian.HttpError(err, http.StatusBadRequest)
 	}
 
-	results, err := eventdC.GetMultiStreamEvents(c.Request().Context(), params, client)
+	results, err := store.GetMultiStreamEvents(c.Request().Context(), params)
 	if err != nil {
 		glog.Error(err)
 		return obsidian.HttpError(err, http.StatusInternalServerError)
@@ -148,14 +155,14 @@ func MultiStreamEventsHandler(c echo.Context, client *elastic.Client) error {
 	return c.JSON(http.StatusOK, results)
 }
 
-// EventCountHandler handles event counting queries using ES
-func EventCountHandler(c echo.Context, client *elastic.Client) error {
+// EventCountHandler handles event counting queries
+func EventCountHandler(c echo.Context, store storage.EventStorage) error {
 	params, err := getMultiStreamQueryParameters(c)
 	if err != nil {
 		return obsidian.HttpError(err, http.StatusBadRequest)
 	}
 
-	result, err := eventdC.GetEventCount(c.Request().Context(), params, client)
+	result, err := store.GetEventCount(c.Request().Context(), params)
 	if err != nil {
 		glog.Error(err)
 		return obsidian.HttpError(err, http.StatusInternalServerError)
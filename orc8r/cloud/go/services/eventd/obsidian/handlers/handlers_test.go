@@ -14,14 +14,26 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/obsidian"
+	"magma/orc8r/cloud/go/obsidian/tests"
+	"magma/orc8r/cloud/go/services/eventd"
 	eventdC "magma/orc8r/cloud/go/services/eventd/eventd_client"
+	"magma/orc8r/cloud/go/services/eventd/obsidian/models"
+	"magma/orc8r/cloud/go/services/eventd/protos"
+	eventd_test_init "magma/orc8r/cloud/go/services/eventd/test_init"
+	lib_protos "magma/orc8r/lib/go/protos"
 
 	"github.com/labstack/echo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type queryParamTestCase struct {
@@ -182,3 +194,156 @@ func runMultiStreamQueryParamTestCase(t *testing.T, tc queryMultiStreamParamTest
 		assert.Equal(t, tc.expectedParams, params)
 	}
 }
+
+func TestEventHandlers_SQLStorage(t *testing.T) {
+	store := eventd_test_init.StartTestService(t)
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	e := echo.New()
+
+	obsidianHandlers := GetObsidianHandlers(store, nil, nil, errors.New("elastic isn't configured"))
+	listStream := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, EventsPath, obsidian.GET).HandlerFunc
+	listStreams := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, EventsRootPath, obsidian.GET).HandlerFunc
+	countEvents := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, EventsCountPath, obsidian.GET).HandlerFunc
+	searchLogs := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, LogSearchQueryPath, obsidian.GET).HandlerFunc
+
+	// Events are timestamped by eventd
+	err := eventd.PutEvents(context.Background(), []*protos.EventRecord{
+		{NetworkId: "n1", HardwareId: "hw1", Event: &lib_protos.Event{StreamName: "sessiond", EventType: "session_created", Tag: "IMSI1", Value: `{"apn": "internet"}`}},
+		{NetworkId: "n1", HardwareId: "hw1", Event: &lib_protos.Event{StreamName: "mme", EventType: "attach_success", Tag: "IMSI1", Value: `{}`}},
+		{NetworkId: "n2", Event: &lib_protos.Event{StreamName: "sessiond", EventType: "session_created", Value: `{}`}},
+	})
+	require.NoError(t, err)
+
+	// Invalid value
+	err = eventd.PutEvents(context.Background(), []*protos.EventRecord{
+		{NetworkId: "n1", Event: &lib_protos.Event{StreamName: "mme", Value: "not json"}},
+	})
+	assert.Error(t, err)
+
+	sessionCreated := models.Event{
+		StreamName: "sessiond",
+		EventType:  "session_created",
+		HardwareID: "hw1",
+		Tag:        "IMSI1",
+		Timestamp:  "1970-01-01T00:16:40Z",
+		Value:      map[string]interface{}{"apn": "internet"},
+	}
+	attachSuccess := models.Event{
+		StreamName: "mme",
+		EventType:  "attach_success",
+		HardwareID: "hw1",
+		Tag:        "IMSI1",
+		Timestamp:  "1970-01-01T00:16:40Z",
+		Value:      map[string]interface{}{},
+	}
+
+	tc := tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/events/n1/sessiond",
+		ParamNames:     []string{"network_id", "stream_name"},
+		ParamValues:    []string{"n1", "sessiond"},
+		Handler:        listStream,
+		ExpectedStatus: 200,
+		ExpectedResult: tests.JSONMarshaler([]models.Event{sessionCreated}),
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/events/n1?streams=sessiond,mme&tags=IMSI1",
+		ParamNames:     []string{"network_id"},
+		ParamValues:    []string{"n1"},
+		Handler:        listStreams,
+		ExpectedStatus: 200,
+		ExpectedResult: tests.JSONMarshaler([]models.Event{sessionCreated, attachSuccess}),
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/events/n1/about/count?events=attach_success",
+		ParamNames:     []string{"network_id"},
+		ParamValues:    []string{"n1"},
+		Handler:        countEvents,
+		ExpectedStatus: 200,
+		ExpectedResult: tests.JSONMarshaler(1),
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Log queries still require elastic
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/networks/n1/logs/search",
+		ParamNames:     []string{"network_id"},
+		ParamValues:    []string{"n1"},
+		Handler:        searchLogs,
+		ExpectedStatus: 500,
+		ExpectedError:  "initialization Error: elastic isn't configured",
+	}
+	tests.RunUnitTest(t, e, tc)
+}
+
+func TestTailEventsHandler(t *testing.T) {
+	store := eventd_test_init.StartTestService(t)
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	err := eventd.PutEvents(context.Background(), []*protos.EventRecord{
+		{NetworkId: "n1", HardwareId: "hw1", Event: &lib_protos.Event{StreamName: "mme", EventType: "attach_failure", Tag: "IMSI1", Value: `{}`}},
+		{NetworkId: "n1", HardwareId: "hw2", Event: &lib_protos.Event{StreamName: "mme", EventType: "attach_failure", Tag: "IMSI2", Value: `{}`}},
+		{NetworkId: "n1", HardwareId: "hw1", Event: &lib_protos.Event{StreamName: "mme", EventType: "attach_success", Tag: "IMSI1", Value: `{}`}},
+	})
+	require.NoError(t, err)
+
+	tailEvents := func(url string, headers map[string]string) (int, string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		req := httptest.NewRequest(echo.GET, url, nil).WithContext(ctx)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.SetParamNames("network_id")
+		c.SetParamValues("n1")
+		err := TailEventsHandler(c, store)
+		if httpErr, ok := err.(*echo.HTTPError); ok {
+			return httpErr.Code, fmt.Sprint(httpErr.Message)
+		}
+		require.NoError(t, err)
+		assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+		return rec.Code, rec.Body.String()
+	}
+
+	// Events logged before the stream started aren't streamed by default
+	clock.SetAndFreezeClock(t, time.Unix(1001, 0))
+	code, body := tailEvents("/magma/v1/events/n1/about/tail", nil)
+	assert.Equal(t, 200, code)
+	assert.Empty(t, body)
+
+	// From an offset, filtered
+	code, body = tailEvents("/magma/v1/events/n1/about/tail?offset=0-0&events=attach_failure&hw_ids=hw1,hw2", nil)
+	assert.Equal(t, 200, code)
+	assert.Equal(t,
+		"id: 1-1\n"+
+			`data: {"event_type":"attach_failure","hardware_id":"hw1","stream_name":"mme","tag":"IMSI1","timestamp":"1970-01-01T00:16:40Z","value":{}}`+"\n\n"+
+			"id: 2-1\n"+
+			`data: {"event_type":"attach_failure","hardware_id":"hw2","stream_name":"mme","tag":"IMSI2","timestamp":"1970-01-01T00:16:40Z","value":{}}`+"\n\n",
+		body,
+	)
+
+	// Resumed after the last event ID, which takes precedence over the
+	// offset param
+	code, body = tailEvents("/magma/v1/events/n1/about/tail?offset=0-0", map[string]string{"Last-Event-ID": "2-1"})
+	assert.Equal(t, 200, code)
+	assert.Equal(t,
+		"id: 3-1\n"+
+			`data: {"event_type":"attach_success","hardware_id":"hw1","stream_name":"mme","tag":"IMSI1","timestamp":"1970-01-01T00:16:40Z","value":{}}`+"\n\n",
+		body,
+	)
+
+	code, body = tailEvents("/magma/v1/events/n1/about/tail?offset=bad", nil)
+	assert.Equal(t, 400, code)
+	assert.Contains(t, body, "invalid tail offset")
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"magma/orc8r/cloud/go/obsidian"
+	"magma/orc8r/cloud/go/services/eventd/storage"
+	"magma/orc8r/cloud/go/services/eventd/tail"
+
+	"github.com/golang/glog"
+	"github.com/labstack/echo"
+	"github.com/pkg/errors"
+)
+
+const (
+	queryParamOffset  = "offset"
+	headerLastEventID = "Last-Event-ID"
+
+	contentTypeEventStream = "text/event-stream"
+
+	// sseHeartbeatInterval is the max interval between writes to a tail's
+	// event stream, so idle streams aren't closed by proxies
+	sseHeartbeatInterval = 15 * time.Second
+)
+
+// GetTailEventsHandler returns a handler which streams the events of a
+// network as server-sent events.
+func GetTailEventsHandler(store storage.EventStorage) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		return TailEventsHandler(c, store)
+	}
+}
+
+// TailEventsHandler streams the events of a network matching the query as
+// server-sent events, as they're stored.
+// The ID of each server-sent event is the offset to resume tailing after
+// it, which clients pass back as the Last-Event-ID header or the offset query
+// param when reconnecting. Without an offset, tailing starts from the
+// current end of the storage.
+func TailEventsHandler(c echo.Context, store storage.EventStorage) error {
+	filter, offset, err := getTailParameters(c)
+	if err != nil {
+		return obsidian.HttpError(err, http.StatusBadRequest)
+	}
+	if offset == nil {
+		newest, err := tail.NewestOffset(c.Request().Context(), store)
+		if err != nil {
+			return obsidian.HttpError(err, http.StatusInternalServerError)
+		}
+		offset = &newest
+	}
+	if !tail.Acquire() {
+		return obsidian.HttpError(errors.New("too many concurrent event tails"), http.StatusServiceUnavailable)
+	}
+	defer tail.Release()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, contentTypeEventStream)
+	res.Header().Set("Cache-Control", "no-cache")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	tailer := tail.NewTailer(store, filter, *offset)
+	ctx := c.Request().Context()
+	for {
+		events, err := tailer.Next(ctx, sseHeartbeatInterval)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			glog.Error(err)
+			writeSSE(res, "event: error\ndata: %s\n\n", strings.ReplaceAll(err.Error(), "\n", " "))
+			return nil
+		}
+		if len(events) == 0 {
+			err = writeSSE(res, ": heartbeat\n\n")
+		} else {
+			err = writeTailedEvents(res, events)
+		}
+		if err != nil {
+			// Stop tailing once the stream can't be written
+			return nil
+		}
+	}
+}
+
+func writeTailedEvents(res *echo.Response, events []tail.TailedEvent) error {
+	for _, event := range events {
+		data, err := json.Marshal(event.Event)
+		if err != nil {
+			return errors.Wrap(err, "marshal tailed event")
+		}
+		_, err = fmt.Fprintf(res, "id: %s\ndata: %s\n\n", event.Offset, data)
+		if err != nil {
+			return err
+		}
+	}
+	res.Flush()
+	return nil
+}
+
+// getTailParameters returns the tail's filter, and its offset if any.
+func getTailParameters(c echo.Context) (tail.Filter, *tail.Offset, error) {
+	networkID, nerr := obsidian.GetNetworkId(c)
+	if nerr != nil {
+		return tail.Filter{}, nil, nerr
+	}
+	filter := tail.Filter{
+		NetworkID:   networkID,
+		Streams:     getStringListParam(c, pathParamStreams),
+		Events:      getStringListParam(c, pathParamEvents),
+		HardwareIDs: getStringListParam(c, pathParamHardwareIDs),
+		Tags:        getStringListParam(c, pathParamTags),
+	}
+
+	offsetStr := c.Request().Header.Get(headerLastEventID)
+	if offsetStr == "" {
+		offsetStr = c.QueryParam(queryParamOffset)
+	}
+	if offsetStr == "" {
+		return filter, nil, nil
+	}
+	offset, err := tail.ParseOffset(offsetStr)
+	if err != nil {
+		return filter, nil, err
+	}
+	return filter, &offset, nil
+}
+
+func writeSSE(res *echo.Response, format string, args ...interface{}) error {
+	_, err := fmt.Fprintf(res, format, args...)
+	if err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicers
+
+import (
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/eventd/protos"
+	"magma/orc8r/cloud/go/services/eventd/storage"
+	"magma/orc8r/cloud/go/services/eventd/tail"
+	lib_protos "magma/orc8r/lib/go/protos"
+
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// tailMaxWait bounds each wait for tailed events, so tails of closed streams
+// are noticed
+const tailMaxWait = 30 * time.Second
+
+type eventStoreServicer struct {
+	store storage.EventStorage
+}
+
+// NewEventStoreServicer returns a servicer which stores events in the passed
+// storage.
+func NewEventStoreServicer(store storage.EventStorage) protos.EventStoreServer {
+	return &eventStoreServicer{store: store}
+}
+
+func (e *eventStoreServicer) PutEvents(ctx context.Context, req *protos.PutEventsRequest) (*lib_protos.Void, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "nil request")
+	}
+	now, err := ptypes.TimestampProto(clock.Now())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "convert timestamp: %s", err)
+	}
+	for i, record := range req.Events {
+		if record != nil && record.Timestamp == nil {
+			record.Timestamp = now
+		}
+		err = storage.ValidateRecord(record)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid event @ index %d: %s", i, err)
+		}
+	}
+
+	err = e.store.PutEvents(ctx, req.Events)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "store events: %s", err)
+	}
+	return &lib_protos.Void{}, nil
+}
+
+func (e *eventStoreServicer) TailEvents(req *protos.TailEventsRequest, stream protos.EventStore_TailEventsServer) error {
+	if req == nil || req.NetworkId == "" {
+		return status.Error(codes.InvalidArgument, "tail request must have a network ID")
+	}
+	var offset tail.Offset
+	var err error
+	if req.Offset != "" {
+		offset, err = tail.ParseOffset(req.Offset)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	} else {
+		offset, err = tail.NewestOffset(stream.Context(), e.store)
+		if err != nil {
+			return status.Errorf(codes.Internal, "tail events: %s", err)
+		}
+	}
+	if !tail.Acquire() {
+		return status.Error(codes.ResourceExhausted, "too many concurrent event tails")
+	}
+	defer tail.Release()
+
+	filter := tail.Filter{
+		NetworkID:   req.NetworkId,
+		Streams:     req.Streams,
+		Events:      req.EventTypes,
+		HardwareIDs: req.HardwareIds,
+		Tags:        req.Tags,
+	}
+	tailer := tail.NewTailer(e.store, filter, offset)
+	ctx := stream.Context()
+	for {
+		events, err := tailer.Next(ctx, tailMaxWait)
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "tail events: %s", err)
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		res := &protos.TailEventsResponse{Offset: tailer.Offset().String()}
+		for _, event := range events {
+			record, err := event.ToRecord(req.NetworkId)
+			if err != nil {
+				return status.Errorf(codes.Internal, "convert tailed event: %s", err)
+			}
+			res.Events = append(res.Events, record)
+		}
+		err = stream.Send(res)
+		if err != nil {
+			return err
+		}
+	}
+}
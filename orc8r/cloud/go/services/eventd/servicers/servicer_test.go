@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/eventd"
+	"magma/orc8r/cloud/go/services/eventd/protos"
+	eventd_test_init "magma/orc8r/cloud/go/services/eventd/test_init"
+	"magma/orc8r/cloud/go/services/eventd/test_utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEventStoreServicer(t *testing.T) {
+	eventd_test_init.StartTestService(t)
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Invalid events
+	err := eventd.PutEvents(ctx, []*protos.EventRecord{{NetworkId: "n1"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	err = eventd.PutEvents(ctx, []*protos.EventRecord{test_utils.NewEventRecord(t, "", "mme", "attach_failure", "hw1", "IMSI1", time.Time{})})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Invalid tail offset
+	stream, err := eventd.TailEvents(ctx, &protos.TailEventsRequest{NetworkId: "n1", Offset: "1000"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Tail from the first offset, filtered
+	stream, err = eventd.TailEvents(ctx, &protos.TailEventsRequest{NetworkId: "n1", Streams: []string{"mme"}, Offset: "0-0"})
+	require.NoError(t, err)
+	err = eventd.PutEvents(ctx, []*protos.EventRecord{
+		test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw1", "IMSI1", time.Time{}),
+		test_utils.NewEventRecord(t, "n1", "sessiond", "session_created", "hw1", "IMSI1", time.Time{}),
+		test_utils.NewEventRecord(t, "n2", "mme", "attach_failure", "hw1", "IMSI1", time.Time{}),
+	})
+	require.NoError(t, err)
+
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "1-1", res.Offset)
+	require.Len(t, res.Events, 1)
+	expected := test_utils.NewEventRecord(t, "n1", "mme", "attach_failure", "hw1", "IMSI1", time.Time{})
+	expected.Timestamp = res.Events[0].Timestamp
+	assert.Equal(t, expected.String(), res.Events[0].String())
+	assert.Equal(t, int64(1000), res.Events[0].Timestamp.Seconds)
+
+	// Resume after the offset
+	err = eventd.PutEvents(ctx, []*protos.EventRecord{test_utils.NewEventRecord(t, "n1", "mme", "detach", "hw1", "IMSI1", time.Time{})})
+	require.NoError(t, err)
+	stream, err = eventd.TailEvents(ctx, &protos.TailEventsRequest{NetworkId: "n1", Offset: res.Offset})
+	require.NoError(t, err)
+	res, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "4-1", res.Offset)
+	require.Len(t, res.Events, 2)
+	assert.Equal(t, "session_created", res.Events[0].Event.EventType)
+	assert.Equal(t, "detach", res.Events[1].Event.EventType)
+}
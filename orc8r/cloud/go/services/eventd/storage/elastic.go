@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/eventd/eventd_client"
+	"magma/orc8r/cloud/go/services/eventd/obsidian/models"
+	"magma/orc8r/cloud/go/services/eventd/protos"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/olivere/elastic/v7"
+	"github.com/pkg/errors"
+)
+
+const (
+	// elasticIndexPrefix matches the index prefix under which fluentd
+	// writes eventd events
+	elasticIndexPrefix = "eventd-"
+	elasticIndexLayout = "2006.01.02"
+
+	// elasticIngestPipeline stamps eventd documents with the time they're
+	// indexed at, whether written by eventd or fluentd
+	elasticIngestPipeline = "eventd-ingest-time"
+	// elasticTailSettleDelay bounds the time between an event being
+	// stamped by the ingest pipeline and it becoming searchable. Tails
+	// only return events stamped before this delay, so events stamped at
+	// the same time are returned together.
+	elasticTailSettleDelay = 5 * time.Second
+)
+
+// ElasticEventStorage is an EventStorage backed by Elasticsearch, which must
+// be initialized before use.
+type ElasticEventStorage interface {
+	EventStorage
+
+	// Initialize installs the ingest pipeline stamping events with the time
+	// they're indexed at, which tails follow.
+	Initialize() error
+}
+
+type elasticEventStorage struct {
+	client *elastic.Client
+}
+
+// NewElasticEventStorage returns an Elasticsearch-backed implementation of
+// EventStorage. Events are written to the same daily indices as fluentd
+// writes gateway events to.
+func NewElasticEventStorage(client *elastic.Client) ElasticEventStorage {
+	return &elasticEventStorage{client: client}
+}
+
+// Initialize installs the ingest pipeline, and makes it the default
+// pipeline of current and future eventd indices.
+func (e *elasticEventStorage) Initialize() error {
+	ctx := context.Background()
+	pipeline := map[string]interface{}{
+		"description": "Stamps eventd events with the time they're indexed at",
+		"processors": []interface{}{
+			map[string]interface{}{"set": map[string]interface{}{
+				"field": eventd_client.ElasticIngestTimeField,
+				"value": "{{_ingest.timestamp}}",
+			}},
+		},
+	}
+	_, err := e.client.IngestPutPipeline(elasticIngestPipeline).BodyJson(pipeline).Do(ctx)
+	if err != nil {
+		return errors.Wrap(err, "put event ingest pipeline")
+	}
+
+	settings := map[string]interface{}{"index.default_pipeline": elasticIngestPipeline}
+	template := map[string]interface{}{
+		"index_patterns": []string{elasticIndexPrefix + "*"},
+		"settings":       settings,
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				eventd_client.ElasticIngestTimeField: map[string]interface{}{"type": "date"},
+			},
+		},
+	}
+	_, err = e.client.IndexPutTemplate(elasticIngestPipeline).BodyJson(template).Do(ctx)
+	if err != nil {
+		return errors.Wrap(err, "put event index template")
+	}
+	_, err = e.client.IndexPutSettings(elasticIndexPrefix + "*").BodyJson(settings).Do(ctx)
+	return errors.Wrap(err, "set pipeline of event indices")
+}
+
+func (e *elasticEventStorage) PutEvents(ctx context.Context, records []*protos.EventRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	bulk := e.client.Bulk()
+	for _, record := range records {
+		if err := ValidateRecord(record); err != nil {
+			return err
+		}
+		ts, _ := ptypes.Timestamp(record.Timestamp)
+		doc := map[string]interface{}{
+			"stream_name": record.Event.StreamName,
+			"event_type":  record.Event.EventType,
+			"network_id":  record.NetworkId,
+			"hw_id":       record.HardwareId,
+			"event_tag":   record.Event.Tag,
+			"@timestamp":  formatTimestamp(ts),
+			// eventd events carry their value as a JSON string
+			"value": record.Event.Value,
+		}
+		req := elastic.NewBulkIndexRequest().
+			Index(elasticIndexPrefix + ts.UTC().Format(elasticIndexLayout)).
+			Doc(doc)
+		// Indexing a document with the ID of a stored one replaces it
+		if record.EventId != "" {
+			req = req.Id(record.NetworkId + "/" + record.EventId)
+		}
+		bulk.Add(req)
+	}
+	res, err := bulk.Do(ctx)
+	if err != nil {
+		return errors.Wrap(err, "index events")
+	}
+	if failed := res.Failed(); len(failed) > 0 {
+		reason := "unknown"
+		if failed[0].Error != nil {
+			reason = failed[0].Error.Reason
+		}
+		return errors.Errorf("failed to index %d of %d events, first failure: %s", len(failed), len(records), reason)
+	}
+	return nil
+}
+
+func (e *elasticEventStorage) GetEvents(ctx context.Context, params eventd_client.EventQueryParams) ([]models.Event, error) {
+	return eventd_client.GetEvents(ctx, params, e.client)
+}
+
+func (e *elasticEventStorage) GetMultiStreamEvents(ctx context.Context, params eventd_client.MultiStreamEventQueryParams) ([]models.Event, error) {
+	return eventd_client.GetMultiStreamEvents(ctx, params, e.client)
+}
+
+func (e *elasticEventStorage) GetEventCount(ctx context.Context, params eventd_client.MultiStreamEventQueryParams) (int64, error) {
+	return eventd_client.GetEventCount(ctx, params, e.client)
+}
+
+// TailEvents returns events by the time they were indexed at, in Unix
+// milliseconds.
+func (e *elasticEventStorage) TailEvents(ctx context.Context, params eventd_client.MultiStreamEventQueryParams, position int64) ([]PositionedEvent, error) {
+	end := toMillis(clock.Now().Add(-elasticTailSettleDelay))
+	events, ingestTimes, err := eventd_client.GetIngestedEvents(ctx, params, position, end, e.client)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]PositionedEvent, 0, len(events))
+	for i, event := range events {
+		ret = append(ret, PositionedEvent{Event: event, Position: ingestTimes[i]})
+	}
+	return ret, nil
+}
+
+func (e *elasticEventStorage) GetTailPosition(ctx context.Context) (int64, error) {
+	return toMillis(clock.Now().Add(-elasticTailSettleDelay)), nil
+}
@@ -0,0 +1,733 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"magma/orc8r/cloud/go/services/eventd"
+	"magma/orc8r/cloud/go/services/eventd/eventd_client"
+	"magma/orc8r/cloud/go/services/eventd/obsidian/models"
+	"magma/orc8r/cloud/go/services/eventd/protos"
+	"magma/orc8r/cloud/go/sqorc"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+)
+
+const (
+	partitionsTableName = eventd.DBTablePrefix + "_partitions"
+	sequenceTableName   = eventd.DBTablePrefix + "_sequence"
+	eventsTablePrefix   = eventd.DBTablePrefix + "_events_"
+	partitionTableDay   = "20060102"
+
+	dayCol        = "day"
+	maxSeqCol     = "max_seq"
+	idCol         = "id"
+	seqCol        = "seq"
+	networkIDCol  = "network_id"
+	streamNameCol = "stream_name"
+	eventTypeCol  = "event_type"
+	hardwareIDCol = "hardware_id"
+	tagCol        = "tag"
+	timestampCol  = "timestamp"
+	valueCol      = "value"
+	eventIDCol    = "event_id"
+
+	millisPerDay = int64(24 * time.Hour / time.Millisecond)
+
+	// singleStreamQuerySize matches the number of events returned by
+	// single-stream Elasticsearch queries
+	singleStreamQuerySize = 50
+	// maxInsertBatchSize bounds the rows per insert statement, to stay
+	// under the SQL drivers' limits on statement parameters
+	maxInsertBatchSize = 100
+)
+
+// SQLEventStorage is an EventStorage backed by a SQL database, which must be
+// initialized before use and whose retention is bounded by the caller.
+type SQLEventStorage interface {
+	EventStorage
+
+	// Initialize the backing store.
+	Initialize() error
+
+	// DeleteEventsBefore deletes events older than the passed time,
+	// returning the number of events deleted.
+	DeleteEventsBefore(t time.Time) (int64, error)
+}
+
+// sqlEventStorage stores events in SQL tables partitioned by UTC day.
+//
+// Each day's events are held in their own table, named
+// eventd_events_YYYYMMDD, so expired events are deleted by dropping whole
+// tables. The eventd_partitions table lists the days which have a table,
+// along with the greatest sequence number of their events.
+//
+// Events are numbered in the order they're stored, by the single row of the
+// eventd_sequence table. Each insert holds the row's lock until it commits,
+// so events become visible in sequence order, and tails can resume after a
+// sequence number without missing events stored late.
+//
+// Events with an ID are unique by network and ID within their partition.
+// Storing an event again replaces the stored event's columns but keeps its
+// sequence number, so retried puts are neither stored nor tailed twice.
+//
+// Partition table columns:
+//   - network_id	-- network of the event
+//   - stream_name	-- stream of the event
+//   - event_type	-- type of the event
+//   - hardware_id	-- hardware ID of the emitting gateway, or empty
+//   - tag			-- tag of the event
+//   - timestamp		-- Unix milliseconds of the event
+//   - value			-- JSON value of the event
+//   - seq			-- sequence number of the event
+//   - event_id		-- ID of the event within its network, or null
+type sqlEventStorage struct {
+	db      *sql.DB
+	builder sqorc.StatementBuilder
+}
+
+// NewSQLEventStorage returns a SQL-backed implementation of EventStorage.
+func NewSQLEventStorage(db *sql.DB, builder sqorc.StatementBuilder) SQLEventStorage {
+	return &sqlEventStorage{db: db, builder: builder}
+}
+
+func (s *sqlEventStorage) Initialize() error {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		_, err := s.builder.CreateTable(partitionsTableName).
+			IfNotExists().
+			Column(dayCol).Type(sqorc.ColumnTypeBigInt).NotNull().PrimaryKey().EndColumn().
+			Column(maxSeqCol).Type(sqorc.ColumnTypeBigInt).NotNull().Default(0).EndColumn().
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize event partitions table")
+		}
+
+		_, err = s.builder.CreateTable(sequenceTableName).
+			IfNotExists().
+			Column(idCol).Type(sqorc.ColumnTypeInt).NotNull().PrimaryKey().EndColumn().
+			Column(seqCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize event sequence table")
+		}
+		_, err = s.builder.Insert(sequenceTableName).
+			Columns(idCol, seqCol).
+			Values(0, 0).
+			OnConflict(nil, idCol).
+			RunWith(tx).
+			Exec()
+		return nil, errors.Wrap(err, "initialize event sequence")
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+func (s *sqlEventStorage) PutEvents(ctx context.Context, records []*protos.EventRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	for _, record := range records {
+		if err := ValidateRecord(record); err != nil {
+			return err
+		}
+	}
+	records = dedupeRecords(records)
+	recordsByDay := map[int64][]*protos.EventRecord{}
+	for _, record := range records {
+		ts, _ := ptypes.Timestamp(record.Timestamp)
+		day := toMillis(ts) / millisPerDay
+		recordsByDay[day] = append(recordsByDay[day], record)
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		seq, err := s.claimSequence(tx, len(records))
+		if err != nil {
+			return nil, err
+		}
+		for day, dayRecords := range recordsByDay {
+			err = s.createPartition(tx, day)
+			if err != nil {
+				return nil, err
+			}
+			for len(dayRecords) > 0 {
+				n := len(dayRecords)
+				if n > maxInsertBatchSize {
+					n = maxInsertBatchSize
+				}
+				err = s.insertEvents(tx, day, seq, dayRecords[:n])
+				if err != nil {
+					return nil, err
+				}
+				dayRecords = dayRecords[n:]
+				seq += int64(n)
+			}
+			err = s.setPartitionMaxSeq(tx, day, seq-1)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+func (s *sqlEventStorage) GetEvents(ctx context.Context, params eventd_client.EventQueryParams) ([]models.Event, error) {
+	where := squirrel.And{
+		squirrel.Eq{networkIDCol: params.NetworkID},
+		squirrel.Eq{streamNameCol: params.StreamName},
+	}
+	if params.EventType != "" {
+		where = append(where, squirrel.Eq{eventTypeCol: params.EventType})
+	}
+	if params.HardwareID != "" {
+		where = append(where, squirrel.Eq{hardwareIDCol: params.HardwareID})
+	}
+	if params.Tag != "" {
+		where = append(where, squirrel.Eq{tagCol: params.Tag})
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		days, err := s.getPartitions(tx, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		reverse(days)
+		return s.selectEvents(tx, days, where, "DESC", 0, singleStreamQuerySize)
+	}
+	txRet, err := sqorc.ExecInTx(s.db, &sql.TxOptions{ReadOnly: true}, nil, txFn)
+	if err != nil {
+		return nil, err
+	}
+	return txRet.([]models.Event), nil
+}
+
+func (s *sqlEventStorage) GetMultiStreamEvents(ctx context.Context, params eventd_client.MultiStreamEventQueryParams) ([]models.Event, error) {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		days, err := s.getPartitions(tx, params.Start, params.End)
+		if err != nil {
+			return nil, err
+		}
+		return s.selectEvents(tx, days, getMultiStreamFilter(params), "ASC", params.From, params.Size)
+	}
+	txRet, err := sqorc.ExecInTx(s.db, &sql.TxOptions{ReadOnly: true}, nil, txFn)
+	if err != nil {
+		return nil, err
+	}
+	return txRet.([]models.Event), nil
+}
+
+func (s *sqlEventStorage) GetEventCount(ctx context.Context, params eventd_client.MultiStreamEventQueryParams) (int64, error) {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		days, err := s.getPartitions(tx, params.Start, params.End)
+		if err != nil {
+			return nil, err
+		}
+		where := getMultiStreamFilter(params)
+		count := int64(0)
+		for _, day := range days {
+			n, err := s.countEvents(tx, day, where)
+			if err != nil {
+				return nil, err
+			}
+			count += n
+		}
+		return count, nil
+	}
+	txRet, err := sqorc.ExecInTx(s.db, &sql.TxOptions{ReadOnly: true}, nil, txFn)
+	if err != nil {
+		return 0, err
+	}
+	return txRet.(int64), nil
+}
+
+func (s *sqlEventStorage) TailEvents(ctx context.Context, params eventd_client.MultiStreamEventQueryParams, position int64) ([]PositionedEvent, error) {
+	params.Start, params.End = nil, nil
+	limit := uint64(params.From + params.Size)
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		// Events numbered up to the current sequence were all committed,
+		// while later ones may be committed between the queries below
+		last, err := s.getSequence(tx)
+		if err != nil {
+			return nil, err
+		}
+		where := append(getMultiStreamFilter(params), squirrel.GtOrEq{seqCol: position}, squirrel.LtOrEq{seqCol: last})
+		days, err := s.getPartitionsAfterSeq(tx, position)
+		if err != nil {
+			return nil, err
+		}
+		// Events of a partition are in sequence order, but events stored
+		// late may be in any partition, so merge the first events of each
+		var events []PositionedEvent
+		for _, day := range days {
+			rows, err := s.builder.Select(streamNameCol, eventTypeCol, hardwareIDCol, tagCol, timestampCol, valueCol, seqCol).
+				From(getPartitionTableName(day)).
+				Where(where).
+				OrderBy(seqCol).
+				Limit(limit).
+				RunWith(tx).
+				Query()
+			if err != nil {
+				return nil, errors.Wrap(err, "select tailed events")
+			}
+			events, err = scanPositionedEvents(rows, events)
+			if err != nil {
+				return nil, err
+			}
+		}
+		sort.Slice(events, func(i, j int) bool { return events[i].Position < events[j].Position })
+		if len(events) <= params.From {
+			return []PositionedEvent{}, nil
+		}
+		events = events[params.From:]
+		if len(events) > params.Size {
+			events = events[:params.Size]
+		}
+		return events, nil
+	}
+	txRet, err := sqorc.ExecInTx(s.db, &sql.TxOptions{ReadOnly: true}, nil, txFn)
+	if err != nil {
+		return nil, err
+	}
+	return txRet.([]PositionedEvent), nil
+}
+
+// GetTailPosition returns the sequence number after that of the last stored
+// event.
+func (s *sqlEventStorage) GetTailPosition(ctx context.Context) (int64, error) {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		return s.getSequence(tx)
+	}
+	txRet, err := sqorc.ExecInTx(s.db, &sql.TxOptions{ReadOnly: true}, nil, txFn)
+	if err != nil {
+		return 0, err
+	}
+	return txRet.(int64) + 1, nil
+}
+
+func (s *sqlEventStorage) DeleteEventsBefore(t time.Time) (int64, error) {
+	cutoff := toMillis(t)
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		days, err := s.getPartitions(tx, nil, &t)
+		if err != nil {
+			return nil, err
+		}
+		deleted := int64(0)
+		for _, day := range days {
+			// Drop partitions which are entirely expired, and delete the
+			// expired events of the partition the cutoff falls in
+			if (day+1)*millisPerDay <= cutoff {
+				n, err := s.dropPartition(tx, day)
+				if err != nil {
+					return nil, err
+				}
+				deleted += n
+				continue
+			}
+			res, err := s.builder.Delete(getPartitionTableName(day)).
+				Where(squirrel.Lt{timestampCol: cutoff}).
+				RunWith(tx).
+				Exec()
+			if err != nil {
+				return nil, errors.Wrap(err, "delete expired events")
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return nil, errors.Wrap(err, "get number of deleted events")
+			}
+			deleted += n
+		}
+		return deleted, nil
+	}
+	txRet, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	if err != nil {
+		return 0, err
+	}
+	return txRet.(int64), nil
+}
+
+func (s *sqlEventStorage) createPartition(tx *sql.Tx, day int64) error {
+	tableName := getPartitionTableName(day)
+	_, err := s.builder.CreateTable(tableName).
+		IfNotExists().
+		Column(networkIDCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(streamNameCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(eventTypeCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(hardwareIDCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(tagCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(timestampCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+		Column(valueCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+		Column(seqCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+		Column(eventIDCol).Type(sqorc.ColumnTypeText).EndColumn().
+		Unique(networkIDCol, eventIDCol).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrapf(err, "create event partition table %s", tableName)
+	}
+
+	_, err = s.builder.CreateIndex(tableName+"_network_seq_idx").
+		IfNotExists().
+		On(tableName).
+		Columns(networkIDCol, seqCol).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrapf(err, "create event partition index %s", tableName)
+	}
+
+	_, err = s.builder.CreateIndex(tableName+"_network_timestamp_idx").
+		IfNotExists().
+		On(tableName).
+		Columns(networkIDCol, timestampCol).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrapf(err, "create event partition index %s", tableName)
+	}
+
+	_, err = s.builder.Insert(partitionsTableName).
+		Columns(dayCol).
+		Values(day).
+		OnConflict(nil, dayCol).
+		RunWith(tx).
+		Exec()
+	return errors.Wrapf(err, "insert event partition %s", tableName)
+}
+
+func (s *sqlEventStorage) dropPartition(tx *sql.Tx, day int64) (int64, error) {
+	n, err := s.countEvents(tx, day, squirrel.And{})
+	if err != nil {
+		return 0, err
+	}
+	tableName := getPartitionTableName(day)
+	_, err = tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", tableName))
+	if err != nil {
+		return 0, errors.Wrapf(err, "drop event partition table %s", tableName)
+	}
+	_, err = s.builder.Delete(partitionsTableName).
+		Where(squirrel.Eq{dayCol: day}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "delete event partition %s", tableName)
+	}
+	return n, nil
+}
+
+// claimSequence claims n sequence numbers, returning the first. The
+// sequence row stays locked until the transaction ends.
+func (s *sqlEventStorage) claimSequence(tx *sql.Tx, n int) (int64, error) {
+	_, err := s.builder.Update(sequenceTableName).
+		Set(seqCol, squirrel.Expr(seqCol+" + ?", n)).
+		Where(squirrel.Eq{idCol: 0}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return 0, errors.Wrap(err, "update event sequence")
+	}
+	last, err := s.getSequence(tx)
+	if err != nil {
+		return 0, err
+	}
+	return last - int64(n) + 1, nil
+}
+
+// getSequence returns the last claimed sequence number.
+func (s *sqlEventStorage) getSequence(tx *sql.Tx) (int64, error) {
+	var seq int64
+	err := s.builder.Select(seqCol).
+		From(sequenceTableName).
+		Where(squirrel.Eq{idCol: 0}).
+		RunWith(tx).
+		QueryRow().
+		Scan(&seq)
+	return seq, errors.Wrap(err, "select event sequence")
+}
+
+func (s *sqlEventStorage) setPartitionMaxSeq(tx *sql.Tx, day int64, seq int64) error {
+	_, err := s.builder.Update(partitionsTableName).
+		Set(maxSeqCol, seq).
+		Where(squirrel.Eq{dayCol: day}).
+		RunWith(tx).
+		Exec()
+	return errors.Wrap(err, "update event partition sequence")
+}
+
+// insertEvents inserts the records, numbered from seq. Records with the ID
+// of a stored event update it instead.
+func (s *sqlEventStorage) insertEvents(tx *sql.Tx, day int64, seq int64, records []*protos.EventRecord) error {
+	tableName := getPartitionTableName(day)
+	insert := s.builder.Insert(tableName).
+		Columns(networkIDCol, streamNameCol, eventTypeCol, hardwareIDCol, tagCol, timestampCol, valueCol, seqCol, eventIDCol)
+	for i, record := range records {
+		ts, _ := ptypes.Timestamp(record.Timestamp)
+		// Null IDs never conflict, so events without an ID are all stored
+		var eventID interface{}
+		if record.EventId != "" {
+			eventID = record.EventId
+		}
+		insert = insert.Values(
+			record.NetworkId,
+			record.Event.StreamName,
+			record.Event.EventType,
+			record.HardwareId,
+			record.Event.Tag,
+			toMillis(ts),
+			record.Event.Value,
+			seq+int64(i),
+			eventID,
+		)
+	}
+	var upsertValues []sqorc.UpsertValue
+	for _, col := range []string{streamNameCol, eventTypeCol, hardwareIDCol, tagCol, timestampCol, valueCol} {
+		upsertValues = append(upsertValues, sqorc.UpsertValue{Column: col, Value: squirrel.Expr(sqorc.FmtConflictUpdateTarget(tableName, col))})
+	}
+	_, err := insert.OnConflict(upsertValues, networkIDCol, eventIDCol).RunWith(tx).Exec()
+	return errors.Wrap(err, "insert events")
+}
+
+// dedupeRecords returns the records without those followed by a record of
+// the same network and event ID, since a statement can't upsert a row twice.
+func dedupeRecords(records []*protos.EventRecord) []*protos.EventRecord {
+	type eventKey struct{ networkID, eventID string }
+	last := map[eventKey]int{}
+	for i, record := range records {
+		if record.EventId != "" {
+			last[eventKey{record.NetworkId, record.EventId}] = i
+		}
+	}
+	if len(last) == 0 {
+		return records
+	}
+	ret := make([]*protos.EventRecord, 0, len(records))
+	for i, record := range records {
+		if record.EventId != "" && last[eventKey{record.NetworkId, record.EventId}] != i {
+			continue
+		}
+		ret = append(ret, record)
+	}
+	return ret
+}
+
+// getPartitions returns the days of the partitions holding events in the
+// inclusive time range, in ascending order. Nil bounds are unbounded.
+func (s *sqlEventStorage) getPartitions(tx *sql.Tx, start, end *time.Time) ([]int64, error) {
+	where := squirrel.And{}
+	if start != nil {
+		where = append(where, squirrel.GtOrEq{dayCol: toMillis(*start) / millisPerDay})
+	}
+	if end != nil {
+		where = append(where, squirrel.LtOrEq{dayCol: toMillis(*end) / millisPerDay})
+	}
+	rows, err := s.builder.Select(dayCol).
+		From(partitionsTableName).
+		Where(where).
+		OrderBy(dayCol).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, errors.Wrap(err, "select event partitions")
+	}
+	defer sqorc.CloseRowsLogOnError(rows, "getPartitions")
+
+	var days []int64
+	for rows.Next() {
+		var day int64
+		err = rows.Scan(&day)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan event partition row")
+		}
+		days = append(days, day)
+	}
+	return days, errors.Wrap(rows.Err(), "sql rows err")
+}
+
+// getPartitionsAfterSeq returns the days of the partitions holding events
+// numbered at or after seq.
+func (s *sqlEventStorage) getPartitionsAfterSeq(tx *sql.Tx, seq int64) ([]int64, error) {
+	rows, err := s.builder.Select(dayCol).
+		From(partitionsTableName).
+		Where(squirrel.GtOrEq{maxSeqCol: seq}).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, errors.Wrap(err, "select event partitions")
+	}
+	defer sqorc.CloseRowsLogOnError(rows, "getPartitionsAfterSeq")
+
+	var days []int64
+	for rows.Next() {
+		var day int64
+		err = rows.Scan(&day)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan event partition row")
+		}
+		days = append(days, day)
+	}
+	return days, errors.Wrap(rows.Err(), "sql rows err")
+}
+
+// selectEvents returns up to size events matching the filter, skipping the
+// first from events, across the partitions in the passed order.
+func (s *sqlEventStorage) selectEvents(tx *sql.Tx, days []int64, where squirrel.Sqlizer, order string, from int, size int) ([]models.Event, error) {
+	events := []models.Event{}
+	for _, day := range days {
+		if len(events) >= size {
+			break
+		}
+		// Skip partitions whose matching events all precede the page
+		if from > 0 {
+			n, err := s.countEvents(tx, day, where)
+			if err != nil {
+				return nil, err
+			}
+			if n <= int64(from) {
+				from -= int(n)
+				continue
+			}
+		}
+
+		rows, err := s.builder.Select(streamNameCol, eventTypeCol, hardwareIDCol, tagCol, timestampCol, valueCol).
+			From(getPartitionTableName(day)).
+			Where(where).
+			OrderBy(timestampCol + " " + order).
+			Limit(uint64(size - len(events))).
+			Offset(uint64(from)).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "select events")
+		}
+		from = 0
+		events, err = scanEvents(rows, events)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+func (s *sqlEventStorage) countEvents(tx *sql.Tx, day int64, where squirrel.Sqlizer) (int64, error) {
+	var n int64
+	err := s.builder.Select("COUNT(*)").
+		From(getPartitionTableName(day)).
+		Where(where).
+		RunWith(tx).
+		QueryRow().
+		Scan(&n)
+	return n, errors.Wrap(err, "count events")
+}
+
+func scanEvents(rows *sql.Rows, events []models.Event) ([]models.Event, error) {
+	defer sqorc.CloseRowsLogOnError(rows, "scanEvents")
+	for rows.Next() {
+		var event models.Event
+		var timestamp int64
+		var value string
+		err := rows.Scan(&event.StreamName, &event.EventType, &event.HardwareID, &event.Tag, &timestamp, &value)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan event row")
+		}
+		event, err = toEvent(event, timestamp, value)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, errors.Wrap(rows.Err(), "sql rows err")
+}
+
+func scanPositionedEvents(rows *sql.Rows, events []PositionedEvent) ([]PositionedEvent, error) {
+	defer sqorc.CloseRowsLogOnError(rows, "scanPositionedEvents")
+	for rows.Next() {
+		var event models.Event
+		var timestamp, seq int64
+		var value string
+		err := rows.Scan(&event.StreamName, &event.EventType, &event.HardwareID, &event.Tag, &timestamp, &value, &seq)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan event row")
+		}
+		event, err = toEvent(event, timestamp, value)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, PositionedEvent{Event: event, Position: seq})
+	}
+	return events, errors.Wrap(rows.Err(), "sql rows err")
+}
+
+// toEvent sets the event's timestamp and value from their column values.
+func toEvent(event models.Event, timestamp int64, value string) (models.Event, error) {
+	var eventValue map[string]interface{}
+	err := json.Unmarshal([]byte(value), &eventValue)
+	if err != nil {
+		return models.Event{}, errors.Wrap(err, "unmarshal event value")
+	}
+	event.Timestamp = formatTimestamp(fromMillis(timestamp))
+	event.Value = eventValue
+	return event, nil
+}
+
+func getMultiStreamFilter(params eventd_client.MultiStreamEventQueryParams) squirrel.And {
+	where := squirrel.And{squirrel.Eq{networkIDCol: params.NetworkID}}
+	if len(params.Streams) > 0 {
+		where = append(where, squirrel.Eq{streamNameCol: params.Streams})
+	}
+	if len(params.Events) > 0 {
+		where = append(where, squirrel.Eq{eventTypeCol: params.Events})
+	}
+	if len(params.Tags) > 0 {
+		where = append(where, squirrel.Eq{tagCol: params.Tags})
+	}
+	if len(params.HardwareIDs) > 0 {
+		where = append(where, squirrel.Eq{hardwareIDCol: params.HardwareIDs})
+	}
+	if params.Start != nil {
+		where = append(where, squirrel.GtOrEq{timestampCol: toMillis(*params.Start)})
+	}
+	if params.End != nil {
+		where = append(where, squirrel.LtOrEq{timestampCol: toMillis(*params.End)})
+	}
+	return where
+}
+
+func getPartitionTableName(day int64) string {
+	return eventsTablePrefix + fromMillis(day*millisPerDay).Format(partitionTableDay)
+}
+
+func reverse(days []int64) {
+	for i, j := 0, len(days)-1; i < j; i, j = i+1, j-1 {
+		days[i], days[j] = days[j], days[i]
+	}
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func fromMillis(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond)).UTC()
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/services/eventd/eventd_client"
+	"magma/orc8r/cloud/go/services/eventd/obsidian/models"
+	"magma/orc8r/cloud/go/services/eventd/protos"
+	"magma/orc8r/cloud/go/services/eventd/storage"
+	"magma/orc8r/cloud/go/services/eventd/test_utils"
+	"magma/orc8r/cloud/go/sqorc"
+
+	"github.com/golang/protobuf/ptypes"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const day = 24 * time.Hour
+
+func TestSQLEventStorage(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	store := storage.NewSQLEventStorage(db, sqorc.GetSqlBuilder())
+	require.NoError(t, store.Initialize())
+	ctx := context.Background()
+
+	// Empty
+	events, err := store.GetEvents(ctx, eventd_client.EventQueryParams{NetworkID: "n1", StreamName: "s1"})
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+	count, err := store.GetEventCount(ctx, eventd_client.MultiStreamEventQueryParams{NetworkID: "n1"})
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+
+	// Events spread over three days
+	t0 := time.Date(2020, 10, 1, 23, 0, 0, 0, time.UTC)
+	e0 := test_utils.NewEventRecord(t, "n1", "s1", "created", "hw1", "tag1", t0)
+	e1 := test_utils.NewEventRecord(t, "n1", "s2", "created", "hw2", "tag1", t0.Add(time.Hour))
+	e2 := test_utils.NewEventRecord(t, "n1", "s1", "deleted", "hw1", "tag2", t0.Add(2*time.Hour))
+	e3 := test_utils.NewEventRecord(t, "n2", "s1", "created", "hw3", "tag1", t0.Add(3*time.Hour))
+	e4 := test_utils.NewEventRecord(t, "n1", "s1", "created", "", "tag1", t0.Add(day+time.Hour))
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{e4, e0, e1}))
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{e2, e3}))
+
+	// Invalid records
+	assert.Error(t, store.PutEvents(ctx, []*protos.EventRecord{{NetworkId: "n1"}}))
+	invalid := test_utils.NewEventRecord(t, "n1", "s1", "created", "", "", t0)
+	invalid.Event.Value = "not json"
+	assert.Error(t, store.PutEvents(ctx, []*protos.EventRecord{invalid}))
+
+	// Single stream, newest first
+	events, err = store.GetEvents(ctx, eventd_client.EventQueryParams{NetworkID: "n1", StreamName: "s1"})
+	assert.NoError(t, err)
+	assert.Equal(t, toModels(e4, e2, e0), events)
+	events, err = store.GetEvents(ctx, eventd_client.EventQueryParams{NetworkID: "n1", StreamName: "s1", EventType: "created", HardwareID: "hw1", Tag: "tag1"})
+	assert.NoError(t, err)
+	assert.Equal(t, toModels(e0), events)
+
+	// Multi-stream, oldest first
+	events, err = store.GetMultiStreamEvents(ctx, eventd_client.MultiStreamEventQueryParams{NetworkID: "n1", Size: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, toModels(e0, e1, e2, e4), events)
+	events, err = store.GetMultiStreamEvents(ctx, eventd_client.MultiStreamEventQueryParams{
+		NetworkID:   "n1",
+		Streams:     []string{"s1", "s2"},
+		Events:      []string{"created"},
+		Tags:        []string{"tag1"},
+		HardwareIDs: []string{"hw1", "hw2"},
+		Size:        10,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, toModels(e0, e1), events)
+
+	// Paginated across partitions
+	params := eventd_client.MultiStreamEventQueryParams{NetworkID: "n1", From: 1, Size: 2}
+	events, err = store.GetMultiStreamEvents(ctx, params)
+	assert.NoError(t, err)
+	assert.Equal(t, toModels(e1, e2), events)
+	params.From = 3
+	events, err = store.GetMultiStreamEvents(ctx, params)
+	assert.NoError(t, err)
+	assert.Equal(t, toModels(e4), events)
+
+	// Time range, inclusive
+	start, end := t0.Add(time.Hour), t0.Add(day+time.Hour)
+	params = eventd_client.MultiStreamEventQueryParams{NetworkID: "n1", Size: 10, Start: &start, End: &end}
+	events, err = store.GetMultiStreamEvents(ctx, params)
+	assert.NoError(t, err)
+	assert.Equal(t, toModels(e1, e2, e4), events)
+	count, err = store.GetEventCount(ctx, params)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// Retention drops expired partitions, and expired events of the
+	// partition the cutoff falls in
+	n, err := store.DeleteEventsBefore(t0.Add(90 * time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	events, err = store.GetMultiStreamEvents(ctx, eventd_client.MultiStreamEventQueryParams{NetworkID: "n1", Size: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, toModels(e2, e4), events)
+
+	n, err = store.DeleteEventsBefore(t0.Add(2 * day))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	for _, networkID := range []string{"n1", "n2"} {
+		count, err = store.GetEventCount(ctx, eventd_client.MultiStreamEventQueryParams{NetworkID: networkID})
+		assert.NoError(t, err)
+		assert.Zero(t, count)
+	}
+
+	// Dropped partitions are recreated on write
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{e0}))
+	events, err = store.GetEvents(ctx, eventd_client.EventQueryParams{NetworkID: "n1", StreamName: "s1"})
+	assert.NoError(t, err)
+	assert.Equal(t, toModels(e0), events)
+}
+
+func TestSQLEventStorage_EventIDs(t *testing.T) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	store := storage.NewSQLEventStorage(db, sqorc.GetSqlBuilder())
+	require.NoError(t, store.Initialize())
+	ctx := context.Background()
+
+	t0 := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	e0 := test_utils.NewEventRecord(t, "n1", "audit", "rest_post", "", "admin", t0)
+	e0.EventId = "entry1"
+	e1 := test_utils.NewEventRecord(t, "n1", "audit", "rest_put", "", "admin", t0.Add(time.Minute))
+	e1.EventId = "entry2"
+	// Events without an ID aren't deduplicated
+	e2 := test_utils.NewEventRecord(t, "n1", "s1", "created", "hw1", "tag1", t0)
+	// IDs are scoped to their network
+	e3 := test_utils.NewEventRecord(t, "n2", "audit", "rest_post", "", "admin", t0)
+	e3.EventId = "entry1"
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{e0, e2, e3}))
+	position, err := store.GetTailPosition(ctx)
+	require.NoError(t, err)
+
+	// Retried puts replace the stored event, also within a batch
+	retried := test_utils.NewEventRecord(t, "n1", "audit", "rest_post", "", "operator", t0)
+	retried.EventId = "entry1"
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{e0, e1, e2, retried}))
+	events, err := store.GetMultiStreamEvents(ctx, eventd_client.MultiStreamEventQueryParams{NetworkID: "n1", Size: 10})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, toModels(retried, e2, e2, e1), events)
+	count, err := store.GetEventCount(ctx, eventd_client.MultiStreamEventQueryParams{NetworkID: "n2"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// Replaced events keep their position, so aren't tailed again
+	tailed, err := store.TailEvents(ctx, eventd_client.MultiStreamEventQueryParams{NetworkID: "n1", Streams: []string{"audit"}, Size: 10}, position)
+	assert.NoError(t, err)
+	require.Len(t, tailed, 1)
+	assert.Equal(t, toModels(e1)[0], tailed[0].Event)
+}
+
+func toModels(records ...*protos.EventRecord) []models.Event {
+	ret := []models.Event{}
+	for _, record := range records {
+		ts, _ := ptypes.Timestamp(record.Timestamp)
+		var value map[string]interface{}
+		_ = json.Unmarshal([]byte(record.Event.Value), &value)
+		ret = append(ret, models.Event{
+			StreamName: record.Event.StreamName,
+			EventType:  record.Event.EventType,
+			HardwareID: record.HardwareId,
+			Tag:        record.Event.Tag,
+			Timestamp:  ts.Format(time.RFC3339Nano),
+			Value:      value,
+		})
+	}
+	return ret
+}
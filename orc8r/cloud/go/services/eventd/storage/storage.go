@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"magma/orc8r/cloud/go/services/eventd/eventd_client"
+	"magma/orc8r/cloud/go/services/eventd/obsidian/models"
+	"magma/orc8r/cloud/go/services/eventd/protos"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ElasticBackend stores events in Elasticsearch
+	ElasticBackend = "elasticsearch"
+	// SQLBackend stores events in orc8r's SQL database
+	SQLBackend = "sql"
+)
+
+// EventStorage stores events and serves the queries of the eventd REST API.
+type EventStorage interface {
+	// PutEvents stores a batch of events. Each record must have a timestamp
+	// and an event whose value is a JSON object. Records with the network
+	// and event ID of a stored event of the same day replace it.
+	PutEvents(ctx context.Context, records []*protos.EventRecord) error
+
+	// GetEvents returns the most recent events of a single stream, newest
+	// first.
+	GetEvents(ctx context.Context, params eventd_client.EventQueryParams) ([]models.Event, error)
+
+	// GetMultiStreamEvents returns a page of events matching the params,
+	// oldest first.
+	GetMultiStreamEvents(ctx context.Context, params eventd_client.MultiStreamEventQueryParams) ([]models.Event, error)
+
+	// GetEventCount returns the number of events matching the params.
+	GetEventCount(ctx context.Context, params eventd_client.MultiStreamEventQueryParams) (int64, error)
+
+	// TailEvents returns up to params.Size events matching the params'
+	// filters, stored at or after the position, in the order they were
+	// stored. The first params.From of these are skipped, and the params'
+	// time range is ignored.
+	TailEvents(ctx context.Context, params eventd_client.MultiStreamEventQueryParams, position int64) ([]PositionedEvent, error)
+
+	// GetTailPosition returns a position at or before that of all events
+	// stored from now on.
+	GetTailPosition(ctx context.Context) (int64, error)
+}
+
+// PositionedEvent is an event along with its position in the order events
+// were stored. Positions are ordered, but multiple events may share one.
+type PositionedEvent struct {
+	Event    models.Event
+	Position int64
+}
+
+// ValidateRecord verifies an event record can be stored.
+func ValidateRecord(record *protos.EventRecord) error {
+	if record == nil || record.Event == nil {
+		return errors.New("event record must have an event")
+	}
+	if record.NetworkId == "" {
+		return errors.New("event record must have a network ID")
+	}
+	if record.Event.StreamName == "" {
+		return errors.New("event must have a stream name")
+	}
+	if _, err := ptypes.Timestamp(record.Timestamp); err != nil {
+		return errors.Wrap(err, "invalid event timestamp")
+	}
+	var value map[string]interface{}
+	if err := json.Unmarshal([]byte(record.Event.Value), &value); err != nil {
+		return errors.Wrap(err, "event value must be a JSON object")
+	}
+	return nil
+}
+
+// formatTimestamp formats event timestamps the same way for all backends.
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
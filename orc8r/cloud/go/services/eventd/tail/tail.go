@@ -0,0 +1,236 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tail follows the events stored in an eventd storage backend as
+// they're written, for streaming to operators.
+//
+// Tailing polls the storage for events after an offset, in the order they
+// were stored, and only fetches the next batch once the caller asks for it.
+// Slow consumers therefore slow down polling rather than buffering events in
+// eventd. Offsets are positions in the storage order rather than event
+// timestamps, so events emitted earlier but stored late, e.g. after being
+// buffered by fluentd, are still tailed.
+package tail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"magma/orc8r/cloud/go/services/eventd/eventd_client"
+	"magma/orc8r/cloud/go/services/eventd/obsidian/models"
+	"magma/orc8r/cloud/go/services/eventd/protos"
+	"magma/orc8r/cloud/go/services/eventd/storage"
+	lib_protos "magma/orc8r/lib/go/protos"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultPollInterval is the interval at which the storage is polled
+	// while no new events are available.
+	DefaultPollInterval = time.Second
+	// DefaultBatchSize is the max number of events returned by each poll.
+	DefaultBatchSize = 100
+
+	offsetSep = "-"
+
+	// maxConcurrentTails bounds the tails served by each eventd replica,
+	// since each tail polls the storage
+	maxConcurrentTails = 64
+)
+
+var tailSlots = make(chan struct{}, maxConcurrentTails)
+
+// Acquire reserves a tail slot, returning false if all slots are in use.
+// Release must be called once a tail with a reserved slot ends.
+func Acquire() bool {
+	select {
+	case tailSlots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release frees a tail slot reserved by Acquire.
+func Release() {
+	<-tailSlots
+}
+
+// Filter selects the tailed events of a network. Empty lists match all
+// events.
+type Filter struct {
+	NetworkID   string
+	Streams     []string
+	Events      []string
+	HardwareIDs []string
+	Tags        []string
+}
+
+// Offset is the position of a tail, after which tailing resumes.
+type Offset struct {
+	// Position of the last tailed event in the storage order
+	Position int64
+	// Skip is the number of tailed events at the position
+	Skip int
+}
+
+// NewestOffset returns the offset from which only events stored from now on
+// are tailed.
+func NewestOffset(ctx context.Context, store storage.EventStorage) (Offset, error) {
+	position, err := store.GetTailPosition(ctx)
+	if err != nil {
+		return Offset{}, errors.Wrap(err, "get newest tail offset")
+	}
+	return Offset{Position: position}, nil
+}
+
+// ParseOffset parses an offset from its string representation.
+func ParseOffset(s string) (Offset, error) {
+	parts := strings.Split(s, offsetSep)
+	if len(parts) != 2 {
+		return Offset{}, errors.Errorf("invalid tail offset %q", s)
+	}
+	position, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || position < 0 {
+		return Offset{}, errors.Errorf("invalid tail offset position %q", parts[0])
+	}
+	skip, err := strconv.Atoi(parts[1])
+	if err != nil || skip < 0 {
+		return Offset{}, errors.Errorf("invalid tail offset skip %q", parts[1])
+	}
+	return Offset{Position: position, Skip: skip}, nil
+}
+
+func (o Offset) String() string {
+	return fmt.Sprintf("%d%s%d", o.Position, offsetSep, o.Skip)
+}
+
+// TailedEvent is a tailed event, along with the offset to resume tailing
+// after it.
+type TailedEvent struct {
+	Event  models.Event
+	Offset Offset
+}
+
+// Tailer follows the events matching a filter, from an offset.
+type Tailer struct {
+	store  storage.EventStorage
+	filter Filter
+	offset Offset
+
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+// NewTailer returns a tailer of the events stored after the offset.
+func NewTailer(store storage.EventStorage, filter Filter, offset Offset) *Tailer {
+	return &Tailer{
+		store:        store,
+		filter:       filter,
+		offset:       offset,
+		PollInterval: DefaultPollInterval,
+		BatchSize:    DefaultBatchSize,
+	}
+}
+
+// Offset returns the offset after the last tailed event.
+func (t *Tailer) Offset() Offset {
+	return t.offset
+}
+
+// Next returns the next batch of events after the tailer's offset, waiting
+// up to maxWait for one to be stored. The batch is empty if none was.
+func (t *Tailer) Next(ctx context.Context, maxWait time.Duration) ([]TailedEvent, error) {
+	deadline := time.Now().Add(maxWait)
+	for {
+		events, err := t.poll(ctx)
+		if err != nil || len(events) > 0 {
+			return events, err
+		}
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return events, nil
+		}
+		if wait > t.PollInterval {
+			wait = t.PollInterval
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *Tailer) poll(ctx context.Context) ([]TailedEvent, error) {
+	events, err := t.store.TailEvents(ctx, eventd_client.MultiStreamEventQueryParams{
+		NetworkID:   t.filter.NetworkID,
+		Streams:     t.filter.Streams,
+		Events:      t.filter.Events,
+		Tags:        t.filter.Tags,
+		HardwareIDs: t.filter.HardwareIDs,
+		From:        t.offset.Skip,
+		Size:        t.BatchSize,
+	}, t.offset.Position)
+	if err != nil {
+		return nil, errors.Wrap(err, "poll events")
+	}
+
+	offset := t.offset
+	ret := make([]TailedEvent, 0, len(events))
+	for _, event := range events {
+		if event.Position == offset.Position {
+			offset.Skip++
+		} else {
+			offset = Offset{Position: event.Position, Skip: 1}
+		}
+		ret = append(ret, TailedEvent{Event: event.Event, Offset: offset})
+	}
+	t.offset = offset
+	return ret, nil
+}
+
+// ToRecord converts a tailed event of the network to its record.
+func (e TailedEvent) ToRecord(networkID string) (*protos.EventRecord, error) {
+	ts, err := time.Parse(time.RFC3339Nano, e.Event.Timestamp)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse timestamp of tailed event")
+	}
+	tsProto, err := ptypes.TimestampProto(ts)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert timestamp of tailed event")
+	}
+	value, err := json.Marshal(e.Event.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal value of tailed event")
+	}
+	return &protos.EventRecord{
+		NetworkId:  networkID,
+		HardwareId: e.Event.HardwareID,
+		Timestamp:  tsProto,
+		Event: &lib_protos.Event{
+			StreamName: e.Event.StreamName,
+			EventType:  e.Event.EventType,
+			Tag:        e.Event.Tag,
+			Value:      string(value),
+		},
+	}, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tail_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/eventd/protos"
+	"magma/orc8r/cloud/go/services/eventd/storage"
+	"magma/orc8r/cloud/go/services/eventd/tail"
+	"magma/orc8r/cloud/go/services/eventd/test_utils"
+	"magma/orc8r/cloud/go/sqorc"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOffset(t *testing.T) {
+	offset, err := tail.ParseOffset("1601593200000-2")
+	assert.NoError(t, err)
+	assert.Equal(t, tail.Offset{Position: 1601593200000, Skip: 2}, offset)
+	assert.Equal(t, "1601593200000-2", offset.String())
+
+	for _, invalid := range []string{"", "1601593200000", "a-1", "1-b", "-1-1", "1--1", "1-2-3"} {
+		_, err = tail.ParseOffset(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestTailer(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	store := storage.NewSQLEventStorage(db, sqorc.GetSqlBuilder())
+	require.NoError(t, store.Initialize())
+	ctx := context.Background()
+
+	// Events stored before the newest offset aren't tailed
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{test_utils.NewEventRecord(t, "n1", "mme", "e0", "hw1", "IMSI1", time.Unix(999, 0))}))
+	newest, err := tail.NewestOffset(ctx, store)
+	require.NoError(t, err)
+	assert.Equal(t, tail.Offset{Position: 2}, newest)
+	tailer := tail.NewTailer(store, tail.Filter{NetworkID: "n1", Streams: []string{"mme"}}, newest)
+	tailer.PollInterval = 10 * time.Millisecond
+	tailer.BatchSize = 2
+	events, err := tailer.Next(ctx, 50*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	// Batches are bounded, and follow the order events were stored in
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{
+		test_utils.NewEventRecord(t, "n1", "mme", "e1", "hw1", "IMSI1", time.Unix(1000, 0)),
+		test_utils.NewEventRecord(t, "n1", "mme", "e2", "hw1", "IMSI1", time.Unix(1000, 0)),
+		test_utils.NewEventRecord(t, "n1", "mme", "e3", "hw1", "IMSI1", time.Unix(1000, 0)),
+		test_utils.NewEventRecord(t, "n1", "sessiond", "e4", "hw1", "IMSI1", time.Unix(1001, 0)),
+		test_utils.NewEventRecord(t, "n2", "mme", "e5", "hw1", "IMSI1", time.Unix(1001, 0)),
+		test_utils.NewEventRecord(t, "n1", "mme", "e6", "hw1", "IMSI1", time.Unix(1002, 0)),
+	}))
+	events, err = tailer.Next(ctx, 0)
+	assert.NoError(t, err)
+	assertTailed(t, []string{"e1", "e2"}, events)
+	assert.Equal(t, tail.Offset{Position: 3, Skip: 1}, tailer.Offset())
+	assert.Equal(t, tailer.Offset(), events[1].Offset)
+	events, err = tailer.Next(ctx, 0)
+	assert.NoError(t, err)
+	assertTailed(t, []string{"e3", "e6"}, events)
+	assert.Equal(t, tail.Offset{Position: 4, Skip: 1}, events[0].Offset)
+	assert.Equal(t, tail.Offset{Position: 7, Skip: 1}, tailer.Offset())
+
+	// Events stored late, with timestamps before the offset's events and
+	// in an earlier partition, are still tailed
+	require.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{
+		test_utils.NewEventRecord(t, "n1", "mme", "late1", "hw1", "IMSI1", time.Unix(900, 0)),
+		test_utils.NewEventRecord(t, "n1", "mme", "late2", "hw1", "IMSI1", time.Unix(-100000, 0)),
+	}))
+	events, err = tailer.Next(ctx, 0)
+	assert.NoError(t, err)
+	assertTailed(t, []string{"late1", "late2"}, events)
+
+	// Resume from an event's offset
+	resumed := tail.NewTailer(store, tail.Filter{NetworkID: "n1", Streams: []string{"mme"}}, tail.Offset{Position: 2, Skip: 1})
+	events, err = resumed.Next(ctx, 0)
+	assert.NoError(t, err)
+	assertTailed(t, []string{"e2", "e3", "e6", "late1", "late2"}, events)
+
+	// Waits for new events
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		assert.NoError(t, store.PutEvents(ctx, []*protos.EventRecord{test_utils.NewEventRecord(t, "n1", "mme", "e7", "hw1", "IMSI1", time.Unix(1003, 0))}))
+	}()
+	events, err = tailer.Next(ctx, time.Second)
+	assert.NoError(t, err)
+	assertTailed(t, []string{"e7"}, events)
+
+	record, err := events[0].ToRecord("n1")
+	assert.NoError(t, err)
+	assert.Equal(t, test_utils.NewEventRecord(t, "n1", "mme", "e7", "hw1", "IMSI1", time.Unix(1003, 0)).String(), record.String())
+
+	// Canceled
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = tailer.Next(canceled, time.Second)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func assertTailed(t *testing.T, expectedTypes []string, actual []tail.TailedEvent) {
+	var actualTypes []string
+	for _, event := range actual {
+		actualTypes = append(actualTypes, event.Event.EventType)
+	}
+	assert.Equal(t, expectedTypes, actualTypes)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test_utils
+
+import (
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/services/eventd/protos"
+	lib_protos "magma/orc8r/lib/go/protos"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+)
+
+// NewEventRecord returns an event record whose value is a JSON object. A zero
+// timestamp leaves the record's timestamp unset.
+func NewEventRecord(t *testing.T, networkID, stream, eventType, hardwareID, tag string, timestamp time.Time) *protos.EventRecord {
+	record := &protos.EventRecord{
+		NetworkId:  networkID,
+		HardwareId: hardwareID,
+		Event: &lib_protos.Event{
+			StreamName: stream,
+			EventType:  eventType,
+			Tag:        tag,
+			Value:      `{"imsi":"IMSI1"}`,
+		},
+	}
+	if !timestamp.IsZero() {
+		ts, err := ptypes.TimestampProto(timestamp)
+		require.NoError(t, err)
+		record.Timestamp = ts
+	}
+	return record
+}
@@ -54,6 +54,18 @@ func GatewayReboot(networkId string, gatewayId string) error {
 	return err
 }
 
+// GatewayRebootstrap instructs a gateway to rebootstrap, obtaining a new
+// certificate.
+// If gateway not registered, returns ErrNotFound from magma/orc8r/lib/go/errors.
+func GatewayRebootstrap(networkId string, gatewayId string) error {
+	client, ctx, err := getGWMagmadClient(networkId, gatewayId)
+	if err != nil {
+		return err
+	}
+	_, err = client.Rebootstrap(ctx, new(protos.Void))
+	return err
+}
+
 // GatewayRestartServices restarts services at a gateway.
 // If gateway not registered, returns ErrNotFound from magma/orc8r/lib/go/errors.
 func GatewayRestartServices(networkId string, gatewayId string, services []string) error {
@@ -0,0 +1,507 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cardinality guards Prometheus against series explosions caused by
+// high-cardinality labels, e.g. IMSIs or session IDs, on gateway metrics.
+//
+// The guard strips disallowed labels from each metric, aggregating series
+// which collide once stripped, then enforces series budgets per network and
+// per metric within a network. Series count against budgets until they
+// haven't been seen for the series TTL. Series are shared across metricsd
+// replicas via a Store, so budgets hold across replicas.
+package cardinality
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/metricsd/exporters"
+	"magma/orc8r/lib/go/metrics"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	prometheus_models "github.com/prometheus/client_model/go"
+)
+
+const (
+	defaultSeriesTTL = time.Hour
+
+	// syncFraction is the fraction of the series TTL after which a series
+	// seen by this replica is recorded as seen in the store again. A
+	// dropped series is likewise only retried in the store after this
+	// interval.
+	syncFraction = 4
+)
+
+// Config configures the cardinality guard. Zero budgets are unlimited.
+type Config struct {
+	// MaxSeriesPerNetwork bounds the number of series of each network.
+	MaxSeriesPerNetwork int `yaml:"maxSeriesPerNetwork"`
+	// MaxSeriesPerMetric bounds the number of series of each metric, within
+	// each network.
+	MaxSeriesPerMetric int `yaml:"maxSeriesPerMetric"`
+	// DeniedLabels are stripped from all metrics.
+	DeniedLabels []string `yaml:"deniedLabels"`
+	// AllowedLabels maps metric names to the only labels kept on those
+	// metrics. Metrics not listed keep all labels not denied.
+	AllowedLabels map[string][]string `yaml:"allowedLabels"`
+	// SeriesTTL is how long a series counts against its budgets after it was
+	// last seen. Defaults to 1h.
+	SeriesTTL time.Duration `yaml:"seriesTTL"`
+}
+
+// MetricReport is the cardinality of a metric within a network.
+type MetricReport struct {
+	MetricName    string
+	ActiveSeries  int
+	DroppedSeries int64
+}
+
+// NetworkReport is the cardinality of a network's metrics.
+type NetworkReport struct {
+	NetworkID    string
+	ActiveSeries int
+	Metrics      []MetricReport
+}
+
+// Guard enforces label allow/deny lists and series budgets on gateway and
+// pushed metrics. Cloud metrics pass through unchanged.
+type Guard struct {
+	cfg           Config
+	deniedLabels  map[string]bool
+	allowedLabels map[string]map[string]bool
+	store         Store
+
+	mu sync.Mutex
+	// networks caches the series this replica recently synced with the
+	// store, by network ID
+	networks map[string]*networkSeries
+}
+
+// networkSeries is a network's cached series. Its lock isn't held while
+// syncing with the store, so networks are synced concurrently.
+type networkSeries struct {
+	mu sync.Mutex
+	// admitted maps the keys of admitted series to when they were last seen
+	// and last synced
+	admitted map[string]*seenSeries
+	// dropped maps the keys of dropped series to when they were dropped
+	dropped   map[string]time.Time
+	lastSweep time.Time
+}
+
+type seenSeries struct {
+	lastSeen   time.Time
+	lastSynced time.Time
+}
+
+// NewGuard returns a guard enforcing the config, sharing series via the
+// store.
+func NewGuard(cfg Config, store Store) *Guard {
+	if cfg.SeriesTTL <= 0 {
+		cfg.SeriesTTL = defaultSeriesTTL
+	}
+	g := &Guard{
+		cfg:           cfg,
+		deniedLabels:  map[string]bool{},
+		allowedLabels: map[string]map[string]bool{},
+		store:         store,
+		networks:      map[string]*networkSeries{},
+	}
+	for _, l := range cfg.DeniedLabels {
+		g.deniedLabels[l] = true
+	}
+	for metricName, lbls := range cfg.AllowedLabels {
+		g.allowedLabels[metricName] = map[string]bool{}
+		for _, l := range lbls {
+			g.allowedLabels[metricName][l] = true
+		}
+	}
+	return g
+}
+
+// seriesGroup is the metrics of a batch which share a series, once their
+// disallowed labels are stripped.
+type seriesGroup struct {
+	networkID  string
+	id         SeriesID
+	metricType prometheus_models.MetricType
+	// mixedTypes is true if the group's metrics are of different types
+	mixedTypes bool
+	metrics    []*prometheus_models.Metric
+	// aggregate is the group's metrics combined into one, or nil if they
+	// can't be combined
+	aggregate *prometheus_models.Metric
+	admitted  bool
+}
+
+// Filter strips disallowed labels from the metrics, then drops the metrics
+// of new series which would exceed a budget. Families left without metrics
+// are dropped.
+// Metrics which collide once stripped are aggregated: counters are summed,
+// and histograms with the same buckets merged. Other colliding metrics
+// can't be aggregated, so are dropped and counted as dropped series.
+// The metrics are modified in place.
+func (g *Guard) Filter(metricsAndContexts []exporters.MetricAndContext) []exporters.MetricAndContext {
+	groups := map[string]*seriesGroup{}
+	var ordered []*seriesGroup
+	groupOf := map[*prometheus_models.Metric]*seriesGroup{}
+	for _, m := range metricsAndContexts {
+		networkID, ok := getNetworkID(m.Context)
+		if !ok || m.Family == nil {
+			continue
+		}
+		name := m.Context.MetricName
+		for _, metric := range m.Family.Metric {
+			metric.Label = g.filterLabels(name, metric.Label)
+			key := getSeriesKey(name, metric.Label)
+			groupKey := networkID + "/" + key
+			group, ok := groups[groupKey]
+			if !ok {
+				group = &seriesGroup{
+					networkID:  networkID,
+					id:         SeriesID{MetricName: name, Key: key},
+					metricType: m.Family.GetType(),
+				}
+				groups[groupKey] = group
+				ordered = append(ordered, group)
+			}
+			if group.metricType != m.Family.GetType() {
+				group.mixedTypes = true
+			}
+			group.metrics = append(group.metrics, metric)
+			groupOf[metric] = group
+		}
+	}
+
+	collisions := map[string]map[string]int64{}
+	var admissible []*seriesGroup
+	for _, group := range ordered {
+		if !group.mixedTypes {
+			group.aggregate = aggregate(group.metricType, group.metrics)
+		}
+		if group.aggregate == nil {
+			if collisions[group.networkID] == nil {
+				collisions[group.networkID] = map[string]int64{}
+			}
+			collisions[group.networkID][group.id.MetricName]++
+			droppedSeries.WithLabelValues(group.networkID, group.id.MetricName, labelCollisionReason).Inc()
+			continue
+		}
+		admissible = append(admissible, group)
+	}
+	g.admit(admissible, collisions)
+
+	ret := make([]exporters.MetricAndContext, 0, len(metricsAndContexts))
+	for _, m := range metricsAndContexts {
+		if _, ok := getNetworkID(m.Context); !ok || m.Family == nil {
+			ret = append(ret, m)
+			continue
+		}
+		var kept []*prometheus_models.Metric
+		for _, metric := range m.Family.Metric {
+			group := groupOf[metric]
+			// Each series is emitted once, in place of its first metric
+			if !group.admitted || group.metrics[0] != metric {
+				continue
+			}
+			kept = append(kept, group.aggregate)
+		}
+		if len(kept) == 0 {
+			continue
+		}
+		m.Family.Metric = kept
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+// GetNetworkReport returns the cardinality of the network's metrics, with the
+// top offending metrics first: those with the most dropped series, then
+// those with the most active series. At most limit metrics are returned,
+// unless limit is non-positive.
+func (g *Guard) GetNetworkReport(networkID string, limit int) (NetworkReport, error) {
+	report := NetworkReport{NetworkID: networkID, Metrics: []MetricReport{}}
+	active, dropped, err := g.store.GetCounts(networkID, g.getStoreExpiry(clock.Now()))
+	if err != nil {
+		return report, err
+	}
+
+	byName := map[string]*MetricReport{}
+	getMetric := func(name string) *MetricReport {
+		if _, ok := byName[name]; !ok {
+			byName[name] = &MetricReport{MetricName: name}
+		}
+		return byName[name]
+	}
+	for name, n := range active {
+		getMetric(name).ActiveSeries = n
+		report.ActiveSeries += n
+	}
+	for name, n := range dropped {
+		getMetric(name).DroppedSeries = n
+	}
+	for _, m := range byName {
+		report.Metrics = append(report.Metrics, *m)
+	}
+	sort.Slice(report.Metrics, func(i, j int) bool {
+		a, b := report.Metrics[i], report.Metrics[j]
+		if a.DroppedSeries != b.DroppedSeries {
+			return a.DroppedSeries > b.DroppedSeries
+		}
+		if a.ActiveSeries != b.ActiveSeries {
+			return a.ActiveSeries > b.ActiveSeries
+		}
+		return a.MetricName < b.MetricName
+	})
+	if limit > 0 && len(report.Metrics) > limit {
+		report.Metrics = report.Metrics[:limit]
+	}
+	return report, nil
+}
+
+// GetConfig returns the guard's config.
+func (g *Guard) GetConfig() Config {
+	return g.cfg
+}
+
+// filterLabels strips the labels which aren't allowed on the metric.
+// Orc8r's network and gateway labels are never stripped.
+func (g *Guard) filterLabels(metricName string, lbls []*prometheus_models.LabelPair) []*prometheus_models.LabelPair {
+	allowed, hasAllowlist := g.allowedLabels[metricName]
+	kept := lbls[:0]
+	for _, l := range lbls {
+		name := l.GetName()
+		isContext := name == metrics.NetworkLabelName || name == metrics.GatewayLabelName
+		if !isContext && (g.deniedLabels[name] || (hasAllowlist && !allowed[name])) {
+			continue
+		}
+		kept = append(kept, l)
+	}
+	return kept
+}
+
+// admit marks the groups whose series are within budget as admitted, and
+// records the series dropped for colliding.
+// Series recently synced with the store are admitted, or dropped, from the
+// local cache. Other series are synced with the store, per network. If the
+// store is unavailable, series are admitted. Without budgets, all series are
+// admitted without syncing them with the store.
+func (g *Guard) admit(groups []*seriesGroup, collisions map[string]map[string]int64) {
+	if g.hasBudgets() {
+		groupsByNetwork := map[string][]*seriesGroup{}
+		for _, group := range groups {
+			groupsByNetwork[group.networkID] = append(groupsByNetwork[group.networkID], group)
+		}
+		now := clock.Now()
+		for networkID, networkGroups := range groupsByNetwork {
+			g.admitNetwork(networkID, networkGroups, now)
+		}
+	} else {
+		for _, group := range groups {
+			group.admitted = true
+		}
+	}
+
+	for networkID, dropped := range collisions {
+		err := g.store.AddDropped(networkID, dropped)
+		if err != nil {
+			glog.Errorf("Failed to record colliding series of network %s: %s", networkID, err)
+		}
+	}
+}
+
+// admitNetwork marks the groups of the network whose series are within
+// budget as admitted.
+func (g *Guard) admitNetwork(networkID string, groups []*seriesGroup, now time.Time) {
+	network := g.getNetwork(networkID)
+	syncInterval := g.cfg.SeriesTTL / syncFraction
+
+	network.mu.Lock()
+	if now.Sub(network.lastSweep) >= g.cfg.SeriesTTL {
+		g.sweep(network, now)
+	}
+	var toSync []*seriesGroup
+	for _, group := range groups {
+		if seen, ok := network.admitted[group.id.Key]; ok && now.Sub(seen.lastSynced) < syncInterval {
+			seen.lastSeen = now
+			group.admitted = true
+			continue
+		}
+		if droppedAt, ok := network.dropped[group.id.Key]; ok && now.Sub(droppedAt) < syncInterval {
+			continue
+		}
+		toSync = append(toSync, group)
+	}
+	network.mu.Unlock()
+	if len(toSync) == 0 {
+		return
+	}
+
+	budgets := Budgets{MaxSeriesPerNetwork: g.cfg.MaxSeriesPerNetwork, MaxSeriesPerMetric: g.cfg.MaxSeriesPerMetric}
+	series := make([]SeriesID, 0, len(toSync))
+	for _, group := range toSync {
+		series = append(series, group.id)
+	}
+	res, err := g.store.Admit(networkID, series, budgets, now, g.getStoreExpiry(now))
+	if err != nil {
+		glog.Errorf("Admitting series of network %s without enforcing budgets: %s", networkID, err)
+		for _, group := range toSync {
+			group.admitted = true
+		}
+		return
+	}
+	activeSeries.WithLabelValues(networkID).Set(float64(res.ActiveSeries))
+
+	network.mu.Lock()
+	defer network.mu.Unlock()
+	for _, group := range toSync {
+		if reason, ok := res.Dropped[group.id.Key]; ok {
+			network.dropped[group.id.Key] = now
+			droppedSeries.WithLabelValues(networkID, group.id.MetricName, reason).Inc()
+			continue
+		}
+		delete(network.dropped, group.id.Key)
+		network.admitted[group.id.Key] = &seenSeries{lastSeen: now, lastSynced: now}
+		group.admitted = true
+	}
+}
+
+// hasBudgets returns true if any series budget is limited.
+func (g *Guard) hasBudgets() bool {
+	return g.cfg.MaxSeriesPerNetwork > 0 || g.cfg.MaxSeriesPerMetric > 0
+}
+
+// getStoreExpiry returns the time before which series last seen are expired
+// from the store. Series are synced to the store at most once per sync
+// interval, so the store's last seen times lag by up to that interval.
+func (g *Guard) getStoreExpiry(now time.Time) time.Time {
+	return now.Add(-g.cfg.SeriesTTL - g.cfg.SeriesTTL/syncFraction)
+}
+
+// getNetwork returns the network's cached series.
+func (g *Guard) getNetwork(networkID string) *networkSeries {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	network, ok := g.networks[networkID]
+	if !ok {
+		network = &networkSeries{
+			admitted:  map[string]*seenSeries{},
+			dropped:   map[string]time.Time{},
+			lastSweep: clock.Now(),
+		}
+		g.networks[networkID] = network
+	}
+	return network
+}
+
+// sweep forgets the network's cached series which haven't been seen within
+// the TTL. The network's lock must be held.
+func (g *Guard) sweep(network *networkSeries, now time.Time) {
+	for key, seen := range network.admitted {
+		if now.Sub(seen.lastSeen) >= g.cfg.SeriesTTL {
+			delete(network.admitted, key)
+		}
+	}
+	for key, droppedAt := range network.dropped {
+		if now.Sub(droppedAt) >= g.cfg.SeriesTTL/syncFraction {
+			delete(network.dropped, key)
+		}
+	}
+	network.lastSweep = now
+}
+
+// aggregate returns the metrics of a series combined into one, or nil if
+// they can't be combined.
+func aggregate(metricType prometheus_models.MetricType, ms []*prometheus_models.Metric) *prometheus_models.Metric {
+	if len(ms) == 1 {
+		return ms[0]
+	}
+	ret := &prometheus_models.Metric{Label: ms[0].Label}
+	for _, m := range ms {
+		if m.GetTimestampMs() > ret.GetTimestampMs() {
+			ret.TimestampMs = proto.Int64(m.GetTimestampMs())
+		}
+	}
+	switch metricType {
+	case prometheus_models.MetricType_COUNTER:
+		var sum float64
+		for _, m := range ms {
+			sum += m.GetCounter().GetValue()
+		}
+		ret.Counter = &prometheus_models.Counter{Value: proto.Float64(sum)}
+		return ret
+	case prometheus_models.MetricType_HISTOGRAM:
+		ret.Histogram = mergeHistograms(ms)
+		if ret.Histogram == nil {
+			return nil
+		}
+		return ret
+	default:
+		// Gauges, summary quantiles, and untyped values can't be summed
+		return nil
+	}
+}
+
+// mergeHistograms returns the sum of the metrics' histograms, or nil if
+// their buckets differ.
+func mergeHistograms(ms []*prometheus_models.Metric) *prometheus_models.Histogram {
+	first := ms[0].GetHistogram()
+	ret := &prometheus_models.Histogram{
+		SampleCount: proto.Uint64(0),
+		SampleSum:   proto.Float64(0),
+	}
+	for _, b := range first.GetBucket() {
+		ret.Bucket = append(ret.Bucket, &prometheus_models.Bucket{UpperBound: proto.Float64(b.GetUpperBound()), CumulativeCount: proto.Uint64(0)})
+	}
+	for _, m := range ms {
+		h := m.GetHistogram()
+		if len(h.GetBucket()) != len(ret.Bucket) {
+			return nil
+		}
+		for i, b := range h.GetBucket() {
+			if b.GetUpperBound() != ret.Bucket[i].GetUpperBound() {
+				return nil
+			}
+			*ret.Bucket[i].CumulativeCount += b.GetCumulativeCount()
+		}
+		*ret.SampleCount += h.GetSampleCount()
+		*ret.SampleSum += h.GetSampleSum()
+	}
+	return ret
+}
+
+func getNetworkID(ctx exporters.MetricContext) (string, bool) {
+	switch c := ctx.AdditionalContext.(type) {
+	case *exporters.GatewayMetricContext:
+		return c.NetworkID, true
+	case *exporters.PushedMetricContext:
+		return c.NetworkID, true
+	default:
+		return "", false
+	}
+}
+
+// getSeriesKey returns a key identifying the series of the metric name and
+// labels, of the form name{label1=value1,...} with labels sorted by name.
+func getSeriesKey(metricName string, lbls []*prometheus_models.LabelPair) string {
+	pairs := make([]string, 0, len(lbls))
+	for _, l := range lbls {
+		pairs = append(pairs, l.GetName()+"="+l.GetValue())
+	}
+	sort.Strings(pairs)
+	return metricName + "{" + strings.Join(pairs, ",") + "}"
+}
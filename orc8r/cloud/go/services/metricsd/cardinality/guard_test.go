@@ -0,0 +1,253 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cardinality
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/metricsd/exporters"
+	tests "magma/orc8r/cloud/go/services/metricsd/test_common"
+	"magma/orc8r/cloud/go/sqorc"
+	"magma/orc8r/lib/go/metrics"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuard_Labels(t *testing.T) {
+	guard := NewGuard(Config{
+		DeniedLabels:        []string{metrics.ImsiLabelName, metrics.NetworkLabelName},
+		AllowedLabels:       map[string][]string{"restricted": {"service"}},
+		MaxSeriesPerNetwork: 100,
+	}, newTestStore(t))
+
+	filtered := guard.Filter([]exporters.MetricAndContext{
+		makeMetric("n1", "m1", map[string]string{metrics.ImsiLabelName: "IMSI001", "service": "mme"}),
+		makeMetric("n1", "restricted", map[string]string{"service": "mme", "apn": "internet"}),
+	})
+	assert.Equal(t, []map[string]string{
+		{metrics.NetworkLabelName: "n1", metrics.GatewayLabelName: "g1", "service": "mme"},
+		{metrics.NetworkLabelName: "n1", metrics.GatewayLabelName: "g1", "service": "mme"},
+	}, getLabels(filtered))
+
+	// Gauges collapsed into one series can't be aggregated, so are dropped
+	family := makeMetric("n1", "m2", map[string]string{metrics.ImsiLabelName: "IMSI001"})
+	family.Family.Metric = append(family.Family.Metric, makeMetric("n1", "m2", map[string]string{metrics.ImsiLabelName: "IMSI002"}).Family.Metric...)
+	filtered = guard.Filter([]exporters.MetricAndContext{
+		family,
+		makeMetric("n1", "m1", map[string]string{metrics.ImsiLabelName: "IMSI001", "service": "mme"}),
+		makeMetric("n1", "m1", map[string]string{metrics.ImsiLabelName: "IMSI002", "service": "mme"}),
+	})
+	assert.Empty(t, filtered)
+	report, err := guard.GetNetworkReport("n1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []MetricReport{
+		{MetricName: "m1", ActiveSeries: 1, DroppedSeries: 1},
+		{MetricName: "m2", DroppedSeries: 1},
+		{MetricName: "restricted", ActiveSeries: 1},
+	}, report.Metrics)
+
+	// Counters are summed, and histograms merged
+	counter := makeFamily("counter", dto.MetricType_COUNTER, tests.MakePromoCounter(1), tests.MakePromoCounter(2))
+	histogram := makeFamily("histogram", dto.MetricType_HISTOGRAM,
+		tests.MakePromoHistogram([]float64{1, 5}, []float64{0.5, 3}),
+		tests.MakePromoHistogram([]float64{1, 5}, []float64{0.5, 3}),
+	)
+	filtered = guard.Filter([]exporters.MetricAndContext{counter, histogram})
+	require.Len(t, filtered, 2)
+	require.Len(t, filtered[0].Family.Metric, 1)
+	assert.Equal(t, float64(3), filtered[0].Family.Metric[0].GetCounter().GetValue())
+	require.Len(t, filtered[1].Family.Metric, 1)
+	merged := filtered[1].Family.Metric[0].GetHistogram()
+	assert.Equal(t, uint64(4), merged.GetSampleCount())
+	assert.Equal(t, float64(7), merged.GetSampleSum())
+	assert.Equal(t, uint64(2), merged.GetBucket()[0].GetCumulativeCount())
+	assert.Equal(t, uint64(4), merged.GetBucket()[1].GetCumulativeCount())
+
+	// Cloud metrics pass through
+	cloud := makeMetric("n1", "m3", map[string]string{metrics.ImsiLabelName: "IMSI001"})
+	cloud.Context.AdditionalContext = &exporters.CloudMetricContext{CloudHost: "host"}
+	filtered = guard.Filter([]exporters.MetricAndContext{cloud})
+	assert.Len(t, filtered[0].Family.Metric[0].Label, 3)
+}
+
+func TestGuard_NoBudgets(t *testing.T) {
+	guard := NewGuard(Config{}, newTestStore(t))
+
+	// Without budgets, series are admitted without being tracked
+	filtered := guard.Filter([]exporters.MetricAndContext{
+		makeMetric("n1", "m1", map[string]string{metrics.ImsiLabelName: "IMSI001"}),
+		makeMetric("n1", "m1", map[string]string{metrics.ImsiLabelName: "IMSI002"}),
+	})
+	assert.Len(t, filtered, 2)
+	report, err := guard.GetNetworkReport("n1", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Metrics)
+}
+
+func TestGuard_Budgets(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	guard := NewGuard(Config{MaxSeriesPerNetwork: 3, MaxSeriesPerMetric: 2, SeriesTTL: time.Minute}, newTestStore(t))
+	submit := func(networkID, name, imsi string) bool {
+		return len(guard.Filter([]exporters.MetricAndContext{makeMetric(networkID, name, map[string]string{metrics.ImsiLabelName: imsi})})) == 1
+	}
+	droppedBefore := testutil.ToFloat64(droppedSeries.WithLabelValues("budgets", "m1", metricBudgetReason))
+
+	assert.True(t, submit("budgets", "m1", "IMSI001"))
+	assert.True(t, submit("budgets", "m1", "IMSI002"))
+	// Per-metric budget
+	assert.False(t, submit("budgets", "m1", "IMSI003"))
+	assert.False(t, submit("budgets", "m1", "IMSI004"))
+	// Existing series are still admitted
+	assert.True(t, submit("budgets", "m1", "IMSI001"))
+	assert.True(t, submit("budgets", "m2", "IMSI001"))
+	// Per-network budget
+	assert.False(t, submit("budgets", "m2", "IMSI002"))
+	// Other networks have their own budgets
+	assert.True(t, submit("other", "m1", "IMSI003"))
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(droppedSeries.WithLabelValues("budgets", "m1", metricBudgetReason))-droppedBefore)
+	report, err := guard.GetNetworkReport("budgets", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, NetworkReport{
+		NetworkID:    "budgets",
+		ActiveSeries: 3,
+		Metrics: []MetricReport{
+			{MetricName: "m1", ActiveSeries: 2, DroppedSeries: 2},
+			{MetricName: "m2", ActiveSeries: 1, DroppedSeries: 1},
+		},
+	}, report)
+	report, err = guard.GetNetworkReport("budgets", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, NetworkReport{
+		NetworkID:    "budgets",
+		ActiveSeries: 3,
+		Metrics:      []MetricReport{{MetricName: "m1", ActiveSeries: 2, DroppedSeries: 2}},
+	}, report)
+	report, err = guard.GetNetworkReport("unknown", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, NetworkReport{NetworkID: "unknown", Metrics: []MetricReport{}}, report)
+
+	// Series expire after the TTL, freeing their budget
+	clock.SetAndFreezeClock(t, time.Unix(1030, 0))
+	assert.True(t, submit("budgets", "m1", "IMSI001"))
+	clock.SetAndFreezeClock(t, time.Unix(1080, 0))
+	assert.True(t, submit("budgets", "m1", "IMSI003"))
+	report, err = guard.GetNetworkReport("budgets", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.ActiveSeries)
+	assert.Equal(t, MetricReport{MetricName: "m1", ActiveSeries: 2, DroppedSeries: 2}, report.Metrics[0])
+}
+
+func TestGuard_AcrossReplicas(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	store := newTestStore(t)
+	cfg := Config{MaxSeriesPerMetric: 1, SeriesTTL: time.Minute}
+	replicaA, replicaB := NewGuard(cfg, store), NewGuard(cfg, store)
+	submit := func(guard *Guard, imsi string) bool {
+		return len(guard.Filter([]exporters.MetricAndContext{makeMetric("n1", "m1", map[string]string{metrics.ImsiLabelName: imsi})})) == 1
+	}
+
+	// Budgets are shared
+	assert.True(t, submit(replicaA, "IMSI001"))
+	assert.False(t, submit(replicaB, "IMSI002"))
+	assert.True(t, submit(replicaB, "IMSI001"))
+
+	// As are reports
+	expected := NetworkReport{
+		NetworkID:    "n1",
+		ActiveSeries: 1,
+		Metrics:      []MetricReport{{MetricName: "m1", ActiveSeries: 1, DroppedSeries: 1}},
+	}
+	for _, guard := range []*Guard{replicaA, replicaB} {
+		report, err := guard.GetNetworkReport("n1", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, report)
+	}
+
+	// Series seen by either replica stay active
+	clock.SetAndFreezeClock(t, time.Unix(1050, 0))
+	assert.True(t, submit(replicaB, "IMSI001"))
+	clock.SetAndFreezeClock(t, time.Unix(1100, 0))
+	assert.False(t, submit(replicaA, "IMSI002"))
+}
+
+func newTestStore(t *testing.T) Store {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	store := NewSQLStore(db, sqorc.NewSQLiteStatementBuilder())
+	require.NoError(t, store.Initialize())
+	return store
+}
+
+func makeMetric(networkID, name string, lbls map[string]string) exporters.MetricAndContext {
+	labelPairs := []*dto.LabelPair{
+		{Name: tests.MakeStrPtr(metrics.NetworkLabelName), Value: tests.MakeStrPtr(networkID)},
+		{Name: tests.MakeStrPtr(metrics.GatewayLabelName), Value: tests.MakeStrPtr("g1")},
+	}
+	for k, v := range lbls {
+		labelPairs = append(labelPairs, &dto.LabelPair{Name: tests.MakeStrPtr(k), Value: tests.MakeStrPtr(v)})
+	}
+	gauge := tests.MakePromoGauge(1)
+	gauge.Label = labelPairs
+	return exporters.MetricAndContext{
+		Family: &dto.MetricFamily{
+			Name:   tests.MakeStrPtr(name),
+			Type:   tests.MakeMetricTypePointer(dto.MetricType_GAUGE),
+			Metric: []*dto.Metric{&gauge},
+		},
+		Context: exporters.MetricContext{
+			MetricName:        name,
+			AdditionalContext: &exporters.GatewayMetricContext{NetworkID: networkID, GatewayID: "g1"},
+		},
+	}
+}
+
+// makeFamily returns a family of the passed metrics, labeled with
+// consecutive IMSIs.
+func makeFamily(name string, metricType dto.MetricType, ms ...dto.Metric) exporters.MetricAndContext {
+	ret := makeMetric("n1", name, nil)
+	ret.Family.Type = tests.MakeMetricTypePointer(metricType)
+	ret.Family.Metric = nil
+	for i := range ms {
+		imsi := fmt.Sprintf("IMSI00%d", i+1)
+		ms[i].Label = makeMetric("n1", name, map[string]string{metrics.ImsiLabelName: imsi}).Family.Metric[0].Label
+		ret.Family.Metric = append(ret.Family.Metric, &ms[i])
+	}
+	return ret
+}
+
+func getLabels(metricsAndContexts []exporters.MetricAndContext) []map[string]string {
+	var ret []map[string]string
+	for _, m := range metricsAndContexts {
+		for _, metric := range m.Family.Metric {
+			lbls := map[string]string{}
+			for _, l := range metric.Label {
+				lbls[l.GetName()] = l.GetValue()
+			}
+			ret = append(ret, lbls)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cardinality
+
+import (
+	"magma/orc8r/lib/go/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	metricNameLabel = "metric_name"
+	reasonLabel     = "reason"
+
+	// Reasons a series is dropped
+	networkBudgetReason = "network_budget"
+	metricBudgetReason  = "metric_budget"
+	// labelCollisionReason is for series which collided once their
+	// disallowed labels were stripped, and couldn't be aggregated
+	labelCollisionReason = "label_collision"
+)
+
+var (
+	droppedSeries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "metricsd_dropped_series_total",
+			Help: "Number of series dropped by this metricsd replica for exceeding a series budget, or colliding once stripped",
+		},
+		[]string{metrics.NetworkLabelName, metricNameLabel, reasonLabel},
+	)
+	activeSeries = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "metricsd_active_series",
+			Help: "Number of series of the network seen by any metricsd replica within the series TTL, as of this replica's last sync",
+		},
+		[]string{metrics.NetworkLabelName},
+	)
+)
@@ -0,0 +1,459 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cardinality
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"magma/orc8r/cloud/go/sqorc"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+)
+
+const (
+	networksTableName = "metricsd_cardinality_networks"
+	seriesTableName   = "metricsd_cardinality_series"
+	droppedTableName  = "metricsd_cardinality_dropped"
+
+	networkIDCol  = "network_id"
+	lockedAtCol   = "locked_at"
+	seriesKeyCol  = "series_key"
+	metricNameCol = "metric_name"
+	lastSeenCol   = "last_seen"
+	droppedCol    = "dropped"
+)
+
+// SeriesID identifies a series of a metric.
+type SeriesID struct {
+	MetricName string
+	Key        string
+}
+
+// Budgets bound the number of series of a network.
+type Budgets struct {
+	MaxSeriesPerNetwork int
+	MaxSeriesPerMetric  int
+}
+
+// AdmitResult is the outcome of admitting a network's series.
+type AdmitResult struct {
+	// Dropped maps the keys of dropped series to the reason they were
+	// dropped
+	Dropped map[string]string
+	// ActiveSeries is the network's number of active series, after admission
+	ActiveSeries int
+}
+
+// Store shares the series seen, and the counts of series dropped, across
+// metricsd replicas, so budgets hold across replicas.
+type Store interface {
+	// Admit records the network's series as seen at now, dropping new series
+	// which would exceed the budgets. Series last seen before expiry are
+	// forgotten first.
+	Admit(networkID string, series []SeriesID, budgets Budgets, now, expiry time.Time) (AdmitResult, error)
+
+	// AddDropped adds to the counts of the network's dropped series, keyed
+	// by metric name.
+	AddDropped(networkID string, dropped map[string]int64) error
+
+	// GetCounts returns the network's active series last seen after expiry,
+	// and its dropped series, keyed by metric name.
+	GetCounts(networkID string, expiry time.Time) (map[string]int, map[string]int64, error)
+}
+
+// SQLStore is a Store backed by a SQL database.
+// Concurrent admissions for a network are serialized by locking the
+// network's row of the networks table.
+//
+// Series columns:
+//	- network_id	-- network of the series
+//	- series_key	-- key identifying the series within the network
+//	- metric_name	-- name of the series' metric
+//	- last_seen		-- Unix time, in nanoseconds, the series was last seen
+type SQLStore struct {
+	db      *sql.DB
+	builder sqorc.StatementBuilder
+}
+
+// NewSQLStore returns a store backed by the SQL database.
+// Initialize must be called before use.
+func NewSQLStore(db *sql.DB, builder sqorc.StatementBuilder) *SQLStore {
+	return &SQLStore{db: db, builder: builder}
+}
+
+// Initialize the store's tables.
+func (s *SQLStore) Initialize() error {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		_, err := s.builder.CreateTable(networksTableName).
+			IfNotExists().
+			Column(networkIDCol).Type(sqorc.ColumnTypeText).NotNull().PrimaryKey().EndColumn().
+			Column(lockedAtCol).Type(sqorc.ColumnTypeBigInt).NotNull().Default(0).EndColumn().
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize cardinality networks table")
+		}
+		_, err = s.builder.CreateTable(seriesTableName).
+			IfNotExists().
+			Column(networkIDCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(seriesKeyCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(metricNameCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(lastSeenCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			PrimaryKey(networkIDCol, seriesKeyCol).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize cardinality series table")
+		}
+		_, err = s.builder.CreateTable(droppedTableName).
+			IfNotExists().
+			Column(networkIDCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(metricNameCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(droppedCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			PrimaryKey(networkIDCol, metricNameCol).
+			RunWith(tx).
+			Exec()
+		return nil, errors.Wrap(err, "initialize cardinality dropped table")
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+func (s *SQLStore) Admit(networkID string, series []SeriesID, budgets Budgets, now, expiry time.Time) (AdmitResult, error) {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		err := s.lockNetwork(tx, networkID, now)
+		if err != nil {
+			return nil, err
+		}
+		_, err = s.builder.Delete(seriesTableName).
+			Where(squirrel.And{
+				squirrel.Eq{networkIDCol: networkID},
+				squirrel.Lt{lastSeenCol: expiry.UnixNano()},
+			}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "delete expired series")
+		}
+
+		existing, err := s.getExistingKeys(tx, networkID, series)
+		if err != nil {
+			return nil, err
+		}
+		activeByMetric, err := s.getActiveCounts(tx, networkID)
+		if err != nil {
+			return nil, err
+		}
+		active := 0
+		for _, n := range activeByMetric {
+			active += n
+		}
+
+		result := AdmitResult{Dropped: map[string]string{}}
+		var touched []string
+		var created []SeriesID
+		dropped := map[string]int64{}
+		for _, sid := range series {
+			if existing[sid.Key] {
+				touched = append(touched, sid.Key)
+				continue
+			}
+			reason := ""
+			switch {
+			case budgets.MaxSeriesPerNetwork > 0 && active >= budgets.MaxSeriesPerNetwork:
+				reason = networkBudgetReason
+			case budgets.MaxSeriesPerMetric > 0 && activeByMetric[sid.MetricName] >= budgets.MaxSeriesPerMetric:
+				reason = metricBudgetReason
+			}
+			if reason != "" {
+				result.Dropped[sid.Key] = reason
+				dropped[sid.MetricName]++
+				continue
+			}
+			// Guard against duplicate keys in the passed series
+			existing[sid.Key] = true
+			created = append(created, sid)
+			active++
+			activeByMetric[sid.MetricName]++
+		}
+		result.ActiveSeries = active
+
+		err = s.touchSeries(tx, networkID, touched, now)
+		if err != nil {
+			return nil, err
+		}
+		err = s.insertSeries(tx, networkID, created, now)
+		if err != nil {
+			return nil, err
+		}
+		err = s.addDropped(tx, networkID, dropped)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	ret, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	if err != nil {
+		return AdmitResult{}, errors.Wrapf(err, "admit series of network %s", networkID)
+	}
+	return ret.(AdmitResult), nil
+}
+
+func (s *SQLStore) AddDropped(networkID string, dropped map[string]int64) error {
+	if len(dropped) == 0 {
+		return nil
+	}
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		err := s.lockNetwork(tx, networkID, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.addDropped(tx, networkID, dropped)
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return errors.Wrapf(err, "add dropped series of network %s", networkID)
+}
+
+func (s *SQLStore) GetCounts(networkID string, expiry time.Time) (map[string]int, map[string]int64, error) {
+	type counts struct {
+		active  map[string]int
+		dropped map[string]int64
+	}
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		ret := counts{active: map[string]int{}, dropped: map[string]int64{}}
+		rows, err := s.builder.Select(metricNameCol, "COUNT(*)").
+			From(seriesTableName).
+			Where(squirrel.And{
+				squirrel.Eq{networkIDCol: networkID},
+				squirrel.GtOrEq{lastSeenCol: expiry.UnixNano()},
+			}).
+			GroupBy(metricNameCol).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "count active series")
+		}
+		defer sqorc.CloseRowsLogOnError(rows, "GetCounts")
+		for rows.Next() {
+			var name string
+			var n int
+			if err := rows.Scan(&name, &n); err != nil {
+				return nil, errors.Wrap(err, "scan active series count")
+			}
+			ret.active[name] = n
+		}
+		if err := rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "count active series")
+		}
+
+		ret.dropped, err = s.getDropped(tx, networkID)
+		if err != nil {
+			return nil, err
+		}
+		return ret, nil
+	}
+	ret, err := sqorc.ExecInTx(s.db, &sql.TxOptions{ReadOnly: true}, nil, txFn)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "get series counts of network %s", networkID)
+	}
+	c := ret.(counts)
+	return c.active, c.dropped, nil
+}
+
+// lockNetwork locks the network's row until the transaction completes,
+// creating the row if needed.
+func (s *SQLStore) lockNetwork(tx *sql.Tx, networkID string, now time.Time) error {
+	_, err := s.builder.Insert(networksTableName).
+		Columns(networkIDCol, lockedAtCol).
+		Values(networkID, now.UnixNano()).
+		OnConflict(nil, networkIDCol).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "insert network")
+	}
+	_, err = s.builder.Update(networksTableName).
+		Set(lockedAtCol, now.UnixNano()).
+		Where(squirrel.Eq{networkIDCol: networkID}).
+		RunWith(tx).
+		Exec()
+	return errors.Wrap(err, "lock network")
+}
+
+// getExistingKeys returns the keys of the passed series which are recorded.
+func (s *SQLStore) getExistingKeys(tx *sql.Tx, networkID string, series []SeriesID) (map[string]bool, error) {
+	ret := map[string]bool{}
+	chunkSize := s.builder.MaxBindVariables() - 1
+	for start := 0; start < len(series); start += chunkSize {
+		end := start + chunkSize
+		if end > len(series) {
+			end = len(series)
+		}
+		keys := make([]string, 0, end-start)
+		for _, sid := range series[start:end] {
+			keys = append(keys, sid.Key)
+		}
+		rows, err := s.builder.Select(seriesKeyCol).
+			From(seriesTableName).
+			Where(squirrel.And{
+				squirrel.Eq{networkIDCol: networkID},
+				squirrel.Eq{seriesKeyCol: keys},
+			}).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "select existing series")
+		}
+		for rows.Next() {
+			var key string
+			if err := rows.Scan(&key); err != nil {
+				sqorc.CloseRowsLogOnError(rows, "getExistingKeys")
+				return nil, errors.Wrap(err, "scan existing series")
+			}
+			ret[key] = true
+		}
+		err = rows.Err()
+		sqorc.CloseRowsLogOnError(rows, "getExistingKeys")
+		if err != nil {
+			return nil, errors.Wrap(err, "select existing series")
+		}
+	}
+	return ret, nil
+}
+
+func (s *SQLStore) getActiveCounts(tx *sql.Tx, networkID string) (map[string]int, error) {
+	rows, err := s.builder.Select(metricNameCol, "COUNT(*)").
+		From(seriesTableName).
+		Where(squirrel.Eq{networkIDCol: networkID}).
+		GroupBy(metricNameCol).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, errors.Wrap(err, "count series")
+	}
+	defer sqorc.CloseRowsLogOnError(rows, "getActiveCounts")
+	ret := map[string]int{}
+	for rows.Next() {
+		var name string
+		var n int
+		if err := rows.Scan(&name, &n); err != nil {
+			return nil, errors.Wrap(err, "scan series count")
+		}
+		ret[name] = n
+	}
+	return ret, errors.Wrap(rows.Err(), "count series")
+}
+
+func (s *SQLStore) touchSeries(tx *sql.Tx, networkID string, keys []string, now time.Time) error {
+	chunkSize := s.builder.MaxBindVariables() - 2
+	for start := 0; start < len(keys); start += chunkSize {
+		end := start + chunkSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		_, err := s.builder.Update(seriesTableName).
+			Set(lastSeenCol, now.UnixNano()).
+			Where(squirrel.And{
+				squirrel.Eq{networkIDCol: networkID},
+				squirrel.Eq{seriesKeyCol: keys[start:end]},
+			}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return errors.Wrap(err, "touch series")
+		}
+	}
+	return nil
+}
+
+func (s *SQLStore) insertSeries(tx *sql.Tx, networkID string, series []SeriesID, now time.Time) error {
+	chunkSize := sqorc.GetInsertChunkSize(s.builder, 4)
+	for start := 0; start < len(series); start += chunkSize {
+		end := start + chunkSize
+		if end > len(series) {
+			end = len(series)
+		}
+		insert := s.builder.Insert(seriesTableName).
+			Columns(networkIDCol, seriesKeyCol, metricNameCol, lastSeenCol)
+		for _, sid := range series[start:end] {
+			insert = insert.Values(networkID, sid.Key, sid.MetricName, now.UnixNano())
+		}
+		_, err := insert.RunWith(tx).Exec()
+		if err != nil {
+			return errors.Wrap(err, "insert series")
+		}
+	}
+	return nil
+}
+
+// addDropped adds to the network's dropped series counts. The network must
+// be locked.
+func (s *SQLStore) addDropped(tx *sql.Tx, networkID string, dropped map[string]int64) error {
+	if len(dropped) == 0 {
+		return nil
+	}
+	current, err := s.getDropped(tx, networkID)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(dropped))
+	for name := range dropped {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if count, ok := current[name]; ok {
+			_, err = s.builder.Update(droppedTableName).
+				Set(droppedCol, count+dropped[name]).
+				Where(squirrel.Eq{networkIDCol: networkID, metricNameCol: name}).
+				RunWith(tx).
+				Exec()
+		} else {
+			_, err = s.builder.Insert(droppedTableName).
+				Columns(networkIDCol, metricNameCol, droppedCol).
+				Values(networkID, name, dropped[name]).
+				RunWith(tx).
+				Exec()
+		}
+		if err != nil {
+			return errors.Wrapf(err, "add dropped series of metric %s", name)
+		}
+	}
+	return nil
+}
+
+func (s *SQLStore) getDropped(tx *sql.Tx, networkID string) (map[string]int64, error) {
+	rows, err := s.builder.Select(metricNameCol, droppedCol).
+		From(droppedTableName).
+		Where(squirrel.Eq{networkIDCol: networkID}).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, errors.Wrap(err, "select dropped series")
+	}
+	defer sqorc.CloseRowsLogOnError(rows, "getDropped")
+	ret := map[string]int64{}
+	for rows.Next() {
+		var name string
+		var n int64
+		if err := rows.Scan(&name, &n); err != nil {
+			return nil, errors.Wrap(err, "scan dropped series")
+		}
+		ret[name] = n
+	}
+	return ret, errors.Wrap(rows.Err(), "select dropped series")
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsd
+
+import (
+	"magma/orc8r/cloud/go/orc8r"
+	"magma/orc8r/cloud/go/services/metricsd/cardinality"
+	"magma/orc8r/cloud/go/services/metricsd/exporters"
+	"magma/orc8r/lib/go/service/config"
+
+	"github.com/pkg/errors"
+)
+
+// Config is the structured part of the metricsd.yml service config.
+type Config struct {
+	// Exporters write directly to datasinks, e.g. Prometheus remote-write or
+	// OTLP endpoints.
+	Exporters []exporters.ExporterConfig `yaml:"exporters"`
+	// Cardinality configures the series budgets and label allow/deny lists
+	// of gateway metrics.
+	Cardinality cardinality.Config `yaml:"cardinality"`
+}
+
+// GetServiceConfig returns the structured metricsd service config.
+func GetServiceConfig() (Config, error) {
+	var cfg Config
+	_, _, err := config.GetStructuredServiceConfig(orc8r.ModuleName, ServiceName, &cfg)
+	if err != nil {
+		return Config{}, errors.Wrap(err, "read metricsd service config")
+	}
+	return cfg, nil
+}
+
+// GetConfiguredExporters returns the exporters configured in the metricsd
+// service config.
+func GetConfiguredExporters(cfg Config) ([]exporters.Exporter, error) {
+	var ret []exporters.Exporter
+	for _, exporterCfg := range cfg.Exporters {
+		e, err := exporters.NewExporter(exporterCfg)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, e)
+	}
+	return ret, nil
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporters
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// maxBufferedBatches bounds the in-memory buffer of an exporter. Metrics
+// beyond the bound are spooled without attempting to write them.
+const maxBufferedBatches = 10
+
+// batchWriter writes a batch of metrics to a datasink.
+type batchWriter interface {
+	// write writes the batch. Errors wrapped in permanentError indicate the
+	// batch was rejected, and shouldn't be retried.
+	write(batch []MetricAndContext) error
+}
+
+// permanentError marks a write error which retrying won't fix.
+type permanentError struct {
+	error
+}
+
+func isPermanent(err error) bool {
+	_, ok := err.(permanentError)
+	return ok
+}
+
+// bufferedExporter batches submitted metrics and writes them to a datasink
+// in the background. Failed writes are retried with exponential backoff,
+// then spooled to disk until the datasink is available again.
+type bufferedExporter struct {
+	name   string
+	writer batchWriter
+	cfg    BatchConfig
+	// spool is nil when spooling is disabled
+	spool *spool
+
+	bufferMu sync.Mutex
+	buffer   []MetricAndContext
+	flushCh  chan struct{}
+
+	// flushMu serializes flushes
+	flushMu sync.Mutex
+}
+
+// newBufferedExporter returns a buffered exporter which flushes in the
+// background.
+func newBufferedExporter(name string, writer batchWriter, cfg BatchConfig) (*bufferedExporter, error) {
+	e, err := makeBufferedExporter(name, writer, cfg)
+	if err != nil {
+		return nil, err
+	}
+	go e.run()
+	return e, nil
+}
+
+func makeBufferedExporter(name string, writer batchWriter, cfg BatchConfig) (*bufferedExporter, error) {
+	e := &bufferedExporter{
+		name:    name,
+		writer:  writer,
+		cfg:     cfg.withDefaults(),
+		flushCh: make(chan struct{}, 1),
+	}
+	if e.cfg.SpoolDir != "" {
+		s, err := newSpool(e.cfg.SpoolDir, e.cfg.MaxSpoolBytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "create spool of exporter %s", name)
+		}
+		e.spool = s
+	}
+	return e, nil
+}
+
+// Submit buffers the metrics, to be written on the next flush. Write errors
+// are logged rather than returned.
+// Samples without a timestamp are stamped on submit, so samples written late,
+// e.g. from the spool, keep their collection time.
+func (e *bufferedExporter) Submit(metrics []MetricAndContext) error {
+	metrics = stampMetrics(metrics)
+	e.bufferMu.Lock()
+	e.buffer = append(e.buffer, metrics...)
+	var overflow []MetricAndContext
+	if maxLen := maxBufferedBatches * e.cfg.MaxBatchSize; len(e.buffer) > maxLen {
+		overflow = e.buffer[:len(e.buffer)-maxLen]
+		e.buffer = e.buffer[len(e.buffer)-maxLen:]
+	}
+	full := len(e.buffer) >= e.cfg.MaxBatchSize
+	e.bufferMu.Unlock()
+
+	if full {
+		select {
+		case e.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	if len(overflow) != 0 {
+		e.handleFailedBatch(overflow, errors.New("exporter buffer full"))
+	}
+	return nil
+}
+
+func (e *bufferedExporter) run() {
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-e.flushCh:
+		}
+		e.flush()
+	}
+}
+
+// flush writes all buffered metrics, then retries spooled batches if every
+// write succeeded.
+func (e *bufferedExporter) flush() {
+	e.flushMu.Lock()
+	defer e.flushMu.Unlock()
+
+	e.bufferMu.Lock()
+	buffer := e.buffer
+	e.buffer = nil
+	e.bufferMu.Unlock()
+
+	ok := true
+	for len(buffer) != 0 {
+		n := e.cfg.MaxBatchSize
+		if n > len(buffer) {
+			n = len(buffer)
+		}
+		batch := buffer[:n]
+		buffer = buffer[n:]
+		if err := e.writeWithRetries(batch); err != nil {
+			ok = ok && isPermanent(err)
+			e.handleFailedBatch(batch, err)
+		}
+	}
+	if ok {
+		e.drainSpool()
+	}
+}
+
+func (e *bufferedExporter) writeWithRetries(batch []MetricAndContext) error {
+	backoff := e.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := e.writer.write(batch)
+		if err == nil || isPermanent(err) || attempt >= e.cfg.MaxRetries {
+			return err
+		}
+		glog.V(2).Infof("Exporter %s write failed, retrying in %s: %s", e.name, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// handleFailedBatch spools the batch, unless the failure was permanent or
+// spooling is disabled, in which case the batch is dropped.
+func (e *bufferedExporter) handleFailedBatch(batch []MetricAndContext, err error) {
+	if isPermanent(err) || e.spool == nil {
+		glog.Errorf("Exporter %s dropped batch of %d metric families: %s", e.name, len(batch), err)
+		return
+	}
+	glog.Warningf("Exporter %s spooling batch of %d metric families: %s", e.name, len(batch), err)
+	if serr := e.spool.push(batch); serr != nil {
+		glog.Errorf("Exporter %s dropped batch of %d metric families: %s", e.name, len(batch), serr)
+	}
+}
+
+// drainSpool writes spooled batches, oldest first, until a write fails.
+func (e *bufferedExporter) drainSpool() {
+	if e.spool == nil {
+		return
+	}
+	files, err := e.spool.list()
+	if err != nil {
+		glog.Errorf("Exporter %s failed to list spool: %s", e.name, err)
+		return
+	}
+	for _, f := range files {
+		batch, err := e.spool.read(f)
+		if err != nil {
+			err = permanentError{err}
+		} else {
+			err = e.writer.write(batch)
+		}
+		if err != nil && !isPermanent(err) {
+			glog.V(2).Infof("Exporter %s failed to write spooled batch %s: %s", e.name, f, err)
+			return
+		}
+		if err != nil {
+			glog.Errorf("Exporter %s dropped spooled batch %s: %s", e.name, f, err)
+		}
+		if err := e.spool.remove(f); err != nil {
+			glog.Errorf("Exporter %s: %s", e.name, err)
+			return
+		}
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporters
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	tests "magma/orc8r/cloud/go/services/metricsd/test_common"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBatchWriter struct {
+	batches [][]MetricAndContext
+	err     error
+	calls   int
+}
+
+func (w *testBatchWriter) write(batch []MetricAndContext) error {
+	w.calls++
+	if w.err != nil {
+		return w.err
+	}
+	w.batches = append(w.batches, batch)
+	return nil
+}
+
+func TestBufferedExporter_Batching(t *testing.T) {
+	writer := &testBatchWriter{}
+	e, err := makeBufferedExporter("test", writer, BatchConfig{MaxBatchSize: 2, FlushInterval: time.Hour})
+	require.NoError(t, err)
+
+	assert.NoError(t, e.Submit(makeTestMetrics("m1", "m2", "m3")))
+	e.flush()
+	require.Len(t, writer.batches, 2)
+	assert.Equal(t, []string{"m1", "m2"}, getMetricNames(writer.batches[0]))
+	assert.Equal(t, []string{"m3"}, getMetricNames(writer.batches[1]))
+
+	// Nothing buffered
+	e.flush()
+	assert.Len(t, writer.batches, 2)
+}
+
+func TestBufferedExporter_Spool(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	dir, err := ioutil.TempDir("", "metricsd_spool")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writer := &testBatchWriter{err: errors.New("unavailable")}
+	e, err := makeBufferedExporter("test", writer, BatchConfig{
+		MaxBatchSize:  1,
+		FlushInterval: time.Hour,
+		MaxRetries:    2,
+		RetryBackoff:  time.Millisecond,
+		SpoolDir:      dir,
+	})
+	require.NoError(t, err)
+
+	// Failed writes are retried, then spooled
+	assert.NoError(t, e.Submit(makeTestMetrics("m1")))
+	e.flush()
+	assert.Equal(t, 3, writer.calls)
+	assert.NoError(t, e.Submit(makeTestMetrics("m2")))
+	e.flush()
+	files, err := e.spool.list()
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	// Spooled batches are written, oldest first, after the next successful
+	// flush
+	writer.err = nil
+	clock.SetAndFreezeClock(t, time.Unix(2000, 0))
+	assert.NoError(t, e.Submit(makeTestMetrics("m3")))
+	e.flush()
+	require.Len(t, writer.batches, 3)
+	assert.Equal(t, []string{"m3"}, getMetricNames(writer.batches[0]))
+	assert.Equal(t, []string{"m1"}, getMetricNames(writer.batches[1]))
+	assert.Equal(t, []string{"m2"}, getMetricNames(writer.batches[2]))
+
+	// Spooled samples keep the time they were submitted
+	assert.Equal(t, int64(2000*1000), writer.batches[0][0].Family.Metric[0].GetTimestampMs())
+	assert.Equal(t, int64(1000*1000), writer.batches[1][0].Family.Metric[0].GetTimestampMs())
+	files, err = e.spool.list()
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	// Rejected batches are dropped rather than spooled
+	writer.err = permanentError{errors.New("rejected")}
+	writer.calls = 0
+	assert.NoError(t, e.Submit(makeTestMetrics("m4")))
+	e.flush()
+	assert.Equal(t, 1, writer.calls)
+	files, err = e.spool.list()
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestSpool_MaxBytes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metricsd_spool")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s, err := newSpool(dir, 1)
+	require.NoError(t, err)
+	err = s.push(makeTestMetrics("m1"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds spool size of 1 bytes")
+
+	// Room for two batches
+	s, err = newSpool(dir, 1<<20)
+	require.NoError(t, err)
+	require.NoError(t, s.push(makeTestMetrics("m1")))
+	files, err := s.list()
+	require.NoError(t, err)
+	info, err := os.Stat(filepath.Join(dir, files[0]))
+	require.NoError(t, err)
+	s.maxBytes = 2*info.Size() + info.Size()/2
+	for _, name := range []string{"m2", "m3"} {
+		require.NoError(t, s.push(makeTestMetrics(name)))
+	}
+	files, err = s.list()
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	batch, err := s.read(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m2"}, getMetricNames(batch))
+}
+
+func makeTestMetrics(names ...string) []MetricAndContext {
+	var ret []MetricAndContext
+	for _, name := range names {
+		family := tests.MakeTestMetricFamily(dto.MetricType_GAUGE, 1, gatewayLabels())
+		ret = append(ret, MetricAndContext{
+			Family: family,
+			Context: MetricContext{
+				MetricName:        name,
+				AdditionalContext: &GatewayMetricContext{NetworkID: testNetwork, GatewayID: testGateway},
+			},
+		})
+	}
+	return ret
+}
+
+func getMetricNames(metrics []MetricAndContext) []string {
+	var ret []string
+	for _, m := range metrics {
+		ret = append(ret, m.Context.MetricName)
+	}
+	return ret
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporters
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/pkg/relabel"
+)
+
+// Exporter types
+const (
+	RemoteWriteExporterType = "remote_write"
+	OTLPExporterType        = "otlp"
+)
+
+const (
+	defaultTimeout       = 30 * time.Second
+	defaultMaxBatchSize  = 500
+	defaultFlushInterval = 5 * time.Second
+	defaultMaxRetries    = 3
+	defaultRetryBackoff  = time.Second
+	defaultMaxSpoolBytes = 256 * 1024 * 1024
+)
+
+// ExporterConfig configures an exporter which writes metrics directly to a
+// datasink.
+type ExporterConfig struct {
+	// Name identifies the exporter in logs and its spool.
+	Name string `yaml:"name"`
+	// Type is one of RemoteWriteExporterType or OTLPExporterType.
+	Type string `yaml:"type"`
+	// Endpoint is the remote-write URL, or the OTLP/gRPC host:port.
+	Endpoint string `yaml:"endpoint"`
+	// Headers are added to every request, e.g. for authorization. For OTLP,
+	// they're sent as gRPC metadata.
+	Headers map[string]string `yaml:"headers"`
+	// Timeout of each request. Defaults to 30s.
+	Timeout time.Duration `yaml:"timeout"`
+	// Insecure disables TLS to the OTLP endpoint.
+	Insecure bool `yaml:"insecure"`
+	// RelabelConfigs are applied to each metric's labels before export, in
+	// the same format as Prometheus' write_relabel_configs. The metric name
+	// is the __name__ label.
+	RelabelConfigs []*relabel.Config `yaml:"relabelConfigs"`
+	// Batch configures batching and retries.
+	Batch BatchConfig `yaml:"batch"`
+}
+
+// BatchConfig configures how an exporter batches metrics, and retries
+// failed writes.
+type BatchConfig struct {
+	// MaxBatchSize is the max number of metric families written per request.
+	// Defaults to 500.
+	MaxBatchSize int `yaml:"maxBatchSize"`
+	// FlushInterval is the max time metrics are buffered before being
+	// written. Defaults to 5s.
+	FlushInterval time.Duration `yaml:"flushInterval"`
+	// MaxRetries is the number of times a failed write is retried, with
+	// exponential backoff, before its batch is spooled. Defaults to 3;
+	// negative disables retries.
+	MaxRetries int `yaml:"maxRetries"`
+	// RetryBackoff is the wait before the first retry. Defaults to 1s.
+	RetryBackoff time.Duration `yaml:"retryBackoff"`
+	// SpoolDir is the directory to which batches are spooled when the
+	// datasink is unavailable, to be written once it's back. Batches are
+	// dropped if empty.
+	SpoolDir string `yaml:"spoolDir"`
+	// MaxSpoolBytes bounds the spool's size on disk. The oldest batches are
+	// dropped when it's full. Defaults to 256MiB.
+	MaxSpoolBytes int64 `yaml:"maxSpoolBytes"`
+}
+
+// NewExporter returns the exporter configured by cfg.
+func NewExporter(cfg ExporterConfig) (Exporter, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("exporter name must be non-empty")
+	}
+	switch cfg.Type {
+	case RemoteWriteExporterType:
+		return NewRemoteWriteExporter(cfg)
+	case OTLPExporterType:
+		return NewOTLPExporter(cfg)
+	default:
+		return nil, errors.Errorf("unknown type %q of exporter %s", cfg.Type, cfg.Name)
+	}
+}
+
+func (cfg ExporterConfig) getTimeout() time.Duration {
+	if cfg.Timeout <= 0 {
+		return defaultTimeout
+	}
+	return cfg.Timeout
+}
+
+func (cfg BatchConfig) withDefaults() BatchConfig {
+	if cfg.MaxBatchSize <= 0 {
+		cfg.MaxBatchSize = defaultMaxBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+	if cfg.MaxSpoolBytes <= 0 {
+		cfg.MaxSpoolBytes = defaultMaxSpoolBytes
+	}
+	return cfg
+}
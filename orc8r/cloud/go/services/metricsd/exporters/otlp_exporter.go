@@ -0,0 +1,273 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporters
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"magma/orc8r/cloud/go/services/metricsd/protos/otlp"
+	"magma/orc8r/lib/go/metrics"
+
+	"github.com/pkg/errors"
+	prometheus_models "github.com/prometheus/client_model/go"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Resource attributes to which metric contexts are mapped
+const (
+	ServiceNameAttribute = "service.name"
+	HostNameAttribute    = "host.name"
+	NetworkIDAttribute   = "magma.network.id"
+	GatewayIDAttribute   = "magma.gateway.id"
+
+	otlpServiceName = "orc8r"
+	otlpScopeName   = "magma/orc8r/metricsd"
+)
+
+// contextLabels are the metric labels duplicating a metric's context. They're
+// exported as resource attributes rather than data point attributes.
+var contextLabels = map[string]bool{
+	metrics.NetworkLabelName:   true,
+	metrics.GatewayLabelName:   true,
+	metrics.CloudHostLabelName: true,
+}
+
+// retryableOTLPCodes are the gRPC codes for which the OTLP spec permits
+// retrying an export.
+var retryableOTLPCodes = map[codes.Code]bool{
+	codes.Canceled:          true,
+	codes.DeadlineExceeded:  true,
+	codes.ResourceExhausted: true,
+	codes.Aborted:           true,
+	codes.OutOfRange:        true,
+	codes.Unavailable:       true,
+	codes.DataLoss:          true,
+}
+
+// otlpWriter writes metrics to an OTLP/gRPC endpoint.
+type otlpWriter struct {
+	cfg    ExporterConfig
+	client otlp.MetricsServiceClient
+}
+
+// NewOTLPExporter returns an exporter which writes metrics to the
+// OpenTelemetry OTLP/gRPC endpoint at cfg.Endpoint.
+func NewOTLPExporter(cfg ExporterConfig) (Exporter, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.Errorf("OTLP exporter %s requires an endpoint", cfg.Name)
+	}
+	creds := grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{}))
+	if cfg.Insecure {
+		creds = grpc.WithInsecure()
+	}
+	conn, err := grpc.Dial(cfg.Endpoint, creds)
+	if err != nil {
+		return nil, errors.Wrapf(err, "dial OTLP endpoint %s", cfg.Endpoint)
+	}
+	w := &otlpWriter{cfg: cfg, client: otlp.NewMetricsServiceClient(conn)}
+	return newBufferedExporter(cfg.Name, w, cfg.Batch)
+}
+
+func (w *otlpWriter) write(metrics []MetricAndContext) error {
+	req := makeExportRequest(getSeries(metrics, w.cfg.RelabelConfigs))
+	if len(req.ResourceMetrics) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.getTimeout())
+	defer cancel()
+	for k, v := range w.cfg.Headers {
+		ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(k), v)
+	}
+	res, err := w.client.Export(ctx, req)
+	if err != nil {
+		if retryableOTLPCodes[status.Code(err)] {
+			return errors.Wrap(err, "export OTLP metrics")
+		}
+		return permanentError{errors.Wrap(err, "export OTLP metrics")}
+	}
+	if partial := res.GetPartialSuccess(); partial.GetRejectedDataPoints() > 0 {
+		// Rejected data points must not be retried
+		return permanentError{fmt.Errorf("OTLP endpoint rejected %d data points: %s", partial.RejectedDataPoints, partial.ErrorMessage)}
+	}
+	return nil
+}
+
+// makeExportRequest converts series to OTLP metrics, grouped by the
+// resource their context maps to, then by name.
+func makeExportRequest(series []series) *otlp.ExportMetricsServiceRequest {
+	req := &otlp.ExportMetricsServiceRequest{}
+	resources := map[string]*otlp.ResourceMetrics{}
+	metricsByResource := map[string]map[string]*otlp.Metric{}
+	for _, s := range series {
+		resource := getResource(s)
+		key := getResourceKey(resource)
+		rm, ok := resources[key]
+		if !ok {
+			rm = &otlp.ResourceMetrics{
+				Resource:     resource,
+				ScopeMetrics: []*otlp.ScopeMetrics{{Scope: &otlp.InstrumentationScope{Name: otlpScopeName}}},
+			}
+			resources[key] = rm
+			metricsByResource[key] = map[string]*otlp.Metric{}
+			req.ResourceMetrics = append(req.ResourceMetrics, rm)
+		}
+
+		name := s.name()
+		metric, ok := metricsByResource[key][name]
+		if !ok {
+			metric = newOTLPMetric(name, s.metricType)
+			if metric == nil {
+				continue
+			}
+			metricsByResource[key][name] = metric
+			rm.ScopeMetrics[0].Metrics = append(rm.ScopeMetrics[0].Metrics, metric)
+		}
+		addDataPoint(metric, s)
+	}
+	return req
+}
+
+// getResource maps the series' context to resource attributes.
+func getResource(s series) *otlp.Resource {
+	attrs := map[string]string{ServiceNameAttribute: otlpServiceName}
+	switch ctx := s.context.AdditionalContext.(type) {
+	case *CloudMetricContext:
+		attrs[HostNameAttribute] = ctx.CloudHost
+	case *GatewayMetricContext:
+		attrs[NetworkIDAttribute] = ctx.NetworkID
+		attrs[GatewayIDAttribute] = ctx.GatewayID
+	case *PushedMetricContext:
+		attrs[NetworkIDAttribute] = ctx.NetworkID
+		if gatewayID := s.labels.Get(metrics.GatewayLabelName); gatewayID != "" {
+			attrs[GatewayIDAttribute] = gatewayID
+		}
+	}
+	return &otlp.Resource{Attributes: makeAttributes(attrs)}
+}
+
+func getResourceKey(resource *otlp.Resource) string {
+	var parts []string
+	for _, attr := range resource.Attributes {
+		parts = append(parts, attr.Key+"="+attr.Value.GetStringValue())
+	}
+	return strings.Join(parts, ",")
+}
+
+func newOTLPMetric(name string, metricType prometheus_models.MetricType) *otlp.Metric {
+	metric := &otlp.Metric{Name: name}
+	switch metricType {
+	case prometheus_models.MetricType_COUNTER:
+		metric.Data = &otlp.Metric_Sum{Sum: &otlp.Sum{
+			AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+			IsMonotonic:            true,
+		}}
+	case prometheus_models.MetricType_GAUGE, prometheus_models.MetricType_UNTYPED:
+		metric.Data = &otlp.Metric_Gauge{Gauge: &otlp.Gauge{}}
+	case prometheus_models.MetricType_SUMMARY:
+		metric.Data = &otlp.Metric_Summary{Summary: &otlp.Summary{}}
+	case prometheus_models.MetricType_HISTOGRAM:
+		metric.Data = &otlp.Metric_Histogram{Histogram: &otlp.Histogram{
+			AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+		}}
+	default:
+		return nil
+	}
+	return metric
+}
+
+// addDataPoint adds the series to the metric as a data point. The metric
+// must have been created for the series' type.
+func addDataPoint(metric *otlp.Metric, s series) {
+	attrs := map[string]string{}
+	for _, l := range s.labels {
+		if l.Name != labels.MetricName && !contextLabels[l.Name] {
+			attrs[l.Name] = l.Value
+		}
+	}
+	attributes := makeAttributes(attrs)
+	timeUnixNano := uint64(s.timestampMs) * 1e6
+
+	switch data := metric.Data.(type) {
+	case *otlp.Metric_Sum:
+		data.Sum.DataPoints = append(data.Sum.DataPoints, &otlp.NumberDataPoint{
+			Attributes:   attributes,
+			TimeUnixNano: timeUnixNano,
+			Value:        &otlp.NumberDataPoint_AsDouble{AsDouble: s.metric.GetCounter().GetValue()},
+		})
+	case *otlp.Metric_Gauge:
+		value := s.metric.GetGauge().GetValue()
+		if s.metricType == prometheus_models.MetricType_UNTYPED {
+			value = s.metric.GetUntyped().GetValue()
+		}
+		data.Gauge.DataPoints = append(data.Gauge.DataPoints, &otlp.NumberDataPoint{
+			Attributes:   attributes,
+			TimeUnixNano: timeUnixNano,
+			Value:        &otlp.NumberDataPoint_AsDouble{AsDouble: value},
+		})
+	case *otlp.Metric_Summary:
+		summary := s.metric.GetSummary()
+		dp := &otlp.SummaryDataPoint{
+			Attributes:   attributes,
+			TimeUnixNano: timeUnixNano,
+			Count:        summary.GetSampleCount(),
+			Sum:          summary.GetSampleSum(),
+		}
+		for _, q := range summary.GetQuantile() {
+			dp.QuantileValues = append(dp.QuantileValues, &otlp.SummaryDataPoint_ValueAtQuantile{Quantile: q.GetQuantile(), Value: q.GetValue()})
+		}
+		data.Summary.DataPoints = append(data.Summary.DataPoints, dp)
+	case *otlp.Metric_Histogram:
+		histogram := s.metric.GetHistogram()
+		dp := &otlp.HistogramDataPoint{
+			Attributes:   attributes,
+			TimeUnixNano: timeUnixNano,
+			Count:        histogram.GetSampleCount(),
+			Sum:          histogram.GetSampleSum(),
+		}
+		// OTLP buckets hold non-cumulative counts, with an implicit +Inf
+		// upper bound on the last bucket
+		var prev uint64
+		for _, b := range histogram.GetBucket() {
+			if math.IsInf(b.GetUpperBound(), 1) {
+				break
+			}
+			dp.ExplicitBounds = append(dp.ExplicitBounds, b.GetUpperBound())
+			dp.BucketCounts = append(dp.BucketCounts, b.GetCumulativeCount()-prev)
+			prev = b.GetCumulativeCount()
+		}
+		dp.BucketCounts = append(dp.BucketCounts, histogram.GetSampleCount()-prev)
+		data.Histogram.DataPoints = append(data.Histogram.DataPoints, dp)
+	}
+}
+
+// makeAttributes converts the attributes to key-values, sorted by key.
+func makeAttributes(attrs map[string]string) []*otlp.KeyValue {
+	ret := make([]*otlp.KeyValue, 0, len(attrs))
+	for k, v := range attrs {
+		ret = append(ret, &otlp.KeyValue{Key: k, Value: &otlp.AnyValue{Value: &otlp.AnyValue_StringValue{StringValue: v}}})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Key < ret[j].Key })
+	return ret
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporters
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/metricsd/protos/otlp"
+	tests "magma/orc8r/cloud/go/services/metricsd/test_common"
+	"magma/orc8r/lib/go/metrics"
+
+	"github.com/golang/protobuf/proto"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type testMetricsService struct {
+	requests []*otlp.ExportMetricsServiceRequest
+	err      error
+}
+
+func (s *testMetricsService) Export(ctx context.Context, req *otlp.ExportMetricsServiceRequest) (*otlp.ExportMetricsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get("x-scope-orgid")) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing org ID")
+	}
+	s.requests = append(s.requests, req)
+	return &otlp.ExportMetricsServiceResponse{}, s.err
+}
+
+func TestOTLPExporter(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	service := &testMetricsService{}
+	otlp.RegisterMetricsServiceServer(srv, service)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	e, err := NewExporter(ExporterConfig{
+		Name:     "test",
+		Type:     OTLPExporterType,
+		Endpoint: lis.Addr().String(),
+		Insecure: true,
+		Headers:  map[string]string{"X-Scope-OrgID": "magma"},
+		Batch:    BatchConfig{FlushInterval: time.Hour, MaxRetries: -1},
+	})
+	require.NoError(t, err)
+	exporter := e.(*bufferedExporter)
+
+	histogram := tests.MakePromoHistogram([]float64{1, 5}, []float64{0.5, 3, 7})
+	histogram.Label = gatewayLabels()
+	counter := tests.MakePromoCounter(3)
+	counter.Label = []*dto.LabelPair{
+		{Name: tests.MakeStrPtr(metrics.NetworkLabelName), Value: tests.MakeStrPtr(testNetwork)},
+		{Name: tests.MakeStrPtr(metrics.GatewayLabelName), Value: tests.MakeStrPtr("gw2")},
+		{Name: tests.MakeStrPtr("service"), Value: tests.MakeStrPtr("mme")},
+	}
+	err = exporter.Submit([]MetricAndContext{
+		makeGatewayMetric("latency", dto.MetricType_HISTOGRAM, &histogram),
+		{
+			Family: &dto.MetricFamily{
+				Name:   tests.MakeStrPtr("restarts"),
+				Type:   tests.MakeMetricTypePointer(dto.MetricType_COUNTER),
+				Metric: []*dto.Metric{&counter},
+			},
+			Context: MetricContext{MetricName: "restarts", AdditionalContext: &PushedMetricContext{NetworkID: testNetwork}},
+		},
+	})
+	assert.NoError(t, err)
+	exporter.flush()
+
+	attr := func(k, v string) *otlp.KeyValue {
+		return &otlp.KeyValue{Key: k, Value: &otlp.AnyValue{Value: &otlp.AnyValue_StringValue{StringValue: v}}}
+	}
+	scope := &otlp.InstrumentationScope{Name: otlpScopeName}
+	timeUnixNano := uint64(1000 * time.Second)
+	expected := &otlp.ExportMetricsServiceRequest{
+		ResourceMetrics: []*otlp.ResourceMetrics{
+			{
+				Resource: &otlp.Resource{Attributes: []*otlp.KeyValue{
+					attr(GatewayIDAttribute, testGateway),
+					attr(NetworkIDAttribute, testNetwork),
+					attr(ServiceNameAttribute, otlpServiceName),
+				}},
+				ScopeMetrics: []*otlp.ScopeMetrics{{
+					Scope: scope,
+					Metrics: []*otlp.Metric{{
+						Name: "latency",
+						Data: &otlp.Metric_Histogram{Histogram: &otlp.Histogram{
+							AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+							DataPoints: []*otlp.HistogramDataPoint{{
+								Attributes:     []*otlp.KeyValue{},
+								TimeUnixNano:   timeUnixNano,
+								Count:          3,
+								Sum:            10.5,
+								BucketCounts:   []uint64{1, 1, 1},
+								ExplicitBounds: []float64{1, 5},
+							}},
+						}},
+					}},
+				}},
+			},
+			{
+				Resource: &otlp.Resource{Attributes: []*otlp.KeyValue{
+					attr(GatewayIDAttribute, "gw2"),
+					attr(NetworkIDAttribute, testNetwork),
+					attr(ServiceNameAttribute, otlpServiceName),
+				}},
+				ScopeMetrics: []*otlp.ScopeMetrics{{
+					Scope: scope,
+					Metrics: []*otlp.Metric{{
+						Name: "restarts",
+						Data: &otlp.Metric_Sum{Sum: &otlp.Sum{
+							AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+							IsMonotonic:            true,
+							DataPoints: []*otlp.NumberDataPoint{{
+								Attributes:   []*otlp.KeyValue{attr("service", "mme")},
+								TimeUnixNano: timeUnixNano,
+								Value:        &otlp.NumberDataPoint_AsDouble{AsDouble: 3},
+							}},
+						}},
+					}},
+				}},
+			},
+		},
+	}
+	require.Len(t, service.requests, 1)
+	assert.True(t, proto.Equal(expected, service.requests[0]), "expected %v, got %v", expected, service.requests[0])
+
+	// Only transient errors are retried
+	service.err = status.Error(codes.Unavailable, "unavailable")
+	err = exporter.writer.write([]MetricAndContext{makeGatewayMetric("latency", dto.MetricType_HISTOGRAM, &histogram)})
+	assert.Error(t, err)
+	assert.False(t, isPermanent(err))
+
+	service.err = status.Error(codes.InvalidArgument, "bad metric")
+	err = exporter.writer.write([]MetricAndContext{makeGatewayMetric("latency", dto.MetricType_HISTOGRAM, &histogram)})
+	assert.True(t, isPermanent(err))
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporters
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+
+	"magma/orc8r/cloud/go/services/metricsd/protos"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	prometheus_models "github.com/prometheus/client_model/go"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/relabel"
+)
+
+const (
+	remoteWriteVersion = "0.1.0"
+
+	bucketLabel   = "le"
+	quantileLabel = "quantile"
+)
+
+// remoteWriteWriter writes metrics to a Prometheus remote-write endpoint.
+type remoteWriteWriter struct {
+	url            string
+	headers        map[string]string
+	client         *http.Client
+	relabelConfigs []*relabel.Config
+}
+
+// NewRemoteWriteExporter returns an exporter which writes metrics to the
+// Prometheus remote-write endpoint at cfg.Endpoint.
+func NewRemoteWriteExporter(cfg ExporterConfig) (Exporter, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.Errorf("remote-write exporter %s requires an endpoint", cfg.Name)
+	}
+	w := &remoteWriteWriter{
+		url:            cfg.Endpoint,
+		headers:        cfg.Headers,
+		client:         &http.Client{Timeout: cfg.getTimeout()},
+		relabelConfigs: cfg.RelabelConfigs,
+	}
+	return newBufferedExporter(cfg.Name, w, cfg.Batch)
+}
+
+func (w *remoteWriteWriter) write(metrics []MetricAndContext) error {
+	req := makeWriteRequest(getSeries(metrics, w.relabelConfigs))
+	if len(req.Timeseries) == 0 {
+		return nil
+	}
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return permanentError{errors.Wrap(err, "marshal remote-write request")}
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(snappy.Encode(nil, body)))
+	if err != nil {
+		return permanentError{errors.Wrap(err, "create remote-write request")}
+	}
+	for k, v := range w.headers {
+		httpReq.Header.Set(k, v)
+	}
+	httpReq.Header.Set("Content-Encoding", "snappy")
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	httpReq.Header.Set("X-Prometheus-Remote-Write-Version", remoteWriteVersion)
+
+	resp, err := w.client.Do(httpReq)
+	if err != nil {
+		return errors.Wrap(err, "send remote-write request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil
+	}
+
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("remote-write endpoint returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	// Only server errors and throttling are worth retrying
+	if resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests {
+		return err
+	}
+	return permanentError{err}
+}
+
+// makeWriteRequest converts series to remote-write timeseries, following
+// Prometheus' exposition conventions for summaries and histograms.
+func makeWriteRequest(series []series) *protos.WriteRequest {
+	req := &protos.WriteRequest{}
+	for _, s := range series {
+		name := s.name()
+		add := func(name string, value float64, extra ...string) {
+			builder := labels.NewBuilder(s.labels).Set(labels.MetricName, name)
+			for i := 0; i+1 < len(extra); i += 2 {
+				builder.Set(extra[i], extra[i+1])
+			}
+			req.Timeseries = append(req.Timeseries, &protos.TimeSeries{
+				Labels:  makeLabels(builder.Labels()),
+				Samples: []*protos.Sample{{Value: value, Timestamp: s.timestampMs}},
+			})
+		}
+
+		switch s.metricType {
+		case prometheus_models.MetricType_COUNTER:
+			add(name, s.metric.GetCounter().GetValue())
+		case prometheus_models.MetricType_GAUGE:
+			add(name, s.metric.GetGauge().GetValue())
+		case prometheus_models.MetricType_UNTYPED:
+			add(name, s.metric.GetUntyped().GetValue())
+		case prometheus_models.MetricType_SUMMARY:
+			summary := s.metric.GetSummary()
+			for _, q := range summary.GetQuantile() {
+				add(name, q.GetValue(), quantileLabel, formatFloat(q.GetQuantile()))
+			}
+			add(name+"_sum", summary.GetSampleSum())
+			add(name+"_count", float64(summary.GetSampleCount()))
+		case prometheus_models.MetricType_HISTOGRAM:
+			histogram := s.metric.GetHistogram()
+			hasInf := false
+			for _, b := range histogram.GetBucket() {
+				hasInf = hasInf || math.IsInf(b.GetUpperBound(), 1)
+				add(name+"_bucket", float64(b.GetCumulativeCount()), bucketLabel, formatFloat(b.GetUpperBound()))
+			}
+			if !hasInf {
+				add(name+"_bucket", float64(histogram.GetSampleCount()), bucketLabel, formatFloat(math.Inf(1)))
+			}
+			add(name+"_sum", histogram.GetSampleSum())
+			add(name+"_count", float64(histogram.GetSampleCount()))
+		}
+	}
+	return req
+}
+
+func makeLabels(lbls labels.Labels) []*protos.Label {
+	ret := make([]*protos.Label, 0, len(lbls))
+	for _, l := range lbls {
+		ret = append(ret, &protos.Label{Name: l.Name, Value: l.Value})
+	}
+	return ret
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
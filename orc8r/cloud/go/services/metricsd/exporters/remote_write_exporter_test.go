@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporters
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/metricsd/protos"
+	tests "magma/orc8r/cloud/go/services/metricsd/test_common"
+	"magma/orc8r/lib/go/metrics"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/relabel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteWriteExporter(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	var received []*protos.WriteRequest
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		decoded, err := snappy.Decode(nil, body)
+		require.NoError(t, err)
+		req := &protos.WriteRequest{}
+		require.NoError(t, proto.Unmarshal(decoded, req))
+		received = append(received, req)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	e, err := NewExporter(ExporterConfig{
+		Name:     "test",
+		Type:     RemoteWriteExporterType,
+		Endpoint: srv.URL,
+		Headers:  map[string]string{"Authorization": "secret"},
+		RelabelConfigs: []*relabel.Config{
+			{
+				SourceLabels: model.LabelNames{model.MetricNameLabel},
+				Regex:        relabel.MustNewRegexp("dropped_.*"),
+				Action:       relabel.Drop,
+			},
+		},
+		Batch: BatchConfig{FlushInterval: time.Hour, MaxRetries: -1},
+	})
+	require.NoError(t, err)
+	exporter := e.(*bufferedExporter)
+
+	histogram := tests.MakePromoHistogram([]float64{1, 5}, []float64{0.5, 3, 7})
+	histogram.Label = gatewayLabels()
+	gauge := tests.MakePromoGauge(42)
+	gauge.Label = gatewayLabels()
+	err = exporter.Submit([]MetricAndContext{
+		makeGatewayMetric("latency", dto.MetricType_HISTOGRAM, &histogram),
+		makeGatewayMetric("temperature", dto.MetricType_GAUGE, &gauge),
+		makeGatewayMetric("dropped_metric", dto.MetricType_GAUGE, &gauge),
+	})
+	assert.NoError(t, err)
+	exporter.flush()
+
+	sample := func(value float64) []*protos.Sample {
+		return []*protos.Sample{{Value: value, Timestamp: 1000 * 1000}}
+	}
+	series := func(name string, value float64, extra ...*protos.Label) *protos.TimeSeries {
+		lbls := []*protos.Label{{Name: "__name__", Value: name}, {Name: metrics.GatewayLabelName, Value: testGateway}}
+		lbls = append(lbls, extra...)
+		lbls = append(lbls, &protos.Label{Name: metrics.NetworkLabelName, Value: testNetwork})
+		return &protos.TimeSeries{Labels: lbls, Samples: sample(value)}
+	}
+	expected := &protos.WriteRequest{
+		Timeseries: []*protos.TimeSeries{
+			series("latency_bucket", 1, &protos.Label{Name: "le", Value: "1"}),
+			series("latency_bucket", 2, &protos.Label{Name: "le", Value: "5"}),
+			series("latency_bucket", 3, &protos.Label{Name: "le", Value: "+Inf"}),
+			series("latency_sum", 10.5),
+			series("latency_count", 3),
+			series("temperature", 42),
+		},
+	}
+	require.Len(t, received, 1)
+	assert.Equal(t, expected, received[0])
+
+	// Rejected requests aren't retried
+	status = http.StatusBadRequest
+	err = exporter.Submit([]MetricAndContext{makeGatewayMetric("temperature", dto.MetricType_GAUGE, &gauge)})
+	assert.NoError(t, err)
+	exporter.flush()
+	assert.Len(t, received, 2)
+	err = exporter.writer.write([]MetricAndContext{makeGatewayMetric("temperature", dto.MetricType_GAUGE, &gauge)})
+	assert.True(t, isPermanent(err))
+
+	status = http.StatusServiceUnavailable
+	err = exporter.writer.write([]MetricAndContext{makeGatewayMetric("temperature", dto.MetricType_GAUGE, &gauge)})
+	assert.Error(t, err)
+	assert.False(t, isPermanent(err))
+}
+
+func gatewayLabels() []*dto.LabelPair {
+	return []*dto.LabelPair{
+		{Name: tests.MakeStrPtr(metrics.NetworkLabelName), Value: tests.MakeStrPtr(testNetwork)},
+		{Name: tests.MakeStrPtr(metrics.GatewayLabelName), Value: tests.MakeStrPtr(testGateway)},
+	}
+}
+
+func makeGatewayMetric(name string, metricType dto.MetricType, metric *dto.Metric) MetricAndContext {
+	return MetricAndContext{
+		Family: &dto.MetricFamily{
+			Name:   tests.MakeStrPtr(name),
+			Type:   tests.MakeMetricTypePointer(metricType),
+			Metric: []*dto.Metric{metric},
+		},
+		Context: MetricContext{
+			MetricName:        name,
+			AdditionalContext: &GatewayMetricContext{NetworkID: testNetwork, GatewayID: testGateway},
+		},
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporters
+
+import (
+	"magma/orc8r/cloud/go/clock"
+
+	"github.com/golang/protobuf/proto"
+	prometheus_models "github.com/prometheus/client_model/go"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/relabel"
+)
+
+// series is a single metric, with its name folded into its labels as
+// __name__, after relabeling.
+type series struct {
+	labels      labels.Labels
+	metricType  prometheus_models.MetricType
+	metric      *prometheus_models.Metric
+	context     MetricContext
+	timestampMs int64
+}
+
+// name returns the (possibly relabeled) name of the series.
+func (s series) name() string {
+	return s.labels.Get(labels.MetricName)
+}
+
+// stampMetrics returns the metrics with samples lacking a timestamp stamped
+// with the current time. Metrics are submitted to multiple exporters, so
+// stamped families and samples are copied rather than modified in place.
+func stampMetrics(metrics []MetricAndContext) []MetricAndContext {
+	nowMs := clock.Now().UnixNano() / 1e6
+	ret := make([]MetricAndContext, 0, len(metrics))
+	for _, m := range metrics {
+		if m.Family == nil || !hasUnstampedMetric(m.Family) {
+			ret = append(ret, m)
+			continue
+		}
+		family := *m.Family
+		family.Metric = make([]*prometheus_models.Metric, 0, len(m.Family.Metric))
+		for _, metric := range m.Family.Metric {
+			if metric.GetTimestampMs() == 0 {
+				stamped := *metric
+				stamped.TimestampMs = proto.Int64(nowMs)
+				metric = &stamped
+			}
+			family.Metric = append(family.Metric, metric)
+		}
+		ret = append(ret, MetricAndContext{Family: &family, Context: m.Context})
+	}
+	return ret
+}
+
+func hasUnstampedMetric(family *prometheus_models.MetricFamily) bool {
+	for _, metric := range family.Metric {
+		if metric.GetTimestampMs() == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// getSeries flattens the metrics into series, applying the relabel configs
+// to each. Series dropped by the relabel configs aren't returned.
+// Samples are expected to be timestamped, see stampMetrics.
+func getSeries(metrics []MetricAndContext, relabelConfigs []*relabel.Config) []series {
+	var ret []series
+	for _, m := range metrics {
+		if m.Family == nil {
+			continue
+		}
+		name := m.Context.MetricName
+		if name == "" {
+			name = m.Family.GetName()
+		}
+		for _, metric := range m.Family.Metric {
+			builder := labels.NewBuilder(nil)
+			for _, l := range metric.Label {
+				builder.Set(l.GetName(), l.GetValue())
+			}
+			builder.Set(labels.MetricName, name)
+			lbls := builder.Labels()
+			if len(relabelConfigs) != 0 {
+				lbls = relabel.Process(lbls, relabelConfigs...)
+				if lbls == nil || lbls.Get(labels.MetricName) == "" {
+					continue
+				}
+			}
+
+			ret = append(ret, series{
+				labels:      lbls,
+				metricType:  m.Family.GetType(),
+				metric:      metric,
+				context:     m.Context,
+				timestampMs: metric.GetTimestampMs(),
+			})
+		}
+	}
+	return ret
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporters
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/metricsd/protos"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+const spoolFileSuffix = ".batch"
+
+// spool is a bounded on-disk FIFO of metric batches.
+// Each batch is a file holding a serialized SubmitMetricsRequest, named so
+// files sort in the order they were pushed.
+type spool struct {
+	dir      string
+	maxBytes int64
+
+	mu  sync.Mutex
+	seq uint64
+}
+
+func newSpool(dir string, maxBytes int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "create spool directory %s", dir)
+	}
+	return &spool{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// push appends the batch to the spool, dropping the oldest batches if the
+// spool would exceed its max size.
+func (s *spool) push(batch []MetricAndContext) error {
+	data, err := proto.Marshal(&protos.SubmitMetricsRequest{Metrics: MakeProtoMetrics(batch)})
+	if err != nil {
+		return errors.Wrap(err, "marshal spooled batch")
+	}
+	if int64(len(data)) > s.maxBytes {
+		return fmt.Errorf("batch of %d bytes exceeds spool size of %d bytes", len(data), s.maxBytes)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	name := fmt.Sprintf("%020d-%010d%s", clock.Now().UnixNano(), s.seq, spoolFileSuffix)
+	tmp := filepath.Join(s.dir, "."+name)
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return errors.Wrap(err, "write spooled batch")
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, name)); err != nil {
+		return errors.Wrap(err, "write spooled batch")
+	}
+	return s.truncate()
+}
+
+// list returns the spooled batch files, oldest first.
+func (s *spool) list() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listLocked()
+}
+
+// read returns the batch spooled in the file.
+func (s *spool) read(file string) ([]MetricAndContext, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, file))
+	if err != nil {
+		return nil, errors.Wrap(err, "read spooled batch")
+	}
+	req := &protos.SubmitMetricsRequest{}
+	if err := proto.Unmarshal(data, req); err != nil {
+		return nil, errors.Wrap(err, "unmarshal spooled batch")
+	}
+	return MakeNativeMetrics(req.Metrics), nil
+}
+
+// remove removes the file from the spool. Files already removed, e.g. by
+// truncation, are ignored.
+func (s *spool) remove(file string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := os.Remove(filepath.Join(s.dir, file))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "remove spooled batch")
+	}
+	return nil
+}
+
+// truncate drops the oldest batches until the spool is within its max size.
+// Must be called with the lock held.
+func (s *spool) truncate() error {
+	files, err := s.listLocked()
+	if err != nil {
+		return err
+	}
+	var total int64
+	sizes := make([]int64, len(files))
+	for i, f := range files {
+		info, err := os.Stat(filepath.Join(s.dir, f))
+		if err != nil {
+			continue
+		}
+		sizes[i] = info.Size()
+		total += sizes[i]
+	}
+	for i := 0; total > s.maxBytes && i < len(files); i++ {
+		if err := os.Remove(filepath.Join(s.dir, files[i])); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "drop spooled batch")
+		}
+		glog.Warningf("Spool %s full, dropped oldest batch %s", s.dir, files[i])
+		total -= sizes[i]
+	}
+	return nil
+}
+
+func (s *spool) listLocked() ([]string, error) {
+	infos, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "list spool")
+	}
+	var files []string
+	for _, info := range infos {
+		name := info.Name()
+		if !info.IsDir() && !strings.HasPrefix(name, ".") && strings.HasSuffix(name, spoolFileSuffix) {
+			files = append(files, name)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -22,9 +22,12 @@ import (
 	"magma/orc8r/cloud/go/orc8r"
 	"magma/orc8r/cloud/go/service"
 	"magma/orc8r/cloud/go/services/metricsd"
+	"magma/orc8r/cloud/go/services/metricsd/cardinality"
 	"magma/orc8r/cloud/go/services/metricsd/collection"
 	"magma/orc8r/cloud/go/services/metricsd/obsidian/handlers"
 	"magma/orc8r/cloud/go/services/metricsd/servicers"
+	"magma/orc8r/cloud/go/sqorc"
+	"magma/orc8r/cloud/go/storage"
 	"magma/orc8r/lib/go/protos"
 
 	"github.com/golang/glog"
@@ -47,7 +50,30 @@ func main() {
 		glog.Fatalf("Error creating orc8r service for metricsd: %s", err)
 	}
 
+	serviceConfig, err := metricsd.GetServiceConfig()
+	if err != nil {
+		glog.Fatalf("Error reading metricsd service config: %s", err)
+	}
+
+	db, err := sqorc.Open(storage.GetSQLDriver(), storage.GetDatabaseSource())
+	if err != nil {
+		glog.Fatalf("Error opening db connection: %s", err)
+	}
+	cardinalityStore := cardinality.NewSQLStore(db, sqorc.GetSqlBuilder())
+	if err := cardinalityStore.Initialize(); err != nil {
+		glog.Fatalf("Error initializing cardinality storage: %s", err)
+	}
+	guard := cardinality.NewGuard(serviceConfig.Cardinality, cardinalityStore)
+
 	controllerServicer := servicers.NewMetricsControllerServer()
+	controllerServicer.SetCardinalityGuard(guard)
+	configuredExporters, err := metricsd.GetConfiguredExporters(serviceConfig)
+	if err != nil {
+		glog.Fatalf("Error creating metrics exporters: %s", err)
+	}
+	for _, e := range configuredExporters {
+		controllerServicer.RegisterExporter(e)
+	}
 	protos.RegisterMetricsControllerServer(srv.GrpcServer, controllerServicer)
 
 	swagger_protos.RegisterSwaggerSpecServer(srv.GrpcServer, swagger.NewSpecServicerFromFile(metricsd.ServiceName))
@@ -65,7 +91,7 @@ func main() {
 	go controllerServicer.ConsumeCloudMetrics(metricsCh, service.MustGetHostname())
 	gatherer.Run()
 
-	obsidian.AttachHandlers(srv.EchoServer, handlers.GetObsidianHandlers(srv.Config))
+	obsidian.AttachHandlers(srv.EchoServer, handlers.GetObsidianHandlers(srv.Config, guard))
 	err = srv.Run()
 	if err != nil {
 		glog.Fatalf("Error running metricsd service: %s", err)
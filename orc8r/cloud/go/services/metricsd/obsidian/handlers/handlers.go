@@ -17,9 +17,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"magma/orc8r/cloud/go/obsidian"
 	"magma/orc8r/cloud/go/services/metricsd"
+	"magma/orc8r/cloud/go/services/metricsd/cardinality"
+	"magma/orc8r/cloud/go/services/metricsd/obsidian/models"
 	promH "magma/orc8r/cloud/go/services/metricsd/prometheus/handlers"
 	"magma/orc8r/lib/go/protos"
 	"magma/orc8r/lib/go/service/config"
@@ -30,11 +33,13 @@ import (
 )
 
 const (
+	defaultCardinalityLimit = 10
+
 	MetricsV1Root = obsidian.V1Root + obsidian.MagmaNetworksUrlPart + "/:network_id" + obsidian.UrlSep + "metrics"
 )
 
 // GetObsidianHandlers returns all obsidian handlers for metricsd
-func GetObsidianHandlers(configMap *config.ConfigMap) []obsidian.Handler {
+func GetObsidianHandlers(configMap *config.ConfigMap, guard *cardinality.Guard) []obsidian.Handler {
 	useSeriesCache, _ := configMap.GetBool(metricsd.UseSeriesCache)
 	var ret []obsidian.Handler
 	client, err := promAPI.NewClient(promAPI.Config{Address: configMap.MustGetString(metricsd.PrometheusQueryAddress)})
@@ -109,6 +114,7 @@ func GetObsidianHandlers(configMap *config.ConfigMap) []obsidian.Handler {
 		obsidian.Handler{Path: promH.AlertSilencerV1URL, Methods: obsidian.DELETE, HandlerFunc: promH.GetDeleteSilencerHandler(alertmanagerURL, httpClient)},
 
 		obsidian.Handler{Path: MetricsV1Root + "/push", Methods: obsidian.POST, HandlerFunc: pushHandler},
+		obsidian.Handler{Path: MetricsV1Root + "/cardinality", Methods: obsidian.GET, HandlerFunc: getCardinalityHandler(guard)},
 	)
 
 	return ret
@@ -142,3 +148,25 @@ func pushHandler(c echo.Context) error {
 	}
 	return c.NoContent(http.StatusOK)
 }
+
+func getCardinalityHandler(guard *cardinality.Guard) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		nID, nerr := obsidian.GetNetworkId(c)
+		if nerr != nil {
+			return nerr
+		}
+		limit := defaultCardinalityLimit
+		if limitParam := c.QueryParam("limit"); limitParam != "" {
+			l, err := strconv.Atoi(limitParam)
+			if err != nil || l <= 0 {
+				return obsidian.HttpError(fmt.Errorf("invalid limit %q, must be a positive integer", limitParam), http.StatusBadRequest)
+			}
+			limit = l
+		}
+		report, err := guard.GetNetworkReport(nID, limit)
+		if err != nil {
+			return obsidian.HttpError(err, http.StatusInternalServerError)
+		}
+		return c.JSON(http.StatusOK, (&models.NetworkMetricsCardinality{}).FromReport(report, guard.GetConfig()))
+	}
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"magma/orc8r/cloud/go/services/metricsd/cardinality"
+)
+
+func (m *NetworkMetricsCardinality) FromReport(report cardinality.NetworkReport, cfg cardinality.Config) *NetworkMetricsCardinality {
+	m.NetworkID = report.NetworkID
+	m.ActiveSeries = int64(report.ActiveSeries)
+	m.MaxSeriesPerNetwork = int64(cfg.MaxSeriesPerNetwork)
+	m.MaxSeriesPerMetric = int64(cfg.MaxSeriesPerMetric)
+	m.Metrics = make([]*MetricCardinality, 0, len(report.Metrics))
+	for _, metric := range report.Metrics {
+		m.Metrics = append(m.Metrics, &MetricCardinality{
+			MetricName:    metric.MetricName,
+			ActiveSeries:  int64(metric.ActiveSeries),
+			DroppedSeries: metric.DroppedSeries,
+		})
+	}
+	return m
+}
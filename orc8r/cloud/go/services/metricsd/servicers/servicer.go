@@ -19,6 +19,7 @@ import (
 	"magma/orc8r/cloud/go/serdes"
 	"magma/orc8r/cloud/go/services/configurator"
 	"magma/orc8r/cloud/go/services/metricsd"
+	"magma/orc8r/cloud/go/services/metricsd/cardinality"
 	"magma/orc8r/cloud/go/services/metricsd/exporters"
 	"magma/orc8r/lib/go/metrics"
 	"magma/orc8r/lib/go/protos"
@@ -34,6 +35,8 @@ import (
 // writing to storage
 type MetricsControllerServer struct {
 	exporters []exporters.Exporter
+	// guard filters gateway and pushed metrics, if set
+	guard *cardinality.Guard
 }
 
 func NewMetricsControllerServer() *MetricsControllerServer {
@@ -45,12 +48,12 @@ func (srv *MetricsControllerServer) Push(ctx context.Context, in *protos.PushedM
 		return new(protos.Void), nil
 	}
 
-	metricsExporters, err := metricsd.GetMetricsExporters()
+	metricsExporters, err := srv.getExporters()
 	if err != nil {
 		return &protos.Void{}, err
 	}
+	metricsToSubmit := srv.filter(pushedMetricsToMetricsAndContext(in))
 	for _, e := range metricsExporters {
-		metricsToSubmit := pushedMetricsToMetricsAndContext(in)
 		err := e.Submit(metricsToSubmit)
 		if err != nil {
 			glog.Error(err)
@@ -79,8 +82,8 @@ func (srv *MetricsControllerServer) Collect(ctx context.Context, in *protos.Metr
 	}
 	glog.V(2).Infof("collecting %v metrics from gateway %v\n", len(in.Family), in.GatewayId)
 
-	metricsToSubmit := metricsContainerToMetricAndContexts(in, networkID, gatewayID)
-	metricsExporters, err := metricsd.GetMetricsExporters()
+	metricsToSubmit := srv.filter(metricsContainerToMetricAndContexts(in, networkID, gatewayID))
+	metricsExporters, err := srv.getExporters()
 	if err != nil {
 		return &protos.Void{}, err
 	}
@@ -99,7 +102,7 @@ func (srv *MetricsControllerServer) Collect(ctx context.Context, in *protos.Metr
 func (srv *MetricsControllerServer) ConsumeCloudMetrics(inputChan chan *prom_proto.MetricFamily, hostName string) {
 	for family := range inputChan {
 		metricsToSubmit := preprocessCloudMetrics(family, hostName)
-		metricsExporters, err := metricsd.GetMetricsExporters()
+		metricsExporters, err := srv.getExporters()
 		if err != nil {
 			glog.Error(err)
 			continue
@@ -133,6 +136,29 @@ func (srv *MetricsControllerServer) RegisterExporter(e exporters.Exporter) []exp
 	return srv.exporters
 }
 
+// SetCardinalityGuard sets the guard which filters gateway and pushed
+// metrics before they're exported.
+func (srv *MetricsControllerServer) SetCardinalityGuard(guard *cardinality.Guard) {
+	srv.guard = guard
+}
+
+func (srv *MetricsControllerServer) filter(metrics []exporters.MetricAndContext) []exporters.MetricAndContext {
+	if srv.guard == nil {
+		return metrics
+	}
+	return srv.guard.Filter(metrics)
+}
+
+// getExporters returns the remote exporter services, followed by the
+// exporters registered with the server.
+func (srv *MetricsControllerServer) getExporters() ([]exporters.Exporter, error) {
+	remoteExporters, err := metricsd.GetMetricsExporters()
+	if err != nil {
+		return nil, err
+	}
+	return append(remoteExporters, srv.exporters...), nil
+}
+
 func metricsContainerToMetricAndContexts(
 	in *protos.MetricsContainer,
 	networkID, gatewayID string,
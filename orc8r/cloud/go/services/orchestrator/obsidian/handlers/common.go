@@ -14,13 +14,19 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"reflect"
+	"strconv"
+	"strings"
 
 	"magma/orc8r/cloud/go/obsidian"
 	"magma/orc8r/cloud/go/serde"
+	"magma/orc8r/cloud/go/services/configurator"
+	"magma/orc8r/cloud/go/storage"
 
 	"github.com/labstack/echo"
+	"github.com/pkg/errors"
 )
 
 // GetAndValidatePayload can be used by any model that implements ValidateModel
@@ -41,3 +47,46 @@ func GetAndValidatePayload(c echo.Context, model interface{}) (serde.Validatable
 	}
 	return iModel, nil
 }
+
+// SetETag sets the ETag response header to the entity version.
+func SetETag(c echo.Context, version uint64) {
+	c.Response().Header().Set("ETag", fmt.Sprintf("%q", strconv.FormatUint(version, 10)))
+}
+
+// GetIfMatchVersion returns the entity version specified by the request's
+// If-Match header, or nil if the header isn't set.
+// The version is the value of an ETag previously returned by SetETag.
+func GetIfMatchVersion(c echo.Context) (*uint64, *echo.HTTPError) {
+	ifMatch := c.Request().Header.Get("If-Match")
+	if ifMatch == "" {
+		return nil, nil
+	}
+	version, err := strconv.ParseUint(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), 10, 64)
+	if err != nil {
+		return nil, obsidian.HttpError(errors.Errorf("invalid If-Match header %s", ifMatch), http.StatusBadRequest)
+	}
+	return &version, nil
+}
+
+// SetExpectedVersion guards the update to the entity with the expected
+// version, adding an update if the writes don't already include one.
+func SetExpectedVersion(writes []configurator.EntityWriteOperation, id storage.TypeAndKey, version uint64) []configurator.EntityWriteOperation {
+	for i, write := range writes {
+		update, ok := write.(configurator.EntityUpdateCriteria)
+		if ok && update.GetTypeAndKey() == id {
+			update.ExpectedVersion = &version
+			writes[i] = update
+			return writes
+		}
+	}
+	return append(writes, configurator.EntityUpdateCriteria{Type: id.Type, Key: id.Key, ExpectedVersion: &version})
+}
+
+// MakeUpdateErr returns a 412 Precondition Failed error if the update failed
+// due to a version conflict, and a 500 Internal Server Error otherwise.
+func MakeUpdateErr(err error) *echo.HTTPError {
+	if _, ok := errors.Cause(err).(*storage.VersionConflictError); ok {
+		return obsidian.HttpError(err, http.StatusPreconditionFailed)
+	}
+	return obsidian.HttpError(err, http.StatusInternalServerError)
+}
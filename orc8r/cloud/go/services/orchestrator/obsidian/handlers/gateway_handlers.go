@@ -214,14 +214,22 @@ func getGatewayHandler(c echo.Context) error {
 	if nerr != nil {
 		return nerr
 	}
-	ret, nerr := LoadMagmadGateway(c.Request().Context(), nid, gid)
+	ret, version, nerr := loadMagmadGateway(c.Request().Context(), nid, gid)
 	if nerr != nil {
 		return nerr
 	}
+	SetETag(c, version)
 	return c.JSON(http.StatusOK, ret)
 }
 
 func LoadMagmadGateway(ctx context.Context, networkID string, gatewayID string) (*models.MagmadGateway, *echo.HTTPError) {
+	ret, _, nerr := loadMagmadGateway(ctx, networkID, gatewayID)
+	return ret, nerr
+}
+
+// loadMagmadGateway returns the magmad gateway along with the version of its
+// entity.
+func loadMagmadGateway(ctx context.Context, networkID string, gatewayID string) (*models.MagmadGateway, uint64, *echo.HTTPError) {
 	ent, err := configurator.LoadEntity(
 		networkID, orc8r.MagmadGatewayType, gatewayID,
 		configurator.EntityLoadCriteria{
@@ -233,19 +241,19 @@ func LoadMagmadGateway(ctx context.Context, networkID string, gatewayID string)
 		serdes.Entity,
 	)
 	if err == merrors.ErrNotFound {
-		return nil, echo.ErrNotFound
+		return nil, 0, echo.ErrNotFound
 	}
 	if err != nil {
-		return nil, obsidian.HttpError(err, http.StatusInternalServerError)
+		return nil, 0, obsidian.HttpError(err, http.StatusInternalServerError)
 	}
 
 	dev, err := device.GetDevice(ctx, networkID, orc8r.AccessGatewayRecordType, ent.PhysicalID, serdes.Device)
 	if err != nil && err != merrors.ErrNotFound {
-		return nil, obsidian.HttpError(err, http.StatusInternalServerError)
+		return nil, 0, obsidian.HttpError(err, http.StatusInternalServerError)
 	}
 	status, err := wrappers.GetGatewayStatus(ctx, networkID, ent.PhysicalID)
 	if err != nil && err != merrors.ErrNotFound {
-		return nil, obsidian.HttpError(err, http.StatusInternalServerError)
+		return nil, 0, obsidian.HttpError(err, http.StatusInternalServerError)
 	}
 
 	// If the gateway/network is malformed, we could get no corresponding
@@ -254,7 +262,7 @@ func LoadMagmadGateway(ctx context.Context, networkID string, gatewayID string)
 	if dev != nil {
 		devCasted = dev.(*models.GatewayDevice)
 	}
-	return (&models.MagmadGateway{}).FromBackendModels(ent, devCasted, status), nil
+	return (&models.MagmadGateway{}).FromBackendModels(ent, devCasted, status), ent.Version, nil
 }
 
 func updateGatewayHandler(c echo.Context) error {
@@ -307,10 +315,17 @@ func UpdateGateway(c echo.Context, nid string, gid string, model MagmadEncompass
 	if nerr != nil {
 		return nerr
 	}
+	expectedVersion, nerr := GetIfMatchVersion(c)
+	if nerr != nil {
+		return nerr
+	}
+	if expectedVersion != nil {
+		writes = SetExpectedVersion(writes, storage.TypeAndKey{Type: orc8r.MagmadGatewayType, Key: gid}, *expectedVersion)
+	}
 
 	err = configurator.WriteEntities(nid, writes, entitySerdes)
 	if err != nil {
-		return obsidian.HttpError(err, http.StatusInternalServerError)
+		return MakeUpdateErr(err)
 	}
 
 	// Device info is cheap to update, so just do it all the time if
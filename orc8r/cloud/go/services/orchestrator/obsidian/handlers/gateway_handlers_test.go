@@ -389,13 +389,14 @@ func TestGetGateway(t *testing.T) {
 	expected.Status.CheckinTime = uint64(time.Unix(1000000, 0).UnixNano() / (int64(time.Millisecond) / int64(time.Nanosecond)))
 
 	tc := tests.Test{
-		Method:         "GET",
-		URL:            testURLRoot + "/g1",
-		Handler:        getGateway,
-		ParamNames:     []string{"network_id", "gateway_id"},
-		ParamValues:    []string{"n1", "g1"},
-		ExpectedStatus: 200,
-		ExpectedResult: expected,
+		Method:          "GET",
+		URL:             testURLRoot + "/g1",
+		Handler:         getGateway,
+		ParamNames:      []string{"network_id", "gateway_id"},
+		ParamValues:     []string{"n1", "g1"},
+		ExpectedStatus:  200,
+		ExpectedResult:  expected,
+		ExpectedHeaders: map[string]string{"ETag": `"0"`},
 	}
 	tests.RunUnitTest(t, e, tc)
 
@@ -546,6 +547,47 @@ func TestUpdateGateway(t *testing.T) {
 	assert.Equal(t, expectedEnts, actualEnts)
 	assert.Equal(t, payload.Device, actualDevice)
 
+	// 412 stale If-Match
+	tc = tests.Test{
+		Method:                 "PUT",
+		URL:                    testURLRoot + "/g1",
+		Handler:                updateGateway,
+		Payload:                payload,
+		ParamNames:             []string{"network_id", "gateway_id"},
+		ParamValues:            []string{"n1", "g1"},
+		Headers:                map[string]string{"If-Match": `"0"`},
+		ExpectedStatus:         412,
+		ExpectedErrorSubstring: "version conflict",
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// 400 malformed If-Match
+	tc = tests.Test{
+		Method:         "PUT",
+		URL:            testURLRoot + "/g1",
+		Handler:        updateGateway,
+		Payload:        payload,
+		ParamNames:     []string{"network_id", "gateway_id"},
+		ParamValues:    []string{"n1", "g1"},
+		Headers:        map[string]string{"If-Match": "*"},
+		ExpectedStatus: 400,
+		ExpectedError:  "invalid If-Match header *",
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// matching If-Match
+	tc = tests.Test{
+		Method:         "PUT",
+		URL:            testURLRoot + "/g1",
+		Handler:        updateGateway,
+		Payload:        payload,
+		ParamNames:     []string{"network_id", "gateway_id"},
+		ParamValues:    []string{"n1", "g1"},
+		Headers:        map[string]string{"If-Match": `"1"`},
+		ExpectedStatus: 204,
+	}
+	tests.RunUnitTest(t, e, tc)
+
 	// 400 mismatch gateway_id in parameter vs. payload
 	tc = tests.Test{
 		Method:                 "PUT",
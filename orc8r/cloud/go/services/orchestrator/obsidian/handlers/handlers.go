@@ -38,6 +38,9 @@ const (
 	ManageNetworkDNSPath               = ManageNetworkPath + obsidian.UrlSep + "dns"
 	ManageNetworkDNSRecordsPath        = ManageNetworkDNSPath + obsidian.UrlSep + "records"
 	ManageNetworkDNSRecordByDomainPath = ManageNetworkDNSRecordsPath + obsidian.UrlSep + ":domain"
+	ListNetworkRevisionsPath           = ManageNetworkPath + obsidian.UrlSep + "revisions"
+	DiffNetworkRevisionsPath           = ListNetworkRevisionsPath + obsidian.UrlSep + "diff"
+	RollbackNetworkPath                = ListNetworkRevisionsPath + obsidian.UrlSep + ":revision" + obsidian.UrlSep + "rollback"
 
 	Gateways                     = "gateways"
 	ListGatewaysPath             = ManageNetworkPath + obsidian.UrlSep + Gateways
@@ -82,6 +85,11 @@ func GetObsidianHandlers() []obsidian.Handler {
 		{Path: ManageNetworkDNSRecordByDomainPath, Methods: obsidian.PUT, HandlerFunc: UpdateDNSRecord},
 		{Path: ManageNetworkDNSRecordByDomainPath, Methods: obsidian.DELETE, HandlerFunc: DeleteDNSRecord},
 
+		// Magma V1 Network Revisions
+		{Path: ListNetworkRevisionsPath, Methods: obsidian.GET, HandlerFunc: listRevisions},
+		{Path: DiffNetworkRevisionsPath, Methods: obsidian.GET, HandlerFunc: diffRevisions},
+		{Path: RollbackNetworkPath, Methods: obsidian.POST, HandlerFunc: rollbackNetwork},
+
 		// Magma V1 Gateways
 		{Path: ListGatewaysPath, Methods: obsidian.GET, HandlerFunc: listGatewaysHandler},
 		{Path: ListGatewaysPath, Methods: obsidian.POST, HandlerFunc: createGatewayHandler},
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2020 The Magma Authors.
+ *
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"magma/orc8r/cloud/go/obsidian"
+	"magma/orc8r/cloud/go/serdes"
+	"magma/orc8r/cloud/go/services/configurator"
+	"magma/orc8r/cloud/go/services/orchestrator/obsidian/models"
+	merrors "magma/orc8r/lib/go/errors"
+
+	"github.com/labstack/echo"
+	"github.com/pkg/errors"
+)
+
+const (
+	queryParamFromRevision = "from"
+	queryParamFromTime     = "from_time"
+	queryParamToRevision   = "to"
+	queryParamToTime       = "to_time"
+)
+
+func listRevisions(c echo.Context) error {
+	networkID, nerr := obsidian.GetNetworkId(c)
+	if nerr != nil {
+		return nerr
+	}
+	revisions, err := configurator.ListRevisions(networkID)
+	if err != nil {
+		return obsidian.HttpError(err, http.StatusInternalServerError)
+	}
+
+	ret := make([]*models.NetworkRevision, 0, len(revisions))
+	for _, rev := range revisions {
+		ret = append(ret, (&models.NetworkRevision{}).FromConfiguratorRevision(rev))
+	}
+	return c.JSON(http.StatusOK, ret)
+}
+
+func diffRevisions(c echo.Context) error {
+	networkID, nerr := obsidian.GetNetworkId(c)
+	if nerr != nil {
+		return nerr
+	}
+	from, err := getRevisionSelector(c, queryParamFromRevision, queryParamFromTime)
+	if err != nil {
+		return obsidian.HttpError(err, http.StatusBadRequest)
+	}
+	to, err := getRevisionSelector(c, queryParamToRevision, queryParamToTime)
+	if err != nil {
+		return obsidian.HttpError(err, http.StatusBadRequest)
+	}
+
+	diff, err := configurator.DiffRevisions(networkID, from, to, serdes.Network, serdes.Entity)
+	if err == merrors.ErrNotFound {
+		return obsidian.HttpError(err, http.StatusNotFound)
+	}
+	if err != nil {
+		return obsidian.HttpError(err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, (&models.NetworkRevisionDiff{}).FromConfiguratorDiff(diff))
+}
+
+func rollbackNetwork(c echo.Context) error {
+	networkID, nerr := obsidian.GetNetworkId(c)
+	if nerr != nil {
+		return nerr
+	}
+	revision, err := strconv.ParseUint(c.Param("revision"), 10, 64)
+	if err != nil || revision == 0 {
+		return obsidian.HttpError(errors.New("revision must be a positive integer"), http.StatusBadRequest)
+	}
+
+	diff, err := configurator.RollbackNetwork(networkID, configurator.RevisionSelector{Revision: revision}, serdes.Network, serdes.Entity)
+	if err == merrors.ErrNotFound {
+		return obsidian.HttpError(err, http.StatusNotFound)
+	}
+	if err != nil {
+		return obsidian.HttpError(err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, (&models.NetworkRevisionDiff{}).FromConfiguratorDiff(diff))
+}
+
+// getRevisionSelector returns the revision selected by the passed query
+// params. Selects the latest revision if neither param is set.
+func getRevisionSelector(c echo.Context, revisionParam, timeParam string) (configurator.RevisionSelector, error) {
+	revisionStr, timeStr := c.QueryParam(revisionParam), c.QueryParam(timeParam)
+	switch {
+	case revisionStr != "" && timeStr != "":
+		return configurator.RevisionSelector{}, errors.Errorf("only one of %s and %s may be set", revisionParam, timeParam)
+	case revisionStr != "":
+		revision, err := strconv.ParseUint(revisionStr, 10, 64)
+		if err != nil || revision == 0 {
+			return configurator.RevisionSelector{}, errors.Errorf("%s must be a positive integer", revisionParam)
+		}
+		return configurator.RevisionSelector{Revision: revision}, nil
+	case timeStr != "":
+		timestamp, err := strconv.ParseInt(timeStr, 10, 64)
+		if err != nil || timestamp <= 0 {
+			return configurator.RevisionSelector{}, errors.Errorf("%s must be a positive Unix timestamp", timeParam)
+		}
+		return configurator.RevisionSelector{Timestamp: timestamp}, nil
+	default:
+		return configurator.RevisionSelector{}, nil
+	}
+}
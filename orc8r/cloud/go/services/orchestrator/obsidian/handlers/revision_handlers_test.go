@@ -0,0 +1,196 @@
+/*
+ * Copyright 2020 The Magma Authors.
+ *
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers_test
+
+import (
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	models1 "magma/orc8r/cloud/go/models"
+	"magma/orc8r/cloud/go/obsidian"
+	"magma/orc8r/cloud/go/obsidian/tests"
+	"magma/orc8r/cloud/go/orc8r"
+	"magma/orc8r/cloud/go/serdes"
+	"magma/orc8r/cloud/go/services/configurator"
+	"magma/orc8r/cloud/go/services/configurator/test_init"
+	"magma/orc8r/cloud/go/services/orchestrator/obsidian/handlers"
+	"magma/orc8r/cloud/go/services/orchestrator/obsidian/models"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NetworkRevisionHandlers(t *testing.T) {
+	test_init.StartTestService(t)
+	defer clock.UnfreezeClock(t)
+
+	e := echo.New()
+	testURLRoot := "/magma/v1/networks/n1/revisions"
+	obsidianHandlers := handlers.GetObsidianHandlers()
+	listRevisions := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, "/magma/v1/networks/:network_id/revisions", obsidian.GET).HandlerFunc
+	diffRevisions := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, "/magma/v1/networks/:network_id/revisions/diff", obsidian.GET).HandlerFunc
+	rollbackNetwork := tests.GetHandlerByPathAndMethod(t, obsidianHandlers, "/magma/v1/networks/:network_id/revisions/:revision/rollback", obsidian.POST).HandlerFunc
+
+	// Revision 1: create network
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	err := configurator.CreateNetwork(configurator.Network{ID: "n1", Name: "network1"}, serdes.Network)
+	assert.NoError(t, err)
+
+	// Revision 2: create tier
+	clock.SetAndFreezeClock(t, time.Unix(2000, 0))
+	tier := &models.Tier{ID: "tier1", Name: "tier 1", Version: "1.0.0", Images: []*models.TierImage{}, Gateways: []models1.GatewayID{}}
+	_, err = configurator.CreateEntity("n1", tier.ToNetworkEntity(), serdes.Entity)
+	assert.NoError(t, err)
+
+	// Revision 3: update tier
+	clock.SetAndFreezeClock(t, time.Unix(3000, 0))
+	updatedTier := &models.Tier{ID: "tier1", Name: "tier 1", Version: "2.0.0", Images: []*models.TierImage{}, Gateways: []models1.GatewayID{}}
+	_, err = configurator.UpdateEntity("n1", updatedTier.ToUpdateCriteria(), serdes.Entity)
+	assert.NoError(t, err)
+
+	// List revisions
+	revisions := []*models.NetworkRevision{
+		{Revision: 1, CreatedAt: 1000},
+		{Revision: 2, CreatedAt: 2000},
+		{Revision: 3, CreatedAt: 3000},
+	}
+	tc := tests.Test{
+		Method:         "GET",
+		URL:            testURLRoot,
+		ParamNames:     []string{"network_id"},
+		ParamValues:    []string{"n1"},
+		Handler:        listRevisions,
+		ExpectedStatus: 200,
+		ExpectedResult: tests.JSONMarshaler(revisions),
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Diff from revision 1 to latest
+	tierID := &models.RevisionEntityID{Type: orc8r.UpgradeTierEntityType, Key: "tier1"}
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            testURLRoot + "/diff?from=1",
+		ParamNames:     []string{"network_id"},
+		ParamValues:    []string{"n1"},
+		Handler:        diffRevisions,
+		ExpectedStatus: 200,
+		ExpectedResult: &models.NetworkRevisionDiff{
+			From: revisions[0],
+			To:   revisions[2],
+			Entities: []*models.EntityRevisionDiff{
+				{
+					ID: tierID,
+					After: &models.RevisionEntity{
+						Type:         orc8r.UpgradeTierEntityType,
+						Key:          "tier1",
+						Name:         "tier 1",
+						Config:       updatedTier,
+						Associations: []*models.RevisionEntityID{},
+					},
+				},
+			},
+		},
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Diff by timestamp
+	tierDiff := &models.EntityRevisionDiff{
+		ID: tierID,
+		Before: &models.RevisionEntity{
+			Type:         orc8r.UpgradeTierEntityType,
+			Key:          "tier1",
+			Name:         "tier 1",
+			Config:       tier,
+			Associations: []*models.RevisionEntityID{},
+		},
+		After: &models.RevisionEntity{
+			Type:         orc8r.UpgradeTierEntityType,
+			Key:          "tier1",
+			Name:         "tier 1",
+			Config:       updatedTier,
+			Associations: []*models.RevisionEntityID{},
+		},
+	}
+	tc.URL = testURLRoot + "/diff?from_time=2500&to=3"
+	tc.ExpectedResult = &models.NetworkRevisionDiff{
+		From:     revisions[1],
+		To:       revisions[2],
+		Entities: []*models.EntityRevisionDiff{tierDiff},
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Bad query params
+	tc.URL = testURLRoot + "/diff?from=1&from_time=1000"
+	tc.ExpectedStatus = 400
+	tc.ExpectedError = "only one of from and from_time may be set"
+	tests.RunUnitTest(t, e, tc)
+
+	tc.URL = testURLRoot + "/diff?to=abc"
+	tc.ExpectedError = "to must be a positive integer"
+	tests.RunUnitTest(t, e, tc)
+
+	// Unknown revision
+	tc.URL = testURLRoot + "/diff?from=4"
+	tc.ExpectedStatus = 404
+	tc.ExpectedError = "Not found"
+	tests.RunUnitTest(t, e, tc)
+
+	// Roll back to revision 2
+	clock.SetAndFreezeClock(t, time.Unix(4000, 0))
+	tc = tests.Test{
+		Method:         "POST",
+		URL:            testURLRoot + "/2/rollback",
+		ParamNames:     []string{"network_id", "revision"},
+		ParamValues:    []string{"n1", "2"},
+		Handler:        rollbackNetwork,
+		ExpectedStatus: 200,
+		ExpectedResult: &models.NetworkRevisionDiff{
+			From: revisions[2],
+			To:   revisions[1],
+			Entities: []*models.EntityRevisionDiff{
+				{ID: tierID, Before: tierDiff.After, After: tierDiff.Before},
+			},
+		},
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	ent, err := configurator.LoadEntity("n1", orc8r.UpgradeTierEntityType, "tier1", configurator.EntityLoadCriteria{LoadConfig: true}, serdes.Entity)
+	assert.NoError(t, err)
+	assert.Equal(t, tier, ent.Config)
+
+	tc.URL = testURLRoot + "/5/rollback"
+	tc.ParamValues = []string{"n1", "5"}
+	tc.ExpectedStatus = 404
+	tc.ExpectedError = "Not found"
+	tests.RunUnitTest(t, e, tc)
+
+	tc.URL = testURLRoot + "/0/rollback"
+	tc.ParamValues = []string{"n1", "0"}
+	tc.ExpectedStatus = 400
+	tc.ExpectedError = "revision must be a positive integer"
+	tests.RunUnitTest(t, e, tc)
+
+	// Revisions of an unknown network
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/networks/n2/revisions",
+		ParamNames:     []string{"network_id"},
+		ParamValues:    []string{"n2"},
+		Handler:        listRevisions,
+		ExpectedStatus: 200,
+		ExpectedResult: tests.JSONMarshaler([]*models.NetworkRevision{}),
+	}
+	tests.RunUnitTest(t, e, tc)
+}
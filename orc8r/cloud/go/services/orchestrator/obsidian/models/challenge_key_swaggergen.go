@@ -19,13 +19,16 @@ import (
 // swagger:model challenge_key
 type ChallengeKey struct {
 
-	// key
+	// For TPM2_ATTESTATION keys, the DER-encoded certificate of the TPM's RSA endorsement key, followed by any intermediate certificates up to a trusted TPM manufacturer root
+	EndorsementCerts []strfmt.Base64 `json:"endorsement_certs,omitempty"`
+
+	// DER-encoded public key, or for TPM2_ATTESTATION keys, the TPMT_PUBLIC area of the TPM's attestation key, e.g. as output by `tpm2_readpublic -f tpmt`
 	// Format: byte
 	Key *strfmt.Base64 `json:"key,omitempty"`
 
 	// key type
 	// Required: true
-	// Enum: [ECHO SOFTWARE_ECDSA_SHA256]
+	// Enum: [ECHO SOFTWARE_ECDSA_SHA256 TPM2_ATTESTATION]
 	KeyType string `json:"key_type"`
 }
 
@@ -62,7 +65,7 @@ var challengeKeyTypeKeyTypePropEnum []interface{}
 
 func init() {
 	var res []string
-	if err := json.Unmarshal([]byte(`["ECHO","SOFTWARE_ECDSA_SHA256"]`), &res); err != nil {
+	if err := json.Unmarshal([]byte(`["ECHO","SOFTWARE_ECDSA_SHA256","TPM2_ATTESTATION"]`), &res); err != nil {
 		panic(err)
 	}
 	for _, v := range res {
@@ -77,6 +80,9 @@ const (
 
 	// ChallengeKeyKeyTypeSOFTWAREECDSASHA256 captures enum value "SOFTWARE_ECDSA_SHA256"
 	ChallengeKeyKeyTypeSOFTWAREECDSASHA256 string = "SOFTWARE_ECDSA_SHA256"
+
+	// ChallengeKeyKeyTypeTPM2ATTESTATION captures enum value "TPM2_ATTESTATION"
+	ChallengeKeyKeyTypeTPM2ATTESTATION string = "TPM2_ATTESTATION"
 )
 
 // prop value enum
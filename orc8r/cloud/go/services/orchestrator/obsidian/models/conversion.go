@@ -14,6 +14,7 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"magma/orc8r/cloud/go/models"
@@ -567,3 +568,69 @@ func getGatewayIDs(gatewayTKs []storage.TypeAndKey) []models.GatewayID {
 			return models.GatewayID(tk.Key)
 		}).([]models.GatewayID)
 }
+
+func (m *NetworkRevision) FromConfiguratorRevision(rev configurator.NetworkRevision) *NetworkRevision {
+	m.Revision = rev.Revision
+	m.CreatedAt = rev.CreatedAt
+	return m
+}
+
+func (m *NetworkRevisionDiff) FromConfiguratorDiff(diff configurator.NetworkDiff) *NetworkRevisionDiff {
+	m.From = (&NetworkRevision{}).FromConfiguratorRevision(diff.From)
+	m.To = (&NetworkRevision{}).FromConfiguratorRevision(diff.To)
+	if diff.NetworkBefore != nil {
+		m.NetworkBefore = (&RevisionNetwork{}).FromConfiguratorNetwork(*diff.NetworkBefore)
+	}
+	if diff.NetworkAfter != nil {
+		m.NetworkAfter = (&RevisionNetwork{}).FromConfiguratorNetwork(*diff.NetworkAfter)
+	}
+	m.Entities = make([]*EntityRevisionDiff, 0, len(diff.Entities))
+	for _, entDiff := range diff.Entities {
+		ret := &EntityRevisionDiff{ID: &RevisionEntityID{Type: entDiff.ID.Type, Key: entDiff.ID.Key}}
+		if entDiff.Before != nil {
+			ret.Before = (&RevisionEntity{}).FromConfiguratorEntity(*entDiff.Before)
+		}
+		if entDiff.After != nil {
+			ret.After = (&RevisionEntity{}).FromConfiguratorEntity(*entDiff.After)
+		}
+		m.Entities = append(m.Entities, ret)
+	}
+	return m
+}
+
+func (m *RevisionNetwork) FromConfiguratorNetwork(n configurator.Network) *RevisionNetwork {
+	m.ID = n.ID
+	m.Type = n.Type
+	m.Name = n.Name
+	m.Description = n.Description
+	m.Configs = map[string]interface{}{}
+	for typ, config := range n.Configs {
+		m.Configs[typ] = getRevisionConfig(config)
+	}
+	return m
+}
+
+func (m *RevisionEntity) FromConfiguratorEntity(ent configurator.NetworkEntity) *RevisionEntity {
+	m.Type = ent.Type
+	m.Key = ent.Key
+	m.Name = ent.Name
+	m.Description = ent.Description
+	m.PhysicalID = ent.PhysicalID
+	m.Config = getRevisionConfig(ent.Config)
+	m.Associations = make([]*RevisionEntityID, 0, len(ent.Associations))
+	for _, tk := range ent.Associations {
+		m.Associations = append(m.Associations, &RevisionEntityID{Type: tk.Type, Key: tk.Key})
+	}
+	return m
+}
+
+// getRevisionConfig returns a config which can be rendered as JSON. Configs
+// whose serdes aren't registered with this service are still serialized - most
+// serdes serialize to JSON, so pass those through as-is.
+func getRevisionConfig(config interface{}) interface{} {
+	b, ok := config.([]byte)
+	if ok && json.Valid(b) {
+		return json.RawMessage(b)
+	}
+	return config
+}
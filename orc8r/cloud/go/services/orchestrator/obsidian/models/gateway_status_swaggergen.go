@@ -19,6 +19,9 @@ type GatewayStatus struct {
 	// cert expiration time
 	CertExpirationTime int64 `json:"cert_expiration_time,omitempty"`
 
+	// cert rotation
+	CertRotation *CertRotationStatus `json:"cert_rotation,omitempty"`
+
 	// checkin time
 	CheckinTime uint64 `json:"checkin_time,omitempty"`
 
@@ -54,6 +57,10 @@ type GatewayStatus struct {
 func (m *GatewayStatus) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateCertRotation(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateMachineInfo(formats); err != nil {
 		res = append(res, err)
 	}
@@ -72,6 +79,24 @@ func (m *GatewayStatus) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *GatewayStatus) validateCertRotation(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.CertRotation) { // not required
+		return nil
+	}
+
+	if m.CertRotation != nil {
+		if err := m.CertRotation.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("cert_rotation")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *GatewayStatus) validateMachineInfo(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.MachineInfo) { // not required
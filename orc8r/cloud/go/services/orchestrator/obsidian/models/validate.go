@@ -14,15 +14,19 @@
 package models
 
 import (
+	"crypto/rsa"
 	"crypto/x509"
 	"errors"
 	"fmt"
 
+	"magma/orc8r/lib/go/security/tpm"
+
 	"github.com/go-openapi/strfmt"
 )
 
 const echoKeyType = "ECHO"
 const ecdsaKeyType = "SOFTWARE_ECDSA_SHA256"
+const tpmKeyType = "TPM2_ATTESTATION"
 
 func (m *Network) ValidateModel() error {
 	return m.Validate(strfmt.Default)
@@ -75,6 +79,30 @@ func (m *ChallengeKey) ValidateModel() error {
 			return fmt.Errorf("Failed to parse key: %s", err)
 		}
 		return nil
+	case tpmKeyType:
+		if m.Key == nil {
+			return fmt.Errorf("No key supplied")
+		}
+		ak, err := tpm.DecodePublic(*m.Key)
+		if err != nil {
+			return fmt.Errorf("Failed to parse key: %s", err)
+		}
+		if ak.Attributes&tpm.AttestationKeyAttrs != tpm.AttestationKeyAttrs {
+			return fmt.Errorf("Key is not a restricted signing key bound to its TPM")
+		}
+		if len(m.EndorsementCerts) == 0 {
+			return fmt.Errorf("No endorsement certificates supplied")
+		}
+		for i, der := range m.EndorsementCerts {
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				return fmt.Errorf("Failed to parse endorsement certificate %d: %s", i, err)
+			}
+			if _, ok := cert.PublicKey.(*rsa.PublicKey); i == 0 && !ok {
+				return fmt.Errorf("Endorsement key must be an RSA key")
+			}
+		}
+		return nil
 	default:
 		return fmt.Errorf("Unknown key type %s", m.KeyType)
 	}
@@ -15,15 +15,19 @@ package state
 
 import (
 	"context"
+	"io"
 
 	"magma/orc8r/cloud/go/serde"
 	state_types "magma/orc8r/cloud/go/services/state/types"
+	"magma/orc8r/cloud/go/storage"
 	merrors "magma/orc8r/lib/go/errors"
 	"magma/orc8r/lib/go/protos"
 	"magma/orc8r/lib/go/registry"
 
 	"github.com/golang/glog"
 	"github.com/thoas/go-funk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // GetStateClient returns a client to the state service.
@@ -78,6 +82,20 @@ func GetStates(ctx context.Context, networkID string, stateIDs state_types.IDs,
 	return state_types.MakeStatesByID(res.States, serdes)
 }
 
+// SearchFilter specifies the states matched by a state search.
+// TypeFilter and KeyFilter are both OR clauses, and the final predicate
+// applied to the search will be the AND of both filters.
+// e.g.: ["t1", "t2"], ["k1", "k2"] => (t1 OR t2) AND (k1 OR k2)
+type SearchFilter struct {
+	TypeFilter []string
+	KeyFilter  []string
+	// KeyPrefix, if non-empty, takes precedence over KeyFilter.
+	KeyPrefix string
+	// ReporterHardwareID, if non-empty, limits the search to states most
+	// recently reported by the gateway with this hardware ID.
+	ReporterHardwareID string
+}
+
 // SearchStates returns all states matching the filter arguments.
 // typeFilter and keyFilter are both OR clauses, and the final predicate
 // applied to the search will be the AND of both filters.
@@ -85,27 +103,90 @@ func GetStates(ctx context.Context, networkID string, stateIDs state_types.IDs,
 // the keyFilter argument.
 // e.g.: ["t1", "t2"], ["k1", "k2"] => (t1 OR t2) AND (k1 OR k2)
 func SearchStates(ctx context.Context, networkID string, typeFilter []string, keyFilter []string, keyPrefix *string, serdes serde.Registry) (state_types.StatesByID, error) {
-	client, err := GetStateClient()
+	filter := SearchFilter{TypeFilter: typeFilter, KeyFilter: keyFilter}
+	if !funk.IsEmpty(keyPrefix) {
+		filter.KeyPrefix = *keyPrefix
+	}
+	return SearchAllStates(ctx, networkID, filter, serdes)
+}
+
+// SearchAllStates returns all states matching the filter.
+// States are streamed from the state service one page at a time, so
+// searches can match any number of states. Use ForEachStatesPage to process
+// the states without holding all of them in memory.
+func SearchAllStates(ctx context.Context, networkID string, filter SearchFilter, serdes serde.Registry) (state_types.StatesByID, error) {
+	ret := state_types.StatesByID{}
+	err := ForEachStatesPage(ctx, networkID, filter, serdes, func(states state_types.StatesByID) error {
+		for id, st := range states {
+			ret[id] = st
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return ret, nil
+}
 
-	req := &protos.GetStatesRequest{
-		NetworkID:  networkID,
-		TypeFilter: typeFilter,
-		IdFilter:   keyFilter,
-		LoadValues: true,
+// ForEachStatesPage calls fn with each page of the states matching the
+// filter, as the pages are streamed from the state service. Only one page of
+// states is held in memory at a time. Returns the first error returned by
+// fn, after which no more pages are streamed.
+func ForEachStatesPage(ctx context.Context, networkID string, filter SearchFilter, serdes serde.Registry, fn func(state_types.StatesByID) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	return streamStates(ctx, makeSearchRequest(networkID, filter), func(page []*protos.State) error {
+		states, err := state_types.MakeStatesByID(page, serdes)
+		if err != nil {
+			return err
+		}
+		return fn(states)
+	})
+}
+
+// SearchStateIDs returns the IDs of all states matching the filter, without
+// loading their values.
+func SearchStateIDs(ctx context.Context, networkID string, filter SearchFilter) (state_types.IDs, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	req := makeSearchRequest(networkID, filter)
+	req.LoadValues = false
+	var ids state_types.IDs
+	err := streamStates(ctx, req, func(page []*protos.State) error {
+		for _, st := range page {
+			ids = append(ids, state_types.ID{Type: st.Type, DeviceID: st.DeviceID})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if !funk.IsEmpty(keyPrefix) {
-		req.IdPrefix = *keyPrefix
-		req.IdFilter = nil
+	return ids, nil
+}
+
+// SearchStatesPage returns a page of at most pageSize states matching the
+// filter, ordered by type then key, along with the token for the next page.
+// An empty page token requests the first page, and an empty next page token
+// indicates no states remain.
+func SearchStatesPage(ctx context.Context, networkID string, filter SearchFilter, pageSize uint32, pageToken string, serdes serde.Registry) (state_types.StatesByID, string, error) {
+	client, err := GetStateClient()
+	if err != nil {
+		return nil, "", err
 	}
+
+	req := makeSearchRequest(networkID, filter)
+	req.PageSize = pageSize
+	req.PageToken = pageToken
 	res, err := client.GetStates(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return state_types.MakeStatesByID(res.States, serdes)
+	states, err := state_types.MakeStatesByID(res.States, serdes)
+	if err != nil {
+		return nil, "", err
+	}
+	return states, res.NextPageToken, nil
 }
 
 // DeleteStates deletes states specified by the networkID and a list of
@@ -125,6 +206,41 @@ func DeleteStates(ctx context.Context, networkID string, stateIDs state_types.ID
 	return err
 }
 
+// CompareAndSwapStates writes the states only if the stored version of each
+// state in expected matches its expected version, where a version of
+// storage.VersionAbsent requires the state to not exist. The version of each
+// written state with an expected version is set to 1 more than its expected
+// version, or to 0 when it's created.
+// Returns a *storage.VersionConflictError, keyed by state type and device ID,
+// if any version doesn't match.
+func CompareAndSwapStates(ctx context.Context, networkID string, expected map[state_types.ID]uint64, states state_types.SerializedStatesByID) error {
+	client, err := GetStateClient()
+	if err != nil {
+		return err
+	}
+
+	req := &protos.CompareAndSwapStatesRequest{NetworkID: networkID}
+	for id, st := range states {
+		req.States = append(req.States, &protos.State{
+			Type:     id.Type,
+			DeviceID: id.DeviceID,
+			Value:    st.SerializedReportedState,
+			Version:  st.Version,
+		})
+	}
+	for id, version := range expected {
+		req.ExpectedVersions = append(req.ExpectedVersions, &protos.IDAndVersion{
+			Id:      &protos.StateID{Type: id.Type, DeviceID: id.DeviceID},
+			Version: version,
+		})
+	}
+	_, err = client.CompareAndSwapStates(ctx, req)
+	if status.Code(err) == codes.Aborted {
+		return makeVersionConflictError(status.Convert(err))
+	}
+	return err
+}
+
 // GetSerializedStates returns a map of states specified by the networkID and
 // a list of type and key.
 func GetSerializedStates(ctx context.Context, networkID string, stateIDs state_types.IDs) (state_types.SerializedStatesByID, error) {
@@ -150,6 +266,49 @@ func GetSerializedStates(ctx context.Context, networkID string, stateIDs state_t
 	return state_types.MakeSerializedStatesByID(res.States)
 }
 
+// streamStates calls fn with each page of states streamed for the search
+// request. Callers should cancel ctx once done, so the stream is released
+// when fn returns an error.
+func streamStates(ctx context.Context, req *protos.GetStatesRequest, fn func([]*protos.State) error) error {
+	client, err := GetStateClient()
+	if err != nil {
+		return err
+	}
+
+	stream, err := client.StreamStates(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = fn(res.States)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func makeSearchRequest(networkID string, filter SearchFilter) *protos.GetStatesRequest {
+	req := &protos.GetStatesRequest{
+		NetworkID:          networkID,
+		TypeFilter:         filter.TypeFilter,
+		IdFilter:           filter.KeyFilter,
+		LoadValues:         true,
+		ReporterHardwareId: filter.ReporterHardwareID,
+	}
+	if filter.KeyPrefix != "" {
+		req.IdPrefix = filter.KeyPrefix
+		req.IdFilter = nil
+	}
+	return req
+}
+
 func makeProtoIDs(stateIDs state_types.IDs) []*protos.StateID {
 	var ids []*protos.StateID
 	for _, st := range stateIDs {
@@ -157,3 +316,21 @@ func makeProtoIDs(stateIDs state_types.IDs) []*protos.StateID {
 	}
 	return ids
 }
+
+// makeVersionConflictError converts an Aborted status from the state service
+// back to a version conflict error.
+func makeVersionConflictError(st *status.Status) error {
+	conflict := &storage.VersionConflictError{Versions: map[storage.TypeAndKey]uint64{}}
+	for _, detail := range st.Details() {
+		idAndVersion, ok := detail.(*protos.IDAndVersion)
+		if !ok {
+			continue
+		}
+		id := storage.TypeAndKey{Type: idAndVersion.Id.GetType(), Key: idAndVersion.Id.GetDeviceID()}
+		conflict.Versions[id] = idAndVersion.Version
+	}
+	if len(conflict.Versions) == 0 {
+		return st.Err()
+	}
+	return conflict
+}
@@ -16,6 +16,7 @@ package state_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -137,6 +138,97 @@ func TestStateService(t *testing.T) {
 	testGetStatesResponse(t, states, bundle0)
 }
 
+func TestStateService_Pagination(t *testing.T) {
+	configurator_test_init.StartTestService(t)
+	device_test_init.StartTestService(t)
+	state_test_init.StartTestService(t)
+
+	networkID := "state_service_test_network"
+	configurator_test.RegisterNetwork(t, networkID, "State Service Test")
+	configurator_test.RegisterGateway(t, networkID, "gw0", &models.GatewayDevice{HardwareID: "hw0"})
+	configurator_test.RegisterGateway(t, networkID, "gw1", &models.GatewayDevice{HardwareID: "hw1"})
+	ctx0 := test_utils.GetContextWithCertificate(t, "hw0")
+	ctx1 := test_utils.GetContextWithCertificate(t, "hw1")
+
+	var bundles0, bundles1 []stateBundle
+	for i := 0; i < 5; i++ {
+		bundles0 = append(bundles0, makeStateBundle("test-serde", fmt.Sprintf("key0%d", i), Name{Name: fmt.Sprintf("name0%d", i)}))
+		bundles1 = append(bundles1, makeStateBundle("test-serde", fmt.Sprintf("key1%d", i), Name{Name: fmt.Sprintf("name1%d", i)}))
+	}
+	_, err := reportStates(ctx0, bundles0...)
+	assert.NoError(t, err)
+	_, err = reportStates(ctx1, bundles1...)
+	assert.NoError(t, err)
+
+	// Page through all states
+	filter := state.SearchFilter{TypeFilter: []string{"test-serde"}}
+	var pages []state_types.StatesByID
+	pageToken := ""
+	for {
+		states, nextPageToken, err := state.SearchStatesPage(context.Background(), networkID, filter, 4, pageToken, stateSerdes)
+		assert.NoError(t, err)
+		pages = append(pages, states)
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+	assert.Len(t, pages, 3)
+	assert.Len(t, pages[0], 4)
+	assert.Len(t, pages[1], 4)
+	assert.Len(t, pages[2], 2)
+	// Pages are ordered by key
+	assert.Contains(t, pages[0], bundles0[0].ID)
+	assert.Contains(t, pages[2], bundles1[4].ID)
+
+	// Filter by reporter, within a page
+	filter.ReporterHardwareID = "hw1"
+	states, nextPageToken, err := state.SearchStatesPage(context.Background(), networkID, filter, 3, "", stateSerdes)
+	assert.NoError(t, err)
+	assert.Len(t, states, 3)
+	assert.NotEmpty(t, nextPageToken)
+	testGetStatesResponse(t, states, bundles1[0], bundles1[1], bundles1[2])
+	states, nextPageToken, err = state.SearchStatesPage(context.Background(), networkID, filter, 3, nextPageToken, stateSerdes)
+	assert.NoError(t, err)
+	assert.Empty(t, nextPageToken)
+	assert.Len(t, states, 2)
+	testGetStatesResponse(t, states, bundles1[3], bundles1[4])
+
+	// Stream all states, filtered by reporter and key prefix
+	states, err = state.SearchAllStates(context.Background(), networkID, state.SearchFilter{KeyPrefix: "key0", ReporterHardwareID: "hw0"}, stateSerdes)
+	assert.NoError(t, err)
+	assert.Len(t, states, 5)
+	testGetStatesResponse(t, states, bundles0...)
+	states, err = state.SearchAllStates(context.Background(), networkID, state.SearchFilter{KeyPrefix: "key0", ReporterHardwareID: "hw1"}, stateSerdes)
+	assert.NoError(t, err)
+	assert.Empty(t, states)
+
+	// Stream states page by page, stopping at the first error
+	var numStates int
+	err = state.ForEachStatesPage(context.Background(), networkID, state.SearchFilter{TypeFilter: []string{"test-serde"}}, stateSerdes, func(states state_types.StatesByID) error {
+		numStates += len(states)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 10, numStates)
+	var numPages int
+	err = state.ForEachStatesPage(context.Background(), networkID, state.SearchFilter{TypeFilter: []string{"test-serde"}}, stateSerdes, func(states state_types.StatesByID) error {
+		numPages++
+		return errors.New("stop")
+	})
+	assert.EqualError(t, err, "stop")
+	assert.Equal(t, 1, numPages)
+
+	// Stream IDs only
+	ids, err := state.SearchStateIDs(context.Background(), networkID, state.SearchFilter{KeyPrefix: "key1"})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, state_types.IDs{bundles1[0].ID, bundles1[1].ID, bundles1[2].ID, bundles1[3].ID, bundles1[4].ID}, ids)
+
+	// Invalid page token
+	_, _, err = state.SearchStatesPage(context.Background(), networkID, filter, 3, "not-a-token", stateSerdes)
+	assert.Error(t, err)
+}
+
 type stateBundle struct {
 	state *protos.State
 	ID    state_types.ID
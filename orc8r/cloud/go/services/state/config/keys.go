@@ -20,4 +20,10 @@ const (
 	// When value is true, state service handles automatically reindex state indexers.
 	// When value is false, reindexing must be handled by the provided CLI.
 	EnableAutomaticReindexing = "enable_automatic_reindexing"
+
+	// StateRetention is a parameter name in the state service config.
+	// Value is a map of state type to retention duration, e.g. "24h".
+	// Reported states of a listed type are deleted once they haven't been
+	// reported for the retention duration.
+	StateRetention = "state_retention"
 )
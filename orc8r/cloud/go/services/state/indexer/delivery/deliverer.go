@@ -0,0 +1,186 @@
+/*
+ * Copyright 2020 The Magma Authors.
+ *
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delivery
+
+import (
+	"context"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/state/indexer"
+	"magma/orc8r/cloud/go/services/state/indexer/index"
+	"magma/orc8r/cloud/go/services/state/indexer/metrics"
+	state_types "magma/orc8r/cloud/go/services/state/types"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// pollInterval is how often the deliverer checks for due batches, when
+	// none were due at the previous check.
+	pollInterval = 5 * time.Second
+	// claimLimit is the max number of batches claimed at a time.
+	claimLimit = 20
+)
+
+var (
+	// TestHookDeliverDone is a function called after each DeliverDue call
+	// in Run() completes, regardless of success or failure.
+	// This should only be set by test code.
+	TestHookDeliverDone = func() {}
+)
+
+// MustIndex forwards states to all registered indexers, according to their
+// subscriptions, making one attempt per indexer.
+// Failed index calls are enqueued for retried delivery, and successful index
+// calls supersede older queued versions of the states.
+// Returns after completing attempt at indexing states.
+func MustIndex(queue Queue, networkID string, states state_types.SerializedStatesByID) {
+	errs, err := index.IndexOnce(networkID, states)
+	if err != nil {
+		// Since we don't have a good way of recovering from failed goroutines
+		// right now, fail immediately to restart the service to a good state
+		glog.Fatalf("Error getting indexers during Index goroutine: %v", err)
+	}
+	idxs, err := indexer.GetIndexers()
+	if err != nil {
+		glog.Fatalf("Error getting indexers during Index goroutine: %v", err)
+	}
+	for _, idx := range idxs {
+		indexerID := idx.GetID()
+		filtered := states.Filter(idx.GetTypes()...)
+		indexErr, failed := errs[indexerID]
+		if !failed {
+			if len(filtered) == 0 {
+				continue
+			}
+			err = queue.MarkIndexed(indexerID, networkID, filtered)
+			if err != nil {
+				glog.Errorf("Failed to mark states indexed for indexer %s: %s", indexerID, err)
+			}
+			continue
+		}
+
+		metrics.DeliveryErrors.WithLabelValues(indexerID).Inc()
+		glog.Warningf("Enqueueing state batch for retried delivery: %s", indexErr)
+		err = queue.Enqueue(indexerID, networkID, filtered, indexErr)
+		if err != nil {
+			glog.Errorf("Failed to enqueue state batch for retried delivery to indexer %s: %s", indexerID, err)
+		}
+	}
+	glog.V(2).Infof("Completed state index for network %s with %d states", networkID, len(states))
+}
+
+// Deliverer retries delivery of enqueued state batches to their indexers.
+type Deliverer struct {
+	queue Queue
+}
+
+// NewDeliverer returns a deliverer for the batches of the passed queue.
+func NewDeliverer(queue Queue) *Deliverer {
+	return &Deliverer{queue: queue}
+}
+
+// Run periodically claims due batches and attempts their delivery, reporting
+// per-indexer delivery metrics.
+// Returns only upon context cancellation.
+func (d *Deliverer) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			glog.Warning("State indexer delivery async job canceled")
+			return
+		default:
+		}
+
+		d.reportMetrics()
+
+		n, err := d.DeliverDue()
+		TestHookDeliverDone()
+		if err != nil {
+			glog.Errorf("Failed to deliver state batches from delivery queue: %s", err)
+		}
+		if err != nil || n == 0 {
+			clock.Sleep(pollInterval)
+		}
+	}
+}
+
+// DeliverDue claims due batches and attempts their delivery.
+// Returns the number of claimed batches.
+func (d *Deliverer) DeliverDue() (int, error) {
+	batches, err := d.queue.ClaimDueBatches(claimLimit)
+	if err != nil {
+		return 0, err
+	}
+	for _, b := range batches {
+		// States may have been superseded since the batch was claimed
+		err = d.queue.DropSupersededStates(b)
+		if err != nil {
+			return len(batches), err
+		}
+		if len(b.States) == 0 {
+			continue
+		}
+		deliverErr := deliver(b)
+		if deliverErr != nil {
+			metrics.DeliveryErrors.WithLabelValues(b.IndexerID).Inc()
+			glog.Warningf("Failed retried delivery of state batch %s: %s", b.ID, deliverErr)
+		}
+		err = d.queue.CompleteBatch(b, deliverErr)
+		if err != nil {
+			return len(batches), err
+		}
+	}
+	return len(batches), nil
+}
+
+func (d *Deliverer) reportMetrics() {
+	stats, err := d.queue.GetStats()
+	if err != nil {
+		glog.Errorf("Report delivery metrics failed to get delivery stats: %v", err)
+		return
+	}
+
+	idxs, err := indexer.GetIndexers()
+	if err != nil {
+		glog.Errorf("Report delivery metrics failed to get indexers: %v", err)
+		return
+	}
+	// Reset metrics of indexers without pending or failed batches
+	for _, idx := range idxs {
+		if _, ok := stats[idx.GetID()]; !ok {
+			stats[idx.GetID()] = Stats{}
+		}
+	}
+
+	now := clock.Now()
+	for id, st := range stats {
+		metrics.DeliveryPending.WithLabelValues(id).Set(float64(st.Pending))
+		metrics.DeliveryFailed.WithLabelValues(id).Set(float64(st.Failed))
+		var lag float64
+		if st.Pending != 0 {
+			lag = now.Sub(st.OldestPending).Seconds()
+		}
+		metrics.DeliveryLag.WithLabelValues(id).Set(lag)
+	}
+}
+
+func deliver(b *Batch) error {
+	idx, err := indexer.GetIndexer(b.IndexerID)
+	if err != nil {
+		return err
+	}
+	return index.IndexOne(b.NetworkID, idx, b.States)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery_test
+
+import (
+	"testing"
+
+	"magma/orc8r/cloud/go/orc8r"
+	"magma/orc8r/cloud/go/serde"
+	"magma/orc8r/cloud/go/serdes"
+	"magma/orc8r/cloud/go/services/state"
+	"magma/orc8r/cloud/go/services/state/indexer"
+	"magma/orc8r/cloud/go/services/state/indexer/delivery"
+	delivery_mocks "magma/orc8r/cloud/go/services/state/indexer/delivery/mocks"
+	"magma/orc8r/cloud/go/services/state/indexer/mocks"
+	state_test_init "magma/orc8r/cloud/go/services/state/test_init"
+	state_types "magma/orc8r/cloud/go/services/state/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMustIndex(t *testing.T) {
+	in := getSerializedStates(t)
+	idx0 := getIndexer(id0)
+	idx1 := getIndexer(id1)
+	idx0.On("Index", nid0, in).Return(nil, nil).Once()
+	idx1.On("Index", nid0, in).Return(nil, someErr).Once()
+
+	indexer.DeregisterAllForTest(t)
+	state_test_init.StartNewTestIndexer(t, idx0)
+	state_test_init.StartNewTestIndexer(t, idx1)
+
+	// Only the failed index call is enqueued, and the successful index call
+	// supersedes queued states
+	q := &delivery_mocks.Queue{}
+	q.On("Enqueue", id1, nid0, in, mock.Anything).Return(nil).Once()
+	q.On("MarkIndexed", id0, nid0, in).Return(nil).Once()
+
+	delivery.MustIndex(q, nid0, in)
+	idx0.AssertExpectations(t)
+	idx1.AssertExpectations(t)
+	q.AssertExpectations(t)
+}
+
+func TestDeliverer_DeliverDue(t *testing.T) {
+	in := getSerializedStates(t)
+	idx0 := getIndexer(id0)
+	idx1 := getIndexer(id1)
+	idx0.On("Index", nid0, in).Return(nil, nil).Once()
+	idx1.On("Index", nid0, in).Return(nil, someErr).Once()
+
+	indexer.DeregisterAllForTest(t)
+	state_test_init.StartNewTestIndexer(t, idx0)
+	state_test_init.StartNewTestIndexer(t, idx1)
+
+	b0 := &delivery.Batch{ID: "batch0", IndexerID: id0, NetworkID: nid0, States: in, Attempts: 1}
+	b1 := &delivery.Batch{ID: "batch1", IndexerID: id1, NetworkID: nid0, States: in, Attempts: 1}
+	b2 := &delivery.Batch{ID: "batch2", IndexerID: "some_missing_indexerid", NetworkID: nid0, States: in, Attempts: 1}
+
+	// Batch 3's states were superseded after it was claimed
+	b3 := &delivery.Batch{ID: "batch3", IndexerID: id0, NetworkID: nid0, States: in, Attempts: 1}
+
+	q := &delivery_mocks.Queue{}
+	q.On("ClaimDueBatches", mock.Anything).Return([]*delivery.Batch{b0, b1, b2, b3}, nil).Once()
+	q.On("DropSupersededStates", b0).Return(nil).Once()
+	q.On("DropSupersededStates", b1).Return(nil).Once()
+	q.On("DropSupersededStates", b2).Return(nil).Once()
+	q.On("DropSupersededStates", b3).Run(func(args mock.Arguments) {
+		args.Get(0).(*delivery.Batch).States = state_types.SerializedStatesByID{}
+	}).Return(nil).Once()
+	q.On("CompleteBatch", b0, nil).Return(nil).Once()
+	q.On("CompleteBatch", b1, mock.MatchedBy(func(err error) bool { return err != nil })).Return(nil).Once()
+	q.On("CompleteBatch", b2, mock.MatchedBy(func(err error) bool { return err != nil })).Return(nil).Once()
+
+	n, err := delivery.NewDeliverer(q).DeliverDue()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+	idx0.AssertExpectations(t)
+	idx1.AssertExpectations(t)
+	q.AssertExpectations(t)
+
+	// Claim error
+	q = &delivery_mocks.Queue{}
+	q.On("ClaimDueBatches", mock.Anything).Return(nil, someErr).Once()
+	_, err = delivery.NewDeliverer(q).DeliverDue()
+	assert.Error(t, err)
+	q.AssertExpectations(t)
+}
+
+func getIndexer(id string) *mocks.Indexer {
+	idx := &mocks.Indexer{}
+	idx.On("GetID").Return(id)
+	idx.On("GetTypes").Return([]string{orc8r.StringMapSerdeType})
+	idx.On("GetVersion").Return(indexer.Version(42))
+	return idx
+}
+
+func getSerializedStates(t *testing.T) state_types.SerializedStatesByID {
+	id := state_types.ID{Type: orc8r.StringMapSerdeType, DeviceID: "some_deviceid"}
+	rep, err := serde.Serialize(&state.StringToStringMap{"apple": "banana"}, id.Type, serdes.State)
+	require.NoError(t, err)
+	return state_types.SerializedStatesByID{id: {SerializedReportedState: rep}}
+}
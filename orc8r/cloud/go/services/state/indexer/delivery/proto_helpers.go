@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	"sort"
+
+	state_protos "magma/orc8r/cloud/go/services/state/protos"
+	"magma/orc8r/lib/go/protos"
+)
+
+func MakeProtoBatches(batches []*Batch) []*state_protos.FailedBatch {
+	var ret []*state_protos.FailedBatch
+	for _, b := range batches {
+		ret = append(ret, MakeProtoBatch(b))
+	}
+	return ret
+}
+
+// MakeProtoBatch converts a batch to its proto representation.
+// States are represented by their IDs, sorted by type then device ID.
+func MakeProtoBatch(b *Batch) *state_protos.FailedBatch {
+	var ids []*protos.StateID
+	for id := range b.States {
+		ids = append(ids, &protos.StateID{Type: id.Type, DeviceID: id.DeviceID})
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].Type != ids[j].Type {
+			return ids[i].Type < ids[j].Type
+		}
+		return ids[i].DeviceID < ids[j].DeviceID
+	})
+
+	return &state_protos.FailedBatch{
+		BatchId:    b.ID,
+		IndexerId:  b.IndexerID,
+		NetworkId:  b.NetworkID,
+		StateIds:   ids,
+		Attempts:   uint32(b.Attempts),
+		Error:      b.Error,
+		EnqueuedAt: b.EnqueuedAt.Unix(),
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2020 The Magma Authors.
+ *
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delivery
+
+import (
+	"time"
+
+	state_types "magma/orc8r/cloud/go/services/state/types"
+)
+
+const (
+	// DefaultMaxAttempts is the default max number of attempts at delivering
+	// a batch before it's considered failed.
+	DefaultMaxAttempts uint = 10
+
+	// baseBackoff is the delay before the first retried delivery of a batch.
+	// Each subsequent retry doubles the delay, up to maxBackoff.
+	baseBackoff = 10 * time.Second
+	maxBackoff  = 10 * time.Minute
+)
+
+// Batch of states awaiting delivery to an indexer.
+type Batch struct {
+	// ID is the batch's unique identifier.
+	ID string
+	// IndexerID is the ID of the indexer to which the batch is delivered.
+	IndexerID string
+	// NetworkID is the network of the batch's states.
+	NetworkID string
+	// States to deliver.
+	States state_types.SerializedStatesByID
+
+	// Attempts is the number of delivery attempts made so far.
+	Attempts uint
+	// Error is the error from the most recent delivery attempt.
+	Error string
+	// EnqueuedAt is when the batch was first enqueued.
+	EnqueuedAt time.Time
+}
+
+// Stats summarize the state of an indexer's deliveries.
+type Stats struct {
+	// Pending is the number of batches awaiting retried delivery.
+	Pending uint
+	// Failed is the number of batches which exhausted their delivery attempts.
+	Failed uint
+	// OldestPending is when the oldest pending batch was enqueued.
+	// Zero if no batches are pending.
+	OldestPending time.Time
+}
+
+// Queue is a durable per-indexer queue of state batches whose delivery to
+// their indexer failed.
+// Batches are retried with exponential backoff, and batches which exhaust
+// their attempts are kept as failed until replayed.
+// ClaimDueBatches should be polled periodically, as batches become due at any
+// time.
+type Queue interface {
+	// Initialize the queue.
+	// Call before other methods.
+	Initialize() error
+
+	// Enqueue adds a batch of states for retried delivery to an indexer,
+	// recording the error from the failed delivery attempt.
+	// Pending work is coalesced per state: states older than their queued
+	// version are dropped, and states newer than their queued version
+	// replace it.
+	Enqueue(indexerID string, networkID string, states state_types.SerializedStatesByID, withErr error) error
+
+	// MarkIndexed indicates the states were delivered to the indexer outside
+	// the queue, removing queued versions of the states which aren't newer.
+	MarkIndexed(indexerID string, networkID string, states state_types.SerializedStatesByID) error
+
+	// ClaimDueBatches claims up to limit batches which are due for retried
+	// delivery, to safely perform delivery.
+	// Claimed batches aren't returned to other callers until they're
+	// completed or the claim times out.
+	ClaimDueBatches(limit uint64) ([]*Batch, error)
+
+	// DropSupersededStates removes from a claimed batch the states whose
+	// queued version it no longer holds, as newer versions of the states were
+	// enqueued or indexed since the batch was claimed. Call right before
+	// delivering the batch. Batches left empty are removed from the queue.
+	DropSupersededStates(batch *Batch) error
+
+	// CompleteBatch indicates completion of a delivery attempt.
+	// Successfully delivered batches are removed from the queue.
+	CompleteBatch(batch *Batch, withErr error) error
+
+	// GetFailedBatches returns the batches which exhausted their delivery
+	// attempts. If indexerID is non-empty, only that indexer's batches are
+	// returned.
+	GetFailedBatches(indexerID string) ([]*Batch, error)
+
+	// ReplayFailedBatches makes failed batches of an indexer due for delivery
+	// again, with a fresh set of attempts.
+	// If batchIDs is empty, replays all of the indexer's failed batches.
+	// Returns the number of replayed batches.
+	ReplayFailedBatches(indexerID string, batchIDs []string) (uint, error)
+
+	// GetStats returns delivery stats, keyed by indexer ID.
+	// Only indexers with pending or failed batches are included.
+	GetStats() (map[string]Stats, error)
+}
+
+// getBackoff returns the delay before the next delivery attempt, after the
+// passed number of attempts.
+func getBackoff(attempts uint) time.Duration {
+	backoff := baseBackoff
+	for i := uint(1); i < attempts; i++ {
+		backoff *= 2
+		if backoff >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,852 @@
+/*
+ * Copyright 2020 The Magma Authors.
+ *
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree.
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delivery
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	state_types "magma/orc8r/cloud/go/services/state/types"
+	"magma/orc8r/cloud/go/sqorc"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	// queueTableName is the name of the SQL table acting as the delivery queue.
+	queueTableName = "indexer_delivery_queue"
+	// keysTableName is the name of the SQL table tracking which batch holds
+	// the queued version of each state.
+	keysTableName = "indexer_delivery_queue_keys"
+
+	idCol          = "id"
+	indexerIDCol   = "indexer_id"
+	networkIDCol   = "network_id"
+	statesCol      = "states"
+	attemptsCol    = "attempts"
+	errorCol       = "error"
+	enqueuedAtCol  = "enqueued_at"
+	nextAttemptCol = "next_attempt"
+
+	batchIDCol   = "batch_id"
+	stateTypeCol = "state_type"
+	deviceIDCol  = "device_id"
+	timeMsCol    = "time_ms"
+	versionCol   = "version"
+
+	// defaultClaimTimeout after which claimed batches are considered
+	// abandoned, and become due for delivery again.
+	defaultClaimTimeout = 5 * time.Minute
+)
+
+// sqlQueue wraps a SQL table to provide a durable delivery queue for state
+// batches.
+//
+// Queue columns:
+//	- id			-- unique ID of the batch
+//	- indexer_id	-- ID of the indexer to which the batch is delivered
+//	- network_id	-- network of the batch's states
+//	- states		-- JSON-encoded states
+//	- attempts		-- number of delivery attempts made so far
+//	- error			-- error from the most recent delivery attempt
+//	- enqueued_at	-- Unix time when the batch was enqueued
+//	- next_attempt	-- Unix time after which the batch is due for delivery
+//
+// Keys columns:
+//	- indexer_id	-- ID of the indexer to which the state is delivered
+//	- network_id	-- network of the state
+//	- state_type	-- type of the state
+//	- device_id		-- device ID of the state
+//	- batch_id		-- ID of the batch holding the queued version of the state,
+//					   empty while a transaction holds the key of a state
+//					   without a queued version
+//	- time_ms		-- receive time of the queued version of the state
+//	- version		-- version of the queued version of the state
+//
+// Notes:
+//	- Pending work is coalesced per (indexer, state ID): a batch only holds
+//	  a state while it's the latest version known to the queue, so retried
+//	  or replayed batches never overwrite newer index data. Enqueueing or
+//	  marking as indexed a newer version of a state removes the state from
+//	  the batch holding its older version, and deletes emptied batches.
+//	- Enqueueing and marking as indexed first upsert the keys of their
+//	  states, so concurrent transactions on the same states are serialized
+//	  by the keys' row locks. Batches are checked against the keys when
+//	  claimed and again right before delivery, so a batch isn't delivered
+//	  with a state it no longer holds.
+//	- Batches with attempts >= max attempts are considered failed, and are
+//	  kept until replayed.
+//	- Claiming a batch pushes back its next attempt by defaultClaimTimeout,
+//	  so a batch whose claimant fails before completing it is retried.
+//	- Batches are claimed with "FOR UPDATE SKIP LOCKED" where the SQL dialect
+//	  supports it. Otherwise claims are serialized by the database-level lock
+//	  held by each transaction.
+type sqlQueue struct {
+	maxAttempts uint
+	db          *sql.DB
+	builder     sqorc.StatementBuilder
+}
+
+// NewSQLQueue returns a new SQL-backed implementation of a delivery queue.
+// The queue is safe for use across goroutines and processes.
+//
+// maxAttempts is the max number of times to attempt delivering a batch.
+func NewSQLQueue(maxAttempts uint, db *sql.DB, builder sqorc.StatementBuilder) Queue {
+	return &sqlQueue{maxAttempts: maxAttempts, db: db, builder: builder}
+}
+
+func (s *sqlQueue) Initialize() error {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		_, err := s.builder.CreateTable(queueTableName).
+			IfNotExists().
+			Column(idCol).Type(sqorc.ColumnTypeText).NotNull().PrimaryKey().EndColumn().
+			Column(indexerIDCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(networkIDCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(statesCol).Type(sqorc.ColumnTypeBytes).NotNull().EndColumn().
+			Column(attemptsCol).Type(sqorc.ColumnTypeInt).Default(0).NotNull().EndColumn().
+			Column(errorCol).Type(sqorc.ColumnTypeText).Default("''").NotNull().EndColumn().
+			Column(enqueuedAtCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			Column(nextAttemptCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize indexer delivery queue table")
+		}
+
+		_, err = s.builder.CreateIndex(queueTableName + "_next_attempt_idx").
+			IfNotExists().
+			On(queueTableName).
+			Columns(nextAttemptCol).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize indexer delivery queue index")
+		}
+
+		_, err = s.builder.CreateTable(keysTableName).
+			IfNotExists().
+			Column(indexerIDCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(networkIDCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(stateTypeCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(deviceIDCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(batchIDCol).Type(sqorc.ColumnTypeText).NotNull().EndColumn().
+			Column(timeMsCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			Column(versionCol).Type(sqorc.ColumnTypeBigInt).NotNull().EndColumn().
+			PrimaryKey(indexerIDCol, networkIDCol, deviceIDCol, stateTypeCol).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize indexer delivery queue keys table")
+		}
+
+		_, err = s.builder.CreateIndex(keysTableName + "_batch_id_idx").
+			IfNotExists().
+			On(keysTableName).
+			Columns(batchIDCol).
+			RunWith(tx).
+			Exec()
+		return nil, errors.Wrap(err, "initialize indexer delivery queue keys index")
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+func (s *sqlQueue) Enqueue(indexerID string, networkID string, states state_types.SerializedStatesByID, withErr error) error {
+	var errVal string
+	if withErr != nil {
+		errVal = withErr.Error()
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		queued, err := s.lockQueuedKeys(tx, indexerID, networkID, states)
+		if err != nil {
+			return nil, err
+		}
+
+		// Drop states whose queued version is newer, and supersede the
+		// states whose queued version is older
+		toEnqueue := state_types.SerializedStatesByID{}
+		for id, st := range states {
+			if q, ok := queued[id]; ok && q.isNewerThan(st) {
+				continue
+			}
+			toEnqueue[id] = st
+		}
+		if len(toEnqueue) == 0 {
+			return nil, nil
+		}
+		err = s.removeQueuedStates(tx, indexerID, networkID, queued, toEnqueue)
+		if err != nil {
+			return nil, err
+		}
+
+		marshaledStates, err := marshalStates(toEnqueue)
+		if err != nil {
+			return nil, err
+		}
+		batchID := uuid.New().String()
+		now := clock.Now()
+		_, err = s.builder.Insert(queueTableName).
+			Columns(idCol, indexerIDCol, networkIDCol, statesCol, attemptsCol, errorCol, enqueuedAtCol, nextAttemptCol).
+			Values(batchID, indexerID, networkID, marshaledStates, 1, errVal, now.Unix(), now.Add(getBackoff(1)).Unix()).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrapf(err, "enqueue state batch for indexer %s", indexerID)
+		}
+		return nil, s.insertQueuedKeys(tx, indexerID, networkID, batchID, toEnqueue)
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+func (s *sqlQueue) MarkIndexed(indexerID string, networkID string, states state_types.SerializedStatesByID) error {
+	// Most states have no queued version, so check for queued versions
+	// without locking the states' keys before superseding them
+	anyQueued, err := s.hasQueuedKeys(indexerID, networkID, states)
+	if err != nil || !anyQueued {
+		return err
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		queued, err := s.lockQueuedKeys(tx, indexerID, networkID, states)
+		if err != nil {
+			return nil, err
+		}
+		superseded := state_types.SerializedStatesByID{}
+		for id, st := range states {
+			if q, ok := queued[id]; ok && !q.isNewerThan(st) {
+				superseded[id] = st
+			}
+		}
+		return nil, s.removeQueuedStates(tx, indexerID, networkID, queued, superseded)
+	}
+	_, err = sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+func (s *sqlQueue) ClaimDueBatches(limit uint64) ([]*Batch, error) {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		now := clock.Now()
+
+		selectDue := s.selectAll().
+			Where(squirrel.And{
+				squirrel.Lt{attemptsCol: s.maxAttempts},
+				squirrel.LtOrEq{nextAttemptCol: now.Unix()},
+			}).
+			OrderBy(nextAttemptCol).
+			Limit(limit)
+		rows, err := s.builder.ForUpdateSkipLocked(selectDue).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "claim due state batches, select due batches")
+		}
+		defer sqorc.CloseRowsLogOnError(rows, "ClaimDueBatches")
+
+		batches, err := scanBatches(rows)
+		if err != nil {
+			return nil, err
+		}
+		batches, err = s.dropSupersededStates(tx, batches)
+		if err != nil {
+			return nil, err
+		}
+		if len(batches) == 0 {
+			return batches, nil
+		}
+
+		var ids []string
+		for _, b := range batches {
+			ids = append(ids, b.ID)
+		}
+		_, err = s.builder.Update(queueTableName).
+			Set(nextAttemptCol, now.Add(defaultClaimTimeout).Unix()).
+			Where(squirrel.Eq{idCol: ids}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "claim due state batches, update next attempt")
+		}
+		return batches, nil
+	}
+
+	txRet, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	if err != nil {
+		return nil, err
+	}
+	return txRet.([]*Batch), nil
+}
+
+func (s *sqlQueue) DropSupersededStates(batch *Batch) error {
+	if batch == nil {
+		return errors.New("batch cannot be nil")
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		return s.dropSupersededStates(tx, []*Batch{batch})
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+func (s *sqlQueue) CompleteBatch(batch *Batch, withErr error) error {
+	if batch == nil {
+		return errors.New("batch cannot be nil")
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		if withErr == nil {
+			_, err := s.builder.Delete(queueTableName).
+				Where(squirrel.Eq{idCol: batch.ID}).
+				RunWith(tx).
+				Exec()
+			if err != nil {
+				return nil, errors.Wrapf(err, "delete delivered state batch %s", batch.ID)
+			}
+			_, err = s.builder.Delete(keysTableName).
+				Where(squirrel.Eq{batchIDCol: batch.ID}).
+				RunWith(tx).
+				Exec()
+			return nil, errors.Wrapf(err, "delete keys of delivered state batch %s", batch.ID)
+		}
+
+		attempts := batch.Attempts + 1
+		_, err := s.builder.Update(queueTableName).
+			Set(attemptsCol, attempts).
+			Set(errorCol, withErr.Error()).
+			Set(nextAttemptCol, clock.Now().Add(getBackoff(attempts)).Unix()).
+			Where(squirrel.Eq{idCol: batch.ID}).
+			RunWith(tx).
+			Exec()
+		return nil, errors.Wrapf(err, "update failed state batch %s", batch.ID)
+	}
+	_, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	return err
+}
+
+func (s *sqlQueue) GetFailedBatches(indexerID string) ([]*Batch, error) {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		where := squirrel.And{squirrel.GtOrEq{attemptsCol: s.maxAttempts}}
+		if indexerID != "" {
+			where = append(where, squirrel.Eq{indexerIDCol: indexerID})
+		}
+		rows, err := s.selectAll().
+			Where(where).
+			OrderBy(enqueuedAtCol, idCol).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "select failed state batches")
+		}
+		defer sqorc.CloseRowsLogOnError(rows, "GetFailedBatches")
+		return scanBatches(rows)
+	}
+
+	txRet, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	if err != nil {
+		return nil, err
+	}
+	return txRet.([]*Batch), nil
+}
+
+func (s *sqlQueue) ReplayFailedBatches(indexerID string, batchIDs []string) (uint, error) {
+	if indexerID == "" {
+		return 0, errors.New("indexer ID cannot be empty")
+	}
+
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		where := squirrel.And{
+			squirrel.Eq{indexerIDCol: indexerID},
+			squirrel.GtOrEq{attemptsCol: s.maxAttempts},
+		}
+		if len(batchIDs) != 0 {
+			where = append(where, squirrel.Eq{idCol: batchIDs})
+		}
+		res, err := s.builder.Update(queueTableName).
+			Set(attemptsCol, 0).
+			Set(nextAttemptCol, clock.Now().Unix()).
+			Where(where).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrapf(err, "replay failed state batches for indexer %s", indexerID)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, errors.Wrap(err, "get number of replayed state batches")
+		}
+		return uint(n), nil
+	}
+
+	txRet, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	if err != nil {
+		return 0, err
+	}
+	return txRet.(uint), nil
+}
+
+func (s *sqlQueue) GetStats() (map[string]Stats, error) {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		stats := map[string]Stats{}
+
+		rows, err := s.builder.Select(indexerIDCol, "COUNT(*)", "MIN("+enqueuedAtCol+")").
+			From(queueTableName).
+			Where(squirrel.Lt{attemptsCol: s.maxAttempts}).
+			GroupBy(indexerIDCol).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "select pending state batch stats")
+		}
+		defer sqorc.CloseRowsLogOnError(rows, "GetStats")
+		for rows.Next() {
+			var id string
+			var count uint
+			var oldest int64
+			err = rows.Scan(&id, &count, &oldest)
+			if err != nil {
+				return nil, errors.Wrap(err, "scan pending state batch stats")
+			}
+			st := stats[id]
+			st.Pending = count
+			st.OldestPending = time.Unix(oldest, 0)
+			stats[id] = st
+		}
+		if err = rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "sql rows err")
+		}
+
+		failedRows, err := s.builder.Select(indexerIDCol, "COUNT(*)").
+			From(queueTableName).
+			Where(squirrel.GtOrEq{attemptsCol: s.maxAttempts}).
+			GroupBy(indexerIDCol).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "select failed state batch stats")
+		}
+		defer sqorc.CloseRowsLogOnError(failedRows, "GetStats")
+		for failedRows.Next() {
+			var id string
+			var count uint
+			err = failedRows.Scan(&id, &count)
+			if err != nil {
+				return nil, errors.Wrap(err, "scan failed state batch stats")
+			}
+			st := stats[id]
+			st.Failed = count
+			stats[id] = st
+		}
+		if err = failedRows.Err(); err != nil {
+			return nil, errors.Wrap(err, "sql rows err")
+		}
+
+		return stats, nil
+	}
+
+	txRet, err := sqorc.ExecInTx(s.db, nil, nil, txFn)
+	if err != nil {
+		return nil, err
+	}
+	return txRet.(map[string]Stats), nil
+}
+
+// queuedKey locates the queued version of a state.
+type queuedKey struct {
+	batchID string
+	timeMs  uint64
+	version uint64
+}
+
+// isNewerThan returns true if the queued version of the state was received
+// after the passed state.
+func (q queuedKey) isNewerThan(st state_types.SerializedState) bool {
+	if q.timeMs != st.TimeMs {
+		return q.timeMs > st.TimeMs
+	}
+	return q.version > st.Version
+}
+
+// lockQueuedKeys locks the keys of the passed states until the end of the
+// transaction, and returns their queued versions, keyed by state ID.
+// Keys of states without a queued version are locked by inserting them
+// without a batch, which lockQueuedKeys deletes again before returning. Their
+// row locks are held regardless, so concurrent transactions locking the same
+// keys wait for this one to complete.
+func (s *sqlQueue) lockQueuedKeys(tx *sql.Tx, indexerID string, networkID string, states state_types.SerializedStatesByID) (map[state_types.ID]queuedKey, error) {
+	// Lock keys in a consistent order, so transactions locking overlapping
+	// keys don't deadlock
+	ids := make([]state_types.ID, 0, len(states))
+	for id := range states {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].Type != ids[j].Type {
+			return ids[i].Type < ids[j].Type
+		}
+		return ids[i].DeviceID < ids[j].DeviceID
+	})
+
+	cols := []string{indexerIDCol, networkIDCol, stateTypeCol, deviceIDCol, batchIDCol, timeMsCol, versionCol}
+	chunkSize := sqorc.GetInsertChunkSize(s.builder, len(cols))
+	for start := 0; start < len(ids); start += chunkSize {
+		end := start + chunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		insert := s.builder.Insert(keysTableName).Columns(cols...)
+		for _, id := range ids[start:end] {
+			insert = insert.Values(indexerID, networkID, id.Type, id.DeviceID, "", 0, 0)
+		}
+		_, err := insert.
+			OnConflict(
+				[]sqorc.UpsertValue{{Column: batchIDCol, Value: squirrel.Expr(keysTableName + "." + batchIDCol)}},
+				indexerIDCol, networkIDCol, deviceIDCol, stateTypeCol,
+			).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "lock queued state keys")
+		}
+	}
+
+	queued, err := s.getQueuedKeys(tx, indexerID, networkID, states)
+	if err != nil {
+		return nil, err
+	}
+
+	var unqueued []state_types.ID
+	for _, id := range ids {
+		if _, ok := queued[id]; !ok {
+			unqueued = append(unqueued, id)
+		}
+	}
+	// 3 bind variables for indexer, network, and batch, 2 per key
+	keyChunkSize := (s.builder.MaxBindVariables() - 3) / 2
+	for start := 0; start < len(unqueued); start += keyChunkSize {
+		end := start + keyChunkSize
+		if end > len(unqueued) {
+			end = len(unqueued)
+		}
+		keys := squirrel.Or{}
+		for _, id := range unqueued[start:end] {
+			keys = append(keys, squirrel.Eq{stateTypeCol: id.Type, deviceIDCol: id.DeviceID})
+		}
+		_, err = s.builder.Delete(keysTableName).
+			Where(squirrel.And{
+				squirrel.Eq{indexerIDCol: indexerID, networkIDCol: networkID, batchIDCol: ""},
+				keys,
+			}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "delete unqueued state keys")
+		}
+	}
+	return queued, nil
+}
+
+// hasQueuedKeys returns true if any of the passed states has a queued
+// version, without locking their keys.
+func (s *sqlQueue) hasQueuedKeys(indexerID string, networkID string, states state_types.SerializedStatesByID) (bool, error) {
+	txFn := func(tx *sql.Tx) (interface{}, error) {
+		queued, err := s.getQueuedKeys(tx, indexerID, networkID, states)
+		if err != nil {
+			return nil, err
+		}
+		return len(queued) != 0, nil
+	}
+	txRet, err := sqorc.ExecInTx(s.db, &sql.TxOptions{ReadOnly: true}, nil, txFn)
+	if err != nil {
+		return false, err
+	}
+	return txRet.(bool), nil
+}
+
+// getQueuedKeys returns the queued versions of the passed states, keyed by
+// state ID.
+func (s *sqlQueue) getQueuedKeys(tx *sql.Tx, indexerID string, networkID string, states state_types.SerializedStatesByID) (map[state_types.ID]queuedKey, error) {
+	var deviceIDs []string
+	for id := range states {
+		deviceIDs = append(deviceIDs, id.DeviceID)
+	}
+
+	queued := map[state_types.ID]queuedKey{}
+	chunkSize := s.builder.MaxBindVariables() - 2
+	for start := 0; start < len(deviceIDs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(deviceIDs) {
+			end = len(deviceIDs)
+		}
+		rows, err := s.builder.Select(stateTypeCol, deviceIDCol, batchIDCol, timeMsCol, versionCol).
+			From(keysTableName).
+			Where(squirrel.Eq{
+				indexerIDCol: indexerID,
+				networkIDCol: networkID,
+				deviceIDCol:  deviceIDs[start:end],
+			}).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return nil, errors.Wrap(err, "select queued state keys")
+		}
+		err = scanQueuedKeys(rows, states, queued)
+		sqorc.CloseRowsLogOnError(rows, "getQueuedKeys")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return queued, nil
+}
+
+func scanQueuedKeys(rows *sql.Rows, states state_types.SerializedStatesByID, queued map[state_types.ID]queuedKey) error {
+	for rows.Next() {
+		var id state_types.ID
+		var q queuedKey
+		err := rows.Scan(&id.Type, &id.DeviceID, &q.batchID, &q.timeMs, &q.version)
+		if err != nil {
+			return errors.Wrap(err, "scan queued state key")
+		}
+		// Keys without a batch are held by lockQueuedKeys
+		if _, ok := states[id]; ok && q.batchID != "" {
+			queued[id] = q
+		}
+	}
+	return errors.Wrap(rows.Err(), "sql rows err")
+}
+
+// insertQueuedKeys records the batch as holding the queued version of the
+// passed states.
+func (s *sqlQueue) insertQueuedKeys(tx *sql.Tx, indexerID string, networkID string, batchID string, states state_types.SerializedStatesByID) error {
+	cols := []string{indexerIDCol, networkIDCol, stateTypeCol, deviceIDCol, batchIDCol, timeMsCol, versionCol}
+	chunkSize := sqorc.GetInsertChunkSize(s.builder, len(cols))
+	insert := s.builder.Insert(keysTableName).Columns(cols...)
+	n := 0
+	for id, st := range states {
+		insert = insert.Values(indexerID, networkID, id.Type, id.DeviceID, batchID, st.TimeMs, st.Version)
+		n++
+		if n%chunkSize == 0 || n == len(states) {
+			_, err := insert.RunWith(tx).Exec()
+			if err != nil {
+				return errors.Wrap(err, "insert queued state keys")
+			}
+			insert = s.builder.Insert(keysTableName).Columns(cols...)
+		}
+	}
+	return nil
+}
+
+// removeQueuedStates removes the passed states from the batches holding
+// their queued versions, deleting batches left empty.
+func (s *sqlQueue) removeQueuedStates(tx *sql.Tx, indexerID string, networkID string, queued map[state_types.ID]queuedKey, states state_types.SerializedStatesByID) error {
+	idsByBatch := map[string][]state_types.ID{}
+	for id := range states {
+		if q, ok := queued[id]; ok {
+			idsByBatch[q.batchID] = append(idsByBatch[q.batchID], id)
+		}
+	}
+
+	for batchID, ids := range idsByBatch {
+		var marshaledStates []byte
+		err := s.builder.Select(statesCol).
+			From(queueTableName).
+			Where(squirrel.Eq{idCol: batchID}).
+			RunWith(tx).
+			QueryRow().
+			Scan(&marshaledStates)
+		if err != nil && err != sql.ErrNoRows {
+			return errors.Wrapf(err, "select states of batch %s", batchID)
+		}
+		if err == nil {
+			err = s.removeBatchStates(tx, batchID, marshaledStates, ids)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, id := range ids {
+			_, err = s.builder.Delete(keysTableName).
+				Where(squirrel.Eq{
+					indexerIDCol: indexerID,
+					networkIDCol: networkID,
+					stateTypeCol: id.Type,
+					deviceIDCol:  id.DeviceID,
+				}).
+				RunWith(tx).
+				Exec()
+			if err != nil {
+				return errors.Wrap(err, "delete queued state key")
+			}
+		}
+	}
+	return nil
+}
+
+// removeBatchStates removes the passed states from the batch, deleting the
+// batch if left empty.
+func (s *sqlQueue) removeBatchStates(tx *sql.Tx, batchID string, marshaledStates []byte, ids []state_types.ID) error {
+	batchStates, err := unmarshalStates(marshaledStates)
+	if err != nil {
+		return errors.Wrapf(err, "unmarshal states of batch %s", batchID)
+	}
+	for _, id := range ids {
+		delete(batchStates, id)
+	}
+
+	if len(batchStates) == 0 {
+		_, err = s.builder.Delete(queueTableName).
+			Where(squirrel.Eq{idCol: batchID}).
+			RunWith(tx).
+			Exec()
+		return errors.Wrapf(err, "delete superseded state batch %s", batchID)
+	}
+
+	marshaledStates, err = marshalStates(batchStates)
+	if err != nil {
+		return err
+	}
+	_, err = s.builder.Update(queueTableName).
+		Set(statesCol, marshaledStates).
+		Where(squirrel.Eq{idCol: batchID}).
+		RunWith(tx).
+		Exec()
+	return errors.Wrapf(err, "update states of batch %s", batchID)
+}
+
+// dropSupersededStates removes from the batches the states whose queued
+// version is held by another batch, so they're never delivered over newer
+// versions. Batches left empty are deleted, and aren't returned.
+func (s *sqlQueue) dropSupersededStates(tx *sql.Tx, batches []*Batch) ([]*Batch, error) {
+	if len(batches) == 0 {
+		return batches, nil
+	}
+	var ids []string
+	for _, b := range batches {
+		ids = append(ids, b.ID)
+	}
+	rows, err := s.builder.Select(batchIDCol, stateTypeCol, deviceIDCol).
+		From(keysTableName).
+		Where(squirrel.Eq{batchIDCol: ids}).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, errors.Wrap(err, "select keys of claimed state batches")
+	}
+	defer sqorc.CloseRowsLogOnError(rows, "dropSupersededStates")
+	held := map[string]map[state_types.ID]bool{}
+	for rows.Next() {
+		var batchID string
+		var id state_types.ID
+		err = rows.Scan(&batchID, &id.Type, &id.DeviceID)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan key of claimed state batch")
+		}
+		if held[batchID] == nil {
+			held[batchID] = map[state_types.ID]bool{}
+		}
+		held[batchID][id] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "sql rows err")
+	}
+
+	ret := []*Batch{}
+	for _, b := range batches {
+		for id := range b.States {
+			if !held[b.ID][id] {
+				delete(b.States, id)
+			}
+		}
+		if len(b.States) != 0 {
+			ret = append(ret, b)
+			continue
+		}
+		_, err = s.builder.Delete(queueTableName).
+			Where(squirrel.Eq{idCol: b.ID}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, errors.Wrapf(err, "delete superseded state batch %s", b.ID)
+		}
+	}
+	return ret, nil
+}
+
+func (s *sqlQueue) selectAll() squirrel.SelectBuilder {
+	return s.builder.Select(idCol, indexerIDCol, networkIDCol, statesCol, attemptsCol, errorCol, enqueuedAtCol).
+		From(queueTableName)
+}
+
+func scanBatches(rows *sql.Rows) ([]*Batch, error) {
+	batches := []*Batch{}
+	for rows.Next() {
+		batch := &Batch{}
+		var marshaledStates []byte
+		var enqueuedAt int64
+		err := rows.Scan(&batch.ID, &batch.IndexerID, &batch.NetworkID, &marshaledStates, &batch.Attempts, &batch.Error, &enqueuedAt)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan state batch row")
+		}
+		batch.States, err = unmarshalStates(marshaledStates)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unmarshal states of batch %s", batch.ID)
+		}
+		batch.EnqueuedAt = time.Unix(enqueuedAt, 0)
+		batches = append(batches, batch)
+	}
+	err := rows.Err()
+	if err != nil {
+		return nil, errors.Wrap(err, "sql rows err")
+	}
+	return batches, nil
+}
+
+// batchState is the stored form of a state in a batch, since
+// SerializedStatesByID doesn't have a JSON encoding.
+type batchState struct {
+	Type     string
+	DeviceID string
+	State    state_types.SerializedState
+}
+
+func marshalStates(states state_types.SerializedStatesByID) ([]byte, error) {
+	var toMarshal []batchState
+	for id, st := range states {
+		toMarshal = append(toMarshal, batchState{Type: id.Type, DeviceID: id.DeviceID, State: st})
+	}
+	marshaled, err := json.Marshal(toMarshal)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal states")
+	}
+	return marshaled, nil
+}
+
+func unmarshalStates(marshaled []byte) (state_types.SerializedStatesByID, error) {
+	var unmarshaled []batchState
+	err := json.Unmarshal(marshaled, &unmarshaled)
+	if err != nil {
+		return nil, err
+	}
+	states := state_types.SerializedStatesByID{}
+	for _, st := range unmarshaled {
+		states[state_types.ID{Type: st.Type, DeviceID: st.DeviceID}] = st.State
+	}
+	return states, nil
+}
@@ -0,0 +1,366 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/state/indexer/delivery"
+	state_types "magma/orc8r/cloud/go/services/state/types"
+	"magma/orc8r/cloud/go/sqorc"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	maxAttempts = 3
+
+	id0 = "some_indexerid_0"
+	id1 = "some_indexerid_1"
+
+	nid0 = "some_networkid_0"
+)
+
+var (
+	someErr = errors.New("some_error")
+
+	states = state_types.SerializedStatesByID{
+		{Type: "type0", DeviceID: "key0"}: {SerializedReportedState: []byte("value0"), ReporterID: "hwid0", Version: 1},
+		{Type: "type1", DeviceID: "key1"}: {SerializedReportedState: []byte("value1"), ReporterID: "hwid1", Version: 2},
+	}
+)
+
+func TestSQLQueue(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	q := initTestQueue(t)
+
+	// Empty queue
+	batches, err := q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	assert.Empty(t, batches)
+	stats, err := q.GetStats()
+	assert.NoError(t, err)
+	assert.Empty(t, stats)
+
+	// Enqueue batches for two indexers
+	err = q.Enqueue(id0, nid0, states, someErr)
+	assert.NoError(t, err)
+	err = q.Enqueue(id1, nid0, states, someErr)
+	assert.NoError(t, err)
+
+	stats, err = q.GetStats()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]delivery.Stats{
+		id0: {Pending: 1, OldestPending: time.Unix(1000, 0)},
+		id1: {Pending: 1, OldestPending: time.Unix(1000, 0)},
+	}, stats)
+
+	// Batches not due before backoff elapses
+	batches, err = q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	assert.Empty(t, batches)
+
+	// Batches due after backoff, and not re-claimable while claimed
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(time.Minute))
+	batches, err = q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	require.Len(t, batches, 2)
+	for _, b := range batches {
+		assert.Equal(t, nid0, b.NetworkID)
+		assert.Equal(t, uint(1), b.Attempts)
+		assert.Equal(t, someErr.Error(), b.Error)
+		assert.Equal(t, states, b.States)
+		assert.Equal(t, time.Unix(1000, 0), b.EnqueuedAt)
+	}
+	again, err := q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	assert.Empty(t, again)
+
+	// Successful delivery removes the batch, failed delivery keeps it
+	b0, b1 := batches[0], batches[1]
+	if b0.IndexerID != id0 {
+		b0, b1 = b1, b0
+	}
+	err = q.CompleteBatch(b0, nil)
+	assert.NoError(t, err)
+	err = q.CompleteBatch(b1, someErr)
+	assert.NoError(t, err)
+
+	stats, err = q.GetStats()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]delivery.Stats{
+		id1: {Pending: 1, OldestPending: time.Unix(1000, 0)},
+	}, stats)
+
+	// Exhaust attempts
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(time.Hour))
+	batches, err = q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, uint(2), batches[0].Attempts)
+	err = q.CompleteBatch(batches[0], someErr)
+	assert.NoError(t, err)
+
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(2*time.Hour))
+	batches, err = q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	assert.Empty(t, batches)
+
+	stats, err = q.GetStats()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]delivery.Stats{id1: {Failed: 1}}, stats)
+
+	failed, err := q.GetFailedBatches("")
+	assert.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, b1.ID, failed[0].ID)
+	assert.Equal(t, uint(maxAttempts), failed[0].Attempts)
+	failed, err = q.GetFailedBatches(id0)
+	assert.NoError(t, err)
+	assert.Empty(t, failed)
+
+	// Replay
+	_, err = q.ReplayFailedBatches("", nil)
+	assert.Error(t, err)
+	n, err := q.ReplayFailedBatches(id1, []string{"some_other_batch"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), n)
+	n, err = q.ReplayFailedBatches(id1, []string{b1.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), n)
+
+	batches, err = q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, b1.ID, batches[0].ID)
+	err = q.CompleteBatch(batches[0], nil)
+	assert.NoError(t, err)
+
+	stats, err = q.GetStats()
+	assert.NoError(t, err)
+	assert.Empty(t, stats)
+}
+
+func TestSQLQueue_Coalesce(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	q := initTestQueue(t)
+
+	key0 := state_types.ID{Type: "type0", DeviceID: "key0"}
+	key1 := state_types.ID{Type: "type1", DeviceID: "key1"}
+	old := state_types.SerializedStatesByID{
+		key0: {SerializedReportedState: []byte("value0"), TimeMs: 10},
+		key1: {SerializedReportedState: []byte("value1"), TimeMs: 10},
+	}
+	err := q.Enqueue(id0, nid0, old, someErr)
+	assert.NoError(t, err)
+	err = q.Enqueue(id1, nid0, old, someErr)
+	assert.NoError(t, err)
+
+	// Newer version replaces the queued version of the indexer's state
+	newer := state_types.SerializedStatesByID{
+		key0: {SerializedReportedState: []byte("value0_newer"), TimeMs: 20},
+	}
+	err = q.Enqueue(id0, nid0, newer, someErr)
+	assert.NoError(t, err)
+
+	// Older version is dropped
+	older := state_types.SerializedStatesByID{
+		key0: {SerializedReportedState: []byte("value0_older"), TimeMs: 5},
+	}
+	err = q.Enqueue(id0, nid0, older, someErr)
+	assert.NoError(t, err)
+
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(time.Minute))
+	batches, err := q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	got := map[string][]state_types.SerializedStatesByID{}
+	for _, b := range batches {
+		got[b.IndexerID] = append(got[b.IndexerID], b.States)
+	}
+	assert.ElementsMatch(t, []state_types.SerializedStatesByID{
+		{key1: old[key1]},
+		newer,
+	}, got[id0])
+	assert.Equal(t, []state_types.SerializedStatesByID{old}, got[id1])
+	for _, b := range batches {
+		err = q.CompleteBatch(b, someErr)
+		assert.NoError(t, err)
+	}
+
+	// Newer version indexed outside the queue supersedes the queued version,
+	// deleting emptied batches, including failed ones
+	for i := 0; i < maxAttempts; i++ {
+		clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(time.Duration(i+2)*time.Hour))
+		batches, err = q.ClaimDueBatches(10)
+		assert.NoError(t, err)
+		for _, b := range batches {
+			err = q.CompleteBatch(b, someErr)
+			assert.NoError(t, err)
+		}
+	}
+	stats, err := q.GetStats()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]delivery.Stats{id0: {Failed: 2}, id1: {Failed: 1}}, stats)
+
+	indexed := state_types.SerializedStatesByID{
+		key0: {SerializedReportedState: []byte("value0_indexed"), TimeMs: 30},
+		key1: {SerializedReportedState: []byte("value1_indexed"), TimeMs: 30},
+	}
+	err = q.MarkIndexed(id0, nid0, indexed)
+	assert.NoError(t, err)
+	err = q.MarkIndexed(id1, nid0, state_types.SerializedStatesByID{key1: indexed[key1]})
+	assert.NoError(t, err)
+
+	stats, err = q.GetStats()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]delivery.Stats{id1: {Failed: 1}}, stats)
+
+	// Replay only delivers states which weren't superseded
+	n, err := q.ReplayFailedBatches(id1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), n)
+	batches, err = q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, state_types.SerializedStatesByID{key0: old[key0]}, batches[0].States)
+	err = q.CompleteBatch(batches[0], nil)
+	assert.NoError(t, err)
+
+	// Delivered states no longer supersede later versions
+	err = q.Enqueue(id1, nid0, state_types.SerializedStatesByID{key0: old[key0]}, someErr)
+	assert.NoError(t, err)
+	stats, err = q.GetStats()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]delivery.Stats{id1: {Pending: 1, OldestPending: clock.Now()}}, stats)
+}
+
+func TestSQLQueue_ClaimTimeout(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	q := initTestQueue(t)
+
+	err := q.Enqueue(id0, nid0, states, someErr)
+	assert.NoError(t, err)
+
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(time.Minute))
+	batches, err := q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	require.Len(t, batches, 1)
+
+	// Abandoned claim becomes due again
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(time.Hour))
+	again, err := q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	require.Len(t, again, 1)
+	assert.Equal(t, batches[0].ID, again[0].ID)
+}
+
+func TestSQLQueue_ClaimSuperseded(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	q, db := initTestQueueWithDB(t)
+
+	key1 := state_types.ID{Type: "type1", DeviceID: "key1"}
+	err := q.Enqueue(id0, nid0, states, someErr)
+	assert.NoError(t, err)
+	err = q.MarkIndexed(id1, nid0, states)
+	assert.NoError(t, err)
+
+	// Keys are only held by batches
+	var n int
+	err = db.QueryRow("SELECT COUNT(*) FROM indexer_delivery_queue_keys WHERE batch_id = ''").Scan(&n)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	// States whose keys moved to another batch aren't delivered
+	_, err = db.Exec("UPDATE indexer_delivery_queue_keys SET batch_id = 'other' WHERE device_id = 'key0'")
+	require.NoError(t, err)
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(time.Minute))
+	batches, err := q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, state_types.SerializedStatesByID{key1: states[key1]}, batches[0].States)
+	err = q.CompleteBatch(batches[0], someErr)
+	assert.NoError(t, err)
+
+	// Batches left without states are deleted
+	_, err = db.Exec("UPDATE indexer_delivery_queue_keys SET batch_id = 'other' WHERE device_id = 'key1'")
+	require.NoError(t, err)
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(time.Hour))
+	batches, err = q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	assert.Empty(t, batches)
+	stats, err := q.GetStats()
+	assert.NoError(t, err)
+	assert.Empty(t, stats)
+}
+
+func TestSQLQueue_DropSupersededStates(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+	q := initTestQueue(t)
+
+	key0 := state_types.ID{Type: "type0", DeviceID: "key0"}
+	key1 := state_types.ID{Type: "type1", DeviceID: "key1"}
+	err := q.Enqueue(id0, nid0, states, someErr)
+	assert.NoError(t, err)
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(time.Minute))
+	batches, err := q.ClaimDueBatches(10)
+	assert.NoError(t, err)
+	require.Len(t, batches, 1)
+	batch := batches[0]
+
+	// Newer versions indexed after the batch was claimed aren't delivered
+	newer := states[key0]
+	newer.Version++
+	err = q.MarkIndexed(id0, nid0, state_types.SerializedStatesByID{key0: newer})
+	assert.NoError(t, err)
+	err = q.DropSupersededStates(batch)
+	assert.NoError(t, err)
+	assert.Equal(t, state_types.SerializedStatesByID{key1: states[key1]}, batch.States)
+
+	// Batches left without states are deleted
+	newer = states[key1]
+	newer.Version++
+	err = q.MarkIndexed(id0, nid0, state_types.SerializedStatesByID{key1: newer})
+	assert.NoError(t, err)
+	err = q.DropSupersededStates(batch)
+	assert.NoError(t, err)
+	assert.Empty(t, batch.States)
+	stats, err := q.GetStats()
+	assert.NoError(t, err)
+	assert.Empty(t, stats)
+}
+
+func initTestQueue(t *testing.T) delivery.Queue {
+	q, _ := initTestQueueWithDB(t)
+	return q
+}
+
+func initTestQueueWithDB(t *testing.T) (delivery.Queue, *sql.DB) {
+	db, err := sqorc.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	q := delivery.NewSQLQueue(maxAttempts, db, sqorc.NewSQLiteStatementBuilder())
+	err = q.Initialize()
+	require.NoError(t, err)
+	return q, db
+}
@@ -65,21 +65,44 @@ func MustIndex(networkID string, states state_types.SerializedStatesByID) {
 //	- returns after all goroutines have completed
 // Prefer MustIndex except where receiving the returned errors is relevant.
 func Index(networkID string, states state_types.SerializedStatesByID) ([]error, error) {
-	index := func(indexers chan indexer.Indexer, out chan error) {
+	errsByID, err := indexAll(networkID, states, maxRetry)
+	if err != nil {
+		return nil, err
+	}
+	var indexErrs []error
+	for _, e := range errsByID {
+		indexErrs = append(indexErrs, e)
+	}
+	return indexErrs, nil
+}
+
+// IndexOnce makes one index call per indexer, via worker goroutines.
+// Returns errors keyed by the ID of the failed indexer, after all
+// goroutines have completed.
+func IndexOnce(networkID string, states state_types.SerializedStatesByID) (map[string]error, error) {
+	return indexAll(networkID, states, 1)
+}
+
+func indexAll(networkID string, states state_types.SerializedStatesByID, attempts int) (map[string]error, error) {
+	type indexResult struct {
+		id  string
+		err error
+	}
+	index := func(indexers chan indexer.Indexer, out chan indexResult) {
 		for x := range indexers {
 			var indexErr error
-			for i := 0; i < maxRetry; i++ {
-				indexErr = indexOne(networkID, x, states)
-				if indexErr == nil {
+			for i := 0; i < attempts; i++ {
+				indexErr = IndexOne(networkID, x, states)
+				if indexErr == nil || i == attempts-1 {
 					break
 				}
 				clock.Sleep(defaultIndexSleep)
 			}
-			out <- indexErr
+			out <- indexResult{id: x.GetID(), err: indexErr}
 		}
 	}
 	in := make(chan indexer.Indexer)
-	out := make(chan error)
+	out := make(chan indexResult)
 	for i := 0; i < nIndexWorkers; i++ {
 		go index(in, out)
 	}
@@ -95,17 +118,20 @@ func Index(networkID string, states state_types.SerializedStatesByID) ([]error,
 		close(in)
 	}()
 
-	var indexErrs []error
+	indexErrs := map[string]error{}
 	for i := 0; i < len(indexers); i++ {
-		if e := <-out; e != nil {
-			indexErrs = append(indexErrs, e)
+		if r := <-out; r.err != nil {
+			indexErrs[r.id] = r.err
 		}
 	}
 
 	return indexErrs, nil
 }
 
-func indexOne(networkID string, idx indexer.Indexer, states state_types.SerializedStatesByID) error {
+// IndexOne forwards states to an indexer, according to its subscriptions.
+// Per-state indexing errors are logged and reported as metrics, and only
+// returned when every state experienced an error.
+func IndexOne(networkID string, idx indexer.Indexer, states state_types.SerializedStatesByID) error {
 	filtered := states.Filter(idx.GetTypes()...)
 	if len(filtered) == 0 {
 		return nil
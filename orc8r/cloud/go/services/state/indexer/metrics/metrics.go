@@ -80,4 +80,32 @@ var (
 		},
 		[]string{IndexerNameLabel},
 	)
+	DeliveryPending = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "stateindexer_delivery_pending_batches",
+			Help: "Number of state batches awaiting retried delivery to indexer",
+		},
+		[]string{IndexerNameLabel},
+	)
+	DeliveryFailed = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "stateindexer_delivery_failed_batches",
+			Help: "Number of state batches whose delivery to indexer failed after all retries",
+		},
+		[]string{IndexerNameLabel},
+	)
+	DeliveryLag = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "stateindexer_delivery_lag_seconds",
+			Help: "Age of indexer's oldest state batch awaiting retried delivery",
+		},
+		[]string{IndexerNameLabel},
+	)
+	DeliveryErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "stateindexer_delivery_errors_count",
+			Help: "Number of failed attempts at delivering state batches to indexer",
+		},
+		[]string{IndexerNameLabel},
+	)
 )
@@ -114,7 +114,8 @@ type sqlJobQueue struct {
 //		  so for now each controller warning-logs either success or failure to write to the job queue, and manual
 //		  inspection of the logs would be required (thankfully, we also have tests to ensure this doesn't happen in the expected case).
 //
-// Only provides Postgres support due to use of the non-standard "FOR UPDATE SKIP LOCKED" clause.
+// Jobs are claimed with "FOR UPDATE SKIP LOCKED" where the SQL dialect supports it. Otherwise
+// claims are serialized by the database-level lock held by each transaction.
 func NewSQLJobQueue(maxAttempts uint, db *sql.DB, builder sqorc.StatementBuilder) JobQueue {
 	return &sqlJobQueue{maxAttempts: maxAttempts, db: db, builder: builder}
 }
@@ -422,7 +423,7 @@ func (s *sqlJobQueue) claimAvailableJob() (*reindexJob, error) {
 		now := clock.Now()
 		timeoutThreshold := now.Add(-defaultJobTimeout)
 
-		rows, err := s.selectAll().
+		selectAvailable := s.selectAll().
 			From(queueTableName).
 			Where(
 				squirrel.And{
@@ -440,8 +441,8 @@ func (s *sqlJobQueue) claimAvailableJob() (*reindexJob, error) {
 					},
 				},
 			).
-			Limit(1).
-			Suffix("FOR UPDATE SKIP LOCKED").
+			Limit(1)
+		rows, err := s.builder.ForUpdateSkipLocked(selectAvailable).
 			RunWith(tx).
 			Query()
 
@@ -20,6 +20,7 @@ limitations under the License.
 package reindex_test
 
 import (
+	"os"
 	"sync"
 	"testing"
 	"time"
@@ -38,6 +39,10 @@ func init() {
 }
 
 func TestSQLReindexJobQueue_Integration_PopulateJobs(t *testing.T) {
+	if os.Getenv("SQL_DRIVER") == sqorc.SQLiteDriver {
+		// sqlite3 serializes tx1 behind tx0 rather than letting it move first
+		t.Skip("requires concurrent transactions")
+	}
 	dbName := "state___reindex_queue___populate_jobs"
 	queue := initSQLTest(t, dbName)
 
@@ -10,6 +10,7 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
+	protos "magma/orc8r/lib/go/protos"
 	math "math"
 )
 
@@ -194,7 +195,12 @@ type IndexerInfo struct {
 	// actual_version is the indexer's current version.
 	ActualVersion uint32 `protobuf:"varint,2,opt,name=actual_version,json=actualVersion,proto3" json:"actual_version,omitempty"`
 	// desired_version is the version to which the indexer will be reindexed.
-	DesiredVersion       uint32   `protobuf:"varint,3,opt,name=desired_version,json=desiredVersion,proto3" json:"desired_version,omitempty"`
+	DesiredVersion uint32 `protobuf:"varint,3,opt,name=desired_version,json=desiredVersion,proto3" json:"desired_version,omitempty"`
+	// pending_batches is the number of state batches awaiting retried delivery.
+	PendingBatches uint32 `protobuf:"varint,4,opt,name=pending_batches,json=pendingBatches,proto3" json:"pending_batches,omitempty"`
+	// failed_batches is the number of state batches which exhausted their
+	// delivery retries.
+	FailedBatches        uint32   `protobuf:"varint,5,opt,name=failed_batches,json=failedBatches,proto3" json:"failed_batches,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -246,6 +252,282 @@ func (m *IndexerInfo) GetDesiredVersion() uint32 {
 	return 0
 }
 
+func (m *IndexerInfo) GetPendingBatches() uint32 {
+	if m != nil {
+		return m.PendingBatches
+	}
+	return 0
+}
+
+func (m *IndexerInfo) GetFailedBatches() uint32 {
+	if m != nil {
+		return m.FailedBatches
+	}
+	return 0
+}
+
+type GetFailedBatchesRequest struct {
+	// indexer_id is the ID of the indexer whose failed batches to get.
+	// If indexer_id is empty, gets failed batches of all indexers.
+	IndexerId            string   `protobuf:"bytes,1,opt,name=indexer_id,json=indexerId,proto3" json:"indexer_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetFailedBatchesRequest) Reset()         { *m = GetFailedBatchesRequest{} }
+func (m *GetFailedBatchesRequest) String() string { return proto.CompactTextString(m) }
+func (*GetFailedBatchesRequest) ProtoMessage()    {}
+func (*GetFailedBatchesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bef462805dadd859, []int{5}
+}
+
+func (m *GetFailedBatchesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetFailedBatchesRequest.Unmarshal(m, b)
+}
+func (m *GetFailedBatchesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetFailedBatchesRequest.Marshal(b, m, deterministic)
+}
+func (m *GetFailedBatchesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetFailedBatchesRequest.Merge(m, src)
+}
+func (m *GetFailedBatchesRequest) XXX_Size() int {
+	return xxx_messageInfo_GetFailedBatchesRequest.Size(m)
+}
+func (m *GetFailedBatchesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetFailedBatchesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetFailedBatchesRequest proto.InternalMessageInfo
+
+func (m *GetFailedBatchesRequest) GetIndexerId() string {
+	if m != nil {
+		return m.IndexerId
+	}
+	return ""
+}
+
+type GetFailedBatchesResponse struct {
+	Batches              []*FailedBatch `protobuf:"bytes,1,rep,name=batches,proto3" json:"batches,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *GetFailedBatchesResponse) Reset()         { *m = GetFailedBatchesResponse{} }
+func (m *GetFailedBatchesResponse) String() string { return proto.CompactTextString(m) }
+func (*GetFailedBatchesResponse) ProtoMessage()    {}
+func (*GetFailedBatchesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bef462805dadd859, []int{6}
+}
+
+func (m *GetFailedBatchesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetFailedBatchesResponse.Unmarshal(m, b)
+}
+func (m *GetFailedBatchesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetFailedBatchesResponse.Marshal(b, m, deterministic)
+}
+func (m *GetFailedBatchesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetFailedBatchesResponse.Merge(m, src)
+}
+func (m *GetFailedBatchesResponse) XXX_Size() int {
+	return xxx_messageInfo_GetFailedBatchesResponse.Size(m)
+}
+func (m *GetFailedBatchesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetFailedBatchesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetFailedBatchesResponse proto.InternalMessageInfo
+
+func (m *GetFailedBatchesResponse) GetBatches() []*FailedBatch {
+	if m != nil {
+		return m.Batches
+	}
+	return nil
+}
+
+type ReplayFailedBatchesRequest struct {
+	// indexer_id is the ID of the indexer whose failed batches to replay.
+	IndexerId string `protobuf:"bytes,1,opt,name=indexer_id,json=indexerId,proto3" json:"indexer_id,omitempty"`
+	// batch_ids are the IDs of the batches to replay.
+	// If batch_ids is empty, replays all failed batches of the indexer.
+	BatchIds             []string `protobuf:"bytes,2,rep,name=batch_ids,json=batchIds,proto3" json:"batch_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplayFailedBatchesRequest) Reset()         { *m = ReplayFailedBatchesRequest{} }
+func (m *ReplayFailedBatchesRequest) String() string { return proto.CompactTextString(m) }
+func (*ReplayFailedBatchesRequest) ProtoMessage()    {}
+func (*ReplayFailedBatchesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bef462805dadd859, []int{7}
+}
+
+func (m *ReplayFailedBatchesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplayFailedBatchesRequest.Unmarshal(m, b)
+}
+func (m *ReplayFailedBatchesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplayFailedBatchesRequest.Marshal(b, m, deterministic)
+}
+func (m *ReplayFailedBatchesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplayFailedBatchesRequest.Merge(m, src)
+}
+func (m *ReplayFailedBatchesRequest) XXX_Size() int {
+	return xxx_messageInfo_ReplayFailedBatchesRequest.Size(m)
+}
+func (m *ReplayFailedBatchesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplayFailedBatchesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplayFailedBatchesRequest proto.InternalMessageInfo
+
+func (m *ReplayFailedBatchesRequest) GetIndexerId() string {
+	if m != nil {
+		return m.IndexerId
+	}
+	return ""
+}
+
+func (m *ReplayFailedBatchesRequest) GetBatchIds() []string {
+	if m != nil {
+		return m.BatchIds
+	}
+	return nil
+}
+
+type ReplayFailedBatchesResponse struct {
+	// num_replayed is the number of batches re-enqueued for delivery.
+	NumReplayed          uint32   `protobuf:"varint,1,opt,name=num_replayed,json=numReplayed,proto3" json:"num_replayed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplayFailedBatchesResponse) Reset()         { *m = ReplayFailedBatchesResponse{} }
+func (m *ReplayFailedBatchesResponse) String() string { return proto.CompactTextString(m) }
+func (*ReplayFailedBatchesResponse) ProtoMessage()    {}
+func (*ReplayFailedBatchesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bef462805dadd859, []int{8}
+}
+
+func (m *ReplayFailedBatchesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplayFailedBatchesResponse.Unmarshal(m, b)
+}
+func (m *ReplayFailedBatchesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplayFailedBatchesResponse.Marshal(b, m, deterministic)
+}
+func (m *ReplayFailedBatchesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplayFailedBatchesResponse.Merge(m, src)
+}
+func (m *ReplayFailedBatchesResponse) XXX_Size() int {
+	return xxx_messageInfo_ReplayFailedBatchesResponse.Size(m)
+}
+func (m *ReplayFailedBatchesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplayFailedBatchesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplayFailedBatchesResponse proto.InternalMessageInfo
+
+func (m *ReplayFailedBatchesResponse) GetNumReplayed() uint32 {
+	if m != nil {
+		return m.NumReplayed
+	}
+	return 0
+}
+
+// FailedBatch is a batch of states whose delivery to an indexer failed.
+type FailedBatch struct {
+	BatchId   string `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	IndexerId string `protobuf:"bytes,2,opt,name=indexer_id,json=indexerId,proto3" json:"indexer_id,omitempty"`
+	NetworkId string `protobuf:"bytes,3,opt,name=network_id,json=networkId,proto3" json:"network_id,omitempty"`
+	// state_ids are the IDs of the states in the batch.
+	StateIds []*protos.StateID `protobuf:"bytes,4,rep,name=state_ids,json=stateIds,proto3" json:"state_ids,omitempty"`
+	// attempts is the number of delivery attempts made.
+	Attempts uint32 `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// error is the error from the most recent delivery attempt.
+	Error string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	// enqueued_at is the unix time, in seconds, at which the batch was enqueued.
+	EnqueuedAt           int64    `protobuf:"varint,7,opt,name=enqueued_at,json=enqueuedAt,proto3" json:"enqueued_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FailedBatch) Reset()         { *m = FailedBatch{} }
+func (m *FailedBatch) String() string { return proto.CompactTextString(m) }
+func (*FailedBatch) ProtoMessage()    {}
+func (*FailedBatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bef462805dadd859, []int{9}
+}
+
+func (m *FailedBatch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FailedBatch.Unmarshal(m, b)
+}
+func (m *FailedBatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FailedBatch.Marshal(b, m, deterministic)
+}
+func (m *FailedBatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FailedBatch.Merge(m, src)
+}
+func (m *FailedBatch) XXX_Size() int {
+	return xxx_messageInfo_FailedBatch.Size(m)
+}
+func (m *FailedBatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_FailedBatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FailedBatch proto.InternalMessageInfo
+
+func (m *FailedBatch) GetBatchId() string {
+	if m != nil {
+		return m.BatchId
+	}
+	return ""
+}
+
+func (m *FailedBatch) GetIndexerId() string {
+	if m != nil {
+		return m.IndexerId
+	}
+	return ""
+}
+
+func (m *FailedBatch) GetNetworkId() string {
+	if m != nil {
+		return m.NetworkId
+	}
+	return ""
+}
+
+func (m *FailedBatch) GetStateIds() []*protos.StateID {
+	if m != nil {
+		return m.StateIds
+	}
+	return nil
+}
+
+func (m *FailedBatch) GetAttempts() uint32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *FailedBatch) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *FailedBatch) GetEnqueuedAt() int64 {
+	if m != nil {
+		return m.EnqueuedAt
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*GetIndexersRequest)(nil), "magma.orc8r.state.GetIndexersRequest")
 	proto.RegisterType((*GetIndexersResponse)(nil), "magma.orc8r.state.GetIndexersResponse")
@@ -253,6 +535,11 @@ func init() {
 	proto.RegisterType((*StartReindexRequest)(nil), "magma.orc8r.state.StartReindexRequest")
 	proto.RegisterType((*StartReindexResponse)(nil), "magma.orc8r.state.StartReindexResponse")
 	proto.RegisterType((*IndexerInfo)(nil), "magma.orc8r.state.IndexerInfo")
+	proto.RegisterType((*GetFailedBatchesRequest)(nil), "magma.orc8r.state.GetFailedBatchesRequest")
+	proto.RegisterType((*GetFailedBatchesResponse)(nil), "magma.orc8r.state.GetFailedBatchesResponse")
+	proto.RegisterType((*ReplayFailedBatchesRequest)(nil), "magma.orc8r.state.ReplayFailedBatchesRequest")
+	proto.RegisterType((*ReplayFailedBatchesResponse)(nil), "magma.orc8r.state.ReplayFailedBatchesResponse")
+	proto.RegisterType((*FailedBatch)(nil), "magma.orc8r.state.FailedBatch")
 }
 
 func init() {
@@ -260,32 +547,49 @@ func init() {
 }
 
 var fileDescriptor_bef462805dadd859 = []byte{
-	// 391 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0x51, 0xeb, 0xda, 0x30,
-	0x14, 0xc5, 0x97, 0xbf, 0xfc, 0x65, 0xde, 0x6a, 0x37, 0xa3, 0x0c, 0x11, 0x36, 0xa4, 0xe0, 0x94,
-	0x31, 0xda, 0xe1, 0xf6, 0x20, 0x3e, 0x0a, 0x63, 0x74, 0xb0, 0x97, 0x0e, 0xf6, 0xb0, 0x07, 0x4b,
-	0x6c, 0xae, 0x52, 0xa6, 0x8d, 0x4b, 0x52, 0x59, 0x61, 0x5f, 0x73, 0xfb, 0x3c, 0xc3, 0x34, 0x8e,
-	0x8a, 0x05, 0x7d, 0x6a, 0x73, 0xf8, 0xe5, 0x9c, 0xe6, 0x9e, 0x06, 0x16, 0x42, 0x26, 0x73, 0x19,
-	0x24, 0x3b, 0x91, 0xf3, 0x60, 0x2b, 0x02, 0x85, 0xf2, 0x98, 0x26, 0xa8, 0x02, 0xa5, 0x99, 0xc6,
-	0xe0, 0x20, 0x85, 0x16, 0x2a, 0x48, 0x33, 0x8e, 0xbf, 0x50, 0xc6, 0x7b, 0x96, 0xb1, 0x2d, 0x4a,
-	0xdf, 0xc8, 0xb4, 0xbb, 0x67, 0xdb, 0x3d, 0xf3, 0x8d, 0x83, 0x6f, 0x78, 0xaf, 0x0f, 0xf4, 0x13,
-	0xea, 0xb0, 0xc4, 0x55, 0x84, 0x3f, 0x73, 0x54, 0xda, 0xfb, 0x4b, 0xa0, 0x77, 0x21, 0xab, 0x83,
-	0xc8, 0x14, 0xd2, 0x15, 0xb8, 0xd6, 0x59, 0xc5, 0xeb, 0x22, 0x4e, 0xf9, 0x80, 0x8c, 0x1a, 0x53,
-	0x67, 0x36, 0xf7, 0xaf, 0x9c, 0xfd, 0x9a, 0xfd, 0xfe, 0x59, 0x58, 0x16, 0x21, 0xff, 0x98, 0x69,
-	0x59, 0x44, 0xed, 0xb4, 0x22, 0x0d, 0x63, 0xe8, 0x5e, 0x21, 0xf4, 0x39, 0x34, 0x7e, 0x60, 0x31,
-	0x20, 0x23, 0x32, 0x6d, 0x45, 0xa7, 0x57, 0xfa, 0x01, 0x1e, 0x8f, 0x6c, 0x97, 0xe3, 0xe0, 0x61,
-	0x44, 0xa6, 0xce, 0xec, 0x55, 0x4d, 0xba, 0xb5, 0x09, 0xb3, 0x8d, 0x88, 0x4a, 0x78, 0xf1, 0x30,
-	0x27, 0xde, 0x67, 0xe8, 0x7d, 0xd5, 0x4c, 0xea, 0x08, 0x4d, 0xae, 0x3d, 0x2f, 0x7d, 0x09, 0x70,
-	0x9e, 0x98, 0x39, 0xd3, 0x29, 0xa9, 0x65, 0x95, 0x90, 0xd3, 0x3e, 0x3c, 0x6e, 0x84, 0x4c, 0xca,
-	0xbc, 0xa7, 0x51, 0xb9, 0xf0, 0x7c, 0xe8, 0x5f, 0x7a, 0xd9, 0x21, 0xbd, 0x80, 0x66, 0x7e, 0xe0,
-	0x4c, 0xa3, 0x35, 0xb2, 0x2b, 0xef, 0x37, 0x38, 0x95, 0xaf, 0xba, 0x95, 0x39, 0x06, 0x97, 0x25,
-	0x3a, 0x67, 0xbb, 0xf8, 0x88, 0x52, 0xa5, 0x22, 0x33, 0xe1, 0x9d, 0xa8, 0x53, 0xaa, 0xdf, 0x4a,
-	0x91, 0x4e, 0xe0, 0x19, 0x47, 0x95, 0x4a, 0xe4, 0xff, 0xb9, 0x86, 0xe1, 0x5c, 0x2b, 0x5b, 0x70,
-	0xf6, 0x87, 0x80, 0x6b, 0xe3, 0xbf, 0x94, 0x3f, 0x05, 0x5d, 0x81, 0x53, 0x29, 0x89, 0x8e, 0x6f,
-	0x95, 0x68, 0x66, 0x35, 0x7c, 0x7d, 0x5f, 0xd7, 0xde, 0x13, 0x9a, 0x40, 0xbb, 0x3a, 0x20, 0x5a,
-	0xb7, 0xb3, 0xa6, 0x8d, 0xe1, 0xe4, 0x26, 0x77, 0x8e, 0x78, 0x47, 0x96, 0x6f, 0xbf, 0xbf, 0x31,
-	0x74, 0x70, 0xcf, 0xbd, 0x58, 0x37, 0xcd, 0xf3, 0xfd, 0xbf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x2d,
-	0x2a, 0xbd, 0x7e, 0x46, 0x03, 0x00, 0x00,
+	// 664 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xdf, 0x6e, 0xd3, 0x3e,
+	0x14, 0xfe, 0xa5, 0xdd, 0x9f, 0xf6, 0x74, 0xeb, 0x6f, 0xf3, 0x2a, 0x08, 0x99, 0x06, 0x25, 0xd2,
+	0x58, 0x35, 0x20, 0x85, 0xc1, 0x45, 0xb5, 0x2b, 0x98, 0x80, 0x29, 0x48, 0xdc, 0x78, 0x08, 0x21,
+	0x2e, 0x16, 0x79, 0xf5, 0x59, 0x89, 0xd6, 0x26, 0x9d, 0xed, 0x14, 0xfa, 0x6e, 0x3c, 0x02, 0xd7,
+	0x3c, 0x05, 0x0f, 0x81, 0x6a, 0x3b, 0x55, 0xb6, 0x05, 0xad, 0xe2, 0xaa, 0xf5, 0xe7, 0xef, 0x7c,
+	0xdf, 0xf1, 0xf1, 0xf1, 0x09, 0x1c, 0xa6, 0xa2, 0xdf, 0x13, 0xdd, 0xfe, 0x30, 0xcd, 0x78, 0x77,
+	0x90, 0x76, 0x25, 0x8a, 0x49, 0xdc, 0x47, 0xd9, 0x95, 0x8a, 0x29, 0xec, 0x8e, 0x45, 0xaa, 0x52,
+	0xd9, 0x8d, 0x13, 0x8e, 0xdf, 0x51, 0x44, 0x23, 0x96, 0xb0, 0x01, 0x8a, 0x40, 0xc3, 0x64, 0x73,
+	0xc4, 0x06, 0x23, 0x16, 0x68, 0x85, 0x40, 0xf3, 0x3d, 0xd7, 0xc8, 0xd9, 0x30, 0x8d, 0x19, 0xb2,
+	0xdf, 0x02, 0x72, 0x8c, 0x2a, 0x34, 0x42, 0x92, 0xe2, 0x65, 0x86, 0x52, 0xf9, 0xbf, 0x1c, 0xd8,
+	0xba, 0x02, 0xcb, 0x71, 0x9a, 0x48, 0x24, 0xa7, 0xd0, 0xb4, 0x9e, 0x32, 0x3a, 0x9b, 0x46, 0x31,
+	0x77, 0x9d, 0x76, 0xb5, 0xd3, 0x38, 0xe8, 0x05, 0x37, 0x3c, 0x83, 0x92, 0xf8, 0x20, 0x07, 0x8e,
+	0xa6, 0x21, 0x7f, 0x9b, 0x28, 0x31, 0xa5, 0x6b, 0x71, 0x01, 0xf2, 0x22, 0xd8, 0xbc, 0x41, 0x21,
+	0x1b, 0x50, 0xbd, 0xc0, 0xa9, 0xeb, 0xb4, 0x9d, 0x4e, 0x9d, 0xce, 0xfe, 0x92, 0x97, 0xb0, 0x3c,
+	0x61, 0xc3, 0x0c, 0xdd, 0x4a, 0xdb, 0xe9, 0x34, 0x0e, 0xee, 0x97, 0xb8, 0x5b, 0x99, 0x30, 0x39,
+	0x4f, 0xa9, 0x21, 0x1f, 0x56, 0x7a, 0x8e, 0xff, 0x1e, 0xb6, 0x4e, 0x14, 0x13, 0x8a, 0xa2, 0xf6,
+	0xb5, 0xe7, 0x25, 0x3b, 0x00, 0x79, 0x2d, 0x63, 0x6e, 0x9d, 0xea, 0x16, 0x09, 0x39, 0x69, 0xc1,
+	0xf2, 0x79, 0x2a, 0xfa, 0xc6, 0xaf, 0x46, 0xcd, 0xc2, 0x0f, 0xa0, 0x75, 0x55, 0xcb, 0x16, 0xe9,
+	0x0e, 0xac, 0x64, 0x63, 0xce, 0x14, 0x5a, 0x21, 0xbb, 0xf2, 0x7f, 0x3a, 0xd0, 0x28, 0xa4, 0x75,
+	0x9b, 0xe9, 0x2e, 0x34, 0x59, 0x5f, 0x65, 0x6c, 0x18, 0x4d, 0x50, 0xc8, 0x38, 0x4d, 0xb4, 0xfb,
+	0x3a, 0x5d, 0x37, 0xe8, 0x27, 0x03, 0x92, 0x3d, 0xf8, 0x9f, 0xa3, 0x8c, 0x05, 0xf2, 0x39, 0xaf,
+	0xaa, 0x79, 0x4d, 0x0b, 0x17, 0x88, 0x63, 0x4c, 0x78, 0x9c, 0x0c, 0xa2, 0x33, 0xa6, 0xfa, 0x5f,
+	0x51, 0xba, 0x4b, 0x86, 0x68, 0xe1, 0x23, 0x83, 0xce, 0x8c, 0xcf, 0x59, 0x3c, 0x44, 0x3e, 0xe7,
+	0x2d, 0x1b, 0x63, 0x83, 0x5a, 0x9a, 0xdf, 0x83, 0xbb, 0xc7, 0xa8, 0xde, 0x15, 0xb1, 0xc5, 0xca,
+	0xe9, 0x7f, 0x04, 0xf7, 0x66, 0xa4, 0x2d, 0x5e, 0x0f, 0x56, 0x73, 0x57, 0xd3, 0x5a, 0x65, 0x97,
+	0x5b, 0x08, 0xa5, 0x39, 0xdd, 0xff, 0x0c, 0x1e, 0xc5, 0xf1, 0x90, 0x4d, 0xff, 0x21, 0x25, 0xb2,
+	0x0d, 0x75, 0xad, 0x13, 0xc5, 0x5c, 0xba, 0x95, 0x76, 0xb5, 0x53, 0xa7, 0x35, 0x0d, 0x84, 0x5c,
+	0xfa, 0xaf, 0x60, 0xbb, 0x54, 0xd9, 0xa6, 0xfc, 0x10, 0xd6, 0x92, 0x6c, 0x14, 0x09, 0x4d, 0x41,
+	0x23, 0xbe, 0x4e, 0x1b, 0x49, 0x36, 0xa2, 0x16, 0xf2, 0x7f, 0x3b, 0xd0, 0x28, 0x04, 0x93, 0x7b,
+	0x50, 0xcb, 0xed, 0x6c, 0x2e, 0xab, 0xd6, 0xed, 0x5a, 0xa2, 0x95, 0xeb, 0x89, 0xee, 0x00, 0x24,
+	0xa8, 0xbe, 0xa5, 0xe2, 0x62, 0xb6, 0x5d, 0x35, 0xdb, 0x16, 0x09, 0x39, 0x79, 0x0e, 0x75, 0x5d,
+	0x22, 0x7d, 0x8e, 0x25, 0x5d, 0xc0, 0xd6, 0x95, 0x02, 0x9e, 0xcc, 0x76, 0xc3, 0x37, 0xb4, 0xa6,
+	0x69, 0x21, 0x97, 0xc4, 0x83, 0x1a, 0x53, 0x0a, 0x47, 0x63, 0x95, 0x5f, 0xf4, 0x7c, 0x3d, 0x6b,
+	0x7c, 0x14, 0x22, 0x15, 0xee, 0x8a, 0x36, 0x32, 0x0b, 0xf2, 0x00, 0x1a, 0x98, 0x5c, 0x66, 0x98,
+	0x21, 0x8f, 0x98, 0x72, 0x57, 0xdb, 0x4e, 0xa7, 0x4a, 0x21, 0x87, 0x5e, 0xab, 0x83, 0x1f, 0x55,
+	0x68, 0xda, 0x4e, 0xff, 0x60, 0x46, 0x13, 0x39, 0x85, 0x46, 0x61, 0x20, 0x90, 0xdd, 0xdb, 0x06,
+	0x86, 0xbe, 0x35, 0xef, 0xd1, 0x62, 0x73, 0xc5, 0xff, 0x8f, 0xf4, 0x61, 0xad, 0xf8, 0x18, 0x49,
+	0x59, 0x64, 0xc9, 0xcb, 0xf7, 0xf6, 0x6e, 0xe5, 0xe5, 0x16, 0xcf, 0x1c, 0x32, 0x82, 0x8d, 0xeb,
+	0x8d, 0x4b, 0xf6, 0xcb, 0x53, 0x2c, 0x6b, 0x42, 0xef, 0xf1, 0x42, 0xdc, 0xf9, 0x99, 0x26, 0xb0,
+	0x55, 0xd2, 0x77, 0xe4, 0x69, 0x89, 0xca, 0xdf, 0x3b, 0xdf, 0x0b, 0x16, 0xa5, 0xe7, 0xbe, 0x47,
+	0x4f, 0xbe, 0xec, 0xeb, 0x90, 0xee, 0x22, 0x1f, 0xa1, 0xb3, 0x15, 0xfd, 0xfb, 0xe2, 0xcf, 0x00,
+	0x8b, 0x53, 0x28, 0x94, 0xb3, 0x06, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -305,6 +609,11 @@ type IndexerManagerClient interface {
 	// StartReindex kicks off any required reindex jobs for some or all indexers.
 	// Blocks till reindex job returns, streaming loggable updates.
 	StartReindex(ctx context.Context, in *StartReindexRequest, opts ...grpc.CallOption) (IndexerManager_StartReindexClient, error)
+	// GetFailedBatches returns the state batches whose delivery to an indexer
+	// exhausted their retries.
+	GetFailedBatches(ctx context.Context, in *GetFailedBatchesRequest, opts ...grpc.CallOption) (*GetFailedBatchesResponse, error)
+	// ReplayFailedBatches re-enqueues failed state batches for delivery.
+	ReplayFailedBatches(ctx context.Context, in *ReplayFailedBatchesRequest, opts ...grpc.CallOption) (*ReplayFailedBatchesResponse, error)
 }
 
 type indexerManagerClient struct {
@@ -356,6 +665,24 @@ func (x *indexerManagerStartReindexClient) Recv() (*StartReindexResponse, error)
 	return m, nil
 }
 
+func (c *indexerManagerClient) GetFailedBatches(ctx context.Context, in *GetFailedBatchesRequest, opts ...grpc.CallOption) (*GetFailedBatchesResponse, error) {
+	out := new(GetFailedBatchesResponse)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.state.IndexerManager/GetFailedBatches", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexerManagerClient) ReplayFailedBatches(ctx context.Context, in *ReplayFailedBatchesRequest, opts ...grpc.CallOption) (*ReplayFailedBatchesResponse, error) {
+	out := new(ReplayFailedBatchesResponse)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.state.IndexerManager/ReplayFailedBatches", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // IndexerManagerServer is the server API for IndexerManager service.
 type IndexerManagerServer interface {
 	// GetIndexers returns indexer info for all tracked indexers.
@@ -363,6 +690,11 @@ type IndexerManagerServer interface {
 	// StartReindex kicks off any required reindex jobs for some or all indexers.
 	// Blocks till reindex job returns, streaming loggable updates.
 	StartReindex(*StartReindexRequest, IndexerManager_StartReindexServer) error
+	// GetFailedBatches returns the state batches whose delivery to an indexer
+	// exhausted their retries.
+	GetFailedBatches(context.Context, *GetFailedBatchesRequest) (*GetFailedBatchesResponse, error)
+	// ReplayFailedBatches re-enqueues failed state batches for delivery.
+	ReplayFailedBatches(context.Context, *ReplayFailedBatchesRequest) (*ReplayFailedBatchesResponse, error)
 }
 
 // UnimplementedIndexerManagerServer can be embedded to have forward compatible implementations.
@@ -375,6 +707,12 @@ func (*UnimplementedIndexerManagerServer) GetIndexers(ctx context.Context, req *
 func (*UnimplementedIndexerManagerServer) StartReindex(req *StartReindexRequest, srv IndexerManager_StartReindexServer) error {
 	return status.Errorf(codes.Unimplemented, "method StartReindex not implemented")
 }
+func (*UnimplementedIndexerManagerServer) GetFailedBatches(ctx context.Context, req *GetFailedBatchesRequest) (*GetFailedBatchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFailedBatches not implemented")
+}
+func (*UnimplementedIndexerManagerServer) ReplayFailedBatches(ctx context.Context, req *ReplayFailedBatchesRequest) (*ReplayFailedBatchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayFailedBatches not implemented")
+}
 
 func RegisterIndexerManagerServer(s *grpc.Server, srv IndexerManagerServer) {
 	s.RegisterService(&_IndexerManager_serviceDesc, srv)
@@ -419,6 +757,42 @@ func (x *indexerManagerStartReindexServer) Send(m *StartReindexResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _IndexerManager_GetFailedBatches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFailedBatchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexerManagerServer).GetFailedBatches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.state.IndexerManager/GetFailedBatches",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexerManagerServer).GetFailedBatches(ctx, req.(*GetFailedBatchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IndexerManager_ReplayFailedBatches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayFailedBatchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexerManagerServer).ReplayFailedBatches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.state.IndexerManager/ReplayFailedBatches",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexerManagerServer).ReplayFailedBatches(ctx, req.(*ReplayFailedBatchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _IndexerManager_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "magma.orc8r.state.IndexerManager",
 	HandlerType: (*IndexerManagerServer)(nil),
@@ -427,6 +801,14 @@ var _IndexerManager_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetIndexers",
 			Handler:    _IndexerManager_GetIndexers_Handler,
 		},
+		{
+			MethodName: "GetFailedBatches",
+			Handler:    _IndexerManager_GetFailedBatches_Handler,
+		},
+		{
+			MethodName: "ReplayFailedBatches",
+			Handler:    _IndexerManager_ReplayFailedBatches_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -17,6 +17,7 @@ import (
 	"context"
 
 	"magma/orc8r/cloud/go/services/state/indexer"
+	"magma/orc8r/cloud/go/services/state/indexer/delivery"
 	"magma/orc8r/cloud/go/services/state/indexer/reindex"
 	indexer_protos "magma/orc8r/cloud/go/services/state/protos"
 	"magma/orc8r/lib/go/protos"
@@ -28,10 +29,13 @@ import (
 type indexerServicer struct {
 	reindexer   reindex.Reindexer
 	autoEnabled bool
+	deliveries  delivery.Queue
 }
 
-func NewIndexerManagerServicer(reindexer reindex.Reindexer, autoReindexEnabled bool) indexer_protos.IndexerManagerServer {
-	return &indexerServicer{reindexer: reindexer, autoEnabled: autoReindexEnabled}
+// NewIndexerManagerServicer returns an indexer manager server.
+// If deliveries is nil, delivery-related RPCs are unavailable.
+func NewIndexerManagerServicer(reindexer reindex.Reindexer, autoReindexEnabled bool, deliveries delivery.Queue) indexer_protos.IndexerManagerServer {
+	return &indexerServicer{reindexer: reindexer, autoEnabled: autoReindexEnabled, deliveries: deliveries}
 }
 
 func (srv *indexerServicer) GetIndexers(ctx context.Context, req *indexer_protos.GetIndexersRequest) (*indexer_protos.GetIndexersResponse, error) {
@@ -44,7 +48,19 @@ func (srv *indexerServicer) GetIndexers(ctx context.Context, req *indexer_protos
 		return nil, internalErr(err, "error getting indexer versions from reindex job queue")
 	}
 
-	ret := &indexer_protos.GetIndexersResponse{IndexersById: indexer.MakeProtoInfos(versions)}
+	infos := indexer.MakeProtoInfos(versions)
+	if srv.deliveries != nil {
+		stats, err := srv.deliveries.GetStats()
+		if err != nil {
+			return nil, internalErr(err, "error getting indexer delivery stats")
+		}
+		for id, info := range infos {
+			info.PendingBatches = uint32(stats[id].Pending)
+			info.FailedBatches = uint32(stats[id].Failed)
+		}
+	}
+
+	ret := &indexer_protos.GetIndexersResponse{IndexersById: infos}
 	return ret, nil
 }
 
@@ -65,6 +81,41 @@ func (srv *indexerServicer) StartReindex(req *indexer_protos.StartReindexRequest
 	return nil
 }
 
+func (srv *indexerServicer) GetFailedBatches(ctx context.Context, req *indexer_protos.GetFailedBatchesRequest) (*indexer_protos.GetFailedBatchesResponse, error) {
+	if err := validateCtx(ctx); err != nil {
+		return nil, err
+	}
+	if err := srv.validateDeliveries(); err != nil {
+		return nil, err
+	}
+
+	batches, err := srv.deliveries.GetFailedBatches(req.IndexerId)
+	if err != nil {
+		return nil, internalErr(err, "error getting failed batches from delivery queue")
+	}
+
+	ret := &indexer_protos.GetFailedBatchesResponse{Batches: delivery.MakeProtoBatches(batches)}
+	return ret, nil
+}
+
+func (srv *indexerServicer) ReplayFailedBatches(ctx context.Context, req *indexer_protos.ReplayFailedBatchesRequest) (*indexer_protos.ReplayFailedBatchesResponse, error) {
+	if err := validateCtx(ctx); err != nil {
+		return nil, err
+	}
+	if err := srv.validateDeliveries(); err != nil {
+		return nil, err
+	}
+	if req.IndexerId == "" {
+		return nil, status.Error(codes.InvalidArgument, "indexer ID must be non-empty")
+	}
+
+	n, err := srv.deliveries.ReplayFailedBatches(req.IndexerId, req.BatchIds)
+	if err != nil {
+		return nil, internalErr(err, "error replaying failed batches in delivery queue")
+	}
+	return &indexer_protos.ReplayFailedBatchesResponse{NumReplayed: uint32(n)}, nil
+}
+
 func validateCtx(ctx context.Context) error {
 	gw := protos.GetClientGateway(ctx)
 	if gw != nil {
@@ -73,6 +124,13 @@ func validateCtx(ctx context.Context) error {
 	return nil
 }
 
+func (srv *indexerServicer) validateDeliveries() error {
+	if srv.deliveries == nil {
+		return status.Error(codes.Unimplemented, "indexer delivery queue not enabled")
+	}
+	return nil
+}
+
 func (srv *indexerServicer) validateReindexReq(req *indexer_protos.StartReindexRequest) error {
 	if srv.autoEnabled && !req.Force {
 		return status.Error(codes.FailedPrecondition, "automatic reindexing is enabled and request didn't override")
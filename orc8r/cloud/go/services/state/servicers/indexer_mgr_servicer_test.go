@@ -16,12 +16,16 @@ package servicers_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"magma/orc8r/cloud/go/services/state/indexer"
+	"magma/orc8r/cloud/go/services/state/indexer/delivery"
+	delivery_mocks "magma/orc8r/cloud/go/services/state/indexer/delivery/mocks"
 	reindex_mocks "magma/orc8r/cloud/go/services/state/indexer/reindex/mocks"
 	indexer_protos "magma/orc8r/cloud/go/services/state/protos"
 	state_proto_mocks "magma/orc8r/cloud/go/services/state/protos/mocks"
 	"magma/orc8r/cloud/go/services/state/servicers"
+	state_types "magma/orc8r/cloud/go/services/state/types"
 	"magma/orc8r/lib/go/protos"
 
 	"github.com/pkg/errors"
@@ -67,7 +71,7 @@ func TestIndexerServicer_GetIndexers(t *testing.T) {
 
 		r := &reindex_mocks.Reindexer{}
 		r.On("GetIndexerVersions").Return(composed, nil)
-		srv := servicers.NewIndexerManagerServicer(r, false)
+		srv := servicers.NewIndexerManagerServicer(r, false, nil)
 
 		got, err := srv.GetIndexers(ctxBlank, &indexer_protos.GetIndexersRequest{})
 		assert.NoError(t, err)
@@ -75,8 +79,31 @@ func TestIndexerServicer_GetIndexers(t *testing.T) {
 		assert.Equal(t, want, got)
 	})
 
+	t.Run("with delivery stats", func(t *testing.T) {
+		composed := []*indexer.Versions{
+			{IndexerID: id0, Actual: version0, Desired: version0},
+			{IndexerID: id1, Actual: version1, Desired: version1},
+		}
+		asProtos := map[string]*indexer_protos.IndexerInfo{
+			id0: {IndexerId: id0, ActualVersion: version0, DesiredVersion: version0, PendingBatches: 2, FailedBatches: 1},
+			id1: {IndexerId: id1, ActualVersion: version1, DesiredVersion: version1},
+		}
+
+		r := &reindex_mocks.Reindexer{}
+		r.On("GetIndexerVersions").Return(composed, nil)
+		q := &delivery_mocks.Queue{}
+		q.On("GetStats").Return(map[string]delivery.Stats{id0: {Pending: 2, Failed: 1}}, nil)
+		srv := servicers.NewIndexerManagerServicer(r, false, q)
+
+		got, err := srv.GetIndexers(ctxBlank, &indexer_protos.GetIndexersRequest{})
+		assert.NoError(t, err)
+		want := &indexer_protos.GetIndexersResponse{IndexersById: asProtos}
+		assert.Equal(t, want, got)
+		q.AssertExpectations(t)
+	})
+
 	t.Run("fail when blankCtx identity is present", func(t *testing.T) {
-		srv := servicers.NewIndexerManagerServicer(&reindex_mocks.Reindexer{}, false)
+		srv := servicers.NewIndexerManagerServicer(&reindex_mocks.Reindexer{}, false, nil)
 
 		_, err := srv.GetIndexers(ctxWithIdentity, &indexer_protos.GetIndexersRequest{})
 		assert.Error(t, err)
@@ -88,7 +115,7 @@ func TestIndexerServicer_GetIndexers(t *testing.T) {
 	t.Run("reindexer err", func(t *testing.T) {
 		r := &reindex_mocks.Reindexer{}
 		r.On("GetIndexerVersions").Return(nil, someErr)
-		srv := servicers.NewIndexerManagerServicer(r, false)
+		srv := servicers.NewIndexerManagerServicer(r, false, nil)
 
 		_, err := srv.GetIndexers(ctxBlank, &indexer_protos.GetIndexersRequest{})
 		assert.Error(t, err)
@@ -105,7 +132,7 @@ func TestIndexerServicer_StartReindex(t *testing.T) {
 		r := &reindex_mocks.Reindexer{}
 		r.On("RunUnsafe", ctxBlank, id0, mock.Anything).Return(nil)
 
-		srv := servicers.NewIndexerManagerServicer(r, false)
+		srv := servicers.NewIndexerManagerServicer(r, false, nil)
 		err := srv.StartReindex(&indexer_protos.StartReindexRequest{IndexerId: id0}, stream)
 		assert.NoError(t, err)
 		r.AssertExpectations(t)
@@ -115,7 +142,7 @@ func TestIndexerServicer_StartReindex(t *testing.T) {
 	t.Run("reindex multiple with override", func(t *testing.T) {
 		r := &reindex_mocks.Reindexer{}
 		r.On("RunUnsafe", ctxBlank, "", mock.Anything).Return(nil)
-		srv := servicers.NewIndexerManagerServicer(r, true)
+		srv := servicers.NewIndexerManagerServicer(r, true, nil)
 
 		err := srv.StartReindex(&indexer_protos.StartReindexRequest{IndexerId: "", Force: true}, stream)
 		assert.NoError(t, err)
@@ -123,7 +150,7 @@ func TestIndexerServicer_StartReindex(t *testing.T) {
 	})
 
 	t.Run("fail when auto reindex enabled", func(t *testing.T) {
-		srv := servicers.NewIndexerManagerServicer(&reindex_mocks.Reindexer{}, true)
+		srv := servicers.NewIndexerManagerServicer(&reindex_mocks.Reindexer{}, true, nil)
 
 		err := srv.StartReindex(&indexer_protos.StartReindexRequest{Force: false}, stream)
 		assert.Error(t, err)
@@ -135,7 +162,7 @@ func TestIndexerServicer_StartReindex(t *testing.T) {
 	t.Run("fail when blankCtx identity is present", func(t *testing.T) {
 		stream := &state_proto_mocks.IndexerManager_StartReindexServer{}
 		stream.On("Context").Return(ctxWithIdentity)
-		srv := servicers.NewIndexerManagerServicer(&reindex_mocks.Reindexer{}, true)
+		srv := servicers.NewIndexerManagerServicer(&reindex_mocks.Reindexer{}, true, nil)
 
 		err := srv.StartReindex(&indexer_protos.StartReindexRequest{}, stream)
 		assert.Error(t, err)
@@ -147,10 +174,101 @@ func TestIndexerServicer_StartReindex(t *testing.T) {
 	t.Run("reindexer err", func(t *testing.T) {
 		r := &reindex_mocks.Reindexer{}
 		r.On("RunUnsafe", ctxBlank, "", mock.Anything).Return(someErr)
-		srv := servicers.NewIndexerManagerServicer(r, false)
+		srv := servicers.NewIndexerManagerServicer(r, false, nil)
 
 		err := srv.StartReindex(&indexer_protos.StartReindexRequest{}, stream)
 		assert.Error(t, err)
 		r.AssertExpectations(t)
 	})
 }
+
+func TestIndexerServicer_GetFailedBatches(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		batches := []*delivery.Batch{
+			{
+				ID:        "batch0",
+				IndexerID: id0,
+				NetworkID: "nid0",
+				States: state_types.SerializedStatesByID{
+					{Type: "t1", DeviceID: "k1"}: {},
+					{Type: "t0", DeviceID: "k0"}: {},
+				},
+				Attempts:   10,
+				Error:      "some_error",
+				EnqueuedAt: time.Unix(1000, 0),
+			},
+		}
+		q := &delivery_mocks.Queue{}
+		q.On("GetFailedBatches", id0).Return(batches, nil)
+		srv := servicers.NewIndexerManagerServicer(&reindex_mocks.Reindexer{}, false, q)
+
+		got, err := srv.GetFailedBatches(ctxBlank, &indexer_protos.GetFailedBatchesRequest{IndexerId: id0})
+		assert.NoError(t, err)
+		want := &indexer_protos.GetFailedBatchesResponse{Batches: []*indexer_protos.FailedBatch{
+			{
+				BatchId:    "batch0",
+				IndexerId:  id0,
+				NetworkId:  "nid0",
+				StateIds:   []*protos.StateID{{Type: "t0", DeviceID: "k0"}, {Type: "t1", DeviceID: "k1"}},
+				Attempts:   10,
+				Error:      "some_error",
+				EnqueuedAt: 1000,
+			},
+		}}
+		assert.Equal(t, want, got)
+		q.AssertExpectations(t)
+	})
+
+	t.Run("fail when deliveries not enabled", func(t *testing.T) {
+		srv := servicers.NewIndexerManagerServicer(&reindex_mocks.Reindexer{}, false, nil)
+
+		_, err := srv.GetFailedBatches(ctxBlank, &indexer_protos.GetFailedBatchesRequest{})
+		assert.Error(t, err)
+		e, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.Unimplemented, e.Code())
+	})
+
+	t.Run("queue err", func(t *testing.T) {
+		q := &delivery_mocks.Queue{}
+		q.On("GetFailedBatches", "").Return(nil, someErr)
+		srv := servicers.NewIndexerManagerServicer(&reindex_mocks.Reindexer{}, false, q)
+
+		_, err := srv.GetFailedBatches(ctxBlank, &indexer_protos.GetFailedBatchesRequest{})
+		assert.Error(t, err)
+		q.AssertExpectations(t)
+	})
+}
+
+func TestIndexerServicer_ReplayFailedBatches(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		q := &delivery_mocks.Queue{}
+		q.On("ReplayFailedBatches", id0, []string{"batch0"}).Return(uint(1), nil)
+		srv := servicers.NewIndexerManagerServicer(&reindex_mocks.Reindexer{}, false, q)
+
+		got, err := srv.ReplayFailedBatches(ctxBlank, &indexer_protos.ReplayFailedBatchesRequest{IndexerId: id0, BatchIds: []string{"batch0"}})
+		assert.NoError(t, err)
+		assert.Equal(t, &indexer_protos.ReplayFailedBatchesResponse{NumReplayed: 1}, got)
+		q.AssertExpectations(t)
+	})
+
+	t.Run("fail when indexer ID empty", func(t *testing.T) {
+		srv := servicers.NewIndexerManagerServicer(&reindex_mocks.Reindexer{}, false, &delivery_mocks.Queue{})
+
+		_, err := srv.ReplayFailedBatches(ctxBlank, &indexer_protos.ReplayFailedBatchesRequest{})
+		assert.Error(t, err)
+		e, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, e.Code())
+	})
+
+	t.Run("fail when blankCtx identity is present", func(t *testing.T) {
+		srv := servicers.NewIndexerManagerServicer(&reindex_mocks.Reindexer{}, false, &delivery_mocks.Queue{})
+
+		_, err := srv.ReplayFailedBatches(ctxWithIdentity, &indexer_protos.ReplayFailedBatchesRequest{IndexerId: id0})
+		assert.Error(t, err)
+		e, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.PermissionDenied, e.Code())
+	})
+}
@@ -14,16 +14,20 @@ limitations under the License.
 package servicers
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"time"
 
 	"magma/orc8r/cloud/go/blobstore"
 	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/state/indexer/delivery"
 	"magma/orc8r/cloud/go/services/state/indexer/index"
+	state_protos "magma/orc8r/cloud/go/services/state/protos"
 	state_types "magma/orc8r/cloud/go/services/state/types"
 	"magma/orc8r/cloud/go/storage"
 	"magma/orc8r/lib/go/protos"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"github.com/thoas/go-funk"
 	"golang.org/x/net/context"
@@ -31,21 +35,34 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// defaultStreamPageSize is the number of states sent per response by
+	// StreamStates, when the request doesn't specify a page size.
+	defaultStreamPageSize = 100
+)
+
 var (
 	errMissingGateway       = status.Error(codes.PermissionDenied, "missing gateway identity")
 	errGatewayNotRegistered = status.Error(codes.PermissionDenied, "gateway not registered")
 )
 
 type stateServicer struct {
-	factory blobstore.BlobStorageFactory
+	factory    blobstore.BlobStorageFactory
+	retention  map[string]time.Duration
+	deliveries delivery.Queue
 }
 
 // NewStateServicer returns a state server backed by storage passed in.
-func NewStateServicer(factory blobstore.BlobStorageFactory) (protos.StateServiceServer, error) {
+// Retention maps state types to how long reported states of that type are
+// kept after their most recent report. States of types without a retention
+// are kept until deleted.
+// Failed index calls are enqueued to the deliveries queue for retried
+// delivery. If deliveries is nil, index calls are retried in-line.
+func NewStateServicer(factory blobstore.BlobStorageFactory, retention map[string]time.Duration, deliveries delivery.Queue) (protos.StateServiceServer, error) {
 	if factory == nil {
 		return nil, errors.New("storage factory is nil")
 	}
-	return &stateServicer{factory}, nil
+	return &stateServicer{factory: factory, retention: retention, deliveries: deliveries}, nil
 }
 
 func (srv *stateServicer) GetStates(ctx context.Context, req *protos.GetStatesRequest) (*protos.GetStatesResponse, error) {
@@ -58,6 +75,30 @@ func (srv *stateServicer) GetStates(ctx context.Context, req *protos.GetStatesRe
 	return srv.searchStates(ctx, req)
 }
 
+// StreamStates streams the states matching a search, one page per response.
+func (srv *stateServicer) StreamStates(req *protos.GetStatesRequest, stream protos.StateService_StreamStatesServer) error {
+	if err := validateStreamStatesRequest(req); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.PageSize == 0 {
+		req.PageSize = defaultStreamPageSize
+	}
+	for {
+		res, err := srv.searchStates(stream.Context(), req)
+		if err != nil {
+			return err
+		}
+		err = stream.Send(res)
+		if err != nil {
+			return err
+		}
+		if res.NextPageToken == "" {
+			return nil
+		}
+		req.PageToken = res.NextPageToken
+	}
+}
+
 // ReportStates from a gateway.
 // Always reports UnreportedStates as empty.
 func (srv *stateServicer) ReportStates(ctx context.Context, req *protos.ReportStatesRequest) (*protos.ReportStatesResponse, error) {
@@ -82,6 +123,7 @@ func (srv *stateServicer) ReportStates(ctx context.Context, req *protos.ReportSt
 	if err != nil {
 		return nil, internalErr(err, "ReportStates convert to blobs")
 	}
+	srv.setExpiries(states)
 
 	store, err := srv.factory.StartTransaction(nil)
 	if err != nil {
@@ -101,7 +143,7 @@ func (srv *stateServicer) ReportStates(ctx context.Context, req *protos.ReportSt
 	if err != nil {
 		return nil, internalErr(err, "ReportStates make states by ID")
 	}
-	go index.MustIndex(networkID, byID)
+	go srv.index(networkID, byID)
 
 	return &protos.ReportStatesResponse{}, nil
 }
@@ -191,6 +233,70 @@ func (srv *stateServicer) SyncStates(ctx context.Context, req *protos.SyncStates
 	return &protos.SyncStatesResponse{UnsyncedStates: unsyncedStates}, nil
 }
 
+// CompareAndSwapStates from a gateway or cloud service.
+// States are written only if every expected version matches the stored
+// version, in which case each guarded state's version is set to 1 more than
+// its expected version.
+func (srv *stateServicer) CompareAndSwapStates(ctx context.Context, req *protos.CompareAndSwapStatesRequest) (*protos.Void, error) {
+	var hwID string
+	gw := protos.GetClientGateway(ctx)
+	if gw != nil {
+		if !gw.Registered() {
+			return nil, errGatewayNotRegistered
+		}
+		hwID = gw.HardwareId
+		if len(req.GetNetworkID()) == 0 {
+			req.NetworkID = gw.NetworkId
+		}
+	}
+	if err := validateCompareAndSwapStatesRequest(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	networkID := req.GetNetworkID()
+	certExpiry := protos.GetClientCertExpiration(ctx)
+	timeMs := uint64(clock.Now().UnixNano()) / uint64(time.Millisecond)
+
+	expected := idAndVersionsToVersions(req.GetExpectedVersions())
+	for _, st := range req.GetStates() {
+		version, ok := expected[storage.TypeAndKey{Type: st.Type, Key: st.DeviceID}]
+		if ok && version == storage.VersionAbsent {
+			st.Version = 0
+		} else if ok {
+			st.Version = version + 1
+		}
+	}
+	states, err := addWrapperAndMakeBlobs(req.States, hwID, timeMs, certExpiry)
+	if err != nil {
+		return nil, internalErr(err, "CompareAndSwapStates convert to blobs")
+	}
+	srv.setExpiries(states)
+
+	store, err := srv.factory.StartTransaction(nil)
+	if err != nil {
+		return nil, internalErr(err, "CompareAndSwapStates blobstore start transaction")
+	}
+	err = store.CompareAndSwap(networkID, expected, states)
+	if err != nil {
+		_ = store.Rollback()
+		if conflict, ok := err.(*storage.VersionConflictError); ok {
+			return nil, conflictErr(conflict)
+		}
+		return nil, internalErr(err, "CompareAndSwapStates blobstore compare and swap")
+	}
+	err = store.Commit()
+	if err != nil {
+		return nil, internalErr(err, "CompareAndSwapStates blobstore commit transaction")
+	}
+
+	byID, err := state_types.MakeSerializedStatesByID(req.States)
+	if err != nil {
+		return nil, internalErr(err, "CompareAndSwapStates make states by ID")
+	}
+	go srv.index(networkID, byID)
+
+	return &protos.Void{}, nil
+}
+
 func (srv *stateServicer) getStates(_ context.Context, req *protos.GetStatesRequest) (*protos.GetStatesResponse, error) {
 	store, err := srv.factory.StartTransaction(nil)
 	if err != nil {
@@ -209,23 +315,25 @@ func (srv *stateServicer) getStates(_ context.Context, req *protos.GetStatesRequ
 		return nil, internalErr(err, "GetStates (get) blobstore commit transaction")
 	}
 
+	blobs, err = filterByReporter(blobs, req.ReporterHardwareId)
+	if err != nil {
+		return nil, internalErr(err, "GetStates (get) filter by reporter")
+	}
 	return &protos.GetStatesResponse{States: blobsToStates(blobs)}, nil
 }
 
 func (srv *stateServicer) searchStates(_ context.Context, req *protos.GetStatesRequest) (*protos.GetStatesResponse, error) {
+	startAfter, err := deserializePageToken(req.PageToken)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %s", err)
+	}
+
 	store, err := srv.factory.StartTransaction(nil)
 	if err != nil {
 		return nil, internalErr(err, "GetStates (search) blobstore start transaction")
 	}
 
-	var idPrefix *string
-	if req.IdPrefix != "" {
-		idPrefix = &req.IdPrefix
-	}
-	searchResults, err := store.Search(
-		blobstore.CreateSearchFilter(&req.NetworkID, req.TypeFilter, req.IdFilter, idPrefix),
-		blobstore.LoadCriteria{LoadValue: req.LoadValues},
-	)
+	blobs, lastScanned, err := searchPage(store, req, startAfter)
 	if err != nil {
 		_ = store.Rollback()
 		return nil, internalErr(err, "GetStates (search) blobstore search")
@@ -236,7 +344,90 @@ func (srv *stateServicer) searchStates(_ context.Context, req *protos.GetStatesR
 		return nil, internalErr(err, "GetStates (search) blobstore commit transaction")
 	}
 
-	return &protos.GetStatesResponse{States: blobsToStates(searchResults[req.NetworkID])}, nil
+	res := &protos.GetStatesResponse{States: blobsToStates(blobs)}
+	if lastScanned != nil {
+		res.NextPageToken, err = serializePageToken(lastScanned)
+		if err != nil {
+			return nil, internalErr(err, "GetStates (search) serialize page token")
+		}
+	}
+	return res, nil
+}
+
+// searchPage returns the blobs matching the search request, starting after
+// the passed blob ID.
+// For paginated requests, the returned blob ID is that of the last blob
+// scanned for the page, or nil if no blobs remain.
+func searchPage(store blobstore.TransactionalBlobStorage, req *protos.GetStatesRequest, startAfter *storage.TypeAndKey) (blobstore.Blobs, *storage.TypeAndKey, error) {
+	var idPrefix *string
+	if req.IdPrefix != "" {
+		idPrefix = &req.IdPrefix
+	}
+	filter := blobstore.CreateSearchFilter(&req.NetworkID, req.TypeFilter, req.IdFilter, idPrefix)
+	// Reporter IDs are stored within state values
+	criteria := blobstore.LoadCriteria{
+		LoadValue:  req.LoadValues || req.ReporterHardwareId != "",
+		StartAfter: startAfter,
+	}
+
+	var page blobstore.Blobs
+	var lastScanned *storage.TypeAndKey
+	for {
+		criteria.PageSize = 0
+		if req.PageSize != 0 {
+			criteria.PageSize = uint64(req.PageSize) - uint64(len(page))
+		}
+		searchResults, err := store.Search(filter, criteria)
+		if err != nil {
+			return nil, nil, err
+		}
+		blobs := searchResults[req.NetworkID]
+		matched, err := filterByReporter(blobs, req.ReporterHardwareId)
+		if err != nil {
+			return nil, nil, err
+		}
+		page = append(page, matched...)
+
+		// Unpaginated, or no blobs remain
+		if criteria.PageSize == 0 || uint64(len(blobs)) < criteria.PageSize {
+			lastScanned = nil
+			break
+		}
+		last := blobs[len(blobs)-1]
+		lastScanned = &storage.TypeAndKey{Type: last.Type, Key: last.Key}
+		if len(page) == int(req.PageSize) {
+			break
+		}
+		criteria.StartAfter = lastScanned
+	}
+
+	if !req.LoadValues {
+		for i := range page {
+			page[i].Value = nil
+		}
+	}
+	return page, lastScanned, nil
+}
+
+// filterByReporter returns the state blobs most recently reported by the
+// gateway with the passed hardware ID. An empty hardware ID matches all
+// blobs.
+func filterByReporter(blobs blobstore.Blobs, reporterHwID string) (blobstore.Blobs, error) {
+	if reporterHwID == "" {
+		return blobs, nil
+	}
+	var ret blobstore.Blobs
+	for _, blob := range blobs {
+		st := state_types.SerializedState{}
+		err := json.Unmarshal(blob.Value, &st)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unmarshal state %s", blob.Key)
+		}
+		if st.ReporterID == reporterHwID {
+			ret = append(ret, blob)
+		}
+	}
+	return ret, nil
 }
 
 func isStateSynced(deviceIdToStates map[string][]*protos.State, reqIdAndVersion *protos.IDAndVersion) (bool, uint64) {
@@ -280,6 +471,28 @@ func addWrapperAndMakeBlobs(states []*protos.State, hwID string, timeMs uint64,
 	return blobs, nil
 }
 
+// index forwards states to the registered indexers.
+func (srv *stateServicer) index(networkID string, states state_types.SerializedStatesByID) {
+	if srv.deliveries == nil {
+		index.MustIndex(networkID, states)
+		return
+	}
+	delivery.MustIndex(srv.deliveries, networkID, states)
+}
+
+// setExpiries sets the expiry of each state blob according to the retention
+// configured for its type.
+func (srv *stateServicer) setExpiries(blobs blobstore.Blobs) {
+	now := clock.Now()
+	for i := range blobs {
+		retention, ok := srv.retention[blobs[i].Type]
+		if !ok || retention <= 0 {
+			continue
+		}
+		blobs[i].ExpiresAt = now.Add(retention).Unix()
+	}
+}
+
 func idToTK(id *protos.StateID) storage.TypeAndKey {
 	return storage.TypeAndKey{Type: id.GetType(), Key: id.GetDeviceID()}
 }
@@ -300,6 +513,14 @@ func idAndVersionsToTKs(IDs []*protos.IDAndVersion) []storage.TypeAndKey {
 	return ids
 }
 
+func idAndVersionsToVersions(IDs []*protos.IDAndVersion) map[storage.TypeAndKey]uint64 {
+	versions := map[storage.TypeAndKey]uint64{}
+	for _, idAndVersion := range IDs {
+		versions[idToTK(idAndVersion.Id)] = idAndVersion.Version
+	}
+	return versions
+}
+
 func blobsToStates(blobs blobstore.Blobs) []*protos.State {
 	var states []*protos.State
 	for _, b := range blobs {
@@ -323,7 +544,52 @@ func stateToBlob(state *protos.State) blobstore.Blob {
 	}
 }
 
+func serializePageToken(startAfter *storage.TypeAndKey) (string, error) {
+	token := &state_protos.StatePageToken{LastType: startAfter.Type, LastDeviceId: startAfter.Key}
+	marshalledToken, err := proto.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(marshalledToken), nil
+}
+
+// deserializePageToken returns the blob ID encoded in the page token, or nil
+// for an empty token.
+func deserializePageToken(encodedToken string) (*storage.TypeAndKey, error) {
+	if encodedToken == "" {
+		return nil, nil
+	}
+	marshalledToken, err := base64.StdEncoding.DecodeString(encodedToken)
+	if err != nil {
+		return nil, err
+	}
+	token := &state_protos.StatePageToken{}
+	err = proto.Unmarshal(marshalledToken, token)
+	if err != nil {
+		return nil, err
+	}
+	return &storage.TypeAndKey{Type: token.LastType, Key: token.LastDeviceId}, nil
+}
+
 func internalErr(err error, wrap string) error {
 	e := errors.Wrap(err, wrap)
 	return status.Error(codes.Internal, e.Error())
 }
+
+// conflictErr converts a version conflict to an Aborted status, with the
+// stored version of each mismatched state attached as details.
+func conflictErr(conflict *storage.VersionConflictError) error {
+	st := status.New(codes.Aborted, conflict.Error())
+	for _, id := range conflict.IDs() {
+		detail := &protos.IDAndVersion{
+			Id:      &protos.StateID{Type: id.Type, DeviceID: id.Key},
+			Version: conflict.Versions[id],
+		}
+		withDetail, err := st.WithDetails(detail)
+		if err != nil {
+			return internalErr(err, "CompareAndSwapStates attach conflict details")
+		}
+		st = withDetail
+	}
+	return st.Err()
+}
@@ -16,9 +16,11 @@ package servicers_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"magma/orc8r/cloud/go/blobstore"
 	"magma/orc8r/cloud/go/blobstore/mocks"
+	"magma/orc8r/cloud/go/clock"
 	"magma/orc8r/cloud/go/services/state/servicers"
 	"magma/orc8r/cloud/go/storage"
 	"magma/orc8r/lib/go/protos"
@@ -58,7 +60,7 @@ func TestStateServicer_GetStates(t *testing.T) {
 	fact := &mocks.BlobStorageFactory{}
 	fact.On("StartTransaction", mock.Anything).Return(mockStore, nil)
 
-	srv, err := servicers.NewStateServicer(fact)
+	srv, err := servicers.NewStateServicer(fact, nil, nil)
 	assert.NoError(t, err)
 
 	actual, err := srv.GetStates(ctx, &protos.GetStatesRequest{
@@ -104,6 +106,37 @@ func TestStateServicer_GetStates(t *testing.T) {
 	fact.AssertExpectations(t)
 }
 
+func TestStateServicer_ReportStates_Retention(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	mockStore := &mocks.TransactionalBlobStorage{}
+	mockStore.On("CreateOrUpdate", "network1", mock.MatchedBy(func(blobs blobstore.Blobs) bool {
+		return len(blobs) == 2 &&
+			blobs[0].Type == "t1" && blobs[0].ExpiresAt == 1060 &&
+			blobs[1].Type == "t2" && blobs[1].ExpiresAt == 0
+	})).Return(nil)
+	mockStore.On("Commit").Return(nil)
+
+	fact := &mocks.BlobStorageFactory{}
+	fact.On("StartTransaction", mock.Anything).Return(mockStore, nil)
+
+	srv, err := servicers.NewStateServicer(fact, map[string]time.Duration{"t1": time.Minute}, nil)
+	assert.NoError(t, err)
+
+	gwCtx := protos.NewGatewayIdentity("hw1", "network1", "gw1").NewContextWithIdentity(ctx)
+	_, err = srv.ReportStates(gwCtx, &protos.ReportStatesRequest{
+		States: []*protos.State{
+			{Type: "t1", DeviceID: "k1", Value: []byte(`{"foo": "bar"}`)},
+			{Type: "t2", DeviceID: "k2", Value: []byte(`{"foo": "baz"}`)},
+		},
+	})
+	assert.NoError(t, err)
+
+	mockStore.AssertExpectations(t)
+	fact.AssertExpectations(t)
+}
+
 func strPtr(s string) *string {
 	return &s
 }
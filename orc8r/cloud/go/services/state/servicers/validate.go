@@ -24,6 +24,23 @@ func validateGetStatesRequest(req *protos.GetStatesRequest) error {
 	if !funk.IsEmpty(req.Ids) && funk.IsEmpty(req.NetworkID) {
 		return errors.New("network ID must be non-empty for non-empty state IDs")
 	}
+	isPaginated := req.PageSize != 0 || req.PageToken != ""
+	if isPaginated && !funk.IsEmpty(req.Ids) {
+		return errors.New("pagination isn't supported for non-empty state IDs")
+	}
+	if isPaginated && funk.IsEmpty(req.NetworkID) {
+		return errors.New("network ID must be non-empty for paginated searches")
+	}
+	return nil
+}
+
+func validateStreamStatesRequest(req *protos.GetStatesRequest) error {
+	if err := enforceNetworkID(req.NetworkID); err != nil {
+		return err
+	}
+	if !funk.IsEmpty(req.Ids) {
+		return errors.New("state IDs must be empty for streamed searches")
+	}
 	return nil
 }
 
@@ -51,6 +68,21 @@ func validateSyncStatesRequest(req *protos.SyncStatesRequest) error {
 	return nil
 }
 
+func validateCompareAndSwapStatesRequest(req *protos.CompareAndSwapStatesRequest) error {
+	if err := enforceNetworkID(req.NetworkID); err != nil {
+		return err
+	}
+	if funk.IsEmpty(req.States) {
+		return errors.New("states value must be specified and non-empty")
+	}
+	for _, idAndVersion := range req.ExpectedVersions {
+		if idAndVersion.GetId() == nil {
+			return errors.New("expected version must specify a state ID")
+		}
+	}
+	return nil
+}
+
 func enforceNetworkID(networkID string) error {
 	if len(networkID) == 0 {
 		return errors.New("network ID must be specified")
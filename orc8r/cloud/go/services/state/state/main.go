@@ -16,6 +16,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
 	"magma/orc8r/cloud/go/blobstore"
@@ -23,6 +24,7 @@ import (
 	"magma/orc8r/cloud/go/service"
 	"magma/orc8r/cloud/go/services/state"
 	state_config "magma/orc8r/cloud/go/services/state/config"
+	"magma/orc8r/cloud/go/services/state/indexer/delivery"
 	"magma/orc8r/cloud/go/services/state/indexer/reindex"
 	"magma/orc8r/cloud/go/services/state/metrics"
 	indexer_protos "magma/orc8r/cloud/go/services/state/protos"
@@ -38,6 +40,9 @@ import (
 // how often to report gateway status
 const gatewayStatusReportInterval = time.Second * 60
 
+// how often to delete expired states
+const expiredStateSweepInterval = time.Minute * 5
+
 const nonPostgresDriverMessage = `Configuration warning:
 
 This deployment has automatic state reindexing enabled, but is targeting a
@@ -69,12 +74,20 @@ func main() {
 		glog.Fatalf("Error initializing state database: %v", err)
 	}
 
-	stateServicer := newStateServicer(store)
+	deliveries := delivery.NewSQLQueue(delivery.DefaultMaxAttempts, db, sqorc.GetSqlBuilder())
+	err = deliveries.Initialize()
+	if err != nil {
+		glog.Fatalf("Error initializing state indexer delivery queue: %v", err)
+	}
+
+	stateServicer := newStateServicer(srv.Config, store, deliveries)
 	protos.RegisterStateServiceServer(srv.GrpcServer, stateServicer)
-	indexerManagerServer := newIndexerManagerServicer(srv.Config, db, store)
+	indexerManagerServer := newIndexerManagerServicer(srv.Config, db, store, deliveries)
 	indexer_protos.RegisterIndexerManagerServer(srv.GrpcServer, indexerManagerServer)
 
 	go metrics.PeriodicallyReportGatewayStatus(gatewayStatusReportInterval)
+	go blobstore.NewSweeper(state.DBTableName, store, expiredStateSweepInterval).Run(context.Background())
+	go delivery.NewDeliverer(deliveries).Run(context.Background())
 
 	err = srv.Run()
 	if err != nil {
@@ -82,15 +95,36 @@ func main() {
 	}
 }
 
-func newStateServicer(store blobstore.BlobStorageFactory) protos.StateServiceServer {
-	servicer, err := servicers.NewStateServicer(store)
+func newStateServicer(cfg *config.ConfigMap, store blobstore.BlobStorageFactory, deliveries delivery.Queue) protos.StateServiceServer {
+	servicer, err := servicers.NewStateServicer(store, getStateRetention(cfg), deliveries)
 	if err != nil {
 		glog.Fatalf("Error creating state servicer: %v", err)
 	}
 	return servicer
 }
 
-func newIndexerManagerServicer(cfg *config.ConfigMap, db *sql.DB, store blobstore.BlobStorageFactory) indexer_protos.IndexerManagerServer {
+// getStateRetention returns the configured retention per state type.
+// Retention is optional, to support configs predating it.
+func getStateRetention(cfg *config.ConfigMap) map[string]time.Duration {
+	if _, ok := cfg.RawMap[state_config.StateRetention]; !ok {
+		glog.Info("No state retention configured for state service")
+		return nil
+	}
+	retentionByType := cfg.MustGetMap(state_config.StateRetention)
+
+	ret := map[string]time.Duration{}
+	for typ, retention := range retentionByType {
+		d, err := time.ParseDuration(fmt.Sprint(retention))
+		if err != nil {
+			glog.Fatalf("Invalid state retention for type %v: %v", typ, err)
+		}
+		ret[fmt.Sprint(typ)] = d
+	}
+	glog.Infof("State retention configured for state service: %v", ret)
+	return ret
+}
+
+func newIndexerManagerServicer(cfg *config.ConfigMap, db *sql.DB, store blobstore.BlobStorageFactory, deliveries delivery.Queue) indexer_protos.IndexerManagerServer {
 	queue := reindex.NewSQLJobQueue(reindex.DefaultMaxAttempts, db, sqorc.GetSqlBuilder())
 	err := queue.Initialize()
 	if err != nil {
@@ -103,7 +137,7 @@ func newIndexerManagerServicer(cfg *config.ConfigMap, db *sql.DB, store blobstor
 
 	autoReindex := cfg.MustGetBool(state_config.EnableAutomaticReindexing)
 	reindexer := reindex.NewReindexer(queue, reindex.NewStore(store))
-	servicer := servicers.NewIndexerManagerServicer(reindexer, autoReindex)
+	servicer := servicers.NewIndexerManagerServicer(reindexer, autoReindex, deliveries)
 
 	if autoReindex && storage.GetSQLDriver() != sqorc.PostgresDriver {
 		glog.Warning(nonPostgresDriverMessage)
@@ -54,14 +54,14 @@ func startService(t *testing.T, db *sql.DB) (reindex.Reindexer, reindex.JobQueue
 
 	factory := blobstore.NewSQLBlobStorageFactory(state.DBTableName, db, sqorc.GetSqlBuilder())
 	require.NoError(t, factory.InitializeFactory())
-	stateServicer, err := servicers.NewStateServicer(factory)
+	stateServicer, err := servicers.NewStateServicer(factory, nil, nil)
 	require.NoError(t, err)
 	protos.RegisterStateServiceServer(srv.GrpcServer, stateServicer)
 
 	queue := reindex.NewSQLJobQueue(singleAttempt, db, sqorc.GetSqlBuilder())
 	require.NoError(t, queue.Initialize())
 	reindexer := reindex.NewReindexer(queue, reindex.NewStore(factory))
-	indexerServicer := servicers.NewIndexerManagerServicer(reindexer, false)
+	indexerServicer := servicers.NewIndexerManagerServicer(reindexer, false, nil)
 	indexer_protos.RegisterIndexerManagerServer(srv.GrpcServer, indexerServicer)
 
 	go srv.RunTest(lis)
@@ -17,15 +17,30 @@ package wrappers
 
 import (
 	"context"
+	"sync"
+	"time"
 
+	"magma/orc8r/cloud/go/clock"
 	"magma/orc8r/cloud/go/orc8r"
 	"magma/orc8r/cloud/go/serdes"
+	"magma/orc8r/cloud/go/services/bootstrapper"
+	bootstrapper_protos "magma/orc8r/cloud/go/services/bootstrapper/protos"
 	"magma/orc8r/cloud/go/services/orchestrator/obsidian/models"
 	"magma/orc8r/cloud/go/services/state"
 	"magma/orc8r/cloud/go/services/state/types"
 	"magma/orc8r/lib/go/errors"
+
+	"github.com/golang/glog"
 )
 
+// certRotationCacheTTL is how long gateways' certificate rotation statuses
+// are cached. Statuses only change as often as the bootstrapper checks
+// certificates, so caching spares a bootstrapper RPC on most gateway status
+// requests.
+const certRotationCacheTTL = time.Minute
+
+var certRotations = newCertRotationCache(certRotationCacheTTL, bootstrapper.GetCertRotationStatuses)
+
 // GetGatewayStatus returns the status for an indicated gateway.
 func GetGatewayStatus(ctx context.Context, networkID string, deviceID string) (*models.GatewayStatus, error) {
 	st, err := state.GetState(ctx, networkID, orc8r.GatewayStateType, deviceID, serdes.State)
@@ -35,7 +50,9 @@ func GetGatewayStatus(ctx context.Context, networkID string, deviceID string) (*
 	if st.ReportedState == nil {
 		return nil, errors.ErrNotFound
 	}
-	return fillInGatewayStatusState(st), nil
+	gwStatus := fillInGatewayStatusState(st)
+	fillInCertRotations(ctx, networkID, map[string]*models.GatewayStatus{deviceID: gwStatus})
+	return gwStatus, nil
 }
 
 // GetGatewayStatuses returns the status for indicated gateways, keyed by
@@ -51,6 +68,7 @@ func GetGatewayStatuses(ctx context.Context, networkID string, deviceIDs []strin
 	for stateID, st := range res {
 		ret[stateID.DeviceID] = fillInGatewayStatusState(st)
 	}
+	fillInCertRotations(ctx, networkID, ret)
 	return ret, nil
 }
 
@@ -64,3 +82,125 @@ func fillInGatewayStatusState(st types.State) *models.GatewayStatus {
 	gwStatus.HardwareID = st.ReporterID
 	return gwStatus
 }
+
+// fillInCertRotations fills in the certificate rotation status of each
+// gateway status, keyed by device ID.
+// Rotation statuses are supplementary, so errors are logged rather than
+// returned.
+func fillInCertRotations(ctx context.Context, networkID string, gwStatuses map[string]*models.GatewayStatus) {
+	var deviceIDs []string
+	for deviceID, gwStatus := range gwStatuses {
+		if gwStatus != nil {
+			deviceIDs = append(deviceIDs, deviceID)
+		}
+	}
+	if len(deviceIDs) == 0 {
+		return
+	}
+	rotations, err := certRotations.get(ctx, networkID, deviceIDs)
+	if err != nil {
+		glog.Errorf("Error getting cert rotation statuses for network %s: %v", networkID, err)
+		return
+	}
+	for deviceID, rotation := range rotations {
+		if gwStatus, ok := gwStatuses[deviceID]; ok && gwStatus != nil {
+			gwStatus.CertRotation = certRotationToModel(rotation)
+		}
+	}
+}
+
+func certRotationToModel(rotation *bootstrapper_protos.CertRotationStatus) *models.CertRotationStatus {
+	return &models.CertRotationStatus{
+		Status:             rotation.Status.String(),
+		CertExpirationTime: rotation.CertExpirationTime,
+		Attempts:           rotation.Attempts,
+		LastAttemptTime:    rotation.LastAttemptTime,
+		LastRotationTime:   rotation.LastRotationTime,
+		LastError:          rotation.LastError,
+	}
+}
+
+type getCertRotationsFunc func(ctx context.Context, networkID string, hwIDs []string) (map[string]*bootstrapper_protos.CertRotationStatus, error)
+
+// certRotationCache caches gateways' certificate rotation statuses, keyed by
+// network ID and hardware ID. Gateways without a status are cached as nil.
+// Entries past their TTL are evicted on refresh, at most once per TTL, so
+// removed gateways and networks don't accumulate.
+type certRotationCache struct {
+	ttl   time.Duration
+	fetch getCertRotationsFunc
+
+	sync.Mutex
+	entries   map[string]map[string]certRotationEntry
+	evictedAt time.Time
+}
+
+type certRotationEntry struct {
+	status    *bootstrapper_protos.CertRotationStatus
+	fetchedAt time.Time
+}
+
+func newCertRotationCache(ttl time.Duration, fetch getCertRotationsFunc) *certRotationCache {
+	return &certRotationCache{ttl: ttl, fetch: fetch, entries: map[string]map[string]certRotationEntry{}}
+}
+
+// get returns the rotation statuses of the passed gateways, keyed by
+// hardware ID, fetching only those not cached within the TTL.
+func (c *certRotationCache) get(ctx context.Context, networkID string, hwIDs []string) (map[string]*bootstrapper_protos.CertRotationStatus, error) {
+	now := clock.Now()
+	ret := map[string]*bootstrapper_protos.CertRotationStatus{}
+	var stale []string
+
+	c.Lock()
+	for _, hwID := range hwIDs {
+		entry, ok := c.entries[networkID][hwID]
+		if !ok || now.Sub(entry.fetchedAt) >= c.ttl {
+			stale = append(stale, hwID)
+			continue
+		}
+		if entry.status != nil {
+			ret[hwID] = entry.status
+		}
+	}
+	c.Unlock()
+	if len(stale) == 0 {
+		return ret, nil
+	}
+
+	fetched, err := c.fetch(ctx, networkID, stale)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if now.Sub(c.evictedAt) >= c.ttl {
+		c.evictExpired(now)
+	}
+	if _, ok := c.entries[networkID]; !ok {
+		c.entries[networkID] = map[string]certRotationEntry{}
+	}
+	for _, hwID := range stale {
+		status := fetched[hwID]
+		c.entries[networkID][hwID] = certRotationEntry{status: status, fetchedAt: now}
+		if status != nil {
+			ret[hwID] = status
+		}
+	}
+	return ret, nil
+}
+
+// evictExpired deletes the entries past their TTL. The cache must be locked.
+func (c *certRotationCache) evictExpired(now time.Time) {
+	for networkID, entries := range c.entries {
+		for hwID, entry := range entries {
+			if now.Sub(entry.fetchedAt) >= c.ttl {
+				delete(entries, hwID)
+			}
+		}
+		if len(entries) == 0 {
+			delete(c.entries, networkID)
+		}
+	}
+	c.evictedAt = now
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrappers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/bootstrapper/protos"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertRotationCache(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	hw1 := &protos.CertRotationStatus{Status: protos.CertRotationStatus_ROTATING}
+	var fetches [][]string
+	var fetchErr error
+	cache := newCertRotationCache(time.Minute, func(ctx context.Context, networkID string, hwIDs []string) (map[string]*protos.CertRotationStatus, error) {
+		fetches = append(fetches, hwIDs)
+		return map[string]*protos.CertRotationStatus{"hw1": hw1}, fetchErr
+	})
+
+	got, err := cache.get(context.Background(), "n1", []string{"hw1", "hw2"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*protos.CertRotationStatus{"hw1": hw1}, got)
+
+	// Cached statuses, including absent ones, aren't refetched
+	got, err = cache.get(context.Background(), "n1", []string{"hw1", "hw2", "hw3"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*protos.CertRotationStatus{"hw1": hw1}, got)
+	assert.Equal(t, [][]string{{"hw1", "hw2"}, {"hw3"}}, fetches)
+	_, err = cache.get(context.Background(), "n2", []string{"hw4"})
+	assert.NoError(t, err)
+
+	// Statuses are refetched after the TTL, and failed fetches aren't cached
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0).Add(time.Minute))
+	fetchErr = errors.New("bootstrapper unavailable")
+	_, err = cache.get(context.Background(), "n1", []string{"hw1"})
+	assert.Error(t, err)
+	fetchErr = nil
+	_, err = cache.get(context.Background(), "n1", []string{"hw1"})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"hw1", "hw2"}, {"hw3"}, {"hw4"}, {"hw1"}, {"hw1"}}, fetches)
+
+	// Expired entries are evicted on refresh, along with emptied networks
+	assert.Equal(t, map[string]map[string]certRotationEntry{
+		"n1": {"hw1": {status: hw1, fetchedAt: time.Unix(1000, 0).Add(time.Minute)}},
+	}, cache.entries)
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/lib/go/protos"
+)
+
+// DefaultSnapshotCacheSize is the default number of snapshots retained by a
+// snapshot cache.
+const DefaultSnapshotCacheSize = 16
+
+// SnapshotCache computes delta update batches for providers whose data
+// doesn't track its own changes.
+//
+// Each full snapshot of a provider's data is versioned by a digest of its
+// contents, and the per-key digests of recent snapshots are cached, so
+// deltas can be computed against any of them. Since versions are content
+// addressed, snapshots are shared across gateways with the same data.
+//
+// Gateways whose version isn't in the cache, e.g. because it was evicted or
+// was computed by another replica, receive a full snapshot.
+type SnapshotCache struct {
+	maxSize int
+
+	sync.Mutex
+	// snapshots maps version to the per-key value digests of the snapshot.
+	snapshots map[string]map[string]string
+	// order of snapshot versions, least to most recently used.
+	order []string
+}
+
+// NewSnapshotCache returns a snapshot cache retaining up to maxSize
+// snapshots.
+func NewSnapshotCache(maxSize int) *SnapshotCache {
+	return &SnapshotCache{maxSize: maxSize, snapshots: map[string]map[string]string{}}
+}
+
+// GetDeltaBatch returns the batch of updates to bring a gateway from the
+// passed version to the snapshot of the passed updates.
+// Updates must contain unique keys.
+func (c *SnapshotCache) GetDeltaBatch(version string, updates []*protos.DataUpdate) *protos.DataUpdateBatch {
+	digests := getDigests(updates)
+	current := getVersion(digests)
+
+	c.Lock()
+	defer c.Unlock()
+
+	previous, ok := c.snapshots[version]
+	c.put(current, digests)
+	if version == "" || !ok {
+		return &protos.DataUpdateBatch{Updates: updates, Resync: true, Version: current}
+	}
+
+	batch := &protos.DataUpdateBatch{Updates: []*protos.DataUpdate{}, Version: current}
+	for _, u := range updates {
+		if previous[u.Key] != digests[u.Key] {
+			batch.Updates = append(batch.Updates, u)
+		}
+	}
+	for key := range previous {
+		if _, ok := digests[key]; !ok {
+			batch.RemovedKeys = append(batch.RemovedKeys, key)
+		}
+	}
+	sort.Strings(batch.RemovedKeys)
+	return batch
+}
+
+// NetworkSnapshotCache holds a separate snapshot cache per network, so the
+// snapshots of one network's gateways aren't evicted by those of other
+// networks.
+type NetworkSnapshotCache struct {
+	maxSize int
+
+	sync.Mutex
+	caches map[string]*SnapshotCache
+}
+
+// NewNetworkSnapshotCache returns a network snapshot cache retaining up to
+// maxSize snapshots per network. Non-positive sizes use
+// DefaultSnapshotCacheSize.
+func NewNetworkSnapshotCache(maxSize int) *NetworkSnapshotCache {
+	if maxSize <= 0 {
+		maxSize = DefaultSnapshotCacheSize
+	}
+	return &NetworkSnapshotCache{maxSize: maxSize, caches: map[string]*SnapshotCache{}}
+}
+
+// GetDeltaBatch returns the batch of updates to bring a gateway of the network
+// from the passed version to the snapshot of the passed updates.
+// See SnapshotCache.GetDeltaBatch.
+func (c *NetworkSnapshotCache) GetDeltaBatch(networkID string, version string, updates []*protos.DataUpdate) *protos.DataUpdateBatch {
+	c.Lock()
+	cache, ok := c.caches[networkID]
+	if !ok {
+		cache = NewSnapshotCache(c.maxSize)
+		c.caches[networkID] = cache
+	}
+	c.Unlock()
+	return cache.GetDeltaBatch(version, updates)
+}
+
+// NetworkLoadCache memoizes the data a provider loads for each network, so
+// requests from a network's gateways within maxAge of each other share a
+// single load rather than each reloading the network's data.
+type NetworkLoadCache struct {
+	maxAge time.Duration
+
+	sync.Mutex
+	loads map[string]networkLoad
+}
+
+type networkLoad struct {
+	value    interface{}
+	loadedAt time.Time
+}
+
+// NewNetworkLoadCache returns a load cache reusing loads for up to maxAge.
+// A non-positive max age disables reuse.
+func NewNetworkLoadCache(maxAge time.Duration) *NetworkLoadCache {
+	return &NetworkLoadCache{maxAge: maxAge, loads: map[string]networkLoad{}}
+}
+
+// Get returns the network's data loaded within maxAge, or calls load to
+// reload it. Failed loads aren't cached.
+func (c *NetworkLoadCache) Get(networkID string, load func() (interface{}, error)) (interface{}, error) {
+	if c.maxAge <= 0 {
+		return load()
+	}
+
+	now := clock.Now()
+	c.Lock()
+	l, ok := c.loads[networkID]
+	c.Unlock()
+	if ok && now.Sub(l.loadedAt) < c.maxAge {
+		return l.value, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.Lock()
+	c.loads[networkID] = networkLoad{value: value, loadedAt: now}
+	c.Unlock()
+	return value, nil
+}
+
+// put adds a snapshot to the cache as most recently used, evicting the least
+// recently used snapshot when full.
+func (c *SnapshotCache) put(version string, digests map[string]string) {
+	for i, v := range c.order {
+		if v == version {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	c.order = append(c.order, version)
+	c.snapshots[version] = digests
+
+	for len(c.order) > c.maxSize {
+		delete(c.snapshots, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+func getDigests(updates []*protos.DataUpdate) map[string]string {
+	digests := make(map[string]string, len(updates))
+	for _, u := range updates {
+		sum := sha256.Sum256(u.Value)
+		digests[u.Key] = string(sum[:])
+	}
+	return digests
+}
+
+// getVersion returns a digest over the per-key digests of a snapshot.
+func getVersion(digests map[string]string) string {
+	keys := make([]string, 0, len(digests))
+	for k := range digests {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(digests[k]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
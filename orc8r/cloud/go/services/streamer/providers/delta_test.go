@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providers_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"magma/orc8r/cloud/go/clock"
+	"magma/orc8r/cloud/go/services/streamer/providers"
+	"magma/orc8r/lib/go/protos"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotCache(t *testing.T) {
+	c := providers.NewSnapshotCache(2)
+
+	v0 := c.GetDeltaBatch("", []*protos.DataUpdate{{Key: "a", Value: []byte("0")}})
+	assert.True(t, v0.Resync)
+	v1 := c.GetDeltaBatch(v0.Version, []*protos.DataUpdate{{Key: "a", Value: []byte("1")}})
+	assert.False(t, v1.Resync)
+	assert.Equal(t, []*protos.DataUpdate{{Key: "a", Value: []byte("1")}}, v1.Updates)
+
+	// Same contents, same version
+	again := c.GetDeltaBatch("", []*protos.DataUpdate{{Key: "a", Value: []byte("1")}})
+	assert.True(t, again.Resync)
+	assert.Equal(t, v1.Version, again.Version)
+
+	// Moving key-value pairs between keys changes the version
+	swapped0 := c.GetDeltaBatch("", []*protos.DataUpdate{{Key: "a", Value: []byte("0")}, {Key: "b", Value: []byte("1")}})
+	swapped1 := c.GetDeltaBatch("", []*protos.DataUpdate{{Key: "a", Value: []byte("1")}, {Key: "b", Value: []byte("0")}})
+	assert.NotEqual(t, swapped0.Version, swapped1.Version)
+
+	// Least recently used snapshot evicted, falling back to full snapshot
+	evicted := c.GetDeltaBatch(v1.Version, []*protos.DataUpdate{{Key: "a", Value: []byte("2")}})
+	assert.True(t, evicted.Resync)
+	assert.Len(t, evicted.Updates, 1)
+
+	// Recently used snapshot still cached
+	cached := c.GetDeltaBatch(evicted.Version, []*protos.DataUpdate{})
+	assert.False(t, cached.Resync)
+	assert.Empty(t, cached.Updates)
+	assert.Equal(t, []string{"a"}, cached.RemovedKeys)
+}
+
+func TestNetworkSnapshotCache(t *testing.T) {
+	c := providers.NewNetworkSnapshotCache(1)
+
+	n0 := c.GetDeltaBatch("n0", "", []*protos.DataUpdate{{Key: "a", Value: []byte("0")}})
+	assert.True(t, n0.Resync)
+
+	// Other networks' snapshots don't evict the network's snapshots
+	n1 := c.GetDeltaBatch("n1", "", []*protos.DataUpdate{{Key: "a", Value: []byte("1")}})
+	assert.True(t, n1.Resync)
+	delta := c.GetDeltaBatch("n0", n0.Version, []*protos.DataUpdate{{Key: "a", Value: []byte("0")}, {Key: "b", Value: []byte("0")}})
+	assert.False(t, delta.Resync)
+	assert.Equal(t, []*protos.DataUpdate{{Key: "b", Value: []byte("0")}}, delta.Updates)
+
+	// Snapshots aren't shared across networks
+	other := c.GetDeltaBatch("n1", delta.Version, []*protos.DataUpdate{{Key: "a", Value: []byte("1")}})
+	assert.True(t, other.Resync)
+}
+
+func TestNetworkLoadCache(t *testing.T) {
+	clock.SetAndFreezeClock(t, time.Unix(1000, 0))
+	defer clock.UnfreezeClock(t)
+
+	loads := 0
+	load := func() (interface{}, error) {
+		loads++
+		return loads, nil
+	}
+	failLoad := func() (interface{}, error) { return nil, errors.New("some_error") }
+
+	c := providers.NewNetworkLoadCache(10 * time.Second)
+	v, err := c.Get("n0", load)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	// Reused within max age, per network
+	clock.SetAndFreezeClock(t, time.Unix(1009, 0))
+	v, err = c.Get("n0", load)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	v, err = c.Get("n1", load)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+
+	// Reloaded after max age, and failed loads aren't cached
+	clock.SetAndFreezeClock(t, time.Unix(1010, 0))
+	_, err = c.Get("n0", failLoad)
+	assert.Error(t, err)
+	v, err = c.Get("n0", load)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v)
+
+	// Disabled reuse
+	c = providers.NewNetworkLoadCache(0)
+	v, err = c.Get("n0", load)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, v)
+	v, err = c.Get("n0", load)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, v)
+}
@@ -31,3 +31,17 @@ type StreamProvider interface {
 	// on the same stream
 	GetUpdates(ctx context.Context, gatewayId string, extraArgs *any.Any) ([]*protos.DataUpdate, error)
 }
+
+// DeltaStreamProvider is a stream provider which can also stream incremental
+// updates, relative to a version of its data previously streamed to the
+// gateway.
+type DeltaStreamProvider interface {
+	StreamProvider
+
+	// GetDeltaUpdates returns the batch of updates to bring a gateway from
+	// the passed version of the provider's data to the current version.
+	// When version is empty or no longer known to the provider, the
+	// returned batch is a full snapshot with Resync set.
+	// Error semantics match GetUpdates.
+	GetDeltaUpdates(ctx context.Context, gatewayId string, version string, extraArgs *any.Any) (*protos.DataUpdateBatch, error)
+}
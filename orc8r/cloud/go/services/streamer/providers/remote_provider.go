@@ -45,6 +45,17 @@ func (r *remoteProvider) GetStreamName() string {
 }
 
 func (r *remoteProvider) GetUpdates(ctx context.Context, gatewayId string, extraArgs *any.Any) ([]*protos.DataUpdate, error) {
+	res, err := r.GetDeltaUpdates(ctx, gatewayId, "", extraArgs)
+	if err != nil {
+		return nil, err
+	}
+	return res.Updates, nil
+}
+
+// GetDeltaUpdates forwards the gateway's version to the remote provider.
+// Remote providers which don't support versioning return unversioned
+// batches, which are always full snapshots.
+func (r *remoteProvider) GetDeltaUpdates(ctx context.Context, gatewayId string, version string, extraArgs *any.Any) (*protos.DataUpdateBatch, error) {
 	c, err := r.getProviderClient()
 	if err != nil {
 		return nil, err
@@ -53,11 +64,16 @@ func (r *remoteProvider) GetUpdates(ctx context.Context, gatewayId string, extra
 		GatewayId:  gatewayId,
 		StreamName: r.GetStreamName(),
 		ExtraArgs:  extraArgs,
+		Version:    version,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return res.Updates, nil
+	if res.Version == "" {
+		res.Resync = true
+		res.RemovedKeys = nil
+	}
+	return res, nil
 }
 
 func (r *remoteProvider) getProviderClient() (streamer_protos.StreamProviderClient, error) {
@@ -14,6 +14,7 @@ limitations under the License.
 package servicers
 
 import (
+	"context"
 	"fmt"
 
 	"magma/orc8r/cloud/go/identity"
@@ -62,28 +63,47 @@ func (srv *streamerServicer) GetUpdates(request *protos.StreamRequest, stream pr
 // If provider's GetUpdates() returns error == nil, the update batch is sent to the client and the stream is closed
 // If provider's GetUpdates() returns error == EAGAIN, the update batch is sent to the client and the streaming
 // continues on the same stream
+// If the provider supports delta updates, each batch contains only the changes since the version in the
+// request, or in the previously-sent batch
 func GetUpdatesUnverified(request *protos.StreamRequest, stream protos.Streamer_GetUpdatesServer) error {
 	provider, err := providers.GetStreamProvider(request.GetStreamName())
 	if err != nil {
 		return status.Errorf(codes.Unavailable, "stream %s does not exist", request.GetStreamName())
 	}
-	var updates []*protos.DataUpdate
+	version := request.GetVersion()
+	var batch *protos.DataUpdateBatch
 	for err = streamer.EAGAIN; err == streamer.EAGAIN; {
-		updates, err = provider.GetUpdates(stream.Context(), request.GetGatewayId(), request.ExtraArgs)
+		batch, err = getUpdateBatch(stream.Context(), provider, request, version)
 		err = normalizeError(err)
 		if err != nil && err != streamer.EAGAIN {
 			return status.Errorf(codes.Aborted, "error while streaming updates: %s", err)
 		}
-		batch := &protos.DataUpdateBatch{Updates: updates, Resync: true}
 
 		sendErr := stream.Send(batch)
 		if sendErr != nil {
 			return status.Errorf(codes.Internal, "error sending update batch %+v: %v", batch, sendErr)
 		}
+		version = batch.Version
 	}
 	return nil
 }
 
+// getUpdateBatch gets the next update batch from the provider, as a delta from
+// the passed version when the provider supports it.
+// Always returns a non-nil batch.
+func getUpdateBatch(ctx context.Context, provider providers.StreamProvider, request *protos.StreamRequest, version string) (*protos.DataUpdateBatch, error) {
+	deltaProvider, ok := provider.(providers.DeltaStreamProvider)
+	if !ok {
+		updates, err := provider.GetUpdates(ctx, request.GetGatewayId(), request.ExtraArgs)
+		return &protos.DataUpdateBatch{Updates: updates, Resync: true}, err
+	}
+	batch, err := deltaProvider.GetDeltaUpdates(ctx, request.GetGatewayId(), version, request.ExtraArgs)
+	if batch == nil {
+		batch = &protos.DataUpdateBatch{Resync: true}
+	}
+	return batch, err
+}
+
 // normalizeError determines whether the gRPC-returned error status is
 // equivalent to streamer.EAGAIN.
 func normalizeError(err error) error {
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"magma/orc8r/cloud/go/services/streamer"
+	"magma/orc8r/cloud/go/services/streamer/providers"
 	streamer_test_init "magma/orc8r/cloud/go/services/streamer/test_init"
 	"magma/orc8r/lib/go/protos"
 	"magma/orc8r/lib/go/registry"
@@ -38,6 +39,15 @@ func (m *mockStreamProvider) GetUpdates(ctx context2.Context, gatewayId string,
 	return m.retVal, m.retErr
 }
 
+type mockDeltaStreamProvider struct {
+	mockStreamProvider
+	cache *providers.SnapshotCache
+}
+
+func (m *mockDeltaStreamProvider) GetDeltaUpdates(ctx context2.Context, gatewayId string, version string, extraArgs *any.Any) (*protos.DataUpdateBatch, error) {
+	return m.cache.GetDeltaBatch(version, m.retVal), m.retErr
+}
+
 func TestStreamingServer_GetUpdates(t *testing.T) {
 	streamer_test_init.StartTestService(t)
 	conn, err := registry.GetConnection(streamer.ServiceName)
@@ -85,3 +95,67 @@ func TestStreamingServer_GetUpdates(t *testing.T) {
 	_, err = streamerClient.Recv()
 	assert.Error(t, err, "Stream stream_dne does not exist", codes.Unavailable)
 }
+
+func TestStreamingServer_GetUpdates_Delta(t *testing.T) {
+	streamer_test_init.StartTestService(t)
+	conn, err := registry.GetConnection(streamer.ServiceName)
+	assert.NoError(t, err)
+	grpcClient := protos.NewStreamerClient(conn)
+
+	provider := &mockDeltaStreamProvider{
+		mockStreamProvider: mockStreamProvider{retVal: []*protos.DataUpdate{
+			{Key: "a", Value: []byte("123")},
+			{Key: "b", Value: []byte("456")},
+		}},
+		cache: providers.NewSnapshotCache(providers.DefaultSnapshotCacheSize),
+	}
+	streamer_test_init.StartNewTestProvider(t, provider, "mock_delta")
+
+	getBatch := func(version string) *protos.DataUpdateBatch {
+		streamerClient, err := grpcClient.GetUpdates(
+			context.Background(),
+			&protos.StreamRequest{GatewayId: "hwId", StreamName: "mock_delta", Version: version},
+		)
+		assert.NoError(t, err)
+		batch, err := streamerClient.Recv()
+		assert.NoError(t, err)
+		return batch
+	}
+
+	// No version -> full snapshot
+	batch0 := getBatch("")
+	assert.True(t, batch0.Resync)
+	assert.Len(t, batch0.Updates, 2)
+	assert.NotEmpty(t, batch0.Version)
+
+	// Unchanged -> empty delta
+	batch1 := getBatch(batch0.Version)
+	assert.False(t, batch1.Resync)
+	assert.Empty(t, batch1.Updates)
+	assert.Empty(t, batch1.RemovedKeys)
+	assert.Equal(t, batch0.Version, batch1.Version)
+
+	// Changed, added, and removed keys -> delta
+	provider.retVal = []*protos.DataUpdate{
+		{Key: "a", Value: []byte("123")},
+		{Key: "b", Value: []byte("789")},
+		{Key: "c", Value: []byte("000")},
+	}
+	batch2 := getBatch(batch0.Version)
+	assert.False(t, batch2.Resync)
+	assert.Equal(t, []string{"b", "c"}, []string{batch2.Updates[0].Key, batch2.Updates[1].Key})
+	assert.Empty(t, batch2.RemovedKeys)
+	assert.NotEqual(t, batch0.Version, batch2.Version)
+
+	provider.retVal = provider.retVal[1:]
+	batch3 := getBatch(batch2.Version)
+	assert.False(t, batch3.Resync)
+	assert.Empty(t, batch3.Updates)
+	assert.Equal(t, []string{"a"}, batch3.RemovedKeys)
+
+	// Unknown version -> full snapshot
+	batch4 := getBatch("some_unknown_version")
+	assert.True(t, batch4.Resync)
+	assert.Len(t, batch4.Updates, 2)
+	assert.Equal(t, batch3.Version, batch4.Version)
+}
@@ -44,6 +44,9 @@ func StartNewTestProvider(t *testing.T, provider providers.StreamProvider, strea
 }
 
 func (p *providerServicer) GetUpdates(ctx context.Context, req *protos.StreamRequest) (*protos.DataUpdateBatch, error) {
+	if deltaProvider, ok := p.provider.(providers.DeltaStreamProvider); ok {
+		return deltaProvider.GetDeltaUpdates(context.Background(), req.GatewayId, req.Version, req.ExtraArgs)
+	}
 	updates, err := p.provider.GetUpdates(context.Background(), req.GatewayId, req.ExtraArgs)
 	res := &protos.DataUpdateBatch{Updates: updates}
 	return res, err
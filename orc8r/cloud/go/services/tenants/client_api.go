@@ -126,3 +126,21 @@ func DeleteTenant(ctx context.Context, tenantID int64) error {
 	}
 	return err
 }
+
+// GetNetworkTenant returns the ID and tenant of the tenant owning the network.
+// If no tenant owns the network, returns ErrNotFound from
+// magma/orc8r/lib/go/errors.
+func GetNetworkTenant(ctx context.Context, networkID string) (int64, *protos.Tenant, error) {
+	tenantList, err := GetAllTenants(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, t := range tenantList.Tenants {
+		for _, nid := range t.GetTenant().GetNetworks() {
+			if nid == networkID {
+				return t.Id, t.Tenant, nil
+			}
+		}
+	}
+	return 0, nil, merrors.ErrNotFound
+}
@@ -19,17 +19,21 @@ import (
 	"net/http"
 
 	"magma/orc8r/cloud/go/obsidian"
+	"magma/orc8r/cloud/go/serde"
+	"magma/orc8r/cloud/go/services/configurator"
 	"magma/orc8r/cloud/go/services/tenants"
 	"magma/orc8r/cloud/go/services/tenants/obsidian/models"
 	"magma/orc8r/lib/go/errors"
 	"magma/orc8r/lib/go/protos"
 
 	"github.com/labstack/echo"
+	"github.com/thoas/go-funk"
 )
 
 const (
 	TenantRootPath = obsidian.V1Root + "tenants"
 	TenantInfoURL  = TenantRootPath + obsidian.UrlSep + ":tenant_id"
+	TenantUsageURL = TenantInfoURL + obsidian.UrlSep + "usage"
 )
 
 func GetObsidianHandlers() []obsidian.Handler {
@@ -59,6 +63,11 @@ func GetObsidianHandlers() []obsidian.Handler {
 			Methods:     obsidian.DELETE,
 			HandlerFunc: DeleteTenantHandler,
 		},
+		{
+			Path:        TenantUsageURL,
+			Methods:     obsidian.GET,
+			HandlerFunc: GetTenantUsageHandler,
+		},
 	}
 }
 
@@ -72,7 +81,8 @@ func GetTenantsHandler(c echo.Context) error {
 		tenantsAndIDs = append(tenantsAndIDs, models.Tenant{
 			ID:       &tenant.Id,
 			Networks: tenant.Tenant.Networks,
-			Name:     tenant.Tenant.Name})
+			Name:     tenant.Tenant.Name,
+			Quotas:   (&models.TenantQuotas{}).FromProto(tenant.Tenant.Quotas)})
 	}
 	return c.JSON(http.StatusOK, tenantsAndIDs)
 }
@@ -90,6 +100,7 @@ func CreateTenantHandler(c echo.Context) error {
 	_, err = tenants.CreateTenant(c.Request().Context(), *tenantInfo.ID, &protos.Tenant{
 		Name:     tenantInfo.Name,
 		Networks: tenantInfo.Networks,
+		Quotas:   tenantInfo.Quotas.ToProto(),
 	})
 	if err != nil {
 		return obsidian.HttpError(fmt.Errorf("Error creating tenant: %v", err), http.StatusInternalServerError)
@@ -109,7 +120,12 @@ func GetTenantHandler(c echo.Context) error {
 	case err != nil:
 		return obsidian.HttpError(err, http.StatusInternalServerError)
 	}
-	return c.JSON(http.StatusOK, models.Tenant{ID: &tenantID, Name: tenantInfo.Name, Networks: tenantInfo.Networks})
+	return c.JSON(http.StatusOK, models.Tenant{
+		ID:       &tenantID,
+		Name:     tenantInfo.Name,
+		Networks: tenantInfo.Networks,
+		Quotas:   (&models.TenantQuotas{}).FromProto(tenantInfo.Quotas),
+	})
 }
 
 func SetTenantHandler(c echo.Context) error {
@@ -148,3 +164,64 @@ func DeleteTenantHandler(c echo.Context) error {
 	}
 	return c.NoContent(http.StatusNoContent)
 }
+
+// GetTenantUsageHandler returns the tenant's usage of each of its quota'd
+// resources. Only the tenant's networks which exist are counted.
+func GetTenantUsageHandler(c echo.Context) error {
+	tenantID, terr := obsidian.GetTenantID(c)
+	if terr != nil {
+		return terr
+	}
+	tenantInfo, err := tenants.GetTenant(c.Request().Context(), tenantID)
+	switch {
+	case err == errors.ErrNotFound:
+		return obsidian.HttpError(fmt.Errorf("Tenant %d does not exist", tenantID), http.StatusNotFound)
+	case err != nil:
+		return obsidian.HttpError(err, http.StatusInternalServerError)
+	}
+
+	allNetworks, err := configurator.ListNetworkIDs()
+	if err != nil {
+		return obsidian.HttpError(fmt.Errorf("Error listing networks: %v", err), http.StatusInternalServerError)
+	}
+	networks := funk.IntersectString(tenantInfo.Networks, allNetworks)
+
+	quotas := tenantInfo.Quotas
+	usage := &models.TenantUsage{
+		Networks:             &models.ResourceUsage{Used: int64(len(networks)), Limit: quotas.GetMaxNetworks()},
+		MaxRequestsPerSecond: quotas.GetMaxRequestsPerSecond(),
+	}
+	usage.Gateways, err = getEntityUsage(networks, tenants.MaxGatewaysQuota, quotas)
+	if err != nil {
+		return obsidian.HttpError(err, http.StatusInternalServerError)
+	}
+	usage.Subscribers, err = getEntityUsage(networks, tenants.MaxSubscribersQuota, quotas)
+	if err != nil {
+		return obsidian.HttpError(err, http.StatusInternalServerError)
+	}
+	usage.PolicyRules, err = getEntityUsage(networks, tenants.MaxPolicyRulesQuota, quotas)
+	if err != nil {
+		return obsidian.HttpError(err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, usage)
+}
+
+// getEntityUsage returns the usage of the named quota, counting the entities
+// of each type registered for the quota.
+func getEntityUsage(networks []string, quota string, quotas *protos.TenantQuotas) (*models.ResourceUsage, error) {
+	usage := &models.ResourceUsage{Limit: tenants.GetQuotaLimit(quotas, quota)}
+	entityTypes, err := tenants.GetQuotaEntityTypesOf(quota)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting entity types of quota %s: %v", quota, err)
+	}
+	for _, entityType := range entityTypes {
+		for _, nid := range networks {
+			count, err := configurator.CountEntitiesOfType(nid, entityType, configurator.EntityLoadCriteria{}, serde.NewRegistry())
+			if err != nil {
+				return nil, fmt.Errorf("Error counting %s entities of network %s: %v", entityType, nid, err)
+			}
+			usage.Used += int64(count)
+		}
+	}
+	return usage, nil
+}
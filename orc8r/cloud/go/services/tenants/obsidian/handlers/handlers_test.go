@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers_test
+
+import (
+	"context"
+	"testing"
+
+	"magma/orc8r/cloud/go/obsidian"
+	"magma/orc8r/cloud/go/obsidian/tests"
+	"magma/orc8r/cloud/go/orc8r"
+	"magma/orc8r/cloud/go/serde"
+	"magma/orc8r/cloud/go/services/configurator"
+	configurator_test_init "magma/orc8r/cloud/go/services/configurator/test_init"
+	"magma/orc8r/cloud/go/services/tenants"
+	"magma/orc8r/cloud/go/services/tenants/obsidian/handlers"
+	"magma/orc8r/cloud/go/services/tenants/obsidian/models"
+	"magma/orc8r/lib/go/protos"
+	"magma/orc8r/lib/go/registry"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTenantUsageHandler(t *testing.T) {
+	configurator_test_init.StartTestService(t)
+	registry.AddService(registry.ServiceLocation{
+		Name:        "quota_test_service",
+		Labels:      map[string]string{orc8r.TenantQuotaLabel: "true"},
+		Annotations: map[string]string{orc8r.TenantQuotaEntityTypesAnnotation: "max_subscribers:subscriber"},
+	})
+	e := echo.New()
+
+	getUsage := tests.GetHandlerByPathAndMethod(t, handlers.GetObsidianHandlers(), handlers.TenantUsageURL, obsidian.GET).HandlerFunc
+
+	err := configurator.CreateNetwork(configurator.Network{ID: "n1"}, serde.NewRegistry())
+	require.NoError(t, err)
+	_, err = configurator.CreateEntities("n1", []configurator.NetworkEntity{
+		{Type: orc8r.MagmadGatewayType, Key: "g1"},
+		{Type: orc8r.MagmadGatewayType, Key: "g2"},
+		{Type: "subscriber", Key: "s1"},
+	}, serde.NewRegistry())
+	require.NoError(t, err)
+	_, err = tenants.CreateTenant(context.Background(), 1, &protos.Tenant{
+		Name:     "t1",
+		Networks: []string{"n1", "n2"},
+		Quotas:   &protos.TenantQuotas{MaxNetworks: 2, MaxGateways: 5, MaxRequestsPerSecond: 10},
+	})
+	require.NoError(t, err)
+
+	// Only existing networks are counted
+	tc := tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/tenants/1/usage",
+		ParamNames:     []string{"tenant_id"},
+		ParamValues:    []string{"1"},
+		Handler:        getUsage,
+		ExpectedStatus: 200,
+		ExpectedResult: &models.TenantUsage{
+			Networks:             &models.ResourceUsage{Used: 1, Limit: 2},
+			Gateways:             &models.ResourceUsage{Used: 2, Limit: 5},
+			Subscribers:          &models.ResourceUsage{Used: 1},
+			PolicyRules:          &models.ResourceUsage{},
+			MaxRequestsPerSecond: 10,
+		},
+	}
+	tests.RunUnitTest(t, e, tc)
+
+	// Unknown tenant
+	tc = tests.Test{
+		Method:         "GET",
+		URL:            "/magma/v1/tenants/2/usage",
+		ParamNames:     []string{"tenant_id"},
+		ParamValues:    []string{"2"},
+		Handler:        getUsage,
+		ExpectedStatus: 404,
+		ExpectedError:  "Tenant 2 does not exist",
+	}
+	tests.RunUnitTest(t, e, tc)
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"magma/orc8r/lib/go/protos"
+)
+
+func (m *TenantQuotas) ToProto() *protos.TenantQuotas {
+	if m == nil {
+		return nil
+	}
+	return &protos.TenantQuotas{
+		MaxNetworks:          m.MaxNetworks,
+		MaxGateways:          m.MaxGateways,
+		MaxSubscribers:       m.MaxSubscribers,
+		MaxPolicyRules:       m.MaxPolicyRules,
+		MaxRequestsPerSecond: m.MaxRequestsPerSecond,
+	}
+}
+
+func (m *TenantQuotas) FromProto(quotas *protos.TenantQuotas) *TenantQuotas {
+	if quotas == nil {
+		return nil
+	}
+	m.MaxNetworks = quotas.MaxNetworks
+	m.MaxGateways = quotas.MaxGateways
+	m.MaxSubscribers = quotas.MaxSubscribers
+	m.MaxPolicyRules = quotas.MaxPolicyRules
+	m.MaxRequestsPerSecond = quotas.MaxRequestsPerSecond
+	return m
+}
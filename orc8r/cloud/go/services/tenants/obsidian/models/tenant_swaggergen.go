@@ -28,6 +28,9 @@ type Tenant struct {
 	// List of accessible networks
 	// Required: true
 	Networks []string `json:"networks"`
+
+	// quotas
+	Quotas *TenantQuotas `json:"quotas,omitempty"`
 }
 
 // Validate validates this tenant
@@ -42,6 +45,10 @@ func (m *Tenant) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateQuotas(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -70,6 +77,24 @@ func (m *Tenant) validateNetworks(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Tenant) validateQuotas(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Quotas) { // not required
+		return nil
+	}
+
+	if m.Quotas != nil {
+		if err := m.Quotas.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("quotas")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *Tenant) MarshalBinary() ([]byte, error) {
 	if m == nil {
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenants
+
+import (
+	"fmt"
+	"strings"
+
+	"magma/orc8r/cloud/go/orc8r"
+	"magma/orc8r/lib/go/protos"
+	"magma/orc8r/lib/go/registry"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Names of the tenant quotas limiting counts of configurator entities.
+const (
+	MaxGatewaysQuota    = "max_gateways"
+	MaxSubscribersQuota = "max_subscribers"
+	MaxPolicyRulesQuota = "max_policy_rules"
+
+	// quotaEntityTypeSeparator separates the quota name and entity type of
+	// each field of the orc8r.io/tenant_quota_entity_types annotation
+	quotaEntityTypeSeparator = ":"
+)
+
+// GetQuotaLimit returns the value of the named entity count quota, or 0 if
+// unlimited.
+func GetQuotaLimit(quotas *protos.TenantQuotas, quota string) uint32 {
+	switch quota {
+	case MaxGatewaysQuota:
+		return quotas.GetMaxGateways()
+	case MaxSubscribersQuota:
+		return quotas.GetMaxSubscribers()
+	case MaxPolicyRulesQuota:
+		return quotas.GetMaxPolicyRules()
+	default:
+		return 0
+	}
+}
+
+// GetQuotaEntityTypes returns the configurator entity types whose counts are
+// limited by tenant quotas, mapped to the name of the limiting quota.
+// Orc8r's gateways are limited by max_gateways. Other modules register their
+// entity types via services with the orc8r.io/tenant_quota label, whose
+// orc8r.io/tenant_quota_entity_types annotation lists <quota>:<entity type>
+// pairs.
+func GetQuotaEntityTypes() (map[string]string, error) {
+	ret := map[string]string{orc8r.MagmadGatewayType: MaxGatewaysQuota}
+	services, err := registry.FindServices(orc8r.TenantQuotaLabel)
+	if err != nil {
+		return nil, errors.Wrap(err, "find tenant quota services")
+	}
+	for _, s := range services {
+		fields, err := registry.GetAnnotationList(s, orc8r.TenantQuotaEntityTypesAnnotation)
+		if err != nil {
+			glog.Warningf("Received error getting annotation %s for service %s: %v", orc8r.TenantQuotaEntityTypesAnnotation, s, err)
+			continue
+		}
+		for _, field := range fields {
+			quotaAndType := strings.SplitN(field, quotaEntityTypeSeparator, 2)
+			if len(quotaAndType) != 2 || !isEntityQuota(quotaAndType[0]) || quotaAndType[1] == "" {
+				glog.Warningf("Ignoring invalid tenant quota entity type %s of service %s", field, s)
+				continue
+			}
+			ret[quotaAndType[1]] = quotaAndType[0]
+		}
+	}
+	return ret, nil
+}
+
+// GetQuotaEntityTypesOf returns the configurator entity types limited by the
+// named quota.
+func GetQuotaEntityTypesOf(quota string) ([]string, error) {
+	quotaByType, err := GetQuotaEntityTypes()
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	for entityType, q := range quotaByType {
+		if q == quota {
+			ret = append(ret, entityType)
+		}
+	}
+	return ret, nil
+}
+
+func isEntityQuota(quota string) bool {
+	switch quota {
+	case MaxGatewaysQuota, MaxSubscribersQuota, MaxPolicyRulesQuota:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewQuotaExceededError returns the error with which writes exceeding a
+// tenant's quota are rejected. The error's status carries a QuotaFailure
+// detail, distinguishing it from other ResourceExhausted errors.
+func NewQuotaExceededError(tenantID int64, resource string, limit uint32) error {
+	description := fmt.Sprintf("at most %d %s allowed", limit, resource)
+	st := status.Newf(codes.ResourceExhausted, "tenant %d quota exceeded: %s", tenantID, description)
+	withDetails, err := st.WithDetails(&errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{{Subject: fmt.Sprintf("tenant:%d", tenantID), Description: description}},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// IsQuotaExceededError returns true if the error, or its cause, was returned
+// by NewQuotaExceededError.
+func IsQuotaExceededError(err error) bool {
+	st, ok := status.FromError(errors.Cause(err))
+	if !ok || st.Code() != codes.ResourceExhausted {
+		return false
+	}
+	for _, detail := range st.Details() {
+		if _, ok := detail.(*errdetails.QuotaFailure); ok {
+			return true
+		}
+	}
+	return false
+}
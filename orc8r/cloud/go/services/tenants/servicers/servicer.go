@@ -20,6 +20,7 @@ import (
 	"magma/orc8r/lib/go/errors"
 	"magma/orc8r/lib/go/protos"
 
+	"magma/orc8r/cloud/go/services/tenants"
 	"magma/orc8r/cloud/go/services/tenants/servicers/storage"
 
 	"google.golang.org/grpc/codes"
@@ -55,6 +56,10 @@ func (s *tenantsServicer) CreateTenant(c context.Context, request *protos.IDAndT
 		return nil, status.Errorf(codes.Internal, "Error getting existing tenants: %v", err)
 	}
 
+	err = verifyNetworkQuota(request.Id, request.Tenant)
+	if err != nil {
+		return nil, err
+	}
 	err = s.store.CreateTenant(request.Id, *request.Tenant)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Error creating tenant: %v", err)
@@ -82,6 +87,10 @@ func (s *tenantsServicer) SetTenant(c context.Context, request *protos.IDAndTena
 	case err != nil:
 		return nil, status.Errorf(codes.Internal, "Error getting tenant %d: %v", request.Id, err)
 	}
+	err = verifyNetworkQuota(request.Id, request.Tenant)
+	if err != nil {
+		return nil, err
+	}
 	err = s.store.SetTenant(request.Id, *request.Tenant)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Error setting tenant %d: %v", request.Id, err)
@@ -99,3 +108,12 @@ func (s *tenantsServicer) DeleteTenant(c context.Context, request *protos.GetTen
 	}
 	return &protos.Void{}, nil
 }
+
+// verifyNetworkQuota verifies the tenant's networks are within its quota.
+func verifyNetworkQuota(tenantID int64, tenant *protos.Tenant) error {
+	limit := tenant.GetQuotas().GetMaxNetworks()
+	if limit != 0 && len(tenant.GetNetworks()) > int(limit) {
+		return tenants.NewQuotaExceededError(tenantID, "networks", limit)
+	}
+	return nil
+}
@@ -96,6 +96,33 @@ func TestTenantsServicer(t *testing.T) {
 	assert.Equal(t, "Tenant 2 not found", status.Convert(err).Message())
 }
 
+func TestTenantsServicer_NetworkQuota(t *testing.T) {
+	srv, err := newTestService(t)
+	assert.NoError(t, err)
+
+	tenant := &protos.Tenant{
+		Name:     "test",
+		Networks: []string{"network_1", "network_2"},
+		Quotas:   &protos.TenantQuotas{MaxNetworks: 1},
+	}
+	_, err = srv.CreateTenant(context.Background(), &protos.IDAndTenant{Id: 1, Tenant: tenant})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, "tenant 1 quota exceeded: at most 1 networks allowed", status.Convert(err).Message())
+
+	tenant.Quotas.MaxNetworks = 2
+	_, err = srv.CreateTenant(context.Background(), &protos.IDAndTenant{Id: 1, Tenant: tenant})
+	assert.NoError(t, err)
+
+	tenant.Networks = append(tenant.Networks, "network_3")
+	_, err = srv.SetTenant(context.Background(), &protos.IDAndTenant{Id: 1, Tenant: tenant})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// Unlimited
+	tenant.Quotas = nil
+	_, err = srv.SetTenant(context.Background(), &protos.IDAndTenant{Id: 1, Tenant: tenant})
+	assert.NoError(t, err)
+}
+
 func newTestService(t *testing.T) (protos.TenantsServiceServer, error) {
 	srv, lis := test_utils.NewTestService(t, orc8r.ModuleName, tenants.ServiceName)
 	factory := test_utils.NewSQLBlobstore(t, "tenants_servicer_test_blobstore")
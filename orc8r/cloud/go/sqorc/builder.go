@@ -29,6 +29,17 @@ import (
 const (
 	PostgresDialect = "psql"
 	MariaDialect    = "maria"
+	SQLiteDialect   = "sqlite"
+
+	// postgresMaxBindVariables is the max number of bind variables in a
+	// single Postgres statement, bounded by the wire protocol's int16 count.
+	postgresMaxBindVariables = 65535
+	// mariaMaxBindVariables is the max number of placeholders in a single
+	// MariaDB prepared statement.
+	mariaMaxBindVariables = 65535
+	// sqliteMaxBindVariables is SQLITE_MAX_VARIABLE_NUMBER for SQLite
+	// versions before 3.32.0.
+	sqliteMaxBindVariables = 999
 )
 
 // GetSqlBuilder returns a squirrel Builder for the configured SQL dialect as
@@ -45,6 +56,8 @@ func GetSqlBuilder() StatementBuilder {
 		return NewPostgresStatementBuilder()
 	case MariaDialect:
 		return NewMariaDBStatementBuilder()
+	case SQLiteDialect:
+		return NewSQLiteStatementBuilder()
 	default:
 		panic(fmt.Sprintf("unsupported sql dialect %s", dialect))
 	}
@@ -77,6 +90,28 @@ type StatementBuilder interface {
 	// RunWith on this StatementBuilder due to a reflection bug that's
 	// tricky to chase down.
 	CreateIndex(name string) CreateIndexBuilder
+
+	// ForUpdateSkipLocked locks the rows returned by a select statement for
+	// the rest of the transaction, skipping rows already locked by other
+	// transactions.
+	// Dialects without row-level locking lock the whole database for the
+	// duration of a writable transaction instead, and return the select
+	// statement unchanged.
+	ForUpdateSkipLocked(b squirrel.SelectBuilder) squirrel.SelectBuilder
+
+	// MaxBindVariables returns the max number of bind variables the dialect
+	// supports in a single statement.
+	MaxBindVariables() int
+}
+
+// GetInsertChunkSize returns the max number of rows with numColumns columns
+// which fit in a single multi-row insert statement for the builder's dialect.
+// Bulk inserts of more rows should be split into chunks of this size.
+func GetInsertChunkSize(b StatementBuilder, numColumns int) int {
+	if numColumns <= 0 {
+		return b.MaxBindVariables()
+	}
+	return b.MaxBindVariables() / numColumns
 }
 
 // NewPostgresStatementBuilder returns an implementation of StatementBuilder
@@ -93,6 +128,13 @@ func NewMariaDBStatementBuilder() StatementBuilder {
 	return mariaDBStatementBuilder{StatementBuilderType: baseBuilder}
 }
 
+// NewSQLiteStatementBuilder returns an implementation of StatementBuilder for
+// SQLite dialect.
+func NewSQLiteStatementBuilder() StatementBuilder {
+	baseBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question)
+	return sqliteStatementBuilder{StatementBuilderType: baseBuilder}
+}
+
 type postgresStatementBuilder struct {
 	squirrel.StatementBuilderType
 }
@@ -118,6 +160,14 @@ func (psb postgresStatementBuilder) CreateIndex(name string) CreateIndexBuilder
 		Name(name)
 }
 
+func (psb postgresStatementBuilder) ForUpdateSkipLocked(b squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return b.Suffix("FOR UPDATE SKIP LOCKED")
+}
+
+func (psb postgresStatementBuilder) MaxBindVariables() int {
+	return postgresMaxBindVariables
+}
+
 type mariaDBStatementBuilder struct {
 	squirrel.StatementBuilderType
 }
@@ -140,6 +190,47 @@ func (msb mariaDBStatementBuilder) CreateIndex(name string) CreateIndexBuilder {
 		Name(name)
 }
 
+func (msb mariaDBStatementBuilder) ForUpdateSkipLocked(b squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return b.Suffix("FOR UPDATE SKIP LOCKED")
+}
+
+func (msb mariaDBStatementBuilder) MaxBindVariables() int {
+	return mariaMaxBindVariables
+}
+
+type sqliteStatementBuilder struct {
+	squirrel.StatementBuilderType
+}
+
+func (ssb sqliteStatementBuilder) Insert(into string) InsertBuilder {
+	baseInsertBuilder := ssb.StatementBuilderType.Insert(into)
+	return sqliteInsertBuilder{baseInsertBuilder}
+}
+
+func (ssb sqliteStatementBuilder) CreateTable(name string) CreateTableBuilder {
+	// see comment on the postgres builder about the EmptyBuilder
+	return CreateTableBuilder(builder.EmptyBuilder).
+		columnTypeNames(sqliteColumnTypeMap).
+		Name(name)
+}
+
+func (ssb sqliteStatementBuilder) CreateIndex(name string) CreateIndexBuilder {
+	// see comment on postgres builder CreateTable about EmptyBuilder
+	return CreateIndexBuilder(builder.EmptyBuilder).
+		Name(name)
+}
+
+// ForUpdateSkipLocked is a no-op for SQLite, which doesn't support row-level
+// locking. Writable transactions hold the database's write lock until they
+// complete (see Open), so concurrent transactions are serialized instead.
+func (ssb sqliteStatementBuilder) ForUpdateSkipLocked(b squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return b
+}
+
+func (ssb sqliteStatementBuilder) MaxBindVariables() int {
+	return sqliteMaxBindVariables
+}
+
 // InsertBuilder is an interface which tracks squirrel's InsertBuilder
 // struct but returns InsertBuilder on all self-referencing returns and adds
 // an OnConflict method to support upserts.
@@ -311,6 +402,78 @@ func (mib mariaInsertBuilder) Select(sb squirrel.SelectBuilder) InsertBuilder {
 	return mariaInsertBuilder{newDelegate}
 }
 
+type sqliteInsertBuilder struct {
+	squirrel.InsertBuilder
+}
+
+// OnConflict uses the same upsert syntax as Postgres, which SQLite supports
+// since 3.24.
+func (sib sqliteInsertBuilder) OnConflict(setValues []UpsertValue, columns ...string) InsertBuilder {
+	if funk.IsEmpty(columns) {
+		panic("must provide at least one column in upsert clause builder")
+	}
+
+	suffixFormat := "ON CONFLICT %s DO %s"
+	colList := fmt.Sprintf("(%s)", strings.Join(columns, ", "))
+
+	if funk.IsEmpty(setValues) {
+		return sib.Suffix(fmt.Sprintf(suffixFormat, colList, "NOTHING"))
+	}
+
+	updateStr, updateArgs := setValuesToUpsertClause(setValues, true)
+	return sib.Suffix(fmt.Sprintf(suffixFormat, colList, updateStr), updateArgs...)
+}
+
+func (sib sqliteInsertBuilder) PlaceholderFormat(f squirrel.PlaceholderFormat) InsertBuilder {
+	newDelegate := sib.InsertBuilder.PlaceholderFormat(f)
+	return sqliteInsertBuilder{newDelegate}
+}
+
+func (sib sqliteInsertBuilder) RunWith(runner squirrel.BaseRunner) InsertBuilder {
+	newDelegate := sib.InsertBuilder.RunWith(runner)
+	return sqliteInsertBuilder{newDelegate}
+}
+
+func (sib sqliteInsertBuilder) Prefix(sql string, args ...interface{}) InsertBuilder {
+	newDelegate := sib.InsertBuilder.Prefix(sql, args...)
+	return sqliteInsertBuilder{newDelegate}
+}
+
+func (sib sqliteInsertBuilder) Options(options ...string) InsertBuilder {
+	newDelegate := sib.InsertBuilder.Options(options...)
+	return sqliteInsertBuilder{newDelegate}
+}
+
+func (sib sqliteInsertBuilder) Into(from string) InsertBuilder {
+	newDelegate := sib.InsertBuilder.Into(from)
+	return sqliteInsertBuilder{newDelegate}
+}
+
+func (sib sqliteInsertBuilder) Columns(columns ...string) InsertBuilder {
+	newDelegate := sib.InsertBuilder.Columns(columns...)
+	return sqliteInsertBuilder{newDelegate}
+}
+
+func (sib sqliteInsertBuilder) Values(values ...interface{}) InsertBuilder {
+	newDelegate := sib.InsertBuilder.Values(values...)
+	return sqliteInsertBuilder{newDelegate}
+}
+
+func (sib sqliteInsertBuilder) Suffix(sql string, args ...interface{}) InsertBuilder {
+	newDelegate := sib.InsertBuilder.Suffix(sql, args...)
+	return sqliteInsertBuilder{newDelegate}
+}
+
+func (sib sqliteInsertBuilder) SetMap(clauses map[string]interface{}) InsertBuilder {
+	newDelegate := sib.InsertBuilder.SetMap(clauses)
+	return sqliteInsertBuilder{newDelegate}
+}
+
+func (sib sqliteInsertBuilder) Select(sb squirrel.SelectBuilder) InsertBuilder {
+	newDelegate := sib.InsertBuilder.Select(sb)
+	return sqliteInsertBuilder{newDelegate}
+}
+
 func ClearStatementCacheLogOnError(cache *squirrel.StmtCache, callsite string) {
 	err := cache.Clear()
 	if err != nil {
@@ -323,6 +486,7 @@ func ClearStatementCacheLogOnError(cache *squirrel.StmtCache, callsite string) {
 // SQL dialect. Currently supported syntax includes
 // 1. MariaDB: "c1=t2.c1"
 // 2. Postgres: "c1=excluded.c1"
+// 3. SQLite: "c1=excluded.c1"
 func FmtConflictUpdateTarget(tableName string, colName string) string {
 	dialect, envFound := os.LookupEnv("SQL_DIALECT")
 	if !envFound {
@@ -331,7 +495,7 @@ func FmtConflictUpdateTarget(tableName string, colName string) string {
 
 	var upsertColumnPrefix string
 	switch strings.ToLower(dialect) {
-	case PostgresDialect:
+	case PostgresDialect, SQLiteDialect:
 		upsertColumnPrefix = "excluded"
 	case MariaDialect:
 		upsertColumnPrefix = tableName
@@ -353,8 +517,8 @@ func setValuesToUpsertClause(setValues []UpsertValue, writeSet bool) (string, []
 	}).([]string)
 	setClause := strings.Join(setParts, ", ")
 
-	// This is sloppy but we can make it nice if we ever have to support more
-	// than just psql and mysql
+	// This is sloppy but we can make it nice if we ever have to support a
+	// dialect with neither psql nor mysql upsert syntax
 	var upsertClause string
 	if writeSet {
 		upsertClause = fmt.Sprintf("UPDATE SET %s", setClause)
@@ -14,6 +14,7 @@
 package sqorc
 
 import (
+	"os"
 	"testing"
 
 	"github.com/Masterminds/squirrel"
@@ -102,6 +103,71 @@ func TestMysqlInsertBuilder_OnConflict(t *testing.T) {
 	runCases(t, cases, ib)
 }
 
+func TestSQLiteInsertBuilder_OnConflict(t *testing.T) {
+	ib := sqliteStatementBuilder{squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question)}.Insert("table")
+
+	cases := []testCase{
+		{
+			setValues:    nil,
+			columns:      []string{"foo"},
+			expectedSql:  "INSERT INTO table (foo,bar) VALUES (?,?) ON CONFLICT (foo) DO NOTHING",
+			expectedArgs: []interface{}{},
+		},
+		{
+			setValues: []UpsertValue{
+				{Column: "foo", Value: 1},
+				{Column: "bar", Value: squirrel.Expr("excluded.bar")},
+			},
+			columns:      []string{"foo", "bar"},
+			expectedSql:  "INSERT INTO table (foo,bar) VALUES (?,?) ON CONFLICT (foo, bar) DO UPDATE SET foo = ?, bar = excluded.bar",
+			expectedArgs: []interface{}{1},
+		},
+	}
+	runCases(t, cases, ib)
+}
+
+func TestStatementBuilder_ForUpdateSkipLocked(t *testing.T) {
+	sel := squirrel.Select("foo").From("table").Limit(1)
+
+	actual, _, err := NewPostgresStatementBuilder().ForUpdateSkipLocked(sel).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT foo FROM table LIMIT 1 FOR UPDATE SKIP LOCKED", actual)
+
+	actual, _, err = NewMariaDBStatementBuilder().ForUpdateSkipLocked(sel).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT foo FROM table LIMIT 1 FOR UPDATE SKIP LOCKED", actual)
+
+	actual, _, err = NewSQLiteStatementBuilder().ForUpdateSkipLocked(sel).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT foo FROM table LIMIT 1", actual)
+}
+
+func TestGetInsertChunkSize(t *testing.T) {
+	assert.Equal(t, 8191, GetInsertChunkSize(NewPostgresStatementBuilder(), 8))
+	assert.Equal(t, 8191, GetInsertChunkSize(NewMariaDBStatementBuilder(), 8))
+	assert.Equal(t, 124, GetInsertChunkSize(NewSQLiteStatementBuilder(), 8))
+	assert.Equal(t, 999, GetInsertChunkSize(NewSQLiteStatementBuilder(), 0))
+}
+
+func TestGetSqlBuilder(t *testing.T) {
+	defer os.Unsetenv("SQL_DIALECT")
+
+	os.Unsetenv("SQL_DIALECT")
+	assert.IsType(t, postgresStatementBuilder{}, GetSqlBuilder())
+	assert.Equal(t, "excluded.foo", FmtConflictUpdateTarget("table", "foo"))
+
+	os.Setenv("SQL_DIALECT", MariaDialect)
+	assert.IsType(t, mariaDBStatementBuilder{}, GetSqlBuilder())
+	assert.Equal(t, "table.foo", FmtConflictUpdateTarget("table", "foo"))
+
+	os.Setenv("SQL_DIALECT", SQLiteDialect)
+	assert.IsType(t, sqliteStatementBuilder{}, GetSqlBuilder())
+	assert.Equal(t, "excluded.foo", FmtConflictUpdateTarget("table", "foo"))
+
+	os.Setenv("SQL_DIALECT", "oracle")
+	assert.Panics(t, func() { GetSqlBuilder() })
+}
+
 func runCases(t *testing.T, tcs []testCase, ib InsertBuilder) {
 	for _, tc := range tcs {
 		actualSql, actualArgs, err := ib.Columns("foo", "bar").Values("fooV", "barV").OnConflict(tc.setValues, tc.columns...).ToSql()
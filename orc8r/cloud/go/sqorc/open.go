@@ -15,6 +15,7 @@ package sqorc
 
 import (
 	"database/sql"
+	"net/url"
 	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -30,13 +31,33 @@ const (
 	SQLiteDriver   = "sqlite3"
 )
 
+// sqliteDefaultParams are the connection parameters set on sqlite3 data
+// sources, unless the source sets them itself.
+//	- _txlock=immediate acquires the database's write lock at the start of
+//	  each transaction, standing in for the row-level locks of other dialects
+//	- _busy_timeout waits for the write lock rather than failing immediately
+//	- _foreign_keys enforces foreign key constraints, which sqlite3 ignores by
+//	  default
+var sqliteDefaultParams = map[string]string{
+	"_txlock":       "immediate",
+	"_busy_timeout": "10000",
+	"_foreign_keys": "1",
+}
+
 // Open is a wrapper for sql.Open which sets the max open connections to 1
 // for in memory sqlite3 dbs. In memory sqlite3 creates a new database
 // on each connection, so the number of open connections must be limited
 // to 1 for thread safety. Otherwise, there is a race condition between
 // threads using a cached connection to the original database or opening
 // a new connection to a new database.
+//
+// For sqlite3, Open also sets the connection parameters needed to share the
+// database between goroutines and processes. File dbs use write-ahead
+// logging so reads don't block on writes.
 func Open(driver string, source string) (*sql.DB, error) {
+	if driver == SQLiteDriver {
+		source = withSQLiteDefaults(source)
+	}
 	db, err := sql.Open(driver, source)
 	if err != nil {
 		return nil, err
@@ -46,3 +67,26 @@ func Open(driver string, source string) (*sql.DB, error) {
 	}
 	return db, nil
 }
+
+func withSQLiteDefaults(source string) string {
+	path, rawParams := source, ""
+	if i := strings.IndexRune(source, '?'); i >= 0 {
+		path, rawParams = source[:i], source[i+1:]
+	}
+	params, err := url.ParseQuery(rawParams)
+	if err != nil {
+		// Leave malformed sources for the driver to report
+		return source
+	}
+
+	for k, v := range sqliteDefaultParams {
+		if _, ok := params[k]; !ok {
+			params.Set(k, v)
+		}
+	}
+	_, hasJournalMode := params["_journal_mode"]
+	if !hasJournalMode && !strings.Contains(path, ":memory:") && params.Get("mode") != "memory" {
+		params.Set("_journal_mode", "WAL")
+	}
+	return path + "?" + params.Encode()
+}
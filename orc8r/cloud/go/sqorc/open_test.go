@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqorc
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSQLiteDefaults(t *testing.T) {
+	assert.Equal(t, ":memory:?_busy_timeout=10000&_foreign_keys=1&_txlock=immediate", withSQLiteDefaults(":memory:"))
+	assert.Equal(t, "file::memory:?_busy_timeout=10000&_foreign_keys=1&_txlock=immediate&cache=shared", withSQLiteDefaults("file::memory:?cache=shared"))
+	assert.Equal(t, "/var/opt/magma/orc8r.db?_busy_timeout=10000&_foreign_keys=1&_journal_mode=WAL&_txlock=immediate", withSQLiteDefaults("/var/opt/magma/orc8r.db"))
+	// Explicit params are kept
+	assert.Equal(t, "orc8r.db?_busy_timeout=10&_foreign_keys=0&_journal_mode=DELETE&_txlock=deferred", withSQLiteDefaults("orc8r.db?_busy_timeout=10&_foreign_keys=0&_journal_mode=DELETE&_txlock=deferred"))
+}
+
+func TestOpen_SQLite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqorc")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	source := filepath.Join(dir, "test.db")
+
+	db1, err := Open(SQLiteDriver, source)
+	require.NoError(t, err)
+	defer db1.Close()
+	db2, err := Open(SQLiteDriver, source)
+	require.NoError(t, err)
+	defer db2.Close()
+
+	builder := NewSQLiteStatementBuilder()
+	_, err = builder.CreateTable("parent").
+		IfNotExists().
+		Column("id").Type(ColumnTypeText).PrimaryKey().EndColumn().
+		RunWith(db1).
+		Exec()
+	require.NoError(t, err)
+	_, err = builder.CreateTable("child").
+		IfNotExists().
+		Column("id").Type(ColumnTypeText).PrimaryKey().EndColumn().
+		Column("parent").Type(ColumnTypeText).References("parent", "id").OnDelete(ColumnOnDeleteCascade).EndColumn().
+		Column("value").Type(ColumnTypeBytes).EndColumn().
+		RunWith(db1).
+		Exec()
+	require.NoError(t, err)
+
+	// Foreign keys are enforced
+	_, err = builder.Insert("child").Columns("id", "parent").Values("c1", "p1").RunWith(db1).Exec()
+	assert.EqualError(t, err, "FOREIGN KEY constraint failed")
+
+	// Upsert
+	_, err = builder.Insert("parent").Columns("id").Values("p1").RunWith(db1).Exec()
+	require.NoError(t, err)
+	for _, value := range []string{"foo", "bar"} {
+		_, err = builder.Insert("child").
+			Columns("id", "parent", "value").
+			Values("c1", "p1", []byte(value)).
+			OnConflict([]UpsertValue{{Column: "value", Value: squirrel.Expr("excluded.value")}}, "id").
+			RunWith(db1).
+			Exec()
+		require.NoError(t, err)
+	}
+	var value []byte
+	err = builder.Select("value").From("child").Where(squirrel.Eq{"id": "c1"}).RunWith(db2).QueryRow().Scan(&value)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("bar"), value)
+
+	// Writable transactions on separate connections are serialized
+	tx1, err := db1.Begin()
+	require.NoError(t, err)
+	locked := make(chan error)
+	go func() {
+		_, err := ExecInTx(db2, nil, nil, func(tx *sql.Tx) (interface{}, error) {
+			return builder.Delete("parent").RunWith(tx).Exec()
+		})
+		locked <- err
+	}()
+	var count int
+	err = builder.ForUpdateSkipLocked(builder.Select("COUNT(*)").From("child")).RunWith(tx1).QueryRow().Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.NoError(t, tx1.Commit())
+	assert.NoError(t, <-locked)
+
+	// Cascading delete
+	err = builder.Select("COUNT(*)").From("child").RunWith(db1).QueryRow().Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
@@ -32,9 +32,10 @@ func init() {
 }
 
 /*
-Because we are only supporting psql and mysql right now and the only difference
-between those dialects for table creation is the names of column types, we can
-use concrete types for the CREATE TABLE builder and column builder.
+Because we are only supporting psql, mysql and sqlite right now and the only
+difference between those dialects for table creation is the names of column
+types, we can use concrete types for the CREATE TABLE builder and column
+builder.
 
 The parameterized difference between the dialects is stored as a mapping of
 column type to name inside the data structure for each builder.
@@ -60,6 +61,17 @@ var mariaColumnTypeMap = map[ColumnType]string{
 	ColumnTypeBool:  "BOOLEAN",
 }
 
+var sqliteColumnTypeMap = map[ColumnType]string{
+	ColumnTypeText: "TEXT",
+	// SQLite integers are always stored in up to 8 bytes, so both int types
+	// map to INTEGER
+	ColumnTypeInt:    "INTEGER",
+	ColumnTypeBigInt: "INTEGER",
+	// BYTEA would be given NUMERIC type affinity by SQLite
+	ColumnTypeBytes: "BLOB",
+	ColumnTypeBool:  "BOOLEAN",
+}
+
 // ColumnOnDeleteOption is an enum type to specify ON DELETE behavior for
 // foreign keys
 type ColumnOnDeleteOption uint8
@@ -212,6 +212,24 @@ func TestCreateTableBuilder_ToSql(t *testing.T) {
 		"pk VARCHAR(255) PRIMARY KEY\n" +
 		")"
 	assert.Equal(t, expected, actual)
+
+	// sqlite
+	actual, _, err = tableBuilder(sqliteColumnTypeMap).
+		Name("foobar").
+		IfNotExists().
+		Column("pk").Type(ColumnTypeText).PrimaryKey().EndColumn().
+		Column("foo").Type(ColumnTypeBytes).NotNull().References("barbaz", "bites").OnDelete(ColumnOnDeleteCascade).EndColumn().
+		Column("bar").Type(ColumnTypeBigInt).Default(42).EndColumn().
+		Unique("foo", "bar").
+		ToSql()
+	assert.NoError(t, err)
+	expected = "CREATE TABLE IF NOT EXISTS foobar (\n" +
+		"pk TEXT PRIMARY KEY,\n" +
+		"foo BLOB NOT NULL REFERENCES barbaz (bites) ON DELETE CASCADE,\n" +
+		"bar INTEGER DEFAULT 42,\n" +
+		"UNIQUE (foo, bar)\n" +
+		")"
+	assert.Equal(t, expected, actual)
 }
 
 func TestCreateTableBuilder_Exec(t *testing.T) {
@@ -17,6 +17,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"magma/orc8r/lib/go/definitions"
@@ -50,6 +51,15 @@ const (
 // OpenCleanForTest is the same as OpenForTest, except it also drops then
 // creates the underlying DB name before returning.
 func OpenCleanForTest(t *testing.T, dbName, dbDriver string) *sql.DB {
+	if definitions.GetEnvWithDefault("SQL_DRIVER", dbDriver) == SQLiteDriver {
+		// sqlite3 DBs are files, created on first connection
+		err := os.Remove(getSQLiteTestPath(dbName))
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatalf("Failed to drop test DB: %s", err)
+		}
+		return OpenForTest(t, dbName, dbDriver)
+	}
+
 	rootDB := OpenForTest(t, "", dbDriver)
 	_, err := rootDB.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbName))
 	if err != nil {
@@ -72,6 +82,7 @@ func OpenCleanForTest(t *testing.T, dbName, dbDriver string) *sql.DB {
 // Supported DB drivers include:
 //	- postgres
 //	- mysql
+//	- sqlite3, with each DB name stored as a file in the temp directory
 // Environment variables:
 //	- SQL_DRIVER overrides the Go SQL driver
 //	- TEST_DATABASE_HOST overrides the DB connection host
@@ -100,11 +111,17 @@ func setDialect(driver string) {
 		_ = os.Setenv("SQL_DIALECT", PostgresDialect)
 	case MariaDriver:
 		_ = os.Setenv("SQL_DIALECT", MariaDialect)
+	case SQLiteDriver:
+		_ = os.Setenv("SQL_DIALECT", SQLiteDialect)
 	}
 }
 
 // getSource returns the driver-specific data source name.
 func getSource(t *testing.T, dbName, driver string) string {
+	if driver == SQLiteDriver {
+		return getSQLiteTestPath(dbName)
+	}
+
 	host := getHost(t, driver)
 	port := getPort(t, driver)
 
@@ -157,3 +174,8 @@ func getPort(t *testing.T, driver string) string {
 
 	return port
 }
+
+// getSQLiteTestPath returns the path of the file backing a sqlite3 test DB.
+func getSQLiteTestPath(dbName string) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("magma_test_%s.db", dbName))
+}
@@ -17,6 +17,7 @@ package storage
 
 import (
 	"fmt"
+	"math"
 	"sort"
 
 	"magma/orc8r/lib/go/definitions"
@@ -163,3 +164,52 @@ func GetSQLDriver() string {
 func GetDatabaseSource() string {
 	return definitions.MustGetEnv("DATABASE_SOURCE")
 }
+
+// VersionAbsent is the version of an object which doesn't exist. As an
+// expected version in a conditional write, it requires the object to not
+// exist, i.e. makes the write create-only.
+const VersionAbsent uint64 = math.MaxUint64
+
+// VersionConflictError is returned by conditional writes when the stored
+// versions of some objects don't match the versions expected by the caller.
+type VersionConflictError struct {
+	// Versions maps the ID of each mismatched object to its stored version.
+	// A stored version of VersionAbsent indicates the object doesn't exist.
+	Versions map[TypeAndKey]uint64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict on %v", e.IDs())
+}
+
+// IDs returns the IDs of the mismatched objects, sorted.
+func (e *VersionConflictError) IDs() TKs {
+	ids := make(TKs, 0, len(e.Versions))
+	for id := range e.Versions {
+		ids = append(ids, id)
+	}
+	ids.Sort()
+	return ids
+}
+
+// CheckVersions compares the stored versions of objects against the versions
+// expected by a conditional write. Objects missing from stored are treated as
+// not existing, i.e. as having version VersionAbsent.
+// Returns a *VersionConflictError listing every mismatch, or nil if all
+// versions match.
+func CheckVersions(expected map[TypeAndKey]uint64, stored map[TypeAndKey]uint64) error {
+	conflicts := map[TypeAndKey]uint64{}
+	for id, version := range expected {
+		storedVersion, exists := stored[id]
+		if !exists {
+			storedVersion = VersionAbsent
+		}
+		if storedVersion != version {
+			conflicts[id] = storedVersion
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return &VersionConflictError{Versions: conflicts}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package handlers implements individual accessc commands as well as common
+// across multiple commands functionality
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"magma/orc8r/cloud/go/identity"
+	"magma/orc8r/cloud/go/services/accessd"
+	accessprotos "magma/orc8r/cloud/go/services/accessd/protos"
+	"magma/orc8r/cloud/go/tools/commands"
+	"magma/orc8r/lib/go/protos"
+)
+
+var (
+	bindRole     string
+	bindNetworks string
+	bindTenants  string
+)
+
+// Role binding commands - bind Roles to & unbind Roles from an Operator
+func init() {
+	bindCmd := CommandRegistry.Add(
+		"bind",
+		"Bind a Role to an Operator, in addition to its existing Role bindings",
+		bind)
+	f := bindCmd.Flags()
+	f.Usage = func() {
+		fmt.Fprintf(os.Stderr, // std Usage() & PrintDefaults() use Stderr
+			"\tUsage: %s %s [OPTIONS] <Operator ID>\n",
+			os.Args[0], bindCmd.Name())
+		f.PrintDefaults()
+	}
+	f.StringVar(&bindRole, "r", "", "Role name")
+	f.StringVar(&bindNetworks, "n", "",
+		"Comma separated network IDs to scope the binding to")
+	f.StringVar(&bindTenants, "t", "",
+		"Comma separated tenant IDs to scope the binding to their networks. "+
+			"The binding is global if neither networks nor tenants are given")
+
+	unbindCmd := CommandRegistry.Add(
+		"unbind",
+		"Remove all of an Operator's bindings to a Role",
+		unbind)
+	uf := unbindCmd.Flags()
+	uf.Usage = func() {
+		fmt.Fprintf(os.Stderr,
+			"\tUsage: %s %s -r <Role Name> <Operator ID>\n",
+			os.Args[0], unbindCmd.Name())
+		uf.PrintDefaults()
+	}
+	uf.StringVar(&bindRole, "r", "", "Role name")
+}
+
+func bind(cmd *commands.Command, args []string) int {
+	operator, bindings := getOperatorRoleBindings(cmd)
+	binding := &accessprotos.RoleBinding{Role: bindRole, Networks: splitList(bindNetworks)}
+	for _, tidStr := range splitList(bindTenants) {
+		tid, err := strconv.ParseInt(tidStr, 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid tenant ID '%s': %s", tidStr, err)
+		}
+		binding.Tenants = append(binding.Tenants, tid)
+	}
+	setOperatorRoleBindings(operator, append(bindings, binding))
+	return 0
+}
+
+func unbind(cmd *commands.Command, args []string) int {
+	operator, bindings := getOperatorRoleBindings(cmd)
+	var remaining []*accessprotos.RoleBinding
+	for _, binding := range bindings {
+		if binding.Role != bindRole {
+			remaining = append(remaining, binding)
+		}
+	}
+	setOperatorRoleBindings(operator, remaining)
+	return 0
+}
+
+func getOperatorRoleBindings(cmd *commands.Command) (*protos.Identity, []*accessprotos.RoleBinding) {
+	f := cmd.Flags()
+	oid := strings.TrimSpace(f.Arg(0))
+	if f.NArg() != 1 || len(oid) == 0 {
+		f.Usage()
+		log.Fatalf("A single Operator Id must be specified.")
+	}
+	if len(bindRole) == 0 {
+		f.Usage()
+		log.Fatalf("A Role name must be specified.")
+	}
+	operator := identity.NewOperator(oid)
+	bindings, err := accessd.GetRoleBindings(context.Background(), operator)
+	if err != nil {
+		log.Fatalf("Get Role Bindings for %s Error: %s", operator.HashString(), err)
+	}
+	return operator, bindings
+}
+
+func setOperatorRoleBindings(operator *protos.Identity, bindings []*accessprotos.RoleBinding) {
+	err := accessd.SetRoleBindings(context.Background(), operator, bindings)
+	if err != nil {
+		log.Fatalf("Set Role Bindings for %s Error: %s", operator.HashString(), err)
+	}
+	fmt.Printf("Operator %s Role Bindings:\n", operator.HashString())
+	for _, binding := range bindings {
+		fmt.Printf(
+			"\t%s: networks: %v, tenants: %v\n",
+			binding.Role, binding.Networks, binding.Tenants)
+	}
+}
+
+func splitList(list string) []string {
+	var ret []string
+	for _, s := range strings.Split(list, ",") {
+		s = strings.TrimSpace(s)
+		if len(s) > 0 {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
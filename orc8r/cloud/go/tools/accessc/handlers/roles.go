@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package handlers implements individual accessc commands as well as common
+// across multiple commands functionality
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"magma/orc8r/cloud/go/services/accessd"
+	accessprotos "magma/orc8r/cloud/go/services/accessd/protos"
+	"magma/orc8r/cloud/go/tools/commands"
+)
+
+// Rules - list of role rules compiled from command line flags
+type Rules []*accessprotos.Role_Rule
+
+var (
+	roleDescription string
+	roleRules       Rules
+)
+
+// String - stringer for rules
+func (rules *Rules) String() string {
+	if rules == nil {
+		return "<nil>"
+	}
+	var res []string
+	for _, rule := range *rules {
+		res = append(res, ruleToString(rule))
+	}
+	return strings.Join(res, "; ")
+}
+
+// Set adds a new Rule from provided flag value string
+func (rules *Rules) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	path := strings.TrimSpace(parts[0])
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("Invalid Rule Specification for '%s', expected <absolute path>[=METHOD,...]", value)
+	}
+	rule := &accessprotos.Role_Rule{Path: path}
+	if len(parts) == 2 {
+		for _, method := range strings.Split(parts[1], ",") {
+			method = strings.ToUpper(strings.TrimSpace(method))
+			if len(method) > 0 {
+				rule.Methods = append(rule.Methods, method)
+			}
+		}
+	}
+	*rules = append(*rules, rule)
+	return nil
+}
+
+// Role commands - list, create/overwrite & delete roles
+func init() {
+	cmd := CommandRegistry.Add(
+		"list_roles",
+		"List all built-in and custom Roles and their Rules",
+		listRoles)
+	cmd.Flags().Usage = func() {
+		fmt.Fprintf(os.Stderr, "\tUsage: %s %s\n", os.Args[0], cmd.Name())
+	}
+
+	setCmd := CommandRegistry.Add(
+		"set_role",
+		"Create or overwrite a custom Role",
+		setRole)
+	f := setCmd.Flags()
+	f.Usage = func() {
+		fmt.Fprintf(os.Stderr, // std Usage() & PrintDefaults() use Stderr
+			"\tUsage: %s %s [OPTIONS] <Role Name>\n",
+			os.Args[0], setCmd.Name())
+		f.PrintDefaults()
+	}
+	f.StringVar(&roleDescription, "d", "", "Role description")
+	f.Var(&roleRules, "r",
+		"Rule in the form: <path prefix>[=METHOD,...], e.g. "+
+			"'/magma/v1/lte/:network_id/subscribers=GET,HEAD'. "+
+			"All methods are allowed if none are given")
+
+	deleteCmd := CommandRegistry.Add(
+		"delete_role",
+		"Delete a custom Role",
+		deleteRole)
+	deleteCmd.Flags().Usage = func() {
+		fmt.Fprintf(os.Stderr,
+			"\tUsage: %s %s <Role Name>\n", os.Args[0], deleteCmd.Name())
+	}
+}
+
+func listRoles(cmd *commands.Command, args []string) int {
+	roles, err := accessd.ListRoles(context.Background())
+	if err != nil {
+		log.Fatalf("List Roles Error: %s", err)
+	}
+	fmt.Println("Roles:")
+	for _, role := range roles {
+		PrintRole(role)
+	}
+	return 0
+}
+
+func setRole(cmd *commands.Command, args []string) int {
+	f := cmd.Flags()
+	name := strings.TrimSpace(f.Arg(0))
+	if f.NArg() != 1 || len(name) == 0 {
+		f.Usage()
+		log.Fatalf("A single Role name must be specified.")
+	}
+	if len(roleRules) == 0 {
+		f.Usage()
+		log.Fatalf("At least one Rule must be specified.")
+	}
+	role := &accessprotos.Role{Name: name, Description: roleDescription, Rules: roleRules}
+	err := accessd.SetRole(context.Background(), role)
+	if err != nil {
+		log.Fatalf("Set Role %s Error: %s", name, err)
+	}
+	fmt.Print("Set Role:\n")
+	PrintRole(role)
+	return 0
+}
+
+func deleteRole(cmd *commands.Command, args []string) int {
+	f := cmd.Flags()
+	name := strings.TrimSpace(f.Arg(0))
+	if f.NArg() != 1 || len(name) == 0 {
+		f.Usage()
+		log.Fatalf("A single Role name must be specified.")
+	}
+	err := accessd.DeleteRole(context.Background(), name)
+	if err != nil {
+		log.Fatalf("Delete Role %s Error: %s", name, err)
+	}
+	return 0
+}
+
+// PrintRole - prints role name, description & rules
+func PrintRole(role *accessprotos.Role) {
+	fmt.Printf("\t%s: %s\n\t\tRules:\n", role.Name, role.Description)
+	for _, rule := range role.Rules {
+		fmt.Printf("\t\t  %s\n", ruleToString(rule))
+	}
+	fmt.Println()
+}
+
+func ruleToString(rule *accessprotos.Role_Rule) string {
+	methods := "*"
+	if len(rule.Methods) > 0 {
+		methods = strings.Join(rule.Methods, ",")
+	}
+	return fmt.Sprintf("%s=%s", rule.Path, methods)
+}
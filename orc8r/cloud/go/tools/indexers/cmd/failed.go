@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"magma/orc8r/cloud/go/services/state/protos"
+
+	"github.com/spf13/cobra"
+)
+
+var failedCmd = &cobra.Command{
+	Use:   "failed",
+	Short: "List state batches whose delivery to an indexer exhausted their retries",
+	Run:   runFailed,
+}
+
+var replayCmd = &cobra.Command{
+	Use:   "replay [batch IDs]",
+	Short: "Replay failed state batches of an indexer, or all if no batch IDs are specified",
+	Run:   runReplay,
+}
+
+func init() {
+	rootCmd.AddCommand(failedCmd)
+	failedCmd.Flags().StringVarP(&failedID, "id", "i", "", "restrict to specific indexer ID")
+
+	rootCmd.AddCommand(replayCmd)
+	replayCmd.Flags().StringVarP(&replayID, "id", "i", "", "indexer ID whose batches to replay")
+	_ = replayCmd.MarkFlagRequired("id")
+}
+
+func runFailed(cmd *cobra.Command, args []string) {
+	res, err := getClient().GetFailedBatches(context.Background(), &protos.GetFailedBatchesRequest{IndexerId: failedID})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, b := range res.Batches {
+		enqueuedAt := time.Unix(b.EnqueuedAt, 0).UTC().Format(time.RFC3339)
+		fmt.Printf("{batch_id: %s, indexer_id: %s, network_id: %s, num_states: %d, attempts: %d, enqueued_at: %s, error: %s}\n",
+			b.BatchId, b.IndexerId, b.NetworkId, len(b.StateIds), b.Attempts, enqueuedAt, b.Error)
+	}
+}
+
+func runReplay(cmd *cobra.Command, args []string) {
+	res, err := getClient().ReplayFailedBatches(context.Background(), &protos.ReplayFailedBatchesRequest{IndexerId: replayID, BatchIds: args})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Replayed %d failed batches\n", res.NumReplayed)
+}
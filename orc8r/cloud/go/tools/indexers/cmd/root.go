@@ -33,6 +33,8 @@ var (
 	listShort    bool
 	reindexID    string
 	reindexForce bool
+	failedID     string
+	replayID     string
 )
 
 func init() {
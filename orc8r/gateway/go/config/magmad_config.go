@@ -30,11 +30,34 @@ const (
 	DefaultChallengeKeyFile = "/var/opt/magma/certs/gw_challenge.key"
 	DefaultStaticConfigDir  = "/etc/magma"
 	DefaultDynamicConfigDir = "/var/opt/magma/configs"
+	DefaultTPMEKHandle      = "0x81010001"
+	DefaultTPMAKHandle      = "0x81010002"
+	DefaultTPMQuotePCRs     = "sha256:0,1,2,3,4,5,6,7"
 )
 
 // BootstrapConfig bootstrapper related configuration - `yaml:"bootstrap_config"`
 type BootstrapConfig struct {
-	ChallengeKey string `yaml:"challenge_key"`
+	ChallengeKey string    `yaml:"challenge_key"`
+	TPM          TPMConfig `yaml:"tpm"`
+}
+
+// TPMConfig configures the TPM used for TPM2_ATTESTATION bootstrap
+// challenges, via tpm2-tools - `yaml:"tpm"`
+type TPMConfig struct {
+	// TCTI selects the TPM, e.g. "device:/dev/tpmrm0", or "swtpm:port=2321"
+	// for a software TPM simulator. Defaults to the tpm2-tools default.
+	TCTI string `yaml:"tcti"`
+	// EKHandle and AKHandle are the persistent handles of the endorsement and
+	// attestation keys
+	EKHandle string `yaml:"ek_handle"`
+	AKHandle string `yaml:"ak_handle"`
+	// QuotePCRs are the PCRs quoted, in tpm2-tools PCR selection format
+	QuotePCRs string `yaml:"quote_pcrs"`
+}
+
+// NewDefaultTPMConfig returns new default TPM configs
+func NewDefaultTPMConfig() TPMConfig {
+	return TPMConfig{EKHandle: DefaultTPMEKHandle, AKHandle: DefaultTPMAKHandle, QuotePCRs: DefaultTPMQuotePCRs}
 }
 
 // MagmadCfg represents magmad.yml based configuration
@@ -136,7 +159,7 @@ func NewDefaultMgmadCfg() *MagmadCfg {
 		InitSystem:                       "",
 		StaticMconfigDir:                 DefaultStaticConfigDir,
 		DynamicMconfigDir:                DefaultDynamicConfigDir,
-		BootstrapConfig:                  BootstrapConfig{ChallengeKey: DefaultChallengeKeyFile},
+		BootstrapConfig:                  BootstrapConfig{ChallengeKey: DefaultChallengeKeyFile, TPM: NewDefaultTPMConfig()},
 		EnableConfigStreamer:             true,
 		EnableUpgradeMamager:             false,
 		EnableNetworkMonitor:             false,
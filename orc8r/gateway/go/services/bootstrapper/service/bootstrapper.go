@@ -56,6 +56,9 @@ type Bootstrapper struct {
 	// CompletionChan is an optional chan which will receive BootstrapCompletion on every
 	// successful or failed (Result ==/!= nil) bootstrap event
 	CompletionChan chan interface{}
+	// TPM is an optional TPM used for TPM2_ATTESTATION challenges, if not set - the TPM
+	// configured in magmad's bootstrap_config is used via tpm2-tools
+	TPM TPM
 	//
 	// private, caches, tmps
 	//
@@ -289,21 +292,10 @@ func (b *Bootstrapper) bootstrap() (*protos.Certificate, interface{}, error) {
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get Bootstrapper challenge: %v", err)
 	}
-	if challenge.KeyType != protos.ChallengeKey_SOFTWARE_ECDSA_SHA256 {
-		return nil, nil, fmt.Errorf("unsupported Bootstrapper challenge type: %s", challenge.KeyType.String())
-	}
-	hashed := sha256.Sum256(challenge.Challenge)
-	r, s, err := ecdsa.Sign(rand.Reader, b.challengeKey, hashed[:])
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to sign challenge: %v", err)
-	}
 	// create challenge response
 	resp := &protos.Response{
 		HwId:      &protos.AccessGatewayID{Id: b.HardwareId},
 		Challenge: challenge.Challenge,
-		Response: &protos.Response_EcdsaResponse{
-			EcdsaResponse: &protos.Response_ECDSA{R: r.Bytes(), S: s.Bytes()},
-		},
 		Csr: &protos.CSR{
 			Id:        protos.NewGatewayIdentity(b.HardwareId, "", ""),
 			ValidTime: ptypes.DurationProto(PREEXPIRY_BOOTSTRAP_INTERVAL * 5),
@@ -311,6 +303,29 @@ func (b *Bootstrapper) bootstrap() (*protos.Certificate, interface{}, error) {
 			CertType:  protos.CertType_DEFAULT,
 		},
 	}
+	switch challenge.KeyType {
+	case protos.ChallengeKey_SOFTWARE_ECDSA_SHA256:
+		hashed := sha256.Sum256(challenge.Challenge)
+		r, s, err := ecdsa.Sign(rand.Reader, b.challengeKey, hashed[:])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to sign challenge: %v", err)
+		}
+		resp.Response = &protos.Response_EcdsaResponse{
+			EcdsaResponse: &protos.Response_ECDSA{R: r.Bytes(), S: s.Bytes()},
+		}
+	case protos.ChallengeKey_TPM2_ATTESTATION:
+		tpm := b.TPM
+		if tpm == nil {
+			tpm = newTPM2Tools(config.GetMagmadConfigs().BootstrapConfig.TPM)
+		}
+		quote, err := respondTPM(tpm, challenge)
+		if err != nil {
+			return nil, nil, err
+		}
+		resp.Response = &protos.Response_TpmResponse{TpmResponse: quote}
+	default:
+		return nil, nil, fmt.Errorf("unsupported Bootstrapper challenge type: %s", challenge.KeyType.String())
+	}
 	newCert, err := client.RequestSign(context.Background(), resp)
 
 	if err != nil {
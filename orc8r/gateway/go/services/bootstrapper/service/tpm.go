@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// package service implements the core of bootstrapper
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"magma/gateway/config"
+	"magma/orc8r/lib/go/protos"
+)
+
+// tpm2ToolsCredentialMagic and tpm2ToolsCredentialVersion prefix the
+// credential files read by tpm2_activatecredential
+const (
+	tpm2ToolsCredentialMagic   uint32 = 0xBADCC0DE
+	tpm2ToolsCredentialVersion uint32 = 1
+)
+
+// TPM is a TPM 2.0 holding the gateway's endorsement and attestation keys,
+// used to respond to TPM2_ATTESTATION challenges.
+// tpm.Simulator from magma/orc8r/lib/go/security/tpm implements TPM for
+// testing.
+type TPM interface {
+	// ActivateCredential recovers the secret of a credential made for the
+	// TPM's endorsement and attestation keys, via TPM2_ActivateCredential
+	ActivateCredential(credentialBlob, encryptedSecret []byte) ([]byte, error)
+	// Quote returns a TPMS_ATTEST quote qualified by the passed data, and its
+	// TPMT_SIGNATURE by the attestation key
+	Quote(qualifyingData []byte) ([]byte, []byte, error)
+}
+
+// respondTPM responds to a TPM2_ATTESTATION challenge with a quote qualified
+// by the challenge and the secret of its credential.
+func respondTPM(tpm TPM, challenge *protos.Challenge) (*protos.Response_TPMQuote, error) {
+	credential := challenge.GetTpmCredential()
+	if credential == nil {
+		return nil, fmt.Errorf("TPM challenge is missing credential")
+	}
+	secret, err := tpm.ActivateCredential(credential.CredentialBlob, credential.EncryptedSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate TPM credential: %v", err)
+	}
+	qualifyingData := sha256.New()
+	qualifyingData.Write(challenge.Challenge)
+	qualifyingData.Write(secret)
+	quote, signature, err := tpm.Quote(qualifyingData.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to quote: %v", err)
+	}
+	return &protos.Response_TPMQuote{Quote: quote, Signature: signature}, nil
+}
+
+// tpm2Tools is a TPM accessed via the tpm2-tools CLI, which also supports
+// software TPM simulators via its TCTI option.
+type tpm2Tools struct {
+	cfg config.TPMConfig
+}
+
+func newTPM2Tools(cfg config.TPMConfig) *tpm2Tools {
+	defaults := config.NewDefaultTPMConfig()
+	if len(cfg.EKHandle) == 0 {
+		cfg.EKHandle = defaults.EKHandle
+	}
+	if len(cfg.AKHandle) == 0 {
+		cfg.AKHandle = defaults.AKHandle
+	}
+	if len(cfg.QuotePCRs) == 0 {
+		cfg.QuotePCRs = defaults.QuotePCRs
+	}
+	return &tpm2Tools{cfg: cfg}
+}
+
+func (t *tpm2Tools) ActivateCredential(credentialBlob, encryptedSecret []byte) ([]byte, error) {
+	dir, err := ioutil.TempDir("", "magma_tpm")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	credential := &bytes.Buffer{}
+	binary.Write(credential, binary.BigEndian, tpm2ToolsCredentialMagic)
+	binary.Write(credential, binary.BigEndian, tpm2ToolsCredentialVersion)
+	binary.Write(credential, binary.BigEndian, uint16(len(credentialBlob)))
+	credential.Write(credentialBlob)
+	binary.Write(credential, binary.BigEndian, uint16(len(encryptedSecret)))
+	credential.Write(encryptedSecret)
+	credentialFile, secretFile, sessionFile := filepath.Join(dir, "cred"), filepath.Join(dir, "secret"), filepath.Join(dir, "session.ctx")
+	if err = ioutil.WriteFile(credentialFile, credential.Bytes(), 0600); err != nil {
+		return nil, err
+	}
+
+	// Use of the endorsement key requires a policy session satisfying its
+	// endorsement hierarchy policy
+	if err = t.run("tpm2_startauthsession", "--policy-session", "-S", sessionFile); err != nil {
+		return nil, err
+	}
+	defer t.run("tpm2_flushcontext", sessionFile)
+	if err = t.run("tpm2_policysecret", "-S", sessionFile, "-c", "e"); err != nil {
+		return nil, err
+	}
+	err = t.run(
+		"tpm2_activatecredential",
+		"-c", t.cfg.AKHandle,
+		"-C", t.cfg.EKHandle,
+		"-i", credentialFile,
+		"-o", secretFile,
+		"-P", "session:"+sessionFile,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(secretFile)
+}
+
+func (t *tpm2Tools) Quote(qualifyingData []byte) ([]byte, []byte, error) {
+	dir, err := ioutil.TempDir("", "magma_tpm")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	quoteFile, signatureFile := filepath.Join(dir, "quote"), filepath.Join(dir, "signature")
+	err = t.run(
+		"tpm2_quote",
+		"-c", t.cfg.AKHandle,
+		"-l", t.cfg.QuotePCRs,
+		"-q", hex.EncodeToString(qualifyingData),
+		"-g", "sha256",
+		"-m", quoteFile,
+		"-s", signatureFile,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	quote, err := ioutil.ReadFile(quoteFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	signature, err := ioutil.ReadFile(signatureFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return quote, signature, nil
+}
+
+func (t *tpm2Tools) run(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if len(t.cfg.TCTI) != 0 {
+		cmd.Env = append(os.Environ(), "TPM2TOOLS_TCTI="+t.cfg.TCTI)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %v; output: %s", name, err, out)
+	}
+	return nil
+}
@@ -135,7 +135,7 @@ func main() {
 			glog.Fatalf("configurator start error: %v", err)
 		}
 	}()
-	if err := service.StartMagmadServer(); err != nil {
+	if err := service.StartMagmadServer(b); err != nil {
 		glog.Fatalf("magmad start error: %v", err)
 	}
 }
@@ -38,8 +38,14 @@ import (
 	"magma/orc8r/lib/go/service"
 )
 
+// Rebootstrapper obtains a new gateway certificate on demand
+type Rebootstrapper interface {
+	ForceBootstrap() error
+}
+
 type magmadService struct {
 	protos.UnimplementedMagmadServer
+	rebootstrapper Rebootstrapper
 }
 
 func (m *magmadService) StartServices(context.Context, *protos.Void) (*protos.Void, error) {
@@ -209,19 +215,33 @@ func (m *magmadService) TailLogs(req *protos.TailLogsRequest, srv protos.Magmad_
 	return nil
 }
 
+// Rebootstrap forces the gateway to bootstrap in the background, obtaining a new certificate
+func (m *magmadService) Rebootstrap(context.Context, *protos.Void) (*protos.Void, error) {
+	if m.rebootstrapper == nil {
+		return nil, fmt.Errorf("bootstrapper is not configured")
+	}
+	glog.Info("Rebootstrapping Gateway")
+	go func() {
+		if err := m.rebootstrapper.ForceBootstrap(); err != nil {
+			glog.Errorf("rebootstrap error: %v", err)
+		}
+	}()
+	return &protos.Void{}, nil
+}
+
 // NewMagmadService returns a new magmad service
-func NewMagmadService() protos.MagmadServer {
-	return &magmadService{}
+func NewMagmadService(rebootstrapper Rebootstrapper) protos.MagmadServer {
+	return &magmadService{rebootstrapper: rebootstrapper}
 }
 
 // StartMagmadServer runs instance of the magmad grpc service
 // StartMagmadServer only returns on error and has to be run in its own Go routine or main thread
-func StartMagmadServer() error {
+func StartMagmadServer(rebootstrapper Rebootstrapper) error {
 	srv, err := service.NewServiceWithOptions("", strings.ToUpper(definitions.MagmadServiceName))
 	if err != nil {
 		return fmt.Errorf("error creating '%s' service: %v", definitions.MagmadServiceName, err)
 	}
-	protos.RegisterMagmadServer(srv.GrpcServer, NewMagmadService())
+	protos.RegisterMagmadServer(srv.GrpcServer, NewMagmadService(rebootstrapper))
 	glog.Infof("starting '%s' Service", definitions.MagmadServiceName)
 	err = srv.Run()
 	if err != nil {
@@ -156,6 +156,9 @@ func (cl *streamerClient) startStreaming(l *listener) (*grpc.ClientConn, protos.
 		return nil, nil, err
 	}
 	req := &protos.StreamRequest{GatewayId: "", StreamName: l.GetName(), ExtraArgs: l.GetExtraArgs()}
+	if vl, ok := l.Listener.(VersionedListener); ok {
+		req.Version = vl.GetVersion()
+	}
 	grpcStreamerClient, err := protos.NewStreamerClient(conn).GetUpdates(context.Background(), req)
 	if err != nil {
 		conn.Close()
@@ -42,3 +42,13 @@ type Listener interface {
 	// ExtraArgs field in GetUpdates request payload. Most listeners may just return nil
 	GetExtraArgs() *any.Any
 }
+
+// VersionedListener is a Listener which tracks the version of the stream's data
+// it last applied, to receive only the changes since that version
+type VersionedListener interface {
+	Listener
+	// GetVersion will be called prior to each stream request and its returned value will be used to initialize
+	// Version field in GetUpdates request payload. It should return the Version of the last DataUpdateBatch
+	// successfully applied by Update(), or an empty string to request a full snapshot
+	GetVersion() string
+}
@@ -74,6 +74,56 @@ class StreamerClient(threading.Thread):
             """
             raise NotImplementedError()
 
+    class VersionedCallback(Callback):
+        """
+        Callback for streams whose updates are versioned. The version last
+        applied is sent with each stream request, so the cloud can stream
+        only the changes since that version rather than a full snapshot.
+        """
+
+        @abc.abstractmethod
+        def get_version(self, stream_name: str) -> str:
+            """
+            This is called before every stream request to get the version of
+            the stream's data last applied by the callback.
+
+            Args:
+                stream_name: Name of the stream
+
+            Returns: The last applied version, or the empty string to
+                request a full snapshot
+            """
+            raise NotImplementedError()
+
+        @abc.abstractmethod
+        def process_versioned_update(
+            self, stream_name: str, updates: List[DataUpdate],
+            resync: bool, removed_keys: List[str], version: str,
+        ):
+            """
+            Called instead of process_update when we get an update from the
+            cloud. This method will be called in the event loop provided to
+            the StreamerClient.
+
+            Args:
+                stream_name: Name of the stream
+                updates: Array of updates
+                resync: if true, the application can clear the
+                    contents before applying the updates
+                removed_keys: keys to remove, only set when resync is false
+                version: version of the stream's data after applying the
+                    update, to be returned by subsequent get_version calls
+            """
+            raise NotImplementedError()
+
+        def process_update(
+            self, stream_name: str, updates: List[DataUpdate],
+            resync: bool,
+        ):
+            self.process_versioned_update(
+                stream_name, updates, resync, [], '',
+            )
+
     def __init__(self, stream_callbacks, loop):
         """
         Args:
@@ -139,9 +189,22 @@ class StreamerClient(threading.Thread):
             stream_name=stream_name,
             extra_args=extra_args,
         )
+        versioned = isinstance(callback, StreamerClient.VersionedCallback)
+        if versioned:
+            request.version = callback.get_version(stream_name)
         for update_batch in client.GetUpdates(
                 request, timeout=self._stream_timeout,
         ):
+            if versioned:
+                self._loop.call_soon_threadsafe(
+                    callback.process_versioned_update,
+                    stream_name,
+                    update_batch.updates,
+                    update_batch.resync,
+                    list(update_batch.removed_keys),
+                    update_batch.version,
+                )
+                continue
             self._loop.call_soon_threadsafe(
                 callback.process_update,
                 stream_name,
@@ -62,5 +62,6 @@ class CtraceDRpcServicer(CallTraceServiceServicer):
         response = EndTraceResponse(
             success=True,
             trace_content=res.data,
+            chunk_count=res.chunk_count,
         )
         return response
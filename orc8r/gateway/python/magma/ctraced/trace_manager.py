@@ -20,21 +20,33 @@ import threading
 import time
 from collections import namedtuple
 from subprocess import SubprocessError
-from typing import List
+from typing import List, Optional
 
 import grpc
 from magma.ctraced.command_builder import get_trace_builder
-from orc8r.protos.ctraced_pb2 import ReportEndedTraceRequest
+from orc8r.protos.ctraced_pb2 import (
+    ReportCallTraceChunkRequest,
+    ReportEndedTraceRequest,
+)
 from orc8r.protos.ctraced_pb2_grpc import CallTraceControllerStub
 
 _TRACE_FILE_NAME = "call_trace"
 _TRACE_FILE_NAME_POSTPROCESSED = "call_trace_postprocessed"
 _TRACE_FILE_EXT = "pcapng"
 _TRACE_FILE_WRITE_TIMEOUT = 20  # 20 seconds for TShark to write a trace to disk
-_MAX_FILESIZE = 4000  # ~ 4 MiB for a trace
+# Default bound on the size of a trace, in bytes, matching the default
+# max_trace_size of the cloud's ctraced
+_DEFAULT_MAX_TRACE_SIZE = 256 * 1024 * 1024  # 256 MiB
+# TShark only stops once a capture file exceeds its size limit, so captures
+# are stopped this far below the bound on the size of a trace
+_MAX_TRACE_SIZE_HEADROOM = 1024 * 1024  # 1 MiB
 _POSTPROCESSING_TIMEOUT = 10  # 10 seconds for TShark to apply display filters
+_CHUNK_SIZE = 1024 * 1024  # 1 MiB, traces above are uploaded in chunks
 
-EndTraceResult = namedtuple('EndTraceResult', ['success', 'data'])
+# Traces uploaded in chunks have a chunk_count, and no data
+EndTraceResult = namedtuple(
+    'EndTraceResult', ['success', 'data', 'chunk_count'],
+)
 
 
 class TraceManager:
@@ -67,6 +79,13 @@ class TraceManager:
         # TShark display filters are saved to postprocess packet capture files
         self._display_filters = ""  # type: str
 
+        # Bound on the size of a trace, in bytes. Should be at most the
+        # max_trace_size of the cloud's ctraced, which rejects larger traces
+        self._max_trace_size = config.get(
+            "max_trace_size",
+            _DEFAULT_MAX_TRACE_SIZE,
+        )  # type: int
+
         self._tool_name = config.get("trace_tool", "tshark")  # type: str
         self._trace_builder = get_trace_builder(self._tool_name)
 
@@ -107,7 +126,7 @@ class TraceManager:
 
         command = self._trace_builder.build_trace_command(
             self._trace_interfaces,
-            _MAX_FILESIZE,
+            self._get_max_filesize(),
             timeout,
             self._trace_filename,
             capture_filters,
@@ -122,28 +141,44 @@ class TraceManager:
 
         Returns:
             success: True if call trace finished without issue
-            data: Call trace file in bytes
+            data: Call trace file in bytes, if not uploaded in chunks
+            chunk_count: Number of chunks the call trace was uploaded in
         """
         # If trace is active, then stop it
         if self._is_active:
             self._is_stopping_trace = True
             stopped = self._stop_trace()
             if not stopped:
-                return EndTraceResult(False, None)
+                return EndTraceResult(False, None, 0)
             self._wait_until_trace_file_exists()
 
         # Perform postprocessing of capture file with TShark display filters
         succeeded = self._conditionally_postprocess_trace()
         if not succeeded:
-            return EndTraceResult(False, None)
+            return EndTraceResult(False, None, 0)
 
         data = self._get_final_trace_data()  # type: bytes
 
         self._cleanup_trace()
         logging.info("TraceManager: Call trace has ended")
 
+        chunk_count = self._upload_trace_chunks(data)
+        if chunk_count is None:
+            return EndTraceResult(False, None, 0)
+        if chunk_count > 0:
+            return EndTraceResult(True, None, chunk_count)
+
         # Everything cleaned up, return bytes
-        return EndTraceResult(True, data)
+        return EndTraceResult(True, data, 0)
+
+    def _get_max_filesize(self) -> int:
+        """Returns the max capture filesize, in KiB, keeping traces within
+        the configured bound on their size.
+        """
+        max_size = self._max_trace_size - _MAX_TRACE_SIZE_HEADROOM
+        if max_size <= 0:
+            max_size = self._max_trace_size // 2
+        return max(max_size // 1024, 1)
 
     def _execute_start_trace_command(self, command: List[str]) -> bool:
         """Executes a command to start a call trace
@@ -235,12 +270,23 @@ class TraceManager:
         self._report_trace_success(data)
 
     def _report_trace_success(self, data: bytes):
+        chunk_count = self._upload_trace_chunks(data)
+        if chunk_count is None:
+            self._report_trace_failure()
+            return
         try:
-            req = ReportEndedTraceRequest(
-                trace_id=self._trace_id,
-                success=True,
-                trace_content=data,
-            )
+            if chunk_count > 0:
+                req = ReportEndedTraceRequest(
+                    trace_id=self._trace_id,
+                    success=True,
+                    chunk_count=chunk_count,
+                )
+            else:
+                req = ReportEndedTraceRequest(
+                    trace_id=self._trace_id,
+                    success=True,
+                    trace_content=data,
+                )
             self._ctraced_stub.ReportEndedCallTrace(req)
         except grpc.RpcError:
             logging.error(
@@ -248,6 +294,36 @@ class TraceManager:
                 self._trace_id,
             )
 
+    def _upload_trace_chunks(self, data: bytes) -> Optional[int]:
+        """Uploads a call trace too large for a single message in chunks.
+
+        Returns:
+            Number of chunks uploaded, 0 if the call trace is small enough to
+            be sent as is, or None if the upload failed
+        """
+        if data is None or len(data) <= _CHUNK_SIZE:
+            return 0
+        chunk_count = 0
+        for offset in range(0, len(data), _CHUNK_SIZE):
+            try:
+                req = ReportCallTraceChunkRequest(
+                    trace_id=self._trace_id,
+                    index=chunk_count,
+                    content=data[offset:offset + _CHUNK_SIZE],
+                )
+                self._ctraced_stub.ReportCallTraceChunk(req)
+            except grpc.RpcError:
+                logging.error(
+                    'Unable to upload chunk %d of call trace for %s. ',
+                    chunk_count, self._trace_id,
+                )
+                return None
+            chunk_count += 1
+        logging.info(
+            "TraceManager: Uploaded call trace in %d chunks", chunk_count,
+        )
+        return chunk_count
+
     def _report_trace_failure(self):
         try:
             req = ReportEndedTraceRequest(
@@ -106,15 +106,19 @@ class BootstrapManager(SDWatchdogTask):
         elif self._state == BootstrapState.IDLE:
             pass
 
-    async def schedule_bootstrap_now(self):
+    async def schedule_bootstrap_now(self, force=False):
         """Public Interface to start a bootstrap
 
         1. If the device is already bootstrapping, do nothing
         2. If it is waiting for a next bootstrap check or bootstrap, wake up
            and do it now.
+        3. If force is set, bootstrap even if the current cert is still
+           valid, e.g. when instructed to rotate it by the cloud.
         """
         if self._state is BootstrapState.BOOTSTRAPPING:
             return
+        if force:
+            self._state = BootstrapState.SCHEDULED_BOOTSTRAP
         await self.wake_up()
 
     def _maybe_create_challenge_key(self):
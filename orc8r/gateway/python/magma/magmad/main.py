@@ -243,6 +243,7 @@ def main():
         services, service_manager, get_mconfig_manager(), command_executor,
         service.loop,
         service.config.get('print_grpc_payload', False),
+        bootstrap_manager,
     )
     magmad_servicer.add_to_server(service.rpc_server)
 
@@ -15,7 +15,7 @@ import logging
 import os
 import queue
 import signal
-from typing import List
+from typing import List, Optional
 
 import grpc
 import snowflake
@@ -31,6 +31,7 @@ from magma.common.stateless_agw import (
     enable_stateless_agw,
 )
 from magma.configuration.mconfig_managers import MconfigManager
+from magma.magmad.bootstrap_manager import BootstrapManager
 from magma.magmad.check.network_check import ping, traceroute
 from magma.magmad.generic_command.command_executor import CommandExecutor
 from magma.magmad.service_manager import ServiceManager
@@ -51,6 +52,7 @@ class MagmadRpcServicer(magmad_pb2_grpc.MagmadServicer):
         command_executor: CommandExecutor,
         loop: asyncio.AbstractEventLoop,
         print_grpc_payload: bool = False,
+        bootstrap_manager: Optional[BootstrapManager] = None,
     ):
         """
         Constructor for the magmad RPC servicer
@@ -65,6 +67,7 @@ class MagmadRpcServicer(magmad_pb2_grpc.MagmadServicer):
             service_manager: ServiceManager instance
             mconfig_manager: MconfigManager instance
             loop: event loop
+            bootstrap_manager: BootstrapManager instance, used to rebootstrap
         """
         self._print_grpc_payload = print_grpc_payload
         self._service_manager = service_manager
@@ -73,6 +76,7 @@ class MagmadRpcServicer(magmad_pb2_grpc.MagmadServicer):
         self._magma_service = magma_service
         self._command_executor = command_executor
         self._loop = loop
+        self._bootstrap_manager = bootstrap_manager
 
     def add_to_server(self, server):
         """
@@ -106,6 +110,25 @@ class MagmadRpcServicer(magmad_pb2_grpc.MagmadServicer):
         logging.info("Remote reboot triggered! Rebooting gateway...")
         self._loop.create_task(run_reboot())
 
+    @return_void
+    def Rebootstrap(self, _, context):
+        """
+        Obtain a new gateway certificate, regardless of the current
+        certificate's expiry
+        """
+        if self._bootstrap_manager is None:
+            set_grpc_err(
+                context,
+                grpc.StatusCode.FAILED_PRECONDITION,
+                'Bootstrap manager is not configured',
+            )
+            return
+
+        logging.info("Remote rebootstrap triggered! Bootstrapping gateway...")
+        self._loop.create_task(
+            self._bootstrap_manager.schedule_bootstrap_now(force=True),
+        )
+
     @return_void
     def RestartServices(self, request, context):
         """
@@ -30,18 +30,23 @@ const (
 	ChallengeKey_ECHO                  ChallengeKey_KeyType = 0
 	ChallengeKey_SOFTWARE_RSA_SHA256   ChallengeKey_KeyType = 1
 	ChallengeKey_SOFTWARE_ECDSA_SHA256 ChallengeKey_KeyType = 2
+	// TPM 2.0 attestation key, whose endorsement key certificate chain is
+	// registered alongside it
+	ChallengeKey_TPM2_ATTESTATION ChallengeKey_KeyType = 3
 )
 
 var ChallengeKey_KeyType_name = map[int32]string{
 	0: "ECHO",
 	1: "SOFTWARE_RSA_SHA256",
 	2: "SOFTWARE_ECDSA_SHA256",
+	3: "TPM2_ATTESTATION",
 }
 
 var ChallengeKey_KeyType_value = map[string]int32{
 	"ECHO":                  0,
 	"SOFTWARE_RSA_SHA256":   1,
 	"SOFTWARE_ECDSA_SHA256": 2,
+	"TPM2_ATTESTATION":      3,
 }
 
 func (x ChallengeKey_KeyType) String() string {
@@ -49,15 +54,18 @@ func (x ChallengeKey_KeyType) String() string {
 }
 
 func (ChallengeKey_KeyType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_b592b3c4e9ae6813, []int{1, 0}
+	return fileDescriptor_b592b3c4e9ae6813, []int{2, 0}
 }
 
 type Challenge struct {
-	KeyType              ChallengeKey_KeyType `protobuf:"varint,1,opt,name=key_type,json=keyType,proto3,enum=magma.orc8r.ChallengeKey_KeyType" json:"key_type,omitempty"`
-	Challenge            []byte               `protobuf:"bytes,2,opt,name=challenge,proto3" json:"challenge,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	KeyType   ChallengeKey_KeyType `protobuf:"varint,1,opt,name=key_type,json=keyType,proto3,enum=magma.orc8r.ChallengeKey_KeyType" json:"key_type,omitempty"`
+	Challenge []byte               `protobuf:"bytes,2,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	// Credential protecting a secret to be recovered by the gateway's TPM for
+	// TPM2_ATTESTATION challenges
+	TpmCredential        *TPMCredential `protobuf:"bytes,3,opt,name=tpm_credential,json=tpmCredential,proto3" json:"tpm_credential,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *Challenge) Reset()         { *m = Challenge{} }
@@ -99,12 +107,74 @@ func (m *Challenge) GetChallenge() []byte {
 	return nil
 }
 
+func (m *Challenge) GetTpmCredential() *TPMCredential {
+	if m != nil {
+		return m.TpmCredential
+	}
+	return nil
+}
+
+// --------------------------------------------------------------------------
+// TPM credential is the output of TPM2_MakeCredential over the gateway's
+// endorsement key and attestation key name. Only the TPM holding both keys
+// can recover its secret, via TPM2_ActivateCredential.
+// --------------------------------------------------------------------------
+type TPMCredential struct {
+	// TPM2B_ID_OBJECT contents
+	CredentialBlob []byte `protobuf:"bytes,1,opt,name=credential_blob,json=credentialBlob,proto3" json:"credential_blob,omitempty"`
+	// TPM2B_ENCRYPTED_SECRET contents
+	EncryptedSecret      []byte   `protobuf:"bytes,2,opt,name=encrypted_secret,json=encryptedSecret,proto3" json:"encrypted_secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TPMCredential) Reset()         { *m = TPMCredential{} }
+func (m *TPMCredential) String() string { return proto.CompactTextString(m) }
+func (*TPMCredential) ProtoMessage()    {}
+func (*TPMCredential) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b592b3c4e9ae6813, []int{1}
+}
+
+func (m *TPMCredential) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TPMCredential.Unmarshal(m, b)
+}
+func (m *TPMCredential) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TPMCredential.Marshal(b, m, deterministic)
+}
+func (m *TPMCredential) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TPMCredential.Merge(m, src)
+}
+func (m *TPMCredential) XXX_Size() int {
+	return xxx_messageInfo_TPMCredential.Size(m)
+}
+func (m *TPMCredential) XXX_DiscardUnknown() {
+	xxx_messageInfo_TPMCredential.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TPMCredential proto.InternalMessageInfo
+
+func (m *TPMCredential) GetCredentialBlob() []byte {
+	if m != nil {
+		return m.CredentialBlob
+	}
+	return nil
+}
+
+func (m *TPMCredential) GetEncryptedSecret() []byte {
+	if m != nil {
+		return m.EncryptedSecret
+	}
+	return nil
+}
+
 // --------------------------------------------------------------------------
 // Challenge key stores the key used for challenge-response during bootstrap.
 // --------------------------------------------------------------------------
 type ChallengeKey struct {
 	KeyType ChallengeKey_KeyType `protobuf:"varint,1,opt,name=key_type,json=keyType,proto3,enum=magma.orc8r.ChallengeKey_KeyType" json:"key_type,omitempty"`
-	// Public key encoded in DER format
+	// Public key encoded in DER format, or TPMT_PUBLIC format for
+	// TPM2_ATTESTATION keys
 	Key                  []byte   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -115,7 +185,7 @@ func (m *ChallengeKey) Reset()         { *m = ChallengeKey{} }
 func (m *ChallengeKey) String() string { return proto.CompactTextString(m) }
 func (*ChallengeKey) ProtoMessage()    {}
 func (*ChallengeKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b592b3c4e9ae6813, []int{1}
+	return fileDescriptor_b592b3c4e9ae6813, []int{2}
 }
 
 func (m *ChallengeKey) XXX_Unmarshal(b []byte) error {
@@ -157,6 +227,7 @@ type Response struct {
 	//	*Response_EchoResponse
 	//	*Response_RsaResponse
 	//	*Response_EcdsaResponse
+	//	*Response_TpmResponse
 	Response             isResponse_Response `protobuf_oneof:"response"`
 	Csr                  *CSR                `protobuf:"bytes,6,opt,name=csr,proto3" json:"csr,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
@@ -168,7 +239,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b592b3c4e9ae6813, []int{2}
+	return fileDescriptor_b592b3c4e9ae6813, []int{3}
 }
 
 func (m *Response) XXX_Unmarshal(b []byte) error {
@@ -219,12 +290,18 @@ type Response_EcdsaResponse struct {
 	EcdsaResponse *Response_ECDSA `protobuf:"bytes,5,opt,name=ecdsa_response,json=ecdsaResponse,proto3,oneof"`
 }
 
+type Response_TpmResponse struct {
+	TpmResponse *Response_TPMQuote `protobuf:"bytes,7,opt,name=tpm_response,json=tpmResponse,proto3,oneof"`
+}
+
 func (*Response_EchoResponse) isResponse_Response() {}
 
 func (*Response_RsaResponse) isResponse_Response() {}
 
 func (*Response_EcdsaResponse) isResponse_Response() {}
 
+func (*Response_TpmResponse) isResponse_Response() {}
+
 func (m *Response) GetResponse() isResponse_Response {
 	if m != nil {
 		return m.Response
@@ -253,6 +330,13 @@ func (m *Response) GetEcdsaResponse() *Response_ECDSA {
 	return nil
 }
 
+func (m *Response) GetTpmResponse() *Response_TPMQuote {
+	if x, ok := m.GetResponse().(*Response_TpmResponse); ok {
+		return x.TpmResponse
+	}
+	return nil
+}
+
 func (m *Response) GetCsr() *CSR {
 	if m != nil {
 		return m.Csr
@@ -266,6 +350,7 @@ func (*Response) XXX_OneofWrappers() []interface{} {
 		(*Response_EchoResponse)(nil),
 		(*Response_RsaResponse)(nil),
 		(*Response_EcdsaResponse)(nil),
+		(*Response_TpmResponse)(nil),
 	}
 }
 
@@ -280,7 +365,7 @@ func (m *Response_Echo) Reset()         { *m = Response_Echo{} }
 func (m *Response_Echo) String() string { return proto.CompactTextString(m) }
 func (*Response_Echo) ProtoMessage()    {}
 func (*Response_Echo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b592b3c4e9ae6813, []int{2, 0}
+	return fileDescriptor_b592b3c4e9ae6813, []int{3, 0}
 }
 
 func (m *Response_Echo) XXX_Unmarshal(b []byte) error {
@@ -319,7 +404,7 @@ func (m *Response_RSA) Reset()         { *m = Response_RSA{} }
 func (m *Response_RSA) String() string { return proto.CompactTextString(m) }
 func (*Response_RSA) ProtoMessage()    {}
 func (*Response_RSA) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b592b3c4e9ae6813, []int{2, 1}
+	return fileDescriptor_b592b3c4e9ae6813, []int{3, 1}
 }
 
 func (m *Response_RSA) XXX_Unmarshal(b []byte) error {
@@ -359,7 +444,7 @@ func (m *Response_ECDSA) Reset()         { *m = Response_ECDSA{} }
 func (m *Response_ECDSA) String() string { return proto.CompactTextString(m) }
 func (*Response_ECDSA) ProtoMessage()    {}
 func (*Response_ECDSA) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b592b3c4e9ae6813, []int{2, 2}
+	return fileDescriptor_b592b3c4e9ae6813, []int{3, 2}
 }
 
 func (m *Response_ECDSA) XXX_Unmarshal(b []byte) error {
@@ -394,52 +479,114 @@ func (m *Response_ECDSA) GetS() []byte {
 	return nil
 }
 
+// TPM quote whose qualifying data is
+// SHA256(challenge || activated credential secret)
+type Response_TPMQuote struct {
+	// TPMS_ATTEST
+	Quote []byte `protobuf:"bytes,1,opt,name=quote,proto3" json:"quote,omitempty"`
+	// TPMT_SIGNATURE by the attestation key
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Response_TPMQuote) Reset()         { *m = Response_TPMQuote{} }
+func (m *Response_TPMQuote) String() string { return proto.CompactTextString(m) }
+func (*Response_TPMQuote) ProtoMessage()    {}
+func (*Response_TPMQuote) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b592b3c4e9ae6813, []int{3, 3}
+}
+
+func (m *Response_TPMQuote) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Response_TPMQuote.Unmarshal(m, b)
+}
+func (m *Response_TPMQuote) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Response_TPMQuote.Marshal(b, m, deterministic)
+}
+func (m *Response_TPMQuote) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Response_TPMQuote.Merge(m, src)
+}
+func (m *Response_TPMQuote) XXX_Size() int {
+	return xxx_messageInfo_Response_TPMQuote.Size(m)
+}
+func (m *Response_TPMQuote) XXX_DiscardUnknown() {
+	xxx_messageInfo_Response_TPMQuote.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Response_TPMQuote proto.InternalMessageInfo
+
+func (m *Response_TPMQuote) GetQuote() []byte {
+	if m != nil {
+		return m.Quote
+	}
+	return nil
+}
+
+func (m *Response_TPMQuote) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("magma.orc8r.ChallengeKey_KeyType", ChallengeKey_KeyType_name, ChallengeKey_KeyType_value)
 	proto.RegisterType((*Challenge)(nil), "magma.orc8r.Challenge")
+	proto.RegisterType((*TPMCredential)(nil), "magma.orc8r.TPMCredential")
 	proto.RegisterType((*ChallengeKey)(nil), "magma.orc8r.ChallengeKey")
 	proto.RegisterType((*Response)(nil), "magma.orc8r.Response")
 	proto.RegisterType((*Response_Echo)(nil), "magma.orc8r.Response.Echo")
 	proto.RegisterType((*Response_RSA)(nil), "magma.orc8r.Response.RSA")
 	proto.RegisterType((*Response_ECDSA)(nil), "magma.orc8r.Response.ECDSA")
+	proto.RegisterType((*Response_TPMQuote)(nil), "magma.orc8r.Response.TPMQuote")
 }
 
 func init() { proto.RegisterFile("orc8r/protos/bootstrapper.proto", fileDescriptor_b592b3c4e9ae6813) }
 
 var fileDescriptor_b592b3c4e9ae6813 = []byte{
-	// 507 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x53, 0x5d, 0x6f, 0xda, 0x30,
-	0x14, 0x4d, 0x0a, 0xb4, 0xf4, 0x92, 0x56, 0xc8, 0x53, 0x37, 0x08, 0x48, 0xeb, 0xd2, 0x97, 0x3e,
-	0x05, 0x8d, 0x69, 0xd3, 0x1e, 0xa6, 0x69, 0xe1, 0xa3, 0x50, 0xf5, 0xa1, 0x92, 0x53, 0x69, 0xd2,
-	0x5e, 0x50, 0x30, 0x77, 0x21, 0x82, 0x26, 0x99, 0xed, 0x0a, 0xe5, 0x9f, 0xec, 0x1f, 0xec, 0x7f,
-	0xec, 0x97, 0x4d, 0x31, 0x21, 0x21, 0x12, 0xeb, 0x4b, 0x9f, 0x62, 0xfb, 0x9c, 0x7b, 0xce, 0xcd,
-	0xb1, 0x2f, 0xbc, 0x8d, 0x38, 0xfb, 0xcc, 0x7b, 0x31, 0x8f, 0x64, 0x24, 0x7a, 0xf3, 0x28, 0x92,
-	0x42, 0x72, 0x2f, 0x8e, 0x91, 0xdb, 0xea, 0x8c, 0x34, 0x1e, 0x3d, 0xff, 0xd1, 0xb3, 0x15, 0xcd,
-	0xec, 0x96, 0xd8, 0x0c, 0xb9, 0x0c, 0x7e, 0x06, 0x3b, 0xaa, 0xd9, 0x29, 0xa1, 0xc1, 0x02, 0x43,
-	0x19, 0xc8, 0x64, 0x0b, 0x5a, 0x3e, 0x9c, 0x0e, 0x97, 0xde, 0x7a, 0x8d, 0xa1, 0x8f, 0xe4, 0x0b,
-	0xd4, 0x57, 0x98, 0xcc, 0x64, 0x12, 0x63, 0x4b, 0xbf, 0xd4, 0xaf, 0xcf, 0xfb, 0xef, 0xec, 0x3d,
-	0x1f, 0x3b, 0x67, 0xde, 0x61, 0x62, 0xdf, 0x61, 0xf2, 0x90, 0xc4, 0x48, 0x4f, 0x56, 0xdb, 0x05,
-	0xe9, 0xc2, 0x29, 0xdb, 0x11, 0x5a, 0x47, 0x97, 0xfa, 0xb5, 0x41, 0x8b, 0x03, 0xeb, 0x8f, 0x0e,
-	0xc6, 0x7e, 0xfd, 0x0b, 0xcd, 0x9a, 0x50, 0x59, 0x61, 0x92, 0xd9, 0xa4, 0x4b, 0x6b, 0x02, 0x27,
-	0x19, 0x8b, 0xd4, 0xa1, 0x3a, 0x1e, 0x4e, 0xef, 0x9b, 0x1a, 0x79, 0x03, 0xaf, 0xdc, 0xfb, 0x9b,
-	0x87, 0xef, 0x0e, 0x1d, 0xcf, 0xa8, 0xeb, 0xcc, 0xdc, 0xa9, 0xd3, 0xff, 0xf8, 0xa9, 0xa9, 0x93,
-	0x36, 0x5c, 0xe4, 0xc0, 0x78, 0x38, 0x2a, 0xa0, 0x23, 0xeb, 0x6f, 0x05, 0xea, 0x14, 0x45, 0x1c,
-	0x85, 0x02, 0xc9, 0x7b, 0xa8, 0x2d, 0x37, 0xb3, 0x60, 0xa1, 0x5a, 0x6c, 0xf4, 0xbb, 0xa5, 0x16,
-	0x1d, 0xc6, 0x50, 0x88, 0x89, 0x27, 0x71, 0xe3, 0x25, 0xb7, 0x23, 0x5a, 0x5d, 0x6e, 0x6e, 0x17,
-	0xcf, 0xe7, 0x40, 0x1c, 0x38, 0x43, 0xb6, 0x8c, 0x66, 0x3c, 0x73, 0x68, 0x55, 0x94, 0xb0, 0x59,
-	0x12, 0xde, 0xd9, 0xdb, 0x63, 0xb6, 0x8c, 0xa6, 0x1a, 0x35, 0xd2, 0x92, 0xbc, 0xa7, 0xaf, 0x60,
-	0x70, 0xe1, 0x15, 0x0a, 0x55, 0xa5, 0xd0, 0x3e, 0xac, 0x40, 0x5d, 0x67, 0xaa, 0xd1, 0x06, 0x17,
-	0x5e, 0x5e, 0x3f, 0x82, 0x73, 0x64, 0x8b, 0x7d, 0x85, 0x9a, 0x52, 0xe8, 0xfc, 0xa7, 0x87, 0x34,
-	0x9e, 0xa9, 0x46, 0xcf, 0x54, 0x51, 0xae, 0x62, 0x41, 0x85, 0x09, 0xde, 0x3a, 0x56, 0xa5, 0xcd,
-	0xf2, 0xd5, 0xb9, 0x94, 0xa6, 0xa0, 0x69, 0x41, 0x35, 0xfd, 0x03, 0x62, 0x42, 0x3d, 0xf7, 0xd2,
-	0x55, 0x22, 0xf9, 0xde, 0xbc, 0x82, 0x0a, 0x75, 0x9d, 0x34, 0x35, 0x11, 0xf8, 0xa1, 0x27, 0x9f,
-	0xf8, 0x8e, 0x53, 0x1c, 0x98, 0x57, 0x50, 0x53, 0x6d, 0x10, 0x03, 0x74, 0x9e, 0xc1, 0x3a, 0x4f,
-	0x77, 0x22, 0x8b, 0x58, 0x17, 0x03, 0x28, 0x5c, 0xfa, 0xbf, 0x75, 0x30, 0x06, 0x7b, 0x63, 0x43,
-	0x6e, 0xc0, 0x98, 0xa0, 0x2c, 0xde, 0xfa, 0xb3, 0x37, 0x69, 0xbe, 0x3e, 0xfc, 0x14, 0x2d, 0x8d,
-	0x7c, 0x83, 0x06, 0xc5, 0x5f, 0x4f, 0x28, 0xa4, 0x1b, 0xf8, 0x21, 0xb9, 0x38, 0x98, 0x99, 0xd9,
-	0x2a, 0xd7, 0x6f, 0x27, 0x92, 0x79, 0x12, 0x2d, 0x6d, 0xd0, 0xf9, 0xd1, 0x56, 0x60, 0x6f, 0x3b,
-	0x97, 0xeb, 0x60, 0xde, 0xf3, 0xa3, 0x6c, 0x3c, 0xe7, 0xc7, 0xea, 0xfb, 0xe1, 0x5f, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0x66, 0x62, 0xe2, 0x84, 0x01, 0x04, 0x00, 0x00,
+	// 647 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xd1, 0x6e, 0xda, 0x4a,
+	0x10, 0xc5, 0x01, 0x12, 0x32, 0x18, 0x82, 0xf6, 0x26, 0xf7, 0x12, 0x27, 0xba, 0x37, 0xd7, 0x79,
+	0x68, 0xfa, 0x02, 0x2a, 0x55, 0xab, 0x3e, 0x54, 0x51, 0x0d, 0x21, 0x21, 0x8a, 0xd2, 0xa4, 0x6b,
+	0x4b, 0x95, 0x2a, 0x55, 0x96, 0x59, 0xa6, 0x60, 0x05, 0xb0, 0xb3, 0xbb, 0x51, 0xe4, 0x3f, 0xe9,
+	0x37, 0xf4, 0x1f, 0xfa, 0xdc, 0xdf, 0xaa, 0xbc, 0x18, 0x1b, 0x57, 0xb4, 0x7d, 0xe8, 0x13, 0xbb,
+	0x33, 0x67, 0xce, 0x39, 0x9e, 0x1d, 0x06, 0xfe, 0x0b, 0x38, 0x7b, 0xc5, 0xdb, 0x21, 0x0f, 0x64,
+	0x20, 0xda, 0xc3, 0x20, 0x90, 0x42, 0x72, 0x2f, 0x0c, 0x91, 0xb7, 0x54, 0x8c, 0x54, 0x67, 0xde,
+	0x78, 0xe6, 0xb5, 0x14, 0xcc, 0x38, 0xcc, 0xa1, 0x19, 0x72, 0xe9, 0x7f, 0xf2, 0x97, 0x50, 0xe3,
+	0x20, 0x97, 0xf5, 0x47, 0x38, 0x97, 0xbe, 0x8c, 0x16, 0x49, 0xf3, 0x8b, 0x06, 0xdb, 0xbd, 0x89,
+	0x37, 0x9d, 0xe2, 0x7c, 0x8c, 0xe4, 0x35, 0x54, 0xee, 0x30, 0x72, 0x65, 0x14, 0x62, 0x53, 0x3b,
+	0xd2, 0x4e, 0xea, 0x9d, 0xff, 0x5b, 0x2b, 0x42, 0xad, 0x14, 0x79, 0x85, 0x51, 0xeb, 0x0a, 0x23,
+	0x27, 0x0a, 0x91, 0x6e, 0xdd, 0x2d, 0x0e, 0xe4, 0x10, 0xb6, 0xd9, 0x12, 0xd0, 0xdc, 0x38, 0xd2,
+	0x4e, 0x74, 0x9a, 0x05, 0x88, 0x05, 0x75, 0x19, 0xce, 0x5c, 0xc6, 0x51, 0x39, 0xf0, 0xa6, 0xcd,
+	0xe2, 0x91, 0x76, 0x52, 0xed, 0x18, 0x39, 0x05, 0xe7, 0xf6, 0xba, 0x97, 0x22, 0x68, 0x4d, 0x86,
+	0xb3, 0xec, 0x6a, 0x32, 0xa8, 0xe5, 0xf2, 0xe4, 0x09, 0xec, 0x64, 0x7c, 0xee, 0x70, 0x1a, 0x0c,
+	0x95, 0x6d, 0x9d, 0xd6, 0xb3, 0x70, 0x77, 0x1a, 0x0c, 0xc9, 0x53, 0x68, 0xe0, 0x9c, 0xf1, 0x28,
+	0x94, 0x38, 0x72, 0x05, 0x32, 0x8e, 0x32, 0x71, 0xb8, 0x93, 0xc6, 0x6d, 0x15, 0x36, 0xbf, 0x6a,
+	0xa0, 0xaf, 0x7e, 0xe7, 0x1f, 0x36, 0xa5, 0x01, 0xc5, 0x3b, 0x8c, 0x12, 0xb1, 0xf8, 0x68, 0x7e,
+	0x84, 0xad, 0x04, 0x45, 0x2a, 0x50, 0xea, 0xf7, 0x06, 0x37, 0x8d, 0x02, 0xf9, 0x07, 0xfe, 0xb2,
+	0x6f, 0xce, 0x9d, 0xf7, 0x16, 0xed, 0xbb, 0xd4, 0xb6, 0x5c, 0x7b, 0x60, 0x75, 0x5e, 0xbc, 0x6c,
+	0x68, 0x64, 0x1f, 0xf6, 0xd2, 0x44, 0xbf, 0x77, 0x96, 0xa5, 0x36, 0xc8, 0x2e, 0x34, 0x9c, 0xdb,
+	0xeb, 0x8e, 0x6b, 0x39, 0x4e, 0xdf, 0x76, 0x2c, 0xe7, 0xf2, 0xe6, 0x6d, 0xa3, 0x68, 0x7e, 0x2b,
+	0x41, 0x85, 0xa2, 0x08, 0x83, 0xb9, 0x40, 0xf2, 0x0c, 0xca, 0x93, 0x47, 0xd7, 0x1f, 0x29, 0xe3,
+	0xd5, 0xce, 0x61, 0xce, 0xb8, 0xc5, 0x18, 0x0a, 0x71, 0xe1, 0x49, 0x7c, 0xf4, 0xa2, 0xcb, 0x33,
+	0x5a, 0x9a, 0x3c, 0x5e, 0x8e, 0x7e, 0xfb, 0x8a, 0x35, 0x64, 0x93, 0xc0, 0xe5, 0x89, 0xc2, 0xda,
+	0x47, 0x5c, 0xca, 0xb7, 0xfa, 0x6c, 0x12, 0x0c, 0x0a, 0x54, 0x8f, 0x4b, 0x52, 0x4f, 0xa7, 0xa0,
+	0x73, 0xe1, 0x65, 0x0c, 0x25, 0xc5, 0xb0, 0xbf, 0x9e, 0x81, 0xda, 0xd6, 0xa0, 0x40, 0xab, 0x5c,
+	0x78, 0x69, 0xfd, 0x19, 0xd4, 0x91, 0x8d, 0x56, 0x19, 0xca, 0x8a, 0xe1, 0xe0, 0x27, 0x1e, 0xe2,
+	0xa6, 0x0d, 0x0a, 0xb4, 0xa6, 0x8a, 0x52, 0x96, 0x1e, 0xe8, 0xf1, 0x38, 0xa6, 0x1c, 0x5b, 0x8a,
+	0xe3, 0xdf, 0xf5, 0x1c, 0xce, 0xed, 0xf5, 0xbb, 0x87, 0x40, 0x62, 0x6c, 0x45, 0x86, 0xb3, 0x94,
+	0xc4, 0x84, 0x22, 0x13, 0xbc, 0xb9, 0xa9, 0x6a, 0x1b, 0xf9, 0xa9, 0xb0, 0x29, 0x8d, 0x93, 0x86,
+	0x09, 0xa5, 0xb8, 0x0d, 0xc4, 0x80, 0x4a, 0x2a, 0xb6, 0x18, 0xd2, 0xf4, 0x6e, 0x1c, 0x43, 0x91,
+	0xda, 0x56, 0xdc, 0x7a, 0xe1, 0x8f, 0xe7, 0x9e, 0x7c, 0xe0, 0x4b, 0x4c, 0x16, 0x30, 0x8e, 0xa1,
+	0xac, 0xbe, 0x85, 0xe8, 0xa0, 0xf1, 0x24, 0xad, 0xf1, 0xf8, 0x26, 0x92, 0x77, 0xd2, 0x84, 0x71,
+	0x0a, 0x95, 0xa5, 0x59, 0xb2, 0x0b, 0xe5, 0xfb, 0xf8, 0x90, 0x60, 0x17, 0x97, 0xbc, 0xc8, 0xc6,
+	0x0f, 0x22, 0x5d, 0xc8, 0x5c, 0x76, 0x3e, 0x6b, 0xa0, 0x77, 0x57, 0x56, 0x0f, 0x39, 0x07, 0xfd,
+	0x02, 0x65, 0xb6, 0x2e, 0x7e, 0x39, 0x4e, 0xc6, 0xdf, 0xeb, 0xff, 0x25, 0x66, 0x81, 0xbc, 0x81,
+	0x2a, 0xc5, 0xfb, 0x07, 0x14, 0xd2, 0xf6, 0xc7, 0x73, 0xb2, 0xb7, 0xb6, 0xe9, 0x46, 0x33, 0x5f,
+	0xbf, 0xd8, 0x6a, 0xcc, 0x93, 0x68, 0x16, 0xba, 0x07, 0x1f, 0xf6, 0x55, 0xb2, 0xbd, 0xd8, 0x6d,
+	0x53, 0x7f, 0xd8, 0x1e, 0x07, 0xc9, 0x8a, 0x1b, 0x6e, 0xaa, 0xdf, 0xe7, 0xdf, 0x07, 0x00, 0x08,
+	0x84, 0xda, 0xcb, 0x45, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -311,8 +311,12 @@ func (m *EndTraceRequest) GetTraceId() string {
 }
 
 type EndTraceResponse struct {
-	Success              bool     `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	TraceContent         []byte   `protobuf:"bytes,2,opt,name=trace_content,json=traceContent,proto3" json:"trace_content,omitempty"`
+	Success      bool   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	TraceContent []byte `protobuf:"bytes,2,opt,name=trace_content,json=traceContent,proto3" json:"trace_content,omitempty"`
+	// Set instead of trace_content if the trace was too large for a single
+	// message. The trace was uploaded in chunk_count chunks through
+	// ReportCallTraceChunk before responding.
+	ChunkCount           uint32   `protobuf:"varint,3,opt,name=chunk_count,json=chunkCount,proto3" json:"chunk_count,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -357,10 +361,19 @@ func (m *EndTraceResponse) GetTraceContent() []byte {
 	return nil
 }
 
+func (m *EndTraceResponse) GetChunkCount() uint32 {
+	if m != nil {
+		return m.ChunkCount
+	}
+	return 0
+}
+
 type ReportEndedTraceRequest struct {
-	TraceId              string   `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
-	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
-	TraceContent         []byte   `protobuf:"bytes,3,opt,name=trace_content,json=traceContent,proto3" json:"trace_content,omitempty"`
+	TraceId      string `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	Success      bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	TraceContent []byte `protobuf:"bytes,3,opt,name=trace_content,json=traceContent,proto3" json:"trace_content,omitempty"`
+	// Set instead of trace_content if the trace was uploaded in chunks
+	ChunkCount           uint32   `protobuf:"varint,4,opt,name=chunk_count,json=chunkCount,proto3" json:"chunk_count,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -412,6 +425,13 @@ func (m *ReportEndedTraceRequest) GetTraceContent() []byte {
 	return nil
 }
 
+func (m *ReportEndedTraceRequest) GetChunkCount() uint32 {
+	if m != nil {
+		return m.ChunkCount
+	}
+	return 0
+}
+
 type ReportEndedTraceResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -443,6 +463,92 @@ func (m *ReportEndedTraceResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ReportEndedTraceResponse proto.InternalMessageInfo
 
+type ReportCallTraceChunkRequest struct {
+	TraceId              string   `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	Index                uint32   `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Content              []byte   `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReportCallTraceChunkRequest) Reset()         { *m = ReportCallTraceChunkRequest{} }
+func (m *ReportCallTraceChunkRequest) String() string { return proto.CompactTextString(m) }
+func (*ReportCallTraceChunkRequest) ProtoMessage()    {}
+func (*ReportCallTraceChunkRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_74b70534723ed60d, []int{6}
+}
+
+func (m *ReportCallTraceChunkRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReportCallTraceChunkRequest.Unmarshal(m, b)
+}
+func (m *ReportCallTraceChunkRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReportCallTraceChunkRequest.Marshal(b, m, deterministic)
+}
+func (m *ReportCallTraceChunkRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReportCallTraceChunkRequest.Merge(m, src)
+}
+func (m *ReportCallTraceChunkRequest) XXX_Size() int {
+	return xxx_messageInfo_ReportCallTraceChunkRequest.Size(m)
+}
+func (m *ReportCallTraceChunkRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReportCallTraceChunkRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReportCallTraceChunkRequest proto.InternalMessageInfo
+
+func (m *ReportCallTraceChunkRequest) GetTraceId() string {
+	if m != nil {
+		return m.TraceId
+	}
+	return ""
+}
+
+func (m *ReportCallTraceChunkRequest) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ReportCallTraceChunkRequest) GetContent() []byte {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+type ReportCallTraceChunkResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReportCallTraceChunkResponse) Reset()         { *m = ReportCallTraceChunkResponse{} }
+func (m *ReportCallTraceChunkResponse) String() string { return proto.CompactTextString(m) }
+func (*ReportCallTraceChunkResponse) ProtoMessage()    {}
+func (*ReportCallTraceChunkResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_74b70534723ed60d, []int{7}
+}
+
+func (m *ReportCallTraceChunkResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReportCallTraceChunkResponse.Unmarshal(m, b)
+}
+func (m *ReportCallTraceChunkResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReportCallTraceChunkResponse.Marshal(b, m, deterministic)
+}
+func (m *ReportCallTraceChunkResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReportCallTraceChunkResponse.Merge(m, src)
+}
+func (m *ReportCallTraceChunkResponse) XXX_Size() int {
+	return xxx_messageInfo_ReportCallTraceChunkResponse.Size(m)
+}
+func (m *ReportCallTraceChunkResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReportCallTraceChunkResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReportCallTraceChunkResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterEnum("magma.orc8r.StartTraceRequest_TraceType", StartTraceRequest_TraceType_name, StartTraceRequest_TraceType_value)
 	proto.RegisterEnum("magma.orc8r.StartTraceRequest_ProtocolName", StartTraceRequest_ProtocolName_name, StartTraceRequest_ProtocolName_value)
@@ -453,48 +559,55 @@ func init() {
 	proto.RegisterType((*EndTraceResponse)(nil), "magma.orc8r.EndTraceResponse")
 	proto.RegisterType((*ReportEndedTraceRequest)(nil), "magma.orc8r.ReportEndedTraceRequest")
 	proto.RegisterType((*ReportEndedTraceResponse)(nil), "magma.orc8r.ReportEndedTraceResponse")
+	proto.RegisterType((*ReportCallTraceChunkRequest)(nil), "magma.orc8r.ReportCallTraceChunkRequest")
+	proto.RegisterType((*ReportCallTraceChunkResponse)(nil), "magma.orc8r.ReportCallTraceChunkResponse")
 }
 
 func init() { proto.RegisterFile("orc8r/protos/ctraced.proto", fileDescriptor_74b70534723ed60d) }
 
 var fileDescriptor_74b70534723ed60d = []byte{
-	// 568 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xdf, 0x8f, 0xd2, 0x40,
-	0x10, 0xc7, 0xaf, 0x80, 0x50, 0x46, 0xe0, 0xea, 0x6a, 0x62, 0x0f, 0x7f, 0x5d, 0xea, 0x2f, 0x8c,
-	0x97, 0x12, 0xcf, 0x17, 0x5f, 0xa1, 0xf6, 0x08, 0x06, 0x0e, 0xdc, 0xf6, 0x12, 0xe3, 0xcb, 0xa5,
-	0xd7, 0xee, 0x5d, 0x9a, 0x94, 0xb6, 0xee, 0x2e, 0x26, 0x24, 0xfe, 0x4d, 0x3e, 0xfa, 0xf7, 0x99,
-	0x4e, 0x29, 0x3f, 0x3c, 0x11, 0x9f, 0xda, 0x99, 0xf9, 0xce, 0x67, 0x76, 0x66, 0x27, 0x0b, 0xed,
-	0x84, 0xfb, 0x1f, 0x78, 0x37, 0xe5, 0x89, 0x4c, 0x44, 0xd7, 0x97, 0xdc, 0xf3, 0x59, 0x60, 0xa2,
-	0x49, 0xee, 0xce, 0xbc, 0x9b, 0x99, 0x67, 0xa2, 0xc2, 0xf8, 0x59, 0x81, 0x7b, 0x8e, 0xf4, 0xb8,
-	0x74, 0x33, 0x09, 0x65, 0xdf, 0xe6, 0x4c, 0x48, 0x72, 0x04, 0x2a, 0xa6, 0x5c, 0x86, 0x81, 0xae,
-	0x1e, 0x2b, 0x9d, 0x3a, 0xad, 0xa1, 0x3d, 0x0c, 0xc8, 0x00, 0x20, 0x0f, 0xc9, 0x45, 0xca, 0x74,
-	0xe5, 0x58, 0xe9, 0xb4, 0x4e, 0x3b, 0xe6, 0x06, 0xd2, 0xbc, 0x85, 0x33, 0xd1, 0x70, 0x17, 0x29,
-	0xa3, 0x75, 0x59, 0xfc, 0x12, 0x02, 0x95, 0x70, 0x26, 0x42, 0xbd, 0x84, 0x7c, 0xfc, 0x27, 0x03,
-	0x50, 0xf1, 0x8c, 0x7e, 0x12, 0xe9, 0x65, 0x44, 0xbf, 0xdd, 0x83, 0x9e, 0x2e, 0xe5, 0xe7, 0xde,
-	0x8c, 0xd1, 0x55, 0x32, 0xf9, 0x04, 0xf5, 0x30, 0x96, 0x8c, 0x5f, 0x7b, 0x3e, 0xd3, 0x2b, 0x48,
-	0x3a, 0xd9, 0x43, 0x1a, 0x16, 0x7a, 0x44, 0xad, 0xd3, 0x89, 0x0e, 0x35, 0x19, 0xce, 0x58, 0x32,
-	0x97, 0xfa, 0x9d, 0x63, 0xa5, 0xd3, 0xa4, 0x85, 0x49, 0x5e, 0xc3, 0xa1, 0xef, 0xa5, 0x72, 0xce,
-	0xd9, 0xe5, 0x75, 0x18, 0x49, 0xc6, 0x85, 0x5e, 0xc5, 0x6e, 0x5a, 0x4b, 0xf7, 0x59, 0xee, 0xcd,
-	0x84, 0x41, 0x28, 0xd2, 0xc8, 0x5b, 0xac, 0x84, 0xb5, 0x5c, 0xb8, 0x74, 0x2f, 0x85, 0xc6, 0x18,
-	0xea, 0xab, 0x61, 0x91, 0x1a, 0x94, 0x7b, 0xa3, 0x91, 0x76, 0x40, 0x5a, 0x00, 0xce, 0x45, 0xdf,
-	0xb1, 0xe8, 0xb0, 0x6f, 0x53, 0x4d, 0x21, 0x0d, 0x50, 0xa7, 0x74, 0xe2, 0x4e, 0xac, 0xc9, 0x48,
-	0x2b, 0x91, 0x26, 0xd4, 0x87, 0xe7, 0xae, 0x4d, 0xcf, 0x7a, 0x96, 0xad, 0x95, 0x09, 0x40, 0xd5,
-	0xba, 0x70, 0xdc, 0xc9, 0x58, 0xab, 0x18, 0xaf, 0xa0, 0xb1, 0x39, 0x20, 0xa2, 0x42, 0xc5, 0xb1,
-	0xdc, 0xa9, 0x76, 0x90, 0x21, 0x3e, 0x0e, 0x7b, 0x63, 0xdb, 0xcd, 0x80, 0xc6, 0x1b, 0x68, 0x6e,
-	0xb5, 0x8f, 0xc2, 0x77, 0xbd, 0x4c, 0x58, 0x85, 0xd2, 0xe0, 0x8b, 0xa6, 0xe0, 0xd7, 0xd5, 0x4a,
-	0x86, 0x09, 0x64, 0x73, 0x76, 0x22, 0x4d, 0x62, 0x81, 0x33, 0x12, 0x73, 0xdf, 0x67, 0x42, 0xe0,
-	0x4a, 0xa8, 0xb4, 0x30, 0x8d, 0x13, 0x38, 0xb4, 0xe3, 0x60, 0xe7, 0x76, 0x29, 0x5b, 0xdb, 0x65,
-	0x7c, 0x06, 0x6d, 0xad, 0xde, 0xc7, 0x26, 0xcf, 0xa1, 0x99, 0x83, 0xfc, 0x24, 0x96, 0x2c, 0x96,
-	0xb8, 0x4b, 0x0d, 0xda, 0x40, 0xa7, 0x95, 0xfb, 0x0c, 0x01, 0x0f, 0x29, 0x4b, 0x13, 0x2e, 0xed,
-	0x38, 0x60, 0xff, 0x7b, 0x90, 0xcd, 0xa2, 0xa5, 0x3d, 0x45, 0xcb, 0x7f, 0x29, 0xda, 0x06, 0xfd,
-	0x76, 0xd1, 0xbc, 0x9f, 0xd3, 0x5f, 0x0a, 0x68, 0x96, 0x17, 0x45, 0xe8, 0x75, 0x18, 0xff, 0x1e,
-	0xfa, 0x8c, 0x38, 0xd0, 0xc2, 0xb1, 0xae, 0x02, 0xe4, 0xe9, 0xbf, 0xf7, 0xb5, 0xfd, 0x6c, 0x67,
-	0x3c, 0xaf, 0x63, 0x1c, 0x90, 0x31, 0x34, 0xec, 0x38, 0x58, 0x23, 0x1f, 0x6f, 0xa5, 0xfc, 0x71,
-	0x2d, 0xed, 0x27, 0x3b, 0xa2, 0x05, 0xee, 0xf4, 0x07, 0xdc, 0x5f, 0xb1, 0xb2, 0x46, 0x79, 0x12,
-	0x45, 0x8c, 0x13, 0x06, 0x0f, 0x36, 0x7a, 0x5d, 0x57, 0x7b, 0xb1, 0xc5, 0xdb, 0x71, 0x07, 0xed,
-	0x97, 0x7b, 0x54, 0x45, 0xf5, 0xfe, 0xa3, 0xaf, 0x47, 0xa8, 0xec, 0xe6, 0x4f, 0x5b, 0x14, 0x5e,
-	0x75, 0x6f, 0x92, 0xe5, 0x0b, 0x77, 0x55, 0xc5, 0xef, 0xfb, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff,
-	0x91, 0x74, 0x1b, 0x85, 0xf8, 0x04, 0x00, 0x00,
+	// 648 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0xdb, 0x4e, 0xdb, 0x40,
+	0x10, 0xc5, 0x49, 0xc8, 0x65, 0x48, 0x82, 0xbb, 0x45, 0xaa, 0x09, 0x14, 0x90, 0x7b, 0x0b, 0x2a,
+	0x4a, 0x54, 0xfa, 0xd2, 0xd7, 0xe0, 0x1a, 0x94, 0x8a, 0x10, 0xb4, 0x36, 0x52, 0xd5, 0x17, 0x64,
+	0xec, 0x05, 0xac, 0x3a, 0x5e, 0x77, 0xbd, 0xa9, 0xca, 0x7f, 0xf4, 0x3b, 0xfa, 0xd8, 0x9f, 0xea,
+	0x4f, 0x54, 0x1e, 0xc7, 0xb9, 0x14, 0x52, 0xf3, 0x64, 0xcf, 0xcc, 0x99, 0x33, 0x67, 0x26, 0x27,
+	0x32, 0xb4, 0xb8, 0x70, 0x3f, 0x88, 0x6e, 0x24, 0xb8, 0xe4, 0x71, 0xd7, 0x95, 0xc2, 0x71, 0x99,
+	0xd7, 0xc1, 0x90, 0xac, 0x8d, 0x9c, 0x9b, 0x91, 0xd3, 0x41, 0x84, 0xfe, 0xab, 0x04, 0x4f, 0x2c,
+	0xe9, 0x08, 0x69, 0x27, 0x10, 0xca, 0xbe, 0x8d, 0x59, 0x2c, 0xc9, 0x26, 0x54, 0xb1, 0xe5, 0xd2,
+	0xf7, 0xb4, 0xea, 0x9e, 0xd2, 0xae, 0xd1, 0x0a, 0xc6, 0x7d, 0x8f, 0x9c, 0x00, 0xa4, 0x25, 0x79,
+	0x17, 0x31, 0x4d, 0xd9, 0x53, 0xda, 0xcd, 0xc3, 0x76, 0x67, 0x8e, 0xb2, 0x73, 0x8f, 0xae, 0x83,
+	0x81, 0x7d, 0x17, 0x31, 0x5a, 0x93, 0xd9, 0x2b, 0x21, 0x50, 0xf2, 0x47, 0xb1, 0xaf, 0x15, 0x90,
+	0x1f, 0xdf, 0xc9, 0x09, 0x54, 0x51, 0xa3, 0xcb, 0x03, 0xad, 0x88, 0xd4, 0x6f, 0x73, 0xa8, 0xcf,
+	0x27, 0xf0, 0x33, 0x67, 0xc4, 0xe8, 0xb4, 0x99, 0x7c, 0x82, 0x9a, 0x1f, 0x4a, 0x26, 0xae, 0x1d,
+	0x97, 0x69, 0x25, 0x64, 0x3a, 0xc8, 0x61, 0xea, 0x67, 0x78, 0xa4, 0x9a, 0xb5, 0x13, 0x0d, 0x2a,
+	0xd2, 0x1f, 0x31, 0x3e, 0x96, 0xda, 0xea, 0x9e, 0xd2, 0x6e, 0xd0, 0x2c, 0x24, 0x6f, 0x60, 0xdd,
+	0x75, 0x22, 0x39, 0x16, 0xec, 0xf2, 0xda, 0x0f, 0x24, 0x13, 0xb1, 0x56, 0xc6, 0x6d, 0x9a, 0x93,
+	0xf4, 0x71, 0x9a, 0x4d, 0x80, 0x9e, 0x1f, 0x47, 0x81, 0x73, 0x37, 0x05, 0x56, 0x52, 0xe0, 0x24,
+	0x3d, 0x01, 0xea, 0x03, 0xa8, 0x4d, 0x8f, 0x45, 0x2a, 0x50, 0xec, 0x9d, 0x9e, 0xaa, 0x2b, 0xa4,
+	0x09, 0x60, 0x5d, 0x1c, 0x59, 0x06, 0xed, 0x1f, 0x99, 0x54, 0x55, 0x48, 0x1d, 0xaa, 0xe7, 0x74,
+	0x68, 0x0f, 0x8d, 0xe1, 0xa9, 0x5a, 0x20, 0x0d, 0xa8, 0xf5, 0xcf, 0x6c, 0x93, 0x1e, 0xf7, 0x0c,
+	0x53, 0x2d, 0x12, 0x80, 0xb2, 0x71, 0x61, 0xd9, 0xc3, 0x81, 0x5a, 0xd2, 0x5f, 0x43, 0x7d, 0xfe,
+	0x40, 0xa4, 0x0a, 0x25, 0xcb, 0xb0, 0xcf, 0xd5, 0x95, 0x84, 0xe2, 0x63, 0xbf, 0x37, 0x30, 0xed,
+	0x84, 0x50, 0xdf, 0x87, 0xc6, 0xc2, 0xfa, 0x08, 0x7c, 0xd7, 0x4b, 0x80, 0x65, 0x28, 0x9c, 0x7c,
+	0x56, 0x15, 0x7c, 0xda, 0x6a, 0x41, 0xef, 0x00, 0x99, 0xbf, 0x5d, 0x1c, 0xf1, 0x30, 0xc6, 0x1b,
+	0xc5, 0x63, 0xd7, 0x65, 0x71, 0x8c, 0x96, 0xa8, 0xd2, 0x2c, 0xd4, 0x0f, 0x60, 0xdd, 0x0c, 0xbd,
+	0xa5, 0xee, 0x52, 0x16, 0xdc, 0xa5, 0x0b, 0x50, 0x67, 0xe8, 0x3c, 0x6e, 0xf2, 0x02, 0x1a, 0x29,
+	0x91, 0xcb, 0x43, 0xc9, 0x42, 0x89, 0x5e, 0xaa, 0xd3, 0x3a, 0x26, 0x8d, 0x34, 0x47, 0x76, 0x61,
+	0xcd, 0xbd, 0x1d, 0x87, 0x5f, 0x2f, 0x5d, 0x3e, 0x0e, 0x25, 0xda, 0xaa, 0x41, 0x01, 0x53, 0x46,
+	0x92, 0xd1, 0x7f, 0x2a, 0xf0, 0x8c, 0xb2, 0x88, 0x0b, 0x69, 0x86, 0x1e, 0x7b, 0xac, 0xd4, 0x79,
+	0x59, 0x85, 0x1c, 0x59, 0xc5, 0x7c, 0x59, 0xa5, 0x7b, 0xb2, 0x5a, 0xa0, 0xdd, 0x57, 0x95, 0x9e,
+	0x44, 0xbf, 0x85, 0xad, 0xb4, 0x66, 0x38, 0x41, 0x80, 0x25, 0x23, 0x69, 0x7c, 0x84, 0xea, 0x0d,
+	0x58, 0xf5, 0x43, 0x8f, 0xfd, 0x40, 0xcd, 0x0d, 0x9a, 0x06, 0xc9, 0x2e, 0x8b, 0x5a, 0xb3, 0x50,
+	0xdf, 0x81, 0xed, 0x87, 0x27, 0xa5, 0x4a, 0x0e, 0x7f, 0x2b, 0xa0, 0x4e, 0x4b, 0x16, 0x13, 0xdf,
+	0x7d, 0x97, 0x11, 0x0b, 0x9a, 0xe8, 0x91, 0x69, 0x81, 0xec, 0xfc, 0xff, 0xcf, 0xd7, 0xda, 0x5d,
+	0x5a, 0x9f, 0x6c, 0xbc, 0x42, 0x06, 0x50, 0x37, 0x43, 0x6f, 0x46, 0xb9, 0xbd, 0xd0, 0xf2, 0x8f,
+	0xc7, 0x5a, 0xcf, 0x97, 0x54, 0x33, 0xba, 0xc3, 0x3f, 0x0a, 0x3c, 0x9d, 0xed, 0xc4, 0x43, 0x29,
+	0x78, 0x10, 0x30, 0x41, 0x18, 0x6c, 0xcc, 0x9d, 0x7d, 0x36, 0xee, 0xe5, 0x02, 0xe1, 0x12, 0xbf,
+	0xb4, 0x5e, 0xe5, 0xa0, 0xa6, 0xdb, 0x8c, 0xb2, 0x31, 0x8b, 0x77, 0x25, 0xed, 0x07, 0x08, 0x1e,
+	0xfc, 0x91, 0x5b, 0xfb, 0x8f, 0x40, 0x66, 0xe3, 0x8e, 0xb6, 0xbe, 0x6c, 0x22, 0xba, 0x9b, 0x7e,
+	0x17, 0x02, 0xff, 0xaa, 0x7b, 0xc3, 0x27, 0x9f, 0x87, 0xab, 0x32, 0x3e, 0xdf, 0xff, 0x1d, 0x00,
+	0xd4, 0xea, 0x9a, 0x45, 0x35, 0x06, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -630,6 +743,11 @@ type CallTraceControllerClient interface {
 	// Report that a call trace has ended
 	//
 	ReportEndedCallTrace(ctx context.Context, in *ReportEndedTraceRequest, opts ...grpc.CallOption) (*ReportEndedTraceResponse, error)
+	// Upload a chunk of a call trace that's too large for a single message.
+	// Chunks are numbered from 0, and the upload is completed by reporting
+	// the ended call trace with the number of chunks uploaded.
+	//
+	ReportCallTraceChunk(ctx context.Context, in *ReportCallTraceChunkRequest, opts ...grpc.CallOption) (*ReportCallTraceChunkResponse, error)
 }
 
 type callTraceControllerClient struct {
@@ -649,11 +767,25 @@ func (c *callTraceControllerClient) ReportEndedCallTrace(ctx context.Context, in
 	return out, nil
 }
 
+func (c *callTraceControllerClient) ReportCallTraceChunk(ctx context.Context, in *ReportCallTraceChunkRequest, opts ...grpc.CallOption) (*ReportCallTraceChunkResponse, error) {
+	out := new(ReportCallTraceChunkResponse)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.CallTraceController/ReportCallTraceChunk", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CallTraceControllerServer is the server API for CallTraceController service.
 type CallTraceControllerServer interface {
 	// Report that a call trace has ended
 	//
 	ReportEndedCallTrace(context.Context, *ReportEndedTraceRequest) (*ReportEndedTraceResponse, error)
+	// Upload a chunk of a call trace that's too large for a single message.
+	// Chunks are numbered from 0, and the upload is completed by reporting
+	// the ended call trace with the number of chunks uploaded.
+	//
+	ReportCallTraceChunk(context.Context, *ReportCallTraceChunkRequest) (*ReportCallTraceChunkResponse, error)
 }
 
 // UnimplementedCallTraceControllerServer can be embedded to have forward compatible implementations.
@@ -663,6 +795,9 @@ type UnimplementedCallTraceControllerServer struct {
 func (*UnimplementedCallTraceControllerServer) ReportEndedCallTrace(ctx context.Context, req *ReportEndedTraceRequest) (*ReportEndedTraceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReportEndedCallTrace not implemented")
 }
+func (*UnimplementedCallTraceControllerServer) ReportCallTraceChunk(ctx context.Context, req *ReportCallTraceChunkRequest) (*ReportCallTraceChunkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportCallTraceChunk not implemented")
+}
 
 func RegisterCallTraceControllerServer(s *grpc.Server, srv CallTraceControllerServer) {
 	s.RegisterService(&_CallTraceController_serviceDesc, srv)
@@ -686,6 +821,24 @@ func _CallTraceController_ReportEndedCallTrace_Handler(srv interface{}, ctx cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CallTraceController_ReportCallTraceChunk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportCallTraceChunkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CallTraceControllerServer).ReportCallTraceChunk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.CallTraceController/ReportCallTraceChunk",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CallTraceControllerServer).ReportCallTraceChunk(ctx, req.(*ReportCallTraceChunkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _CallTraceController_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "magma.orc8r.CallTraceController",
 	HandlerType: (*CallTraceControllerServer)(nil),
@@ -694,6 +847,10 @@ var _CallTraceController_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ReportEndedCallTrace",
 			Handler:    _CallTraceController_ReportEndedCallTrace_Handler,
 		},
+		{
+			MethodName: "ReportCallTraceChunk",
+			Handler:    _CallTraceController_ReportCallTraceChunk_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "orc8r/protos/ctraced.proto",
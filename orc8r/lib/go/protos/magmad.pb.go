@@ -860,74 +860,74 @@ func init() {
 func init() { proto.RegisterFile("orc8r/protos/magmad.proto", fileDescriptor_9e01809f6f4dd6f6) }
 
 var fileDescriptor_9e01809f6f4dd6f6 = []byte{
-	// 1057 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0xeb, 0x52, 0xdb, 0x46,
-	0x14, 0xb6, 0x6c, 0x7c, 0x3b, 0x5c, 0xec, 0x6e, 0x28, 0x35, 0x0e, 0x4c, 0xe8, 0x32, 0x93, 0x21,
-	0xcd, 0xd4, 0xce, 0x90, 0xf4, 0xf2, 0xab, 0x2d, 0x38, 0x5c, 0x5c, 0x0c, 0xa1, 0x6b, 0x87, 0xcc,
-	0xe4, 0x8f, 0x46, 0x96, 0x36, 0x42, 0x83, 0xa5, 0x55, 0x77, 0xd7, 0x40, 0xfe, 0xb7, 0x0f, 0xd0,
-	0x57, 0xe8, 0x4b, 0xf5, 0x09, 0xfa, 0x1e, 0x1d, 0xad, 0x56, 0x46, 0x02, 0x9b, 0xd2, 0xe6, 0x97,
-	0x75, 0xce, 0x7e, 0xdf, 0xb9, 0xed, 0x39, 0x67, 0x0d, 0xab, 0x8c, 0xdb, 0xdf, 0xf3, 0x76, 0xc8,
-	0x99, 0x64, 0xa2, 0xed, 0x5b, 0xae, 0x6f, 0x39, 0x2d, 0x25, 0xa1, 0x79, 0x25, 0xb5, 0x14, 0xa0,
-	0x99, 0xc5, 0xd9, 0xcc, 0xf7, 0x59, 0x10, 0xe3, 0x9a, 0xcd, 0xac, 0x09, 0x9b, 0x05, 0x1f, 0x3c,
-	0x57, 0x9f, 0xad, 0xb9, 0x8c, 0xb9, 0x23, 0x1a, 0x1f, 0x0e, 0xc7, 0x1f, 0xda, 0x42, 0xf2, 0xb1,
-	0x2d, 0xe3, 0x53, 0x7c, 0x04, 0x70, 0xea, 0x05, 0xee, 0xa9, 0xc5, 0x2d, 0x5f, 0xa0, 0x35, 0x80,
-	0x73, 0x26, 0xa4, 0xc9, 0xb8, 0xe9, 0x85, 0x0d, 0x63, 0xc3, 0xd8, 0xaa, 0x92, 0x4a, 0xa4, 0x79,
-	0xc3, 0xbb, 0x21, 0x7a, 0x02, 0xf3, 0xc1, 0xd8, 0x37, 0x43, 0xcb, 0xbe, 0xa0, 0x52, 0x34, 0xf2,
-	0x1b, 0xc6, 0x56, 0x91, 0x40, 0x30, 0xf6, 0x4f, 0x63, 0x0d, 0x1e, 0x43, 0x7d, 0xc0, 0x2d, 0x9b,
-	0x72, 0x36, 0x96, 0xf4, 0x41, 0x26, 0x57, 0xa1, 0xe2, 0x5b, 0xd7, 0xe6, 0x39, 0x0b, 0x13, 0x7b,
-	0x65, 0xdf, 0xba, 0x3e, 0x64, 0xa1, 0x40, 0x5b, 0x50, 0x1f, 0x7e, 0x94, 0x54, 0x98, 0x21, 0xe5,
-	0xda, 0x67, 0xa3, 0xa0, 0x20, 0x4b, 0x4a, 0x7f, 0x4a, 0x79, 0xec, 0x17, 0xff, 0x66, 0x00, 0x3a,
-	0xa1, 0xf2, 0x8a, 0xf1, 0x8b, 0x01, 0x15, 0x92, 0xd0, 0x5f, 0xc7, 0x54, 0x48, 0xf4, 0x35, 0x14,
-	0x43, 0x2f, 0x70, 0x45, 0xc3, 0xd8, 0x28, 0x6c, 0xcd, 0x6f, 0x7f, 0xd1, 0x4a, 0x15, 0xb3, 0x75,
-	0x93, 0x34, 0x89, 0x51, 0xe8, 0x47, 0x98, 0x97, 0x93, 0xe0, 0xa3, 0x68, 0x22, 0xd2, 0x7a, 0x86,
-	0x74, 0x3b, 0x39, 0x92, 0x66, 0xe0, 0xbf, 0x8d, 0xb8, 0x96, 0x84, 0x8a, 0xf1, 0x48, 0x7e, 0x62,
-	0x2d, 0xd1, 0x32, 0x14, 0x29, 0xe7, 0x8c, 0xab, 0x9c, 0xab, 0x24, 0x16, 0x50, 0x1b, 0x1e, 0x69,
-	0x8a, 0x29, 0xb9, 0x15, 0x08, 0xdf, 0x93, 0x92, 0x3a, 0x8d, 0x39, 0x45, 0x47, 0xfa, 0x68, 0x70,
-	0x73, 0x82, 0x9e, 0x41, 0x3d, 0x21, 0x70, 0x6a, 0x53, 0xef, 0x92, 0x3a, 0x8d, 0xa2, 0x42, 0xd7,
-	0xb4, 0x9e, 0x68, 0x35, 0x7a, 0x0a, 0x35, 0xeb, 0xd2, 0x35, 0x39, 0x15, 0x21, 0x0b, 0x04, 0x35,
-	0x7d, 0xd1, 0x28, 0x6d, 0x18, 0x5b, 0x79, 0xb2, 0x68, 0x5d, 0x46, 0x49, 0x29, 0xed, 0xb1, 0xc0,
-	0x03, 0xa8, 0xa5, 0x0a, 0xc1, 0xd9, 0x90, 0xa2, 0x26, 0xa8, 0xcc, 0x02, 0xcb, 0xa7, 0xe9, 0x4c,
-	0x23, 0x19, 0x2d, 0x41, 0xde, 0x0b, 0x55, 0x82, 0x55, 0x92, 0xf7, 0x42, 0xf4, 0x39, 0x94, 0xb8,
-	0x94, 0x91, 0xf5, 0x82, 0xb2, 0x5e, 0xe4, 0x52, 0x1e, 0x0b, 0xfc, 0x0e, 0x16, 0x6f, 0xac, 0x1e,
-	0xb2, 0x10, 0xd5, 0xa1, 0xe0, 0x39, 0xd7, 0xca, 0x5c, 0x91, 0x44, 0x9f, 0xe8, 0x15, 0x94, 0xc2,
-	0xc8, 0x5d, 0x72, 0x39, 0x6b, 0xb3, 0x2e, 0x27, 0x02, 0x11, 0x8d, 0xc5, 0x97, 0xe9, 0xa6, 0xd4,
-	0x77, 0x33, 0x29, 0xae, 0x91, 0x2e, 0x6e, 0xf6, 0xc6, 0xf2, 0xb7, 0x6e, 0xac, 0x05, 0x73, 0xaa,
-	0x4d, 0x0b, 0xca, 0x77, 0x73, 0x86, 0xef, 0x43, 0x16, 0x12, 0x85, 0xc3, 0xbf, 0x1b, 0xf0, 0x28,
-	0xd3, 0x95, 0x71, 0x01, 0xff, 0xbd, 0x2d, 0xe3, 0x18, 0xff, 0x57, 0x5b, 0x6a, 0x6a, 0xa6, 0x2d,
-	0xbf, 0x81, 0xe5, 0x03, 0x2a, 0x0f, 0x2c, 0x49, 0xaf, 0xac, 0x8f, 0x5d, 0x67, 0x12, 0xc7, 0x3a,
-	0x80, 0x1b, 0x2b, 0x4d, 0xcf, 0xd1, 0x85, 0xa8, 0xba, 0x09, 0x0c, 0xbf, 0x82, 0x15, 0x42, 0x85,
-	0xb4, 0xb8, 0xec, 0x53, 0x7e, 0xe9, 0xd9, 0x54, 0x24, 0x73, 0xd5, 0x84, 0x8a, 0xd0, 0x2a, 0x95,
-	0x43, 0x95, 0x4c, 0x64, 0x6c, 0x45, 0xce, 0x02, 0xca, 0x3d, 0xbb, 0xc3, 0x7c, 0xdf, 0x0a, 0x1c,
-	0xbd, 0x05, 0x1a, 0x50, 0xb6, 0x63, 0x85, 0xf6, 0x94, 0x88, 0xa8, 0x0d, 0xa5, 0x50, 0x61, 0x54,
-	0xc1, 0xa3, 0x7a, 0xc4, 0xfb, 0xaa, 0x95, 0xec, 0xab, 0x56, 0x5f, 0xed, 0x2b, 0xa2, 0x61, 0xf8,
-	0x18, 0x56, 0xb2, 0x2e, 0x26, 0x19, 0xbd, 0x84, 0x4a, 0xd2, 0xbc, 0xca, 0xcb, 0x3d, 0xc6, 0x26,
-	0x40, 0xfc, 0x1c, 0x6a, 0x03, 0xcb, 0x1b, 0xf5, 0x98, 0x3b, 0x49, 0xb0, 0x01, 0x65, 0x9d, 0x50,
-	0x12, 0xac, 0x16, 0xf1, 0x3a, 0x94, 0x7b, 0xcc, 0xed, 0x79, 0x01, 0x45, 0x08, 0xe6, 0x46, 0x5e,
-	0x90, 0x20, 0xd4, 0x37, 0xfe, 0xd3, 0x80, 0x95, 0xce, 0x39, 0xb5, 0x2f, 0xfa, 0xd2, 0x92, 0x74,
-	0x44, 0x85, 0x98, 0xc4, 0xb6, 0x0f, 0x15, 0xcb, 0xbd, 0x32, 0x7d, 0xe6, 0xc4, 0x94, 0xa5, 0xed,
-	0xe7, 0x99, 0x3b, 0x9c, 0x4e, 0x6b, 0xed, 0x1c, 0xbc, 0x3b, 0x66, 0x0e, 0x25, 0x65, 0xcb, 0xbd,
-	0x8a, 0x3e, 0xf0, 0x4f, 0x50, 0xd6, 0x3a, 0x34, 0x0f, 0xe5, 0xee, 0xc9, 0xd9, 0x4e, 0xaf, 0xfb,
-	0xba, 0x9e, 0x43, 0x8b, 0x50, 0xed, 0x0f, 0x76, 0x06, 0x7b, 0xbd, 0xbd, 0x7e, 0xbf, 0x6e, 0xa0,
-	0x05, 0xa8, 0x28, 0x71, 0xff, 0x6d, 0xaf, 0x9e, 0x8f, 0x90, 0x9d, 0x37, 0x84, 0xbc, 0x3d, 0x1d,
-	0xd4, 0x0b, 0xf8, 0x0f, 0x03, 0x56, 0x3b, 0xea, 0x81, 0x18, 0x73, 0x9a, 0xf2, 0x18, 0xe7, 0xde,
-	0x05, 0x88, 0x5f, 0x0f, 0xd3, 0xf6, 0x1d, 0x1d, 0xe9, 0x57, 0xd9, 0x48, 0x67, 0x71, 0x5b, 0x1d,
-	0xdf, 0x21, 0xd5, 0x98, 0xdd, 0xf1, 0x1d, 0xfc, 0x0c, 0x0a, 0x1d, 0xdf, 0x41, 0x55, 0x28, 0x76,
-	0x0e, 0xf7, 0x3a, 0x47, 0xf5, 0x5c, 0x14, 0xc7, 0xeb, 0x6e, 0x7f, 0x67, 0xb7, 0xb7, 0x57, 0x37,
-	0x10, 0x40, 0x69, 0xef, 0x44, 0x7d, 0xe7, 0xb7, 0xff, 0x2a, 0x41, 0xe9, 0x58, 0x3d, 0x7c, 0xe8,
-	0x3b, 0x58, 0xec, 0xa7, 0xbb, 0x0e, 0x7d, 0x96, 0xf1, 0x7e, 0xc6, 0x3c, 0xa7, 0x79, 0x57, 0x85,
-	0x73, 0xe8, 0x5b, 0x58, 0xe8, 0x4b, 0x16, 0xfe, 0x67, 0xde, 0x0b, 0x28, 0x11, 0x3a, 0x64, 0x4c,
-	0x3e, 0x98, 0x71, 0x04, 0xb5, 0x5b, 0xa3, 0x81, 0x36, 0x33, 0xb8, 0xe9, 0x83, 0x33, 0xdd, 0xd8,
-	0x0f, 0x00, 0x7d, 0x2a, 0xe3, 0xa2, 0x0a, 0xf4, 0x38, 0x03, 0xd1, 0x43, 0xab, 0x0f, 0x67, 0xf2,
-	0x0f, 0x6e, 0xf8, 0x53, 0x52, 0xb8, 0xcf, 0x24, 0xce, 0xa1, 0x33, 0xa8, 0x91, 0x71, 0x90, 0x5a,
-	0x54, 0x02, 0x3d, 0xc9, 0x30, 0xee, 0xbe, 0xac, 0xcd, 0x8d, 0xd9, 0x00, 0x3d, 0x55, 0x39, 0xb4,
-	0x0f, 0x0b, 0xe9, 0xb5, 0x33, 0x2d, 0xb2, 0x2f, 0xb3, 0x91, 0x4d, 0x59, 0x52, 0x38, 0x87, 0xde,
-	0xc3, 0x52, 0x76, 0xdc, 0xd1, 0x6d, 0xda, 0xdd, 0x75, 0xd3, 0xdc, 0xbc, 0x07, 0x92, 0xb2, 0xbd,
-	0x0b, 0x95, 0x64, 0xf6, 0xd1, 0xad, 0xc7, 0x24, 0xbb, 0x12, 0x9a, 0xcb, 0x99, 0x53, 0xbd, 0x03,
-	0x70, 0xee, 0x85, 0x81, 0x7e, 0x86, 0xa5, 0xec, 0xec, 0x4e, 0xcb, 0x74, 0xf3, 0x01, 0xb3, 0x8e,
-	0x73, 0xe8, 0x17, 0x40, 0x77, 0xa7, 0x0b, 0x3d, 0x7d, 0xd8, 0xf8, 0x4d, 0x6d, 0x8f, 0xdd, 0xc7,
-	0xef, 0x57, 0x95, 0xb6, 0x1d, 0xff, 0x43, 0x1c, 0x79, 0xc3, 0xb6, 0xcb, 0xf4, 0x1f, 0xc5, 0x61,
-	0x49, 0xfd, 0xbe, 0xfc, 0x27, 0x00, 0x00, 0xff, 0xff, 0x11, 0x2a, 0x2c, 0x88, 0x82, 0x0a, 0x00,
-	0x00,
+	// 1064 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0xdd, 0x52, 0x1b, 0x37,
+	0x14, 0xf6, 0xda, 0xf8, 0xef, 0x18, 0xb0, 0xab, 0x50, 0x6a, 0x1c, 0x98, 0x50, 0x31, 0x93, 0x21,
+	0xcd, 0xd4, 0xce, 0x90, 0xa4, 0xed, 0x55, 0x5b, 0x70, 0xf8, 0x71, 0x31, 0x84, 0xca, 0x0e, 0x99,
+	0xc9, 0x8d, 0x47, 0xde, 0x55, 0x96, 0x1d, 0xbc, 0xab, 0xad, 0x24, 0x03, 0xb9, 0x6f, 0x6f, 0x3b,
+	0xd3, 0x57, 0xe8, 0xbb, 0xf5, 0x3d, 0x3a, 0xab, 0x95, 0xcd, 0x2e, 0x18, 0x4a, 0xdb, 0x2b, 0xef,
+	0x39, 0xfa, 0xbe, 0xf3, 0xa7, 0x73, 0x8e, 0x0c, 0x2b, 0x5c, 0xd8, 0xdf, 0x89, 0x56, 0x28, 0xb8,
+	0xe2, 0xb2, 0xe5, 0x53, 0xd7, 0xa7, 0x4e, 0x53, 0x4b, 0xa8, 0xa2, 0xa5, 0xa6, 0x06, 0x34, 0xd2,
+	0x38, 0x9b, 0xfb, 0x3e, 0x0f, 0x62, 0x5c, 0xa3, 0x91, 0x36, 0x61, 0xf3, 0xe0, 0xa3, 0xe7, 0x9a,
+	0xb3, 0x55, 0x97, 0x73, 0x77, 0xc4, 0xe2, 0xc3, 0xe1, 0xf8, 0x63, 0x4b, 0x2a, 0x31, 0xb6, 0x55,
+	0x7c, 0x8a, 0x0f, 0x01, 0x4e, 0xbc, 0xc0, 0x3d, 0xa1, 0x82, 0xfa, 0x12, 0xad, 0x02, 0x9c, 0x71,
+	0xa9, 0x06, 0x5c, 0x0c, 0xbc, 0xb0, 0x6e, 0xad, 0x5b, 0x9b, 0x65, 0x52, 0x8a, 0x34, 0x6f, 0x45,
+	0x27, 0x44, 0x4f, 0xa0, 0x12, 0x8c, 0xfd, 0x41, 0x48, 0xed, 0x73, 0xa6, 0x64, 0x3d, 0xbb, 0x6e,
+	0x6d, 0xe6, 0x09, 0x04, 0x63, 0xff, 0x24, 0xd6, 0xe0, 0x31, 0xd4, 0xfa, 0x82, 0xda, 0x4c, 0xf0,
+	0xb1, 0x62, 0x0f, 0x32, 0xb9, 0x02, 0x25, 0x9f, 0x5e, 0x0d, 0xce, 0x78, 0x38, 0xb1, 0x57, 0xf4,
+	0xe9, 0xd5, 0x01, 0x0f, 0x25, 0xda, 0x84, 0xda, 0xf0, 0x93, 0x62, 0x72, 0x10, 0x32, 0x61, 0x7c,
+	0xd6, 0x73, 0x1a, 0xb2, 0xa8, 0xf5, 0x27, 0x4c, 0xc4, 0x7e, 0xf1, 0xaf, 0x16, 0xa0, 0x63, 0xa6,
+	0x2e, 0xb9, 0x38, 0xef, 0x33, 0xa9, 0x08, 0xfb, 0x65, 0xcc, 0xa4, 0x42, 0x5f, 0x43, 0x3e, 0xf4,
+	0x02, 0x57, 0xd6, 0xad, 0xf5, 0xdc, 0x66, 0x65, 0xeb, 0x8b, 0x66, 0xa2, 0x98, 0xcd, 0xeb, 0xa4,
+	0x49, 0x8c, 0x42, 0x3f, 0x40, 0x45, 0x4d, 0x83, 0x8f, 0xa2, 0x89, 0x48, 0x6b, 0x29, 0xd2, 0xcd,
+	0xe4, 0x48, 0x92, 0x81, 0xff, 0xb2, 0xe2, 0x5a, 0x12, 0x26, 0xc7, 0x23, 0xf5, 0x3f, 0x6b, 0x89,
+	0x96, 0x20, 0xcf, 0x84, 0xe0, 0x42, 0xe7, 0x5c, 0x26, 0xb1, 0x80, 0x5a, 0xf0, 0xc8, 0x50, 0x06,
+	0x4a, 0xd0, 0x40, 0xfa, 0x9e, 0x52, 0xcc, 0xa9, 0xcf, 0x69, 0x3a, 0x32, 0x47, 0xfd, 0xeb, 0x13,
+	0xf4, 0x0c, 0x6a, 0x13, 0x82, 0x60, 0x36, 0xf3, 0x2e, 0x98, 0x53, 0xcf, 0x6b, 0x74, 0xd5, 0xe8,
+	0x89, 0x51, 0xa3, 0xa7, 0x50, 0xa5, 0x17, 0xee, 0x40, 0x30, 0x19, 0xf2, 0x40, 0xb2, 0x81, 0x2f,
+	0xeb, 0x85, 0x75, 0x6b, 0x33, 0x4b, 0x16, 0xe8, 0x85, 0x4b, 0x8c, 0xf6, 0x48, 0xe2, 0x3e, 0x54,
+	0x13, 0x85, 0x10, 0x7c, 0xc8, 0x50, 0x03, 0x74, 0x66, 0x01, 0xf5, 0x59, 0x32, 0xd3, 0x48, 0x46,
+	0x8b, 0x90, 0xf5, 0x42, 0x9d, 0x60, 0x99, 0x64, 0xbd, 0x10, 0x7d, 0x0e, 0x05, 0xa1, 0x54, 0x64,
+	0x3d, 0xa7, 0xad, 0xe7, 0x85, 0x52, 0x47, 0x12, 0xbf, 0x87, 0x85, 0x6b, 0xab, 0x07, 0x3c, 0x44,
+	0x35, 0xc8, 0x79, 0xce, 0x95, 0x36, 0x97, 0x27, 0xd1, 0x27, 0x7a, 0x05, 0x85, 0x30, 0x72, 0x37,
+	0xb9, 0x9c, 0xd5, 0xbb, 0x2e, 0x27, 0x02, 0x11, 0x83, 0xc5, 0x17, 0xc9, 0xa6, 0x34, 0x77, 0x33,
+	0x2d, 0xae, 0x95, 0x2c, 0x6e, 0xfa, 0xc6, 0xb2, 0x37, 0x6e, 0xac, 0x09, 0x73, 0xba, 0x4d, 0x73,
+	0xda, 0x77, 0xe3, 0x0e, 0xdf, 0x07, 0x3c, 0x24, 0x1a, 0x87, 0x7f, 0xb3, 0xe0, 0x51, 0xaa, 0x2b,
+	0xe3, 0x02, 0xfe, 0x73, 0x5b, 0xc6, 0x31, 0xfe, 0xa7, 0xb6, 0x34, 0xd4, 0x54, 0x5b, 0xbe, 0x86,
+	0xa5, 0x7d, 0xa6, 0xf6, 0xa9, 0x62, 0x97, 0xf4, 0x53, 0xc7, 0x99, 0xc6, 0xb1, 0x06, 0xe0, 0xc6,
+	0xca, 0x81, 0xe7, 0x98, 0x42, 0x94, 0xdd, 0x09, 0x0c, 0xbf, 0x82, 0x65, 0xc2, 0xa4, 0xa2, 0x42,
+	0xf5, 0x98, 0xb8, 0xf0, 0x6c, 0x26, 0x27, 0x73, 0xd5, 0x80, 0x92, 0x34, 0x2a, 0x9d, 0x43, 0x99,
+	0x4c, 0x65, 0x4c, 0x23, 0x67, 0x01, 0x13, 0x9e, 0xdd, 0xe6, 0xbe, 0x4f, 0x03, 0xc7, 0x6c, 0x81,
+	0x3a, 0x14, 0xed, 0x58, 0x61, 0x3c, 0x4d, 0x44, 0xd4, 0x82, 0x42, 0xa8, 0x31, 0xba, 0xe0, 0x51,
+	0x3d, 0xe2, 0x7d, 0xd5, 0x9c, 0xec, 0xab, 0x66, 0x4f, 0xef, 0x2b, 0x62, 0x60, 0xf8, 0x08, 0x96,
+	0xd3, 0x2e, 0xa6, 0x19, 0xbd, 0x84, 0xd2, 0xa4, 0x79, 0xeb, 0xd6, 0xfd, 0xc6, 0xa6, 0x40, 0xfc,
+	0x1c, 0xaa, 0x7d, 0xea, 0x8d, 0xba, 0xdc, 0x9d, 0x26, 0x58, 0x87, 0xa2, 0x49, 0x68, 0x12, 0xac,
+	0x11, 0xf1, 0x1a, 0x14, 0xbb, 0xdc, 0xed, 0x7a, 0x01, 0x43, 0x08, 0xe6, 0x46, 0x5e, 0x30, 0x41,
+	0xe8, 0x6f, 0xfc, 0xa7, 0x05, 0xcb, 0xed, 0x33, 0x66, 0x9f, 0xf7, 0x14, 0x55, 0x6c, 0xc4, 0xa4,
+	0x9c, 0xc6, 0xb6, 0x07, 0x25, 0xea, 0x5e, 0x0e, 0x7c, 0xee, 0xc4, 0x94, 0xc5, 0xad, 0xe7, 0xa9,
+	0x3b, 0x9c, 0x4d, 0x6b, 0x6e, 0xef, 0xbf, 0x3f, 0xe2, 0x0e, 0x23, 0x45, 0xea, 0x5e, 0x46, 0x1f,
+	0xf8, 0x47, 0x28, 0x1a, 0x1d, 0xaa, 0x40, 0xb1, 0x73, 0x7c, 0xba, 0xdd, 0xed, 0xbc, 0xa9, 0x65,
+	0xd0, 0x02, 0x94, 0x7b, 0xfd, 0xed, 0xfe, 0x6e, 0x77, 0xb7, 0xd7, 0xab, 0x59, 0x68, 0x1e, 0x4a,
+	0x5a, 0xdc, 0x7b, 0xd7, 0xad, 0x65, 0x23, 0x64, 0xfb, 0x2d, 0x21, 0xef, 0x4e, 0xfa, 0xb5, 0x1c,
+	0xfe, 0xc3, 0x82, 0x95, 0xb6, 0x7e, 0x20, 0xc6, 0x82, 0x25, 0x3c, 0xc6, 0xb9, 0x77, 0x00, 0xe2,
+	0xd7, 0x63, 0x60, 0xfb, 0x8e, 0x89, 0xf4, 0xab, 0x74, 0xa4, 0x77, 0x71, 0x9b, 0x6d, 0xdf, 0x21,
+	0xe5, 0x98, 0xdd, 0xf6, 0x1d, 0xfc, 0x0c, 0x72, 0x6d, 0xdf, 0x41, 0x65, 0xc8, 0xb7, 0x0f, 0x76,
+	0xdb, 0x87, 0xb5, 0x4c, 0x14, 0xc7, 0x9b, 0x4e, 0x6f, 0x7b, 0xa7, 0xbb, 0x5b, 0xb3, 0x10, 0x40,
+	0x61, 0xf7, 0x58, 0x7f, 0x67, 0xb7, 0x7e, 0x2f, 0x42, 0xe1, 0x48, 0x3f, 0x7c, 0xe8, 0x5b, 0x58,
+	0xe8, 0x25, 0xbb, 0x0e, 0x7d, 0x96, 0xf2, 0x7e, 0xca, 0x3d, 0xa7, 0x71, 0x5b, 0x85, 0x33, 0xe8,
+	0x1b, 0x98, 0xef, 0x29, 0x1e, 0xfe, 0x6b, 0xde, 0x0b, 0x28, 0x10, 0x36, 0xe4, 0x5c, 0x3d, 0x98,
+	0x71, 0x08, 0xd5, 0x1b, 0xa3, 0x81, 0x36, 0x52, 0xb8, 0xd9, 0x83, 0x33, 0xdb, 0xd8, 0xf7, 0x00,
+	0x3d, 0xa6, 0xe2, 0xa2, 0x4a, 0xf4, 0x38, 0x05, 0x31, 0x43, 0x6b, 0x0e, 0xef, 0xe4, 0xef, 0x5f,
+	0xf3, 0x67, 0xa4, 0x70, 0x9f, 0x49, 0x9c, 0x41, 0xa7, 0x50, 0x25, 0xe3, 0x20, 0xb1, 0xa8, 0x24,
+	0x7a, 0x92, 0x62, 0xdc, 0x7e, 0x59, 0x1b, 0xeb, 0x77, 0x03, 0xcc, 0x54, 0x65, 0xd0, 0x1e, 0xcc,
+	0x27, 0xd7, 0xce, 0xac, 0xc8, 0xbe, 0x4c, 0x47, 0x36, 0x63, 0x49, 0xe1, 0x0c, 0xfa, 0x00, 0x8b,
+	0xe9, 0x71, 0x47, 0x37, 0x69, 0xb7, 0xd7, 0x4d, 0x63, 0xe3, 0x1e, 0x48, 0xc2, 0xf6, 0x0e, 0x94,
+	0x26, 0xb3, 0x8f, 0x6e, 0x3c, 0x26, 0xe9, 0x95, 0xd0, 0x58, 0x4a, 0x9d, 0x9a, 0x1d, 0x80, 0x33,
+	0x2f, 0x2c, 0xf4, 0x13, 0x2c, 0xa6, 0x67, 0x77, 0x56, 0xa6, 0x1b, 0x0f, 0x98, 0x75, 0x9c, 0x41,
+	0x3f, 0x03, 0xba, 0x3d, 0x5d, 0xe8, 0xe9, 0xc3, 0xc6, 0x6f, 0x76, 0x7b, 0xbc, 0x86, 0x4a, 0xdc,
+	0xdd, 0x52, 0x09, 0x1a, 0x3e, 0xb4, 0xc5, 0x77, 0x1e, 0x7f, 0x58, 0xd1, 0xda, 0x56, 0xfc, 0xc7,
+	0x72, 0xe4, 0x0d, 0x5b, 0x2e, 0x37, 0xff, 0x2f, 0x87, 0x05, 0xfd, 0xfb, 0xf2, 0xef, 0x01, 0x00,
+	0xd3, 0x9d, 0x7a, 0xe4, 0xb9, 0x0a, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -966,6 +966,9 @@ type MagmadClient interface {
 	CheckStateless(ctx context.Context, in *Void, opts ...grpc.CallOption) (*CheckStatelessResponse, error)
 	// ConfigureStateless configures the stateless mode of AGW
 	ConfigureStateless(ctx context.Context, in *ConfigureStatelessRequest, opts ...grpc.CallOption) (*Void, error)
+	// Rebootstrap obtains a new gateway certificate via the bootstrapper,
+	// regardless of the current certificate's expiry
+	Rebootstrap(ctx context.Context, in *Void, opts ...grpc.CallOption) (*Void, error)
 }
 
 type magmadClient struct {
@@ -1107,6 +1110,15 @@ func (c *magmadClient) ConfigureStateless(ctx context.Context, in *ConfigureStat
 	return out, nil
 }
 
+func (c *magmadClient) Rebootstrap(ctx context.Context, in *Void, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.Magmad/Rebootstrap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MagmadServer is the server API for Magmad service.
 type MagmadServer interface {
 	// Starts all magma services
@@ -1133,6 +1145,9 @@ type MagmadServer interface {
 	CheckStateless(context.Context, *Void) (*CheckStatelessResponse, error)
 	// ConfigureStateless configures the stateless mode of AGW
 	ConfigureStateless(context.Context, *ConfigureStatelessRequest) (*Void, error)
+	// Rebootstrap obtains a new gateway certificate via the bootstrapper,
+	// regardless of the current certificate's expiry
+	Rebootstrap(context.Context, *Void) (*Void, error)
 }
 
 // UnimplementedMagmadServer can be embedded to have forward compatible implementations.
@@ -1175,6 +1190,9 @@ func (*UnimplementedMagmadServer) CheckStateless(ctx context.Context, req *Void)
 func (*UnimplementedMagmadServer) ConfigureStateless(ctx context.Context, req *ConfigureStatelessRequest) (*Void, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ConfigureStateless not implemented")
 }
+func (*UnimplementedMagmadServer) Rebootstrap(ctx context.Context, req *Void) (*Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rebootstrap not implemented")
+}
 
 func RegisterMagmadServer(s *grpc.Server, srv MagmadServer) {
 	s.RegisterService(&_Magmad_serviceDesc, srv)
@@ -1399,6 +1417,24 @@ func _Magmad_ConfigureStateless_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Magmad_Rebootstrap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Void)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagmadServer).Rebootstrap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.Magmad/Rebootstrap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagmadServer).Rebootstrap(ctx, req.(*Void))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Magmad_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "magma.orc8r.Magmad",
 	HandlerType: (*MagmadServer)(nil),
@@ -1447,6 +1483,10 @@ var _Magmad_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ConfigureStateless",
 			Handler:    _Magmad_ConfigureStateless_Handler,
 		},
+		{
+			MethodName: "Rebootstrap",
+			Handler:    _Magmad_Rebootstrap_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -91,7 +91,17 @@ type GetStatesRequest struct {
 	// If non-empty, the value of id_prefix will be used to do a prefix-match
 	// on the keys of queried states. This argument supersedes any value for
 	// idFilter.
-	IdPrefix             string   `protobuf:"bytes,13,opt,name=id_prefix,json=idPrefix,proto3" json:"id_prefix,omitempty"`
+	IdPrefix string `protobuf:"bytes,13,opt,name=id_prefix,json=idPrefix,proto3" json:"id_prefix,omitempty"`
+	// If non-empty, only states most recently reported by the gateway with
+	// this hardware ID are returned.
+	ReporterHardwareId string `protobuf:"bytes,14,opt,name=reporter_hardware_id,json=reporterHardwareId,proto3" json:"reporter_hardware_id,omitempty"`
+	// If non-zero, at most page_size states are returned, ordered by type
+	// then device ID, along with a token for the next page.
+	// Pagination isn't supported for requests specifying ids.
+	PageSize uint32 `protobuf:"varint,15,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the opaque token, returned in a previous response, from
+	// which to continue a paginated search.
+	PageToken            string   `protobuf:"bytes,16,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -164,8 +174,32 @@ func (m *GetStatesRequest) GetIdPrefix() string {
 	return ""
 }
 
+func (m *GetStatesRequest) GetReporterHardwareId() string {
+	if m != nil {
+		return m.ReporterHardwareId
+	}
+	return ""
+}
+
+func (m *GetStatesRequest) GetPageSize() uint32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetStatesRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
 type GetStatesResponse struct {
-	States               []*State `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`
+	States []*State `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`
+	// next_page_token is the token for the next page of a paginated search.
+	// Empty when there are no more states.
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -203,6 +237,13 @@ func (m *GetStatesResponse) GetStates() []*State {
 	return nil
 }
 
+func (m *GetStatesResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 type ReportStatesRequest struct {
 	States               []*State `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -508,6 +549,66 @@ func (m *SyncStatesResponse) GetUnsyncedStates() []*IDAndVersion {
 	return nil
 }
 
+type CompareAndSwapStatesRequest struct {
+	// networkID defaults to the calling gateway's network when empty.
+	NetworkID string `protobuf:"bytes,1,opt,name=networkID,proto3" json:"networkID,omitempty"`
+	// states to write.
+	States []*State `protobuf:"bytes,2,rep,name=states,proto3" json:"states,omitempty"`
+	// expectedVersions are the versions the states must currently be stored
+	// at for the write to succeed. A version of 0 requires the state to not
+	// exist.
+	ExpectedVersions     []*IDAndVersion `protobuf:"bytes,3,rep,name=expectedVersions,proto3" json:"expectedVersions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *CompareAndSwapStatesRequest) Reset()         { *m = CompareAndSwapStatesRequest{} }
+func (m *CompareAndSwapStatesRequest) String() string { return proto.CompactTextString(m) }
+func (*CompareAndSwapStatesRequest) ProtoMessage()    {}
+func (*CompareAndSwapStatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_645e93724c8b4dfe, []int{10}
+}
+
+func (m *CompareAndSwapStatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompareAndSwapStatesRequest.Unmarshal(m, b)
+}
+func (m *CompareAndSwapStatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompareAndSwapStatesRequest.Marshal(b, m, deterministic)
+}
+func (m *CompareAndSwapStatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompareAndSwapStatesRequest.Merge(m, src)
+}
+func (m *CompareAndSwapStatesRequest) XXX_Size() int {
+	return xxx_messageInfo_CompareAndSwapStatesRequest.Size(m)
+}
+func (m *CompareAndSwapStatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompareAndSwapStatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompareAndSwapStatesRequest proto.InternalMessageInfo
+
+func (m *CompareAndSwapStatesRequest) GetNetworkID() string {
+	if m != nil {
+		return m.NetworkID
+	}
+	return ""
+}
+
+func (m *CompareAndSwapStatesRequest) GetStates() []*State {
+	if m != nil {
+		return m.States
+	}
+	return nil
+}
+
+func (m *CompareAndSwapStatesRequest) GetExpectedVersions() []*IDAndVersion {
+	if m != nil {
+		return m.ExpectedVersions
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*StateID)(nil), "magma.orc8r.StateID")
 	proto.RegisterType((*GetStatesRequest)(nil), "magma.orc8r.GetStatesRequest")
@@ -519,46 +620,56 @@ func init() {
 	proto.RegisterType((*SyncStatesRequest)(nil), "magma.orc8r.SyncStatesRequest")
 	proto.RegisterType((*IDAndVersion)(nil), "magma.orc8r.IDAndVersion")
 	proto.RegisterType((*SyncStatesResponse)(nil), "magma.orc8r.SyncStatesResponse")
+	proto.RegisterType((*CompareAndSwapStatesRequest)(nil), "magma.orc8r.CompareAndSwapStatesRequest")
 }
 
 func init() { proto.RegisterFile("orc8r/protos/state.proto", fileDescriptor_645e93724c8b4dfe) }
 
 var fileDescriptor_645e93724c8b4dfe = []byte{
-	// 537 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x54, 0xd1, 0x6f, 0x12, 0x4f,
-	0x10, 0x2e, 0xd0, 0x5f, 0x5b, 0x06, 0x7e, 0xa6, 0x4c, 0x49, 0x5c, 0xae, 0xb6, 0xe2, 0xc6, 0x18,
-	0xe2, 0x03, 0x68, 0x79, 0xd1, 0x27, 0x83, 0xd2, 0x1a, 0x12, 0xa3, 0x66, 0x89, 0x98, 0xd8, 0x07,
-	0x43, 0xd9, 0xb1, 0xd9, 0x08, 0xb7, 0xb8, 0x7b, 0x54, 0xf9, 0x1b, 0x7d, 0xf2, 0x3f, 0x32, 0xb7,
-	0x77, 0x3d, 0xee, 0x8e, 0xd2, 0xa4, 0x0f, 0x3e, 0xdd, 0xcd, 0x7c, 0xf3, 0x7d, 0xf7, 0xcd, 0xec,
-	0xdc, 0x02, 0xd3, 0x66, 0xf2, 0xc2, 0x74, 0xe6, 0x46, 0x07, 0xda, 0x76, 0x6c, 0x30, 0x0e, 0xa8,
-	0xed, 0x02, 0xac, 0xcc, 0xc6, 0x97, 0xb3, 0x71, 0xdb, 0xe1, 0x5e, 0x23, 0x53, 0x36, 0xd1, 0xb3,
-	0x99, 0xf6, 0xa3, 0x3a, 0xef, 0x28, 0xab, 0x40, 0xe6, 0x4a, 0x4d, 0xa8, 0xfb, 0xac, 0x1b, 0xc1,
-	0xfc, 0x25, 0xec, 0x0e, 0x43, 0xd5, 0x41, 0x1f, 0x11, 0xb6, 0x83, 0xe5, 0x9c, 0x58, 0xa1, 0x59,
-	0x68, 0x95, 0x85, 0x7b, 0x47, 0x0f, 0xf6, 0x24, 0x85, 0x8c, 0x41, 0x9f, 0x15, 0x5d, 0x3e, 0x89,
-	0xf9, 0x9f, 0x02, 0xec, 0xbf, 0xa5, 0xc0, 0xd1, 0xad, 0xa0, 0x1f, 0x0b, 0xb2, 0x01, 0x3e, 0x80,
-	0xb2, 0x4f, 0xc1, 0x4f, 0x6d, 0xbe, 0x0f, 0xfa, 0xb1, 0xd2, 0x2a, 0x81, 0x4f, 0xa0, 0xa4, 0xa4,
-	0x65, 0xc5, 0x66, 0xa9, 0x55, 0x39, 0xa9, 0xb7, 0x53, 0x2d, 0xb4, 0x63, 0x17, 0x22, 0x2c, 0xc0,
-	0x63, 0x80, 0xf0, 0xf3, 0x67, 0x6a, 0x1a, 0x90, 0x61, 0xd0, 0x2c, 0xb5, 0xca, 0x22, 0x95, 0x09,
-	0x6d, 0x29, 0x19, 0xa3, 0x15, 0x87, 0x26, 0x71, 0xc8, 0x9d, 0xea, 0xb1, 0x1c, 0x8d, 0xa7, 0x0b,
-	0xb2, 0xac, 0xda, 0x2c, 0xb4, 0xf6, 0x44, 0x2a, 0x83, 0x87, 0x50, 0x56, 0xf2, 0xeb, 0xdc, 0xd0,
-	0x37, 0xf5, 0x8b, 0xfd, 0x1f, 0xf5, 0xa4, 0xe4, 0x47, 0x17, 0xf3, 0x57, 0x50, 0x4b, 0xb5, 0x64,
-	0xe7, 0xda, 0xb7, 0x84, 0x4f, 0x61, 0xc7, 0x4d, 0xde, 0xb2, 0x82, 0x33, 0x8e, 0xeb, 0xc6, 0x45,
-	0x5c, 0xc1, 0x7b, 0x70, 0x20, 0x68, 0xae, 0x4d, 0x6e, 0x2c, 0x77, 0x91, 0x38, 0x87, 0x7a, 0x56,
-	0x22, 0xb6, 0xf1, 0x06, 0xf6, 0x17, 0xbe, 0x71, 0x08, 0xc9, 0x61, 0x5a, 0xed, 0x7e, 0x46, 0x6d,
-	0xd0, 0xef, 0xf9, 0xf2, 0xd4, 0x18, 0x6d, 0xc4, 0x1a, 0x81, 0x0b, 0x80, 0x15, 0x7e, 0xd7, 0x23,
-	0xc7, 0x3a, 0xfc, 0x47, 0x21, 0x91, 0x95, 0x1c, 0x10, 0x05, 0xfc, 0x1c, 0x0e, 0xfa, 0x34, 0xa5,
-	0x80, 0xfe, 0xc1, 0x2a, 0xf0, 0x33, 0xa8, 0x0d, 0x97, 0xfe, 0x24, 0x2b, 0xfd, 0x3c, 0x37, 0xce,
-	0xc6, 0xfa, 0x00, 0x46, 0x64, 0xac, 0xd2, 0x7e, 0x32, 0xd5, 0xf7, 0x50, 0x4d, 0xe7, 0xf1, 0x31,
-	0x14, 0x95, 0x74, 0xb6, 0x36, 0x7d, 0xbe, 0xa8, 0x24, 0x32, 0xd8, 0xbd, 0x8a, 0x08, 0x6e, 0x16,
-	0xdb, 0xe2, 0x3a, 0xe4, 0x9f, 0x01, 0xd3, 0xbe, 0xe2, 0x33, 0xea, 0xc1, 0xbd, 0x85, 0x6f, 0x97,
-	0xfe, 0x24, 0x77, 0x42, 0xb7, 0x18, 0xcc, 0x11, 0x4e, 0x7e, 0x17, 0xa1, 0xea, 0x5e, 0x87, 0xd1,
-	0xbf, 0x8a, 0xef, 0xa0, 0x9c, 0xec, 0x24, 0x1e, 0x65, 0x84, 0xf2, 0xbf, 0x9f, 0x77, 0xbc, 0x09,
-	0x8e, 0xfc, 0xf1, 0x2d, 0xfc, 0x04, 0xd5, 0xf4, 0x76, 0x61, 0x33, 0xc3, 0xb8, 0x61, 0x77, 0xbd,
-	0x47, 0xb7, 0x54, 0x24, 0xb2, 0xa7, 0x50, 0x4d, 0xef, 0x40, 0x4e, 0xf6, 0x86, 0xf5, 0xf0, 0x6a,
-	0x99, 0x8a, 0x91, 0x56, 0x92, 0x6f, 0xe1, 0x07, 0x80, 0xd5, 0x54, 0x31, 0xdb, 0xcd, 0xda, 0x1a,
-	0x78, 0x0f, 0x37, 0xe2, 0xd7, 0xbe, 0x5e, 0x1f, 0x7e, 0x69, 0xb8, 0x9a, 0x4e, 0x74, 0x0d, 0x4e,
-	0xd5, 0x45, 0xe7, 0x52, 0xc7, 0xb7, 0xe1, 0xc5, 0x8e, 0x7b, 0x76, 0xff, 0x06, 0x00, 0x00, 0xff,
-	0xff, 0x78, 0x62, 0x28, 0xdf, 0x66, 0x05, 0x00, 0x00,
+	// 682 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0x4f, 0x4f, 0xdb, 0x4e,
+	0x10, 0xc5, 0x09, 0x3f, 0x20, 0x43, 0x80, 0x30, 0x44, 0xfa, 0x2d, 0xa6, 0xd0, 0xd4, 0xaa, 0x50,
+	0xd4, 0x43, 0x42, 0xe1, 0xd2, 0x1e, 0x53, 0x02, 0x6d, 0xa4, 0xaa, 0xa5, 0x4e, 0xa1, 0x52, 0x39,
+	0x44, 0x26, 0x3b, 0x4d, 0x57, 0x24, 0x5e, 0x77, 0xed, 0xf0, 0xef, 0x43, 0xf5, 0x2b, 0xf4, 0x53,
+	0xf5, 0x5e, 0x79, 0x6d, 0x1c, 0x3b, 0x09, 0x20, 0xa4, 0xf6, 0x94, 0xcc, 0xbc, 0x99, 0xb7, 0xcf,
+	0x6f, 0x67, 0x6c, 0x60, 0x52, 0x75, 0x5f, 0xa9, 0xba, 0xa7, 0x64, 0x20, 0xfd, 0xba, 0x1f, 0x38,
+	0x01, 0xd5, 0x74, 0x80, 0x8b, 0x03, 0xa7, 0x37, 0x70, 0x6a, 0x1a, 0x37, 0xd7, 0x33, 0x65, 0x5d,
+	0x39, 0x18, 0x48, 0x37, 0xaa, 0x33, 0x37, 0xb3, 0x0c, 0xa4, 0x2e, 0x44, 0x97, 0xf6, 0x76, 0xf6,
+	0x22, 0xd8, 0x7a, 0x0d, 0xf3, 0xed, 0x90, 0xb5, 0xd5, 0x44, 0x84, 0xd9, 0xe0, 0xda, 0x23, 0x66,
+	0x54, 0x8c, 0x6a, 0xc1, 0xd6, 0xff, 0xd1, 0x84, 0x05, 0x4e, 0x61, 0x47, 0xab, 0xc9, 0x72, 0x3a,
+	0x9f, 0xc4, 0xd6, 0xaf, 0x1c, 0x94, 0xde, 0x52, 0xa0, 0xdb, 0x7d, 0x9b, 0x7e, 0x0c, 0xc9, 0x0f,
+	0xf0, 0x09, 0x14, 0x5c, 0x0a, 0x2e, 0xa5, 0x3a, 0x6f, 0x35, 0x63, 0xa6, 0x51, 0x02, 0xb7, 0x21,
+	0x2f, 0xb8, 0xcf, 0x72, 0x95, 0x7c, 0x75, 0x71, 0xb7, 0x5c, 0x4b, 0x3d, 0x42, 0x2d, 0x56, 0x61,
+	0x87, 0x05, 0xb8, 0x05, 0x10, 0x1e, 0x7f, 0x28, 0xfa, 0x01, 0x29, 0x06, 0x95, 0x7c, 0xb5, 0x60,
+	0xa7, 0x32, 0xa1, 0x2c, 0xc1, 0x63, 0x74, 0x51, 0xa3, 0x49, 0x1c, 0xf6, 0xf6, 0xa5, 0xc3, 0x4f,
+	0x9c, 0xfe, 0x90, 0x7c, 0x56, 0xac, 0x18, 0xd5, 0x05, 0x3b, 0x95, 0xc1, 0x0d, 0x28, 0x08, 0xde,
+	0xf1, 0x14, 0x7d, 0x13, 0x57, 0x6c, 0x29, 0x7a, 0x26, 0xc1, 0x8f, 0x74, 0x8c, 0x3b, 0x50, 0x56,
+	0xe4, 0x49, 0x15, 0x90, 0xea, 0x7c, 0x77, 0x14, 0xbf, 0x74, 0x14, 0x75, 0x04, 0x67, 0xcb, 0xba,
+	0x0e, 0x6f, 0xb1, 0x77, 0x31, 0xd4, 0xe2, 0x21, 0x9d, 0xe7, 0xf4, 0xa8, 0xe3, 0x8b, 0x1b, 0x62,
+	0x2b, 0x15, 0xa3, 0xba, 0x64, 0x2f, 0x84, 0x89, 0xb6, 0xb8, 0x21, 0xdc, 0x04, 0xd0, 0x60, 0x20,
+	0xcf, 0xc9, 0x65, 0xa5, 0xc8, 0x8e, 0x30, 0xf3, 0x39, 0x4c, 0x58, 0x3d, 0x58, 0x4d, 0x19, 0xe8,
+	0x7b, 0xd2, 0xf5, 0x09, 0x5f, 0xc0, 0x9c, 0xbe, 0x67, 0x9f, 0x19, 0xda, 0x26, 0x9c, 0xb4, 0xc9,
+	0x8e, 0x2b, 0x70, 0x1b, 0x56, 0x5c, 0xba, 0x0a, 0x3a, 0xa9, 0x43, 0xa2, 0x5b, 0x5a, 0x0a, 0xd3,
+	0x47, 0xc9, 0x41, 0x0d, 0x58, 0xb3, 0xb5, 0xf4, 0xec, 0x65, 0x3d, 0xe2, 0x28, 0xeb, 0x14, 0xca,
+	0x59, 0x8a, 0x58, 0xee, 0x3e, 0x94, 0x86, 0x6e, 0xec, 0x0b, 0x6f, 0xa7, 0xd9, 0xfe, 0xcf, 0xb0,
+	0xb5, 0x9a, 0x0d, 0x97, 0x1f, 0x28, 0x25, 0x95, 0x3d, 0xd1, 0x60, 0xd9, 0x00, 0x23, 0xfc, 0xb1,
+	0x83, 0x88, 0x65, 0xf8, 0x8f, 0xc2, 0x46, 0x96, 0xd7, 0x40, 0x14, 0x58, 0xa7, 0xb0, 0xd6, 0xa4,
+	0x3e, 0x05, 0xf4, 0x0f, 0x06, 0xd4, 0x3a, 0x84, 0xd5, 0xf6, 0xb5, 0xdb, 0xcd, 0x52, 0xbf, 0x1c,
+	0xb3, 0x73, 0x7d, 0xd2, 0x80, 0x13, 0x52, 0xbe, 0x90, 0x6e, 0xe2, 0xea, 0x07, 0x28, 0xa6, 0xf3,
+	0xf8, 0x1c, 0x72, 0x82, 0x6b, 0x59, 0x77, 0x1d, 0x9f, 0x13, 0x1c, 0x19, 0xcc, 0x5f, 0x44, 0x0d,
+	0xda, 0x8b, 0x59, 0xfb, 0x36, 0xb4, 0xbe, 0x00, 0xa6, 0x75, 0xc5, 0x77, 0xd4, 0x80, 0xe5, 0xa1,
+	0xeb, 0x5f, 0xbb, 0xdd, 0xb1, 0x1b, 0xba, 0x47, 0xe0, 0x58, 0x83, 0xf5, 0xd3, 0x80, 0x8d, 0x7d,
+	0x39, 0xf0, 0x1c, 0x45, 0x0d, 0x97, 0xb7, 0x2f, 0x1d, 0xef, 0x31, 0xb6, 0x8e, 0x06, 0x2d, 0xf7,
+	0xe0, 0x4c, 0x1f, 0x40, 0x89, 0xae, 0x3c, 0xea, 0x06, 0x74, 0x2b, 0xc6, 0x67, 0xf9, 0x87, 0xe4,
+	0x4e, 0xb4, 0xec, 0xfe, 0xce, 0x43, 0x51, 0x13, 0xb7, 0xa3, 0x57, 0x1e, 0xbe, 0x87, 0x42, 0xb2,
+	0x6c, 0xb8, 0x99, 0xa1, 0x1a, 0x7f, 0x8b, 0x99, 0x5b, 0x77, 0xc1, 0x91, 0xa1, 0xd6, 0x0c, 0x7e,
+	0x0a, 0xd9, 0x15, 0x39, 0x83, 0xbf, 0x44, 0xb8, 0x63, 0xe0, 0x31, 0x14, 0xd3, 0x1b, 0x86, 0x95,
+	0x4c, 0xcf, 0x94, 0xfd, 0x35, 0x9f, 0xdd, 0x53, 0x91, 0x28, 0x3d, 0x80, 0x62, 0x7a, 0x0f, 0xc6,
+	0x68, 0xa7, 0xac, 0x88, 0xb9, 0x9a, 0xa9, 0x38, 0x91, 0x82, 0x5b, 0x33, 0xf8, 0x11, 0x60, 0x34,
+	0x59, 0x98, 0x7d, 0x9e, 0x89, 0x55, 0x30, 0x9f, 0xde, 0x89, 0x27, 0xba, 0x8e, 0xa1, 0x3c, 0x6d,
+	0xa0, 0xb0, 0x9a, 0x69, 0xbd, 0x67, 0xe6, 0xa6, 0xea, 0x7c, 0xb3, 0xf1, 0x75, 0x5d, 0x67, 0xeb,
+	0xd1, 0x77, 0xaf, 0x2f, 0xce, 0xea, 0x3d, 0x19, 0x7f, 0xfe, 0xce, 0xe6, 0xf4, 0xef, 0xde, 0x9f,
+	0x01, 0x00, 0x54, 0x7e, 0x92, 0x12, 0x57, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -575,6 +686,11 @@ const _ = grpc.SupportPackageIsVersion6
 type StateServiceClient interface {
 	// GetStates retrieves states from blobstorage.
 	GetStates(ctx context.Context, in *GetStatesRequest, opts ...grpc.CallOption) (*GetStatesResponse, error)
+	// StreamStates retrieves states matching a search from blobstorage,
+	// streaming one page of states per response.
+	// The request's page_size sets the size of each page, and its page_token
+	// the point from which to start streaming.
+	StreamStates(ctx context.Context, in *GetStatesRequest, opts ...grpc.CallOption) (StateService_StreamStatesClient, error)
 	// ReportStates saves states into blobstorage.
 	ReportStates(ctx context.Context, in *ReportStatesRequest, opts ...grpc.CallOption) (*ReportStatesResponse, error)
 	// DeleteStates deletes states from blobstorage.
@@ -583,6 +699,11 @@ type StateServiceClient interface {
 	// to the states included in the request, and returns the IDAndVersions
 	// that differ.
 	SyncStates(ctx context.Context, in *SyncStatesRequest, opts ...grpc.CallOption) (*SyncStatesResponse, error)
+	// CompareAndSwapStates saves states into blobstorage only if the stored
+	// version of each state matches its expected version.
+	// On mismatch, nothing is written and an Aborted error is returned, with
+	// the stored IDAndVersion of each mismatched state attached as details.
+	CompareAndSwapStates(ctx context.Context, in *CompareAndSwapStatesRequest, opts ...grpc.CallOption) (*Void, error)
 }
 
 type stateServiceClient struct {
@@ -602,6 +723,38 @@ func (c *stateServiceClient) GetStates(ctx context.Context, in *GetStatesRequest
 	return out, nil
 }
 
+func (c *stateServiceClient) StreamStates(ctx context.Context, in *GetStatesRequest, opts ...grpc.CallOption) (StateService_StreamStatesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StateService_serviceDesc.Streams[0], "/magma.orc8r.StateService/StreamStates", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &stateServiceStreamStatesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StateService_StreamStatesClient interface {
+	Recv() (*GetStatesResponse, error)
+	grpc.ClientStream
+}
+
+type stateServiceStreamStatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *stateServiceStreamStatesClient) Recv() (*GetStatesResponse, error) {
+	m := new(GetStatesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *stateServiceClient) ReportStates(ctx context.Context, in *ReportStatesRequest, opts ...grpc.CallOption) (*ReportStatesResponse, error) {
 	out := new(ReportStatesResponse)
 	err := c.cc.Invoke(ctx, "/magma.orc8r.StateService/ReportStates", in, out, opts...)
@@ -629,10 +782,24 @@ func (c *stateServiceClient) SyncStates(ctx context.Context, in *SyncStatesReque
 	return out, nil
 }
 
+func (c *stateServiceClient) CompareAndSwapStates(ctx context.Context, in *CompareAndSwapStatesRequest, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/magma.orc8r.StateService/CompareAndSwapStates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StateServiceServer is the server API for StateService service.
 type StateServiceServer interface {
 	// GetStates retrieves states from blobstorage.
 	GetStates(context.Context, *GetStatesRequest) (*GetStatesResponse, error)
+	// StreamStates retrieves states matching a search from blobstorage,
+	// streaming one page of states per response.
+	// The request's page_size sets the size of each page, and its page_token
+	// the point from which to start streaming.
+	StreamStates(*GetStatesRequest, StateService_StreamStatesServer) error
 	// ReportStates saves states into blobstorage.
 	ReportStates(context.Context, *ReportStatesRequest) (*ReportStatesResponse, error)
 	// DeleteStates deletes states from blobstorage.
@@ -641,6 +808,11 @@ type StateServiceServer interface {
 	// to the states included in the request, and returns the IDAndVersions
 	// that differ.
 	SyncStates(context.Context, *SyncStatesRequest) (*SyncStatesResponse, error)
+	// CompareAndSwapStates saves states into blobstorage only if the stored
+	// version of each state matches its expected version.
+	// On mismatch, nothing is written and an Aborted error is returned, with
+	// the stored IDAndVersion of each mismatched state attached as details.
+	CompareAndSwapStates(context.Context, *CompareAndSwapStatesRequest) (*Void, error)
 }
 
 // UnimplementedStateServiceServer can be embedded to have forward compatible implementations.
@@ -650,6 +822,9 @@ type UnimplementedStateServiceServer struct {
 func (*UnimplementedStateServiceServer) GetStates(ctx context.Context, req *GetStatesRequest) (*GetStatesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStates not implemented")
 }
+func (*UnimplementedStateServiceServer) StreamStates(req *GetStatesRequest, srv StateService_StreamStatesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamStates not implemented")
+}
 func (*UnimplementedStateServiceServer) ReportStates(ctx context.Context, req *ReportStatesRequest) (*ReportStatesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReportStates not implemented")
 }
@@ -659,6 +834,9 @@ func (*UnimplementedStateServiceServer) DeleteStates(ctx context.Context, req *D
 func (*UnimplementedStateServiceServer) SyncStates(ctx context.Context, req *SyncStatesRequest) (*SyncStatesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SyncStates not implemented")
 }
+func (*UnimplementedStateServiceServer) CompareAndSwapStates(ctx context.Context, req *CompareAndSwapStatesRequest) (*Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareAndSwapStates not implemented")
+}
 
 func RegisterStateServiceServer(s *grpc.Server, srv StateServiceServer) {
 	s.RegisterService(&_StateService_serviceDesc, srv)
@@ -682,6 +860,27 @@ func _StateService_GetStates_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StateService_StreamStates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetStatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StateServiceServer).StreamStates(m, &stateServiceStreamStatesServer{stream})
+}
+
+type StateService_StreamStatesServer interface {
+	Send(*GetStatesResponse) error
+	grpc.ServerStream
+}
+
+type stateServiceStreamStatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *stateServiceStreamStatesServer) Send(m *GetStatesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _StateService_ReportStates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReportStatesRequest)
 	if err := dec(in); err != nil {
@@ -736,6 +935,24 @@ func _StateService_SyncStates_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StateService_CompareAndSwapStates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareAndSwapStatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateServiceServer).CompareAndSwapStates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/magma.orc8r.StateService/CompareAndSwapStates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServiceServer).CompareAndSwapStates(ctx, req.(*CompareAndSwapStatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _StateService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "magma.orc8r.StateService",
 	HandlerType: (*StateServiceServer)(nil),
@@ -756,7 +973,17 @@ var _StateService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SyncStates",
 			Handler:    _StateService_SyncStates_Handler,
 		},
+		{
+			MethodName: "CompareAndSwapStates",
+			Handler:    _StateService_CompareAndSwapStates_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamStates",
+			Handler:       _StateService_StreamStates_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "orc8r/protos/state.proto",
 }
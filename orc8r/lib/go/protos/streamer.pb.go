@@ -32,7 +32,10 @@ type StreamRequest struct {
 	StreamName string `protobuf:"bytes,2,opt,name=stream_name,json=streamName,proto3" json:"stream_name,omitempty"`
 	// extra_args contain any extra data to send up with the stream request.
 	// This value will be different per stream provider.
-	ExtraArgs            *any.Any `protobuf:"bytes,3,opt,name=extra_args,json=extraArgs,proto3" json:"extra_args,omitempty"`
+	ExtraArgs *any.Any `protobuf:"bytes,3,opt,name=extra_args,json=extraArgs,proto3" json:"extra_args,omitempty"`
+	// version of the stream's data last applied by the gateway, as received in
+	// a previous DataUpdateBatch. Empty requests a full snapshot.
+	Version              string   `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -84,12 +87,25 @@ func (m *StreamRequest) GetExtraArgs() *any.Any {
 	return nil
 }
 
+func (m *StreamRequest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
 type DataUpdateBatch struct {
 	// updates to config values
 	Updates []*DataUpdate `protobuf:"bytes,1,rep,name=updates,proto3" json:"updates,omitempty"`
 	// resync is true iff the updates would be a snapshot of all the contents
 	// in the cloud.
-	Resync               bool     `protobuf:"varint,2,opt,name=resync,proto3" json:"resync,omitempty"`
+	Resync bool `protobuf:"varint,2,opt,name=resync,proto3" json:"resync,omitempty"`
+	// version of the stream's data after applying this batch.
+	// Empty if the stream's provider doesn't support versioning.
+	Version string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// removed_keys are the keys removed since the version in the request.
+	// Only set when resync is false.
+	RemovedKeys          []string `protobuf:"bytes,4,rep,name=removed_keys,json=removedKeys,proto3" json:"removed_keys,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -134,6 +150,20 @@ func (m *DataUpdateBatch) GetResync() bool {
 	return false
 }
 
+func (m *DataUpdateBatch) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *DataUpdateBatch) GetRemovedKeys() []string {
+	if m != nil {
+		return m.RemovedKeys
+	}
+	return nil
+}
+
 type DataUpdate struct {
 	// key is the unique key for each item
 	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -193,27 +223,30 @@ func init() {
 func init() { proto.RegisterFile("orc8r/protos/streamer.proto", fileDescriptor_acdce76608ae0d01) }
 
 var fileDescriptor_acdce76608ae0d01 = []byte{
-	// 310 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0x4f, 0x4f, 0x02, 0x31,
-	0x10, 0xc5, 0x5d, 0x89, 0x08, 0xb3, 0x1a, 0x4d, 0x43, 0x74, 0xf9, 0x93, 0x48, 0xf6, 0xc4, 0xa9,
-	0xab, 0xe0, 0xc1, 0x2b, 0xc4, 0xc4, 0xe8, 0xc1, 0x43, 0x09, 0x1e, 0x8c, 0x09, 0x19, 0x60, 0xac,
-	0x46, 0x76, 0x8b, 0x6d, 0x57, 0xdd, 0xb3, 0x5f, 0xdc, 0xd8, 0x2e, 0x41, 0x0e, 0x9e, 0xda, 0x37,
-	0xfd, 0x35, 0xf3, 0xde, 0x0c, 0xb4, 0x95, 0x9e, 0x5f, 0xe9, 0x64, 0xa5, 0x95, 0x55, 0x26, 0x31,
-	0x56, 0x13, 0xa6, 0xa4, 0xb9, 0xd3, 0x2c, 0x4c, 0x51, 0xa6, 0xc8, 0x1d, 0xd2, 0x6a, 0x4a, 0xa5,
-	0xe4, 0x92, 0x3c, 0x3a, 0xcb, 0x9f, 0x13, 0xcc, 0x0a, 0xcf, 0xc5, 0xdf, 0x01, 0x1c, 0x8e, 0xdd,
-	0x57, 0x41, 0xef, 0x39, 0x19, 0xcb, 0x3a, 0x50, 0x97, 0x68, 0xe9, 0x13, 0x8b, 0xdb, 0x45, 0x14,
-	0x74, 0x83, 0x5e, 0x5d, 0x6c, 0x0a, 0xec, 0x0c, 0x42, 0xdf, 0x69, 0x9a, 0x61, 0x4a, 0xd1, 0xae,
-	0x7b, 0x07, 0x5f, 0xba, 0xc7, 0x94, 0xd8, 0x00, 0x80, 0xbe, 0xac, 0xc6, 0x29, 0x6a, 0x69, 0xa2,
-	0x4a, 0x37, 0xe8, 0x85, 0xfd, 0x06, 0xf7, 0x06, 0xf8, 0xda, 0x00, 0x1f, 0x66, 0x85, 0xa8, 0x3b,
-	0x6e, 0xa8, 0xa5, 0x89, 0x9f, 0xe0, 0xe8, 0x1a, 0x2d, 0x4e, 0x56, 0x0b, 0xb4, 0x34, 0x42, 0x3b,
-	0x7f, 0x61, 0x17, 0xb0, 0x9f, 0x3b, 0x69, 0xa2, 0xa0, 0x5b, 0xe9, 0x85, 0xfd, 0x53, 0xfe, 0x27,
-	0x12, 0xdf, 0xe0, 0x62, 0xcd, 0xb1, 0x13, 0xa8, 0x6a, 0x32, 0x45, 0x36, 0x77, 0xb6, 0x6a, 0xa2,
-	0x54, 0xf1, 0x25, 0xc0, 0x06, 0x67, 0xc7, 0x50, 0x79, 0xa3, 0xa2, 0x4c, 0xf6, 0x7b, 0x65, 0x0d,
-	0xd8, 0xfb, 0xc0, 0x65, 0xee, 0xd3, 0x1c, 0x08, 0x2f, 0xfa, 0x0f, 0x50, 0x1b, 0x97, 0x33, 0x65,
-	0x77, 0x00, 0x37, 0x64, 0x27, 0x65, 0x9f, 0xd6, 0x96, 0x93, 0xad, 0xe9, 0xb5, 0x3a, 0xff, 0xb8,
-	0x74, 0xa1, 0xe2, 0x9d, 0xf3, 0x60, 0xd4, 0x7e, 0x6c, 0x3a, 0x24, 0xf1, 0xeb, 0x5b, 0xbe, 0xce,
-	0x12, 0xa9, 0xca, 0x2d, 0xce, 0xaa, 0xee, 0x1c, 0xfc, 0x04, 0x00, 0x00, 0xff, 0xff, 0x98, 0x86,
-	0xe8, 0x16, 0xdc, 0x01, 0x00, 0x00,
+	// 357 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0x4f, 0x6f, 0xda, 0x40,
+	0x10, 0xc5, 0xeb, 0x9a, 0x02, 0x1e, 0x53, 0xb5, 0x5a, 0xa1, 0xd6, 0xfc, 0x91, 0xea, 0xfa, 0xe4,
+	0x93, 0xdd, 0x42, 0x0e, 0xb9, 0x82, 0x22, 0x45, 0x49, 0xa4, 0x1c, 0x8c, 0xc8, 0x21, 0x17, 0x34,
+	0xc0, 0xc4, 0x41, 0x60, 0x2f, 0xd9, 0x5d, 0x93, 0xf8, 0xa3, 0xe4, 0x90, 0xef, 0x1a, 0x65, 0xd7,
+	0x08, 0x38, 0xe4, 0x64, 0xbf, 0xf1, 0xcf, 0xfb, 0xde, 0xcc, 0x0e, 0xf4, 0xb8, 0x58, 0x9c, 0x8b,
+	0x78, 0x2b, 0xb8, 0xe2, 0x32, 0x96, 0x4a, 0x10, 0x66, 0x24, 0x22, 0xad, 0x99, 0x9b, 0x61, 0x9a,
+	0x61, 0xa4, 0x91, 0x6e, 0x27, 0xe5, 0x3c, 0xdd, 0x90, 0x41, 0xe7, 0xc5, 0x43, 0x8c, 0x79, 0x69,
+	0xb8, 0xe0, 0xcd, 0x82, 0xef, 0x13, 0xfd, 0x6b, 0x42, 0x4f, 0x05, 0x49, 0xc5, 0xfa, 0xe0, 0xa4,
+	0xa8, 0xe8, 0x19, 0xcb, 0xab, 0xa5, 0x67, 0xf9, 0x56, 0xe8, 0x24, 0x87, 0x02, 0xfb, 0x03, 0xae,
+	0x71, 0x9a, 0xe5, 0x98, 0x91, 0xf7, 0x55, 0x7f, 0x07, 0x53, 0xba, 0xc5, 0x8c, 0xd8, 0x10, 0x80,
+	0x5e, 0x94, 0xc0, 0x19, 0x8a, 0x54, 0x7a, 0xb6, 0x6f, 0x85, 0xee, 0xa0, 0x1d, 0x99, 0x00, 0xd1,
+	0x3e, 0x40, 0x34, 0xca, 0xcb, 0xc4, 0xd1, 0xdc, 0x48, 0xa4, 0x92, 0x79, 0xd0, 0xd8, 0x91, 0x90,
+	0x2b, 0x9e, 0x7b, 0x35, 0x7d, 0xe2, 0x5e, 0x06, 0xaf, 0x16, 0xfc, 0xb8, 0x40, 0x85, 0xd3, 0xed,
+	0x12, 0x15, 0x8d, 0x51, 0x2d, 0x1e, 0xd9, 0x7f, 0x68, 0x14, 0x5a, 0x4a, 0xcf, 0xf2, 0xed, 0xd0,
+	0x1d, 0xfc, 0x8e, 0x8e, 0xba, 0x8d, 0x0e, 0x78, 0xb2, 0xe7, 0xd8, 0x2f, 0xa8, 0x0b, 0x92, 0x65,
+	0xbe, 0xd0, 0x89, 0x9b, 0x49, 0xa5, 0x8e, 0x8d, 0xed, 0x13, 0x63, 0xf6, 0x17, 0x5a, 0x82, 0x32,
+	0xbe, 0xa3, 0xe5, 0x6c, 0x4d, 0xa5, 0xf4, 0x6a, 0xbe, 0x1d, 0x3a, 0x89, 0x5b, 0xd5, 0x6e, 0xa8,
+	0x94, 0xc1, 0x19, 0xc0, 0xc1, 0x8b, 0xfd, 0x04, 0x7b, 0x4d, 0x65, 0x35, 0xb1, 0x8f, 0x57, 0xd6,
+	0x86, 0x6f, 0x3b, 0xdc, 0x14, 0x66, 0x4a, 0xad, 0xc4, 0x88, 0xc1, 0x1d, 0x34, 0x27, 0xd5, 0x5d,
+	0xb1, 0x6b, 0x80, 0x4b, 0x52, 0xd3, 0x2a, 0x64, 0xf7, 0xa4, 0x8d, 0x93, 0x5b, 0xe9, 0xf6, 0x3f,
+	0x69, 0x51, 0x4f, 0x24, 0xf8, 0xf2, 0xcf, 0x1a, 0xf7, 0xee, 0x3b, 0x1a, 0x89, 0xcd, 0x5a, 0x6c,
+	0x56, 0xf3, 0x38, 0xe5, 0xd5, 0x76, 0xcc, 0xeb, 0xfa, 0x39, 0x7c, 0x1f, 0x00, 0x86, 0xac, 0x75,
+	0x5b, 0x34, 0x02, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -64,11 +64,13 @@ func (m *GetTenantRequest) GetId() int64 {
 }
 
 type Tenant struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Networks             []string `protobuf:"bytes,2,rep,name=networks,proto3" json:"networks,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Name     string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Networks []string `protobuf:"bytes,2,rep,name=networks,proto3" json:"networks,omitempty"`
+	// Limits on the tenant's resources. Nil if unlimited.
+	Quotas               *TenantQuotas `protobuf:"bytes,3,opt,name=quotas,proto3" json:"quotas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *Tenant) Reset()         { *m = Tenant{} }
@@ -110,6 +112,92 @@ func (m *Tenant) GetNetworks() []string {
 	return nil
 }
 
+func (m *Tenant) GetQuotas() *TenantQuotas {
+	if m != nil {
+		return m.Quotas
+	}
+	return nil
+}
+
+// TenantQuotas limits the resources of a tenant. Zero values are unlimited.
+type TenantQuotas struct {
+	// Number of networks of the tenant
+	MaxNetworks uint32 `protobuf:"varint,1,opt,name=max_networks,json=maxNetworks,proto3" json:"max_networks,omitempty"`
+	// Number of gateways across the tenant's networks
+	MaxGateways uint32 `protobuf:"varint,2,opt,name=max_gateways,json=maxGateways,proto3" json:"max_gateways,omitempty"`
+	// Number of subscribers across the tenant's networks
+	MaxSubscribers uint32 `protobuf:"varint,3,opt,name=max_subscribers,json=maxSubscribers,proto3" json:"max_subscribers,omitempty"`
+	// Number of policy rules across the tenant's networks
+	MaxPolicyRules uint32 `protobuf:"varint,4,opt,name=max_policy_rules,json=maxPolicyRules,proto3" json:"max_policy_rules,omitempty"`
+	// Sustained rate of REST API requests to the tenant's networks, across
+	// its operators. Each REST API server replica enforces the limit
+	// separately.
+	MaxRequestsPerSecond uint32   `protobuf:"varint,5,opt,name=max_requests_per_second,json=maxRequestsPerSecond,proto3" json:"max_requests_per_second,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TenantQuotas) Reset()         { *m = TenantQuotas{} }
+func (m *TenantQuotas) String() string { return proto.CompactTextString(m) }
+func (*TenantQuotas) ProtoMessage()    {}
+func (*TenantQuotas) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e7fa0ed566a19fde, []int{2}
+}
+
+func (m *TenantQuotas) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TenantQuotas.Unmarshal(m, b)
+}
+func (m *TenantQuotas) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TenantQuotas.Marshal(b, m, deterministic)
+}
+func (m *TenantQuotas) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantQuotas.Merge(m, src)
+}
+func (m *TenantQuotas) XXX_Size() int {
+	return xxx_messageInfo_TenantQuotas.Size(m)
+}
+func (m *TenantQuotas) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantQuotas.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantQuotas proto.InternalMessageInfo
+
+func (m *TenantQuotas) GetMaxNetworks() uint32 {
+	if m != nil {
+		return m.MaxNetworks
+	}
+	return 0
+}
+
+func (m *TenantQuotas) GetMaxGateways() uint32 {
+	if m != nil {
+		return m.MaxGateways
+	}
+	return 0
+}
+
+func (m *TenantQuotas) GetMaxSubscribers() uint32 {
+	if m != nil {
+		return m.MaxSubscribers
+	}
+	return 0
+}
+
+func (m *TenantQuotas) GetMaxPolicyRules() uint32 {
+	if m != nil {
+		return m.MaxPolicyRules
+	}
+	return 0
+}
+
+func (m *TenantQuotas) GetMaxRequestsPerSecond() uint32 {
+	if m != nil {
+		return m.MaxRequestsPerSecond
+	}
+	return 0
+}
+
 type TenantList struct {
 	Tenants              []*IDAndTenant `protobuf:"bytes,1,rep,name=tenants,proto3" json:"tenants,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
@@ -121,7 +209,7 @@ func (m *TenantList) Reset()         { *m = TenantList{} }
 func (m *TenantList) String() string { return proto.CompactTextString(m) }
 func (*TenantList) ProtoMessage()    {}
 func (*TenantList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e7fa0ed566a19fde, []int{2}
+	return fileDescriptor_e7fa0ed566a19fde, []int{3}
 }
 
 func (m *TenantList) XXX_Unmarshal(b []byte) error {
@@ -161,7 +249,7 @@ func (m *IDAndTenant) Reset()         { *m = IDAndTenant{} }
 func (m *IDAndTenant) String() string { return proto.CompactTextString(m) }
 func (*IDAndTenant) ProtoMessage()    {}
 func (*IDAndTenant) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e7fa0ed566a19fde, []int{3}
+	return fileDescriptor_e7fa0ed566a19fde, []int{4}
 }
 
 func (m *IDAndTenant) XXX_Unmarshal(b []byte) error {
@@ -199,6 +287,7 @@ func (m *IDAndTenant) GetTenant() *Tenant {
 func init() {
 	proto.RegisterType((*GetTenantRequest)(nil), "magma.orc8r.GetTenantRequest")
 	proto.RegisterType((*Tenant)(nil), "magma.orc8r.Tenant")
+	proto.RegisterType((*TenantQuotas)(nil), "magma.orc8r.TenantQuotas")
 	proto.RegisterType((*TenantList)(nil), "magma.orc8r.TenantList")
 	proto.RegisterType((*IDAndTenant)(nil), "magma.orc8r.IDAndTenant")
 }
@@ -206,28 +295,37 @@ func init() {
 func init() { proto.RegisterFile("orc8r/protos/tenants.proto", fileDescriptor_e7fa0ed566a19fde) }
 
 var fileDescriptor_e7fa0ed566a19fde = []byte{
-	// 328 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x92, 0x41, 0x4b, 0xfb, 0x40,
-	0x10, 0xc5, 0x9b, 0xf4, 0x4f, 0xff, 0x66, 0x52, 0x8b, 0x8e, 0x07, 0xd3, 0x88, 0x10, 0xf6, 0x14,
-	0x10, 0x12, 0x88, 0x97, 0x22, 0x14, 0x6c, 0x2d, 0x14, 0xc5, 0x53, 0x2a, 0x1e, 0xbc, 0xa5, 0xcd,
-	0x50, 0x82, 0x49, 0x56, 0x93, 0x55, 0x3f, 0xa7, 0xdf, 0x48, 0xdc, 0x5d, 0x43, 0x53, 0x2b, 0xe2,
-	0x29, 0x99, 0x97, 0xdf, 0xbc, 0xbc, 0x19, 0x06, 0x5c, 0x5e, 0xad, 0x46, 0x55, 0xf8, 0x54, 0x71,
-	0xc1, 0xeb, 0x50, 0x50, 0x99, 0x94, 0xa2, 0x0e, 0x64, 0x89, 0x76, 0x91, 0xac, 0x8b, 0x24, 0x90,
-	0x84, 0x3b, 0x6c, 0x81, 0x2b, 0x5e, 0x14, 0xbc, 0x54, 0x1c, 0x63, 0x70, 0x30, 0x27, 0x71, 0x27,
-	0x7b, 0x63, 0x7a, 0x7e, 0xa1, 0x5a, 0xe0, 0x00, 0xcc, 0x2c, 0x75, 0x0c, 0xcf, 0xf0, 0xbb, 0xb1,
-	0x99, 0xa5, 0x6c, 0x04, 0x3d, 0x05, 0x20, 0xc2, 0xbf, 0x32, 0x29, 0x48, 0x7e, 0xb3, 0x62, 0xf9,
-	0x8e, 0x2e, 0xec, 0x95, 0x24, 0xde, 0x78, 0xf5, 0x58, 0x3b, 0xa6, 0xd7, 0xf5, 0xad, 0xb8, 0xa9,
-	0xd9, 0x25, 0x80, 0xea, 0xbc, 0xcd, 0x6a, 0x81, 0x11, 0xfc, 0xd7, 0x21, 0x1d, 0xc3, 0xeb, 0xfa,
-	0x76, 0xe4, 0x04, 0x1b, 0x29, 0x83, 0xeb, 0xd9, 0xa4, 0x4c, 0x75, 0x92, 0x2f, 0x90, 0xdd, 0x80,
-	0xbd, 0xa1, 0x6f, 0x47, 0xc3, 0x33, 0xe8, 0x29, 0xd2, 0x31, 0x3d, 0xc3, 0xb7, 0xa3, 0xa3, 0x96,
-	0xa3, 0x36, 0xd3, 0x48, 0xf4, 0x6e, 0xc2, 0x40, 0x49, 0xf5, 0x82, 0xaa, 0xd7, 0x6c, 0x45, 0x38,
-	0x86, 0xfd, 0x39, 0x89, 0x49, 0x9e, 0x6b, 0x1d, 0x0f, 0x5b, 0x06, 0xf7, 0x3c, 0x4b, 0xdd, 0xe3,
-	0x1d, 0x9e, 0x9f, 0xf3, 0xb0, 0x0e, 0x4e, 0xc0, 0x6a, 0xb6, 0x87, 0xa7, 0x2d, 0x6e, 0x7b, 0xab,
-	0xee, 0xae, 0x68, 0xac, 0x83, 0x63, 0xe8, 0x5f, 0x55, 0x94, 0x08, 0xd2, 0x2e, 0x3f, 0xee, 0xc4,
-	0xfd, 0x1e, 0x8d, 0x75, 0xf0, 0x02, 0xac, 0x45, 0x93, 0xe0, 0x8f, 0xbd, 0x53, 0xe8, 0xcf, 0x28,
-	0xa7, 0xe6, 0xd7, 0xbf, 0x0c, 0xb0, 0xcb, 0x63, 0x7a, 0xf2, 0x30, 0x94, 0x6a, 0xa8, 0x4e, 0x2c,
-	0xcf, 0x96, 0xe1, 0x9a, 0xeb, 0x4b, 0x5b, 0xf6, 0xe4, 0xf3, 0xfc, 0x23, 0x00, 0x00, 0xff, 0xff,
-	0x06, 0x90, 0xe8, 0xaa, 0xa9, 0x02, 0x00, 0x00,
+	// 467 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0xdd, 0x8a, 0xd3, 0x40,
+	0x14, 0xc7, 0xfb, 0xb1, 0x56, 0x7b, 0xd2, 0xd6, 0xf5, 0x28, 0x6c, 0x1a, 0x11, 0x6a, 0x6e, 0x0c,
+	0x08, 0x2d, 0x46, 0x04, 0x11, 0x16, 0xec, 0xba, 0x50, 0x14, 0x91, 0x35, 0x15, 0x2f, 0xbc, 0x29,
+	0xd3, 0xe4, 0x50, 0xc2, 0x66, 0x32, 0xdd, 0x99, 0xa9, 0xdb, 0x7d, 0x4c, 0x5f, 0xc0, 0x67, 0x91,
+	0xcc, 0x4c, 0x6b, 0xbb, 0x56, 0x10, 0xaf, 0x92, 0xf9, 0xcf, 0x6f, 0xce, 0xc7, 0xff, 0xcc, 0x40,
+	0x20, 0x64, 0xfa, 0x5a, 0x8e, 0x96, 0x52, 0x68, 0xa1, 0x46, 0x9a, 0x4a, 0x56, 0x6a, 0x35, 0x34,
+	0x4b, 0xf4, 0x38, 0x5b, 0x70, 0x36, 0x34, 0x44, 0xd0, 0xdf, 0x03, 0x53, 0xc1, 0xb9, 0x28, 0x2d,
+	0x17, 0x86, 0x70, 0x3c, 0x21, 0xfd, 0xc5, 0x9c, 0x4d, 0xe8, 0x6a, 0x45, 0x4a, 0x63, 0x0f, 0x1a,
+	0x79, 0xe6, 0xd7, 0x07, 0xf5, 0xa8, 0x99, 0x34, 0xf2, 0x2c, 0xbc, 0x84, 0x96, 0x05, 0x10, 0xe1,
+	0xa8, 0x64, 0x9c, 0xcc, 0x5e, 0x3b, 0x31, 0xff, 0x18, 0xc0, 0xbd, 0x92, 0xf4, 0xb5, 0x90, 0x97,
+	0xca, 0x6f, 0x0c, 0x9a, 0x51, 0x3b, 0xd9, 0xae, 0xf1, 0x05, 0xb4, 0xae, 0x56, 0x42, 0x33, 0xe5,
+	0x37, 0x07, 0xf5, 0xc8, 0x8b, 0xfb, 0xc3, 0x9d, 0xb2, 0x86, 0x36, 0xe8, 0x67, 0x03, 0x24, 0x0e,
+	0x0c, 0x7f, 0xd6, 0xa1, 0xb3, 0xbb, 0x81, 0x4f, 0xa1, 0xc3, 0xd9, 0x7a, 0xb6, 0xcd, 0x51, 0xe5,
+	0xee, 0x26, 0x1e, 0x67, 0xeb, 0x4f, 0x9b, 0x34, 0x0e, 0x59, 0x30, 0x4d, 0xd7, 0xec, 0xa6, 0x2a,
+	0x63, 0x83, 0x4c, 0x9c, 0x84, 0xcf, 0xe0, 0x7e, 0x85, 0xa8, 0xd5, 0x5c, 0xa5, 0x32, 0x9f, 0x93,
+	0xb4, 0x25, 0x75, 0x93, 0x1e, 0x67, 0xeb, 0xe9, 0x6f, 0x15, 0x23, 0x38, 0xae, 0xc0, 0xa5, 0x28,
+	0xf2, 0xf4, 0x66, 0x26, 0x57, 0x05, 0x29, 0xff, 0x68, 0x4b, 0x5e, 0x18, 0x39, 0xa9, 0x54, 0x7c,
+	0x05, 0x27, 0x15, 0x29, 0xad, 0x6b, 0x6a, 0xb6, 0x24, 0x39, 0x53, 0x94, 0x8a, 0x32, 0xf3, 0xef,
+	0x98, 0x03, 0x8f, 0x38, 0x5b, 0x3b, 0x4f, 0xd5, 0x05, 0xc9, 0xa9, 0xd9, 0x0b, 0xdf, 0x02, 0xd8,
+	0xfe, 0x3e, 0xe6, 0x4a, 0x63, 0x0c, 0x77, 0xdd, 0xe0, 0xfc, 0xfa, 0xa0, 0x19, 0x79, 0xb1, 0xbf,
+	0x67, 0xd1, 0xfb, 0xf3, 0x71, 0x99, 0xb9, 0xe9, 0x6c, 0xc0, 0xf0, 0x03, 0x78, 0x3b, 0xfa, 0xed,
+	0x71, 0xe1, 0x73, 0x68, 0x59, 0xd2, 0xf8, 0xe0, 0xc5, 0x0f, 0x0f, 0x98, 0x9e, 0x38, 0x24, 0xfe,
+	0xd1, 0x80, 0x9e, 0x95, 0xd4, 0x94, 0xe4, 0xf7, 0x3c, 0x25, 0x3c, 0x85, 0xee, 0x84, 0xf4, 0xb8,
+	0x28, 0x9c, 0x8e, 0x0f, 0xf6, 0x02, 0x7c, 0x15, 0x79, 0x16, 0x9c, 0x1c, 0x88, 0x59, 0xf5, 0x13,
+	0xd6, 0x70, 0x0c, 0xed, 0xed, 0x8d, 0xc2, 0x27, 0x7b, 0xdc, 0xed, 0x9b, 0x16, 0x1c, 0x2a, 0x2d,
+	0xac, 0xe1, 0x29, 0x74, 0xde, 0x49, 0x62, 0x9a, 0x5c, 0x94, 0xbf, 0x7a, 0x12, 0xfc, 0x59, 0x5a,
+	0x58, 0xc3, 0x37, 0xd0, 0x9e, 0x92, 0xfe, 0xbf, 0xb3, 0x67, 0xd0, 0x39, 0xa7, 0x82, 0x34, 0xfd,
+	0x5b, 0x03, 0x87, 0x62, 0x9c, 0x3d, 0xfe, 0xd6, 0x37, 0xea, 0xc8, 0x3e, 0xbb, 0x22, 0x9f, 0x8f,
+	0x16, 0xc2, 0xbd, 0xbe, 0x79, 0xcb, 0x7c, 0x5f, 0xfe, 0x1a, 0x00, 0x63, 0x36, 0x55, 0x28, 0xbd,
+	0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpm
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// credentialSymKeyBits is the symmetric key size of the default RSA
+	// endorsement key template, per the TCG EK Credential Profile.
+	credentialSymKeyBits = 128
+
+	labelIdentity  = "IDENTITY"
+	labelStorage   = "STORAGE"
+	labelIntegrity = "INTEGRITY"
+)
+
+// MakeCredential protects a secret such that only the TPM holding both the
+// passed endorsement key and the attestation key of the passed name can
+// recover it, via TPM2_ActivateCredential.
+// Returns the credential blob (TPM2B_ID_OBJECT contents) and encrypted seed
+// (TPM2B_ENCRYPTED_SECRET contents).
+//
+// The endorsement key must follow the default RSA template, i.e. use SHA256
+// and AES-128-CFB.
+func MakeCredential(ek *rsa.PublicKey, akName, secret []byte) ([]byte, []byte, error) {
+	if len(secret) > sha256.Size {
+		return nil, nil, fmt.Errorf("secret of %d bytes exceeds max of %d", len(secret), sha256.Size)
+	}
+	seed := make([]byte, sha256.Size)
+	_, err := rand.Read(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	encryptedSeed, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, ek, seed, nullTerminated(labelIdentity))
+	if err != nil {
+		return nil, nil, fmt.Errorf("encrypt seed: %v", err)
+	}
+
+	plaintext := &bytes.Buffer{}
+	writeTPM2B(plaintext, secret)
+	encIdentity, err := cfb(seed, akName, plaintext.Bytes(), false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credentialBlob := &bytes.Buffer{}
+	writeTPM2B(credentialBlob, integrityHMAC(seed, akName, encIdentity))
+	credentialBlob.Write(encIdentity)
+	return credentialBlob.Bytes(), encryptedSeed, nil
+}
+
+// ActivateCredential recovers the secret protected by MakeCredential, as
+// TPM2_ActivateCredential would within the TPM holding the endorsement key.
+func ActivateCredential(ek *rsa.PrivateKey, akName, credentialBlob, encryptedSeed []byte) ([]byte, error) {
+	seed, err := rsa.DecryptOAEP(sha256.New(), nil, ek, encryptedSeed, nullTerminated(labelIdentity))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt seed: %v", err)
+	}
+
+	r := &reader{r: bytes.NewReader(credentialBlob)}
+	outerHMAC := r.tpm2b()
+	if r.err != nil {
+		return nil, fmt.Errorf("malformed credential blob: %v", r.err)
+	}
+	encIdentity := credentialBlob[len(credentialBlob)-r.r.Len():]
+	if !hmac.Equal(outerHMAC, integrityHMAC(seed, akName, encIdentity)) {
+		return nil, fmt.Errorf("credential integrity check failed")
+	}
+
+	plaintext, err := cfb(seed, akName, encIdentity, true)
+	if err != nil {
+		return nil, err
+	}
+	r = &reader{r: bytes.NewReader(plaintext)}
+	secret := r.tpm2b()
+	if r.err != nil {
+		return nil, fmt.Errorf("malformed credential: %v", r.err)
+	}
+	return secret, nil
+}
+
+func integrityHMAC(seed, akName, encIdentity []byte) []byte {
+	hmacKey := kdfa(seed, labelIntegrity, nil, nil, sha256.Size*8)
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write(encIdentity)
+	mac.Write(akName)
+	return mac.Sum(nil)
+}
+
+// cfb encrypts or decrypts the credential with AES-CFB, under a key derived
+// from the seed and attestation key name, and a zero IV.
+func cfb(seed, akName, in []byte, decrypt bool) ([]byte, error) {
+	symKey := kdfa(seed, labelStorage, akName, nil, credentialSymKeyBits)
+	block, err := aes.NewCipher(symKey)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, block.BlockSize())
+	out := make([]byte, len(in))
+	if decrypt {
+		cipher.NewCFBDecrypter(block, iv).XORKeyStream(out, in)
+	} else {
+		cipher.NewCFBEncrypter(block, iv).XORKeyStream(out, in)
+	}
+	return out, nil
+}
+
+// kdfa is the SP800-108 counter mode KDF, with HMAC-SHA256, per TPM 2.0
+// part 1, section 11.4.10.2.
+func kdfa(key []byte, label string, contextU, contextV []byte, bits int) []byte {
+	var out []byte
+	for i := uint32(1); len(out)*8 < bits; i++ {
+		mac := hmac.New(sha256.New, key)
+		binary.Write(mac, binary.BigEndian, i)
+		mac.Write(nullTerminated(label))
+		mac.Write(contextU)
+		mac.Write(contextV)
+		binary.Write(mac, binary.BigEndian, uint32(bits))
+		out = mac.Sum(out)
+	}
+	return out[:(bits+7)/8]
+}
+
+func nullTerminated(label string) []byte {
+	return append([]byte(label), 0)
+}
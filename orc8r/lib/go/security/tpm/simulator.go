@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpm
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Simulator is a software TPM, holding an RSA endorsement key certified by
+// a simulated manufacturer CA, and an ECDSA attestation key.
+// It offers none of the security of a hardware TPM, and is intended for
+// testing attestation flows only.
+type Simulator struct {
+	ek       *rsa.PrivateKey
+	ekCert   []byte
+	ak       *ecdsa.PrivateKey
+	akPublic *Public
+
+	mu    sync.Mutex
+	clock uint64
+}
+
+// NewSimulator returns a simulated TPM whose endorsement key is certified
+// by the passed manufacturer CA.
+func NewSimulator(manufacturerCert *x509.Certificate, manufacturerKey crypto.Signer) (*Simulator, error) {
+	ek, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "Simulated TPM EK"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment,
+	}
+	ekCert, err := x509.CreateCertificate(rand.Reader, template, manufacturerCert, &ek.PublicKey, manufacturerKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ak, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	akPublic := &Public{
+		Type:       AlgECC,
+		NameAlg:    AlgSHA256,
+		Attributes: AttestationKeyAttrs | AttrUserWithAuth,
+		Symmetric:  AlgNull,
+		Scheme:     AlgECDSA,
+		SchemeHash: AlgSHA256,
+		KDF:        AlgNull,
+		Key:        &ak.PublicKey,
+	}
+	return &Simulator{ek: ek, ekCert: ekCert, ak: ak, akPublic: akPublic}, nil
+}
+
+// NewSimulatedManufacturerCA returns a self-signed CA certificate and key
+// with which to certify simulated endorsement keys.
+func NewSimulatedManufacturerCA() (*x509.Certificate, crypto.Signer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Simulated TPM Manufacturer CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// EndorsementCertificate returns the DER-encoded endorsement key
+// certificate.
+func (s *Simulator) EndorsementCertificate() []byte {
+	return s.ekCert
+}
+
+// AttestationKeyPublic returns the TPMT_PUBLIC of the attestation key.
+func (s *Simulator) AttestationKeyPublic() ([]byte, error) {
+	return s.akPublic.Encode()
+}
+
+// ActivateCredential recovers the secret of a credential made for this TPM's
+// endorsement and attestation keys.
+func (s *Simulator) ActivateCredential(credentialBlob, encryptedSecret []byte) ([]byte, error) {
+	name, err := s.akPublic.Name()
+	if err != nil {
+		return nil, err
+	}
+	return ActivateCredential(s.ek, name, credentialBlob, encryptedSecret)
+}
+
+// Quote returns a quote over PCR 0, qualified by the passed data, and its
+// signature by the attestation key, as a TPMS_ATTEST and TPMT_SIGNATURE.
+func (s *Simulator) Quote(qualifyingData []byte) ([]byte, []byte, error) {
+	name, err := s.akPublic.Name()
+	if err != nil {
+		return nil, nil, err
+	}
+	s.mu.Lock()
+	s.clock++
+	clock := s.clock
+	s.mu.Unlock()
+
+	pcr := make([]byte, sha256.Size)
+	pcrDigest := sha256.Sum256(pcr)
+	attest := (&Attest{
+		Magic:           GeneratedValue,
+		Type:            STAttestQuote,
+		QualifiedSigner: name,
+		ExtraData:       qualifyingData,
+		Clock:           clock,
+		Safe:            true,
+		PCRSelections:   []PCRSelection{{Hash: AlgSHA256, Select: []byte{0x01, 0x00, 0x00}}},
+		PCRDigest:       pcrDigest[:],
+	}).Encode()
+
+	digest := sha256.Sum256(attest)
+	r, ss, err := ecdsa.Sign(rand.Reader, s.ak, digest[:])
+	if err != nil {
+		return nil, nil, err
+	}
+	sig := &Signature{Alg: AlgECDSA, Hash: AlgSHA256, R: r, S: ss}
+	return attest, sig.Encode(), nil
+}
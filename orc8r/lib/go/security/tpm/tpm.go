@@ -0,0 +1,506 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tpm implements the subset of the TPM 2.0 wire format and
+// cryptography needed to verify TPM attestation during gateway bootstrap:
+// attestation key public areas, quotes, signatures, and credential
+// activation, per the TPM 2.0 Library specification, part 2.
+package tpm
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// Algorithm IDs, per TPM 2.0 part 2, section 6.3.
+const (
+	AlgRSA    uint16 = 0x0001
+	AlgSHA256 uint16 = 0x000B
+	AlgSHA384 uint16 = 0x000C
+	AlgNull   uint16 = 0x0010
+	AlgRSASSA uint16 = 0x0014
+	AlgECDSA  uint16 = 0x0018
+	AlgECC    uint16 = 0x0023
+	AlgAES    uint16 = 0x0006
+	AlgCFB    uint16 = 0x0043
+)
+
+// ECC curve IDs, per TPM 2.0 part 2, section 6.4.
+const (
+	CurveNISTP256 uint16 = 0x0003
+	CurveNISTP384 uint16 = 0x0004
+)
+
+// Object attributes, per TPM 2.0 part 2, section 8.3.
+const (
+	AttrFixedTPM            uint32 = 1 << 1
+	AttrFixedParent         uint32 = 1 << 4
+	AttrSensitiveDataOrigin uint32 = 1 << 5
+	AttrUserWithAuth        uint32 = 1 << 6
+	AttrRestricted          uint32 = 1 << 16
+	AttrDecrypt             uint32 = 1 << 17
+	AttrSign                uint32 = 1 << 18
+
+	// AttestationKeyAttrs are the attributes required of an attestation key:
+	// a restricted signing key generated by, and bound to, its TPM.
+	AttestationKeyAttrs = AttrFixedTPM | AttrFixedParent | AttrSensitiveDataOrigin | AttrRestricted | AttrSign
+)
+
+// defaultRSAExponent is the RSA exponent denoted by an encoded exponent of 0.
+const defaultRSAExponent = 1<<16 + 1
+
+const (
+	// GeneratedValue prefixes all TPM-generated attestation structures, so
+	// that a restricted signing key never signs externally-provided data
+	// resembling an attestation.
+	GeneratedValue uint32 = 0xff544347
+	// STAttestQuote is the structure tag of quote attestations.
+	STAttestQuote uint16 = 0x8018
+)
+
+// Public is a TPMT_PUBLIC, restricted to RSA and ECC keys.
+type Public struct {
+	Type       uint16
+	NameAlg    uint16
+	Attributes uint32
+	AuthPolicy []byte
+	// Symmetric is the symmetric algorithm of storage keys, or AlgNull
+	Symmetric     uint16
+	SymmetricBits uint16
+	SymmetricMode uint16
+	// Scheme and SchemeHash are the signing scheme of signing keys, or
+	// AlgNull
+	Scheme     uint16
+	SchemeHash uint16
+	// Exponent is the RSA exponent as encoded, where 0 denotes the default
+	// exponent 65537, as used by e.g. `tpm2_createak`
+	Exponent uint32
+	// KDF and KDFHash are the key derivation scheme of ECC keys, or AlgNull
+	KDF     uint16
+	KDFHash uint16
+	// Key is the *rsa.PublicKey or *ecdsa.PublicKey of the object
+	Key crypto.PublicKey
+
+	// encoded is the TPMT_PUBLIC the public area was decoded from, which
+	// its name is computed over
+	encoded []byte
+}
+
+// DecodePublic decodes a TPMT_PUBLIC, e.g. as output by
+// `tpm2_readpublic -f tpmt`.
+func DecodePublic(b []byte) (*Public, error) {
+	r := &reader{r: bytes.NewReader(b)}
+	p := &Public{
+		Type:       r.uint16(),
+		NameAlg:    r.uint16(),
+		Attributes: r.uint32(),
+		AuthPolicy: r.tpm2b(),
+		encoded:    append([]byte{}, b...),
+	}
+	p.Symmetric = r.uint16()
+	if p.Symmetric != AlgNull {
+		p.SymmetricBits = r.uint16()
+		p.SymmetricMode = r.uint16()
+	}
+	p.Scheme = r.uint16()
+	if p.Scheme != AlgNull {
+		p.SchemeHash = r.uint16()
+	}
+
+	switch p.Type {
+	case AlgRSA:
+		keyBits := r.uint16()
+		p.Exponent = r.uint32()
+		modulus := r.tpm2b()
+		if r.err != nil {
+			break
+		}
+		exponent := p.Exponent
+		if exponent == 0 {
+			exponent = defaultRSAExponent
+		}
+		if len(modulus)*8 != int(keyBits) {
+			return nil, fmt.Errorf("RSA modulus size %d doesn't match key bits %d", len(modulus)*8, keyBits)
+		}
+		p.Key = &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(exponent)}
+	case AlgECC:
+		curveID := r.uint16()
+		p.KDF = r.uint16()
+		if p.KDF != AlgNull {
+			p.KDFHash = r.uint16()
+		}
+		x, y := r.tpm2b(), r.tpm2b()
+		if r.err != nil {
+			break
+		}
+		curve, err := getCurve(curveID)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("ECC point is not on curve")
+		}
+		p.Key = key
+	default:
+		return nil, fmt.Errorf("unsupported key type 0x%04x", p.Type)
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("malformed public area: %v", r.err)
+	}
+	if r.r.Len() != 0 {
+		return nil, fmt.Errorf("malformed public area: %d trailing bytes", r.r.Len())
+	}
+	return p, nil
+}
+
+// Encode encodes the public area as a TPMT_PUBLIC.
+func (p *Public) Encode() ([]byte, error) {
+	w := &bytes.Buffer{}
+	writeUint16(w, p.Type)
+	writeUint16(w, p.NameAlg)
+	writeUint32(w, p.Attributes)
+	writeTPM2B(w, p.AuthPolicy)
+	writeUint16(w, p.Symmetric)
+	if p.Symmetric != AlgNull {
+		writeUint16(w, p.SymmetricBits)
+		writeUint16(w, p.SymmetricMode)
+	}
+	writeUint16(w, p.Scheme)
+	if p.Scheme != AlgNull {
+		writeUint16(w, p.SchemeHash)
+	}
+
+	switch key := p.Key.(type) {
+	case *rsa.PublicKey:
+		if p.Exponent != uint32(key.E) && !(p.Exponent == 0 && key.E == defaultRSAExponent) {
+			return nil, fmt.Errorf("RSA exponent %d doesn't match key exponent %d", p.Exponent, key.E)
+		}
+		writeUint16(w, uint16(key.N.BitLen()))
+		writeUint32(w, p.Exponent)
+		writeTPM2B(w, key.N.Bytes())
+	case *ecdsa.PublicKey:
+		curveID, err := getCurveID(key.Curve)
+		if err != nil {
+			return nil, err
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		writeUint16(w, curveID)
+		writeUint16(w, p.KDF)
+		if p.KDF != AlgNull {
+			writeUint16(w, p.KDFHash)
+		}
+		writeTPM2B(w, padLeft(key.X.Bytes(), size))
+		writeTPM2B(w, padLeft(key.Y.Bytes(), size))
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", p.Key)
+	}
+	return w.Bytes(), nil
+}
+
+// Name returns the TPM name of the object: its name algorithm, followed by
+// the digest of its public area. The digest of a decoded public area is over
+// the bytes it was decoded from, as the TPM computes it.
+func (p *Public) Name() ([]byte, error) {
+	hash, err := getHash(p.NameAlg)
+	if err != nil {
+		return nil, err
+	}
+	encoded := p.encoded
+	if encoded == nil {
+		encoded, err = p.Encode()
+		if err != nil {
+			return nil, err
+		}
+	}
+	h := hash.New()
+	h.Write(encoded)
+
+	name := &bytes.Buffer{}
+	writeUint16(name, p.NameAlg)
+	name.Write(h.Sum(nil))
+	return name.Bytes(), nil
+}
+
+// PCRSelection is a TPMS_PCR_SELECTION.
+type PCRSelection struct {
+	Hash   uint16
+	Select []byte
+}
+
+// Attest is a TPMS_ATTEST of a quote.
+type Attest struct {
+	Magic           uint32
+	Type            uint16
+	QualifiedSigner []byte
+	ExtraData       []byte
+	Clock           uint64
+	ResetCount      uint32
+	RestartCount    uint32
+	Safe            bool
+	FirmwareVersion uint64
+	PCRSelections   []PCRSelection
+	PCRDigest       []byte
+}
+
+// DecodeAttest decodes a TPMS_ATTEST of a quote, e.g. as output by
+// `tpm2_quote -m`.
+func DecodeAttest(b []byte) (*Attest, error) {
+	r := &reader{r: bytes.NewReader(b)}
+	a := &Attest{
+		Magic:           r.uint32(),
+		Type:            r.uint16(),
+		QualifiedSigner: r.tpm2b(),
+		ExtraData:       r.tpm2b(),
+		Clock:           r.uint64(),
+		ResetCount:      r.uint32(),
+		RestartCount:    r.uint32(),
+		Safe:            r.uint8() != 0,
+		FirmwareVersion: r.uint64(),
+	}
+	if r.err == nil && a.Type != STAttestQuote {
+		return nil, fmt.Errorf("unsupported attestation type 0x%04x", a.Type)
+	}
+	count := r.uint32()
+	for i := uint32(0); i < count && r.err == nil; i++ {
+		sel := PCRSelection{Hash: r.uint16()}
+		sel.Select = r.bytes(int(r.uint8()))
+		a.PCRSelections = append(a.PCRSelections, sel)
+	}
+	a.PCRDigest = r.tpm2b()
+	if r.err != nil {
+		return nil, fmt.Errorf("malformed attestation: %v", r.err)
+	}
+	if r.r.Len() != 0 {
+		return nil, fmt.Errorf("malformed attestation: %d trailing bytes", r.r.Len())
+	}
+	return a, nil
+}
+
+// Encode encodes the attestation as a TPMS_ATTEST.
+func (a *Attest) Encode() []byte {
+	w := &bytes.Buffer{}
+	writeUint32(w, a.Magic)
+	writeUint16(w, a.Type)
+	writeTPM2B(w, a.QualifiedSigner)
+	writeTPM2B(w, a.ExtraData)
+	binary.Write(w, binary.BigEndian, a.Clock)
+	writeUint32(w, a.ResetCount)
+	writeUint32(w, a.RestartCount)
+	if a.Safe {
+		w.WriteByte(1)
+	} else {
+		w.WriteByte(0)
+	}
+	binary.Write(w, binary.BigEndian, a.FirmwareVersion)
+	writeUint32(w, uint32(len(a.PCRSelections)))
+	for _, sel := range a.PCRSelections {
+		writeUint16(w, sel.Hash)
+		w.WriteByte(uint8(len(sel.Select)))
+		w.Write(sel.Select)
+	}
+	writeTPM2B(w, a.PCRDigest)
+	return w.Bytes()
+}
+
+// Signature is a TPMT_SIGNATURE, restricted to RSASSA and ECDSA signatures.
+type Signature struct {
+	Alg  uint16
+	Hash uint16
+	// RSA is the signature of RSASSA signatures
+	RSA []byte
+	// R and S are the signature of ECDSA signatures
+	R, S *big.Int
+}
+
+// DecodeSignature decodes a TPMT_SIGNATURE, e.g. as output by
+// `tpm2_quote -s`.
+func DecodeSignature(b []byte) (*Signature, error) {
+	r := &reader{r: bytes.NewReader(b)}
+	s := &Signature{Alg: r.uint16(), Hash: r.uint16()}
+	switch s.Alg {
+	case AlgRSASSA:
+		s.RSA = r.tpm2b()
+	case AlgECDSA:
+		s.R = new(big.Int).SetBytes(r.tpm2b())
+		s.S = new(big.Int).SetBytes(r.tpm2b())
+	default:
+		if r.err == nil {
+			return nil, fmt.Errorf("unsupported signature algorithm 0x%04x", s.Alg)
+		}
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("malformed signature: %v", r.err)
+	}
+	return s, nil
+}
+
+// Encode encodes the signature as a TPMT_SIGNATURE.
+func (s *Signature) Encode() []byte {
+	w := &bytes.Buffer{}
+	writeUint16(w, s.Alg)
+	writeUint16(w, s.Hash)
+	switch s.Alg {
+	case AlgRSASSA:
+		writeTPM2B(w, s.RSA)
+	case AlgECDSA:
+		writeTPM2B(w, s.R.Bytes())
+		writeTPM2B(w, s.S.Bytes())
+	}
+	return w.Bytes()
+}
+
+// Verify verifies the signature over the passed message with the passed key.
+func (s *Signature) Verify(key crypto.PublicKey, msg []byte) error {
+	hash, err := getHash(s.Hash)
+	if err != nil {
+		return err
+	}
+	h := hash.New()
+	h.Write(msg)
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if s.Alg != AlgRSASSA {
+			return fmt.Errorf("signature algorithm 0x%04x doesn't match RSA key", s.Alg)
+		}
+		return rsa.VerifyPKCS1v15(k, hash, digest, s.RSA)
+	case *ecdsa.PublicKey:
+		if s.Alg != AlgECDSA {
+			return fmt.Errorf("signature algorithm 0x%04x doesn't match ECC key", s.Alg)
+		}
+		if !ecdsa.Verify(k, digest, s.R, s.S) {
+			return fmt.Errorf("invalid ECDSA signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+func getHash(alg uint16) (crypto.Hash, error) {
+	switch alg {
+	case AlgSHA256:
+		return crypto.SHA256, nil
+	case AlgSHA384:
+		return crypto.SHA384, nil
+	default:
+		return 0, fmt.Errorf("unsupported hash algorithm 0x%04x", alg)
+	}
+}
+
+func getCurve(curveID uint16) (elliptic.Curve, error) {
+	switch curveID {
+	case CurveNISTP256:
+		return elliptic.P256(), nil
+	case CurveNISTP384:
+		return elliptic.P384(), nil
+	default:
+		return nil, fmt.Errorf("unsupported ECC curve 0x%04x", curveID)
+	}
+}
+
+func getCurveID(curve elliptic.Curve) (uint16, error) {
+	switch curve {
+	case elliptic.P256():
+		return CurveNISTP256, nil
+	case elliptic.P384():
+		return CurveNISTP384, nil
+	default:
+		return 0, fmt.Errorf("unsupported ECC curve %s", curve.Params().Name)
+	}
+}
+
+func padLeft(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	return append(make([]byte, size-len(b)), b...)
+}
+
+// reader decodes big-endian TPM structures, retaining the first error.
+type reader struct {
+	r   *bytes.Reader
+	err error
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > r.r.Len() {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := make([]byte, n)
+	_, r.err = io.ReadFull(r.r, b)
+	return b
+}
+
+func (r *reader) uint8() uint8 {
+	b := r.bytes(1)
+	if r.err != nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *reader) uint16() uint16 {
+	b := r.bytes(2)
+	if r.err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b)
+}
+
+func (r *reader) uint32() uint32 {
+	b := r.bytes(4)
+	if r.err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (r *reader) uint64() uint64 {
+	b := r.bytes(8)
+	if r.err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// tpm2b reads a TPM2B, i.e. a buffer prefixed by its 16-bit size.
+func (r *reader) tpm2b() []byte {
+	return r.bytes(int(r.uint16()))
+}
+
+func writeUint16(w *bytes.Buffer, v uint16) {
+	binary.Write(w, binary.BigEndian, v)
+}
+
+func writeUint32(w *bytes.Buffer, v uint32) {
+	binary.Write(w, binary.BigEndian, v)
+}
+
+func writeTPM2B(w *bytes.Buffer, b []byte) {
+	writeUint16(w, uint16(len(b)))
+	w.Write(b)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Magma Authors.
+
+This source code is licensed under the BSD-style license found in the
+LICENSE file in the root directory of this source tree.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpm_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"testing"
+
+	"magma/orc8r/lib/go/security/tpm"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulator(t *testing.T) {
+	caCert, caKey, err := tpm.NewSimulatedManufacturerCA()
+	require.NoError(t, err)
+	sim, err := tpm.NewSimulator(caCert, caKey)
+	require.NoError(t, err)
+
+	ekCert, err := x509.ParseCertificate(sim.EndorsementCertificate())
+	require.NoError(t, err)
+	assert.NoError(t, ekCert.CheckSignatureFrom(caCert))
+
+	// Public area round-trips
+	encodedAK, err := sim.AttestationKeyPublic()
+	require.NoError(t, err)
+	ak, err := tpm.DecodePublic(encodedAK)
+	require.NoError(t, err)
+	assert.Equal(t, tpm.AttestationKeyAttrs, ak.Attributes&tpm.AttestationKeyAttrs)
+	reencoded, err := ak.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, encodedAK, reencoded)
+	name, err := ak.Name()
+	require.NoError(t, err)
+	assert.Len(t, name, 34)
+
+	// Credential activation recovers the secret, only for the named AK
+	secret := []byte("some secret")
+	blob, encSeed, err := tpm.MakeCredential(ekCert.PublicKey.(*rsa.PublicKey), name, secret)
+	require.NoError(t, err)
+	activated, err := sim.ActivateCredential(blob, encSeed)
+	assert.NoError(t, err)
+	assert.Equal(t, secret, activated)
+
+	otherName := append([]byte{}, name...)
+	otherName[len(otherName)-1] ^= 0xff
+	blob, encSeed, err = tpm.MakeCredential(ekCert.PublicKey.(*rsa.PublicKey), otherName, secret)
+	require.NoError(t, err)
+	_, err = sim.ActivateCredential(blob, encSeed)
+	assert.Error(t, err)
+
+	// Quotes verify against the AK
+	attestBytes, sigBytes, err := sim.Quote([]byte("some nonce"))
+	require.NoError(t, err)
+	attest, err := tpm.DecodeAttest(attestBytes)
+	require.NoError(t, err)
+	assert.Equal(t, tpm.GeneratedValue, attest.Magic)
+	assert.Equal(t, []byte("some nonce"), attest.ExtraData)
+	assert.Equal(t, name, attest.QualifiedSigner)
+	assert.Equal(t, attestBytes, attest.Encode())
+
+	sig, err := tpm.DecodeSignature(sigBytes)
+	require.NoError(t, err)
+	assert.NoError(t, sig.Verify(ak.Key, attestBytes))
+	attestBytes[len(attestBytes)-1] ^= 0xff
+	assert.Error(t, sig.Verify(ak.Key, attestBytes))
+}
+
+func TestPublic_Name(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	eccKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	// RSA AK as created by tpm2_createak, with the default exponent encoded
+	// as 0
+	rsaAK := encodePublic(tpm.AlgRSA, tpm.AlgRSASSA,
+		u16(2048), u32(0), tpm2b(rsaKey.N.Bytes()),
+	)
+	// ECC AK with a KDF scheme (TPM_ALG_KDF1_SP800_56A)
+	eccAK := encodePublic(tpm.AlgECC, tpm.AlgECDSA,
+		u16(tpm.CurveNISTP256), u16(0x0020), u16(tpm.AlgSHA256),
+		tpm2b(padLeft(eccKey.X.Bytes(), 32)), tpm2b(padLeft(eccKey.Y.Bytes(), 32)),
+	)
+
+	ek, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	for _, encoded := range [][]byte{rsaAK, eccAK} {
+		ak, err := tpm.DecodePublic(encoded)
+		require.NoError(t, err)
+		reencoded, err := ak.Encode()
+		require.NoError(t, err)
+		assert.Equal(t, encoded, reencoded)
+
+		// The name is the digest of the public area as the TPM holds it
+		digest := sha256.Sum256(encoded)
+		tpmName := append(u16(tpm.AlgSHA256), digest[:]...)
+		name, err := ak.Name()
+		require.NoError(t, err)
+		assert.Equal(t, tpmName, name)
+
+		// So the TPM activates credentials made for the decoded AK
+		secret := []byte("some secret")
+		blob, encSeed, err := tpm.MakeCredential(&ek.PublicKey, name, secret)
+		require.NoError(t, err)
+		activated, err := tpm.ActivateCredential(ek, tpmName, blob, encSeed)
+		assert.NoError(t, err)
+		assert.Equal(t, secret, activated)
+	}
+
+	ak, err := tpm.DecodePublic(rsaAK)
+	require.NoError(t, err)
+	assert.Equal(t, 65537, ak.Key.(*rsa.PublicKey).E)
+}
+
+func TestDecode_Malformed(t *testing.T) {
+	_, err := tpm.DecodePublic([]byte{0x00, 0x23, 0x00})
+	assert.Error(t, err)
+	_, err = tpm.DecodeAttest([]byte{0xff, 0x54, 0x43, 0x47, 0x80, 0x18, 0x00, 0x10})
+	assert.Error(t, err)
+	_, err = tpm.DecodeSignature([]byte{0x00, 0x05, 0x00, 0x0b})
+	assert.Error(t, err)
+}
+
+// encodePublic encodes the TPMT_PUBLIC of an attestation key with the
+// passed type, signing scheme and type-specific parameters and unique
+// fields.
+func encodePublic(keyType, scheme uint16, fields ...[]byte) []byte {
+	b := bytes.Join([][]byte{
+		u16(keyType),
+		u16(tpm.AlgSHA256),
+		u32(tpm.AttestationKeyAttrs | tpm.AttrUserWithAuth),
+		tpm2b(nil),
+		u16(tpm.AlgNull),
+		u16(scheme), u16(tpm.AlgSHA256),
+	}, nil)
+	return append(b, bytes.Join(fields, nil)...)
+}
+
+func u16(v uint16) []byte {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, v)
+	return b
+}
+
+func u32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
+
+func tpm2b(v []byte) []byte {
+	return append(u16(uint16(len(v))), v...)
+}
+
+func padLeft(b []byte, size int) []byte {
+	return append(make([]byte, size-len(b)), b...)
+}